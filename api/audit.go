@@ -20,8 +20,9 @@ type AuditEvent struct {
 	Version  string `json:"version"`
 
 	// User identification
-	UserID    string `json:"userId"`
-	UserAgent string `json:"userAgent"`
+	UserID         string `json:"userId"`
+	ImpersonatorID string `json:"impersonatorId"` // Operator acting as the user, empty unless impersonating
+	UserAgent      string `json:"userAgent"`
 
 	// Request information
 	RequestID     string         `json:"requestId"`
@@ -46,8 +47,9 @@ type AuditEventParams struct {
 	Version  string
 
 	// User identification
-	UserID    string
-	UserAgent string
+	UserID         string
+	ImpersonatorID string
+	UserAgent      string
 
 	// Request information
 	RequestID     string
@@ -67,20 +69,21 @@ type AuditEventParams struct {
 // NewAuditEvent creates a new audit event with the given parameters.
 func NewAuditEvent(params AuditEventParams) *AuditEvent {
 	return &AuditEvent{
-		BaseEvent:     event.NewBaseEvent(eventTypeAudit),
-		Resource:      params.Resource,
-		Action:        params.Action,
-		Version:       params.Version,
-		UserID:        params.UserID,
-		UserAgent:     params.UserAgent,
-		RequestID:     params.RequestID,
-		RequestIP:     params.RequestIP,
-		RequestParams: params.RequestParams,
-		RequestMeta:   params.RequestMeta,
-		ResultCode:    params.ResultCode,
-		ResultMessage: params.ResultMessage,
-		ResultData:    params.ResultData,
-		ElapsedTime:   params.ElapsedTime,
+		BaseEvent:      event.NewBaseEvent(eventTypeAudit),
+		Resource:       params.Resource,
+		Action:         params.Action,
+		Version:        params.Version,
+		UserID:         params.UserID,
+		ImpersonatorID: params.ImpersonatorID,
+		UserAgent:      params.UserAgent,
+		RequestID:      params.RequestID,
+		RequestIP:      params.RequestIP,
+		RequestParams:  params.RequestParams,
+		RequestMeta:    params.RequestMeta,
+		ResultCode:     params.ResultCode,
+		ResultMessage:  params.ResultMessage,
+		ResultData:     params.ResultData,
+		ElapsedTime:    params.ElapsedTime,
 	}
 }
 
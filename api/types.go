@@ -97,6 +97,12 @@ type OperationSpec struct {
 	PermToken string
 	// RateLimit represents the rate limit for an Api endpoint
 	RateLimit *RateLimitConfig
+	// IPFilter restricts the client addresses allowed to call the Api endpoint
+	IPFilter *IPFilterConfig
+	// BodyLimit restricts the request bodies accepted by the Api endpoint below the application body limit
+	BodyLimit *BodyLimitConfig
+	// Cache caches the responses of the Api endpoint, which must be idempotent
+	Cache *CacheConfig
 	// Handler is the business logic handler.
 	Handler any
 }
@@ -115,6 +121,12 @@ type Operation struct {
 	Auth *AuthConfig
 	// RateLimit is the final rate limit configuration.
 	RateLimit *RateLimitConfig
+	// IPFilter is the operation level IP filter, applied in addition to the global one.
+	IPFilter *IPFilterConfig
+	// BodyLimit is the operation level body limit, applied in addition to the application one.
+	BodyLimit *BodyLimitConfig
+	// Cache is the response cache configuration.
+	Cache *CacheConfig
 	// Handler is the resolved handler (before adaptation).
 	Handler any
 	// Dynamic indicates whether this operation is registered dynamically.
@@ -134,6 +146,21 @@ func (o *Operation) RequiresAuth() bool {
 	return o.Auth.Strategy != AuthStrategyNone
 }
 
+// HasIPFilter returns true if the operation restricts client addresses.
+func (o *Operation) HasIPFilter() bool {
+	return o.IPFilter != nil && (len(o.IPFilter.Allow) > 0 || len(o.IPFilter.Deny) > 0)
+}
+
+// HasBodyLimit returns true if the operation restricts request bodies.
+func (o *Operation) HasBodyLimit() bool {
+	return o.BodyLimit != nil && (o.BodyLimit.MaxSize > 0 || o.BodyLimit.MaxParts > 0 || o.BodyLimit.MaxFileSize > 0)
+}
+
+// HasCache returns true if the responses of the operation are cached.
+func (o *Operation) HasCache() bool {
+	return o.Cache != nil && o.Cache.TTL > 0
+}
+
 // RateLimitConfig defines rate limiting configuration.
 type RateLimitConfig struct {
 	// Max is the maximum number of requests allowed.
@@ -144,3 +171,38 @@ type RateLimitConfig struct {
 	// Empty means using the default key.
 	Key string
 }
+
+// IPFilterConfig defines the client addresses allowed to call an operation.
+// Entries are IP addresses or CIDR ranges; deny entries take precedence over allow entries.
+type IPFilterConfig struct {
+	// Allow lists the only addresses admitted once non-empty.
+	Allow []string
+	// Deny lists the addresses always rejected.
+	Deny []string
+}
+
+// BodyLimitConfig defines the request bodies accepted by an operation; zero values leave a dimension unlimited.
+// Bodies over a limit are rejected with 413 Request Entity Too Large.
+type BodyLimitConfig struct {
+	// MaxSize is the maximum body size in bytes.
+	MaxSize int64
+	// MaxParts is the maximum number of parts of multipart bodies, form values and files together.
+	MaxParts int
+	// MaxFileSize is the maximum size in bytes of each file of multipart bodies.
+	MaxFileSize int64
+}
+
+// CacheConfig defines the caching of the successful responses of an idempotent operation.
+// Responses are keyed by the operation, the request URL, params and meta and, unless shared, the principal;
+// they are invalidated as soon as a table read while producing them is written.
+type CacheConfig struct {
+	// TTL is the duration a response is served as fresh.
+	TTL time.Duration
+	// StaleTTL is the duration after TTL a stale response is still served while a single request refreshes it.
+	StaleTTL time.Duration
+	// Shared caches the responses once for all principals instead of per principal,
+	// for operations whose responses do not depend on the caller.
+	Shared bool
+	// Tables lists additional tables whose writes invalidate the responses, e.g. tables only joined by the queries.
+	Tables []string
+}
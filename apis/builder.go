@@ -14,6 +14,8 @@ type baseBuilder[T any] struct {
 	public      bool
 	permToken   string
 	rateLimit   *api.RateLimitConfig
+	ipFilter    *api.IPFilterConfig
+	cache       *api.CacheConfig
 
 	self T
 }
@@ -67,6 +69,32 @@ func (b *baseBuilder[T]) RateLimit(maxRequests int, period time.Duration) T {
 	return b.self
 }
 
+func (b *baseBuilder[T]) AllowIPs(entries ...string) T {
+	if b.ipFilter == nil {
+		b.ipFilter = &api.IPFilterConfig{}
+	}
+
+	b.ipFilter.Allow = append(b.ipFilter.Allow, entries...)
+
+	return b.self
+}
+
+func (b *baseBuilder[T]) DenyIPs(entries ...string) T {
+	if b.ipFilter == nil {
+		b.ipFilter = &api.IPFilterConfig{}
+	}
+
+	b.ipFilter.Deny = append(b.ipFilter.Deny, entries...)
+
+	return b.self
+}
+
+func (b *baseBuilder[T]) Cache(config api.CacheConfig) T {
+	b.cache = &config
+
+	return b.self
+}
+
 func (b *baseBuilder[T]) Build(handler any) api.OperationSpec {
 	return api.OperationSpec{
 		Action:      b.action,
@@ -75,6 +103,8 @@ func (b *baseBuilder[T]) Build(handler any) api.OperationSpec {
 		Public:      b.public,
 		PermToken:   b.permToken,
 		RateLimit:   b.rateLimit,
+		IPFilter:    b.ipFilter,
+		Cache:       b.cache,
 		Handler:     handler,
 	}
 }
@@ -8,6 +8,8 @@ type TabularFormat string
 // i18n message keys for APIs.
 const (
 	ErrMessageProcessorMustReturnSlice = "processor_must_return_slice"
+	ErrMessageSortFieldNotAllowed      = "sort_field_not_allowed"
+	ErrMessageIncludeNotAllowed        = "include_relation_not_allowed"
 )
 
 // Error codes for APIs.
@@ -56,6 +58,8 @@ const (
 	defaultAuditUserNameColumn = "name"
 	defaultLabelColumn         = "name"
 	defaultValueColumn         = constants.ColumnID
+	defaultMaxIncludeDepth     = 2
+	includeParam               = "include"
 
 	IDColumn          = constants.ColumnID
 	ParentIDColumn    = "parent_id"
@@ -13,3 +13,6 @@ var ErrSearchTypeMismatch = errors.New("search type mismatch")
 
 // ErrColumnNotFound indicates a column does not exist in the model.
 var ErrColumnNotFound = errors.New("column does not exist in model")
+
+// ErrRelationNotFound indicates a relation does not exist in the model.
+var ErrRelationNotFound = errors.New("relation does not exist in model")
@@ -24,6 +24,7 @@ func (a *findAllApi[TModel, TSearch]) findAll(db orm.DB) (func(ctx fiber.Ctx, db
 			Condition:         []QueryPart{QueryRoot},
 			Sort:              []QueryPart{QueryRoot},
 			AuditUserRelation: []QueryPart{QueryRoot},
+			Include:           []QueryPart{QueryRoot},
 		},
 	}); err != nil {
 		return nil, err
@@ -2,6 +2,7 @@ package apis_test
 
 import (
 	"github.com/gofiber/fiber/v3"
+	"github.com/uptrace/bun"
 
 	"github.com/ilxqx/vef-framework-go/api"
 	"github.com/ilxqx/vef-framework-go/apis"
@@ -137,6 +138,30 @@ func NewMultipleDefaultSortUserFindAllResource() api.Resource {
 	}
 }
 
+// IncludeTestUser reads the test users with their creator as an includable relation.
+type IncludeTestUser struct {
+	bun.BaseModel `bun:"table:test_user,alias:tu"`
+	orm.Model
+
+	Name    string         `json:"name"    bun:",notnull"`
+	Creator *TestAuditUser `json:"creator" bun:"rel:belongs-to,join:created_by=id"`
+}
+
+// Include User Resource - with includable relations.
+type IncludeUserFindAllResource struct {
+	api.Resource
+	apis.FindAll[IncludeTestUser, TestUserSearch]
+}
+
+func NewIncludeUserFindAllResource() api.Resource {
+	return &IncludeUserFindAllResource{
+		Resource: api.NewRPCResource("test/user_all_include"),
+		FindAll: apis.NewFindAll[IncludeTestUser, TestUserSearch]().
+			WithIncludes("Creator").
+			Public(),
+	}
+}
+
 // FindAllTestSuite tests the FindAll API functionality
 // including basic queries, search filters, processors, sorting, audit user names, and negative cases.
 type FindAllTestSuite struct {
@@ -153,6 +178,7 @@ func (suite *FindAllTestSuite) SetupSuite() {
 		NewAuditUserTestUserFindAllResource,
 		NewNoDefaultSortUserFindAllResource,
 		NewMultipleDefaultSortUserFindAllResource,
+		NewIncludeUserFindAllResource,
 	)
 }
 
@@ -669,3 +695,73 @@ func (suite *FindAllTestSuite) TestFindAllRequestSortOverride() {
 		suite.T().Logf("Request sort applied to resource with disabled default sort: emails sorted ASC")
 	})
 }
+
+// TestFindAllWithIncludes tests loading the relations requested by the include parameter.
+func (suite *FindAllTestSuite) TestFindAllWithIncludes() {
+	suite.T().Logf("Testing FindAll API includes for %s", suite.dbType)
+
+	findAll := func(params map[string]any) result.Result {
+		resp := suite.makeApiRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "test/user_all_include",
+				Action:   "find_all",
+				Version:  "v1",
+			},
+			Params: params,
+		})
+
+		suite.Equal(200, resp.StatusCode, "Should return 200 status code")
+
+		return suite.readBody(resp)
+	}
+
+	suite.Run("WithoutInclude", func() {
+		body := findAll(map[string]any{"status": "active"})
+		suite.True(body.IsOk(), "Should return successful response")
+
+		for _, u := range suite.readDataAsSlice(body.Data) {
+			suite.Nil(suite.readDataAsMap(u)["creator"], "Should not load relations not requested")
+		}
+	})
+
+	suite.Run("IncludeWhitelistedRelation", func() {
+		body := findAll(map[string]any{"status": "active", "include": "creator"})
+		suite.True(body.IsOk(), "Should return successful response")
+
+		users := suite.readDataAsSlice(body.Data)
+		suite.Len(users, 7, "Should return 7 active users")
+
+		for _, u := range users {
+			user := suite.readDataAsMap(u)
+			creator := suite.readDataAsMap(user["creator"])
+			suite.Equal(user["createdBy"], creator["id"], "Should load the creator of user %s", user["id"])
+			suite.Contains([]string{"John Doe", "Jane Smith", "Michael Johnson", "Sarah Williams"}, creator["name"])
+		}
+	})
+
+	suite.Run("IncludeAsList", func() {
+		body := findAll(map[string]any{"include": []string{"Creator"}})
+		suite.True(body.IsOk(), "Should accept a list of relations")
+
+		users := suite.readDataAsSlice(body.Data)
+		suite.NotNil(suite.readDataAsMap(users[0])["creator"], "Should load the creator")
+	})
+
+	suite.Run("IncludeUnknownRelationRejected", func() {
+		body := findAll(map[string]any{"include": "creator,posts"})
+		suite.False(body.IsOk(), "Should reject relations not whitelisted")
+		suite.Equal(result.ErrCodeBadRequest, body.Code)
+	})
+
+	suite.Run("IncludeNestedNotWhitelistedRejected", func() {
+		body := findAll(map[string]any{"include": "creator.creator"})
+		suite.False(body.IsOk(), "Should reject relations nested in a whitelisted one")
+		suite.Equal(result.ErrCodeBadRequest, body.Code)
+	})
+
+	suite.Run("IncludeTooDeepRejected", func() {
+		body := findAll(map[string]any{"include": "creator.creator.creator"})
+		suite.False(body.IsOk(), "Should reject relations nested beyond the maximum depth")
+		suite.Equal(result.ErrCodeBadRequest, body.Code)
+	})
+}
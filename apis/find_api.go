@@ -1,6 +1,7 @@
 package apis
 
 import (
+	"maps"
 	"slices"
 
 	"github.com/gofiber/fiber/v3"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/ilxqx/vef-framework-go/api"
 	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/fieldmap"
 	"github.com/ilxqx/vef-framework-go/orm"
 	"github.com/ilxqx/vef-framework-go/sortx"
 )
@@ -24,6 +26,10 @@ type baseFindApi[TModel, TSearch, TProcessorIn, TApi any] struct {
 	auditUserModel      any
 	auditUserNameColumn string
 	defaultSort         []*sortx.OrderSpec
+	sortableFields      []string
+	sortMapping         map[string]string
+	includes            []string
+	maxIncludeDepth     int
 	processor           Processor[TProcessorIn, TSearch]
 
 	self TApi
@@ -44,7 +50,7 @@ func (a *baseFindApi[TModel, TSearch, TProcessorIn, TApi]) Setup(db orm.DB, conf
 			table = db.TableOf((*TModel)(nil))
 		)
 
-		opt := withSearchApplier[TSearch](
+		opt := withSearchApplier[TModel, TSearch](
 			lo.Ternary(qp.Condition != nil, qp.Condition, []QueryPart{QueryRoot})...,
 		)
 		a.options = append(a.options, opt)
@@ -74,6 +80,17 @@ func (a *baseFindApi[TModel, TSearch, TProcessorIn, TApi]) Setup(db orm.DB, conf
 			a.options = append(a.options, opt)
 		}
 
+		if len(a.includes) > 0 && qp.Include != nil {
+			policy, err := newIncludePolicy(table, a.includes, lo.Ternary(a.maxIncludeDepth > 0, a.maxIncludeDepth, defaultMaxIncludeDepth))
+			if err != nil {
+				return err
+			}
+
+			a.options = append(a.options, withIncludes(policy, qp.Include...))
+		}
+
+		policy := newSortPolicy(table, fieldmap.For[TModel](), a.sortableFields, a.sortMapping)
+
 		if a.defaultSort == nil {
 			if len(table.PKs) == 1 {
 				opt := withSort(
@@ -83,6 +100,7 @@ func (a *baseFindApi[TModel, TSearch, TProcessorIn, TApi]) Setup(db orm.DB, conf
 							Direction: sortx.OrderDesc,
 						},
 					},
+					policy,
 					lo.Ternary(qp.Sort != nil, qp.Sort, []QueryPart{QueryRoot})...,
 				)
 				a.options = append(a.options, opt)
@@ -95,6 +113,7 @@ func (a *baseFindApi[TModel, TSearch, TProcessorIn, TApi]) Setup(db orm.DB, conf
 								Direction: sortx.OrderDesc,
 							},
 						},
+						policy,
 						lo.Ternary(qp.Sort != nil, qp.Sort, []QueryPart{QueryRoot})...,
 					)
 					a.options = append(a.options, opt)
@@ -102,7 +121,7 @@ func (a *baseFindApi[TModel, TSearch, TProcessorIn, TApi]) Setup(db orm.DB, conf
 			}
 		} else if len(a.defaultSort) > 0 {
 			// User-defined default sorting
-			opt := withSort(a.defaultSort, lo.Ternary(qp.Sort != nil, qp.Sort, []QueryPart{QueryRoot})...)
+			opt := withSort(a.defaultSort, policy, lo.Ternary(qp.Sort != nil, qp.Sort, []QueryPart{QueryRoot})...)
 			a.options = append(a.options, opt)
 		}
 	}
@@ -202,6 +221,47 @@ func (a *baseFindApi[TModel, TSearch, TProcessorIn, TApi]) WithDefaultSort(order
 	return a.self
 }
 
+// WithSortableFields restricts request sorting to the given columns.
+// Without it, request sorting is limited to the columns of the model.
+// Must be called before the API is registered (before Setup() is invoked).
+func (a *baseFindApi[TModel, TSearch, TProcessorIn, TApi]) WithSortableFields(fields ...string) TApi {
+	a.sortableFields = append(a.sortableFields, fields...)
+
+	return a.self
+}
+
+// WithSortMapping maps the sort field names accepted in requests to database columns,
+// e.g. {"createdAt": "created_at", "deptName": "sd.name"}. Mapped names are sortable as well.
+// Must be called before the API is registered (before Setup() is invoked).
+func (a *baseFindApi[TModel, TSearch, TProcessorIn, TApi]) WithSortMapping(mapping map[string]string) TApi {
+	if a.sortMapping == nil {
+		a.sortMapping = make(map[string]string, len(mapping))
+	}
+
+	maps.Copy(a.sortMapping, mapping)
+
+	return a.self
+}
+
+// WithIncludes admits exactly the given relations in the include request parameter, e.g. "user,category.parent",
+// which loads them with the results; nested relations must be given by their full path. Relations are given
+// by the Go field path and requested by it, optionally with lower case first letters. The loaded relations
+// are restricted by the tenancy and data scopes of the DB like the results. Without it, the include parameter is ignored.
+// Must be called before the API is registered (before Setup() is invoked).
+func (a *baseFindApi[TModel, TSearch, TProcessorIn, TApi]) WithIncludes(relations ...string) TApi {
+	a.includes = append(a.includes, relations...)
+
+	return a.self
+}
+
+// WithMaxIncludeDepth limits the nesting of the relations requests may include, 2 by default.
+// Must be called before the API is registered (before Setup() is invoked).
+func (a *baseFindApi[TModel, TSearch, TProcessorIn, TApi]) WithMaxIncludeDepth(depth int) TApi {
+	a.maxIncludeDepth = depth
+
+	return a.self
+}
+
 // DisableDataPerm disables data permission filtering for this API.
 // By default, data permission filtering is enabled (WithDataPerm is auto-applied in Setup).
 func (a *baseFindApi[TModel, TSearch, TProcessorIn, TApi]) DisableDataPerm() TApi {
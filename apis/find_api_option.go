@@ -2,12 +2,21 @@ package apis
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/ilxqx/go-streams"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun/schema"
 
 	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/fieldmap"
+	"github.com/ilxqx/vef-framework-go/i18n"
+	"github.com/ilxqx/vef-framework-go/internal/api/shared"
 	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/result"
 	"github.com/ilxqx/vef-framework-go/search"
 	"github.com/ilxqx/vef-framework-go/sortx"
 )
@@ -87,10 +96,52 @@ func withSelectAs(column, alias string, parts ...QueryPart) *FindApiOption {
 	}
 }
 
+// sortPolicy decides which sort fields requests may use and the columns they map to.
+type sortPolicy struct {
+	columns map[string]string // requested field -> column
+}
+
+// newSortPolicy creates the sort policy of an API. Explicitly sortable fields and mappings replace
+// the default, which admits the columns of the model and the Api fields mapped to them.
+// Explicitly sortable fields may be given as Api fields or as columns.
+func newSortPolicy(table *schema.Table, fields *fieldmap.Mapping, sortableFields []string, mapping map[string]string) *sortPolicy {
+	policy := &sortPolicy{columns: make(map[string]string)}
+
+	if len(sortableFields) == 0 && len(mapping) == 0 {
+		for _, field := range table.Fields {
+			policy.columns[field.Name] = field.Name
+
+			if apiField, ok := fields.Field(field.Name); ok {
+				policy.columns[apiField] = field.Name
+			}
+		}
+
+		return policy
+	}
+
+	for _, field := range sortableFields {
+		column, ok := fields.Column(field)
+		policy.columns[field] = lo.Ternary(ok, column, field)
+	}
+
+	maps.Copy(policy.columns, mapping)
+
+	return policy
+}
+
+// resolve returns the column of a requested sort field.
+func (p *sortPolicy) resolve(field string) (string, bool) {
+	column, ok := p.columns[field]
+
+	return column, ok
+}
+
 // withSort adds ordering based on sort.OrderSpec specifications.
 // Applies to root query only by default (QueryRoot).
 // Supports ascending/descending order and NULLS FIRST/LAST positioning.
-func withSort(specs []*sortx.OrderSpec, parts ...QueryPart) *FindApiOption {
+// Sort fields from the request are resolved through the policy and rejected when not admitted,
+// the default specs are configured by the API and used as is.
+func withSort(specs []*sortx.OrderSpec, policy *sortPolicy, parts ...QueryPart) *FindApiOption {
 	return &FindApiOption{
 		Parts: resolveQueryParts(parts...),
 		Applier: func(query orm.SelectQuery, _ any, meta api.Meta, _ fiber.Ctx) error {
@@ -126,7 +177,25 @@ func withSort(specs []*sortx.OrderSpec, parts ...QueryPart) *FindApiOption {
 			}
 
 			if len(sortable.Sort) > 0 {
-				streams.FromSlice(sortable.Sort).
+				resolved := make([]sortx.OrderSpec, 0, len(sortable.Sort))
+				for _, spec := range sortable.Sort {
+					if !spec.IsValid() {
+						continue
+					}
+
+					column, ok := policy.resolve(spec.Column)
+					if !ok {
+						return result.Err(
+							i18n.T(ErrMessageSortFieldNotAllowed, map[string]any{"field": spec.Column}),
+							result.WithCode(result.ErrCodeBadRequest),
+						)
+					}
+
+					spec.Column = column
+					resolved = append(resolved, spec)
+				}
+
+				streams.FromSlice(resolved).
 					ForEach(func(spec sortx.OrderSpec) {
 						applyOrderSpec(spec)
 					})
@@ -142,6 +211,130 @@ func withSort(specs []*sortx.OrderSpec, parts ...QueryPart) *FindApiOption {
 	}
 }
 
+// includePolicy decides which relations requests may include and the relation paths they map to.
+type includePolicy struct {
+	table    *schema.Table
+	allowed  []string // whitelisted relation paths
+	maxDepth int
+}
+
+// newIncludePolicy creates the include policy of an API, failing when a whitelisted relation does not exist.
+func newIncludePolicy(table *schema.Table, relations []string, maxDepth int) (*includePolicy, error) {
+	policy := &includePolicy{
+		table:    table,
+		allowed:  make([]string, 0, len(relations)),
+		maxDepth: maxDepth,
+	}
+
+	for _, relation := range relations {
+		path, ok := resolveRelationPath(table, relation)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrRelationNotFound, relation)
+		}
+
+		policy.allowed = append(policy.allowed, path)
+	}
+
+	return policy, nil
+}
+
+// resolve returns the relation path of a requested relation, admitted when the path is whitelisted
+// and it is nested no deeper than the maximum depth.
+func (p *includePolicy) resolve(relation string) (string, bool) {
+	if strings.Count(relation, ".") >= p.maxDepth {
+		return "", false
+	}
+
+	path, ok := resolveRelationPath(p.table, relation)
+	if !ok {
+		return "", false
+	}
+
+	return path, slices.Contains(p.allowed, path)
+}
+
+// resolveRelationPath resolves a dotted relation path against the relations of a table, each relation given
+// by its Go field name or that name with a lower case first letter, returning it with the Go field names,
+// e.g. "user.dept" as "User.Dept".
+func resolveRelationPath(table *schema.Table, relation string) (string, bool) {
+	segments := strings.Split(relation, ".")
+	for i, segment := range segments {
+		if segment == "" {
+			return "", false
+		}
+
+		name := strings.ToUpper(segment[:1]) + segment[1:]
+
+		rel, ok := table.Relations[name]
+		if !ok {
+			return "", false
+		}
+
+		segments[i] = name
+		table = rel.JoinTable
+	}
+
+	return strings.Join(segments, "."), true
+}
+
+// requestedIncludes returns the relations requested by the include parameter,
+// given as a comma-separated string or a list of them.
+func requestedIncludes(ctx fiber.Ctx) []string {
+	req := shared.Request(ctx)
+	if req == nil {
+		return nil
+	}
+
+	var values []string
+
+	switch value := req.Params[includeParam].(type) {
+	case string:
+		values = append(values, value)
+	case []any:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	var relations []string
+
+	for _, value := range values {
+		for relation := range strings.SplitSeq(value, ",") {
+			if relation = strings.TrimSpace(relation); relation != "" && !slices.Contains(relations, relation) {
+				relations = append(relations, relation)
+			}
+		}
+	}
+
+	return relations
+}
+
+// withIncludes loads the relations requested by the include parameter with the results.
+// Applies to root query only by default (QueryRoot).
+// Requested relations are resolved through the policy and rejected when not admitted.
+func withIncludes(policy *includePolicy, parts ...QueryPart) *FindApiOption {
+	return &FindApiOption{
+		Parts: resolveQueryParts(parts...),
+		Applier: func(query orm.SelectQuery, _ any, _ api.Meta, ctx fiber.Ctx) error {
+			for _, relation := range requestedIncludes(ctx) {
+				path, ok := policy.resolve(relation)
+				if !ok {
+					return result.Err(
+						i18n.T(ErrMessageIncludeNotAllowed, map[string]any{"relation": relation}),
+						result.WithCode(result.ErrCodeBadRequest),
+					)
+				}
+
+				query.Relation(path)
+			}
+
+			return nil
+		},
+	}
+}
+
 // withCondition adds a WHERE condition using ConditionBuilder.
 // Applies to root query only by default (QueryRoot).
 // This is useful for adding simple filtering conditions.
@@ -158,8 +351,9 @@ func withCondition(fn func(cb orm.ConditionBuilder), parts ...QueryPart) *FindAp
 
 // withSearchApplier creates a FindApiOption that automatically applies search conditions
 // based on struct field tags (search:"eq", search:"contains", search:"gte", etc.).
-func withSearchApplier[TSearch any](parts ...QueryPart) *FindApiOption {
-	applier := search.Applier[TSearch]()
+// Fields without an explicit column resolve to the columns of the model through its field mapping.
+func withSearchApplier[TModel, TSearch any](parts ...QueryPart) *FindApiOption {
+	applier := search.Applier[TSearch](search.WithFieldMapping(fieldmap.For[TModel]()))
 
 	return &FindApiOption{
 		Parts: resolveQueryParts(parts...),
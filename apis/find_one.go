@@ -23,6 +23,7 @@ func (a *findOneApi[TModel, TSearch]) findOne(db orm.DB) (func(ctx fiber.Ctx, db
 			Condition:         []QueryPart{QueryRoot},
 			Sort:              []QueryPart{QueryRoot},
 			AuditUserRelation: []QueryPart{QueryRoot},
+			Include:           []QueryPart{QueryRoot},
 		},
 	}); err != nil {
 		return nil, err
@@ -18,6 +18,7 @@ type findPageApi[TModel, TSearch any] struct {
 	Find[TModel, TSearch, []TModel, FindPage[TModel, TSearch]]
 
 	defaultPageSize int
+	maxPageSize     int
 }
 
 func (a *findPageApi[TModel, TSearch]) Provide() []api.OperationSpec {
@@ -31,19 +32,27 @@ func (a *findPageApi[TModel, TSearch]) WithDefaultPageSize(size int) FindPage[TM
 	return a
 }
 
+// Larger requested sizes are reduced to this value.
+func (a *findPageApi[TModel, TSearch]) WithMaxPageSize(size int) FindPage[TModel, TSearch] {
+	a.maxPageSize = size
+
+	return a
+}
+
 func (a *findPageApi[TModel, TSearch]) findPage(db orm.DB) (func(ctx fiber.Ctx, db orm.DB, transformer mold.Transformer, pageable page.Pageable, search TSearch, meta api.Meta) error, error) {
 	if err := a.Setup(db, &FindApiConfig{
 		QueryParts: &QueryPartsConfig{
 			Condition:         []QueryPart{QueryRoot},
 			Sort:              []QueryPart{QueryRoot},
 			AuditUserRelation: []QueryPart{QueryRoot},
+			Include:           []QueryPart{QueryRoot},
 		},
 	}); err != nil {
 		return nil, err
 	}
 
 	return func(ctx fiber.Ctx, db orm.DB, transformer mold.Transformer, pageable page.Pageable, search TSearch, meta api.Meta) (err error) {
-		pageable.Normalize(a.defaultPageSize)
+		pageable.NormalizeWithin(a.defaultPageSize, a.maxPageSize)
 
 		var (
 			models []TModel
@@ -81,6 +81,22 @@ func NewAuditUserTestUserFindPageResource() api.Resource {
 	}
 }
 
+// Bounded User Resource - with max page size and sort mapping.
+type BoundedUserFindPageResource struct {
+	api.Resource
+	apis.FindPage[TestUser, TestUserSearch]
+}
+
+func NewBoundedUserFindPageResource() api.Resource {
+	return &BoundedUserFindPageResource{
+		Resource: api.NewRPCResource("test/user_page_bounded"),
+		FindPage: apis.NewFindPage[TestUser, TestUserSearch]().
+			WithMaxPageSize(3).
+			WithSortMapping(map[string]string{"userName": "name"}).
+			Public(),
+	}
+}
+
 // FindPageTestSuite tests the FindPage API functionality
 // including basic pagination, search filters, processors, filter appliers, audit user names, and negative cases.
 type FindPageTestSuite struct {
@@ -94,6 +110,7 @@ func (suite *FindPageTestSuite) SetupSuite() {
 		NewProcessedUserFindPageResource,
 		NewFilteredUserFindPageResource,
 		NewAuditUserTestUserFindPageResource,
+		NewBoundedUserFindPageResource,
 	)
 }
 
@@ -470,3 +487,149 @@ func (suite *FindPageTestSuite) TestFindPageWithAuditUserNames() {
 
 	suite.T().Logf("Found %d users with audit user names populated on page %v (size=%v, total=%v)", len(items), page["page"], page["size"], page["total"])
 }
+
+// TestFindPageBounds tests the max page size and the sort field policy.
+func (suite *FindPageTestSuite) TestFindPageBounds() {
+	suite.T().Logf("Testing FindPage API bounds for %s", suite.dbType)
+
+	suite.Run("PageSizeCappedAtFrameworkMaximum", func() {
+		resp := suite.makeApiRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "test/user_page",
+				Action:   "find_page",
+				Version:  "v1",
+			},
+			Meta: map[string]any{
+				"page": 1,
+				"size": 100000,
+			},
+		})
+
+		body := suite.readBody(resp)
+		suite.True(body.IsOk(), "Should return successful response")
+
+		page := suite.readDataAsMap(body.Data)
+		suite.Equal(float64(1000), page["size"], "Size should be capped at the framework maximum")
+	})
+
+	suite.Run("PageSizeCappedAtApiMaximum", func() {
+		resp := suite.makeApiRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "test/user_page_bounded",
+				Action:   "find_page",
+				Version:  "v1",
+			},
+			Meta: map[string]any{
+				"page": 1,
+				"size": 100000,
+			},
+		})
+
+		body := suite.readBody(resp)
+		suite.True(body.IsOk(), "Should return successful response")
+
+		page := suite.readDataAsMap(body.Data)
+		suite.Equal(float64(3), page["size"], "Size should be capped at the Api maximum")
+		suite.Len(suite.readDataAsSlice(page["items"]), 3, "Should return at most 3 items")
+	})
+
+	suite.Run("SortByModelColumn", func() {
+		resp := suite.makeApiRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "test/user_page",
+				Action:   "find_page",
+				Version:  "v1",
+			},
+			Meta: map[string]any{
+				"page": 1,
+				"size": 10,
+				"sort": []map[string]any{{"column": "email", "direction": "asc"}},
+			},
+		})
+
+		body := suite.readBody(resp)
+		suite.True(body.IsOk(), "Should sort by a column of the model")
+	})
+
+	suite.Run("SortByApiField", func() {
+		resp := suite.makeApiRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "test/user_page",
+				Action:   "find_page",
+				Version:  "v1",
+			},
+			Meta: map[string]any{
+				"page": 1,
+				"size": 10,
+				"sort": []map[string]any{{"column": "createdAt", "direction": "desc"}},
+			},
+		})
+
+		body := suite.readBody(resp)
+		suite.True(body.IsOk(), "Should sort by an Api field mapped to a column of the model")
+	})
+
+	suite.Run("SortByUnknownColumnRejected", func() {
+		resp := suite.makeApiRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "test/user_page",
+				Action:   "find_page",
+				Version:  "v1",
+			},
+			Meta: map[string]any{
+				"page": 1,
+				"size": 10,
+				"sort": []map[string]any{{"column": "(select 1)", "direction": "asc"}},
+			},
+		})
+
+		suite.Equal(200, resp.StatusCode, "Should return 200 status code")
+		body := suite.readBody(resp)
+		suite.False(body.IsOk(), "Should reject sorting by an arbitrary expression")
+		suite.Equal(result.ErrCodeBadRequest, body.Code)
+	})
+
+	suite.Run("SortByMappedField", func() {
+		resp := suite.makeApiRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "test/user_page_bounded",
+				Action:   "find_page",
+				Version:  "v1",
+			},
+			Meta: map[string]any{
+				"page": 1,
+				"size": 3,
+				"sort": []map[string]any{{"column": "userName", "direction": "desc"}},
+			},
+		})
+
+		body := suite.readBody(resp)
+		suite.True(body.IsOk(), "Should sort by a mapped field")
+
+		items := suite.readDataAsSlice(suite.readDataAsMap(body.Data)["items"])
+		suite.Require().Len(items, 3)
+
+		first := suite.readDataAsMap(items[0])["name"].(string)
+		last := suite.readDataAsMap(items[2])["name"].(string)
+		suite.True(first > last, "Names should be sorted descending")
+	})
+
+	suite.Run("SortByUnmappedColumnRejected", func() {
+		resp := suite.makeApiRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "test/user_page_bounded",
+				Action:   "find_page",
+				Version:  "v1",
+			},
+			Meta: map[string]any{
+				"page": 1,
+				"size": 3,
+				"sort": []map[string]any{{"column": "email", "direction": "asc"}},
+			},
+		})
+
+		body := suite.readBody(resp)
+		suite.False(body.IsOk(), "Should reject columns outside the mapping")
+		suite.Equal(result.ErrCodeBadRequest, body.Code)
+	})
+}
@@ -21,6 +21,10 @@ type Builder[T any] interface {
 	Public() T
 	PermToken(token string) T
 	RateLimit(maxRequests int, period time.Duration) T
+	AllowIPs(entries ...string) T
+	DenyIPs(entries ...string) T
+	// Cache caches the successful responses of idempotent endpoints, see api.CacheConfig.
+	Cache(config api.CacheConfig) T
 	Build(handler any) api.OperationSpec
 }
 
@@ -123,6 +127,14 @@ type Find[TModel, TSearch, TProcessorIn, TApi any] interface {
 	// WithSelectAs adds a column with an alias to the SELECT clause for specified query parts.
 	WithSelectAs(column, alias string, parts ...QueryPart) TApi
 	WithDefaultSort(sort ...*sortx.OrderSpec) TApi
+	// WithSortableFields restricts request sorting to the given columns instead of the model columns.
+	WithSortableFields(fields ...string) TApi
+	// WithSortMapping maps sort field names accepted in requests to database columns.
+	WithSortMapping(mapping map[string]string) TApi
+	// WithIncludes admits exactly the given relation paths in the include request parameter.
+	WithIncludes(relations ...string) TApi
+	// WithMaxIncludeDepth limits the nesting of the relations requests may include.
+	WithMaxIncludeDepth(depth int) TApi
 	// WithCondition adds a WHERE condition using ConditionBuilder for specified query parts.
 	WithCondition(fn func(cb orm.ConditionBuilder), parts ...QueryPart) TApi
 	// DisableDataPerm disables automatic data permission filtering for this endpoint.
@@ -157,6 +169,8 @@ type FindPage[TModel, TSearch any] interface {
 	Find[TModel, TSearch, []TModel, FindPage[TModel, TSearch]]
 
 	WithDefaultPageSize(size int) FindPage[TModel, TSearch]
+	// WithMaxPageSize caps the page size requests may ask for, at most page.MaxPageSize.
+	WithMaxPageSize(size int) FindPage[TModel, TSearch]
 }
 
 // FindTree provides a fluent interface for building find tree endpoints.
@@ -22,6 +22,8 @@ type QueryPartsConfig struct {
 	Sort []QueryPart
 	// AuditUserRelation specifies which queries auto-join audit user relations (created_by, updated_by)
 	AuditUserRelation []QueryPart
+	// Include specifies which queries load the relations requested by the include parameter
+	Include []QueryPart
 }
 
 // CreateManyParams is a wrapper type for batch create parameters.
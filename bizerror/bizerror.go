@@ -0,0 +1,148 @@
+package bizerror
+
+import (
+	"errors"
+	"maps"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/i18n"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+// Error is a domain error carrying a business code and an i18n message key.
+// The message is resolved with the current language when the error is rendered,
+// template args fill the placeholders of the message (e.g. {{.orderNo}}).
+// The optional cause is kept for logging and errors.Is/As but never exposed to clients.
+//
+// Errors are usually declared once as sentinels and specialized per occurrence:
+//
+//	var ErrOrderNotFound = bizerror.New(20001, "order_not_found", bizerror.WithStatus(fiber.StatusNotFound))
+//
+//	return ErrOrderNotFound.WithArgs(map[string]any{"orderNo": no}).Wrap(err)
+type Error struct {
+	code       int
+	messageKey string
+	args       map[string]any
+	status     int
+	grpcCode   GRPCCode
+	cause      error
+}
+
+// New creates a domain error with the business code and the i18n key of its message.
+// The HTTP status defaults to 200, consistent with result.Error.
+func New(code int, messageKey string, opts ...Option) *Error {
+	err := &Error{
+		code:       code,
+		messageKey: messageKey,
+		status:     fiber.StatusOK,
+		grpcCode:   grpcCodeUnset,
+	}
+
+	for _, opt := range opts {
+		opt(err)
+	}
+
+	return err
+}
+
+// Wrap creates a domain error caused by err.
+func Wrap(cause error, code int, messageKey string, opts ...Option) *Error {
+	err := New(code, messageKey, opts...)
+	err.cause = cause
+
+	return err
+}
+
+// As extracts the first Error in the chain of err.
+func As(err error) (*Error, bool) {
+	var target *Error
+	if errors.As(err, &target) {
+		return target, true
+	}
+
+	return nil, false
+}
+
+// Error returns the message key followed by the cause, intended for logs rather than clients.
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.messageKey + ": " + e.cause.Error()
+	}
+
+	return e.messageKey
+}
+
+// Unwrap returns the cause.
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is an Error with the same code and message key,
+// so that specialized copies of a sentinel still match it.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+
+	return ok && t.code == e.code && t.messageKey == e.messageKey
+}
+
+// Code returns the business code.
+func (e *Error) Code() int {
+	return e.code
+}
+
+// MessageKey returns the i18n key of the message.
+func (e *Error) MessageKey() string {
+	return e.messageKey
+}
+
+// Args returns a copy of the message template args.
+func (e *Error) Args() map[string]any {
+	return maps.Clone(e.args)
+}
+
+// Status returns the HTTP status.
+func (e *Error) Status() int {
+	return e.status
+}
+
+// Message resolves the message in the current language.
+// The message key itself is returned when no translation exists.
+func (e *Error) Message() string {
+	if len(e.args) == 0 {
+		return i18n.T(e.messageKey)
+	}
+
+	return i18n.T(e.messageKey, e.args)
+}
+
+// WithArgs returns a copy of the error with the template args merged into its args.
+func (e *Error) WithArgs(args map[string]any) *Error {
+	clone := *e
+	clone.args = maps.Clone(e.args)
+
+	if clone.args == nil {
+		clone.args = make(map[string]any, len(args))
+	}
+
+	maps.Copy(clone.args, args)
+
+	return &clone
+}
+
+// Wrap returns a copy of the error caused by cause.
+func (e *Error) Wrap(cause error) *Error {
+	clone := *e
+	clone.cause = cause
+
+	return &clone
+}
+
+// ResultError translates the error into the API error envelope.
+func (e *Error) ResultError() result.Error {
+	return result.Error{
+		Code:    e.code,
+		Message: e.Message(),
+		Status:  e.status,
+	}
+}
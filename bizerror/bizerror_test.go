@@ -0,0 +1,128 @@
+package bizerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/i18n"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+var errOrderLocked = New(30001, "unsupported_authentication_type", WithStatus(fiber.StatusConflict))
+
+// TestNew tests creating domain errors.
+func TestNew(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		err := New(30000, "order_not_found")
+
+		assert.Equal(t, 30000, err.Code())
+		assert.Equal(t, "order_not_found", err.MessageKey())
+		assert.Equal(t, fiber.StatusOK, err.Status(), "Should default to status 200")
+		assert.Nil(t, err.Unwrap())
+		assert.Equal(t, "order_not_found", err.Error())
+	})
+
+	t.Run("UntranslatedKey", func(t *testing.T) {
+		err := New(30000, "order_not_found")
+
+		assert.Equal(t, "order_not_found", err.Message(), "Should fall back to the message key")
+	})
+}
+
+// TestMessage tests resolving the message in the current language.
+func TestMessage(t *testing.T) {
+	require.NoError(t, i18n.SetLanguage("en"))
+
+	defer func() {
+		_ = i18n.SetLanguage("")
+	}()
+
+	err := errOrderLocked.WithArgs(map[string]any{"kind": "ticket"})
+	assert.Equal(t, "Unsupported authentication type: ticket", err.Message())
+
+	require.NoError(t, i18n.SetLanguage("zh-CN"))
+	assert.NotEqual(t, "Unsupported authentication type: ticket", err.Message(), "Should resolve with the current language")
+}
+
+// TestWithArgs tests that specialized copies leave the sentinel untouched.
+func TestWithArgs(t *testing.T) {
+	first := errOrderLocked.WithArgs(map[string]any{"kind": "a"})
+	second := first.WithArgs(map[string]any{"extra": 1})
+
+	assert.Nil(t, errOrderLocked.Args(), "Sentinel should stay unchanged")
+	assert.Equal(t, map[string]any{"kind": "a"}, first.Args())
+	assert.Equal(t, map[string]any{"kind": "a", "extra": 1}, second.Args())
+}
+
+// TestCauseChaining tests wrapping causes and matching sentinels.
+func TestCauseChaining(t *testing.T) {
+	cause := errors.New("row locked")
+
+	t.Run("WrapMethod", func(t *testing.T) {
+		err := errOrderLocked.WithArgs(map[string]any{"kind": "a"}).Wrap(cause)
+
+		assert.ErrorIs(t, err, cause, "Should unwrap to the cause")
+		assert.ErrorIs(t, err, errOrderLocked, "Should match the sentinel")
+		assert.Equal(t, "unsupported_authentication_type: row locked", err.Error())
+		assert.Nil(t, errOrderLocked.Unwrap(), "Sentinel should stay unchanged")
+	})
+
+	t.Run("WrapFunction", func(t *testing.T) {
+		err := Wrap(cause, 30001, "unsupported_authentication_type")
+
+		assert.ErrorIs(t, err, cause)
+		assert.ErrorIs(t, err, errOrderLocked, "Should match errors with the same code and key")
+		assert.NotErrorIs(t, err, New(30002, "unsupported_authentication_type"))
+	})
+
+	t.Run("As", func(t *testing.T) {
+		wrapped := fmt.Errorf("service failed: %w", errOrderLocked.Wrap(cause))
+
+		bizErr, ok := As(wrapped)
+		require.True(t, ok)
+		assert.Equal(t, 30001, bizErr.Code())
+
+		_, ok = As(cause)
+		assert.False(t, ok)
+	})
+}
+
+// TestResultError tests the translation into the API envelope.
+func TestResultError(t *testing.T) {
+	wrapped := fmt.Errorf("handler: %w", errOrderLocked.Wrap(result.ErrRecordNotFound))
+
+	resultErr, ok := result.AsErr(wrapped)
+	require.True(t, ok, "Should convert domain errors")
+	assert.Equal(t, 30001, resultErr.Code, "Should prefer the domain error over its cause")
+	assert.Equal(t, fiber.StatusConflict, resultErr.Status)
+	assert.Equal(t, errOrderLocked.Message(), resultErr.Message)
+}
+
+// TestGRPCCode tests the gRPC status code mapping.
+func TestGRPCCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *Error
+		expected GRPCCode
+	}{
+		{"BusinessError", New(1, "k"), GRPCCodeFailedPrecondition},
+		{"NotFound", New(1, "k", WithStatus(fiber.StatusNotFound)), GRPCCodeNotFound},
+		{"Conflict", New(1, "k", WithStatus(fiber.StatusConflict)), GRPCCodeAlreadyExists},
+		{"Unauthorized", New(1, "k", WithStatus(fiber.StatusUnauthorized)), GRPCCodeUnauthenticated},
+		{"UnmappedServerError", New(1, "k", WithStatus(fiber.StatusBadGateway)), GRPCCodeInternal},
+		{"UnmappedClientError", New(1, "k", WithStatus(fiber.StatusTeapot)), GRPCCodeUnknown},
+		{"Explicit", New(1, "k", WithStatus(fiber.StatusConflict), WithGRPCCode(GRPCCodeAborted)), GRPCCodeAborted},
+		{"ExplicitOK", New(1, "k", WithGRPCCode(GRPCCodeOK)), GRPCCodeOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.err.GRPCCode())
+		})
+	}
+}
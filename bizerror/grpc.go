@@ -0,0 +1,68 @@
+package bizerror
+
+import "github.com/gofiber/fiber/v3"
+
+// GRPCCode is a gRPC status code. The values equal those of google.golang.org/grpc/codes,
+// so codes.Code(err.GRPCCode()) converts without a mapping table.
+type GRPCCode uint32
+
+const (
+	GRPCCodeOK                 GRPCCode = 0
+	GRPCCodeCanceled           GRPCCode = 1
+	GRPCCodeUnknown            GRPCCode = 2
+	GRPCCodeInvalidArgument    GRPCCode = 3
+	GRPCCodeDeadlineExceeded   GRPCCode = 4
+	GRPCCodeNotFound           GRPCCode = 5
+	GRPCCodeAlreadyExists      GRPCCode = 6
+	GRPCCodePermissionDenied   GRPCCode = 7
+	GRPCCodeResourceExhausted  GRPCCode = 8
+	GRPCCodeFailedPrecondition GRPCCode = 9
+	GRPCCodeAborted            GRPCCode = 10
+	GRPCCodeOutOfRange         GRPCCode = 11
+	GRPCCodeUnimplemented      GRPCCode = 12
+	GRPCCodeInternal           GRPCCode = 13
+	GRPCCodeUnavailable        GRPCCode = 14
+	GRPCCodeDataLoss           GRPCCode = 15
+	GRPCCodeUnauthenticated    GRPCCode = 16
+
+	grpcCodeUnset GRPCCode = 1<<32 - 1
+)
+
+// statusGRPCCodes maps HTTP statuses to gRPC codes following the gRPC HTTP mapping.
+var statusGRPCCodes = map[int]GRPCCode{
+	fiber.StatusBadRequest:          GRPCCodeInvalidArgument,
+	fiber.StatusUnauthorized:        GRPCCodeUnauthenticated,
+	fiber.StatusForbidden:           GRPCCodePermissionDenied,
+	fiber.StatusNotFound:            GRPCCodeNotFound,
+	fiber.StatusRequestTimeout:      GRPCCodeDeadlineExceeded,
+	fiber.StatusConflict:            GRPCCodeAlreadyExists,
+	fiber.StatusPreconditionFailed:  GRPCCodeFailedPrecondition,
+	fiber.StatusTooManyRequests:     GRPCCodeResourceExhausted,
+	fiber.StatusInternalServerError: GRPCCodeInternal,
+	fiber.StatusNotImplemented:      GRPCCodeUnimplemented,
+	fiber.StatusServiceUnavailable:  GRPCCodeUnavailable,
+	fiber.StatusGatewayTimeout:      GRPCCodeDeadlineExceeded,
+}
+
+// GRPCCode returns the gRPC status code of the error.
+// Unless set with WithGRPCCode it is derived from the HTTP status;
+// business errors answered with 200 map to FailedPrecondition, as the request was valid but rejected by a rule.
+func (e *Error) GRPCCode() GRPCCode {
+	if e.grpcCode != grpcCodeUnset {
+		return e.grpcCode
+	}
+
+	if e.status == fiber.StatusOK {
+		return GRPCCodeFailedPrecondition
+	}
+
+	if code, ok := statusGRPCCodes[e.status]; ok {
+		return code
+	}
+
+	if e.status >= fiber.StatusInternalServerError {
+		return GRPCCodeInternal
+	}
+
+	return GRPCCodeUnknown
+}
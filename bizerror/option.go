@@ -0,0 +1,19 @@
+package bizerror
+
+// Option configures an Error.
+type Option func(*Error)
+
+// WithArgs sets the template args of the message.
+func WithArgs(args map[string]any) Option {
+	return func(e *Error) { e.args = args }
+}
+
+// WithStatus sets the HTTP status.
+func WithStatus(status int) Option {
+	return func(e *Error) { e.status = status }
+}
+
+// WithGRPCCode sets the gRPC status code, overriding the one derived from the HTTP status.
+func WithGRPCCode(code GRPCCode) Option {
+	return func(e *Error) { e.grpcCode = code }
+}
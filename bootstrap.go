@@ -8,20 +8,28 @@ import (
 
 	"github.com/ilxqx/vef-framework-go/internal/api"
 	"github.com/ilxqx/vef-framework-go/internal/app"
+	"github.com/ilxqx/vef-framework-go/internal/calendar"
 	"github.com/ilxqx/vef-framework-go/internal/config"
 	"github.com/ilxqx/vef-framework-go/internal/cron"
 	"github.com/ilxqx/vef-framework-go/internal/database"
+	"github.com/ilxqx/vef-framework-go/internal/datasync"
+	"github.com/ilxqx/vef-framework-go/internal/delayjob"
 	"github.com/ilxqx/vef-framework-go/internal/event"
+	"github.com/ilxqx/vef-framework-go/internal/integrity"
 	ilog "github.com/ilxqx/vef-framework-go/internal/log"
 	"github.com/ilxqx/vef-framework-go/internal/mcp"
+	"github.com/ilxqx/vef-framework-go/internal/menu"
+	"github.com/ilxqx/vef-framework-go/internal/metering"
 	"github.com/ilxqx/vef-framework-go/internal/middleware"
 	"github.com/ilxqx/vef-framework-go/internal/mold"
 	"github.com/ilxqx/vef-framework-go/internal/monitor"
 	"github.com/ilxqx/vef-framework-go/internal/orm"
 	"github.com/ilxqx/vef-framework-go/internal/redis"
+	"github.com/ilxqx/vef-framework-go/internal/report"
 	"github.com/ilxqx/vef-framework-go/internal/schema"
 	"github.com/ilxqx/vef-framework-go/internal/security"
 	"github.com/ilxqx/vef-framework-go/internal/storage"
+	"github.com/ilxqx/vef-framework-go/internal/tenancy"
 	"github.com/ilxqx/vef-framework-go/log"
 )
 
@@ -42,12 +50,20 @@ func Run(options ...fx.Option) {
 		fx.WithLogger(newFxLogger),
 		config.Module,
 		database.Module,
+		tenancy.Module,
 		orm.Module,
 		middleware.Module,
 		api.Module,
 		security.Module,
 		event.Module,
 		cron.Module,
+		delayjob.Module,
+		metering.Module,
+		report.Module,
+		integrity.Module,
+		datasync.Module,
+		calendar.Module,
+		menu.Module,
 		redis.Module,
 		mold.Module,
 		storage.Module,
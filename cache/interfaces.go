@@ -17,6 +17,8 @@ type Cache[T any] interface {
 	GetOrLoad(ctx context.Context, key string, loader LoaderFunc[T], ttl ...time.Duration) (T, error)
 	// Set stores a value with the given key. If ttl is provided and > 0, the entry will expire after the duration.
 	Set(ctx context.Context, key string, value T, ttl ...time.Duration) error
+	// SetIfAbsent atomically stores a value unless the key exists, reporting whether it was stored.
+	SetIfAbsent(ctx context.Context, key string, value T, ttl ...time.Duration) (bool, error)
 	// Contains checks if a key exists in the cache.
 	Contains(ctx context.Context, key string) bool
 	// Delete removes a key from the cache.
@@ -151,9 +151,35 @@ func (m *memoryCache[T]) Set(_ context.Context, key string, value T, ttl ...time
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Check if key already exists (update case)
 	_, exists := m.data.Load(key)
 
+	return m.store(key, value, exists, ttl)
+}
+
+// SetIfAbsent stores a value unless the key exists and has not expired.
+func (m *memoryCache[T]) SetIfAbsent(_ context.Context, key string, value T, ttl ...time.Duration) (bool, error) {
+	if m.closed.Load() {
+		return false, ErrCacheClosed
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, exists := m.data.Load(key)
+	if exists && !m.checkExpired(key, entry) {
+		return false, nil
+	}
+
+	// An expired entry has just been removed, so the value is stored as a new entry
+	if err := m.store(key, value, false, ttl); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// store stores a value under the lock, exists telling whether it updates an entry.
+func (m *memoryCache[T]) store(key string, value T, exists bool, ttl []time.Duration) error {
 	// Handle memory limit and eviction only for new entries
 	if !exists && m.maxSize > 0 {
 		for m.size.Load() >= m.maxSize {
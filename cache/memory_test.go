@@ -123,6 +123,37 @@ func TestMemoryCacheBasicOperations(t *testing.T) {
 		assert.Equal(t, "value2", value)
 	})
 
+	t.Run("SetIfAbsent", func(t *testing.T) {
+		cache := newTestCache[string](0, 0, EvictionPolicyLRU, 5*time.Minute)
+		defer cache.Close()
+
+		stored, err := cache.SetIfAbsent(ctx, "key1", "value1")
+		require.NoError(t, err)
+		assert.True(t, stored)
+
+		stored, err = cache.SetIfAbsent(ctx, "key1", "value2")
+		require.NoError(t, err)
+		assert.False(t, stored, "Should not overwrite an existing key")
+
+		value, _ := cache.Get(ctx, "key1")
+		assert.Equal(t, "value1", value)
+	})
+
+	t.Run("SetIfAbsentReplacesExpiredKey", func(t *testing.T) {
+		cache := newTestCache[string](0, 0, EvictionPolicyLRU, 0)
+		defer cache.Close()
+
+		_ = cache.Set(ctx, "key1", "value1", 10*time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+
+		stored, err := cache.SetIfAbsent(ctx, "key1", "value2")
+		require.NoError(t, err)
+		assert.True(t, stored, "Should store over an expired key")
+
+		size, _ := cache.Size(ctx)
+		assert.Equal(t, int64(1), size)
+	})
+
 	t.Run("GetOrLoadUsesLoaderOnce", func(t *testing.T) {
 		cache := newTestCache[string](0, 0, EvictionPolicyLRU, 5*time.Minute)
 		defer cache.Close()
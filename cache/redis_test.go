@@ -101,6 +101,21 @@ func (suite *RedisCacheTestSuite) TestRedisCacheBasicOperations() {
 		suite.False(userCache.Contains(suite.ctx, "nonexistent"))
 	})
 
+	suite.Run("SetIfAbsent", func() {
+		user := TestUser{ID: 5, Name: "Eve", Age: 28}
+
+		stored, err := userCache.SetIfAbsent(suite.ctx, "user5", user)
+		suite.Require().NoError(err)
+		suite.True(stored)
+
+		stored, err = userCache.SetIfAbsent(suite.ctx, "user5", TestUser{ID: 5, Name: "Mallory"})
+		suite.Require().NoError(err)
+		suite.False(stored, "Should not overwrite an existing key")
+
+		result, _ := userCache.Get(suite.ctx, "user5")
+		suite.Equal(user, result)
+	})
+
 	suite.Run("Delete", func() {
 		user := TestUser{ID: 3, Name: "Charlie", Age: 35}
 
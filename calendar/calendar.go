@@ -0,0 +1,188 @@
+package calendar
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/cache"
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/event"
+	ilog "github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/log"
+)
+
+const (
+	// eventTypeHolidaysChanged is the event type for holiday table changes.
+	// When this event is published, the cached holidays of the affected years are reloaded on next use.
+	eventTypeHolidaysChanged = "vef.calendar.holidays.changed"
+	// maxScanDays bounds the day-by-day scan of AddWorkdays so a misconfigured calendar
+	// without any working day cannot loop forever.
+	maxScanDays = 366 * 10
+)
+
+// HolidaysChangedEvent is published when the holiday table is modified.
+type HolidaysChangedEvent struct {
+	event.BaseEvent
+
+	Years []int `json:"years"` // Affected years (empty means all years)
+}
+
+// PublishHolidaysChangedEvent publishes a holidays changed event via the provided publisher.
+// If no years are specified, subscribers should interpret the event as affecting all years.
+func PublishHolidaysChangedEvent(publisher event.Publisher, years ...int) {
+	publisher.Publish(&HolidaysChangedEvent{
+		BaseEvent: event.NewBaseEvent(eventTypeHolidaysChanged),
+
+		Years: years,
+	})
+}
+
+// Option configures a business calendar.
+type Option func(*businessCalendar)
+
+// WithWeekend overrides the days treated as weekend (Saturday and Sunday by default).
+func WithWeekend(days ...time.Weekday) Option {
+	return func(c *businessCalendar) {
+		c.weekend = make(map[time.Weekday]bool, len(days))
+		for _, day := range days {
+			c.weekend[day] = true
+		}
+	}
+}
+
+// businessCalendar implements Calendar on top of a HolidayLoader with per-year caching.
+type businessCalendar struct {
+	loader    HolidayLoader
+	weekend   map[time.Weekday]bool
+	yearCache cache.Cache[map[string]bool]
+	logger    log.Logger
+}
+
+// New creates a business calendar backed by the given holiday loader.
+// Holidays are cached per year and invalidated by HolidaysChangedEvent when a subscriber is given.
+func New(loader HolidayLoader, subscriber event.Subscriber, opts ...Option) Calendar {
+	c := &businessCalendar{
+		loader:    loader,
+		weekend:   map[time.Weekday]bool{time.Saturday: true, time.Sunday: true},
+		yearCache: cache.NewMemory[map[string]bool](),
+		logger:    ilog.Named("calendar"),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if subscriber != nil {
+		subscriber.Subscribe(eventTypeHolidaysChanged, c.handleHolidaysChanged)
+	}
+
+	return c
+}
+
+func (c *businessCalendar) handleHolidaysChanged(ctx context.Context, evt event.Event) {
+	changeEvent, ok := evt.(*HolidaysChangedEvent)
+	if !ok {
+		c.logger.Errorf("Received invalid event type: %T", evt)
+
+		return
+	}
+
+	if len(changeEvent.Years) == 0 {
+		if err := c.yearCache.Clear(ctx); err != nil {
+			c.logger.Errorf("Failed to clear holiday cache: %v", err)
+		} else {
+			c.logger.Info("Cleared holiday cache")
+		}
+
+		return
+	}
+
+	for _, year := range changeEvent.Years {
+		if err := c.yearCache.Delete(ctx, strconv.Itoa(year)); err != nil {
+			c.logger.Errorf("Failed to delete holiday cache for year %d: %v", year, err)
+		} else {
+			c.logger.Infof("Cleared holiday cache for year: %d", year)
+		}
+	}
+}
+
+func (c *businessCalendar) IsWorkday(ctx context.Context, date datetime.Date) (bool, error) {
+	overrides, err := c.loadYear(ctx, date.Year())
+	if err != nil {
+		return false, err
+	}
+
+	if isWorkday, ok := overrides[date.String()]; ok {
+		return isWorkday, nil
+	}
+
+	return !c.weekend[date.Weekday()], nil
+}
+
+func (c *businessCalendar) AddWorkdays(ctx context.Context, date datetime.Date, days int) (datetime.Date, error) {
+	step := 1
+	if days < 0 {
+		step = -1
+		days = -days
+	}
+
+	current := date
+	for scanned := 0; days > 0; scanned++ {
+		if scanned >= maxScanDays {
+			return date, ErrNoWorkdayInRange
+		}
+
+		current = current.AddDays(step)
+
+		isWorkday, err := c.IsWorkday(ctx, current)
+		if err != nil {
+			return date, err
+		}
+
+		if isWorkday {
+			days--
+		}
+	}
+
+	return current, nil
+}
+
+func (c *businessCalendar) WorkdaysBetween(ctx context.Context, start, end datetime.Date) (int, error) {
+	// Count (start, end] forwards and [end, start) backwards, mirroring AddWorkdays
+	sign, from, to := 1, start.AddDays(1), end
+	if end.Before(start) {
+		sign, from, to = -1, end, start.AddDays(-1)
+	}
+
+	count := 0
+	for current := from; !current.After(to); current = current.AddDays(1) {
+		isWorkday, err := c.IsWorkday(ctx, current)
+		if err != nil {
+			return 0, err
+		}
+
+		if isWorkday {
+			count++
+		}
+	}
+
+	return sign * count, nil
+}
+
+// loadYear returns the working-day overrides of a year keyed by date string.
+func (c *businessCalendar) loadYear(ctx context.Context, year int) (map[string]bool, error) {
+	return c.yearCache.GetOrLoad(ctx, strconv.Itoa(year), func(ctx context.Context) (map[string]bool, error) {
+		holidays, err := c.loader.LoadHolidays(ctx, year)
+		if err != nil {
+			return nil, err
+		}
+
+		overrides := make(map[string]bool, len(holidays))
+		for _, holiday := range holidays {
+			overrides[holiday.Date.String()] = holiday.IsWorkday
+		}
+
+		return overrides, nil
+	})
+}
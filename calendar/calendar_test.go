@@ -0,0 +1,240 @@
+package calendar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	ievent "github.com/ilxqx/vef-framework-go/internal/event"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+type MockHolidayLoader struct {
+	mock.Mock
+}
+
+func (m *MockHolidayLoader) LoadHolidays(ctx context.Context, year int) ([]Holiday, error) {
+	args := m.Called(ctx, year)
+
+	return args.Get(0).([]Holiday), args.Error(1)
+}
+
+// Task is a test model with a due date column.
+type Task struct {
+	orm.BaseModel `bun:"table:test_task,alias:tk"`
+
+	ID      string        `bun:"id,pk"`
+	DueDate datetime.Date `bun:"due_date,notnull,type:date"`
+}
+
+func date(value string) datetime.Date {
+	d, err := datetime.ParseDate(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// october2026 holds the National Day holiday on Thursday 2026-10-01 and an adjusted working day on Saturday 2026-10-10.
+var october2026 = []Holiday{
+	{Date: date("2026-10-01"), Name: "National Day"},
+	{Date: date("2026-10-10"), Name: "National Day (adjusted)", IsWorkday: true},
+}
+
+type CalendarTestSuite struct {
+	suite.Suite
+
+	ctx context.Context
+	bus event.Bus
+}
+
+func (s *CalendarTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	s.bus = ievent.NewMemoryBus([]event.Middleware{})
+	err := s.bus.(interface{ Start() error }).Start()
+	s.Require().NoError(err, "Should start event bus")
+}
+
+func (s *CalendarTestSuite) newCalendar(opts ...Option) (Calendar, *MockHolidayLoader) {
+	loader := new(MockHolidayLoader)
+	loader.On("LoadHolidays", mock.Anything, 2026).Return(october2026, nil)
+
+	return New(loader, s.bus, opts...), loader
+}
+
+func (s *CalendarTestSuite) TestIsWorkday() {
+	cal, loader := s.newCalendar()
+
+	cases := map[string]bool{
+		"2026-09-30": true,  // Wednesday
+		"2026-10-01": false, // Holiday on a Thursday
+		"2026-10-03": false, // Saturday
+		"2026-10-10": true,  // Adjusted working Saturday
+	}
+
+	for value, expected := range cases {
+		isWorkday, err := cal.IsWorkday(s.ctx, date(value))
+		s.Require().NoError(err)
+		s.Equal(expected, isWorkday, "Unexpected working day flag for %s", value)
+	}
+
+	loader.AssertNumberOfCalls(s.T(), "LoadHolidays", 1)
+}
+
+func (s *CalendarTestSuite) TestAddWorkdays() {
+	cal, _ := s.newCalendar()
+
+	result, err := cal.AddWorkdays(s.ctx, date("2026-09-30"), 2)
+	s.Require().NoError(err)
+	s.Equal("2026-10-05", result.String(), "Should skip the holiday and the weekend")
+
+	result, err = cal.AddWorkdays(s.ctx, date("2026-10-09"), 1)
+	s.Require().NoError(err)
+	s.Equal("2026-10-10", result.String(), "Should land on the adjusted working Saturday")
+
+	result, err = cal.AddWorkdays(s.ctx, date("2026-10-05"), -2)
+	s.Require().NoError(err)
+	s.Equal("2026-09-30", result.String(), "Should move backwards over weekend and holiday")
+
+	result, err = cal.AddWorkdays(s.ctx, date("2026-10-03"), 0)
+	s.Require().NoError(err)
+	s.Equal("2026-10-03", result.String(), "Adding zero days should keep the date")
+}
+
+func (s *CalendarTestSuite) TestWorkdaysBetween() {
+	cal, _ := s.newCalendar()
+
+	count, err := cal.WorkdaysBetween(s.ctx, date("2026-09-30"), date("2026-10-12"))
+	s.Require().NoError(err)
+	s.Equal(8, count, "Should count working days in (start, end]")
+
+	count, err = cal.WorkdaysBetween(s.ctx, date("2026-10-05"), date("2026-09-30"))
+	s.Require().NoError(err)
+	s.Equal(-2, count, "Should return a negative count when end is before start")
+
+	for _, days := range []int{-7, -1, 1, 5, 20} {
+		target, err := cal.AddWorkdays(s.ctx, date("2026-10-08"), days)
+		s.Require().NoError(err)
+
+		count, err := cal.WorkdaysBetween(s.ctx, date("2026-10-08"), target)
+		s.Require().NoError(err)
+		s.Equal(days, count, "WorkdaysBetween should invert AddWorkdays")
+	}
+}
+
+func (s *CalendarTestSuite) TestCustomWeekend() {
+	cal, _ := s.newCalendar(WithWeekend(time.Friday, time.Saturday))
+
+	isWorkday, err := cal.IsWorkday(s.ctx, date("2026-10-04"))
+	s.Require().NoError(err)
+	s.True(isWorkday, "Sunday should be a working day with a Friday/Saturday weekend")
+
+	isWorkday, err = cal.IsWorkday(s.ctx, date("2026-10-02"))
+	s.Require().NoError(err)
+	s.False(isWorkday, "Friday should be a weekend day")
+}
+
+func (s *CalendarTestSuite) TestCacheInvalidation() {
+	cal, loader := s.newCalendar()
+
+	_, err := cal.IsWorkday(s.ctx, date("2026-10-01"))
+	s.Require().NoError(err)
+
+	PublishHolidaysChangedEvent(s.bus, 2026)
+
+	s.Eventually(func() bool {
+		_, err := cal.IsWorkday(s.ctx, date("2026-10-01"))
+		s.Require().NoError(err)
+
+		return len(loader.Calls) == 2
+	}, time.Second, 10*time.Millisecond, "Should reload holidays after the change event")
+}
+
+func (s *CalendarTestSuite) TestDBLoaderAndWhereWorkday() {
+	bunDB, err := database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	defer bunDB.Close()
+
+	bunDB.RegisterModel((*Holiday)(nil), (*Task)(nil))
+
+	for _, model := range []any{(*Holiday)(nil), (*Task)(nil)} {
+		_, err = bunDB.NewCreateTable().Model(model).Exec(s.ctx)
+		s.Require().NoError(err)
+	}
+
+	holidays := []Holiday{october2026[0], october2026[1]}
+	for i := range holidays {
+		holidays[i].ID = holidays[i].Date.String()
+		holidays[i].CreatedBy = "system"
+		holidays[i].UpdatedBy = "system"
+	}
+
+	_, err = bunDB.NewInsert().Model(&holidays).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	tasks := []Task{
+		{ID: "holiday", DueDate: date("2026-10-01")},
+		{ID: "friday", DueDate: date("2026-10-02")},
+		{ID: "saturday", DueDate: date("2026-10-03")},
+		{ID: "adjusted", DueDate: date("2026-10-10")},
+	}
+	_, err = bunDB.NewInsert().Model(&tasks).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	db := iorm.New(bunDB)
+
+	s.Run("LoadHolidays", func() {
+		loaded, err := NewDBHolidayLoader(db).LoadHolidays(s.ctx, 2026)
+		s.Require().NoError(err)
+		s.Len(loaded, 2, "Should load both holiday entries of the year")
+		s.True(loaded[1].IsWorkday, "Adjusted working day should be flagged")
+
+		loaded, err = NewDBHolidayLoader(db).LoadHolidays(s.ctx, 2025)
+		s.Require().NoError(err)
+		s.Empty(loaded, "Should not load entries of other years")
+	})
+
+	s.Run("WhereWorkday", func() {
+		var result []Task
+
+		err := db.NewSelect().
+			Model(&result).
+			Where(WhereWorkday("due_date")).
+			OrderBy("due_date").
+			Scan(s.ctx)
+		s.Require().NoError(err)
+		s.Len(result, 2)
+		s.Equal("friday", result[0].ID)
+		s.Equal("adjusted", result[1].ID)
+	})
+
+	s.Run("WhereNotWorkday", func() {
+		var result []Task
+
+		err := db.NewSelect().
+			Model(&result).
+			Where(WhereNotWorkday("due_date")).
+			OrderBy("due_date").
+			Scan(s.ctx)
+		s.Require().NoError(err)
+		s.Len(result, 2)
+		s.Equal("holiday", result[0].ID)
+		s.Equal("saturday", result[1].ID)
+	})
+}
+
+func TestCalendarTestSuite(t *testing.T) {
+	suite.Run(t, new(CalendarTestSuite))
+}
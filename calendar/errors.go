@@ -0,0 +1,7 @@
+package calendar
+
+import "errors"
+
+// ErrNoWorkdayInRange is returned when no working day could be found within the scan limit,
+// which usually indicates a misconfigured weekend or holiday table.
+var ErrNoWorkdayInRange = errors.New("no working day found within the scan range")
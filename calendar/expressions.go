@@ -0,0 +1,80 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// defaultWeekend holds the weekend days used by the SQL helpers unless overridden.
+var defaultWeekend = []time.Weekday{time.Saturday, time.Sunday}
+
+// WhereWorkday returns a condition that matches rows whose date column falls on a working day,
+// evaluated in SQL against the holiday table:
+// the date is an adjusted working day, or it is neither a weekend day nor a holiday.
+func WhereWorkday(column string, weekend ...time.Weekday) orm.ApplyFunc[orm.ConditionBuilder] {
+	return func(cb orm.ConditionBuilder) {
+		cb.Group(func(cb orm.ConditionBuilder) {
+			cb.Expr(func(eb orm.ExprBuilder) any {
+				return eb.Expr("? IN ?", eb.ToDate(eb.Column(column)), eb.SubQuery(holidayDates(true)))
+			})
+			cb.OrGroup(func(cb orm.ConditionBuilder) {
+				cb.Expr(func(eb orm.ExprBuilder) any {
+					return eb.NotIn(eb.ExtractDayOfWeek(eb.Column(column)), isoWeekdays(weekend)...)
+				})
+				cb.Expr(func(eb orm.ExprBuilder) any {
+					return eb.Expr("? NOT IN ?", eb.ToDate(eb.Column(column)), eb.SubQuery(holidayDates(false)))
+				})
+			})
+		})
+	}
+}
+
+// WhereNotWorkday returns a condition that matches rows whose date column falls on a weekend day or holiday.
+func WhereNotWorkday(column string, weekend ...time.Weekday) orm.ApplyFunc[orm.ConditionBuilder] {
+	return func(cb orm.ConditionBuilder) {
+		cb.Group(func(cb orm.ConditionBuilder) {
+			cb.Expr(func(eb orm.ExprBuilder) any {
+				return eb.Expr("? IN ?", eb.ToDate(eb.Column(column)), eb.SubQuery(holidayDates(false)))
+			})
+			cb.OrGroup(func(cb orm.ConditionBuilder) {
+				cb.Expr(func(eb orm.ExprBuilder) any {
+					return eb.In(eb.ExtractDayOfWeek(eb.Column(column)), isoWeekdays(weekend)...)
+				})
+				cb.Expr(func(eb orm.ExprBuilder) any {
+					return eb.Expr("? NOT IN ?", eb.ToDate(eb.Column(column)), eb.SubQuery(holidayDates(true)))
+				})
+			})
+		})
+	}
+}
+
+// holidayDates builds an uncorrelated subquery selecting the holiday dates with the given working-day flag,
+// so the outer column keeps resolving against the outer query's table alias.
+func holidayDates(isWorkday bool) func(orm.SelectQuery) {
+	return func(sq orm.SelectQuery) {
+		sq.Model((*Holiday)(nil)).
+			Select("date").
+			Where(func(cb orm.ConditionBuilder) {
+				cb.Equals("is_workday", isWorkday)
+			})
+	}
+}
+
+// isoWeekdays converts weekdays to ISO numbers (1 = Monday through 7 = Sunday) matching ExtractDayOfWeek.
+func isoWeekdays(weekend []time.Weekday) []any {
+	if len(weekend) == 0 {
+		weekend = defaultWeekend
+	}
+
+	days := make([]any, len(weekend))
+	for i, day := range weekend {
+		if day == time.Sunday {
+			days[i] = 7
+		} else {
+			days[i] = int(day)
+		}
+	}
+
+	return days
+}
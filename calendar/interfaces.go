@@ -0,0 +1,27 @@
+package calendar
+
+import (
+	"context"
+
+	"github.com/ilxqx/vef-framework-go/datetime"
+)
+
+// Calendar answers working-day questions based on the configured weekend days and the holiday table.
+// It is used for SLA and approval deadline computations.
+type Calendar interface {
+	// IsWorkday reports whether the given date is a working day.
+	IsWorkday(ctx context.Context, date datetime.Date) (bool, error)
+	// AddWorkdays moves the date by the given number of working days, backwards when days is negative.
+	// Adding zero days returns the date unchanged.
+	AddWorkdays(ctx context.Context, date datetime.Date, days int) (datetime.Date, error)
+	// WorkdaysBetween counts the working days in (start, end], or the negated count of [end, start)
+	// when end is before start, so that WorkdaysBetween(d, AddWorkdays(d, n)) == n.
+	WorkdaysBetween(ctx context.Context, start, end datetime.Date) (int, error)
+}
+
+// HolidayLoader loads the holiday entries of a year.
+// Implementations typically read the holiday table, see NewDBHolidayLoader.
+type HolidayLoader interface {
+	// LoadHolidays returns all holiday entries (including adjusted working days) of the given year.
+	LoadHolidays(ctx context.Context, year int) ([]Holiday, error)
+}
@@ -0,0 +1,36 @@
+package calendar
+
+import (
+	"context"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// dbHolidayLoader loads holidays from the sys_holiday table.
+type dbHolidayLoader struct {
+	db orm.DB
+}
+
+// NewDBHolidayLoader creates a HolidayLoader reading the holiday table through the given database.
+func NewDBHolidayLoader(db orm.DB) HolidayLoader {
+	return &dbHolidayLoader{db: db}
+}
+
+func (l *dbHolidayLoader) LoadHolidays(ctx context.Context, year int) ([]Holiday, error) {
+	var holidays []Holiday
+
+	firstDay := datetime.DateOf(time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local))
+	if err := l.db.NewSelect().
+		Model(&holidays).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.Between("date", firstDay, firstDay.EndOfYear())
+		}).
+		OrderBy("date").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	return holidays, nil
+}
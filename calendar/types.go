@@ -0,0 +1,21 @@
+package calendar
+
+import (
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Holiday is an entry of the holiday table.
+// Besides public holidays it also records adjusted working days, i.e. weekend days that
+// are working days because a holiday was moved (IsWorkday = true).
+type Holiday struct {
+	orm.BaseModel `bun:"table:sys_holiday,alias:sh"`
+	orm.Model
+
+	// Date is the calendar date of the entry
+	Date datetime.Date `json:"date"      bun:"date,notnull,unique,type:date"`
+	// Name is the display name of the holiday, e.g. "New Year's Day"
+	Name string `json:"name"      bun:"name,notnull"`
+	// IsWorkday marks an adjusted working day instead of a day off
+	IsWorkday bool `json:"isWorkday" bun:"is_workday,notnull"`
+}
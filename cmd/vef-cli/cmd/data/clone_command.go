@@ -0,0 +1,146 @@
+package data
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+var errInvalidFlag = errors.New("invalid flag")
+
+func cloneCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clone [flags]",
+		Short: "Clone tables to another database with anonymized data",
+		Long: `Copy tables from a source database, e.g. production, to a target database, e.g. staging,
+anonymizing sensitive columns on the way so that realistic volumes can be tested safely.
+
+Masked columns are given as "<table>.<column>=<rule>" with one of the rules:
+  name     fake person name, a Chinese one when the value contains Han characters
+  phone    digits scrambled except the first three
+  email    fake address under example.com
+  digits   all digits scrambled, e.g. for ID card or bank card numbers
+  partial  middle of the value replaced by asterisks
+  hash     hex digest of the value
+  null     NULL
+
+Masking is deterministic for a key: equal values are masked to equal results, so a column referencing
+a masked column stays consistent when masked by the same rule. Keys and unmasked columns are copied as is.
+Without --key a random key is used, pass one to get the same masked values across clones.
+
+Tables are copied in the given order, list referenced tables first. Rows are upserted by primary key.
+With --clear the target tables are emptied first, in the reverse order.
+
+Example usage:
+  vef-cli data clone -s postgres://app@prod-db/app -t postgres://app@staging-db/app \
+    --table sys_department --table sys_user \
+    --mask sys_user.name=name --mask sys_user.mobile=phone --mask sys_user.email=email
+`,
+		Args: cobra.NoArgs,
+		RunE: runClone,
+	}
+
+	cmd.Flags().StringP("source", "s", "", "URL of the source database")
+	cmd.Flags().StringP("target", "t", "", "URL of the target database")
+	cmd.Flags().StringArray("table", nil, "Table to clone, can be repeated")
+	cmd.Flags().StringArray("mask", nil, `Masked column as "<table>.<column>=<rule>", can be repeated`)
+	cmd.Flags().String("key", "", "Key of the deterministic masking, random when empty")
+	cmd.Flags().Bool("clear", false, "Delete the rows of the target tables before cloning")
+	cmd.Flags().Int("batch-size", 500, "Number of rows read and written per batch")
+
+	_ = cmd.MarkFlagRequired("source")
+	_ = cmd.MarkFlagRequired("target")
+	_ = cmd.MarkFlagRequired("table")
+
+	return cmd
+}
+
+func runClone(cmd *cobra.Command, _ []string) error {
+	sourceURL, _ := cmd.Flags().GetString("source")
+	targetURL, _ := cmd.Flags().GetString("target")
+	tables, _ := cmd.Flags().GetStringArray("table")
+	masks, _ := cmd.Flags().GetStringArray("mask")
+	key, _ := cmd.Flags().GetString("key")
+	clearTarget, _ := cmd.Flags().GetBool("clear")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+
+	output := termenv.DefaultOutput()
+
+	if batchSize <= 0 {
+		return fmt.Errorf("%w: batch size must be positive", errInvalidFlag)
+	}
+
+	if key == "" {
+		key = rand.Text()
+	}
+
+	masker, err := NewMasker(key, masks)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range masker.Tables() {
+		if !slices.Contains(tables, table) {
+			return fmt.Errorf("%w: mask rules of %s which is not cloned", errInvalidFlag, table)
+		}
+	}
+
+	source, err := ParseDatasource(sourceURL)
+	if err != nil {
+		return err
+	}
+
+	target, err := ParseDatasource(targetURL)
+	if err != nil {
+		return err
+	}
+
+	cloner, err := NewCloner(source, target, masker, batchSize)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = cloner.Close() }()
+
+	// Check all tables first so that a misconfiguration does not leave the target half cleared
+	for _, table := range tables {
+		if err := cloner.Check(cmd.Context(), table); err != nil {
+			return err
+		}
+	}
+
+	if clearTarget {
+		for _, table := range slices.Backward(tables) {
+			deleted, err := cloner.Clear(cmd.Context(), table)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Println(output.String(fmt.Sprintf("- %s: %d rows deleted", table, deleted)).Foreground(termenv.ANSIBrightBlack))
+		}
+	}
+
+	_, _ = fmt.Println(output.String(fmt.Sprintf("Cloning %d tables...", len(tables))).Foreground(termenv.ANSICyan))
+
+	for _, table := range tables {
+		copied, err := cloner.Clone(cmd.Context(), table, func(copied int) {
+			_, _ = fmt.Println(output.String(fmt.Sprintf("    %s: %d rows copied", table, copied)).Foreground(termenv.ANSIBrightBlack))
+		})
+		if err != nil {
+			return err
+		}
+
+		masked := masker.Columns(table)
+		if len(masked) == 0 {
+			_, _ = fmt.Println(output.String(fmt.Sprintf("✓ %s: %d rows", table, copied)).Foreground(termenv.ANSIGreen))
+		} else {
+			_, _ = fmt.Println(output.String(fmt.Sprintf("✓ %s: %d rows, masked %v", table, copied, masked)).Foreground(termenv.ANSIGreen))
+		}
+	}
+
+	return nil
+}
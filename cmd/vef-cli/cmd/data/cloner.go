@@ -0,0 +1,137 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+var errUnknownMaskColumn = errors.New("mask rule targets unknown columns")
+
+// Cloner copies tables from a source to a target database, anonymizing them with a masker.
+type Cloner struct {
+	source    *endpoint
+	target    *endpoint
+	masker    *Masker
+	batchSize int
+}
+
+// NewCloner connects to the source and the target databases.
+func NewCloner(source, target *config.DatasourceConfig, masker *Masker, batchSize int) (*Cloner, error) {
+	sourceEndpoint, targetEndpoint, err := openEndpoints(source, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cloner{
+		source:    sourceEndpoint,
+		target:    targetEndpoint,
+		masker:    masker,
+		batchSize: batchSize,
+	}, nil
+}
+
+// Close closes the connections to both databases.
+func (c *Cloner) Close() error {
+	return errors.Join(c.source.close(), c.target.close())
+}
+
+// Check ensures a table can be cloned: it must have a primary key, the target table all its columns
+// and the table all its masked columns.
+func (c *Cloner) Check(ctx context.Context, table string) error {
+	_, _, err := c.inspect(ctx, table)
+
+	return err
+}
+
+func (c *Cloner) inspect(ctx context.Context, table string) (columns, keys []string, err error) {
+	if columns, keys, err = inspectTable(ctx, c.source, c.target, table); err != nil {
+		return nil, nil, err
+	}
+
+	unknownColumns := slices.DeleteFunc(c.masker.Columns(table), func(column string) bool {
+		return slices.Contains(columns, column)
+	})
+	if len(unknownColumns) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s (%s)", errUnknownMaskColumn, table, strings.Join(unknownColumns, ", "))
+	}
+
+	return columns, keys, nil
+}
+
+// Clear deletes all rows of a target table.
+func (c *Cloner) Clear(ctx context.Context, table string) (int64, error) {
+	res, err := c.target.db.NewDelete().
+		Table(table).
+		AllowFullTable().
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear target table %s: %w", table, err)
+	}
+
+	return res.RowsAffected()
+}
+
+// Clone copies the rows of a table in batches, masking them before they are written.
+// Rows are upserted by primary key, so cloning again refreshes the target, and each batch is written in a transaction.
+// The progress callback is called with the number of rows copied after each batch.
+func (c *Cloner) Clone(ctx context.Context, table string, progress func(copied int)) (int, error) {
+	columns, keys, err := c.inspect(ctx, table)
+	if err != nil {
+		return 0, err
+	}
+
+	updateColumns := slices.DeleteFunc(slices.Clone(columns), func(column string) bool {
+		return slices.Contains(keys, column)
+	})
+
+	var copied int
+
+	for {
+		rows, err := readRows(ctx, c.source.db, table, columns, keys, copied, c.batchSize)
+		if err != nil {
+			return copied, fmt.Errorf("failed to read source table %s: %w", table, err)
+		}
+
+		if len(rows) == 0 {
+			return copied, nil
+		}
+
+		if err := c.target.db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+			for _, row := range rows {
+				c.masker.Mask(table, row)
+
+				query := tx.NewUpsert().
+					Model(&row).
+					Table(table).
+					ConflictColumns(keys...)
+
+				if len(updateColumns) > 0 {
+					query.UpdateColumns(updateColumns...)
+				} else {
+					query.DoNothing()
+				}
+
+				if _, err := query.Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return copied, fmt.Errorf("failed to write target table %s: %w", table, err)
+		}
+
+		copied += len(rows)
+		progress(copied)
+
+		if len(rows) < c.batchSize {
+			return copied, nil
+		}
+	}
+}
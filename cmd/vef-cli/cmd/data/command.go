@@ -0,0 +1,17 @@
+package data
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Command returns the data cobra command grouping the data tools.
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "data",
+		Short: "Compare, sync, clone and re-encrypt data between environments",
+	}
+
+	cmd.AddCommand(diffCommand(), cloneCommand(), rekeyCommand())
+
+	return cmd
+}
@@ -0,0 +1,247 @@
+package data
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+var errTablesDiffer = errors.New("tables differ")
+
+// TableDiff describes how the rows of a table differ between the source and the target.
+type TableDiff struct {
+	Table   string
+	Keys    []string // Primary key columns
+	Columns []string // Compared columns, those of the source table
+	Rows    int      // Number of source rows
+	Missing []Row    // Source rows absent from the target
+	Changed []Row    // Source rows whose target row differs
+	Extra   []Row    // Target rows absent from the source, with their key columns only
+}
+
+// Identical reports whether the table holds the same rows in both environments.
+func (d *TableDiff) Identical() bool {
+	return len(d.Missing) == 0 && len(d.Changed) == 0 && len(d.Extra) == 0
+}
+
+// Key returns the primary key of a row as a display string.
+func (d *TableDiff) Key(row Row) string {
+	values := make([]string, len(d.Keys))
+	for i, key := range d.Keys {
+		values[i] = normalize(row[key])
+	}
+
+	return strings.Join(values, ",")
+}
+
+// Differ compares the rows of tables between a source and a target database and syncs the target.
+type Differ struct {
+	source *endpoint
+	target *endpoint
+}
+
+// NewDiffer connects to the source and the target databases.
+func NewDiffer(source, target *config.DatasourceConfig) (*Differ, error) {
+	sourceEndpoint, targetEndpoint, err := openEndpoints(source, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Differ{
+		source: sourceEndpoint,
+		target: targetEndpoint,
+	}, nil
+}
+
+// Close closes the connections to both databases.
+func (d *Differ) Close() error {
+	return errors.Join(d.source.close(), d.target.close())
+}
+
+// Diff compares the rows of a table by primary key and by the hash of their values.
+func (d *Differ) Diff(ctx context.Context, table string) (*TableDiff, error) {
+	columns, keys, err := inspectTable(ctx, d.source, d.target, table)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &TableDiff{
+		Table:   table,
+		Keys:    keys,
+		Columns: columns,
+	}
+
+	sourceRows, err := readRows(ctx, d.source.db, table, diff.Columns, diff.Keys, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source table %s: %w", table, err)
+	}
+
+	targetRows, err := readRows(ctx, d.target.db, table, diff.Columns, diff.Keys, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target table %s: %w", table, err)
+	}
+
+	diff.Rows = len(sourceRows)
+
+	targetHashes := make(map[string]string, len(targetRows))
+	for _, row := range targetRows {
+		targetHashes[diff.Key(row)] = hashRow(row, diff.Columns)
+	}
+
+	sourceKeys := make(map[string]bool, len(sourceRows))
+	for _, row := range sourceRows {
+		key := diff.Key(row)
+		sourceKeys[key] = true
+
+		targetHash, ok := targetHashes[key]
+
+		switch {
+		case !ok:
+			diff.Missing = append(diff.Missing, row)
+		case targetHash != hashRow(row, diff.Columns):
+			diff.Changed = append(diff.Changed, row)
+		}
+	}
+
+	for _, row := range targetRows {
+		if !sourceKeys[diff.Key(row)] {
+			keyRow := make(Row, len(diff.Keys))
+			for _, key := range diff.Keys {
+				keyRow[key] = row[key]
+			}
+
+			diff.Extra = append(diff.Extra, keyRow)
+		}
+	}
+
+	return diff, nil
+}
+
+// Sync makes the target table hold the rows of the source table: missing and changed rows are upserted
+// and, when deleteExtra is set, target rows absent from the source are deleted.
+// When out is not nil the statements are written to it instead of being executed.
+func (d *Differ) Sync(ctx context.Context, diff *TableDiff, deleteExtra bool, out io.Writer) error {
+	upserts := slices.Concat(diff.Missing, diff.Changed)
+	updateColumns := slices.DeleteFunc(slices.Clone(diff.Columns), func(column string) bool {
+		return slices.Contains(diff.Keys, column)
+	})
+
+	statements := make([]func(db orm.DB) (string, error), 0, len(upserts)+len(diff.Extra))
+
+	// Rows are upserted one by one as map models cannot be inserted in bulk
+	for _, row := range upserts {
+		statements = append(statements, func(db orm.DB) (string, error) {
+			query := db.NewUpsert().
+				Model(&row).
+				Table(diff.Table).
+				ConflictColumns(diff.Keys...)
+
+			if len(updateColumns) > 0 {
+				query.UpdateColumns(updateColumns...)
+			} else {
+				query.DoNothing()
+			}
+
+			if out != nil {
+				return query.SQL()
+			}
+
+			_, err := query.Exec(ctx)
+
+			return "", err
+		})
+	}
+
+	if deleteExtra {
+		for _, row := range diff.Extra {
+			statements = append(statements, func(db orm.DB) (string, error) {
+				if out != nil {
+					query := d.target.bunDB.NewDelete().Table(diff.Table)
+					for _, key := range diff.Keys {
+						query.Where("? = ?", bun.Ident(key), row[key])
+					}
+
+					return query.String(), nil
+				}
+
+				_, err := db.NewDelete().
+					Table(diff.Table).
+					Where(func(cb orm.ConditionBuilder) {
+						for _, key := range diff.Keys {
+							cb.Equals(key, row[key])
+						}
+					}).
+					Exec(ctx)
+
+				return "", err
+			})
+		}
+	}
+
+	if out != nil {
+		for _, statement := range statements {
+			query, err := statement(d.target.db)
+			if err != nil {
+				return err
+			}
+
+			if _, err := fmt.Fprintf(out, "%s;\n", query); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return d.target.db.RunInTX(ctx, func(_ context.Context, tx orm.DB) error {
+		for _, statement := range statements {
+			if _, err := statement(tx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// hashRow hashes the normalized values of the columns of a row.
+func hashRow(row Row, columns []string) string {
+	hash := sha256.New()
+	for _, column := range columns {
+		_, _ = io.WriteString(hash, normalize(row[column]))
+		_, _ = hash.Write([]byte{0x1f})
+	}
+
+	return string(hash.Sum(nil))
+}
+
+// normalize formats a value read by any driver in a driver independent way,
+// e.g. MySQL returns strings as bytes and SQLite returns booleans as integers.
+func normalize(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "\x00"
+	case []byte:
+		return string(v)
+	case bool:
+		if v {
+			return "1"
+		}
+
+		return "0"
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,126 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	ischema "github.com/ilxqx/vef-framework-go/internal/schema"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/schema"
+)
+
+var (
+	errNoPrimaryKey    = errors.New("table has no primary key")
+	errColumnsMismatch = errors.New("target table lacks columns of the source table")
+)
+
+// Row is a row read from a table, keyed by column.
+type Row = map[string]any
+
+// endpoint is the source or the target database of a data command.
+type endpoint struct {
+	bunDB  *bun.DB
+	db     orm.DB
+	schema schema.Service
+}
+
+func openEndpoint(cfg *config.DatasourceConfig) (*endpoint, error) {
+	bunDB, err := database.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := ischema.NewService(bunDB.DB, cfg)
+	if err != nil {
+		_ = bunDB.Close()
+
+		return nil, err
+	}
+
+	return &endpoint{
+		bunDB:  bunDB,
+		db:     iorm.New(bunDB),
+		schema: service,
+	}, nil
+}
+
+// openEndpoints connects to the source and the target databases.
+func openEndpoints(source, target *config.DatasourceConfig) (*endpoint, *endpoint, error) {
+	sourceEndpoint, err := openEndpoint(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to the source: %w", err)
+	}
+
+	targetEndpoint, err := openEndpoint(target)
+	if err != nil {
+		_ = sourceEndpoint.close()
+
+		return nil, nil, fmt.Errorf("failed to connect to the target: %w", err)
+	}
+
+	return sourceEndpoint, targetEndpoint, nil
+}
+
+func (e *endpoint) close() error {
+	return e.bunDB.Close()
+}
+
+// inspectTable returns the columns and the primary key columns of a source table,
+// ensuring the target table has all the columns.
+func inspectTable(ctx context.Context, source, target *endpoint, table string) (columns, keys []string, err error) {
+	sourceSchema, err := source.schema.GetTableSchema(ctx, table)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to inspect source table %s: %w", table, err)
+	}
+
+	if sourceSchema.PrimaryKey == nil || len(sourceSchema.PrimaryKey.Columns) == 0 {
+		return nil, nil, fmt.Errorf("%w: %s", errNoPrimaryKey, table)
+	}
+
+	targetSchema, err := target.schema.GetTableSchema(ctx, table)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to inspect target table %s: %w", table, err)
+	}
+
+	var missingColumns []string
+
+	for _, column := range sourceSchema.Columns {
+		columns = append(columns, column.Name)
+
+		if !slices.ContainsFunc(targetSchema.Columns, func(c schema.Column) bool { return c.Name == column.Name }) {
+			missingColumns = append(missingColumns, column.Name)
+		}
+	}
+
+	if len(missingColumns) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s (%s)", errColumnsMismatch, table, strings.Join(missingColumns, ", "))
+	}
+
+	return columns, sourceSchema.PrimaryKey.Columns, nil
+}
+
+// readRows reads the rows of a table ordered by its primary key, all of them when limit is 0.
+func readRows(ctx context.Context, db orm.DB, table string, columns, keys []string, offset, limit int) ([]Row, error) {
+	var rows []Row
+
+	query := db.NewSelect().
+		Table(table).
+		Select(columns...).
+		OrderBy(keys...)
+
+	if limit > 0 {
+		query.Offset(offset).Limit(limit)
+	}
+
+	err := query.Scan(ctx, &rows)
+
+	return rows, err
+}
@@ -0,0 +1,185 @@
+package data
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+// MaskRule names how the values of a column are anonymized.
+type MaskRule string
+
+const (
+	MaskName    MaskRule = "name"    // Fake person name, a Chinese one when the value contains Han characters
+	MaskPhone   MaskRule = "phone"   // Digits scrambled except the first three, keeping the carrier prefix
+	MaskEmail   MaskRule = "email"   // Fake address under example.com
+	MaskDigits  MaskRule = "digits"  // All digits scrambled, e.g. for ID card or bank card numbers
+	MaskPartial MaskRule = "partial" // Middle of the value replaced by asterisks
+	MaskHash    MaskRule = "hash"    // Hex digest of the value
+	MaskNull    MaskRule = "null"    // NULL
+)
+
+var (
+	maskRules = []MaskRule{MaskName, MaskPhone, MaskEmail, MaskDigits, MaskPartial, MaskHash, MaskNull}
+
+	errInvalidMaskRule = errors.New(`mask rule must be "<table>.<column>=<rule>" with rule one of name, phone, email, digits, partial, hash or null`)
+
+	englishFirstNames = []string{
+		"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda",
+		"William", "Elizabeth", "David", "Barbara", "Richard", "Susan", "Joseph", "Jessica",
+	}
+	englishLastNames = []string{
+		"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis",
+		"Wilson", "Anderson", "Taylor", "Thomas", "Moore", "Martin", "Jackson", "Lee",
+	}
+	chineseSurnames = []string{
+		"王", "李", "张", "刘", "陈", "杨", "黄", "赵", "吴", "周",
+		"徐", "孙", "马", "朱", "胡", "郭", "何", "高", "林", "罗",
+	}
+	chineseGivenNames = []string{
+		"伟", "芳", "娜", "敏", "静", "丽", "强", "磊", "军", "洋", "勇", "艳",
+		"杰", "娟", "涛", "明", "超", "秀", "霞", "平", "刚", "桂", "华", "宇",
+	}
+)
+
+// Masker anonymizes the values of table columns according to mask rules.
+//
+// Masking is deterministic for a key: a value is always masked to the same result by the same rule,
+// so columns referencing a masked column stay consistent when masked by the same rule and key.
+type Masker struct {
+	key   []byte
+	rules map[string]map[string]MaskRule // Table -> column -> rule
+}
+
+// NewMasker creates a masker from rules given as "<table>.<column>=<rule>".
+func NewMasker(key string, specs []string) (*Masker, error) {
+	masker := &Masker{
+		key:   []byte(key),
+		rules: make(map[string]map[string]MaskRule),
+	}
+
+	for _, spec := range specs {
+		target, rule, ok := strings.Cut(spec, constants.Equals)
+		table, column, hasColumn := strings.Cut(target, constants.Dot)
+
+		if !ok || !hasColumn || table == constants.Empty || column == constants.Empty ||
+			!slices.Contains(maskRules, MaskRule(rule)) {
+			return nil, fmt.Errorf("%w, got %q", errInvalidMaskRule, spec)
+		}
+
+		if masker.rules[table] == nil {
+			masker.rules[table] = make(map[string]MaskRule)
+		}
+
+		masker.rules[table][column] = MaskRule(rule)
+	}
+
+	return masker, nil
+}
+
+// Tables returns the sorted tables having mask rules.
+func (m *Masker) Tables() []string {
+	return slices.Sorted(maps.Keys(m.rules))
+}
+
+// Columns returns the sorted masked columns of a table.
+func (m *Masker) Columns(table string) []string {
+	return slices.Sorted(maps.Keys(m.rules[table]))
+}
+
+// Mask anonymizes the masked columns of a row of a table in place.
+func (m *Masker) Mask(table string, row Row) {
+	for column, rule := range m.rules[table] {
+		if value, ok := row[column]; ok {
+			row[column] = m.maskValue(rule, value)
+		}
+	}
+}
+
+func (m *Masker) maskValue(rule MaskRule, value any) any {
+	if value == nil || rule == MaskNull {
+		return nil
+	}
+
+	var text string
+	if bytes, ok := value.([]byte); ok {
+		text = string(bytes)
+	} else {
+		text = fmt.Sprint(value)
+	}
+
+	digest := m.digest(text)
+
+	switch rule {
+	case MaskName:
+		if strings.ContainsFunc(text, func(r rune) bool { return unicode.Is(unicode.Han, r) }) {
+			name := pick(chineseSurnames, digest[0]) + pick(chineseGivenNames, digest[1])
+			if len([]rune(text)) > 2 {
+				name += pick(chineseGivenNames, digest[2])
+			}
+
+			return name
+		}
+
+		return pick(englishFirstNames, digest[0]) + constants.Space + pick(englishLastNames, digest[1])
+	case MaskPhone:
+		return scrambleDigits(text, digest, 3)
+	case MaskEmail:
+		return "user_" + hex.EncodeToString(digest[:5]) + "@example.com"
+	case MaskDigits:
+		return scrambleDigits(text, digest, 0)
+	case MaskPartial:
+		return security.MaskString(text)
+	case MaskHash:
+		return hex.EncodeToString(digest[:16])
+	default:
+		return value
+	}
+}
+
+func (m *Masker) digest(value string) []byte {
+	mac := hmac.New(sha256.New, m.key)
+	_, _ = mac.Write([]byte(value))
+
+	return mac.Sum(nil)
+}
+
+func pick(values []string, b byte) string {
+	return values[int(b)%len(values)]
+}
+
+// scrambleDigits replaces the digits of a value after the first keep digits with digits derived from the digest,
+// keeping the other characters, e.g. separators and the check letter of ID card numbers.
+func scrambleDigits(value string, digest []byte, keep int) string {
+	var (
+		builder strings.Builder
+		index   int
+	)
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			_, _ = builder.WriteRune(r)
+
+			continue
+		}
+
+		if index < keep {
+			_, _ = builder.WriteRune(r)
+		} else {
+			_ = builder.WriteByte('0' + digest[index%len(digest)]%10)
+		}
+
+		index++
+	}
+
+	return builder.String()
+}
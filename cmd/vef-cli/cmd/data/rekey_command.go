@@ -0,0 +1,118 @@
+package data
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlite"
+)
+
+func rekeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rekey [flags]",
+		Short: "Re-encrypt an encrypted SQLite database with a new key",
+		Long: `Rotate the key of a SQLite database encrypted with SQLCipher (vef.datasource.encryption).
+
+The database is decrypted with the current key and re-encrypted in place with the new key,
+then reopened with the new key to verify it. Stop the applications using the database first
+and keep a backup until the new key has been rolled out.
+
+Keys are read from environment variables or files, e.g. mounted secrets, so that they do not
+show up in the shell history or the process list.
+
+The command requires a vef-cli built against a SQLCipher libsqlite3:
+  CGO_ENABLED=1 go build -tags cgosqlite,libsqlite3 ./cmd/vef-cli
+
+Example usage:
+  vef-cli data rekey -p /var/lib/app/app.db --key-env APP_DB_KEY --new-key-env APP_DB_NEW_KEY
+  vef-cli data rekey -p /var/lib/app/app.db --key-file /run/secrets/db_key --new-key-file /run/secrets/db_new_key
+`,
+		Args: cobra.NoArgs,
+		RunE: runRekey,
+	}
+
+	cmd.Flags().StringP("path", "p", "", "Path of the encrypted SQLite database")
+	cmd.Flags().String("key-env", "", "Environment variable holding the current key")
+	cmd.Flags().String("key-file", "", "File holding the current key")
+	cmd.Flags().String("new-key-env", "", "Environment variable holding the new key")
+	cmd.Flags().String("new-key-file", "", "File holding the new key")
+
+	_ = cmd.MarkFlagRequired("path")
+	cmd.MarkFlagsMutuallyExclusive("key-env", "key-file")
+	cmd.MarkFlagsOneRequired("key-env", "key-file")
+	cmd.MarkFlagsMutuallyExclusive("new-key-env", "new-key-file")
+	cmd.MarkFlagsOneRequired("new-key-env", "new-key-file")
+
+	return cmd
+}
+
+func runRekey(cmd *cobra.Command, _ []string) error {
+	path, _ := cmd.Flags().GetString("path")
+	keyEnv, _ := cmd.Flags().GetString("key-env")
+	keyFile, _ := cmd.Flags().GetString("key-file")
+	newKeyEnv, _ := cmd.Flags().GetString("new-key-env")
+	newKeyFile, _ := cmd.Flags().GetString("new-key-file")
+
+	output := termenv.DefaultOutput()
+
+	// SQLite creates missing databases, which must not be mistaken for a rekeyed one
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+
+	newEncryption := config.SQLiteEncryptionConfig{Enabled: true, KeyEnv: newKeyEnv, KeyFile: newKeyFile}
+
+	newKey, err := sqlite.EncryptionKey(newEncryption)
+	if err != nil {
+		return fmt.Errorf("failed to load the new key: %w", err)
+	}
+
+	db, err := database.New(&config.DatasourceConfig{
+		Type:       constants.SQLite,
+		Path:       path,
+		Encryption: config.SQLiteEncryptionConfig{Enabled: true, KeyEnv: keyEnv, KeyFile: keyFile},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Rekeying applies to the connection it runs on, other connections would keep the current key
+	db.SetMaxOpenConns(1)
+
+	if err := sqlite.Rekey(cmd.Context(), db.DB, newKey); err != nil {
+		_ = db.Close()
+
+		return err
+	}
+
+	if err := db.Close(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Println(output.String("Verifying the new key...").Foreground(termenv.ANSICyan))
+
+	verifyDB, err := database.New(&config.DatasourceConfig{
+		Type:       constants.SQLite,
+		Path:       path,
+		Encryption: newEncryption,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = verifyDB.Close() }()
+
+	if err := verifyDB.PingContext(cmd.Context()); err != nil {
+		return fmt.Errorf("failed to open the database with the new key: %w", err)
+	}
+
+	_, _ = fmt.Println(output.String(fmt.Sprintf("✓ %s re-encrypted with the new key", path)).Foreground(termenv.ANSIGreen))
+
+	return nil
+}
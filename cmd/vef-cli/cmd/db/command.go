@@ -0,0 +1,17 @@
+package db
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Command returns the db cobra command grouping the database tools.
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage the database of the application",
+	}
+
+	cmd.AddCommand(seedCommand())
+
+	return cmd
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+var errProductionSeed = errors.New("refusing to seed a production environment without --force")
+
+// productionEnvs are the environments seeded only with --force.
+var productionEnvs = []string{"prod", "production"}
+
+func seedCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "seed [steps...]",
+		Short: "Seed the database with fixtures and generated data",
+		Long: `Run the seed program of the project, which registers its seed steps with the seed package and
+calls Seeder.RunFromEnv. The environment and the steps are passed as the environment variables
+VEF_SEED_ENV and VEF_SEED_STEPS, steps restricted to other environments are skipped.
+
+Without steps all registered steps run, otherwise the given steps run with the steps they depend on.
+Seeding a prod or production environment is refused unless --force is given.
+
+A seed program looks like:
+  func main() {
+      // open the database as the application does
+      seeder, _ := seed.New(db)
+      seeder.Models((*models.User)(nil), (*models.Post)(nil)).Register(
+          seed.Fixtures("users", fixtures, "users.yaml"),
+          seed.Step{Name: "posts", DependsOn: []string{"users"}, Envs: []string{"stage"}, Run: seedPosts},
+      )
+
+      if err := seeder.RunFromEnv(context.Background()); err != nil {
+          log.Fatal(err)
+      }
+  }
+
+Example usage:
+  vef-cli db seed
+  vef-cli db seed --env stage posts
+  vef-cli db seed --package ./tools/seed
+`,
+		RunE: runSeed,
+	}
+
+	cmd.Flags().StringP("env", "e", "dev", "Environment to seed, e.g. dev or stage")
+	cmd.Flags().StringP("package", "p", "./cmd/seed", "Go package of the seed program")
+	cmd.Flags().Bool("force", false, "Allow seeding a production environment")
+
+	return cmd
+}
+
+func runSeed(cmd *cobra.Command, steps []string) error {
+	env, _ := cmd.Flags().GetString("env")
+	pkg, _ := cmd.Flags().GetString("package")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if slices.Contains(productionEnvs, strings.ToLower(env)) && !force {
+		return fmt.Errorf("%w: %s", errProductionSeed, env)
+	}
+
+	output := termenv.DefaultOutput()
+
+	_, _ = fmt.Println(output.String("Seeding the database...").Foreground(termenv.ANSICyan))
+	_, _ = fmt.Println(output.String("  Environment: " + env).Foreground(termenv.ANSIBrightBlack))
+	_, _ = fmt.Println(output.String("  Package: " + pkg).Foreground(termenv.ANSIBrightBlack))
+
+	if len(steps) > 0 {
+		_, _ = fmt.Println(output.String("  Steps: " + strings.Join(steps, ", ")).Foreground(termenv.ANSIBrightBlack))
+	}
+
+	run := exec.CommandContext(cmd.Context(), "go", "run", pkg)
+	run.Env = append(
+		os.Environ(),
+		constants.EnvSeedEnv+"="+env,
+		constants.EnvSeedSteps+"="+strings.Join(steps, constants.Comma),
+	)
+	run.Stdin = os.Stdin
+	run.Stdout = os.Stdout
+	run.Stderr = os.Stderr
+
+	if err := run.Run(); err != nil {
+		return fmt.Errorf("failed to run seed program %s: %w", pkg, err)
+	}
+
+	_, _ = fmt.Println(output.String("✓ Successfully seeded the database").Foreground(termenv.ANSIGreen))
+
+	return nil
+}
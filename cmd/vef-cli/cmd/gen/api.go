@@ -0,0 +1,408 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+var (
+	// ErrInvalidEntity indicates the entity name is not an exported Go identifier.
+	ErrInvalidEntity = errors.New("entity must be an exported Go identifier such as Order or PurchaseOrder")
+	// ErrInvalidField indicates a field spec that cannot be parsed.
+	ErrInvalidField = errors.New(`field must be given as "name:type[?][:rules]", e.g. "title:string:required,max=64" or "due_date:date?"`)
+	// ErrFileExists indicates a generated file would overwrite an existing one.
+	ErrFileExists = errors.New("file already exists, use --force to overwrite")
+)
+
+var (
+	entityPattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	fieldPattern  = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	wordPattern   = regexp.MustCompile(`[A-Z]+[a-z0-9]*`)
+)
+
+// fieldTypes maps the types of field specs to Go types, non-null and nullable.
+var fieldTypes = map[string]goType{
+	"string":   {name: "string", nullName: "null.String", nullImp: importNull},
+	"text":     {name: "string", nullName: "null.String", nullImp: importNull},
+	"int":      {name: "int32", nullName: "null.Int32", nullImp: importNull},
+	"int64":    {name: "int64", nullName: "null.Int", nullImp: importNull},
+	"bool":     {name: "bool", nullName: "null.Bool", nullImp: importNull},
+	"decimal":  {name: "decimal.Decimal", imp: importDecimal, nullName: "null.Decimal", nullImp: importNull},
+	"date":     {name: "datetime.Date", imp: importDatetime, nullName: "null.Date", nullImp: importNull},
+	"datetime": {name: "datetime.DateTime", imp: importDatetime, nullName: "null.DateTime", nullImp: importNull},
+}
+
+// ApiOptions configures the scaffolding of an Api module.
+type ApiOptions struct {
+	// Entity is the model name, e.g. "PurchaseOrder".
+	Entity string
+	// Package is the package of the module, the lower case entity by default.
+	Package string
+	// Table is the table of the model, the snake case entity by default.
+	Table string
+	// Resource is the Api resource name, the snake case entity by default.
+	Resource string
+	// Fields declares the fields of the model as "name:type[?][:rules]", "name:string:required,max=64" by default.
+	Fields []string
+}
+
+// apiField is a field of the scaffolded model and params.
+type apiField struct {
+	Name     string
+	Column   string
+	JSON     string
+	Label    string
+	Type     string
+	Nullable bool
+	Validate string
+	Search   bool
+}
+
+// apiData is the data of the Api module templates.
+type apiData struct {
+	Package  string
+	Entity   string
+	Label    string
+	Table    string
+	Alias    string
+	Resource string
+	Fields   []apiField
+	Keyword  string // Columns searched by the keyword
+	Imports  []string
+}
+
+// GenerateApi generates the files of an Api module for an entity, keyed by file name:
+// the model, the search and create/update params, a service and the resource with its module.
+func GenerateApi(opts ApiOptions) (map[string][]byte, error) {
+	if !entityPattern.MatchString(opts.Entity) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEntity, opts.Entity)
+	}
+
+	words := wordPattern.FindAllString(opts.Entity, -1)
+	snake := strings.ToLower(strings.Join(words, "_"))
+
+	data := apiData{
+		Package:  orDefault(opts.Package, strings.ToLower(opts.Entity)),
+		Entity:   opts.Entity,
+		Label:    strings.ToLower(strings.Join(words, " ")),
+		Table:    orDefault(opts.Table, snake),
+		Resource: orDefault(opts.Resource, snake),
+	}
+	data.Alias = orm.DeriveAlias(data.Table)
+
+	specs := opts.Fields
+	if len(specs) == 0 {
+		specs = []string{"name:string:required,max=64"}
+	}
+
+	imports := make(map[string]bool)
+	var keyword []string
+
+	for _, spec := range specs {
+		field, imp, err := parseField(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		if imp != constants.Empty {
+			imports[imp] = true
+		}
+
+		if field.Search {
+			keyword = append(keyword, field.Column)
+		}
+
+		data.Fields = append(data.Fields, field)
+	}
+
+	data.Keyword = strings.Join(keyword, "|")
+	data.Imports = sortedImports(imports)
+
+	files := make(map[string][]byte, len(apiTemplates))
+
+	for name, text := range apiTemplates {
+		tpl, err := template.New(name).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+		}
+
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to execute template %s: %w", name, err)
+		}
+
+		source, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to format %s: %w", name, err)
+		}
+
+		files[name] = source
+	}
+
+	return files, nil
+}
+
+// WriteApi writes the files of an Api module to a directory, refusing to overwrite existing files unless forced.
+func WriteApi(dir string, files map[string][]byte, force bool) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	if !force {
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return fmt.Errorf("%w: %s", ErrFileExists, filepath.Join(dir, name))
+			}
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), files[name], 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// parseField parses a field spec, returning the field and the import of its type.
+func parseField(spec string) (apiField, string, error) {
+	parts := strings.SplitN(spec, ":", 3)
+	if len(parts) < 2 || !fieldPattern.MatchString(parts[0]) {
+		return apiField{}, constants.Empty, fmt.Errorf("%w: %s", ErrInvalidField, spec)
+	}
+
+	typeName, nullable := strings.CutSuffix(parts[1], "?")
+
+	t, ok := fieldTypes[typeName]
+	if !ok {
+		return apiField{}, constants.Empty, fmt.Errorf("%w: unknown type %q in %s", ErrInvalidField, typeName, spec)
+	}
+
+	name := identifier(parts[0])
+	field := apiField{
+		Name:     name,
+		Column:   parts[0],
+		JSON:     jsonName(name),
+		Label:    strings.ReplaceAll(parts[0], "_", " "),
+		Type:     t.name,
+		Nullable: nullable,
+		Search:   t.name == "string",
+	}
+
+	imp := t.imp
+	if nullable {
+		field.Type, imp = t.nullName, t.nullImp
+	}
+
+	if len(parts) == 3 {
+		field.Validate = parts[2]
+	}
+
+	if nullable && field.Validate != constants.Empty && !strings.HasPrefix(field.Validate, "omitempty") {
+		field.Validate = "omitempty," + field.Validate
+	}
+
+	return field, imp, nil
+}
+
+func orDefault(value, fallback string) string {
+	if value != constants.Empty {
+		return value
+	}
+
+	return fallback
+}
+
+// apiTemplates are the templates of the files of an Api module, keyed by file name.
+var apiTemplates = map[string]string{
+	"model.go": `package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{if .}}"{{.}}"{{end}}
+{{- end}}
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// {{.Entity}} is a {{.Label}} stored in the {{.Table}} table.
+type {{.Entity}} struct {
+	orm.BaseModel ` + "`" + `bun:"table:{{.Table}},alias:{{.Alias}}"` + "`" + `
+	orm.Model
+{{range .Fields}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.JSON}}" bun:"{{.Column}}{{if not .Nullable}},notnull{{end}}"` + "`" + `
+{{- end}}
+}
+`,
+
+	"payloads.go": `package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{if .}}"{{.}}"{{end}}
+{{- end}}
+	"github.com/ilxqx/vef-framework-go/api"
+)
+
+// {{.Entity}}Search is the search of {{.Label}} queries.
+type {{.Entity}}Search struct {
+	api.P
+{{if .Keyword}}
+	Keyword string ` + "`" + `json:"keyword" search:"contains,column={{.Keyword}}"` + "`" + `
+{{- end}}
+}
+
+// {{.Entity}}Params holds the fields shared by the creation and the update of {{.Label}}s.
+type {{.Entity}}Params struct {
+	api.P
+{{range .Fields}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.JSON}}"{{if .Validate}} validate:"{{.Validate}}"{{end}} label:"{{.Label}}"` + "`" + `
+{{- end}}
+}
+
+// {{.Entity}}CreateParams are the params of the creation of a {{.Label}}.
+type {{.Entity}}CreateParams struct {
+	{{.Entity}}Params ` + "`" + `json:",inline"` + "`" + `
+}
+
+// {{.Entity}}UpdateParams are the params of the update of a {{.Label}}.
+type {{.Entity}}UpdateParams struct {
+	{{.Entity}}Params ` + "`" + `json:",inline"` + "`" + `
+
+	ID string ` + "`" + `json:"id" validate:"required" label:"id"` + "`" + `
+}
+
+// {{.Entity}}GetParams identifies a {{.Label}}.
+type {{.Entity}}GetParams struct {
+	api.P
+
+	ID string ` + "`" + `json:"id" validate:"required" label:"id"` + "`" + `
+}
+`,
+
+	"service.go": `package {{.Package}}
+
+import (
+	"context"
+
+	"github.com/ilxqx/vef-framework-go"
+	"github.com/ilxqx/vef-framework-go/log"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// {{.Entity}}Service holds the business logic of {{.Label}}s beyond CRUD.
+type {{.Entity}}Service struct {
+	logger log.Logger
+}
+
+// New{{.Entity}}Service creates a new {{.Label}} service.
+func New{{.Entity}}Service() *{{.Entity}}Service {
+	return &{{.Entity}}Service{
+		logger: vef.NamedLogger("{{.Package}}"),
+	}
+}
+
+// WithLogger returns the service logging through the request scoped logger when injected into handlers.
+func (s *{{.Entity}}Service) WithLogger(logger log.Logger) *{{.Entity}}Service {
+	return &{{.Entity}}Service{
+		logger: logger,
+	}
+}
+
+// Get returns the {{.Label}} with the given id.
+func (s *{{.Entity}}Service) Get(ctx context.Context, db orm.DB, id string) (*{{.Entity}}, error) {
+	model := new({{.Entity}})
+	model.ID = id
+
+	if err := db.NewSelect().Model(model).WherePK().Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debugf("Loaded {{.Label}} %s", id)
+
+	return model, nil
+}
+`,
+
+	"resource.go": `package {{.Package}}
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/apis"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+// {{.Entity}}Resource is the Api resource of {{.Label}}s.
+type {{.Entity}}Resource struct {
+	api.Resource
+	apis.FindPage[{{.Entity}}, {{.Entity}}Search]
+	apis.Create[{{.Entity}}, {{.Entity}}CreateParams]
+	apis.Update[{{.Entity}}, {{.Entity}}UpdateParams]
+	apis.Delete[{{.Entity}}]
+
+	service *{{.Entity}}Service
+}
+
+// New{{.Entity}}Resource creates the {{.Label}} resource.
+func New{{.Entity}}Resource(service *{{.Entity}}Service) api.Resource {
+	return &{{.Entity}}Resource{
+		Resource: api.NewRPCResource(
+			"{{.Resource}}",
+			api.WithOperations(
+				api.OperationSpec{Action: "get"},
+			),
+		),
+		FindPage: apis.NewFindPage[{{.Entity}}, {{.Entity}}Search](),
+		Create:   apis.NewCreate[{{.Entity}}, {{.Entity}}CreateParams](),
+		Update:   apis.NewUpdate[{{.Entity}}, {{.Entity}}UpdateParams](),
+		Delete:   apis.NewDelete[{{.Entity}}](),
+		service:  service,
+	}
+}
+
+// Get handles the get action, the service is injected from the resource field.
+func (*{{.Entity}}Resource) Get(ctx fiber.Ctx, db orm.DB, service *{{.Entity}}Service, params {{.Entity}}GetParams) error {
+	model, err := service.Get(ctx.Context(), db, params.ID)
+	if err != nil {
+		return err
+	}
+
+	return result.Ok(model).Response(ctx)
+}
+`,
+
+	"module.go": `package {{.Package}}
+
+import (
+	"github.com/ilxqx/vef-framework-go"
+)
+
+// Module provides the {{.Label}} service and Api resource.
+var Module = vef.Module(
+	"app:{{.Package}}",
+	vef.Provide(New{{.Entity}}Service),
+	vef.ProvideApiResource(New{{.Entity}}Resource),
+)
+`,
+}
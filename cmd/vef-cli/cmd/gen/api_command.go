@@ -0,0 +1,92 @@
+package gen
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+func apiCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api <Entity> [flags]",
+		Short: "Scaffold a CRUD Api module for an entity",
+		Long: `Scaffold a package holding a CRUD Api module for an entity.
+
+The package contains:
+  - model.go     the model embedding orm.Model
+  - payloads.go  the search, create and update params with validation tags
+  - service.go   a service for business logic beyond CRUD, provided through dependency injection
+  - resource.go  the RPC resource with find_page, create, update, delete and a get action using the service
+  - module.go    the module providing the service and the resource, to pass to vef.Run
+
+Fields are given as "name:type[?][:rules]" where type is one of string, text, int, int64, bool,
+decimal, date or datetime, "?" makes the field nullable and rules are validation rules.
+String fields are searched by the keyword of find_page.
+
+Example usage:
+  vef-cli gen api PurchaseOrder --resource erp/purchase/order --field code:string:required,max=32 --field amount:decimal --field due_date:date?
+  vef-cli gen api Customer -o internal/crm/customer --table crm_customer
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: runGenApi,
+	}
+
+	cmd.Flags().StringP("output", "o", "", "Output directory, internal/<package> by default")
+	cmd.Flags().StringP("package", "p", "", "Package name, the lower case entity by default")
+	cmd.Flags().String("table", "", "Table name, the snake case entity by default")
+	cmd.Flags().String("resource", "", "Api resource name, the snake case entity by default")
+	cmd.Flags().StringArray("field", nil, "Field of the model, can be repeated")
+	cmd.Flags().Bool("force", false, "Overwrite existing files")
+
+	return cmd
+}
+
+func runGenApi(cmd *cobra.Command, args []string) error {
+	outputDir, _ := cmd.Flags().GetString("output")
+	pkg, _ := cmd.Flags().GetString("package")
+	table, _ := cmd.Flags().GetString("table")
+	resource, _ := cmd.Flags().GetString("resource")
+	fields, _ := cmd.Flags().GetStringArray("field")
+	force, _ := cmd.Flags().GetBool("force")
+
+	opts := ApiOptions{
+		Entity:   args[0],
+		Package:  pkg,
+		Table:    table,
+		Resource: resource,
+		Fields:   fields,
+	}
+
+	files, err := GenerateApi(opts)
+	if err != nil {
+		return err
+	}
+
+	if outputDir == "" {
+		outputDir = filepath.Join("internal", orDefault(pkg, strings.ToLower(opts.Entity)))
+	}
+
+	if err := WriteApi(outputDir, files, force); err != nil {
+		return err
+	}
+
+	output := termenv.DefaultOutput()
+	_, _ = fmt.Println(output.String(fmt.Sprintf("✓ Generated %s Api module in %s", opts.Entity, outputDir)).Foreground(termenv.ANSIGreen))
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		_, _ = fmt.Println(output.String("    " + filepath.Join(outputDir, name)).Foreground(termenv.ANSIBrightBlack))
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package gen
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Command returns the gen cobra command grouping the code generators.
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gen",
+		Short: "Generate models and Api modules",
+	}
+
+	cmd.AddCommand(modelCommand(), apiCommand())
+
+	return cmd
+}
@@ -0,0 +1,397 @@
+package gen
+
+import (
+	"fmt"
+	"go/format"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/schema"
+)
+
+const (
+	importDatetime = "github.com/ilxqx/vef-framework-go/datetime"
+	importDecimal  = "github.com/ilxqx/vef-framework-go/decimal"
+	importNull     = "github.com/ilxqx/vef-framework-go/null"
+	importOrm      = "github.com/ilxqx/vef-framework-go/orm"
+	importTime     = "time"
+
+	// softDeleteColumn is the column holding the deletion time of soft deleted rows.
+	softDeleteColumn = "deleted_at"
+)
+
+// initialisms are the words written in upper case in Go identifiers.
+var initialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "id": true, "ip": true,
+	"json": true, "sql": true, "uri": true, "url": true, "uuid": true, "xml": true,
+}
+
+// typeParams matches the parameters of column types, e.g. "(255)" in "varchar(255)".
+var typeParams = regexp.MustCompile(`\(.*\)`)
+
+// ModelOptions configures the generation of models.
+type ModelOptions struct {
+	// Package is the package of the generated file.
+	Package string
+	// TrimPrefix is removed from table names to name structs, e.g. "sys_".
+	TrimPrefix string
+}
+
+// goType is the Go type of a column, non-null and nullable.
+type goType struct {
+	name, nullName string
+	imp, nullImp   string
+}
+
+// modelField is a field of a generated model.
+type modelField struct {
+	comment string
+	name    string
+	typ     string
+	tag     string
+}
+
+// model is a generated model.
+type model struct {
+	comment string
+	name    string
+	table   string
+	alias   string
+	embeds  []string
+	fields  []modelField
+	stubs   []string // Relations to tables outside of the generated ones, written as comments
+}
+
+// GenerateModels generates the Go source of models for the tables.
+// Audit columns are mapped to the embedded base models of the orm package, a deleted_at column to a soft delete
+// field, and the foreign keys to belongs-to relations, as comments when the referenced table is not generated.
+func GenerateModels(tables []*schema.TableSchema, opts ModelOptions) ([]byte, error) {
+	structNames := make(map[string]string, len(tables))
+	for _, table := range tables {
+		structNames[table.Name] = structName(table.Name, opts.TrimPrefix)
+	}
+
+	imports := make(map[string]bool)
+	models := make([]model, len(tables))
+
+	for i, table := range tables {
+		models[i] = buildModel(table, structNames, imports)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("// Code generated by vef-cli gen model. Review before use.\n\n")
+	sb.WriteString("package " + opts.Package + "\n\n")
+	sb.WriteString("import (\n")
+
+	for _, imp := range sortedImports(imports) {
+		if imp == constants.Empty {
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("\t%q\n", imp))
+		}
+	}
+
+	sb.WriteString(")\n")
+
+	for _, m := range models {
+		writeModel(&sb, m)
+	}
+
+	source, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated models: %w", err)
+	}
+
+	return source, nil
+}
+
+func buildModel(table *schema.TableSchema, structNames map[string]string, imports map[string]bool) model {
+	m := model{
+		comment: table.Comment,
+		name:    structNames[table.Name],
+		table:   table.Name,
+		alias:   orm.DeriveAlias(table.Name),
+	}
+
+	imports[importOrm] = true
+
+	columns := make(map[string]bool, len(table.Columns))
+	for _, column := range table.Columns {
+		columns[column.Name] = true
+	}
+
+	var primaryKey []string
+	if table.PrimaryKey != nil {
+		primaryKey = table.PrimaryKey.Columns
+	}
+
+	// Audit columns are taken over by the base models, the single string id primary key with them
+	skipped := make(map[string]bool)
+	hasID := slices.Equal(primaryKey, []string{"id"}) && isStringColumn(table, "id")
+	hasCreated := columns["created_at"] && columns["created_by"]
+	hasUpdated := columns["updated_at"] && columns["updated_by"]
+
+	switch {
+	case hasID && hasCreated && hasUpdated:
+		m.embeds = append(m.embeds, "orm.Model")
+	case hasCreated && hasUpdated:
+		m.embeds = append(m.embeds, "orm.AuditedModel")
+	case hasCreated:
+		m.embeds = append(m.embeds, "orm.CreatedModel")
+	}
+
+	if hasID {
+		skipped["id"] = true
+
+		if len(m.embeds) == 0 || m.embeds[0] != "orm.Model" {
+			m.embeds = append([]string{"orm.IDModel"}, m.embeds...)
+		}
+	}
+
+	if hasCreated {
+		skipped["created_at"], skipped["created_by"] = true, true
+	}
+
+	if hasCreated && hasUpdated {
+		skipped["updated_at"], skipped["updated_by"] = true, true
+	}
+
+	names := make(map[string]bool)
+
+	for _, column := range table.Columns {
+		if skipped[column.Name] {
+			continue
+		}
+
+		field := buildField(column, slices.Contains(primaryKey, column.Name), imports)
+		names[field.name] = true
+		m.fields = append(m.fields, field)
+	}
+
+	for _, fk := range table.ForeignKeys {
+		if len(fk.Columns) != 1 || len(fk.RefColumns) != 1 {
+			continue
+		}
+
+		ref := structNames[fk.RefTable]
+
+		name := identifier(strings.TrimSuffix(fk.Columns[0], "_id"))
+		if names[name] {
+			name += "Ref"
+		}
+
+		names[name] = true
+
+		field := modelField{
+			name: name,
+			typ:  "*" + ref,
+			tag:  fmt.Sprintf(`json:"%s,omitempty" bun:"rel:belongs-to,join:%s=%s"`, jsonName(name), fk.Columns[0], fk.RefColumns[0]),
+		}
+
+		if ref == constants.Empty {
+			m.stubs = append(m.stubs, fmt.Sprintf("%s *%s `%s`", field.name, structName(fk.RefTable, ""), field.tag))
+
+			continue
+		}
+
+		m.fields = append(m.fields, field)
+	}
+
+	return m
+}
+
+func buildField(column schema.Column, primaryKey bool, imports map[string]bool) modelField {
+	name := identifier(column.Name)
+	options := []string{column.Name}
+
+	if column.Name == softDeleteColumn {
+		imports[importTime] = true
+
+		return modelField{
+			comment: column.Comment,
+			name:    name,
+			typ:     "time.Time",
+			tag:     fmt.Sprintf(`json:"%s" bun:"%s,soft_delete,nullzero"`, jsonName(name), column.Name),
+		}
+	}
+
+	if primaryKey {
+		options = append(options, "pk")
+	}
+
+	if column.IsAutoIncrement {
+		options = append(options, "autoincrement")
+	}
+
+	t := mapType(column.Type)
+	typ, imp := t.name, t.imp
+
+	if column.Nullable && !primaryKey {
+		typ, imp = t.nullName, t.nullImp
+	} else if !primaryKey {
+		options = append(options, "notnull")
+	}
+
+	if imp != constants.Empty {
+		imports[imp] = true
+	}
+
+	return modelField{
+		comment: column.Comment,
+		name:    name,
+		typ:     typ,
+		tag:     fmt.Sprintf(`json:"%s" bun:"%s"`, jsonName(name), strings.Join(options, ",")),
+	}
+}
+
+// mapType maps a database column type to Go types, falling back to strings for unknown types.
+func mapType(columnType string) goType {
+	raw := strings.ToLower(strings.TrimSpace(columnType))
+	base := strings.TrimSpace(typeParams.ReplaceAllString(raw, ""))
+	base = strings.TrimSpace(strings.TrimSuffix(base, "unsigned"))
+
+	switch base {
+	case "tinyint":
+		if strings.HasPrefix(raw, "tinyint(1)") {
+			return goType{name: "bool", nullName: "null.Bool", nullImp: importNull}
+		}
+
+		return goType{name: "int16", nullName: "null.Int16", nullImp: importNull}
+	case "smallint", "int2", "smallserial":
+		return goType{name: "int16", nullName: "null.Int16", nullImp: importNull}
+	case "int", "integer", "int4", "mediumint", "serial":
+		return goType{name: "int32", nullName: "null.Int32", nullImp: importNull}
+	case "bigint", "int8", "bigserial":
+		return goType{name: "int64", nullName: "null.Int", nullImp: importNull}
+	case "bool", "boolean":
+		return goType{name: "bool", nullName: "null.Bool", nullImp: importNull}
+	case "decimal", "numeric":
+		return goType{name: "decimal.Decimal", imp: importDecimal, nullName: "null.Decimal", nullImp: importNull}
+	case "real", "float", "float4", "double", "double precision", "float8":
+		return goType{name: "float64", nullName: "null.Float", nullImp: importNull}
+	case "date":
+		return goType{name: "datetime.Date", imp: importDatetime, nullName: "null.Date", nullImp: importNull}
+	case "time", "time without time zone":
+		return goType{name: "datetime.Time", imp: importDatetime, nullName: "null.Time", nullImp: importNull}
+	case "timestamp", "timestamptz", "datetime", "timestamp without time zone", "timestamp with time zone":
+		return goType{name: "datetime.DateTime", imp: importDatetime, nullName: "null.DateTime", nullImp: importNull}
+	case "json", "jsonb":
+		return goType{name: "map[string]any", nullName: "map[string]any"}
+	case "bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary":
+		return goType{name: "[]byte", nullName: "[]byte"}
+	default:
+		return goType{name: "string", nullName: "null.String", nullImp: importNull}
+	}
+}
+
+func isStringColumn(table *schema.TableSchema, name string) bool {
+	for _, column := range table.Columns {
+		if column.Name == name {
+			return mapType(column.Type).name == "string"
+		}
+	}
+
+	return false
+}
+
+func writeModel(sb *strings.Builder, m model) {
+	sb.WriteString("\n")
+
+	if m.comment != constants.Empty {
+		sb.WriteString(fmt.Sprintf("// %s maps the %s table: %s\n", m.name, m.table, m.comment))
+	} else {
+		sb.WriteString(fmt.Sprintf("// %s maps the %s table.\n", m.name, m.table))
+	}
+
+	sb.WriteString(fmt.Sprintf("type %s struct {\n", m.name))
+	sb.WriteString(fmt.Sprintf("\torm.BaseModel `bun:\"table:%s,alias:%s\"`\n", m.table, m.alias))
+
+	for _, embed := range m.embeds {
+		sb.WriteString("\t" + embed + "\n")
+	}
+
+	sb.WriteString("\n")
+
+	for _, field := range m.fields {
+		if field.comment != constants.Empty {
+			sb.WriteString("\t// " + field.comment + "\n")
+		}
+
+		sb.WriteString(fmt.Sprintf("\t%s %s `%s`\n", field.name, field.typ, field.tag))
+	}
+
+	for _, stub := range m.stubs {
+		sb.WriteString("\t// " + stub + "\n")
+	}
+
+	sb.WriteString("}\n")
+}
+
+func sortedImports(imports map[string]bool) []string {
+	var std, module []string
+
+	for imp := range imports {
+		if strings.Contains(imp, ".") {
+			module = append(module, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+
+	slices.Sort(std)
+	slices.Sort(module)
+
+	if len(std) > 0 && len(module) > 0 {
+		std = append(std, constants.Empty)
+	}
+
+	return append(std, module...)
+}
+
+// structName names the struct of a table, e.g. "User" for "sys_user" with prefix "sys_".
+func structName(table, trimPrefix string) string {
+	return identifier(strings.TrimPrefix(table, trimPrefix))
+}
+
+// identifier converts a snake case name to a Go identifier, e.g. "ParentID" for "parent_id".
+func identifier(name string) string {
+	var sb strings.Builder
+
+	for word := range strings.SplitSeq(strings.ToLower(name), "_") {
+		if word == constants.Empty {
+			continue
+		}
+
+		if initialisms[word] {
+			sb.WriteString(strings.ToUpper(word))
+		} else {
+			sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+
+	return sb.String()
+}
+
+// jsonName converts a Go identifier to the lower camel case JSON name, e.g. "parentId" for "ParentID".
+func jsonName(name string) string {
+	var sb strings.Builder
+
+	runes := []rune(name)
+	for i, r := range runes {
+		upper := r >= 'A' && r <= 'Z'
+		// Keep the first letter of each initialism upper case after the first word, e.g. "userId" from "UserID"
+		wordStart := i == 0 || !(runes[i-1] >= 'A' && runes[i-1] <= 'Z')
+
+		if upper && (i == 0 || !wordStart) {
+			sb.WriteRune(r + ('a' - 'A'))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
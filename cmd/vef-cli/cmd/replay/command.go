@@ -0,0 +1,98 @@
+package replay
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+// Command returns the replay cobra command.
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay [flags] <capture file or directory>...",
+		Short: "Replay captured requests against another environment",
+		Long: `Replay requests captured by the capture middleware against another environment.
+
+Captured records are JSON files stored under the "captures/" prefix of the object storage.
+Download them and pass the files or directories to this command. Every request is sent
+to the target and the response status is compared with the captured one.
+
+Redacted values (e.g. the Authorization header) cannot be replayed as captured, pass
+replacements with --header. Requests whose body was not recorded are skipped.
+
+Example usage:
+  vef-cli replay -t http://staging:8080 captures/2026/10/16
+  vef-cli replay -t http://localhost:8080 -H "Authorization: Bearer <token>" --compare-body record.json
+`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runReplay,
+	}
+
+	cmd.Flags().StringP("target", "t", "", "Base URL of the environment to replay against")
+	cmd.Flags().StringArrayP("header", "H", nil, `Header to set on every request, e.g. "Authorization: Bearer <token>"`)
+	cmd.Flags().Bool("compare-body", false, "Also compare the response bodies")
+	cmd.Flags().Duration("timeout", 30*time.Second, "Timeout of each request")
+
+	_ = cmd.MarkFlagRequired("target")
+
+	return cmd
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	target, _ := cmd.Flags().GetString("target")
+	headers, _ := cmd.Flags().GetStringArray("header")
+	compareBody, _ := cmd.Flags().GetBool("compare-body")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	output := termenv.DefaultOutput()
+
+	records, err := LoadRecords(args)
+	if err != nil {
+		return err
+	}
+
+	replayer, err := NewReplayer(target, headers, timeout)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Println(output.String(fmt.Sprintf("Replaying %d captured requests against %s...", len(records), target)).Foreground(termenv.ANSICyan))
+
+	var mismatches, skipped int
+
+	for _, record := range records {
+		outcome := replayer.Replay(cmd.Context(), record, compareBody)
+		line := fmt.Sprintf("%s %s (%s)", record.Request.Method, record.Request.Path, record.ID)
+
+		switch {
+		case outcome.Skipped != "":
+			skipped++
+
+			_, _ = fmt.Println(output.String("- " + line + ": skipped, " + outcome.Skipped).Foreground(termenv.ANSIBrightBlack))
+		case outcome.Err != nil:
+			mismatches++
+
+			_, _ = fmt.Println(output.String(fmt.Sprintf("✗ %s: %v", line, outcome.Err)).Foreground(termenv.ANSIRed))
+		case len(outcome.Differences) > 0:
+			mismatches++
+
+			_, _ = fmt.Println(output.String("✗ " + line).Foreground(termenv.ANSIRed))
+			for _, difference := range outcome.Differences {
+				_, _ = fmt.Println(output.String("    " + difference).Foreground(termenv.ANSIBrightBlack))
+			}
+		default:
+			_, _ = fmt.Println(output.String("✓ " + line).Foreground(termenv.ANSIGreen))
+		}
+	}
+
+	replayed := len(records) - skipped
+	if mismatches > 0 {
+		return fmt.Errorf("%w: %d of %d replayed requests", errResponsesDiffer, mismatches, replayed)
+	}
+
+	_, _ = fmt.Println(output.String(fmt.Sprintf("✓ All %d replayed requests matched", replayed)).Foreground(termenv.ANSIGreen))
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/middleware"
+)
+
+var (
+	errResponsesDiffer = errors.New("responses differ")
+	errInvalidHeader   = errors.New(`header must have the form "Name: value"`)
+	errInvalidTarget   = errors.New("target must be an absolute http(s) URL")
+)
+
+// skippedHeaders are not replayed since the HTTP client manages them.
+var skippedHeaders = map[string]struct{}{
+	"host":              {},
+	"content-length":    {},
+	"connection":        {},
+	"accept-encoding":   {},
+	"transfer-encoding": {},
+}
+
+// Outcome is the result of replaying a record.
+type Outcome struct {
+	// Skipped is the reason the record was not replayed.
+	Skipped string
+	// Err is the error of sending the request.
+	Err error
+	// Differences describe how the response differs from the captured one.
+	Differences []string
+}
+
+// Replayer sends captured requests to a target environment.
+type Replayer struct {
+	target  *url.URL
+	headers http.Header
+	client  *http.Client
+}
+
+// NewReplayer creates a replayer for the target base URL.
+// Headers have the form "Name: value" and replace the captured values.
+func NewReplayer(target string, headers []string, timeout time.Duration) (*Replayer, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil || (targetURL.Scheme != "http" && targetURL.Scheme != "https") || targetURL.Host == "" {
+		return nil, fmt.Errorf("%w: %s", errInvalidTarget, target)
+	}
+
+	overrides := make(http.Header, len(headers))
+	for _, header := range headers {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("%w: %s", errInvalidHeader, header)
+		}
+
+		overrides.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	return &Replayer{
+		target:  targetURL,
+		headers: overrides,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Replay sends the captured request and compares the response with the captured one.
+func (r *Replayer) Replay(ctx context.Context, record *middleware.CaptureRecord, compareBody bool) Outcome {
+	if record.Request.BodyOmitted {
+		return Outcome{Skipped: "request body was not captured"}
+	}
+
+	req, err := r.buildRequest(ctx, record)
+	if err != nil {
+		return Outcome{Err: err}
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return Outcome{Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Outcome{Err: fmt.Errorf("failed to read response: %w", err)}
+	}
+
+	var differences []string
+	if resp.StatusCode != record.Response.Status {
+		differences = append(differences, fmt.Sprintf("status: captured %d, replayed %d", record.Response.Status, resp.StatusCode))
+	}
+
+	if compareBody && !record.Response.BodyOmitted && !sameBody(record.Response.Body, string(body)) {
+		differences = append(differences, "body: captured "+truncate(record.Response.Body)+", replayed "+truncate(string(body)))
+	}
+
+	return Outcome{Differences: differences}
+}
+
+func (r *Replayer) buildRequest(ctx context.Context, record *middleware.CaptureRecord) (*http.Request, error) {
+	target := r.target.JoinPath(record.Request.Path)
+	target.RawQuery = record.Request.Query
+
+	req, err := http.NewRequestWithContext(ctx, record.Request.Method, target.String(), strings.NewReader(record.Request.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	for name, value := range record.Request.Headers {
+		if _, skipped := skippedHeaders[strings.ToLower(name)]; skipped || value == middleware.RedactedValue {
+			continue
+		}
+
+		req.Header.Set(name, value)
+	}
+
+	for name, values := range r.headers {
+		req.Header[name] = values
+	}
+
+	return req, nil
+}
+
+// sameBody compares JSON bodies structurally and other bodies literally.
+func sameBody(captured, replayed string) bool {
+	var capturedValue, replayedValue any
+	if json.Unmarshal([]byte(captured), &capturedValue) != nil || json.Unmarshal([]byte(replayed), &replayedValue) != nil {
+		return captured == replayed
+	}
+
+	capturedJSON, _ := json.Marshal(capturedValue)
+	replayedJSON, _ := json.Marshal(replayedValue)
+
+	return string(capturedJSON) == string(replayedJSON)
+}
+
+func truncate(body string) string {
+	const maxLength = 200
+
+	if len(body) <= maxLength {
+		return body
+	}
+
+	return body[:maxLength] + "..."
+}
+
+// LoadRecords reads capture records from files and directories, ordered by capture time.
+func LoadRecords(paths []string) ([]*middleware.CaptureRecord, error) {
+	var records []*middleware.CaptureRecord
+
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() || filepath.Ext(file) != ".json" {
+				return nil
+			}
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+
+			var record middleware.CaptureRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return fmt.Errorf("failed to parse capture record %s: %w", file, err)
+			}
+
+			records = append(records, &record)
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortStableFunc(records, func(a, b *middleware.CaptureRecord) int {
+		return a.CapturedAt.Compare(b.CapturedAt)
+	})
+
+	return records, nil
+}
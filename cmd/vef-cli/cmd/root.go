@@ -8,7 +8,11 @@ import (
 
 	"github.com/ilxqx/vef-framework-go/cmd/vef-cli/cmd/buildinfo"
 	"github.com/ilxqx/vef-framework-go/cmd/vef-cli/cmd/create"
+	"github.com/ilxqx/vef-framework-go/cmd/vef-cli/cmd/data"
+	"github.com/ilxqx/vef-framework-go/cmd/vef-cli/cmd/db"
+	"github.com/ilxqx/vef-framework-go/cmd/vef-cli/cmd/gen"
 	"github.com/ilxqx/vef-framework-go/cmd/vef-cli/cmd/modelschema"
+	"github.com/ilxqx/vef-framework-go/cmd/vef-cli/cmd/replay"
 )
 
 var (
@@ -48,6 +52,10 @@ func init() {
 		create.Command(),
 		buildinfo.Command(),
 		modelschema.Command(),
+		replay.Command(),
+		data.Command(),
+		db.Command(),
+		gen.Command(),
 	}
 
 	setupHelpColors(rootCmd)
@@ -0,0 +1,11 @@
+package config
+
+// CaptureConfig defines sampling of request/response pairs into the object storage,
+// from where they can be replayed against another environment with `vef-cli replay`.
+type CaptureConfig struct {
+	Enabled      bool     `config:"enabled"`       // Capture sampled requests (default: false)
+	SampleRate   float64  `config:"sample_rate"`   // Fraction of matching requests captured, between 0 and 1 (default: 0.01)
+	Paths        []string `config:"paths"`         // Path prefixes of captured requests (default: /api)
+	MaxBodySize  int      `config:"max_body_size"` // Bodies larger than this many bytes are not recorded (default: 65536)
+	RedactFields []string `config:"redact_fields"` // Additional JSON fields whose values are redacted
+}
@@ -1,7 +1,27 @@
 package config
 
 // CorsConfig defines CORS middleware settings.
+// Routes override the default policy for the paths they match; the first matching route applies.
 type CorsConfig struct {
-	Enabled      bool     `config:"enabled"`
-	AllowOrigins []string `config:"allow_origins"`
+	Enabled    bool `config:"enabled"`
+	CorsPolicy `config:",squash"`
+
+	Routes []CorsRouteConfig `config:"routes"`
+}
+
+// CorsPolicy defines the cross-origin requests admitted.
+type CorsPolicy struct {
+	AllowOrigins     []string `config:"allow_origins"`
+	AllowMethods     []string `config:"allow_methods"`     // Default: HEAD, GET, POST, PUT, DELETE
+	AllowHeaders     []string `config:"allow_headers"`     // Default: content type, authorization, request id and signature headers
+	ExposeHeaders    []string `config:"expose_headers"`    // Response headers readable by scripts
+	AllowCredentials bool     `config:"allow_credentials"` // Admit cookies and authorization headers; requires explicit origins
+	MaxAge           int      `config:"max_age"`           // Seconds preflight responses may be cached (default: 7200)
+}
+
+// CorsRouteConfig defines the CORS policy of the paths under a prefix.
+// Lists and the max age left empty are taken from the default policy.
+type CorsRouteConfig struct {
+	Path       string `config:"path"` // Path prefix, e.g. "/api/public"
+	CorsPolicy `config:",squash"`
 }
@@ -1,16 +1,124 @@
 package config
 
-import "github.com/ilxqx/vef-framework-go/constants"
+import (
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
 
 // DatasourceConfig defines database connection settings.
 type DatasourceConfig struct {
-	Type           constants.DBType `config:"type"`
-	Host           string           `config:"host"`
-	Port           uint16           `config:"port"`
-	User           string           `config:"user"`
-	Password       string           `config:"password"`
-	Database       string           `config:"database"`
-	Schema         string           `config:"schema"`
-	Path           string           `config:"path"`
-	EnableSQLGuard bool             `config:"enable_sql_guard"`
+	Type               constants.DBType       `config:"type"`
+	Host               string                 `config:"host"`
+	Port               uint16                 `config:"port"`
+	User               string                 `config:"user"`
+	Password           string                 `config:"password"`
+	Database           string                 `config:"database"`
+	Schema             string                 `config:"schema"`
+	Path               string                 `config:"path"`
+	EnableSQLGuard     bool                   `config:"enable_sql_guard"`
+	EnableContextAudit bool                   `config:"enable_context_audit"` // Flag queries run from request handlers without the request context (debugging only)
+	StatementTimeout   time.Duration          `config:"statement_timeout"`    // Cancel statements running longer: statement_timeout on Postgres, max_execution_time (selects only) on MySQL, a context deadline elsewhere (default: unlimited)
+	LeakDetection      LeakDetectionConfig    `config:"leak_detection"`
+	PoolMetrics        PoolMetricsConfig      `config:"pool_metrics"`
+	QueryPolicy        QueryPolicyConfig      `config:"query_policy"`
+	SchemaCache        SchemaCacheConfig      `config:"schema_cache"`
+	JSONCodec          string                 `config:"json_codec"` // Codec of JSON columns and bind parameters: std, sonic (requires the sonic build tag) or a registered codec (default: std)
+	TLS                DatasourceTLSConfig    `config:"tls"`
+	SSHTunnel          SSHTunnelConfig        `config:"ssh_tunnel"`
+	Auth               DatasourceAuthConfig   `config:"auth"`
+	Encryption         SQLiteEncryptionConfig `config:"encryption"`
+}
+
+// TLS modes of datasource connections.
+const (
+	TLSModeDisable    = "disable"     // Plain connections
+	TLSModeRequire    = "require"     // Encrypted connections without verification of the server certificate
+	TLSModeVerifyCA   = "verify_ca"   // Encrypted connections to servers whose certificate is signed by a trusted CA
+	TLSModeVerifyFull = "verify_full" // As verify_ca, the certificate also has to match the server name
+)
+
+// DatasourceTLSConfig defines encrypted connections to Postgres and MySQL datasources.
+// Certificates without CA bundle are verified against the system roots.
+type DatasourceTLSConfig struct {
+	Mode       string `config:"mode"`        // disable, require, verify_ca or verify_full (default: disable)
+	CAFile     string `config:"ca_file"`     // PEM bundle of the CAs trusted to sign the server certificate
+	CertFile   string `config:"cert_file"`   // PEM client certificate, for servers requiring client authentication
+	KeyFile    string `config:"key_file"`    // PEM private key of the client certificate
+	ServerName string `config:"server_name"` // Name verified by verify_full (default: host)
+}
+
+// SSHTunnelConfig defines connections to Postgres and MySQL datasources through an SSH bastion host.
+// The host key is verified against the known hosts file unless explicitly disabled.
+type SSHTunnelConfig struct {
+	Enabled               bool   `config:"enabled"`
+	Host                  string `config:"host"`
+	Port                  uint16 `config:"port"` // default: 22
+	User                  string `config:"user"`
+	Password              string `config:"password"`
+	PrivateKeyFile        string `config:"private_key_file"`
+	PrivateKeyPassphrase  string `config:"private_key_passphrase"`
+	KnownHostsFile        string `config:"known_hosts_file"`
+	InsecureIgnoreHostKey bool   `config:"insecure_ignore_host_key"` // Accept any host key (testing only)
+}
+
+// DatasourceAuthConfig defines token based authentication of Postgres and MySQL datasources, replacing the password.
+// Tokens are obtained from the identity of the deployment and refreshed before they expire.
+type DatasourceAuthConfig struct {
+	Provider      string        `config:"provider"`       // aws_rds_iam, gcp_cloud_sql_iam, azure_ad or a registered provider (default: password)
+	Region        string        `config:"region"`         // AWS region of the database (default: AWS_REGION)
+	ClientID      string        `config:"client_id"`      // Client id of the Azure user-assigned managed identity (default: system-assigned identity)
+	RefreshBefore time.Duration `config:"refresh_before"` // Refresh tokens this long before they expire (default: 5m)
+}
+
+// SQLiteEncryptionConfig defines encryption at rest of SQLite databases with SQLCipher, e.g. for edge and embedded deployments.
+// It requires the binary to link a SQLCipher build of SQLite (cgosqlite and libsqlite3 build tags), the embedded driver cannot encrypt.
+// The key is a passphrase, given by exactly one of key, key_env or key_file.
+type SQLiteEncryptionConfig struct {
+	Enabled bool   `config:"enabled"`
+	Key     string `config:"key"`      // Passphrase, prefer key_env or key_file to keep it out of config files
+	KeyEnv  string `config:"key_env"`  // Environment variable holding the passphrase
+	KeyFile string `config:"key_file"` // File holding the passphrase, e.g. a secret mounted by the orchestrator
+}
+
+// LeakDetectionConfig defines connection leak detection settings.
+// Detection records a stack trace per query and should only be enabled for debugging.
+type LeakDetectionConfig struct {
+	Enabled       bool          `config:"enabled"`        // Record connection checkouts and expose current holders (default: false)
+	HoldThreshold time.Duration `config:"hold_threshold"` // Warn when a connection is held longer (default: 30s)
+}
+
+// PoolMetricsConfig defines the sampling of the connection pool statistics, served as Prometheus metrics and reported
+// to the health check so exhaustion of the pool can be alerted on.
+type PoolMetricsConfig struct {
+	Enabled         bool          `config:"enabled"`          // Sample the pool and serve the metrics (default: false)
+	Interval        time.Duration `config:"interval"`         // Interval between samples (default: 15s)
+	Path            string        `config:"path"`             // Path serving the Prometheus metrics (default: /metrics)
+	ExhaustionRatio float64       `config:"exhaustion_ratio"` // Share of the maximum open connections in use reported as degraded (default: 0.9)
+}
+
+// SchemaCacheConfig defines caching of inspected table schemas.
+// Cached schemas are invalidated by schema.SchemaChangedEvent, which migrations publish after they run,
+// and whenever the schema version returned by the version query changes, e.g. after another instance deployed.
+type SchemaCacheConfig struct {
+	Enabled       bool          `config:"enabled"`        // Cache inspected schemas (default: false)
+	VersionQuery  string        `config:"version_query"`  // Query returning the schema version, e.g. "SELECT MAX(version) FROM schema_migrations"
+	CheckInterval time.Duration `config:"check_interval"` // Minimum interval between schema version checks (default: 30s)
+}
+
+// QueryPolicyConfig defines execution limits enforced on every statement.
+// Group policies are keyed by API resource prefix (e.g. "sys" or "sys/user") and model policies by table name;
+// each field falls back from the model policy to the most specific group policy and then to the default policy.
+type QueryPolicyConfig struct {
+	Enabled bool                   `config:"enabled"` // Enforce query policies (default: false)
+	Default QueryPolicy            `config:"default"` // Policy applied to all statements
+	Groups  map[string]QueryPolicy `config:"groups"`  // Policies per API resource prefix
+	Models  map[string]QueryPolicy `config:"models"`  // Policies per table name
+}
+
+// QueryPolicy defines the limits of a statement.
+type QueryPolicy struct {
+	MaxExecutionTime time.Duration `config:"max_execution_time"` // Cancel statements running longer (default: unlimited)
+	MaxRows          int           `config:"max_rows"`           // Reject selects limited above and report larger results (default: unlimited)
+	Forbidden        []string      `config:"forbidden"`          // Forbidden operations: drop, truncate, delete_without_where, update_without_where
 }
@@ -0,0 +1,12 @@
+package config
+
+import "time"
+
+// DelayJobConfig defines the polling of delayed jobs from the sys_delayed_job table.
+type DelayJobConfig struct {
+	Enabled      bool          `config:"enabled"`       // Run due delayed jobs (default: false)
+	PollInterval time.Duration `config:"poll_interval"` // Maximum time between two polls for due jobs (default: 5s)
+	BatchSize    int           `config:"batch_size"`    // Maximum number of jobs claimed per poll (default: 20)
+	Concurrency  int           `config:"concurrency"`   // Maximum number of jobs running at the same time (default: 10)
+	Lease        time.Duration `config:"lease"`         // Time a claimed job may run before other instances may claim it again (default: 5m)
+}
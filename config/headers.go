@@ -0,0 +1,27 @@
+package config
+
+const (
+	// HeadersProfileBasic sets X-Content-Type-Options, Strict-Transport-Security over https and a no-store Cache-Control.
+	HeadersProfileBasic = "basic"
+	// HeadersProfileStrict adds a restrictive Content-Security-Policy, X-Frame-Options, Referrer-Policy,
+	// Permissions-Policy and Cross-Origin-Opener-Policy to the basic profile, with a preloadable HSTS policy.
+	HeadersProfileStrict = "strict"
+	// HeadersProfileNone sets only the headers configured explicitly.
+	HeadersProfileNone = "none"
+
+	// HeaderValueOff disables a header set by the profile.
+	HeaderValueOff = "off"
+)
+
+// HeadersConfig defines the security headers set on responses.
+// Each header left empty takes the value of the profile, HeaderValueOff omits it.
+type HeadersConfig struct {
+	Profile                 string `config:"profile"`                    // basic, strict or none (default: basic)
+	ContentSecurityPolicy   string `config:"content_security_policy"`    // Content-Security-Policy
+	StrictTransportSecurity string `config:"strict_transport_security"`  // Strict-Transport-Security, only sent over https
+	FrameOptions            string `config:"frame_options"`              // X-Frame-Options, e.g. DENY or SAMEORIGIN
+	ReferrerPolicy          string `config:"referrer_policy"`            // Referrer-Policy
+	PermissionsPolicy       string `config:"permissions_policy"`         // Permissions-Policy
+	CrossOriginOpenerPolicy string `config:"cross_origin_opener_policy"` // Cross-Origin-Opener-Policy
+	CacheControl            string `config:"cache_control"`              // Cache-Control of responses setting none
+}
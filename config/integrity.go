@@ -0,0 +1,18 @@
+package config
+
+// IntegrityConfig defines the logical foreign keys checked for orphaned references and their scheduled check.
+type IntegrityConfig struct {
+	Schedule   string            `config:"schedule"`   // Cron expression of the scheduled check, empty disables it
+	Fix        bool              `config:"fix"`        // Fix the orphans found by the scheduled check (default: false, report only)
+	References []ReferenceConfig `config:"references"` // Logical foreign keys checked in addition to the provided ones
+}
+
+// ReferenceConfig defines a logical foreign key not enforced by a database constraint.
+type ReferenceConfig struct {
+	Name      string `config:"name"`       // Name identifying the reference (default: table.column)
+	Table     string `config:"table"`      // Referencing table
+	Column    string `config:"column"`     // Referencing column
+	RefTable  string `config:"ref_table"`  // Referenced table
+	RefColumn string `config:"ref_column"` // Referenced column (default: id)
+	OnOrphan  string `config:"on_orphan"`  // Fix applied to orphans: report, set_null or delete (default: report)
+}
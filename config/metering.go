@@ -0,0 +1,12 @@
+package config
+
+import "time"
+
+// MeteringConfig defines the aggregation of recorded usage into billing events.
+type MeteringConfig struct {
+	Enabled       bool          `config:"enabled"`        // Record usage and emit billing events (default: false)
+	Window        time.Duration `config:"window"`         // Length of the aggregation windows, aligned to the Unix epoch (default: 1h)
+	FlushInterval time.Duration `config:"flush_interval"` // Time between two flushes of the buffered usage (default: 10s)
+	Grace         time.Duration `config:"grace"`          // Time after the end of a window before it is closed, covering late flushes (default: 1m)
+	BatchSize     int           `config:"batch_size"`     // Maximum number of windows closed or events published per run (default: 100)
+}
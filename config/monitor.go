@@ -4,6 +4,15 @@ import "time"
 
 // MonitorConfig defines monitoring service settings.
 type MonitorConfig struct {
-	SampleInterval time.Duration `config:"sample_interval"` // Interval between samples (default: 10s)
-	SampleDuration time.Duration `config:"sample_duration"` // Sampling window duration (default: 2s)
+	SampleInterval time.Duration        `config:"sample_interval"` // Interval between samples (default: 10s)
+	SampleDuration time.Duration        `config:"sample_duration"` // Sampling window duration (default: 2s)
+	LockContention LockContentionConfig `config:"lock_contention"` // Lock contention detection settings
+}
+
+// LockContentionConfig defines lock wait sampling settings for hot row contention detection.
+type LockContentionConfig struct {
+	Enabled        bool          `config:"enabled"`         // Enable lock wait sampling (default: false)
+	SampleInterval time.Duration `config:"sample_interval"` // Interval between lock wait samples (default: 5s)
+	Window         time.Duration `config:"window"`          // Period over which lock waits are aggregated (default: 10m)
+	Threshold      int           `config:"threshold"`       // Number of lock waits within the window that flags a statement or row as hot (default: 3)
 }
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+// ReportConfig defines the scheduled generation of registered reports.
+type ReportConfig struct {
+	Enabled     bool          `config:"enabled"`      // Schedule the registered reports (default: false)
+	KeyPrefix   string        `config:"key_prefix"`   // Storage key prefix of the rendered artifacts (default: reports/)
+	LinkExpires time.Duration `config:"link_expires"` // Lifetime of the delivered download links, capped by vef.storage.signing.max_expires (default: the signing default)
+}
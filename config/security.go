@@ -4,5 +4,39 @@ import "time"
 
 // SecurityConfig defines security settings.
 type SecurityConfig struct {
-	TokenExpires time.Duration `config:"token_expires"`
+	TokenExpires         time.Duration     `config:"token_expires"`
+	ImpersonationExpires time.Duration     `config:"impersonation_expires"` // Absolute lifetime of impersonations, refreshes included (default: 1h)
+	Session              SessionConfig     `config:"session"`
+	LoginAudit           LoginAuditConfig  `config:"login_audit"`
+	ExternalApp          ExternalAppConfig `config:"external_app"`
+	IPFilter             IPFilterConfig    `config:"ip_filter"`
+}
+
+// SessionConfig defines tracking of login sessions per user and device.
+// With sessions enabled tokens are only accepted while their session is active, which allows remote logout.
+type SessionConfig struct {
+	Enabled       bool   `config:"enabled"`        // Track sessions and reject tokens of revoked sessions (default: false)
+	MaxConcurrent int    `config:"max_concurrent"` // Maximum number of active sessions per user; 0 is unlimited
+	LimitStrategy string `config:"limit_strategy"` // Handling of logins beyond the limit: "evict_oldest" revokes the oldest session, "reject" refuses the login (default: evict_oldest)
+}
+
+// LoginAuditConfig defines recording of logins and logouts to the sys_login_record table.
+type LoginAuditConfig struct {
+	Enabled        bool    `config:"enabled"`          // Record logins, failed attempts and logouts (default: false)
+	MaxTravelSpeed float64 `config:"max_travel_speed"` // Speed in km/h above which consecutive logins are flagged as impossible travel (default: 1000)
+}
+
+// ExternalAppConfig defines the registry of open-platform callers authenticated by request signatures.
+type ExternalAppConfig struct {
+	Registry bool `config:"registry"` // Load apps from the sys_external_app table when no ExternalAppLoader is provided (default: false)
+}
+
+// IPFilterConfig defines the application wide IP allowlist and denylist.
+// Entries are IP addresses or CIDR ranges; deny entries take precedence over allow entries.
+type IPFilterConfig struct {
+	Enabled        bool     `config:"enabled"`         // Reject requests from blocked addresses (default: false)
+	Allow          []string `config:"allow"`           // Addresses admitted exclusively once non-empty
+	Deny           []string `config:"deny"`            // Addresses always rejected
+	Managed        bool     `config:"managed"`         // Also apply the enabled rules of the sys_ip_rule table (default: false)
+	TrustedProxies []string `config:"trusted_proxies"` // Proxies whose X-Forwarded-For entries are trusted, also by operation filters; empty filters the remote address
 }
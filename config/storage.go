@@ -1,12 +1,19 @@
 package config
 
-import "github.com/ilxqx/vef-framework-go/constants"
+import (
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
 
 // StorageConfig defines storage provider settings.
 type StorageConfig struct {
 	Provider   constants.StorageProvider `config:"provider"`
 	MinIO      MinIOConfig               `config:"minio"`
 	Filesystem FilesystemConfig          `config:"filesystem"`
+	Multipart  MultipartConfig           `config:"multipart"`
+	Processing ProcessingConfig          `config:"processing"`
+	Signing    SigningConfig             `config:"signing"`
 }
 
 // MinIOConfig defines MinIO storage settings.
@@ -23,3 +30,42 @@ type MinIOConfig struct {
 type FilesystemConfig struct {
 	Root string `config:"root"`
 }
+
+// MultipartConfig defines chunked uploads.
+type MultipartConfig struct {
+	Expiry time.Duration `config:"expiry"` // Uploads without activity for this long are removed, swept hourly (default: 24h)
+}
+
+// ProcessingConfig defines post-processing of uploaded files.
+// Uploads are processed asynchronously by a worker pool and their status is tracked in the object metadata.
+type ProcessingConfig struct {
+	Enabled bool          `config:"enabled"`  // Process uploaded files (default: false)
+	Workers int           `config:"workers"`  // Maximum number of concurrent processing workers (default: 2)
+	Queue   int           `config:"queue"`    // Maximum number of queued uploads (default: 100)
+	Timeout time.Duration `config:"timeout"`  // Processing timeout of a single upload (default: 2m)
+	MaxSize int64         `config:"max_size"` // Maximum size in bytes of processed files, larger files fail processing (default: 32MB)
+	ClamAV  ClamAVConfig  `config:"clamav"`
+	Image   ImageConfig   `config:"image"`
+}
+
+// ClamAVConfig defines virus scanning of uploaded files with a clamd daemon.
+type ClamAVConfig struct {
+	Address string        `config:"address"` // clamd address, e.g. "tcp://127.0.0.1:3310" or "unix:///run/clamav/clamd.ctl"; empty disables scanning
+	Timeout time.Duration `config:"timeout"` // Timeout of a single scan (default: 30s)
+}
+
+// ImageConfig defines processing of uploaded JPEG, PNG and GIF images.
+type ImageConfig struct {
+	MaxWidth        int  `config:"max_width"`        // Images wider than this are scaled down; 0 keeps the width
+	MaxHeight       int  `config:"max_height"`       // Images higher than this are scaled down; 0 keeps the height
+	ThumbnailWidth  int  `config:"thumbnail_width"`  // Width bound of the generated thumbnail; 0 with a zero height disables thumbnails
+	ThumbnailHeight int  `config:"thumbnail_height"` // Height bound of the generated thumbnail
+	StripEXIF       bool `config:"strip_exif"`       // Re-encode JPEG images to drop EXIF and other metadata segments
+}
+
+// SigningConfig defines signed URLs granting temporary access to private objects.
+type SigningConfig struct {
+	Secret         string        `config:"secret"`          // HMAC secret shared by all instances; a random secret is generated when empty, invalidating URLs on restart
+	DefaultExpires time.Duration `config:"default_expires"` // Lifetime of signed URLs without an explicit expiry (default: 1h)
+	MaxExpires     time.Duration `config:"max_expires"`     // Maximum lifetime of signed URLs (default: 7d)
+}
@@ -0,0 +1,22 @@
+package config
+
+import "time"
+
+// SyncConfig defines the synchronization of models between a central server and embedded clients.
+// The server serves the changes logged in the sys_sync_change table, clients push their local changes and pull the server changes.
+type SyncConfig struct {
+	PullLimit    int              `config:"pull_limit"`    // Maximum number of changes returned per pull (default: 500)
+	SettleWindow time.Duration    `config:"settle_window"` // Time changes logged after a gap in the log are held back, longer than the longest transaction (default: 30s)
+	Client       SyncClientConfig `config:"client"`
+}
+
+// SyncClientConfig defines the synchronization of an embedded client app with its server.
+type SyncClientConfig struct {
+	Enabled   bool          `config:"enabled"`
+	Endpoint  string        `config:"endpoint"`   // RPC endpoint of the server, e.g. https://example.com/api
+	ClientID  string        `config:"client_id"`  // Identifies the client, unique among the clients of the server
+	Token     string        `config:"token"`      // Bearer token authorizing the requests, unless a datasync.Transport is provided
+	Models    []string      `config:"models"`     // Synchronized models (default: all declared models)
+	BatchSize int           `config:"batch_size"` // Maximum number of changes pushed or pulled per request (default: 200)
+	Interval  time.Duration `config:"interval"`   // Interval of the scheduled synchronization, zero syncs on demand only
+}
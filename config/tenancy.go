@@ -0,0 +1,10 @@
+package config
+
+// TenancyConfig defines the isolation of the data of tenants by the ORM, the tenant of a request is read with contextx.TenantID
+// unless an orm.TenantResolver is provided. Tenancy is disabled when no strategy is configured.
+type TenancyConfig struct {
+	Strategy      string `config:"strategy"`       // row (tenant column in shared tables) or schema (a PostgreSQL schema per tenant)
+	Column        string `config:"column"`         // Tenant column of row-per-tenant tenancy (default: tenant_id)
+	SchemaPrefix  string `config:"schema_prefix"`  // Prefix of the tenant ID naming the schema of schema-per-tenant tenancy (default: tenant_)
+	RequireTenant bool   `config:"require_tenant"` // Fail statements of requests without tenant rather than leaving them unisolated
+}
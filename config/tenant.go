@@ -0,0 +1,30 @@
+package config
+
+import "context"
+
+// TenantConfig resolves configuration values and feature flags with the overrides of the tenant of the request,
+// letting multi-tenant deployments vary limits and features per customer.
+// The tenant is read with contextx.TenantID; requests without tenant get the plain configuration.
+type TenantConfig interface {
+	// Unmarshal decodes the configuration at the given key into target, with the overrides of the tenant applied on top.
+	Unmarshal(ctx context.Context, key string, target any) error
+	// FeatureEnabled reports whether the feature is enabled for the tenant, as configured under "features.<name>".
+	// Features configured nowhere are disabled.
+	FeatureEnabled(ctx context.Context, feature string) bool
+	// Invalidate drops the cached overrides of the tenant, so they are loaded again on next use.
+	Invalidate(ctx context.Context, tenantID string) error
+}
+
+// TenantOverrideLoader loads the configuration overrides of a tenant, e.g. from a database.
+// Overrides are nested maps rooted like the configuration file, e.g. {"features": {"export": true}}.
+type TenantOverrideLoader interface {
+	// Load returns the overrides of the tenant, nil if it has none.
+	Load(ctx context.Context, tenantID string) (map[string]any, error)
+}
+
+// TenantOverrideLoaderFunc adapts a function to the TenantOverrideLoader interface.
+type TenantOverrideLoaderFunc func(ctx context.Context, tenantID string) (map[string]any, error)
+
+func (f TenantOverrideLoaderFunc) Load(ctx context.Context, tenantID string) (map[string]any, error) {
+	return f(ctx, tenantID)
+}
@@ -7,4 +7,8 @@ const (
 	EnvLogLevel     = EnvKeyPrefix + "_LOG_LEVEL"     // Log level (debug|info|warn|error)
 	EnvConfigPath   = EnvKeyPrefix + "_CONFIG_PATH"   // Custom config file path
 	EnvI18NLanguage = EnvKeyPrefix + "_I18N_LANGUAGE" // Override default language
+	EnvPlanUpdate   = EnvKeyPrefix + "_PLAN_UPDATE"   // Rewrite query plan baselines (true|false)
+	EnvSQLUpdate    = EnvKeyPrefix + "_SQL_UPDATE"    // Rewrite SQL snapshots (true|false)
+	EnvSeedEnv      = EnvKeyPrefix + "_SEED_ENV"      // Environment seeded by vef-cli db seed (dev|stage)
+	EnvSeedSteps    = EnvKeyPrefix + "_SEED_STEPS"    // Comma-separated seed steps to run, all when empty
 )
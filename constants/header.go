@@ -7,4 +7,5 @@ const (
 	HeaderXNonce      = "X-Nonce"
 	HeaderXSignature  = "X-Signature"
 	HeaderXMetaPrefix = "X-Meta-"
+	HeaderXCache      = "X-Cache"
 )
@@ -32,6 +32,9 @@ const (
 	ColumnUpdatedByName = "updated_by_name"
 )
 
+// ColumnTenantID is the default column holding the tenant of rows under row-per-tenant tenancy.
+const ColumnTenantID = "tenant_id"
+
 // Go struct field names corresponding to audit columns.
 const (
 	FieldID            = "ID"
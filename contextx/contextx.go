@@ -20,6 +20,7 @@ const (
 	KeyLogger
 	KeyDB
 	KeyDataPermApplier
+	KeyTenantID
 )
 
 // setValue stores a value in the context, handling both fiber.Ctx and standard context.Context.
@@ -92,3 +93,14 @@ func RequestIP(ctx context.Context) string {
 func SetRequestIP(ctx context.Context, ip string) context.Context {
 	return setValue(ctx, KeyRequestIP, ip)
 }
+
+// TenantID returns the ID of the tenant the request acts for, empty for single-tenant requests.
+func TenantID(ctx context.Context) string {
+	tenantID, _ := ctx.Value(KeyTenantID).(string)
+
+	return tenantID
+}
+
+func SetTenantID(ctx context.Context, tenantID string) context.Context {
+	return setValue(ctx, KeyTenantID, tenantID)
+}
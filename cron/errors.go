@@ -9,4 +9,10 @@ var (
 	ErrJobTaskHandlerRequired = errors.New("job task handler is required")
 	// ErrJobTaskHandlerMustFunc indicates job task handler must be a function.
 	ErrJobTaskHandlerMustFunc = errors.New("job task handler must be a function")
+	// ErrJobTaskParamsMismatch indicates the job task params do not match the handler parameters.
+	ErrJobTaskParamsMismatch = errors.New("job task params do not match the handler parameters")
+	// ErrJobNotFound indicates no job with the given id is registered.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotRunning indicates the job has no running execution to abort.
+	ErrJobNotRunning = errors.New("job is not running")
 )
@@ -0,0 +1,172 @@
+package cron
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// executionHistorySize is the number of finished executions kept per job.
+const executionHistorySize = 20
+
+// ExecutionStatus is the status of a job execution.
+type ExecutionStatus string
+
+const (
+	// ExecutionStatusRunning executions have not finished yet.
+	ExecutionStatusRunning ExecutionStatus = "running"
+	// ExecutionStatusSucceeded executions returned without error.
+	ExecutionStatusSucceeded ExecutionStatus = "succeeded"
+	// ExecutionStatusFailed executions returned an error or panicked.
+	ExecutionStatusFailed ExecutionStatus = "failed"
+	// ExecutionStatusAborted executions were aborted through Scheduler.Abort.
+	ExecutionStatusAborted ExecutionStatus = "aborted"
+)
+
+// Execution is a single run of a job.
+type Execution struct {
+	ID         string          `json:"id"`
+	JobID      string          `json:"jobId"`
+	JobName    string          `json:"jobName"`
+	Status     ExecutionStatus `json:"status"`
+	StartedAt  time.Time       `json:"startedAt"`
+	FinishedAt *time.Time      `json:"finishedAt,omitempty"`
+	Duration   int64           `json:"duration"` // Milliseconds, up to now for running executions
+	Error      string          `json:"error,omitempty"`
+}
+
+type runningExecution struct {
+	execution Execution
+	cancel    context.CancelFunc
+	aborted   bool
+}
+
+// executionTracker records the running and the recently finished executions of the jobs of a scheduler.
+type executionTracker struct {
+	mu       sync.Mutex
+	running  map[string][]*runningExecution
+	finished map[string][]Execution // Oldest first
+}
+
+func newExecutionTracker() *executionTracker {
+	return &executionTracker{
+		running:  make(map[string][]*runningExecution),
+		finished: make(map[string][]Execution),
+	}
+}
+
+// start records a new execution and returns its context, canceled when the execution is aborted,
+// together with the function recording its outcome.
+func (t *executionTracker) start(ctx context.Context, jobID, jobName string) (context.Context, func(error)) {
+	ctx, cancel := context.WithCancel(ctx)
+	run := &runningExecution{
+		execution: Execution{
+			ID:        uuid.NewString(),
+			JobID:     jobID,
+			JobName:   jobName,
+			Status:    ExecutionStatusRunning,
+			StartedAt: time.Now(),
+		},
+		cancel: cancel,
+	}
+
+	t.mu.Lock()
+	t.running[jobID] = append(t.running[jobID], run)
+	t.mu.Unlock()
+
+	return ctx, func(err error) {
+		cancel()
+		t.finish(run, err)
+	}
+}
+
+func (t *executionTracker) finish(run *runningExecution, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	execution := run.execution
+	finishedAt := time.Now()
+	execution.FinishedAt = &finishedAt
+	execution.Duration = finishedAt.Sub(execution.StartedAt).Milliseconds()
+
+	switch {
+	case run.aborted:
+		execution.Status = ExecutionStatusAborted
+	case err != nil:
+		execution.Status = ExecutionStatusFailed
+	default:
+		execution.Status = ExecutionStatusSucceeded
+	}
+
+	if err != nil {
+		execution.Error = err.Error()
+	}
+
+	jobID := execution.JobID
+	t.running[jobID] = slices.DeleteFunc(t.running[jobID], func(r *runningExecution) bool {
+		return r == run
+	})
+
+	if len(t.running[jobID]) == 0 {
+		delete(t.running, jobID)
+	}
+
+	history := append(t.finished[jobID], execution)
+	if len(history) > executionHistorySize {
+		history = slices.Clone(history[len(history)-executionHistorySize:])
+	}
+
+	t.finished[jobID] = history
+}
+
+// executions returns the running executions of the job followed by the finished ones, newest first.
+func (t *executionTracker) executions(jobID string) []Execution {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	running := t.running[jobID]
+	finished := t.finished[jobID]
+	executions := make([]Execution, 0, len(running)+len(finished))
+
+	for i := len(running) - 1; i >= 0; i-- {
+		execution := running[i].execution
+		execution.Duration = now.Sub(execution.StartedAt).Milliseconds()
+		executions = append(executions, execution)
+	}
+
+	for i := len(finished) - 1; i >= 0; i-- {
+		executions = append(executions, finished[i])
+	}
+
+	return executions
+}
+
+// abort cancels the contexts of the running executions of the job.
+func (t *executionTracker) abort(jobID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	running := t.running[jobID]
+	if len(running) == 0 {
+		return ErrJobNotRunning
+	}
+
+	for _, run := range running {
+		run.aborted = true
+		run.cancel()
+	}
+
+	return nil
+}
+
+// forget drops the history of a removed job.
+func (t *executionTracker) forget(jobID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.finished, jobID)
+}
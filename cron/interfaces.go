@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
 )
 
 // Job represents a scheduled task in the cron system.
@@ -25,6 +26,14 @@ type Job interface {
 	RunNow() error
 	// Tags returns the list of tags associated with the job for grouping and filtering.
 	Tags() []string
+	// Schedule describes when the job runs, e.g. its cron expression or interval.
+	Schedule() string
+	// Executions returns the running and the last finished executions of the job, newest first.
+	Executions() []Execution
+	// Abort cancels the context passed to the running executions of the job.
+	// Handlers not accepting a context.Context cannot be interrupted and run to completion,
+	// their executions are still recorded as aborted. Returns ErrJobNotRunning when nothing runs.
+	Abort() error
 }
 
 // JobDefinition defines how a job should be scheduled and executed.
@@ -32,7 +41,9 @@ type Job interface {
 type JobDefinition interface {
 	// build converts the high-level job definition into gocron-specific components.
 	// This is an internal method used by the scheduler implementation.
-	build() (gocron.JobDefinition, gocron.Task, []gocron.JobOption, error)
+	build(id uuid.UUID, tracker *executionTracker) (gocron.JobDefinition, gocron.Task, []gocron.JobOption, error)
+	// describe returns the human-readable schedule of the job.
+	describe() string
 }
 
 // Scheduler manages the lifecycle and execution of cron jobs.
@@ -40,6 +51,8 @@ type JobDefinition interface {
 type Scheduler interface {
 	// Jobs returns all jobs currently registered with the scheduler.
 	Jobs() []Job
+	// Job returns the job with the specified unique identifier, or ErrJobNotFound.
+	Job(id string) (Job, error)
 	// NewJob creates and registers a new job with the scheduler.
 	// The job will be scheduled according to its definition when the scheduler is running.
 	// If the task function accepts a context.Context as its first parameter,
@@ -9,7 +9,8 @@ import (
 // jobAdapter adapts gocron.Job to implement the framework's Job interface.
 // It provides a clean abstraction layer over the underlying gocron job.
 type jobAdapter struct {
-	job gocron.Job
+	job       gocron.Job
+	scheduler *schedulerAdapter
 }
 
 func (j *jobAdapter) ID() string {
@@ -39,3 +40,15 @@ func (j *jobAdapter) RunNow() error {
 func (j *jobAdapter) Tags() []string {
 	return j.job.Tags()
 }
+
+func (j *jobAdapter) Schedule() string {
+	return j.scheduler.schedule(j.ID())
+}
+
+func (j *jobAdapter) Executions() []Execution {
+	return j.scheduler.tracker.executions(j.ID())
+}
+
+func (j *jobAdapter) Abort() error {
+	return j.scheduler.tracker.abort(j.ID())
+}
@@ -1,9 +1,13 @@
 package cron
 
 import (
+	"strings"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+
+	"github.com/ilxqx/vef-framework-go/constants"
 )
 
 // applyOptions applies the given options to the job descriptor.
@@ -21,7 +25,7 @@ type OneTimeJobDefinition struct {
 	times []time.Time
 }
 
-func (d *OneTimeJobDefinition) build() (gocron.JobDefinition, gocron.Task, []gocron.JobOption, error) {
+func (d *OneTimeJobDefinition) build(id uuid.UUID, tracker *executionTracker) (gocron.JobDefinition, gocron.Task, []gocron.JobOption, error) {
 	var startAt gocron.OneTimeJobStartAtOption
 
 	switch len(d.times) {
@@ -35,7 +39,7 @@ func (d *OneTimeJobDefinition) build() (gocron.JobDefinition, gocron.Task, []goc
 
 	definition := gocron.OneTimeJob(startAt)
 
-	task, options, err := d.buildDescriptor()
+	task, options, err := d.buildDescriptor(id, tracker)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -43,6 +47,19 @@ func (d *OneTimeJobDefinition) build() (gocron.JobDefinition, gocron.Task, []goc
 	return definition, task, options, nil
 }
 
+func (d *OneTimeJobDefinition) describe() string {
+	if len(d.times) == 0 {
+		return "once immediately"
+	}
+
+	times := make([]string, len(d.times))
+	for i, t := range d.times {
+		times[i] = t.Format(time.DateTime)
+	}
+
+	return "once at " + strings.Join(times, constants.CommaSpace)
+}
+
 // DurationJobDefinition defines a job that runs repeatedly at fixed intervals.
 // The interval is specified as a time.Duration.
 type DurationJobDefinition struct {
@@ -51,10 +68,10 @@ type DurationJobDefinition struct {
 	interval time.Duration
 }
 
-func (d *DurationJobDefinition) build() (gocron.JobDefinition, gocron.Task, []gocron.JobOption, error) {
+func (d *DurationJobDefinition) build(id uuid.UUID, tracker *executionTracker) (gocron.JobDefinition, gocron.Task, []gocron.JobOption, error) {
 	definition := gocron.DurationJob(d.interval)
 
-	task, options, err := d.buildDescriptor()
+	task, options, err := d.buildDescriptor(id, tracker)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -62,6 +79,10 @@ func (d *DurationJobDefinition) build() (gocron.JobDefinition, gocron.Task, []go
 	return definition, task, options, nil
 }
 
+func (d *DurationJobDefinition) describe() string {
+	return "every " + d.interval.String()
+}
+
 // DurationRandomJobDefinition defines a job that runs at random intervals.
 // The interval is randomly chosen between MinInterval and MaxInterval for each execution.
 type DurationRandomJobDefinition struct {
@@ -71,10 +92,10 @@ type DurationRandomJobDefinition struct {
 	maxInterval time.Duration
 }
 
-func (d *DurationRandomJobDefinition) build() (gocron.JobDefinition, gocron.Task, []gocron.JobOption, error) {
+func (d *DurationRandomJobDefinition) build(id uuid.UUID, tracker *executionTracker) (gocron.JobDefinition, gocron.Task, []gocron.JobOption, error) {
 	definition := gocron.DurationRandomJob(d.minInterval, d.maxInterval)
 
-	task, options, err := d.buildDescriptor()
+	task, options, err := d.buildDescriptor(id, tracker)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -82,6 +103,10 @@ func (d *DurationRandomJobDefinition) build() (gocron.JobDefinition, gocron.Task
 	return definition, task, options, nil
 }
 
+func (d *DurationRandomJobDefinition) describe() string {
+	return "every " + d.minInterval.String() + " to " + d.maxInterval.String()
+}
+
 // CronJobDefinition defines a job using standard cron expression syntax.
 // It supports both standard 5-field and extended 6-field (with seconds) cron expressions.
 type CronJobDefinition struct {
@@ -91,10 +116,10 @@ type CronJobDefinition struct {
 	withSeconds bool
 }
 
-func (d *CronJobDefinition) build() (gocron.JobDefinition, gocron.Task, []gocron.JobOption, error) {
+func (d *CronJobDefinition) build(id uuid.UUID, tracker *executionTracker) (gocron.JobDefinition, gocron.Task, []gocron.JobOption, error) {
 	definition := gocron.CronJob(d.expression, d.withSeconds)
 
-	task, options, err := d.buildDescriptor()
+	task, options, err := d.buildDescriptor(id, tracker)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -102,6 +127,10 @@ func (d *CronJobDefinition) build() (gocron.JobDefinition, gocron.Task, []gocron
 	return definition, task, options, nil
 }
 
+func (d *CronJobDefinition) describe() string {
+	return d.expression
+}
+
 // NewOneTimeJob creates a new one-time job definition with the specified execution times.
 // If times is empty, the job will run immediately. If it contains one time, the job runs once at that time.
 // If it contains multiple times, the job will run at each specified time.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
@@ -12,6 +13,8 @@ import (
 	"github.com/ilxqx/vef-framework-go/constants"
 )
 
+var contextType = reflect.TypeFor[context.Context]()
+
 // jobInfo contains metadata and configuration options for a cron job.
 // It encapsulates all the scheduling parameters and runtime options.
 type jobInfo struct {
@@ -25,16 +28,11 @@ type jobInfo struct {
 	ctx              context.Context
 }
 
-func (i *jobInfo) buildJobOptions() ([]gocron.JobOption, error) {
+func (i *jobInfo) buildJobOptions(id uuid.UUID) ([]gocron.JobOption, error) {
 	if i.name == constants.Empty {
 		return nil, ErrJobNameRequired
 	}
 
-	id, err := uuid.NewRandom()
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate uuid: %w", err)
-	}
-
 	options := []gocron.JobOption{
 		gocron.WithIdentifier(id),
 		gocron.WithName(i.name),
@@ -76,16 +74,82 @@ type jobTask struct {
 	params  []any
 }
 
-func (t *jobTask) buildTask() (gocron.Task, error) {
+// buildTask wraps the handler so that every execution is recorded by the tracker.
+// Handlers accepting a context.Context as first parameter receive a context canceled when the execution is aborted.
+func (t *jobTask) buildTask(id uuid.UUID, name string, tracker *executionTracker) (gocron.Task, error) {
 	if t.handler == nil {
 		return nil, ErrJobTaskHandlerRequired
 	}
 
-	if reflect.ValueOf(t.handler).Kind() != reflect.Func {
+	handler := reflect.ValueOf(t.handler)
+	if handler.Kind() != reflect.Func {
 		return nil, ErrJobTaskHandlerMustFunc
 	}
 
-	return gocron.NewTask(t.handler, t.params...), nil
+	handlerType := handler.Type()
+	acceptsContext := handlerType.NumIn() > 0 && handlerType.In(0) == contextType
+	params := t.params
+
+	// An explicitly passed context replaces the job context as parent of the execution context.
+	var parentCtx context.Context
+	if acceptsContext && len(params) > 0 {
+		if ctx, ok := params[0].(context.Context); ok {
+			parentCtx = ctx
+			params = params[1:]
+		}
+	}
+
+	args := make([]reflect.Value, 0, handlerType.NumIn())
+	if acceptsContext {
+		args = append(args, reflect.Value{})
+	}
+
+	for _, param := range params {
+		args = append(args, reflect.ValueOf(param))
+	}
+
+	if len(args) != handlerType.NumIn() {
+		return nil, fmt.Errorf("%w: expected %d, got %d", ErrJobTaskParamsMismatch, handlerType.NumIn(), len(args))
+	}
+
+	for i, arg := range args {
+		if arg.IsValid() && !arg.Type().AssignableTo(handlerType.In(i)) {
+			return nil, fmt.Errorf("%w: param %d is %s, expected %s", ErrJobTaskParamsMismatch, i, arg.Type(), handlerType.In(i))
+		}
+	}
+
+	jobID := id.String()
+
+	return gocron.NewTask(func(jobCtx context.Context) (err error) {
+		if parentCtx != nil {
+			jobCtx = parentCtx
+		}
+
+		ctx, finish := tracker.start(jobCtx, jobID, name)
+
+		defer func() {
+			if r := recover(); r != nil {
+				finish(fmt.Errorf("%w: %v", gocron.ErrPanicRecovered, r))
+				// Let gocron handle the panic as for unwrapped handlers
+				panic(r)
+			}
+
+			finish(err)
+		}()
+
+		callArgs := slices.Clone(args)
+		if acceptsContext {
+			callArgs[0] = reflect.ValueOf(ctx)
+		}
+
+		for _, value := range handler.Call(callArgs) {
+			if err, ok := value.Interface().(error); ok {
+				return err
+			}
+		}
+
+		return nil
+	}), nil
 }
 
 // jobDescriptor combines job metadata and task information.
@@ -95,13 +159,13 @@ type jobDescriptor struct {
 	jobTask
 }
 
-func (d *jobDescriptor) buildDescriptor() (gocron.Task, []gocron.JobOption, error) {
-	task, err := d.buildTask()
+func (d *jobDescriptor) buildDescriptor(id uuid.UUID, tracker *executionTracker) (gocron.Task, []gocron.JobOption, error) {
+	task, err := d.buildTask(id, d.name, tracker)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to build job task: %w", err)
 	}
 
-	options, err := d.buildJobOptions()
+	options, err := d.buildJobOptions(id)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to build job options: %w", err)
 	}
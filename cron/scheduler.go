@@ -1,28 +1,48 @@
 package cron
 
 import (
+	"sync"
+
 	"github.com/go-co-op/gocron/v2"
 	"github.com/google/uuid"
 	"github.com/samber/lo"
 )
 
 // schedulerAdapter implements the Scheduler interface by adapting a gocron.Scheduler.
-// It provides a clean abstraction layer over the underlying gocron scheduler.
+// It provides a clean abstraction layer over the underlying gocron scheduler
+// and records the executions of the jobs it creates.
 type schedulerAdapter struct {
 	scheduler gocron.Scheduler
+	tracker   *executionTracker
+	schedules sync.Map // job id -> schedule description
 }
 
 func (s *schedulerAdapter) Jobs() []Job {
 	return lo.Map(
 		s.scheduler.Jobs(),
 		func(job gocron.Job, _ int) Job {
-			return &jobAdapter{job: job}
+			return s.wrap(job)
 		},
 	)
 }
 
+func (s *schedulerAdapter) Job(id string) (Job, error) {
+	for _, job := range s.scheduler.Jobs() {
+		if job.ID().String() == id {
+			return s.wrap(job), nil
+		}
+	}
+
+	return nil, ErrJobNotFound
+}
+
 func (s *schedulerAdapter) NewJob(definition JobDefinition) (Job, error) {
-	def, task, options, err := definition.build()
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	def, task, options, err := definition.build(id, s.tracker)
 	if err != nil {
 		return nil, err
 	}
@@ -32,10 +52,18 @@ func (s *schedulerAdapter) NewJob(definition JobDefinition) (Job, error) {
 		return nil, err
 	}
 
-	return &jobAdapter{job: job}, nil
+	s.schedules.Store(job.ID().String(), definition.describe())
+
+	return s.wrap(job), nil
 }
 
 func (s *schedulerAdapter) RemoveByTags(tags ...string) {
+	for _, job := range s.scheduler.Jobs() {
+		if lo.Some(job.Tags(), tags) {
+			s.forget(job.ID().String())
+		}
+	}
+
 	s.scheduler.RemoveByTags(tags...)
 }
 
@@ -45,7 +73,13 @@ func (s *schedulerAdapter) RemoveJob(id string) error {
 		return err
 	}
 
-	return s.scheduler.RemoveJob(uuid)
+	if err := s.scheduler.RemoveJob(uuid); err != nil {
+		return err
+	}
+
+	s.forget(id)
+
+	return nil
 }
 
 func (s *schedulerAdapter) Start() {
@@ -62,7 +96,7 @@ func (s *schedulerAdapter) Update(id string, definition JobDefinition) (Job, err
 		return nil, err
 	}
 
-	def, task, options, err := definition.build()
+	def, task, options, err := definition.build(uuid, s.tracker)
 	if err != nil {
 		return nil, err
 	}
@@ -72,17 +106,37 @@ func (s *schedulerAdapter) Update(id string, definition JobDefinition) (Job, err
 		return nil, err
 	}
 
-	return &jobAdapter{job: job}, nil
+	s.schedules.Store(job.ID().String(), definition.describe())
+
+	return s.wrap(job), nil
 }
 
 func (s *schedulerAdapter) JobsWaitingInQueue() int {
 	return s.scheduler.JobsWaitingInQueue()
 }
 
+func (s *schedulerAdapter) wrap(job gocron.Job) Job {
+	return &jobAdapter{job: job, scheduler: s}
+}
+
+func (s *schedulerAdapter) schedule(id string) string {
+	if schedule, ok := s.schedules.Load(id); ok {
+		return schedule.(string)
+	}
+
+	return ""
+}
+
+func (s *schedulerAdapter) forget(id string) {
+	s.schedules.Delete(id)
+	s.tracker.forget(id)
+}
+
 // NewScheduler creates a new Scheduler implementation wrapping the provided gocron.Scheduler.
 // This is the main entry point for creating scheduler instances in the application.
 func NewScheduler(scheduler gocron.Scheduler) Scheduler {
 	return &schedulerAdapter{
 		scheduler: scheduler,
+		tracker:   newExecutionTracker(),
 	}
 }
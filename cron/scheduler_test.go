@@ -2,6 +2,7 @@ package cron
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -332,6 +333,7 @@ func TestScheduler_UpdateJob(t *testing.T) {
 	updatedJob, err := scheduler.Update(originalID, jobDef2)
 	require.NoError(t, err, "Should update job")
 	assert.Equal(t, "updated-job", updatedJob.Name(), "Updated job name should match")
+	assert.Equal(t, originalID, updatedJob.ID(), "Updated job should keep its identifier")
 
 	scheduler.Start()
 	time.Sleep(100 * time.Millisecond)
@@ -499,3 +501,178 @@ func TestJob_NextRuns(t *testing.T) {
 		assert.InDelta(t, time.Minute, diff, float64(time.Second), "Next runs should be ~1 minute apart")
 	}
 }
+
+// TestScheduler_Job tests looking up a job by id.
+func TestScheduler_Job(t *testing.T) {
+	gocronScheduler, err := createTestScheduler()
+	require.NoError(t, err, "Should create gocron scheduler")
+
+	defer func() {
+		if err := gocronScheduler.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown gocron scheduler: %v", err)
+		}
+	}()
+
+	scheduler := NewScheduler(gocronScheduler)
+
+	created, err := scheduler.NewJob(NewCronJob("0 * * * *", false,
+		WithName("test-lookup"),
+		WithTask(func() {}),
+	))
+	require.NoError(t, err, "Should create job")
+
+	job, err := scheduler.Job(created.ID())
+	require.NoError(t, err, "Should find the job")
+	assert.Equal(t, "test-lookup", job.Name())
+	assert.Equal(t, "0 * * * *", job.Schedule(), "Should describe the cron expression")
+
+	_, err = scheduler.Job("00000000-0000-0000-0000-000000000000")
+	assert.ErrorIs(t, err, ErrJobNotFound, "Should report unknown jobs")
+}
+
+// TestJob_Schedule tests the schedule descriptions of the job definitions.
+func TestJob_Schedule(t *testing.T) {
+	runAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.Local)
+
+	tests := []struct {
+		name       string
+		definition JobDefinition
+		expected   string
+	}{
+		{"OneTimeImmediately", NewOneTimeJob(nil), "once immediately"},
+		{"OneTimeAt", NewOneTimeJob([]time.Time{runAt}), "once at 2030-01-02 03:04:05"},
+		{"Duration", NewDurationJob(5 * time.Minute), "every 5m0s"},
+		{"DurationRandom", NewDurationRandomJob(time.Minute, 2*time.Minute), "every 1m0s to 2m0s"},
+		{"Cron", NewCronJob("*/5 * * * * *", true), "*/5 * * * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.definition.describe())
+		})
+	}
+}
+
+// TestJob_Executions tests recording succeeded and failed executions.
+func TestJob_Executions(t *testing.T) {
+	gocronScheduler, err := createTestScheduler()
+	require.NoError(t, err, "Should create gocron scheduler")
+
+	defer func() {
+		if err := gocronScheduler.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown gocron scheduler: %v", err)
+		}
+	}()
+
+	scheduler := NewScheduler(gocronScheduler)
+
+	var runs int32
+
+	job, err := scheduler.NewJob(NewOneTimeJob([]time.Time{time.Now().Add(time.Hour)},
+		WithName("test-executions"),
+		WithTask(func(_ context.Context, failOn int32) error {
+			if atomic.AddInt32(&runs, 1) == failOn {
+				return errors.New("boom")
+			}
+
+			return nil
+		}, int32(2)),
+	))
+	require.NoError(t, err, "Should create job")
+
+	scheduler.Start()
+
+	require.NoError(t, job.RunNow())
+	assert.Eventually(t, func() bool { return len(job.Executions()) == 1 }, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, job.RunNow())
+	assert.Eventually(t, func() bool {
+		executions := job.Executions()
+
+		return len(executions) == 2 && executions[0].Status != ExecutionStatusRunning
+	}, time.Second, 10*time.Millisecond)
+
+	executions := job.Executions()
+	assert.Equal(t, ExecutionStatusFailed, executions[0].Status, "Newest execution should come first")
+	assert.Equal(t, "boom", executions[0].Error)
+	assert.NotNil(t, executions[0].FinishedAt)
+	assert.Equal(t, ExecutionStatusSucceeded, executions[1].Status)
+	assert.Equal(t, job.ID(), executions[1].JobID)
+	assert.Equal(t, "test-executions", executions[1].JobName)
+}
+
+// TestJob_Abort tests aborting a running execution.
+func TestJob_Abort(t *testing.T) {
+	gocronScheduler, err := createTestScheduler()
+	require.NoError(t, err, "Should create gocron scheduler")
+
+	defer func() {
+		if err := gocronScheduler.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown gocron scheduler: %v", err)
+		}
+	}()
+
+	scheduler := NewScheduler(gocronScheduler)
+
+	started := make(chan struct{})
+
+	job, err := scheduler.NewJob(NewOneTimeJob([]time.Time{time.Now().Add(time.Hour)},
+		WithName("test-abort"),
+		WithTask(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+
+			return ctx.Err()
+		}),
+	))
+	require.NoError(t, err, "Should create job")
+
+	assert.ErrorIs(t, job.Abort(), ErrJobNotRunning, "Should report that nothing runs")
+
+	scheduler.Start()
+	require.NoError(t, job.RunNow())
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		require.Fail(t, "Job should start")
+	}
+
+	executions := job.Executions()
+	require.Len(t, executions, 1)
+	assert.Equal(t, ExecutionStatusRunning, executions[0].Status)
+
+	require.NoError(t, job.Abort(), "Should abort the running execution")
+
+	assert.Eventually(t, func() bool {
+		executions := job.Executions()
+
+		return len(executions) == 1 && executions[0].Status == ExecutionStatusAborted
+	}, time.Second, 10*time.Millisecond, "Execution should be recorded as aborted")
+}
+
+// TestScheduler_NewJob_ParamsMismatch tests rejecting task params not matching the handler.
+func TestScheduler_NewJob_ParamsMismatch(t *testing.T) {
+	gocronScheduler, err := createTestScheduler()
+	require.NoError(t, err, "Should create gocron scheduler")
+
+	defer func() {
+		if err := gocronScheduler.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown gocron scheduler: %v", err)
+		}
+	}()
+
+	scheduler := NewScheduler(gocronScheduler)
+
+	_, err = scheduler.NewJob(NewOneTimeJob(nil,
+		WithName("test-mismatch"),
+		WithTask(func(string) {}, 1),
+	))
+	assert.ErrorIs(t, err, ErrJobTaskParamsMismatch, "Should reject params of the wrong type")
+
+	_, err = scheduler.NewJob(NewOneTimeJob(nil,
+		WithName("test-mismatch"),
+		WithTask(func(string, int) {}, "a"),
+	))
+	assert.ErrorIs(t, err, ErrJobTaskParamsMismatch, "Should reject missing params")
+}
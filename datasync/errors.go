@@ -0,0 +1,10 @@
+package datasync
+
+import "errors"
+
+var (
+	// ErrUnknownModel is returned for changes of models that are not synchronized.
+	ErrUnknownModel = errors.New("model is not synchronized")
+	// ErrServerError is returned when the server responds to a pull or push with an error result.
+	ErrServerError = errors.New("sync server returned an error")
+)
@@ -0,0 +1,37 @@
+package datasync
+
+import (
+	"context"
+
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Service records the changes of synchronized models and exchanges them with clients.
+type Service interface {
+	// Models returns the names of the synchronized models, sorted.
+	Models() []string
+	// Record logs changes of synchronized rows, which are pointers to or values of declared models.
+	// It is called with the transaction writing the rows, so that the log stays consistent with the tables.
+	Record(ctx context.Context, db orm.DB, operation Operation, rows ...any) error
+	// Pull returns the changes following the cursor, leaving out the changes pushed by the client itself and the upserts
+	// of rows the request cannot select under the tenancy and data scopes of the DB. Deletes only hold the primary key.
+	Pull(ctx context.Context, req PullRequest) (*PullResult, error)
+	// Push applies the changes of a client, resolving conflicts with the policies of their models. The record ID of each
+	// change must be the primary key of its row.
+	Push(ctx context.Context, req PushRequest) (*PushResult, error)
+}
+
+// Client synchronizes the embedded database of a client app with its server.
+type Client interface {
+	// Sync pushes the local changes and then pulls the server changes, returning a summary.
+	Sync(ctx context.Context) (*SyncResult, error)
+	// State returns the synchronization progress of the client.
+	State(ctx context.Context) (*State, error)
+}
+
+// Transport carries the requests of a client to the pull and push endpoints of its server.
+// NewHTTPTransport calls the sys/sync Api resource, applications provide their own e.g. for custom authentication.
+type Transport interface {
+	Pull(ctx context.Context, req PullRequest) (*PullResult, error)
+	Push(ctx context.Context, req PushRequest) (*PushResult, error)
+}
@@ -0,0 +1,111 @@
+package datasync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+// Resource is the Api resource serving the pull and push endpoints.
+const Resource = "sys/sync"
+
+// httpTransport calls the RPC endpoint of the server.
+type httpTransport struct {
+	endpoint  string
+	authorize func(ctx context.Context, req *http.Request) error
+	client    *http.Client
+}
+
+// NewHTTPTransport creates a Transport posting to the RPC endpoint of the server, e.g. "https://example.com/api".
+// authorize adds the credentials to the requests, e.g. a bearer token, it may be nil.
+func NewHTTPTransport(endpoint string, authorize func(ctx context.Context, req *http.Request) error) Transport {
+	return &httpTransport{
+		endpoint:  endpoint,
+		authorize: authorize,
+		client:    &http.Client{Timeout: time.Minute},
+	}
+}
+
+// BearerToken authorizes requests with a static bearer token, e.g. a long-lived token of the device.
+func BearerToken(token string) func(ctx context.Context, req *http.Request) error {
+	return func(_ context.Context, req *http.Request) error {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+
+		return nil
+	}
+}
+
+func (t *httpTransport) Pull(ctx context.Context, req PullRequest) (*PullResult, error) {
+	var pulled PullResult
+
+	return &pulled, t.call(ctx, "pull", req, &pulled)
+}
+
+func (t *httpTransport) Push(ctx context.Context, req PushRequest) (*PushResult, error) {
+	var pushed PushResult
+
+	return &pushed, t.call(ctx, "push", req, &pushed)
+}
+
+func (t *httpTransport) call(ctx context.Context, action string, params, data any) error {
+	body, err := json.Marshal(struct {
+		api.Identifier
+
+		Params any `json:"params"`
+	}{
+		Identifier: api.Identifier{Resource: Resource, Action: action, Version: api.VersionV1},
+		Params:     params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	if t.authorize != nil {
+		if err := t.authorize(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to %s changes: %w", action, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to %s changes: %w", action, err)
+	}
+
+	var res struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(content, &res); err != nil {
+		return fmt.Errorf("failed to %s changes: unexpected response with status %d: %w", action, resp.StatusCode, err)
+	}
+
+	if res.Code != result.OkCode {
+		return fmt.Errorf("%w: %s: %d %s", ErrServerError, action, res.Code, res.Message)
+	}
+
+	return json.Unmarshal(res.Data, data)
+}
@@ -0,0 +1,129 @@
+package datasync
+
+import (
+	"context"
+
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Operation is the kind of a change of a synchronized row.
+type Operation string
+
+const (
+	// OperationUpsert inserts the row or replaces it with the recorded values.
+	OperationUpsert Operation = "upsert"
+	// OperationDelete deletes the row.
+	OperationDelete Operation = "delete"
+)
+
+// Policy decides between a change pushed by a client and a change the client had not pulled yet of the same row.
+type Policy string
+
+const (
+	// PolicyServerWins rejects the client change, the client takes the server row with its next pull.
+	PolicyServerWins Policy = "server_wins"
+	// PolicyClientWins applies the client change over the server change.
+	PolicyClientWins Policy = "client_wins"
+	// PolicyLastWriteWins keeps the change made last by ChangedAt, the server change on ties.
+	PolicyLastWriteWins Policy = "last_write_wins"
+)
+
+// Status is the outcome of a pushed change.
+type Status string
+
+const (
+	// StatusApplied changes were applied on the server.
+	StatusApplied Status = "applied"
+	// StatusMerged changes conflicted and were replaced by the change returned by the resolver.
+	StatusMerged Status = "merged"
+	// StatusRejected changes conflicted and lost against the server change.
+	StatusRejected Status = "rejected"
+)
+
+// Model declares a model synchronized between the server and embedded clients.
+// Both sides declare the same models, the server with its PostgreSQL tables and the clients with their SQLite tables.
+type Model struct {
+	Name     string   // Identifies the model in the protocol, defaults to the table name
+	Model    any      // Pointer to the model struct, e.g. (*Order)(nil), which needs a primary key
+	Policy   Policy   // Resolution of conflicting pushes, defaults to PolicyServerWins
+	Resolver Resolver // Custom resolution of conflicting pushes, takes precedence over Policy
+}
+
+// Conflict is a change pushed by a client for a row changed on the server since the client last pulled.
+type Conflict struct {
+	Model  string // Name of the synchronized model
+	Client Change // Change pushed by the client
+	Server Change // Latest change of the row not pulled by the client
+}
+
+// Resolver resolves a conflict on the server, returning the change to apply instead of both changes
+// or nil to keep the server row. The returned change is sent to all clients, including the pushing one.
+type Resolver func(ctx context.Context, conflict Conflict) (*Change, error)
+
+// Change is a change of a synchronized row, as recorded in the change log and exchanged between server and clients.
+// Changes are recorded in the same transaction as the row, see Service.Record.
+type Change struct {
+	orm.BaseModel `bun:"table:sys_sync_change,alias:ssc"`
+
+	Seq       int64             `json:"seq"       bun:",pk,autoincrement"` // Position in the change log of the database
+	Model     string            `json:"model"     bun:",notnull"`
+	RecordID  string            `json:"recordId"  bun:",notnull"`                // Primary key of the row, keys of composite primary keys joined by commas
+	Operation Operation         `json:"operation" bun:",notnull"`                // Kind of the change
+	Data      string            `json:"data"      bun:",notnull"`                // JSON-encoded row
+	ClientID  string            `json:"clientId"  bun:",notnull"`                // Client the change was pushed by, empty for changes made on the database itself
+	ChangedAt datetime.DateTime `json:"changedAt" bun:",notnull,type:timestamp"` // Time the change was made, on the client for pushed changes
+	LoggedAt  datetime.DateTime `json:"-"         bun:",notnull,type:timestamp"` // Time the change was logged in the database
+}
+
+// State is the progress of a client synchronizing with its server, kept in the client database.
+type State struct {
+	orm.BaseModel `bun:"table:sys_sync_state,alias:sss"`
+
+	ClientID  string        `json:"clientId"  bun:",pk"`
+	PulledSeq int64         `json:"pulledSeq" bun:",notnull"` // Seq of the last server change pulled
+	PushedSeq int64         `json:"pushedSeq" bun:",notnull"` // Seq of the last local change pushed
+	SyncedAt  null.DateTime `json:"syncedAt"  bun:",type:timestamp"`
+}
+
+// PullRequest requests the server changes following a cursor.
+type PullRequest struct {
+	ClientID string   `json:"clientId"`
+	Cursor   int64    `json:"cursor"` // Seq of the last server change pulled, 0 for the first pull
+	Models   []string `json:"models"` // Names of the pulled models, all synchronized models when empty
+	Limit    int      `json:"limit"`  // Maximum number of changes, bounded by the server
+}
+
+// PullResult contains server changes ordered by Seq.
+type PullResult struct {
+	Changes []Change `json:"changes"`
+	Cursor  int64    `json:"cursor"`  // Cursor of the next pull
+	HasMore bool     `json:"hasMore"` // Whether more changes are available right away
+}
+
+// PushRequest pushes the changes made by a client since its last push.
+type PushRequest struct {
+	ClientID string   `json:"clientId"`
+	Cursor   int64    `json:"cursor"` // Seq of the last server change pulled, server changes after it conflict
+	Changes  []Change `json:"changes"`
+}
+
+// PushResult contains the outcome of every pushed change, in the order of the request.
+type PushResult struct {
+	Outcomes []Outcome `json:"outcomes"`
+}
+
+// Outcome is the outcome of a pushed change.
+type Outcome struct {
+	Model    string `json:"model"`
+	RecordID string `json:"recordId"`
+	Status   Status `json:"status"`
+}
+
+// SyncResult summarizes a synchronization of a client.
+type SyncResult struct {
+	Pushed   int `json:"pushed"`   // Number of local changes pushed
+	Rejected int `json:"rejected"` // Number of pushed changes rejected or merged by the server
+	Pulled   int `json:"pulled"`   // Number of server changes applied
+}
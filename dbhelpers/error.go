@@ -1,6 +1,7 @@
 package dbhelpers
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -12,6 +13,7 @@ import (
 const (
 	pgUniqueViolation     = "23505"
 	pgForeignKeyViolation = "23503"
+	pgQueryCanceled       = "57014"
 )
 
 // MySQL error numbers.
@@ -20,6 +22,7 @@ const (
 	mysqlDupUnique       = 1169
 	mysqlRowIsReferenced = 1451
 	mysqlNoReferencedRow = 1452
+	mysqlQueryTimeout    = 3024
 )
 
 // containsAny checks if the message contains any of the given substrings.
@@ -121,3 +124,38 @@ func IsForeignKeyError(err error) bool {
 
 	return hasFKPattern || hasOracleIntegrityPattern
 }
+
+// IsStatementTimeoutError checks if the error is a statement cancelled for running longer than its timeout,
+// by the statement timeout of the database or by the deadline of its context.
+func IsStatementTimeoutError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	// PostgreSQL: code 57014 (query_canceled)
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) {
+		return pgErr.Field('C') == pgQueryCanceled
+	}
+
+	// MySQL: 3024 (ER_QUERY_TIMEOUT)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlQueryTimeout
+	}
+
+	return containsAny(strings.ToLower(err.Error()),
+		// PostgreSQL
+		"statement timeout",
+		// MySQL
+		"maximum statement execution time exceeded",
+		// SQL Server
+		"query timeout expired",
+		// Oracle (ORA-01013)
+		"ora-01013",
+	)
+}
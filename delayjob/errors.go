@@ -0,0 +1,6 @@
+package delayjob
+
+import "errors"
+
+// ErrJobNotPending is returned when canceling a job that is not pending.
+var ErrJobNotPending = errors.New("delayed job is not pending")
@@ -0,0 +1,20 @@
+package delayjob
+
+import (
+	"context"
+	"time"
+)
+
+// Scheduler persists jobs to run at a given time, e.g. reminders or timeout driven state transitions.
+// Due jobs are claimed by exactly one application instance and retried with backoff on failure.
+type Scheduler interface {
+	// Schedule persists a job of jobType running at runAt and returns its id.
+	// The payload is encoded as JSON and decoded by the handler with Job.Bind.
+	Schedule(ctx context.Context, jobType string, payload any, runAt time.Time, opts ...ScheduleOption) (string, error)
+	// ScheduleAfter persists a job of jobType running after delay and returns its id.
+	ScheduleAfter(ctx context.Context, jobType string, payload any, delay time.Duration, opts ...ScheduleOption) (string, error)
+	// Cancel cancels the pending job with the id. Returns ErrJobNotPending if it already ran or is running.
+	Cancel(ctx context.Context, id string) error
+	// CancelByKey cancels the pending job scheduled with the key, if any.
+	CancelByKey(ctx context.Context, key string) error
+}
@@ -0,0 +1,58 @@
+package delayjob
+
+import "time"
+
+const (
+	defaultMaxAttempts = 3
+	defaultRetryDelay  = time.Minute
+)
+
+// ScheduleOptions holds the options of a scheduled job.
+type ScheduleOptions struct {
+	Key         string
+	MaxAttempts int
+	RetryDelay  time.Duration
+}
+
+// ScheduleOption configures a scheduled job.
+type ScheduleOption func(*ScheduleOptions)
+
+// WithKey identifies the job by a business key, e.g. "order:42:timeout".
+// Scheduling again with the same key replaces the pending job instead of adding another one.
+func WithKey(key string) ScheduleOption {
+	return func(o *ScheduleOptions) {
+		o.Key = key
+	}
+}
+
+// WithMaxAttempts sets how often the job is attempted before it fails. Defaults to 3.
+func WithMaxAttempts(attempts int) ScheduleOption {
+	return func(o *ScheduleOptions) {
+		o.MaxAttempts = attempts
+	}
+}
+
+// WithRetryDelay sets the delay before the first retry, doubled for every further retry. Defaults to 1 minute.
+func WithRetryDelay(delay time.Duration) ScheduleOption {
+	return func(o *ScheduleOptions) {
+		o.RetryDelay = delay
+	}
+}
+
+// NewScheduleOptions applies the options to the defaults.
+func NewScheduleOptions(opts ...ScheduleOption) *ScheduleOptions {
+	options := &ScheduleOptions{
+		MaxAttempts: defaultMaxAttempts,
+		RetryDelay:  defaultRetryDelay,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+
+	return options
+}
@@ -0,0 +1,81 @@
+package delayjob
+
+import (
+	"context"
+
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/encoding"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Status is the execution status of a delayed job.
+type Status string
+
+const (
+	// StatusPending jobs wait for their run time, or for their next attempt after a failure.
+	StatusPending Status = "pending"
+	// StatusRunning jobs are claimed by an instance and executing.
+	StatusRunning Status = "running"
+	// StatusSucceeded jobs completed successfully.
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed jobs failed in their last allowed attempt.
+	StatusFailed Status = "failed"
+	// StatusCanceled jobs were canceled before they ran.
+	StatusCanceled Status = "canceled"
+)
+
+// Job is a job persisted to run at a given time.
+type Job struct {
+	orm.BaseModel `bun:"table:sys_delayed_job,alias:sdj"`
+	orm.Model
+
+	Type        string            `json:"type"        bun:",notnull"`
+	Key         null.String       `json:"key"`     // Identifies the pending job to replace when scheduling again
+	Payload     string            `json:"payload"` // JSON-encoded payload
+	RunAt       datetime.DateTime `json:"runAt"       bun:",notnull,type:timestamp"`
+	Status      Status            `json:"status"      bun:",notnull"`
+	Attempts    int               `json:"attempts"    bun:",notnull"`
+	MaxAttempts int               `json:"maxAttempts" bun:",notnull"`
+	RetryDelay  int               `json:"retryDelay"  bun:",notnull"` // Seconds before the first retry, doubled for every further retry
+	LastError   null.String       `json:"lastError"`
+	LockedUntil null.DateTime     `json:"lockedUntil" bun:",type:timestamp"`
+	FinishedAt  null.DateTime     `json:"finishedAt"  bun:",type:timestamp"`
+	// PendingKey holds the key while the job is pending, its unique index keeps a key to one pending job.
+	PendingKey null.String `json:"-" bun:",unique:uk_sys_delayed_job_pending_key"`
+}
+
+// Bind decodes the payload into target.
+func (j *Job) Bind(target any) error {
+	return encoding.DecodeJSON(j.Payload, target)
+}
+
+// Handler executes the delayed jobs of one type.
+// A returned error fails the attempt; the job is retried until its attempts are exhausted.
+type Handler interface {
+	// Type returns the job type handled.
+	Type() string
+	// Handle executes the job. The context is canceled when the lease of the job expires.
+	Handle(ctx context.Context, job *Job) error
+}
+
+type handlerFunc struct {
+	jobType string
+	handle  func(ctx context.Context, job *Job) error
+}
+
+func (h *handlerFunc) Type() string {
+	return h.jobType
+}
+
+func (h *handlerFunc) Handle(ctx context.Context, job *Job) error {
+	return h.handle(ctx, job)
+}
+
+// NewHandler creates a Handler executing the jobs of jobType with the given function.
+func NewHandler(jobType string, handle func(ctx context.Context, job *Job) error) Handler {
+	return &handlerFunc{
+		jobType: jobType,
+		handle:  handle,
+	}
+}
@@ -5,9 +5,13 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/delayjob"
 	"github.com/ilxqx/vef-framework-go/internal/app"
 	"github.com/ilxqx/vef-framework-go/mcp"
 	"github.com/ilxqx/vef-framework-go/middleware"
+	"github.com/ilxqx/vef-framework-go/monitor"
+	"github.com/ilxqx/vef-framework-go/report"
+	"github.com/ilxqx/vef-framework-go/storage"
 )
 
 var (
@@ -133,6 +137,121 @@ func SupplySPAConfigs(config *middleware.SPAConfig, configs ...*middleware.SPACo
 	return fx.Supply(spaConfigs...)
 }
 
+// ProvideHealthContributor provides a health contributor to the dependency injection container.
+// The contributor will be registered in the "vef:monitor:health_contributors" group and reported by the health check.
+func ProvideHealthContributor(constructor any, paramTags ...string) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(monitor.HealthContributor)),
+			fx.ParamTags(paramTags...),
+			fx.ResultTags(`group:"vef:monitor:health_contributors"`),
+		),
+	)
+}
+
+// ProvideStorageProcessor provides an upload post-processor to the dependency injection container.
+// The processor will be registered in the "vef:storage:processors" group and runs after the built-in processors.
+func ProvideStorageProcessor(constructor any, paramTags ...string) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(storage.Processor)),
+			fx.ParamTags(paramTags...),
+			fx.ResultTags(`group:"vef:storage:processors"`),
+		),
+	)
+}
+
+// ProvideStorageWatermarker provides a document watermarker to the dependency injection container.
+// The watermarker will be registered in the "vef:storage:watermarkers" group and used by signed URLs requesting a watermark.
+func ProvideStorageWatermarker(constructor any, paramTags ...string) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(storage.Watermarker)),
+			fx.ParamTags(paramTags...),
+			fx.ResultTags(`group:"vef:storage:watermarkers"`),
+		),
+	)
+}
+
+// ProvideDelayJobHandler provides a delayed job handler to the dependency injection container.
+// The handler will be registered in the "vef:delayjob:handlers" group and runs the due jobs of its type.
+func ProvideDelayJobHandler(constructor any, paramTags ...string) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(delayjob.Handler)),
+			fx.ParamTags(paramTags...),
+			fx.ResultTags(`group:"vef:delayjob:handlers"`),
+		),
+	)
+}
+
+// ProvideReport provides a scheduled report to the dependency injection container.
+// The report will be registered in the "vef:report:reports" group and generated on its cron schedule.
+func ProvideReport(constructor any, paramTags ...string) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(report.Report)),
+			fx.ParamTags(paramTags...),
+			fx.ResultTags(`group:"vef:report:reports"`),
+		),
+	)
+}
+
+// ProvideReportRenderer provides a report renderer to the dependency injection container.
+// The renderer will be registered in the "vef:report:renderers" group and replaces the built-in renderer of its format.
+func ProvideReportRenderer(constructor any, paramTags ...string) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(report.Renderer)),
+			fx.ParamTags(paramTags...),
+			fx.ResultTags(`group:"vef:report:renderers"`),
+		),
+	)
+}
+
+// ProvideReportDeliverer provides a report deliverer to the dependency injection container.
+// The deliverer will be registered in the "vef:report:deliverers" group and receives the links of every generated report.
+func ProvideReportDeliverer(constructor any, paramTags ...string) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(report.Deliverer)),
+			fx.ParamTags(paramTags...),
+			fx.ResultTags(`group:"vef:report:deliverers"`),
+		),
+	)
+}
+
+// ProvideIntegrityReference provides a logical foreign key to the dependency injection container.
+// The reference will be registered in the "vef:integrity:references" group and checked for orphaned rows.
+func ProvideIntegrityReference(constructor any, paramTags ...string) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.ParamTags(paramTags...),
+			fx.ResultTags(`group:"vef:integrity:references"`),
+		),
+	)
+}
+
+// ProvideSyncModel provides a synchronized model to the dependency injection container.
+// The model will be registered in the "vef:datasync:models" group and exchanged between the server and embedded clients.
+func ProvideSyncModel(constructor any, paramTags ...string) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.ParamTags(paramTags...),
+			fx.ResultTags(`group:"vef:datasync:models"`),
+		),
+	)
+}
+
 // ProvideMcpTools provides an MCP tool provider.
 func ProvideMcpTools(constructor any, paramTags ...string) fx.Option {
 	return fx.Provide(
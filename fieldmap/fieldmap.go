@@ -0,0 +1,201 @@
+// Package fieldmap maps the field names used by Api payloads to the columns of the underlying models.
+//
+// The mapping of a model is derived from its struct tags: the Api name of a field is its json name
+// (the Go field name when untagged) and its column is its bun name (the snake_case Go field name when
+// untagged). Fields hidden from JSON or from the database, relations and embedded models are handled
+// the same way encoding/json and bun handle them. Explicit overrides can be registered per model.
+package fieldmap
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/reflectx"
+)
+
+const (
+	tagJSON = "json"
+	tagBun  = "bun"
+)
+
+// Mapping maps the Api field names of a model to its columns.
+type Mapping struct {
+	columns map[string]string // Api field -> column
+	fields  map[string]string // Column -> Api field
+}
+
+// Column returns the column of an Api field.
+func (m *Mapping) Column(field string) (string, bool) {
+	if m == nil {
+		return constants.Empty, false
+	}
+
+	column, ok := m.columns[field]
+
+	return column, ok
+}
+
+// Field returns the Api field of a column.
+func (m *Mapping) Field(column string) (string, bool) {
+	if m == nil {
+		return constants.Empty, false
+	}
+
+	field, ok := m.fields[column]
+
+	return field, ok
+}
+
+// Columns resolves Api fields to columns, e.g. for the fields of filter.NewSchemaFor. It reports the fields not mapped.
+func (m *Mapping) Columns(fields ...string) (columns, unknown []string) {
+	for _, field := range fields {
+		if column, ok := m.Column(field); ok {
+			columns = append(columns, column)
+		} else {
+			unknown = append(unknown, field)
+		}
+	}
+
+	return columns, unknown
+}
+
+// Fields returns the sorted Api fields of the mapping.
+func (m *Mapping) Fields() []string {
+	if m == nil {
+		return nil
+	}
+
+	return slices.Sorted(maps.Keys(m.columns))
+}
+
+var (
+	mu        sync.RWMutex
+	mappings  = make(map[reflect.Type]*Mapping)
+	overrides = make(map[reflect.Type]map[string]string)
+)
+
+// For returns the mapping of model type T.
+func For[T any]() *Mapping {
+	return Of(reflect.TypeFor[T]())
+}
+
+// Of returns the mapping of a model type, nil when it is not a struct type.
+// Mappings are derived once and cached.
+func Of(typ reflect.Type) *Mapping {
+	typ = reflectx.Indirect(typ)
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	mu.RLock()
+	mapping, ok := mappings[typ]
+	mu.RUnlock()
+
+	if ok {
+		return mapping
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if mapping, ok = mappings[typ]; ok {
+		return mapping
+	}
+
+	mapping = build(typ, overrides[typ])
+	mappings[typ] = mapping
+
+	return mapping
+}
+
+// Register overrides the columns of Api fields of model type T, e.g. for fields computed from
+// a column with a different name. Overrides are merged into the derived mapping.
+func Register[T any](columns map[string]string) {
+	typ := reflectx.Indirect(reflect.TypeFor[T]())
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	merged := maps.Clone(overrides[typ])
+	if merged == nil {
+		merged = make(map[string]string, len(columns))
+	}
+
+	maps.Copy(merged, columns)
+	overrides[typ] = merged
+	delete(mappings, typ)
+}
+
+func build(typ reflect.Type, overrides map[string]string) *Mapping {
+	mapping := &Mapping{
+		columns: make(map[string]string),
+		fields:  make(map[string]string),
+	}
+
+	collect(typ, mapping)
+
+	for field, column := range overrides {
+		if previous, ok := mapping.columns[field]; ok {
+			delete(mapping.fields, previous)
+		}
+
+		mapping.columns[field] = column
+		mapping.fields[column] = field
+	}
+
+	return mapping
+}
+
+// collect adds the fields of typ to the mapping. Embedded structs are collected after the own
+// fields so that own fields shadow embedded ones, as in encoding/json.
+func collect(typ reflect.Type, mapping *Mapping) {
+	var embeddeds []reflect.Type
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		bunTag := field.Tag.Get(tagBun)
+		bunName, _, _ := strings.Cut(bunTag, constants.Comma)
+
+		if bunName == constants.Hyphen || strings.Contains(bunTag, "table:") || strings.Contains(bunTag, "embed:") ||
+			strings.Contains(bunTag, "rel:") || strings.Contains(bunTag, "m2m:") || strings.Contains(bunTag, "scanonly") {
+			continue
+		}
+
+		if field.Anonymous && bunName == constants.Empty {
+			if embedded := reflectx.Indirect(field.Type); embedded.Kind() == reflect.Struct {
+				embeddeds = append(embeddeds, embedded)
+
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		jsonName, _, _ := strings.Cut(field.Tag.Get(tagJSON), constants.Comma)
+		if jsonName == constants.Hyphen {
+			continue
+		}
+
+		apiField := lo.Ternary(jsonName == constants.Empty, field.Name, jsonName)
+		column := lo.Ternary(bunName == constants.Empty, lo.SnakeCase(field.Name), bunName)
+
+		if _, ok := mapping.columns[apiField]; ok {
+			continue
+		}
+
+		mapping.columns[apiField] = column
+		mapping.fields[column] = apiField
+	}
+
+	for _, embedded := range embeddeds {
+		collect(embedded, mapping)
+	}
+}
@@ -0,0 +1,110 @@
+package fieldmap
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+type department struct {
+	bun.BaseModel `bun:"table:test_department"`
+	orm.Model
+
+	Name string `json:"name"`
+}
+
+type employee struct {
+	bun.BaseModel `bun:"table:test_employee,alias:te"`
+	orm.Model
+
+	Name       string `json:"userName"     bun:"name,notnull"`
+	DeptID     string `json:"deptId"`
+	Salary     int    `json:"-"`
+	Nickname   string `bun:"-"`
+	Remark     string
+	DeptName   string      `json:"deptName"     bun:",scanonly"`
+	Department *department `json:"department"   bun:"rel:belongs-to,join:dept_id=id"`
+	HiredAt    string      `json:"hiredTime"    bun:"hired_time"`
+}
+
+func TestFor(t *testing.T) {
+	mapping := For[employee]()
+
+	tests := []struct {
+		field  string
+		column string
+	}{
+		{"userName", "name"},
+		{"deptId", "dept_id"},
+		{"Remark", "remark"},
+		{"id", "id"},
+		{"createdBy", "created_by"},
+		{"hiredTime", "hired_time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			column, ok := mapping.Column(tt.field)
+			assert.True(t, ok, "Field should be mapped")
+			assert.Equal(t, tt.column, column, "Should map the field to its column")
+
+			field, ok := mapping.Field(tt.column)
+			assert.True(t, ok, "Column should be mapped")
+			assert.Equal(t, tt.field, field, "Should map the column back to its field")
+		})
+	}
+
+	for _, field := range []string{"Salary", "Nickname", "deptName", "department", "HiredAt", "BaseModel"} {
+		_, ok := mapping.Column(field)
+		assert.False(t, ok, "Field %s should not be mapped", field)
+	}
+
+	assert.Same(t, mapping, For[*employee](), "Should cache the mapping per model type")
+}
+
+func TestColumns(t *testing.T) {
+	columns, unknown := For[employee]().Columns("userName", "deptId", "salary")
+
+	assert.Equal(t, []string{"name", "dept_id"}, columns, "Should resolve the mapped fields")
+	assert.Equal(t, []string{"salary"}, unknown, "Should report the unmapped fields")
+}
+
+func TestRegister(t *testing.T) {
+	type product struct {
+		Title string `json:"title"`
+		Code  string `json:"code"`
+	}
+
+	assert.Equal(t, "title", lookup(t, For[product](), "title"))
+
+	Register[product](map[string]string{"title": "product_name", "label": "display_label"})
+
+	mapping := For[product]()
+	assert.Equal(t, "product_name", lookup(t, mapping, "title"), "Should override the derived column")
+	assert.Equal(t, "display_label", lookup(t, mapping, "label"), "Should add the registered field")
+	assert.Equal(t, "code", lookup(t, mapping, "code"), "Should keep the derived fields")
+
+	_, ok := mapping.Field("title")
+	assert.False(t, ok, "Should drop the overridden column")
+}
+
+func TestOfNonStruct(t *testing.T) {
+	mapping := Of(reflect.TypeFor[string]())
+	assert.Nil(t, mapping)
+
+	_, ok := mapping.Column("name")
+	assert.False(t, ok, "Nil mapping should not map any field")
+}
+
+func lookup(t *testing.T, mapping *Mapping, field string) string {
+	t.Helper()
+
+	column, ok := mapping.Column(field)
+	assert.True(t, ok, "Field %s should be mapped", field)
+
+	return column
+}
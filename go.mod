@@ -6,6 +6,7 @@ require (
 	ariga.io/atlas v1.0.0
 	github.com/ajitpratap0/GoSQLX v1.6.0
 	github.com/bwmarrin/snowflake v0.3.0
+	github.com/bytedance/sonic v1.14.1
 	github.com/cloudwego/eino v0.7.28
 	github.com/dlclark/regexp2 v1.11.5
 	github.com/dop251/goja v0.0.0-20260106131823-651366fbe6e3
@@ -62,6 +63,7 @@ require (
 	golang.org/x/sync v0.19.0
 	golang.org/x/text v0.33.0
 	golang.org/x/tools v0.41.0
+	modernc.org/sqlite v1.41.0
 )
 
 require (
@@ -77,7 +79,6 @@ require (
 	github.com/bmatcuk/doublestar v1.3.4 // indirect
 	github.com/buger/jsonparser v1.1.1 // indirect
 	github.com/bytedance/gopkg v0.1.3 // indirect
-	github.com/bytedance/sonic v1.14.1 // indirect
 	github.com/bytedance/sonic/loader v0.3.0 // indirect
 	github.com/cenkalti/backoff/v4 v4.3.0 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
@@ -209,5 +210,4 @@ require (
 	modernc.org/libc v1.67.1 // indirect
 	modernc.org/mathutil v1.7.1 // indirect
 	modernc.org/memory v1.11.0 // indirect
-	modernc.org/sqlite v1.41.0 // indirect
 )
@@ -23,3 +23,14 @@ func Generate() string {
 func GenerateUUID() string {
 	return DefaultUUIDGenerator.Generate()
 }
+
+// GenerateULID creates a new ULID using the default ULID generator.
+// ULIDs sort by creation time and are 26-character Crockford base32 strings.
+//
+// Example:
+//
+//	ulid := GenerateULID()
+//	// Returns something like: "01ARYZ6S41TSV4RRFFQ69G5FAV"
+func GenerateULID() string {
+	return DefaultULIDGenerator.Generate()
+}
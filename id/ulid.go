@@ -0,0 +1,79 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ulidEncoding is the Crockford base32 alphabet of ULIDs.
+const ulidEncoding = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// DefaultULIDGenerator is the default ULID generator instance.
+var DefaultULIDGenerator = NewULIDGenerator()
+
+// ulidGenerator generates monotonic ULIDs: IDs of the same millisecond increment the random part of the previous one,
+// so they sort in generation order within the process.
+type ulidGenerator struct {
+	mu      sync.Mutex
+	lastMs  uint64
+	entropy [10]byte
+}
+
+// Generate creates a new ULID as a 26-character Crockford base32 string.
+func (g *ulidGenerator) Generate() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(time.Now().UnixMilli())
+	if ms > g.lastMs || !g.increment() {
+		if _, err := rand.Read(g.entropy[:]); err != nil {
+			panic(fmt.Errorf("failed to generate ULID: %w", err))
+		}
+
+		// An overflowing random part moves on to the next millisecond to keep the order
+		g.lastMs = max(ms, g.lastMs+1)
+	}
+
+	var data [16]byte
+	binary.BigEndian.PutUint16(data[0:], uint16(g.lastMs>>32))
+	binary.BigEndian.PutUint32(data[2:], uint32(g.lastMs))
+	copy(data[6:], g.entropy[:])
+
+	return encodeULID(data)
+}
+
+// increment adds one to the random part, reporting false when it overflows.
+func (g *ulidGenerator) increment() bool {
+	for i := len(g.entropy) - 1; i >= 0; i-- {
+		g.entropy[i]++
+		if g.entropy[i] != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encodeULID encodes the 128 bits of a ULID as 26 base32 characters, 5 bits each after 2 leading zero bits.
+func encodeULID(data [16]byte) string {
+	hi := binary.BigEndian.Uint64(data[:8])
+	lo := binary.BigEndian.Uint64(data[8:])
+
+	var out [26]byte
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = ulidEncoding[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(out[:])
+}
+
+// NewULIDGenerator creates a new ULID generator instance.
+// ULIDs embed a millisecond timestamp for natural ordering and 80 random bits for uniqueness.
+func NewULIDGenerator() IDGenerator {
+	return &ulidGenerator{}
+}
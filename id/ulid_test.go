@@ -0,0 +1,62 @@
+package id
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestULIDGenerator(t *testing.T) {
+	t.Run("CreateGenerator", func(t *testing.T) {
+		generator := NewULIDGenerator()
+		assert.NotNil(t, generator, "Generator should not be nil")
+	})
+
+	t.Run("GenerateValidULIDFormat", func(t *testing.T) {
+		id := NewULIDGenerator().Generate()
+
+		assert.Len(t, id, 26, "ULID should be 26 characters")
+		assert.Regexp(t, regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`), id, "ULID should use Crockford base32")
+	})
+
+	t.Run("EncodeTimestamp", func(t *testing.T) {
+		var data [16]byte
+
+		assert.Equal(t, "00000000000000000000000000", encodeULID(data))
+
+		for i := range data {
+			data[i] = 0xff
+		}
+
+		assert.Equal(t, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", encodeULID(data), "Should encode the largest ULID")
+
+		// 1469918176385 is the timestamp of the ULID specification example 01ARYZ6S41
+		data = [16]byte{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x81}
+		assert.Equal(t, "01ARYZ6S41", encodeULID(data)[:10])
+	})
+
+	t.Run("GenerateMonotonicIDs", func(t *testing.T) {
+		generator := NewULIDGenerator()
+		previous := generator.Generate()
+
+		for range 10000 {
+			id := generator.Generate()
+			assert.Greater(t, id, previous, "ULIDs should increase within the process")
+			previous = id
+		}
+	})
+
+	t.Run("OverflowRandomPart", func(t *testing.T) {
+		lastMs := uint64(time.Now().Add(time.Hour).UnixMilli())
+
+		generator := &ulidGenerator{lastMs: lastMs}
+		for i := range generator.entropy {
+			generator.entropy[i] = 0xff
+		}
+
+		generator.Generate()
+		assert.Equal(t, lastMs+1, generator.lastMs, "Should move on to the next millisecond when the random part overflows")
+	})
+}
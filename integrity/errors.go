@@ -0,0 +1,6 @@
+package integrity
+
+import "errors"
+
+// ErrReferenceNotFound is returned when checking a reference that is not configured.
+var ErrReferenceNotFound = errors.New("integrity reference not found")
@@ -0,0 +1,14 @@
+package integrity
+
+import "context"
+
+// Checker scans logical foreign keys for orphaned references.
+type Checker interface {
+	// References returns the checked references ordered by name.
+	References() []Reference
+	// Check counts the orphaned rows of the references, all of them when no name is given.
+	Check(ctx context.Context, names ...string) ([]Result, error)
+	// Fix applies the OnOrphan action of the references to their orphaned rows, all of them when no name is given.
+	// References with ActionReport are checked only.
+	Fix(ctx context.Context, names ...string) ([]Result, error)
+}
@@ -0,0 +1,31 @@
+package integrity
+
+// Action is the fix applied to the orphaned rows of a reference.
+type Action string
+
+const (
+	// ActionReport only reports orphaned rows.
+	ActionReport Action = "report"
+	// ActionSetNull clears the referencing column of orphaned rows.
+	ActionSetNull Action = "set_null"
+	// ActionDelete deletes orphaned rows.
+	ActionDelete Action = "delete"
+)
+
+// Reference is a logical foreign key not enforced by a database constraint.
+type Reference struct {
+	Name      string `json:"name"`      // Identifies the reference, defaults to table.column
+	Table     string `json:"table"`     // Referencing table
+	Column    string `json:"column"`    // Referencing column
+	RefTable  string `json:"refTable"`  // Referenced table
+	RefColumn string `json:"refColumn"` // Referenced column, defaults to id
+	OnOrphan  Action `json:"onOrphan"`  // Fix applied to orphaned rows, defaults to ActionReport
+}
+
+// Result is the outcome of checking a reference.
+type Result struct {
+	Reference Reference `json:"reference"`
+	Orphans   int64     `json:"orphans"` // Number of rows referencing a missing row
+	Samples   []string  `json:"samples"` // Some of the missing referenced values
+	Fixed     int64     `json:"fixed"`   // Number of rows fixed, always zero when only checking
+}
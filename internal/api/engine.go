@@ -224,6 +224,9 @@ func (e *engine) registerOperation(res api.Resource, spec api.OperationSpec) err
 		Auth:        ac,
 		Timeout:     e.resolveTimeout(spec.Timeout),
 		RateLimit:   e.resolveRateLimit(spec.RateLimit),
+		IPFilter:    spec.IPFilter,
+		BodyLimit:   spec.BodyLimit,
+		Cache:       spec.Cache,
 		EnableAudit: spec.EnableAudit,
 		Meta: map[string]any{
 			shared.MetaKeyResource: res,
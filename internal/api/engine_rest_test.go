@@ -103,6 +103,12 @@ func NewTestRESTResource() api.Resource {
 					Public:  true,
 					Handler: "Panic",
 				},
+				api.OperationSpec{
+					Action:    "post limited",
+					Public:    true,
+					Handler:   "Post",
+					BodyLimit: &api.BodyLimitConfig{MaxSize: 64},
+				},
 			),
 		),
 	}
@@ -582,6 +588,14 @@ func (suite *RESTEngineTestSuite) TestPostCreateInvalidBody() {
 	suite.Equal(result.ErrCodeBadRequest, body.Code, "Should return bad request error")
 }
 
+func (suite *RESTEngineTestSuite) TestBodyLimitBeforeParsing() {
+	suite.T().Log("Testing oversized bodies are rejected before they are parsed")
+
+	resp := suite.makeRESTRequest(fiber.MethodPost, "/api/items/limited", "{invalid json"+strings.Repeat(" ", 64)+"}")
+
+	suite.Equal(413, resp.StatusCode, "Oversized body should be rejected before it is parsed")
+}
+
 func (suite *RESTEngineTestSuite) TestPutUpdateWithoutToken() {
 	suite.T().Log("Testing PUT update without token")
 
@@ -1,8 +1,10 @@
 package api_test
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -115,6 +117,11 @@ func NewTestRPCResource() api.Resource {
 					Action: "panic",
 					Public: true,
 				},
+				api.OperationSpec{
+					Action:    "limited",
+					Public:    true,
+					BodyLimit: &api.BodyLimitConfig{MaxSize: 2048, MaxFileSize: 16},
+				},
 			),
 		),
 	}
@@ -204,6 +211,10 @@ func (*TestRPCResource) Panic(_ fiber.Ctx) error {
 	panic("intentional panic for testing")
 }
 
+func (*TestRPCResource) Limited(ctx fiber.Ctx, params *EchoParams) error {
+	return result.Ok(params).Response(ctx)
+}
+
 // RPCEngineTestSuite tests RPC API engine functionality.
 type RPCEngineTestSuite struct {
 	suite.Suite
@@ -863,6 +874,61 @@ func (suite *RPCEngineTestSuite) TestHandlerPanic() {
 	suite.Equal(500, resp.StatusCode, "Should return 500 Internal Server Error")
 }
 
+func (suite *RPCEngineTestSuite) TestBodyLimitBeforeParsing() {
+	suite.T().Log("Testing oversized bodies are rejected before they are parsed")
+
+	suite.Run("WithinLimit", func() {
+		resp := suite.makeApiRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "test",
+				Action:   "limited",
+				Version:  "v1",
+			},
+			Params: map[string]any{"message": "hello"},
+		})
+
+		suite.Equal(200, resp.StatusCode, "Body within the limit should be accepted")
+	})
+
+	suite.Run("OversizedJSON", func() {
+		// The params are not an object, parsing the body would fail with a bad request
+		body := `{"resource":"test","action":"limited","version":"v1","params":"` + strings.Repeat("x", 2048) + `"}`
+
+		req := httptest.NewRequest(fiber.MethodPost, "/api", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := suite.app.Test(req, 30*time.Second)
+		suite.Require().NoError(err)
+		suite.Equal(413, resp.StatusCode, "Oversized body should be rejected before it is parsed")
+	})
+
+	suite.Run("OversizedFile", func() {
+		var body bytes.Buffer
+
+		writer := multipart.NewWriter(&body)
+		suite.Require().NoError(writer.WriteField("params", "{invalid"))
+
+		file, err := writer.CreateFormFile("file", "upload.bin")
+		suite.Require().NoError(err)
+
+		_, err = file.Write(bytes.Repeat([]byte("x"), 17))
+		suite.Require().NoError(err)
+
+		// The identifier follows the file, it is still found without parsing the form
+		suite.Require().NoError(writer.WriteField("resource", "test"))
+		suite.Require().NoError(writer.WriteField("action", "limited"))
+		suite.Require().NoError(writer.WriteField("version", "v1"))
+		suite.Require().NoError(writer.Close())
+
+		req := httptest.NewRequest(fiber.MethodPost, "/api", &body)
+		req.Header.Set(fiber.HeaderContentType, writer.FormDataContentType())
+
+		resp, err := suite.app.Test(req, 30*time.Second)
+		suite.Require().NoError(err)
+		suite.Equal(413, resp.StatusCode, "Oversized file should be rejected before the form is parsed")
+	})
+}
+
 func (suite *RPCEngineTestSuite) TestUserLoaderCalledOnLogin() {
 	suite.T().Log("Testing UserLoader is called during login")
 
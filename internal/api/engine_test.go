@@ -487,6 +487,29 @@ func TestEngineRegister(t *testing.T) {
 		assert.Equal(t, 10, op.RateLimit.Max, "Operation should have custom rate limit max")
 	})
 
+	t.Run("OperationWithIPFilter", func(t *testing.T) {
+		router := &mockRouterStrategy{name: "rpc"}
+		ipFilter := &api.IPFilterConfig{Allow: []string{"10.0.0.0/8"}}
+		collector := &mockOperationsCollector{
+			specs: []api.OperationSpec{{Action: "callback", IPFilter: ipFilter}},
+		}
+		resolver := &mockHandlerResolver{handler: dummyHandler}
+		eng, _ := NewEngine(
+			WithRouters(router),
+			WithOperationCollectors(collector),
+			WithHandlerResolvers(resolver),
+		)
+		res := &mockResource{kind: api.KindRPC, name: "payment"}
+
+		err := eng.Register(res)
+		assert.NoError(t, err, "Registration with IP filter should succeed")
+
+		op := eng.Lookup(api.Identifier{Resource: "payment", Action: "callback", Version: api.VersionV1})
+		assert.NotNil(t, op, "Operation should be found")
+		assert.True(t, op.HasIPFilter(), "Operation should have the IP filter")
+		assert.Equal(t, ipFilter, op.IPFilter, "Operation should keep the configured IP filter")
+	})
+
 	t.Run("OperationWithEnableAudit", func(t *testing.T) {
 		router := &mockRouterStrategy{name: "rpc"}
 		collector := &mockOperationsCollector{
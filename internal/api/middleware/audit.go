@@ -9,6 +9,7 @@ import (
 	"github.com/gofiber/utils/v2"
 
 	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/config"
 	"github.com/ilxqx/vef-framework-go/contextx"
 	"github.com/ilxqx/vef-framework-go/encoding"
 	"github.com/ilxqx/vef-framework-go/event"
@@ -22,13 +23,16 @@ import (
 
 // Audit handles audit logging.
 type Audit struct {
-	publisher event.Publisher
+	publisher      event.Publisher
+	trustedProxies *security.TrustedProxies
 }
 
 // NewAudit creates a new audit middleware.
-func NewAudit(publisher event.Publisher) api.Middleware {
+// Client addresses are resolved through the trusted proxies of the IP filter configuration.
+func NewAudit(publisher event.Publisher, securityConfig *config.SecurityConfig) api.Middleware {
 	return &Audit{
-		publisher: publisher,
+		publisher:      publisher,
+		trustedProxies: security.NewTrustedProxies(securityConfig.IPFilter.TrustedProxies),
 	}
 }
 
@@ -65,7 +69,7 @@ func (m *Audit) audit(ctx fiber.Ctx, op *api.Operation) error {
 		elapsed    = time.Since(start).Milliseconds()
 	)
 
-	evt, buildErr := buildAuditEvent(ctx, elapsed, handlerErr)
+	evt, buildErr := buildAuditEvent(ctx, webhelpers.GetClientIP(ctx, m.trustedProxies.Contains), elapsed, handlerErr)
 	if buildErr != nil {
 		contextx.Logger(ctx).Errorf("%v: %v", ErrAuditEventBuildFailed, buildErr)
 
@@ -77,7 +81,7 @@ func (m *Audit) audit(ctx fiber.Ctx, op *api.Operation) error {
 	return handlerErr
 }
 
-func buildAuditEvent(ctx fiber.Ctx, elapsed int64, err error) (*api.AuditEvent, error) {
+func buildAuditEvent(ctx fiber.Ctx, requestIP string, elapsed int64, err error) (*api.AuditEvent, error) {
 	req := shared.Request(ctx)
 	if req == nil {
 		return nil, ErrRequestNotFound
@@ -89,15 +93,19 @@ func buildAuditEvent(ctx fiber.Ctx, elapsed int64, err error) (*api.AuditEvent,
 	}
 
 	var (
-		userID     = principal.ID
-		requestID  = contextx.RequestID(ctx)
-		requestIP  = webhelpers.GetIP(ctx)
-		userAgent  = utils.CopyString(ctx.Get(fiber.HeaderUserAgent))
-		resultCode int
-		resultMsg  string
-		resultData any
+		userID         = principal.ID
+		impersonatorID string
+		requestID      = contextx.RequestID(ctx)
+		userAgent      = utils.CopyString(ctx.Get(fiber.HeaderUserAgent))
+		resultCode     int
+		resultMsg      string
+		resultData     any
 	)
 
+	if principal.Impersonator != nil {
+		impersonatorID = principal.Impersonator.ID
+	}
+
 	if err != nil {
 		resultCode, resultMsg = extractErrorInfo(err)
 	} else {
@@ -112,19 +120,20 @@ func buildAuditEvent(ctx fiber.Ctx, elapsed int64, err error) (*api.AuditEvent,
 	}
 
 	return api.NewAuditEvent(api.AuditEventParams{
-		Resource:      req.Resource,
-		Action:        req.Action,
-		Version:       req.Version,
-		UserID:        userID,
-		UserAgent:     userAgent,
-		RequestID:     requestID,
-		RequestIP:     requestIP,
-		RequestParams: req.Params,
-		RequestMeta:   req.Meta,
-		ResultCode:    resultCode,
-		ResultMessage: resultMsg,
-		ResultData:    resultData,
-		ElapsedTime:   elapsed,
+		Resource:       req.Resource,
+		Action:         req.Action,
+		Version:        req.Version,
+		UserID:         userID,
+		ImpersonatorID: impersonatorID,
+		UserAgent:      userAgent,
+		RequestID:      requestID,
+		RequestIP:      requestIP,
+		RequestParams:  req.Params,
+		RequestMeta:    req.Meta,
+		ResultCode:     resultCode,
+		ResultMessage:  resultMsg,
+		ResultData:     resultData,
+		ElapsedTime:    elapsed,
 	}), nil
 }
 
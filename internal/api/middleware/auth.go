@@ -10,6 +10,7 @@ import (
 	"github.com/ilxqx/vef-framework-go/constants"
 	"github.com/ilxqx/vef-framework-go/contextx"
 	"github.com/ilxqx/vef-framework-go/internal/api/shared"
+	"github.com/ilxqx/vef-framework-go/result"
 	"github.com/ilxqx/vef-framework-go/security"
 )
 
@@ -32,7 +33,7 @@ func (*Auth) Name() string {
 }
 
 // Order returns the middleware order.
-// Authentication runs first in the middleware chain.
+// Authentication runs first in the middleware chain, after the IP filter.
 func (*Auth) Order() int {
 	return -100
 }
@@ -65,6 +66,11 @@ func (m *Auth) authenticate(ctx fiber.Ctx, op *api.Operation) error {
 	contextx.SetPrincipal(ctx, principal)
 	ctx.SetContext(contextx.SetPrincipal(ctx.Context(), principal))
 
+	// Fields tagged with perm are filtered for the principal when the response is serialized.
+	result.SetDataFilter(ctx, func(ctx fiber.Ctx, data any) (any, error) {
+		return security.FilterFields(ctx.Context(), m.checker, principal, data)
+	})
+
 	return m.checkPermission(ctx, op, principal)
 }
 
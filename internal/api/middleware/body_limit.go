@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/webhelpers"
+)
+
+// CheckBodyLimit rejects request bodies exceeding the operation's body limit.
+// The application body limit still caps all bodies, this narrows it per operation.
+// Routers call it before the body is parsed: multipart bodies are scanned part by part
+// without being buffered, so that forms over a limit are never parsed.
+func CheckBodyLimit(ctx fiber.Ctx, op *api.Operation) error {
+	if op == nil || !op.HasBodyLimit() {
+		return nil
+	}
+
+	limit := op.BodyLimit
+	if limit.MaxSize > 0 {
+		size, err := bodySize(ctx, limit.MaxSize)
+		if err != nil {
+			return err
+		}
+
+		if size > limit.MaxSize {
+			contextx.Logger(ctx).Warnf("Rejected body of %s/%s/%s: size exceeds %d bytes", op.Resource, op.Version, op.Action, limit.MaxSize)
+
+			return result.ErrRequestEntityTooLarge
+		}
+	}
+
+	if (limit.MaxParts > 0 || limit.MaxFileSize > 0) && webhelpers.IsMultipart(ctx) {
+		reason, err := scanMultipart(ctx, limit)
+		if err != nil {
+			return err
+		}
+
+		if reason != constants.Empty {
+			contextx.Logger(ctx).Warnf("Rejected body of %s/%s/%s: %s", op.Resource, op.Version, op.Action, reason)
+
+			return result.ErrRequestEntityTooLarge
+		}
+	}
+
+	return nil
+}
+
+// scanMultipart reads the parts of a multipart body one at a time and returns why the body exceeds the limit,
+// or an empty string when it is within the limit. Parts are discarded as they are read.
+func scanMultipart(ctx fiber.Ctx, limit *api.BodyLimitConfig) (string, error) {
+	boundary := string(ctx.Request().Header.MultipartFormBoundary())
+	reader := multipart.NewReader(bytes.NewReader(ctx.Body()), boundary)
+
+	for parts := 1; ; parts++ {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return constants.Empty, nil
+		}
+
+		if err != nil {
+			return constants.Empty, err
+		}
+
+		if limit.MaxParts > 0 && parts > limit.MaxParts {
+			return fmt.Sprintf("parts exceed %d", limit.MaxParts), nil
+		}
+
+		if limit.MaxFileSize > 0 && part.FileName() != constants.Empty {
+			size, err := io.Copy(io.Discard, io.LimitReader(part, limit.MaxFileSize+1))
+			if err != nil {
+				return constants.Empty, err
+			}
+
+			if size > limit.MaxFileSize {
+				return fmt.Sprintf("file %q exceeds %d bytes", part.FileName(), limit.MaxFileSize), nil
+			}
+		}
+	}
+}
+
+// bodySize returns the size of the request body, without reading further than one byte past maxSize.
+// The declared content length rejects bodies before they are read; streamed bodies are read up to the limit
+// and kept in memory for the handler when within it.
+func bodySize(ctx fiber.Ctx, maxSize int64) (int64, error) {
+	req := ctx.Request()
+	if length := int64(req.Header.ContentLength()); length > maxSize {
+		return length, nil
+	}
+
+	if !req.IsBodyStream() {
+		return int64(len(req.Body())), nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.BodyStream(), maxSize+1))
+	if err != nil {
+		return 0, err
+	}
+
+	if int64(len(body)) > maxSize {
+		// The rest of the body stays unread, the connection cannot serve further requests
+		req.SetConnectionClose()
+	} else {
+		req.SetBody(body)
+	}
+
+	return int64(len(body)), nil
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+func newMultipartBody(t *testing.T, values int, fileSize int) (string, *bytes.Buffer) {
+	t.Helper()
+
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+	for range values {
+		require.NoError(t, writer.WriteField("field", "value"))
+	}
+
+	file, err := writer.CreateFormFile("file", "upload.bin")
+	require.NoError(t, err)
+
+	_, err = file.Write(bytes.Repeat([]byte("x"), fileSize))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return writer.FormDataContentType(), &body
+}
+
+func TestCheckBodyLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     *api.BodyLimitConfig
+		multipart bool
+		values    int
+		size      int
+		status    int
+	}{
+		{"WithinMaxSize", &api.BodyLimitConfig{MaxSize: 64}, false, 0, 64, fiber.StatusOK},
+		{"OverMaxSize", &api.BodyLimitConfig{MaxSize: 64}, false, 0, 65, fiber.StatusRequestEntityTooLarge},
+		{"WithinMaxParts", &api.BodyLimitConfig{MaxParts: 3}, true, 2, 8, fiber.StatusOK},
+		{"OverMaxParts", &api.BodyLimitConfig{MaxParts: 3}, true, 3, 8, fiber.StatusRequestEntityTooLarge},
+		{"WithinMaxFileSize", &api.BodyLimitConfig{MaxFileSize: 16}, true, 0, 16, fiber.StatusOK},
+		{"OverMaxFileSize", &api.BodyLimitConfig{MaxFileSize: 16}, true, 0, 17, fiber.StatusRequestEntityTooLarge},
+		{"OperationWithoutLimit", nil, false, 0, 1024, fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{
+				ErrorHandler: func(ctx fiber.Ctx, err error) error {
+					if e, ok := result.AsErr(err); ok {
+						return ctx.SendStatus(e.Status)
+					}
+
+					return ctx.SendStatus(fiber.StatusInternalServerError)
+				},
+			})
+			app.Post("/api", func(ctx fiber.Ctx) error {
+				contextx.SetLogger(ctx, log.Named("test"))
+
+				if err := CheckBodyLimit(ctx, &api.Operation{BodyLimit: tt.limit}); err != nil {
+					return err
+				}
+
+				return ctx.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest(fiber.MethodPost, "/api", strings.NewReader(strings.Repeat("x", tt.size)))
+			if tt.multipart {
+				contentType, body := newMultipartBody(t, tt.values, tt.size)
+				req = httptest.NewRequest(fiber.MethodPost, "/api", body)
+				req.Header.Set(fiber.HeaderContentType, contentType)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/gofiber/fiber/v3"
@@ -9,21 +10,25 @@ import (
 	"github.com/ilxqx/vef-framework-go/constants"
 	"github.com/ilxqx/vef-framework-go/contextx"
 	"github.com/ilxqx/vef-framework-go/internal/api/shared"
+	"github.com/ilxqx/vef-framework-go/internal/database/querypolicy"
 	"github.com/ilxqx/vef-framework-go/orm"
 	"github.com/ilxqx/vef-framework-go/security"
 )
 
 // Contextual injects DB and Logger into the request context.
-// It sets up a contextual database with the operator ID and a scoped logger
-// with request identification information.
+// It sets up a contextual database with the operator ID, a scoped logger
+// with request identification information, the resource resolving query policies
+// and the data scope context of the principal.
 type Contextual struct {
-	db orm.DB
+	db       orm.DB
+	resolver security.DataScopeContextResolver
 }
 
 // NewContextual creates a new context middleware.
-func NewContextual(db orm.DB) api.Middleware {
+func NewContextual(db orm.DB, resolver security.DataScopeContextResolver) api.Middleware {
 	return &Contextual{
-		db: db,
+		db:       db,
+		resolver: resolver,
 	}
 }
 
@@ -49,7 +54,17 @@ func (m *Contextual) Process(ctx fiber.Ctx) error {
 	contextx.SetDB(ctx, db)
 	ctx.SetContext(contextx.SetDB(ctx.Context(), db))
 
+	sc, err := m.dataScopeContext(ctx, principal)
+	if err != nil {
+		return err
+	}
+
+	ctx.SetContext(orm.WithDataScopeContext(ctx.Context(), sc))
+
 	req := shared.Request(ctx)
+	if req != nil {
+		querypolicy.SetResource(ctx, req.Resource)
+	}
 
 	lgr := contextx.Logger(ctx)
 	if req != nil && lgr != nil {
@@ -63,6 +78,27 @@ func (m *Contextual) Process(ctx fiber.Ctx) error {
 	return ctx.Next()
 }
 
+// dataScopeContext resolves the scope context of the principal, defaulting to its ID and the tenant of the request.
+// System principals bypass the data scopes.
+func (m *Contextual) dataScopeContext(ctx fiber.Ctx, principal *security.Principal) (*orm.DataScopeContext, error) {
+	if m.resolver != nil {
+		sc, err := m.resolver.ResolveDataScopeContext(ctx.Context(), principal)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve data scope context of principal %q: %w", principal.ID, err)
+		}
+
+		if sc != nil {
+			return sc, nil
+		}
+	}
+
+	return &orm.DataScopeContext{
+		UserID:   principal.ID,
+		TenantID: contextx.TenantID(ctx.Context()),
+		Bypass:   principal.Type == security.PrincipalTypeSystem,
+	}, nil
+}
+
 // buildRequestLoggerName creates a logger name from request info.
 // Format: resource:action@version.
 func buildRequestLoggerName(resource, action, version string) string {
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+// staticScopeResolver resolves the same scope context, or error, for every principal.
+type staticScopeResolver struct {
+	sc  *orm.DataScopeContext
+	err error
+}
+
+func (r staticScopeResolver) ResolveDataScopeContext(context.Context, *security.Principal) (*orm.DataScopeContext, error) {
+	return r.sc, r.err
+}
+
+func TestContextualDataScopeContext(t *testing.T) {
+	bunDB, err := database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = bunDB.Close() })
+
+	db := iorm.New(bunDB)
+	resolved := &orm.DataScopeContext{UserID: "u1", DeptIDs: []string{"sales"}}
+
+	tests := []struct {
+		name      string
+		principal *security.Principal
+		resolver  security.DataScopeContextResolver
+		status    int
+		expected  *orm.DataScopeContext
+	}{
+		{"User", security.NewUser("u1", "Alice"), nil, fiber.StatusOK, &orm.DataScopeContext{UserID: "u1", TenantID: "t1"}},
+		{"System", security.PrincipalSystem, nil, fiber.StatusOK, &orm.DataScopeContext{UserID: security.PrincipalSystem.ID, TenantID: "t1", Bypass: true}},
+		{"Anonymous", nil, nil, fiber.StatusOK, &orm.DataScopeContext{UserID: constants.OperatorAnonymous, TenantID: "t1"}},
+		{"Resolved", security.NewUser("u1", "Alice"), staticScopeResolver{sc: resolved}, fiber.StatusOK, resolved},
+		{"ResolvedDefault", security.NewUser("u1", "Alice"), staticScopeResolver{}, fiber.StatusOK, &orm.DataScopeContext{UserID: "u1", TenantID: "t1"}},
+		{"ResolutionFailed", security.NewUser("u1", "Alice"), staticScopeResolver{err: errors.New("directory down")}, fiber.StatusInternalServerError, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sc *orm.DataScopeContext
+
+			app := fiber.New()
+			app.Post("/api", func(ctx fiber.Ctx) error {
+				if tt.principal != nil {
+					contextx.SetPrincipal(ctx, tt.principal)
+				}
+
+				ctx.SetContext(contextx.SetTenantID(ctx.Context(), "t1"))
+
+				return ctx.Next()
+			}, NewContextual(db, tt.resolver).Process, func(ctx fiber.Ctx) error {
+				sc = orm.DataScopeContextFrom(ctx.Context())
+
+				return ctx.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/api", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.expected, sc, "Should carry the data scope context of the principal")
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/api/shared"
+	"github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+// recordingPublisher collects published events.
+type recordingPublisher struct {
+	events []event.Event
+}
+
+func (p *recordingPublisher) Publish(evt event.Event) {
+	p.events = append(p.events, evt)
+}
+
+func TestIPFilter(t *testing.T) {
+	op := &api.Operation{
+		Identifier: api.Identifier{Resource: "payment", Action: "callback", Version: api.VersionV1},
+		IPFilter: &api.IPFilterConfig{
+			Allow: []string{"10.0.0.0/8"},
+			Deny:  []string{"10.0.0.13"},
+		},
+	}
+
+	// The remote address of test requests is 0.0.0.0, a trusted proxy appending the client to X-Forwarded-For
+	securityConfig := &config.SecurityConfig{
+		IPFilter: config.IPFilterConfig{TrustedProxies: []string{"0.0.0.0"}},
+	}
+
+	tests := []struct {
+		name      string
+		op        *api.Operation
+		forwarded string
+		ip        string
+		status    int
+		reason    string
+		published bool
+	}{
+		{"AllowedAddress", op, "10.1.2.3", "10.1.2.3", fiber.StatusOK, "", false},
+		{"DeniedAddress", op, "10.0.0.13", "10.0.0.13", fiber.StatusUnauthorized, security.IPBlockReasonDenied, true},
+		{"AddressOutsideAllowList", op, "192.168.1.1", "192.168.1.1", fiber.StatusUnauthorized, security.IPBlockReasonNotAllowed, true},
+		{"ForgedAllowedAddress", op, "10.1.2.3, 192.168.1.1", "192.168.1.1", fiber.StatusUnauthorized, security.IPBlockReasonNotAllowed, true},
+		{"ForgedEvadingDenial", op, "10.1.2.3, 10.0.0.13", "10.0.0.13", fiber.StatusUnauthorized, security.IPBlockReasonDenied, true},
+		{"OperationWithoutFilter", &api.Operation{}, "192.168.1.1", "192.168.1.1", fiber.StatusOK, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := new(recordingPublisher)
+			mid := NewIPFilter(publisher, securityConfig)
+
+			app := fiber.New(fiber.Config{
+				ErrorHandler: func(ctx fiber.Ctx, _ error) error {
+					return ctx.SendStatus(fiber.StatusUnauthorized)
+				},
+			})
+			app.Post("/api", func(ctx fiber.Ctx) error {
+				shared.SetOperation(ctx, tt.op)
+				contextx.SetLogger(ctx, log.Named("test"))
+
+				return ctx.Next()
+			}, mid.Process, func(ctx fiber.Ctx) error {
+				return ctx.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest(fiber.MethodPost, "/api", nil)
+			req.Header.Set(fiber.HeaderXForwardedFor, tt.forwarded)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			if !tt.published {
+				assert.Empty(t, publisher.events, "Should not publish a blocked event")
+
+				return
+			}
+
+			require.Len(t, publisher.events, 1, "Should publish a blocked event")
+			blocked, ok := publisher.events[0].(*security.IPBlockedEvent)
+			require.True(t, ok)
+			assert.Equal(t, tt.ip, blocked.IP)
+			assert.Equal(t, tt.reason, blocked.Reason)
+			assert.Equal(t, security.IPFilterScopeOperation, blocked.Scope)
+			assert.Equal(t, "payment", blocked.Resource)
+			assert.Equal(t, "callback", blocked.Action)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/metering"
+)
+
+// Metering counts the API calls of each tenant for usage-based billing.
+// Requests without tenant are not counted.
+type Metering struct {
+	recorder metering.Recorder
+}
+
+// NewMetering creates a new metering middleware, recorder is nil when metering is disabled.
+func NewMetering(recorder metering.Recorder) api.Middleware {
+	return &Metering{
+		recorder: recorder,
+	}
+}
+
+// Name returns the middleware name.
+func (*Metering) Name() string {
+	return "metering"
+}
+
+// Order returns the middleware order.
+func (*Metering) Order() int {
+	return -65
+}
+
+// Process counts the call once the handler ran.
+func (m *Metering) Process(ctx fiber.Ctx) error {
+	if m.recorder == nil {
+		return ctx.Next()
+	}
+
+	err := ctx.Next()
+
+	if tenantID := contextx.TenantID(ctx); tenantID != constants.Empty {
+		m.recorder.Add(ctx, tenantID, metering.MeterAPICalls, 1)
+	}
+
+	return err
+}
@@ -16,16 +16,30 @@ var Module = fx.Module(
 		),
 		fx.Annotate(
 			NewContextual,
+			fx.ParamTags(``, `optional:"true"`),
 			fx.ResultTags(`group:"vef:api:middlewares"`),
 		),
 		fx.Annotate(
 			NewDataPermission,
 			fx.ResultTags(`group:"vef:api:middlewares"`),
 		),
+		fx.Annotate(
+			NewIPFilter,
+			fx.ResultTags(`group:"vef:api:middlewares"`),
+		),
 		fx.Annotate(
 			NewRateLimit,
 			fx.ResultTags(`group:"vef:api:middlewares"`),
 		),
+		fx.Annotate(
+			NewMetering,
+			fx.ParamTags(`optional:"true"`),
+			fx.ResultTags(`group:"vef:api:middlewares"`),
+		),
+		fx.Annotate(
+			NewResponseCache,
+			fx.ResultTags(`group:"vef:api:middlewares"`),
+		),
 	),
 	fx.Provide(
 		fx.Annotate(
@@ -8,6 +8,7 @@ import (
 	"github.com/gofiber/fiber/v3/middleware/limiter"
 
 	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/config"
 	"github.com/ilxqx/vef-framework-go/constants"
 	"github.com/ilxqx/vef-framework-go/contextx"
 	"github.com/ilxqx/vef-framework-go/internal/api/shared"
@@ -28,7 +29,10 @@ type RateLimit struct {
 }
 
 // NewRateLimit creates a new rate limit middleware with shared state.
-func NewRateLimit() api.Middleware {
+// Client addresses are resolved through the trusted proxies of the IP filter configuration.
+func NewRateLimit(securityConfig *config.SecurityConfig) api.Middleware {
+	trustedProxies := security.NewTrustedProxies(securityConfig.IPFilter.TrustedProxies)
+
 	return &RateLimit{
 		h: limiter.New(limiter.Config{
 			LimiterMiddleware: limiter.FixedWindow{},
@@ -51,7 +55,7 @@ func NewRateLimit() api.Middleware {
 					sb.WriteByte(constants.ByteColon)
 					sb.WriteString(req.Action)
 					sb.WriteByte(constants.ByteColon)
-					sb.WriteString(webhelpers.GetIP(ctx))
+					sb.WriteString(webhelpers.GetClientIP(ctx, trustedProxies.Contains))
 					sb.WriteByte(constants.ByteColon)
 				}
 
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/internal/api/shared"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+func TestRateLimitIgnoresForgedForwardedFor(t *testing.T) {
+	identifier := api.Identifier{Resource: "sys/user", Action: "find_page", Version: api.VersionV1}
+	mid := NewRateLimit(&config.SecurityConfig{})
+
+	app := fiber.New(fiber.Config{ErrorHandler: func(ctx fiber.Ctx, err error) error {
+		return ctx.Status(fiber.StatusTooManyRequests).SendString(err.Error())
+	}})
+	app.Get("/api", func(ctx fiber.Ctx) error {
+		shared.SetOperation(ctx, &api.Operation{Identifier: identifier, RateLimit: &api.RateLimitConfig{Max: 1}})
+		shared.SetRequest(ctx, &api.Request{Identifier: identifier})
+
+		return ctx.Next()
+	}, mid.Process, func(ctx fiber.Ctx) error {
+		return result.Ok().Response(ctx)
+	})
+
+	request := func(forwardedFor string) int {
+		req := httptest.NewRequest(fiber.MethodGet, "/api", nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, forwardedFor)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusOK, request("203.0.113.1"))
+	assert.Equal(t, fiber.StatusTooManyRequests, request("203.0.113.2"), "Should limit the remote address whatever X-Forwarded-For claims")
+}
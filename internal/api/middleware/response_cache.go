@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/cache"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/encoding"
+	"github.com/ilxqx/vef-framework-go/hashx"
+	"github.com/ilxqx/vef-framework-go/internal/api/shared"
+	"github.com/ilxqx/vef-framework-go/internal/database/tabletag"
+	"github.com/ilxqx/vef-framework-go/internal/redis"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+const (
+	// defaultResponseCacheMaxSize bounds the number of responses cached in memory.
+	defaultResponseCacheMaxSize = 10000
+
+	// responseCacheNamespace and tableVersionNamespace namespace the keys of the response cache in Redis.
+	responseCacheNamespace = "api_response"
+	tableVersionNamespace  = "api_table_version"
+
+	cacheStatusHit   = "HIT"
+	cacheStatusStale = "STALE"
+	cacheStatusMiss  = "MISS"
+)
+
+// cachedResponse is a response stored by the response cache.
+type cachedResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+	StoredAt    time.Time
+	// Versions holds the version of each table read while producing the response when it was stored.
+	Versions map[string]int64
+}
+
+// ResponseCache serves the cached responses of operations configuring a cache.
+// Responses are tagged with the tables read by the queries producing them and dropped once any of them is written.
+// Past their TTL, responses are served stale while the first request refreshes them.
+type ResponseCache struct {
+	responses  cache.Cache[*cachedResponse]
+	versions   cache.Cache[int64]
+	refreshing sync.Map
+}
+
+// NewResponseCache creates a new response cache middleware invalidated by the writes of the table tracker.
+// Responses and table versions are kept in the configured Redis, so all instances serve and invalidate
+// the same responses, or in memory per instance when Redis is not configured.
+func NewResponseCache(tracker *tabletag.Tracker, rds *redis.Configured) api.Middleware {
+	if rds.Client == nil {
+		return newResponseCache(
+			tracker,
+			cache.NewMemory[*cachedResponse](cache.WithMemMaxSize(defaultResponseCacheMaxSize)),
+			cache.NewMemory[int64](),
+		)
+	}
+
+	return newResponseCache(
+		tracker,
+		cache.NewRedis[*cachedResponse](rds.Client, responseCacheNamespace),
+		cache.NewRedis[int64](rds.Client, tableVersionNamespace),
+	)
+}
+
+func newResponseCache(tracker *tabletag.Tracker, responses cache.Cache[*cachedResponse], versions cache.Cache[int64]) *ResponseCache {
+	m := &ResponseCache{
+		responses: responses,
+		versions:  versions,
+	}
+
+	tracker.OnWrite(m.invalidate)
+
+	return m
+}
+
+// Name returns the middleware name.
+func (*ResponseCache) Name() string {
+	return "response_cache"
+}
+
+// Order returns the middleware order.
+// Cached responses are served after the audit so that hits are audited as well.
+func (*ResponseCache) Order() int {
+	return -50
+}
+
+// Process handles the response cache.
+func (m *ResponseCache) Process(ctx fiber.Ctx) error {
+	op := shared.Operation(ctx)
+	if op == nil || !op.HasCache() {
+		return ctx.Next()
+	}
+
+	key, err := m.key(ctx, op)
+	if err != nil {
+		contextx.Logger(ctx).Warnf("Response cache skipped: %v", err)
+
+		return ctx.Next()
+	}
+
+	if entry, ok := m.responses.Get(ctx, key); ok && m.isCurrent(ctx, entry) {
+		if time.Since(entry.StoredAt) <= op.Cache.TTL {
+			return sendCached(ctx, entry, cacheStatusHit)
+		}
+
+		if _, refreshing := m.refreshing.LoadOrStore(key, struct{}{}); refreshing {
+			return sendCached(ctx, entry, cacheStatusStale)
+		}
+
+		defer m.refreshing.Delete(key)
+	}
+
+	return m.fill(ctx, op, key)
+}
+
+// fill handles the request and stores its response if it succeeded and no table it read was written meanwhile.
+func (m *ResponseCache) fill(ctx fiber.Ctx, op *api.Operation, key string) error {
+	start := time.Now()
+	reads := tabletag.Track(ctx)
+
+	ctx.Set(constants.HeaderXCache, cacheStatusMiss)
+
+	if err := ctx.Next(); err != nil {
+		return err
+	}
+
+	resp := ctx.Response()
+	if resp.StatusCode() != fiber.StatusOK || resp.IsBodyStream() {
+		return nil
+	}
+
+	body := resp.Body()
+	if res, err := encoding.FromJSON[result.Result](string(body)); err != nil || !res.IsOk() {
+		return nil
+	}
+
+	versions, ok := m.tableVersions(ctx, append(reads.Tables(), op.Cache.Tables...), start)
+	if !ok {
+		return nil
+	}
+
+	entry := &cachedResponse{
+		Status:      resp.StatusCode(),
+		ContentType: string(resp.Header.ContentType()),
+		Body:        bytes.Clone(body),
+		StoredAt:    time.Now(),
+		Versions:    versions,
+	}
+
+	if err := m.responses.Set(ctx, key, entry, op.Cache.TTL+op.Cache.StaleTTL); err != nil {
+		contextx.Logger(ctx).Warnf("Failed to cache response of %s/%s/%s: %v", op.Resource, op.Version, op.Action, err)
+	}
+
+	return nil
+}
+
+// tableVersions returns the current versions of the tables,
+// or false if any was written since start and the response may already be outdated.
+func (m *ResponseCache) tableVersions(ctx context.Context, tables []string, start time.Time) (map[string]int64, bool) {
+	versions := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		version, _ := m.versions.Get(ctx, table)
+		if version > start.UnixNano() {
+			return nil, false
+		}
+
+		versions[table] = version
+	}
+
+	return versions, true
+}
+
+// isCurrent reports whether none of the tables read by the cached response was written since it was stored.
+func (m *ResponseCache) isCurrent(ctx context.Context, entry *cachedResponse) bool {
+	for table, version := range entry.Versions {
+		if current, _ := m.versions.Get(ctx, table); current != version {
+			return false
+		}
+	}
+
+	return true
+}
+
+// invalidate bumps the version of the written table, outdating the responses which read it.
+func (m *ResponseCache) invalidate(ctx context.Context, table string) {
+	if err := m.versions.Set(context.WithoutCancel(ctx), table, time.Now().UnixNano()); err != nil {
+		contextx.Logger(ctx).Warnf("Failed to invalidate cached responses of table %s: %v", table, err)
+	}
+}
+
+// key identifies the response by operation, URL, params and meta and by principal or, when shared, by its roles:
+// fields are filtered by the permissions of the principal, which derive from its roles.
+func (*ResponseCache) key(ctx fiber.Ctx, op *api.Operation) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString(op.Resource)
+	sb.WriteByte(constants.ByteColon)
+	sb.WriteString(op.Version)
+	sb.WriteByte(constants.ByteColon)
+	sb.WriteString(op.Action)
+	sb.WriteByte(constants.ByteColon)
+
+	principal := contextx.Principal(ctx)
+	if principal == nil {
+		principal = security.PrincipalAnonymous
+	}
+
+	if op.Cache.Shared {
+		sb.WriteString(string(principal.Type))
+		sb.WriteByte(constants.ByteColon)
+		sb.WriteString(strings.Join(slices.Sorted(slices.Values(principal.Roles)), constants.Comma))
+	} else {
+		sb.WriteString(principal.ID)
+	}
+
+	sb.WriteByte(constants.ByteColon)
+
+	// Map keys are marshaled sorted, so equal params and meta yield equal digests
+	request, err := encoding.ToJSON(shared.Request(ctx))
+	if err != nil {
+		return constants.Empty, err
+	}
+
+	sb.WriteString(hashx.SHA256(ctx.OriginalURL() + request))
+
+	return sb.String(), nil
+}
+
+func sendCached(ctx fiber.Ctx, entry *cachedResponse, status string) error {
+	ctx.Set(constants.HeaderXCache, status)
+	ctx.Set(fiber.HeaderContentType, entry.ContentType)
+
+	return ctx.Status(entry.Status).Send(entry.Body)
+}
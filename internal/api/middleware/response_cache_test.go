@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/cache"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/internal/api/shared"
+	"github.com/ilxqx/vef-framework-go/internal/database/tabletag"
+	"github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+func newResponseCacheApp(cfg *api.CacheConfig, mid api.Middleware, calls *int) *fiber.App {
+	app := fiber.New()
+	app.Get("/api", func(ctx fiber.Ctx) error {
+		shared.SetOperation(ctx, &api.Operation{
+			Identifier: api.Identifier{Resource: "sys/user", Action: "find_page", Version: api.VersionV1},
+			Cache:      cfg,
+		})
+		shared.SetRequest(ctx, &api.Request{Params: api.Params{"keyword": ctx.Query("keyword")}})
+		contextx.SetLogger(ctx, log.Named("test"))
+		var roles []string
+		if header := ctx.Get("X-Roles"); header != constants.Empty {
+			roles = strings.Split(header, constants.Comma)
+		}
+
+		contextx.SetPrincipal(ctx, security.NewUser(ctx.Get("X-User"), ctx.Get("X-User"), roles...))
+
+		return ctx.Next()
+	}, mid.Process, func(ctx fiber.Ctx) error {
+		*calls++
+
+		return result.Ok(*calls).Response(ctx)
+	})
+
+	return app
+}
+
+func newMemoryResponseCache() api.Middleware {
+	return newResponseCache(tabletag.NewTracker(), cache.NewMemory[*cachedResponse](), cache.NewMemory[int64]())
+}
+
+func requestCached(t *testing.T, app *fiber.App, url, user string, roles ...string) string {
+	t.Helper()
+
+	req := httptest.NewRequest(fiber.MethodGet, url, nil)
+	req.Header.Set("X-User", user)
+	req.Header.Set("X-Roles", strings.Join(roles, constants.Comma))
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	return resp.Header.Get(constants.HeaderXCache)
+}
+
+func TestResponseCache(t *testing.T) {
+	t.Run("HitPerPrincipalAndParams", func(t *testing.T) {
+		var calls int
+
+		app := newResponseCacheApp(&api.CacheConfig{TTL: time.Minute}, newMemoryResponseCache(), &calls)
+
+		assert.Equal(t, cacheStatusMiss, requestCached(t, app, "/api?keyword=a", "alice"))
+		assert.Equal(t, cacheStatusHit, requestCached(t, app, "/api?keyword=a", "alice"))
+		assert.Equal(t, cacheStatusMiss, requestCached(t, app, "/api?keyword=b", "alice"), "Should key responses by params")
+		assert.Equal(t, cacheStatusMiss, requestCached(t, app, "/api?keyword=a", "bob"), "Should key responses by principal")
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("Shared", func(t *testing.T) {
+		var calls int
+
+		app := newResponseCacheApp(&api.CacheConfig{TTL: time.Minute, Shared: true}, newMemoryResponseCache(), &calls)
+
+		assert.Equal(t, cacheStatusMiss, requestCached(t, app, "/api", "alice", "editor", "viewer"))
+		assert.Equal(t, cacheStatusHit, requestCached(t, app, "/api", "bob", "viewer", "editor"), "Should share responses between principals of the same roles")
+		assert.Equal(t, cacheStatusMiss, requestCached(t, app, "/api", "carol", "viewer"), "Should key responses by roles, fields are filtered by their permissions")
+		assert.Equal(t, cacheStatusMiss, requestCached(t, app, "/api", "dave"))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("InvalidatedByTableWrite", func(t *testing.T) {
+		var calls int
+
+		mid := newMemoryResponseCache()
+		app := newResponseCacheApp(&api.CacheConfig{TTL: time.Minute, Tables: []string{"sys_user"}}, mid, &calls)
+
+		assert.Equal(t, cacheStatusMiss, requestCached(t, app, "/api", "alice"))
+		assert.Equal(t, cacheStatusHit, requestCached(t, app, "/api", "alice"))
+
+		mid.(*ResponseCache).invalidate(context.Background(), "sys_role")
+		assert.Equal(t, cacheStatusHit, requestCached(t, app, "/api", "alice"), "Should ignore writes to unrelated tables")
+
+		mid.(*ResponseCache).invalidate(context.Background(), "sys_user")
+		assert.Equal(t, cacheStatusMiss, requestCached(t, app, "/api", "alice"), "Should drop responses whose tables were written")
+		assert.Equal(t, cacheStatusHit, requestCached(t, app, "/api", "alice"))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("StaleWhileRevalidate", func(t *testing.T) {
+		var calls int
+
+		mid := newMemoryResponseCache()
+		app := newResponseCacheApp(&api.CacheConfig{TTL: 20 * time.Millisecond, StaleTTL: time.Minute}, mid, &calls)
+
+		assert.Equal(t, cacheStatusMiss, requestCached(t, app, "/api", "alice"))
+		time.Sleep(30 * time.Millisecond)
+
+		cache := mid.(*ResponseCache)
+		key := "sys/user:" + api.VersionV1 + ":find_page:alice:"
+
+		keys, err := cache.responses.Keys(context.Background(), key)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+
+		// Simulates a refresh in flight, concurrent requests are served the stale response meanwhile
+		cache.refreshing.Store(keys[0], struct{}{})
+		assert.Equal(t, cacheStatusStale, requestCached(t, app, "/api", "alice"))
+		cache.refreshing.Delete(keys[0])
+
+		assert.Equal(t, cacheStatusMiss, requestCached(t, app, "/api", "alice"), "Should refresh stale responses")
+		assert.Equal(t, cacheStatusHit, requestCached(t, app, "/api", "alice"))
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -69,9 +69,13 @@ func (r *REST) Route(handler fiber.Handler, op *api.Operation) {
 	op.Meta[shared.MetaKeyRESTHttpPath] = r.basePath + fullPath
 }
 
-// createResolver creates a middleware that parses request and sets operation in context.
+// createResolver creates a middleware that enforces the body limit, parses request and sets operation in context.
 func (r *REST) createResolver(op *api.Operation) fiber.Handler {
 	return func(ctx fiber.Ctx) error {
+		if err := middleware.CheckBodyLimit(ctx, op); err != nil {
+			return err
+		}
+
 		req, err := r.parseRequest(ctx, op)
 		if err != nil {
 			return err
@@ -1,8 +1,11 @@
 package router
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/hbollon/go-edlib"
@@ -23,6 +26,9 @@ const (
 	DefaultRPCEndpoint = "/api"
 	FormKeyParams      = "params"
 	FormKeyMeta        = "meta"
+
+	// maxIdentifierFieldSize caps the identifier fields read when peeking multipart bodies.
+	maxIdentifierFieldSize = 256
 )
 
 // RPC implements api.RouterStrategy for RPC-style single endpoint routing.
@@ -74,7 +80,16 @@ func (r *RPC) Setup(router fiber.Router) error {
 }
 
 // resolve is a middleware that parses the request and sets the operation in context.
+// The body limit of the operation is enforced before the body is parsed, the operation is found by peeking its identifier.
 func (r *RPC) resolve(ctx fiber.Ctx) error {
+	if identifier, ok := peekIdentifier(ctx); ok {
+		if entry, ok := r.operations.Get(identifier); ok {
+			if err := middleware.CheckBodyLimit(ctx, entry.op); err != nil {
+				return err
+			}
+		}
+	}
+
 	req, err := r.parseRequest(ctx)
 	if err != nil {
 		return err
@@ -208,6 +223,54 @@ func parseFormRequest(ctx fiber.Ctx, request *api.Request) error {
 	return nil
 }
 
+// peekIdentifier reads the identifier of the request without parsing its params:
+// JSON bodies are decoded into the identifier only and multipart bodies are read up to the identifier fields, skipping files.
+func peekIdentifier(ctx fiber.Ctx) (api.Identifier, bool) {
+	var identifier api.Identifier
+
+	switch {
+	case webhelpers.IsJSON(ctx):
+		if err := json.Unmarshal(ctx.Body(), &identifier); err != nil {
+			return identifier, false
+		}
+	case webhelpers.IsMultipart(ctx):
+		boundary := string(ctx.Request().Header.MultipartFormBoundary())
+		reader := multipart.NewReader(bytes.NewReader(ctx.Body()), boundary)
+
+		fields := map[string]*string{
+			"resource": &identifier.Resource,
+			"action":   &identifier.Action,
+			"version":  &identifier.Version,
+		}
+
+		for len(fields) > 0 {
+			part, err := reader.NextPart()
+			if err != nil {
+				return identifier, false
+			}
+
+			field, ok := fields[part.FormName()]
+			if !ok || part.FileName() != constants.Empty {
+				continue
+			}
+
+			value, err := io.ReadAll(io.LimitReader(part, maxIdentifierFieldSize))
+			if err != nil {
+				return identifier, false
+			}
+
+			*field = string(value)
+			delete(fields, part.FormName())
+		}
+	default:
+		identifier.Resource = ctx.FormValue("resource")
+		identifier.Action = ctx.FormValue("action")
+		identifier.Version = ctx.FormValue("version")
+	}
+
+	return identifier, true
+}
+
 func identifierToString(identifier api.Identifier) string {
 	return fmt.Sprintf("%s/%s@%s", identifier.Resource, identifier.Action, identifier.Version)
 }
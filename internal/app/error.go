@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofiber/fiber/v3"
 
+	"github.com/ilxqx/vef-framework-go/bizerror"
 	"github.com/ilxqx/vef-framework-go/contextx"
 	"github.com/ilxqx/vef-framework-go/i18n"
 	"github.com/ilxqx/vef-framework-go/result"
@@ -38,6 +39,10 @@ var fiberErrorMappings = map[int]fiberErrorMapping{
 		code:    result.ErrCodeRequestTimeout,
 		message: result.ErrMessageRequestTimeout,
 	},
+	fiber.StatusRequestEntityTooLarge: {
+		code:    result.ErrCodeRequestEntityTooLarge,
+		message: result.ErrMessageRequestEntityTooLarge,
+	},
 }
 
 // handleError handles the error and returns the response.
@@ -69,6 +74,14 @@ func handleError(ctx fiber.Ctx, err error) error {
 	}
 
 	if resultErr, ok := result.AsErr(err); ok {
+		// The cause of a domain error is hidden from clients, keep it in the logs
+		if bizErr, ok := bizerror.As(err); ok && bizErr.Unwrap() != nil {
+			contextx.Logger(ctx).Warnf(
+				"Business error: code=%d, error=%v",
+				bizErr.Code(), err,
+			)
+		}
+
 		return responseError(resultErr, ctx)
 	}
 
@@ -10,19 +10,27 @@ import (
 	"github.com/ilxqx/vef-framework-go/config"
 	"github.com/ilxqx/vef-framework-go/internal/api"
 	"github.com/ilxqx/vef-framework-go/internal/app"
+	"github.com/ilxqx/vef-framework-go/internal/calendar"
 	iconfig "github.com/ilxqx/vef-framework-go/internal/config"
 	"github.com/ilxqx/vef-framework-go/internal/cron"
 	"github.com/ilxqx/vef-framework-go/internal/database"
+	"github.com/ilxqx/vef-framework-go/internal/datasync"
+	"github.com/ilxqx/vef-framework-go/internal/delayjob"
 	"github.com/ilxqx/vef-framework-go/internal/event"
+	"github.com/ilxqx/vef-framework-go/internal/integrity"
 	"github.com/ilxqx/vef-framework-go/internal/mcp"
+	"github.com/ilxqx/vef-framework-go/internal/menu"
+	"github.com/ilxqx/vef-framework-go/internal/metering"
 	"github.com/ilxqx/vef-framework-go/internal/middleware"
 	"github.com/ilxqx/vef-framework-go/internal/mold"
 	"github.com/ilxqx/vef-framework-go/internal/monitor"
 	"github.com/ilxqx/vef-framework-go/internal/orm"
 	"github.com/ilxqx/vef-framework-go/internal/redis"
+	"github.com/ilxqx/vef-framework-go/internal/report"
 	"github.com/ilxqx/vef-framework-go/internal/schema"
 	"github.com/ilxqx/vef-framework-go/internal/security"
 	"github.com/ilxqx/vef-framework-go/internal/storage"
+	"github.com/ilxqx/vef-framework-go/internal/tenancy"
 )
 
 // MockConfig implements config.Config for testing without file dependencies.
@@ -85,12 +93,20 @@ func buildOptions(options ...fx.Option) []fx.Option {
 		),
 		iconfig.Module,
 		database.Module,
+		tenancy.Module,
 		orm.Module,
 		middleware.Module,
 		api.Module,
 		security.Module,
 		event.Module,
 		cron.Module,
+		delayjob.Module,
+		metering.Module,
+		report.Module,
+		integrity.Module,
+		datasync.Module,
+		calendar.Module,
+		menu.Module,
 		redis.Module,
 		mold.Module,
 		storage.Module,
@@ -0,0 +1,27 @@
+package calendar
+
+import (
+	"go.uber.org/fx"
+
+	"github.com/ilxqx/vef-framework-go/calendar"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Module provides the business calendar backed by the holiday table.
+// Applications can supply their own calendar.HolidayLoader to read holidays from another source.
+var Module = fx.Module(
+	"vef:calendar",
+	fx.Provide(
+		fx.Annotate(
+			func(loader calendar.HolidayLoader, db orm.DB, bus event.Bus) calendar.Calendar {
+				if loader == nil {
+					loader = calendar.NewDBHolidayLoader(db)
+				}
+
+				return calendar.New(loader, bus)
+			},
+			fx.ParamTags(`optional:"true"`),
+		),
+	),
+)
@@ -21,6 +21,7 @@ var (
 		c.DecodeHook = mapx.DecoderHook
 	}
 	configName = "application"
+	logger     = ilog.Named("config")
 	configDir  = "configs"
 )
 
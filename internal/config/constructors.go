@@ -24,10 +24,42 @@ func newDatasourceConfig(cfg config.Config) (*config.DatasourceConfig, error) {
 	return unmarshalConfig(cfg, "vef.datasource", new(config.DatasourceConfig))
 }
 
+func newTenancyConfig(cfg config.Config) (*config.TenancyConfig, error) {
+	return unmarshalConfig(cfg, "vef.tenancy", new(config.TenancyConfig))
+}
+
 func newCorsConfig(cfg config.Config) (*config.CorsConfig, error) {
 	return unmarshalConfig(cfg, "vef.cors", new(config.CorsConfig))
 }
 
+func newHeadersConfig(cfg config.Config) (*config.HeadersConfig, error) {
+	return unmarshalConfig(cfg, "vef.headers", new(config.HeadersConfig))
+}
+
+func newCaptureConfig(cfg config.Config) (*config.CaptureConfig, error) {
+	return unmarshalConfig(cfg, "vef.capture", new(config.CaptureConfig))
+}
+
+func newDelayJobConfig(cfg config.Config) (*config.DelayJobConfig, error) {
+	return unmarshalConfig(cfg, "vef.delay_job", new(config.DelayJobConfig))
+}
+
+func newMeteringConfig(cfg config.Config) (*config.MeteringConfig, error) {
+	return unmarshalConfig(cfg, "vef.metering", new(config.MeteringConfig))
+}
+
+func newReportConfig(cfg config.Config) (*config.ReportConfig, error) {
+	return unmarshalConfig(cfg, "vef.report", new(config.ReportConfig))
+}
+
+func newIntegrityConfig(cfg config.Config) (*config.IntegrityConfig, error) {
+	return unmarshalConfig(cfg, "vef.integrity", new(config.IntegrityConfig))
+}
+
+func newSyncConfig(cfg config.Config) (*config.SyncConfig, error) {
+	return unmarshalConfig(cfg, "vef.sync", new(config.SyncConfig))
+}
+
 func newSecurityConfig(cfg config.Config) (*config.SecurityConfig, error) {
 	return unmarshalConfig(cfg, "vef.security", new(config.SecurityConfig))
 }
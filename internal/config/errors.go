@@ -0,0 +1,6 @@
+package config
+
+import "errors"
+
+// ErrUnsupportedConfig is returned when tenant overrides are requested for a config not backed by viper.
+var ErrUnsupportedConfig = errors.New("tenant overrides are not supported by the config")
@@ -10,11 +10,23 @@ var Module = fx.Module(
 		newConfig,
 		newAppConfig,
 		newDatasourceConfig,
+		newTenancyConfig,
 		newCorsConfig,
+		newHeadersConfig,
+		newCaptureConfig,
+		newDelayJobConfig,
+		newMeteringConfig,
+		newReportConfig,
+		newIntegrityConfig,
+		newSyncConfig,
 		newSecurityConfig,
 		newRedisConfig,
 		newStorageConfig,
 		newMonitorConfig,
 		newMcpConfig,
+		fx.Annotate(
+			newTenantConfig,
+			fx.ParamTags(``, `optional:"true"`),
+		),
 	),
 )
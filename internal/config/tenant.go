@@ -0,0 +1,196 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+
+	"github.com/ilxqx/vef-framework-go/cache"
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/contextx"
+)
+
+const (
+	// tenantsKey is the configuration section holding static per-tenant overrides, e.g. [tenants.acme.features].
+	tenantsKey = "tenants"
+	// featuresKey is the configuration section holding the feature flags.
+	featuresKey = "features"
+	// defaultTenantOverridesTTL bounds how long loaded overrides are served before being loaded again.
+	defaultTenantOverridesTTL = 5 * time.Minute
+)
+
+// TenantConfig resolves configuration values with the static overrides of the tenants section
+// and those of the loader on top, caching the overrides of each tenant.
+type TenantConfig struct {
+	v         *viper.Viper
+	loader    config.TenantOverrideLoader
+	overrides cache.Cache[map[string]any]
+}
+
+func newTenantConfig(cfg config.Config, loader config.TenantOverrideLoader) (config.TenantConfig, error) {
+	vc, ok := cfg.(*ViperConfig)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedConfig, cfg)
+	}
+
+	return NewTenantConfig(vc.v, loader), nil
+}
+
+// NewTenantConfig creates a tenant config over the configuration, loader may be nil.
+func NewTenantConfig(v *viper.Viper, loader config.TenantOverrideLoader) *TenantConfig {
+	return &TenantConfig{
+		v:         v,
+		loader:    loader,
+		overrides: cache.NewMemory[map[string]any](cache.WithMemDefaultTTL(defaultTenantOverridesTTL)),
+	}
+}
+
+func (c *TenantConfig) Unmarshal(ctx context.Context, key string, target any) error {
+	value, err := c.resolve(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	decoderConfig := &mapstructure.DecoderConfig{
+		Result:           target,
+		WeaklyTypedInput: true,
+	}
+	decodeUsingConfigTagOption(decoderConfig)
+
+	decoder, err := mapstructure.NewDecoder(decoderConfig)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(value)
+}
+
+func (c *TenantConfig) FeatureEnabled(ctx context.Context, feature string) bool {
+	value, err := c.resolve(ctx, featuresKey+constants.Dot+feature)
+	if err != nil {
+		logger.Warnf("Failed to resolve feature %s, treating it as disabled: %v", feature, err)
+
+		return false
+	}
+
+	enabled, _ := cast.ToBoolE(value)
+
+	return enabled
+}
+
+func (c *TenantConfig) Invalidate(ctx context.Context, tenantID string) error {
+	return c.overrides.Delete(ctx, tenantID)
+}
+
+// resolve returns the value at key with the overrides of the tenant of the context merged on top.
+func (c *TenantConfig) resolve(ctx context.Context, key string) (any, error) {
+	key = strings.ToLower(key)
+	value := c.v.Get(key)
+
+	tenantID := contextx.TenantID(ctx)
+	if tenantID == constants.Empty {
+		return value, nil
+	}
+
+	overrides, err := c.overrides.GetOrLoad(ctx, tenantID, func(ctx context.Context) (map[string]any, error) {
+		return c.load(ctx, tenantID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if override, ok := lookup(overrides, key); ok {
+		return merge(value, override), nil
+	}
+
+	return value, nil
+}
+
+// load returns the static overrides of the tenant with those of the loader merged on top.
+func (c *TenantConfig) load(ctx context.Context, tenantID string) (map[string]any, error) {
+	overrides := normalize(c.v.GetStringMap(tenantsKey + constants.Dot + strings.ToLower(tenantID)))
+
+	if c.loader != nil {
+		loaded, err := c.loader.Load(ctx, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config overrides of tenant %s: %w", tenantID, err)
+		}
+
+		overrides, _ = merge(overrides, normalize(loaded)).(map[string]any)
+	}
+
+	return overrides, nil
+}
+
+// lookup returns the value at the dotted key of the nested map.
+func lookup(values map[string]any, key string) (any, bool) {
+	var current any = values
+	for part := range strings.SplitSeq(key, constants.Dot) {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// merge returns the override merged onto the base: nested maps are merged key by key, other values replaced.
+func merge(base, override any) any {
+	baseMap, ok := base.(map[string]any)
+	if !ok {
+		return override
+	}
+
+	overrideMap, ok := override.(map[string]any)
+	if !ok {
+		return override
+	}
+
+	merged := maps.Clone(baseMap)
+	for key, value := range overrideMap {
+		merged[key] = merge(merged[key], value)
+	}
+
+	return merged
+}
+
+// normalize lowercases the keys of the nested map as viper does, so overrides match the configuration keys.
+// Dotted keys are expanded into nested maps.
+func normalize(values map[string]any) map[string]any {
+	normalized := make(map[string]any, len(values))
+	for key, value := range values {
+		if nested, ok := value.(map[string]any); ok {
+			value = normalize(nested)
+		}
+
+		parts := strings.Split(strings.ToLower(key), constants.Dot)
+		target := normalized
+
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := target[part].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				target[part] = next
+			}
+
+			target = next
+		}
+
+		last := parts[len(parts)-1]
+		target[last] = merge(target[last], value)
+	}
+
+	return normalized
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/contextx"
+)
+
+const tenantTestConfig = `
+[vef.app]
+name = "shop"
+port = 8080
+
+[features]
+export = false
+reports = true
+
+[tenants.acme.vef.app]
+port = 9090
+
+[tenants.acme.features]
+export = true
+`
+
+func newTenantTestConfig(t *testing.T, loader config.TenantOverrideLoader) *TenantConfig {
+	t.Helper()
+
+	v := viper.New()
+	v.SetConfigType("toml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(tenantTestConfig)))
+
+	return NewTenantConfig(v, loader)
+}
+
+type tenantAppConfig struct {
+	Name string `config:"name"`
+	Port int    `config:"port"`
+}
+
+func TestTenantConfig(t *testing.T) {
+	ctx := context.Background()
+	acme := contextx.SetTenantID(ctx, "acme")
+	globex := contextx.SetTenantID(ctx, "globex")
+
+	t.Run("StaticOverrides", func(t *testing.T) {
+		cfg := newTenantTestConfig(t, nil)
+
+		var app tenantAppConfig
+
+		require.NoError(t, cfg.Unmarshal(ctx, "vef.app", &app))
+		assert.Equal(t, tenantAppConfig{Name: "shop", Port: 8080}, app, "Should not override requests without tenant")
+
+		require.NoError(t, cfg.Unmarshal(acme, "vef.app", &app))
+		assert.Equal(t, tenantAppConfig{Name: "shop", Port: 9090}, app, "Should merge the overrides of the tenant")
+
+		require.NoError(t, cfg.Unmarshal(globex, "vef.app", &app))
+		assert.Equal(t, tenantAppConfig{Name: "shop", Port: 8080}, app, "Should not override tenants without overrides")
+
+		assert.False(t, cfg.FeatureEnabled(ctx, "export"))
+		assert.True(t, cfg.FeatureEnabled(acme, "export"), "Should enable features per tenant")
+		assert.True(t, cfg.FeatureEnabled(acme, "reports"), "Should inherit features not overridden")
+		assert.False(t, cfg.FeatureEnabled(acme, "unknown"), "Should disable unknown features")
+	})
+
+	t.Run("LoaderWithInvalidation", func(t *testing.T) {
+		loads := 0
+		reports := false
+
+		cfg := newTenantTestConfig(t, config.TenantOverrideLoaderFunc(func(_ context.Context, tenantID string) (map[string]any, error) {
+			loads++
+
+			if tenantID != "acme" {
+				return nil, nil
+			}
+
+			return map[string]any{
+				"Features.Reports": reports,
+				"vef":              map[string]any{"App": map[string]any{"Name": "acme shop"}},
+			}, nil
+		}))
+
+		var app tenantAppConfig
+
+		require.NoError(t, cfg.Unmarshal(acme, "vef.app", &app))
+		assert.Equal(t, tenantAppConfig{Name: "acme shop", Port: 9090}, app, "Should merge loaded overrides onto the static ones")
+		assert.False(t, cfg.FeatureEnabled(acme, "reports"), "Should apply dotted keys of loaded overrides")
+		assert.True(t, cfg.FeatureEnabled(acme, "export"))
+		assert.Equal(t, 1, loads, "Should cache the overrides of the tenant")
+
+		reports = true
+		assert.False(t, cfg.FeatureEnabled(acme, "reports"), "Should serve cached overrides")
+
+		require.NoError(t, cfg.Invalidate(ctx, "acme"))
+		assert.True(t, cfg.FeatureEnabled(acme, "reports"), "Should reload invalidated overrides")
+		assert.Equal(t, 2, loads)
+	})
+}
@@ -0,0 +1,157 @@
+package cron
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/cron"
+	"github.com/ilxqx/vef-framework-go/i18n"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+const (
+	permTokenCronQuery   = "sys.cron.query"
+	permTokenCronExecute = "sys.cron.execute"
+
+	// nextRunCount is the number of upcoming run times returned per job.
+	nextRunCount = 5
+)
+
+// NewResource creates the cron resource exposing the registered jobs, their executions and manual controls.
+func NewResource(scheduler cron.Scheduler) api.Resource {
+	return &Resource{
+		scheduler: scheduler,
+		Resource: api.NewRPCResource(
+			"sys/cron",
+			api.WithOperations(
+				api.OperationSpec{Action: "get_jobs", PermToken: permTokenCronQuery},
+				api.OperationSpec{Action: "get_executions", PermToken: permTokenCronQuery},
+				api.OperationSpec{Action: "run_job", PermToken: permTokenCronExecute, EnableAudit: true},
+				api.OperationSpec{Action: "abort_job", PermToken: permTokenCronExecute, EnableAudit: true},
+			),
+		),
+	}
+}
+
+// Resource handles cron Api endpoints.
+type Resource struct {
+	api.Resource
+
+	scheduler cron.Scheduler
+}
+
+// JobInfo describes a registered job for dashboards.
+type JobInfo struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Tags          []string        `json:"tags"`
+	Schedule      string          `json:"schedule"`
+	Running       bool            `json:"running"`
+	LastRun       *time.Time      `json:"lastRun"`
+	NextRuns      []time.Time     `json:"nextRuns"`
+	LastExecution *cron.Execution `json:"lastExecution"`
+}
+
+// GetJobs returns the registered jobs ordered by name with their upcoming runs and latest execution.
+func (r *Resource) GetJobs(ctx fiber.Ctx) error {
+	jobs := r.scheduler.Jobs()
+	infos := make([]JobInfo, 0, len(jobs))
+
+	for _, job := range jobs {
+		info := JobInfo{
+			ID:       job.ID(),
+			Name:     job.Name(),
+			Tags:     job.Tags(),
+			Schedule: job.Schedule(),
+			NextRuns: []time.Time{},
+		}
+
+		if lastRun, err := job.LastRun(); err == nil && !lastRun.IsZero() {
+			info.LastRun = &lastRun
+		}
+
+		if nextRuns, err := job.NextRuns(nextRunCount); err == nil {
+			info.NextRuns = slices.DeleteFunc(nextRuns, time.Time.IsZero)
+		}
+
+		if executions := job.Executions(); len(executions) > 0 {
+			info.LastExecution = &executions[0]
+			info.Running = executions[0].Status == cron.ExecutionStatusRunning
+		}
+
+		infos = append(infos, info)
+	}
+
+	slices.SortFunc(infos, func(a, b JobInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result.Ok(infos).Response(ctx)
+}
+
+// JobParams contains the id of the job to operate on.
+type JobParams struct {
+	api.P
+
+	JobID string `json:"jobId" validate:"required"`
+}
+
+// GetExecutions returns the running and the last finished executions of a job, newest first.
+func (r *Resource) GetExecutions(ctx fiber.Ctx, params JobParams) error {
+	job, err := r.scheduler.Job(params.JobID)
+	if err != nil {
+		return mapJobError(err)
+	}
+
+	return result.Ok(job.Executions()).Response(ctx)
+}
+
+// RunJob triggers an immediate run of a job without affecting its schedule.
+func (r *Resource) RunJob(ctx fiber.Ctx, params JobParams) error {
+	job, err := r.scheduler.Job(params.JobID)
+	if err != nil {
+		return mapJobError(err)
+	}
+
+	if err := job.RunNow(); err != nil {
+		return err
+	}
+
+	return result.Ok().Response(ctx)
+}
+
+// AbortJob cancels the running executions of a job.
+func (r *Resource) AbortJob(ctx fiber.Ctx, params JobParams) error {
+	job, err := r.scheduler.Job(params.JobID)
+	if err != nil {
+		return mapJobError(err)
+	}
+
+	if err := job.Abort(); err != nil {
+		return mapJobError(err)
+	}
+
+	return result.Ok().Response(ctx)
+}
+
+func mapJobError(err error) error {
+	switch {
+	case errors.Is(err, cron.ErrJobNotFound):
+		return result.Err(
+			i18n.T(result.ErrMessageCronJobNotFound),
+			result.WithCode(result.ErrCodeCronJobNotFound),
+		)
+	case errors.Is(err, cron.ErrJobNotRunning):
+		return result.Err(
+			i18n.T(result.ErrMessageCronJobNotRunning),
+			result.WithCode(result.ErrCodeCronJobNotRunning),
+		)
+	default:
+		return err
+	}
+}
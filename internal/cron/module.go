@@ -11,4 +11,10 @@ var Module = fx.Module(
 	"vef:cron",
 	fx.Provide(newScheduler, fx.Private),
 	fx.Provide(cron.NewScheduler),
+	fx.Provide(
+		fx.Annotate(
+			NewResource,
+			fx.ResultTags(`group:"vef:api:resources"`),
+		),
+	),
 )
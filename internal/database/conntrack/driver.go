@@ -0,0 +1,346 @@
+package conntrack
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+)
+
+var (
+	errNamedParamsUnsupported = errors.New("sql: driver does not support the use of Named Parameters")
+	errIsolationUnsupported   = errors.New("sql: driver does not support non-default isolation level")
+	errReadOnlyUnsupported    = errors.New("sql: driver does not support read-only transactions")
+)
+
+// WrapConnector returns a connector whose connections record result set and transaction checkouts.
+// The connector is returned unchanged when leak detection is disabled.
+func (t *Tracker) WrapConnector(connector driver.Connector) driver.Connector {
+	if !t.config.Enabled {
+		return connector
+	}
+
+	return &trackedConnector{Connector: connector, tracker: t}
+}
+
+type trackedConnector struct {
+	driver.Connector
+
+	tracker *Tracker
+}
+
+func (c *trackedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &trackedConn{Conn: conn, tracker: c.tracker}, nil
+}
+
+// Close closes the underlying connector if it holds resources, called by sql.DB.Close.
+func (c *trackedConnector) Close() error {
+	if closer, ok := c.Connector.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// trackedConn forwards to the driver connection and falls back like database/sql
+// when the driver lacks an optional interface.
+type trackedConn struct {
+	driver.Conn
+
+	tracker *Tracker
+}
+
+func (c *trackedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *trackedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &trackedStmt{Stmt: stmt, query: query, tracker: c.tracker}, nil
+}
+
+//nolint:staticcheck // Begin must be forwarded for drivers without BeginTx
+func (c *trackedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *trackedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var (
+		tx  driver.Tx
+		err error
+	)
+
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = beginner.BeginTx(ctx, opts)
+	} else {
+		if opts.Isolation != driver.IsolationLevel(0) {
+			return nil, errIsolationUnsupported
+		}
+
+		if opts.ReadOnly {
+			return nil, errReadOnlyUnsupported
+		}
+
+		//nolint:staticcheck // Fallback for drivers without BeginTx
+		tx, err = c.Conn.Begin()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &trackedTx{Tx: tx, release: c.tracker.acquire(KindTx, "")}, nil
+}
+
+func (c *trackedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	var (
+		rows driver.Rows
+		err  error
+	)
+
+	switch queryer := c.Conn.(type) {
+	case driver.QueryerContext:
+		rows, err = queryer.QueryContext(ctx, query, args)
+	case driver.Queryer: //nolint:staticcheck // Fallback for drivers without QueryerContext
+		values, convertErr := namedValuesToValues(args)
+		if convertErr != nil {
+			return nil, convertErr
+		}
+
+		rows, err = queryer.Query(query, values)
+	default:
+		return nil, driver.ErrSkip
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newTrackedRows(rows, c.tracker.acquire(KindRows, query)), nil
+}
+
+func (c *trackedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	switch execer := c.Conn.(type) {
+	case driver.ExecerContext:
+		return execer.ExecContext(ctx, query, args)
+	case driver.Execer: //nolint:staticcheck // Fallback for drivers without ExecerContext
+		values, err := namedValuesToValues(args)
+		if err != nil {
+			return nil, err
+		}
+
+		return execer.Exec(query, values)
+	default:
+		return nil, driver.ErrSkip
+	}
+}
+
+func (c *trackedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *trackedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *trackedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+
+	return true
+}
+
+func (c *trackedConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+
+	return driver.ErrSkip
+}
+
+type trackedStmt struct {
+	driver.Stmt
+
+	query   string
+	tracker *Tracker
+}
+
+func (s *trackedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return execer.ExecContext(ctx, args)
+	}
+
+	values, err := namedValuesToValues(args)
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:staticcheck // Fallback for statements without ExecContext
+	return s.Stmt.Exec(values)
+}
+
+func (s *trackedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	var (
+		rows driver.Rows
+		err  error
+	)
+
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		values, convertErr := namedValuesToValues(args)
+		if convertErr != nil {
+			return nil, convertErr
+		}
+
+		//nolint:staticcheck // Fallback for statements without QueryContext
+		rows, err = s.Stmt.Query(values)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newTrackedRows(rows, s.tracker.acquire(KindRows, s.query)), nil
+}
+
+func (s *trackedStmt) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+
+	return driver.ErrSkip
+}
+
+type trackedTx struct {
+	driver.Tx
+
+	release func()
+}
+
+func (tx *trackedTx) Commit() error {
+	defer tx.release()
+
+	return tx.Tx.Commit()
+}
+
+func (tx *trackedTx) Rollback() error {
+	defer tx.release()
+
+	return tx.Tx.Rollback()
+}
+
+// trackedRows releases its checkout on Close and forwards the optional column type interfaces.
+type trackedRows struct {
+	driver.Rows
+
+	release func()
+}
+
+func newTrackedRows(rows driver.Rows, release func()) *trackedRows {
+	return &trackedRows{Rows: rows, release: release}
+}
+
+func (r *trackedRows) Close() error {
+	defer r.release()
+
+	return r.Rows.Close()
+}
+
+func (r *trackedRows) HasNextResultSet() bool {
+	if next, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return next.HasNextResultSet()
+	}
+
+	return false
+}
+
+func (r *trackedRows) NextResultSet() error {
+	if next, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return next.NextResultSet()
+	}
+
+	return io.EOF
+}
+
+func (r *trackedRows) ColumnTypeScanType(index int) reflect.Type {
+	if typed, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return typed.ColumnTypeScanType(index)
+	}
+
+	return reflect.TypeFor[any]()
+}
+
+func (r *trackedRows) ColumnTypeDatabaseTypeName(index int) string {
+	if typed, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return typed.ColumnTypeDatabaseTypeName(index)
+	}
+
+	return ""
+}
+
+func (r *trackedRows) ColumnTypeLength(index int) (int64, bool) {
+	if typed, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return typed.ColumnTypeLength(index)
+	}
+
+	return 0, false
+}
+
+func (r *trackedRows) ColumnTypeNullable(index int) (bool, bool) {
+	if typed, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return typed.ColumnTypeNullable(index)
+	}
+
+	return false, false
+}
+
+func (r *trackedRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if typed, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return typed.ColumnTypePrecisionScale(index)
+	}
+
+	return 0, 0, false
+}
+
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errNamedParamsUnsupported
+		}
+
+		values[i] = arg.Value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,241 @@
+// Package conntrack detects connection leaks by wrapping the database driver.
+//
+// A pooled connection stays checked out while a result set or a transaction is open on it,
+// so the tracker records the stack trace of every query returning rows and of every transaction
+// begin, and releases the record on Rows.Close, Commit or Rollback. Records held longer than the
+// threshold are reported once as potential leaks and exposed as current holders for diagnostics.
+package conntrack
+
+import (
+	"cmp"
+	"context"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/log"
+	"github.com/ilxqx/vef-framework-go/monitor"
+)
+
+const (
+	// KindRows marks a connection held by an open result set.
+	KindRows = "rows"
+	// KindTx marks a connection held by an open transaction.
+	KindTx = "tx"
+
+	// DefaultHoldThreshold is the default duration after which a held connection is reported as a potential leak.
+	DefaultHoldThreshold = 30 * time.Second
+
+	// maxStackDepth bounds the number of frames recorded per checkout.
+	maxStackDepth = 64
+	// minCheckInterval bounds how often the watchdog scans the holders.
+	minCheckInterval = time.Second
+)
+
+// ignoredFramePrefixes are the functions skipped in recorded stacks so that the application call site comes first.
+var ignoredFramePrefixes = []string{
+	"runtime.",
+	"database/sql.",
+	"github.com/uptrace/bun.",
+	"github.com/ilxqx/vef-framework-go/internal/database/conntrack.(*Tracker)",
+	"github.com/ilxqx/vef-framework-go/internal/database/conntrack.(*tracked",
+}
+
+// holder is a connection checkout recorded by the tracker.
+type holder struct {
+	id         uint64
+	kind       string
+	query      string
+	acquiredAt time.Time
+	stack      string
+	reported   bool
+}
+
+// Tracker records connection checkouts and reports those held longer than the threshold.
+// It implements monitor.ConnectionLeakDetector.
+type Tracker struct {
+	config *config.LeakDetectionConfig
+	logger log.Logger
+	now    func() time.Time
+
+	nextID  atomic.Uint64
+	mu      sync.Mutex
+	holders map[uint64]*holder
+
+	watchdogCancel context.CancelFunc
+	watchdogDone   chan struct{}
+}
+
+// NewTracker creates a tracker; the driver is only wrapped and checkouts only recorded when enabled.
+func NewTracker(cfg *config.LeakDetectionConfig, logger log.Logger) *Tracker {
+	cfgToUse := *cfg
+	if cfgToUse.HoldThreshold <= 0 {
+		cfgToUse.HoldThreshold = DefaultHoldThreshold
+	}
+
+	return &Tracker{
+		config:  &cfgToUse,
+		logger:  logger,
+		now:     time.Now,
+		holders: make(map[uint64]*holder),
+	}
+}
+
+// Enabled reports whether leak detection is enabled.
+func (t *Tracker) Enabled() bool {
+	return t.config.Enabled
+}
+
+// Start starts the watchdog reporting connections held longer than the threshold.
+func (t *Tracker) Start() {
+	if !t.config.Enabled || t.watchdogCancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.watchdogCancel = cancel
+	t.watchdogDone = make(chan struct{})
+
+	go t.runWatchdog(ctx)
+}
+
+// Stop stops the watchdog.
+func (t *Tracker) Stop() {
+	if t.watchdogCancel == nil {
+		return
+	}
+
+	t.watchdogCancel()
+	<-t.watchdogDone
+	t.watchdogCancel = nil
+}
+
+func (t *Tracker) runWatchdog(ctx context.Context) {
+	defer close(t.watchdogDone)
+
+	ticker := time.NewTicker(max(t.config.HoldThreshold/2, minCheckInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.reportLeaks()
+		}
+	}
+}
+
+// reportLeaks warns once about every checkout held longer than the threshold.
+func (t *Tracker) reportLeaks() {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, h := range t.holders {
+		held := now.Sub(h.acquiredAt)
+		if h.reported || held < t.config.HoldThreshold {
+			continue
+		}
+
+		h.reported = true
+
+		if h.kind == KindRows {
+			t.logger.Warnf("Rows not closed after %s, connection is still held: %s\n%s", held.Truncate(time.Millisecond), h.query, h.stack)
+		} else {
+			t.logger.Warnf("Transaction open for %s, connection is still held\n%s", held.Truncate(time.Millisecond), h.stack)
+		}
+	}
+}
+
+// acquire records a checkout with the caller's stack trace and returns the function releasing it.
+// The release function is idempotent.
+func (t *Tracker) acquire(kind, query string) func() {
+	h := &holder{
+		id:         t.nextID.Add(1),
+		kind:       kind,
+		query:      query,
+		acquiredAt: t.now(),
+		stack:      captureStack(),
+	}
+
+	t.mu.Lock()
+	t.holders[h.id] = h
+	t.mu.Unlock()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			delete(t.holders, h.id)
+			t.mu.Unlock()
+		})
+	}
+}
+
+// ConnectionHolders returns the current connection holders, longest held first.
+func (t *Tracker) ConnectionHolders(context.Context) (*monitor.ConnectionHoldersReport, error) {
+	report := &monitor.ConnectionHoldersReport{
+		Enabled:         t.config.Enabled,
+		HoldThresholdMs: t.config.HoldThreshold.Milliseconds(),
+		Holders:         []monitor.ConnectionHolder{},
+	}
+
+	now := t.now()
+
+	t.mu.Lock()
+	for _, h := range t.holders {
+		held := now.Sub(h.acquiredAt)
+		report.Holders = append(report.Holders, monitor.ConnectionHolder{
+			ID:         h.id,
+			Kind:       h.kind,
+			Query:      h.query,
+			AcquiredAt: h.acquiredAt.UnixMilli(),
+			HeldMs:     held.Milliseconds(),
+			Leaked:     held >= t.config.HoldThreshold,
+			Stack:      h.stack,
+		})
+	}
+	t.mu.Unlock()
+
+	slices.SortFunc(report.Holders, func(a, b monitor.ConnectionHolder) int {
+		return cmp.Or(cmp.Compare(a.AcquiredAt, b.AcquiredAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return report, nil
+}
+
+// captureStack formats the current call stack without runtime, database/sql, bun and tracker frames.
+func captureStack() string {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var sb strings.Builder
+	for {
+		frame, more := frames.Next()
+		if !slices.ContainsFunc(ignoredFramePrefixes, func(prefix string) bool {
+			return strings.HasPrefix(frame.Function, prefix)
+		}) {
+			sb.WriteString(frame.Function)
+			sb.WriteString("\n\t")
+			sb.WriteString(frame.File)
+			sb.WriteByte(':')
+			sb.WriteString(strconv.Itoa(frame.Line))
+			sb.WriteByte('\n')
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,125 @@
+package conntrack
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlite"
+	ilog "github.com/ilxqx/vef-framework-go/internal/log"
+)
+
+type TrackerTestSuite struct {
+	suite.Suite
+
+	ctx     context.Context
+	tracker *Tracker
+	db      *sql.DB
+}
+
+func (suite *TrackerTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.tracker = NewTracker(&config.LeakDetectionConfig{Enabled: true, HoldThreshold: time.Minute}, ilog.Named("conntrack"))
+
+	connector, _, err := sqlite.NewProvider().Connect(&config.DatasourceConfig{Type: constants.SQLite})
+	suite.Require().NoError(err)
+
+	suite.db = sql.OpenDB(suite.tracker.WrapConnector(connector))
+	_, err = suite.db.ExecContext(suite.ctx, "CREATE TABLE IF NOT EXISTS test_item (id INTEGER PRIMARY KEY, name TEXT)")
+	suite.Require().NoError(err)
+}
+
+func (suite *TrackerTestSuite) TearDownTest() {
+	suite.Require().NoError(suite.db.Close())
+}
+
+func (suite *TrackerTestSuite) holders() int {
+	report, err := suite.tracker.ConnectionHolders(suite.ctx)
+	suite.Require().NoError(err)
+
+	return len(report.Holders)
+}
+
+func (suite *TrackerTestSuite) TestRowsHolder() {
+	rows, err := suite.db.QueryContext(suite.ctx, "SELECT id, name FROM test_item")
+	suite.Require().NoError(err)
+
+	report, err := suite.tracker.ConnectionHolders(suite.ctx)
+	suite.Require().NoError(err)
+	suite.True(report.Enabled)
+	suite.Require().Len(report.Holders, 1, "Open rows should hold a connection")
+
+	holder := report.Holders[0]
+	suite.Equal(KindRows, holder.Kind)
+	suite.Equal("SELECT id, name FROM test_item", holder.Query)
+	suite.Contains(holder.Stack, "TestRowsHolder", "Stack should start at the caller")
+	suite.NotContains(holder.Stack, "database/sql.", "Stack should skip database/sql frames")
+	suite.False(holder.Leaked)
+
+	suite.Require().NoError(rows.Close())
+	suite.Equal(0, suite.holders(), "Closing rows should release the connection")
+}
+
+func (suite *TrackerTestSuite) TestQueryRowReleases() {
+	var count int
+	suite.Require().NoError(suite.db.QueryRowContext(suite.ctx, "SELECT COUNT(*) FROM test_item").Scan(&count))
+	suite.Equal(0, suite.holders(), "Scanning a single row should release the connection")
+}
+
+func (suite *TrackerTestSuite) TestTxHolder() {
+	tx, err := suite.db.BeginTx(suite.ctx, nil)
+	suite.Require().NoError(err)
+
+	report, err := suite.tracker.ConnectionHolders(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(report.Holders, 1, "Open transaction should hold a connection")
+	suite.Equal(KindTx, report.Holders[0].Kind)
+
+	_, err = tx.ExecContext(suite.ctx, "INSERT INTO test_item (name) VALUES ('a')")
+	suite.Require().NoError(err)
+	suite.Require().NoError(tx.Rollback())
+	suite.Equal(0, suite.holders(), "Rollback should release the connection")
+}
+
+func (suite *TrackerTestSuite) TestLeakReporting() {
+	rows, err := suite.db.QueryContext(suite.ctx, "SELECT id FROM test_item")
+	suite.Require().NoError(err)
+
+	defer rows.Close()
+
+	suite.tracker.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	suite.tracker.reportLeaks()
+
+	report, err := suite.tracker.ConnectionHolders(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(report.Holders, 1)
+	suite.True(report.Holders[0].Leaked, "Holder beyond the threshold should be flagged")
+	suite.GreaterOrEqual(report.Holders[0].HeldMs, int64(time.Minute/time.Millisecond))
+
+	for _, h := range suite.tracker.holders {
+		suite.True(h.reported, "Leak should be reported once")
+	}
+}
+
+func (suite *TrackerTestSuite) TestDisabled() {
+	tracker := NewTracker(&config.LeakDetectionConfig{}, ilog.Named("conntrack"))
+
+	connector, _, err := sqlite.NewProvider().Connect(&config.DatasourceConfig{Type: constants.SQLite})
+	suite.Require().NoError(err)
+	suite.Same(connector, tracker.WrapConnector(connector), "Disabled tracker should not wrap the connector")
+
+	report, err := tracker.ConnectionHolders(suite.ctx)
+	suite.Require().NoError(err)
+	suite.False(report.Enabled)
+	suite.Equal(DefaultHoldThreshold.Milliseconds(), report.HoldThresholdMs)
+	suite.Empty(report.Holders)
+}
+
+func TestTrackerSuite(t *testing.T) {
+	suite.Run(t, new(TrackerTestSuite))
+}
@@ -0,0 +1,127 @@
+// Package ctxaudit flags queries executed from request-handling goroutines with a context that
+// does not derive from the request, e.g. context.Background(). Such queries are neither cancelled
+// when the client disconnects nor bound by the request deadline.
+//
+// The request middleware marks the request context and the handling goroutine, the query hook
+// checks both on every query. Goroutine lookup is relatively expensive, so the audit is meant for debugging.
+package ctxaudit
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlredact"
+	"github.com/ilxqx/vef-framework-go/log"
+)
+
+// maxStackDepth bounds the number of frames identifying and reported for a call site.
+const maxStackDepth = 32
+
+// requestScopeKey marks contexts derived from a request.
+type requestScopeKey struct{}
+
+// requestGoroutines holds the ids of the goroutines currently handling a request.
+var requestGoroutines sync.Map
+
+// MarkRequest marks the request context and the current goroutine as request-handling.
+// The returned function must be called when the request is handled.
+func MarkRequest(ctx fiber.Ctx) (done func()) {
+	ctx.Locals(requestScopeKey{}, true)
+	ctx.SetContext(context.WithValue(ctx.Context(), requestScopeKey{}, true))
+
+	id := goroutineID()
+	requestGoroutines.Store(id, struct{}{})
+
+	return func() {
+		requestGoroutines.Delete(id)
+	}
+}
+
+// InRequestScope reports whether the context derives from a marked request.
+func InRequestScope(ctx context.Context) bool {
+	marked, _ := ctx.Value(requestScopeKey{}).(bool)
+
+	return marked
+}
+
+// IsRequestGoroutine reports whether the current goroutine is handling a marked request.
+func IsRequestGoroutine() bool {
+	_, ok := requestGoroutines.Load(goroutineID())
+
+	return ok
+}
+
+// goroutineID parses the current goroutine id from the stack header "goroutine 42 [running]:".
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		buf = buf[:i]
+	}
+
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+
+	return id
+}
+
+// Hook is a query hook warning about queries executed from a request-handling goroutine
+// with a context that does not derive from the request. Each call site is reported once.
+type Hook struct {
+	logger   log.Logger
+	reported sync.Map
+}
+
+// NewHook creates the context audit query hook.
+func NewHook(logger log.Logger) *Hook {
+	return &Hook{logger: logger}
+}
+
+func (h *Hook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	if InRequestScope(ctx) || !IsRequestGoroutine() {
+		return ctx
+	}
+
+	// Call sites are identified by their program counters, which unlike formatted stacks carry no argument values
+	var pcs [maxStackDepth]uintptr
+	runtime.Callers(2, pcs[:])
+
+	if _, reported := h.reported.LoadOrStore(pcs, struct{}{}); !reported {
+		h.logger.Warnf("Query executed from a request handler without the request context, it will not be cancelled with the request: %s\n%s", sqlredact.Redact(event.Query), formatStack(pcs[:]))
+	}
+
+	return ctx
+}
+
+func formatStack(pcs []uintptr) string {
+	var sb strings.Builder
+
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			sb.WriteString(frame.Function)
+			sb.WriteString("\n\t")
+			sb.WriteString(frame.File)
+			sb.WriteByte(':')
+			sb.WriteString(strconv.Itoa(frame.Line))
+			sb.WriteByte('\n')
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return sb.String()
+}
+
+func (*Hook) AfterQuery(context.Context, *bun.QueryEvent) {}
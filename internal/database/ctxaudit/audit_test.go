@@ -0,0 +1,113 @@
+package ctxaudit
+
+import (
+	"context"
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlite"
+	ilog "github.com/ilxqx/vef-framework-go/internal/log"
+)
+
+type AuditTestSuite struct {
+	suite.Suite
+
+	db   *bun.DB
+	hook *Hook
+}
+
+func (suite *AuditTestSuite) SetupTest() {
+	connector, dialect, err := sqlite.NewProvider().Connect(&config.DatasourceConfig{Type: constants.SQLite})
+	suite.Require().NoError(err)
+
+	suite.hook = NewHook(ilog.Named("ctxaudit"))
+	suite.db = bun.NewDB(sql.OpenDB(connector), dialect)
+	suite.db.AddQueryHook(suite.hook)
+}
+
+func (suite *AuditTestSuite) TearDownTest() {
+	suite.Require().NoError(suite.db.Close())
+}
+
+func (suite *AuditTestSuite) reportedCount() int {
+	count := 0
+	suite.hook.reported.Range(func(any, any) bool {
+		count++
+
+		return true
+	})
+
+	return count
+}
+
+func (suite *AuditTestSuite) query(ctx context.Context) {
+	var value int
+	suite.Require().NoError(suite.db.NewSelect().ColumnExpr("1").Scan(ctx, &value))
+}
+
+// serve runs the handler as a marked request, like the context audit middleware does.
+func (suite *AuditTestSuite) serve(handler func(ctx fiber.Ctx)) {
+	app := fiber.New()
+	app.Get("/", func(ctx fiber.Ctx) error {
+		done := MarkRequest(ctx)
+		defer done()
+
+		handler(ctx)
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	suite.Require().NoError(err)
+	suite.Equal(fiber.StatusNoContent, resp.StatusCode)
+}
+
+func (suite *AuditTestSuite) TestRequestContextNotFlagged() {
+	suite.serve(func(ctx fiber.Ctx) {
+		suite.True(InRequestScope(ctx), "Fiber context should be marked")
+		suite.True(InRequestScope(ctx.Context()), "Request context should be marked")
+
+		suite.query(ctx.Context())
+		suite.query(ctx)
+	})
+
+	suite.Equal(0, suite.reportedCount(), "Queries with the request context should not be flagged")
+}
+
+func (suite *AuditTestSuite) TestBackgroundContextFlagged() {
+	suite.serve(func(fiber.Ctx) {
+		for range 3 {
+			suite.query(context.Background())
+		}
+	})
+
+	suite.Equal(1, suite.reportedCount(), "Query without request context should be flagged once per call site")
+	suite.False(IsRequestGoroutine(), "Goroutine should be unmarked after the request")
+}
+
+func (suite *AuditTestSuite) TestOutsideRequestNotFlagged() {
+	suite.query(context.Background())
+
+	done := make(chan struct{})
+	suite.serve(func(fiber.Ctx) {
+		go func() {
+			defer close(done)
+
+			suite.False(IsRequestGoroutine(), "Spawned goroutines are not request goroutines")
+		}()
+		<-done
+	})
+
+	suite.Equal(0, suite.reportedCount(), "Background jobs should not be flagged")
+}
+
+func TestAuditSuite(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
@@ -8,6 +8,9 @@ import (
 
 	"github.com/ilxqx/vef-framework-go/config"
 	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/ctxaudit"
+	"github.com/ilxqx/vef-framework-go/internal/database/jsoncodec"
+	"github.com/ilxqx/vef-framework-go/internal/database/querypolicy"
 	"github.com/ilxqx/vef-framework-go/log"
 )
 
@@ -22,13 +25,25 @@ func logDBVersion(provider DatabaseProvider, db *bun.DB, logger log.Logger) erro
 	return nil
 }
 
-func setupBunDB(sqlDB *sql.DB, dialect schema.Dialect, opts *databaseOptions) *bun.DB {
+func setupBunDB(sqlDB *sql.DB, dialect schema.Dialect, opts *databaseOptions, policyHook *querypolicy.Hook) *bun.DB {
 	db := bun.NewDB(sqlDB, dialect, opts.BunOptions...)
 
 	if opts.EnableQueryHook {
 		addQueryHook(db, opts.Logger, opts.SQLGuardConfig)
 	}
 
+	if policyHook != nil {
+		db.AddQueryHook(policyHook)
+	}
+
+	if opts.Config.EnableContextAudit {
+		db.AddQueryHook(ctxaudit.NewHook(opts.Logger))
+	}
+
+	if opts.TableTracker != nil {
+		db.AddQueryHook(opts.TableTracker)
+	}
+
 	db = db.WithNamedArg(constants.PlaceholderKeyOperator, constants.OperatorSystem)
 
 	return db
@@ -40,17 +55,36 @@ func New(cfg *config.DatasourceConfig, options ...Option) (*bun.DB, error) {
 		return nil, newUnsupportedDBTypeError(cfg.Type)
 	}
 
-	sqlDB, dialect, err := provider.Connect(cfg)
-	if err != nil || sqlDB == nil {
+	connector, dialect, err := provider.Connect(cfg)
+	if err != nil || connector == nil {
 		return nil, err
 	}
 
+	if cfg.JSONCodec != constants.Empty {
+		if err := jsoncodec.Use(cfg.JSONCodec); err != nil {
+			return nil, err
+		}
+	}
+
 	opts := newDefaultOptions(cfg)
 	opts.apply(options...)
 
+	var policyHook *querypolicy.Hook
+	if opts.QueryPolicy != nil {
+		if policyHook, err = querypolicy.NewHook(opts.QueryPolicy, opts.Logger.Named("query_policy")); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.ConnTracker != nil {
+		connector = opts.ConnTracker.WrapConnector(connector)
+	}
+
+	sqlDB := sql.OpenDB(connector)
+
 	if opts.PoolConfig != nil {
 		opts.PoolConfig.ApplyToDB(sqlDB)
 	}
 
-	return setupBunDB(sqlDB, dialect, opts), nil
+	return setupBunDB(sqlDB, dialect, opts, policyHook), nil
 }
@@ -13,6 +13,7 @@ import (
 
 	"github.com/ilxqx/vef-framework-go/config"
 	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/dbhelpers"
 	"github.com/ilxqx/vef-framework-go/internal/database/sqlguard"
 	"github.com/ilxqx/vef-framework-go/internal/testhelpers"
 )
@@ -73,6 +74,35 @@ func (suite *DatabaseTestSuite) TestSQLiteWithOptions() {
 	suite.Require().NoError(db.Close(), "Database should close without error")
 }
 
+// TestStatementTimeout tests that statements running longer than the statement timeout are cancelled,
+// by a context deadline on SQLite and by statement_timeout on PostgreSQL.
+func (suite *DatabaseTestSuite) TestStatementTimeout() {
+	postgresConfig := *suite.postgresContainer.DsConfig
+	postgresConfig.StatementTimeout = 100 * time.Millisecond
+
+	configs := map[string]*config.DatasourceConfig{
+		"SQLite":     {Type: constants.SQLite, StatementTimeout: 100 * time.Millisecond},
+		"PostgreSQL": &postgresConfig,
+	}
+
+	for name, cfg := range configs {
+		suite.Run(name, func() {
+			db, err := New(cfg)
+			suite.Require().NoError(err)
+
+			defer func() {
+				suite.NoError(db.Close())
+			}()
+
+			var count int
+
+			err = db.NewRaw("WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 100000000) SELECT count(*) FROM n").
+				Scan(suite.ctx, &count)
+			suite.True(dbhelpers.IsStatementTimeoutError(err), "Statement should time out, got %v", err)
+		})
+	}
+}
+
 // TestPostgreSQLConnection tests PostgreSQL database connection via Testcontainers.
 func (suite *DatabaseTestSuite) TestPostgreSQLConnection() {
 	config := suite.postgresContainer.DsConfig
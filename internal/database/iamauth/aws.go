@@ -0,0 +1,248 @@
+package iamauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+const (
+	awsTokenLifetime   = 15 * time.Minute
+	awsEmptyBodySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// ErrAWSRegionRequired is returned when the region of an RDS database is neither configured nor set in the environment.
+var ErrAWSRegionRequired = errors.New("aws region is required for rds iam authentication, set auth.region or AWS_REGION")
+
+// awsCredentials are the credentials signing RDS auth tokens.
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// awsRDSSource issues RDS IAM auth tokens, presigned connect requests signed with Signature Version 4.
+// Credentials are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables
+// and otherwise from the instance role through the EC2 instance metadata service.
+type awsRDSSource struct {
+	endpoint    string
+	region      string
+	user        string
+	client      *http.Client
+	metadataURL string
+	now         func() time.Time
+}
+
+func newAWSRDSSource(cfg config.DatasourceAuthConfig, target Target) (TokenSource, error) {
+	region := cfg.Region
+	for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region == constants.Empty {
+			region = os.Getenv(env)
+		}
+	}
+
+	if region == constants.Empty {
+		return nil, ErrAWSRegionRequired
+	}
+
+	return &awsRDSSource{
+		endpoint:    net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))),
+		region:      region,
+		user:        target.User,
+		client:      &http.Client{Timeout: 5 * time.Second},
+		metadataURL: "http://169.254.169.254",
+		now:         time.Now,
+	}, nil
+}
+
+func (s *awsRDSSource) Token(ctx context.Context) (Token, error) {
+	credentials, err := s.credentials(ctx)
+	if err != nil {
+		return Token{}, err
+	}
+
+	now := s.now().UTC()
+
+	return Token{
+		Value:  s.sign(credentials, now),
+		Expiry: now.Add(awsTokenLifetime),
+	}, nil
+}
+
+// sign returns the presigned connect request without scheme, which RDS accepts as password.
+func (s *awsRDSSource) sign(credentials awsCredentials, now time.Time) string {
+	date := now.Format("20060102")
+	amzDate := now.Format("20060102T150405Z")
+	scope := date + "/" + s.region + "/rds-db/aws4_request"
+
+	query := map[string]string{
+		"Action":              "connect",
+		"DBUser":              s.user,
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    credentials.AccessKeyID + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(awsTokenLifetime.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	if credentials.SessionToken != constants.Empty {
+		query["X-Amz-Security-Token"] = credentials.SessionToken
+	}
+
+	canonicalQuery := awsCanonicalQuery(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		"/",
+		canonicalQuery,
+		"host:" + s.endpoint + "\n",
+		"host",
+		awsEmptyBodySHA256,
+	}, "\n")
+
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := []byte("AWS4" + credentials.SecretAccessKey)
+	for _, part := range []string{date, s.region, "rds-db", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return s.endpoint + "/?" + canonicalQuery + "&X-Amz-Signature=" + signature
+}
+
+func (s *awsRDSSource) credentials(ctx context.Context) (awsCredentials, error) {
+	if accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID"); accessKeyID != constants.Empty {
+		return awsCredentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+
+	credentials, err := s.instanceCredentials(ctx)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to load aws credentials from the environment or instance metadata: %w", err)
+	}
+
+	return credentials, nil
+}
+
+// instanceCredentials reads the credentials of the instance role using IMDSv2.
+func (s *awsRDSSource) instanceCredentials(ctx context.Context) (awsCredentials, error) {
+	sessionToken, err := s.metadata(ctx, http.MethodPut, "/latest/api/token", constants.Empty)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+
+	role, err := s.metadata(ctx, http.MethodGet, "/latest/meta-data/iam/security-credentials/", sessionToken)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+
+	body, err := s.metadata(ctx, http.MethodGet, "/latest/meta-data/iam/security-credentials/"+strings.TrimSpace(role), sessionToken)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+
+	var result struct {
+		AccessKeyID     string `json:"AccessKeyId"`
+		SecretAccessKey string `json:"SecretAccessKey"`
+		Token           string `json:"Token"`
+	}
+	if err := json.Unmarshal([]byte(body), &result); err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to decode instance credentials: %w", err)
+	}
+
+	return awsCredentials{
+		AccessKeyID:     result.AccessKeyID,
+		SecretAccessKey: result.SecretAccessKey,
+		SessionToken:    result.Token,
+	}, nil
+}
+
+func (s *awsRDSSource) metadata(ctx context.Context, method, path, sessionToken string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.metadataURL+path, nil)
+	if err != nil {
+		return constants.Empty, err
+	}
+
+	if sessionToken == constants.Empty {
+		req.Header.Set("X-Aws-Ec2-Metadata-Token-Ttl-Seconds", "21600")
+	} else {
+		req.Header.Set("X-Aws-Ec2-Metadata-Token", sessionToken)
+	}
+
+	body, err := doRequest(s.client, req)
+	if err != nil {
+		return constants.Empty, err
+	}
+
+	return string(body), nil
+}
+
+// awsCanonicalQuery encodes the query sorted by key with the URI encoding of Signature Version 4.
+func awsCanonicalQuery(query map[string]string) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = awsEscape(key) + "=" + awsEscape(query[key])
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+func awsEscape(value string) string {
+	// Signature Version 4 encodes spaces as %20 and leaves only unreserved characters as they are
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+// doRequest sends a request and returns the body of a successful response.
+func doRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: unexpected status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
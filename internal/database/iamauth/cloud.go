@@ -0,0 +1,120 @@
+package iamauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+const (
+	gcpSQLLoginScope = "https://www.googleapis.com/auth/sqlservice.login"
+	azureDBResource  = "https://ossrdbms-aad.database.windows.net"
+)
+
+// gcpCloudSQLSource issues OAuth2 access tokens of the service account attached to the workload,
+// from the GCE metadata server which is also served to GKE workload identities and Cloud Run.
+// The database user is the service account email, without .gserviceaccount.com on Postgres.
+type gcpCloudSQLSource struct {
+	tokenURL string
+	client   *http.Client
+}
+
+func newGCPCloudSQLSource(config.DatasourceAuthConfig, Target) (TokenSource, error) {
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == constants.Empty {
+		host = "metadata.google.internal"
+	}
+
+	return &gcpCloudSQLSource{
+		tokenURL: "http://" + host + "/computeMetadata/v1/instance/service-accounts/default/token?scopes=" + url.QueryEscape(gcpSQLLoginScope),
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+func (s *gcpCloudSQLSource) Token(ctx context.Context) (Token, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.tokenURL, nil)
+	if err != nil {
+		return Token{}, err
+	}
+
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := getJSON(s.client, req, &result); err != nil {
+		return Token{}, fmt.Errorf("failed to obtain gcp access token: %w", err)
+	}
+
+	return Token{
+		Value:  result.AccessToken,
+		Expiry: time.Now().Add(time.Duration(result.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// azureADSource issues Microsoft Entra ID (Azure AD) access tokens of the managed identity of the workload
+// from the instance metadata service. The database user is the name of the identity's database role.
+type azureADSource struct {
+	tokenURL string
+	client   *http.Client
+}
+
+func newAzureADSource(cfg config.DatasourceAuthConfig, _ Target) (TokenSource, error) {
+	query := url.Values{
+		"api-version": {"2018-02-01"},
+		"resource":    {azureDBResource},
+	}
+	if cfg.ClientID != constants.Empty {
+		query.Set("client_id", cfg.ClientID)
+	}
+
+	return &azureADSource{
+		tokenURL: "http://169.254.169.254/metadata/identity/oauth2/token?" + query.Encode(),
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+func (s *azureADSource) Token(ctx context.Context) (Token, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.tokenURL, nil)
+	if err != nil {
+		return Token{}, err
+	}
+
+	req.Header.Set("Metadata", "true")
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresOn   string `json:"expires_on"`
+	}
+	if err := getJSON(s.client, req, &result); err != nil {
+		return Token{}, fmt.Errorf("failed to obtain azure access token: %w", err)
+	}
+
+	expiresOn, err := strconv.ParseInt(result.ExpiresOn, 10, 64)
+	if err != nil {
+		return Token{}, fmt.Errorf("failed to parse azure token expiry %q: %w", result.ExpiresOn, err)
+	}
+
+	return Token{
+		Value:  result.AccessToken,
+		Expiry: time.Unix(expiresOn, 0),
+	}, nil
+}
+
+func getJSON(client *http.Client, req *http.Request, result any) error {
+	body, err := doRequest(client, req)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, result)
+}
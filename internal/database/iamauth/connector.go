@@ -0,0 +1,87 @@
+package iamauth
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultRefreshBefore = 5 * time.Minute
+
+// Connector connects with the current token of a source, refreshing it before it expires.
+// The driver connector is recreated with every new token, connections opened before keep working
+// as tokens are only checked when authenticating.
+type Connector struct {
+	source        TokenSource
+	refreshBefore time.Duration
+	newConnector  func(password string) (driver.Connector, error)
+	driver        driver.Driver
+
+	mu        sync.Mutex
+	token     Token
+	connector driver.Connector
+}
+
+// NewConnector returns a connector authenticating with tokens of source,
+// newConnector creates the driver connector using a token as password.
+func NewConnector(source TokenSource, refreshBefore time.Duration, newConnector func(password string) (driver.Connector, error)) (*Connector, error) {
+	// The driver connector without password only serves Driver, which database/sql may call before connecting
+	connector, err := newConnector("")
+	if err != nil {
+		return nil, err
+	}
+
+	if refreshBefore <= 0 {
+		refreshBefore = defaultRefreshBefore
+	}
+
+	return &Connector{
+		source:        source,
+		refreshBefore: refreshBefore,
+		newConnector:  newConnector,
+		driver:        connector.Driver(),
+	}, nil
+}
+
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	connector, err := c.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return connector.Connect(ctx)
+}
+
+func (c *Connector) Driver() driver.Driver {
+	return c.driver
+}
+
+func (c *Connector) current(ctx context.Context) (driver.Connector, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.connector != nil && time.Until(c.token.Expiry) > c.refreshBefore {
+		return c.connector, nil
+	}
+
+	token, err := c.source.Token(ctx)
+	if err != nil {
+		// Keep connecting with the current token while it is valid, the refresh is retried on the next connection
+		if c.connector != nil && time.Now().Before(c.token.Expiry) {
+			return c.connector, nil
+		}
+
+		return nil, fmt.Errorf("failed to obtain database auth token: %w", err)
+	}
+
+	connector, err := c.newConnector(token.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	c.token, c.connector = token, connector
+
+	return connector, nil
+}
@@ -0,0 +1,100 @@
+// Package iamauth authenticates datasources with short-lived tokens issued to the identity of the deployment
+// instead of static passwords.
+//
+// Built-in providers cover AWS RDS IAM, GCP Cloud SQL IAM and Azure AD authentication, other identity providers
+// are plugged in with Register. Tokens are cached by the connector and refreshed before they expire,
+// so that new connections of the pool always authenticate with a valid token.
+package iamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// Built-in provider names.
+const (
+	AWSRDS      = "aws_rds_iam"
+	GCPCloudSQL = "gcp_cloud_sql_iam"
+	AzureAD     = "azure_ad"
+)
+
+var (
+	// ErrUnknownProvider is returned when selecting a provider that is not registered.
+	ErrUnknownProvider = errors.New("unknown datasource auth provider")
+	// ErrPasswordWithProvider is returned when both a password and an auth provider are configured.
+	ErrPasswordWithProvider = errors.New("datasource password and auth provider are mutually exclusive")
+	// ErrUserRequired is returned when token authentication is configured without database user.
+	ErrUserRequired = errors.New("datasource user is required for token authentication")
+)
+
+// Token is a database password valid until its expiry.
+type Token struct {
+	Value  string
+	Expiry time.Time
+}
+
+// TokenSource issues tokens authenticating the database user.
+type TokenSource interface {
+	// Token returns a new token, it is called again once the previous token is about to expire.
+	Token(ctx context.Context) (Token, error)
+}
+
+// Target describes the database tokens are issued for.
+type Target struct {
+	Type constants.DBType
+	Host string
+	Port uint16
+	User string
+}
+
+// Factory creates the token source of a provider for a datasource.
+type Factory func(cfg config.DatasourceAuthConfig, target Target) (TokenSource, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{
+		AWSRDS:      newAWSRDSSource,
+		GCPCloudSQL: newGCPCloudSQLSource,
+		AzureAD:     newAzureADSource,
+	}
+)
+
+// Register registers a provider under name, replacing a provider registered under the same name.
+// Providers are selected by name in the auth configuration of datasources.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	factories[name] = factory
+}
+
+// New returns the token source of the configured provider, nil when the datasource authenticates with its password.
+func New(cfg *config.DatasourceConfig, target Target) (TokenSource, error) {
+	if cfg.Auth.Provider == constants.Empty {
+		return nil, nil
+	}
+
+	if cfg.Password != constants.Empty {
+		return nil, ErrPasswordWithProvider
+	}
+
+	if target.User == constants.Empty {
+		return nil, ErrUserRequired
+	}
+
+	mu.RLock()
+	factory, ok := factories[cfg.Auth.Provider]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, cfg.Auth.Provider)
+	}
+
+	return factory(cfg.Auth, target)
+}
@@ -0,0 +1,273 @@
+package iamauth
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+type staticSource struct {
+	tokens []Token
+	err    error
+	calls  int
+}
+
+func (s *staticSource) Token(context.Context) (Token, error) {
+	s.calls++
+	if s.err != nil {
+		return Token{}, s.err
+	}
+
+	return s.tokens[min(s.calls, len(s.tokens))-1], nil
+}
+
+type passwordConnector struct {
+	password string
+}
+
+func (c *passwordConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, errors.New("connected with " + c.password)
+}
+
+func (*passwordConnector) Driver() driver.Driver {
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	target := Target{Type: constants.Postgres, Host: "db.internal", Port: 5432, User: "app"}
+
+	t.Run("PasswordAuthentication", func(t *testing.T) {
+		source, err := New(&config.DatasourceConfig{Password: "secret"}, target)
+		require.NoError(t, err)
+		assert.Nil(t, source)
+	})
+
+	t.Run("RegisteredProvider", func(t *testing.T) {
+		Register("static", func(config.DatasourceAuthConfig, Target) (TokenSource, error) {
+			return &staticSource{}, nil
+		})
+
+		source, err := New(&config.DatasourceConfig{Auth: config.DatasourceAuthConfig{Provider: "static"}}, target)
+		require.NoError(t, err)
+		assert.IsType(t, &staticSource{}, source)
+	})
+
+	t.Run("AWSRegionFromEnvironment", func(t *testing.T) {
+		t.Setenv("AWS_REGION", "eu-west-1")
+
+		source, err := New(&config.DatasourceConfig{Auth: config.DatasourceAuthConfig{Provider: AWSRDS}}, target)
+		require.NoError(t, err)
+		assert.Equal(t, "eu-west-1", source.(*awsRDSSource).region)
+	})
+
+	t.Run("AWSRegionRequired", func(t *testing.T) {
+		t.Setenv("AWS_REGION", "")
+		t.Setenv("AWS_DEFAULT_REGION", "")
+
+		_, err := New(&config.DatasourceConfig{Auth: config.DatasourceAuthConfig{Provider: AWSRDS}}, target)
+		assert.ErrorIs(t, err, ErrAWSRegionRequired)
+	})
+
+	tests := []struct {
+		name   string
+		cfg    *config.DatasourceConfig
+		target Target
+		err    error
+	}{
+		{"UnknownProvider", &config.DatasourceConfig{Auth: config.DatasourceAuthConfig{Provider: "kerberos"}}, target, ErrUnknownProvider},
+		{"PasswordWithProvider", &config.DatasourceConfig{Password: "secret", Auth: config.DatasourceAuthConfig{Provider: AzureAD}}, target, ErrPasswordWithProvider},
+		{"UserRequired", &config.DatasourceConfig{Auth: config.DatasourceAuthConfig{Provider: AzureAD}}, Target{Host: "db.internal"}, ErrUserRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg, tt.target)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestAWSRDSToken(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	t.Setenv("AWS_SESSION_TOKEN", "session/token")
+
+	source, err := newAWSRDSSource(config.DatasourceAuthConfig{Region: "us-east-1"}, Target{Host: "db.us-east-1.rds.amazonaws.com", Port: 5432, User: "app"})
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	source.(*awsRDSSource).now = func() time.Time { return now }
+
+	token, err := source.Token(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, now.Add(15*time.Minute), token.Expiry)
+	assert.True(t, strings.HasPrefix(token.Value, "db.us-east-1.rds.amazonaws.com:5432/?Action=connect&DBUser=app&"), token.Value)
+	assert.Contains(t, token.Value, "X-Amz-Credential=AKIDEXAMPLE%2F20240102%2Fus-east-1%2Frds-db%2Faws4_request")
+	assert.Contains(t, token.Value, "X-Amz-Date=20240102T030405Z&X-Amz-Expires=900")
+	assert.Contains(t, token.Value, "X-Amz-Security-Token=session%2Ftoken")
+	assert.Regexp(t, regexp.MustCompile(`&X-Amz-Signature=[0-9a-f]{64}$`), token.Value)
+
+	again, err := source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, token.Value, again.Value, "Signing should be deterministic")
+}
+
+func TestAWSInstanceCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			_, _ = w.Write([]byte("imds-token"))
+		case r.Header.Get("X-Aws-Ec2-Metadata-Token") != "imds-token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/latest/meta-data/iam/security-credentials/":
+			_, _ = w.Write([]byte("app-role\n"))
+		case r.URL.Path == "/latest/meta-data/iam/security-credentials/app-role":
+			_, _ = w.Write([]byte(`{"AccessKeyId":"ASIAROLE","SecretAccessKey":"secret","Token":"role-token"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source, err := newAWSRDSSource(config.DatasourceAuthConfig{Region: "us-east-1"}, Target{Host: "db", Port: 3306, User: "app"})
+	require.NoError(t, err)
+
+	source.(*awsRDSSource).metadataURL = server.URL
+
+	token, err := source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, token.Value, "X-Amz-Credential=ASIAROLE%2F")
+	assert.Contains(t, token.Value, "X-Amz-Security-Token=role-token")
+}
+
+func TestCloudTokens(t *testing.T) {
+	expiresOn := time.Now().Add(time.Hour).Unix()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("Metadata-Flavor") == "Google":
+			assert.Equal(t, gcpSQLLoginScope, r.URL.Query().Get("scopes"))
+			_, _ = w.Write([]byte(`{"access_token":"gcp-token","expires_in":3599,"token_type":"Bearer"}`))
+		case r.Header.Get("Metadata") == "true":
+			assert.Equal(t, azureDBResource, r.URL.Query().Get("resource"))
+			assert.Equal(t, "client-id", r.URL.Query().Get("client_id"))
+			_, _ = w.Write([]byte(`{"access_token":"azure-token","expires_on":"` + strconv.FormatInt(expiresOn, 10) + `"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("GCPCloudSQL", func(t *testing.T) {
+		t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(server.URL, "http://"))
+
+		source, err := newGCPCloudSQLSource(config.DatasourceAuthConfig{}, Target{})
+		require.NoError(t, err)
+
+		token, err := source.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "gcp-token", token.Value)
+		assert.WithinDuration(t, time.Now().Add(3599*time.Second), token.Expiry, time.Minute)
+	})
+
+	t.Run("AzureAD", func(t *testing.T) {
+		source, err := newAzureADSource(config.DatasourceAuthConfig{ClientID: "client-id"}, Target{})
+		require.NoError(t, err)
+
+		azure := source.(*azureADSource)
+		azure.tokenURL = server.URL + azure.tokenURL[strings.Index(azure.tokenURL, "/metadata"):]
+
+		token, err := source.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "azure-token", token.Value)
+		assert.Equal(t, expiresOn, token.Expiry.Unix())
+	})
+
+	t.Run("ErrorStatus", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer failing.Close()
+
+		source := &gcpCloudSQLSource{tokenURL: failing.URL, client: http.DefaultClient}
+
+		_, err := source.Token(context.Background())
+		assert.ErrorContains(t, err, "unexpected status 403")
+	})
+}
+
+func TestConnector(t *testing.T) {
+	ctx := context.Background()
+
+	newConnector := func(password string) (driver.Connector, error) {
+		return &passwordConnector{password: password}, nil
+	}
+
+	connectedWith := func(connector *Connector) string {
+		_, err := connector.Connect(ctx)
+		require.Error(t, err)
+
+		return strings.TrimPrefix(err.Error(), "connected with ")
+	}
+
+	t.Run("CachesValidToken", func(t *testing.T) {
+		source := &staticSource{tokens: []Token{{Value: "first", Expiry: time.Now().Add(time.Hour)}}}
+
+		connector, err := NewConnector(source, 0, newConnector)
+		require.NoError(t, err)
+
+		assert.Equal(t, "first", connectedWith(connector))
+		assert.Equal(t, "first", connectedWith(connector))
+		assert.Equal(t, 1, source.calls, "Token should be reused until it is about to expire")
+	})
+
+	t.Run("RefreshesExpiringToken", func(t *testing.T) {
+		source := &staticSource{tokens: []Token{
+			{Value: "first", Expiry: time.Now().Add(time.Minute)},
+			{Value: "second", Expiry: time.Now().Add(time.Hour)},
+		}}
+
+		connector, err := NewConnector(source, 5*time.Minute, newConnector)
+		require.NoError(t, err)
+
+		assert.Equal(t, "first", connectedWith(connector))
+		assert.Equal(t, "second", connectedWith(connector))
+		assert.Equal(t, "second", connectedWith(connector))
+		assert.Equal(t, 2, source.calls)
+	})
+
+	t.Run("KeepsValidTokenOnRefreshFailure", func(t *testing.T) {
+		source := &staticSource{tokens: []Token{{Value: "first", Expiry: time.Now().Add(time.Minute)}}}
+
+		connector, err := NewConnector(source, 5*time.Minute, newConnector)
+		require.NoError(t, err)
+		assert.Equal(t, "first", connectedWith(connector))
+
+		source.err = errors.New("metadata unavailable")
+		assert.Equal(t, "first", connectedWith(connector))
+	})
+
+	t.Run("FailsWithoutToken", func(t *testing.T) {
+		connector, err := NewConnector(&staticSource{err: errors.New("metadata unavailable")}, 0, newConnector)
+		require.NoError(t, err)
+
+		_, err = connector.Connect(ctx)
+		assert.ErrorContains(t, err, "failed to obtain database auth token: metadata unavailable")
+	})
+}
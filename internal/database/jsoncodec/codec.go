@@ -0,0 +1,69 @@
+package jsoncodec
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/uptrace/bun/extra/bunjson"
+)
+
+// Built-in codec names; Sonic is only available in binaries built with the sonic build tag.
+const (
+	Std   = "std"
+	Sonic = "sonic"
+)
+
+// ErrUnknownCodec is returned when selecting a codec that is not registered.
+var ErrUnknownCodec = errors.New("unknown json codec")
+
+type (
+	// Codec encodes model JSON fields and map/struct bind parameters.
+	//
+	// Codecs must produce output compatible with encoding/json so that switching codecs never changes stored JSON:
+	// values implementing json.Marshaler or encoding.TextMarshaler (datetime, decimal, time.Time) format themselves,
+	// map keys are sorted and HTML characters are escaped.
+	Codec = bunjson.Provider
+	// Encoder is the streaming encoder of a codec.
+	Encoder = bunjson.Encoder
+	// Decoder is the streaming decoder of a codec.
+	Decoder = bunjson.Decoder
+)
+
+var (
+	mu     sync.RWMutex
+	codecs = map[string]Codec{
+		Std: bunjson.StdProvider{},
+	}
+)
+
+// Register registers a codec under name, replacing a codec registered under the same name.
+// Codecs backed by other libraries, e.g. go-json, are plugged in this way and selected by name in configuration.
+func Register(name string, codec Codec) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	codecs[name] = codec
+}
+
+// Lookup returns the codec registered under name.
+func Lookup(name string) (Codec, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	codec, ok := codecs[name]
+
+	return codec, ok
+}
+
+// Use selects the codec registered under name for all databases of the process.
+func Use(name string) error {
+	codec, ok := Lookup(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownCodec, name)
+	}
+
+	bunjson.SetProvider(codec)
+
+	return nil
+}
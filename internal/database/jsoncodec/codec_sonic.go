@@ -0,0 +1,34 @@
+//go:build sonic
+
+package jsoncodec
+
+import (
+	"io"
+
+	"github.com/bytedance/sonic"
+)
+
+func init() {
+	Register(Sonic, sonicCodec{api: sonic.ConfigStd})
+}
+
+// sonicCodec is a Codec backed by sonic in its encoding/json compatible configuration.
+type sonicCodec struct {
+	api sonic.API
+}
+
+func (c sonicCodec) Marshal(v any) ([]byte, error) {
+	return c.api.Marshal(v)
+}
+
+func (c sonicCodec) Unmarshal(data []byte, v any) error {
+	return c.api.Unmarshal(data, v)
+}
+
+func (c sonicCodec) NewEncoder(w io.Writer) Encoder {
+	return c.api.NewEncoder(w)
+}
+
+func (c sonicCodec) NewDecoder(r io.Reader) Decoder {
+	return c.api.NewDecoder(r)
+}
@@ -0,0 +1,170 @@
+package jsoncodec
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/extra/bunjson"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/decimal"
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlite"
+)
+
+type payload struct {
+	Name      string            `json:"name"`
+	Amount    decimal.Decimal   `json:"amount"`
+	CreatedAt datetime.DateTime `json:"createdAt"`
+	Time      time.Time         `json:"time"`
+	Tags      []string          `json:"tags"`
+	Meta      map[string]any    `json:"meta"`
+}
+
+func newPayload() payload {
+	return payload{
+		Name:      "<order>",
+		Amount:    decimal.NewFromFloat(1234.5678),
+		CreatedAt: datetime.Now(),
+		Time:      time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC),
+		Tags:      []string{"a", "b"},
+		Meta:      map[string]any{"z": 1, "a": "x", "m": []any{true, nil}},
+	}
+}
+
+// countingCodec counts the calls reaching the standard codec.
+type countingCodec struct {
+	bunjson.StdProvider
+
+	marshals atomic.Int32
+	decodes  atomic.Int32
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	c.marshals.Add(1)
+
+	return c.StdProvider.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.decodes.Add(1)
+
+	return c.StdProvider.Unmarshal(data, v)
+}
+
+func (c *countingCodec) NewDecoder(r io.Reader) Decoder {
+	c.decodes.Add(1)
+
+	return c.StdProvider.NewDecoder(r)
+}
+
+type document struct {
+	bun.BaseModel `bun:"table:codec_document"`
+
+	ID   int64          `bun:"id,pk,autoincrement"`
+	Data map[string]any `bun:"data,type:json"`
+}
+
+func TestUseUnknownCodec(t *testing.T) {
+	err := Use("unknown")
+	assert.ErrorIs(t, err, ErrUnknownCodec, "Should reject unregistered codecs")
+}
+
+func TestRegisteredCodecEncodesJSONColumns(t *testing.T) {
+	codec := new(countingCodec)
+	Register("counting", codec)
+	require.NoError(t, Use("counting"))
+
+	t.Cleanup(func() {
+		require.NoError(t, Use(Std))
+	})
+
+	connector, dialect, err := sqlite.NewProvider().Connect(&config.DatasourceConfig{Type: constants.SQLite})
+	require.NoError(t, err)
+
+	db := bun.NewDB(sql.OpenDB(connector), dialect)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err = db.NewCreateTable().Model((*document)(nil)).Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewInsert().Model(&document{Data: map[string]any{"key": "value"}}).Exec(ctx)
+	require.NoError(t, err)
+
+	var count int
+
+	err = db.NewSelect().Model((*document)(nil)).ColumnExpr("COUNT(*)").Where("data = ?", map[string]any{"key": "value"}).Scan(ctx, &count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "Bind parameter should be encoded like the stored column")
+
+	var doc document
+	require.NoError(t, db.NewSelect().Model(&doc).Limit(1).Scan(ctx))
+	assert.Equal(t, "value", doc.Data["key"])
+
+	assert.Equal(t, int32(2), codec.marshals.Load(), "Column and bind parameter should be encoded by the codec")
+	assert.Positive(t, codec.decodes.Load(), "Column should be decoded by the codec")
+}
+
+func TestCodecsMatchStandardOutput(t *testing.T) {
+	value := newPayload()
+
+	expected, err := json.Marshal(value)
+	require.NoError(t, err)
+
+	for _, name := range []string{Std, Sonic} {
+		codec, ok := Lookup(name)
+		if !ok {
+			continue
+		}
+
+		t.Run(name, func(t *testing.T) {
+			actual, err := codec.Marshal(value)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(actual), "Codec should encode like encoding/json")
+			assert.Equal(t, string(expected), string(actual), "Codec should sort map keys and escape HTML like encoding/json")
+
+			var decoded payload
+			require.NoError(t, codec.Unmarshal(actual, &decoded))
+			assert.True(t, value.Amount.Equal(decoded.Amount), "Decimal should round-trip")
+			assert.True(t, value.Time.Equal(decoded.Time), "Time should round-trip")
+		})
+	}
+}
+
+func BenchmarkCodecs(b *testing.B) {
+	value := newPayload()
+
+	for _, name := range []string{Std, Sonic} {
+		codec, ok := Lookup(name)
+		if !ok {
+			continue
+		}
+
+		data, err := codec.Marshal(value)
+		require.NoError(b, err)
+
+		b.Run(name+"/Marshal", func(b *testing.B) {
+			for b.Loop() {
+				_, _ = codec.Marshal(value)
+			}
+		})
+
+		b.Run(name+"/Unmarshal", func(b *testing.B) {
+			for b.Loop() {
+				var decoded payload
+				_ = codec.Unmarshal(data, &decoded)
+			}
+		})
+	}
+}
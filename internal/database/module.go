@@ -8,7 +8,11 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/internal/database/conntrack"
+	"github.com/ilxqx/vef-framework-go/internal/database/poolstats"
+	"github.com/ilxqx/vef-framework-go/internal/database/tabletag"
 	"github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/monitor"
 )
 
 var (
@@ -17,8 +21,27 @@ var (
 		"vef:database",
 		fx.Provide(
 			fx.Annotate(
-				func(lc fx.Lifecycle, cfg *config.DatasourceConfig) (db *bun.DB, err error) {
-					if db, err = New(cfg); err != nil {
+				func(lc fx.Lifecycle, cfg *config.DatasourceConfig) *conntrack.Tracker {
+					tracker := conntrack.NewTracker(&cfg.LeakDetection, logger)
+					if tracker.Enabled() {
+						logger.Warn("Connection leak detection is enabled, this should only be used for debugging")
+					}
+
+					lc.Append(fx.StartStopHook(tracker.Start, tracker.Stop))
+
+					return tracker
+				},
+				fx.As(new(monitor.ConnectionLeakDetector)),
+				fx.As(fx.Self()),
+			),
+			fx.Annotate(
+				func(
+					lc fx.Lifecycle,
+					cfg *config.DatasourceConfig,
+					tracker *conntrack.Tracker,
+					tableTracker *tabletag.Tracker,
+				) (db *bun.DB, err error) {
+					if db, err = New(cfg, WithConnTracker(tracker), WithTableTracker(tableTracker)); err != nil {
 						return db, err
 					}
 
@@ -54,9 +77,26 @@ var (
 				fx.As(new(bun.IDB)),
 				fx.As(fx.Self()),
 			),
+			tabletag.NewTracker,
 			func(db *bun.DB) *sql.DB {
 				return db.DB
 			},
+			fx.Annotate(
+				func(lc fx.Lifecycle, cfg *config.DatasourceConfig, db *sql.DB) *poolstats.Sampler {
+					sampler := poolstats.NewSampler(&cfg.PoolMetrics, db, logger)
+					lc.Append(fx.StartStopHook(sampler.Start, sampler.Stop))
+
+					return sampler
+				},
+				fx.As(new(monitor.ConnectionPoolMonitor)),
+				fx.As(fx.Self()),
+			),
+			fx.Annotate(
+				func(sampler *poolstats.Sampler) monitor.HealthContributor {
+					return sampler
+				},
+				fx.ResultTags(`group:"vef:monitor:health_contributors"`),
+			),
 		),
 	)
 )
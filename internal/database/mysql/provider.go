@@ -1,8 +1,9 @@
 package mysql
 
 import (
-	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"strconv"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/samber/lo"
@@ -12,6 +13,8 @@ import (
 
 	"github.com/ilxqx/vef-framework-go/config"
 	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/iamauth"
+	"github.com/ilxqx/vef-framework-go/internal/database/transport"
 )
 
 type Provider struct {
@@ -28,17 +31,63 @@ func (p *Provider) Type() constants.DBType {
 	return p.dbType
 }
 
-func (p *Provider) Connect(cfg *config.DatasourceConfig) (*sql.DB, schema.Dialect, error) {
+func (p *Provider) Connect(cfg *config.DatasourceConfig) (driver.Connector, schema.Dialect, error) {
 	if err := p.ValidateConfig(cfg); err != nil {
 		return nil, nil, err
 	}
 
-	connector, err := mysql.NewConnector(p.buildConfig(cfg))
+	mysqlCfg := p.buildConfig(cfg)
+	host := lo.Ternary(cfg.Host != constants.Empty, cfg.Host, "127.0.0.1")
+
+	tlsConfig, err := transport.TLSConfig(cfg.TLS, host)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mysqlCfg.TLS = tlsConfig
+
+	tokenSource, err := iamauth.New(cfg, iamauth.Target{
+		Type: p.dbType,
+		Host: host,
+		Port: lo.Ternary(cfg.Port != 0, cfg.Port, uint16(3306)),
+		User: mysqlCfg.User,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tunnel, err := transport.NewTunnel(cfg.SSHTunnel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if tunnel != nil {
+		mysqlCfg.DialFunc = tunnel.DialContext
+	}
+
+	var connector driver.Connector
+	if tokenSource != nil {
+		// Tokens are sent with the cleartext authentication plugin, which the auth proxies of the cloud databases expect
+		mysqlCfg.AllowCleartextPasswords = true
+		connector, err = iamauth.NewConnector(tokenSource, cfg.Auth.RefreshBefore, func(password string) (driver.Connector, error) {
+			tokenCfg := mysqlCfg.Clone()
+			tokenCfg.Passwd = password
+
+			return mysql.NewConnector(tokenCfg)
+		})
+	} else {
+		connector, err = mysql.NewConnector(mysqlCfg)
+	}
+
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create mysql connector: %w", err)
 	}
 
-	return sql.OpenDB(connector), mysqldialect.New(), nil
+	if tunnel != nil {
+		return transport.WithCloser(connector, tunnel), mysqldialect.New(), nil
+	}
+
+	return connector, mysqldialect.New(), nil
 }
 
 func (*Provider) ValidateConfig(cfg *config.DatasourceConfig) error {
@@ -67,5 +116,12 @@ func (*Provider) buildConfig(cfg *config.DatasourceConfig) *mysql.Config {
 	mysqlCfg.ParseTime = true
 	mysqlCfg.Collation = "utf8mb4_unicode_ci"
 
+	if cfg.StatementTimeout > 0 {
+		// Bounds selects only, MySQL does not time out other statements
+		mysqlCfg.Params = map[string]string{
+			"max_execution_time": strconv.FormatInt(max(cfg.StatementTimeout.Milliseconds(), 1), 10),
+		}
+	}
+
 	return mysqlCfg
 }
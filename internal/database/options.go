@@ -1,10 +1,15 @@
 package database
 
 import (
+	"time"
+
 	"github.com/uptrace/bun"
 
 	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/conntrack"
 	"github.com/ilxqx/vef-framework-go/internal/database/sqlguard"
+	"github.com/ilxqx/vef-framework-go/internal/database/tabletag"
 	"github.com/ilxqx/vef-framework-go/log"
 )
 
@@ -15,6 +20,9 @@ type databaseOptions struct {
 	Logger          log.Logger
 	BunOptions      []bun.DBOption
 	SQLGuardConfig  *sqlguard.Config
+	ConnTracker     *conntrack.Tracker
+	QueryPolicy     *config.QueryPolicyConfig
+	TableTracker    *tabletag.Tracker
 }
 
 type Option func(*databaseOptions)
@@ -25,6 +33,15 @@ func newDefaultOptions(cfg *config.DatasourceConfig) *databaseOptions {
 		guardConfig = sqlguard.DefaultConfig()
 	}
 
+	var policyConfig *config.QueryPolicyConfig
+	if cfg.QueryPolicy.Enabled {
+		policyConfig = &cfg.QueryPolicy
+	}
+
+	if cfg.StatementTimeout > 0 && cfg.Type != constants.Postgres && cfg.Type != constants.MySQL {
+		policyConfig = withDefaultExecutionTime(policyConfig, cfg.StatementTimeout)
+	}
+
 	return &databaseOptions{
 		Config:          cfg,
 		PoolConfig:      NewDefaultConnectionPoolConfig(),
@@ -32,7 +49,24 @@ func newDefaultOptions(cfg *config.DatasourceConfig) *databaseOptions {
 		Logger:          logger,
 		BunOptions:      []bun.DBOption{bun.WithDiscardUnknownColumns()},
 		SQLGuardConfig:  guardConfig,
+		QueryPolicy:     policyConfig,
+	}
+}
+
+// withDefaultExecutionTime enforces the statement timeout of databases without a server-side timeout as the default
+// execution time of the query policies, which cancel statements through their context. An execution time of the
+// default policy takes precedence.
+func withDefaultExecutionTime(policyConfig *config.QueryPolicyConfig, timeout time.Duration) *config.QueryPolicyConfig {
+	cfg := config.QueryPolicyConfig{Enabled: true}
+	if policyConfig != nil {
+		cfg = *policyConfig
+	}
+
+	if cfg.Default.MaxExecutionTime <= 0 {
+		cfg.Default.MaxExecutionTime = timeout
 	}
+
+	return &cfg
 }
 
 func WithConnectionPool(poolConfig *ConnectionPoolConfig) Option {
@@ -74,6 +108,28 @@ func DisableSQLGuard() Option {
 	}
 }
 
+// WithConnTracker wraps the driver with the connection leak tracker.
+// The driver is only wrapped when leak detection is enabled for the tracker.
+func WithConnTracker(tracker *conntrack.Tracker) Option {
+	return func(opts *databaseOptions) {
+		opts.ConnTracker = tracker
+	}
+}
+
+// WithQueryPolicy sets the query policies enforced on every statement.
+func WithQueryPolicy(cfg *config.QueryPolicyConfig) Option {
+	return func(opts *databaseOptions) {
+		opts.QueryPolicy = cfg
+	}
+}
+
+// WithTableTracker adds the tracker recording the tables read and written by queries.
+func WithTableTracker(tracker *tabletag.Tracker) Option {
+	return func(opts *databaseOptions) {
+		opts.TableTracker = tracker
+	}
+}
+
 func (opts *databaseOptions) apply(options ...Option) {
 	for _, opt := range options {
 		opt(opts)
@@ -0,0 +1,225 @@
+// Package poolstats samples the statistics of the database connection pool on an interval.
+//
+// The samples are served as Prometheus metrics and reported to the health check: the pool is degraded
+// when the share of the maximum open connections in use reaches the exhaustion ratio or when queries
+// waited for a connection of the exhausted pool since the previous sample, and down when the database
+// does not answer a ping.
+package poolstats
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/log"
+	"github.com/ilxqx/vef-framework-go/monitor"
+)
+
+const (
+	// DefaultInterval is the default interval between samples.
+	DefaultInterval = 15 * time.Second
+	// DefaultExhaustionRatio is the default share of the maximum open connections in use reported as degraded.
+	DefaultExhaustionRatio = 0.9
+
+	// pingTimeout bounds the ping checking the database answers.
+	pingTimeout = 5 * time.Second
+	// componentName names the database in the health report.
+	componentName = "database"
+)
+
+// Sampler samples the connection pool statistics of a database.
+// It implements monitor.ConnectionPoolMonitor and monitor.HealthContributor.
+type Sampler struct {
+	config *config.PoolMetricsConfig
+	db     *sql.DB
+	logger log.Logger
+	now    func() time.Time
+
+	mu      sync.RWMutex
+	sample  *monitor.ConnectionPoolStats
+	pingErr error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSampler creates a sampler of the pool of the database; the pool is only sampled when enabled.
+func NewSampler(cfg *config.PoolMetricsConfig, db *sql.DB, logger log.Logger) *Sampler {
+	cfgToUse := *cfg
+	if cfgToUse.Interval <= 0 {
+		cfgToUse.Interval = DefaultInterval
+	}
+	if cfgToUse.ExhaustionRatio <= 0 || cfgToUse.ExhaustionRatio > 1 {
+		cfgToUse.ExhaustionRatio = DefaultExhaustionRatio
+	}
+
+	return &Sampler{
+		config: &cfgToUse,
+		db:     db,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Enabled reports whether the pool is sampled.
+func (s *Sampler) Enabled() bool {
+	return s.config.Enabled
+}
+
+// Start takes a first sample and keeps sampling the pool on the interval.
+func (s *Sampler) Start(ctx context.Context) {
+	if !s.config.Enabled || s.cancel != nil {
+		return
+	}
+
+	s.Sample(ctx)
+
+	samplerCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(samplerCtx)
+}
+
+// Stop stops sampling the pool.
+func (s *Sampler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	<-s.done
+	s.cancel = nil
+}
+
+func (s *Sampler) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sample(ctx)
+		}
+	}
+}
+
+// Sample samples the pool statistics and pings the database.
+func (s *Sampler) Sample(ctx context.Context) {
+	pingErr := s.ping(ctx)
+	if pingErr != nil && ctx.Err() != nil {
+		return
+	}
+
+	stats := s.db.Stats()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var waitsSinceLastCheck int64
+	if s.sample != nil {
+		waitsSinceLastCheck = stats.WaitCount - s.sample.WaitCount
+	}
+
+	if pingErr != nil && s.pingErr == nil {
+		s.logger.Warnf("Database does not answer pings: %v", pingErr)
+	}
+
+	s.pingErr = pingErr
+	s.sample = &monitor.ConnectionPoolStats{
+		Enabled:             true,
+		SampledAt:           s.now().UnixMilli(),
+		MaxOpenConnections:  stats.MaxOpenConnections,
+		OpenConnections:     stats.OpenConnections,
+		InUse:               stats.InUse,
+		Idle:                stats.Idle,
+		WaitCount:           stats.WaitCount,
+		WaitDurationMs:      stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:       stats.MaxIdleClosed,
+		MaxIdleTimeClosed:   stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:   stats.MaxLifetimeClosed,
+		WaitsSinceLastCheck: waitsSinceLastCheck,
+	}
+}
+
+// ping checks the database answers. Exhausted pools are not pinged: the ping would wait for a connection
+// like the queries do, while the connections in use show the database answers.
+func (s *Sampler) ping(ctx context.Context) error {
+	if stats := s.db.Stats(); stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections {
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	return s.db.PingContext(pingCtx)
+}
+
+func (s *Sampler) ConnectionPoolStats(context.Context) (*monitor.ConnectionPoolStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.sample == nil {
+		return &monitor.ConnectionPoolStats{}, nil
+	}
+
+	sample := *s.sample
+
+	return &sample, nil
+}
+
+func (*Sampler) Name() string {
+	return componentName
+}
+
+// Health derives the health of the database from the latest sample, or from a ping when the pool is not sampled.
+func (s *Sampler) Health(ctx context.Context) *monitor.ComponentHealth {
+	if !s.config.Enabled {
+		if err := s.ping(ctx); err != nil {
+			return &monitor.ComponentHealth{Status: monitor.HealthDown, Reason: err.Error()}
+		}
+
+		return &monitor.ComponentHealth{Status: monitor.HealthUp}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.sample == nil {
+		return &monitor.ComponentHealth{Status: monitor.HealthUp}
+	}
+
+	sample := s.sample
+	health := &monitor.ComponentHealth{
+		Status: monitor.HealthUp,
+		Details: map[string]any{
+			"maxOpenConnections":  sample.MaxOpenConnections,
+			"inUse":               sample.InUse,
+			"idle":                sample.Idle,
+			"waitsSinceLastCheck": sample.WaitsSinceLastCheck,
+		},
+	}
+
+	switch {
+	case s.pingErr != nil:
+		health.Status = monitor.HealthDown
+		health.Reason = s.pingErr.Error()
+	case sample.MaxOpenConnections <= 0:
+		// Unlimited pools are never exhausted
+	case sample.InUse >= sample.MaxOpenConnections && sample.WaitsSinceLastCheck > 0:
+		health.Status = monitor.HealthDegraded
+		health.Reason = fmt.Sprintf("connection pool exhausted, %d queries waited for a connection", sample.WaitsSinceLastCheck)
+	case float64(sample.InUse) >= s.config.ExhaustionRatio*float64(sample.MaxOpenConnections):
+		health.Status = monitor.HealthDegraded
+		health.Reason = fmt.Sprintf("connection pool nearly exhausted, %d of %d connections in use", sample.InUse, sample.MaxOpenConnections)
+	}
+
+	return health
+}
@@ -0,0 +1,151 @@
+package poolstats
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlite"
+	ilog "github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/monitor"
+)
+
+type SamplerTestSuite struct {
+	suite.Suite
+
+	ctx     context.Context
+	db      *sql.DB
+	sampler *Sampler
+}
+
+func (suite *SamplerTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+
+	connector, _, err := sqlite.NewProvider().Connect(&config.DatasourceConfig{Type: constants.SQLite})
+	suite.Require().NoError(err)
+
+	suite.db = sql.OpenDB(connector)
+	suite.db.SetMaxOpenConns(2)
+	suite.sampler = NewSampler(&config.PoolMetricsConfig{Enabled: true, Interval: time.Hour}, suite.db, ilog.Named("poolstats"))
+}
+
+func (suite *SamplerTestSuite) TearDownTest() {
+	suite.sampler.Stop()
+	suite.NoError(suite.db.Close())
+}
+
+func (suite *SamplerTestSuite) stats() *monitor.ConnectionPoolStats {
+	stats, err := suite.sampler.ConnectionPoolStats(suite.ctx)
+	suite.Require().NoError(err)
+
+	return stats
+}
+
+func (suite *SamplerTestSuite) TestSample() {
+	suite.False(suite.stats().Enabled, "Stats should be empty before the first sample")
+
+	suite.sampler.Start(suite.ctx)
+
+	stats := suite.stats()
+	suite.True(stats.Enabled)
+	suite.Positive(stats.SampledAt)
+	suite.Equal(2, stats.MaxOpenConnections)
+	suite.Equal(1, stats.OpenConnections, "The ping should have opened a connection")
+	suite.Equal(monitor.HealthUp, suite.sampler.Health(suite.ctx).Status)
+}
+
+func (suite *SamplerTestSuite) TestNearlyExhausted() {
+	conns := suite.holdConnections(2)
+	defer closeAll(conns)
+
+	suite.sampler.Sample(suite.ctx)
+
+	health := suite.sampler.Health(suite.ctx)
+	suite.Equal(monitor.HealthDegraded, health.Status, "All connections in use should degrade the pool")
+	suite.Contains(health.Reason, "nearly exhausted")
+	suite.Equal(2, health.Details["inUse"])
+}
+
+func (suite *SamplerTestSuite) TestExhausted() {
+	suite.sampler.Sample(suite.ctx)
+
+	conns := suite.holdConnections(2)
+
+	waited := make(chan error)
+	go func() {
+		conn, err := suite.db.Conn(suite.ctx)
+		if err == nil {
+			err = conn.Close()
+		}
+
+		waited <- err
+	}()
+
+	suite.Eventually(func() bool {
+		return suite.db.Stats().WaitCount > 0
+	}, time.Second, 10*time.Millisecond, "A third connection should wait")
+
+	suite.sampler.Sample(suite.ctx)
+
+	health := suite.sampler.Health(suite.ctx)
+	suite.Equal(monitor.HealthDegraded, health.Status)
+	suite.Contains(health.Reason, "exhausted, 1 queries waited")
+	suite.Equal(int64(1), suite.stats().WaitsSinceLastCheck)
+
+	closeAll(conns)
+	suite.NoError(<-waited)
+
+	suite.sampler.Sample(suite.ctx)
+	suite.Equal(int64(0), suite.stats().WaitsSinceLastCheck, "Waits should be counted since the previous sample")
+	suite.Equal(monitor.HealthUp, suite.sampler.Health(suite.ctx).Status)
+}
+
+func (suite *SamplerTestSuite) TestDown() {
+	suite.sampler.Sample(suite.ctx)
+	suite.Require().NoError(suite.db.Close())
+	suite.sampler.Sample(suite.ctx)
+
+	health := suite.sampler.Health(suite.ctx)
+	suite.Equal(monitor.HealthDown, health.Status, "Databases not answering pings should be down")
+	suite.Contains(health.Reason, "database is closed")
+}
+
+func (suite *SamplerTestSuite) TestDisabled() {
+	sampler := NewSampler(&config.PoolMetricsConfig{}, suite.db, ilog.Named("poolstats"))
+	sampler.Start(suite.ctx)
+
+	stats, err := sampler.ConnectionPoolStats(suite.ctx)
+	suite.Require().NoError(err)
+	suite.False(stats.Enabled, "Disabled samplers should not sample the pool")
+
+	suite.Equal(monitor.HealthUp, sampler.Health(suite.ctx).Status, "Disabled samplers should ping the database")
+	suite.Require().NoError(suite.db.Close())
+	suite.Equal(monitor.HealthDown, sampler.Health(suite.ctx).Status)
+}
+
+func (suite *SamplerTestSuite) holdConnections(n int) []*sql.Conn {
+	conns := make([]*sql.Conn, n)
+	for i := range conns {
+		conn, err := suite.db.Conn(suite.ctx)
+		suite.Require().NoError(err)
+
+		conns[i] = conn
+	}
+
+	return conns
+}
+
+func closeAll(conns []*sql.Conn) {
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+}
+
+func TestSamplerSuite(t *testing.T) {
+	suite.Run(t, new(SamplerTestSuite))
+}
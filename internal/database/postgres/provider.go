@@ -1,8 +1,9 @@
 package postgres
 
 import (
-	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"slices"
 
 	"github.com/samber/lo"
 	"github.com/uptrace/bun"
@@ -12,6 +13,8 @@ import (
 
 	"github.com/ilxqx/vef-framework-go/config"
 	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/iamauth"
+	"github.com/ilxqx/vef-framework-go/internal/database/transport"
 )
 
 type Provider struct {
@@ -28,29 +31,78 @@ func (p *Provider) Type() constants.DBType {
 	return p.dbType
 }
 
-func (p *Provider) Connect(cfg *config.DatasourceConfig) (*sql.DB, schema.Dialect, error) {
+func (p *Provider) Connect(cfg *config.DatasourceConfig) (driver.Connector, schema.Dialect, error) {
 	if err := p.ValidateConfig(cfg); err != nil {
 		return nil, nil, err
 	}
 
-	connector := pgdriver.NewConnector(
+	host := lo.Ternary(cfg.Host != constants.Empty, cfg.Host, "127.0.0.1")
+	port := lo.Ternary(cfg.Port != 0, cfg.Port, uint16(5432))
+	user := lo.Ternary(cfg.User != constants.Empty, cfg.User, "postgres")
+
+	tlsConfig, err := transport.TLSConfig(cfg.TLS, host)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokenSource, err := iamauth.New(cfg, iamauth.Target{Type: p.dbType, Host: host, Port: port, User: user})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tunnel, err := transport.NewTunnel(cfg.SSHTunnel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options := []pgdriver.Option{
 		pgdriver.WithNetwork("tcp"),
-		pgdriver.WithAddr(fmt.Sprintf(
-			"%s:%d",
-			lo.Ternary(cfg.Host != constants.Empty, cfg.Host, "127.0.0.1"),
-			lo.Ternary(cfg.Port != 0, cfg.Port, uint16(5432)),
-		)),
-		pgdriver.WithInsecure(true),
-		pgdriver.WithUser(lo.Ternary(cfg.User != constants.Empty, cfg.User, "postgres")),
+		pgdriver.WithAddr(fmt.Sprintf("%s:%d", host, port)),
+		lo.TernaryF(
+			tlsConfig != nil,
+			func() pgdriver.Option { return pgdriver.WithTLSConfig(tlsConfig) },
+			func() pgdriver.Option { return pgdriver.WithInsecure(true) },
+		),
+		pgdriver.WithUser(user),
 		pgdriver.WithPassword(lo.Ternary(cfg.Password != constants.Empty, cfg.Password, "postgres")),
 		pgdriver.WithDatabase(lo.Ternary(cfg.Database != constants.Empty, cfg.Database, "postgres")),
 		pgdriver.WithApplicationName("vef"),
-		pgdriver.WithConnParams(map[string]any{
-			"search_path": lo.Ternary(cfg.Schema != constants.Empty, cfg.Schema, "public"),
-		}),
-	)
+		pgdriver.WithConnParams(connParams(cfg)),
+	}
+
+	if tunnel != nil {
+		options = append(options, func(c *pgdriver.Config) {
+			c.Dialer = tunnel.DialContext
+		})
+	}
+
+	var connector driver.Connector = pgdriver.NewConnector(options...)
+	if tokenSource != nil {
+		if connector, err = iamauth.NewConnector(tokenSource, cfg.Auth.RefreshBefore, func(password string) (driver.Connector, error) {
+			return pgdriver.NewConnector(slices.Concat(options, []pgdriver.Option{pgdriver.WithPassword(password)})...), nil
+		}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if tunnel != nil {
+		connector = transport.WithCloser(connector, tunnel)
+	}
+
+	return connector, pgdialect.New(), nil
+}
+
+// connParams returns the run-time parameters set on the connections.
+func connParams(cfg *config.DatasourceConfig) map[string]any {
+	params := map[string]any{
+		"search_path": lo.Ternary(cfg.Schema != constants.Empty, cfg.Schema, "public"),
+	}
+
+	if cfg.StatementTimeout > 0 {
+		params["statement_timeout"] = max(cfg.StatementTimeout.Milliseconds(), 1)
+	}
 
-	return sql.OpenDB(connector), pgdialect.New(), nil
+	return params
 }
 
 func (*Provider) ValidateConfig(_ *config.DatasourceConfig) error {
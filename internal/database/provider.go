@@ -1,7 +1,7 @@
 package database
 
 import (
-	"database/sql"
+	"database/sql/driver"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/schema"
@@ -14,7 +14,7 @@ import (
 )
 
 type DatabaseProvider interface {
-	Connect(config *config.DatasourceConfig) (*sql.DB, schema.Dialect, error)
+	Connect(config *config.DatasourceConfig) (driver.Connector, schema.Dialect, error)
 	Type() constants.DBType
 	ValidateConfig(config *config.DatasourceConfig) error
 	QueryVersion(db *bun.DB) (string, error)
@@ -14,6 +14,7 @@ import (
 
 	"github.com/ilxqx/vef-framework-go/constants"
 	"github.com/ilxqx/vef-framework-go/internal/database/sqlguard"
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlredact"
 	"github.com/ilxqx/vef-framework-go/log"
 )
 
@@ -56,7 +57,7 @@ func (qh *queryHook) AfterQuery(_ context.Context, event *bun.QueryEvent) {
 
 	elapsedStyle := qh.formatElapsedTime(elapsed)
 	operationStyle := qh.formatOperation(event.Operation())
-	queryStyle := qh.formatQuery(event.Query)
+	queryStyle := qh.formatQuery(sqlredact.Redact(event.Query))
 
 	displayErr := guardErr
 	if displayErr == nil {
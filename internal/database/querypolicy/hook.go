@@ -0,0 +1,288 @@
+package querypolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ajitpratap0/GoSQLX/pkg/gosqlx"
+	"github.com/ajitpratap0/GoSQLX/pkg/sql/ast"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlredact"
+	"github.com/ilxqx/vef-framework-go/log"
+)
+
+// Rule names reported for limit violations.
+const (
+	RuleMaxExecutionTime = "max_execution_time"
+	RuleMaxRows          = "max_rows"
+)
+
+// stateStashKey is the stash key for storing the policy state of a statement.
+const stateStashKey = "__query_policy_state"
+
+var (
+	// ErrPolicyViolation is returned when a statement violates its query policy.
+	ErrPolicyViolation = errors.New("query policy violation")
+	// ErrUnknownOperation is returned when a policy forbids an unknown operation.
+	ErrUnknownOperation = errors.New("unknown forbidden operation")
+)
+
+// ViolationError describes a query policy violation.
+type ViolationError struct {
+	Rule        string
+	Resource    string
+	Table       string
+	Description string
+}
+
+func (e *ViolationError) Error() string {
+	return fmt.Sprintf("%v: rule=%s, table=%s, resource=%s, description=%s",
+		ErrPolicyViolation, e.Rule, e.Table, e.Resource, e.Description)
+}
+
+func (*ViolationError) Unwrap() error {
+	return ErrPolicyViolation
+}
+
+// rejectionKey carries the slot receiving the violation the statements of a context are rejected for.
+type rejectionKey struct{}
+
+// WithRejection returns a context receiving the violation its statements are rejected for before execution.
+// Rejected statements fail with the cancellation of their context, Rejected reports the violation in place of it.
+func WithRejection(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rejectionKey{}, new(atomic.Pointer[ViolationError]))
+}
+
+// Rejected returns the violation a statement run with a context of WithRejection was rejected for in place of err,
+// its cancellation error, which is kept in the message only. Other errors are returned unchanged.
+func Rejected(ctx context.Context, err error) error {
+	slot, _ := ctx.Value(rejectionKey{}).(*atomic.Pointer[ViolationError])
+	if err == nil || slot == nil {
+		return err
+	}
+
+	violation := slot.Load()
+	if violation == nil || !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	return fmt.Errorf("%w (%v)", violation, err)
+}
+
+// statementState is the policy state of a statement shared between BeforeQuery and AfterQuery.
+type statementState struct {
+	policy   *Policy
+	resource string
+	table    string
+	timeout  *ViolationError
+	cancel   context.CancelFunc
+}
+
+// Hook enforces query policies on every statement.
+// Forbidden operations and selects limited above the row limit are rejected before execution,
+// statements exceeding the execution time are cancelled, and larger results are reported afterwards.
+type Hook struct {
+	resolver *Resolver
+	logger   log.Logger
+}
+
+// NewHook creates a query policy hook.
+func NewHook(cfg *config.QueryPolicyConfig, logger log.Logger) (*Hook, error) {
+	resolver, err := NewResolver(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Hook{
+		resolver: resolver,
+		logger:   logger,
+	}, nil
+}
+
+func (h *Hook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	var (
+		resource = Resource(ctx)
+		table    = tableName(event)
+		tree     *ast.AST
+	)
+
+	if table == constants.Empty && h.resolver.hasModels() {
+		tree = h.parse(event.Query)
+		table = statementTable(tree)
+	}
+
+	policy := h.resolver.Resolve(resource, table)
+	if policy.isEmpty() {
+		return ctx
+	}
+
+	if policy.needsStatement() {
+		if tree == nil {
+			tree = h.parse(event.Query)
+		}
+
+		if violation := checkStatement(policy, tree); violation != nil {
+			violation.Resource = resource
+			violation.Table = table
+			h.report(violation, event.Query)
+
+			if slot, ok := ctx.Value(rejectionKey{}).(*atomic.Pointer[ViolationError]); ok {
+				slot.CompareAndSwap(nil, violation)
+			}
+
+			cancelCtx, cancel := context.WithCancelCause(ctx)
+			cancel(violation)
+
+			return cancelCtx
+		}
+	}
+
+	state := &statementState{
+		policy:   policy,
+		resource: resource,
+		table:    table,
+	}
+
+	if policy.MaxExecutionTime > 0 {
+		state.timeout = &ViolationError{
+			Rule:        RuleMaxExecutionTime,
+			Resource:    resource,
+			Table:       table,
+			Description: "statement exceeded the maximum execution time of " + policy.MaxExecutionTime.String(),
+		}
+		ctx, state.cancel = context.WithTimeoutCause(ctx, policy.MaxExecutionTime, state.timeout)
+	}
+
+	if event.Stash == nil {
+		event.Stash = make(map[any]any)
+	}
+
+	event.Stash[stateStashKey] = state
+
+	return ctx
+}
+
+func (h *Hook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	state, _ := event.Stash[stateStashKey].(*statementState)
+	if state == nil {
+		return
+	}
+
+	if state.cancel != nil {
+		if context.Cause(ctx) == state.timeout {
+			h.report(state.timeout, event.Query)
+		}
+
+		// Open rows are still read with this context, so the deadline is left to expire and keeps bounding the iteration
+		if !isOpenRows(event) {
+			state.cancel()
+		}
+	}
+
+	if state.policy.MaxRows <= 0 || event.Result == nil {
+		return
+	}
+
+	if rows, err := event.Result.RowsAffected(); err == nil && rows > int64(state.policy.MaxRows) {
+		h.report(&ViolationError{
+			Rule:        RuleMaxRows,
+			Resource:    state.resource,
+			Table:       state.table,
+			Description: fmt.Sprintf("statement returned or affected %d rows, exceeding the maximum of %d", rows, state.policy.MaxRows),
+		}, event.Query)
+	}
+}
+
+func (h *Hook) parse(query string) *ast.AST {
+	tree, err := gosqlx.Parse(query)
+	if err != nil {
+		h.logger.Debugf("Failed to parse sql for query policy check: %v", err)
+
+		return nil
+	}
+
+	return tree
+}
+
+// report logs a violation for auditing.
+func (h *Hook) report(violation *ViolationError, query string) {
+	h.logger.Warnf("Query policy violation: rule=%s, table=%s, resource=%s, description=%s, sql=%s",
+		violation.Rule, violation.Table, violation.Resource, violation.Description, sqlredact.Redact(query))
+}
+
+// checkStatement checks the forbidden operations and the row limit of explicitly limited selects.
+// Statements that cannot be parsed are not checked.
+func checkStatement(policy *Policy, tree *ast.AST) *ViolationError {
+	if tree == nil {
+		return nil
+	}
+
+	for _, rule := range policy.Forbidden {
+		if violation := rule.Check(tree); violation != nil {
+			return &ViolationError{
+				Rule:        rule.Name(),
+				Description: violation.Description,
+			}
+		}
+	}
+
+	if policy.MaxRows <= 0 {
+		return nil
+	}
+
+	for _, stmt := range tree.Statements {
+		if s, ok := stmt.(*ast.SelectStatement); ok && s.Limit != nil && *s.Limit > policy.MaxRows {
+			return &ViolationError{
+				Rule:        RuleMaxRows,
+				Description: fmt.Sprintf("select limit %d exceeds the maximum of %d rows", *s.Limit, policy.MaxRows),
+			}
+		}
+	}
+
+	return nil
+}
+
+// tableName returns the table of the model query issuing the statement.
+func tableName(event *bun.QueryEvent) string {
+	if query, ok := event.IQuery.(interface{ GetTableName() string }); ok {
+		return strings.Trim(query.GetTableName(), `"`+"`")
+	}
+
+	return constants.Empty
+}
+
+// statementTable returns the table of the first parsed statement.
+func statementTable(tree *ast.AST) string {
+	if tree == nil || len(tree.Statements) == 0 {
+		return constants.Empty
+	}
+
+	switch s := tree.Statements[0].(type) {
+	case *ast.SelectStatement:
+		if len(s.From) > 0 {
+			return s.From[0].Name
+		}
+
+		return s.TableName
+	case *ast.InsertStatement:
+		return s.TableName
+	case *ast.UpdateStatement:
+		return s.TableName
+	case *ast.DeleteStatement:
+		return s.TableName
+	}
+
+	return constants.Empty
+}
+
+// isOpenRows reports whether the statement may have returned rows that are still read with its context,
+// which is the case when it succeeded without a result, e.g. SelectQuery.Rows or DB.QueryRowContext.
+func isOpenRows(event *bun.QueryEvent) bool {
+	return event.Err == nil && event.Result == nil
+}
@@ -0,0 +1,183 @@
+// Package querypolicy enforces execution limits on statements through a query hook.
+//
+// A policy bounds the execution time and the number of rows of a statement and forbids dangerous
+// operations such as deletes without a WHERE clause. The policy of a statement is resolved from the
+// table it targets and the API resource of the request issuing it, and violations are logged for auditing.
+package querypolicy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlguard"
+)
+
+// Forbidden operation names accepted in QueryPolicy.Forbidden.
+const (
+	OperationDrop               = "drop"
+	OperationTruncate           = "truncate"
+	OperationDeleteWithoutWhere = "delete_without_where"
+	OperationUpdateWithoutWhere = "update_without_where"
+)
+
+// operationRules maps forbidden operation names to the sql guard rules detecting them.
+var operationRules = map[string]sqlguard.Rule{
+	OperationDrop:               new(sqlguard.DropStatementRule),
+	OperationTruncate:           new(sqlguard.TruncateStatementRule),
+	OperationDeleteWithoutWhere: new(sqlguard.DeleteWithoutWhereRule),
+	OperationUpdateWithoutWhere: new(sqlguard.UpdateWithoutWhereRule),
+}
+
+// resourceKey carries the API resource issuing the statements of a request.
+type resourceKey struct{}
+
+// SetResource records the API resource of the request so that group policies apply to its statements.
+func SetResource(ctx fiber.Ctx, resource string) {
+	ctx.Locals(resourceKey{}, resource)
+	ctx.SetContext(context.WithValue(ctx.Context(), resourceKey{}, resource))
+}
+
+// Resource returns the API resource recorded in the context, or an empty string.
+func Resource(ctx context.Context) string {
+	resource, _ := ctx.Value(resourceKey{}).(string)
+
+	return resource
+}
+
+// Policy is the effective policy of a statement.
+type Policy struct {
+	MaxExecutionTime time.Duration
+	MaxRows          int
+	Forbidden        []sqlguard.Rule
+}
+
+// needsStatement reports whether enforcing the policy requires parsing the statement.
+func (p *Policy) needsStatement() bool {
+	return p.MaxRows > 0 || len(p.Forbidden) > 0
+}
+
+// isEmpty reports whether the policy enforces nothing.
+func (p *Policy) isEmpty() bool {
+	return p.MaxExecutionTime <= 0 && !p.needsStatement()
+}
+
+// Resolver resolves the effective policy of statements from the configuration.
+type Resolver struct {
+	defaultPolicy *Policy
+	groups        map[string]*Policy
+	groupKeys     []string
+	models        map[string]*Policy
+}
+
+// NewResolver creates a resolver, returning an error when a policy forbids an unknown operation.
+func NewResolver(cfg *config.QueryPolicyConfig) (*Resolver, error) {
+	defaultPolicy, err := newPolicy(&cfg.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Resolver{
+		defaultPolicy: defaultPolicy,
+		groups:        make(map[string]*Policy, len(cfg.Groups)),
+		models:        make(map[string]*Policy, len(cfg.Models)),
+	}
+
+	for group, policy := range cfg.Groups {
+		if r.groups[strings.Trim(group, constants.Slash)], err = newPolicy(&policy); err != nil {
+			return nil, fmt.Errorf("group %q: %w", group, err)
+		}
+	}
+
+	for table, policy := range cfg.Models {
+		if r.models[table], err = newPolicy(&policy); err != nil {
+			return nil, fmt.Errorf("model %q: %w", table, err)
+		}
+	}
+
+	for group := range r.groups {
+		r.groupKeys = append(r.groupKeys, group)
+	}
+
+	// Longest prefixes first so that the most specific group matches
+	slices.SortFunc(r.groupKeys, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	return r, nil
+}
+
+func newPolicy(cfg *config.QueryPolicy) (*Policy, error) {
+	policy := &Policy{
+		MaxExecutionTime: cfg.MaxExecutionTime,
+		MaxRows:          cfg.MaxRows,
+	}
+
+	for _, operation := range cfg.Forbidden {
+		rule, ok := operationRules[operation]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownOperation, operation)
+		}
+
+		policy.Forbidden = append(policy.Forbidden, rule)
+	}
+
+	return policy, nil
+}
+
+// hasModels reports whether any model policy is configured.
+func (r *Resolver) hasModels() bool {
+	return len(r.models) > 0
+}
+
+// Resolve returns the policy of a statement on the table issued for the resource.
+// Each field falls back from the model policy to the most specific group policy and then to the default policy.
+func (r *Resolver) Resolve(resource, table string) *Policy {
+	policy := *r.defaultPolicy
+
+	if group := r.matchGroup(resource); group != nil {
+		overlay(&policy, group)
+	}
+
+	if model, ok := r.models[table]; ok {
+		overlay(&policy, model)
+	}
+
+	return &policy
+}
+
+// matchGroup returns the policy of the longest group that equals the resource or is a path prefix of it.
+func (r *Resolver) matchGroup(resource string) *Policy {
+	if resource == constants.Empty {
+		return nil
+	}
+
+	resource = strings.Trim(resource, constants.Slash)
+	for _, group := range r.groupKeys {
+		if resource == group || strings.HasPrefix(resource, group+constants.Slash) {
+			return r.groups[group]
+		}
+	}
+
+	return nil
+}
+
+func overlay(policy, override *Policy) {
+	if override.MaxExecutionTime > 0 {
+		policy.MaxExecutionTime = override.MaxExecutionTime
+	}
+
+	if override.MaxRows > 0 {
+		policy.MaxRows = override.MaxRows
+	}
+
+	if len(override.Forbidden) > 0 {
+		policy.Forbidden = override.Forbidden
+	}
+}
@@ -0,0 +1,206 @@
+package querypolicy
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlite"
+	ilog "github.com/ilxqx/vef-framework-go/internal/log"
+)
+
+type policyItem struct {
+	bun.BaseModel `bun:"table:policy_item"`
+
+	ID   int64  `bun:"id,pk,autoincrement"`
+	Name string `bun:"name"`
+}
+
+func TestResolve(t *testing.T) {
+	resolver, err := NewResolver(&config.QueryPolicyConfig{
+		Default: config.QueryPolicy{MaxExecutionTime: time.Second, MaxRows: 1000},
+		Groups: map[string]config.QueryPolicy{
+			"sys":       {MaxRows: 500},
+			"sys/audit": {MaxExecutionTime: 5 * time.Second},
+		},
+		Models: map[string]config.QueryPolicy{
+			"sys_user": {MaxRows: 100, Forbidden: []string{OperationDeleteWithoutWhere}},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		resource         string
+		table            string
+		maxExecutionTime time.Duration
+		maxRows          int
+		forbidden        int
+	}{
+		{"Default", "", "orders", time.Second, 1000, 0},
+		{"Group", "sys/role", "orders", time.Second, 500, 0},
+		{"MostSpecificGroup", "sys/audit/log", "orders", 5 * time.Second, 1000, 0},
+		{"GroupPrefixBoundary", "system/role", "orders", time.Second, 1000, 0},
+		{"Model", "", "sys_user", time.Second, 100, 1},
+		{"ModelOverGroup", "sys/audit", "sys_user", 5 * time.Second, 100, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := resolver.Resolve(tt.resource, tt.table)
+
+			assert.Equal(t, tt.maxExecutionTime, policy.MaxExecutionTime, "Should resolve the max execution time")
+			assert.Equal(t, tt.maxRows, policy.MaxRows, "Should resolve the max rows")
+			assert.Len(t, policy.Forbidden, tt.forbidden, "Should resolve the forbidden operations")
+		})
+	}
+}
+
+func TestNewResolverUnknownOperation(t *testing.T) {
+	_, err := NewResolver(&config.QueryPolicyConfig{
+		Models: map[string]config.QueryPolicy{
+			"sys_user": {Forbidden: []string{"delete_everything"}},
+		},
+	})
+	require.ErrorIs(t, err, ErrUnknownOperation, "Should reject unknown forbidden operations")
+}
+
+type HookTestSuite struct {
+	suite.Suite
+
+	ctx context.Context
+	db  *bun.DB
+}
+
+func (suite *HookTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+
+	// A file database survives the connection dropped by a cancelled statement
+	connector, dialect, err := sqlite.NewProvider().Connect(&config.DatasourceConfig{
+		Type: constants.SQLite,
+		Path: filepath.Join(suite.T().TempDir(), "policy.db"),
+	})
+	suite.Require().NoError(err)
+
+	hook, err := NewHook(&config.QueryPolicyConfig{
+		Enabled: true,
+		Groups: map[string]config.QueryPolicy{
+			"sys": {MaxExecutionTime: 50 * time.Millisecond},
+		},
+		Models: map[string]config.QueryPolicy{
+			"policy_item": {
+				MaxRows:   2,
+				Forbidden: []string{OperationDeleteWithoutWhere, OperationUpdateWithoutWhere},
+			},
+		},
+	}, ilog.Named("query_policy"))
+	suite.Require().NoError(err)
+
+	sqlDB := sql.OpenDB(connector)
+	sqlDB.SetMaxOpenConns(1)
+
+	suite.db = bun.NewDB(sqlDB, dialect)
+
+	_, err = suite.db.NewCreateTable().Model((*policyItem)(nil)).Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	items := []policyItem{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	_, err = suite.db.NewInsert().Model(&items).Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	suite.db.AddQueryHook(hook)
+}
+
+func (suite *HookTestSuite) TearDownTest() {
+	suite.Require().NoError(suite.db.Close())
+}
+
+func (suite *HookTestSuite) count() int {
+	var count int
+	suite.Require().NoError(suite.db.NewRaw("SELECT COUNT(*) FROM policy_item").Scan(suite.ctx, &count))
+
+	return count
+}
+
+func (suite *HookTestSuite) TestForbiddenOperations() {
+	_, err := suite.db.NewDelete().Model((*policyItem)(nil)).Where("1 = 1").Exec(suite.ctx)
+	suite.NoError(err, "Delete with WHERE should be allowed")
+	suite.Equal(0, suite.count())
+
+	items := []policyItem{{Name: "a"}}
+	_, err = suite.db.NewInsert().Model(&items).Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	ctx := WithRejection(suite.ctx)
+
+	_, err = suite.db.NewRaw("DELETE FROM policy_item").Exec(ctx)
+	suite.ErrorIs(Rejected(ctx, err), ErrPolicyViolation, "Delete without WHERE should be rejected")
+
+	ctx = WithRejection(suite.ctx)
+
+	_, err = suite.db.NewRaw("UPDATE policy_item SET name = 'x'").Exec(ctx)
+	suite.ErrorIs(Rejected(ctx, err), ErrPolicyViolation, "Update without WHERE should be rejected")
+
+	var violation *ViolationError
+	suite.Require().ErrorAs(Rejected(ctx, err), &violation)
+	suite.Equal("policy_item", violation.Table, "Should report the table of the violation")
+	suite.Equal(1, suite.count(), "Rejected statements should not be executed")
+}
+
+func (suite *HookTestSuite) TestMaxRows() {
+	var items []policyItem
+
+	err := suite.db.NewSelect().Model(&items).Limit(2).Scan(suite.ctx)
+	suite.NoError(err, "Select limited to the maximum should be allowed")
+	suite.Len(items, 2)
+
+	ctx := WithRejection(suite.ctx)
+
+	err = suite.db.NewSelect().Model(&items).Limit(10).Scan(ctx)
+	suite.ErrorIs(Rejected(ctx, err), ErrPolicyViolation, "Select limited above the maximum should be rejected")
+
+	err = suite.db.NewSelect().Model(&items).Scan(suite.ctx)
+	suite.NoError(err, "Select without limit should only be reported")
+	suite.Len(items, 3)
+}
+
+func (suite *HookTestSuite) TestMaxExecutionTime() {
+	query := "WITH RECURSIVE n(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM n) SELECT COUNT(*) FROM n"
+
+	var count int
+
+	err := suite.db.NewRaw(query).Scan(context.WithValue(suite.ctx, resourceKey{}, "sys/user"), &count)
+	suite.ErrorIs(err, context.DeadlineExceeded, "Statement exceeding the group execution time should be cancelled")
+
+	err = suite.db.NewRaw("SELECT COUNT(*) FROM policy_item").Scan(context.WithValue(suite.ctx, resourceKey{}, "sys/user"), &count)
+	suite.NoError(err, "Statement within the execution time should succeed")
+	suite.Equal(3, count)
+}
+
+func (suite *HookTestSuite) TestRowsWithExecutionTime() {
+	rows, err := suite.db.NewSelect().Model((*policyItem)(nil)).Rows(context.WithValue(suite.ctx, resourceKey{}, "sys"))
+	suite.Require().NoError(err)
+
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		count++
+	}
+
+	suite.NoError(rows.Err(), "Open rows should remain readable after the statement returns")
+	suite.Equal(3, count)
+}
+
+func TestHookTestSuite(t *testing.T) {
+	suite.Run(t, new(HookTestSuite))
+}
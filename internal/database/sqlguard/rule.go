@@ -92,6 +92,40 @@ func (r *DeleteWithoutWhereRule) Check(astNode *ast.AST) *Violation {
 	return nil
 }
 
+// UpdateWithoutWhereRule blocks UPDATE statements without WHERE clause.
+// It is not part of the default rules and is enabled through query policies.
+type UpdateWithoutWhereRule struct{}
+
+func (*UpdateWithoutWhereRule) Name() string {
+	return "update_requires_where"
+}
+
+func (r *UpdateWithoutWhereRule) Check(astNode *ast.AST) *Violation {
+	for _, stmt := range astNode.Statements {
+		switch s := stmt.(type) {
+		case *ast.UpdateStatement:
+			if s.Where == nil {
+				return &Violation{
+					Rule:        r.Name(),
+					Statement:   "UPDATE",
+					Description: "UPDATE statements without WHERE clause are prohibited",
+				}
+			}
+
+		case *ast.Update:
+			if s.Where == nil {
+				return &Violation{
+					Rule:        r.Name(),
+					Statement:   "UPDATE",
+					Description: "UPDATE statements without WHERE clause are prohibited",
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 // DefaultRules returns the default set of SQL checking rules.
 func DefaultRules() []Rule {
 	return []Rule{
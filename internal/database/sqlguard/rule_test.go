@@ -109,6 +109,36 @@ func TestDeleteWithoutWhereRule(t *testing.T) {
 	}
 }
 
+func TestUpdateWithoutWhereRule(t *testing.T) {
+	rule := new(UpdateWithoutWhereRule)
+
+	tests := []struct {
+		name      string
+		sql       string
+		wantBlock bool
+	}{
+		{"UpdateWithoutWhere", "UPDATE users SET name = 'test'", true},
+		{"UpdateWithWhere", "UPDATE users SET name = 'test' WHERE id = 1", false},
+		{"SelectQuery", "SELECT * FROM users", false},
+		{"DeleteWithoutWhere", "DELETE FROM users", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			astNode := parseSQL(t, tt.sql)
+			violation := rule.Check(astNode)
+
+			if tt.wantBlock {
+				require.NotNil(t, violation, "Should block UPDATE without WHERE")
+				assert.Equal(t, "update_requires_where", violation.Rule)
+				assert.Equal(t, "UPDATE", violation.Statement)
+			} else {
+				assert.Nil(t, violation, "Should allow statement")
+			}
+		})
+	}
+}
+
 func TestDefaultRules(t *testing.T) {
 	rules := DefaultRules()
 
@@ -0,0 +1,213 @@
+//go:build !cgosqlite && ((darwin && amd64) || (darwin && arm64) || (linux && 386) || (linux && amd64) || (linux && arm) || (linux && arm64) || (windows && amd64))
+
+package sqlite
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/md5"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"regexp"
+	"slices"
+	"sync"
+
+	"github.com/uptrace/bun/driver/sqliteshim"
+	"modernc.org/sqlite"
+)
+
+// maxCachedPatterns bounds the compiled patterns kept between calls, the cache is reset once exceeded.
+const maxCachedPatterns = 256
+
+var (
+	registerFunctionsOnce sync.Once
+	patternsMu            sync.Mutex
+	patterns              = make(map[string]*regexp.Regexp)
+)
+
+// sqliteDriver returns the driver registered by modernc.org/sqlite, which unlike the driver of sqliteshim
+// carries the functions registered on the package, and provides the REGEXP operator and hash functions on it.
+func sqliteDriver() driver.Driver {
+	registerFunctions()
+
+	db, err := sql.Open("sqlite", "")
+	if err != nil {
+		return sqliteshim.Driver()
+	}
+
+	defer func() { _ = db.Close() }()
+
+	return db.Driver()
+}
+
+// registerFunctions provides the REGEXP operator, which SQLite leaves to an application defined regexp function,
+// using Go regular expressions, the md5, sha256, crc32, aes_encrypt and aes_decrypt functions SQLite lacks, and the
+// MakePoint, ST_X and ST_Y functions of SpatiaLite for points, on which spatial distances are computed.
+// A function registered beforehand, e.g. by an extension, is kept. The functions are available to connections
+// opened afterwards, so they are registered before connecting.
+func registerFunctions() {
+	registerFunctionsOnce.Do(func() {
+		_ = sqlite.RegisterDeterministicScalarFunction("regexp", 2, matchRegexp)
+		_ = sqlite.RegisterDeterministicScalarFunction("md5", 1, hashFunc(func(data []byte) driver.Value {
+			sum := md5.Sum(data)
+
+			return hex.EncodeToString(sum[:])
+		}))
+		_ = sqlite.RegisterDeterministicScalarFunction("sha256", 1, hashFunc(func(data []byte) driver.Value {
+			sum := sha256.Sum256(data)
+
+			return hex.EncodeToString(sum[:])
+		}))
+		_ = sqlite.RegisterDeterministicScalarFunction("crc32", 1, hashFunc(func(data []byte) driver.Value {
+			return int64(crc32.ChecksumIEEE(data))
+		}))
+		_ = sqlite.RegisterDeterministicScalarFunction("aes_encrypt", 2, aesEncrypt)
+		_ = sqlite.RegisterDeterministicScalarFunction("aes_decrypt", 2, aesDecrypt)
+		_ = sqlite.RegisterDeterministicScalarFunction("makepoint", -1, makePoint)
+		_ = sqlite.RegisterDeterministicScalarFunction("st_x", 1, pointCoordinate(0))
+		_ = sqlite.RegisterDeterministicScalarFunction("st_y", 1, pointCoordinate(1))
+	})
+}
+
+// matchRegexp implements "value REGEXP pattern", which SQLite calls as regexp(pattern, value).
+func matchRegexp(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+
+	pattern, err := compilePattern(fmt.Sprint(args[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := args[1].(type) {
+	case []byte:
+		return pattern.Match(value), nil
+	case string:
+		return pattern.MatchString(value), nil
+	default:
+		return pattern.MatchString(fmt.Sprint(value)), nil
+	}
+}
+
+func compilePattern(expr string) (*regexp.Regexp, error) {
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+
+	if pattern, ok := patterns[expr]; ok {
+		return pattern, nil
+	}
+
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %w", expr, err)
+	}
+
+	if len(patterns) >= maxCachedPatterns {
+		clear(patterns)
+	}
+
+	patterns[expr] = pattern
+
+	return pattern, nil
+}
+
+// hashFunc adapts a hash of bytes to a scalar function hashing the UTF-8 bytes of text and the bytes of blobs,
+// other values by their text form as the other databases convert them, and returning NULL for NULL.
+func hashFunc(hash func(data []byte) driver.Value) func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+	return func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		data, ok := valueBytes(args[0])
+		if !ok {
+			return nil, nil
+		}
+
+		return hash(data), nil
+	}
+}
+
+// valueBytes returns the UTF-8 bytes of text, the bytes of blobs and the text form of other values, false for NULL.
+func valueBytes(value driver.Value) ([]byte, bool) {
+	switch value := value.(type) {
+	case nil:
+		return nil, false
+	case []byte:
+		return value, true
+	case string:
+		return []byte(value), true
+	default:
+		return []byte(fmt.Sprint(value)), true
+	}
+}
+
+// aesEncrypt implements aes_encrypt(value, key) as MySQL's AES_ENCRYPT does in its default aes-128-ecb mode,
+// so values encrypted by either database decrypt on the other.
+func aesEncrypt(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	plaintext, ok := valueBytes(args[0])
+	if !ok {
+		return nil, nil
+	}
+
+	key, ok := valueBytes(args[1])
+	if !ok {
+		return nil, nil
+	}
+
+	block, err := aes.NewCipher(foldAESKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := append(slices.Clone(plaintext), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	for i := 0; i < len(ciphertext); i += aes.BlockSize {
+		block.Encrypt(ciphertext[i:i+aes.BlockSize], ciphertext[i:i+aes.BlockSize])
+	}
+
+	return ciphertext, nil
+}
+
+// aesDecrypt implements aes_decrypt(value, key) as MySQL's AES_DECRYPT does, returning NULL for values
+// not encrypted with the key.
+func aesDecrypt(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	ciphertext, ok := valueBytes(args[0])
+	if !ok || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, nil
+	}
+
+	key, ok := valueBytes(args[1])
+	if !ok {
+		return nil, nil
+	}
+
+	block, err := aes.NewCipher(foldAESKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	for i := 0; i < len(ciphertext); i += aes.BlockSize {
+		block.Decrypt(plaintext[i:i+aes.BlockSize], ciphertext[i:i+aes.BlockSize])
+	}
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, nil
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// foldAESKey derives the 128-bit key from a key of any length by XOR-ing its bytes into 16 bytes, as MySQL does.
+func foldAESKey(key []byte) []byte {
+	folded := make([]byte, 16)
+	for i, b := range key {
+		folded[i%len(folded)] ^= b
+	}
+
+	return folded
+}
@@ -0,0 +1,15 @@
+//go:build cgosqlite || !((darwin && amd64) || (darwin && arm64) || (linux && 386) || (linux && amd64) || (linux && arm) || (linux && arm64) || (windows && amd64))
+
+package sqlite
+
+import (
+	"database/sql/driver"
+
+	"github.com/uptrace/bun/driver/sqliteshim"
+)
+
+// sqliteDriver returns the cgo driver of sqliteshim, whose REGEXP operator, md5, sha256, crc32, aes_encrypt
+// and aes_decrypt functions and spatial functions require a loaded extension providing them, e.g. SpatiaLite.
+func sqliteDriver() driver.Driver {
+	return sqliteshim.Driver()
+}
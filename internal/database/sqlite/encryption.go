@@ -0,0 +1,148 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// EncryptionKey resolves the passphrase of an encrypted database from the literal key,
+// the environment variable or the file configured, trailing line breaks of files are ignored.
+func EncryptionKey(cfg config.SQLiteEncryptionConfig) (string, error) {
+	var sources int
+
+	for _, source := range []string{cfg.Key, cfg.KeyEnv, cfg.KeyFile} {
+		if source != constants.Empty {
+			sources++
+		}
+	}
+
+	if sources != 1 {
+		return constants.Empty, ErrEncryptionKeyRequired
+	}
+
+	var key string
+
+	switch {
+	case cfg.Key != constants.Empty:
+		key = cfg.Key
+	case cfg.KeyEnv != constants.Empty:
+		key = os.Getenv(cfg.KeyEnv)
+	default:
+		content, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return constants.Empty, fmt.Errorf("failed to read sqlite encryption key file: %w", err)
+		}
+
+		key = strings.TrimRight(string(content), "\r\n")
+	}
+
+	if key == constants.Empty {
+		return constants.Empty, ErrEncryptionKeyEmpty
+	}
+
+	return key, nil
+}
+
+// Rekey re-encrypts an encrypted database with a new passphrase. The other connections of db keep the old key
+// and fail afterwards, so db should be limited to one connection and be closed once rekeyed.
+func Rekey(ctx context.Context, db *sql.DB, key string) error {
+	if key == constants.Empty {
+		return ErrEncryptionKeyEmpty
+	}
+
+	if _, err := db.ExecContext(ctx, "PRAGMA rekey = "+quoteKey(key)); err != nil {
+		return fmt.Errorf("failed to rekey sqlite database: %w", err)
+	}
+
+	return nil
+}
+
+// encryptedConnector keys every connection before it is used, as SQLCipher requires the key to be the first statement.
+type encryptedConnector struct {
+	driver.Connector
+
+	key string
+}
+
+func (c *encryptedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyKey(ctx, conn, c.key); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// applyKey keys the connection and reads the schema, which fails on a wrong key.
+// Builds of SQLite without SQLCipher ignore the key pragma, they are detected by the missing cipher version.
+func applyKey(ctx context.Context, conn driver.Conn, key string) error {
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		return ErrConnNotExecutable
+	}
+
+	queryer, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return ErrConnNotExecutable
+	}
+
+	if _, err := execer.ExecContext(ctx, "PRAGMA key = "+quoteKey(key), nil); err != nil {
+		return fmt.Errorf("failed to key sqlite database: %w", err)
+	}
+
+	hasCipher, err := queryRow(ctx, queryer, "PRAGMA cipher_version")
+	if err != nil {
+		return fmt.Errorf("failed to query sqlite cipher version: %w", err)
+	}
+
+	if !hasCipher {
+		return ErrEncryptionNotSupported
+	}
+
+	if _, err := queryRow(ctx, queryer, "SELECT COUNT(*) FROM sqlite_master"); err != nil {
+		return fmt.Errorf("failed to decrypt sqlite database, check the encryption key: %w", err)
+	}
+
+	return nil
+}
+
+// queryRow runs a query and reports whether it returned a row.
+func queryRow(ctx context.Context, queryer driver.QueryerContext, query string) (bool, error) {
+	rows, err := queryer.QueryContext(ctx, query, nil)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	dest := make([]driver.Value, len(rows.Columns()))
+	if err := rows.Next(dest); err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// quoteKey quotes a passphrase as string literal, pragmas do not accept bound parameters.
+func quoteKey(key string) string {
+	return "'" + strings.ReplaceAll(key, "'", "''") + "'"
+}
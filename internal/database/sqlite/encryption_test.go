@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+func TestEncryptionKey(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("file-secret\n"), 0o600))
+	t.Setenv("VEF_TEST_SQLITE_KEY", "env-secret")
+	t.Setenv("VEF_TEST_SQLITE_EMPTY_KEY", "")
+
+	tests := []struct {
+		name string
+		cfg  config.SQLiteEncryptionConfig
+		key  string
+		err  error
+	}{
+		{"Key", config.SQLiteEncryptionConfig{Key: "secret"}, "secret", nil},
+		{"KeyEnv", config.SQLiteEncryptionConfig{KeyEnv: "VEF_TEST_SQLITE_KEY"}, "env-secret", nil},
+		{"KeyFile", config.SQLiteEncryptionConfig{KeyFile: keyFile}, "file-secret", nil},
+		{"NoSource", config.SQLiteEncryptionConfig{}, "", ErrEncryptionKeyRequired},
+		{"SeveralSources", config.SQLiteEncryptionConfig{Key: "secret", KeyEnv: "VEF_TEST_SQLITE_KEY"}, "", ErrEncryptionKeyRequired},
+		{"EmptyKey", config.SQLiteEncryptionConfig{KeyEnv: "VEF_TEST_SQLITE_EMPTY_KEY"}, "", ErrEncryptionKeyEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := EncryptionKey(tt.cfg)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.key, key)
+		})
+	}
+}
+
+func TestEncryptedConnect(t *testing.T) {
+	provider := NewProvider()
+
+	t.Run("RequiresPath", func(t *testing.T) {
+		_, _, err := provider.Connect(&config.DatasourceConfig{
+			Type:       constants.SQLite,
+			Encryption: config.SQLiteEncryptionConfig{Enabled: true, Key: "secret"},
+		})
+		assert.ErrorIs(t, err, ErrEncryptionRequiresPath)
+	})
+
+	t.Run("DriverWithoutCipher", func(t *testing.T) {
+		connector, _, err := provider.Connect(&config.DatasourceConfig{
+			Type:       constants.SQLite,
+			Path:       filepath.Join(t.TempDir(), "app.db"),
+			Encryption: config.SQLiteEncryptionConfig{Enabled: true, Key: "secret"},
+		})
+		require.NoError(t, err)
+
+		_, err = connector.Connect(context.Background())
+		assert.ErrorIs(t, err, ErrEncryptionNotSupported, "SQLite builds without SQLCipher should be rejected instead of storing plain data")
+	})
+}
+
+func TestQuoteKey(t *testing.T) {
+	assert.Equal(t, `'it''s secret'`, quoteKey("it's secret"))
+}
@@ -0,0 +1,14 @@
+package sqlite
+
+import "errors"
+
+var (
+	ErrTLSNotSupported          = errors.New("tls is not supported for SQLite")
+	ErrSSHTunnelNotSupported    = errors.New("ssh_tunnel is not supported for SQLite")
+	ErrAuthProviderNotSupported = errors.New("auth provider is not supported for SQLite")
+	ErrEncryptionRequiresPath   = errors.New("encryption requires a database path, in-memory databases are not encrypted")
+	ErrEncryptionKeyRequired    = errors.New("encryption requires exactly one of key, key_env or key_file")
+	ErrEncryptionKeyEmpty       = errors.New("encryption key is empty")
+	ErrEncryptionNotSupported   = errors.New("encryption is not supported by the SQLite driver, build with the cgosqlite and libsqlite3 tags against a SQLCipher libsqlite3")
+	ErrConnNotExecutable        = errors.New("SQLite driver connection cannot execute statements")
+)
@@ -0,0 +1,110 @@
+//go:build !cgosqlite && ((darwin && amd64) || (darwin && arm64) || (linux && 386) || (linux && amd64) || (linux && arm) || (linux && arm64) || (windows && amd64))
+
+package sqlite
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"modernc.org/sqlite"
+)
+
+// The markers and class of the SpatiaLite BLOB-Geometry format of points.
+const (
+	geometryStart     = 0x00
+	geometryMBREnd    = 0x7C
+	geometryEnd       = 0xFE
+	geometryLittle    = 0x01
+	geometryBig       = 0x00
+	geometryPoint     = 1
+	geometryPointSize = 60
+)
+
+// makePoint implements MakePoint(x, y [, srid]) as SpatiaLite does, encoding the point in the BLOB-Geometry format
+// of SpatiaLite, so points stored without SpatiaLite read with it and the other way round.
+func makePoint(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, fmt.Errorf("wrong number of arguments to function MakePoint(): %d", len(args))
+	}
+
+	x, ok := valueFloat(args[0])
+	if !ok {
+		return nil, nil
+	}
+
+	y, ok := valueFloat(args[1])
+	if !ok {
+		return nil, nil
+	}
+
+	var srid int64
+	if len(args) == 3 {
+		value, ok := args[2].(int64)
+		if !ok {
+			return nil, nil
+		}
+
+		srid = value
+	}
+
+	blob := make([]byte, 0, geometryPointSize)
+	blob = append(blob, geometryStart, geometryLittle)
+	blob = binary.LittleEndian.AppendUint32(blob, uint32(int32(srid)))
+
+	// The minimum bounding rectangle of a point is the point itself.
+	for _, coordinate := range []float64{x, y, x, y} {
+		blob = binary.LittleEndian.AppendUint64(blob, math.Float64bits(coordinate))
+	}
+
+	blob = append(blob, geometryMBREnd)
+	blob = binary.LittleEndian.AppendUint32(blob, geometryPoint)
+	blob = binary.LittleEndian.AppendUint64(blob, math.Float64bits(x))
+	blob = binary.LittleEndian.AppendUint64(blob, math.Float64bits(y))
+
+	return append(blob, geometryEnd), nil
+}
+
+// pointCoordinate adapts the reading of a coordinate of a point to ST_X and ST_Y, which return NULL
+// for values other than points.
+func pointCoordinate(index int) func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+	return func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		blob, ok := args[0].([]byte)
+		if !ok || len(blob) != geometryPointSize || blob[0] != geometryStart || blob[38] != geometryMBREnd ||
+			blob[geometryPointSize-1] != geometryEnd {
+			return nil, nil
+		}
+
+		var order binary.ByteOrder
+
+		switch blob[1] {
+		case geometryLittle:
+			order = binary.LittleEndian
+		case geometryBig:
+			order = binary.BigEndian
+		default:
+			return nil, nil
+		}
+
+		if order.Uint32(blob[39:43]) != geometryPoint {
+			return nil, nil
+		}
+
+		offset := 43 + index*8
+
+		return math.Float64frombits(order.Uint64(blob[offset : offset+8])), nil
+	}
+}
+
+// valueFloat returns numbers as floats, false for NULL and other values.
+func valueFloat(value driver.Value) (float64, bool) {
+	switch value := value.(type) {
+	case int64:
+		return float64(value), true
+	case float64:
+		return value, true
+	default:
+		return 0, false
+	}
+}
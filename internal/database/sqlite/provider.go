@@ -1,12 +1,12 @@
 package sqlite
 
 import (
-	"database/sql"
+	"context"
+	"database/sql/driver"
 	"fmt"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/sqlitedialect"
-	"github.com/uptrace/bun/driver/sqliteshim"
 	"github.com/uptrace/bun/schema"
 
 	"github.com/ilxqx/vef-framework-go/config"
@@ -27,20 +27,59 @@ func (p *Provider) Type() constants.DBType {
 	return p.dbType
 }
 
-func (p *Provider) Connect(cfg *config.DatasourceConfig) (*sql.DB, schema.Dialect, error) {
+func (p *Provider) Connect(cfg *config.DatasourceConfig) (driver.Connector, schema.Dialect, error) {
 	if err := p.ValidateConfig(cfg); err != nil {
 		return nil, nil, err
 	}
 
-	db, err := sql.Open(sqliteshim.ShimName, p.buildDsn(cfg))
+	connector, err := p.openConnector(p.buildDsn(cfg))
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to open sqlite database: %w", err)
+		return nil, nil, err
+	}
+
+	if cfg.Encryption.Enabled {
+		key, err := EncryptionKey(cfg.Encryption)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		connector = &encryptedConnector{Connector: connector, key: key}
+	}
+
+	return connector, sqlitedialect.New(), nil
+}
+
+func (*Provider) openConnector(dsn string) (driver.Connector, error) {
+	drv := sqliteDriver()
+	if driverContext, ok := drv.(driver.DriverContext); ok {
+		connector, err := driverContext.OpenConnector(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+		}
+
+		return connector, nil
 	}
 
-	return db, sqlitedialect.New(), nil
+	return &dsnConnector{dsn: dsn, driver: drv}, nil
 }
 
-func (*Provider) ValidateConfig(_ *config.DatasourceConfig) error {
+func (*Provider) ValidateConfig(cfg *config.DatasourceConfig) error {
+	if cfg.TLS.Mode != constants.Empty && cfg.TLS.Mode != config.TLSModeDisable {
+		return ErrTLSNotSupported
+	}
+
+	if cfg.SSHTunnel.Enabled {
+		return ErrSSHTunnelNotSupported
+	}
+
+	if cfg.Auth.Provider != constants.Empty {
+		return ErrAuthProviderNotSupported
+	}
+
+	if cfg.Encryption.Enabled && cfg.Path == constants.Empty {
+		return ErrEncryptionRequiresPath
+	}
+
 	return nil
 }
 
@@ -58,3 +97,17 @@ func (*Provider) buildDsn(cfg *config.DatasourceConfig) string {
 
 	return "file:" + cfg.Path
 }
+
+// dsnConnector adapts a driver without connector support, mirroring the connector used by sql.Open.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
@@ -0,0 +1,56 @@
+// Package sqlredact masks secrets inlined into statements, e.g. the keys of encryption expressions,
+// wherever statements are logged or reported.
+package sqlredact
+
+import (
+	"strings"
+	"sync"
+)
+
+// Mask replaces the secrets in redacted statements.
+const Mask = "******"
+
+var (
+	mu       sync.RWMutex
+	secrets  = make(map[string]struct{})
+	replacer *strings.Replacer
+)
+
+// Register registers a secret to mask in statements. Empty secrets are ignored.
+func Register(secret string) {
+	if secret == "" {
+		return
+	}
+
+	mu.RLock()
+	_, ok := secrets[secret]
+	mu.RUnlock()
+
+	if ok {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	secrets[secret] = struct{}{}
+
+	oldnew := make([]string, 0, len(secrets)*2)
+	for s := range secrets {
+		oldnew = append(oldnew, s, Mask)
+	}
+
+	replacer = strings.NewReplacer(oldnew...)
+}
+
+// Redact returns the statement with the registered secrets masked.
+func Redact(query string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if replacer == nil {
+		return query
+	}
+
+	return replacer.Replace(query)
+}
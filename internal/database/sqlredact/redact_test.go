@@ -0,0 +1,22 @@
+package sqlredact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	assert.Equal(t, "SELECT 1", Redact("SELECT 1"), "Statements should be kept without registered secrets")
+
+	Register("")
+	Register("s3cr3t-key")
+	Register("s3cr3t-key")
+	Register("other-key")
+
+	assert.Equal(t,
+		"SELECT AES_ENCRYPT('x', '******'), AES_DECRYPT(y, '******')",
+		Redact("SELECT AES_ENCRYPT('x', 's3cr3t-key'), AES_DECRYPT(y, 'other-key')"),
+	)
+	assert.Equal(t, "SELECT 1", Redact("SELECT 1"))
+}
@@ -0,0 +1,132 @@
+// Package tabletag tags work with the tables its queries touch, so results derived from the database,
+// e.g. cached responses, can be invalidated when those tables change.
+//
+// A context started with Track records the model table of every select executed with it, the query hook
+// notifies the listeners of the tracker of the model table of every successful insert, update, delete and merge.
+// Writes within a transaction are notified when executed and again once it commits, so responses read meanwhile,
+// before the write became visible, are outdated as well; writes rolled back are not notified again.
+// Raw queries carry no model and are neither recorded nor notified, nor are tables only joined by a select.
+package tabletag
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/txcommit"
+)
+
+// readsKey carries the Reads of a tracked context.
+type readsKey struct{}
+
+// Reads collects the tables read by the queries of a tracked context.
+type Reads struct {
+	mu     sync.Mutex
+	tables map[string]struct{}
+}
+
+// Tables returns the sorted tables read so far.
+func (r *Reads) Tables() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tables := make([]string, 0, len(r.tables))
+	for table := range r.tables {
+		tables = append(tables, table)
+	}
+
+	slices.Sort(tables)
+
+	return tables
+}
+
+func (r *Reads) add(table string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tables[table] = struct{}{}
+}
+
+// Track makes the request context record the tables read by the queries executed with it,
+// whether handlers query with the fiber context or with the context it carries.
+func Track(ctx fiber.Ctx) *Reads {
+	reads := &Reads{tables: make(map[string]struct{})}
+
+	ctx.Locals(readsKey{}, reads)
+	ctx.SetContext(context.WithValue(ctx.Context(), readsKey{}, reads))
+
+	return reads
+}
+
+// WriteListener is notified of the table written by a successful statement.
+type WriteListener func(ctx context.Context, table string)
+
+// Tracker is a query hook recording the tables read by tracked contexts and notifying writes to its listeners.
+type Tracker struct {
+	mu        sync.RWMutex
+	listeners []WriteListener
+}
+
+// NewTracker creates a table tracker without listeners.
+func NewTracker() *Tracker {
+	return new(Tracker)
+}
+
+// OnWrite registers a listener notified of every table written.
+func (t *Tracker) OnWrite(listener WriteListener) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.listeners = append(t.listeners, listener)
+}
+
+func (*Tracker) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (t *Tracker) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if event.Err != nil {
+		return
+	}
+
+	query, ok := event.IQuery.(schema.Query)
+	if !ok {
+		return
+	}
+
+	table := strings.Trim(query.GetTableName(), `"`+"`")
+	if table == constants.Empty {
+		return
+	}
+
+	switch query.Operation() {
+	case "SELECT":
+		if reads, ok := ctx.Value(readsKey{}).(*Reads); ok {
+			reads.add(table)
+		}
+	case "INSERT", "UPDATE", "DELETE", "MERGE", "TRUNCATE TABLE", "DROP TABLE":
+		t.notify(ctx, table)
+
+		if txcommit.InTransaction(ctx) {
+			txcommit.AfterCommit(ctx, func() {
+				t.notify(ctx, table)
+			})
+		}
+	}
+}
+
+func (t *Tracker) notify(ctx context.Context, table string) {
+	t.mu.RLock()
+	listeners := t.listeners
+	t.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(ctx, table)
+	}
+}
@@ -0,0 +1,112 @@
+package tabletag
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlite"
+	"github.com/ilxqx/vef-framework-go/internal/orm"
+)
+
+type taggedItem struct {
+	bun.BaseModel `bun:"table:tagged_item"`
+
+	ID   int64  `bun:"id,pk,autoincrement"`
+	Name string `bun:"name"`
+}
+
+// writes records the tables notified to a listener.
+type writes struct {
+	mu     sync.Mutex
+	tables []string
+}
+
+func (w *writes) listen(_ context.Context, table string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.tables = append(w.tables, table)
+}
+
+func (w *writes) take() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	tables := w.tables
+	w.tables = nil
+
+	return tables
+}
+
+func newTrackedDB(t *testing.T) (orm.DB, *writes) {
+	t.Helper()
+
+	connector, dialect, err := sqlite.NewProvider().Connect(&config.DatasourceConfig{
+		Type: constants.SQLite,
+		Path: filepath.Join(t.TempDir(), "tabletag.db"),
+	})
+	require.NoError(t, err)
+
+	bunDB := bun.NewDB(sql.OpenDB(connector), dialect)
+	t.Cleanup(func() { _ = bunDB.Close() })
+
+	_, err = bunDB.NewCreateTable().Model((*taggedItem)(nil)).Exec(context.Background())
+	require.NoError(t, err)
+
+	listener := new(writes)
+	tracker := NewTracker()
+	tracker.OnWrite(listener.listen)
+	bunDB.AddQueryHook(tracker)
+
+	return orm.New(bunDB), listener
+}
+
+func TestTrackerNotifiesWrites(t *testing.T) {
+	errRollback := errors.New("rollback")
+	ctx := context.Background()
+
+	t.Run("OutsideTransaction", func(t *testing.T) {
+		db, listener := newTrackedDB(t)
+
+		_, err := db.NewInsert().Model(&taggedItem{Name: "a"}).Exec(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tagged_item"}, listener.take(), "Should notify the write once")
+	})
+
+	t.Run("Committed", func(t *testing.T) {
+		db, listener := newTrackedDB(t)
+
+		require.NoError(t, db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+			_, err := tx.NewInsert().Model(&taggedItem{Name: "a"}).Exec(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"tagged_item"}, listener.take(), "Should notify the write when executed")
+
+			return nil
+		}))
+
+		assert.Equal(t, []string{"tagged_item"}, listener.take(), "Should notify the write again once committed")
+	})
+
+	t.Run("RolledBack", func(t *testing.T) {
+		db, listener := newTrackedDB(t)
+
+		require.ErrorIs(t, db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+			_, err := tx.NewInsert().Model(&taggedItem{Name: "a"}).Exec(ctx)
+			require.NoError(t, err)
+
+			return errRollback
+		}), errRollback)
+
+		assert.Equal(t, []string{"tagged_item"}, listener.take(), "Should not notify rolled back writes again")
+	})
+}
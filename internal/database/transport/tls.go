@@ -0,0 +1,111 @@
+// Package transport secures the network connections of datasources.
+//
+// It builds the TLS configuration of encrypted connections from certificate files and dials connections
+// through SSH tunnels to reach databases behind bastion hosts. Both are validated when the datasource is opened,
+// so that missing files and incomplete settings fail the startup with an error naming the offending option.
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+var (
+	// ErrUnknownTLSMode is returned for TLS modes other than disable, require, verify_ca and verify_full.
+	ErrUnknownTLSMode = errors.New("unknown tls mode")
+	// ErrTLSOptionsWithoutMode is returned when certificate files are set while TLS is disabled.
+	ErrTLSOptionsWithoutMode = errors.New("tls certificate options are set but tls mode is disable")
+	// ErrIncompleteClientCert is returned when only one of the client certificate and key files is set.
+	ErrIncompleteClientCert = errors.New("tls cert_file and key_file must be set together")
+	// ErrInvalidCABundle is returned when the CA bundle holds no PEM certificate.
+	ErrInvalidCABundle = errors.New("tls ca_file holds no PEM certificate")
+)
+
+// TLSConfig builds the TLS configuration of connections to host, nil when TLS is disabled.
+func TLSConfig(cfg config.DatasourceTLSConfig, host string) (*tls.Config, error) {
+	switch cfg.Mode {
+	case constants.Empty, config.TLSModeDisable:
+		if cfg.CAFile != constants.Empty || cfg.CertFile != constants.Empty || cfg.KeyFile != constants.Empty {
+			return nil, ErrTLSOptionsWithoutMode
+		}
+
+		return nil, nil
+	case config.TLSModeRequire, config.TLSModeVerifyCA, config.TLSModeVerifyFull:
+	default:
+		return nil, fmt.Errorf("%w %q, expected %s, %s, %s or %s", ErrUnknownTLSMode, cfg.Mode,
+			config.TLSModeDisable, config.TLSModeRequire, config.TLSModeVerifyCA, config.TLSModeVerifyFull)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: host,
+	}
+
+	if cfg.ServerName != constants.Empty {
+		tlsConfig.ServerName = cfg.ServerName
+	}
+
+	if (cfg.CertFile == constants.Empty) != (cfg.KeyFile == constants.Empty) {
+		return nil, ErrIncompleteClientCert
+	}
+
+	if cfg.CertFile != constants.Empty {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls client certificate %s: %w", cfg.CertFile, err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.CAFile != constants.Empty {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls ca_file: %w", err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCABundle, cfg.CAFile)
+		}
+	}
+
+	switch cfg.Mode {
+	case config.TLSModeRequire:
+		tlsConfig.InsecureSkipVerify = true
+	case config.TLSModeVerifyCA:
+		// The chain is verified without the server name, which managed databases often do not match
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = verifyChain(tlsConfig.RootCAs)
+	}
+
+	return tlsConfig, nil
+}
+
+// verifyChain returns a connection verifier checking the server certificate is signed by one of the roots,
+// the system roots when nil.
+func verifyChain(roots *x509.CertPool) func(tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return errors.New("tls server sent no certificate")
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range state.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+
+		_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+
+		return err
+	}
+}
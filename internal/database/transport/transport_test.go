@@ -0,0 +1,158 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/config"
+)
+
+// writeCertificate writes a self-signed certificate and its key to dir and returns both paths.
+func writeCertificate(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+
+	return certFile, keyFile
+}
+
+func TestTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir)
+
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("not a certificate"), 0o600))
+
+	t.Run("Disabled", func(t *testing.T) {
+		tlsConfig, err := TLSConfig(config.DatasourceTLSConfig{}, "db.internal")
+		require.NoError(t, err)
+		assert.Nil(t, tlsConfig)
+	})
+
+	t.Run("Require", func(t *testing.T) {
+		tlsConfig, err := TLSConfig(config.DatasourceTLSConfig{Mode: config.TLSModeRequire}, "db.internal")
+		require.NoError(t, err)
+		assert.True(t, tlsConfig.InsecureSkipVerify)
+		assert.Nil(t, tlsConfig.VerifyConnection)
+	})
+
+	t.Run("VerifyCA", func(t *testing.T) {
+		tlsConfig, err := TLSConfig(config.DatasourceTLSConfig{Mode: config.TLSModeVerifyCA, CAFile: certFile}, "db.internal")
+		require.NoError(t, err)
+		assert.True(t, tlsConfig.InsecureSkipVerify)
+		assert.NotNil(t, tlsConfig.RootCAs)
+		assert.NotNil(t, tlsConfig.VerifyConnection)
+		assert.Error(t, tlsConfig.VerifyConnection(tls.ConnectionState{}))
+	})
+
+	t.Run("VerifyFull", func(t *testing.T) {
+		tlsConfig, err := TLSConfig(config.DatasourceTLSConfig{
+			Mode:       config.TLSModeVerifyFull,
+			CAFile:     certFile,
+			CertFile:   certFile,
+			KeyFile:    keyFile,
+			ServerName: "primary.db.internal",
+		}, "10.0.0.1")
+		require.NoError(t, err)
+		assert.False(t, tlsConfig.InsecureSkipVerify)
+		assert.Equal(t, "primary.db.internal", tlsConfig.ServerName)
+		assert.Len(t, tlsConfig.Certificates, 1)
+	})
+
+	tests := []struct {
+		name string
+		cfg  config.DatasourceTLSConfig
+		err  error
+	}{
+		{"UnknownMode", config.DatasourceTLSConfig{Mode: "strict"}, ErrUnknownTLSMode},
+		{"OptionsWithoutMode", config.DatasourceTLSConfig{CAFile: certFile}, ErrTLSOptionsWithoutMode},
+		{"CertWithoutKey", config.DatasourceTLSConfig{Mode: config.TLSModeRequire, CertFile: certFile}, ErrIncompleteClientCert},
+		{"InvalidCABundle", config.DatasourceTLSConfig{Mode: config.TLSModeVerifyFull, CAFile: invalidFile}, ErrInvalidCABundle},
+		{"MissingCAFile", config.DatasourceTLSConfig{Mode: config.TLSModeVerifyFull, CAFile: filepath.Join(dir, "missing.pem")}, os.ErrNotExist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := TLSConfig(tt.cfg, "db.internal")
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestNewTunnel(t *testing.T) {
+	dir := t.TempDir()
+	_, keyFile := writeCertificate(t, dir)
+
+	t.Run("Disabled", func(t *testing.T) {
+		tunnel, err := NewTunnel(config.SSHTunnelConfig{Host: "bastion"})
+		require.NoError(t, err)
+		assert.Nil(t, tunnel)
+	})
+
+	t.Run("PrivateKey", func(t *testing.T) {
+		tunnel, err := NewTunnel(config.SSHTunnelConfig{
+			Enabled:               true,
+			Host:                  "bastion",
+			User:                  "deploy",
+			PrivateKeyFile:        keyFile,
+			InsecureIgnoreHostKey: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "bastion:22", tunnel.addr)
+		assert.NoError(t, tunnel.Close())
+	})
+
+	tests := []struct {
+		name string
+		cfg  config.SSHTunnelConfig
+		err  error
+	}{
+		{"HostRequired", config.SSHTunnelConfig{Enabled: true, User: "deploy"}, ErrTunnelHostRequired},
+		{"UserRequired", config.SSHTunnelConfig{Enabled: true, Host: "bastion"}, ErrTunnelUserRequired},
+		{"AuthRequired", config.SSHTunnelConfig{Enabled: true, Host: "bastion", User: "deploy"}, ErrTunnelAuthRequired},
+		{"HostKeyRequired", config.SSHTunnelConfig{Enabled: true, Host: "bastion", User: "deploy", Password: "secret"}, ErrTunnelHostKeyRequired},
+		{"MissingPrivateKey", config.SSHTunnelConfig{Enabled: true, Host: "bastion", User: "deploy", PrivateKeyFile: filepath.Join(dir, "id_missing")}, os.ErrNotExist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTunnel(tt.cfg)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package transport
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+var (
+	// ErrTunnelHostRequired is returned when the tunnel is enabled without bastion host.
+	ErrTunnelHostRequired = errors.New("ssh_tunnel host is required")
+	// ErrTunnelUserRequired is returned when the tunnel is enabled without user.
+	ErrTunnelUserRequired = errors.New("ssh_tunnel user is required")
+	// ErrTunnelAuthRequired is returned when the tunnel has neither password nor private key.
+	ErrTunnelAuthRequired = errors.New("ssh_tunnel password or private_key_file is required")
+	// ErrTunnelHostKeyRequired is returned when the host key can't be verified and verification isn't disabled.
+	ErrTunnelHostKeyRequired = errors.New("ssh_tunnel known_hosts_file is required unless insecure_ignore_host_key is set")
+)
+
+// Tunnel dials connections through an SSH bastion host.
+// The SSH connection is established on the first dial and re-established once the server closes it.
+type Tunnel struct {
+	addr         string
+	clientConfig *ssh.ClientConfig
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// NewTunnel validates the tunnel configuration and loads its keys, nil when the tunnel is disabled.
+func NewTunnel(cfg config.SSHTunnelConfig) (*Tunnel, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	if cfg.Host == constants.Empty {
+		return nil, ErrTunnelHostRequired
+	}
+
+	if cfg.User == constants.Empty {
+		return nil, ErrTunnelUserRequired
+	}
+
+	var auth []ssh.AuthMethod
+
+	if cfg.PrivateKeyFile != constants.Empty {
+		signer, err := loadSigner(cfg.PrivateKeyFile, cfg.PrivateKeyPassphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+
+	if cfg.Password != constants.Empty {
+		auth = append(auth, ssh.Password(cfg.Password))
+	}
+
+	if len(auth) == 0 {
+		return nil, ErrTunnelAuthRequired
+	}
+
+	hostKeyCallback, err := hostKeyCallback(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = 22
+	}
+
+	return &Tunnel{
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(int(port))),
+		clientConfig: &ssh.ClientConfig{
+			User:            cfg.User,
+			Auth:            auth,
+			HostKeyCallback: hostKeyCallback,
+		},
+	}, nil
+}
+
+func loadSigner(file, passphrase string) (ssh.Signer, error) {
+	key, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ssh_tunnel private_key_file: %w", err)
+	}
+
+	var signer ssh.Signer
+	if passphrase != constants.Empty {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(key)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ssh_tunnel private key %s: %w", file, err)
+	}
+
+	return signer, nil
+}
+
+func hostKeyCallback(cfg config.SSHTunnelConfig) (ssh.HostKeyCallback, error) {
+	if cfg.KnownHostsFile == constants.Empty {
+		if !cfg.InsecureIgnoreHostKey {
+			return nil, ErrTunnelHostKeyRequired
+		}
+
+		return ssh.InsecureIgnoreHostKey(), nil //nolint:gosec // explicitly enabled by configuration
+	}
+
+	callback, err := knownhosts.New(cfg.KnownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ssh_tunnel known_hosts_file: %w", err)
+	}
+
+	return callback, nil
+}
+
+// DialContext dials addr from the bastion host, matching the dialer signatures of the database drivers.
+func (t *Tunnel) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s through ssh tunnel %s: %w", addr, t.addr, err)
+	}
+
+	return conn, nil
+}
+
+func (t *Tunnel) connect(ctx context.Context) (*ssh.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client != nil {
+		return t.client, nil
+	}
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect ssh tunnel %s: %w", t.addr, err)
+	}
+
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, t.addr, t.clientConfig)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("failed to connect ssh tunnel %s: %w", t.addr, err)
+	}
+
+	client := ssh.NewClient(sshConn, channels, requests)
+	t.client = client
+
+	go func() {
+		_ = client.Wait()
+
+		t.mu.Lock()
+		if t.client == client {
+			t.client = nil
+		}
+		t.mu.Unlock()
+	}()
+
+	return client, nil
+}
+
+// Close closes the SSH connection, connections dialed through it are closed as well.
+func (t *Tunnel) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client == nil {
+		return nil
+	}
+
+	err := t.client.Close()
+	t.client = nil
+
+	return err
+}
+
+// WithCloser returns a connector closing closer together with the connector when sql.DB is closed.
+func WithCloser(connector driver.Connector, closer io.Closer) driver.Connector {
+	return &closingConnector{Connector: connector, closer: closer}
+}
+
+type closingConnector struct {
+	driver.Connector
+
+	closer io.Closer
+}
+
+func (c *closingConnector) Close() error {
+	var err error
+	if closer, ok := c.Connector.(io.Closer); ok {
+		err = closer.Close()
+	}
+
+	return errors.Join(err, c.closer.Close())
+}
@@ -0,0 +1,69 @@
+// Package txcommit defers work until the transaction of a context commits, e.g. notifications of writes,
+// so that nothing observes changes which are rolled back or not visible yet.
+package txcommit
+
+import (
+	"context"
+	"sync"
+)
+
+// callbacksKey carries the callbacks of a transaction context.
+type callbacksKey struct{}
+
+// callbacks collects the functions to run once the transaction of a context commits.
+type callbacks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+func (c *callbacks) add(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fns = append(c.fns, fn)
+}
+
+func (c *callbacks) take() []func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fns := c.fns
+	c.fns = nil
+
+	return fns
+}
+
+// AfterCommit runs fn once the transaction of ctx commits, it never runs if the transaction rolls back.
+// Within a nested transaction fn waits for the outermost transaction, outside a transaction it runs immediately.
+func AfterCommit(ctx context.Context, fn func()) {
+	if pending, ok := ctx.Value(callbacksKey{}).(*callbacks); ok {
+		pending.add(fn)
+
+		return
+	}
+
+	fn()
+}
+
+// InTransaction reports whether ctx is the context of a transaction run with Collect.
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(callbacksKey{}).(*callbacks)
+
+	return ok
+}
+
+// Collect runs fn, which runs a transaction, with a context collecting the callbacks of AfterCommit.
+// Once fn committed, the callbacks are handed to the enclosing transaction, or run when there is none;
+// they are dropped if fn fails.
+func Collect(ctx context.Context, fn func(context.Context) error) error {
+	pending := new(callbacks)
+	if err := fn(context.WithValue(ctx, callbacksKey{}, pending)); err != nil {
+		return err
+	}
+
+	for _, callback := range pending.take() {
+		AfterCommit(ctx, callback)
+	}
+
+	return nil
+}
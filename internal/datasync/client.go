@@ -0,0 +1,262 @@
+package datasync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/datasync"
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+const defaultBatchSize = 200
+
+var (
+	// ErrClientIDRequired is returned when the sync client is enabled without client id.
+	ErrClientIDRequired = errors.New("sync client requires a client id")
+	// ErrEndpointRequired is returned when the sync client is enabled without endpoint nor transport.
+	ErrEndpointRequired = errors.New("sync client requires the endpoint of the server")
+)
+
+// Client synchronizes the local database with the server through a transport.
+// Local changes are the changes logged in the local database without client id,
+// the pulled server changes are applied without being logged so that they are not pushed back.
+type Client struct {
+	service   *Service
+	db        orm.DB
+	transport datasync.Transport
+	clientID  string
+	models    []string
+	batchSize int
+
+	mu sync.Mutex
+}
+
+// NewClient creates the sync client, returning nil when the client is disabled.
+// The HTTP transport of the configured endpoint is used unless a transport is provided.
+func NewClient(cfg *config.SyncConfig, service *Service, db orm.DB, transport datasync.Transport) (*Client, error) {
+	if !cfg.Client.Enabled {
+		return nil, nil
+	}
+
+	if cfg.Client.ClientID == constants.Empty {
+		return nil, ErrClientIDRequired
+	}
+
+	if transport == nil {
+		if cfg.Client.Endpoint == constants.Empty {
+			return nil, ErrEndpointRequired
+		}
+
+		var authorize func(context.Context, *http.Request) error
+		if cfg.Client.Token != constants.Empty {
+			authorize = datasync.BearerToken(cfg.Client.Token)
+		}
+
+		transport = datasync.NewHTTPTransport(cfg.Client.Endpoint, authorize)
+	}
+
+	models := cfg.Client.Models
+	if len(models) == 0 {
+		models = service.Models()
+	}
+
+	for _, name := range models {
+		if _, ok := service.models[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", datasync.ErrUnknownModel, name)
+		}
+	}
+
+	batchSize := cfg.Client.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &Client{
+		service:   service,
+		db:        db,
+		transport: transport,
+		clientID:  cfg.Client.ClientID,
+		models:    models,
+		batchSize: batchSize,
+	}, nil
+}
+
+func (c *Client) Sync(ctx context.Context) (*datasync.SyncResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, err := c.State(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var synced datasync.SyncResult
+
+	// Pushing first lets the server resolve conflicts before the server changes overwrite the local rows
+	if err := c.push(ctx, state, &synced); err != nil {
+		return &synced, err
+	}
+
+	if err := c.pull(ctx, state, &synced); err != nil {
+		return &synced, err
+	}
+
+	state.SyncedAt = null.DateTimeFrom(datetime.Now())
+
+	return &synced, c.saveState(ctx, c.db, state)
+}
+
+func (c *Client) State(ctx context.Context) (*datasync.State, error) {
+	state := &datasync.State{ClientID: c.clientID}
+
+	if err := c.db.NewSelect().
+		Model(state).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.Equals("client_id", c.clientID)
+		}).
+		Scan(ctx); err != nil && !result.IsRecordNotFound(err) {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// push sends the local changes logged after the last push in batches, only the latest change of a row is sent.
+func (c *Client) push(ctx context.Context, state *datasync.State, synced *datasync.SyncResult) error {
+	for {
+		var logged []datasync.Change
+		if err := c.db.NewSelect().
+			Model(&logged).
+			Where(func(cb orm.ConditionBuilder) {
+				cb.GreaterThan("seq", state.PushedSeq).
+					Equals("client_id", constants.Empty).
+					In("model", c.models)
+			}).
+			OrderBy("seq").
+			Limit(c.batchSize).
+			Scan(ctx); err != nil || len(logged) == 0 {
+			return err
+		}
+
+		changes := latestChanges(logged)
+
+		pushed, err := c.transport.Push(ctx, datasync.PushRequest{
+			ClientID: c.clientID,
+			Cursor:   state.PulledSeq,
+			Changes:  changes,
+		})
+		if err != nil {
+			return err
+		}
+
+		synced.Pushed += len(changes)
+
+		for _, outcome := range pushed.Outcomes {
+			if outcome.Status != datasync.StatusApplied {
+				synced.Rejected++
+			}
+		}
+
+		state.PushedSeq = logged[len(logged)-1].Seq
+		if err := c.saveState(ctx, c.db, state); err != nil {
+			return err
+		}
+
+		if len(logged) < c.batchSize {
+			return nil
+		}
+	}
+}
+
+// pull applies the server changes following the cursor, each batch in a transaction together with the new cursor.
+func (c *Client) pull(ctx context.Context, state *datasync.State, synced *datasync.SyncResult) error {
+	for {
+		pulled, err := c.transport.Pull(ctx, datasync.PullRequest{
+			ClientID: c.clientID,
+			Cursor:   state.PulledSeq,
+			Models:   c.models,
+			Limit:    c.batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := c.db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+			for _, change := range pulled.Changes {
+				applied, err := c.applyPulled(ctx, tx, state, change)
+				if err != nil {
+					return err
+				}
+
+				if applied {
+					synced.Pulled++
+				}
+			}
+
+			state.PulledSeq = pulled.Cursor
+
+			return c.saveState(ctx, tx, state)
+		}); err != nil {
+			return err
+		}
+
+		if !pulled.HasMore {
+			return nil
+		}
+	}
+}
+
+// applyPulled applies a server change unless the row was changed locally since the push,
+// the local change is pushed with the next sync and resolved by the server then.
+func (c *Client) applyPulled(ctx context.Context, tx orm.DB, state *datasync.State, change datasync.Change) (bool, error) {
+	model, ok := c.service.models[change.Model]
+	if !ok {
+		return false, fmt.Errorf("%w: %s", datasync.ErrUnknownModel, change.Model)
+	}
+
+	changedLocally, err := tx.NewSelect().
+		Model((*datasync.Change)(nil)).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.GreaterThan("seq", state.PushedSeq).
+				Equals("client_id", constants.Empty).
+				Equals("model", change.Model).
+				Equals("record_id", change.RecordID)
+		}).
+		Exists(ctx)
+	if err != nil || changedLocally {
+		return false, err
+	}
+
+	return true, apply(ctx, tx, model, change)
+}
+
+func (c *Client) saveState(ctx context.Context, db orm.DB, state *datasync.State) error {
+	_, err := db.NewUpsert().Model(state).Exec(ctx)
+
+	return err
+}
+
+// latestChanges returns the latest change of every row in the order they were made.
+func latestChanges(logged []datasync.Change) []datasync.Change {
+	latest := make(map[string]int, len(logged))
+	for i, change := range logged {
+		latest[change.Model+constants.Colon+change.RecordID] = i
+	}
+
+	changes := make([]datasync.Change, 0, len(latest))
+	for i, change := range logged {
+		if latest[change.Model+constants.Colon+change.RecordID] == i {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
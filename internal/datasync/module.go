@@ -0,0 +1,76 @@
+package datasync
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/cron"
+	"github.com/ilxqx/vef-framework-go/datasync"
+	"github.com/ilxqx/vef-framework-go/internal/log"
+)
+
+var logger = log.Named("datasync")
+
+// Module provides the sync service of the provided models, its Api resource and, on embedded clients, the sync client.
+// Applications can supply their own datasync.Transport to the client, e.g. for custom authentication.
+var Module = fx.Module(
+	"vef:datasync",
+	fx.Provide(
+		fx.Annotate(
+			NewService,
+			fx.ParamTags(``, ``, `group:"vef:datasync:models"`),
+		),
+		func(service *Service) datasync.Service {
+			return service
+		},
+		fx.Annotate(
+			NewClient,
+			fx.ParamTags(``, ``, ``, `optional:"true"`),
+		),
+		newSyncClient,
+		fx.Annotate(
+			NewResource,
+			fx.ResultTags(`group:"vef:api:resources"`),
+		),
+	),
+	fx.Invoke(scheduleSync),
+)
+
+// newSyncClient exposes the client through the public interface, nil when the client is disabled.
+func newSyncClient(client *Client) datasync.Client {
+	if client == nil {
+		return nil
+	}
+
+	return client
+}
+
+// scheduleSync registers the scheduled synchronization of the client when an interval is configured.
+func scheduleSync(cfg *config.SyncConfig, client *Client, scheduler cron.Scheduler) error {
+	if client == nil || cfg.Client.Interval <= 0 {
+		return nil
+	}
+
+	_, err := scheduler.NewJob(cron.NewDurationJob(
+		cfg.Client.Interval,
+		cron.WithName("datasync"),
+		cron.WithTags("vef", "datasync"),
+		cron.WithTask(func(ctx context.Context) error {
+			synced, err := client.Sync(ctx)
+			if err != nil {
+				// Clients are offline regularly, the next run retries
+				logger.Warnf("Failed to sync with the server: %v", err)
+
+				return nil
+			}
+
+			logger.Debugf("Synced with the server: %d pushed, %d rejected, %d pulled", synced.Pushed, synced.Rejected, synced.Pulled)
+
+			return nil
+		}),
+	))
+
+	return err
+}
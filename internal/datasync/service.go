@@ -0,0 +1,436 @@
+package datasync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/datasync"
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/encoding"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+const (
+	defaultPullLimit    = 500
+	defaultSettleWindow = 30 * time.Second
+)
+
+var (
+	// ErrInvalidModel is returned when a synchronized model is no struct pointer, has no primary key or an unknown policy.
+	ErrInvalidModel = errors.New("invalid synchronized model")
+	// ErrDuplicateModel is returned when two synchronized models share the same name.
+	ErrDuplicateModel = errors.New("duplicate synchronized model")
+	// ErrInvalidChange is returned when applying a change of an unknown operation or whose record ID is not the primary
+	// key of its row.
+	ErrInvalidChange = errors.New("invalid change")
+)
+
+// syncModel is a synchronized model with its table.
+type syncModel struct {
+	datasync.Model
+
+	table *schema.Table
+}
+
+// Service logs the changes of synchronized models and applies the changes pushed by clients.
+type Service struct {
+	db           orm.DB
+	models       map[string]*syncModel
+	byType       map[reflect.Type]*syncModel
+	names        []string
+	pullLimit    int
+	settleWindow time.Duration
+	now          func() time.Time
+}
+
+// NewService creates the sync service of the provided models.
+func NewService(cfg *config.SyncConfig, db orm.DB, models []datasync.Model) (*Service, error) {
+	s := &Service{
+		db:           db,
+		models:       make(map[string]*syncModel, len(models)),
+		byType:       make(map[reflect.Type]*syncModel, len(models)),
+		pullLimit:    cfg.PullLimit,
+		settleWindow: cfg.SettleWindow,
+		now:          time.Now,
+	}
+
+	for _, model := range models {
+		typ := reflect.TypeOf(model.Model)
+		if typ == nil || typ.Kind() != reflect.Pointer || typ.Elem().Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%w: model must be a struct pointer, got %T", ErrInvalidModel, model.Model)
+		}
+
+		table := db.TableOf(model.Model)
+		if len(table.PKs) == 0 {
+			return nil, fmt.Errorf("%w: %s has no primary key", ErrInvalidModel, table.Name)
+		}
+
+		model.Name = lo.CoalesceOrEmpty(model.Name, table.Name)
+		model.Policy = lo.CoalesceOrEmpty(model.Policy, datasync.PolicyServerWins)
+
+		if !slices.Contains([]datasync.Policy{datasync.PolicyServerWins, datasync.PolicyClientWins, datasync.PolicyLastWriteWins}, model.Policy) {
+			return nil, fmt.Errorf("%w: unknown policy %q of %s", ErrInvalidModel, model.Policy, model.Name)
+		}
+
+		if _, exists := s.models[model.Name]; exists {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateModel, model.Name)
+		}
+
+		m := &syncModel{Model: model, table: table}
+		s.models[model.Name] = m
+		s.byType[typ.Elem()] = m
+		s.names = append(s.names, model.Name)
+	}
+
+	slices.Sort(s.names)
+
+	if s.pullLimit <= 0 {
+		s.pullLimit = defaultPullLimit
+	}
+
+	if s.settleWindow <= 0 {
+		s.settleWindow = defaultSettleWindow
+	}
+
+	return s, nil
+}
+
+func (s *Service) Models() []string {
+	return slices.Clone(s.names)
+}
+
+func (s *Service) Record(ctx context.Context, db orm.DB, operation datasync.Operation, rows ...any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	now := datetime.Of(s.now())
+	changes := make([]datasync.Change, len(rows))
+
+	for i, row := range rows {
+		value := reflect.Indirect(reflect.ValueOf(row))
+
+		model, ok := s.byType[value.Type()]
+		if !ok {
+			return fmt.Errorf("%w: %s", datasync.ErrUnknownModel, value.Type())
+		}
+
+		data, err := encoding.ToJSON(value.Interface())
+		if err != nil {
+			return fmt.Errorf("failed to encode %s row: %w", model.Name, err)
+		}
+
+		changes[i] = datasync.Change{
+			Model:     model.Name,
+			RecordID:  model.recordID(value),
+			Operation: operation,
+			Data:      data,
+			ChangedAt: now,
+			LoggedAt:  now,
+		}
+	}
+
+	_, err := db.NewInsert().Model(&changes).Exec(ctx)
+
+	return err
+}
+
+func (s *Service) Pull(ctx context.Context, req datasync.PullRequest) (*datasync.PullResult, error) {
+	models := req.Models
+	if len(models) == 0 {
+		models = s.names
+	}
+
+	for _, name := range models {
+		if _, ok := s.models[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", datasync.ErrUnknownModel, name)
+		}
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > s.pullLimit {
+		limit = s.pullLimit
+	}
+
+	// Changes are read regardless of model and client, so that gaps in the log can be told apart from filtered changes
+	var logged []datasync.Change
+	if err := s.db.NewSelect().
+		Model(&logged).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.GreaterThan("seq", req.Cursor)
+		}).
+		OrderBy("seq").
+		Limit(limit + 1).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	pulled := &datasync.PullResult{
+		Changes: make([]datasync.Change, 0, min(len(logged), limit)),
+		Cursor:  req.Cursor,
+		HasMore: len(logged) > limit,
+	}
+	settled := s.now().Add(-s.settleWindow)
+
+	for _, change := range logged[:min(len(logged), limit)] {
+		// A gap is a seq taken by a transaction that rolled back or has not committed yet, later changes are held back
+		// until the gap is older than the settle window so that a late commit is not skipped by the cursor
+		if change.Seq != pulled.Cursor+1 && change.LoggedAt.Unwrap().After(settled) {
+			pulled.HasMore = false
+
+			break
+		}
+
+		pulled.Cursor = change.Seq
+
+		if change.ClientID != req.ClientID && slices.Contains(models, change.Model) {
+			pulled.Changes = append(pulled.Changes, change)
+		}
+	}
+
+	var err error
+	if pulled.Changes, err = s.visible(ctx, pulled.Changes); err != nil {
+		return nil, err
+	}
+
+	return pulled, nil
+}
+
+// visible keeps the pulled changes the request may see: upserts of the rows it can select, through the tenancy and
+// data scopes of the DB, and deletes, reduced to the primary key of the row as a deleted row cannot be scoped.
+func (s *Service) visible(ctx context.Context, changes []datasync.Change) ([]datasync.Change, error) {
+	upserts := make(map[string][]reflect.Value)
+
+	for i, change := range changes {
+		model := s.models[change.Model]
+
+		row, err := model.decode(change)
+		if err != nil {
+			return nil, err
+		}
+
+		if change.Operation == datasync.OperationDelete {
+			if changes[i].Data, err = model.keyData(row); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		upserts[change.Model] = append(upserts[change.Model], row)
+	}
+
+	selectable := make(map[string]bool)
+
+	for name, rows := range upserts {
+		model := s.models[name]
+		selected := reflect.New(reflect.SliceOf(model.table.Type))
+
+		if err := s.db.NewSelect().
+			Model(selected.Interface()).
+			Where(func(cb orm.ConditionBuilder) {
+				if len(model.table.PKs) == 1 {
+					pk := model.table.PKs[0]
+					cb.In(pk.Name, lo.Map(rows, func(row reflect.Value, _ int) any {
+						return pk.Value(row.Elem()).Interface()
+					}))
+
+					return
+				}
+
+				for _, row := range rows {
+					cb.OrGroup(func(cb orm.ConditionBuilder) {
+						for _, pk := range model.table.PKs {
+							cb.Equals(pk.Name, pk.Value(row.Elem()).Interface())
+						}
+					})
+				}
+			}).
+			Scan(ctx); err != nil {
+			return nil, err
+		}
+
+		for i := range selected.Elem().Len() {
+			selectable[name+constants.Colon+model.recordID(selected.Elem().Index(i))] = true
+		}
+	}
+
+	return slices.DeleteFunc(changes, func(change datasync.Change) bool {
+		return change.Operation != datasync.OperationDelete && !selectable[change.Model+constants.Colon+change.RecordID]
+	}), nil
+}
+
+func (s *Service) Push(ctx context.Context, req datasync.PushRequest) (*datasync.PushResult, error) {
+	pushed := &datasync.PushResult{Outcomes: make([]datasync.Outcome, len(req.Changes))}
+
+	if err := s.db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+		for i, change := range req.Changes {
+			model, ok := s.models[change.Model]
+			if !ok {
+				return fmt.Errorf("%w: %s", datasync.ErrUnknownModel, change.Model)
+			}
+
+			// Conflicts are detected by the record ID, which must therefore be the row the change applies
+			if _, err := model.decode(change); err != nil {
+				return err
+			}
+
+			status, err := s.push(ctx, tx, model, req, change)
+			if err != nil {
+				return err
+			}
+
+			pushed.Outcomes[i] = datasync.Outcome{Model: change.Model, RecordID: change.RecordID, Status: status}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return pushed, nil
+}
+
+// push applies a pushed change unless it conflicts with a server change the client has not pulled.
+// Changes winning a conflict are logged as server changes, so that the pushing client pulls them after the server change.
+func (s *Service) push(ctx context.Context, tx orm.DB, model *syncModel, req datasync.PushRequest, change datasync.Change) (datasync.Status, error) {
+	var server datasync.Change
+
+	if err := tx.NewSelect().
+		Model(&server).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.Equals("model", change.Model).
+				Equals("record_id", change.RecordID).
+				GreaterThan("seq", req.Cursor).
+				NotEquals("client_id", req.ClientID)
+		}).
+		OrderByDesc("seq").
+		Limit(1).
+		Scan(ctx); err != nil {
+		if !result.IsRecordNotFound(err) {
+			return constants.Empty, err
+		}
+
+		return datasync.StatusApplied, s.applyAndLog(ctx, tx, model, change, req.ClientID)
+	}
+
+	if model.Resolver != nil {
+		resolved, err := model.Resolver(ctx, datasync.Conflict{Model: model.Name, Client: change, Server: server})
+		if err != nil || resolved == nil {
+			return datasync.StatusRejected, err
+		}
+
+		resolved.Model, resolved.RecordID = model.Name, change.RecordID
+		if resolved.ChangedAt.Unwrap().IsZero() {
+			resolved.ChangedAt = datetime.Of(s.now())
+		}
+
+		return datasync.StatusMerged, s.applyAndLog(ctx, tx, model, *resolved, constants.Empty)
+	}
+
+	switch model.Policy {
+	case datasync.PolicyClientWins:
+		return datasync.StatusApplied, s.applyAndLog(ctx, tx, model, change, constants.Empty)
+	case datasync.PolicyLastWriteWins:
+		if change.ChangedAt.After(server.ChangedAt) {
+			return datasync.StatusApplied, s.applyAndLog(ctx, tx, model, change, constants.Empty)
+		}
+	}
+
+	return datasync.StatusRejected, nil
+}
+
+// applyAndLog applies a change to the table of its model and logs it as pushed by the client.
+func (s *Service) applyAndLog(ctx context.Context, tx orm.DB, model *syncModel, change datasync.Change, clientID string) error {
+	if err := apply(ctx, tx, model, change); err != nil {
+		return err
+	}
+
+	logged := datasync.Change{
+		Model:     change.Model,
+		RecordID:  change.RecordID,
+		Operation: change.Operation,
+		Data:      change.Data,
+		ClientID:  clientID,
+		ChangedAt: change.ChangedAt,
+		LoggedAt:  datetime.Of(s.now()),
+	}
+
+	_, err := tx.NewInsert().Model(&logged).Exec(ctx)
+
+	return err
+}
+
+// apply writes a change to the table of its model.
+func apply(ctx context.Context, db orm.DB, model *syncModel, change datasync.Change) error {
+	row, err := model.decode(change)
+	if err != nil {
+		return err
+	}
+
+	switch change.Operation {
+	case datasync.OperationUpsert:
+		_, err = db.NewUpsert().Model(row.Interface()).Exec(ctx)
+	case datasync.OperationDelete:
+		_, err = db.NewDelete().Model(row.Interface()).WherePK().Exec(ctx)
+	default:
+		err = fmt.Errorf("%w: unknown operation %q", ErrInvalidChange, change.Operation)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to apply %s change of %s row %s: %w", change.Operation, model.Name, change.RecordID, err)
+	}
+
+	return nil
+}
+
+// decode decodes the row of a change into a pointer to the model, failing when the record ID of the change is not
+// the primary key of the row.
+func (m *syncModel) decode(change datasync.Change) (reflect.Value, error) {
+	row := reflect.New(m.table.Type)
+	if err := encoding.DecodeJSON(change.Data, row.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to decode %s row %s: %w", m.Name, change.RecordID, err)
+	}
+
+	if recordID := m.recordID(row.Elem()); recordID != change.RecordID {
+		return reflect.Value{}, fmt.Errorf("%w: %s record %s holds row %s", ErrInvalidChange, m.Name, change.RecordID, recordID)
+	}
+
+	return row, nil
+}
+
+// keyData returns the JSON-encoded row holding only the primary key of the row.
+func (m *syncModel) keyData(row reflect.Value) (string, error) {
+	key := reflect.New(m.table.Type)
+	for _, pk := range m.table.PKs {
+		pk.Value(key.Elem()).Set(pk.Value(row.Elem()))
+	}
+
+	data, err := encoding.ToJSON(key.Interface())
+	if err != nil {
+		return constants.Empty, fmt.Errorf("failed to encode %s key: %w", m.Name, err)
+	}
+
+	return data, nil
+}
+
+// recordID returns the primary key of a row, keys of composite primary keys joined by commas.
+func (m *syncModel) recordID(row reflect.Value) string {
+	keys := make([]string, len(m.table.PKs))
+	for i, pk := range m.table.PKs {
+		keys[i] = fmt.Sprint(pk.Value(row).Interface())
+	}
+
+	return strings.Join(keys, constants.Comma)
+}
@@ -0,0 +1,327 @@
+package datasync
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/datasync"
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+type testNote struct {
+	bun.BaseModel `bun:"table:test_note"`
+
+	ID    string `json:"id"    bun:"id,pk"`
+	Title string `json:"title" bun:"title,notnull"`
+}
+
+// node is the database and sync service of the server or of a client.
+type node struct {
+	db      orm.DB
+	service *Service
+}
+
+func newNode(t *testing.T, name string, model datasync.Model, scopes ...orm.DataScope) *node {
+	t.Helper()
+
+	bunDB, err := database.New(&config.DatasourceConfig{
+		Type: constants.SQLite,
+		Path: filepath.Join(t.TempDir(), name+".db"),
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = bunDB.Close() })
+
+	for _, table := range []any{(*datasync.Change)(nil), (*datasync.State)(nil), (*testNote)(nil)} {
+		_, err := bunDB.NewCreateTable().Model(table).Exec(context.Background())
+		require.NoError(t, err)
+	}
+
+	db := iorm.New(bunDB)
+
+	service, err := NewService(&config.SyncConfig{}, db.WithDataScopes(scopes...), []datasync.Model{model})
+	require.NoError(t, err)
+
+	return &node{db: db, service: service}
+}
+
+// save writes a note and logs its change as the application does.
+func (n *node) save(t *testing.T, note testNote) {
+	t.Helper()
+
+	require.NoError(t, n.db.RunInTX(context.Background(), func(ctx context.Context, tx orm.DB) error {
+		if _, err := tx.NewUpsert().Model(&note).Exec(ctx); err != nil {
+			return err
+		}
+
+		return n.service.Record(ctx, tx, datasync.OperationUpsert, &note)
+	}))
+}
+
+func (n *node) delete(t *testing.T, id string) {
+	t.Helper()
+
+	require.NoError(t, n.db.RunInTX(context.Background(), func(ctx context.Context, tx orm.DB) error {
+		note := testNote{ID: id}
+		if _, err := tx.NewDelete().Model(&note).WherePK().Exec(ctx); err != nil {
+			return err
+		}
+
+		return n.service.Record(ctx, tx, datasync.OperationDelete, note)
+	}))
+}
+
+func (n *node) titles(t *testing.T) map[string]string {
+	t.Helper()
+
+	var notes []testNote
+	require.NoError(t, n.db.NewSelect().Model(&notes).OrderBy("id").Scan(context.Background()))
+
+	titles := make(map[string]string, len(notes))
+	for _, note := range notes {
+		titles[note.ID] = note.Title
+	}
+
+	return titles
+}
+
+// newPair creates a server and a client synchronizing the notes, the client using the server service as transport.
+func newPair(t *testing.T, model datasync.Model) (server, local *node, client *Client) {
+	t.Helper()
+
+	server = newNode(t, "server", model)
+	local = newNode(t, "client", model)
+
+	client, err := NewClient(&config.SyncConfig{Client: config.SyncClientConfig{Enabled: true, ClientID: "device-1"}}, local.service, local.db, server.service)
+	require.NoError(t, err)
+
+	return server, local, client
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	server, local, client := newPair(t, datasync.Model{Model: (*testNote)(nil)})
+
+	local.save(t, testNote{ID: "n1", Title: "draft"})
+	local.save(t, testNote{ID: "n1", Title: "offline"})
+	local.save(t, testNote{ID: "n2", Title: "to delete"})
+	local.delete(t, "n2")
+	server.save(t, testNote{ID: "n3", Title: "from server"})
+
+	synced, err := client.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &datasync.SyncResult{Pushed: 2, Pulled: 1}, synced, "Only the latest change of every row should be pushed")
+
+	assert.Equal(t, map[string]string{"n1": "offline", "n3": "from server"}, server.titles(t))
+	assert.Equal(t, map[string]string{"n1": "offline", "n3": "from server"}, local.titles(t))
+
+	t.Run("NothingToSync", func(t *testing.T) {
+		synced, err := client.Sync(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &datasync.SyncResult{}, synced, "Pushed changes should not be pulled back")
+
+		state, err := client.State(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "device-1", state.ClientID)
+		assert.True(t, state.SyncedAt.Valid)
+	})
+
+	t.Run("ServerDelete", func(t *testing.T) {
+		server.delete(t, "n3")
+
+		synced, err := client.Sync(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, synced.Pulled)
+		assert.Equal(t, map[string]string{"n1": "offline"}, local.titles(t))
+	})
+}
+
+func TestConflicts(t *testing.T) {
+	ctx := context.Background()
+
+	conflict := func(t *testing.T, model datasync.Model) (server, local *node, synced *datasync.SyncResult) {
+		server, local, client := newPair(t, model)
+
+		server.save(t, testNote{ID: "n1", Title: "base"})
+		_, err := client.Sync(ctx)
+		require.NoError(t, err)
+
+		// Both sides change the row before the client syncs again
+		server.save(t, testNote{ID: "n1", Title: "server"})
+		local.save(t, testNote{ID: "n1", Title: "client"})
+
+		synced, err = client.Sync(ctx)
+		require.NoError(t, err)
+
+		return server, local, synced
+	}
+
+	t.Run("ServerWins", func(t *testing.T) {
+		server, local, synced := conflict(t, datasync.Model{Model: (*testNote)(nil)})
+
+		assert.Equal(t, 1, synced.Rejected)
+		assert.Equal(t, "server", server.titles(t)["n1"])
+		assert.Equal(t, "server", local.titles(t)["n1"], "The client should take the server row")
+	})
+
+	t.Run("ClientWins", func(t *testing.T) {
+		server, local, synced := conflict(t, datasync.Model{Model: (*testNote)(nil), Policy: datasync.PolicyClientWins})
+
+		assert.Equal(t, 0, synced.Rejected)
+		assert.Equal(t, "client", server.titles(t)["n1"])
+		assert.Equal(t, "client", local.titles(t)["n1"], "The winning change should be pulled after the server change")
+	})
+
+	t.Run("LastWriteWins", func(t *testing.T) {
+		server, local, client := newPair(t, datasync.Model{Model: (*testNote)(nil), Policy: datasync.PolicyLastWriteWins})
+
+		server.save(t, testNote{ID: "n1", Title: "server"})
+
+		// The client change was made an hour before the server change, e.g. while offline
+		local.service.now = func() time.Time { return time.Now().Add(-time.Hour) }
+		local.save(t, testNote{ID: "n1", Title: "client"})
+
+		synced, err := client.Sync(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, synced.Rejected)
+		assert.Equal(t, "server", local.titles(t)["n1"])
+	})
+
+	t.Run("Resolver", func(t *testing.T) {
+		server, local, synced := conflict(t, datasync.Model{
+			Model: (*testNote)(nil),
+			Resolver: func(_ context.Context, conflict datasync.Conflict) (*datasync.Change, error) {
+				assert.Equal(t, "test_note", conflict.Model)
+				assert.Contains(t, conflict.Server.Data, `"server"`)
+
+				return &datasync.Change{
+					Operation: datasync.OperationUpsert,
+					Data:      strings.Replace(conflict.Client.Data, `"client"`, `"client+server"`, 1),
+				}, nil
+			},
+		})
+
+		assert.Equal(t, 1, synced.Rejected)
+		assert.Equal(t, "client+server", server.titles(t)["n1"])
+		assert.Equal(t, "client+server", local.titles(t)["n1"])
+	})
+}
+
+func TestPullScoped(t *testing.T) {
+	ctx := orm.WithDataScopeContext(context.Background(), &orm.DataScopeContext{UserID: "u1"})
+	server := newNode(t, "server", datasync.Model{Model: (*testNote)(nil)},
+		orm.DataScopeFunc(func(_ context.Context, table *schema.Table, cb orm.ConditionBuilder) {
+			if table.HasField("title") {
+				cb.NotStartsWith("title", "secret")
+			}
+		}),
+	)
+
+	server.save(t, testNote{ID: "n1", Title: "public"})
+	server.save(t, testNote{ID: "n2", Title: "secret plan"})
+	server.save(t, testNote{ID: "n3", Title: "secret draft"})
+	server.delete(t, "n3")
+
+	pulled, err := server.service.Pull(ctx, datasync.PullRequest{ClientID: "device-1"})
+	require.NoError(t, err)
+	require.Len(t, pulled.Changes, 2, "Upserts of rows out of scope should not be pulled")
+	assert.Equal(t, int64(4), pulled.Cursor, "Changes left out should still advance the cursor")
+
+	assert.Equal(t, "n1", pulled.Changes[0].RecordID)
+	assert.Equal(t, datasync.OperationDelete, pulled.Changes[1].Operation)
+	assert.Equal(t, "n3", pulled.Changes[1].RecordID)
+	assert.NotContains(t, pulled.Changes[1].Data, "secret", "Deletes should only hold the primary key")
+}
+
+func TestPushRecordMismatch(t *testing.T) {
+	server := newNode(t, "server", datasync.Model{Model: (*testNote)(nil)})
+	server.save(t, testNote{ID: "n1", Title: "base"})
+
+	_, err := server.service.Push(context.Background(), datasync.PushRequest{
+		ClientID: "device-1",
+		Changes: []datasync.Change{{
+			Model:     "test_note",
+			RecordID:  "harmless",
+			Operation: datasync.OperationUpsert,
+			Data:      `{"id":"n1","title":"overwritten"}`,
+			ChangedAt: datetime.Now(),
+		}},
+	})
+	require.ErrorIs(t, err, ErrInvalidChange, "Changes whose record ID is not their row should be rejected")
+	assert.Equal(t, map[string]string{"n1": "base"}, server.titles(t))
+}
+
+func TestPullSettleWindow(t *testing.T) {
+	ctx := context.Background()
+	server := newNode(t, "server", datasync.Model{Model: (*testNote)(nil)})
+
+	now := time.Now()
+	changes := []datasync.Change{
+		{Seq: 1, Model: "test_note", RecordID: "n1", Operation: datasync.OperationUpsert, Data: `{"id":"n1"}`, LoggedAt: datetime.Of(now)},
+		{Seq: 3, Model: "test_note", RecordID: "n3", Operation: datasync.OperationUpsert, Data: `{"id":"n3"}`, LoggedAt: datetime.Of(now)},
+	}
+	_, err := server.db.NewInsert().Model(&changes).Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = server.db.NewInsert().Model(&[]testNote{{ID: "n1"}, {ID: "n3"}}).Exec(ctx)
+	require.NoError(t, err)
+
+	pulled, err := server.service.Pull(ctx, datasync.PullRequest{ClientID: "device-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pulled.Cursor, "Changes after a recent gap should be held back")
+	assert.Len(t, pulled.Changes, 1)
+
+	server.service.now = func() time.Time { return now.Add(time.Minute) }
+
+	pulled, err = server.service.Pull(ctx, datasync.PullRequest{ClientID: "device-1", Cursor: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), pulled.Cursor, "Settled gaps should be skipped")
+	assert.Len(t, pulled.Changes, 1)
+}
+
+func TestInvalidModels(t *testing.T) {
+	ctx := context.Background()
+	server := newNode(t, "server", datasync.Model{Model: (*testNote)(nil)})
+
+	t.Run("RecordUnknownModel", func(t *testing.T) {
+		err := server.service.Record(ctx, server.db, datasync.OperationUpsert, &datasync.State{})
+		assert.ErrorIs(t, err, datasync.ErrUnknownModel)
+	})
+
+	t.Run("PullUnknownModel", func(t *testing.T) {
+		_, err := server.service.Pull(ctx, datasync.PullRequest{ClientID: "device-1", Models: []string{"test_order"}})
+		assert.ErrorIs(t, err, datasync.ErrUnknownModel)
+	})
+
+	t.Run("Declarations", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			models []datasync.Model
+			err    error
+		}{
+			{"NotPointer", []datasync.Model{{Model: testNote{}}}, ErrInvalidModel},
+			{"UnknownPolicy", []datasync.Model{{Model: (*testNote)(nil), Policy: "newest"}}, ErrInvalidModel},
+			{"Duplicate", []datasync.Model{{Model: (*testNote)(nil)}, {Model: (*testNote)(nil)}}, ErrDuplicateModel},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := NewService(&config.SyncConfig{}, server.db, tt.models)
+				assert.ErrorIs(t, err, tt.err)
+			})
+		}
+	})
+}
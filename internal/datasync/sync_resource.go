@@ -0,0 +1,97 @@
+package datasync
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/datasync"
+	"github.com/ilxqx/vef-framework-go/i18n"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+const (
+	permTokenSyncPull = "sys.sync.pull"
+	permTokenSyncPush = "sys.sync.push"
+)
+
+// NewResource creates the sync resource serving the pull and push endpoints to embedded clients.
+func NewResource(service datasync.Service) api.Resource {
+	return &Resource{
+		service: service,
+		Resource: api.NewRPCResource(
+			datasync.Resource,
+			api.WithOperations(
+				api.OperationSpec{Action: "pull", PermToken: permTokenSyncPull},
+				api.OperationSpec{Action: "push", PermToken: permTokenSyncPush},
+			),
+		),
+	}
+}
+
+// Resource handles sync Api endpoints.
+type Resource struct {
+	api.Resource
+
+	service datasync.Service
+}
+
+// PullParams contains parameters for pulling the server changes.
+type PullParams struct {
+	api.P
+
+	ClientID string   `json:"clientId" validate:"required"`
+	Cursor   int64    `json:"cursor"   validate:"gte=0"`
+	Models   []string `json:"models"`
+	Limit    int      `json:"limit"    validate:"gte=0"`
+}
+
+// Pull returns the changes following the cursor of the client.
+func (r *Resource) Pull(ctx fiber.Ctx, params PullParams) error {
+	pulled, err := r.service.Pull(ctx.Context(), datasync.PullRequest{
+		ClientID: params.ClientID,
+		Cursor:   params.Cursor,
+		Models:   params.Models,
+		Limit:    params.Limit,
+	})
+	if err != nil {
+		return mapSyncError(err)
+	}
+
+	return result.Ok(pulled).Response(ctx)
+}
+
+// PushParams contains parameters for pushing the changes of a client.
+type PushParams struct {
+	api.P
+
+	ClientID string            `json:"clientId" validate:"required"`
+	Cursor   int64             `json:"cursor"   validate:"gte=0"`
+	Changes  []datasync.Change `json:"changes"  validate:"required"`
+}
+
+// Push applies the changes of the client and returns their outcomes.
+func (r *Resource) Push(ctx fiber.Ctx, params PushParams) error {
+	pushed, err := r.service.Push(ctx.Context(), datasync.PushRequest{
+		ClientID: params.ClientID,
+		Cursor:   params.Cursor,
+		Changes:  params.Changes,
+	})
+	if err != nil {
+		return mapSyncError(err)
+	}
+
+	return result.Ok(pushed).Response(ctx)
+}
+
+func mapSyncError(err error) error {
+	if errors.Is(err, datasync.ErrUnknownModel) {
+		return result.Err(
+			i18n.T(result.ErrMessageSyncModelUnknown),
+			result.WithCode(result.ErrCodeSyncModelUnknown),
+		)
+	}
+
+	return err
+}
@@ -0,0 +1,42 @@
+package delayjob
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"github.com/ilxqx/vef-framework-go/delayjob"
+)
+
+// Module provides the delayed job scheduler.
+var Module = fx.Module(
+	"vef:delayjob",
+	fx.Provide(
+		fx.Annotate(
+			NewScheduler,
+			fx.ParamTags(``, ``, `group:"vef:delayjob:handlers"`),
+			fx.OnStart(func(scheduler *Scheduler) {
+				if scheduler != nil {
+					scheduler.Start()
+				}
+			}),
+			fx.OnStop(func(ctx context.Context, scheduler *Scheduler) error {
+				if scheduler == nil {
+					return nil
+				}
+
+				return scheduler.Stop(ctx)
+			}),
+		),
+		newDelayJobScheduler,
+	),
+)
+
+// newDelayJobScheduler exposes the scheduler through the public interface, nil when delayed jobs are disabled.
+func newDelayJobScheduler(scheduler *Scheduler) delayjob.Scheduler {
+	if scheduler == nil {
+		return nil
+	}
+
+	return scheduler
+}
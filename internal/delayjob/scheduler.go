@@ -0,0 +1,540 @@
+package delayjob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/delayjob"
+	"github.com/ilxqx/vef-framework-go/encoding"
+	"github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+var logger = log.Named("delayjob")
+
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultBatchSize    = 20
+	defaultConcurrency  = 10
+	defaultLease        = 5 * time.Minute
+	maxRetryDelay       = time.Hour
+	maxErrorLength      = 2000
+)
+
+// Scheduler persists delayed jobs and runs the due ones.
+// Due jobs are claimed with SELECT ... FOR UPDATE SKIP LOCKED so that several instances can poll the same table;
+// SQLite has no row locks and is only suited for a single instance.
+type Scheduler struct {
+	db           orm.DB
+	handlers     map[string]delayjob.Handler
+	types        []string
+	pollInterval time.Duration
+	batchSize    int
+	lease        time.Duration
+	slots        chan struct{}
+	wake         chan struct{}
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// NewScheduler creates the delayed job scheduler, returning nil when delayed jobs are disabled.
+func NewScheduler(cfg *config.DelayJobConfig, db orm.DB, handlers []delayjob.Handler) (*Scheduler, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	s := &Scheduler{
+		db:           db,
+		handlers:     make(map[string]delayjob.Handler, len(handlers)),
+		pollInterval: cfg.PollInterval,
+		batchSize:    cfg.BatchSize,
+		lease:        cfg.Lease,
+		wake:         make(chan struct{}, 1),
+	}
+
+	for _, handler := range handlers {
+		if _, exists := s.handlers[handler.Type()]; exists {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateHandler, handler.Type())
+		}
+
+		s.handlers[handler.Type()] = handler
+		s.types = append(s.types, handler.Type())
+	}
+
+	if s.pollInterval <= 0 {
+		s.pollInterval = defaultPollInterval
+	}
+
+	if s.batchSize <= 0 {
+		s.batchSize = defaultBatchSize
+	}
+
+	if s.lease <= 0 {
+		s.lease = defaultLease
+	}
+
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	s.slots = make(chan struct{}, concurrency)
+
+	return s, nil
+}
+
+func (s *Scheduler) Schedule(ctx context.Context, jobType string, payload any, runAt time.Time, opts ...delayjob.ScheduleOption) (string, error) {
+	options := delayjob.NewScheduleOptions(opts...)
+
+	encoded, err := encoding.ToJSON(payload)
+	if err != nil {
+		return constants.Empty, fmt.Errorf("failed to encode payload of delayed job %s: %w", jobType, err)
+	}
+
+	// Run times are stored with second precision, rounding up keeps jobs from running early.
+	runAt = runAt.Add(time.Second - 1).Truncate(time.Second)
+
+	job := &delayjob.Job{
+		Type:        jobType,
+		Payload:     encoded,
+		RunAt:       datetime.Of(runAt),
+		Status:      delayjob.StatusPending,
+		MaxAttempts: options.MaxAttempts,
+		RetryDelay:  int(options.RetryDelay / time.Second),
+	}
+
+	if options.Key == constants.Empty {
+		if _, err := s.db.NewInsert().Model(job).Exec(ctx); err != nil {
+			return constants.Empty, fmt.Errorf("failed to schedule delayed job %s: %w", jobType, err)
+		}
+	} else if err := s.scheduleWithKey(ctx, job, options.Key); err != nil {
+		return constants.Empty, fmt.Errorf("failed to schedule delayed job %s: %w", jobType, err)
+	}
+
+	// Jobs due before the next poll wake the poller, which then waits exactly until the job is due.
+	if time.Until(runAt) < s.pollInterval {
+		s.poke()
+	}
+
+	return job.ID, nil
+}
+
+// scheduleWithKey inserts the job or, when a pending job holds the key, replaces that job.
+// The unique index of the pending key makes concurrent schedules with the same key converge on one job.
+func (s *Scheduler) scheduleWithKey(ctx context.Context, job *delayjob.Job, key string) error {
+	job.Key = null.StringFrom(key)
+	job.PendingKey = job.Key
+
+	return s.db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+		if _, err := tx.NewInsert().
+			Model(job).
+			OnConflict(func(cb orm.ConflictBuilder) {
+				cb.Columns("pending_key").
+					DoUpdate().
+					Set("type").
+					Set("payload").
+					Set("run_at").
+					Set("attempts").
+					Set("max_attempts").
+					Set("retry_delay").
+					Set("last_error").
+					Set("updated_at").
+					Set("updated_by")
+			}).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		// The replaced job keeps its ID
+		return tx.NewSelect().
+			Model((*delayjob.Job)(nil)).
+			Select("id").
+			Where(func(cb orm.ConditionBuilder) {
+				cb.Equals("pending_key", key)
+			}).
+			Scan(ctx, &job.ID)
+	})
+}
+
+func (s *Scheduler) ScheduleAfter(ctx context.Context, jobType string, payload any, delay time.Duration, opts ...delayjob.ScheduleOption) (string, error) {
+	return s.Schedule(ctx, jobType, payload, time.Now().Add(delay), opts...)
+}
+
+func (s *Scheduler) Cancel(ctx context.Context, id string) error {
+	res, err := s.db.NewUpdate().
+		Model((*delayjob.Job)(nil)).
+		Set("status", delayjob.StatusCanceled).
+		Set("pending_key", null.String{}).
+		Set("finished_at", datetime.Now()).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.PKEquals(id).
+				Equals("status", delayjob.StatusPending)
+		}).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to cancel delayed job %s: %w", id, err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return delayjob.ErrJobNotPending
+	}
+
+	return nil
+}
+
+func (s *Scheduler) CancelByKey(ctx context.Context, key string) error {
+	if _, err := s.db.NewUpdate().
+		Model((*delayjob.Job)(nil)).
+		Set("status", delayjob.StatusCanceled).
+		Set("pending_key", null.String{}).
+		Set("finished_at", datetime.Now()).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.Equals("key", key).
+				Equals("status", delayjob.StatusPending)
+		}).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("failed to cancel delayed job with key %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Start starts polling for due jobs.
+func (s *Scheduler) Start() {
+	if len(s.handlers) == 0 {
+		logger.Info("No delayed job handlers registered, polling is skipped")
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Go(func() {
+		s.loop(ctx)
+	})
+
+	logger.Infof("Delayed job scheduler started for job types %v", s.types)
+}
+
+// Stop stops polling and waits for the running jobs.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) poke() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-s.wake:
+		}
+
+		s.poll(ctx)
+
+		timer.Reset(s.nextWait(ctx))
+	}
+}
+
+// poll claims and starts due jobs while execution slots are free.
+func (s *Scheduler) poll(ctx context.Context) {
+	for {
+		free := cap(s.slots) - len(s.slots)
+		if free == 0 {
+			return
+		}
+
+		jobs, err := s.claim(ctx, min(free, s.batchSize))
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Errorf("Failed to claim delayed jobs: %v", err)
+			}
+
+			return
+		}
+
+		for _, job := range jobs {
+			s.slots <- struct{}{}
+
+			s.wg.Go(func() {
+				defer func() { <-s.slots }()
+
+				s.execute(ctx, job)
+			})
+		}
+
+		if len(jobs) < s.batchSize {
+			return
+		}
+	}
+}
+
+// nextWait returns the time until the next pending job is due, bounded by the poll interval.
+func (s *Scheduler) nextWait(ctx context.Context) time.Duration {
+	var runAt []datetime.DateTime
+
+	if err := s.db.NewSelect().
+		Model((*delayjob.Job)(nil)).
+		Select("run_at").
+		Where(func(cb orm.ConditionBuilder) {
+			cb.Equals("status", delayjob.StatusPending).
+				In("type", s.types)
+		}).
+		OrderBy("run_at").
+		Limit(1).
+		Scan(ctx, &runAt); err != nil || len(runAt) == 0 {
+		return s.pollInterval
+	}
+
+	return max(min(time.Until(runAt[0].Unwrap()), s.pollInterval), 0)
+}
+
+// claim marks up to limit due jobs as running under a lease.
+// Running jobs whose lease expired, e.g. after a crash, are claimed again.
+func (s *Scheduler) claim(ctx context.Context, limit int) ([]*delayjob.Job, error) {
+	var jobs []*delayjob.Job
+
+	err := s.db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+		now := datetime.Now()
+
+		query := tx.NewSelect().
+			Model(&jobs).
+			Where(func(cb orm.ConditionBuilder) {
+				cb.In("type", s.types).
+					Group(func(cb orm.ConditionBuilder) {
+						cb.Group(func(cb orm.ConditionBuilder) {
+							cb.Equals("status", delayjob.StatusPending).
+								LessThanOrEqual("run_at", now)
+						}).OrGroup(func(cb orm.ConditionBuilder) {
+							cb.Equals("status", delayjob.StatusRunning).
+								LessThan("locked_until", now)
+						})
+					})
+			}).
+			OrderBy("run_at").
+			Limit(limit)
+
+		query.ExprBuilder().ExecByDialect(orm.DialectExecs{
+			SQLite:  func() {},
+			Default: func() { query.ForUpdateSkipLocked() },
+		})
+
+		if err := query.Scan(ctx); err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		// The lease identifies the claim, an instance stores the outcome only while the job holds its lease.
+		lockedUntil := null.DateTimeFrom(now.Add(s.lease))
+		ids := make([]string, len(jobs))
+
+		for i, job := range jobs {
+			ids[i] = job.ID
+			job.Status = delayjob.StatusRunning
+			job.Attempts++
+			job.LockedUntil = lockedUntil
+		}
+
+		_, err := tx.NewUpdate().
+			Model((*delayjob.Job)(nil)).
+			Set("status", delayjob.StatusRunning).
+			Set("pending_key", null.String{}).
+			SetExpr("attempts", func(eb orm.ExprBuilder) any {
+				return eb.Add(eb.Column("attempts"), 1)
+			}).
+			Set("locked_until", lockedUntil).
+			Where(func(cb orm.ConditionBuilder) {
+				cb.PKIn(ids)
+			}).
+			Exec(ctx)
+
+		return err
+	})
+
+	return jobs, err
+}
+
+func (s *Scheduler) execute(ctx context.Context, job *delayjob.Job) {
+	handlerCtx, cancel := context.WithTimeout(ctx, s.lease)
+	defer cancel()
+
+	start := time.Now()
+	err := s.handle(handlerCtx, job)
+
+	// The outcome is stored even when the scheduler stops meanwhile.
+	storeCtx, storeCancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer storeCancel()
+
+	if err == nil {
+		logger.Infof("Delayed job %s (%s) succeeded in %v", job.ID, job.Type, time.Since(start))
+		s.finish(storeCtx, job, delayjob.StatusSucceeded, nil)
+
+		return
+	}
+
+	if job.Attempts >= job.MaxAttempts {
+		logger.Errorf("Delayed job %s (%s) failed after %d attempts: %v", job.ID, job.Type, job.Attempts, err)
+		s.finish(storeCtx, job, delayjob.StatusFailed, err)
+
+		return
+	}
+
+	retryAt := datetime.Of(time.Now().Add(retryDelay(job)))
+	logger.Warnf("Delayed job %s (%s) attempt %d failed, retrying at %s: %v", job.ID, job.Type, job.Attempts, retryAt, err)
+
+	s.reschedule(storeCtx, job, retryAt, err)
+}
+
+// handle runs the handler, converting panics into errors.
+func (s *Scheduler) handle(ctx context.Context, job *delayjob.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v\n%s", ErrHandlerPanicked, r, debug.Stack())
+		}
+	}()
+
+	return s.handlers[job.Type].Handle(ctx, job)
+}
+
+func (s *Scheduler) finish(ctx context.Context, job *delayjob.Job, status delayjob.Status, err error) {
+	query := s.db.NewUpdate().
+		Model((*delayjob.Job)(nil)).
+		Set("status", status).
+		Set("finished_at", datetime.Now()).
+		Set("locked_until", null.DateTime{}).
+		Where(owned(job))
+
+	if err != nil {
+		query.Set("last_error", errorMessage(err))
+	}
+
+	res, updateErr := query.Exec(ctx)
+	if updateErr != nil {
+		logger.Errorf("Failed to store outcome of delayed job %s: %v", job.ID, updateErr)
+
+		return
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		logger.Warnf("Delayed job %s lost its lease before it finished, its outcome is discarded", job.ID)
+	}
+}
+
+// reschedule makes the job pending again for its next attempt.
+// The job takes back its key unless another job was scheduled with the key while it was running.
+func (s *Scheduler) reschedule(ctx context.Context, job *delayjob.Job, retryAt datetime.DateTime, err error) {
+	update := func(pendingKey null.String) (int64, error) {
+		res, execErr := s.db.NewUpdate().
+			Model((*delayjob.Job)(nil)).
+			Set("status", delayjob.StatusPending).
+			Set("pending_key", pendingKey).
+			Set("run_at", retryAt).
+			Set("last_error", errorMessage(err)).
+			Set("locked_until", null.DateTime{}).
+			Where(owned(job)).
+			Exec(ctx)
+		if execErr != nil {
+			return 0, execErr
+		}
+
+		return res.RowsAffected()
+	}
+
+	affected, updateErr := update(job.Key)
+	if errors.Is(updateErr, result.ErrRecordAlreadyExists) {
+		affected, updateErr = update(null.String{})
+	}
+
+	if updateErr != nil {
+		logger.Errorf("Failed to reschedule delayed job %s: %v", job.ID, updateErr)
+
+		return
+	}
+
+	if affected == 0 {
+		logger.Warnf("Delayed job %s lost its lease before it finished, its retry is left to the new claim", job.ID)
+	}
+}
+
+// owned matches the job while it is running under the lease of its claim,
+// so that an instance whose lease expired does not overwrite the outcome of a new claim.
+func owned(job *delayjob.Job) func(orm.ConditionBuilder) {
+	return func(cb orm.ConditionBuilder) {
+		cb.PKEquals(job.ID).
+			Equals("status", delayjob.StatusRunning).
+			Equals("locked_until", job.LockedUntil)
+	}
+}
+
+// retryDelay doubles the configured delay for every failed attempt.
+func retryDelay(job *delayjob.Job) time.Duration {
+	delay := time.Duration(job.RetryDelay) * time.Second
+	for range job.Attempts - 1 {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+
+	return delay
+}
+
+// errorMessage returns the message of err, cut to maxErrorLength characters.
+func errorMessage(err error) string {
+	message := err.Error()
+	if utf8.RuneCountInString(message) > maxErrorLength {
+		message = string([]rune(message)[:maxErrorLength])
+	}
+
+	return message
+}
+
+// Types returns the job types handled by this instance.
+func (s *Scheduler) Types() []string {
+	return slices.Clone(s.types)
+}
+
+var (
+	// ErrDuplicateHandler is returned when two handlers are registered for the same job type.
+	ErrDuplicateHandler = errors.New("duplicate delayed job handler")
+	// ErrHandlerPanicked is recorded when a handler panics.
+	ErrHandlerPanicked = errors.New("delayed job handler panicked")
+)
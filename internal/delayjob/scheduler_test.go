@@ -0,0 +1,344 @@
+package delayjob
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/delayjob"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+var errHandlerFailed = errors.New("handler failed")
+
+type reminderPayload struct {
+	OrderID string `json:"orderId"`
+}
+
+// SchedulerTestSuite tests scheduling, claiming and running delayed jobs on SQLite.
+type SchedulerTestSuite struct {
+	suite.Suite
+
+	ctx   context.Context
+	bunDB *bun.DB
+	db    orm.DB
+
+	handled  chan *delayjob.Job
+	failures atomic.Int32
+}
+
+func (s *SchedulerTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	var err error
+
+	s.bunDB, err = database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	_, err = s.bunDB.NewCreateTable().Model((*delayjob.Job)(nil)).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	s.db = iorm.New(s.bunDB)
+}
+
+func (s *SchedulerTestSuite) TearDownSuite() {
+	if s.bunDB != nil {
+		s.Require().NoError(s.bunDB.Close())
+	}
+}
+
+func (s *SchedulerTestSuite) SetupTest() {
+	s.handled = make(chan *delayjob.Job, 10)
+	s.failures.Store(0)
+
+	_, err := s.bunDB.NewDelete().Model((*delayjob.Job)(nil)).Where("1 = 1").Exec(s.ctx)
+	s.Require().NoError(err)
+}
+
+func (s *SchedulerTestSuite) newScheduler() *Scheduler {
+	scheduler, err := NewScheduler(
+		&config.DelayJobConfig{Enabled: true, PollInterval: time.Second},
+		s.db,
+		[]delayjob.Handler{
+			delayjob.NewHandler("reminder", func(_ context.Context, job *delayjob.Job) error {
+				s.handled <- job
+
+				return nil
+			}),
+			delayjob.NewHandler("flaky", func(context.Context, *delayjob.Job) error {
+				s.failures.Add(1)
+
+				return errHandlerFailed
+			}),
+		},
+	)
+	s.Require().NoError(err)
+	s.Require().NotNil(scheduler)
+
+	return scheduler
+}
+
+func (s *SchedulerTestSuite) findJob(id string) *delayjob.Job {
+	var job delayjob.Job
+
+	s.Require().NoError(s.db.NewSelect().
+		Model(&job).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.PKEquals(id)
+		}).
+		Scan(s.ctx))
+
+	return &job
+}
+
+func (s *SchedulerTestSuite) TestDisabled() {
+	scheduler, err := NewScheduler(&config.DelayJobConfig{}, s.db, nil)
+	s.NoError(err)
+	s.Nil(scheduler, "Should not create a scheduler when disabled")
+}
+
+func (s *SchedulerTestSuite) TestDuplicateHandler() {
+	_, err := NewScheduler(&config.DelayJobConfig{Enabled: true}, s.db, []delayjob.Handler{
+		delayjob.NewHandler("reminder", func(context.Context, *delayjob.Job) error { return nil }),
+		delayjob.NewHandler("reminder", func(context.Context, *delayjob.Job) error { return nil }),
+	})
+	s.ErrorIs(err, ErrDuplicateHandler)
+}
+
+func (s *SchedulerTestSuite) TestRunDueJob() {
+	scheduler := s.newScheduler()
+	scheduler.Start()
+
+	defer func() {
+		s.NoError(scheduler.Stop(s.ctx))
+	}()
+
+	id, err := scheduler.ScheduleAfter(s.ctx, "reminder", reminderPayload{OrderID: "42"}, 500*time.Millisecond)
+	s.Require().NoError(err)
+
+	select {
+	case job := <-s.handled:
+		s.Equal(id, job.ID)
+		s.Equal(1, job.Attempts)
+
+		var payload reminderPayload
+		s.Require().NoError(job.Bind(&payload))
+		s.Equal("42", payload.OrderID)
+	case <-time.After(5 * time.Second):
+		s.Fail("Should run the job once it is due")
+	}
+
+	s.Eventually(func() bool {
+		return s.findJob(id).Status == delayjob.StatusSucceeded
+	}, 2*time.Second, 50*time.Millisecond, "Should mark the job succeeded")
+}
+
+func (s *SchedulerTestSuite) TestNotDueJobIsNotClaimed() {
+	scheduler := s.newScheduler()
+
+	_, err := scheduler.ScheduleAfter(s.ctx, "reminder", nil, time.Hour)
+	s.Require().NoError(err)
+
+	jobs, err := scheduler.claim(s.ctx, 10)
+	s.Require().NoError(err)
+	s.Empty(jobs, "Should not claim jobs before their run time")
+}
+
+func (s *SchedulerTestSuite) TestRetryThenFail() {
+	scheduler := s.newScheduler()
+
+	id, err := scheduler.Schedule(s.ctx, "flaky", nil, time.Now().Add(-time.Second),
+		delayjob.WithMaxAttempts(2), delayjob.WithRetryDelay(time.Minute))
+	s.Require().NoError(err)
+
+	jobs, err := scheduler.claim(s.ctx, 10)
+	s.Require().NoError(err)
+	s.Require().Len(jobs, 1)
+
+	scheduler.execute(s.ctx, jobs[0])
+
+	job := s.findJob(id)
+	s.Equal(delayjob.StatusPending, job.Status, "Should reschedule the failed attempt")
+	s.Equal(1, job.Attempts)
+	s.Equal(errHandlerFailed.Error(), job.LastError.ValueOrZero())
+	s.True(job.RunAt.Unwrap().After(time.Now().Add(50*time.Second)), "Should delay the retry")
+
+	// Make the retry due
+	_, err = s.db.NewUpdate().
+		Model((*delayjob.Job)(nil)).
+		Set("run_at", datetime.Of(time.Now().Add(-time.Second))).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.PKEquals(id)
+		}).
+		Exec(s.ctx)
+	s.Require().NoError(err)
+
+	jobs, err = scheduler.claim(s.ctx, 10)
+	s.Require().NoError(err)
+	s.Require().Len(jobs, 1)
+	s.Equal(2, jobs[0].Attempts)
+
+	scheduler.execute(s.ctx, jobs[0])
+
+	job = s.findJob(id)
+	s.Equal(delayjob.StatusFailed, job.Status, "Should fail once the attempts are exhausted")
+	s.True(job.FinishedAt.Valid)
+	s.Equal(int32(2), s.failures.Load())
+}
+
+func (s *SchedulerTestSuite) TestExpiredLeaseIsReclaimed() {
+	scheduler := s.newScheduler()
+
+	id, err := scheduler.Schedule(s.ctx, "reminder", nil, time.Now().Add(-time.Second))
+	s.Require().NoError(err)
+
+	jobs, err := scheduler.claim(s.ctx, 10)
+	s.Require().NoError(err)
+	s.Require().Len(jobs, 1)
+
+	jobs, err = scheduler.claim(s.ctx, 10)
+	s.Require().NoError(err)
+	s.Empty(jobs, "Should not claim a job under lease")
+
+	_, err = s.db.NewUpdate().
+		Model((*delayjob.Job)(nil)).
+		Set("locked_until", datetime.Of(time.Now().Add(-time.Second))).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.PKEquals(id)
+		}).
+		Exec(s.ctx)
+	s.Require().NoError(err)
+
+	jobs, err = scheduler.claim(s.ctx, 10)
+	s.Require().NoError(err)
+	s.Require().Len(jobs, 1, "Should claim the job again after its lease expired")
+	s.Equal(2, jobs[0].Attempts)
+}
+
+func (s *SchedulerTestSuite) TestScheduleWithKeyReplacesPendingJob() {
+	scheduler := s.newScheduler()
+
+	first, err := scheduler.ScheduleAfter(s.ctx, "reminder", reminderPayload{OrderID: "1"}, time.Hour, delayjob.WithKey("order:1:remind"))
+	s.Require().NoError(err)
+
+	second, err := scheduler.ScheduleAfter(s.ctx, "reminder", reminderPayload{OrderID: "2"}, 2*time.Hour, delayjob.WithKey("order:1:remind"))
+	s.Require().NoError(err)
+	s.Equal(first, second, "Should replace the pending job with the same key")
+
+	count, err := s.db.NewSelect().Model((*delayjob.Job)(nil)).Count(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(1), count)
+
+	var payload reminderPayload
+	s.Require().NoError(s.findJob(first).Bind(&payload))
+	s.Equal("2", payload.OrderID)
+}
+
+func (s *SchedulerTestSuite) TestScheduleWithKeyWhileRunning() {
+	scheduler := s.newScheduler()
+
+	first, err := scheduler.Schedule(s.ctx, "flaky", nil, time.Now().Add(-time.Second),
+		delayjob.WithKey("order:2:remind"), delayjob.WithRetryDelay(time.Minute))
+	s.Require().NoError(err)
+
+	jobs, err := scheduler.claim(s.ctx, 10)
+	s.Require().NoError(err)
+	s.Require().Len(jobs, 1)
+
+	second, err := scheduler.ScheduleAfter(s.ctx, "flaky", nil, time.Hour, delayjob.WithKey("order:2:remind"))
+	s.Require().NoError(err)
+	s.NotEqual(first, second, "Should not replace a running job")
+
+	scheduler.execute(s.ctx, jobs[0])
+
+	job := s.findJob(first)
+	s.Equal(delayjob.StatusPending, job.Status, "Should reschedule the failed attempt")
+	s.False(job.PendingKey.Valid, "Should leave the key to the job scheduled meanwhile")
+	s.Equal("order:2:remind", s.findJob(second).PendingKey.ValueOrZero())
+
+	third, err := scheduler.ScheduleAfter(s.ctx, "flaky", nil, time.Hour, delayjob.WithKey("order:2:remind"))
+	s.Require().NoError(err)
+	s.Equal(second, third, "Should replace the job holding the key")
+}
+
+func (s *SchedulerTestSuite) TestExpiredClaimDoesNotStoreOutcome() {
+	scheduler := s.newScheduler()
+
+	id, err := scheduler.Schedule(s.ctx, "reminder", nil, time.Now().Add(-time.Second))
+	s.Require().NoError(err)
+
+	stale, err := scheduler.claim(s.ctx, 10)
+	s.Require().NoError(err)
+	s.Require().Len(stale, 1)
+
+	_, err = s.db.NewUpdate().
+		Model((*delayjob.Job)(nil)).
+		Set("locked_until", datetime.Of(time.Now().Add(-time.Second))).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.PKEquals(id)
+		}).
+		Exec(s.ctx)
+	s.Require().NoError(err)
+
+	// Another instance claims the job under a lease of its own
+	scheduler.lease = time.Hour
+
+	jobs, err := scheduler.claim(s.ctx, 10)
+	s.Require().NoError(err)
+	s.Require().Len(jobs, 1)
+
+	scheduler.finish(s.ctx, stale[0], delayjob.StatusFailed, errHandlerFailed)
+
+	job := s.findJob(id)
+	s.Equal(delayjob.StatusRunning, job.Status, "Should not store the outcome of an expired claim")
+	s.Equal(jobs[0].LockedUntil.ValueOrZero().String(), job.LockedUntil.ValueOrZero().String(), "Should keep the lease of the current claim")
+
+	scheduler.finish(s.ctx, jobs[0], delayjob.StatusSucceeded, nil)
+	s.Equal(delayjob.StatusSucceeded, s.findJob(id).Status, "Should store the outcome of the current claim")
+}
+
+func (s *SchedulerTestSuite) TestCancel() {
+	scheduler := s.newScheduler()
+
+	id, err := scheduler.ScheduleAfter(s.ctx, "reminder", nil, time.Hour)
+	s.Require().NoError(err)
+
+	s.Require().NoError(scheduler.Cancel(s.ctx, id))
+	s.Equal(delayjob.StatusCanceled, s.findJob(id).Status)
+
+	s.ErrorIs(scheduler.Cancel(s.ctx, id), delayjob.ErrJobNotPending, "Should not cancel a job twice")
+}
+
+func (s *SchedulerTestSuite) TestCancelByKey() {
+	scheduler := s.newScheduler()
+
+	id, err := scheduler.ScheduleAfter(s.ctx, "reminder", nil, time.Hour, delayjob.WithKey("order:7:timeout"))
+	s.Require().NoError(err)
+
+	s.Require().NoError(scheduler.CancelByKey(s.ctx, "order:7:timeout"))
+	s.Equal(delayjob.StatusCanceled, s.findJob(id).Status)
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
+
+func TestErrorMessage(t *testing.T) {
+	message := errorMessage(errors.New(strings.Repeat("错", maxErrorLength+1)))
+
+	assert.True(t, utf8.ValidString(message), "Should not cut a character in half")
+	assert.Equal(t, maxErrorLength, utf8.RuneCountInString(message))
+}
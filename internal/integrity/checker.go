@@ -0,0 +1,212 @@
+package integrity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/integrity"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+const (
+	// sampleSize is the number of missing referenced values reported per reference.
+	sampleSize = 10
+	// refAlias aliases the referenced table so that self references resolve the referencing column to the outer table.
+	refAlias = "ref"
+)
+
+var (
+	// ErrInvalidReference is returned when a reference misses its tables or columns or has an unknown action.
+	ErrInvalidReference = errors.New("invalid integrity reference")
+	// ErrDuplicateReference is returned when two references share the same name.
+	ErrDuplicateReference = errors.New("duplicate integrity reference")
+)
+
+// Checker scans logical foreign keys for rows referencing missing rows.
+type Checker struct {
+	db         orm.DB
+	references []integrity.Reference
+}
+
+// NewChecker creates a checker of the configured and the provided references.
+func NewChecker(cfg *config.IntegrityConfig, db orm.DB, provided []integrity.Reference) (*Checker, error) {
+	references := make([]integrity.Reference, 0, len(cfg.References)+len(provided))
+	for _, ref := range cfg.References {
+		references = append(references, integrity.Reference{
+			Name:      ref.Name,
+			Table:     ref.Table,
+			Column:    ref.Column,
+			RefTable:  ref.RefTable,
+			RefColumn: ref.RefColumn,
+			OnOrphan:  integrity.Action(ref.OnOrphan),
+		})
+	}
+
+	references = append(references, provided...)
+
+	names := make(map[string]bool, len(references))
+	for i := range references {
+		ref := &references[i]
+		if ref.Table == constants.Empty || ref.Column == constants.Empty || ref.RefTable == constants.Empty {
+			return nil, fmt.Errorf("%w: table, column and ref table are required, got %+v", ErrInvalidReference, *ref)
+		}
+
+		ref.Name = lo.CoalesceOrEmpty(ref.Name, ref.Table+constants.Dot+ref.Column)
+		ref.RefColumn = lo.CoalesceOrEmpty(ref.RefColumn, constants.ColumnID)
+		ref.OnOrphan = lo.CoalesceOrEmpty(ref.OnOrphan, integrity.ActionReport)
+
+		if !slices.Contains([]integrity.Action{integrity.ActionReport, integrity.ActionSetNull, integrity.ActionDelete}, ref.OnOrphan) {
+			return nil, fmt.Errorf("%w: unknown action %q of %s", ErrInvalidReference, ref.OnOrphan, ref.Name)
+		}
+
+		if names[ref.Name] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateReference, ref.Name)
+		}
+
+		names[ref.Name] = true
+	}
+
+	slices.SortFunc(references, func(a, b integrity.Reference) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return &Checker{
+		db:         db,
+		references: references,
+	}, nil
+}
+
+func (c *Checker) References() []integrity.Reference {
+	return slices.Clone(c.references)
+}
+
+func (c *Checker) Check(ctx context.Context, names ...string) ([]integrity.Result, error) {
+	return c.run(ctx, names, false)
+}
+
+func (c *Checker) Fix(ctx context.Context, names ...string) ([]integrity.Result, error) {
+	return c.run(ctx, names, true)
+}
+
+func (c *Checker) run(ctx context.Context, names []string, fix bool) ([]integrity.Result, error) {
+	references, err := c.selectReferences(names)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]integrity.Result, 0, len(references))
+	for _, ref := range references {
+		result, err := c.check(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check reference %s: %w", ref.Name, err)
+		}
+
+		if fix && result.Orphans > 0 && ref.OnOrphan != integrity.ActionReport {
+			if result.Fixed, err = c.fix(ctx, ref); err != nil {
+				return nil, fmt.Errorf("failed to fix reference %s: %w", ref.Name, err)
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (c *Checker) selectReferences(names []string) ([]integrity.Reference, error) {
+	if len(names) == 0 {
+		return c.references, nil
+	}
+
+	references := make([]integrity.Reference, 0, len(names))
+	for _, name := range names {
+		ref, ok := lo.Find(c.references, func(ref integrity.Reference) bool {
+			return ref.Name == name
+		})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", integrity.ErrReferenceNotFound, name)
+		}
+
+		references = append(references, ref)
+	}
+
+	return references, nil
+}
+
+// orphaned restricts a query on the referencing table to the rows whose referenced row is missing.
+func orphaned(ref integrity.Reference) func(orm.ConditionBuilder) {
+	column := ref.Table + constants.Dot + ref.Column
+
+	return func(cb orm.ConditionBuilder) {
+		cb.IsNotNull(column).
+			Expr(func(eb orm.ExprBuilder) any {
+				return eb.NotExists(func(query orm.SelectQuery) {
+					query.Table(ref.RefTable, refAlias).
+						SelectExpr(func(eb orm.ExprBuilder) any {
+							return eb.Expr("1")
+						}).
+						Where(func(cb orm.ConditionBuilder) {
+							cb.EqualsColumn(refAlias+constants.Dot+ref.RefColumn, column)
+						})
+				})
+			})
+	}
+}
+
+func (c *Checker) check(ctx context.Context, ref integrity.Reference) (integrity.Result, error) {
+	result := integrity.Result{
+		Reference: ref,
+		Samples:   []string{},
+	}
+
+	var err error
+	if result.Orphans, err = c.db.NewSelect().
+		Table(ref.Table).
+		Where(orphaned(ref)).
+		Count(ctx); err != nil || result.Orphans == 0 {
+		return result, err
+	}
+
+	err = c.db.NewSelect().
+		Table(ref.Table).
+		Distinct().
+		Select(ref.Table+constants.Dot+ref.Column).
+		Where(orphaned(ref)).
+		OrderBy(ref.Table+constants.Dot+ref.Column).
+		Limit(sampleSize).
+		Scan(ctx, &result.Samples)
+
+	return result, err
+}
+
+func (c *Checker) fix(ctx context.Context, ref integrity.Reference) (int64, error) {
+	var executor orm.QueryExecutor
+
+	switch ref.OnOrphan {
+	case integrity.ActionSetNull:
+		executor = c.db.NewUpdate().
+			Table(ref.Table).
+			Set(ref.Column, nil).
+			Where(orphaned(ref))
+	case integrity.ActionDelete:
+		executor = c.db.NewDelete().
+			Table(ref.Table).
+			Where(orphaned(ref))
+	default:
+		return 0, nil
+	}
+
+	res, err := executor.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
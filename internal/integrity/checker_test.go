@@ -0,0 +1,184 @@
+package integrity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/integrity"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+type testDepartment struct {
+	bun.BaseModel `bun:"table:test_department"`
+
+	ID       string      `bun:"id,pk"`
+	ParentID null.String `bun:"parent_id"`
+}
+
+type testEmployee struct {
+	bun.BaseModel `bun:"table:test_employee"`
+
+	ID     string      `bun:"id,pk"`
+	DeptID null.String `bun:"dept_id"`
+}
+
+// CheckerTestSuite tests checking and fixing logical foreign keys on SQLite.
+type CheckerTestSuite struct {
+	suite.Suite
+
+	ctx   context.Context
+	bunDB *bun.DB
+	db    orm.DB
+}
+
+func (s *CheckerTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	var err error
+
+	s.bunDB, err = database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	for _, model := range []any{(*testDepartment)(nil), (*testEmployee)(nil)} {
+		_, err = s.bunDB.NewCreateTable().Model(model).Exec(s.ctx)
+		s.Require().NoError(err)
+	}
+
+	s.db = iorm.New(s.bunDB)
+}
+
+func (s *CheckerTestSuite) TearDownSuite() {
+	if s.bunDB != nil {
+		s.Require().NoError(s.bunDB.Close())
+	}
+}
+
+func (s *CheckerTestSuite) SetupTest() {
+	for _, model := range []any{(*testDepartment)(nil), (*testEmployee)(nil)} {
+		_, err := s.bunDB.NewDelete().Model(model).Where("1 = 1").Exec(s.ctx)
+		s.Require().NoError(err)
+	}
+
+	departments := []testDepartment{
+		{ID: "d1"},
+		{ID: "d2", ParentID: null.StringFrom("d1")},
+		{ID: "d3", ParentID: null.StringFrom("d9")},
+	}
+	employees := []testEmployee{
+		{ID: "e1", DeptID: null.StringFrom("d1")},
+		{ID: "e2", DeptID: null.StringFrom("d8")},
+		{ID: "e3", DeptID: null.StringFrom("d7")},
+		{ID: "e4"},
+	}
+
+	_, err := s.bunDB.NewInsert().Model(&departments).Exec(s.ctx)
+	s.Require().NoError(err)
+	_, err = s.bunDB.NewInsert().Model(&employees).Exec(s.ctx)
+	s.Require().NoError(err)
+}
+
+func (s *CheckerTestSuite) newChecker(onOrphan integrity.Action) *Checker {
+	checker, err := NewChecker(
+		&config.IntegrityConfig{
+			References: []config.ReferenceConfig{
+				{Table: "test_employee", Column: "dept_id", RefTable: "test_department", OnOrphan: string(onOrphan)},
+			},
+		},
+		s.db,
+		[]integrity.Reference{
+			{Name: "department_parent", Table: "test_department", Column: "parent_id", RefTable: "test_department"},
+		},
+	)
+	s.Require().NoError(err)
+
+	return checker
+}
+
+func (s *CheckerTestSuite) countEmployees() int64 {
+	count, err := s.db.NewSelect().Model((*testEmployee)(nil)).Count(s.ctx)
+	s.Require().NoError(err)
+
+	return count
+}
+
+func (s *CheckerTestSuite) TestReferences() {
+	references := s.newChecker("").References()
+
+	s.Require().Len(references, 2)
+	s.Equal("department_parent", references[0].Name, "Should order references by name")
+	s.Equal("test_employee.dept_id", references[1].Name, "Should default the name to table.column")
+	s.Equal(constants.ColumnID, references[1].RefColumn, "Should default the referenced column to id")
+	s.Equal(integrity.ActionReport, references[1].OnOrphan, "Should default to reporting orphans")
+}
+
+func (s *CheckerTestSuite) TestInvalidReferences() {
+	_, err := NewChecker(&config.IntegrityConfig{}, s.db, []integrity.Reference{{Table: "test_employee"}})
+	s.ErrorIs(err, ErrInvalidReference)
+
+	_, err = NewChecker(&config.IntegrityConfig{}, s.db, []integrity.Reference{
+		{Table: "test_employee", Column: "dept_id", RefTable: "test_department", OnOrphan: "cascade"},
+	})
+	s.ErrorIs(err, ErrInvalidReference, "Should reject unknown actions")
+
+	_, err = NewChecker(&config.IntegrityConfig{}, s.db, []integrity.Reference{
+		{Table: "test_employee", Column: "dept_id", RefTable: "test_department"},
+		{Table: "test_employee", Column: "dept_id", RefTable: "test_department"},
+	})
+	s.ErrorIs(err, ErrDuplicateReference)
+}
+
+func (s *CheckerTestSuite) TestCheck() {
+	results, err := s.newChecker(integrity.ActionDelete).Check(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(results, 2)
+
+	s.Equal(int64(1), results[0].Orphans, "Should find the department with a missing parent")
+	s.Equal([]string{"d9"}, results[0].Samples)
+
+	s.Equal(int64(2), results[1].Orphans, "Should find the employees of missing departments, ignoring nulls")
+	s.Equal([]string{"d7", "d8"}, results[1].Samples)
+	s.Zero(results[1].Fixed, "Should not fix when checking")
+	s.Equal(int64(4), s.countEmployees())
+}
+
+func (s *CheckerTestSuite) TestCheckByName() {
+	results, err := s.newChecker("").Check(s.ctx, "department_parent")
+	s.Require().NoError(err)
+	s.Require().Len(results, 1)
+	s.Equal("department_parent", results[0].Reference.Name)
+
+	_, err = s.newChecker("").Check(s.ctx, "unknown")
+	s.ErrorIs(err, integrity.ErrReferenceNotFound)
+}
+
+func (s *CheckerTestSuite) TestFixDelete() {
+	results, err := s.newChecker(integrity.ActionDelete).Fix(s.ctx)
+	s.Require().NoError(err)
+
+	s.Zero(results[0].Fixed, "Should only report references with the report action")
+	s.Equal(int64(2), results[1].Fixed)
+	s.Equal(int64(2), s.countEmployees(), "Should delete the orphaned employees")
+}
+
+func (s *CheckerTestSuite) TestFixSetNull() {
+	results, err := s.newChecker(integrity.ActionSetNull).Fix(s.ctx, "test_employee.dept_id")
+	s.Require().NoError(err)
+	s.Equal(int64(2), results[0].Fixed)
+	s.Equal(int64(4), s.countEmployees(), "Should keep the orphaned employees")
+
+	results, err = s.newChecker(integrity.ActionSetNull).Check(s.ctx, "test_employee.dept_id")
+	s.Require().NoError(err)
+	s.Zero(results[0].Orphans, "Should clear the missing references")
+}
+
+func TestCheckerTestSuite(t *testing.T) {
+	suite.Run(t, new(CheckerTestSuite))
+}
@@ -0,0 +1,82 @@
+package integrity
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/i18n"
+	"github.com/ilxqx/vef-framework-go/integrity"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+const (
+	permTokenIntegrityQuery = "sys.integrity.query"
+	permTokenIntegrityFix   = "sys.integrity.fix"
+)
+
+// NewResource creates the integrity resource listing, checking and fixing the logical foreign keys.
+func NewResource(checker integrity.Checker) api.Resource {
+	return &Resource{
+		checker: checker,
+		Resource: api.NewRPCResource(
+			"sys/integrity",
+			api.WithOperations(
+				api.OperationSpec{Action: "get_references", PermToken: permTokenIntegrityQuery},
+				api.OperationSpec{Action: "check", PermToken: permTokenIntegrityQuery},
+				api.OperationSpec{Action: "fix", PermToken: permTokenIntegrityFix, EnableAudit: true},
+			),
+		),
+	}
+}
+
+// Resource handles integrity Api endpoints.
+type Resource struct {
+	api.Resource
+
+	checker integrity.Checker
+}
+
+// GetReferences returns the checked references ordered by name.
+func (r *Resource) GetReferences(ctx fiber.Ctx) error {
+	return result.Ok(r.checker.References()).Response(ctx)
+}
+
+// CheckParams contains the names of the references to check, all of them when empty.
+type CheckParams struct {
+	api.P
+
+	Names []string `json:"names"`
+}
+
+// Check counts the orphaned rows of the references.
+func (r *Resource) Check(ctx fiber.Ctx, params CheckParams) error {
+	results, err := r.checker.Check(ctx.Context(), params.Names...)
+	if err != nil {
+		return mapReferenceError(err)
+	}
+
+	return result.Ok(results).Response(ctx)
+}
+
+// Fix applies the configured action of the references to their orphaned rows.
+func (r *Resource) Fix(ctx fiber.Ctx, params CheckParams) error {
+	results, err := r.checker.Fix(ctx.Context(), params.Names...)
+	if err != nil {
+		return mapReferenceError(err)
+	}
+
+	return result.Ok(results).Response(ctx)
+}
+
+func mapReferenceError(err error) error {
+	if errors.Is(err, integrity.ErrReferenceNotFound) {
+		return result.Err(
+			i18n.T(result.ErrMessageIntegrityReferenceNotFound),
+			result.WithCode(result.ErrCodeIntegrityReferenceNotFound),
+		)
+	}
+
+	return err
+}
@@ -0,0 +1,77 @@
+package integrity
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/cron"
+	"github.com/ilxqx/vef-framework-go/integrity"
+	"github.com/ilxqx/vef-framework-go/internal/log"
+)
+
+var logger = log.Named("integrity")
+
+// Module provides the soft reference integrity checker, its Api resource and its scheduled check.
+var Module = fx.Module(
+	"vef:integrity",
+	fx.Provide(
+		fx.Annotate(
+			NewChecker,
+			fx.ParamTags(``, ``, `group:"vef:integrity:references"`),
+			fx.As(new(integrity.Checker)),
+		),
+		fx.Annotate(
+			NewResource,
+			fx.ResultTags(`group:"vef:api:resources"`),
+		),
+	),
+	fx.Invoke(scheduleCheck),
+)
+
+// scheduleCheck registers the scheduled check of the references when a schedule is configured.
+func scheduleCheck(cfg *config.IntegrityConfig, checker integrity.Checker, scheduler cron.Scheduler) error {
+	if cfg.Schedule == "" {
+		return nil
+	}
+
+	_, err := scheduler.NewJob(cron.NewCronJob(
+		cfg.Schedule,
+		false,
+		cron.WithName("integrity_check"),
+		cron.WithTags("vef", "integrity"),
+		cron.WithTask(func(ctx context.Context) error {
+			return runCheck(ctx, checker, cfg.Fix)
+		}),
+	))
+
+	return err
+}
+
+// runCheck checks or fixes all references and logs the orphans found.
+func runCheck(ctx context.Context, checker integrity.Checker, fix bool) error {
+	run := checker.Check
+	if fix {
+		run = checker.Fix
+	}
+
+	results, err := run(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if result.Orphans == 0 {
+			continue
+		}
+
+		logger.Warnf(
+			"Reference %s has %d orphaned rows in %s.%s (fixed: %d), missing %s.%s values: %v",
+			result.Reference.Name, result.Orphans, result.Reference.Table, result.Reference.Column,
+			result.Fixed, result.Reference.RefTable, result.Reference.RefColumn, result.Samples,
+		)
+	}
+
+	return nil
+}
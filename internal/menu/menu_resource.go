@@ -0,0 +1,125 @@
+package menu
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/menu"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+const (
+	permTokenMenuQuery  = "sys.menu.query"
+	permTokenMenuAssign = "sys.menu.assign"
+)
+
+// NewResource creates the menu resource serving the permitted menus of the current user and role bindings.
+func NewResource(service menu.Service, db orm.DB, publisher event.Publisher) api.Resource {
+	return &Resource{
+		service:   service,
+		db:        db,
+		publisher: publisher,
+		Resource: api.NewRPCResource(
+			"sys/menu",
+			api.WithOperations(
+				api.OperationSpec{Action: "get_permitted"},
+				api.OperationSpec{Action: "get_tree", PermToken: permTokenMenuQuery},
+				api.OperationSpec{Action: "get_role_menus", PermToken: permTokenMenuQuery},
+				api.OperationSpec{Action: "save_role_menus", PermToken: permTokenMenuAssign, EnableAudit: true},
+			),
+		),
+	}
+}
+
+// Resource handles menu Api endpoints.
+type Resource struct {
+	api.Resource
+
+	service   menu.Service
+	db        orm.DB
+	publisher event.Publisher
+}
+
+// GetPermitted returns the navigation menus and the permission tokens of buttons permitted to the current user.
+func (r *Resource) GetPermitted(ctx fiber.Ctx, principal *security.Principal) error {
+	permitted, err := r.service.Permitted(ctx.Context(), principal)
+	if err != nil {
+		return err
+	}
+
+	return result.Ok(permitted).Response(ctx)
+}
+
+// GetTree returns all menu entries as a tree, e.g. for the role assignment page.
+func (r *Resource) GetTree(ctx fiber.Ctx) error {
+	tree, err := r.service.Tree(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	return result.Ok(tree).Response(ctx)
+}
+
+// RoleMenusParams contains parameters for reading the menu bindings of a role.
+type RoleMenusParams struct {
+	api.P
+
+	Role string `json:"role" validate:"required"`
+}
+
+// GetRoleMenus returns the ids of the menu entries bound to a role.
+func (r *Resource) GetRoleMenus(ctx fiber.Ctx, params RoleMenusParams) error {
+	ids, err := r.service.RoleMenuIDs(ctx.Context(), params.Role)
+	if err != nil {
+		return err
+	}
+
+	return result.Ok(ids).Response(ctx)
+}
+
+// SaveRoleMenusParams contains parameters for replacing the menu bindings of a role.
+type SaveRoleMenusParams struct {
+	api.P
+
+	Role    string   `json:"role"    validate:"required"`
+	MenuIDs []string `json:"menuIds"`
+}
+
+// SaveRoleMenus replaces the menu bindings of a role in the sys_role_menu table.
+// The menu and RBAC permission caches of the role are invalidated afterwards.
+func (r *Resource) SaveRoleMenus(ctx fiber.Ctx, params SaveRoleMenusParams) error {
+	if err := r.db.RunInTX(ctx.Context(), func(txCtx context.Context, tx orm.DB) error {
+		if _, err := tx.NewDelete().
+			Model((*menu.RoleMenu)(nil)).
+			Where(func(cb orm.ConditionBuilder) {
+				cb.Equals("role", params.Role)
+			}).
+			Exec(txCtx); err != nil {
+			return err
+		}
+
+		if len(params.MenuIDs) == 0 {
+			return nil
+		}
+
+		bindings := make([]menu.RoleMenu, 0, len(params.MenuIDs))
+		for _, menuID := range params.MenuIDs {
+			bindings = append(bindings, menu.RoleMenu{Role: params.Role, MenuID: menuID})
+		}
+
+		_, err := tx.NewInsert().Model(&bindings).Exec(txCtx)
+
+		return err
+	}); err != nil {
+		return err
+	}
+
+	menu.PublishMenusChangedEvent(r.publisher, params.Role)
+
+	return result.Ok().Response(ctx)
+}
@@ -0,0 +1,31 @@
+package menu
+
+import (
+	"go.uber.org/fx"
+
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/menu"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Module provides the menu service backed by the menu tables and the menu Api resource.
+// Applications can supply their own menu.Loader to read menus from another source.
+var Module = fx.Module(
+	"vef:menu",
+	fx.Provide(
+		fx.Annotate(
+			func(loader menu.Loader, db orm.DB, bus event.Bus) menu.Service {
+				if loader == nil {
+					loader = menu.NewDBLoader(db)
+				}
+
+				return menu.New(loader, bus)
+			},
+			fx.ParamTags(`optional:"true"`),
+		),
+		fx.Annotate(
+			NewResource,
+			fx.ResultTags(`group:"vef:api:resources"`),
+		),
+	),
+)
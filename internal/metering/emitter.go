@@ -0,0 +1,404 @@
+package metering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/metering"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+var logger = log.Named("metering")
+
+const (
+	defaultWindow        = time.Hour
+	defaultFlushInterval = 10 * time.Second
+	defaultGrace         = time.Minute
+	defaultBatchSize     = 100
+)
+
+// ErrWindowClosed is returned when usage of the current window cannot be stored because the window was closed,
+// e.g. by an instance whose clock is ahead.
+var ErrWindowClosed = errors.New("metering window already closed")
+
+// usageKey identifies the buffered usage of a subject and meter within a window.
+type usageKey struct {
+	subject     string
+	meter       string
+	aggregation metering.Aggregation
+	windowStart int64 // Unix seconds
+}
+
+// Emitter buffers recorded usage in memory and periodically:
+//   - flushes it into the sys_metered_usage rows of its windows,
+//   - closes the windows ended longer than the grace period ago by writing their billing records to the
+//     sys_billing_outbox table in the same transaction,
+//   - relays the unpublished billing records as billing events.
+//
+// Every subject, meter and window yields exactly one billing record, identified by its idempotency key,
+// even when several instances close the same window. Events are published at least once.
+type Emitter struct {
+	db            orm.DB
+	publisher     event.Publisher
+	window        time.Duration
+	flushInterval time.Duration
+	grace         time.Duration
+	batchSize     int
+	now           func() time.Time
+
+	mu      sync.Mutex
+	pending map[usageKey]int64
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewEmitter creates the usage emitter, returning nil when metering is disabled.
+func NewEmitter(cfg *config.MeteringConfig, db orm.DB, publisher event.Publisher) *Emitter {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	e := &Emitter{
+		db:            db,
+		publisher:     publisher,
+		window:        cfg.Window,
+		flushInterval: cfg.FlushInterval,
+		grace:         cfg.Grace,
+		batchSize:     cfg.BatchSize,
+		now:           time.Now,
+		pending:       make(map[usageKey]int64),
+	}
+
+	if e.window <= 0 {
+		e.window = defaultWindow
+	}
+
+	if e.flushInterval <= 0 {
+		e.flushInterval = defaultFlushInterval
+	}
+
+	if e.grace <= 0 {
+		e.grace = defaultGrace
+	}
+
+	if e.batchSize <= 0 {
+		e.batchSize = defaultBatchSize
+	}
+
+	return e
+}
+
+func (e *Emitter) Add(_ context.Context, subject, meter string, quantity int64) {
+	if quantity != 0 {
+		e.record(e.key(subject, meter, metering.AggregationSum), quantity)
+	}
+}
+
+func (e *Emitter) Observe(_ context.Context, subject, meter string, value int64) {
+	e.record(e.key(subject, meter, metering.AggregationMax), value)
+}
+
+// key returns the key of the usage within the current window.
+func (e *Emitter) key(subject, meter string, aggregation metering.Aggregation) usageKey {
+	return usageKey{
+		subject:     subject,
+		meter:       meter,
+		aggregation: aggregation,
+		windowStart: e.now().Truncate(e.window).Unix(),
+	}
+}
+
+func (e *Emitter) record(key usageKey, quantity int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	current, exists := e.pending[key]
+	if exists && key.aggregation == metering.AggregationMax {
+		quantity = max(current, quantity)
+	} else {
+		quantity += current
+	}
+
+	e.pending[key] = quantity
+}
+
+// Start starts flushing the usage and emitting the billing events of closed windows.
+func (e *Emitter) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+
+	e.wg.Go(func() {
+		e.loop(ctx)
+	})
+
+	logger.Infof("Usage metering started with %v windows", e.window)
+}
+
+// Stop stops the emitter and flushes the buffered usage.
+func (e *Emitter) Stop(ctx context.Context) error {
+	if e.cancel == nil {
+		return nil
+	}
+
+	e.cancel()
+	e.wg.Wait()
+
+	e.flush(ctx)
+
+	return nil
+}
+
+func (e *Emitter) loop(ctx context.Context) {
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		e.run(ctx)
+	}
+}
+
+// run flushes the buffered usage, closes the ended windows and relays their billing events.
+func (e *Emitter) run(ctx context.Context) {
+	e.flush(ctx)
+
+	for {
+		closed, err := e.closeWindows(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Errorf("Failed to close metering windows: %v", err)
+			}
+
+			break
+		}
+
+		if closed < e.batchSize {
+			break
+		}
+	}
+
+	for {
+		published, err := e.relay(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Errorf("Failed to relay billing events: %v", err)
+			}
+
+			return
+		}
+
+		if published < e.batchSize {
+			return
+		}
+	}
+}
+
+// flush stores the buffered usage, usage failing to be stored is buffered again for the next flush.
+func (e *Emitter) flush(ctx context.Context) {
+	e.mu.Lock()
+	pending := e.pending
+	e.pending = make(map[usageKey]int64)
+	e.mu.Unlock()
+
+	for key, quantity := range pending {
+		if err := e.store(ctx, key, quantity); err != nil {
+			logger.Errorf("Failed to store usage of %s for meter %s: %v", key.subject, key.meter, err)
+			e.record(key, quantity)
+		}
+	}
+}
+
+// store adds the quantity to the usage row of the window, inserting the row on the first flush.
+// Usage of a window closed meanwhile is billed with the current window instead.
+func (e *Emitter) store(ctx context.Context, key usageKey, quantity int64) error {
+	for range 2 {
+		stored, err := e.update(ctx, key, quantity)
+		if err != nil || stored {
+			return err
+		}
+
+		// The insert conflicts when another instance inserted the row meanwhile or the window was closed.
+		if err = e.insert(ctx, key, quantity); !errors.Is(err, result.ErrRecordAlreadyExists) {
+			return err
+		}
+	}
+
+	late := key
+	late.windowStart = e.now().Truncate(e.window).Unix()
+
+	if late == key {
+		return fmt.Errorf("%w: %s", ErrWindowClosed, time.Unix(key.windowStart, 0).Format(time.DateTime))
+	}
+
+	logger.Warnf("Window %s of %s for meter %s was closed before its usage was flushed, billing it with the current window",
+		time.Unix(key.windowStart, 0).Format(time.DateTime), key.subject, key.meter)
+	e.record(late, quantity)
+
+	return nil
+}
+
+func (e *Emitter) update(ctx context.Context, key usageKey, quantity int64) (bool, error) {
+	res, err := e.db.NewUpdate().
+		Model((*metering.Usage)(nil)).
+		SetExpr("quantity", func(eb orm.ExprBuilder) any {
+			if key.aggregation == metering.AggregationMax {
+				return eb.Greatest(eb.Column("quantity"), quantity)
+			}
+
+			return eb.Add(eb.Column("quantity"), quantity)
+		}).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.Equals("subject", key.subject).
+				Equals("meter", key.meter).
+				Equals("window_start", datetime.Of(time.Unix(key.windowStart, 0))).
+				IsNull("emitted_at")
+		}).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+
+	return affected > 0, err
+}
+
+func (e *Emitter) insert(ctx context.Context, key usageKey, quantity int64) error {
+	windowStart := time.Unix(key.windowStart, 0)
+
+	_, err := e.db.NewInsert().
+		Model(&metering.Usage{
+			Subject:     key.subject,
+			Meter:       key.meter,
+			WindowStart: datetime.Of(windowStart),
+			WindowEnd:   datetime.Of(windowStart.Add(e.window)),
+			Aggregation: key.aggregation,
+			Quantity:    quantity,
+		}).
+		Exec(ctx)
+
+	return err
+}
+
+// closeWindows writes the billing records of up to one batch of ended windows and marks their usage emitted.
+// The usage rows are locked so that no flush adds to them while they are closed; SQLite serializes writers anyway.
+func (e *Emitter) closeWindows(ctx context.Context) (int, error) {
+	var usages []*metering.Usage
+
+	err := e.db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+		query := tx.NewSelect().
+			Model(&usages).
+			Where(func(cb orm.ConditionBuilder) {
+				cb.IsNull("emitted_at").
+					LessThanOrEqual("window_end", datetime.Of(e.now().Add(-e.grace)))
+			}).
+			OrderBy("window_start").
+			Limit(e.batchSize)
+
+		query.ExprBuilder().ExecByDialect(orm.DialectExecs{
+			SQLite:  func() {},
+			Default: func() { query.ForUpdateSkipLocked() },
+		})
+
+		if err := query.Scan(ctx); err != nil || len(usages) == 0 {
+			return err
+		}
+
+		records := make([]*metering.BillingRecord, len(usages))
+		ids := make([]string, len(usages))
+
+		for i, usage := range usages {
+			ids[i] = usage.ID
+			records[i] = &metering.BillingRecord{
+				IdempotencyKey: metering.IdempotencyKey(usage.Subject, usage.Meter, usage.WindowStart.Unwrap()),
+				Subject:        usage.Subject,
+				Meter:          usage.Meter,
+				Aggregation:    usage.Aggregation,
+				Quantity:       usage.Quantity,
+				WindowStart:    usage.WindowStart,
+				WindowEnd:      usage.WindowEnd,
+			}
+		}
+
+		if _, err := tx.NewUpsert().
+			Model(&records).
+			ConflictColumns("idempotency_key").
+			DoNothing().
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		_, err := tx.NewUpdate().
+			Model((*metering.Usage)(nil)).
+			Set("emitted_at", datetime.Of(e.now())).
+			Where(func(cb orm.ConditionBuilder) {
+				cb.PKIn(ids)
+			}).
+			Exec(ctx)
+
+		return err
+	})
+
+	return len(usages), err
+}
+
+// relay publishes up to one batch of unpublished billing records and marks them published.
+// Events are published before the transaction commits, so a failed commit publishes them again.
+func (e *Emitter) relay(ctx context.Context) (int, error) {
+	var records []*metering.BillingRecord
+
+	err := e.db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+		query := tx.NewSelect().
+			Model(&records).
+			Where(func(cb orm.ConditionBuilder) {
+				cb.IsNull("published_at")
+			}).
+			OrderBy("window_start").
+			Limit(e.batchSize)
+
+		query.ExprBuilder().ExecByDialect(orm.DialectExecs{
+			SQLite:  func() {},
+			Default: func() { query.ForUpdateSkipLocked() },
+		})
+
+		if err := query.Scan(ctx); err != nil || len(records) == 0 {
+			return err
+		}
+
+		ids := make([]string, len(records))
+		for i, record := range records {
+			ids[i] = record.ID
+		}
+
+		if _, err := tx.NewUpdate().
+			Model((*metering.BillingRecord)(nil)).
+			Set("published_at", datetime.Of(e.now())).
+			Where(func(cb orm.ConditionBuilder) {
+				cb.PKIn(ids)
+			}).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			e.publisher.Publish(metering.NewBillingEvent(record))
+		}
+
+		return nil
+	})
+
+	return len(records), err
+}
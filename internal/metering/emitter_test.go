@@ -0,0 +1,206 @@
+package metering
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/metering"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []*metering.BillingEvent
+}
+
+func (p *recordingPublisher) Publish(evt event.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, evt.(*metering.BillingEvent))
+}
+
+// EmitterTestSuite tests aggregating usage into windows and emitting billing events on SQLite.
+type EmitterTestSuite struct {
+	suite.Suite
+
+	ctx   context.Context
+	bunDB *bun.DB
+	db    orm.DB
+
+	publisher *recordingPublisher
+	emitter   *Emitter
+	now       time.Time
+}
+
+func (s *EmitterTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	var err error
+
+	s.bunDB, err = database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	for _, model := range []any{(*metering.Usage)(nil), (*metering.BillingRecord)(nil)} {
+		_, err = s.bunDB.NewCreateTable().Model(model).Exec(s.ctx)
+		s.Require().NoError(err)
+	}
+
+	s.db = iorm.New(s.bunDB)
+}
+
+func (s *EmitterTestSuite) TearDownSuite() {
+	if s.bunDB != nil {
+		s.Require().NoError(s.bunDB.Close())
+	}
+}
+
+func (s *EmitterTestSuite) SetupTest() {
+	for _, model := range []any{(*metering.Usage)(nil), (*metering.BillingRecord)(nil)} {
+		_, err := s.bunDB.NewDelete().Model(model).Where("1 = 1").Exec(s.ctx)
+		s.Require().NoError(err)
+	}
+
+	s.publisher = new(recordingPublisher)
+	s.now = time.Date(2025, 3, 1, 10, 15, 0, 0, time.Local)
+	s.emitter = s.newEmitter()
+}
+
+func (s *EmitterTestSuite) newEmitter() *Emitter {
+	emitter := NewEmitter(&config.MeteringConfig{Enabled: true, Window: time.Hour, Grace: time.Minute}, s.db, s.publisher)
+	emitter.now = func() time.Time { return s.now }
+
+	return emitter
+}
+
+func (s *EmitterTestSuite) usages() []metering.Usage {
+	var usages []metering.Usage
+
+	s.Require().NoError(s.db.NewSelect().Model(&usages).OrderBy("subject", "meter", "window_start").Scan(s.ctx))
+
+	return usages
+}
+
+func (s *EmitterTestSuite) TestAggregatesWindows() {
+	s.emitter.Add(s.ctx, "acme", metering.MeterAPICalls, 1)
+	s.emitter.Add(s.ctx, "acme", metering.MeterAPICalls, 2)
+	s.emitter.Observe(s.ctx, "acme", metering.MeterStorageBytes, 500)
+	s.emitter.Observe(s.ctx, "acme", metering.MeterStorageBytes, 300)
+	s.emitter.flush(s.ctx)
+
+	s.emitter.Add(s.ctx, "acme", metering.MeterAPICalls, 4)
+	s.emitter.Observe(s.ctx, "acme", metering.MeterStorageBytes, 400)
+	s.emitter.flush(s.ctx)
+
+	s.now = s.now.Add(time.Hour)
+	s.emitter.Add(s.ctx, "acme", metering.MeterAPICalls, 10)
+	s.emitter.flush(s.ctx)
+
+	usages := s.usages()
+	s.Require().Len(usages, 3)
+	s.Equal(int64(7), usages[0].Quantity, "Should sum counted usage within the window")
+	s.Equal(int64(10), usages[1].Quantity, "Should start a new window")
+	s.Equal(int64(500), usages[2].Quantity, "Should keep the peak of gauged usage")
+	s.Equal(metering.AggregationMax, usages[2].Aggregation)
+	s.True(usages[0].WindowStart.Unwrap().Equal(time.Date(2025, 3, 1, 10, 0, 0, 0, time.Local)), "Should align windows")
+	s.True(usages[0].WindowEnd.Unwrap().Equal(time.Date(2025, 3, 1, 11, 0, 0, 0, time.Local)))
+}
+
+func (s *EmitterTestSuite) TestEmitsClosedWindows() {
+	s.emitter.Add(s.ctx, "acme", metering.MeterAPICalls, 3)
+	s.emitter.Add(s.ctx, "globex", metering.MeterRows, 100)
+
+	s.now = s.now.Add(45 * time.Minute)
+	s.emitter.run(s.ctx)
+	s.Empty(s.publisher.events, "Should not close windows within the grace period")
+
+	s.now = s.now.Add(time.Minute)
+	s.emitter.run(s.ctx)
+	s.Require().Len(s.publisher.events, 2)
+
+	evt := s.publisher.events[0]
+	if evt.Subject != "acme" {
+		evt = s.publisher.events[1]
+	}
+
+	s.Equal(metering.EventTypeBilling, evt.Type())
+	s.Equal(int64(3), evt.Quantity)
+	s.Equal(metering.IdempotencyKey("acme", metering.MeterAPICalls, evt.WindowStart), evt.IdempotencyKey)
+
+	s.emitter.run(s.ctx)
+	s.Len(s.publisher.events, 2, "Should publish each billing record once")
+
+	for _, usage := range s.usages() {
+		s.True(usage.EmittedAt.Valid, "Should mark closed windows emitted")
+	}
+}
+
+func (s *EmitterTestSuite) TestIdempotentAcrossInstances() {
+	other := s.newEmitter()
+
+	s.emitter.Add(s.ctx, "acme", metering.MeterAPICalls, 1)
+	other.Add(s.ctx, "acme", metering.MeterAPICalls, 2)
+	s.emitter.flush(s.ctx)
+	other.flush(s.ctx)
+
+	s.now = s.now.Add(time.Hour)
+
+	_, err := s.emitter.closeWindows(s.ctx)
+	s.Require().NoError(err)
+
+	// Another instance closing the same window must not bill it twice.
+	_, err = s.db.NewUpdate().
+		Model((*metering.Usage)(nil)).
+		Set("emitted_at", null.DateTime{}).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.Equals("subject", "acme")
+		}).
+		Exec(s.ctx)
+	s.Require().NoError(err)
+
+	_, err = other.closeWindows(s.ctx)
+	s.Require().NoError(err)
+
+	count, err := s.db.NewSelect().Model((*metering.BillingRecord)(nil)).Count(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(1), count, "Should write one billing record per window")
+
+	s.emitter.run(s.ctx)
+	s.Require().Len(s.publisher.events, 1)
+	s.Equal(int64(3), s.publisher.events[0].Quantity, "Should bill the usage of all instances")
+}
+
+func (s *EmitterTestSuite) TestLateUsageBilledWithCurrentWindow() {
+	s.emitter.Add(s.ctx, "acme", metering.MeterAPICalls, 1)
+	s.emitter.flush(s.ctx)
+
+	s.emitter.Add(s.ctx, "acme", metering.MeterAPICalls, 2)
+
+	s.now = s.now.Add(time.Hour)
+	_, err := s.emitter.closeWindows(s.ctx)
+	s.Require().NoError(err)
+
+	s.emitter.flush(s.ctx)
+	s.emitter.flush(s.ctx)
+
+	usages := s.usages()
+	s.Require().Len(usages, 2)
+	s.Equal(int64(1), usages[0].Quantity)
+	s.Equal(int64(2), usages[1].Quantity, "Should move usage of closed windows to the current window")
+	s.False(usages[1].EmittedAt.Valid)
+}
+
+func TestEmitter(t *testing.T) {
+	suite.Run(t, new(EmitterTestSuite))
+}
@@ -0,0 +1,41 @@
+package metering
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"github.com/ilxqx/vef-framework-go/metering"
+)
+
+// Module provides the usage metering emitter.
+var Module = fx.Module(
+	"vef:metering",
+	fx.Provide(
+		fx.Annotate(
+			NewEmitter,
+			fx.OnStart(func(emitter *Emitter) {
+				if emitter != nil {
+					emitter.Start()
+				}
+			}),
+			fx.OnStop(func(ctx context.Context, emitter *Emitter) error {
+				if emitter == nil {
+					return nil
+				}
+
+				return emitter.Stop(ctx)
+			}),
+		),
+		newRecorder,
+	),
+)
+
+// newRecorder exposes the emitter through the public interface, nil when metering is disabled.
+func newRecorder(emitter *Emitter) metering.Recorder {
+	if emitter == nil {
+		return nil
+	}
+
+	return emitter
+}
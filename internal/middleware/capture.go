@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/encoding"
+	"github.com/ilxqx/vef-framework-go/id"
+	"github.com/ilxqx/vef-framework-go/internal/app"
+	"github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/middleware"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+const (
+	defaultCaptureSampleRate  = 0.01
+	defaultCaptureMaxBodySize = 64 * 1024
+	captureUploadTimeout      = 10 * time.Second
+)
+
+var captureLogger = log.Named("capture")
+
+// captureMiddleware records sampled request/response pairs into the object storage.
+type captureMiddleware struct {
+	service      storage.Service
+	sampleRate   float64
+	paths        []string
+	maxBodySize  int
+	redactFields []string
+}
+
+// NewCaptureMiddleware creates the traffic capture middleware, returning nil when capturing is disabled.
+// Sensitive headers and fields are redacted before a record leaves the process.
+func NewCaptureMiddleware(cfg *config.CaptureConfig, service storage.Service) app.Middleware {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	m := &captureMiddleware{
+		service:      service,
+		sampleRate:   cfg.SampleRate,
+		paths:        cfg.Paths,
+		maxBodySize:  cfg.MaxBodySize,
+		redactFields: cfg.RedactFields,
+	}
+
+	if m.sampleRate <= 0 {
+		m.sampleRate = defaultCaptureSampleRate
+	}
+
+	if len(m.paths) == 0 {
+		m.paths = []string{"/api"}
+	}
+
+	if m.maxBodySize <= 0 {
+		m.maxBodySize = defaultCaptureMaxBodySize
+	}
+
+	captureLogger.Infof("Capturing %.2f%% of requests to %v", m.sampleRate*100, m.paths)
+
+	return &SimpleMiddleware{
+		handler: m.handle,
+		name:    "capture",
+		order:   -450,
+	}
+}
+
+func (m *captureMiddleware) handle(ctx fiber.Ctx) error {
+	if !m.matches(ctx.Path()) || rand.Float64() >= m.sampleRate {
+		return ctx.Next()
+	}
+
+	start := time.Now()
+
+	// The response is completed here so that error responses are captured too.
+	if err := ctx.Next(); err != nil {
+		if handlerErr := ctx.App().ErrorHandler(ctx, err); handlerErr != nil {
+			_ = ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+
+	record := m.buildRecord(ctx, start)
+	go m.upload(record)
+
+	return nil
+}
+
+func (m *captureMiddleware) matches(path string) bool {
+	for _, prefix := range m.paths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// buildRecord copies everything it needs, the record outlives the request.
+func (m *captureMiddleware) buildRecord(ctx fiber.Ctx, start time.Time) *middleware.CaptureRecord {
+	request := ctx.Request()
+	response := ctx.Response()
+
+	record := &middleware.CaptureRecord{
+		ID:         id.GenerateUUID(),
+		RequestID:  contextx.RequestID(ctx),
+		CapturedAt: start,
+		Latency:    time.Since(start).Milliseconds(),
+		Request: middleware.CapturedRequest{
+			Method:  ctx.Method(),
+			Path:    utils.CopyString(ctx.Path()),
+			Query:   middleware.RedactQuery(string(request.URI().QueryString()), m.redactFields...),
+			Headers: make(map[string]string),
+		},
+		Response: middleware.CapturedResponse{
+			Status:  response.StatusCode(),
+			Headers: make(map[string]string),
+		},
+	}
+
+	for key, value := range request.Header.All() {
+		record.Request.Headers[string(key)] = m.headerValue(string(key), value)
+	}
+
+	for key, value := range response.Header.All() {
+		record.Response.Headers[string(key)] = m.headerValue(string(key), value)
+	}
+
+	record.Request.Body, record.Request.BodyOmitted = m.body(
+		string(request.Header.ContentType()), request.Body(),
+	)
+	record.Response.Body, record.Response.BodyOmitted = m.body(
+		string(response.Header.ContentType()), response.Body(),
+	)
+
+	return record
+}
+
+func (*captureMiddleware) headerValue(name string, value []byte) string {
+	if middleware.IsRedactedHeader(name) {
+		return middleware.RedactedValue
+	}
+
+	return string(value)
+}
+
+// body returns the redacted body, or reports it omitted when it is too large or not textual.
+func (m *captureMiddleware) body(contentType string, body []byte) (string, bool) {
+	if len(body) == 0 {
+		return "", false
+	}
+
+	if len(body) > m.maxBodySize {
+		return "", true
+	}
+
+	mimeType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+
+	switch mimeType = strings.TrimSpace(mimeType); {
+	case strings.HasSuffix(mimeType, "json"):
+		return middleware.RedactJSON(string(body), m.redactFields...), false
+	case mimeType == fiber.MIMEApplicationForm:
+		return middleware.RedactQuery(string(body), m.redactFields...), false
+	case strings.HasPrefix(mimeType, "text/"), strings.HasSuffix(mimeType, "xml"):
+		return string(bytes.Clone(body)), false
+	default:
+		return "", true
+	}
+}
+
+func (m *captureMiddleware) upload(record *middleware.CaptureRecord) {
+	data, err := encoding.ToJSON(record)
+	if err != nil {
+		captureLogger.Errorf("Failed to encode capture record %s: %v", record.ID, err)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), captureUploadTimeout)
+	defer cancel()
+
+	if _, err := m.service.PutObject(ctx, storage.PutObjectOptions{
+		Key:         middleware.CaptureKey(record),
+		Reader:      strings.NewReader(data),
+		Size:        int64(len(data)),
+		ContentType: fiber.MIMEApplicationJSON,
+	}); err != nil {
+		captureLogger.Errorf("Failed to store capture record %s: %v", record.ID, err)
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/internal/app"
+	"github.com/ilxqx/vef-framework-go/internal/database/ctxaudit"
+)
+
+// NewContextAuditMiddleware marks request contexts and handling goroutines so that queries executed
+// without the request context can be flagged. It is a pass-through unless the context audit is enabled.
+func NewContextAuditMiddleware(cfg *config.DatasourceConfig) app.Middleware {
+	return &SimpleMiddleware{
+		handler: func(ctx fiber.Ctx) error {
+			if !cfg.EnableContextAudit {
+				return ctx.Next()
+			}
+
+			done := ctxaudit.MarkRequest(ctx)
+			defer done()
+
+			return ctx.Next()
+		},
+		name:  "context_audit",
+		order: -550,
+	}
+}
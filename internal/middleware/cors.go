@@ -1,6 +1,10 @@
 package middleware
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/cors"
 
@@ -9,37 +13,135 @@ import (
 	"github.com/ilxqx/vef-framework-go/internal/app"
 )
 
-func NewCorsMiddleware(config *config.CorsConfig) app.Middleware {
-	handler := cors.New(cors.Config{
-		Next: func(_ fiber.Ctx) bool {
-			return !config.Enabled
-		},
-		AllowOrigins: config.AllowOrigins,
-		AllowMethods: []string{
-			fiber.MethodHead,
-			fiber.MethodGet,
-			fiber.MethodPost,
-			fiber.MethodPut,
-			fiber.MethodDelete,
-		},
-		AllowHeaders: []string{
-			fiber.HeaderContentType,
-			fiber.HeaderAuthorization,
-			fiber.HeaderXRequestedWith,
-			fiber.HeaderXRequestID,
-			constants.HeaderXAppID,
-			constants.HeaderXTimestamp,
-			constants.HeaderXNonce,
-			constants.HeaderXSignature,
-		},
-		AllowCredentials: false,
-		ExposeHeaders:    []string{},
-		MaxAge:           7200,
-	})
+var (
+	defaultCorsMethods = []string{
+		fiber.MethodHead,
+		fiber.MethodGet,
+		fiber.MethodPost,
+		fiber.MethodPut,
+		fiber.MethodDelete,
+	}
+	defaultCorsHeaders = []string{
+		fiber.HeaderContentType,
+		fiber.HeaderAuthorization,
+		fiber.HeaderXRequestedWith,
+		fiber.HeaderXRequestID,
+		constants.HeaderXAppID,
+		constants.HeaderXTimestamp,
+		constants.HeaderXNonce,
+		constants.HeaderXSignature,
+	}
+)
+
+const defaultCorsMaxAge = 7200
+
+// corsRoute is the CORS handler of the paths under a prefix.
+type corsRoute struct {
+	prefix  string
+	handler fiber.Handler
+}
+
+// NewCorsMiddleware applies the CORS policy of the first route matching the request path, the default policy otherwise.
+// Returns nil when CORS is disabled.
+func NewCorsMiddleware(config *config.CorsConfig) (app.Middleware, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	defaultPolicy := withCorsDefaults(config.CorsPolicy, config.CorsPolicy)
+
+	defaultHandler, err := newCorsHandler(defaultPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := make([]corsRoute, 0, len(config.Routes))
+	for _, route := range config.Routes {
+		handler, err := newCorsHandler(withCorsDefaults(route.CorsPolicy, defaultPolicy))
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", route.Path, err)
+		}
+
+		routes = append(routes, corsRoute{
+			prefix:  route.Path,
+			handler: handler,
+		})
+	}
+
+	handler := func(ctx fiber.Ctx) error {
+		path := ctx.Path()
+		for _, route := range routes {
+			if matchesPrefix(path, route.prefix) {
+				return route.handler(ctx)
+			}
+		}
+
+		return defaultHandler(ctx)
+	}
 
 	return &SimpleMiddleware{
 		handler: handler,
 		name:    "cors",
 		order:   -800,
+	}, nil
+}
+
+// withCorsDefaults fills the lists and max age left empty in a policy from the defaults.
+func withCorsDefaults(policy, defaults config.CorsPolicy) config.CorsPolicy {
+	if len(policy.AllowOrigins) == 0 {
+		policy.AllowOrigins = defaults.AllowOrigins
+	}
+
+	if len(policy.AllowMethods) == 0 {
+		policy.AllowMethods = defaults.AllowMethods
+	}
+
+	if len(policy.AllowMethods) == 0 {
+		policy.AllowMethods = defaultCorsMethods
+	}
+
+	if len(policy.AllowHeaders) == 0 {
+		policy.AllowHeaders = defaults.AllowHeaders
+	}
+
+	if len(policy.AllowHeaders) == 0 {
+		policy.AllowHeaders = defaultCorsHeaders
+	}
+
+	if len(policy.ExposeHeaders) == 0 {
+		policy.ExposeHeaders = defaults.ExposeHeaders
+	}
+
+	if policy.MaxAge == 0 {
+		policy.MaxAge = defaults.MaxAge
 	}
+
+	if policy.MaxAge == 0 {
+		policy.MaxAge = defaultCorsMaxAge
+	}
+
+	return policy
+}
+
+func newCorsHandler(policy config.CorsPolicy) (fiber.Handler, error) {
+	// No origins admit any origin
+	if policy.AllowCredentials && (len(policy.AllowOrigins) == 0 || slices.Contains(policy.AllowOrigins, constants.Asterisk)) {
+		return nil, ErrCorsCredentialsWildcard
+	}
+
+	return cors.New(cors.Config{
+		AllowOrigins:     policy.AllowOrigins,
+		AllowMethods:     policy.AllowMethods,
+		AllowHeaders:     policy.AllowHeaders,
+		ExposeHeaders:    policy.ExposeHeaders,
+		AllowCredentials: policy.AllowCredentials,
+		MaxAge:           policy.MaxAge,
+	}), nil
+}
+
+// matchesPrefix reports whether the path is the prefix or lies under it.
+func matchesPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, constants.Slash)
+
+	return path == prefix || strings.HasPrefix(path, prefix+constants.Slash) || prefix == constants.Empty
 }
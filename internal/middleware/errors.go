@@ -0,0 +1,10 @@
+package middleware
+
+import "errors"
+
+var (
+	// ErrCorsCredentialsWildcard indicates a CORS policy admits credentials from any origin.
+	ErrCorsCredentialsWildcard = errors.New("cors policy allowing credentials requires explicit origins")
+	// ErrUnknownHeadersProfile indicates the security headers profile is not known.
+	ErrUnknownHeadersProfile = errors.New("unknown security headers profile")
+)
@@ -1,32 +1,120 @@
 package middleware
 
 import (
+	"fmt"
+
 	"github.com/gofiber/fiber/v3"
 
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
 	"github.com/ilxqx/vef-framework-go/internal/app"
 )
 
-// NewHeadersMiddleware sets headers after handler execution to avoid being overwritten by application code.
-func NewHeadersMiddleware() app.Middleware {
+const headerCrossOriginOpenerPolicy = "Cross-Origin-Opener-Policy"
+
+// securityHeaders holds the values of the security headers, empty for omitted headers.
+type securityHeaders struct {
+	contentTypeOptions      string
+	contentSecurityPolicy   string
+	strictTransportSecurity string
+	frameOptions            string
+	referrerPolicy          string
+	permissionsPolicy       string
+	crossOriginOpenerPolicy string
+	cacheControl            string
+}
+
+var headersProfiles = map[string]securityHeaders{
+	config.HeadersProfileBasic: {
+		contentTypeOptions:      "nosniff",
+		strictTransportSecurity: "max-age=31536000; includeSubDomains",
+		cacheControl:            "no-store, no-cache, must-revalidate, max-age=0",
+	},
+	config.HeadersProfileStrict: {
+		contentTypeOptions:      "nosniff",
+		contentSecurityPolicy:   "default-src 'self'; object-src 'none'; base-uri 'self'; frame-ancestors 'none'",
+		strictTransportSecurity: "max-age=63072000; includeSubDomains; preload",
+		frameOptions:            "DENY",
+		referrerPolicy:          "no-referrer",
+		permissionsPolicy:       "camera=(), microphone=(), geolocation=()",
+		crossOriginOpenerPolicy: "same-origin",
+		cacheControl:            "no-store, no-cache, must-revalidate, max-age=0",
+	},
+	config.HeadersProfileNone: {},
+}
+
+// NewHeadersMiddleware sets the security headers of the configured profile after handler execution
+// to avoid being overwritten by application code.
+func NewHeadersMiddleware(config *config.HeadersConfig) (app.Middleware, error) {
+	headers, err := resolveSecurityHeaders(config)
+	if err != nil {
+		return nil, err
+	}
+
 	return &SimpleMiddleware{
 		handler: func(ctx fiber.Ctx) error {
 			if err := ctx.Next(); err != nil {
 				return err
 			}
 
-			ctx.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+			setHeader(ctx, fiber.HeaderXContentTypeOptions, headers.contentTypeOptions)
+			setHeader(ctx, fiber.HeaderContentSecurityPolicy, headers.contentSecurityPolicy)
+			setHeader(ctx, fiber.HeaderXFrameOptions, headers.frameOptions)
+			setHeader(ctx, fiber.HeaderReferrerPolicy, headers.referrerPolicy)
+			setHeader(ctx, fiber.HeaderPermissionsPolicy, headers.permissionsPolicy)
+			setHeader(ctx, headerCrossOriginOpenerPolicy, headers.crossOriginOpenerPolicy)
 
-			if ctx.Protocol() == "https" {
-				ctx.Set(fiber.HeaderStrictTransportSecurity, "max-age=31536000; includeSubDomains")
+			if ctx.Scheme() == "https" {
+				setHeader(ctx, fiber.HeaderStrictTransportSecurity, headers.strictTransportSecurity)
 			}
 
 			if len(ctx.Response().Header.Peek(fiber.HeaderCacheControl)) == 0 {
-				ctx.Set(fiber.HeaderCacheControl, "no-store, no-cache, must-revalidate, max-age=0")
+				setHeader(ctx, fiber.HeaderCacheControl, headers.cacheControl)
 			}
 
 			return nil
 		},
 		name:  "headers",
 		order: -900,
+	}, nil
+}
+
+// resolveSecurityHeaders applies the headers configured explicitly over the values of the profile.
+func resolveSecurityHeaders(cfg *config.HeadersConfig) (securityHeaders, error) {
+	profile := cfg.Profile
+	if profile == constants.Empty {
+		profile = config.HeadersProfileBasic
+	}
+
+	headers, ok := headersProfiles[profile]
+	if !ok {
+		return securityHeaders{}, fmt.Errorf("%w: %s", ErrUnknownHeadersProfile, profile)
+	}
+
+	override := func(value *string, configured string) {
+		switch configured {
+		case constants.Empty:
+		case config.HeaderValueOff:
+			*value = constants.Empty
+		default:
+			*value = configured
+		}
+	}
+
+	override(&headers.contentSecurityPolicy, cfg.ContentSecurityPolicy)
+	override(&headers.strictTransportSecurity, cfg.StrictTransportSecurity)
+	override(&headers.frameOptions, cfg.FrameOptions)
+	override(&headers.referrerPolicy, cfg.ReferrerPolicy)
+	override(&headers.permissionsPolicy, cfg.PermissionsPolicy)
+	override(&headers.crossOriginOpenerPolicy, cfg.CrossOriginOpenerPolicy)
+	override(&headers.cacheControl, cfg.CacheControl)
+
+	return headers, nil
+}
+
+// setHeader sets a response header unless its value is empty.
+func setHeader(ctx fiber.Ctx, key, value string) {
+	if value != constants.Empty {
+		ctx.Set(key, value)
 	}
 }
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/internal/app"
+	isecurity "github.com/ilxqx/vef-framework-go/internal/security"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/security"
+	"github.com/ilxqx/vef-framework-go/webhelpers"
+)
+
+// NewIPFilterMiddleware rejects requests from addresses blocked by the global IP filter.
+// Returns nil when IP filtering is disabled.
+func NewIPFilterMiddleware(filter *isecurity.IPFilter) app.Middleware {
+	if filter == nil {
+		return nil
+	}
+
+	handler := func(ctx fiber.Ctx) error {
+		ip := webhelpers.GetClientIP(ctx, filter.IsTrustedProxy)
+
+		reason := filter.Check(ctx.Context(), ip)
+		if reason == constants.Empty {
+			return ctx.Next()
+		}
+
+		filter.Blocked(security.IPBlockedEventParams{
+			IP:        ip,
+			Reason:    reason,
+			Scope:     security.IPFilterScopeGlobal,
+			Method:    ctx.Method(),
+			Path:      utils.CopyString(ctx.Path()),
+			RequestID: contextx.RequestID(ctx),
+			UserAgent: utils.CopyString(ctx.Get(fiber.HeaderUserAgent)),
+		})
+
+		return result.ErrIPNotAllowed
+	}
+
+	return &SimpleMiddleware{
+		handler: handler,
+		name:    "ip_filter",
+		order:   -400,
+	}
+}
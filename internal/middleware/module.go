@@ -13,6 +13,10 @@ var Module = fx.Module(
 			NewLoggerMiddleware,
 			fx.ResultTags(`group:"vef:app:middlewares"`),
 		),
+		fx.Annotate(
+			NewContextAuditMiddleware,
+			fx.ResultTags(`group:"vef:app:middlewares"`),
+		),
 		fx.Annotate(
 			NewRecoveryMiddleware,
 			fx.ResultTags(`group:"vef:app:middlewares"`),
@@ -22,6 +26,15 @@ var Module = fx.Module(
 			fx.ParamTags(`group:"vef:spa"`),
 			fx.ResultTags(`group:"vef:app:middlewares"`),
 		),
+		fx.Annotate(
+			NewCaptureMiddleware,
+			fx.ResultTags(`group:"vef:app:middlewares"`),
+		),
+		fx.Annotate(
+			NewIPFilterMiddleware,
+			fx.ParamTags(`optional:"true"`),
+			fx.ResultTags(`group:"vef:app:middlewares"`),
+		),
 		fx.Annotate(
 			NewCorsMiddleware,
 			fx.ResultTags(`group:"vef:app:middlewares"`),
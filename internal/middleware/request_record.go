@@ -9,11 +9,13 @@ import (
 	"github.com/muesli/termenv"
 	"github.com/spf13/cast"
 
+	"github.com/ilxqx/vef-framework-go/config"
 	"github.com/ilxqx/vef-framework-go/constants"
 	"github.com/ilxqx/vef-framework-go/contextx"
 	"github.com/ilxqx/vef-framework-go/internal/app"
 	"github.com/ilxqx/vef-framework-go/middleware"
 	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/security"
 	"github.com/ilxqx/vef-framework-go/webhelpers"
 )
 
@@ -135,9 +137,9 @@ func formatStatus(status int) string {
 	return output.String(cast.ToString(status)).Foreground(color).String()
 }
 
-func formatRequestDetails(ctx fiber.Ctx, data *logger.Data) string {
+func formatRequestDetails(ctx fiber.Ctx, data *logger.Data, trustedProxies *security.TrustedProxies) string {
 	method, reqPath := ctx.Method(), ctx.Path()
-	ip, latency, status := webhelpers.GetIP(ctx), data.Stop.Sub(data.Start), ctx.Response().StatusCode()
+	ip, latency, status := webhelpers.GetClientIP(ctx, trustedProxies.Contains), data.Stop.Sub(data.Start), ctx.Response().StatusCode()
 	ua := simplifyUserAgent(ctx.Get(fiber.HeaderUserAgent))
 	ms := latency.Milliseconds()
 
@@ -173,8 +175,8 @@ func formatRequestDetails(ctx fiber.Ctx, data *logger.Data) string {
 	return sb.String()
 }
 
-func logRequest(ctx fiber.Ctx, data *logger.Data) {
-	details := formatRequestDetails(ctx, data)
+func logRequest(ctx fiber.Ctx, data *logger.Data, trustedProxies *security.TrustedProxies) {
+	details := formatRequestDetails(ctx, data, trustedProxies)
 	logger := contextx.Logger(ctx)
 
 	if data.ChainErr == nil {
@@ -208,13 +210,15 @@ func logRequest(ctx fiber.Ctx, data *logger.Data) {
 }
 
 // NewRequestRecordMiddleware skips SPA static assets to reduce log noise while capturing API traffic.
-func NewRequestRecordMiddleware(spaConfigs []*middleware.SPAConfig) app.Middleware {
+// Client addresses are resolved through the trusted proxies of the IP filter configuration.
+func NewRequestRecordMiddleware(spaConfigs []*middleware.SPAConfig, securityConfig *config.SecurityConfig) app.Middleware {
+	trustedProxies := security.NewTrustedProxies(securityConfig.IPFilter.TrustedProxies)
 	handler := logger.New(logger.Config{
 		Next: func(ctx fiber.Ctx) bool {
 			return isSpaStaticRequest(ctx, spaConfigs)
 		},
 		LoggerFunc: func(ctx fiber.Ctx, data *logger.Data, _ *logger.Config) error {
-			logRequest(ctx, data)
+			logRequest(ctx, data, trustedProxies)
 
 			return nil
 		},
@@ -13,15 +13,28 @@ const (
 	// DefaultSampleDuration is the default sampling window duration for CPU and process metrics.
 	// A 2-second window smooths short-term fluctuations while providing responsive metrics.
 	DefaultSampleDuration = 2 * time.Second
+	// DefaultLockSampleInterval is the default interval between lock wait samples.
+	// Lock waits are short-lived, so they are sampled more often than system metrics.
+	DefaultLockSampleInterval = 5 * time.Second
+	// DefaultLockContentionWindow is the default period over which lock waits are aggregated.
+	DefaultLockContentionWindow = 10 * time.Minute
+	// DefaultLockContentionThreshold is the default number of lock waits within the window that flags contention.
+	DefaultLockContentionThreshold = 3
 )
 
 // DefaultConfig returns the default monitor configuration.
 // This configuration provides reasonable defaults for most use cases:
 // - 10 second sampling interval (20% duty cycle with 2s window)
-// - 2 second sampling window (smooths CPU spikes).
+// - 2 second sampling window (smooths CPU spikes)
+// - lock contention detection disabled, sampling every 5 seconds over a 10 minute window when enabled.
 func DefaultConfig() config.MonitorConfig {
 	return config.MonitorConfig{
 		SampleInterval: DefaultSampleInterval,
 		SampleDuration: DefaultSampleDuration,
+		LockContention: config.LockContentionConfig{
+			SampleInterval: DefaultLockSampleInterval,
+			Window:         DefaultLockContentionWindow,
+			Threshold:      DefaultLockContentionThreshold,
+		},
 	}
 }
@@ -0,0 +1,37 @@
+package monitor
+
+import (
+	"context"
+
+	"github.com/ilxqx/vef-framework-go/monitor"
+)
+
+// healthSeverity orders the health statuses from best to worst.
+var healthSeverity = map[monitor.HealthStatus]int{
+	monitor.HealthUp:       0,
+	monitor.HealthDegraded: 1,
+	monitor.HealthDown:     2,
+}
+
+// checkHealth collects the health of the components, the application is as healthy as its least healthy component.
+func checkHealth(ctx context.Context, contributors []monitor.HealthContributor) *monitor.HealthReport {
+	report := &monitor.HealthReport{
+		Status:     monitor.HealthUp,
+		Components: make(map[string]*monitor.ComponentHealth, len(contributors)),
+	}
+
+	for _, contributor := range contributors {
+		if contributor == nil {
+			continue
+		}
+
+		health := contributor.Health(ctx)
+		report.Components[contributor.Name()] = health
+
+		if healthSeverity[health.Status] > healthSeverity[report.Status] {
+			report.Status = health.Status
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,294 @@
+package monitor
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/monitor"
+)
+
+// maxTrackedWaits bounds the number of lock wait occurrences kept in memory; the oldest are evicted first.
+const maxTrackedWaits = 10000
+
+var (
+	// stringLiteralRegex matches single quoted SQL string literals including escaped quotes.
+	stringLiteralRegex = regexp.MustCompile(`'(?:[^']|'')*'`)
+	// numberLiteralRegex matches numeric SQL literals.
+	numberLiteralRegex = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	// whitespaceRegex matches consecutive whitespace characters.
+	whitespaceRegex = regexp.MustCompile(`\s+`)
+)
+
+// waitOccurrence is a single lock wait tracked across samples until it ends.
+type waitOccurrence struct {
+	query    string
+	table    string
+	locator  string
+	waitMs   int64
+	lastSeen time.Time
+}
+
+// DefaultLockContentionTracker implements monitor.LockContentionTracker by periodically sampling
+// the wait-event views of the database and aggregating the waits per statement and per row.
+type DefaultLockContentionTracker struct {
+	config *config.LockContentionConfig
+	source lockWaitSource
+	now    func() time.Time
+
+	mu          sync.Mutex
+	occurrences map[string]*waitOccurrence
+	sampledAt   time.Time
+
+	samplerCancel context.CancelFunc
+	samplerDone   chan struct{}
+}
+
+// NewLockContentionTracker creates a lock contention tracker for the database.
+// Sampling is only started when enabled in the configuration and supported by the dialect (PostgreSQL, MySQL).
+func NewLockContentionTracker(cfg *config.MonitorConfig, db *bun.DB) monitor.LockContentionTracker {
+	return newLockContentionTracker(&cfg.LockContention, newLockWaitSource(db))
+}
+
+func newLockContentionTracker(cfg *config.LockContentionConfig, source lockWaitSource) *DefaultLockContentionTracker {
+	return &DefaultLockContentionTracker{
+		config:      cfg,
+		source:      source,
+		now:         time.Now,
+		occurrences: make(map[string]*waitOccurrence),
+	}
+}
+
+// Init starts the background lock wait sampler.
+func (t *DefaultLockContentionTracker) Init(context.Context) error {
+	if !t.config.Enabled {
+		return nil
+	}
+
+	if t.source == nil {
+		logger.Warn("Lock contention detection is not supported by the current database dialect")
+
+		return nil
+	}
+
+	samplerCtx, cancel := context.WithCancel(context.Background())
+	t.samplerCancel = cancel
+	t.samplerDone = make(chan struct{})
+
+	go t.runSampler(samplerCtx)
+
+	return nil
+}
+
+func (t *DefaultLockContentionTracker) runSampler(ctx context.Context) {
+	defer close(t.samplerDone)
+
+	ticker := time.NewTicker(t.config.SampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.sample(ctx)
+		}
+	}
+}
+
+// Close gracefully stops the background lock wait sampler.
+func (t *DefaultLockContentionTracker) Close() error {
+	if t.samplerCancel != nil {
+		t.samplerCancel()
+	}
+
+	if t.samplerDone != nil {
+		<-t.samplerDone
+	}
+
+	return nil
+}
+
+func (t *DefaultLockContentionTracker) sample(ctx context.Context) {
+	waits, err := t.source.Sample(ctx)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return
+		}
+
+		logger.Errorf("Failed to sample lock waits: %v", err)
+
+		return
+	}
+
+	t.record(waits)
+}
+
+// record merges sampled waits into the tracked occurrences.
+// A wait spanning several samples is counted once with its longest observed duration.
+func (t *DefaultLockContentionTracker) record(waits []lockWait) {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, wait := range waits {
+		key := wait.SessionID + constants.Pipe + wait.WaitID
+
+		occurrence, ok := t.occurrences[key]
+		if !ok {
+			occurrence = &waitOccurrence{
+				query:   normalizeStatement(wait.Query),
+				table:   wait.Table,
+				locator: wait.Locator,
+			}
+			t.occurrences[key] = occurrence
+		}
+
+		occurrence.waitMs = max(occurrence.waitMs, int64(wait.WaitMs))
+		occurrence.lastSeen = now
+
+		if !ok {
+			t.flagIfHot(occurrence)
+		}
+	}
+
+	t.sampledAt = now
+	t.prune(now)
+}
+
+// flagIfHot logs a warning when a new wait makes its statement reach the contention threshold.
+func (t *DefaultLockContentionTracker) flagIfHot(occurrence *waitOccurrence) {
+	count := 0
+	for _, other := range t.occurrences {
+		if other.query == occurrence.query {
+			count++
+		}
+	}
+
+	if count == t.config.Threshold {
+		logger.Warnf("Detected repeated lock contention (%d waits) on %s: %s", count, occurrence.table, occurrence.query)
+	}
+}
+
+// prune drops occurrences outside the window and evicts the oldest ones beyond maxTrackedWaits.
+func (t *DefaultLockContentionTracker) prune(now time.Time) {
+	cutoff := now.Add(-t.config.Window)
+	for key, occurrence := range t.occurrences {
+		if occurrence.lastSeen.Before(cutoff) {
+			delete(t.occurrences, key)
+		}
+	}
+
+	if len(t.occurrences) <= maxTrackedWaits {
+		return
+	}
+
+	keys := make([]string, 0, len(t.occurrences))
+	for key := range t.occurrences {
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, func(a, b string) int {
+		return t.occurrences[a].lastSeen.Compare(t.occurrences[b].lastSeen)
+	})
+
+	for _, key := range keys[:len(keys)-maxTrackedWaits] {
+		delete(t.occurrences, key)
+	}
+}
+
+// LockContention returns the lock waits aggregated over the window, hottest first.
+func (t *DefaultLockContentionTracker) LockContention(context.Context) (*monitor.LockContentionReport, error) {
+	report := &monitor.LockContentionReport{
+		Enabled:    t.config.Enabled,
+		Supported:  t.source != nil,
+		WindowMs:   t.config.Window.Milliseconds(),
+		Threshold:  t.config.Threshold,
+		Statements: []monitor.StatementContention{},
+		Rows:       []monitor.RowContention{},
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.sampledAt.IsZero() {
+		report.SampledAt = t.sampledAt.UnixMilli()
+	}
+
+	statements := make(map[string]*monitor.StatementContention)
+	rows := make(map[string]*monitor.RowContention)
+
+	for _, occurrence := range t.occurrences {
+		lastSeen := occurrence.lastSeen.UnixMilli()
+
+		statement, ok := statements[occurrence.query]
+		if !ok {
+			statement = &monitor.StatementContention{Query: occurrence.query}
+			statements[occurrence.query] = statement
+		}
+
+		statement.WaitCount++
+		statement.TotalWaitMs += occurrence.waitMs
+		statement.MaxWaitMs = max(statement.MaxWaitMs, occurrence.waitMs)
+		statement.LastSeen = max(statement.LastSeen, lastSeen)
+
+		if occurrence.table == constants.Empty && occurrence.locator == constants.Empty {
+			continue
+		}
+
+		rowKey := occurrence.table + constants.Pipe + occurrence.locator
+
+		row, ok := rows[rowKey]
+		if !ok {
+			row = &monitor.RowContention{Table: occurrence.table, Locator: occurrence.locator}
+			rows[rowKey] = row
+		}
+
+		row.WaitCount++
+		row.TotalWaitMs += occurrence.waitMs
+		row.MaxWaitMs = max(row.MaxWaitMs, occurrence.waitMs)
+		row.LastSeen = max(row.LastSeen, lastSeen)
+	}
+
+	for _, statement := range statements {
+		statement.Hot = statement.WaitCount >= t.config.Threshold
+		report.Statements = append(report.Statements, *statement)
+	}
+
+	for _, row := range rows {
+		row.Hot = row.WaitCount >= t.config.Threshold
+		report.Rows = append(report.Rows, *row)
+	}
+
+	slices.SortFunc(report.Statements, func(a, b monitor.StatementContention) int {
+		return cmp.Or(cmp.Compare(b.WaitCount, a.WaitCount), cmp.Compare(b.TotalWaitMs, a.TotalWaitMs), strings.Compare(a.Query, b.Query))
+	})
+	slices.SortFunc(report.Rows, func(a, b monitor.RowContention) int {
+		return cmp.Or(
+			cmp.Compare(b.WaitCount, a.WaitCount),
+			cmp.Compare(b.TotalWaitMs, a.TotalWaitMs),
+			strings.Compare(a.Table, b.Table),
+			strings.Compare(a.Locator, b.Locator),
+		)
+	})
+
+	return report, nil
+}
+
+// normalizeStatement replaces literals with placeholders so that executions of the same statement aggregate together.
+func normalizeStatement(query string) string {
+	query = stringLiteralRegex.ReplaceAllString(query, constants.QuestionMark)
+	query = numberLiteralRegex.ReplaceAllString(query, constants.QuestionMark)
+
+	return strings.TrimSpace(whitespaceRegex.ReplaceAllString(query, constants.Space))
+}
@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+)
+
+type LockContentionTrackerTestSuite struct {
+	suite.Suite
+
+	ctx     context.Context
+	now     time.Time
+	tracker *DefaultLockContentionTracker
+}
+
+func (suite *LockContentionTrackerTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.now = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	suite.tracker = newLockContentionTracker(&config.LockContentionConfig{
+		Enabled:   true,
+		Window:    time.Minute,
+		Threshold: 2,
+	}, nil)
+	suite.tracker.now = func() time.Time { return suite.now }
+}
+
+func (suite *LockContentionTrackerTestSuite) advance(d time.Duration) {
+	suite.now = suite.now.Add(d)
+}
+
+func (suite *LockContentionTrackerTestSuite) TestAggregatesWaitsPerStatementAndRow() {
+	suite.tracker.record([]lockWait{
+		{SessionID: "1", WaitID: "a", Query: "UPDATE stock SET qty = qty - 1 WHERE id = 'sku-1'", WaitMs: 120, Table: "stock", Locator: "tuple (0,1)"},
+		{SessionID: "2", WaitID: "b", Query: "SELECT * FROM orders WHERE id = 7", WaitMs: 30, Table: "orders", Locator: "tuple (3,4)"},
+	})
+
+	suite.advance(5 * time.Second)
+	// Session 1 is still waiting on the same lock, session 3 runs the same statement for another SKU on the same row
+	suite.tracker.record([]lockWait{
+		{SessionID: "1", WaitID: "a", Query: "UPDATE stock SET qty = qty - 1 WHERE id = 'sku-1'", WaitMs: 5120, Table: "stock", Locator: "tuple (0,1)"},
+		{SessionID: "3", WaitID: "c", Query: "UPDATE  stock SET qty = qty - 1\n WHERE id = 'sku-2'", WaitMs: 80, Table: "stock", Locator: "tuple (0,1)"},
+	})
+
+	report, err := suite.tracker.LockContention(suite.ctx)
+	suite.Require().NoError(err)
+
+	suite.True(report.Enabled)
+	suite.False(report.Supported, "Tracker without source should not be supported")
+	suite.Equal(suite.now.UnixMilli(), report.SampledAt)
+
+	suite.Require().Len(report.Statements, 2)
+
+	hottest := report.Statements[0]
+	suite.Equal("UPDATE stock SET qty = qty - ? WHERE id = ?", hottest.Query, "Literals and whitespace should be normalized")
+	suite.Equal(2, hottest.WaitCount, "A wait spanning samples should count once")
+	suite.Equal(int64(5200), hottest.TotalWaitMs)
+	suite.Equal(int64(5120), hottest.MaxWaitMs)
+	suite.True(hottest.Hot, "Statement reaching the threshold should be flagged")
+	suite.False(report.Statements[1].Hot)
+
+	suite.Require().Len(report.Rows, 2)
+	suite.Equal("stock", report.Rows[0].Table)
+	suite.Equal("tuple (0,1)", report.Rows[0].Locator)
+	suite.Equal(2, report.Rows[0].WaitCount)
+	suite.True(report.Rows[0].Hot, "Row reaching the threshold should be flagged")
+}
+
+func (suite *LockContentionTrackerTestSuite) TestPrunesWaitsOutsideWindow() {
+	suite.tracker.record([]lockWait{
+		{SessionID: "1", WaitID: "a", Query: "UPDATE stock SET qty = 0", WaitMs: 100},
+	})
+
+	suite.advance(2 * time.Minute)
+	suite.tracker.record([]lockWait{
+		{SessionID: "2", WaitID: "b", Query: "DELETE FROM stock", WaitMs: 100},
+	})
+
+	report, err := suite.tracker.LockContention(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(report.Statements, 1, "Waits outside the window should be dropped")
+	suite.Equal("DELETE FROM stock", report.Statements[0].Query)
+	suite.Empty(report.Rows, "Waits without lock target should not be reported as rows")
+}
+
+func (suite *LockContentionTrackerTestSuite) TestInitWithoutSource() {
+	suite.Require().NoError(suite.tracker.Init(suite.ctx), "Unsupported dialect should not fail initialization")
+	suite.Nil(suite.tracker.samplerDone, "Sampler should not be started without source")
+	suite.Require().NoError(suite.tracker.Close())
+}
+
+func TestLockContentionTrackerSuite(t *testing.T) {
+	suite.Run(t, new(LockContentionTrackerTestSuite))
+}
@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// lockWait is a session currently waiting for a lock, as reported by the database wait-event views.
+type lockWait struct {
+	// SessionID identifies the waiting backend or connection.
+	SessionID string `bun:"session_id"`
+	// WaitID distinguishes successive waits of the same session, e.g. the wait start time.
+	WaitID string `bun:"wait_id"`
+	// Query is the statement that is waiting.
+	Query string `bun:"query"`
+	// WaitMs is how long the statement has been waiting so far.
+	WaitMs float64 `bun:"wait_ms"`
+	// Table is the table the requested lock belongs to, empty when unknown.
+	Table string `bun:"table_name"`
+	// Locator identifies the locked row within the table, e.g. the tuple position or the key values.
+	Locator string `bun:"locator"`
+}
+
+// lockWaitSource samples the lock waits of a database dialect.
+type lockWaitSource interface {
+	Sample(ctx context.Context) ([]lockWait, error)
+}
+
+// newLockWaitSource returns the lock wait source of the database dialect, or nil when the dialect exposes no wait-event views.
+func newLockWaitSource(db *bun.DB) lockWaitSource {
+	switch db.Dialect().Name() {
+	case dialect.PG:
+		return &sqlLockWaitSource{db: db, query: postgresLockWaitsQuery}
+	case dialect.MySQL:
+		return &sqlLockWaitSource{db: db, query: mysqlLockWaitsQuery}
+	default:
+		return nil
+	}
+}
+
+const (
+	// postgresLockWaitsQuery reads ungranted locks of the current database (waitstart requires PostgreSQL 14+).
+	// Row lock waits show up either as tuple locks or as waits on the transaction holding the row.
+	postgresLockWaitsQuery = `SELECT a.pid::text AS session_id,
+       COALESCE(l.waitstart, a.query_start)::text AS wait_id,
+       a.query AS query,
+       EXTRACT(EPOCH FROM (now() - COALESCE(l.waitstart, a.query_start))) * 1000 AS wait_ms,
+       COALESCE(l.relation::regclass::text, '') AS table_name,
+       CASE l.locktype
+           WHEN 'tuple' THEN 'tuple (' || l.page || ',' || l.tuple || ')'
+           WHEN 'transactionid' THEN 'transaction ' || l.transactionid
+           ELSE l.locktype
+       END AS locator
+FROM pg_stat_activity a
+JOIN pg_locks l ON l.pid = a.pid AND NOT l.granted
+WHERE a.wait_event_type = 'Lock' AND a.datname = current_database()`
+
+	// mysqlLockWaitsQuery reads InnoDB lock waits including the key values of the locked record (MySQL 8.0+).
+	mysqlLockWaitsQuery = `SELECT CAST(w.waiting_pid AS CHAR) AS session_id,
+       CAST(w.wait_started AS CHAR) AS wait_id,
+       w.waiting_query AS query,
+       w.wait_age_secs * 1000 AS wait_ms,
+       w.locked_table AS table_name,
+       CONCAT_WS(' ', w.locked_index, dl.LOCK_DATA) AS locator
+FROM sys.innodb_lock_waits w
+LEFT JOIN performance_schema.data_locks dl ON dl.ENGINE_LOCK_ID = w.waiting_lock_id`
+)
+
+// sqlLockWaitSource samples lock waits with a dialect specific query.
+type sqlLockWaitSource struct {
+	db    *bun.DB
+	query string
+}
+
+func (s *sqlLockWaitSource) Sample(ctx context.Context) ([]lockWait, error) {
+	var waits []lockWait
+	if err := s.db.NewRaw(s.query).Scan(ctx, &waits); err != nil {
+		return nil, err
+	}
+
+	return waits, nil
+}
@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/app"
+	"github.com/ilxqx/vef-framework-go/monitor"
+)
+
+const (
+	// defaultMetricsPath is the default path serving the Prometheus metrics.
+	defaultMetricsPath = "/metrics"
+	// metricsContentType is the content type of the Prometheus text exposition format.
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// poolMetric describes a metric of the connection pool in the Prometheus exposition format.
+type poolMetric struct {
+	name  string
+	kind  string
+	help  string
+	value func(stats *monitor.ConnectionPoolStats) float64
+}
+
+var poolMetrics = []poolMetric{
+	{
+		name: "vef_db_pool_max_open_connections", kind: "gauge", help: "Maximum number of open connections to the database, 0 for unlimited.",
+		value: func(stats *monitor.ConnectionPoolStats) float64 { return float64(stats.MaxOpenConnections) },
+	},
+	{
+		name: "vef_db_pool_open_connections", kind: "gauge", help: "Number of established connections, in use and idle.",
+		value: func(stats *monitor.ConnectionPoolStats) float64 { return float64(stats.OpenConnections) },
+	},
+	{
+		name: "vef_db_pool_in_use_connections", kind: "gauge", help: "Number of connections currently in use.",
+		value: func(stats *monitor.ConnectionPoolStats) float64 { return float64(stats.InUse) },
+	},
+	{
+		name: "vef_db_pool_idle_connections", kind: "gauge", help: "Number of idle connections.",
+		value: func(stats *monitor.ConnectionPoolStats) float64 { return float64(stats.Idle) },
+	},
+	{
+		name: "vef_db_pool_wait_count_total", kind: "counter", help: "Total number of connections waited for.",
+		value: func(stats *monitor.ConnectionPoolStats) float64 { return float64(stats.WaitCount) },
+	},
+	{
+		name: "vef_db_pool_wait_duration_seconds_total", kind: "counter", help: "Total time blocked waiting for a new connection.",
+		value: func(stats *monitor.ConnectionPoolStats) float64 { return float64(stats.WaitDurationMs) / 1000 },
+	},
+	{
+		name: "vef_db_pool_max_idle_closed_total", kind: "counter", help: "Total number of connections closed due to the maximum idle connections.",
+		value: func(stats *monitor.ConnectionPoolStats) float64 { return float64(stats.MaxIdleClosed) },
+	},
+	{
+		name: "vef_db_pool_max_idle_time_closed_total", kind: "counter", help: "Total number of connections closed due to the maximum idle time.",
+		value: func(stats *monitor.ConnectionPoolStats) float64 { return float64(stats.MaxIdleTimeClosed) },
+	},
+	{
+		name: "vef_db_pool_max_lifetime_closed_total", kind: "counter", help: "Total number of connections closed due to the maximum lifetime.",
+		value: func(stats *monitor.ConnectionPoolStats) float64 { return float64(stats.MaxLifetimeClosed) },
+	},
+}
+
+// MetricsMiddleware serves the sampled connection pool statistics as Prometheus metrics.
+type MetricsMiddleware struct {
+	path    string
+	monitor monitor.ConnectionPoolMonitor
+}
+
+// NewMetricsMiddleware creates the metrics middleware, nil when pool metrics are disabled.
+func NewMetricsMiddleware(cfg *config.DatasourceConfig, poolMonitor monitor.ConnectionPoolMonitor) app.Middleware {
+	if !cfg.PoolMetrics.Enabled {
+		return nil
+	}
+
+	path := cfg.PoolMetrics.Path
+	if path == constants.Empty {
+		path = defaultMetricsPath
+	}
+
+	return &MetricsMiddleware{path: path, monitor: poolMonitor}
+}
+
+func (*MetricsMiddleware) Name() string {
+	return "pool_metrics"
+}
+
+func (*MetricsMiddleware) Order() int {
+	return 600
+}
+
+func (m *MetricsMiddleware) Apply(router fiber.Router) {
+	router.Get(m.path, m.handleMetrics)
+	logger.Infof("Connection pool metrics endpoint registered at GET %s", m.path)
+}
+
+func (m *MetricsMiddleware) handleMetrics(ctx fiber.Ctx) error {
+	stats, err := m.monitor.ConnectionPoolStats(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, metricsContentType)
+
+	return ctx.Send(renderPoolMetrics(stats))
+}
+
+// renderPoolMetrics renders the statistics in the Prometheus text exposition format.
+func renderPoolMetrics(stats *monitor.ConnectionPoolStats) []byte {
+	var buf bytes.Buffer
+	for _, metric := range poolMetrics {
+		_, _ = fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", metric.name, metric.help, metric.name, metric.kind, metric.name, metric.value(stats))
+	}
+
+	return buf.Bytes()
+}
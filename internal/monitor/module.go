@@ -32,6 +32,17 @@ var Module = fx.Module(
 			cfgToUse.SampleDuration = cfg.SampleDuration
 		}
 
+		cfgToUse.LockContention.Enabled = cfg.LockContention.Enabled
+		if cfg.LockContention.SampleInterval > 0 {
+			cfgToUse.LockContention.SampleInterval = cfg.LockContention.SampleInterval
+		}
+		if cfg.LockContention.Window > 0 {
+			cfgToUse.LockContention.Window = cfg.LockContention.Window
+		}
+		if cfg.LockContention.Threshold > 0 {
+			cfgToUse.LockContention.Threshold = cfg.LockContention.Threshold
+		}
+
 		return &cfgToUse
 	}),
 	fx.Decorate(
@@ -75,10 +86,38 @@ var Module = fx.Module(
 				return nil
 			}),
 		),
+		// Provide lock contention tracker with lifecycle management
+		fx.Annotate(
+			NewLockContentionTracker,
+			fx.OnStart(func(ctx context.Context, tracker monitor.LockContentionTracker) error {
+				if initializer, ok := tracker.(contract.Initializer); ok {
+					if err := initializer.Init(ctx); err != nil {
+						return fmt.Errorf("failed to initialize lock contention tracker: %w", err)
+					}
+				}
+
+				return nil
+			}),
+			fx.OnStop(func(tracker monitor.LockContentionTracker) error {
+				if closer, ok := tracker.(io.Closer); ok {
+					if err := closer.Close(); err != nil {
+						return fmt.Errorf("failed to close lock contention tracker: %w", err)
+					}
+				}
+
+				return nil
+			}),
+		),
 		// Provide monitor resource
 		fx.Annotate(
 			NewResource,
+			fx.ParamTags(``, ``, ``, ``, `group:"vef:monitor:health_contributors"`),
 			fx.ResultTags(`group:"vef:api:resources"`),
 		),
+		// Provide Prometheus metrics of the connection pool
+		fx.Annotate(
+			NewMetricsMiddleware,
+			fx.ResultTags(`group:"vef:app:middlewares"`),
+		),
 	),
 )
@@ -12,10 +12,21 @@ import (
 // defaultRateLimit is the default rate limit configuration for monitor endpoints.
 var defaultRateLimit = &api.RateLimitConfig{Max: 60}
 
-// NewResource creates a new monitor resource with the provided service.
-func NewResource(service monitor.Service) api.Resource {
+// NewResource creates a new monitor resource with the provided service, lock contention tracker, connection leak detector,
+// connection pool monitor and health contributors.
+func NewResource(
+	service monitor.Service,
+	tracker monitor.LockContentionTracker,
+	leakDetector monitor.ConnectionLeakDetector,
+	poolMonitor monitor.ConnectionPoolMonitor,
+	healthContributors []monitor.HealthContributor,
+) api.Resource {
 	return &Resource{
-		service: service,
+		service:            service,
+		tracker:            tracker,
+		leakDetector:       leakDetector,
+		poolMonitor:        poolMonitor,
+		healthContributors: healthContributors,
 		Resource: api.NewRPCResource(
 			"sys/monitor",
 			api.WithOperations(
@@ -28,6 +39,10 @@ func NewResource(service monitor.Service) api.Resource {
 				api.OperationSpec{Action: "get_process", Public: isMonitorApiPublic, RateLimit: defaultRateLimit},
 				api.OperationSpec{Action: "get_load", Public: isMonitorApiPublic, RateLimit: defaultRateLimit},
 				api.OperationSpec{Action: "get_build_info", Public: isMonitorApiPublic, RateLimit: defaultRateLimit},
+				api.OperationSpec{Action: "get_lock_contention", Public: isMonitorApiPublic, RateLimit: defaultRateLimit},
+				api.OperationSpec{Action: "get_connection_holders", Public: isMonitorApiPublic, RateLimit: defaultRateLimit},
+				api.OperationSpec{Action: "get_connection_pool", Public: isMonitorApiPublic, RateLimit: defaultRateLimit},
+				api.OperationSpec{Action: "get_health", Public: isMonitorApiPublic, RateLimit: defaultRateLimit},
 			),
 		),
 	}
@@ -37,7 +52,11 @@ func NewResource(service monitor.Service) api.Resource {
 type Resource struct {
 	api.Resource
 
-	service monitor.Service
+	service            monitor.Service
+	tracker            monitor.LockContentionTracker
+	leakDetector       monitor.ConnectionLeakDetector
+	poolMonitor        monitor.ConnectionPoolMonitor
+	healthContributors []monitor.HealthContributor
 }
 
 // GetOverview returns a comprehensive system overview.
@@ -130,3 +149,40 @@ func (r *Resource) GetLoad(ctx fiber.Ctx) error {
 func (r *Resource) GetBuildInfo(ctx fiber.Ctx) error {
 	return result.Ok(r.service.BuildInfo()).Response(ctx)
 }
+
+// GetLockContention returns the lock waits aggregated per statement and row, flagging hot spots.
+func (r *Resource) GetLockContention(ctx fiber.Ctx) error {
+	report, err := r.tracker.LockContention(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	return result.Ok(report).Response(ctx)
+}
+
+// GetConnectionHolders returns the database connections currently held with the stack traces that acquired them.
+// Holders are only recorded when connection leak detection is enabled.
+func (r *Resource) GetConnectionHolders(ctx fiber.Ctx) error {
+	report, err := r.leakDetector.ConnectionHolders(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	return result.Ok(report).Response(ctx)
+}
+
+// GetConnectionPool returns the latest sample of the database connection pool statistics.
+// The pool is only sampled when pool metrics are enabled.
+func (r *Resource) GetConnectionPool(ctx fiber.Ctx) error {
+	stats, err := r.poolMonitor.ConnectionPoolStats(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	return result.Ok(stats).Response(ctx)
+}
+
+// GetHealth returns the health of the application and of each of its components.
+func (r *Resource) GetHealth(ctx fiber.Ctx) error {
+	return result.Ok(checkHealth(ctx.Context(), r.healthContributors)).Response(ctx)
+}
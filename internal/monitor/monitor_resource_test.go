@@ -51,7 +51,8 @@ func (suite *MonitorResourceTestSuite) SetupSuite() {
 		suite.T(),
 		fx.Replace(
 			&config.DatasourceConfig{
-				Type: "sqlite",
+				Type:        "sqlite",
+				PoolMetrics: config.PoolMetricsConfig{Enabled: true},
 			},
 			monitorConfig,
 		),
@@ -409,6 +410,127 @@ func (suite *MonitorResourceTestSuite) TestGetBuildInfo() {
 	})
 }
 
+func (suite *MonitorResourceTestSuite) TestGetLockContention() {
+	suite.T().Log("Testing get_lock_contention endpoint")
+
+	suite.Run("Success", func() {
+		resp := suite.makeAPIRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "sys/monitor",
+				Action:   "get_lock_contention",
+				Version:  "v1",
+			},
+		})
+
+		suite.Equal(200, resp.StatusCode, "Should return 200 OK")
+
+		body := suite.readBody(resp)
+		suite.True(body.IsOk(), "Lock contention request should succeed")
+
+		data := suite.readDataAsMap(body.Data)
+
+		suite.Equal(false, data["enabled"], "Lock contention detection should be disabled by default")
+		suite.Equal(false, data["supported"], "SQLite should not support lock contention detection")
+		suite.Empty(data["statements"], "Should have no contended statements")
+		suite.Empty(data["rows"], "Should have no contended rows")
+	})
+}
+
+func (suite *MonitorResourceTestSuite) TestGetConnectionHolders() {
+	suite.T().Log("Testing get_connection_holders endpoint")
+
+	suite.Run("Success", func() {
+		resp := suite.makeAPIRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "sys/monitor",
+				Action:   "get_connection_holders",
+				Version:  "v1",
+			},
+		})
+
+		suite.Equal(200, resp.StatusCode, "Should return 200 OK")
+
+		body := suite.readBody(resp)
+		suite.True(body.IsOk(), "Connection holders request should succeed")
+
+		data := suite.readDataAsMap(body.Data)
+
+		suite.Equal(false, data["enabled"], "Leak detection should be disabled by default")
+		suite.Contains(data, "holdThresholdMs", "Should have hold threshold")
+		suite.Empty(data["holders"], "Should have no recorded holders")
+	})
+}
+
+func (suite *MonitorResourceTestSuite) TestGetConnectionPool() {
+	suite.T().Log("Testing get_connection_pool endpoint")
+
+	suite.Run("Success", func() {
+		resp := suite.makeAPIRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "sys/monitor",
+				Action:   "get_connection_pool",
+				Version:  "v1",
+			},
+		})
+
+		suite.Equal(200, resp.StatusCode, "Should return 200 OK")
+
+		body := suite.readBody(resp)
+		suite.True(body.IsOk(), "Connection pool request should succeed")
+
+		data := suite.readDataAsMap(body.Data)
+
+		suite.Equal(true, data["enabled"], "Pool should be sampled")
+		suite.Positive(data["sampledAt"], "Should have taken a sample at startup")
+		suite.Contains(data, "inUse", "Should have connections in use")
+		suite.Contains(data, "waitCount", "Should have wait count")
+	})
+}
+
+func (suite *MonitorResourceTestSuite) TestGetHealth() {
+	suite.T().Log("Testing get_health endpoint")
+
+	suite.Run("Success", func() {
+		resp := suite.makeAPIRequest(api.Request{
+			Identifier: api.Identifier{
+				Resource: "sys/monitor",
+				Action:   "get_health",
+				Version:  "v1",
+			},
+		})
+
+		suite.Equal(200, resp.StatusCode, "Should return 200 OK")
+
+		body := suite.readBody(resp)
+		suite.True(body.IsOk(), "Health request should succeed")
+
+		data := suite.readDataAsMap(body.Data)
+		suite.Equal("up", data["status"], "Application should be healthy")
+
+		components := suite.readDataAsMap(data["components"])
+		database := suite.readDataAsMap(components["database"])
+		suite.Equal("up", database["status"], "Database should be healthy")
+		suite.Contains(suite.readDataAsMap(database["details"]), "inUse", "Should report the pool usage")
+	})
+}
+
+func (suite *MonitorResourceTestSuite) TestPoolMetrics() {
+	suite.T().Log("Testing Prometheus metrics endpoint")
+
+	resp, err := suite.app.Test(httptest.NewRequest(fiber.MethodGet, "/metrics", nil))
+	suite.Require().NoError(err, "Metrics request should not fail")
+
+	defer resp.Body.Close()
+
+	suite.Equal(200, resp.StatusCode, "Should return 200 OK")
+	suite.Contains(resp.Header.Get(fiber.HeaderContentType), "version=0.0.4", "Should use the Prometheus text format")
+
+	body, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err, "Should read response body")
+	suite.Contains(string(body), "# TYPE vef_db_pool_in_use_connections gauge\nvef_db_pool_in_use_connections ")
+	suite.Contains(string(body), "# TYPE vef_db_pool_wait_count_total counter\nvef_db_pool_wait_count_total 0\n")
+}
+
 func TestMonitorResourceSuite(t *testing.T) {
 	suite.Run(t, new(MonitorResourceTestSuite))
 }
@@ -0,0 +1,10 @@
+package orm
+
+import "github.com/ilxqx/vef-framework-go/internal/database/txcommit"
+
+var (
+	// AfterCommit runs a function once the transaction of the context commits, immediately outside a transaction.
+	AfterCommit = txcommit.AfterCommit
+	// InTransaction reports whether the context is the context of a transaction run by RunInTX and its variants.
+	InTransaction = txcommit.InTransaction
+)
@@ -1,6 +1,8 @@
 package orm
 
 import (
+	"unicode/utf8"
+
 	"github.com/samber/lo"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/schema"
@@ -87,6 +89,12 @@ type StringAggBuilder interface {
 	NullHandlingBuilder[StringAggBuilder]
 
 	Separator(separator string) StringAggBuilder
+	// MaxLength truncates the aggregated string to at most length characters, ending truncated strings with the
+	// overflow indicator. Oracle truncates through ON OVERFLOW TRUNCATE, other databases through SUBSTRING.
+	// On MySQL strings cut by group_concat_max_len are also marked with the indicator instead of truncating silently.
+	MaxLength(length int) StringAggBuilder
+	// OverflowIndicator sets the text ending truncated strings, "..." by default.
+	OverflowIndicator(indicator string) StringAggBuilder
 }
 
 // ArrayAggBuilder defines the ARRAY_AGG aggregate function builder.
@@ -126,6 +134,17 @@ type JSONArrayAggBuilder interface {
 	OrderableAggregate[JSONArrayAggBuilder]
 }
 
+// PercentileBuilder defines the PERCENTILE_CONT and PERCENTILE_DISC ordered-set aggregate function builder.
+// Column or Expr sets the value ordered WITHIN GROUP, Fraction the percentile to compute, 0.5 by default.
+type PercentileBuilder interface {
+	BaseAggregate[PercentileBuilder]
+
+	// Fraction sets the percentile to compute, between 0 and 1.
+	Fraction(fraction float64) PercentileBuilder
+	// Desc orders the values descending, e.g. for the top percentiles.
+	Desc() PercentileBuilder
+}
+
 // BitOrBuilder defines the BIT_OR aggregate function builder.
 type BitOrBuilder interface {
 	BaseAggregate[BitOrBuilder]
@@ -152,6 +171,8 @@ type dialectAggConfig struct {
 	clearDistinct   bool
 	clearOrderBy    bool
 	clearNullsMode  bool
+	// orderWithinGroup renders the ordering as WITHIN GROUP (ORDER BY ...) after the arguments.
+	orderWithinGroup bool
 }
 
 type dialectStrategy struct {
@@ -324,6 +345,32 @@ var stringAggStrategy = &dialectStrategy{
 		},
 		clearNullsMode: true,
 	},
+	// LISTAGG and STRING_AGG skip NULL values and take the ordering WITHIN GROUP.
+	oracle: &dialectAggConfig{
+		funcName: "LISTAGG",
+		argsTransformer: func(eb ExprBuilder, state *aggregateQueryState) schema.QueryAppender {
+			if state.overflowTruncate {
+				return eb.Expr("?, ? ON OVERFLOW TRUNCATE ? WITHOUT COUNT", state.argsExpr, state.separator, state.overflowIndicator)
+			}
+
+			return eb.Expr("?, ?", state.argsExpr, state.separator)
+		},
+		clearNullsMode:   true,
+		orderWithinGroup: true,
+	},
+	sqlsrv: &dialectAggConfig{
+		funcName: "STRING_AGG",
+		argsTransformer: func(eb ExprBuilder, state *aggregateQueryState) schema.QueryAppender {
+			return eb.Expr("?, ?", state.argsExpr, state.separator)
+		},
+		clearNullsMode:   true,
+		orderWithinGroup: true,
+	},
+}
+
+var percentileStrategy = &dialectStrategy{
+	postgres: &dialectAggConfig{orderWithinGroup: true},
+	oracle:   &dialectAggConfig{orderWithinGroup: true},
 }
 
 var stdDevStrategy = &dialectStrategy{
@@ -379,6 +426,10 @@ type aggregateQueryState struct {
 	nullsMode       NullsMode
 	separator       string
 	statisticalMode StatisticalMode
+	withinGroup     bool
+	// overflowTruncate truncates results exceeding the maximum length of the database instead of failing.
+	overflowTruncate  bool
+	overflowIndicator string
 }
 
 type baseAggregateExpr struct {
@@ -445,6 +496,8 @@ func (a *baseAggregateExpr) applyDialectConfig(state *aggregateQueryState, cfg *
 	if cfg.clearNullsMode {
 		state.nullsMode = NullsDefault
 	}
+
+	state.withinGroup = cfg.orderWithinGroup
 }
 
 func (a *baseAggregateExpr) dialectAwareAppendQuery(gen schema.QueryGen, b []byte) ([]byte, error) {
@@ -538,7 +591,7 @@ func (a *baseAggregateExpr) appendQueryWithState(gen schema.QueryGen, b []byte,
 		return
 	}
 
-	if len(state.orderExprs) > 0 {
+	if len(state.orderExprs) > 0 && !state.withinGroup {
 		b = append(b, constants.ByteSpace)
 		if b, err = newOrderByClause(state.orderExprs...).AppendQuery(gen, b); err != nil {
 			return
@@ -547,6 +600,15 @@ func (a *baseAggregateExpr) appendQueryWithState(gen schema.QueryGen, b []byte,
 
 	b = append(b, constants.ByteRightParenthesis)
 
+	if len(state.orderExprs) > 0 && state.withinGroup {
+		b = append(b, " WITHIN GROUP ("...)
+		if b, err = newOrderByClause(state.orderExprs...).AppendQuery(gen, b); err != nil {
+			return
+		}
+
+		b = append(b, constants.ByteRightParenthesis)
+	}
+
 	if state.nullsMode != NullsDefault {
 		b = append(b, constants.ByteSpace)
 		b = append(b, state.nullsMode.String()...)
@@ -729,7 +791,9 @@ type stringAggExpr[T any] struct {
 	*orderableAggregateBuilder[T]
 	*baseNullHandlingBuilder[T]
 
-	separator string
+	separator         string
+	maxLength         int
+	overflowIndicator string
 }
 
 func (s *stringAggExpr[T]) Separator(separator string) T {
@@ -738,9 +802,23 @@ func (s *stringAggExpr[T]) Separator(separator string) T {
 	return s.self
 }
 
+func (s *stringAggExpr[T]) MaxLength(length int) T {
+	s.maxLength = length
+
+	return s.self
+}
+
+func (s *stringAggExpr[T]) OverflowIndicator(indicator string) T {
+	s.overflowIndicator = indicator
+
+	return s.self
+}
+
 func (s *stringAggExpr[T]) AppendQuery(gen schema.QueryGen, b []byte) ([]byte, error) {
 	state := s.buildQueryState()
 	state.separator = s.separator
+	state.overflowTruncate = s.maxLength > 0
+	state.overflowIndicator = s.overflowIndicator
 
 	cfg := s.getDialectConfig()
 	if cfg == nil {
@@ -749,7 +827,38 @@ func (s *stringAggExpr[T]) AppendQuery(gen schema.QueryGen, b []byte) ([]byte, e
 
 	s.applyDialectConfig(&state, cfg)
 
-	return s.appendQueryWithState(gen, b, state)
+	if s.maxLength <= 0 {
+		return s.appendQueryWithState(gen, b, state)
+	}
+
+	agg := &stateAggregateExpr{baseAggregateExpr: s.baseAggregateExpr, state: state}
+	overflow := s.eb.Expr("? > ?", s.eb.CharLength(agg), s.maxLength)
+
+	// GROUP_CONCAT cuts its result at group_concat_max_len bytes without notice
+	s.eb.ExecByDialect(DialectExecs{
+		MySQL: func() {
+			overflow = s.eb.Expr("? OR LENGTH(?) >= @@group_concat_max_len", overflow, agg)
+		},
+	})
+
+	keep := max(s.maxLength-utf8.RuneCountInString(s.overflowIndicator), 0)
+
+	return s.eb.Case(func(cb CaseBuilder) {
+		cb.WhenExpr(overflow).
+			Then(s.eb.Concat(s.eb.SubString(agg, 1, keep), s.overflowIndicator)).
+			Else(agg)
+	}).AppendQuery(gen, b)
+}
+
+// stateAggregateExpr renders an aggregate with a prepared query state, to be wrapped into other expressions.
+type stateAggregateExpr struct {
+	*baseAggregateExpr
+
+	state aggregateQueryState
+}
+
+func (e *stateAggregateExpr) AppendQuery(gen schema.QueryGen, b []byte) ([]byte, error) {
+	return e.appendQueryWithState(gen, b, e.state)
 }
 
 type arrayAggExpr[T any] struct {
@@ -764,6 +873,68 @@ func (a *arrayAggExpr[T]) AppendQuery(gen schema.QueryGen, b []byte) ([]byte, er
 	return a.dialectAwareAppendQuery(gen, b)
 }
 
+type percentileExpr[T any] struct {
+	*baseAggregateExpr
+	*baseAggregateBuilder[T]
+
+	fraction float64
+	desc     bool
+}
+
+func (p *percentileExpr[T]) Fraction(fraction float64) T {
+	p.fraction = fraction
+
+	return p.self
+}
+
+func (p *percentileExpr[T]) Desc() T {
+	p.desc = true
+
+	return p.self
+}
+
+func (p *percentileExpr[T]) AppendQuery(gen schema.QueryGen, b []byte) ([]byte, error) {
+	if p.argsExpr == nil {
+		return nil, ErrAggregateMissingArgs
+	}
+
+	cfg := p.getDialectConfig()
+	if cfg == nil {
+		return nil, ErrDialectUnsupportedOperation
+	}
+
+	state := p.buildQueryState()
+	value := state.argsExpr
+
+	// Without FILTER support the filtered out values become NULL, which percentiles skip.
+	p.eb.ExecByDialect(DialectExecs{
+		Oracle: func() {
+			if state.filter != nil {
+				value = p.eb.Case(func(cb CaseBuilder) {
+					cb.WhenExpr(state.filter).Then(value)
+				})
+				state.filter = nil
+			}
+		},
+	})
+
+	direction := sortx.OrderAsc
+	if p.desc {
+		direction = sortx.OrderDesc
+	}
+
+	state.argsExpr = p.eb.Expr("?", p.fraction)
+	state.orderExprs = []orderExpr{{
+		builders:   p.eb,
+		expr:       value,
+		direction:  direction,
+		nullsOrder: sortx.NullsDefault,
+	}}
+	p.applyDialectConfig(&state, cfg)
+
+	return p.appendQueryWithState(gen, b, state)
+}
+
 type statisticalAggExpr struct {
 	*baseAggregateExpr
 
@@ -1103,7 +1274,8 @@ func newGenericStringAggExpr[T any](self T, qb QueryBuilder) *stringAggExpr[T] {
 		baseNullHandlingBuilder: &baseNullHandlingBuilder[T]{
 			baseAggregateBuilder: baseBuilder,
 		},
-		separator: constants.Comma,
+		separator:         constants.Comma,
+		overflowIndicator: defaultOverflowIndicator,
 	}
 
 	baseBuilder.self = self
@@ -1132,7 +1304,29 @@ func newStringAggExpr(qb QueryBuilder) *stringAggExpr[StringAggBuilder] {
 		baseNullHandlingBuilder: &baseNullHandlingBuilder[StringAggBuilder]{
 			baseAggregateBuilder: baseBuilder,
 		},
-		separator: constants.Comma,
+		separator:         constants.Comma,
+		overflowIndicator: defaultOverflowIndicator,
+	}
+
+	baseBuilder.self = expr
+
+	return expr
+}
+
+func newPercentileExpr(qb QueryBuilder, funcName string) *percentileExpr[PercentileBuilder] {
+	baseExpr := &baseAggregateExpr{
+		qb:       qb,
+		eb:       qb.ExprBuilder(),
+		funcName: funcName,
+		strategy: percentileStrategy,
+	}
+	baseBuilder := &baseAggregateBuilder[PercentileBuilder]{
+		baseAggregateExpr: baseExpr,
+	}
+	expr := &percentileExpr[PercentileBuilder]{
+		baseAggregateExpr:    baseExpr,
+		baseAggregateBuilder: baseBuilder,
+		fraction:             0.5,
 	}
 
 	baseBuilder.self = expr
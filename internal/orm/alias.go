@@ -0,0 +1,92 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+var (
+	// aliasMu guards aliasTables and modelAliases.
+	aliasMu sync.RWMutex
+	// aliasTables holds the name of the table each registered alias belongs to.
+	aliasTables = make(map[string]string)
+	// modelAliases holds the registered alias of each model type.
+	modelAliases = make(map[reflect.Type]string)
+)
+
+// DeriveAlias derives the alias of a table from the initials of the words of its name, e.g. "srr" for
+// sys_report_run, the convention of the models generated by vef-cli.
+func DeriveAlias(table string) string {
+	var sb strings.Builder
+
+	for word := range strings.SplitSeq(strings.ToLower(table), constants.Underscore) {
+		if word != constants.Empty {
+			_ = sb.WriteByte(word[0])
+		}
+	}
+
+	return sb.String()
+}
+
+// RegisterAliases registers the aliases of the given models, so conditions can reference them through MustAlias
+// instead of repeating alias literals. Models declaring no alias tag get the alias derived by DeriveAlias in place of
+// the model name bun defaults to. Registering an alias already taken by another table fails with ErrAliasCollision,
+// an explicit alias tag on either model resolves the collision. Call it at startup before building queries.
+func RegisterAliases(db DB, models ...any) error {
+	bunDB, ok := db.(*BunDB)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrDialectUnsupportedOperation, db)
+	}
+
+	aliasMu.Lock()
+	defer aliasMu.Unlock()
+
+	for _, model := range models {
+		table := db.TableOf(model)
+
+		alias := table.Alias
+		if alias == table.ModelName {
+			alias = DeriveAlias(table.Name)
+		}
+
+		if owner, ok := aliasTables[alias]; ok && owner != table.Name {
+			return fmt.Errorf("%w: alias %q of table %s is taken by table %s, declare an explicit alias tag",
+				ErrAliasCollision, alias, table.Name, owner)
+		}
+
+		if alias != table.Alias {
+			table.Alias = alias
+			table.SQLAlias = schema.Safe(bunDB.quoteIdent(alias))
+		}
+
+		aliasTables[alias] = table.Name
+		modelAliases[table.Type] = alias
+	}
+
+	return nil
+}
+
+// MustAlias returns the registered alias of the model, panicking if the model is not registered with
+// RegisterAliases. Referencing aliases through models keeps joins and conditions consistent with the registry.
+func MustAlias(model any) string {
+	modelType := reflect.TypeOf(model)
+	for modelType != nil && (modelType.Kind() == reflect.Pointer || modelType.Kind() == reflect.Slice) {
+		modelType = modelType.Elem()
+	}
+
+	aliasMu.RLock()
+	alias, ok := modelAliases[modelType]
+	aliasMu.RUnlock()
+
+	if !ok {
+		panic(fmt.Sprintf("orm: no alias registered for model %T, register it with RegisterAliases", model))
+	}
+
+	return alias
+}
@@ -0,0 +1,110 @@
+package orm
+
+import (
+	"github.com/uptrace/bun"
+)
+
+type AliasOrder struct {
+	bun.BaseModel `bun:"table:test_alias_order"`
+	Model
+
+	Code string `json:"code" bun:"code,notnull"`
+}
+
+type AliasOrderLine struct {
+	bun.BaseModel `bun:"table:test_alias_order_line,alias:tol"`
+	Model
+
+	OrderID string `json:"orderId" bun:"order_id,notnull"`
+	Product string `json:"product" bun:"product,notnull"`
+}
+
+type AliasAccount struct {
+	bun.BaseModel `bun:"table:test_alias_account"`
+	Model
+}
+
+type AliasAudit struct {
+	bun.BaseModel `bun:"table:test_alias_audit"`
+	Model
+}
+
+// AliasTestSuite tests deriving and registering table aliases.
+type AliasTestSuite struct {
+	*OrmTestSuite
+}
+
+func (suite *AliasTestSuite) SetupSuite() {
+	suite.Require().NoError(RegisterAliases(suite.db, (*AliasOrder)(nil), (*AliasOrderLine)(nil)))
+
+	bunDB := suite.getBunDB()
+
+	for _, model := range []any{(*AliasOrder)(nil), (*AliasOrderLine)(nil)} {
+		_, err := bunDB.NewDropTable().Model(model).IfExists().Exec(suite.ctx)
+		suite.Require().NoError(err, "Should drop existing alias test table")
+
+		_, err = bunDB.NewCreateTable().Model(model).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should create alias test table")
+	}
+}
+
+func (suite *AliasTestSuite) TearDownSuite() {
+	for _, model := range []any{(*AliasOrder)(nil), (*AliasOrderLine)(nil)} {
+		_, err := suite.getBunDB().NewDropTable().Model(model).IfExists().Exec(suite.ctx)
+		suite.NoError(err, "Should cleanup alias test table")
+	}
+}
+
+func (suite *AliasTestSuite) TestDeriveAlias() {
+	suite.Equal("srr", DeriveAlias("sys_report_run"))
+	suite.Equal("u", DeriveAlias("User"))
+	suite.Equal("ol", DeriveAlias("order__line"))
+}
+
+func (suite *AliasTestSuite) TestRegisterAliases() {
+	suite.Equal("tao", MustAlias((*AliasOrder)(nil)), "Should derive the alias of models without alias tag")
+	suite.Equal("tao", suite.db.TableOf((*AliasOrder)(nil)).Alias, "Should apply the derived alias to the table")
+	suite.Equal("tol", MustAlias(&[]AliasOrderLine{}), "Should keep explicit alias tags")
+
+	suite.NoError(RegisterAliases(suite.db, (*AliasOrder)(nil)), "Should allow registering a model again")
+}
+
+func (suite *AliasTestSuite) TestCollision() {
+	suite.Require().NoError(RegisterAliases(suite.db, (*AliasAccount)(nil)))
+
+	err := RegisterAliases(suite.db, (*AliasAudit)(nil))
+	suite.ErrorIs(err, ErrAliasCollision)
+	suite.ErrorContains(err, "test_alias_account", "Should name the table owning the alias")
+}
+
+func (suite *AliasTestSuite) TestMustAliasUnregistered() {
+	suite.PanicsWithValue(
+		"orm: no alias registered for model *orm.AliasAudit, register it with RegisterAliases",
+		func() { MustAlias((*AliasAudit)(nil)) },
+	)
+}
+
+func (suite *AliasTestSuite) TestQueryWithAliases() {
+	order := &AliasOrder{Code: "SO-1"}
+	_, err := suite.db.NewInsert().Model(order).Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.db.NewInsert().Model(&AliasOrderLine{OrderID: order.ID, Product: "Pen"}).Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	var codes []string
+
+	orderAlias, lineAlias := MustAlias((*AliasOrder)(nil)), MustAlias((*AliasOrderLine)(nil))
+	err = suite.db.NewSelect().
+		Model((*AliasOrder)(nil)).
+		Select(orderAlias+".code").
+		Join((*AliasOrderLine)(nil), func(cb ConditionBuilder) {
+			cb.EqualsColumn(lineAlias+".order_id", orderAlias+".id")
+		}).
+		Where(func(cb ConditionBuilder) {
+			cb.Equals(lineAlias+".product", "Pen")
+		}).
+		Scan(suite.ctx, &codes)
+	suite.Require().NoError(err, "Should build the query on the registered aliases")
+	suite.Equal([]string{"SO-1"}, codes)
+}
@@ -0,0 +1,197 @@
+package orm
+
+import (
+	"strings"
+
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+type ArrayItem struct {
+	bun.BaseModel `bun:"table:test_array_item,alias:tai"`
+
+	Name string `json:"name" bun:"name,pk"`
+}
+
+// ArrayTestSuite tests array conditions and expressions, on native arrays on Postgres and JSON arrays elsewhere.
+type ArrayTestSuite struct {
+	*OrmTestSuite
+}
+
+func (suite *ArrayTestSuite) SetupSuite() {
+	bunDB := suite.getBunDB()
+
+	_, err := bunDB.NewDropTable().Model((*ArrayItem)(nil)).IfExists().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should drop existing array table")
+
+	columnType := map[constants.DBType]string{
+		constants.Postgres: "TEXT[]",
+		constants.MySQL:    "JSON",
+		constants.SQLite:   "TEXT",
+	}[suite.dbType]
+	_, err = bunDB.ExecContext(suite.ctx, "CREATE TABLE test_array_item (name VARCHAR(32) PRIMARY KEY, tags "+columnType+")")
+	suite.Require().NoError(err, "Should create array table")
+
+	for name, tags := range map[string][]string{
+		"go":     {"backend", "compiled"},
+		"python": {"backend", "scripting"},
+		"js":     {"frontend", "scripting"},
+		"empty":  {},
+	} {
+		_, err := bunDB.ExecContext(suite.ctx, "INSERT INTO test_array_item (name, tags) VALUES (?, "+suite.arrayLiteral(tags)+")", name)
+		suite.Require().NoError(err, "Should insert array item")
+	}
+}
+
+func (suite *ArrayTestSuite) TearDownSuite() {
+	_, err := suite.getBunDB().NewDropTable().Model((*ArrayItem)(nil)).IfExists().Exec(suite.ctx)
+	suite.NoError(err, "Should cleanup array table")
+}
+
+// arrayLiteral returns the SQL literal of tags in the column type of the current database.
+func (suite *ArrayTestSuite) arrayLiteral(tags []string) string {
+	quoted := make([]string, len(tags))
+	for i, tag := range tags {
+		quoted[i] = `"` + tag + `"`
+	}
+
+	if suite.dbType == constants.Postgres {
+		return "'{" + strings.Join(quoted, ",") + "}'"
+	}
+
+	return "'[" + strings.Join(quoted, ",") + "]'"
+}
+
+func (suite *ArrayTestSuite) names(builder func(ConditionBuilder)) []string {
+	var items []ArrayItem
+
+	suite.Require().NoError(
+		suite.db.NewSelect().Model(&items).Where(builder).OrderBy("name").Scan(suite.ctx),
+		"Should query array items",
+	)
+
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+
+	return names
+}
+
+func (suite *ArrayTestSuite) TestConditions() {
+	suite.T().Logf("Testing array conditions for %s", suite.dbType)
+
+	suite.Run("ArrayContains", func() {
+		suite.Equal([]string{"go", "python"}, suite.names(func(cb ConditionBuilder) {
+			cb.ArrayContains("tags", []string{"backend"})
+		}))
+		suite.Equal([]string{"python"}, suite.names(func(cb ConditionBuilder) {
+			cb.ArrayContains("tags", []string{"backend", "scripting"})
+		}))
+	})
+
+	suite.Run("ArrayOverlaps", func() {
+		suite.Equal([]string{"go", "js"}, suite.names(func(cb ConditionBuilder) {
+			cb.ArrayOverlaps("tags", []string{"compiled", "frontend", "unknown"})
+		}))
+	})
+
+	suite.Run("ArrayLength", func() {
+		suite.Equal([]string{"empty"}, suite.names(func(cb ConditionBuilder) {
+			cb.ArrayLength("tags", 0)
+		}))
+	})
+
+	suite.Run("OrConditions", func() {
+		suite.Equal([]string{"empty", "js"}, suite.names(func(cb ConditionBuilder) {
+			cb.ArrayContains("tags", []string{"frontend"}).
+				OrArrayLength("tags", 0)
+		}))
+	})
+}
+
+func (suite *ArrayTestSuite) TestArrayConstructor() {
+	suite.T().Logf("Testing array constructor for %s", suite.dbType)
+
+	suite.Run("AsValues", func() {
+		suite.Equal([]string{"python"}, suite.names(func(cb ConditionBuilder) {
+			cb.Expr(func(eb ExprBuilder) any {
+				return eb.ArrayContains(eb.Column("tags"), eb.Array("backend", "scripting"))
+			})
+		}))
+		suite.Equal([]string{"go", "js"}, suite.names(func(cb ConditionBuilder) {
+			cb.Expr(func(eb ExprBuilder) any {
+				return eb.ArrayOverlaps(eb.Column("tags"), eb.Array([]string{"compiled", "frontend"}))
+			})
+		}))
+	})
+
+	suite.Run("Length", func() {
+		var lengths struct {
+			Values int64 `bun:"values_length"`
+			Slice  int64 `bun:"slice_length"`
+			Empty  int64 `bun:"empty_length"`
+		}
+
+		err := suite.db.NewSelect().
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.ArrayLength(eb.Array(1, 2, 3))
+			}, "values_length").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.ArrayLength(eb.Array([]int{1, 2}))
+			}, "slice_length").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.ArrayLength(eb.Array())
+			}, "empty_length").
+			Scan(suite.ctx, &lengths)
+
+		suite.Require().NoError(err, "Should select array lengths")
+		suite.Equal(int64(3), lengths.Values, "Values should make the elements")
+		suite.Equal(int64(2), lengths.Slice, "A slice should be expanded into the elements")
+		suite.Equal(int64(0), lengths.Empty, "No values should make an empty array")
+	})
+}
+
+func (suite *ArrayTestSuite) TestAppendRemove() {
+	suite.T().Logf("Testing array append and remove for %s", suite.dbType)
+
+	bunDB := suite.getBunDB()
+	_, err := bunDB.ExecContext(suite.ctx, "INSERT INTO test_array_item (name, tags) VALUES ('rust', "+suite.arrayLiteral([]string{"backend", "compiled"})+")")
+	suite.Require().NoError(err, "Should insert array item")
+
+	defer func() {
+		_, err := bunDB.ExecContext(suite.ctx, "DELETE FROM test_array_item WHERE name = 'rust'")
+		suite.NoError(err, "Should cleanup array item")
+	}()
+
+	update := func(builder func(ExprBuilder) any) {
+		_, err := suite.db.NewUpdate().
+			Table("test_array_item").
+			SetExpr("tags", builder).
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("name", "rust")
+			}).
+			Exec(suite.ctx)
+		suite.Require().NoError(err, "Should update array")
+	}
+
+	update(func(eb ExprBuilder) any {
+		return eb.ArrayAppend(eb.Column("tags", false), "typed")
+	})
+	suite.Equal([]string{"rust"}, suite.names(func(cb ConditionBuilder) {
+		cb.ArrayContains("tags", []string{"backend", "compiled", "typed"}).
+			ArrayLength("tags", 3)
+	}))
+
+	update(func(eb ExprBuilder) any {
+		return eb.ArrayRemove(eb.Column("tags", false), "backend")
+	})
+	suite.Equal([]string{"rust"}, suite.names(func(cb ConditionBuilder) {
+		cb.ArrayContains("tags", []string{"compiled", "typed"}).
+			ArrayLength("tags", 2)
+	}))
+	suite.Equal([]string{"go", "python"}, suite.names(func(cb ConditionBuilder) {
+		cb.ArrayContains("tags", []string{"backend"})
+	}))
+}
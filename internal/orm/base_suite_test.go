@@ -20,7 +20,7 @@ type User struct {
 	Model
 
 	Name  string `json:"name"     bun:"name,notnull"`
-	Email string `json:"email"    bun:"email,notnull,unique"`
+	Email string `json:"email"    bun:"email,notnull,unique" exprindex:"lower"`
 	Age   int16  `json:"age"      bun:"age,notnull,default:0"`
 	// Bun applies the struct default even when fixtures explicitly set this field to true, so we
 	// avoid declaring a default to keep fixture values intact.
@@ -48,6 +48,7 @@ type Post struct {
 	// Relations
 	User     *User     `json:"user"     bun:"rel:belongs-to,join:user_id=id"`
 	Category *Category `json:"category" bun:"rel:belongs-to,join:category_id=id"`
+	Tags     []Tag     `json:"tags"     bun:"m2m:test_post_tag,join:Post=Tag"`
 }
 
 // Tag represents a content tag.
@@ -111,11 +112,12 @@ func (suite *OrmTestSuite) SetupSuite() {
 	suite.T().Logf("Setting up Orm test suite for %s", suite.dbType)
 
 	db := suite.getBunDB()
+	// The junction model of Post.Tags has to be registered before Post
 	db.RegisterModel(
+		(*PostTag)(nil),
 		(*User)(nil),
 		(*Post)(nil),
 		(*Tag)(nil),
-		(*PostTag)(nil),
 		(*Category)(nil),
 		(*SimpleModel)(nil),
 	)
@@ -149,6 +151,9 @@ func (suite *OrmTestSuite) SetupSuite() {
 	err := fixture.Load(suite.ctx, os.DirFS("testdata"), "fixture.yaml")
 	suite.Require().NoError(err, "Failed to load fixtures")
 
+	err = CreateExpressionIndexes(suite.ctx, suite.db, (*User)(nil))
+	suite.Require().NoError(err, "Failed to create expression indexes")
+
 	_, err = db.NewCreateTable().IfNotExists().Model((*SimpleModel)(nil)).Exec(suite.ctx)
 	suite.Require().NoError(err, "Failed to create simple model table")
 	suite.Require().NoError(err, "Failed to create complex model table")
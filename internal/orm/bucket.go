@@ -0,0 +1,39 @@
+package orm
+
+// BucketBoundary describes the value range covered by a single WidthBucket bucket.
+// The range is half-open: Lower is inclusive and Upper is exclusive.
+type BucketBoundary struct {
+	// Bucket is the 1-based bucket number as returned by WidthBucket.
+	Bucket int `json:"bucket"`
+	// Lower is the inclusive lower bound of the bucket.
+	Lower float64 `json:"lower"`
+	// Upper is the exclusive upper bound of the bucket.
+	Upper float64 `json:"upper"`
+}
+
+// WidthBucketBoundaries returns the boundaries of the buckets produced by WidthBucket with the same arguments,
+// which is typically used to render chart legends. The underflow bucket 0 and the overflow bucket buckets+1
+// are unbounded and therefore not included. It returns nil if buckets is not positive or high is not greater than low.
+func WidthBucketBoundaries(low, high float64, buckets int) []BucketBoundary {
+	if buckets <= 0 || high <= low {
+		return nil
+	}
+
+	var (
+		width      = (high - low) / float64(buckets)
+		boundaries = make([]BucketBoundary, buckets)
+	)
+
+	for i := range buckets {
+		boundaries[i] = BucketBoundary{
+			Bucket: i + 1,
+			Lower:  low + width*float64(i),
+			Upper:  low + width*float64(i+1),
+		}
+	}
+
+	// Pin the last upper bound to avoid floating-point drift
+	boundaries[buckets-1].Upper = high
+
+	return boundaries
+}
@@ -0,0 +1,46 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ilxqx/vef-framework-go/internal/database/querypolicy"
+)
+
+// withRejection returns the context to run the statements of a query with, receiving the query policy violation
+// they are rejected for, which contextError reports in place of their cancellation.
+func withRejection(ctx context.Context) context.Context {
+	return querypolicy.WithRejection(ctx)
+}
+
+// contextError reports the context error in place of err once the context is done, so that cancellation and
+// deadline expiry are detectable with errors.Is regardless of how the driver surfaces them.
+// The driver error is kept in the message only, it is a consequence of the cancellation.
+// Statements rejected by their query policy report the violation, matching ErrPolicyViolation.
+func contextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	ctxErr := ctx.Err()
+	if ctxErr == nil {
+		return querypolicy.Rejected(ctx, err)
+	}
+
+	if errors.Is(err, ctxErr) {
+		return err
+	}
+
+	return fmt.Errorf("%w (%v)", ctxErr, err)
+}
+
+// scanContextError is contextError for reads: a read whose context was cancelled before it returned fails with
+// the context error even if row iteration stopped without error, so callers never consume partially scanned results.
+func scanContextError(ctx context.Context, err error) error {
+	if err == nil {
+		return ctx.Err()
+	}
+
+	return contextError(ctx, err)
+}
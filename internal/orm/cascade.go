@@ -0,0 +1,210 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// cascadeBatchSize is the maximum number of parent keys per statement, within the IN list limit of Oracle.
+const cascadeBatchSize = 1000
+
+// CascadeMode determines how a cascading delete handles the dependent rows of a relation.
+type CascadeMode int
+
+const (
+	// CascadeDefault soft deletes the dependent rows when their model supports soft deletes and the delete is not forced,
+	// and deletes them otherwise.
+	CascadeDefault CascadeMode = iota
+	// CascadeForce force deletes the dependent rows, including the soft deleted ones, and those of their relations.
+	CascadeForce
+	// CascadeSkip leaves the dependent rows and those of their relations untouched.
+	CascadeSkip
+)
+
+// CascadeHook overrides the mode of a relation of a cascading delete. Relations are identified by the path
+// of their Go field names from the deleted model, e.g. "Posts" or "Posts.Comments".
+// The first hook returning a mode other than CascadeDefault decides.
+type CascadeHook func(relation string) CascadeMode
+
+// cascader deletes the rows depending on deleted rows through has-one and has-many relations, deepest first.
+type cascader struct {
+	db      *BunDB
+	hooks   []CascadeHook
+	visited map[string]bool // Rows already walked, keyed by table and primary key, guarding against cyclic data
+}
+
+func (c *cascader) mode(relation string) CascadeMode {
+	for _, hook := range c.hooks {
+		if mode := hook(relation); mode != CascadeDefault {
+			return mode
+		}
+	}
+
+	return CascadeDefault
+}
+
+// cascadeRelations returns the sorted names of the relations of a table a cascading delete walks into.
+func cascadeRelations(table *schema.Table) []string {
+	return slices.Sorted(func(yield func(string) bool) {
+		for name, rel := range table.Relations {
+			if rel.Type == schema.HasOneRelation || rel.Type == schema.HasManyRelation {
+				if !yield(name) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// keyColumns returns the columns of a table to read from its deleted rows: the primary key
+// and the columns its cascaded relations join on.
+func keyColumns(table *schema.Table) []string {
+	columns := make([]string, 0, len(table.PKs))
+	for _, pk := range table.PKs {
+		columns = append(columns, pk.Name)
+	}
+
+	for _, name := range cascadeRelations(table) {
+		for _, field := range table.Relations[name].BasePKs {
+			if !slices.Contains(columns, field.Name) {
+				columns = append(columns, field.Name)
+			}
+		}
+	}
+
+	return columns
+}
+
+// unvisited returns the rows of a table not walked yet and marks them as walked.
+func (c *cascader) unvisited(table *schema.Table, rows []map[string]any) []map[string]any {
+	return slices.DeleteFunc(rows, func(row map[string]any) bool {
+		var key strings.Builder
+
+		_, _ = key.WriteString(table.Name)
+		for _, pk := range table.PKs {
+			_, _ = fmt.Fprintf(&key, "|%v", row[pk.Name])
+		}
+
+		if c.visited[key.String()] {
+			return true
+		}
+
+		c.visited[key.String()] = true
+
+		return false
+	})
+}
+
+// deleteDependents deletes the rows depending on the given rows of a table.
+func (c *cascader) deleteDependents(ctx context.Context, table *schema.Table, rows []map[string]any, path string, force bool) error {
+	for _, name := range cascadeRelations(table) {
+		rel := table.Relations[name]
+		relation := path + name
+
+		mode := c.mode(relation)
+		if mode == CascadeSkip {
+			continue
+		}
+
+		relForce := force || mode == CascadeForce
+
+		for batch := range slices.Chunk(rowKeys(rel.BasePKs, rows), cascadeBatchSize) {
+			if err := c.deleteRelated(ctx, rel, batch, relation, relForce); err != nil {
+				return fmt.Errorf("failed to cascade delete to %s: %w", relation, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteRelated deletes the rows of a relation joining the given values, after their own dependent rows.
+func (c *cascader) deleteRelated(ctx context.Context, rel *schema.Relation, values [][]any, relation string, force bool) error {
+	where := func(cb ConditionBuilder) {
+		if len(rel.JoinPKs) == 1 {
+			column := make([]any, len(values))
+			for i, value := range values {
+				column[i] = value[0]
+			}
+
+			cb.In(rel.JoinPKs[0].Name, column)
+		} else {
+			cb.Group(func(cb ConditionBuilder) {
+				for _, value := range values {
+					cb.OrGroup(func(cb ConditionBuilder) {
+						for i, field := range rel.JoinPKs {
+							cb.Equals(field.Name, value[i])
+						}
+					})
+				}
+			})
+		}
+
+		if rel.PolymorphicField != nil {
+			cb.Equals(rel.PolymorphicField.Name, rel.PolymorphicValue)
+		}
+	}
+
+	join := rel.JoinTable
+	if len(cascadeRelations(join)) > 0 {
+		var dependents []map[string]any
+
+		if err := c.db.NewSelect().
+			Model(join.ZeroIface).
+			Select(keyColumns(join)...).
+			Where(where).
+			ApplyIf(force, func(query SelectQuery) {
+				query.IncludeDeleted()
+			}).
+			Scan(ctx, &dependents); err != nil {
+			return err
+		}
+
+		if dependents = c.unvisited(join, dependents); len(dependents) > 0 {
+			if err := c.deleteDependents(ctx, join, dependents, relation+constants.Dot, force); err != nil {
+				return err
+			}
+		}
+	}
+
+	query := c.db.NewDelete().
+		Model(join.ZeroIface).
+		Where(where)
+	if force {
+		query.ForceDelete().IncludeDeleted()
+	}
+
+	_, err := query.Exec(ctx)
+
+	return err
+}
+
+// rowKeys returns the distinct non-null values of the given columns in the rows.
+func rowKeys(fields []*schema.Field, rows []map[string]any) [][]any {
+	seen := make(map[string]bool, len(rows))
+	values := make([][]any, 0, len(rows))
+
+	for _, row := range rows {
+		value := make([]any, len(fields))
+		for i, field := range fields {
+			value[i] = row[field.Name]
+		}
+
+		if slices.Contains(value, nil) {
+			continue
+		}
+
+		if key := fmt.Sprintf("%#v", value); !seen[key] {
+			seen[key] = true
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
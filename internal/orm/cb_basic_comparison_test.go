@@ -1,7 +1,7 @@
 package orm
 
 // BasicComparisonTestSuite tests basic comparison condition methods.
-// Covers: Equals, NotEquals, GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual
+// Covers: Equals, NotEquals, GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual, HasFlag, HasAnyFlag
 // and their column comparison variants (EqualsColumn, etc.).
 type BasicComparisonTestSuite struct {
 	*ConditionBuilderTestSuite
@@ -498,3 +498,68 @@ func (suite *BasicComparisonTestSuite) TestLessThanOrEqualColumn() {
 		suite.T().Logf("Found %d posts", len(posts))
 	})
 }
+
+// TestHasFlag tests the HasFlag, NotHasFlag, HasAnyFlag conditions and their Or variants.
+func (suite *BasicComparisonTestSuite) TestHasFlag() {
+	suite.T().Logf("Testing HasFlag condition for %s", suite.dbType)
+
+	suite.Run("BasicHasFlag", func() {
+		posts := suite.assertQueryReturnsPosts(
+			suite.db.NewSelect().
+				Model((*Post)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.HasFlag("view_count", 3)
+				}).
+				OrderBy("view_count"),
+		)
+
+		suite.Len(posts, 2, "Should find posts with both low bits set")
+
+		for _, post := range posts {
+			suite.Equal(3, post.ViewCount&3, "Both bits should be set")
+		}
+	})
+
+	suite.Run("NotHasFlag", func() {
+		posts := suite.assertQueryReturnsPosts(
+			suite.db.NewSelect().
+				Model((*Post)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.NotHasFlag("view_count", 1)
+				}),
+		)
+
+		suite.Len(posts, 3, "Should find posts with even view counts")
+
+		for _, post := range posts {
+			suite.Equal(0, post.ViewCount&1, "Lowest bit should be cleared")
+		}
+	})
+
+	suite.Run("HasAnyFlag", func() {
+		posts := suite.assertQueryReturnsPosts(
+			suite.db.NewSelect().
+				Model((*Post)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.HasAnyFlag("view_count", 3)
+				}),
+		)
+
+		suite.Len(posts, 6, "Should find posts with either low bit set")
+	})
+
+	suite.Run("OrHasFlag", func() {
+		posts := suite.assertQueryReturnsPosts(
+			suite.db.NewSelect().
+				Model((*Post)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.HasFlag("view_count", 64).
+						OrHasFlag("view_count", 16)
+				}),
+		)
+
+		suite.Len(posts, 7, "Should find posts with either high bit set")
+
+		suite.T().Logf("Found %d posts", len(posts))
+	})
+}
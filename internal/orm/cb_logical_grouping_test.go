@@ -1,7 +1,7 @@
 package orm
 
 // LogicalGroupingTestSuite tests logical grouping condition methods.
-// Covers: Group, OrGroup (4 methods total including nested scenarios).
+// Covers: Group, OrGroup, NotGroup, OrNotGroup (including nested scenarios).
 type LogicalGroupingTestSuite struct {
 	*ConditionBuilderTestSuite
 }
@@ -169,6 +169,79 @@ func (suite *LogicalGroupingTestSuite) TestOrGroup() {
 	})
 }
 
+// TestNotGroup tests the NotGroup and OrNotGroup conditions for negated grouping.
+func (suite *LogicalGroupingTestSuite) TestNotGroup() {
+	suite.T().Logf("Testing NotGroup condition for %s", suite.dbType)
+
+	suite.Run("FirstNotGroup", func() {
+		// NOT (is_active = true AND age > 25)
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.NotGroup(func(cb ConditionBuilder) {
+						cb.Equals("is_active", true).
+							GreaterThan("age", 25)
+					})
+				}).
+				OrderBy("age"),
+		)
+
+		suite.Require().Len(users, 2, "Should find all users but the active ones older than 25")
+		suite.Equal("Bob Smith", users[0].Name)
+		suite.Equal("Charlie Brown", users[1].Name)
+	})
+
+	suite.Run("NotGroupWithOr", func() {
+		// age > 20 AND NOT (age = 25 OR age = 35)
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.GreaterThan("age", 20).
+						NotGroup(func(cb ConditionBuilder) {
+							cb.Equals("age", 25).
+								OrEquals("age", 35)
+						})
+				}),
+		)
+
+		suite.Require().Len(users, 1, "Should negate the group as a whole")
+		suite.Equal("Alice Johnson", users[0].Name)
+	})
+
+	suite.Run("OrNotGroup", func() {
+		// age = 25 OR NOT (is_active = true)
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.Equals("age", 25).
+						OrNotGroup(func(cb ConditionBuilder) {
+							cb.Equals("is_active", true)
+						})
+				}).
+				OrderBy("age"),
+		)
+
+		suite.Require().Len(users, 2, "Should find Bob and the inactive users")
+		suite.Equal("Bob Smith", users[0].Name)
+		suite.Equal("Charlie Brown", users[1].Name)
+	})
+
+	suite.Run("EmptyNotGroup", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.NotGroup(func(ConditionBuilder) {})
+				}),
+		)
+
+		suite.Len(users, 3, "Should ignore an empty group")
+	})
+}
+
 // TestComplexLogicalCombinations tests complex combinations of Group and OrGroup.
 func (suite *LogicalGroupingTestSuite) TestComplexLogicalCombinations() {
 	suite.T().Logf("Testing complex logical combinations for %s", suite.dbType)
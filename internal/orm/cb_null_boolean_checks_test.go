@@ -1,7 +1,15 @@
 package orm
 
+import (
+	"context"
+	"errors"
+)
+
+// errRollback rolls back the transactions of tests changing fixture rows.
+var errRollback = errors.New("rollback")
+
 // NullBooleanChecksTestSuite tests NULL and boolean check condition methods.
-// Covers: IsNull, IsNotNull, IsTrue, IsFalse, IsTrueOrNull, IsFalseOrNull and their Or variants.
+// Covers: IsNull, IsNotNull, IsTrue, IsFalse, IsTrueOrNull, IsFalseOrNull, EqualsNullSafe and their Or variants.
 type NullBooleanChecksTestSuite struct {
 	*ConditionBuilderTestSuite
 }
@@ -44,6 +52,58 @@ func (suite *NullBooleanChecksTestSuite) TestIsNull() {
 	})
 }
 
+// TestEqualsNullSafe tests the EqualsNullSafe and OrEqualsNullSafe conditions.
+func (suite *NullBooleanChecksTestSuite) TestEqualsNullSafe() {
+	suite.T().Logf("Testing EqualsNullSafe condition for %s", suite.dbType)
+
+	count := func(ctx context.Context, db DB, builder func(cb ConditionBuilder)) int64 {
+		count, err := db.NewSelect().Model((*Post)(nil)).Where(builder).Count(ctx)
+		suite.Require().NoError(err, "Should count posts")
+
+		return count
+	}
+
+	suite.Run("NullValue", func() {
+		// All fixture posts have a description, clear one in a transaction rolled back afterwards
+		err := suite.db.RunInTX(suite.ctx, func(ctx context.Context, tx DB) error {
+			_, err := tx.NewUpdate().
+				Model((*Post)(nil)).
+				Set("description", nil).
+				Where(func(cb ConditionBuilder) {
+					cb.Equals("description", "Getting started with Go")
+				}).
+				Exec(ctx)
+			suite.Require().NoError(err, "Should clear the description")
+
+			suite.Equal(int64(1), count(ctx, tx, func(cb ConditionBuilder) {
+				cb.EqualsNullSafe("description", nil)
+			}), "NULL should equal NULL")
+			suite.Zero(count(ctx, tx, func(cb ConditionBuilder) {
+				cb.Equals("description", nil)
+			}), "Plain equality never matches NULL")
+			suite.Equal(int64(2), count(ctx, tx, func(cb ConditionBuilder) {
+				cb.EqualsNullSafe("description", "Idiomatic Go techniques").OrEqualsNullSafe("description", nil)
+			}), "Should match the post with the description and the post without description")
+
+			return errRollback
+		})
+		suite.ErrorIs(err, errRollback, "Should roll back the cleared description")
+	})
+
+	suite.Run("NonNullValue", func() {
+		posts := suite.assertQueryReturnsPosts(
+			suite.db.NewSelect().
+				Model((*Post)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.EqualsNullSafe("description", "Getting started with Go")
+				}),
+		)
+
+		suite.Len(posts, 1, "Should find the post with the description")
+		suite.Equal("Getting started with Go", *posts[0].Description)
+	})
+}
+
 // TestIsNotNull tests the IsNotNull and OrIsNotNull conditions.
 func (suite *NullBooleanChecksTestSuite) TestIsNotNull() {
 	suite.T().Logf("Testing IsNotNull condition for %s", suite.dbType)
@@ -1,7 +1,13 @@
 package orm
 
+import (
+	"fmt"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
 // RangeSetOperationsTestSuite tests range and set operation condition methods.
-// Covers: Between, NotBetween, BetweenExpr, NotBetweenExpr, In, NotIn, InExpr, NotInExpr.
+// Covers: Between, NotBetween, BetweenExpr, NotBetweenExpr, In, NotIn, InExpr, NotInExpr, TupleIn, TupleNotIn and tuple comparisons.
 type RangeSetOperationsTestSuite struct {
 	*ConditionBuilderTestSuite
 }
@@ -287,6 +293,105 @@ func (suite *RangeSetOperationsTestSuite) TestNotIn() {
 	})
 }
 
+// TestEqualsAnyOf tests the EqualsAnyOf and NotEqualsAllOf conditions with slices of values.
+func (suite *RangeSetOperationsTestSuite) TestEqualsAnyOf() {
+	suite.T().Logf("Testing EqualsAnyOf condition for %s", suite.dbType)
+
+	names := func(builder func(cb ConditionBuilder)) []string {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(builder).
+				OrderBy("name"),
+		)
+
+		names := make([]string, len(users))
+		for i, user := range users {
+			names[i] = user.Name
+		}
+
+		return names
+	}
+
+	suite.Run("EqualsAnyOf", func() {
+		suite.Equal([]string{"Alice Johnson", "Bob Smith"}, names(func(cb ConditionBuilder) {
+			cb.EqualsAnyOf("email", []string{"alice@example.com", "bob@example.com"})
+		}))
+		suite.Equal([]string{"Bob Smith", "Charlie Brown"}, names(func(cb ConditionBuilder) {
+			cb.EqualsAnyOf("age", []int16{25}).OrEqualsAnyOf("age", []int16{35})
+		}))
+	})
+
+	suite.Run("NotEqualsAllOf", func() {
+		suite.Equal([]string{"Alice Johnson"}, names(func(cb ConditionBuilder) {
+			cb.NotEqualsAllOf("age", []int16{25, 35})
+		}))
+	})
+
+	suite.Run("EmptySlice", func() {
+		suite.Empty(names(func(cb ConditionBuilder) {
+			cb.EqualsAnyOf("email", []string{})
+		}), "No values should match no rows")
+		suite.Len(names(func(cb ConditionBuilder) {
+			cb.NotEqualsAllOf("email", []string{})
+		}), 3, "No values should exclude no rows")
+	})
+}
+
+// TestInLarge tests that InLarge joins sets of values above the threshold instead of expanding an IN list.
+func (suite *RangeSetOperationsTestSuite) TestInLarge() {
+	suite.T().Logf("Testing InLarge condition for %s", suite.dbType)
+
+	SetInLargeThreshold(2)
+	defer SetInLargeThreshold(0)
+
+	emails := []string{"alice@example.com", "bob@example.com", "charlie@example.com"}
+	for i := range 5000 {
+		emails = append(emails, fmt.Sprintf("user%d@example.com", i))
+	}
+
+	query := func(emails []string) SelectQuery {
+		return suite.db.NewSelect().
+			Model((*User)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.InLarge("email", emails)
+			}).
+			OrderBy("name")
+	}
+
+	suite.Run("BelowThreshold", func() {
+		sql, err := RenderSQL(query(emails[:2]))
+		suite.Require().NoError(err)
+		suite.Contains(sql, "IN ('alice@example.com', 'bob@example.com')")
+
+		suite.Len(suite.assertQueryReturnsUsers(query(emails[:2])), 2)
+	})
+
+	suite.Run("AboveThreshold", func() {
+		sql, err := RenderSQL(query(emails))
+		suite.Require().NoError(err)
+
+		if suite.dbType == constants.Postgres {
+			suite.Contains(sql, "= ANY ('{")
+		} else {
+			suite.Contains(sql, "IN (VALUES ")
+		}
+
+		suite.Len(suite.assertQueryReturnsUsers(query(emails)), 3)
+	})
+
+	suite.Run("OrInLarge", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.Equals("age", 25).OrInLarge("email", emails[2:])
+				}),
+		)
+		suite.Len(users, 2)
+	})
+}
+
 // TestInExpr tests the InExpr and OrInExpr conditions.
 func (suite *RangeSetOperationsTestSuite) TestInExpr() {
 	suite.T().Logf("Testing InExpr condition for %s", suite.dbType)
@@ -372,3 +477,136 @@ func (suite *RangeSetOperationsTestSuite) TestNotInExpr() {
 		suite.T().Logf("Found %d users", len(users))
 	})
 }
+
+// TestTupleIn tests the TupleIn, OrTupleIn, TupleNotIn and OrTupleNotIn conditions.
+func (suite *RangeSetOperationsTestSuite) TestTupleIn() {
+	suite.T().Logf("Testing TupleIn condition for %s", suite.dbType)
+
+	suite.Run("BasicTupleIn", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.TupleIn([]string{"name", "age"}, [][]any{
+						{"Alice Johnson", 30},
+						{"Bob Smith", 25},
+						{"Charlie Brown", 99},
+					})
+				}).
+				OrderBy("age"),
+		)
+
+		suite.Len(users, 2, "Should find two users matching both columns")
+		suite.Equal("Bob Smith", users[0].Name)
+		suite.Equal("Alice Johnson", users[1].Name)
+
+		suite.T().Logf("Found users: %s, %s", users[0].Name, users[1].Name)
+	})
+
+	suite.Run("TupleNotIn", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.TupleNotIn([]string{"name", "age"}, [][]any{
+						{"Alice Johnson", 30},
+						{"Bob Smith", 25},
+					})
+				}).
+				OrderBy("age"),
+		)
+
+		suite.Len(users, 1, "Should find one user")
+		suite.Equal("Charlie Brown", users[0].Name)
+	})
+
+	suite.Run("OrTupleIn", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.TupleIn([]string{"name", "age"}, [][]any{{"Alice Johnson", 30}}).
+						OrTupleIn([]string{"name", "age"}, [][]any{{"Charlie Brown", 35}})
+				}).
+				OrderBy("age"),
+		)
+
+		suite.Len(users, 2, "Should find two users")
+	})
+
+	suite.Run("EmptyTupleIn", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.TupleIn([]string{"name", "age"}, nil)
+				}),
+		)
+
+		suite.Len(users, 0, "Empty tuple list should match nothing")
+	})
+}
+
+// TestTupleComparison tests the tuple comparison conditions used for keyset pagination.
+func (suite *RangeSetOperationsTestSuite) TestTupleComparison() {
+	suite.T().Logf("Testing tuple comparison conditions for %s", suite.dbType)
+
+	suite.Run("TupleGreaterThan", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.TupleGreaterThan([]string{"is_active", "age"}, []any{true, 25})
+				}).
+				OrderBy("age"),
+		)
+
+		suite.Len(users, 1, "Should find one user after (true, 25)")
+		suite.Equal("Alice Johnson", users[0].Name)
+	})
+
+	suite.Run("TupleGreaterThanOrEqual", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.TupleGreaterThanOrEqual([]string{"age", "name"}, []any{30, "Alice Johnson"})
+				}).
+				OrderBy("age"),
+		)
+
+		suite.Len(users, 2, "Should find users from (30, Alice Johnson) onwards")
+		suite.Equal("Alice Johnson", users[0].Name)
+		suite.Equal("Charlie Brown", users[1].Name)
+	})
+
+	suite.Run("TupleLessThan", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.TupleLessThan([]string{"age", "name"}, []any{30, "Alice Johnson"})
+				}).
+				OrderBy("age"),
+		)
+
+		suite.Len(users, 1, "Should find users before (30, Alice Johnson)")
+		suite.Equal("Bob Smith", users[0].Name)
+	})
+
+	suite.Run("TupleLessThanOrEqual", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.TupleLessThanOrEqual([]string{"age", "name"}, []any{30, "Alice Johnson"}).
+						OrTupleGreaterThan([]string{"age", "name"}, []any{35, "Charlie Brown"})
+				}).
+				OrderBy("age"),
+		)
+
+		suite.Len(users, 2, "Should find users up to (30, Alice Johnson)")
+		suite.Equal("Bob Smith", users[0].Name)
+		suite.Equal("Alice Johnson", users[1].Name)
+	})
+}
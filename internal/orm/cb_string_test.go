@@ -1,8 +1,15 @@
 package orm
 
+import (
+	"github.com/samber/lo"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
 // StringOperationsTestSuite tests string operation condition methods.
 // Covers: Contains, StartsWith, EndsWith, ContainsAny, StartsWithAny, EndsWithAny
-// and their case-insensitive variants (ContainsIgnoreCase, StartsWithIgnoreCase, etc.)
+// and their case-insensitive variants (ContainsIgnoreCase, StartsWithIgnoreCase, etc.),
+// as well as EqualsIgnoreCase, InIgnoreCase and the regular expression conditions (Matches, NotMatches, etc.).
 type StringOperationsTestSuite struct {
 	*ConditionBuilderTestSuite
 }
@@ -329,3 +336,200 @@ func (suite *StringOperationsTestSuite) TestEndsWithAny() {
 		suite.T().Logf("Found %d users", len(users))
 	})
 }
+
+// TestEqualsIgnoreCase tests the EqualsIgnoreCase and OrEqualsIgnoreCase conditions (case-insensitive).
+func (suite *StringOperationsTestSuite) TestEqualsIgnoreCase() {
+	suite.T().Logf("Testing EqualsIgnoreCase condition for %s", suite.dbType)
+
+	suite.Run("BasicIEquals", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.EqualsIgnoreCase("email", "ALICE@Example.COM")
+				}),
+		)
+
+		suite.Len(users, 1, "Should find one user (case-insensitive)")
+		suite.Equal("alice@example.com", users[0].Email)
+
+		suite.T().Logf("Found user: %s", users[0].Email)
+	})
+
+	suite.Run("WildcardsAreLiteral", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.EqualsIgnoreCase("email", "%@example.com")
+				}),
+		)
+
+		suite.Len(users, 0, "Wildcards should not match as patterns")
+	})
+
+	suite.Run("OrIEquals", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.EqualsIgnoreCase("name", "alice johnson").
+						OrEqualsIgnoreCase("name", "BOB SMITH")
+				}).
+				OrderBy("name"),
+		)
+
+		suite.Len(users, 2, "Should find two users (case-insensitive)")
+
+		suite.T().Logf("Found %d users", len(users))
+	})
+
+	suite.Run("WithExpressionIndex", func() {
+		indexes := ExpressionIndexesOf(suite.db.TableOf((*User)(nil)))
+		suite.Len(indexes, 1, "Should parse one tagged expression index")
+		suite.Equal("LOWER(email)", indexes[0].Expr)
+		suite.Equal("idx_test_user_lower_email", indexes[0].Name)
+		suite.True(hasExpressionIndex(suite.db.TableOf((*User)(nil)), "lower( email )"), "Tagged index should be declared")
+		suite.True(hasColumnExpressionIndex(suite.db.TableOf((*User)(nil)), "email"), "Tagged index should involve the column")
+		suite.False(hasColumnExpressionIndex(suite.db.TableOf((*User)(nil)), "meta"), "No index should involve the JSON column")
+		suite.NoError(CreateExpressionIndexes(suite.ctx, suite.db, (*User)(nil)), "Creating existing indexes should be idempotent")
+
+		query, err := ExpressionIndexSQL(suite.db, indexes[0])
+		suite.NoError(err)
+		quote := lo.Ternary(suite.dbType == constants.MySQL, "`", `"`)
+		suite.Contains(query, quote+"idx_test_user_lower_email"+quote+" ON "+quote+"test_user"+quote, "Identifiers should be quoted by the dialect")
+
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.EqualsIgnoreCase("u.email", "Bob@Example.com")
+				}),
+		)
+
+		suite.Len(users, 1, "Should find one user using the LOWER() form")
+		suite.Equal("bob@example.com", users[0].Email)
+	})
+}
+
+// TestInIgnoreCase tests the InIgnoreCase and OrInIgnoreCase conditions (case-insensitive).
+func (suite *StringOperationsTestSuite) TestInIgnoreCase() {
+	suite.T().Logf("Testing InIgnoreCase condition for %s", suite.dbType)
+
+	suite.Run("BasicIIn", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.InIgnoreCase("name", []string{"ALICE JOHNSON", "charlie brown"})
+				}).
+				OrderBy("name"),
+		)
+
+		suite.Len(users, 2, "Should find two users (case-insensitive)")
+		suite.Equal("Alice Johnson", users[0].Name)
+		suite.Equal("Charlie Brown", users[1].Name)
+	})
+
+	suite.Run("EmptyValues", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.InIgnoreCase("name", nil)
+				}),
+		)
+
+		suite.Len(users, 0, "Empty value list should match nothing")
+	})
+
+	suite.Run("OrIIn", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.InIgnoreCase("name", []string{"alice johnson"}).
+						OrInIgnoreCase("name", []string{"BOB SMITH"})
+				}).
+				OrderBy("name"),
+		)
+
+		suite.Len(users, 2, "Should find two users (case-insensitive)")
+
+		suite.T().Logf("Found %d users", len(users))
+	})
+}
+
+// TestMatches tests the regular expression conditions.
+func (suite *StringOperationsTestSuite) TestMatches() {
+	suite.T().Logf("Testing Matches condition for %s", suite.dbType)
+
+	suite.Run("BasicMatches", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.Matches("name", "^(Alice|Bob) ")
+				}).
+				OrderBy("name"),
+		)
+
+		suite.Len(users, 2, "Should find Alice and Bob")
+		suite.Equal("Alice Johnson", users[0].Name)
+		suite.Equal("Bob Smith", users[1].Name)
+	})
+
+	suite.Run("CaseSensitive", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.Matches("name", "^alice")
+				}),
+		)
+
+		suite.Len(users, 0, "Matches should be case-sensitive")
+	})
+
+	suite.Run("MatchesIgnoreCase", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.MatchesIgnoreCase("name", "^alice")
+				}),
+		)
+
+		suite.Len(users, 1, "Should find Alice ignoring case")
+		suite.Equal("Alice Johnson", users[0].Name)
+	})
+
+	suite.Run("NotMatches", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.NotMatches("email", "^(alice|bob)@")
+				}),
+		)
+
+		suite.Len(users, 1, "Should find Charlie")
+		suite.Equal("Charlie Brown", users[0].Name)
+	})
+
+	suite.Run("OrMatches", func() {
+		users := suite.assertQueryReturnsUsers(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.Matches("name", "^Alice").
+						OrNotMatchesIgnoreCase("name", "(alice|bob)")
+				}).
+				OrderBy("name"),
+		)
+
+		suite.Len(users, 2, "Should find Alice and Charlie")
+		suite.Equal("Alice Johnson", users[0].Name)
+		suite.Equal("Charlie Brown", users[1].Name)
+	})
+}
@@ -1,12 +1,14 @@
 package orm
 
 import (
+	"slices"
+
 	"github.com/ilxqx/vef-framework-go/constants"
 )
 
 // SubqueryOperationsTestSuite tests subquery operation condition methods.
 // Covers: InSubQuery, NotInSubQuery, EqualsSubQuery, NotEqualsSubQuery, GreaterThanSubQuery, etc.
-// Also covers: Any, All, Exists, NotExists variants.
+// Also covers: Any, All, Exists, NotExists variants, OuterColumn correlation and HasRelated over relations.
 type SubqueryOperationsTestSuite struct {
 	*ConditionBuilderTestSuite
 }
@@ -568,3 +570,266 @@ func (suite *SubqueryOperationsTestSuite) TestNotExists() {
 		suite.T().Logf("Found %d posts", len(posts))
 	})
 }
+
+// TestOuterColumn tests referencing the columns of enclosing queries in correlated subqueries.
+func (suite *SubqueryOperationsTestSuite) TestOuterColumn() {
+	suite.T().Logf("Testing OuterColumn for %s", suite.dbType)
+
+	activeAuthorPosts, err := suite.db.NewSelect().
+		Model((*Post)(nil)).
+		Where(func(cb ConditionBuilder) {
+			cb.InSubQuery("user_id", func(sq SelectQuery) {
+				sq.Model((*User)(nil)).
+					Select("id").
+					Where(func(cb ConditionBuilder) {
+						cb.Equals("is_active", true)
+					})
+			})
+		}).
+		Count(suite.ctx)
+	suite.Require().NoError(err, "Should count posts of active authors")
+
+	suite.Run("ModelColumn", func() {
+		posts := suite.assertQueryReturnsPosts(
+			suite.db.NewSelect().
+				Model((*Post)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.Expr(func(eb ExprBuilder) any {
+						return eb.Exists(func(sq SelectQuery) {
+							sq.Model((*User)(nil)).
+								Where(func(cb ConditionBuilder) {
+									cb.Equals("is_active", true).
+										Equals("id", sq.OuterColumn("user_id"))
+								})
+						})
+					})
+				}),
+		)
+
+		suite.Len(posts, int(activeAuthorPosts), "Should correlate with the model of the enclosing query")
+	})
+
+	suite.Run("QualifiedColumnOfNestedSubquery", func() {
+		posts := suite.assertQueryReturnsPosts(
+			suite.db.NewSelect().
+				Model((*Post)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.Expr(func(eb ExprBuilder) any {
+						return eb.Exists(func(sq SelectQuery) {
+							sq.Model((*User)(nil)).
+								Where(func(cb ConditionBuilder) {
+									cb.Equals("is_active", true).
+										Expr(func(eb ExprBuilder) any {
+											return eb.Exists(func(nested SelectQuery) {
+												nested.Model((*Category)(nil)).
+													Where(func(cb ConditionBuilder) {
+														cb.Equals("id", nested.OuterColumn("p.category_id")).
+															Equals("u.id", nested.OuterColumn("p.user_id"))
+													})
+											})
+										})
+								})
+						})
+					})
+				}),
+		)
+
+		suite.Len(posts, int(activeAuthorPosts), "Should resolve aliases of any enclosing query")
+	})
+
+	suite.Run("JoinedTable", func() {
+		posts := suite.assertQueryReturnsPosts(
+			suite.db.NewSelect().
+				Model((*Post)(nil)).
+				Join((*User)(nil), func(cb ConditionBuilder) {
+					cb.EqualsColumn("author.id", "user_id")
+				}, "author").
+				Where(func(cb ConditionBuilder) {
+					cb.Expr(func(eb ExprBuilder) any {
+						return eb.Exists(func(sq SelectQuery) {
+							sq.Model((*User)(nil)).
+								Where(func(cb ConditionBuilder) {
+									cb.Equals("is_active", true).
+										Equals("id", sq.OuterColumn("author.id"))
+								})
+						})
+					})
+				}),
+		)
+
+		suite.Len(posts, int(activeAuthorPosts), "Should resolve joined tables of the enclosing query")
+	})
+
+	suite.Run("Validation", func() {
+		build := func(column string) func() {
+			return func() {
+				suite.db.NewSelect().
+					Model((*Post)(nil)).
+					Where(func(cb ConditionBuilder) {
+						cb.Expr(func(eb ExprBuilder) any {
+							return eb.Exists(func(sq SelectQuery) {
+								sq.Model((*User)(nil)).
+									Where(func(cb ConditionBuilder) {
+										cb.Equals("id", sq.OuterColumn(column))
+									})
+							})
+						})
+					})
+			}
+		}
+
+		suite.PanicsWithValue("OuterColumn: table test_post aliased \"p\" has no column \"author_id\"", build("author_id"))
+		suite.PanicsWithValue("OuterColumn: no enclosing query has a table aliased \"x\" in column \"x.id\", known aliases are [p]", build("x.id"))
+		suite.Panics(func() {
+			suite.db.NewSelect().Model((*Post)(nil)).OuterColumn("id")
+		}, "Should require a subquery")
+	})
+}
+
+// TestHasRelated tests the EXISTS and NOT EXISTS conditions over the relations of the model.
+func (suite *SubqueryOperationsTestSuite) TestHasRelated() {
+	suite.T().Logf("Testing HasRelated for %s", suite.dbType)
+
+	userNames := func(query SelectQuery) []string {
+		var users []User
+
+		suite.Require().NoError(query.OrderBy("name").Scan(suite.ctx, &users), "Should query users")
+
+		names := make([]string, len(users))
+		for i, user := range users {
+			names[i] = user.Name
+		}
+
+		return names
+	}
+
+	categoryNames := func(query SelectQuery) []string {
+		var categories []Category
+
+		suite.Require().NoError(query.OrderBy("name").Scan(suite.ctx, &categories), "Should query categories")
+
+		names := make([]string, len(categories))
+		for i, category := range categories {
+			names[i] = category.Name
+		}
+
+		return names
+	}
+
+	postTitles := func(posts []Post) []string {
+		titles := make([]string, len(posts))
+		for i, post := range posts {
+			titles[i] = post.Title
+		}
+
+		slices.Sort(titles)
+
+		return titles
+	}
+
+	suite.Run("HasMany", func() {
+		names := userNames(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.HasRelated("Posts", func(cb ConditionBuilder) {
+						cb.Equals("status", "draft")
+					})
+				}),
+		)
+		suite.Equal([]string{"Alice Johnson"}, names, "Should find users having draft posts")
+
+		names = userNames(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.HasNoRelated("Posts", func(cb ConditionBuilder) {
+						cb.Equals("status", "review")
+					})
+				}),
+		)
+		suite.Equal([]string{"Bob Smith", "Charlie Brown"}, names, "Should find users having no posts in review")
+	})
+
+	suite.Run("BelongsTo", func() {
+		posts := suite.assertQueryReturnsPosts(
+			suite.db.NewSelect().
+				Model((*Post)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.HasRelated("User", func(cb ConditionBuilder) {
+						cb.IsFalse("is_active")
+					})
+				}),
+		)
+		suite.Equal([]string{"Business Strategy Fundamentals", "Practical Tech Tutorials"}, postTitles(posts), "Should find posts of inactive users")
+	})
+
+	suite.Run("ManyToMany", func() {
+		posts := suite.assertQueryReturnsPosts(
+			suite.db.NewSelect().
+				Model((*Post)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.HasRelated("Tags", func(cb ConditionBuilder) {
+						cb.Equals("name", "go")
+					})
+				}),
+		)
+		suite.Equal([]string{"Advanced Go Patterns", "Introduction to Go"}, postTitles(posts), "Should join the junction table")
+
+		posts = suite.assertQueryReturnsPosts(
+			suite.db.NewSelect().
+				Model((*Post)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.HasNoRelated("Tags", func(cb ConditionBuilder) {
+						cb.Equals("name", "tutorial")
+					})
+				}),
+		)
+		suite.Equal([]string{"Advanced Go Patterns"}, postTitles(posts), "Should find posts without the tag")
+	})
+
+	suite.Run("SelfRelation", func() {
+		suite.Equal([]string{"Technology"}, categoryNames(
+			suite.db.NewSelect().
+				Model((*Category)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.HasRelated("Children", nil)
+				}),
+		), "Should alias the related rows apart from the enclosing rows")
+
+		suite.Equal([]string{"GoLang"}, categoryNames(
+			suite.db.NewSelect().
+				Model((*Category)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.HasRelated("Parent", nil)
+				}),
+		))
+	})
+
+	suite.Run("OrHasRelated", func() {
+		names := userNames(
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.Equals("name", "Bob Smith").
+						OrHasNoRelated("Posts", func(cb ConditionBuilder) {
+							cb.Equals("status", "published")
+						}).
+						OrHasRelated("Posts", func(cb ConditionBuilder) {
+							cb.Equals("status", "review")
+						})
+				}),
+		)
+		suite.Equal([]string{"Alice Johnson", "Bob Smith"}, names)
+	})
+
+	suite.Run("UnknownRelation", func() {
+		suite.PanicsWithValue("HasRelated: model User has no relation \"Comments\"", func() {
+			suite.db.NewSelect().
+				Model((*User)(nil)).
+				Where(func(cb ConditionBuilder) {
+					cb.HasRelated("Comments", nil)
+				})
+		})
+	})
+}
@@ -0,0 +1,285 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// TagAudit is the struct tag of the columns of auditable models, `audit:"-"` leaving the column out of the audit
+// events, e.g. for password hashes.
+const TagAudit = "audit"
+
+// AuditOperation is the kind of change an AuditEvent records.
+type AuditOperation string
+
+const (
+	AuditOperationInsert AuditOperation = "INSERT"
+	AuditOperationUpdate AuditOperation = "UPDATE"
+	// AuditOperationDelete records a row removed by a delete, including soft deletes.
+	AuditOperationDelete AuditOperation = "DELETE"
+)
+
+// auditActorColumn aliases the operator of the statement in the selects reading the audited rows.
+const auditActorColumn = "_audit_actor"
+
+// Auditable marks models whose inserts, updates and deletes through the orm emit AuditEvents to the audit sink.
+type Auditable interface {
+	Auditable()
+}
+
+// AuditChange is the change of a column, Old being nil for inserts and New nil for deletes.
+type AuditChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// AuditEvent records the change of a row of an auditable model. Updates record the changed columns only.
+type AuditEvent struct {
+	Table     string                 `json:"table"`
+	Operation AuditOperation         `json:"operation"`
+	PK        map[string]any         `json:"pk"`
+	Changes   map[string]AuditChange `json:"changes"`
+	Actor     string                 `json:"actor"` // Operator of the statement, see constants.ExprOperator
+	At        time.Time              `json:"at"`
+}
+
+// AuditSink receives the audit events of the statements changing auditable models. The events of a statement are
+// written at once after its transaction, or the enclosing one, committed, so that rolled back changes are never
+// audited. Write errors are logged without failing the statement.
+type AuditSink interface {
+	Write(ctx context.Context, events []AuditEvent) error
+}
+
+// TransactionalAuditSink is an AuditSink writing the events with the transaction of the statement instead, e.g.
+// into a table, so that they commit and roll back with the changes. Write errors fail the statement.
+type TransactionalAuditSink interface {
+	AuditSink
+	// WriteInTx writes the events with tx, the transaction of the statement.
+	WriteInTx(ctx context.Context, tx DB, events []AuditEvent) error
+}
+
+// AuditSinkFunc adapts a function to the AuditSink interface.
+type AuditSinkFunc func(ctx context.Context, events []AuditEvent) error
+
+func (f AuditSinkFunc) Write(ctx context.Context, events []AuditEvent) error {
+	return f(ctx, events)
+}
+
+// auditSinkHolder holds the sink in an atomic value, which requires a consistent concrete type.
+type auditSinkHolder struct {
+	sink AuditSink
+}
+
+var auditSink atomic.Value
+
+// SetAuditSink sets the sink receiving the audit events, nil disables auditing.
+func SetAuditSink(sink AuditSink) {
+	auditSink.Store(auditSinkHolder{sink: sink})
+}
+
+func currentAuditSink() AuditSink {
+	holder, _ := auditSink.Load().(auditSinkHolder)
+
+	return holder.sink
+}
+
+// auditOf returns the sink receiving the audit events of the table, nil when the model is not auditable or no
+// sink is set.
+func auditOf(table *schema.Table) AuditSink {
+	if _, ok := table.ZeroIface.(Auditable); !ok {
+		return nil
+	}
+
+	return currentAuditSink()
+}
+
+// auditColumns returns the audited columns of the table.
+func auditColumns(table *schema.Table) []string {
+	columns := make([]string, 0, len(table.Fields))
+	for _, field := range table.Fields {
+		if field.StructField.Tag.Get(TagAudit) != "-" {
+			columns = append(columns, field.Name)
+		}
+	}
+
+	return columns
+}
+
+// readAuditRows reads the audited columns of the rows of the model the filters select, along with the operator
+// of the statement.
+func readAuditRows(ctx context.Context, db *BunDB, model any, table *schema.Table, filters ...ApplyFunc[SelectQuery]) ([]map[string]any, error) {
+	var rows []map[string]any
+
+	if err := NewSelectQuery(db).
+		Model(model).
+		Unscoped().
+		Select(auditColumns(table)...).
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.Expr(constants.ExprOperator)
+		}, auditActorColumn).
+		Apply(filters...).
+		Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to read audited rows of %s: %w", table.Name, err)
+	}
+
+	return rows, nil
+}
+
+// readAuditRowsByKeys reads the audited rows of the model having the primary keys.
+func readAuditRowsByKeys(ctx context.Context, db *BunDB, model any, table *schema.Table, keys [][]any) ([]map[string]any, error) {
+	columns := make([]string, len(table.PKs))
+	for i, pk := range table.PKs {
+		columns[i] = pk.Name
+	}
+
+	var rows []map[string]any
+
+	for batch := range slices.Chunk(keys, cascadeBatchSize) {
+		batchRows, err := readAuditRows(ctx, db, model, table, func(query SelectQuery) {
+			// Rows soft deleted by updates are read as well
+			if table.SoftDeleteField != nil {
+				query.IncludeDeleted()
+			}
+
+			query.Where(func(cb ConditionBuilder) {
+				cb.TupleIn(columns, batch)
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, batchRows...)
+	}
+
+	return rows, nil
+}
+
+// auditModelKeys returns the primary keys of the rows of the model, leaving out the rows with zero keys.
+func auditModelKeys(table *schema.Table, model any) [][]any {
+	columns := make([]string, len(table.PKs))
+	for i, pk := range table.PKs {
+		columns[i] = pk.Name
+	}
+
+	return rowKeys(table.PKs, modelRows(table, columns, model))
+}
+
+// auditEvents builds the events of the rows changed by the operation from their versions before and after it,
+// matched by primary key. Deletes have no versions after, and inserts versions before only for the rows upserts
+// updated, which are recorded as updates.
+func auditEvents(table *schema.Table, operation AuditOperation, before, after []map[string]any) []AuditEvent {
+	at := time.Now()
+	previous := make(map[string]map[string]any, len(before))
+
+	for _, row := range before {
+		previous[auditKey(table, row)] = row
+	}
+
+	rows := after
+	if operation == AuditOperationDelete {
+		rows = before
+	}
+
+	events := make([]AuditEvent, 0, len(rows))
+
+	for _, row := range rows {
+		old := previous[auditKey(table, row)]
+
+		rowOperation := operation
+		if operation == AuditOperationInsert && old != nil {
+			rowOperation = AuditOperationUpdate
+		}
+
+		event := AuditEvent{
+			Table:     table.Name,
+			Operation: rowOperation,
+			PK:        make(map[string]any, len(table.PKs)),
+			Changes:   make(map[string]AuditChange),
+			At:        at,
+		}
+		event.Actor, _ = auditValue(row[auditActorColumn]).(string)
+
+		for _, pk := range table.PKs {
+			event.PK[pk.Name] = auditValue(row[pk.Name])
+		}
+
+		for column, value := range row {
+			if column == auditActorColumn {
+				continue
+			}
+
+			switch rowOperation {
+			case AuditOperationInsert:
+				event.Changes[column] = AuditChange{New: auditValue(value)}
+			case AuditOperationDelete:
+				event.Changes[column] = AuditChange{Old: auditValue(value)}
+			default:
+				if oldValue := auditValue(old[column]); !reflect.DeepEqual(oldValue, auditValue(value)) {
+					event.Changes[column] = AuditChange{Old: oldValue, New: auditValue(value)}
+				}
+			}
+		}
+
+		// Updates leaving the row as it was change nothing
+		if rowOperation == AuditOperationUpdate && (old == nil || len(event.Changes) == 0) {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// auditKey identifies a row by its primary key.
+func auditKey(table *schema.Table, row map[string]any) string {
+	var key strings.Builder
+	for _, pk := range table.PKs {
+		fmt.Fprintf(&key, "%v\x00", auditValue(row[pk.Name]))
+	}
+
+	return key.String()
+}
+
+// auditValue normalizes the values drivers scan, some of them returning text as bytes.
+func auditValue(value any) any {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+
+	return value
+}
+
+// writeAuditEvents writes the events of a statement run with the transaction tx to the sink: transactional sinks
+// write them with tx, other sinks once the transaction commits, logging failures as the statement succeeded then.
+func writeAuditEvents(ctx context.Context, tx DB, sink AuditSink, events []AuditEvent) error {
+	if sink == nil || len(events) == 0 {
+		return nil
+	}
+
+	if sink, ok := sink.(TransactionalAuditSink); ok {
+		if err := sink.WriteInTx(ctx, tx, events); err != nil {
+			return fmt.Errorf("failed to write %d audit events of %s: %w", len(events), events[0].Table, err)
+		}
+
+		return nil
+	}
+
+	AfterCommit(ctx, func() {
+		if err := sink.Write(ctx, events); err != nil {
+			logger.Errorf("Failed to write %d audit events of %s: %v", len(events), events[0].Table, err)
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,279 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// AuditDoc is auditable, leaving its secret out of the audit events.
+type AuditDoc struct {
+	bun.BaseModel `bun:"table:test_audit_doc,alias:tad"`
+	Model
+
+	Title  string `json:"title"  bun:"title,notnull"`
+	Secret string `json:"secret" bun:"secret,notnull" audit:"-"`
+}
+
+func (*AuditDoc) Auditable() {}
+
+// AuditPlainDoc shares the table of AuditDoc without being auditable.
+type AuditPlainDoc struct {
+	bun.BaseModel `bun:"table:test_audit_doc,alias:tad"`
+	Model
+
+	Title  string `json:"title"  bun:"title,notnull"`
+	Secret string `json:"secret" bun:"secret,notnull"`
+}
+
+// AuditTestSuite tests the audit events emitted by the changes of auditable models.
+type AuditTestSuite struct {
+	*OrmTestSuite
+
+	mu     sync.Mutex
+	events []AuditEvent
+}
+
+func (suite *AuditTestSuite) SetupSuite() {
+	bunDB := suite.getBunDB()
+
+	_, err := bunDB.NewDropTable().Model((*AuditDoc)(nil)).IfExists().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should drop existing audit test table")
+
+	_, err = bunDB.NewCreateTable().Model((*AuditDoc)(nil)).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should create audit test table")
+}
+
+func (suite *AuditTestSuite) TearDownSuite() {
+	SetAuditSink(nil)
+
+	_, err := suite.getBunDB().NewDropTable().Model((*AuditDoc)(nil)).IfExists().Exec(suite.ctx)
+	suite.NoError(err, "Should cleanup audit test table")
+}
+
+func (suite *AuditTestSuite) SetupTest() {
+	suite.events = nil
+
+	SetAuditSink(AuditSinkFunc(func(_ context.Context, events []AuditEvent) error {
+		suite.mu.Lock()
+		defer suite.mu.Unlock()
+
+		suite.events = append(suite.events, events...)
+
+		return nil
+	}))
+}
+
+func (suite *AuditTestSuite) TearDownTest() {
+	_, err := suite.db.NewDelete().Model((*AuditPlainDoc)(nil)).AllowFullTable().Exec(suite.ctx)
+	suite.NoError(err, "Should cleanup audit test rows")
+}
+
+func (suite *AuditTestSuite) takeEvents() []AuditEvent {
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+
+	events := suite.events
+	suite.events = nil
+
+	return events
+}
+
+func (suite *AuditTestSuite) insertDocs(titles ...string) []*AuditDoc {
+	docs := make([]*AuditDoc, len(titles))
+	for i, title := range titles {
+		docs[i] = &AuditDoc{Title: title, Secret: "s3cret"}
+	}
+
+	_, err := suite.db.NewInsert().Model(&docs).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert audit docs")
+
+	return docs
+}
+
+func (suite *AuditTestSuite) TestInsert() {
+	docs := suite.insertDocs("First", "Second")
+
+	events := suite.takeEvents()
+	suite.Require().Len(events, 2, "Should emit an event per inserted row")
+
+	titles := make(map[string]string, len(events))
+	for _, event := range events {
+		suite.Equal("test_audit_doc", event.Table)
+		suite.Equal(AuditOperationInsert, event.Operation)
+		suite.Equal(constants.OperatorSystem, event.Actor, "Should record the operator of the statement")
+		suite.False(event.At.IsZero(), "Should record the time of the change")
+		suite.NotContains(event.Changes, "secret", "Should leave out the columns tagged audit:\"-\"")
+		suite.Nil(event.Changes["title"].Old, "Inserts should have no old values")
+
+		titles[event.PK["id"].(string)] = event.Changes["title"].New.(string)
+	}
+
+	suite.Equal(map[string]string{docs[0].ID: "First", docs[1].ID: "Second"}, titles)
+}
+
+func (suite *AuditTestSuite) TestUpdate() {
+	docs := suite.insertDocs("Draft", "Other")
+	suite.takeEvents()
+
+	_, err := suite.db.WithNamedArg(constants.PlaceholderKeyOperator, "alice").
+		NewUpdate().
+		Model((*AuditDoc)(nil)).
+		Set("title", "Published").
+		Set("secret", "changed").
+		Where(func(cb ConditionBuilder) {
+			cb.PKEquals(docs[0].ID)
+		}).
+		Exec(suite.ctx)
+	suite.Require().NoError(err, "Should update audit doc")
+
+	events := suite.takeEvents()
+	suite.Require().Len(events, 1, "Should emit an event per updated row")
+
+	event := events[0]
+	suite.Equal(AuditOperationUpdate, event.Operation)
+	suite.Equal(map[string]any{"id": docs[0].ID}, event.PK)
+	suite.Equal("alice", event.Actor, "Should record the operator of the statement")
+	suite.Equal(AuditChange{Old: "Draft", New: "Published"}, event.Changes["title"])
+	suite.NotContains(event.Changes, "secret", "Should leave out the columns tagged audit:\"-\"")
+	suite.NotContains(event.Changes, "created_by", "Should record the changed columns only")
+}
+
+func (suite *AuditTestSuite) TestUpdateBulk() {
+	docs := suite.insertDocs("One", "Two")
+	suite.takeEvents()
+
+	docs[0].Title = "One Revised"
+
+	_, err := suite.db.NewUpdate().Model(&docs).Select("title").Bulk().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should bulk update audit docs")
+
+	events := suite.takeEvents()
+	suite.Require().Len(events, 1, "Should leave out the rows the update left as they were")
+	suite.Equal(docs[0].ID, events[0].PK["id"])
+	suite.Equal(AuditChange{Old: "One", New: "One Revised"}, events[0].Changes["title"])
+}
+
+func (suite *AuditTestSuite) TestUpsert() {
+	docs := suite.insertDocs("Original")
+	suite.takeEvents()
+
+	_, err := suite.db.NewInsert().
+		Model(&AuditDoc{Model: Model{ID: docs[0].ID}, Title: "Upserted", Secret: "s3cret"}).
+		OnConflict(func(cb ConflictBuilder) {
+			cb.Columns("id").DoUpdate().Set("title", "Upserted")
+		}).
+		Exec(suite.ctx)
+	suite.Require().NoError(err, "Should upsert audit doc")
+
+	events := suite.takeEvents()
+	suite.Require().Len(events, 1, "Should emit an event for the upserted row")
+	suite.Equal(AuditOperationUpdate, events[0].Operation, "Should record upserts of existing rows as updates")
+	suite.Equal(AuditChange{Old: "Original", New: "Upserted"}, events[0].Changes["title"])
+}
+
+func (suite *AuditTestSuite) TestDelete() {
+	docs := suite.insertDocs("Doomed", "Kept")
+	suite.takeEvents()
+
+	_, err := suite.db.NewDelete().
+		Model((*AuditDoc)(nil)).
+		Where(func(cb ConditionBuilder) {
+			cb.PKEquals(docs[0].ID)
+		}).
+		Exec(suite.ctx)
+	suite.Require().NoError(err, "Should delete audit doc")
+
+	events := suite.takeEvents()
+	suite.Require().Len(events, 1, "Should emit an event per deleted row")
+	suite.Equal(AuditOperationDelete, events[0].Operation)
+	suite.Equal(docs[0].ID, events[0].PK["id"])
+	suite.Equal(AuditChange{Old: "Doomed"}, events[0].Changes["title"], "Deletes should have no new values")
+}
+
+func (suite *AuditTestSuite) TestNotAuditable() {
+	_, err := suite.db.NewInsert().Model(&AuditPlainDoc{Title: "Plain", Secret: "s3cret"}).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert plain doc")
+
+	suite.Empty(suite.takeEvents(), "Should emit no events for models not auditable")
+}
+
+func (suite *AuditTestSuite) TestSinkError() {
+	SetAuditSink(AuditSinkFunc(func(context.Context, []AuditEvent) error {
+		return errors.New("sink unavailable")
+	}))
+
+	suite.insertDocs("Unaudited")
+
+	count, err := suite.db.NewSelect().Model((*AuditDoc)(nil)).Count(suite.ctx)
+	suite.Require().NoError(err, "Should count audit docs")
+	suite.Equal(int64(1), count, "Should keep the changes when the sink fails")
+}
+
+func (suite *AuditTestSuite) TestTransaction() {
+	errRollback := errors.New("rollback")
+
+	err := suite.db.RunInTX(suite.ctx, func(ctx context.Context, tx DB) error {
+		_, err := tx.NewInsert().Model(&AuditDoc{Title: "Committed", Secret: "s3cret"}).Exec(ctx)
+		suite.Require().NoError(err, "Should insert audit doc")
+		suite.Empty(suite.takeEvents(), "Should not write the events before the transaction commits")
+
+		return nil
+	})
+	suite.Require().NoError(err)
+	suite.Len(suite.takeEvents(), 1, "Should write the events once the transaction commits")
+
+	err = suite.db.RunInTX(suite.ctx, func(ctx context.Context, tx DB) error {
+		_, err := tx.NewInsert().Model(&AuditDoc{Title: "RolledBack", Secret: "s3cret"}).Exec(ctx)
+		suite.Require().NoError(err, "Should insert audit doc")
+
+		return errRollback
+	})
+	suite.Require().ErrorIs(err, errRollback)
+	suite.Empty(suite.takeEvents(), "Should not write the events of rolled back changes")
+}
+
+// transactionalSink records the events written with the transaction of the statement.
+type transactionalSink struct {
+	AuditSinkFunc
+
+	err    error
+	events []AuditEvent
+}
+
+func (s *transactionalSink) WriteInTx(ctx context.Context, tx DB, events []AuditEvent) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if _, ok := tx.(*BunDB).db.(bun.Tx); !ok {
+		return errors.New("not written with a transaction")
+	}
+
+	s.events = append(s.events, events...)
+
+	return nil
+}
+
+func (suite *AuditTestSuite) TestTransactionalSink() {
+	sink := &transactionalSink{AuditSinkFunc: func(context.Context, []AuditEvent) error {
+		return errors.New("should write with the transaction")
+	}}
+	SetAuditSink(sink)
+
+	suite.insertDocs("Written")
+	suite.Len(sink.events, 1, "Should write the events with the transaction of the statement")
+
+	sink.err = errors.New("sink unavailable")
+
+	_, err := suite.db.NewInsert().Model(&AuditDoc{Title: "Unaudited", Secret: "s3cret"}).Exec(suite.ctx)
+	suite.ErrorIs(err, sink.err, "Should fail the statement when the transactional sink fails")
+
+	count, err := suite.db.NewSelect().Model((*AuditDoc)(nil)).Count(suite.ctx)
+	suite.Require().NoError(err, "Should count audit docs")
+	suite.Equal(int64(1), count, "Should roll back the changes the sink failed to audit")
+}
@@ -209,6 +209,10 @@ type ConditionBuilder interface {
 	EqualsExpr(column string, builder func(ExprBuilder) any) ConditionBuilder
 	// OrEqualsExpr is a condition that checks if a column is equal to an expression.
 	OrEqualsExpr(column string, builder func(ExprBuilder) any) ConditionBuilder
+	// EqualsNullSafe is a condition that checks if a column is equal to a value, treating NULLs as equal to each other.
+	EqualsNullSafe(column string, value any) ConditionBuilder
+	// OrEqualsNullSafe is a condition that checks if a column is equal to a value, treating NULLs as equal to each other.
+	OrEqualsNullSafe(column string, value any) ConditionBuilder
 	// NotEquals is a condition that checks if a column is not equal to a value.
 	NotEquals(column string, value any) ConditionBuilder
 	// OrNotEquals is a condition that checks if a column is not equal to a value.
@@ -349,6 +353,23 @@ type ConditionBuilder interface {
 	In(column string, values any) ConditionBuilder
 	// OrIn is a condition that checks if a column is in a list of values.
 	OrIn(column string, values any) ConditionBuilder
+	// EqualsAnyOf is a condition that checks if a column equals any of the values of a slice. On Postgres the slice
+	// binds as a single array, "= ANY(?)", which keeps the shape of the SQL the same for any number of values and
+	// suits large sets of IDs; elsewhere it expands into an IN list.
+	EqualsAnyOf(column string, values any) ConditionBuilder
+	// OrEqualsAnyOf is a condition that checks if a column equals any of the values of a slice, see EqualsAnyOf.
+	OrEqualsAnyOf(column string, values any) ConditionBuilder
+	// NotEqualsAllOf is a condition that checks if a column differs from all the values of a slice, "<> ALL(?)"
+	// on Postgres and NOT IN elsewhere, see EqualsAnyOf.
+	NotEqualsAllOf(column string, values any) ConditionBuilder
+	// OrNotEqualsAllOf is a condition that checks if a column differs from all the values of a slice, see NotEqualsAllOf.
+	OrNotEqualsAllOf(column string, values any) ConditionBuilder
+	// InLarge is a condition that checks if a column is in a slice of values that may hold thousands of IDs. Up to
+	// the threshold set by SetInLargeThreshold it is In; larger slices are joined as a VALUES table on MySQL and
+	// SQLite and bound as a single array on Postgres instead of expanding into a huge IN list.
+	InLarge(column string, values any) ConditionBuilder
+	// OrInLarge is a condition that checks if a column is in a slice of values that may hold thousands of IDs, see InLarge.
+	OrInLarge(column string, values any) ConditionBuilder
 	// InSubQuery is a condition that checks if a column is in a subquery.
 	InSubQuery(column string, builder func(query SelectQuery)) ConditionBuilder
 	// OrInSubQuery is a condition that checks if a column is in a subquery.
@@ -369,6 +390,53 @@ type ConditionBuilder interface {
 	NotInExpr(column string, builder func(ExprBuilder) any) ConditionBuilder
 	// OrNotInExpr is a condition that checks if a column is not in an expression.
 	OrNotInExpr(column string, builder func(ExprBuilder) any) ConditionBuilder
+	// HasRelated is a condition that checks if the row has rows of the named relation of the model satisfying the
+	// condition, a correlated EXISTS subquery on the related model, e.g. users that have published posts.
+	// Many-to-many relations are followed through their junction table. The condition may be nil.
+	HasRelated(relation string, builder func(ConditionBuilder)) ConditionBuilder
+	// OrHasRelated is a condition that checks if the row has rows of the named relation satisfying the condition.
+	OrHasRelated(relation string, builder func(ConditionBuilder)) ConditionBuilder
+	// HasNoRelated is a condition that checks if the row has no rows of the named relation of the model satisfying
+	// the condition, a correlated NOT EXISTS subquery, see HasRelated.
+	HasNoRelated(relation string, builder func(ConditionBuilder)) ConditionBuilder
+	// OrHasNoRelated is a condition that checks if the row has no rows of the named relation satisfying the condition.
+	OrHasNoRelated(relation string, builder func(ConditionBuilder)) ConditionBuilder
+	// TupleIn is a condition that checks if a tuple of columns is in a list of value tuples.
+	TupleIn(columns []string, values [][]any) ConditionBuilder
+	// OrTupleIn is a condition that checks if a tuple of columns is in a list of value tuples.
+	OrTupleIn(columns []string, values [][]any) ConditionBuilder
+	// TupleNotIn is a condition that checks if a tuple of columns is not in a list of value tuples.
+	TupleNotIn(columns []string, values [][]any) ConditionBuilder
+	// OrTupleNotIn is a condition that checks if a tuple of columns is not in a list of value tuples.
+	OrTupleNotIn(columns []string, values [][]any) ConditionBuilder
+	// TupleGreaterThan is a condition that checks if a tuple of columns is greater than a value tuple.
+	TupleGreaterThan(columns []string, values []any) ConditionBuilder
+	// OrTupleGreaterThan is a condition that checks if a tuple of columns is greater than a value tuple.
+	OrTupleGreaterThan(columns []string, values []any) ConditionBuilder
+	// TupleGreaterThanOrEqual is a condition that checks if a tuple of columns is greater than or equal to a value tuple.
+	TupleGreaterThanOrEqual(columns []string, values []any) ConditionBuilder
+	// OrTupleGreaterThanOrEqual is a condition that checks if a tuple of columns is greater than or equal to a value tuple.
+	OrTupleGreaterThanOrEqual(columns []string, values []any) ConditionBuilder
+	// TupleLessThan is a condition that checks if a tuple of columns is less than a value tuple.
+	TupleLessThan(columns []string, values []any) ConditionBuilder
+	// OrTupleLessThan is a condition that checks if a tuple of columns is less than a value tuple.
+	OrTupleLessThan(columns []string, values []any) ConditionBuilder
+	// TupleLessThanOrEqual is a condition that checks if a tuple of columns is less than or equal to a value tuple.
+	TupleLessThanOrEqual(columns []string, values []any) ConditionBuilder
+	// OrTupleLessThanOrEqual is a condition that checks if a tuple of columns is less than or equal to a value tuple.
+	OrTupleLessThanOrEqual(columns []string, values []any) ConditionBuilder
+	// HasFlag is a condition that checks if all bits of flag are set in an integer column.
+	HasFlag(column string, flag any) ConditionBuilder
+	// OrHasFlag is a condition that checks if all bits of flag are set in an integer column.
+	OrHasFlag(column string, flag any) ConditionBuilder
+	// NotHasFlag is a condition that checks if not all bits of flag are set in an integer column.
+	NotHasFlag(column string, flag any) ConditionBuilder
+	// OrNotHasFlag is a condition that checks if not all bits of flag are set in an integer column.
+	OrNotHasFlag(column string, flag any) ConditionBuilder
+	// HasAnyFlag is a condition that checks if any bit of flag is set in an integer column.
+	HasAnyFlag(column string, flag any) ConditionBuilder
+	// OrHasAnyFlag is a condition that checks if any bit of flag is set in an integer column.
+	OrHasAnyFlag(column string, flag any) ConditionBuilder
 	// IsNull is a condition that checks if a column is null.
 	IsNull(column string) ConditionBuilder
 	// OrIsNull is a condition that checks if a column is null.
@@ -417,6 +485,14 @@ type ConditionBuilder interface {
 	IsFalseExpr(builder func(ExprBuilder) any) ConditionBuilder
 	// OrIsFalseExpr is a condition that checks if a column is false.
 	OrIsFalseExpr(builder func(ExprBuilder) any) ConditionBuilder
+	// EqualsIgnoreCase is a condition that checks if a column is equal to a value, ignoring case.
+	EqualsIgnoreCase(column, value string) ConditionBuilder
+	// OrEqualsIgnoreCase is a condition that checks if a column is equal to a value, ignoring case.
+	OrEqualsIgnoreCase(column, value string) ConditionBuilder
+	// InIgnoreCase is a condition that checks if a column is in a list of values, ignoring case.
+	InIgnoreCase(column string, values []string) ConditionBuilder
+	// OrInIgnoreCase is a condition that checks if a column is in a list of values, ignoring case.
+	OrInIgnoreCase(column string, values []string) ConditionBuilder
 	// Contains is a condition that checks if a column contains a value.
 	Contains(column, value string) ConditionBuilder
 	// OrContains is a condition that checks if a column contains a value.
@@ -513,6 +589,46 @@ type ConditionBuilder interface {
 	NotEndsWithAnyIgnoreCase(column string, values []string) ConditionBuilder
 	// OrNotEndsWithAnyIgnoreCase is a condition that checks if a column does not end with any of the values, ignoring case.
 	OrNotEndsWithAnyIgnoreCase(column string, values []string) ConditionBuilder
+	// Matches is a condition that checks if a column matches a regular expression (case-sensitive).
+	// It maps to ~ on Postgres, REGEXP_LIKE on MySQL, Oracle and SQL Server and REGEXP on SQLite;
+	// patterns should stick to the syntax shared by the databases in use.
+	Matches(column, pattern string) ConditionBuilder
+	// OrMatches is a condition that checks if a column matches a regular expression (case-sensitive).
+	OrMatches(column, pattern string) ConditionBuilder
+	// MatchesIgnoreCase is a condition that checks if a column matches a regular expression, ignoring case.
+	MatchesIgnoreCase(column, pattern string) ConditionBuilder
+	// OrMatchesIgnoreCase is a condition that checks if a column matches a regular expression, ignoring case.
+	OrMatchesIgnoreCase(column, pattern string) ConditionBuilder
+	// NotMatches is a condition that checks if a column does not match a regular expression (case-sensitive).
+	NotMatches(column, pattern string) ConditionBuilder
+	// OrNotMatches is a condition that checks if a column does not match a regular expression (case-sensitive).
+	OrNotMatches(column, pattern string) ConditionBuilder
+	// NotMatchesIgnoreCase is a condition that checks if a column does not match a regular expression, ignoring case.
+	NotMatchesIgnoreCase(column, pattern string) ConditionBuilder
+	// OrNotMatchesIgnoreCase is a condition that checks if a column does not match a regular expression, ignoring case.
+	OrNotMatchesIgnoreCase(column, pattern string) ConditionBuilder
+	// ArrayContains is a condition that checks if the array in a column contains all the values, see ExprBuilder.ArrayContains.
+	ArrayContains(column string, values any) ConditionBuilder
+	// OrArrayContains is a condition that checks if the array in a column contains all the values, see ExprBuilder.ArrayContains.
+	OrArrayContains(column string, values any) ConditionBuilder
+	// ArrayOverlaps is a condition that checks if the array in a column contains any of the values, see ExprBuilder.ArrayOverlaps.
+	ArrayOverlaps(column string, values any) ConditionBuilder
+	// OrArrayOverlaps is a condition that checks if the array in a column contains any of the values, see ExprBuilder.ArrayOverlaps.
+	OrArrayOverlaps(column string, values any) ConditionBuilder
+	// ArrayLength is a condition that checks if the array in a column has the given number of elements.
+	ArrayLength(column string, length int) ConditionBuilder
+	// OrArrayLength is a condition that checks if the array in a column has the given number of elements.
+	OrArrayLength(column string, length int) ConditionBuilder
+	// MatchesFullText is a condition that checks if a column matches a full-text search query, see ExprBuilder.Match.
+	MatchesFullText(column, query string) ConditionBuilder
+	// OrMatchesFullText is a condition that checks if a column matches a full-text search query, see ExprBuilder.Match.
+	OrMatchesFullText(column, query string) ConditionBuilder
+	// GeoNear is a condition that checks if the geometry in a column lies within the radius in meters of the point
+	// at the latitude and longitude, see ExprBuilder.StDistance.
+	GeoNear(column string, lat, lng, radius float64) ConditionBuilder
+	// OrGeoNear is a condition that checks if the geometry in a column lies within the radius in meters of the point
+	// at the latitude and longitude, see ExprBuilder.StDistance.
+	OrGeoNear(column string, lat, lng, radius float64) ConditionBuilder
 	// Expr is a condition that checks if an expression is true.
 	Expr(builder func(ExprBuilder) any) ConditionBuilder
 	// OrExpr is a condition that checks if an expression is true.
@@ -521,4 +637,8 @@ type ConditionBuilder interface {
 	Group(builder func(ConditionBuilder)) ConditionBuilder
 	// OrGroup is a condition that checks if a group of conditions are true.
 	OrGroup(builder func(ConditionBuilder)) ConditionBuilder
+	// NotGroup is a condition that checks if a group of conditions are not all true.
+	NotGroup(builder func(ConditionBuilder)) ConditionBuilder
+	// OrNotGroup is a condition that checks if a group of conditions are not all true.
+	OrNotGroup(builder func(ConditionBuilder)) ConditionBuilder
 }
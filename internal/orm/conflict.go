@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/uptrace/bun"
@@ -43,8 +44,17 @@ type InsertQueryConflictBuilder struct {
 	// action
 	action ConflictAction
 	// updates
-	sets        []schema.QueryAppender
+	sets        []conflictSet
 	updateWhere schema.QueryAppender
+	// guard restricting the updates to the existing rows of a tenant
+	guardColumn string
+	guardValue  any
+}
+
+// conflictSet is an assignment of the DO UPDATE clause.
+type conflictSet struct {
+	column string
+	value  any
 }
 
 func newConflictBuilder(qb QueryBuilder) *InsertQueryConflictBuilder {
@@ -97,7 +107,7 @@ func (b *InsertQueryConflictUpdateBuilder) Set(column string, value ...any) Conf
 				valueExpr = b.parent.eb.Expr("EXCLUDED.?", bun.Name(column))
 			},
 			MySQL: func() {
-				valueExpr = bun.Name(column)
+				valueExpr = bun.SafeQuery("VALUES(?)", bun.Name(column))
 			},
 			SQLite: func() {
 				valueExpr = b.parent.eb.Expr("EXCLUDED.?", bun.Name(column))
@@ -105,38 +115,13 @@ func (b *InsertQueryConflictUpdateBuilder) Set(column string, value ...any) Conf
 		})
 	}
 
-	setExpr := b.parent.eb.ExprByDialect(DialectExprs{
-		Postgres: func() schema.QueryAppender {
-			return b.parent.eb.Expr("? = ?", bun.Name(column), valueExpr)
-		},
-		MySQL: func() schema.QueryAppender {
-			return bun.SafeQuery("? = ?", bun.Name(column), valueExpr)
-		},
-		SQLite: func() schema.QueryAppender {
-			return bun.SafeQuery("? = ?", bun.Name(column), valueExpr)
-		},
-	})
-
-	b.parent.sets = append(b.parent.sets, setExpr)
+	b.parent.sets = append(b.parent.sets, conflictSet{column: column, value: valueExpr})
 
 	return b
 }
 
 func (b *InsertQueryConflictUpdateBuilder) SetExpr(column string, builder func(ExprBuilder) any) ConflictUpdateBuilder {
-	valueExpr := builder(b.parent.eb)
-	setExpr := b.parent.eb.ExprByDialect(DialectExprs{
-		Postgres: func() schema.QueryAppender {
-			return b.parent.eb.Expr("? = ?", bun.Name(column), valueExpr)
-		},
-		MySQL: func() schema.QueryAppender {
-			return b.parent.eb.Expr("? = ?", bun.Name(column), valueExpr)
-		},
-		SQLite: func() schema.QueryAppender {
-			return b.parent.eb.Expr("? = ?", bun.Name(column), valueExpr)
-		},
-	})
-
-	b.parent.sets = append(b.parent.sets, setExpr)
+	b.parent.sets = append(b.parent.sets, conflictSet{column: column, value: builder(b.parent.eb)})
 
 	return b
 }
@@ -147,6 +132,20 @@ func (b *InsertQueryConflictUpdateBuilder) Where(builder func(ConditionBuilder))
 	return b
 }
 
+// guardTenant restricts the updates to the existing rows whose column holds the tenant and keeps the column from
+// being updated, so a conflicting row of another tenant is neither overwritten nor moved to the tenant.
+func (b *InsertQueryConflictBuilder) guardTenant(column, tenantID string) {
+	b.sets = slices.DeleteFunc(b.sets, func(set conflictSet) bool {
+		return set.column == column
+	})
+	b.guardColumn = column
+	b.guardValue = tenantID
+
+	if b.action == ConflictDoUpdate && len(b.sets) == 0 {
+		b.action = ConflictDoNothing
+	}
+}
+
 // build applies the configured conflict handling to the underlying bun.InsertQuery.
 func (b *InsertQueryConflictBuilder) build(query *bun.InsertQuery) {
 	// Dialect specific handling
@@ -162,10 +161,18 @@ func (b *InsertQueryConflictBuilder) build(query *bun.InsertQuery) {
 			// Otherwise treat as DO UPDATE
 			query.On("DUPLICATE KEY UPDATE")
 
+			// MySQL has no DO UPDATE WHERE; ignore updateWhere/targetWhere and guard each assignment instead,
+			// the guard column holding the value of the existing row as it is never assigned
 			for _, set := range b.sets {
-				query.Set("?", set)
+				if b.guardColumn != constants.Empty {
+					query.Set(
+						"? = IF(? = ?, ?, ?)",
+						bun.Name(set.column), bun.Name(b.guardColumn), b.guardValue, set.value, bun.Name(set.column),
+					)
+				} else {
+					query.Set("? = ?", bun.Name(set.column), set.value)
+				}
 			}
-			// MySQL has no DO UPDATE WHERE; ignore updateWhere/targetWhere
 		},
 		Default: func() {
 			// PostgreSQL/SQLite
@@ -208,12 +215,16 @@ func (b *InsertQueryConflictBuilder) build(query *bun.InsertQuery) {
 			query.On(builder.String(), args...)
 
 			for _, set := range b.sets {
-				query.Set("?", set)
+				query.Set("? = ?", bun.Name(set.column), set.value)
 			}
 
 			if b.updateWhere != nil {
 				query.Where("?", b.updateWhere)
 			}
+
+			if b.guardColumn != constants.Empty && b.action == ConflictDoUpdate {
+				query.Where("?TableAlias.? = ?", bun.Name(b.guardColumn), b.guardValue)
+			}
 		},
 	})
 }
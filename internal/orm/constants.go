@@ -5,4 +5,10 @@ const (
 	sqlNull      = "NULL"  // sqlNull is the constant for the NULL value
 	separatorAnd = " AND " // separatorAnd is the separator for the AND condition
 	separatorOr  = " OR "  // separatorOr is the separator for the OR condition
+
+	defaultOverflowIndicator = "..." // defaultOverflowIndicator ends strings truncated by StringAgg
+
+	qualifyAlias = "qualified" // qualifyAlias aliases the derived table of Qualify and emulated FULL JOINs for queries without a model
+
+	groupRowNumberAlias = "group_row_number" // groupRowNumberAlias aliases the row number TopNPerGroup filters on
 )
@@ -1,9 +1,11 @@
 package orm
 
 import (
+	"reflect"
 	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/schema"
 
 	"github.com/ilxqx/vef-framework-go/constants"
@@ -107,6 +109,33 @@ func (cb *CriteriaBuilder) OrEqualsExpr(column string, builder func(ExprBuilder)
 	return cb
 }
 
+func (cb *CriteriaBuilder) EqualsNullSafe(column string, value any) ConditionBuilder {
+	cb.and("?", cb.nullSafeEquals(column, value))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrEqualsNullSafe(column string, value any) ConditionBuilder {
+	cb.or("?", cb.nullSafeEquals(column, value))
+
+	return cb
+}
+
+// nullSafeEquals compares the column with the value, matching when both are NULL.
+func (cb *CriteriaBuilder) nullSafeEquals(column string, value any) schema.QueryAppender {
+	return cb.eb.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return cb.eb.Expr("? IS NOT DISTINCT FROM ?", cb.eb.Column(column), value)
+		},
+		MySQL: func() schema.QueryAppender {
+			return cb.eb.Expr("? <=> ?", cb.eb.Column(column), value)
+		},
+		Default: func() schema.QueryAppender {
+			return cb.eb.Expr("(? = ? OR (? IS NULL AND ? IS NULL))", cb.eb.Column(column), value, cb.eb.Column(column), value)
+		},
+	})
+}
+
 func (cb *CriteriaBuilder) NotEquals(column string, value any) ConditionBuilder {
 	cb.and("? <> ?", cb.eb.Column(column), value)
 
@@ -527,6 +556,64 @@ func (cb *CriteriaBuilder) OrIn(column string, values any) ConditionBuilder {
 	return cb
 }
 
+func (cb *CriteriaBuilder) EqualsAnyOf(column string, values any) ConditionBuilder {
+	cb.and("?", cb.compareArray(column, values, "= ANY", "IN"))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrEqualsAnyOf(column string, values any) ConditionBuilder {
+	cb.or("?", cb.compareArray(column, values, "= ANY", "IN"))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) NotEqualsAllOf(column string, values any) ConditionBuilder {
+	cb.and("?", cb.compareArray(column, values, "<> ALL", "NOT IN"))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrNotEqualsAllOf(column string, values any) ConditionBuilder {
+	cb.or("?", cb.compareArray(column, values, "<> ALL", "NOT IN"))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) InLarge(column string, values any) ConditionBuilder {
+	cb.and("?", cb.inLarge(column, values))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrInLarge(column string, values any) ConditionBuilder {
+	cb.or("?", cb.inLarge(column, values))
+
+	return cb
+}
+
+// compareArray compares the column with the values bound as a single array on Postgres, so the shape of the SQL
+// stays the same whatever the number of values, and expanded into an IN list by the list operator elsewhere, where no values
+// compare as an empty array would: matching no rows for IN and all rows for NOT IN.
+func (cb *CriteriaBuilder) compareArray(column string, values any, arrayOp, listOp string) schema.QueryAppender {
+	return cb.eb.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return cb.eb.Expr("? "+arrayOp+" (?)", cb.eb.Column(column), pgdialect.Array(values))
+		},
+		Default: func() schema.QueryAppender {
+			if value := reflect.ValueOf(values); (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && value.Len() == 0 {
+				if listOp == "IN" {
+					return cb.eb.Expr("1 = 0")
+				}
+
+				return cb.eb.Expr("1 = 1")
+			}
+
+			return cb.eb.Expr("? "+listOp+" (?)", cb.eb.Column(column), bun.In(values))
+		},
+	})
+}
+
 func (cb *CriteriaBuilder) InSubQuery(column string, builder func(query SelectQuery)) ConditionBuilder {
 	cb.and("? IN (?)", cb.eb.Column(column), cb.qb.BuildSubQuery(builder))
 
@@ -587,6 +674,195 @@ func (cb *CriteriaBuilder) OrNotInExpr(column string, builder func(ExprBuilder)
 	return cb
 }
 
+func (cb *CriteriaBuilder) HasRelated(relation string, builder func(ConditionBuilder)) ConditionBuilder {
+	cb.and("EXISTS (?)", cb.relatedSubQuery("HasRelated", relation, builder))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrHasRelated(relation string, builder func(ConditionBuilder)) ConditionBuilder {
+	cb.or("EXISTS (?)", cb.relatedSubQuery("OrHasRelated", relation, builder))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) HasNoRelated(relation string, builder func(ConditionBuilder)) ConditionBuilder {
+	cb.and("NOT EXISTS (?)", cb.relatedSubQuery("HasNoRelated", relation, builder))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrHasNoRelated(relation string, builder func(ConditionBuilder)) ConditionBuilder {
+	cb.or("NOT EXISTS (?)", cb.relatedSubQuery("OrHasNoRelated", relation, builder))
+
+	return cb
+}
+
+// relatedSubQuery builds the subquery selecting the rows of the relation of the model related to the row of the
+// enclosing query and satisfying the condition, joining the junction table of many-to-many relations.
+func (cb *CriteriaBuilder) relatedSubQuery(method, relation string, builder func(ConditionBuilder)) *bun.SelectQuery {
+	table := cb.qb.GetTable()
+	if table == nil {
+		logger.Panicf("%s: relation %q requires a query with a model", method, relation)
+	}
+
+	rel, ok := table.Relations[relation]
+	if !ok {
+		logger.Panicf("%s: model %s has no relation %q", method, table.TypeName, relation)
+	}
+
+	return cb.qb.BuildSubQuery(func(query SelectQuery) {
+		alias := rel.JoinTable.Alias
+		if rel.JoinTable == table {
+			// Rows of self relations are aliased apart from the rows of the enclosing query
+			alias = rel.Field.Name
+			query.Table(rel.JoinTable.Name, alias)
+		} else {
+			query.Model(reflect.New(rel.JoinTable.Type).Interface())
+		}
+
+		query.SelectExpr(func(eb ExprBuilder) any {
+			return eb.Expr("1")
+		})
+
+		// Columns of the enclosing row are compared with the related row, or with the junction row
+		outerAlias, outerPKs := alias, rel.JoinPKs
+		if rel.Type == schema.ManyToManyRelation {
+			outerAlias, outerPKs = rel.M2MTable.Alias, rel.M2MBasePKs
+
+			query.JoinTable(rel.M2MTable.Name, func(cb ConditionBuilder) {
+				for i, pk := range rel.JoinPKs {
+					cb.EqualsColumn(outerAlias+constants.Dot+rel.M2MJoinPKs[i].Name, alias+constants.Dot+pk.Name)
+				}
+			}, outerAlias)
+		}
+
+		query.Where(func(cb ConditionBuilder) {
+			for i, pk := range rel.BasePKs {
+				cb.EqualsExpr(outerAlias+constants.Dot+outerPKs[i].Name, func(ExprBuilder) any {
+					return query.OuterColumn(pk.Name)
+				})
+			}
+
+			if rel.PolymorphicField != nil {
+				cb.Equals(alias+constants.Dot+rel.PolymorphicField.Name, rel.PolymorphicValue)
+			}
+
+			if builder != nil {
+				cb.Group(builder)
+			}
+		})
+	})
+}
+
+func (cb *CriteriaBuilder) TupleIn(columns []string, values [][]any) ConditionBuilder {
+	cb.and("?", buildTupleIn(cb.eb, columns, values, false))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrTupleIn(columns []string, values [][]any) ConditionBuilder {
+	cb.or("?", buildTupleIn(cb.eb, columns, values, false))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) TupleNotIn(columns []string, values [][]any) ConditionBuilder {
+	cb.and("?", buildTupleIn(cb.eb, columns, values, true))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrTupleNotIn(columns []string, values [][]any) ConditionBuilder {
+	cb.or("?", buildTupleIn(cb.eb, columns, values, true))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) TupleGreaterThan(columns []string, values []any) ConditionBuilder {
+	cb.and("?", buildTupleComparison(cb.eb, columns, values, tupleGreaterThan))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrTupleGreaterThan(columns []string, values []any) ConditionBuilder {
+	cb.or("?", buildTupleComparison(cb.eb, columns, values, tupleGreaterThan))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) TupleGreaterThanOrEqual(columns []string, values []any) ConditionBuilder {
+	cb.and("?", buildTupleComparison(cb.eb, columns, values, tupleGreaterThanOrEqual))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrTupleGreaterThanOrEqual(columns []string, values []any) ConditionBuilder {
+	cb.or("?", buildTupleComparison(cb.eb, columns, values, tupleGreaterThanOrEqual))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) TupleLessThan(columns []string, values []any) ConditionBuilder {
+	cb.and("?", buildTupleComparison(cb.eb, columns, values, tupleLessThan))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrTupleLessThan(columns []string, values []any) ConditionBuilder {
+	cb.or("?", buildTupleComparison(cb.eb, columns, values, tupleLessThan))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) TupleLessThanOrEqual(columns []string, values []any) ConditionBuilder {
+	cb.and("?", buildTupleComparison(cb.eb, columns, values, tupleLessThanOrEqual))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrTupleLessThanOrEqual(columns []string, values []any) ConditionBuilder {
+	cb.or("?", buildTupleComparison(cb.eb, columns, values, tupleLessThanOrEqual))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) HasFlag(column string, flag any) ConditionBuilder {
+	cb.and("?", cb.eb.HasFlag(cb.eb.Column(column), flag))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrHasFlag(column string, flag any) ConditionBuilder {
+	cb.or("?", cb.eb.HasFlag(cb.eb.Column(column), flag))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) NotHasFlag(column string, flag any) ConditionBuilder {
+	cb.and("?", cb.eb.Not(cb.eb.HasFlag(cb.eb.Column(column), flag)))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrNotHasFlag(column string, flag any) ConditionBuilder {
+	cb.or("?", cb.eb.Not(cb.eb.HasFlag(cb.eb.Column(column), flag)))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) HasAnyFlag(column string, flag any) ConditionBuilder {
+	cb.and("? <> 0", cb.eb.BitwiseAnd(cb.eb.Column(column), flag))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrHasAnyFlag(column string, flag any) ConditionBuilder {
+	cb.or("? <> 0", cb.eb.BitwiseAnd(cb.eb.Column(column), flag))
+
+	return cb
+}
+
 func (cb *CriteriaBuilder) IsNull(column string) ConditionBuilder {
 	cb.and("? IS NULL", cb.eb.Column(column))
 
@@ -733,6 +1009,30 @@ func (cb *CriteriaBuilder) OrIsFalseExpr(builder func(ExprBuilder) any) Conditio
 	return cb
 }
 
+func (cb *CriteriaBuilder) EqualsIgnoreCase(column, value string) ConditionBuilder {
+	cb.and("?", cb.buildEqualsIgnoreCase(column, value))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrEqualsIgnoreCase(column, value string) ConditionBuilder {
+	cb.or("?", cb.buildEqualsIgnoreCase(column, value))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) InIgnoreCase(column string, values []string) ConditionBuilder {
+	cb.and("?", cb.buildInIgnoreCase(column, values))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrInIgnoreCase(column string, values []string) ConditionBuilder {
+	cb.or("?", cb.buildInIgnoreCase(column, values))
+
+	return cb
+}
+
 func (cb *CriteriaBuilder) Contains(column, value string) ConditionBuilder {
 	cb.and("? LIKE ?", cb.eb.Column(column), FuzzyContains.BuildPattern(value))
 
@@ -1262,6 +1562,161 @@ func (cb *CriteriaBuilder) OrNotEndsWithAnyIgnoreCase(column string, values []st
 	return cb
 }
 
+func (cb *CriteriaBuilder) Matches(column, pattern string) ConditionBuilder {
+	cb.and("?", cb.regexp(column, pattern, false))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrMatches(column, pattern string) ConditionBuilder {
+	cb.or("?", cb.regexp(column, pattern, false))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) MatchesIgnoreCase(column, pattern string) ConditionBuilder {
+	cb.and("?", cb.regexp(column, pattern, true))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrMatchesIgnoreCase(column, pattern string) ConditionBuilder {
+	cb.or("?", cb.regexp(column, pattern, true))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) NotMatches(column, pattern string) ConditionBuilder {
+	cb.and("NOT (?)", cb.regexp(column, pattern, false))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrNotMatches(column, pattern string) ConditionBuilder {
+	cb.or("NOT (?)", cb.regexp(column, pattern, false))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) NotMatchesIgnoreCase(column, pattern string) ConditionBuilder {
+	cb.and("NOT (?)", cb.regexp(column, pattern, true))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrNotMatchesIgnoreCase(column, pattern string) ConditionBuilder {
+	cb.or("NOT (?)", cb.regexp(column, pattern, true))
+
+	return cb
+}
+
+// regexp builds the regular expression match of a column for the current dialect.
+// SQLite delegates REGEXP to Go regular expressions (see the sqlite provider), where (?i) ignores case.
+func (cb *CriteriaBuilder) regexp(column, pattern string, ignoreCase bool) schema.QueryAppender {
+	return cb.eb.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			if ignoreCase {
+				return cb.eb.Expr("? ~* ?", cb.eb.Column(column), pattern)
+			}
+
+			return cb.eb.Expr("? ~ ?", cb.eb.Column(column), pattern)
+		},
+		SQLite: func() schema.QueryAppender {
+			if ignoreCase {
+				return cb.eb.Expr("? REGEXP ?", cb.eb.Column(column), "(?i)"+pattern)
+			}
+
+			return cb.eb.Expr("? REGEXP ?", cb.eb.Column(column), pattern)
+		},
+		Default: func() schema.QueryAppender {
+			// The match type overrides the collation, which makes REGEXP case-insensitive on MySQL by default
+			if ignoreCase {
+				return cb.eb.Expr("REGEXP_LIKE(?, ?, 'i')", cb.eb.Column(column), pattern)
+			}
+
+			return cb.eb.Expr("REGEXP_LIKE(?, ?, 'c')", cb.eb.Column(column), pattern)
+		},
+	})
+}
+
+func (cb *CriteriaBuilder) ArrayContains(column string, values any) ConditionBuilder {
+	cb.and("?", cb.eb.ArrayContains(cb.eb.Column(column), values))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrArrayContains(column string, values any) ConditionBuilder {
+	cb.or("?", cb.eb.ArrayContains(cb.eb.Column(column), values))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) ArrayOverlaps(column string, values any) ConditionBuilder {
+	cb.and("?", cb.eb.ArrayOverlaps(cb.eb.Column(column), values))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrArrayOverlaps(column string, values any) ConditionBuilder {
+	cb.or("?", cb.eb.ArrayOverlaps(cb.eb.Column(column), values))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) ArrayLength(column string, length int) ConditionBuilder {
+	cb.and("? = ?", cb.eb.ArrayLength(cb.eb.Column(column)), length)
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrArrayLength(column string, length int) ConditionBuilder {
+	cb.or("? = ?", cb.eb.ArrayLength(cb.eb.Column(column)), length)
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) MatchesFullText(column, query string) ConditionBuilder {
+	cb.and("?", cb.eb.Match(cb.eb.Column(column), query))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrMatchesFullText(column, query string) ConditionBuilder {
+	cb.or("?", cb.eb.Match(cb.eb.Column(column), query))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) GeoNear(column string, lat, lng, radius float64) ConditionBuilder {
+	cb.and("?", cb.geoNear(column, lat, lng, radius))
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrGeoNear(column string, lat, lng, radius float64) ConditionBuilder {
+	cb.or("?", cb.geoNear(column, lat, lng, radius))
+
+	return cb
+}
+
+// geoNear checks if the geometry in the column lies within the radius in meters of the point at the latitude
+// and longitude, using ST_DWithin on Postgres so that spatial indexes on the column apply.
+func (cb *CriteriaBuilder) geoNear(column string, lat, lng, radius float64) schema.QueryAppender {
+	return cb.eb.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return cb.eb.Expr(
+				"ST_DWithin(CAST(? AS geography), CAST(? AS geography), ?)",
+				cb.eb.Column(column),
+				cb.eb.Point(lat, lng),
+				radius,
+			)
+		},
+		Default: func() schema.QueryAppender {
+			return cb.eb.Expr("? <= ?", cb.eb.StDistance(cb.eb.Column(column), cb.eb.Point(lat, lng)), radius)
+		},
+	})
+}
+
 func (cb *CriteriaBuilder) Expr(builder func(ExprBuilder) any) ConditionBuilder {
 	cb.and("?", builder(cb.eb))
 
@@ -1286,6 +1741,35 @@ func (cb *CriteriaBuilder) OrGroup(builder func(ConditionBuilder)) ConditionBuil
 	return cb
 }
 
+func (cb *CriteriaBuilder) NotGroup(builder func(ConditionBuilder)) ConditionBuilder {
+	if group := cb.buildGroup(builder); group != nil {
+		cb.and("?", cb.eb.Not(group))
+	}
+
+	return cb
+}
+
+func (cb *CriteriaBuilder) OrNotGroup(builder func(ConditionBuilder)) ConditionBuilder {
+	if group := cb.buildGroup(builder); group != nil {
+		cb.or("?", cb.eb.Not(group))
+	}
+
+	return cb
+}
+
+// buildGroup collects the conditions of a group into a standalone expression, returning nil for an empty group.
+// Negated groups are rendered as expressions since the separator of the first condition in a WHERE clause is dropped.
+func (cb *CriteriaBuilder) buildGroup(builder func(ConditionBuilder)) *ClauseConditionBuilder {
+	group := newConditionBuilder(cb.qb)
+	builder(group)
+
+	if len(group.conditions) == 0 {
+		return nil
+	}
+
+	return group
+}
+
 func (cb *CriteriaBuilder) CreatedByEquals(createdBy string, alias ...string) ConditionBuilder {
 	cb.and("? = ?", buildColumnExpr(constants.ColumnCreatedBy, alias...), createdBy)
 
@@ -0,0 +1,140 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// dataScopeContextKey carries the DataScopeContext of a request.
+type dataScopeContextKey struct{}
+
+// DataScope appends row-level predicates, e.g. restricting rows to their creator or to departments, to every select,
+// update and delete on a DB it is registered on with WithDataScopes. Only the model table of the executed query is
+// filtered; joined relations and subqueries are not. Statements on tables not exempt fail with
+// ErrDataScopeContextMissing when their context carries no DataScopeContext, which the Api pipeline sets for every
+// request; other code sets one with WithDataScopeContext, bypassing the scopes for system work.
+type DataScope interface {
+	// Apply adds the conditions of the scope for a query on the table. The conditions of each scope are grouped,
+	// so scopes may combine conditions with Or freely. Scopes not concerning the table add nothing.
+	Apply(ctx context.Context, table *schema.Table, cb ConditionBuilder)
+}
+
+// DataScopeFunc adapts a function to the DataScope interface.
+type DataScopeFunc func(ctx context.Context, table *schema.Table, cb ConditionBuilder)
+
+func (f DataScopeFunc) Apply(ctx context.Context, table *schema.Table, cb ConditionBuilder) {
+	f(ctx, table, cb)
+}
+
+// DataScopeExempt is implemented by models never filtered by data scopes, e.g. dictionaries shared by all users.
+type DataScopeExempt interface {
+	// DataScopeExempt marks the model as exempt from data scopes.
+	DataScopeExempt()
+}
+
+// DataScopeContext is the per-request identity data scopes filter rows by.
+type DataScopeContext struct {
+	// UserID is the ID of the current user.
+	UserID string
+	// TenantID is the ID of the tenant of the current user.
+	TenantID string
+	// DeptIDs are the IDs of the departments whose rows the current user may access.
+	DeptIDs []string
+	// Attrs holds additional values for custom scopes.
+	Attrs map[string]any
+	// Bypass disables data scopes for the request, e.g. for administrators.
+	Bypass bool
+}
+
+// WithDataScopeContext returns a context carrying the scope context of the request.
+func WithDataScopeContext(ctx context.Context, sc *DataScopeContext) context.Context {
+	return context.WithValue(ctx, dataScopeContextKey{}, sc)
+}
+
+// DataScopeContextFrom returns the scope context of the request, or nil if the context carries none.
+func DataScopeContextFrom(ctx context.Context) *DataScopeContext {
+	sc, _ := ctx.Value(dataScopeContextKey{}).(*DataScopeContext)
+
+	return sc
+}
+
+// NewCreatedByDataScope restricts the rows of tables having a created_by column to those created by the current user.
+// Requests without user are not filtered.
+func NewCreatedByDataScope() DataScope {
+	return NewColumnDataScope(constants.ColumnCreatedBy, func(sc *DataScopeContext) []string {
+		if sc.UserID == constants.Empty {
+			return nil
+		}
+
+		return []string{sc.UserID}
+	})
+}
+
+// NewColumnDataScope restricts the rows of tables having the column to those whose column is among the values
+// returned for the scope context of the request, e.g. the department IDs. Requests for which values returns nil
+// are not filtered; an empty non-nil slice matches no rows.
+func NewColumnDataScope(column string, values func(sc *DataScopeContext) []string) DataScope {
+	return DataScopeFunc(func(ctx context.Context, table *schema.Table, cb ConditionBuilder) {
+		sc := DataScopeContextFrom(ctx)
+		if sc == nil || !table.HasField(column) {
+			return
+		}
+
+		ids := values(sc)
+		switch {
+		case ids == nil:
+		case len(ids) == 0:
+			cb.Expr(func(eb ExprBuilder) any {
+				return eb.Expr("1 = 0")
+			})
+		default:
+			cb.In(column, ids)
+		}
+	})
+}
+
+// whereScoped adds the conditions of builder, grouped when data scopes, row-per-tenant tenancy or the default scope
+// of the table apply so that top-level Or conditions cannot escape the scope, tenant and default conditions ANDed at
+// execution. The table is that of a select query, the only queries default scopes apply to, nil otherwise.
+func (d *BunDB) whereScoped(cb ConditionBuilder, table *schema.Table, builder func(ConditionBuilder)) {
+	if len(d.dataScopes) == 0 && !d.tenancy.filtersRows() && !defaultScoped(table) {
+		builder(cb)
+
+		return
+	}
+
+	cb.Group(builder)
+}
+
+// applyDataScopes adds the conditions of the data scopes to a query on the table,
+// unless the model is exempt or the request bypasses them. Statements without scope context fail closed.
+func applyDataScopes(ctx context.Context, scopes []DataScope, table *schema.Table, cb ConditionBuilder) error {
+	if len(scopes) == 0 || table == nil {
+		return nil
+	}
+
+	if _, ok := table.ZeroIface.(DataScopeExempt); ok {
+		return nil
+	}
+
+	sc := DataScopeContextFrom(ctx)
+	if sc == nil {
+		return fmt.Errorf("%w: table %s", ErrDataScopeContextMissing, table.Name)
+	}
+
+	if sc.Bypass {
+		return nil
+	}
+
+	for _, scope := range scopes {
+		cb.Group(func(cb ConditionBuilder) {
+			scope.Apply(ctx, table, cb)
+		})
+	}
+
+	return nil
+}
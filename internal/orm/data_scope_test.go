@@ -0,0 +1,163 @@
+package orm
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+type ScopeNote struct {
+	bun.BaseModel `bun:"table:test_scope_note,alias:tsn"`
+	Model
+
+	Title  string `json:"title"  bun:"title,notnull"`
+	DeptID string `json:"deptId" bun:"dept_id,notnull"`
+}
+
+// SharedScopeNote reads the notes as a model exempt from data scopes.
+type SharedScopeNote struct {
+	bun.BaseModel `bun:"table:test_scope_note,alias:tsn"`
+	Model
+
+	Title  string `json:"title"  bun:"title,notnull"`
+	DeptID string `json:"deptId" bun:"dept_id,notnull"`
+}
+
+func (*SharedScopeNote) DataScopeExempt() {}
+
+// DataScopeTestSuite tests the row-level filtering of queries by the data scopes of the DB.
+type DataScopeTestSuite struct {
+	*OrmTestSuite
+
+	scoped DB
+}
+
+func (suite *DataScopeTestSuite) SetupSuite() {
+	bunDB := suite.getBunDB()
+
+	_, err := bunDB.NewDropTable().Model((*ScopeNote)(nil)).IfExists().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should drop existing scope note table")
+
+	_, err = bunDB.NewCreateTable().Model((*ScopeNote)(nil)).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should create scope note table")
+
+	suite.scoped = suite.db.WithDataScopes(NewColumnDataScope("dept_id", func(sc *DataScopeContext) []string {
+		return sc.DeptIDs
+	}))
+}
+
+func (suite *DataScopeTestSuite) SetupTest() {
+	_, err := suite.db.NewDelete().Model((*ScopeNote)(nil)).AllowFullTable().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should clear scope notes")
+
+	notes := []ScopeNote{
+		{Title: "sales plan", DeptID: "sales"},
+		{Title: "sales report", DeptID: "sales"},
+		{Title: "hiring plan", DeptID: "hr"},
+		{Title: "budget", DeptID: "finance"},
+	}
+
+	_, err = suite.db.NewInsert().Model(&notes).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert scope notes")
+}
+
+func (suite *DataScopeTestSuite) TearDownSuite() {
+	_, err := suite.getBunDB().NewDropTable().Model((*ScopeNote)(nil)).IfExists().Exec(suite.ctx)
+	suite.NoError(err, "Should cleanup scope note table")
+}
+
+func (suite *DataScopeTestSuite) withDepts(deptIDs ...string) context.Context {
+	return WithDataScopeContext(suite.ctx, &DataScopeContext{DeptIDs: append([]string{}, deptIDs...)})
+}
+
+func (suite *DataScopeTestSuite) count(ctx context.Context, db DB) int64 {
+	count, err := db.NewSelect().Model((*ScopeNote)(nil)).Count(ctx)
+	suite.Require().NoError(err, "Should count scope notes")
+
+	return count
+}
+
+// TestSelect tests filtering selects by the scope context of the request.
+func (suite *DataScopeTestSuite) TestSelect() {
+	var notes []ScopeNote
+
+	suite.Require().NoError(suite.scoped.NewSelect().
+		Model(&notes).
+		Where(func(cb ConditionBuilder) {
+			cb.Contains("title", "plan").OrContains("title", "report")
+		}).
+		OrderBy("title").
+		Scan(suite.withDepts("sales")), "Should select scoped notes")
+
+	suite.Len(notes, 2, "Should keep the scope apart from the conditions of the query")
+	suite.Equal("sales plan", notes[0].Title)
+	suite.Equal("sales report", notes[1].Title)
+
+	suite.Equal(int64(3), suite.count(suite.withDepts("sales", "hr"), suite.scoped), "Should match any of the departments")
+	suite.Equal(int64(0), suite.count(suite.withDepts(), suite.scoped), "Should match no rows without departments")
+	_, err := suite.scoped.NewSelect().Model((*ScopeNote)(nil)).Count(suite.ctx)
+	suite.ErrorIs(err, ErrDataScopeContextMissing, "Should fail closed without scope context")
+	suite.Equal(int64(4), suite.count(suite.withDepts("sales"), suite.db), "Should not filter without data scopes")
+
+	bypass := WithDataScopeContext(suite.ctx, &DataScopeContext{DeptIDs: []string{"sales"}, Bypass: true})
+	suite.Equal(int64(4), suite.count(bypass, suite.scoped), "Should not filter bypassing requests")
+}
+
+// TestExemptModel tests that models exempt from data scopes are not filtered.
+func (suite *DataScopeTestSuite) TestExemptModel() {
+	count, err := suite.scoped.NewSelect().Model((*SharedScopeNote)(nil)).Count(suite.withDepts("hr"))
+	suite.Require().NoError(err, "Should count shared notes")
+	suite.Equal(int64(4), count, "Should not filter exempt models")
+}
+
+// TestUpdateAndDelete tests filtering updates and deletes, including within transactions.
+func (suite *DataScopeTestSuite) TestUpdateAndDelete() {
+	ctx := suite.withDepts("sales")
+
+	res, err := suite.scoped.NewUpdate().
+		Model((*ScopeNote)(nil)).
+		Set("title", "archived").
+		AllowFullTable().
+		Exec(ctx)
+	suite.Require().NoError(err, "Should update scoped notes")
+
+	affected, err := res.RowsAffected()
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), affected, "Should update the rows in scope only")
+
+	err = suite.scoped.RunInTX(suite.withDepts("hr"), func(ctx context.Context, tx DB) error {
+		_, err := tx.NewDelete().
+			Model((*ScopeNote)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.NotEquals("title", constants.Empty)
+			}).
+			Exec(ctx)
+
+		return err
+	})
+	suite.Require().NoError(err, "Should delete scoped notes")
+
+	var notes []ScopeNote
+
+	suite.Require().NoError(suite.db.NewSelect().Model(&notes).OrderBy("dept_id").Scan(suite.ctx))
+	suite.Len(notes, 3, "Should delete the rows in scope only")
+	suite.Equal("budget", notes[0].Title, "Should leave rows out of scope untouched")
+	suite.Equal("archived", notes[1].Title)
+	suite.Equal("archived", notes[2].Title)
+}
+
+// TestCreatedByDataScope tests restricting rows to those created by the current user.
+func (suite *DataScopeTestSuite) TestCreatedByDataScope() {
+	db := suite.db.WithDataScopes(NewCreatedByDataScope())
+
+	system := WithDataScopeContext(suite.ctx, &DataScopeContext{UserID: constants.OperatorSystem})
+	suite.Equal(int64(4), suite.count(system, db), "Should match the rows created by the user")
+
+	other := WithDataScopeContext(suite.ctx, &DataScopeContext{UserID: "other"})
+	suite.Equal(int64(0), suite.count(other, db), "Should not match rows created by others")
+
+	anonymous := WithDataScopeContext(suite.ctx, &DataScopeContext{})
+	suite.Equal(int64(4), suite.count(anonymous, db), "Should not filter requests without user")
+}
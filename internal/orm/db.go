@@ -3,9 +3,19 @@ package orm
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/txcommit"
 )
 
 var (
@@ -21,9 +31,16 @@ var (
 	}
 )
 
+// savepointPrefix prefixes the names of the savepoints created by RunInNestedTX.
+const savepointPrefix = "vef_sp_"
+
 // BunDB is a wrapper around the bun.DB type.
 type BunDB struct {
-	db bun.IDB
+	db         bun.IDB
+	dataScopes []DataScope
+	tenancy    *Tenancy
+	// savepoints numbers the savepoints of the transaction, so nested savepoints never share a name.
+	savepoints *atomic.Uint64
 }
 
 func (d *BunDB) NewSelect() SelectQuery {
@@ -46,33 +63,131 @@ func (d *BunDB) NewMerge() MergeQuery {
 	return NewMergeQuery(d)
 }
 
+func (d *BunDB) NewUpsert() UpsertQuery {
+	return newUpsertQuery(d)
+}
+
 func (d *BunDB) NewRaw(query string, args ...any) RawQuery {
 	return newRawQuery(d, query, args...)
 }
 
+func (d *BunDB) CallProc(name string, args ...any) ProcQuery {
+	return newProcQuery(d, name, args...)
+}
+
+func (d *BunDB) CallFunc(name string, args ...any) RawQuery {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+
+	return newRawQuery(d, "SELECT "+d.quoteIdent(name)+"("+placeholders+")", args...)
+}
+
 func (d *BunDB) RunInTX(ctx context.Context, fn func(context.Context, DB) error) error {
-	return d.db.RunInTx(
+	return d.runInTx(
 		ctx,
 		txOptions,
 		func(ctx context.Context, tx bun.Tx) error {
-			return fn(ctx, &BunDB{db: tx})
+			return fn(ctx, d.withTx(tx))
 		},
 	)
 }
 
 func (d *BunDB) RunInReadOnlyTX(ctx context.Context, fn func(context.Context, DB) error) error {
-	return d.db.RunInTx(
+	return d.runInTx(
 		ctx,
 		readOnlyTxOptions,
 		func(ctx context.Context, tx bun.Tx) error {
-			return fn(ctx, &BunDB{db: tx})
+			return fn(ctx, d.withTx(tx))
 		},
 	)
 }
 
+func (d *BunDB) RunInNestedTX(ctx context.Context, fn func(context.Context, DB) error) (err error) {
+	tx, ok := d.db.(bun.Tx)
+	if !ok {
+		return d.RunInTX(ctx, fn)
+	}
+
+	savepoints := d.savepoints
+	if savepoints == nil {
+		savepoints = new(atomic.Uint64)
+	}
+
+	name := fmt.Sprintf("%s%d", savepointPrefix, savepoints.Add(1))
+	if err = d.execSavepoint(ctx, tx, "SAVEPOINT ?", "SAVE TRANSACTION ?", name); err != nil {
+		return fmt.Errorf("failed to create savepoint %s: %w", name, err)
+	}
+
+	committed := false
+
+	defer func() {
+		if committed {
+			return
+		}
+
+		// Rolls back on errors and panics, the panic continues after the rollback
+		if rollbackErr := d.rollbackSavepoint(ctx, tx, name); rollbackErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to roll back to savepoint %s: %w", name, rollbackErr))
+		}
+	}()
+
+	// Callbacks of the savepoint wait for the enclosing transaction, they are dropped with the savepoint
+	if err = txcommit.Collect(ctx, func(ctx context.Context) error {
+		if err := fn(ctx, &BunDB{db: tx, dataScopes: d.dataScopes, tenancy: d.tenancy, savepoints: savepoints}); err != nil {
+			return err
+		}
+
+		if err := d.execSavepoint(ctx, tx, "RELEASE SAVEPOINT ?", constants.Empty, name); err != nil {
+			return fmt.Errorf("failed to release savepoint %s: %w", name, err)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	committed = true
+
+	return nil
+}
+
+// rollbackSavepoint undoes the changes since the savepoint and releases it, so its name may be used again.
+func (d *BunDB) rollbackSavepoint(ctx context.Context, tx bun.Tx, name string) error {
+	if err := d.execSavepoint(ctx, tx, "ROLLBACK TO SAVEPOINT ?", "ROLLBACK TRANSACTION ?", name); err != nil {
+		return err
+	}
+
+	return d.execSavepoint(ctx, tx, "RELEASE SAVEPOINT ?", constants.Empty, name)
+}
+
+// execSavepoint executes a savepoint statement. SQL Server uses its own statements and has no release,
+// which is skipped when msQuery is empty.
+func (d *BunDB) execSavepoint(ctx context.Context, tx bun.Tx, query, msQuery, name string) error {
+	if tx.Dialect().Features().Has(feature.MSSavepoint) {
+		if msQuery == constants.Empty {
+			return nil
+		}
+
+		query = msQuery
+	}
+
+	_, err := tx.ExecContext(ctx, query, bun.Ident(name))
+
+	return err
+}
+
+// withTx returns the DB of the transaction, keeping the numbering of savepoints of enclosing transactions.
+func (d *BunDB) withTx(tx bun.Tx) *BunDB {
+	savepoints := d.savepoints
+	if _, ok := d.db.(bun.Tx); !ok || savepoints == nil {
+		savepoints = new(atomic.Uint64)
+	}
+
+	return &BunDB{db: tx, dataScopes: d.dataScopes, tenancy: d.tenancy, savepoints: savepoints}
+}
+
 func (d *BunDB) WithNamedArg(name string, value any) DB {
 	if db, ok := d.db.(*bun.DB); ok {
-		return &BunDB{db: db.WithNamedArg(name, value)}
+		return &BunDB{db: db.WithNamedArg(name, value), dataScopes: d.dataScopes, tenancy: d.tenancy}
 	}
 
 	logger.Panicf("%q is not supported within a transaction context", "WithNamedArg")
@@ -80,6 +195,33 @@ func (d *BunDB) WithNamedArg(name string, value any) DB {
 	return d
 }
 
+func (d *BunDB) WithDataScopes(scopes ...DataScope) DB {
+	return &BunDB{
+		db:         d.db,
+		dataScopes: append(slices.Clip(d.dataScopes), scopes...),
+		tenancy:    d.tenancy,
+		savepoints: d.savepoints,
+	}
+}
+
+func (d *BunDB) WithTenancy(tenancy *Tenancy) DB {
+	return &BunDB{db: d.db, dataScopes: d.dataScopes, tenancy: tenancy, savepoints: d.savepoints}
+}
+
+func (d *BunDB) WithQueryHooks(hooks ...QueryHook) DB {
+	if db, ok := d.db.(*bun.DB); ok {
+		for _, hook := range hooks {
+			db = db.WithQueryHook(bunQueryHook{hook: hook})
+		}
+
+		return &BunDB{db: db, dataScopes: d.dataScopes, tenancy: d.tenancy}
+	}
+
+	logger.Panicf("%q is not supported within a transaction context", "WithQueryHooks")
+
+	return d
+}
+
 func (d *BunDB) ModelPKs(model any) (map[string]any, error) {
 	pks := d.ModelPKFields(model)
 	pkValues := make(map[string]any, len(pks))
@@ -111,10 +253,19 @@ func (d *BunDB) TableOf(model any) *schema.Table {
 	return getTableSchema(model, d.getBunDB())
 }
 
+func (d *BunDB) Schema(model any) *ModelSchema {
+	return ModelSchemaOf(d.TableOf(model))
+}
+
 func (d *BunDB) Unwrap() bun.IDB {
 	return d.db
 }
 
+// quoteIdent quotes a possibly schema-qualified identifier for the dialect.
+func (d *BunDB) quoteIdent(ident string) string {
+	return string(dialect.AppendIdent(nil, ident, d.db.Dialect().IdentQuote()))
+}
+
 // getBunDB extracts the underlying *bun.DB from the wrapper.
 // If the wrapper contains a transaction, it retrieves the DB from the transaction.
 func (d *BunDB) getBunDB() *bun.DB {
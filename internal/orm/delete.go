@@ -44,6 +44,13 @@ type BunDeleteQuery struct {
 	query   *bun.DeleteQuery
 
 	returningColumns collections.Set[string]
+	hasWhere         bool
+	allowFullTable   bool
+	forceDelete      bool
+	cascade          bool
+	cascadeHooks     []CascadeHook
+	// filters replays the filtering of the query on a select query reading the rows to delete
+	filters []ApplyFunc[SelectQuery]
 }
 
 func (q *BunDeleteQuery) DB() DB {
@@ -133,27 +140,49 @@ func (q *BunDeleteQuery) TableSubQuery(builder func(SelectQuery), alias ...strin
 }
 
 func (q *BunDeleteQuery) Where(builder func(ConditionBuilder)) DeleteQuery {
-	cb := newQueryConditionBuilder(q.query.QueryBuilder(), q)
-	builder(cb)
+	cb := newQueryConditionBuilder(&whereTracker{QueryBuilder: q.query.QueryBuilder(), hasWhere: &q.hasWhere}, q)
+	q.db.whereScoped(cb, nil, builder)
+
+	q.filters = append(q.filters, func(query SelectQuery) {
+		query.Where(builder)
+	})
 
 	return q
 }
 
+func (q *BunDeleteQuery) WhereSpec(spec Spec) DeleteQuery {
+	return q.Where(And(spec))
+}
+
 func (q *BunDeleteQuery) WherePK(columns ...string) DeleteQuery {
+	q.hasWhere = true
 	q.query.WherePK(columns...)
 
+	q.filters = append(q.filters, func(query SelectQuery) {
+		query.WherePK(columns...)
+	})
+
 	return q
 }
 
 func (q *BunDeleteQuery) WhereDeleted() DeleteQuery {
+	q.hasWhere = true
 	q.query.WhereDeleted()
 
+	q.filters = append(q.filters, func(query SelectQuery) {
+		query.WhereDeleted()
+	})
+
 	return q
 }
 
 func (q *BunDeleteQuery) IncludeDeleted() DeleteQuery {
 	q.query.WhereAllWithDeleted()
 
+	q.filters = append(q.filters, func(query SelectQuery) {
+		query.IncludeDeleted()
+	})
+
 	return q
 }
 
@@ -178,11 +207,25 @@ func (q *BunDeleteQuery) OrderByExpr(builder func(ExprBuilder) any) DeleteQuery
 }
 
 func (q *BunDeleteQuery) ForceDelete() DeleteQuery {
+	q.forceDelete = true
 	q.query.ForceDelete()
 
 	return q
 }
 
+func (q *BunDeleteQuery) Cascade(hooks ...CascadeHook) DeleteQuery {
+	q.cascade = true
+	q.cascadeHooks = append(q.cascadeHooks, hooks...)
+
+	return q
+}
+
+func (q *BunDeleteQuery) AllowFullTable() DeleteQuery {
+	q.allowFullTable = true
+
+	return q
+}
+
 func (q *BunDeleteQuery) Limit(limit int) DeleteQuery {
 	q.query.Limit(limit)
 
@@ -227,33 +270,168 @@ func (q *BunDeleteQuery) ApplyIf(condition bool, fns ...ApplyFunc[DeleteQuery])
 	return q
 }
 
-func (q *BunDeleteQuery) beforeDelete() {
+func (q *BunDeleteQuery) beforeDelete(ctx context.Context) error {
+	if !q.hasWhere {
+		if !q.allowFullTable {
+			return ErrMissingWhereClause
+		}
+
+		q.query.Where("1 = 1")
+		q.hasWhere = true
+	}
+
+	cb := newQueryConditionBuilder(q.query.QueryBuilder(), q)
+	if _, err := q.db.applyTenancy(ctx, q.query, q.GetTable(), cb); err != nil {
+		return err
+	}
+
+	if err := applyDataScopes(ctx, q.db.dataScopes, q.GetTable(), cb); err != nil {
+		return err
+	}
+
 	if !q.returningColumns.IsEmpty() {
 		q.query.Returning("?", buildReturningExpr(q.returningColumns, q.eb))
 	}
+
+	return nil
 }
 
-func (q *BunDeleteQuery) Exec(ctx context.Context, dest ...any) (sql.Result, error) {
-	q.beforeDelete()
+func (q *BunDeleteQuery) Exec(ctx context.Context, dest ...any) (res sql.Result, err error) {
+	ctx = withRejection(ctx)
 
-	res, err := q.query.Exec(ctx, dest...)
-	if err != nil {
-		return nil, translateDeleteError(err)
+	if err := q.beforeDelete(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = q.run(ctx, func(ctx context.Context) error {
+		res, err = q.query.Exec(ctx, dest...)
+
+		return err
+	}); err != nil {
+		return nil, contextError(ctx, translateDeleteError(err))
 	}
 
 	return res, nil
 }
 
 func (q *BunDeleteQuery) Scan(ctx context.Context, dest ...any) error {
-	q.beforeDelete()
+	ctx = withRejection(ctx)
+
+	if err := q.beforeDelete(ctx); err != nil {
+		return err
+	}
 
-	if err := q.query.Scan(ctx, dest...); err != nil {
-		return translateDeleteError(err)
+	if err := q.run(ctx, func(ctx context.Context) error {
+		return q.query.Scan(ctx, dest...)
+	}); err != nil {
+		return contextError(ctx, translateDeleteError(err))
 	}
 
 	return nil
 }
 
+// run executes the delete, within a transaction when cascading, recording the history of the deleted rows, adjusting
+// the summary columns over the deleted rows or reading them for the audit events, after deleting the dependent rows
+// when cascading.
+func (q *BunDeleteQuery) run(ctx context.Context, exec func(context.Context) error) error {
+	model, ok := q.query.GetModel().(bun.TableModel)
+
+	var (
+		summaries []*summary
+		history   bool
+		sink      AuditSink
+	)
+	if ok {
+		summaries = summariesOver(model.Table())
+		history = historyOf(model.Table())
+		sink = auditOf(model.Table())
+	}
+
+	if !q.cascade && !history && len(summaries) == 0 && sink == nil {
+		return exec(ctx)
+	}
+
+	if !ok {
+		return ErrCascadeMissingModel
+	}
+
+	table := model.Table()
+
+	var events []AuditEvent
+
+	return q.db.RunInTX(ctx, func(ctx context.Context, tx DB) error {
+		txDB := tx.(*BunDB)
+
+		if q.cascade && len(cascadeRelations(table)) > 0 {
+			var rows []map[string]any
+
+			if err := tx.NewSelect().
+				Model(model.Value()).
+				Select(keyColumns(table)...).
+				Apply(q.filters...).
+				Scan(ctx, &rows); err != nil {
+				return err
+			}
+
+			c := &cascader{
+				db:      txDB,
+				hooks:   q.cascadeHooks,
+				visited: make(map[string]bool),
+			}
+			if err := c.deleteDependents(ctx, table, c.unvisited(table, rows), constants.Empty, q.forceDelete); err != nil {
+				return err
+			}
+		}
+
+		deltas := make(summaryDeltas)
+
+		if len(summaries) > 0 {
+			var rows []map[string]any
+
+			// Soft deleted rows no longer count, also when the delete includes them
+			if err := tx.NewSelect().
+				Model(model.Value()).
+				Select(summaryColumns(summaries)...).
+				Apply(q.filters...).
+				ApplyIf(table.SoftDeleteField != nil, whereLive(table.SoftDeleteField)).
+				Scan(ctx, &rows); err != nil {
+				return err
+			}
+
+			if err := deltas.add(summaries, rows, -1); err != nil {
+				return err
+			}
+		}
+
+		if history {
+			if err := recordHistory(ctx, txDB, model.Value(), q.filters, HistoryOperationDelete); err != nil {
+				return err
+			}
+		}
+
+		if sink != nil {
+			before, err := readAuditRows(ctx, txDB, model.Value(), table, q.filters...)
+			if err != nil {
+				return err
+			}
+
+			events = auditEvents(table, AuditOperationDelete, before, nil)
+		}
+
+		q.query.Conn(txDB.db)
+
+		if err := exec(ctx); err != nil {
+			return err
+		}
+
+		if err := deltas.apply(ctx, txDB.db); err != nil {
+			return err
+		}
+
+		return writeAuditEvents(ctx, txDB, sink, events)
+	})
+}
+
 func (q *BunDeleteQuery) Unwrap() *bun.DeleteQuery {
 	return q.query
 }
@@ -2,6 +2,9 @@ package orm
 
 import (
 	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
 
 	"github.com/ilxqx/vef-framework-go/constants"
 )
@@ -1221,3 +1224,275 @@ func (suite *DeleteTestSuite) TestExecution() {
 		suite.T().Logf("Performance test: deleted %d users", batchSize)
 	})
 }
+
+// TestFullTableGuard tests that deletes without a where clause are rejected unless AllowFullTable is called.
+func (suite *DeleteTestSuite) TestFullTableGuard() {
+	suite.T().Logf("Testing full table guard for %s", suite.dbType)
+
+	testModels := []*SimpleModel{
+		{Name: "Guard Simple 1", Value: 1},
+		{Name: "Guard Simple 2", Value: 2},
+	}
+
+	_, err := suite.db.NewInsert().Model(&testModels).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert test simple models")
+
+	suite.Run("RejectWithoutWhere", func() {
+		_, err := suite.db.NewDelete().Model((*SimpleModel)(nil)).Exec(suite.ctx)
+		suite.ErrorIs(err, ErrMissingWhereClause, "DELETE without WHERE should be rejected")
+
+		err = suite.db.NewDelete().Model((*SimpleModel)(nil)).Scan(suite.ctx)
+		suite.ErrorIs(err, ErrMissingWhereClause, "DELETE scan without WHERE should be rejected")
+	})
+
+	suite.Run("RejectEmptyConditions", func() {
+		_, err := suite.db.NewDelete().
+			Model((*SimpleModel)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.ApplyIf(false, func(cb ConditionBuilder) {
+					cb.Equals("value", 1)
+				})
+				cb.Group(func(ConditionBuilder) {})
+			}).
+			Exec(suite.ctx)
+		suite.ErrorIs(err, ErrMissingWhereClause, "DELETE whose condition builder adds nothing should be rejected")
+
+		count, err := suite.db.NewSelect().Model((*SimpleModel)(nil)).Where(func(cb ConditionBuilder) {
+			cb.StartsWith("name", "Guard Simple")
+		}).Count(suite.ctx)
+		suite.NoError(err)
+		suite.Equal(int64(2), count, "Rejected deletes should not remove rows")
+	})
+
+	suite.Run("AllowGroupedConditions", func() {
+		result, err := suite.db.NewDelete().
+			Model((*SimpleModel)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.Group(func(cb ConditionBuilder) {
+					cb.Equals("value", 1)
+				})
+			}).
+			Exec(suite.ctx)
+		suite.NoError(err, "DELETE with grouped conditions should be allowed")
+
+		rowsAffected, err := result.RowsAffected()
+		suite.NoError(err)
+		suite.Equal(int64(1), rowsAffected)
+	})
+
+	suite.Run("AllowFullTable", func() {
+		_, err := suite.db.NewDelete().Model((*SimpleModel)(nil)).AllowFullTable().Exec(suite.ctx)
+		suite.NoError(err, "DELETE with AllowFullTable should be allowed")
+
+		count, err := suite.db.NewSelect().Model((*SimpleModel)(nil)).Count(suite.ctx)
+		suite.NoError(err)
+		suite.Equal(int64(0), count, "DELETE with AllowFullTable should delete all rows")
+	})
+}
+
+// TestCascade tests cascading deletes along has-one and has-many relations.
+func (suite *DeleteTestSuite) TestCascade() {
+	suite.T().Logf("Testing Cascade method for %s", suite.dbType)
+
+	countPosts := func(userID string) int64 {
+		count, err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("user_id", userID)
+			}).
+			Count(suite.ctx)
+		suite.Require().NoError(err, "Should count posts")
+
+		return count
+	}
+
+	suite.Run("HasMany", func() {
+		user := &User{Name: "Cascade User", Email: "cascade_user@example.com", Age: 30, IsActive: true}
+		_, err := suite.db.NewInsert().Model(user).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should insert cascade user")
+
+		posts := []*Post{
+			{Title: "Cascade Post 1", Content: "Content", UserID: user.ID, CategoryID: "cat1", Status: "draft"},
+			{Title: "Cascade Post 2", Content: "Content", UserID: user.ID, CategoryID: "cat1", Status: "draft"},
+		}
+		_, err = suite.db.NewInsert().Model(&posts).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should insert cascade posts")
+
+		result, err := suite.db.NewDelete().
+			Model((*User)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("email", "cascade_user@example.com")
+			}).
+			Cascade().
+			Exec(suite.ctx)
+		suite.Require().NoError(err, "Cascading delete should work")
+
+		rowsAffected, _ := result.RowsAffected()
+		suite.Equal(int64(1), rowsAffected, "Should report the deleted user only")
+		suite.Equal(int64(0), countPosts(user.ID), "Posts of the user should be deleted")
+	})
+
+	suite.Run("SelfReferencingTree", func() {
+		root := &Category{Name: "Cascade Root"}
+		_, err := suite.db.NewInsert().Model(root).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should insert root category")
+
+		child := &Category{Name: "Cascade Child", ParentID: &root.ID}
+		_, err = suite.db.NewInsert().Model(child).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should insert child category")
+
+		grandchild := &Category{Name: "Cascade Grandchild", ParentID: &child.ID}
+		_, err = suite.db.NewInsert().Model(grandchild).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should insert grandchild category")
+
+		post := &Post{Title: "Cascade Tree Post", Content: "Content", UserID: "user1", CategoryID: grandchild.ID, Status: "draft"}
+		_, err = suite.db.NewInsert().Model(post).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should insert post of grandchild category")
+
+		var relations []string
+
+		_, err = suite.db.NewDelete().
+			Model(root).
+			WherePK().
+			Cascade(func(relation string) CascadeMode {
+				relations = append(relations, relation)
+
+				return CascadeDefault
+			}).
+			Exec(suite.ctx)
+		suite.Require().NoError(err, "Cascading delete of a tree should work")
+
+		suite.Equal([]string{
+			"Children",
+			"Children.Children",
+			"Children.Children.Children",
+			"Children.Children.Posts",
+			"Children.Posts",
+			"Posts",
+		}, relations, "Hooks should be called with the relation paths, deepest first")
+
+		count, err := suite.db.NewSelect().
+			Model((*Category)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.StartsWith("name", "Cascade ")
+			}).
+			Count(suite.ctx)
+		suite.Require().NoError(err, "Should count categories")
+		suite.Equal(int64(0), count, "Whole category tree should be deleted")
+
+		count, err = suite.db.NewSelect().
+			Model((*Post)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("id", post.ID)
+			}).
+			Count(suite.ctx)
+		suite.Require().NoError(err, "Should count posts")
+		suite.Equal(int64(0), count, "Posts of nested categories should be deleted")
+	})
+
+	suite.Run("SkipHook", func() {
+		user := &User{Name: "Cascade Skip User", Email: "cascade_skip@example.com", Age: 30, IsActive: true}
+		_, err := suite.db.NewInsert().Model(user).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should insert cascade user")
+
+		post := &Post{Title: "Cascade Skip Post", Content: "Content", UserID: user.ID, CategoryID: "cat1", Status: "draft"}
+		_, err = suite.db.NewInsert().Model(post).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should insert cascade post")
+
+		_, err = suite.db.NewDelete().
+			Model(user).
+			WherePK().
+			Cascade(func(relation string) CascadeMode {
+				if relation == "Posts" {
+					return CascadeSkip
+				}
+
+				return CascadeDefault
+			}).
+			Exec(suite.ctx)
+		suite.Require().NoError(err, "Cascading delete should work")
+		suite.Equal(int64(1), countPosts(user.ID), "Skipped relation should be left untouched")
+
+		_, err = suite.db.NewDelete().Model(post).WherePK().Exec(suite.ctx)
+		suite.NoError(err, "Should cleanup cascade post")
+	})
+
+	suite.Run("SoftDelete", func() {
+		type SoftDeleteOrderItem struct {
+			bun.BaseModel `bun:"table:test_cascade_order_item,alias:tcoi"`
+			Model
+
+			OrderID   string    `json:"orderId"   bun:"order_id,notnull"`
+			Name      string    `json:"name"      bun:"name,notnull"`
+			DeletedAt time.Time `json:"deletedAt" bun:",soft_delete,nullzero"`
+		}
+
+		type SoftDeleteOrder struct {
+			bun.BaseModel `bun:"table:test_cascade_order,alias:tco"`
+			Model
+
+			Name      string                `json:"name"      bun:"name,notnull"`
+			DeletedAt time.Time             `json:"deletedAt" bun:",soft_delete,nullzero"`
+			Items     []SoftDeleteOrderItem `json:"items"     bun:"rel:has-many,join:id=order_id"`
+		}
+
+		bunDB := suite.getBunDB()
+		for _, model := range []any{(*SoftDeleteOrder)(nil), (*SoftDeleteOrderItem)(nil)} {
+			_, err := bunDB.NewDropTable().Model(model).IfExists().Exec(suite.ctx)
+			suite.Require().NoError(err, "Should drop existing cascade table")
+
+			_, err = bunDB.NewCreateTable().Model(model).Exec(suite.ctx)
+			suite.Require().NoError(err, "Should create cascade table")
+		}
+
+		defer func() {
+			for _, model := range []any{(*SoftDeleteOrder)(nil), (*SoftDeleteOrderItem)(nil)} {
+				_, err := bunDB.NewDropTable().Model(model).IfExists().Exec(suite.ctx)
+				suite.NoError(err, "Should cleanup cascade table")
+			}
+		}()
+
+		order := &SoftDeleteOrder{Name: "Order"}
+		_, err := suite.db.NewInsert().Model(order).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should insert order")
+
+		items := []*SoftDeleteOrderItem{
+			{OrderID: order.ID, Name: "Item 1"},
+			{OrderID: order.ID, Name: "Item 2"},
+		}
+		_, err = suite.db.NewInsert().Model(&items).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should insert order items")
+
+		countItems := func(includeDeleted bool) int64 {
+			count, err := suite.db.NewSelect().
+				Model((*SoftDeleteOrderItem)(nil)).
+				ApplyIf(includeDeleted, func(query SelectQuery) {
+					query.IncludeDeleted()
+				}).
+				Count(suite.ctx)
+			suite.Require().NoError(err, "Should count order items")
+
+			return count
+		}
+
+		_, err = suite.db.NewDelete().Model(order).WherePK().Cascade().Exec(suite.ctx)
+		suite.Require().NoError(err, "Cascading soft delete should work")
+		suite.Equal(int64(0), countItems(false), "Items should be soft deleted")
+		suite.Equal(int64(2), countItems(true), "Soft deleted items should be kept")
+
+		_, err = suite.db.NewDelete().Model(order).WherePK().IncludeDeleted().ForceDelete().Cascade().Exec(suite.ctx)
+		suite.Require().NoError(err, "Cascading force delete should work")
+		suite.Equal(int64(0), countItems(true), "Force delete should also remove the soft deleted items")
+	})
+
+	suite.Run("MissingModel", func() {
+		_, err := suite.db.NewDelete().
+			Table("test_user").
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("email", "cascade_missing@example.com")
+			}).
+			Cascade().
+			Exec(suite.ctx)
+		suite.ErrorIs(err, ErrCascadeMissingModel, "Cascading delete without a model should fail")
+	})
+}
@@ -0,0 +1,183 @@
+package orm
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+const (
+	// TagDenorm is the struct tag declaring a column holding a copy of a column of the row referenced through
+	// a belongs-to relation, given as "<relation>.<column>".
+	// Example: `denorm:"User.name"`.
+	TagDenorm = "denorm"
+
+	// denormAlias aliases the source table in refreshes, which may be the table holding the copies itself.
+	denormAlias = "_denorm"
+)
+
+// denorm is a denormalized column declared on a model.
+type denorm struct {
+	table  *schema.Table
+	column string
+	rel    *schema.Relation
+	source string // Copied column of the referenced rows
+}
+
+var (
+	// denormMu guards denorms.
+	denormMu sync.RWMutex
+	// denorms holds the declared denormalized columns keyed by table and column.
+	denorms = make(map[string]*denorm)
+)
+
+// RegisterDenormalizations registers the denormalized columns declared through denorm tags on the given models.
+// Updates of the copied columns through the orm then refresh the copies in the same transaction, as do inserts of
+// rows holding copies and updates of their relation columns. Changes made outside the orm are not tracked,
+// BackfillDenormalizations repairs the copies after them.
+func RegisterDenormalizations(db DB, models ...any) error {
+	for _, model := range models {
+		table := db.TableOf(model)
+
+		for _, field := range table.Fields {
+			tag, ok := field.StructField.Tag.Lookup(TagDenorm)
+			if !ok {
+				continue
+			}
+
+			relation, source, _ := strings.Cut(tag, constants.Dot)
+
+			rel := table.Relations[relation]
+			if rel == nil || rel.Type != schema.BelongsToRelation {
+				return fmt.Errorf("%w: %s.%s must name a belongs-to relation, got %q",
+					ErrInvalidDenormalization, table.Name, field.Name, tag)
+			}
+
+			if _, ok := rel.JoinTable.FieldMap[source]; !ok {
+				return fmt.Errorf("%w: %s.%s copies unknown column %s.%s",
+					ErrInvalidDenormalization, table.Name, field.Name, rel.JoinTable.Name, source)
+			}
+
+			denormMu.Lock()
+			denorms[table.Name+constants.Dot+field.Name] = &denorm{
+				table:  table,
+				column: field.Name,
+				rel:    rel,
+				source: source,
+			}
+			denormMu.Unlock()
+		}
+	}
+
+	return nil
+}
+
+// BackfillDenormalizations refreshes all registered denormalized columns of the given models
+// from the rows they reference.
+func BackfillDenormalizations(ctx context.Context, db DB, models ...any) error {
+	bunDB, ok := db.(*BunDB)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrDialectUnsupportedOperation, db)
+	}
+
+	return bunDB.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, model := range models {
+			for _, d := range denormsOf(db.TableOf(model)) {
+				if err := d.refresh(ctx, tx, nil, nil); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// findDenorms returns the denormalized columns matching the predicate, sorted by table and column.
+func findDenorms(match func(d *denorm) bool) []*denorm {
+	denormMu.RLock()
+	defer denormMu.RUnlock()
+
+	var result []*denorm
+
+	for _, d := range denorms {
+		if match(d) {
+			result = append(result, d)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *denorm) int {
+		return cmp.Or(cmp.Compare(a.table.Name, b.table.Name), cmp.Compare(a.column, b.column))
+	})
+
+	return result
+}
+
+// denormsOf returns the denormalized columns held by the given table.
+func denormsOf(table *schema.Table) []*denorm {
+	return findDenorms(func(d *denorm) bool {
+		return d.table.Name == table.Name
+	})
+}
+
+// denormsFrom returns the denormalized columns copying columns of the given table.
+func denormsFrom(table *schema.Table) []*denorm {
+	return findDenorms(func(d *denorm) bool {
+		return d.rel.JoinTable.Name == table.Name
+	})
+}
+
+// refresh copies the source column into the rows whose given columns equal one of the keys, all rows without fields.
+// Rows referencing no row keep their value.
+func (d *denorm) refresh(ctx context.Context, db bun.IDB, fields []*schema.Field, keys [][]any) error {
+	source := db.NewSelect().
+		TableExpr("? AS ?", bun.Ident(d.rel.JoinTable.Name), bun.Ident(denormAlias)).
+		ColumnExpr("?.?", bun.Ident(denormAlias), bun.Ident(d.source))
+
+	for i, field := range d.rel.JoinPKs {
+		source.Where("?.? = ?.?",
+			bun.Ident(denormAlias), bun.Ident(field.Name),
+			bun.Ident(d.table.Alias), bun.Ident(d.rel.BasePKs[i].Name))
+	}
+
+	update := func(keys [][]any) error {
+		query := db.NewUpdate().
+			Model(d.table.ZeroIface).
+			Set("? = COALESCE((?), ?)", bun.Ident(d.column), source, bun.Ident(d.column))
+		if d.table.SoftDeleteField != nil {
+			query.WhereAllWithDeleted()
+		}
+
+		if fields == nil {
+			query.Where("1 = 1")
+		} else {
+			whereKeys(query, fields, keys)
+		}
+
+		if _, err := query.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to refresh denormalized column %s.%s: %w", d.table.Name, d.column, err)
+		}
+
+		return nil
+	}
+
+	if fields == nil {
+		return update(nil)
+	}
+
+	for batch := range slices.Chunk(keys, cascadeBatchSize) {
+		if err := update(batch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package orm
+
+import (
+	"github.com/uptrace/bun"
+)
+
+type DenormAuthor struct {
+	bun.BaseModel `bun:"table:test_denorm_author,alias:tda"`
+	Model
+
+	Name string `json:"name" bun:"name,notnull"`
+}
+
+type DenormBook struct {
+	bun.BaseModel `bun:"table:test_denorm_book,alias:tdb"`
+	Model
+
+	Title      string        `json:"title"      bun:"title,notnull"`
+	AuthorID   string        `json:"authorId"   bun:"author_id,notnull"`
+	AuthorName string        `json:"authorName" bun:"author_name,notnull" denorm:"Author.name"`
+	Author     *DenormAuthor `json:"author"     bun:"rel:belongs-to,join:author_id=id"`
+}
+
+// DenormalizeTestSuite tests the maintenance of denormalized columns.
+type DenormalizeTestSuite struct {
+	*OrmTestSuite
+}
+
+func (suite *DenormalizeTestSuite) SetupSuite() {
+	bunDB := suite.getBunDB()
+	for _, model := range []any{(*DenormAuthor)(nil), (*DenormBook)(nil)} {
+		_, err := bunDB.NewDropTable().Model(model).IfExists().Exec(suite.ctx)
+		suite.Require().NoError(err, "Should drop existing denormalization table")
+
+		_, err = bunDB.NewCreateTable().Model(model).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should create denormalization table")
+	}
+
+	suite.Require().NoError(RegisterDenormalizations(suite.db, (*DenormBook)(nil)), "Should register denormalizations")
+}
+
+func (suite *DenormalizeTestSuite) TearDownSuite() {
+	bunDB := suite.getBunDB()
+	for _, model := range []any{(*DenormAuthor)(nil), (*DenormBook)(nil)} {
+		_, err := bunDB.NewDropTable().Model(model).IfExists().Exec(suite.ctx)
+		suite.NoError(err, "Should cleanup denormalization table")
+	}
+}
+
+func (suite *DenormalizeTestSuite) authorNames() map[string]string {
+	var books []DenormBook
+
+	suite.Require().NoError(suite.db.NewSelect().Model(&books).Scan(suite.ctx), "Should read books")
+
+	names := make(map[string]string, len(books))
+	for _, book := range books {
+		names[book.Title] = book.AuthorName
+	}
+
+	return names
+}
+
+func (suite *DenormalizeTestSuite) TestMaintenance() {
+	suite.T().Logf("Testing denormalization maintenance for %s", suite.dbType)
+
+	alice := &DenormAuthor{Name: "Alice"}
+	bob := &DenormAuthor{Name: "Bob"}
+	_, err := suite.db.NewInsert().Model(&[]*DenormAuthor{alice, bob}).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert authors")
+
+	books := []*DenormBook{
+		{Title: "First", AuthorID: alice.ID},
+		{Title: "Second", AuthorID: alice.ID},
+		{Title: "Third", AuthorID: bob.ID},
+	}
+	_, err = suite.db.NewInsert().Model(&books).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert books")
+
+	suite.Run("Insert", func() {
+		suite.Equal(map[string]string{"First": "Alice", "Second": "Alice", "Third": "Bob"}, suite.authorNames())
+	})
+
+	suite.Run("UpdateModel", func() {
+		alice.Name = "Alice Smith"
+		_, err := suite.db.NewUpdate().Model(alice).WherePK().Exec(suite.ctx)
+		suite.Require().NoError(err, "Should update author")
+
+		suite.Equal(map[string]string{"First": "Alice Smith", "Second": "Alice Smith", "Third": "Bob"}, suite.authorNames())
+	})
+
+	suite.Run("UpdateWhere", func() {
+		// The filter no longer matches once updated
+		_, err := suite.db.NewUpdate().
+			Model((*DenormAuthor)(nil)).
+			Set("name", "Robert").
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("name", "Bob")
+			}).
+			Exec(suite.ctx)
+		suite.Require().NoError(err, "Should update author")
+
+		suite.Equal("Robert", suite.authorNames()["Third"])
+	})
+
+	suite.Run("UpdateRelation", func() {
+		_, err := suite.db.NewUpdate().
+			Model((*DenormBook)(nil)).
+			Set("author_id", bob.ID).
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("title", "Second")
+			}).
+			Exec(suite.ctx)
+		suite.Require().NoError(err, "Should move book to another author")
+
+		suite.Equal("Robert", suite.authorNames()["Second"])
+	})
+
+	suite.Run("Backfill", func() {
+		_, err := suite.getBunDB().NewUpdate().
+			Model((*DenormBook)(nil)).
+			Set("author_name = 'stale'").
+			Where("1 = 1").
+			Exec(suite.ctx)
+		suite.Require().NoError(err, "Should corrupt copies")
+
+		suite.Require().NoError(BackfillDenormalizations(suite.ctx, suite.db, (*DenormBook)(nil)), "Should backfill copies")
+		suite.Equal(map[string]string{"First": "Alice Smith", "Second": "Robert", "Third": "Robert"}, suite.authorNames())
+	})
+}
+
+func (suite *DenormalizeTestSuite) TestInvalidDeclaration() {
+	type InvalidDenorm struct {
+		bun.BaseModel `bun:"table:test_denorm_invalid"`
+		Model
+
+		AuthorID   string        `bun:"author_id"`
+		AuthorName string        `bun:"author_name" denorm:"Author.missing"`
+		Author     *DenormAuthor `bun:"rel:belongs-to,join:author_id=id"`
+	}
+
+	suite.ErrorIs(RegisterDenormalizations(suite.db, (*InvalidDenorm)(nil)), ErrInvalidDenormalization)
+}
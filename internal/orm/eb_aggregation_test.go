@@ -5,7 +5,7 @@ import (
 )
 
 // AggregationFunctionsTestSuite tests aggregate expression methods of ExprBuilder
-// including Count, Sum, Avg, Min, Max, StringAgg, ArrayAgg, JsonObjectAgg, JsonArrayAgg,
+// including Count, Sum, Avg, Min, Max, StringAgg, PercentileCont, PercentileDisc, ArrayAgg, JsonObjectAgg, JsonArrayAgg,
 // BitOr, BitAnd, BoolOr, BoolAnd, StdDev, and Variance functions.
 //
 // This suite verifies cross-database compatibility for aggregation functions across
@@ -393,6 +393,63 @@ func (suite *AggregationFunctionsTestSuite) TestAvgColumn() {
 	})
 }
 
+// TestConditionalShorthands tests the CountIf, SumIf and AvgIf shorthands against their builder equivalents.
+func (suite *AggregationFunctionsTestSuite) TestConditionalShorthands() {
+	suite.T().Logf("Testing CountIf, SumIf and AvgIf functions for %s", suite.dbType)
+
+	type ConditionalStats struct {
+		PublishedPosts  int64   `bun:"published_posts"`
+		PublishedViews  int64   `bun:"published_views"`
+		AvgPublished    float64 `bun:"avg_published"`
+		ExpectedPosts   int64   `bun:"expected_posts"`
+		ExpectedViews   int64   `bun:"expected_views"`
+		ExpectedAverage float64 `bun:"expected_average"`
+	}
+
+	published := func(cb ConditionBuilder) {
+		cb.Equals("status", "published")
+	}
+
+	var stats ConditionalStats
+
+	err := suite.db.NewSelect().
+		Model((*Post)(nil)).
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.CountIf(published)
+		}, "published_posts").
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.SumIf("view_count", published)
+		}, "published_views").
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.AvgIf("view_count", published)
+		}, "avg_published").
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.Count(func(cb CountBuilder) {
+				cb.All().Filter(published)
+			})
+		}, "expected_posts").
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.Sum(func(sb SumBuilder) {
+				sb.Column("view_count").Filter(published)
+			})
+		}, "expected_views").
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.Avg(func(ab AvgBuilder) {
+				ab.Column("view_count").Filter(published)
+			})
+		}, "expected_average").
+		Scan(suite.ctx, &stats)
+
+	suite.Require().NoError(err, "Conditional aggregate shorthands should work")
+	suite.True(stats.PublishedPosts > 0, "Should count published posts")
+	suite.Equal(stats.ExpectedPosts, stats.PublishedPosts, "CountIf should match Count with Filter")
+	suite.Equal(stats.ExpectedViews, stats.PublishedViews, "SumIf should match Sum with Filter")
+	suite.InDelta(stats.ExpectedAverage, stats.AvgPublished, 0.001, "AvgIf should match Avg with Filter")
+
+	suite.T().Logf("Published posts: %d, views: %d, average views: %.2f",
+		stats.PublishedPosts, stats.PublishedViews, stats.AvgPublished)
+}
+
 // TestMin tests the Min aggregate function with builder callback.
 func (suite *AggregationFunctionsTestSuite) TestMin() {
 	suite.T().Logf("Testing Min function for %s", suite.dbType)
@@ -603,6 +660,109 @@ func (suite *AggregationFunctionsTestSuite) TestStringAgg() {
 			suite.T().Logf("Status: %s, Titles: %s", result.Status, result.Titles)
 		}
 	})
+
+	suite.Run("StringAggMaxLength", func() {
+		type StringAggResult struct {
+			Titles     string `bun:"titles"`
+			Truncated  string `bun:"truncated"`
+			Indicated  string `bun:"indicated"`
+			Unaffected string `bun:"unaffected"`
+		}
+
+		var result StringAggResult
+
+		err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.StringAgg(func(sab StringAggBuilder) {
+					sab.Column("title").OrderBy("title")
+				})
+			}, "titles").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.StringAgg(func(sab StringAggBuilder) {
+					sab.Column("title").OrderBy("title").MaxLength(12)
+				})
+			}, "truncated").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.StringAgg(func(sab StringAggBuilder) {
+					sab.Column("title").OrderBy("title").MaxLength(12).OverflowIndicator(" [more]")
+				})
+			}, "indicated").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.StringAgg(func(sab StringAggBuilder) {
+					sab.Column("title").OrderBy("title").MaxLength(10000)
+				})
+			}, "unaffected").
+			Scan(suite.ctx, &result)
+
+		suite.NoError(err, "StringAgg with MaxLength should work")
+		suite.Require().Greater(len(result.Titles), 12, "Aggregated titles should exceed the maximum length")
+		suite.Equal(result.Titles[:9]+"...", result.Truncated, "Should truncate with the default indicator")
+		suite.Equal(result.Titles[:5]+" [more]", result.Indicated, "Should truncate with the configured indicator")
+		suite.Equal(result.Titles, result.Unaffected, "Should keep strings within the maximum length")
+	})
+}
+
+// TestPercentile tests the PercentileCont and PercentileDisc ordered-set aggregate functions.
+func (suite *AggregationFunctionsTestSuite) TestPercentile() {
+	suite.T().Logf("Testing Percentile functions for %s", suite.dbType)
+
+	type PercentileResult struct {
+		MinViews   float64 `bun:"min_views"`
+		MaxViews   float64 `bun:"max_views"`
+		Median     float64 `bun:"median"`
+		TopDecile  float64 `bun:"top_decile"`
+		DiscMedian float64 `bun:"disc_median"`
+	}
+
+	query := func() SelectQuery {
+		return suite.db.NewSelect().
+			Model((*Post)(nil)).
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.MinColumn("view_count")
+			}, "min_views").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.MaxColumn("view_count")
+			}, "max_views").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.PercentileCont(func(pb PercentileBuilder) {
+					pb.Column("view_count")
+				})
+			}, "median").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.PercentileCont(func(pb PercentileBuilder) {
+					pb.Column("view_count").Fraction(0.1).Desc()
+				})
+			}, "top_decile").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.PercentileDisc(func(pb PercentileBuilder) {
+					pb.Column("view_count").Filter(func(cb ConditionBuilder) {
+						cb.Equals("status", "published")
+					})
+				})
+			}, "disc_median")
+	}
+
+	if suite.dbType != constants.Postgres {
+		suite.Run("Unsupported", func() {
+			var result PercentileResult
+
+			err := query().Scan(suite.ctx, &result)
+			suite.Error(err, "Percentiles should be rejected on %s", suite.dbType)
+		})
+
+		return
+	}
+
+	suite.Run("WithinGroup", func() {
+		var result PercentileResult
+
+		err := query().Scan(suite.ctx, &result)
+		suite.NoError(err, "Percentiles should work on PostgreSQL")
+		suite.True(result.Median >= result.MinViews && result.Median <= result.MaxViews, "Median should be within range")
+		suite.True(result.TopDecile >= result.Median, "Top decile should not be below the median")
+		suite.True(result.DiscMedian >= result.MinViews && result.DiscMedian <= result.MaxViews, "Discrete median should be within range")
+	})
 }
 
 // TestArrayAgg tests the ArrayAgg aggregate function.
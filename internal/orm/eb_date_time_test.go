@@ -281,6 +281,44 @@ func (suite *DateTimeFunctionsTestSuite) TestExtractDay() {
 	})
 }
 
+// TestExtractDayOfWeek tests the ExtractDayOfWeek function.
+// ExtractDayOfWeek extracts the ISO day of week (1 = Monday through 7 = Sunday).
+func (suite *DateTimeFunctionsTestSuite) TestExtractDayOfWeek() {
+	suite.T().Logf("Testing ExtractDayOfWeek function for %s", suite.dbType)
+
+	suite.Run("ExtractDayOfWeekFromCreatedAt", func() {
+		type DayOfWeekResult struct {
+			CreatedAt time.Time `bun:"created_at"`
+			DayOfWeek int64     `bun:"day_of_week"`
+		}
+
+		var results []DayOfWeekResult
+
+		err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			Select("created_at").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.ExtractDayOfWeek(eb.Column("created_at"))
+			}, "day_of_week").
+			OrderBy("created_at").
+			Limit(5).
+			Scan(suite.ctx, &results)
+
+		suite.NoError(err, "ExtractDayOfWeek query should execute successfully")
+		suite.True(len(results) > 0, "ExtractDayOfWeek should return at least one result")
+
+		for _, result := range results {
+			expected := int64(result.CreatedAt.Weekday())
+			if expected == 0 {
+				expected = 7
+			}
+
+			suite.Equal(expected, result.DayOfWeek, "Extracted day of week should follow ISO numbering")
+			suite.T().Logf("CreatedAt: %v | DayOfWeek: %d", result.CreatedAt, result.DayOfWeek)
+		}
+	})
+}
+
 // TestExtractHour tests the ExtractHour function.
 // ExtractHour extracts the hour from a timestamp.
 func (suite *DateTimeFunctionsTestSuite) TestExtractHour() {
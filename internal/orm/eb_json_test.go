@@ -681,3 +681,90 @@ func (suite *JSONFunctionsTestSuite) TestJsonEdgeCases() {
 		}
 	})
 }
+
+// TestJSONPath tests paths built with JSONPath.
+func (suite *JSONFunctionsTestSuite) TestJSONPath() {
+	suite.T().Logf("Testing JSONPath for %s", suite.dbType)
+
+	type JSONPathResult struct {
+		Name          string  `bun:"name"`
+		Role          string  `bun:"role"`
+		FirstInterest string  `bun:"first_interest"`
+		LastInterest  string  `bun:"last_interest"`
+		Second        *string `bun:"second_interest"`
+		HasSecond     bool    `bun:"has_second"`
+		Interests     string  `bun:"interests"`
+	}
+
+	var results []JSONPathResult
+
+	err := suite.db.NewSelect().
+		Model((*User)(nil)).
+		Select("name").
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.JSONPath(eb.Column("meta")).Field("role").Text()
+		}, "role").
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.JSONPath(eb.Column("meta")).Field("interests").Index(0).Text()
+		}, "first_interest").
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.JSONPath(eb.Column("meta")).Field("interests").Index(-1).Text()
+		}, "last_interest").
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.JSONPath(eb.Column("meta")).Field("interests").Index(1).Text()
+		}, "second_interest").
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.JSONPath(eb.Column("meta")).Field("interests").Index(1).Exists()
+		}, "has_second").
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.JSONPath(eb.Column("meta")).Field("interests").Value()
+		}, "interests").
+		OrderBy("name").
+		Scan(suite.ctx, &results)
+
+	suite.Require().NoError(err, "JSONPath should work")
+	suite.Require().Len(results, 3)
+
+	suite.Equal("admin", results[0].Role)
+	suite.Equal("golang", results[0].FirstInterest)
+	suite.Equal("databases", results[0].LastInterest)
+	suite.True(results[0].HasSecond)
+	suite.JSONEq(`["golang", "databases"]`, results[0].Interests)
+
+	suite.Equal("viewer", results[2].Role)
+	suite.Equal("science", results[2].LastInterest)
+	suite.Nil(results[2].Second, "Missing element should be NULL")
+	suite.False(results[2].HasSecond)
+
+	suite.Run("FilterByPath", func() {
+		var users []User
+
+		err := suite.db.NewSelect().
+			Model(&users).
+			Where(func(cb ConditionBuilder) {
+				cb.Expr(func(eb ExprBuilder) any {
+					return eb.Equals(eb.JSONPath(eb.Column("meta")).Field("interests").Index(-1).Text(), "postgres")
+				})
+			}).
+			Scan(suite.ctx)
+
+		suite.Require().NoError(err, "Filtering by a JSON path should work")
+		suite.Require().Len(users, 1)
+		suite.Equal("Bob Smith", users[0].Name)
+	})
+
+	suite.Run("QuotedKeys", func() {
+		var exists bool
+
+		err := suite.db.NewSelect().
+			Model((*User)(nil)).
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.JSONPath(eb.Column("meta")).Field(`odd "key".name`).Exists()
+			}).
+			Limit(1).
+			Scan(suite.ctx, &exists)
+
+		suite.NoError(err, "Keys with special characters should be quoted")
+		suite.False(exists)
+	})
+}
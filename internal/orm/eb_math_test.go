@@ -1,5 +1,7 @@
 package orm
 
+import "github.com/ilxqx/vef-framework-go/constants"
+
 // MathFunctionsTestSuite tests mathematical function methods of ExprBuilder
 // including basic math operations, power/root functions, logarithmic functions,
 // trigonometric functions, constants, and comparison functions.
@@ -801,6 +803,98 @@ func (suite *MathFunctionsTestSuite) TestLeast() {
 	})
 }
 
+// TestWidthBucket tests the WidthBucket function.
+func (suite *MathFunctionsTestSuite) TestWidthBucket() {
+	suite.T().Logf("Testing WidthBucket function for %s", suite.dbType)
+
+	suite.Run("EqualWidthBuckets", func() {
+		type WidthBucketResult struct {
+			ViewCount int64 `bun:"view_count"`
+			Bucket    int64 `bun:"bucket"`
+		}
+
+		var results []WidthBucketResult
+
+		err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			Select("view_count").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.WidthBucket(eb.Column("view_count"), 50, 100, 5)
+			}, "bucket").
+			OrderBy("view_count").
+			Scan(suite.ctx, &results)
+
+		suite.NoError(err, "WidthBucket should work correctly")
+		suite.True(len(results) > 0, "Should have width bucket results")
+
+		for _, result := range results {
+			var expected int64
+
+			switch {
+			case result.ViewCount < 50:
+				expected = 0
+			case result.ViewCount >= 100:
+				expected = 6
+			default:
+				expected = (result.ViewCount-50)/10 + 1
+			}
+
+			suite.Equal(expected, result.Bucket, "WidthBucket should assign view_count %d to bucket %d", result.ViewCount, expected)
+			suite.T().Logf("ViewCount: %d, Bucket: %d", result.ViewCount, result.Bucket)
+		}
+	})
+
+	suite.Run("BucketBoundaries", func() {
+		boundaries := WidthBucketBoundaries(50, 100, 5)
+		suite.Len(boundaries, 5, "Should return one boundary per bucket")
+		suite.Equal(BucketBoundary{Bucket: 1, Lower: 50, Upper: 60}, boundaries[0], "First bucket should start at low")
+		suite.Equal(BucketBoundary{Bucket: 5, Lower: 90, Upper: 100}, boundaries[4], "Last bucket should end at high")
+
+		suite.Nil(WidthBucketBoundaries(100, 50, 5), "Should return nil for an inverted range")
+		suite.Nil(WidthBucketBoundaries(50, 100, 0), "Should return nil for non-positive buckets")
+	})
+}
+
+// TestFlagFunctions tests the SetFlag, ClearFlag and HasFlag functions.
+func (suite *MathFunctionsTestSuite) TestFlagFunctions() {
+	suite.T().Logf("Testing flag functions for %s", suite.dbType)
+
+	suite.Run("SetAndClearFlag", func() {
+		type FlagResult struct {
+			ViewCount int64 `bun:"view_count"`
+			Set       int64 `bun:"set_flag"`
+			Cleared   int64 `bun:"cleared_flag"`
+			HasFlag   bool  `bun:"has_flag"`
+		}
+
+		var results []FlagResult
+
+		err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			Select("view_count").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.SetFlag(eb.Column("view_count"), 4)
+			}, "set_flag").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.ClearFlag(eb.Column("view_count"), 1)
+			}, "cleared_flag").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.HasFlag(eb.Column("view_count"), 2)
+			}, "has_flag").
+			OrderBy("view_count").
+			Scan(suite.ctx, &results)
+
+		suite.NoError(err, "Flag functions should work correctly")
+		suite.True(len(results) > 0, "Should have flag results")
+
+		for _, result := range results {
+			suite.Equal(result.ViewCount|4, result.Set, "SetFlag should set bit 4 of %d", result.ViewCount)
+			suite.Equal(result.ViewCount&^1, result.Cleared, "ClearFlag should clear bit 1 of %d", result.ViewCount)
+			suite.Equal(result.ViewCount&2 == 2, result.HasFlag, "HasFlag should check bit 2 of %d", result.ViewCount)
+		}
+	})
+}
+
 // TestCombinedMathFunctions tests multiple math functions working together.
 func (suite *MathFunctionsTestSuite) TestCombinedMathFunctions() {
 	suite.T().Logf("Testing combined math functions for %s", suite.dbType)
@@ -970,3 +1064,86 @@ func (suite *MathFunctionsTestSuite) TestCombinedMathFunctions() {
 		}
 	})
 }
+
+// TestSpatial tests the spatial functions and the GeoNear condition with the distance of Beijing and Shanghai,
+// about 1067 km apart.
+func (suite *MathFunctionsTestSuite) TestSpatial() {
+	suite.T().Logf("Testing spatial functions for %s", suite.dbType)
+
+	if suite.dbType == constants.Postgres {
+		suite.T().Skip("PostGIS is not installed on the test database")
+	}
+
+	const (
+		beijingLat, beijingLng   = 39.9042, 116.4074
+		shanghaiLat, shanghaiLng = 31.2304, 121.4737
+	)
+
+	suite.Run("StDistance", func() {
+		var distance float64
+
+		err := suite.db.NewSelect().
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.StDistance(eb.Point(beijingLat, beijingLng), eb.Point(shanghaiLat, shanghaiLng))
+			}).
+			Scan(suite.ctx, &distance)
+
+		suite.Require().NoError(err, "StDistance should work")
+		suite.InDelta(1067000, distance, 5000, "StDistance should return the distance in meters")
+	})
+
+	suite.Run("GeoNear", func() {
+		var names []string
+
+		err := suite.db.NewSelect().
+			With("places", func(query SelectQuery) {
+				query.Model((*User)(nil)).
+					Select("name").
+					SelectExpr(func(eb ExprBuilder) any {
+						return eb.Case(func(cb CaseBuilder) {
+							cb.When(func(cb ConditionBuilder) {
+								cb.Equals("name", "Alice Johnson")
+							}).Then(eb.Point(beijingLat, beijingLng))
+							cb.Else(eb.Point(shanghaiLat, shanghaiLng))
+						})
+					}, "location")
+			}).
+			Table("places").
+			Select("name").
+			Where(func(cb ConditionBuilder) {
+				cb.GeoNear("location", 39.9, 116.4, 10000)
+			}).
+			Scan(suite.ctx, &names)
+
+		suite.Require().NoError(err, "GeoNear should work")
+		suite.Equal([]string{"Alice Johnson"}, names, "GeoNear should only match the locations within the radius")
+	})
+
+	suite.Run("StWithinAndStContains", func() {
+		if suite.dbType == constants.SQLite {
+			suite.T().Skip("SpatiaLite is not loaded on the test database")
+		}
+
+		const area = "POLYGON((116 39, 117 39, 117 41, 116 41, 116 39))"
+
+		type Result struct {
+			Within   bool `bun:"within"`
+			Contains bool `bun:"contains"`
+		}
+
+		var result Result
+
+		err := suite.db.NewSelect().
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.StWithin(eb.Point(beijingLat, beijingLng), eb.Expr("ST_GeomFromText(?)", area))
+			}, "within").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.StContains(eb.Expr("ST_GeomFromText(?)", area), eb.Point(shanghaiLat, shanghaiLng))
+			}, "contains").
+			Scan(suite.ctx, &result)
+
+		suite.Require().NoError(err, "StWithin and StContains should work")
+		suite.True(result.Within, "Beijing should lie within the area")
+		suite.False(result.Contains, "The area should not contain Shanghai")
+	})
+}
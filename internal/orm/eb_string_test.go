@@ -1,14 +1,25 @@
 package orm
 
 import (
+	"bytes"
+	"crypto/aes"
+	"crypto/md5"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"hash/crc32"
 	"strings"
 
+	"github.com/uptrace/bun"
+
 	"github.com/ilxqx/vef-framework-go/constants"
 )
 
 // StringFunctionsTestSuite tests string manipulation methods of ExprBuilder
 // including Concat, ConcatWithSep, SubString, Upper, Lower, Trim, TrimLeft,
-// TrimRight, Length, CharLength, Position, Left, Right, Repeat, Replace, and Reverse.
+// TrimRight, Length, CharLength, Position, Left, Right, Repeat, Replace, Reverse,
+// the hashing functions Md5, Sha256, and Crc32, and the encryption functions
+// PgpEncrypt, PgpDecrypt, AesEncrypt, and AesDecrypt.
 //
 // This suite verifies cross-database compatibility for string functions across
 // PostgreSQL, MySQL, and SQLite, handling database-specific limitations appropriately.
@@ -952,3 +963,287 @@ func (suite *StringFunctionsTestSuite) TestEndsWithIgnoreCase() {
 		}
 	})
 }
+
+// TestHashing tests the Md5, Sha256 and Crc32 functions against the digests computed in Go.
+func (suite *StringFunctionsTestSuite) TestHashing() {
+	suite.T().Logf("Testing hashing functions for %s", suite.dbType)
+
+	suite.Run("DigestsMatchGo", func() {
+		type HashResult struct {
+			Title  string `bun:"title"`
+			Md5    string `bun:"md5"`
+			Sha256 string `bun:"sha256"`
+			Crc32  int64  `bun:"crc32"`
+		}
+
+		var hashResults []HashResult
+
+		err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			Select("title").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.Md5(eb.Column("title"))
+			}, "md5").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.Sha256(eb.Column("title"))
+			}, "sha256").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.Crc32(eb.Column("title"))
+			}, "crc32").
+			OrderBy("title").
+			Scan(suite.ctx, &hashResults)
+
+		suite.Require().NoError(err, "Hashing functions should work")
+		suite.NotEmpty(hashResults, "Should have hash results")
+
+		for _, result := range hashResults {
+			md5Sum := md5.Sum([]byte(result.Title))
+			sha256Sum := sha256.Sum256([]byte(result.Title))
+
+			suite.Equal(hex.EncodeToString(md5Sum[:]), result.Md5, "Md5 should be the lowercase hex digest")
+			suite.Equal(hex.EncodeToString(sha256Sum[:]), result.Sha256, "Sha256 should be the lowercase hex digest")
+			suite.Equal(int64(crc32.ChecksumIEEE([]byte(result.Title))), result.Crc32, "Crc32 should be the unsigned IEEE checksum")
+		}
+	})
+
+	suite.Run("FilterByDigest", func() {
+		var post Post
+
+		suite.Require().NoError(suite.db.NewSelect().Model(&post).OrderBy("title").Limit(1).Scan(suite.ctx))
+
+		sum := md5.Sum([]byte(post.Title))
+
+		var matched []Post
+
+		err := suite.db.NewSelect().
+			Model(&matched).
+			Where(func(cb ConditionBuilder) {
+				cb.Expr(func(eb ExprBuilder) any {
+					return eb.Equals(eb.Md5(eb.Column("title")), hex.EncodeToString(sum[:]))
+				})
+			}).
+			Scan(suite.ctx)
+
+		suite.Require().NoError(err, "Md5 should work in conditions")
+		suite.NotEmpty(matched, "Should find the post by the digest of its title")
+
+		for _, result := range matched {
+			suite.Equal(post.Title, result.Title, "Only posts with the same title should match")
+		}
+	})
+}
+
+// TestEncryption tests the AesEncrypt, AesDecrypt, PgpEncrypt and PgpDecrypt functions with keys resolved from the secret provider.
+func (suite *StringFunctionsTestSuite) TestEncryption() {
+	suite.T().Logf("Testing encryption functions for %s", suite.dbType)
+
+	const aesKey = "0123456789abcdef"
+
+	SetSecretProvider(SecretProviderFunc(func(name string) (string, error) {
+		if name == "TEST_AES_KEY" {
+			return aesKey, nil
+		}
+
+		return EnvSecretProvider.Secret(name)
+	}))
+	defer SetSecretProvider(nil)
+
+	if suite.dbType == constants.Postgres {
+		_, err := suite.getBunDB().ExecContext(suite.ctx, "CREATE EXTENSION IF NOT EXISTS pgcrypto")
+		suite.Require().NoError(err, "Should create the pgcrypto extension")
+	}
+
+	suite.Run("AesRoundTrip", func() {
+		type AesResult struct {
+			Title     string `bun:"title"`
+			Encrypted []byte `bun:"encrypted"`
+			Decrypted string `bun:"decrypted"`
+		}
+
+		var aesResults []AesResult
+
+		err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			Select("title").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.AesEncrypt(eb.Column("title"), SecretKey("TEST_AES_KEY"))
+			}, "encrypted").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.AesDecrypt(eb.AesEncrypt(eb.Column("title"), SecretKey("TEST_AES_KEY")), SecretKey("TEST_AES_KEY"))
+			}, "decrypted").
+			OrderBy("title").
+			Scan(suite.ctx, &aesResults)
+
+		suite.Require().NoError(err, "AES functions should work")
+		suite.NotEmpty(aesResults, "Should have AES results")
+
+		for _, result := range aesResults {
+			suite.Equal(result.Title, result.Decrypted, "Decrypting should restore the title")
+			suite.Equal(aesECBEncrypt([]byte(result.Title), []byte(aesKey)), result.Encrypted, "Values should be encrypted with AES-128 in ECB mode")
+		}
+	})
+
+	suite.Run("AesWrongKey", func() {
+		if suite.dbType == constants.Postgres {
+			suite.T().Skip("PostgreSQL fails decrypting with a wrong key")
+		}
+
+		var decrypted []sql.NullString
+
+		err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.AesDecrypt(eb.AesEncrypt(eb.Column("title"), SecretKey("TEST_AES_KEY")), "fedcba9876543210")
+			}, "decrypted").
+			Scan(suite.ctx, &decrypted)
+
+		suite.Require().NoError(err, "Decrypting with a wrong key should not fail")
+		suite.NotEmpty(decrypted, "Should have results")
+
+		for _, value := range decrypted {
+			suite.False(value.Valid, "Decrypting with a wrong key should return NULL")
+		}
+	})
+
+	suite.Run("KeyRedacted", func() {
+		hook := new(recordingQueryHook)
+
+		_, err := suite.db.WithQueryHooks(hook).NewSelect().
+			Model((*Post)(nil)).
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.AesEncrypt(eb.Column("title"), SecretKey("TEST_AES_KEY"))
+			}, "encrypted").
+			Count(suite.ctx)
+
+		suite.Require().NoError(err)
+		suite.Require().Len(hook.after, 1)
+		suite.NotContains(hook.after[0].Query, aesKey, "Keys should be masked in observed statements")
+	})
+
+	suite.Run("MissingSecret", func() {
+		var encrypted [][]byte
+
+		err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.AesEncrypt(eb.Column("title"), SecretKey("VEF_TEST_MISSING_KEY"))
+			}, "encrypted").
+			Scan(suite.ctx, &encrypted)
+
+		suite.ErrorIs(err, ErrSecretNotFound, "Unresolved secrets should fail the statement")
+	})
+
+	suite.Run("PgpRoundTrip", func() {
+		var decrypted []string
+
+		err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.PgpDecrypt(eb.PgpEncrypt(eb.Column("title"), SecretKey("TEST_AES_KEY")), SecretKey("TEST_AES_KEY"))
+			}, "decrypted").
+			OrderBy("title").
+			Scan(suite.ctx, &decrypted)
+
+		if suite.dbType != constants.Postgres {
+			suite.ErrorIs(err, ErrDialectUnsupportedOperation, "OpenPGP encryption should require PostgreSQL")
+
+			return
+		}
+
+		suite.Require().NoError(err, "PGP functions should work")
+		suite.NotEmpty(decrypted, "Should have PGP results")
+	})
+}
+
+// aesECBEncrypt encrypts as MySQL's AES_ENCRYPT does with a 16-byte key.
+func aesECBEncrypt(plaintext, key []byte) []byte {
+	block, _ := aes.NewCipher(key)
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := append(bytes.Clone(plaintext), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	for i := 0; i < len(ciphertext); i += aes.BlockSize {
+		block.Encrypt(ciphertext[i:i+aes.BlockSize], ciphertext[i:i+aes.BlockSize])
+	}
+
+	return ciphertext
+}
+
+// TestFullText tests the Match and MatchRank functions and the MatchesFullText condition
+// on a dedicated table: a plain table on PostgreSQL, one with a FULLTEXT index on MySQL and an FTS5 table on SQLite.
+func (suite *StringFunctionsTestSuite) TestFullText() {
+	suite.T().Logf("Testing full-text search functions for %s", suite.dbType)
+
+	type FullTextDoc struct {
+		bun.BaseModel `bun:"table:test_fulltext_doc,alias:tfd"`
+
+		ID   string `bun:"id,pk"`
+		Body string `bun:"body"`
+	}
+
+	bunDB := suite.getBunDB()
+
+	_, err := bunDB.NewDropTable().Model((*FullTextDoc)(nil)).IfExists().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should drop existing full-text table")
+
+	switch suite.dbType {
+	case constants.SQLite:
+		_, err = bunDB.ExecContext(suite.ctx, "CREATE VIRTUAL TABLE test_fulltext_doc USING fts5(id UNINDEXED, body)")
+	case constants.MySQL:
+		if _, err = bunDB.NewCreateTable().Model((*FullTextDoc)(nil)).Exec(suite.ctx); err == nil {
+			_, err = bunDB.ExecContext(suite.ctx, "ALTER TABLE test_fulltext_doc ADD FULLTEXT INDEX idx_test_fulltext_doc_body (body)")
+		}
+	default:
+		_, err = bunDB.NewCreateTable().Model((*FullTextDoc)(nil)).Exec(suite.ctx)
+	}
+
+	suite.Require().NoError(err, "Should create full-text table")
+
+	defer func() {
+		_, err := bunDB.NewDropTable().Model((*FullTextDoc)(nil)).IfExists().Exec(suite.ctx)
+		suite.NoError(err, "Should cleanup full-text table")
+	}()
+
+	docs := []FullTextDoc{
+		{ID: "d1", Body: "database indexing strategies for database engines"},
+		{ID: "d2", Body: "cooking recipes for the weekend"},
+		{ID: "d3", Body: "scaling a database cluster"},
+	}
+	_, err = bunDB.NewInsert().Model(&docs).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert documents")
+
+	suite.Run("MatchesFullText", func() {
+		var ids []string
+
+		err := suite.db.NewSelect().
+			Model((*FullTextDoc)(nil)).
+			Select("id").
+			Where(func(cb ConditionBuilder) {
+				cb.MatchesFullText("body", "database")
+			}).
+			OrderBy("id").
+			Scan(suite.ctx, &ids)
+		suite.Require().NoError(err, "MatchesFullText should work")
+		suite.Equal([]string{"d1", "d3"}, ids, "Should match documents containing the term")
+	})
+
+	suite.Run("OrderByRank", func() {
+		var ids []string
+
+		err := suite.db.NewSelect().
+			Model((*FullTextDoc)(nil)).
+			Select("id").
+			Where(func(cb ConditionBuilder) {
+				cb.Expr(func(eb ExprBuilder) any {
+					return eb.Match(eb.Column("body"), "database")
+				})
+			}).
+			OrderByExpr(func(eb ExprBuilder) any {
+				return eb.Order(func(ob OrderBuilder) {
+					ob.Expr(eb.MatchRank(eb.Column("body"), "database")).Desc()
+				})
+			}).
+			Scan(suite.ctx, &ids)
+		suite.Require().NoError(err, "Ordering by MatchRank should work")
+		suite.Equal([]string{"d1", "d3"}, ids, "Document mentioning the term twice should rank first")
+	})
+}
@@ -1,7 +1,7 @@
 package orm
 
 // UtilityFunctionsTestSuite tests utility expression methods of ExprBuilder
-// including Decode and other utility functions.
+// including Decode, Row and other utility functions.
 type UtilityFunctionsTestSuite struct {
 	*OrmTestSuite
 }
@@ -415,3 +415,42 @@ func (suite *UtilityFunctionsTestSuite) TestDecode() {
 		}
 	})
 }
+
+// TestRow tests the Row constructor in comparisons of several columns at once.
+func (suite *UtilityFunctionsTestSuite) TestRow() {
+	suite.T().Logf("Testing Row constructor for %s", suite.dbType)
+
+	names := func(builder func(eb ExprBuilder) any) []string {
+		var names []string
+
+		suite.Require().NoError(suite.db.NewSelect().
+			Model((*User)(nil)).
+			Select("name").
+			Where(func(cb ConditionBuilder) {
+				cb.Expr(builder)
+			}).
+			OrderBy("name").
+			Scan(suite.ctx, &names), "Row comparison should work")
+
+		return names
+	}
+
+	suite.Run("Equals", func() {
+		suite.Equal([]string{"Bob Smith"}, names(func(eb ExprBuilder) any {
+			return eb.Expr("? = ?", eb.Row(eb.Column("name"), eb.Column("age")), eb.Row("Bob Smith", 25))
+		}))
+		suite.Empty(names(func(eb ExprBuilder) any {
+			return eb.Expr("? = ?", eb.Row(eb.Column("name"), eb.Column("age")), eb.Row([]any{"Bob Smith", 30}))
+		}), "All the values of the rows should match")
+	})
+
+	suite.Run("In", func() {
+		suite.Equal([]string{"Alice Johnson", "Charlie Brown"}, names(func(eb ExprBuilder) any {
+			return eb.Expr(
+				"? IN (?)",
+				eb.Row(eb.Column("name"), eb.Column("age")),
+				eb.Exprs(eb.Row("Alice Johnson", 30), eb.Row("Charlie Brown", 35), eb.Row("Bob Smith", 30)),
+			)
+		}))
+	})
+}
@@ -1181,3 +1181,123 @@ func (suite *WindowFunctionsTestSuite) TestWinBoolAnd() {
 		}
 	})
 }
+
+// TestWindowFrames tests RANGE and GROUPS frames and the EXCLUDE clause.
+// MySQL falls back to RANGE for GROUPS frames without offsets and rejects other GROUPS frames and exclusions.
+func (suite *WindowFunctionsTestSuite) TestWindowFrames() {
+	suite.T().Logf("Testing window frames for %s", suite.dbType)
+
+	type PostWithFrame struct {
+		ID         string `bun:"id"`
+		Status     string `bun:"status"`
+		ViewCount  int64  `bun:"view_count"`
+		FrameValue int64  `bun:"frame_value"`
+	}
+
+	selectFrame := func(frame func(eb ExprBuilder) any) ([]PostWithFrame, error) {
+		var posts []PostWithFrame
+
+		err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			Select("id", "status", "view_count").
+			SelectExpr(frame, "frame_value").
+			OrderBy("status", "id").
+			Scan(suite.ctx, &posts)
+
+		return posts, err
+	}
+
+	countByStatus := func(posts []PostWithFrame) map[string]int64 {
+		counts := make(map[string]int64)
+		for _, post := range posts {
+			counts[post.Status]++
+		}
+
+		return counts
+	}
+
+	suite.Run("RangeIncludesPeers", func() {
+		posts, err := selectFrame(func(eb ExprBuilder) any {
+			return eb.WinCount(func(wcb WindowCountBuilder) {
+				wcb.All().Over().OrderBy("status").Range().UnboundedPreceding().And().CurrentRow()
+			})
+		})
+		suite.Require().NoError(err, "RANGE frame should work")
+		suite.Require().NotEmpty(posts, "Should have posts")
+
+		counts := countByStatus(posts)
+		for _, post := range posts {
+			var expected int64
+			for status, count := range counts {
+				if status <= post.Status {
+					expected += count
+				}
+			}
+
+			suite.Equal(expected, post.FrameValue, "RANGE frame should end with the last peer of the current row")
+		}
+	})
+
+	suite.Run("GroupsCurrentRow", func() {
+		posts, err := selectFrame(func(eb ExprBuilder) any {
+			return eb.WinCount(func(wcb WindowCountBuilder) {
+				wcb.All().Over().OrderBy("status").Groups().CurrentRow().And().CurrentRow()
+			})
+		})
+		suite.Require().NoError(err, "GROUPS frame without offsets should work on every database")
+
+		counts := countByStatus(posts)
+		for _, post := range posts {
+			suite.Equal(counts[post.Status], post.FrameValue, "GROUPS CURRENT ROW frame should cover the peers of the current row")
+		}
+	})
+
+	suite.Run("GroupsWithOffset", func() {
+		_, err := selectFrame(func(eb ExprBuilder) any {
+			return eb.WinCount(func(wcb WindowCountBuilder) {
+				wcb.All().Over().OrderBy("status").Groups().Preceding(1).And().CurrentRow()
+			})
+		})
+
+		if suite.dbType == constants.MySQL {
+			suite.ErrorIs(err, ErrDialectUnsupportedOperation, "MySQL should reject GROUPS frames with offsets")
+
+			return
+		}
+
+		suite.NoError(err, "GROUPS frame with offsets should work")
+	})
+
+	suite.Run("ExcludeCurrentRow", func() {
+		posts, err := selectFrame(func(eb ExprBuilder) any {
+			return eb.WinCount(func(wcb WindowCountBuilder) {
+				wcb.All().Over().OrderBy("id").Rows().UnboundedPreceding().And().UnboundedFollowing().ExcludeCurrentRow()
+			})
+		})
+
+		if suite.dbType == constants.MySQL {
+			suite.ErrorIs(err, ErrDialectUnsupportedOperation, "MySQL should reject frame exclusions")
+
+			return
+		}
+
+		suite.Require().NoError(err, "EXCLUDE CURRENT ROW should work")
+
+		for _, post := range posts {
+			suite.Equal(int64(len(posts)-1), post.FrameValue, "Frame should exclude the current row")
+		}
+	})
+
+	suite.Run("ExcludeNoOthers", func() {
+		posts, err := selectFrame(func(eb ExprBuilder) any {
+			return eb.WinCount(func(wcb WindowCountBuilder) {
+				wcb.All().Over().OrderBy("id").Rows().UnboundedPreceding().And().UnboundedFollowing().ExcludeNoOthers()
+			})
+		})
+		suite.Require().NoError(err, "EXCLUDE NO OTHERS should work on every database")
+
+		for _, post := range posts {
+			suite.Equal(int64(len(posts)), post.FrameValue, "Frame should include every row")
+		}
+	})
+}
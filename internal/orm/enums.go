@@ -14,7 +14,7 @@ const (
 	JoinInner
 	JoinLeft
 	JoinRight
-	JoinFull // Note: Not supported by SQLite
+	JoinFull // Note: Emulated on MySQL
 	JoinCross
 )
 
@@ -132,6 +132,32 @@ func (f FrameType) String() string {
 	}
 }
 
+// FrameExclusion specifies the rows excluded from a window frame.
+type FrameExclusion int
+
+const (
+	FrameExcludeDefault FrameExclusion = iota
+	FrameExcludeCurrentRow
+	FrameExcludeGroup
+	FrameExcludeTies
+	FrameExcludeNoOthers
+)
+
+func (f FrameExclusion) String() string {
+	switch f {
+	case FrameExcludeCurrentRow:
+		return "EXCLUDE CURRENT ROW"
+	case FrameExcludeGroup:
+		return "EXCLUDE GROUP"
+	case FrameExcludeTies:
+		return "EXCLUDE TIES"
+	case FrameExcludeNoOthers:
+		return "EXCLUDE NO OTHERS"
+	default:
+		return constants.Empty
+	}
+}
+
 // FrameBoundKind specifies the bound type in a window frame.
 type FrameBoundKind int
 
@@ -280,3 +306,11 @@ func (u DateTimeUnit) ForDateTrunc() string {
 		return "day"
 	}
 }
+
+// TreeDirection specifies the direction a tree query walks from its root rows.
+type TreeDirection int
+
+const (
+	TreeDescendants TreeDirection = iota
+	TreeAncestors
+)
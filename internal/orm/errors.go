@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/ilxqx/vef-framework-go/dbhelpers"
+	"github.com/ilxqx/vef-framework-go/internal/database/querypolicy"
 	"github.com/ilxqx/vef-framework-go/result"
 )
 
@@ -16,6 +17,26 @@ var (
 	ErrMissingColumnOrExpression    = errors.New("order clause requires at least one column or expression")
 	ErrModelMustBePointerToStruct   = errors.New("model must be a pointer to struct")
 	ErrPrimaryKeyUnsupportedType    = errors.New("unsupported primary key type")
+	ErrMissingWhereClause           = errors.New("update and delete queries require a where clause; call AllowFullTable to affect all rows")
+	ErrMissingResultSet             = errors.New("query returned fewer result sets than scan destinations")
+	ErrInvalidScanDest              = errors.New("scan destination must be a non-nil pointer")
+	ErrInvalidProcParam             = errors.New("procedure output parameter must be a non-nil pointer")
+	ErrUpsertMissingModel           = errors.New("upsert requires a model; call Model before executing")
+	ErrUpsertMissingConflictColumns = errors.New("upsert requires conflict columns when the model has no primary key")
+	ErrCascadeMissingModel          = errors.New("cascading delete requires a model to read the relations from")
+	ErrInvalidSummary               = errors.New("invalid summary column declaration")
+	ErrInvalidDenormalization       = errors.New("invalid denormalized column declaration")
+	ErrTreeMissingModel             = errors.New("tree query requires a model; call Model before WithTree")
+	ErrTreeMissingKey               = errors.New("tree query requires a key column when the model has no single-column primary key")
+	ErrAliasCollision               = errors.New("table alias collision")
+	ErrHistoryMissingPrimaryKey     = errors.New("history requires a model with a primary key")
+	ErrTenantRequired               = errors.New("statement requires a tenant but the request acts for none")
+	ErrDataScopeContextMissing      = errors.New("statement is filtered by data scopes but the context carries no data scope context")
+	ErrInvalidTenant                = errors.New("tenant ID must consist of letters, digits and underscores to name a schema")
+	ErrSecretNotFound               = errors.New("secret not found")
+	ErrUnknownIDGenerator           = errors.New("unknown id generator")
+	ErrColumnValueConversion        = errors.New("failed to convert column value")
+	ErrPolicyViolation              = querypolicy.ErrPolicyViolation
 )
 
 // translateWriteError converts database-specific errors to framework errors.
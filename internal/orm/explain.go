@@ -0,0 +1,367 @@
+package orm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// ExplainOptions configures SelectQuery.Explain.
+type ExplainOptions struct {
+	// Analyze executes the query to report the actual rows and timings of the plan nodes, PostgreSQL only.
+	// Other dialects explain the query without executing it.
+	Analyze bool
+}
+
+// QueryPlan is the execution plan of a query, parsed from the EXPLAIN output of the dialect.
+type QueryPlan struct {
+	// Dialect is the database dialect the plan was produced by.
+	Dialect string `json:"dialect"`
+	// Nodes are the root nodes of the plan.
+	Nodes []*PlanNode `json:"nodes"`
+	// Cost is the estimated total cost, zero for dialects without cost estimates (SQLite).
+	Cost float64 `json:"cost"`
+	// Raw is the EXPLAIN output as returned by the database: JSON on PostgreSQL and MySQL, the detail lines on SQLite.
+	Raw string `json:"raw"`
+}
+
+// PlanNode is an operation of a query plan.
+type PlanNode struct {
+	// Operation is the kind of the node, e.g. "Seq Scan" on PostgreSQL, the access type "ALL" on MySQL or "SCAN" on SQLite.
+	Operation string `json:"operation"`
+	// Table is the table (or alias, as reported by the dialect) the node reads, empty for other operations.
+	Table string `json:"table,omitempty"`
+	// Index is the index the node reads the table through, empty for table scans.
+	Index string `json:"index,omitempty"`
+	// Detail describes the node in one line, e.g. "SEARCH u USING INDEX idx_email (email=?)".
+	Detail string `json:"detail"`
+	// FullScan reports whether the node reads its whole table without an index.
+	FullScan bool `json:"fullScan"`
+	// Cost is the estimated cost of the node, zero when not reported.
+	Cost float64 `json:"cost,omitempty"`
+	// Rows is the estimated number of rows the node produces, zero when not reported.
+	Rows float64 `json:"rows,omitempty"`
+	// ActualRows is the number of rows the node produced, reported when analyzed.
+	ActualRows float64 `json:"actualRows,omitempty"`
+	// ActualTime is the time the node took, reported when analyzed.
+	ActualTime time.Duration `json:"actualTime,omitempty"`
+	// Children are the nodes feeding the node.
+	Children []*PlanNode `json:"children,omitempty"`
+}
+
+// Walk calls fn for the nodes of the plan in depth-first order with their depth, 0 for the root nodes.
+func (p *QueryPlan) Walk(fn func(node *PlanNode, depth int)) {
+	var walk func(nodes []*PlanNode, depth int)
+	walk = func(nodes []*PlanNode, depth int) {
+		for _, node := range nodes {
+			fn(node, depth)
+			walk(node.Children, depth+1)
+		}
+	}
+
+	walk(p.Nodes, 0)
+}
+
+// FullScans returns the tables read by a full table scan.
+func (p *QueryPlan) FullScans() []string {
+	tables := []string{}
+	p.Walk(func(node *PlanNode, _ int) {
+		if node.FullScan && !slices.Contains(tables, node.Table) {
+			tables = append(tables, node.Table)
+		}
+	})
+
+	return tables
+}
+
+// String renders the plan as indented detail lines.
+func (p *QueryPlan) String() string {
+	var sb strings.Builder
+
+	p.Walk(func(node *PlanNode, depth int) {
+		sb.WriteString(strings.Repeat("  ", depth))
+		sb.WriteString(node.Detail)
+		sb.WriteByte('\n')
+	})
+
+	return sb.String()
+}
+
+// sqlitePlanRow is a row of SQLite's EXPLAIN QUERY PLAN output.
+type sqlitePlanRow struct {
+	ID      int    `bun:"id"`
+	Parent  int    `bun:"parent"`
+	NotUsed int    `bun:"notused"`
+	Detail  string `bun:"detail"`
+}
+
+// explainQuery explains the SQL of a select query on the connection.
+func explainQuery(ctx context.Context, conn bun.IDB, query string, options ExplainOptions) (*QueryPlan, error) {
+	name := conn.Dialect().Name()
+	plan := &QueryPlan{Dialect: name.String(), Nodes: []*PlanNode{}}
+
+	switch name {
+	case dialect.PG:
+		format := "EXPLAIN (FORMAT JSON) ?"
+		if options.Analyze {
+			format = "EXPLAIN (ANALYZE, FORMAT JSON) ?"
+		}
+
+		if err := conn.NewRaw(format, bun.Safe(query)).Scan(ctx, &plan.Raw); err != nil {
+			return nil, contextError(ctx, err)
+		}
+
+		if err := parsePostgresPlan(plan); err != nil {
+			return nil, err
+		}
+
+	case dialect.MySQL:
+		if err := conn.NewRaw("EXPLAIN FORMAT=JSON ?", bun.Safe(query)).Scan(ctx, &plan.Raw); err != nil {
+			return nil, contextError(ctx, err)
+		}
+
+		if err := parseMySQLPlan(plan); err != nil {
+			return nil, err
+		}
+
+	case dialect.SQLite:
+		var rows []sqlitePlanRow
+		if err := conn.NewRaw("EXPLAIN QUERY PLAN ?", bun.Safe(query)).Scan(ctx, &rows); err != nil {
+			return nil, contextError(ctx, err)
+		}
+
+		parseSQLitePlan(rows, plan)
+
+	default:
+		return nil, fmt.Errorf("%w: EXPLAIN on %s", ErrDialectUnsupportedOperation, name)
+	}
+
+	return plan, nil
+}
+
+// parsePostgresPlan builds the plan tree from the nested plans of the JSON output.
+func parsePostgresPlan(plan *QueryPlan) error {
+	var explained []struct {
+		Plan map[string]any `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan.Raw), &explained); err != nil {
+		return fmt.Errorf("failed to parse PostgreSQL plan: %w", err)
+	}
+
+	if len(explained) == 0 {
+		return nil
+	}
+
+	var parse func(fields map[string]any) *PlanNode
+	parse = func(fields map[string]any) *PlanNode {
+		node := &PlanNode{}
+		node.Operation, _ = fields["Node Type"].(string)
+		node.Table, _ = fields["Relation Name"].(string)
+		node.Index, _ = fields["Index Name"].(string)
+		node.Cost, _ = fields["Total Cost"].(float64)
+		node.Rows, _ = fields["Plan Rows"].(float64)
+		node.ActualRows, _ = fields["Actual Rows"].(float64)
+		node.FullScan = node.Operation == "Seq Scan"
+
+		if millis, ok := fields["Actual Total Time"].(float64); ok {
+			node.ActualTime = time.Duration(millis * float64(time.Millisecond))
+		}
+
+		node.Detail = describePlanNode(node.Operation, node.Table, node.Index)
+
+		children, _ := fields["Plans"].([]any)
+		for _, child := range children {
+			if childFields, ok := child.(map[string]any); ok {
+				node.Children = append(node.Children, parse(childFields))
+			}
+		}
+
+		return node
+	}
+
+	root := parse(explained[0].Plan)
+	plan.Cost = root.Cost
+	plan.Nodes = append(plan.Nodes, root)
+
+	return nil
+}
+
+// parseMySQLPlan builds the plan tree from the query block of the JSON output. Table accesses and the operations
+// wrapping them (nested loops, ordering, grouping, ...) become nodes; access type ALL denotes a full table scan.
+func parseMySQLPlan(plan *QueryPlan) error {
+	var explained struct {
+		QueryBlock map[string]any `json:"query_block"`
+	}
+	if err := json.Unmarshal([]byte(plan.Raw), &explained); err != nil {
+		return fmt.Errorf("failed to parse MySQL plan: %w", err)
+	}
+
+	plan.Cost = mysqlCost(explained.QueryBlock, "query_cost")
+
+	root := &PlanNode{}
+
+	var walk func(value any, parent *PlanNode)
+	walk = func(value any, parent *PlanNode) {
+		switch v := value.(type) {
+		case []any:
+			for _, item := range v {
+				walk(item, parent)
+			}
+
+		case map[string]any:
+			// Map iteration order is random, sort keys to keep the plan stable
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+
+			slices.Sort(keys)
+
+			for _, key := range keys {
+				switch {
+				case key == "table":
+					table, _ := v[key].(map[string]any)
+					node := &PlanNode{}
+					node.Operation, _ = table["access_type"].(string)
+					node.Table, _ = table["table_name"].(string)
+					node.Index, _ = table["key"].(string)
+					node.Cost = mysqlCost(table, "prefix_cost")
+					node.Rows, _ = table["rows_produced_per_join"].(float64)
+					node.FullScan = node.Operation == "ALL"
+					node.Detail = describePlanNode(node.Operation, node.Table, node.Index)
+					parent.Children = append(parent.Children, node)
+					walk(table, node)
+
+				case key == "nested_loop" || strings.HasSuffix(key, "_operation"):
+					node := &PlanNode{Operation: key, Detail: key}
+					parent.Children = append(parent.Children, node)
+					walk(v[key], node)
+
+				default:
+					switch v[key].(type) {
+					case map[string]any, []any:
+						walk(v[key], parent)
+					}
+				}
+			}
+		}
+	}
+
+	walk(explained.QueryBlock, root)
+	plan.Nodes = append(plan.Nodes, root.Children...)
+
+	return nil
+}
+
+// mysqlCost reads a cost of the cost info of a MySQL plan object, which reports costs as strings.
+func mysqlCost(fields map[string]any, name string) float64 {
+	costInfo, _ := fields["cost_info"].(map[string]any)
+	raw, _ := costInfo[name].(string)
+	cost, _ := strconv.ParseFloat(raw, 64)
+
+	return cost
+}
+
+// parseSQLitePlan rebuilds the plan tree from the parent links of the rows.
+// A SCAN without an index is a full table scan.
+func parseSQLitePlan(rows []sqlitePlanRow, plan *QueryPlan) {
+	nodes := make(map[int]*PlanNode, len(rows))
+	details := make([]string, 0, len(rows))
+
+	for _, row := range rows {
+		node := parseSQLitePlanDetail(row.Detail)
+		nodes[row.ID] = node
+		details = append(details, row.Detail)
+
+		if parent, ok := nodes[row.Parent]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			plan.Nodes = append(plan.Nodes, node)
+		}
+	}
+
+	plan.Raw = strings.Join(details, "\n")
+}
+
+// parseSQLitePlanDetail parses a detail line such as "SCAN u", "SCAN TABLE test_user AS u" (before SQLite 3.36)
+// or "SEARCH u USING INDEX idx_email (email=?)".
+func parseSQLitePlanDetail(detail string) *PlanNode {
+	node := &PlanNode{Operation: detail, Detail: detail}
+
+	operation, target, found := strings.Cut(detail, constants.Space)
+	if !found || (operation != "SCAN" && operation != "SEARCH") {
+		return node
+	}
+
+	node.Operation = operation
+
+	// Constant rows and materialized subqueries are no tables
+	if strings.HasPrefix(target, "CONSTANT ROW") || strings.HasPrefix(target, "(") {
+		return node
+	}
+
+	target = strings.TrimPrefix(target, "TABLE ")
+	node.Table, target, _ = strings.Cut(target, constants.Space)
+
+	if _, index, ok := strings.Cut(target, "INDEX "); ok {
+		node.Index, _, _ = strings.Cut(index, constants.Space)
+	} else if strings.Contains(target, "PRIMARY KEY") {
+		node.Index = "PRIMARY KEY"
+	}
+
+	node.FullScan = operation == "SCAN" && node.Index == constants.Empty
+
+	return node
+}
+
+// describePlanNode renders the operation of a node on its table through its index in one line.
+func describePlanNode(operation, table, index string) string {
+	detail := operation
+	if table != constants.Empty {
+		detail += " on " + table
+	}
+
+	if index != constants.Empty {
+		detail += " using " + index
+	}
+
+	return detail
+}
+
+func (q *BunSelectQuery) Explain(ctx context.Context, options ExplainOptions) (*QueryPlan, error) {
+	if q.isSubQuery {
+		return nil, ErrSubQuery
+	}
+
+	q.applySelectState()
+
+	if err := q.applyScopes(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := q.applySampleState(ctx); err != nil {
+		return nil, err
+	}
+
+	query, err := q.query.AppendQuery(q.db.getBunDB().QueryGen(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tenant schemas have pools of their own, explain on the connection the query would run on
+	conn, _, err := q.db.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return explainQuery(ctx, conn, string(query), options)
+}
@@ -1,10 +1,12 @@
 package orm
 
 import (
+	"encoding/json"
 	"strings"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/schema"
 
 	"github.com/ilxqx/vef-framework-go/constants"
@@ -30,14 +32,14 @@ func (b *QueryExprBuilder) Column(column string, withTableAlias ...bool) schema.
 			return b.Expr(sb.String(), bun.Name(name))
 		}
 
-		return b.Expr("?.?", bun.Name(alias), bun.Name(name))
+		return columnExpr{QueryAppender: b.Expr("?.?", bun.Name(alias), bun.Name(name)), column: column}
 	}
 
 	if needTableAlias && b.qb.GetTable() != nil {
-		return b.Expr("?TableAlias.?", bun.Name(column))
+		return columnExpr{QueryAppender: b.Expr("?TableAlias.?", bun.Name(column)), column: column}
 	}
 
-	return b.Expr("?", bun.Name(column))
+	return columnExpr{QueryAppender: b.Expr("?", bun.Name(column)), column: column}
 }
 
 func (b *QueryExprBuilder) TableColumns(withTableAlias ...bool) schema.QueryAppender {
@@ -435,6 +437,24 @@ func (b *QueryExprBuilder) AvgColumn(column string, distinct ...bool) schema.Que
 	})
 }
 
+func (b *QueryExprBuilder) CountIf(condition func(ConditionBuilder)) schema.QueryAppender {
+	return b.Count(func(cb CountBuilder) {
+		cb.All().Filter(condition)
+	})
+}
+
+func (b *QueryExprBuilder) SumIf(column string, condition func(ConditionBuilder)) schema.QueryAppender {
+	return b.Sum(func(sb SumBuilder) {
+		sb.Column(column).Filter(condition)
+	})
+}
+
+func (b *QueryExprBuilder) AvgIf(column string, condition func(ConditionBuilder)) schema.QueryAppender {
+	return b.Avg(func(ab AvgBuilder) {
+		ab.Column(column).Filter(condition)
+	})
+}
+
 func (b *QueryExprBuilder) Min(builder func(MinBuilder)) schema.QueryAppender {
 	cb := newMinExpr(b.qb)
 	builder(cb)
@@ -468,6 +488,20 @@ func (b *QueryExprBuilder) StringAgg(builder func(StringAggBuilder)) schema.Quer
 	return cb
 }
 
+func (b *QueryExprBuilder) PercentileCont(builder func(PercentileBuilder)) schema.QueryAppender {
+	pb := newPercentileExpr(b.qb, "PERCENTILE_CONT")
+	builder(pb)
+
+	return pb
+}
+
+func (b *QueryExprBuilder) PercentileDisc(builder func(PercentileBuilder)) schema.QueryAppender {
+	pb := newPercentileExpr(b.qb, "PERCENTILE_DISC")
+	builder(pb)
+
+	return pb
+}
+
 func (b *QueryExprBuilder) ArrayAgg(builder func(ArrayAggBuilder)) schema.QueryAppender {
 	cb := newArrayAggExpr(b.qb)
 	builder(cb)
@@ -766,14 +800,17 @@ func (b *QueryExprBuilder) ConcatWithSep(separator any, args ...any) schema.Quer
 }
 
 func (b *QueryExprBuilder) SubString(expr, start any, length ...any) schema.QueryAppender {
-	return b.ExprByDialect(DialectExprs{
-		SQLite: func() schema.QueryAppender {
-			if len(length) > 0 {
-				return b.Expr("SUBSTR(?, ?, ?)", expr, start, length[0])
-			}
+	substr := func() schema.QueryAppender {
+		if len(length) > 0 {
+			return b.Expr("SUBSTR(?, ?, ?)", expr, start, length[0])
+		}
 
-			return b.Expr("SUBSTR(?, ?)", expr, start)
-		},
+		return b.Expr("SUBSTR(?, ?)", expr, start)
+	}
+
+	return b.ExprByDialect(DialectExprs{
+		SQLite: substr,
+		Oracle: substr,
 		Default: func() schema.QueryAppender {
 			if len(length) > 0 {
 				return b.Expr("SUBSTRING(?, ?, ?)", expr, start, length[0])
@@ -813,6 +850,12 @@ func (b *QueryExprBuilder) CharLength(expr any) schema.QueryAppender {
 		SQLite: func() schema.QueryAppender {
 			return b.Expr("LENGTH(?)", expr)
 		},
+		Oracle: func() schema.QueryAppender {
+			return b.Expr("LENGTH(?)", expr)
+		},
+		SQLServer: func() schema.QueryAppender {
+			return b.Expr("LEN(?)", expr)
+		},
 		Default: func() schema.QueryAppender {
 			return b.Expr("CHAR_LENGTH(?)", expr)
 		},
@@ -950,6 +993,175 @@ func (b *QueryExprBuilder) Reverse(expr any) schema.QueryAppender {
 	})
 }
 
+// ========== Hashing Functions ==========
+
+func (b *QueryExprBuilder) Md5(expr any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		SQLServer: func() schema.QueryAppender {
+			return b.Expr("LOWER(CONVERT(VARCHAR(32), HASHBYTES('MD5', ?), 2))", utf8Varchar(expr))
+		},
+		Oracle: func() schema.QueryAppender {
+			return b.Expr("LOWER(RAWTOHEX(STANDARD_HASH(?, 'MD5')))", expr)
+		},
+		Default: func() schema.QueryAppender {
+			return b.Expr("MD5(?)", expr)
+		},
+	})
+}
+
+func (b *QueryExprBuilder) Sha256(expr any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("ENCODE(SHA256(CONVERT_TO(?, 'UTF8')), 'hex')", expr)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("SHA2(?, 256)", expr)
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr("SHA256(?)", expr)
+		},
+		SQLServer: func() schema.QueryAppender {
+			return b.Expr("LOWER(CONVERT(VARCHAR(64), HASHBYTES('SHA2_256', ?), 2))", utf8Varchar(expr))
+		},
+		Oracle: func() schema.QueryAppender {
+			return b.Expr("LOWER(RAWTOHEX(STANDARD_HASH(?, 'SHA256')))", expr)
+		},
+	})
+}
+
+func (b *QueryExprBuilder) Crc32(expr any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("CRC32(CONVERT_TO(?, 'UTF8'))", expr)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("CRC32(?)", expr)
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr("CRC32(?)", expr)
+		},
+		Default: func() schema.QueryAppender {
+			return b.unsupported("CRC32")
+		},
+	})
+}
+
+func (b *QueryExprBuilder) PgpEncrypt(expr, key any) schema.QueryAppender {
+	key = secretArg(b.qb.Query(), key)
+
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("PGP_SYM_ENCRYPT(?, ?)", expr, key)
+		},
+		Default: func() schema.QueryAppender {
+			return b.unsupported("PGP_SYM_ENCRYPT")
+		},
+	})
+}
+
+func (b *QueryExprBuilder) PgpDecrypt(expr, key any) schema.QueryAppender {
+	key = secretArg(b.qb.Query(), key)
+
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("PGP_SYM_DECRYPT(?, ?)", expr, key)
+		},
+		Default: func() schema.QueryAppender {
+			return b.unsupported("PGP_SYM_DECRYPT")
+		},
+	})
+}
+
+func (b *QueryExprBuilder) AesEncrypt(expr, key any) schema.QueryAppender {
+	key = secretArg(b.qb.Query(), key)
+
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("ENCRYPT(CONVERT_TO(?, 'UTF8'), CONVERT_TO(?, 'UTF8'), 'aes-ecb/pad:pkcs')", expr, key)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("AES_ENCRYPT(?, ?)", expr, key)
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr("AES_ENCRYPT(?, ?)", expr, key)
+		},
+		Default: func() schema.QueryAppender {
+			return b.unsupported("AES_ENCRYPT")
+		},
+	})
+}
+
+func (b *QueryExprBuilder) AesDecrypt(expr, key any) schema.QueryAppender {
+	key = secretArg(b.qb.Query(), key)
+
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("CONVERT_FROM(DECRYPT(?, CONVERT_TO(?, 'UTF8'), 'aes-ecb/pad:pkcs'), 'UTF8')", expr, key)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("CAST(AES_DECRYPT(?, ?) AS CHAR)", expr, key)
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr("CAST(AES_DECRYPT(?, ?) AS TEXT)", expr, key)
+		},
+		Default: func() schema.QueryAppender {
+			return b.unsupported("AES_DECRYPT")
+		},
+	})
+}
+
+// unsupported fails the query for a function the database does not support; the returned expression only
+// renders the error, which bun would embed in the SQL rather than return.
+func (b *QueryExprBuilder) unsupported(function string) schema.QueryAppender {
+	expr := unsupportedExpr(function)
+	setQueryErr(b.qb.Query(), expr.err())
+
+	return expr
+}
+
+// utf8Varchar converts a string to VARCHAR in a UTF-8 collation on SQL Server, so HASHBYTES digests the UTF-8 bytes
+// of NVARCHAR values, which are UTF-16 otherwise, as the other databases do.
+func utf8Varchar(expr any) schema.QueryAppender {
+	return bun.SafeQuery("CAST(? COLLATE Latin1_General_100_CI_AS_SC_UTF8 AS VARCHAR(MAX))", expr)
+}
+
+// ========== Full-Text Search Functions ==========
+
+func (b *QueryExprBuilder) Match(expr, query any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("to_tsvector(?) @@ websearch_to_tsquery(?)", expr, query)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("MATCH (?) AGAINST (? IN NATURAL LANGUAGE MODE)", expr, query)
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr("? MATCH ?", expr, query)
+		},
+		SQLServer: func() schema.QueryAppender {
+			return b.Expr("FREETEXT(?, ?)", expr, query)
+		},
+		Oracle: func() schema.QueryAppender {
+			return b.Expr("CONTAINS(?, ?) > 0", expr, query)
+		},
+	})
+}
+
+func (b *QueryExprBuilder) MatchRank(expr, query any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("ts_rank(to_tsvector(?), websearch_to_tsquery(?))", expr, query)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("MATCH (?) AGAINST (? IN NATURAL LANGUAGE MODE)", expr, query)
+		},
+		SQLite: func() schema.QueryAppender {
+			// The hidden rank column holds the bm25 score of the match, lower for more relevant rows
+			return b.Expr("-?", b.Column("rank"))
+		},
+	})
+}
+
 // ========== Date and Time Functions ==========
 
 func (b *QueryExprBuilder) CurrentDate() schema.QueryAppender {
@@ -1038,6 +1250,28 @@ func (b *QueryExprBuilder) ExtractDay(expr any) schema.QueryAppender {
 	})
 }
 
+func (b *QueryExprBuilder) ExtractDayOfWeek(expr any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("EXTRACT(ISODOW FROM ?)", expr)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("(WEEKDAY(?) + 1)", expr)
+		},
+		SQLite: func() schema.QueryAppender {
+			// STRFTIME('%w') numbers Sunday as 0, shift it to the ISO numbering
+			return b.Expr("((? + 6) % 7 + 1)", b.ToInteger(b.Expr("STRFTIME(?, ?)", "%w", expr)))
+		},
+		SQLServer: func() schema.QueryAppender {
+			// Normalize DATEPART(WEEKDAY) which depends on the session's DATEFIRST setting
+			return b.Expr("((DATEPART(WEEKDAY, ?) + @@DATEFIRST + 5) % 7 + 1)", expr)
+		},
+		Oracle: func() schema.QueryAppender {
+			return b.Expr("(TRUNC(?) - TRUNC(?, 'IW') + 1)", expr, expr)
+		},
+	})
+}
+
 func (b *QueryExprBuilder) ExtractHour(expr any) schema.QueryAppender {
 	return b.ExprByDialect(DialectExprs{
 		Postgres: func() schema.QueryAppender {
@@ -1520,6 +1754,78 @@ func (b *QueryExprBuilder) Least(args ...any) schema.QueryAppender {
 	})
 }
 
+func (b *QueryExprBuilder) WidthBucket(expr, low, high any, buckets int) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("WIDTH_BUCKET(?, ?, ?, ?)", expr, low, high, buckets)
+		},
+		Oracle: func() schema.QueryAppender {
+			return b.Expr("WIDTH_BUCKET(?, ?, ?, ?)", expr, low, high, buckets)
+		},
+		Default: func() schema.QueryAppender {
+			return b.convertWidthBucketToCase(expr, low, high, buckets)
+		},
+	})
+}
+
+// convertWidthBucketToCase emulates WIDTH_BUCKET with a CASE expression for databases lacking native support.
+func (b *QueryExprBuilder) convertWidthBucketToCase(expr, low, high any, buckets int) schema.QueryAppender {
+	return b.Case(func(cb CaseBuilder) {
+		cb.WhenExpr(b.LessThan(expr, low)).Then(0).
+			WhenExpr(b.GreaterThanOrEqual(expr, high)).Then(buckets + 1).
+			Else(b.Add(
+				b.ToInteger(b.Floor(b.Divide(
+					b.Multiply(b.Paren(b.Subtract(expr, low)), buckets),
+					b.Paren(b.Subtract(high, low)),
+				))),
+				1,
+			))
+	})
+}
+
+func (b *QueryExprBuilder) BitwiseAnd(left, right any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Oracle: func() schema.QueryAppender {
+			return b.Expr("BITAND(?, ?)", left, right)
+		},
+		Default: func() schema.QueryAppender {
+			return b.Expr("(? & ?)", left, right)
+		},
+	})
+}
+
+func (b *QueryExprBuilder) BitwiseOr(left, right any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		// Oracle has no bitwise OR operator: a | b = a + b - (a & b)
+		Oracle: func() schema.QueryAppender {
+			return b.Expr("(? + ? - BITAND(?, ?))", left, right, left, right)
+		},
+		Default: func() schema.QueryAppender {
+			return b.Expr("(? | ?)", left, right)
+		},
+	})
+}
+
+func (b *QueryExprBuilder) HasFlag(expr, flag any) schema.QueryAppender {
+	return b.Equals(b.BitwiseAnd(expr, flag), flag)
+}
+
+func (b *QueryExprBuilder) SetFlag(expr, flag any) schema.QueryAppender {
+	return b.BitwiseOr(expr, flag)
+}
+
+func (b *QueryExprBuilder) ClearFlag(expr, flag any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		// Oracle has no bitwise NOT operator: a & ~b = a - (a & b)
+		Oracle: func() schema.QueryAppender {
+			return b.Expr("(? - BITAND(?, ?))", expr, expr, flag)
+		},
+		Default: func() schema.QueryAppender {
+			return b.Expr("(? & ~?)", expr, flag)
+		},
+	})
+}
+
 // ========== Conditional Functions ==========
 
 func (b *QueryExprBuilder) Coalesce(args ...any) schema.QueryAppender {
@@ -1762,6 +2068,127 @@ func (b *QueryExprBuilder) ToJSON(expr any) schema.QueryAppender {
 	})
 }
 
+// ========== Array Functions ==========
+
+func (b *QueryExprBuilder) ArrayLength(array any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("COALESCE(CARDINALITY(?), 0)", array)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("COALESCE(JSON_LENGTH(?), 0)", array)
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr("COALESCE(JSON_ARRAY_LENGTH(?), 0)", array)
+		},
+	})
+}
+
+func (b *QueryExprBuilder) Array(values ...any) schema.QueryAppender {
+	values = expandValues(values)
+
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			if len(values) == 0 {
+				return b.Expr("'{}'")
+			}
+
+			return b.Expr("ARRAY[?]", b.Exprs(values...))
+		},
+		Default: func() schema.QueryAppender {
+			return b.JSONArray(values...)
+		},
+	})
+}
+
+func (b *QueryExprBuilder) ArrayContains(array, values any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("? @> ?", array, b.arrayValues(values))
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("JSON_CONTAINS(?, ?)", array, b.arrayValues(values))
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr(
+				"NOT EXISTS (SELECT 1 FROM JSON_EACH(?) AS v WHERE v.value NOT IN (SELECT a.value FROM JSON_EACH(?) AS a))",
+				b.arrayValues(values),
+				array,
+			)
+		},
+	})
+}
+
+func (b *QueryExprBuilder) ArrayOverlaps(array, values any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("? && ?", array, b.arrayValues(values))
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("JSON_OVERLAPS(?, ?)", array, b.arrayValues(values))
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr(
+				"EXISTS (SELECT 1 FROM JSON_EACH(?) AS a WHERE a.value IN (SELECT v.value FROM JSON_EACH(?) AS v))",
+				array,
+				b.arrayValues(values),
+			)
+		},
+	})
+}
+
+func (b *QueryExprBuilder) ArrayAppend(array, value any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("ARRAY_APPEND(?, ?)", array, value)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("JSON_ARRAY_APPEND(COALESCE(?, JSON_ARRAY()), '$', ?)", array, value)
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr("JSON_INSERT(COALESCE(?, '[]'), '$[#]', ?)", array, value)
+		},
+	})
+}
+
+func (b *QueryExprBuilder) ArrayRemove(array, value any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("ARRAY_REMOVE(?, ?)", array, value)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr(
+				"(SELECT COALESCE(JSON_ARRAYAGG(a.v), JSON_ARRAY()) FROM JSON_TABLE(?, '$[*]' COLUMNS (v JSON PATH '$')) AS a "+
+					"WHERE NOT JSON_CONTAINS(JSON_ARRAY(?), a.v))",
+				array,
+				value,
+			)
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr("(SELECT JSON_GROUP_ARRAY(a.value) FROM JSON_EACH(?) AS a WHERE a.value IS NOT ?)", array, value)
+		},
+	})
+}
+
+// arrayValues binds a Go slice as an array on Postgres and as a JSON array elsewhere,
+// expressions are passed through.
+func (b *QueryExprBuilder) arrayValues(values any) any {
+	if _, ok := values.(schema.QueryAppender); ok {
+		return values
+	}
+
+	if b.qb.Dialect().Name() == dialect.PG {
+		return pgdialect.Array(values)
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return b.Null()
+	}
+
+	return string(data)
+}
+
 // ========== JSON Functions ==========
 
 // processJSONPath formats the JSON path according to the dialect requirements.
@@ -1814,6 +2241,12 @@ func (b *QueryExprBuilder) JSONExtract(json, path any) schema.QueryAppender {
 	})
 }
 
+func (b *QueryExprBuilder) JSONPath(json any) JSONPathBuilder {
+	b.warnMissingJSONIndex(json)
+
+	return &jsonPath{eb: b, json: json}
+}
+
 func (b *QueryExprBuilder) JSONUnquote(expr any) schema.QueryAppender {
 	return b.ExprByDialect(DialectExprs{
 		Postgres: func() schema.QueryAppender {
@@ -1898,6 +2331,8 @@ func (b *QueryExprBuilder) JSONObject(keyValues ...any) schema.QueryAppender {
 }
 
 func (b *QueryExprBuilder) JSONContains(json, value any) schema.QueryAppender {
+	b.warnMissingJSONIndex(json)
+
 	return b.ExprByDialect(DialectExprs{
 		Postgres: func() schema.QueryAppender {
 			return b.Expr("? @> ?", json, b.ToJSON(value))
@@ -1925,6 +2360,8 @@ func (b *QueryExprBuilder) JSONContains(json, value any) schema.QueryAppender {
 }
 
 func (b *QueryExprBuilder) JSONContainsPath(json, path any) schema.QueryAppender {
+	b.warnMissingJSONIndex(json)
+
 	return b.ExprByDialect(DialectExprs{
 		Postgres: func() schema.QueryAppender {
 			// PostgreSQL uses jsonb_path_exists which requires JSONPath syntax ($.key)
@@ -2205,6 +2642,87 @@ func (b *QueryExprBuilder) JSONArrayAppend(json, path, value any) schema.QueryAp
 	})
 }
 
+// ========== Spatial Functions ==========
+
+// earthRadiusMeters is the mean radius of the Earth used by the haversine formula, as ST_Distance_Sphere uses by default.
+const earthRadiusMeters = 6370986
+
+func (b *QueryExprBuilder) Point(lat, lng any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("ST_SetSRID(ST_MakePoint(?, ?), 4326)", lng, lat)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("POINT(?, ?)", lng, lat)
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr("MAKEPOINT(?, ?, 4326)", lng, lat)
+		},
+		Default: func() schema.QueryAppender {
+			return b.unsupported("POINT")
+		},
+	})
+}
+
+func (b *QueryExprBuilder) StDistance(geom1, geom2 any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("ST_Distance(CAST(? AS geography), CAST(? AS geography))", geom1, geom2)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("ST_Distance_Sphere(?, ?)", geom1, geom2)
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.haversine(geom1, geom2)
+		},
+		Default: func() schema.QueryAppender {
+			return b.unsupported("ST_DISTANCE")
+		},
+	})
+}
+
+func (b *QueryExprBuilder) StWithin(geom1, geom2 any) schema.QueryAppender {
+	return b.spatialPredicate("ST_Within", geom1, geom2)
+}
+
+func (b *QueryExprBuilder) StContains(geom1, geom2 any) schema.QueryAppender {
+	return b.spatialPredicate("ST_Contains", geom1, geom2)
+}
+
+// spatialPredicate renders a predicate of two geometries named alike by PostGIS, MySQL and SpatiaLite,
+// which returns 1 or 0 rather than a boolean on SQLite.
+func (b *QueryExprBuilder) spatialPredicate(name string, geom1, geom2 any) schema.QueryAppender {
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("?(?, ?)", bun.Safe(name), geom1, geom2)
+		},
+		MySQL: func() schema.QueryAppender {
+			return b.Expr("?(?, ?)", bun.Safe(name), geom1, geom2)
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr("?(?, ?) = 1", bun.Safe(name), geom1, geom2)
+		},
+		Default: func() schema.QueryAppender {
+			return b.unsupported(strings.ToUpper(name))
+		},
+	})
+}
+
+// haversine computes the great-circle distance in meters between two points by the haversine formula,
+// reading the coordinates with ST_X and ST_Y, which SpatiaLite and the pure Go SQLite driver provide.
+func (b *QueryExprBuilder) haversine(point1, point2 any) schema.QueryAppender {
+	lat1, lng1 := b.Expr("RADIANS(ST_Y(?))", point1), b.Expr("RADIANS(ST_X(?))", point1)
+	lat2, lng2 := b.Expr("RADIANS(ST_Y(?))", point2), b.Expr("RADIANS(ST_X(?))", point2)
+
+	return b.Expr(
+		"(2 * ? * ASIN(SQRT(POWER(SIN((? - ?) / 2), 2) + COS(?) * COS(?) * POWER(SIN((? - ?) / 2), 2))))",
+		earthRadiusMeters,
+		lat2, lat1,
+		lat1, lat2,
+		lng2, lng1,
+	)
+}
+
 // ========== Utility Functions ==========
 
 func (b *QueryExprBuilder) Decode(args ...any) schema.QueryAppender {
@@ -2228,6 +2746,19 @@ func (b *QueryExprBuilder) Decode(args ...any) schema.QueryAppender {
 	})
 }
 
+func (b *QueryExprBuilder) Row(values ...any) schema.QueryAppender {
+	values = expandValues(values)
+
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("ROW(?)", b.Exprs(values...))
+		},
+		Default: func() schema.QueryAppender {
+			return b.Expr("(?)", b.Exprs(values...))
+		},
+	})
+}
+
 // convertDecodeToCase converts DECODE syntax to CASE WHEN expression using the existing Case builder.
 func (b *QueryExprBuilder) convertDecodeToCase(args ...any) schema.QueryAppender {
 	if len(args) < 3 {
@@ -0,0 +1,361 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/strhelpers"
+)
+
+const (
+	// TagExprIndex is the struct tag used to declare functional indexes on model fields.
+	// The default value is either a function name applied to the column (e.g. "lower") or an
+	// expression template where ? stands for the column (e.g. "LOWER(?)").
+	// Example: `exprindex:"lower"` or `exprindex:"?,using=gin,ops=jsonb_path_ops,name=idx_user_meta"`.
+	TagExprIndex = "exprindex"
+	// AttrExprIndexName overrides the generated index name.
+	AttrExprIndexName = "name"
+	// AttrExprIndexUsing sets the index access method (e.g. gin), honored on PostgreSQL only.
+	AttrExprIndexUsing = "using"
+	// AttrExprIndexOps sets the operator class (e.g. jsonb_path_ops), honored on PostgreSQL only.
+	AttrExprIndexOps = "ops"
+	// AttrExprIndexUnique marks the index as unique.
+	AttrExprIndexUnique = "unique"
+
+	// columnPlaceholder is the placeholder for the indexed column in expression templates.
+	columnPlaceholder = "?"
+)
+
+// ExpressionIndex describes a functional index declared for a table.
+type ExpressionIndex struct {
+	// Name is the index name, derived from the table and expression when empty.
+	Name string
+	// Table is the table the index belongs to.
+	Table string
+	// Expr is the indexed expression, e.g. "LOWER(email)".
+	Expr string
+	// Using is the index access method (e.g. gin), only applied on PostgreSQL.
+	Using string
+	// Ops is the operator class (e.g. jsonb_path_ops), only applied on PostgreSQL.
+	Ops string
+	// Unique marks the index as unique.
+	Unique bool
+}
+
+var (
+	// expressionIndexes holds the functional indexes declared per table, keyed by table name.
+	// Each table maps normalized index expressions (e.g. "lower(email)") to presence.
+	expressionIndexes sync.Map
+	// modelExpressionIndexes caches the functional indexes parsed from model tags, keyed by model type.
+	modelExpressionIndexes sync.Map
+	// missingIndexWarnings records table/expression pairs already reported as lacking a supporting index.
+	missingIndexWarnings sync.Map
+)
+
+// RegisterExpressionIndex declares that the given table has a functional index on expr, e.g. "LOWER(email)".
+// Condition helpers consult the declarations to choose index-friendly SQL forms.
+func RegisterExpressionIndex(table, expr string) {
+	indexes, _ := expressionIndexes.LoadOrStore(table, &sync.Map{})
+	indexes.(*sync.Map).Store(normalizeIndexExpr(expr), true)
+}
+
+// ExpressionIndexesOf returns the functional indexes declared through exprindex tags on the model table.
+func ExpressionIndexesOf(table *schema.Table) []ExpressionIndex {
+	if cached, ok := modelExpressionIndexes.Load(table.Type); ok {
+		return cached.([]ExpressionIndex)
+	}
+
+	indexes := make([]ExpressionIndex, 0)
+
+	for _, field := range table.Fields {
+		tag, ok := field.StructField.Tag.Lookup(TagExprIndex)
+		if !ok || tag == constants.Empty {
+			continue
+		}
+
+		attrs := strhelpers.ParseTag(tag)
+		expr := buildIndexExpr(attrs[strhelpers.DefaultKey], field.Name)
+		_, unique := attrs[AttrExprIndexUnique]
+		indexes = append(indexes, ExpressionIndex{
+			Name:   lo.CoalesceOrEmpty(attrs[AttrExprIndexName], buildIndexName(table.Name, expr)),
+			Table:  table.Name,
+			Expr:   expr,
+			Using:  attrs[AttrExprIndexUsing],
+			Ops:    attrs[AttrExprIndexOps],
+			Unique: unique,
+		})
+	}
+
+	actual, _ := modelExpressionIndexes.LoadOrStore(table.Type, indexes)
+
+	return actual.([]ExpressionIndex)
+}
+
+// CreateExpressionIndexes creates the functional indexes declared through exprindex tags on the given models
+// and registers them so that condition helpers pick index-friendly forms.
+// PostgreSQL and SQLite use CREATE INDEX IF NOT EXISTS, MySQL uses a functional key part (8.0.13+)
+// and SQL Server or Oracle are rejected since they require computed columns instead.
+func CreateExpressionIndexes(ctx context.Context, db DB, models ...any) error {
+	for _, model := range models {
+		table := db.TableOf(model)
+		for _, index := range ExpressionIndexesOf(table) {
+			if err := CreateExpressionIndex(ctx, db, index); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CreateExpressionIndex creates a single functional index and registers it for the table.
+// MySQL has no CREATE INDEX IF NOT EXISTS, so an existing index of the same name is kept there.
+func CreateExpressionIndex(ctx context.Context, db DB, index ExpressionIndex) error {
+	query, err := ExpressionIndexSQL(db, index)
+	if err != nil {
+		return err
+	}
+
+	exists := false
+	if db.(*BunDB).db.Dialect().Name() == dialect.MySQL {
+		if exists, err = mysqlIndexExists(ctx, db, index.Table, expressionIndexName(index)); err != nil {
+			return err
+		}
+	}
+
+	if !exists {
+		if _, err := db.NewRaw(query).Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	RegisterExpressionIndex(index.Table, index.Expr)
+
+	return nil
+}
+
+// ExpressionIndexSQL returns the statement creating a functional index on the database of db.
+// The index, table, access method and operator class names are quoted as identifiers of the database.
+func ExpressionIndexSQL(db DB, index ExpressionIndex) (string, error) {
+	bunDB, ok := db.(*BunDB)
+	if !ok {
+		return constants.Empty, fmt.Errorf("%w: %T", ErrDialectUnsupportedOperation, db)
+	}
+
+	name := expressionIndexName(index)
+	unique := lo.Ternary(index.Unique, "UNIQUE ", constants.Empty)
+
+	dialectName := bunDB.db.Dialect().Name()
+	if dialectName != dialect.PG && (index.Using != constants.Empty || index.Ops != constants.Empty) {
+		logger.Warnf("Index method and operator class of %q are only supported on PostgreSQL, creating a plain expression index", name)
+	}
+
+	gen := bunDB.getBunDB().QueryGen()
+
+	switch dialectName {
+	case dialect.PG:
+		var using, ops schema.QueryAppender = bun.Safe(constants.Empty), bun.Safe(constants.Empty)
+		if index.Using != constants.Empty {
+			using = bun.SafeQuery(" USING ?", bun.Ident(index.Using))
+		}
+
+		if index.Ops != constants.Empty {
+			ops = bun.SafeQuery(" ?", bun.Ident(index.Ops))
+		}
+
+		return gen.FormatQuery("CREATE "+unique+"INDEX IF NOT EXISTS ? ON ?? ((?)?)",
+			bun.Ident(name), bun.Ident(index.Table), using, bun.Safe(index.Expr), ops), nil
+	case dialect.SQLite:
+		return gen.FormatQuery("CREATE "+unique+"INDEX IF NOT EXISTS ? ON ? ((?))",
+			bun.Ident(name), bun.Ident(index.Table), bun.Safe(index.Expr)), nil
+	case dialect.MySQL:
+		return gen.FormatQuery("CREATE "+unique+"INDEX ? ON ? ((?))",
+			bun.Ident(name), bun.Ident(index.Table), bun.Safe(index.Expr)), nil
+	default:
+		return constants.Empty, fmt.Errorf("%w: expression indexes on %s", ErrDialectUnsupportedOperation, dialectName)
+	}
+}
+
+// expressionIndexName returns the name of the index, derived from the table and expression when unset.
+func expressionIndexName(index ExpressionIndex) string {
+	return lo.CoalesceOrEmpty(index.Name, buildIndexName(index.Table, index.Expr))
+}
+
+// mysqlIndexExists reports whether the table has an index of the name, the table being looked up in the current
+// database unless qualified with a schema.
+func mysqlIndexExists(ctx context.Context, db DB, table, name string) (bool, error) {
+	query := "SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?"
+	args := []any{table, name}
+
+	if schemaName, tableName, ok := strings.Cut(table, constants.Dot); ok {
+		query = "SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = ? AND table_name = ? AND index_name = ?"
+		args = []any{schemaName, tableName, name}
+	}
+
+	var count int
+	if err := db.NewRaw(query, args...).Scan(ctx, &count); err != nil {
+		return false, fmt.Errorf("failed to look up index %s of %s: %w", name, table, err)
+	}
+
+	return count > 0, nil
+}
+
+// hasExpressionIndex reports whether a functional index on expr has been declared for the table,
+// either through RegisterExpressionIndex or through exprindex model tags.
+func hasExpressionIndex(table *schema.Table, expr string) bool {
+	if table == nil {
+		return false
+	}
+
+	normalized := normalizeIndexExpr(expr)
+
+	if indexes, ok := expressionIndexes.Load(table.Name); ok {
+		if _, ok = indexes.(*sync.Map).Load(normalized); ok {
+			return true
+		}
+	}
+
+	return lo.ContainsBy(ExpressionIndexesOf(table), func(index ExpressionIndex) bool {
+		return normalizeIndexExpr(index.Expr) == normalized
+	})
+}
+
+// hasColumnExpressionIndex reports whether a functional index involving the column has been declared for the table,
+// e.g. a GIN index on a JSON column or an index on a path extracted from it.
+func hasColumnExpressionIndex(table *schema.Table, column string) bool {
+	column = strings.ToLower(column)
+	involves := func(expr string) bool {
+		return slices.Contains(strings.FieldsFunc(normalizeIndexExpr(expr), func(r rune) bool {
+			return r != '_' && (r < 'a' || r > 'z') && (r < '0' || r > '9')
+		}), column)
+	}
+
+	found := false
+	if indexes, ok := expressionIndexes.Load(table.Name); ok {
+		indexes.(*sync.Map).Range(func(expr, _ any) bool {
+			found = involves(expr.(string))
+
+			return !found
+		})
+	}
+
+	return found || lo.ContainsBy(ExpressionIndexesOf(table), func(index ExpressionIndex) bool {
+		return involves(index.Expr)
+	})
+}
+
+// warnMissingExpressionIndex logs once per table and expression that no supporting functional index is declared,
+// so that queries which will likely fall back to full scans are visible during development.
+func warnMissingExpressionIndex(table *schema.Table, expr string) {
+	if table == nil {
+		return
+	}
+
+	key := table.Name + constants.Colon + normalizeIndexExpr(expr)
+	if _, loaded := missingIndexWarnings.LoadOrStore(key, true); !loaded {
+		logger.Warnf("No expression index declared for %s on table %q, the condition may not use an index", expr, table.Name)
+	}
+}
+
+// columnExpr is a column reference built by ExprBuilder.Column, keeping the column for the checks of supporting
+// indexes.
+type columnExpr struct {
+	schema.QueryAppender
+
+	column string
+}
+
+// warnMissingJSONIndex warns once when a JSON condition helper is applied to a model column no functional index
+// involving the column is declared for.
+func (b *QueryExprBuilder) warnMissingJSONIndex(json any) {
+	column, ok := json.(columnExpr)
+	if !ok {
+		return
+	}
+
+	table := b.qb.GetTable()
+
+	field := resolveModelField(table, column.column)
+	if field == nil || hasColumnExpressionIndex(table, field.Name) {
+		return
+	}
+
+	warnMissingExpressionIndex(table, "JSON paths of "+field.Name)
+}
+
+// buildIndexExpr expands an exprindex tag value into an index expression for the column.
+func buildIndexExpr(template, column string) string {
+	switch {
+	case template == constants.Empty:
+		return column
+	case strings.Contains(template, columnPlaceholder):
+		return strings.ReplaceAll(template, columnPlaceholder, column)
+	default:
+		return strings.ToUpper(template) + "(" + column + ")"
+	}
+}
+
+// buildIndexName derives an index name like idx_test_user_lower_email from the table and expression.
+func buildIndexName(table, expr string) string {
+	var sb strings.Builder
+
+	sb.WriteString("idx_")
+	sb.WriteString(table)
+	sb.WriteByte('_')
+
+	lastUnderscore := true
+	for _, r := range strings.ToLower(expr) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+
+			lastUnderscore = false
+		} else if !lastUnderscore {
+			sb.WriteByte('_')
+
+			lastUnderscore = true
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "_")
+}
+
+// normalizeIndexExpr lowercases an index expression and strips all whitespace and identifier quotes
+// so that declarations and lookups compare equal regardless of formatting.
+func normalizeIndexExpr(expr string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\n', '\r', '"', '`', '[', ']':
+			return -1
+		}
+
+		return r
+	}, strings.ToLower(expr))
+}
+
+// resolveModelField returns the model field referenced by column when the column is unqualified
+// or qualified with the model table alias. It returns nil for columns of other tables.
+func resolveModelField(table *schema.Table, column string) *schema.Field {
+	if table == nil {
+		return nil
+	}
+
+	name := column
+	if alias, after, ok := strings.Cut(column, constants.Dot); ok {
+		if alias != table.Alias {
+			return nil
+		}
+
+		name = after
+	}
+
+	return table.FieldMap[name]
+}
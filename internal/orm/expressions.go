@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/uptrace/bun/dialect"
@@ -75,3 +76,14 @@ func newExpressions(sep any, exprs ...any) *Expressions {
 		sep:   sep,
 	}
 }
+
+// unsupportedExpr fails rendering a function the current database lacks, rather than rendering NULL in its place.
+type unsupportedExpr string
+
+func (u unsupportedExpr) AppendQuery(schema.QueryGen, []byte) ([]byte, error) {
+	return nil, u.err()
+}
+
+func (u unsupportedExpr) err() error {
+	return fmt.Errorf("%w: %s", ErrDialectUnsupportedOperation, string(u))
+}
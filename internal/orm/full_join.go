@@ -0,0 +1,118 @@
+package orm
+
+import (
+	"fmt"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
+)
+
+// fullJoinEmulated holds the dialects lacking FULL OUTER JOIN, where it is emulated.
+var fullJoinEmulated = map[dialect.Name]bool{
+	dialect.MySQL: true,
+}
+
+// fullJoin renders the join type of a FULL JOIN. Databases lacking it get the union of the query with a LEFT JOIN
+// and the query with a RIGHT JOIN restricted to the right rows matching no left row, for which the join condition
+// is not true.
+type fullJoin struct {
+	joinType  JoinType
+	condition schema.QueryAppender
+}
+
+func (j *fullJoin) AppendQuery(_ schema.QueryGen, b []byte) ([]byte, error) {
+	return append(b, j.joinType.String()...), nil
+}
+
+// fullJoinRightOnly restricts the RIGHT JOIN part of an emulated FULL JOIN to the right rows matching no left row.
+type fullJoinRightOnly struct {
+	join *fullJoin
+}
+
+func (r *fullJoinRightOnly) AppendQuery(gen schema.QueryGen, b []byte) (_ []byte, err error) {
+	if r.join.joinType != JoinRight {
+		return append(b, "1 = 1"...), nil
+	}
+
+	b = append(b, '(')
+	if b, err = r.join.condition.AppendQuery(gen, b); err != nil {
+		return
+	}
+
+	return append(b, ") IS NOT TRUE"...), nil
+}
+
+// fullJoinUnion renders the query of an emulated FULL JOIN once with a LEFT JOIN and once with a RIGHT JOIN.
+type fullJoinUnion struct {
+	join  *fullJoin
+	query *bun.SelectQuery
+}
+
+func (u *fullJoinUnion) AppendQuery(gen schema.QueryGen, b []byte) (_ []byte, err error) {
+	defer func() {
+		u.join.joinType = JoinFull
+	}()
+
+	for i, part := range []struct {
+		joinType JoinType
+		alias    string
+	}{
+		{JoinLeft, "full_join_left"},
+		{JoinRight, "full_join_right"},
+	} {
+		if i > 0 {
+			b = append(b, " UNION ALL "...)
+		}
+
+		u.join.joinType = part.joinType
+
+		b = append(b, "SELECT * FROM ("...)
+		if b, err = u.query.AppendQuery(gen, b); err != nil {
+			return
+		}
+
+		b = append(b, ") AS "...)
+		b = gen.AppendIdent(b, part.alias)
+	}
+
+	return b, nil
+}
+
+// newFullJoin records a FULL JOIN on the condition, emulated on databases lacking it, which support one per query:
+// executing a query with more fails with ErrDialectUnsupportedOperation there.
+func (q *BunSelectQuery) newFullJoin(builder func(ConditionBuilder)) *fullJoin {
+	join := &fullJoin{
+		joinType:  JoinFull,
+		condition: q.BuildCondition(builder),
+	}
+
+	if fullJoinEmulated[q.dialect.Name()] {
+		if q.fullJoin != nil {
+			q.err = fmt.Errorf("%w: more than one FULL JOIN per query on %s", ErrDialectUnsupportedOperation, q.dialect.Name())
+			q.query.Err(q.err)
+
+			return join
+		}
+
+		q.fullJoin = join
+	}
+
+	return join
+}
+
+// applyFullJoinState turns the query with an emulated FULL JOIN into the union of its LEFT JOIN and RIGHT JOIN parts,
+// wrapped in a derived table under the alias of its model. The order, limit and offset move to the wrapping query
+// so they apply to the union.
+func (q *BunSelectQuery) applyFullJoinState() {
+	if q.fullJoin == nil || q.fullJoinStateApplied {
+		return
+	}
+
+	q.fullJoinStateApplied = true
+
+	inner := q.query.Limit(0).Offset(0)
+	inner.Where("?", &fullJoinRightOnly{join: q.fullJoin})
+
+	q.query = q.wrapQuery(inner, &fullJoinUnion{join: q.fullJoin, query: inner})
+}
@@ -168,3 +168,45 @@ func buildReturningExpr(returningColumns collections.Set[string], eb ExprBuilder
 
 	return eb.Exprs(columns...)
 }
+
+// setQueryErr fails the bun query with the error when it is executed.
+func setQueryErr(query bun.Query, err error) {
+	switch q := query.(type) {
+	case *bun.SelectQuery:
+		q.Err(err)
+	case *bun.InsertQuery:
+		q.Err(err)
+	case *bun.UpdateQuery:
+		q.Err(err)
+	case *bun.DeleteQuery:
+		q.Err(err)
+	case *bun.MergeQuery:
+		q.Err(err)
+	case *bun.RawQuery:
+		q.Err(err)
+	}
+}
+
+// expandValues expands a single slice argument of a variadic function into its elements,
+// byte slices and expressions excepted.
+func expandValues(values []any) []any {
+	if len(values) != 1 {
+		return values
+	}
+
+	if _, ok := values[0].(schema.QueryAppender); ok {
+		return values
+	}
+
+	slice := reflect.ValueOf(values[0])
+	if slice.Kind() != reflect.Slice || slice.Type() == bytesType {
+		return values
+	}
+
+	expanded := make([]any, slice.Len())
+	for i := range expanded {
+		expanded[i] = slice.Index(i).Interface()
+	}
+
+	return expanded
+}
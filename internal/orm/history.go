@@ -0,0 +1,224 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/datetime"
+)
+
+const (
+	// HistoryTableSuffix is appended to the name of a table to name the table holding its history.
+	HistoryTableSuffix = "_history"
+	// ColumnHistoryOperation holds the operation replacing the recorded row version, UPDATE or DELETE.
+	ColumnHistoryOperation = "history_operation"
+	// ColumnHistoryActor holds the operator performing the operation.
+	ColumnHistoryActor = "history_actor"
+	// ColumnHistoryAt holds the time of the operation, the end of the validity of the recorded row version.
+	ColumnHistoryAt = "history_at"
+
+	// HistoryOperationUpdate records a row version replaced by an update.
+	HistoryOperationUpdate = "UPDATE"
+	// HistoryOperationDelete records a row version removed by a delete, including soft deletes.
+	HistoryOperationDelete = "DELETE"
+
+	// historyCurrentAlias aliases the table in point-in-time queries.
+	historyCurrentAlias = "_current"
+	// historyAlias and historyNextAlias alias the history table in point-in-time queries.
+	historyAlias     = "_history"
+	historyNextAlias = "_history_next"
+)
+
+var (
+	// historyMu guards histories.
+	historyMu sync.RWMutex
+	// histories holds the names of the tables whose history is recorded.
+	histories = make(map[string]bool)
+)
+
+// RegisterHistory enables the history of the given models: updates and deletes through the orm then copy the
+// rows they replace into the history table of the model, named after the table with HistoryTableSuffix, along
+// with the operation, the operator and the time, in the same transaction. SelectQuery.AsOf queries the state of
+// the rows at a point in time from it. CreateHistoryTables creates the history tables.
+func RegisterHistory(db DB, models ...any) error {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	for _, model := range models {
+		table := db.TableOf(model)
+		if len(table.PKs) == 0 {
+			return fmt.Errorf("%w: %s", ErrHistoryMissingPrimaryKey, table.Name)
+		}
+
+		histories[table.Name] = true
+	}
+
+	return nil
+}
+
+// CreateHistoryTables creates the history tables of the given models, holding the columns of the model table
+// followed by the history columns, and indexes them for point-in-time queries. Run it once, e.g. in a migration,
+// and again after adding columns to the model table.
+func CreateHistoryTables(ctx context.Context, db DB, models ...any) error {
+	bunDB, ok := db.(*BunDB)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrDialectUnsupportedOperation, db)
+	}
+
+	dialectName := bunDB.db.Dialect().Name()
+
+	for _, model := range models {
+		table := db.TableOf(model)
+		name := historyTableName(table)
+
+		var (
+			create    = "CREATE TABLE ? AS SELECT * FROM ? WHERE 1 = 0"
+			timestamp = "TIMESTAMP"
+		)
+
+		switch dialectName {
+		case dialect.MSSQL:
+			create, timestamp = "SELECT * INTO ? FROM ? WHERE 1 = 0", "DATETIME2"
+		case dialect.MySQL:
+			timestamp = "DATETIME(6)"
+		}
+
+		statements := []struct {
+			query string
+			args  []any
+		}{
+			{create, []any{bun.Name(name), bun.Name(table.Name)}},
+			{"ALTER TABLE ? ADD ? VARCHAR(16)", []any{bun.Name(name), bun.Name(ColumnHistoryOperation)}},
+			{"ALTER TABLE ? ADD ? VARCHAR(64)", []any{bun.Name(name), bun.Name(ColumnHistoryActor)}},
+			{"ALTER TABLE ? ADD ? " + timestamp, []any{bun.Name(name), bun.Name(ColumnHistoryAt)}},
+			{"CREATE INDEX ? ON ? (?, ?)", []any{
+				bun.Name(name + "_at_idx"), bun.Name(name), Names(pkNames(table)...), bun.Name(ColumnHistoryAt),
+			}},
+		}
+
+		for _, statement := range statements {
+			if _, err := bunDB.db.NewRaw(statement.query, statement.args...).Exec(ctx); err != nil {
+				return fmt.Errorf("failed to create history table %s: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// historyOf reports whether the history of the table is recorded.
+func historyOf(table *schema.Table) bool {
+	historyMu.RLock()
+	defer historyMu.RUnlock()
+
+	return histories[table.Name]
+}
+
+func historyTableName(table *schema.Table) string {
+	return table.Name + HistoryTableSuffix
+}
+
+func pkNames(table *schema.Table) []string {
+	names := make([]string, len(table.PKs))
+	for i, pk := range table.PKs {
+		names[i] = pk.Name
+	}
+
+	return names
+}
+
+// recordHistory copies the rows of the model matching the filters into its history table, before the operation
+// replaces them.
+func recordHistory(ctx context.Context, db *BunDB, model any, filters []ApplyFunc[SelectQuery], operation string) error {
+	table := db.TableOf(model)
+
+	columns := make([]string, 0, len(table.Fields)+3)
+	for _, field := range table.Fields {
+		columns = append(columns, field.Name)
+	}
+
+	source := NewSelectQuery(db)
+	source.Model(model).
+		Unscoped().
+		Select(columns...).
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.Expr("?", operation)
+		}, ColumnHistoryOperation).
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.Expr(constants.ExprOperator)
+		}, ColumnHistoryActor).
+		SelectExpr(func(eb ExprBuilder) any {
+			return eb.Expr("?", time.Now())
+		}, ColumnHistoryAt).
+		Apply(filters...)
+	source.applySelectState()
+
+	if err := source.applyScopes(ctx); err != nil {
+		return err
+	}
+
+	columns = append(columns, ColumnHistoryOperation, ColumnHistoryActor, ColumnHistoryAt)
+	if _, err := db.db.NewRaw(
+		"INSERT INTO ? (?) ?",
+		bun.Name(historyTableName(table)), Names(columns...), source.query,
+	).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record history of %s: %w", table.Name, err)
+	}
+
+	return nil
+}
+
+// asOfSource returns the rows of the table valid at the given time: the current rows not changed since, and the
+// earliest version recorded after the time of the rows changed since. Rows created after the time are left out
+// when the table has a created_at column.
+func asOfSource(db bun.IDB, table *schema.Table, at time.Time) schema.QueryAppender {
+	history := historyTableName(table)
+
+	// Current rows changed since the time, their valid version being recorded
+	changed := db.NewSelect().
+		TableExpr("? AS ?", bun.Name(history), bun.Name(historyAlias)).
+		ColumnExpr("1").
+		Where("?.? > ?", bun.Name(historyAlias), bun.Name(ColumnHistoryAt), at)
+	// Recorded versions followed by an earlier recorded version still valid at the time
+	superseded := db.NewSelect().
+		TableExpr("? AS ?", bun.Name(history), bun.Name(historyNextAlias)).
+		ColumnExpr("1").
+		Where("?.? > ?", bun.Name(historyNextAlias), bun.Name(ColumnHistoryAt), at).
+		Where("?.? < ?.?",
+			bun.Name(historyNextAlias), bun.Name(ColumnHistoryAt), bun.Name(historyAlias), bun.Name(ColumnHistoryAt))
+
+	for _, pk := range table.PKs {
+		changed.Where("?.? = ?.?",
+			bun.Name(historyAlias), bun.Name(pk.Name), bun.Name(historyCurrentAlias), bun.Name(pk.Name))
+		superseded.Where("?.? = ?.?",
+			bun.Name(historyNextAlias), bun.Name(pk.Name), bun.Name(historyAlias), bun.Name(pk.Name))
+	}
+
+	current := db.NewSelect().
+		TableExpr("? AS ?", bun.Name(table.Name), bun.Name(historyCurrentAlias)).
+		Where("NOT EXISTS (?)", changed)
+	recorded := db.NewSelect().
+		TableExpr("? AS ?", bun.Name(history), bun.Name(historyAlias)).
+		Where("?.? > ?", bun.Name(historyAlias), bun.Name(ColumnHistoryAt), at).
+		Where("NOT EXISTS (?)", superseded)
+
+	for _, field := range table.Fields {
+		current.ColumnExpr("?.?", bun.Name(historyCurrentAlias), bun.Name(field.Name))
+		recorded.ColumnExpr("?.?", bun.Name(historyAlias), bun.Name(field.Name))
+	}
+
+	if table.HasField(constants.ColumnCreatedAt) {
+		createdAt := datetime.Of(at)
+		current.Where("?.? <= ?", bun.Name(historyCurrentAlias), bun.Name(constants.ColumnCreatedAt), createdAt)
+		recorded.Where("?.? <= ?", bun.Name(historyAlias), bun.Name(constants.ColumnCreatedAt), createdAt)
+	}
+
+	return bun.SafeQuery("? UNION ALL ?", current, recorded)
+}
@@ -0,0 +1,141 @@
+package orm
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type HistoryDoc struct {
+	bun.BaseModel `bun:"table:test_history_doc,alias:thd"`
+	Model
+
+	Title string `json:"title" bun:"title,notnull"`
+}
+
+type HistoryDocRecord struct {
+	HistoryDoc `bun:",extend"`
+
+	Operation string `bun:"history_operation"`
+	Actor     string `bun:"history_actor"`
+}
+
+// HistoryTestSuite tests recording the history of updates and deletes and querying it with AsOf.
+type HistoryTestSuite struct {
+	*OrmTestSuite
+}
+
+func (suite *HistoryTestSuite) SetupSuite() {
+	suite.Require().NoError(RegisterHistory(suite.db, (*HistoryDoc)(nil)))
+
+	bunDB := suite.getBunDB()
+
+	_, err := bunDB.NewDropTable().Table("test_history_doc" + HistoryTableSuffix).IfExists().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should drop existing history table")
+
+	_, err = bunDB.NewDropTable().Model((*HistoryDoc)(nil)).IfExists().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should drop existing history test table")
+
+	_, err = bunDB.NewCreateTable().Model((*HistoryDoc)(nil)).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should create history test table")
+
+	suite.Require().NoError(CreateHistoryTables(suite.ctx, suite.db, (*HistoryDoc)(nil)), "Should create history table")
+}
+
+func (suite *HistoryTestSuite) TearDownSuite() {
+	bunDB := suite.getBunDB()
+
+	_, err := bunDB.NewDropTable().Table("test_history_doc" + HistoryTableSuffix).IfExists().Exec(suite.ctx)
+	suite.NoError(err, "Should cleanup history table")
+
+	_, err = bunDB.NewDropTable().Model((*HistoryDoc)(nil)).IfExists().Exec(suite.ctx)
+	suite.NoError(err, "Should cleanup history test table")
+}
+
+// now returns the current time, after waiting for the clock to move past the previous statements.
+func (suite *HistoryTestSuite) now() time.Time {
+	time.Sleep(5 * time.Millisecond)
+	now := time.Now()
+	time.Sleep(5 * time.Millisecond)
+
+	return now
+}
+
+func (suite *HistoryTestSuite) titleAsOf(id string, at time.Time) []string {
+	var titles []string
+
+	err := suite.db.NewSelect().
+		Model((*HistoryDoc)(nil)).
+		AsOf(at).
+		Select("title").
+		Where(func(cb ConditionBuilder) {
+			cb.PKEquals(id)
+		}).
+		Scan(suite.ctx, &titles)
+	suite.Require().NoError(err, "AsOf query should execute successfully")
+
+	return titles
+}
+
+func (suite *HistoryTestSuite) TestRecordAndAsOf() {
+	doc := &HistoryDoc{Title: "Draft"}
+	_, err := suite.db.NewInsert().Model(doc).Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	draftAt := suite.now()
+
+	_, err = suite.db.NewUpdate().
+		Model((*HistoryDoc)(nil)).
+		Set("title", "Final").
+		Where(func(cb ConditionBuilder) {
+			cb.PKEquals(doc.ID)
+		}).
+		Exec(suite.ctx)
+	suite.Require().NoError(err, "Update should execute successfully")
+
+	finalAt := suite.now()
+
+	_, err = suite.db.NewDelete().
+		Model((*HistoryDoc)(nil)).
+		Where(func(cb ConditionBuilder) {
+			cb.PKEquals(doc.ID)
+		}).
+		Exec(suite.ctx)
+	suite.Require().NoError(err, "Delete should execute successfully")
+
+	var records []HistoryDocRecord
+
+	err = suite.db.NewSelect().
+		Model(&records).
+		ModelTable("test_history_doc" + HistoryTableSuffix).
+		Where(func(cb ConditionBuilder) {
+			cb.Equals("id", doc.ID)
+		}).
+		OrderBy(ColumnHistoryAt).
+		Scan(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(records, 2, "Should record the replaced versions")
+	suite.Equal("Draft", records[0].Title)
+	suite.Equal(HistoryOperationUpdate, records[0].Operation)
+	suite.Equal("Final", records[1].Title)
+	suite.Equal(HistoryOperationDelete, records[1].Operation)
+	suite.NotEmpty(records[0].Actor, "Should record the operator")
+
+	suite.Equal([]string{"Draft"}, suite.titleAsOf(doc.ID, draftAt), "Should read the version valid before the update")
+	suite.Equal([]string{"Final"}, suite.titleAsOf(doc.ID, finalAt), "Should read the version valid before the delete")
+	suite.Empty(suite.titleAsOf(doc.ID, time.Now()), "Should not read deleted rows")
+}
+
+func (suite *HistoryTestSuite) TestAsOfUnchangedRows() {
+	doc := &HistoryDoc{Title: "Stable"}
+	_, err := suite.db.NewInsert().Model(doc).Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	suite.Equal([]string{"Stable"}, suite.titleAsOf(doc.ID, suite.now()), "Should read unchanged rows from the table")
+}
+
+func (suite *HistoryTestSuite) TestAsOfUnregistered() {
+	suite.Panics(func() {
+		suite.db.NewSelect().Model((*User)(nil)).AsOf(time.Now())
+	}, "Should reject models without recorded history")
+}
@@ -1,27 +1,246 @@
 package orm
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/uptrace/bun/dialect"
 	"github.com/uptrace/bun/schema"
 
 	"github.com/ilxqx/vef-framework-go/constants"
 	"github.com/ilxqx/vef-framework-go/id"
 )
 
+// TagIDGenerator is the struct tag selecting the strategy generating the id primary key of a model, set on the id
+// field or, for models embedding Model, on the embedded bun.BaseModel. It names a registered generator or a database
+// sequence, see IDSequence.
+// Example: `bun:"table:sys_order" idgen:"ulid"` or `bun:"table:sys_order" idgen:"sequence:sys_order_id_seq"`.
+const TagIDGenerator = "idgen"
+
+// Names of the built-in id generators.
+const (
+	IDGeneratorXID       = "xid"
+	IDGeneratorSnowflake = "snowflake"
+	IDGeneratorULID      = "ulid"
+	IDGeneratorUUID      = "uuid"
+)
+
+// idSequencePrefix prefixes the sequence name in the strategies drawing ids from database sequences.
+const idSequencePrefix = "sequence:"
+
+var (
+	idGenerators = map[string]id.IDGenerator{
+		IDGeneratorXID:       id.DefaultXIDGenerator,
+		IDGeneratorSnowflake: id.DefaultSnowflakeIDGenerator,
+		IDGeneratorULID:      id.DefaultULIDGenerator,
+		IDGeneratorUUID:      id.DefaultUUIDGenerator,
+	}
+	// idStrategies holds the strategies registered for tables, taking precedence over their tags
+	idStrategies = make(map[string]string)
+	idMu         sync.RWMutex
+
+	defaultIDStrategy atomic.Value
+)
+
+// IDSequence returns the strategy drawing ids from the named database sequence, supported on PostgreSQL.
+// Integer ids receive the values, string ids their decimal representation.
+func IDSequence(name string) string {
+	return idSequencePrefix + name
+}
+
+// RegisterIDGenerator registers a generator under the name strategies select it by, replacing the generator
+// registered under the name before, e.g. a snowflake generator of the node of the instance.
+func RegisterIDGenerator(name string, generator id.IDGenerator) {
+	idMu.Lock()
+	defer idMu.Unlock()
+
+	idGenerators[name] = generator
+}
+
+// RegisterIDStrategy selects the strategy generating the ids of the given models, in place of their tags, e.g. for
+// models of other packages. An empty strategy restores the tags.
+func RegisterIDStrategy(db DB, strategy string, models ...any) error {
+	if strategy != constants.Empty {
+		if err := validateIDStrategy(strategy); err != nil {
+			return err
+		}
+	}
+
+	idMu.Lock()
+	defer idMu.Unlock()
+
+	for _, model := range models {
+		table := db.TableOf(model)
+		if strategy == constants.Empty {
+			delete(idStrategies, table.Name)
+		} else {
+			idStrategies[table.Name] = strategy
+		}
+	}
+
+	return nil
+}
+
+// SetDefaultIDStrategy sets the strategy of the models selecting none, empty restores IDGeneratorXID.
+func SetDefaultIDStrategy(strategy string) error {
+	if strategy != constants.Empty {
+		if err := validateIDStrategy(strategy); err != nil {
+			return err
+		}
+	}
+
+	defaultIDStrategy.Store(strategy)
+
+	return nil
+}
+
+func validateIDStrategy(strategy string) error {
+	if sequence, ok := strings.CutPrefix(strategy, idSequencePrefix); ok {
+		if sequence == constants.Empty {
+			return fmt.Errorf("%w: %q names no sequence", ErrUnknownIDGenerator, strategy)
+		}
+
+		return nil
+	}
+
+	idMu.RLock()
+	defer idMu.RUnlock()
+
+	if _, ok := idGenerators[strategy]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownIDGenerator, strategy)
+	}
+
+	return nil
+}
+
+// idStrategyOf returns the strategy of the id field of the table: the registered one, the tag of the field, the tag
+// of the embedded bun.BaseModel, and the default strategy in that order.
+func idStrategyOf(table *schema.Table, field *schema.Field) string {
+	idMu.RLock()
+	strategy, ok := idStrategies[table.Name]
+	idMu.RUnlock()
+
+	if ok {
+		return strategy
+	}
+
+	if strategy = field.StructField.Tag.Get(TagIDGenerator); strategy != constants.Empty {
+		return strategy
+	}
+
+	if strategy = baseModelTag(table, TagIDGenerator); strategy != constants.Empty {
+		return strategy
+	}
+
+	if strategy, _ = defaultIDStrategy.Load().(string); strategy != constants.Empty {
+		return strategy
+	}
+
+	return IDGeneratorXID
+}
+
 // IDHandler implements InsertHandler for automatically generating unique primary key IDs.
-// It uses Snowflake algorithm to generate distributed unique IDs in Base36 format.
+// The generator is selected per model through TagIDGenerator or RegisterIDStrategy, XID by default.
 type IDHandler struct{}
 
 // OnInsert automatically generates a unique ID for string primary key fields that are zero-valued.
-// It only applies to primary key fields of string type that haven't been explicitly set.
-func (*IDHandler) OnInsert(_ *BunInsertQuery, _ *schema.Table, field *schema.Field, _ any, value reflect.Value) {
-	if field.IsPK && field.IndirectType.Kind() == reflect.String && value.IsZero() {
-		value.SetString(id.Generate())
+// It only applies to primary key fields of string type that haven't been explicitly set, ids drawn from sequences
+// being assigned before the auto columns.
+func (*IDHandler) OnInsert(_ *BunInsertQuery, table *schema.Table, field *schema.Field, _ any, value reflect.Value) {
+	if !field.IsPK || field.IndirectType.Kind() != reflect.String || !value.IsZero() {
+		return
+	}
+
+	strategy := idStrategyOf(table, field)
+	if strings.HasPrefix(strategy, idSequencePrefix) {
+		return
 	}
+
+	idMu.RLock()
+	generator, ok := idGenerators[strategy]
+	idMu.RUnlock()
+
+	if !ok {
+		logger.Panicf("IDHandler: model %s selects unknown id generator %q", table.TypeName, strategy)
+	}
+
+	value.SetString(generator.Generate())
 }
 
 // Name returns the column name for the ID field.
 func (*IDHandler) Name() string {
 	return constants.ColumnID
 }
+
+// assignSequenceIDs draws the zero ids of the inserted models from the database sequence of their strategy, all of
+// them in one statement.
+func (q *BunInsertQuery) assignSequenceIDs(ctx context.Context, table *schema.Table, mv reflect.Value) error {
+	field, ok := table.FieldMap[constants.ColumnID]
+	if !ok || !field.IsPK {
+		return nil
+	}
+
+	sequence, ok := strings.CutPrefix(idStrategyOf(table, field), idSequencePrefix)
+	if !ok {
+		return nil
+	}
+
+	var values []reflect.Value
+
+	collect := func(row reflect.Value) {
+		row = reflect.Indirect(row)
+		if !row.IsValid() {
+			return
+		}
+
+		if value := field.Value(row); value.IsZero() {
+			values = append(values, value)
+		}
+	}
+
+	if mv.Kind() == reflect.Slice {
+		for i := range mv.Len() {
+			collect(mv.Index(i))
+		}
+	} else {
+		collect(mv)
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	if q.dialect.Name() != dialect.PG {
+		return fmt.Errorf("%w: id sequence %s of %s on %s", ErrDialectUnsupportedOperation, sequence, table.Name, q.dialect.Name())
+	}
+
+	db, _, err := q.db.conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	var ids []int64
+	if err := db.NewRaw("SELECT nextval(?) FROM generate_series(1, ?)", sequence, len(values)).Scan(ctx, &ids); err != nil {
+		return fmt.Errorf("failed to draw ids from sequence %s: %w", sequence, err)
+	}
+
+	for i, value := range values {
+		switch value.Kind() {
+		case reflect.String:
+			value.SetString(strconv.FormatInt(ids[i], 10))
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			value.SetInt(ids[i])
+		case reflect.Uint, reflect.Uint32, reflect.Uint64:
+			value.SetUint(uint64(ids[i]))
+		default:
+			return fmt.Errorf("%w: %s of %s drawn from sequence %s", ErrPrimaryKeyUnsupportedType, value.Type(), table.Name, sequence)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package orm
+
+import (
+	"fmt"
+	"regexp"
+	"sync/atomic"
+
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// IDNote selects ULIDs through the tag on its embedded bun.BaseModel.
+type IDNote struct {
+	bun.BaseModel `bun:"table:test_id_note,alias:tin" idgen:"ulid"`
+	Model
+
+	Title string `json:"title" bun:"title,notnull"`
+}
+
+// IDDefaultNote shares the table of IDNote, selecting no strategy.
+type IDDefaultNote struct {
+	bun.BaseModel `bun:"table:test_id_note,alias:tin"`
+	Model
+
+	Title string `json:"title" bun:"title,notnull"`
+}
+
+// IDCounter draws its integer ids from a database sequence.
+type IDCounter struct {
+	bun.BaseModel `bun:"table:test_id_counter,alias:tic"`
+
+	ID    int64  `json:"id"    bun:"id,pk"                                idgen:"sequence:test_id_counter_seq"`
+	Title string `json:"title" bun:"title,notnull"`
+}
+
+// counterIDGenerator generates sequential ids with a prefix.
+type counterIDGenerator struct {
+	next atomic.Int64
+}
+
+func (g *counterIDGenerator) Generate() string {
+	return fmt.Sprintf("note-%d", g.next.Add(1))
+}
+
+// IDTestSuite tests the strategies generating the ids of inserted models.
+type IDTestSuite struct {
+	*OrmTestSuite
+}
+
+func (suite *IDTestSuite) SetupSuite() {
+	bunDB := suite.getBunDB()
+
+	for _, model := range []any{(*IDNote)(nil), (*IDCounter)(nil)} {
+		_, err := bunDB.NewDropTable().Model(model).IfExists().Exec(suite.ctx)
+		suite.Require().NoError(err, "Should drop existing id test table")
+
+		_, err = bunDB.NewCreateTable().Model(model).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should create id test table")
+	}
+
+	if suite.dbType == constants.Postgres {
+		_, err := bunDB.ExecContext(suite.ctx, "CREATE SEQUENCE IF NOT EXISTS test_id_counter_seq START 100")
+		suite.Require().NoError(err, "Should create id test sequence")
+	}
+}
+
+func (suite *IDTestSuite) TearDownSuite() {
+	bunDB := suite.getBunDB()
+
+	for _, model := range []any{(*IDNote)(nil), (*IDCounter)(nil)} {
+		_, err := bunDB.NewDropTable().Model(model).IfExists().Exec(suite.ctx)
+		suite.NoError(err, "Should cleanup id test table")
+	}
+
+	if suite.dbType == constants.Postgres {
+		_, err := bunDB.ExecContext(suite.ctx, "DROP SEQUENCE IF EXISTS test_id_counter_seq")
+		suite.NoError(err, "Should cleanup id test sequence")
+	}
+}
+
+func (suite *IDTestSuite) insertNote(title string) *IDNote {
+	note := &IDNote{Title: title}
+
+	_, err := suite.db.NewInsert().Model(note).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert the note")
+
+	return note
+}
+
+func (suite *IDTestSuite) TestTagStrategy() {
+	note := suite.insertNote("ULID")
+
+	suite.Regexp(regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`), note.ID, "Should generate a ULID as the tag selects")
+}
+
+func (suite *IDTestSuite) TestDefaultStrategy() {
+	insert := func() string {
+		note := &IDDefaultNote{Title: "Default"}
+
+		_, err := suite.db.NewInsert().Model(note).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should insert the note")
+
+		return note.ID
+	}
+
+	suite.Regexp(regexp.MustCompile(`^[0-9a-v]{20}$`), insert(), "Models selecting no strategy should get XIDs")
+
+	suite.Require().NoError(SetDefaultIDStrategy(IDGeneratorSnowflake))
+
+	defer func() {
+		suite.NoError(SetDefaultIDStrategy(constants.Empty))
+	}()
+
+	suite.NotRegexp(regexp.MustCompile(`^[0-9a-v]{20}$`), insert(), "Should generate ids of the default strategy")
+	suite.Len(suite.insertNote("Tagged").ID, 26, "Tags should take precedence over the default strategy")
+	suite.ErrorIs(SetDefaultIDStrategy("nanoid"), ErrUnknownIDGenerator)
+}
+
+func (suite *IDTestSuite) TestRegisteredStrategy() {
+	suite.Require().NoError(RegisterIDStrategy(suite.db, IDGeneratorUUID, (*IDNote)(nil)))
+
+	note := suite.insertNote("UUID")
+	suite.Regexp(regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), note.ID,
+		"Registered strategy should take precedence over the tag")
+
+	suite.Require().NoError(RegisterIDStrategy(suite.db, constants.Empty, (*IDNote)(nil)))
+	suite.Len(suite.insertNote("Restored").ID, 26, "Empty strategy should restore the tag")
+
+	suite.ErrorIs(RegisterIDStrategy(suite.db, "nanoid", (*IDNote)(nil)), ErrUnknownIDGenerator)
+	suite.ErrorIs(RegisterIDStrategy(suite.db, IDSequence(constants.Empty), (*IDNote)(nil)), ErrUnknownIDGenerator)
+}
+
+func (suite *IDTestSuite) TestCustomGenerator() {
+	RegisterIDGenerator("test_counter", new(counterIDGenerator))
+	suite.Require().NoError(RegisterIDStrategy(suite.db, "test_counter", (*IDNote)(nil)))
+
+	defer func() {
+		suite.NoError(RegisterIDStrategy(suite.db, constants.Empty, (*IDNote)(nil)))
+	}()
+
+	notes := []IDNote{{Title: "First"}, {Title: "Second"}}
+	_, err := suite.db.NewInsert().Model(&notes).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert the notes")
+
+	suite.Equal("note-1", notes[0].ID)
+	suite.Equal("note-2", notes[1].ID)
+
+	explicit := &IDNote{Model: Model{ID: "explicit"}, Title: "Explicit"}
+	_, err = suite.db.NewInsert().Model(explicit).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert the note")
+	suite.Equal("explicit", explicit.ID, "Should keep ids set explicitly")
+}
+
+func (suite *IDTestSuite) TestSequence() {
+	counters := []IDCounter{{Title: "First"}, {Title: "Second"}}
+
+	_, err := suite.db.NewInsert().Model(&counters).Exec(suite.ctx)
+	if suite.dbType != constants.Postgres {
+		suite.ErrorIs(err, ErrDialectUnsupportedOperation, "Sequences should require PostgreSQL")
+
+		return
+	}
+
+	suite.Require().NoError(err, "Should insert the counters")
+	suite.ElementsMatch([]int64{100, 101}, []int64{counters[0].ID, counters[1].ID}, "Should draw the ids from the sequence")
+
+	count, err := suite.db.NewSelect().Model((*IDCounter)(nil)).Where(func(cb ConditionBuilder) {
+		cb.GreaterThanOrEqual("id", 100)
+	}).Count(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), count)
+}
@@ -0,0 +1,124 @@
+package orm
+
+import (
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+const (
+	// mysqlCaseInsensitiveCollation is the collation used for case-insensitive comparisons on MySQL.
+	mysqlCaseInsensitiveCollation = "utf8mb4_general_ci"
+	// sqlServerCaseInsensitiveCollation is the collation used for case-insensitive comparisons on SQL Server.
+	sqlServerCaseInsensitiveCollation = "Latin1_General_CI_AS"
+	// sqlTypeCitext is the PostgreSQL case-insensitive text type.
+	sqlTypeCitext = "citext"
+)
+
+// likeEscaper escapes LIKE wildcards so that ILIKE can be used as a case-insensitive equality.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// buildEqualsIgnoreCase builds a case-insensitive equality condition.
+// A declared LOWER(column) functional index always wins so the index can be used; otherwise
+// PostgreSQL uses plain equality on citext columns and ILIKE elsewhere, MySQL and SQL Server use a
+// case-insensitive collation, and the remaining databases compare LOWER() of both sides.
+func (cb *CriteriaBuilder) buildEqualsIgnoreCase(column, value string) schema.QueryAppender {
+	columnExpr := cb.eb.Column(column)
+	if cb.hasLowerIndex(column) {
+		return cb.eb.Equals(cb.eb.Lower(columnExpr), cb.eb.Lower(value))
+	}
+
+	cb.warnMissingLowerIndex(column)
+
+	return cb.eb.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			if cb.isCitextColumn(column) {
+				return cb.eb.Equals(columnExpr, value)
+			}
+
+			return cb.eb.Expr("? ILIKE ?", columnExpr, likeEscaper.Replace(value))
+		},
+		MySQL: func() schema.QueryAppender {
+			return cb.eb.Expr("? = ? COLLATE ?", columnExpr, value, bun.Safe(mysqlCaseInsensitiveCollation))
+		},
+		SQLServer: func() schema.QueryAppender {
+			return cb.eb.Expr("? COLLATE ? = ?", columnExpr, bun.Safe(sqlServerCaseInsensitiveCollation), value)
+		},
+		Default: func() schema.QueryAppender {
+			return cb.eb.Equals(cb.eb.Lower(columnExpr), cb.eb.Lower(value))
+		},
+	})
+}
+
+// buildInIgnoreCase builds a case-insensitive IN condition following the same dialect rules as buildEqualsIgnoreCase,
+// except that PostgreSQL compares LOWER() of both sides for non-citext columns since ILIKE has no list form.
+func (cb *CriteriaBuilder) buildInIgnoreCase(column string, values []string) schema.QueryAppender {
+	if len(values) == 0 {
+		return cb.eb.Expr("1 = 0")
+	}
+
+	columnExpr := cb.eb.Column(column)
+	lowerIn := func() schema.QueryAppender {
+		lowered := make([]any, len(values))
+		for i, value := range values {
+			lowered[i] = cb.eb.Lower(value)
+		}
+
+		return cb.eb.Expr("? IN (?)", cb.eb.Lower(columnExpr), cb.eb.Exprs(lowered...))
+	}
+
+	if cb.hasLowerIndex(column) {
+		return lowerIn()
+	}
+
+	cb.warnMissingLowerIndex(column)
+
+	return cb.eb.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			if cb.isCitextColumn(column) {
+				return cb.eb.Expr("? IN (?)", columnExpr, bun.In(values))
+			}
+
+			return lowerIn()
+		},
+		MySQL: func() schema.QueryAppender {
+			return cb.eb.Expr("? COLLATE ? IN (?)", columnExpr, bun.Safe(mysqlCaseInsensitiveCollation), bun.In(values))
+		},
+		SQLServer: func() schema.QueryAppender {
+			return cb.eb.Expr("? COLLATE ? IN (?)", columnExpr, bun.Safe(sqlServerCaseInsensitiveCollation), bun.In(values))
+		},
+		Default: lowerIn,
+	})
+}
+
+// hasLowerIndex reports whether a LOWER(column) functional index is declared for the model table.
+func (cb *CriteriaBuilder) hasLowerIndex(column string) bool {
+	field := resolveModelField(cb.qb.GetTable(), column)
+	if field == nil {
+		return false
+	}
+
+	return hasExpressionIndex(cb.qb.GetTable(), "lower("+field.Name+")")
+}
+
+// warnMissingLowerIndex warns once when a case-insensitive comparison on a model column is not backed by
+// a LOWER(column) functional index, unless the column is citext which is directly indexable on PostgreSQL.
+func (cb *CriteriaBuilder) warnMissingLowerIndex(column string) {
+	field := resolveModelField(cb.qb.GetTable(), column)
+	if field == nil || cb.isCitextColumn(column) {
+		return
+	}
+
+	warnMissingExpressionIndex(cb.qb.GetTable(), "LOWER("+field.Name+")")
+}
+
+// isCitextColumn reports whether the column is declared with the PostgreSQL citext type.
+func (cb *CriteriaBuilder) isCitextColumn(column string) bool {
+	field := resolveModelField(cb.qb.GetTable(), column)
+	if field == nil {
+		return false
+	}
+
+	return strings.EqualFold(field.UserSQLType, sqlTypeCitext) || strings.EqualFold(field.CreateTableSQLType, sqlTypeCitext)
+}
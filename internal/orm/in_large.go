@@ -0,0 +1,67 @@
+package orm
+
+import (
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/schema"
+)
+
+// DefaultInLargeThreshold is the number of values above which InLarge stops emitting an IN list.
+const DefaultInLargeThreshold = 1000
+
+var inLargeThreshold atomic.Int64
+
+// SetInLargeThreshold sets the number of values above which InLarge stops emitting an IN list, values below one
+// restore DefaultInLargeThreshold.
+func SetInLargeThreshold(threshold int) {
+	inLargeThreshold.Store(int64(threshold))
+}
+
+func currentInLargeThreshold() int64 {
+	if threshold := inLargeThreshold.Load(); threshold > 0 {
+		return threshold
+	}
+
+	return DefaultInLargeThreshold
+}
+
+// inLarge checks if the column is in the values, rendering an IN list up to the threshold. Larger sets are joined as
+// a table of values rather than compared element by element with thousands of literals: an IN subquery over a VALUES
+// table on MySQL and SQLite and a single array, "= ANY(?)", on Postgres, whose VALUES rows would be typed as text
+// and fail to compare with uuid and other non-text columns.
+func (cb *CriteriaBuilder) inLarge(column string, values any) schema.QueryAppender {
+	value := reflect.ValueOf(values)
+	if (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) || int64(value.Len()) <= currentInLargeThreshold() {
+		return cb.eb.Expr("? IN (?)", cb.eb.Column(column), bun.In(values))
+	}
+
+	valuesTable := func(row string) schema.QueryAppender {
+		args := make([]any, value.Len()+1)
+		args[0] = cb.eb.Column(column)
+
+		for i := range value.Len() {
+			args[i+1] = value.Index(i).Interface()
+		}
+
+		return cb.eb.Expr("? IN (VALUES "+strings.Repeat(row+", ", value.Len()-1)+row+")", args...)
+	}
+
+	return cb.eb.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return cb.eb.Expr("? = ANY (?)", cb.eb.Column(column), pgdialect.Array(values))
+		},
+		MySQL: func() schema.QueryAppender {
+			return valuesTable("ROW(?)")
+		},
+		SQLite: func() schema.QueryAppender {
+			return valuesTable("(?)")
+		},
+		Default: func() schema.QueryAppender {
+			return cb.eb.Expr("? IN (?)", cb.eb.Column(column), bun.In(values))
+		},
+	})
+}
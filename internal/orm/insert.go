@@ -45,6 +45,7 @@ type BunInsertQuery struct {
 	query   *bun.InsertQuery
 
 	returningColumns collections.Set[string]
+	conflict         *InsertQueryConflictBuilder
 }
 
 func (q *BunInsertQuery) DB() DB {
@@ -135,9 +136,8 @@ func (q *BunInsertQuery) TableSubQuery(builder func(SelectQuery), alias ...strin
 
 // OnConflict configures conflict handling via a dialect-aware builder.
 func (q *BunInsertQuery) OnConflict(builder func(ConflictBuilder)) InsertQuery {
-	cb := newConflictBuilder(q)
-	builder(cb)
-	cb.build(q.query)
+	q.conflict = newConflictBuilder(q)
+	builder(q.conflict)
 
 	return q
 }
@@ -217,42 +217,166 @@ func (q *BunInsertQuery) ApplyIf(condition bool, fns ...ApplyFunc[InsertQuery])
 	return q
 }
 
-// beforeInsert applies auto column handlers before executing the insert operation.
-// It processes InsertHandler to automatically set values like IDs, timestamps, and user tracking.
-func (q *BunInsertQuery) beforeInsert() {
+// beforeInsert routes the insert to the database of the tenant and applies auto column handlers before executing it.
+// It processes InsertHandler to automatically set values like IDs, timestamps, and user tracking, and stores the
+// tenant of the request under row-per-tenant tenancy, where conflicting rows are only updated when of the tenant.
+func (q *BunInsertQuery) beforeInsert(ctx context.Context) error {
+	tenantID, err := q.db.applyTenancy(ctx, q.query, nil, nil)
+	if err != nil {
+		return err
+	}
+
 	if table := q.GetTable(); table != nil {
 		modelValue := q.query.GetModel().Value()
 		mv := reflect.Indirect(reflect.ValueOf(modelValue))
 
+		if err := q.assignSequenceIDs(ctx, table, mv); err != nil {
+			return err
+		}
+
 		processAutoColumns(q, table, modelValue, mv)
+
+		if tenantID != constants.Empty && q.db.tenancy.filters(table) {
+			q.ColumnExpr(q.db.tenancy.column, func(eb ExprBuilder) any {
+				return eb.Expr("?", tenantID)
+			})
+
+			if q.conflict != nil {
+				q.conflict.guardTenant(q.db.tenancy.column, tenantID)
+			}
+		}
+	}
+
+	if q.conflict != nil {
+		q.conflict.build(q.query)
 	}
 
 	if !q.returningColumns.IsEmpty() {
 		q.query.Returning("?", buildReturningExpr(q.returningColumns, q.eb))
 	}
+
+	return nil
 }
 
 func (q *BunInsertQuery) Exec(ctx context.Context, dest ...any) (sql.Result, error) {
-	q.beforeInsert()
+	ctx = withRejection(ctx)
 
-	res, err := q.query.Exec(ctx, dest...)
-	if err != nil {
-		return nil, translateWriteError(err)
+	if err := q.beforeInsert(ctx); err != nil {
+		return nil, err
+	}
+
+	var (
+		res sql.Result
+		err error
+	)
+
+	if err = q.run(ctx, func(ctx context.Context) error {
+		res, err = q.query.Exec(ctx, dest...)
+
+		return err
+	}); err != nil {
+		return nil, contextError(ctx, translateWriteError(err))
 	}
 
 	return res, nil
 }
 
 func (q *BunInsertQuery) Scan(ctx context.Context, dest ...any) error {
-	q.beforeInsert()
+	ctx = withRejection(ctx)
 
-	if err := q.query.Scan(ctx, dest...); err != nil {
-		return translateWriteError(err)
+	if err := q.beforeInsert(ctx); err != nil {
+		return err
+	}
+
+	if err := q.run(ctx, func(ctx context.Context) error {
+		return q.query.Scan(ctx, dest...)
+	}); err != nil {
+		return contextError(ctx, translateWriteError(err))
 	}
 
 	return nil
 }
 
+// run executes the insert, within a transaction adjusting the summary columns over the inserted rows,
+// refreshing their denormalized columns and reading them for the audit events if any.
+func (q *BunInsertQuery) run(ctx context.Context, exec func(context.Context) error) error {
+	model, ok := q.query.GetModel().(bun.TableModel)
+	if !ok {
+		return exec(ctx)
+	}
+
+	table := model.Table()
+
+	// Upserts may update existing rows, which the summary columns do not track
+	var summaries []*summary
+	if q.conflict == nil {
+		summaries = summariesOver(table)
+	}
+
+	denorms := denormsOf(table)
+	sink := auditOf(table)
+
+	if len(summaries) == 0 && len(denorms) == 0 && sink == nil {
+		return exec(ctx)
+	}
+
+	return q.db.runInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		txDB := &BunDB{db: tx, dataScopes: q.db.dataScopes, tenancy: q.db.tenancy}
+
+		// Upserts record the rows they update as updates
+		var before []map[string]any
+
+		if sink != nil && q.conflict != nil {
+			var err error
+			if before, err = readAuditRowsByKeys(ctx, txDB, model.Value(), table, auditModelKeys(table, model.Value())); err != nil {
+				return err
+			}
+		}
+
+		q.query.Conn(tx)
+
+		if err := exec(ctx); err != nil {
+			return err
+		}
+
+		if len(summaries) > 0 {
+			deltas := make(summaryDeltas)
+			if err := deltas.add(summaries, modelRows(table, summaryColumns(summaries), model.Value()), 1); err != nil {
+				return err
+			}
+
+			if err := deltas.apply(ctx, tx); err != nil {
+				return err
+			}
+		}
+
+		if len(denorms) > 0 {
+			pks := make([]string, len(table.PKs))
+			for i, pk := range table.PKs {
+				pks[i] = pk.Name
+			}
+
+			keys := rowKeys(table.PKs, modelRows(table, pks, model.Value()))
+			for _, d := range denorms {
+				if err := d.refresh(ctx, tx, table.PKs, keys); err != nil {
+					return err
+				}
+			}
+		}
+
+		if sink != nil {
+			after, err := readAuditRowsByKeys(ctx, txDB, model.Value(), table, auditModelKeys(table, model.Value()))
+			if err != nil {
+				return err
+			}
+
+			return writeAuditEvents(ctx, txDB, sink, auditEvents(table, AuditOperationInsert, before, after))
+		}
+
+		return nil
+	})
+}
+
 func (q *BunInsertQuery) Unwrap() *bun.InsertQuery {
 	return q.query
 }
@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/schema"
 
 	"github.com/ilxqx/vef-framework-go/page"
+	"github.com/ilxqx/vef-framework-go/sortx"
 )
 
 // DBAccessor provides access to the underlying DB instance.
@@ -21,8 +23,10 @@ type DBAccessor interface {
 // It provides the basic execution methods that all query types must implement.
 type QueryExecutor interface {
 	// Exec executes a query and returns the result.
+	// Errors caused by a cancelled context match context.Canceled or context.DeadlineExceeded.
 	Exec(ctx context.Context, dest ...any) (sql.Result, error)
 	// Scan scans the result into a slice of any type.
+	// Reading queries fail with the context error when the context is cancelled before scanning completes.
 	Scan(ctx context.Context, dest ...any) error
 }
 
@@ -99,7 +103,10 @@ type JoinOperations[T any] interface {
 	// RightJoinExpr performs a RIGHT OUTER JOIN with a custom expression.
 	RightJoinExpr(eBuilder func(ExprBuilder) any, cBuilder func(ConditionBuilder), alias ...string) T
 
-	// FullJoin performs a FULL OUTER JOIN with a model.
+	// FullJoin performs a FULL OUTER JOIN with a model. MySQL lacks FULL OUTER JOIN, so the query runs there as the
+	// union of its LEFT JOIN and RIGHT JOIN parts wrapped in a derived table, its order, limit and offset applying to
+	// the union and referencing the selected columns; it supports one FULL JOIN per query, executing queries with more
+	// failing with ErrDialectUnsupportedOperation. SQLite supports FULL OUTER JOIN from 3.39.
 	FullJoin(model any, builder func(ConditionBuilder), alias ...string) T
 	// FullJoinTable performs a FULL OUTER JOIN with a table name.
 	FullJoinTable(name string, builder func(ConditionBuilder), alias ...string) T
@@ -123,6 +130,8 @@ type JoinOperations[T any] interface {
 type Filterable[T QueryExecutor] interface {
 	// Where adds a where clause to the query.
 	Where(func(ConditionBuilder)) T
+	// WhereSpec adds a where clause to the query using the conditions of a spec, grouped as a whole.
+	WhereSpec(spec Spec) T
 	// WherePK adds a where clause to the query using the primary key.
 	WherePK(columns ...string) T
 	// WhereDeleted adds a where clause to the query using the deleted column.
@@ -406,6 +415,13 @@ type ExprBuilder interface {
 	Avg(func(AvgBuilder)) schema.QueryAppender
 	// AvgColumn builds an AVG(column) aggregate expression.
 	AvgColumn(column string, distinct ...bool) schema.QueryAppender
+	// CountIf builds a COUNT(*) aggregate expression counting the rows matching the condition,
+	// rendered with FILTER (WHERE ...) where supported and CASE WHEN otherwise.
+	CountIf(condition func(ConditionBuilder)) schema.QueryAppender
+	// SumIf builds a SUM(column) aggregate expression over the rows matching the condition.
+	SumIf(column string, condition func(ConditionBuilder)) schema.QueryAppender
+	// AvgIf builds an AVG(column) aggregate expression over the rows matching the condition.
+	AvgIf(column string, condition func(ConditionBuilder)) schema.QueryAppender
 	// Min builds a MIN aggregate expression using a builder callback.
 	Min(func(MinBuilder)) schema.QueryAppender
 	// MinColumn builds a MIN(column) aggregate expression.
@@ -415,7 +431,14 @@ type ExprBuilder interface {
 	// MaxColumn builds a MAX(column) aggregate expression.
 	MaxColumn(column string) schema.QueryAppender
 	// StringAgg builds a STRING_AGG aggregate expression using a builder callback.
+	// Oracle renders LISTAGG and SQL Server STRING_AGG with the ordering WITHIN GROUP.
 	StringAgg(func(StringAggBuilder)) schema.QueryAppender
+	// PercentileCont builds a PERCENTILE_CONT(fraction) WITHIN GROUP (ORDER BY ...) ordered-set aggregate,
+	// interpolating between values. Supported on Postgres and Oracle.
+	PercentileCont(func(PercentileBuilder)) schema.QueryAppender
+	// PercentileDisc builds a PERCENTILE_DISC(fraction) WITHIN GROUP (ORDER BY ...) ordered-set aggregate,
+	// returning the first value reaching the fraction. Supported on Postgres and Oracle.
+	PercentileDisc(func(PercentileBuilder)) schema.QueryAppender
 	// ArrayAgg builds an ARRAY_AGG aggregate expression using a builder callback.
 	ArrayAgg(func(ArrayAggBuilder)) schema.QueryAppender
 	// JSONObjectAgg builds a JSON_OBJECT_AGG aggregate expression using a builder callback.
@@ -538,6 +561,46 @@ type ExprBuilder interface {
 	// Reverse reverses a string.
 	Reverse(expr any) schema.QueryAppender
 
+	// ========== Hashing Functions ==========
+
+	// Md5 returns the MD5 digest of a string as 32 lowercase hex characters, hashing its UTF-8 bytes on every database.
+	// On SQLite it requires the md5 function the framework registers on its pure Go driver.
+	Md5(expr any) schema.QueryAppender
+	// Sha256 returns the SHA-256 digest of a string as 64 lowercase hex characters, hashing its UTF-8 bytes on every
+	// database. On SQLite it requires the sha256 function the framework registers on its pure Go driver.
+	Sha256(expr any) schema.QueryAppender
+	// Crc32 returns the IEEE CRC-32 checksum of a string as an unsigned integer. It requires PostgreSQL 18 and, on SQLite,
+	// the crc32 function the framework registers on its pure Go driver; SQL Server and Oracle do not support it.
+	Crc32(expr any) schema.QueryAppender
+
+	// ========== Encryption Functions ==========
+
+	// PgpEncrypt encrypts a string with a symmetric key using OpenPGP, returning bytes. It requires the pgcrypto
+	// extension of PostgreSQL; other databases do not support it. Pass orm.SecretKey to resolve the key from the secret provider.
+	PgpEncrypt(expr, key any) schema.QueryAppender
+	// PgpDecrypt decrypts the bytes encrypted by PgpEncrypt with the key, returning the string.
+	PgpDecrypt(expr, key any) schema.QueryAppender
+	// AesEncrypt encrypts a string with AES in ECB mode with PKCS padding, returning bytes, as MySQL's AES_ENCRYPT does.
+	// It is supported by MySQL, PostgreSQL with the pgcrypto extension and, on SQLite, the aes_encrypt function the framework
+	// registers on its pure Go driver. Values decrypt across these databases with keys of 16 bytes, which all of them use
+	// as the AES-128 key. Pass orm.SecretKey to resolve the key from the secret provider.
+	AesEncrypt(expr, key any) schema.QueryAppender
+	// AesDecrypt decrypts the bytes encrypted by AesEncrypt with the key, returning the string, NULL on MySQL and SQLite
+	// and an error on PostgreSQL for bytes not encrypted with the key.
+	AesDecrypt(expr, key any) schema.QueryAppender
+
+	// ========== Full-Text Search Functions ==========
+
+	// Match checks if a text expression matches a full-text search query: to_tsvector(expr) @@ websearch_to_tsquery(query)
+	// on PostgreSQL, MATCH (expr) AGAINST (query IN NATURAL LANGUAGE MODE) on MySQL, which requires a FULLTEXT index,
+	// expr MATCH query on SQLite, where expr is a column or the table of an FTS5 virtual table,
+	// FREETEXT(expr, query) on SQL Server and CONTAINS(expr, query) > 0 on Oracle.
+	Match(expr, query any) schema.QueryAppender
+	// MatchRank returns the relevance of a text expression to a full-text search query, greater for more relevant rows:
+	// ts_rank on PostgreSQL, the MATCH ... AGAINST score on MySQL and the negated bm25 rank of the FTS5 table
+	// of the query on SQLite. It is NULL on SQL Server and Oracle, which rank through CONTAINSTABLE and SCORE.
+	MatchRank(expr, query any) schema.QueryAppender
+
 	// ========== Date and Time Functions ==========
 
 	// CurrentDate returns the current date.
@@ -554,6 +617,8 @@ type ExprBuilder interface {
 	ExtractMonth(expr any) schema.QueryAppender
 	// ExtractDay extracts the day from a date/timestamp.
 	ExtractDay(expr any) schema.QueryAppender
+	// ExtractDayOfWeek extracts the ISO day of week (1 = Monday through 7 = Sunday) from a date/timestamp.
+	ExtractDayOfWeek(expr any) schema.QueryAppender
 	// ExtractHour extracts the hour from a timestamp.
 	ExtractHour(expr any) schema.QueryAppender
 	// ExtractMinute extracts the minute from a timestamp.
@@ -617,6 +682,19 @@ type ExprBuilder interface {
 	Greatest(args ...any) schema.QueryAppender
 	// Least returns the least value among arguments.
 	Least(args ...any) schema.QueryAppender
+	// WidthBucket assigns expr to one of buckets equal-width buckets spanning [low, high).
+	// Values below low fall into bucket 0 and values at or above high fall into bucket buckets+1.
+	WidthBucket(expr, low, high any, buckets int) schema.QueryAppender
+	// BitwiseAnd returns the bitwise AND of two integer expressions.
+	BitwiseAnd(left, right any) schema.QueryAppender
+	// BitwiseOr returns the bitwise OR of two integer expressions.
+	BitwiseOr(left, right any) schema.QueryAppender
+	// HasFlag checks whether all bits of flag are set in expr.
+	HasFlag(expr, flag any) schema.QueryAppender
+	// SetFlag returns expr with all bits of flag set.
+	SetFlag(expr, flag any) schema.QueryAppender
+	// ClearFlag returns expr with all bits of flag cleared.
+	ClearFlag(expr, flag any) schema.QueryAppender
 
 	// ========== Conditional Functions ==========
 
@@ -648,10 +726,30 @@ type ExprBuilder interface {
 	// ToJSON converts expression to JSON.
 	ToJSON(expr any) schema.QueryAppender
 
+	// ========== Array Functions ==========
+	// Array functions operate on native arrays on Postgres and on JSON arrays stored in the column on MySQL and SQLite.
+	// Values given as Go slices are bound as arrays or JSON arrays accordingly.
+
+	// Array builds an array of the values, an ARRAY[...] constructor on Postgres and a JSON array elsewhere.
+	// A single slice argument is expanded into its elements, e.g. Array(ids) or Array(1, 2, 3).
+	Array(values ...any) schema.QueryAppender
+	// ArrayLength returns the number of elements of an array, 0 for NULL.
+	ArrayLength(array any) schema.QueryAppender
+	// ArrayContains checks if an array contains all the values.
+	ArrayContains(array, values any) schema.QueryAppender
+	// ArrayOverlaps checks if an array contains any of the values.
+	ArrayOverlaps(array, values any) schema.QueryAppender
+	// ArrayAppend appends a value to an array.
+	ArrayAppend(array, value any) schema.QueryAppender
+	// ArrayRemove removes all the elements equal to a value from an array.
+	ArrayRemove(array, value any) schema.QueryAppender
+
 	// ========== JSON Functions ==========
 
 	// JSONExtract extracts value from JSON at specified path.
 	JSONExtract(json, path any) schema.QueryAppender
+	// JSONPath starts a path into JSON built step by step, see JSONPathBuilder.
+	JSONPath(json any) JSONPathBuilder
 	// JSONUnquote removes quotes from JSON string.
 	JSONUnquote(expr any) schema.QueryAppender
 	// JSONArray creates a JSON array from arguments.
@@ -679,25 +777,50 @@ type ExprBuilder interface {
 	// JSONArrayAppend appends value to JSON array at specified path.
 	JSONArrayAppend(json, path, value any) schema.QueryAppender
 
+	// ========== Spatial Functions ==========
+	// Spatial functions map to PostGIS, MySQL spatial functions and SpatiaLite. Geometries are points on the WGS 84
+	// coordinates, with SRID 4326 on Postgres and SQLite and SRID 0 on MySQL as ST_Distance_Sphere expects.
+	// Distances are in meters.
+
+	// Point builds the point at a latitude and longitude, stored with the longitude as X and the latitude as Y.
+	Point(lat, lng any) schema.QueryAppender
+	// StDistance returns the distance in meters between two geometries, on the spheroid on Postgres and on a sphere
+	// elsewhere. On SQLite it computes the haversine formula between two points, which works without SpatiaLite.
+	StDistance(geom1, geom2 any) schema.QueryAppender
+	// StWithin checks if the first geometry lies within the second, e.g. a location within an area.
+	// On SQLite it requires SpatiaLite.
+	StWithin(geom1, geom2 any) schema.QueryAppender
+	// StContains checks if the first geometry contains the second, e.g. an area containing a location.
+	// On SQLite it requires SpatiaLite.
+	StContains(geom1, geom2 any) schema.QueryAppender
+
 	// ========== Utility Functions ==========
 
 	// Decode implements DECODE function (Oracle-style case expression).
 	// Usage: Decode(expr, search1, result1, search2, result2, ..., defaultResult)
 	Decode(args ...any) schema.QueryAppender
+	// Row builds a row value of the values for comparisons of several columns at once, e.g.
+	// Expr("? IN (?)", Row(Column("a"), Column("b")), Exprs(Row(1, 2), Row(3, 4))).
+	// A single slice argument is expanded into its elements.
+	Row(values ...any) schema.QueryAppender
 }
 
 // SelectQueryExecutor is an interface that defines the methods for executing SELECT queries.
 // It extends QueryExecutor with additional methods specific to SELECT operations.
 type SelectQueryExecutor interface {
 	QueryExecutor
-	// Rows returns the result as a sql.Rows.
-	Rows(ctx context.Context) (*sql.Rows, error)
+	// Rows returns the result as rows, which must be closed to end the timeout of the query.
+	Rows(ctx context.Context) (*Rows, error)
 	// ScanAndCount scans the result into a slice of any type and returns the count of the result.
+	// Row and map destinations, of Scan too, get their values converted by their column types as Row describes.
 	ScanAndCount(ctx context.Context, dest ...any) (int64, error)
 	// Count returns the count of the result.
 	Count(ctx context.Context) (int64, error)
 	// Exists returns true if the result exists.
 	Exists(ctx context.Context) (bool, error)
+	// Explain returns the execution plan of the query, parsed from EXPLAIN (FORMAT JSON) on PostgreSQL,
+	// EXPLAIN FORMAT=JSON on MySQL and EXPLAIN QUERY PLAN on SQLite.
+	Explain(ctx context.Context, options ExplainOptions) (*QueryPlan, error)
 }
 
 // SelectQuery is an interface that defines the methods for building and executing SELECT queries.
@@ -730,6 +853,10 @@ type SelectQuery interface {
 	DistinctOnColumns(columns ...string) SelectQuery
 	// DistinctOnExpr returns a distinct query on an expression.
 	DistinctOnExpr(builder func(ExprBuilder) any) SelectQuery
+	// OuterColumn references a column of an enclosing query inside a correlated subquery, e.g. "id" for the model
+	// table of the enclosing query or "u.id" for a table aliased u in any enclosing query. It panics when called
+	// outside a subquery or when no enclosing query has the table or column.
+	OuterColumn(column string) schema.QueryAppender
 	// JoinRelations applies RelationSpec configurations to perform JOIN operations with automatic column resolution.
 	// It provides a declarative way to join related models with minimal configuration.
 	JoinRelations(specs ...*RelationSpec) SelectQuery
@@ -741,10 +868,39 @@ type SelectQuery interface {
 	GroupByExpr(func(ExprBuilder) any) SelectQuery
 	// Having adds a having clause to the query.
 	Having(func(ConditionBuilder)) SelectQuery
+	// Qualify filters the rows on the results of window functions, referenced by their select aliases, as QUALIFY
+	// does. No supported database has QUALIFY, so the query is wrapped in a derived table filtered by the condition,
+	// its order, limit and offset applying to the filtered rows and referencing the selected columns. Calls are
+	// combined with AND.
+	Qualify(func(ConditionBuilder)) SelectQuery
+	// TopNPerGroup keeps the first n rows of every group of rows sharing the partition columns, in the given order,
+	// e.g. the latest record per entity. It numbers the rows with ROW_NUMBER, selected as group_row_number along
+	// with the model columns unless others are selected, and filters on it with Qualify.
+	TopNPerGroup(partitionColumns []string, orders []sortx.OrderSpec, n int) SelectQuery
 	// Offset adds an offset to the query.
 	Offset(offset int) SelectQuery
 	// Paginate paginates the query.
 	Paginate(pageable page.Pageable) SelectQuery
+	// Sample restricts the query to an approximate percentage (0-100) of the model table rows.
+	// It uses TABLESAMPLE on PostgreSQL and SQL Server, and falls back to random ordering
+	// with a limit computed from the table row count on other databases.
+	Sample(percent float64) SelectQuery
+	// AsOf queries the state of the model rows at the given time, read from the current rows and the history
+	// recorded for the model, which must be registered with RegisterHistory. Call it after Model.
+	AsOf(at time.Time) SelectQuery
+	// Unscoped disables the default scope and default order declared by the model
+	// through DefaultScoper and DefaultOrderer.
+	Unscoped() SelectQuery
+	// Timeout cancels the query when it runs longer than the timeout, overriding the statement_timeout of the
+	// datasource. PostgreSQL enforces it with SET LOCAL statement_timeout, running the query in a transaction unless
+	// it runs in one already, MySQL with max_execution_time, which bounds selects only, and other databases with
+	// a context deadline. It applies to Exec, Scan, ScanAndCount, Count and Exists; Rows leaves it to the context.
+	Timeout(timeout time.Duration) SelectQuery
+	// WithTree walks the adjacency-list tree of the model linked through the parent column with a recursive
+	// common table expression and selects the rows found from it instead of the model table, with their distance
+	// from the root rows in TreeDepthColumn and the path from them in TreePathColumn, which models may declare
+	// as fields to read them. The model must be set before.
+	WithTree(parentColumn string, options TreeOptions) SelectQuery
 	// ForShare adds a for share lock to the query.
 	ForShare(tables ...string) SelectQuery
 	// ForShareNoWait adds a for share no wait lock to the query.
@@ -771,10 +927,29 @@ type SelectQuery interface {
 	ExceptAll(func(query SelectQuery)) SelectQuery
 }
 
+// MultiResultScanner is an interface that defines the methods for scanning queries returning multiple result sets,
+// such as procedure calls on MySQL or batches of statements on drivers allowing them.
+type MultiResultScanner interface {
+	// ScanMulti scans the result sets into dest in order, one result set per destination.
+	// Slice destinations receive every row of their result set, other destinations the first row;
+	// it fails with ErrMissingResultSet when fewer result sets are returned than destinations.
+	ScanMulti(ctx context.Context, dest ...any) error
+}
+
 // RawQuery is an interface that defines the methods for executing raw SQL queries.
 // It allows direct SQL execution with parameter binding for cases where the query builder is insufficient.
 type RawQuery interface {
 	QueryExecutor
+	MultiResultScanner
+}
+
+// ProcQuery is an interface that defines the methods for calling stored procedures.
+// Every method calls the procedure and assigns the output parameters; Scan also scans the first result set
+// returned by the procedure into dest and ScanMulti all of them in order.
+// Stored procedures are supported on PostgreSQL and MySQL.
+type ProcQuery interface {
+	QueryExecutor
+	MultiResultScanner
 }
 
 // InsertQuery is an interface that defines the methods for building and executing INSERT queries.
@@ -819,6 +994,8 @@ type UpdateQuery interface {
 	OmitZero() UpdateQuery
 	// Bulk adds a bulk clause to the query.
 	Bulk() UpdateQuery
+	// AllowFullTable allows executing the update without a where clause, updating all rows of the table.
+	AllowFullTable() UpdateQuery
 }
 
 // DeleteQuery is an interface that defines the methods for building and executing DELETE queries.
@@ -837,6 +1014,13 @@ type DeleteQuery interface {
 
 	// ForceDelete adds a force delete clause to the query.
 	ForceDelete() DeleteQuery
+	// AllowFullTable allows executing the delete without a where clause, deleting all rows of the table.
+	AllowFullTable() DeleteQuery
+	// Cascade also deletes the rows depending on the deleted rows through the has-one and has-many relations
+	// of the model, recursively and deepest first, all in one transaction. Dependent rows are soft deleted when
+	// their model supports it and the delete is not forced. Hooks override the handling of single relations.
+	// The filters of the query must not reference its CTEs as they are replayed to read the deleted rows.
+	Cascade(hooks ...CascadeHook) DeleteQuery
 }
 
 // MergeQuery is an interface that defines the methods for building and executing MERGE queries.
@@ -872,6 +1056,32 @@ type MergeQuery interface {
 	WhenNotMatchedBySource(builder ...func(ConditionBuilder)) MergeWhenBuilder
 }
 
+// UpsertQuery is an interface that defines the methods for building and executing portable upserts.
+// It inserts the rows of the model and updates the existing rows with the same conflict key, compiling to
+// INSERT ... ON CONFLICT on PostgreSQL and SQLite, INSERT ... ON DUPLICATE KEY UPDATE on MySQL
+// and MERGE on SQL Server and Oracle.
+type UpsertQuery interface {
+	QueryExecutor
+	DBAccessor
+
+	// Model sets the model or the slice of models to upsert.
+	// Maps (map[string]any or []map[string]any) are supported together with Table and ConflictColumns.
+	Model(model any) UpsertQuery
+	// Table sets the table of map models.
+	Table(name string) UpsertQuery
+	// ConflictColumns sets the columns identifying existing rows, the primary keys of the model by default.
+	// They are required for map models, which have no primary keys.
+	// MySQL detects conflicts on any primary key or unique index regardless of the columns.
+	ConflictColumns(columns ...string) UpsertQuery
+	// UpdateColumns sets the columns updated on existing rows. By default all columns are updated
+	// except the primary keys, the conflict columns and the columns tagged skipupdate.
+	UpdateColumns(columns ...string) UpsertQuery
+	// DoNothing leaves existing rows unchanged, only inserting the new ones.
+	DoNothing() UpsertQuery
+	// SQL returns the statement the upsert executes, e.g. to generate sync scripts.
+	SQL() (string, error)
+}
+
 // DB is an interface that defines the methods for database operations.
 // It provides factory methods for creating different types of queries and supports transactions.
 type DB interface {
@@ -885,18 +1095,38 @@ type DB interface {
 	NewDelete() DeleteQuery
 	// NewMerge creates a new merge query.
 	NewMerge() MergeQuery
+	// NewUpsert creates a new upsert query, portable across the supported databases.
+	NewUpsert() UpsertQuery
 	// NewRaw creates a new raw query.
 	NewRaw(query string, args ...any) RawQuery
+	// CallProc creates a stored procedure call. Arguments are IN parameters unless wrapped with Out or InOut.
+	CallProc(name string, args ...any) ProcQuery
+	// CallFunc creates a query selecting the result of a stored function.
+	CallFunc(name string, args ...any) RawQuery
 	// RunInTX runs a transaction.
 	RunInTX(ctx context.Context, fn func(ctx context.Context, tx DB) error) error
 	// RunInReadOnlyTX runs a read-only transaction.
 	RunInReadOnlyTX(ctx context.Context, fn func(ctx context.Context, tx DB) error) error
+	// RunInNestedTX runs fn within a savepoint of the transaction: an error, or a panic, rolls back the changes of fn
+	// only and the transaction continues. Outside a transaction it runs fn in a new transaction like RunInTX.
+	RunInNestedTX(ctx context.Context, fn func(ctx context.Context, tx DB) error) error
 	// WithNamedArg returns a new DB with the named arg.
 	WithNamedArg(name string, value any) DB
+	// WithDataScopes returns a new DB whose selects, updates and deletes, including those of its transactions,
+	// are also filtered by the data scopes.
+	WithDataScopes(scopes ...DataScope) DB
+	// WithTenancy returns a new DB isolating the data of the tenant of each request, including in its transactions,
+	// by the tenancy.
+	WithTenancy(tenancy *Tenancy) DB
+	// WithQueryHooks returns a new DB whose statements, including those of its transactions,
+	// are observed by the query hooks. It is not supported within a transaction.
+	WithQueryHooks(hooks ...QueryHook) DB
 	// ModelPKs returns the primary keys of a model.
 	ModelPKs(model any) (map[string]any, error)
 	// ModelPKFields returns the primary key fields of a model.
 	ModelPKFields(model any) []*PKField
 	// TableOf returns the table information for a model.
 	TableOf(model any) *schema.Table
+	// Schema returns the metadata of the table of a model with the comments declared through comment tags.
+	Schema(model any) *ModelSchema
 }
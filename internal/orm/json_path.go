@@ -0,0 +1,139 @@
+package orm
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
+)
+
+// JSONPathBuilder builds a path into a JSON value step by step, quoting keys and rendering the path syntax of the database,
+// e.g. eb.JSONPath(eb.Column("meta")).Field("interests").Index(0).Text().
+// Each step returns a new builder, so a common prefix can be shared by several paths.
+type JSONPathBuilder interface {
+	// Field steps into the value of a key of an object.
+	Field(name string) JSONPathBuilder
+	// Index steps into an element of an array, negative indexes count from the end (-1 is the last element).
+	Index(index int) JSONPathBuilder
+	// Exists checks if the path exists in the JSON value, also when it holds a JSON null.
+	Exists() schema.QueryAppender
+	// Value extracts the value at the path as JSON.
+	Value() schema.QueryAppender
+	// Text extracts the value at the path as text, strings without quotes.
+	Text() schema.QueryAppender
+}
+
+// jsonPathStep is a key or an array index of a JSON path.
+type jsonPathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// jsonPath implements JSONPathBuilder interface.
+type jsonPath struct {
+	eb    *QueryExprBuilder
+	json  any
+	steps []jsonPathStep
+}
+
+func (p *jsonPath) Field(name string) JSONPathBuilder {
+	return p.with(jsonPathStep{key: name})
+}
+
+func (p *jsonPath) Index(index int) JSONPathBuilder {
+	return p.with(jsonPathStep{index: index, isIndex: true})
+}
+
+func (p *jsonPath) with(step jsonPathStep) *jsonPath {
+	steps := make([]jsonPathStep, len(p.steps), len(p.steps)+1)
+	copy(steps, p.steps)
+
+	return &jsonPath{eb: p.eb, json: p.json, steps: append(steps, step)}
+}
+
+func (p *jsonPath) Exists() schema.QueryAppender {
+	b := p.eb
+
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("JSONB_PATH_EXISTS(?, ?::jsonpath)", b.ToJSON(p.json), p.path(dialect.PG))
+		},
+		SQLite: func() schema.QueryAppender {
+			// JSON_TYPE returns 'null' for JSON nulls and NULL for missing paths
+			return b.Expr("JSON_TYPE(?, ?) IS NOT NULL", p.json, p.path(dialect.SQLite))
+		},
+		Default: func() schema.QueryAppender {
+			return b.Expr("JSON_CONTAINS_PATH(?, 'one', ?)", p.json, p.path(dialect.MySQL))
+		},
+	})
+}
+
+func (p *jsonPath) Value() schema.QueryAppender {
+	b := p.eb
+
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("JSONB_PATH_QUERY_FIRST(?, ?::jsonpath)", b.ToJSON(p.json), p.path(dialect.PG))
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr("(? -> ?)", p.json, p.path(dialect.SQLite))
+		},
+		Default: func() schema.QueryAppender {
+			return b.Expr("JSON_EXTRACT(?, ?)", p.json, p.path(dialect.MySQL))
+		},
+	})
+}
+
+func (p *jsonPath) Text() schema.QueryAppender {
+	b := p.eb
+
+	return b.ExprByDialect(DialectExprs{
+		Postgres: func() schema.QueryAppender {
+			return b.Expr("(JSONB_PATH_QUERY_FIRST(?, ?::jsonpath) #>> '{}')", b.ToJSON(p.json), p.path(dialect.PG))
+		},
+		SQLite: func() schema.QueryAppender {
+			return b.Expr("(? ->> ?)", p.json, p.path(dialect.SQLite))
+		},
+		Default: func() schema.QueryAppender {
+			return b.Expr("JSON_UNQUOTE(JSON_EXTRACT(?, ?))", p.json, p.path(dialect.MySQL))
+		},
+	})
+}
+
+// path renders the steps in the path syntax of the dialect: SQL/JSON paths on Postgres and MySQL,
+// which address the last element as last, and SQLite paths, which address it as #-1.
+func (p *jsonPath) path(dialectName dialect.Name) string {
+	var sb strings.Builder
+
+	_ = sb.WriteByte('$')
+
+	for _, step := range p.steps {
+		if !step.isIndex {
+			_ = sb.WriteByte('.')
+			_, _ = sb.WriteString(strconv.Quote(step.key))
+
+			continue
+		}
+
+		_ = sb.WriteByte('[')
+
+		switch {
+		case step.index >= 0:
+			_, _ = sb.WriteString(strconv.Itoa(step.index))
+		case dialectName == dialect.SQLite:
+			_, _ = sb.WriteString("#")
+			_, _ = sb.WriteString(strconv.Itoa(step.index))
+		case step.index == -1:
+			_, _ = sb.WriteString("last")
+		default:
+			_, _ = sb.WriteString("last-")
+			_, _ = sb.WriteString(strconv.Itoa(-step.index - 1))
+		}
+
+		_ = sb.WriteByte(']')
+	}
+
+	return sb.String()
+}
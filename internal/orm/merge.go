@@ -247,11 +247,25 @@ func (q *BunMergeQuery) ApplyIf(condition bool, fns ...ApplyFunc[MergeQuery]) Me
 }
 
 func (q *BunMergeQuery) Exec(ctx context.Context, dest ...any) (sql.Result, error) {
-	return q.query.Exec(ctx, dest...)
+	ctx = withRejection(ctx)
+
+	if _, err := q.db.applyTenancy(ctx, q.query, nil, nil); err != nil {
+		return nil, err
+	}
+
+	res, err := q.query.Exec(ctx, dest...)
+
+	return res, contextError(ctx, err)
 }
 
 func (q *BunMergeQuery) Scan(ctx context.Context, dest ...any) error {
-	return q.query.Scan(ctx, dest...)
+	ctx = withRejection(ctx)
+
+	if _, err := q.db.applyTenancy(ctx, q.query, nil, nil); err != nil {
+		return err
+	}
+
+	return contextError(ctx, q.query.Scan(ctx, dest...))
 }
 
 func (q *BunMergeQuery) Unwrap() *bun.MergeQuery {
@@ -1,6 +1,9 @@
 package orm
 
-import "github.com/ilxqx/vef-framework-go/datetime"
+import (
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/sortx"
+)
 
 // IDModel contains only the primary key field.
 type IDModel struct {
@@ -51,6 +54,20 @@ type Model struct {
 	UpdatedByName string `json:"updatedByName" bun:",scanonly"`
 }
 
+// DefaultScoper is implemented by models whose select queries are always filtered, e.g. to active rows.
+// The scope is added to every SelectQuery on the model, including subqueries, unless Unscoped is called.
+type DefaultScoper interface {
+	// DefaultScope adds the default conditions of the model.
+	DefaultScope(cb ConditionBuilder)
+}
+
+// DefaultOrderer is implemented by models that declare a default ORDER BY.
+// The order is applied only when the query has no explicit ordering and Unscoped is not called.
+type DefaultOrderer interface {
+	// DefaultOrder returns the default sort orders of the model.
+	DefaultOrder() []sortx.OrderSpec
+}
+
 // RelationSpec specifies how to join a related model using automatic column resolution.
 // It provides a declarative way to define JOIN operations between models with minimal configuration.
 // The spec automatically resolves foreign keys and primary keys based on model metadata and naming conventions.
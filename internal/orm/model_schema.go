@@ -0,0 +1,82 @@
+package orm
+
+import (
+	"reflect"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// TagComment is the struct tag documenting a column, or the table when set on the embedded bun.BaseModel.
+// Migrations emit the comments into the database and DB.Schema exposes them.
+// Example: `bun:"table:sys_user" comment:"Users"` or `bun:"email,notnull" comment:"Login email"`.
+const TagComment = "comment"
+
+var baseModelType = reflect.TypeFor[bun.BaseModel]()
+
+// ModelSchema is the metadata of the table of a model, e.g. for admin UIs and code generation.
+type ModelSchema struct {
+	Table   string         `json:"table"`
+	Alias   string         `json:"alias,omitempty"`
+	Comment string         `json:"comment,omitempty"`
+	Columns []ColumnSchema `json:"columns"`
+}
+
+// ColumnSchema is the metadata of a column of a model.
+type ColumnSchema struct {
+	Name            string `json:"name"`
+	Field           string `json:"field"` // Go struct field name
+	Type            string `json:"type"`
+	Nullable        bool   `json:"nullable"`
+	Default         string `json:"default,omitempty"`
+	Comment         string `json:"comment,omitempty"`
+	IsPrimaryKey    bool   `json:"isPrimaryKey,omitempty"`
+	IsAutoIncrement bool   `json:"isAutoIncrement,omitempty"`
+}
+
+// ModelSchemaOf returns the metadata of a model table, its columns in field order.
+func ModelSchemaOf(table *schema.Table) *ModelSchema {
+	columns := make([]ColumnSchema, len(table.Fields))
+	for i, field := range table.Fields {
+		columns[i] = ColumnSchema{
+			Name:            field.Name,
+			Field:           field.GoName,
+			Type:            field.CreateTableSQLType,
+			Nullable:        !field.NotNull,
+			Default:         field.SQLDefault,
+			Comment:         ColumnCommentOf(field),
+			IsPrimaryKey:    field.IsPK,
+			IsAutoIncrement: field.AutoIncrement || field.Identity,
+		}
+	}
+
+	return &ModelSchema{
+		Table:   table.Name,
+		Alias:   table.Alias,
+		Comment: TableCommentOf(table),
+		Columns: columns,
+	}
+}
+
+// TableCommentOf returns the comment tag of the embedded bun.BaseModel of a model table.
+func TableCommentOf(table *schema.Table) string {
+	return baseModelTag(table, TagComment)
+}
+
+// baseModelTag returns the tag of the embedded bun.BaseModel of a model table.
+func baseModelTag(table *schema.Table, key string) string {
+	for i := range table.Type.NumField() {
+		if field := table.Type.Field(i); field.Type == baseModelType {
+			return field.Tag.Get(key)
+		}
+	}
+
+	return constants.Empty
+}
+
+// ColumnCommentOf returns the comment tag of a column.
+func ColumnCommentOf(field *schema.Field) string {
+	return field.StructField.Tag.Get(TagComment)
+}
@@ -7,6 +7,27 @@ import "go.uber.org/fx"
 var Module = fx.Module(
 	"vef:orm",
 	fx.Provide(
-		New,
+		fx.Annotate(
+			newDB,
+			fx.ParamTags(``, `optional:"true"`),
+		),
+	),
+	fx.Invoke(
+		fx.Annotate(
+			func(provider SecretProvider) {
+				if provider != nil {
+					SetSecretProvider(provider)
+				}
+			},
+			fx.ParamTags(`optional:"true"`),
+		),
+		fx.Annotate(
+			func(sink AuditSink) {
+				if sink != nil {
+					SetAuditSink(sink)
+				}
+			},
+			fx.ParamTags(`optional:"true"`),
+		),
 	),
 )
@@ -0,0 +1,193 @@
+package orm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/uptrace/bun"
+)
+
+var errNestedFailed = errors.New("nested transaction failed")
+
+type NestedTxItem struct {
+	bun.BaseModel `bun:"table:test_nested_tx_item,alias:tnti"`
+	Model
+
+	Name string `json:"name" bun:"name,notnull"`
+}
+
+// NestedTxTestSuite tests rolling back parts of a transaction with savepoints.
+type NestedTxTestSuite struct {
+	*OrmTestSuite
+}
+
+func (suite *NestedTxTestSuite) SetupSuite() {
+	bunDB := suite.getBunDB()
+
+	_, err := bunDB.NewDropTable().Model((*NestedTxItem)(nil)).IfExists().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should drop existing nested tx item table")
+
+	_, err = bunDB.NewCreateTable().Model((*NestedTxItem)(nil)).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should create nested tx item table")
+}
+
+func (suite *NestedTxTestSuite) SetupTest() {
+	_, err := suite.db.NewDelete().Model((*NestedTxItem)(nil)).AllowFullTable().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should clear nested tx items")
+}
+
+func (suite *NestedTxTestSuite) TearDownSuite() {
+	_, err := suite.getBunDB().NewDropTable().Model((*NestedTxItem)(nil)).IfExists().Exec(suite.ctx)
+	suite.NoError(err, "Should cleanup nested tx item table")
+}
+
+func (suite *NestedTxTestSuite) insert(ctx context.Context, db DB, name string) {
+	_, err := db.NewInsert().Model(&NestedTxItem{Name: name}).Exec(ctx)
+	suite.Require().NoError(err, "Should insert %s", name)
+}
+
+func (suite *NestedTxTestSuite) names() []string {
+	var names []string
+
+	suite.Require().NoError(suite.db.NewSelect().
+		Model((*NestedTxItem)(nil)).
+		Select("name").
+		OrderBy("name").
+		Scan(suite.ctx, &names), "Should select nested tx items")
+
+	return names
+}
+
+// TestPartialRollback tests that a failing savepoint rolls back its changes only.
+func (suite *NestedTxTestSuite) TestPartialRollback() {
+	err := suite.db.RunInTX(suite.ctx, func(ctx context.Context, tx DB) error {
+		suite.insert(ctx, tx, "a")
+
+		err := tx.RunInNestedTX(ctx, func(ctx context.Context, tx DB) error {
+			suite.insert(ctx, tx, "b")
+
+			return errNestedFailed
+		})
+		suite.ErrorIs(err, errNestedFailed, "Should return the error of the savepoint")
+
+		suite.insert(ctx, tx, "c")
+
+		return nil
+	})
+	suite.Require().NoError(err, "Should commit the outer transaction")
+
+	suite.Equal([]string{"a", "c"}, suite.names(), "Should keep the changes outside the failed savepoint")
+}
+
+// TestNestedSavepoints tests savepoints within savepoints.
+func (suite *NestedTxTestSuite) TestNestedSavepoints() {
+	err := suite.db.RunInTX(suite.ctx, func(ctx context.Context, tx DB) error {
+		return tx.RunInNestedTX(ctx, func(ctx context.Context, tx DB) error {
+			suite.insert(ctx, tx, "outer")
+
+			suite.NoError(tx.RunInNestedTX(ctx, func(ctx context.Context, tx DB) error {
+				suite.insert(ctx, tx, "released")
+
+				return nil
+			}))
+
+			suite.Error(tx.RunInNestedTX(ctx, func(ctx context.Context, tx DB) error {
+				suite.insert(ctx, tx, "inner")
+
+				return tx.RunInNestedTX(ctx, func(ctx context.Context, tx DB) error {
+					suite.insert(ctx, tx, "innermost")
+
+					return errNestedFailed
+				})
+			}))
+
+			return nil
+		})
+	})
+	suite.Require().NoError(err, "Should commit the outer transaction")
+
+	suite.Equal([]string{"outer", "released"}, suite.names(), "Should roll back the failed savepoint with its nested ones")
+}
+
+// TestPanicRollsBack tests that a panic rolls back the savepoint before it continues.
+func (suite *NestedTxTestSuite) TestPanicRollsBack() {
+	err := suite.db.RunInTX(suite.ctx, func(ctx context.Context, tx DB) error {
+		suite.insert(ctx, tx, "a")
+
+		suite.Panics(func() {
+			_ = tx.RunInNestedTX(ctx, func(ctx context.Context, tx DB) error {
+				suite.insert(ctx, tx, "b")
+
+				panic("boom")
+			})
+		})
+
+		return nil
+	})
+	suite.Require().NoError(err, "Should commit the outer transaction")
+
+	suite.Equal([]string{"a"}, suite.names())
+}
+
+// TestOutsideTransaction tests that a nested transaction outside a transaction runs a new transaction.
+func (suite *NestedTxTestSuite) TestOutsideTransaction() {
+	err := suite.db.RunInNestedTX(suite.ctx, func(ctx context.Context, tx DB) error {
+		suite.insert(ctx, tx, "a")
+
+		return errNestedFailed
+	})
+	suite.ErrorIs(err, errNestedFailed)
+	suite.Empty(suite.names(), "Should roll back the whole transaction")
+
+	suite.Require().NoError(suite.db.RunInNestedTX(suite.ctx, func(ctx context.Context, tx DB) error {
+		suite.insert(ctx, tx, "b")
+
+		return nil
+	}))
+	suite.Equal([]string{"b"}, suite.names(), "Should commit the transaction")
+}
+
+// TestAfterCommit tests that commit callbacks run once the outermost transaction commits only.
+func (suite *NestedTxTestSuite) TestAfterCommit() {
+	var ran []string
+
+	after := func(ctx context.Context, name string) {
+		AfterCommit(ctx, func() { ran = append(ran, name) })
+	}
+
+	err := suite.db.RunInTX(suite.ctx, func(ctx context.Context, tx DB) error {
+		suite.True(InTransaction(ctx), "Should report the transaction")
+		after(ctx, "outer")
+
+		suite.NoError(tx.RunInNestedTX(ctx, func(ctx context.Context, _ DB) error {
+			after(ctx, "released")
+
+			return nil
+		}))
+
+		suite.Error(tx.RunInNestedTX(ctx, func(ctx context.Context, _ DB) error {
+			after(ctx, "rolled back")
+
+			return errNestedFailed
+		}))
+
+		suite.Empty(ran, "Should wait for the commit")
+
+		return nil
+	})
+	suite.Require().NoError(err, "Should commit the transaction")
+	suite.Equal([]string{"outer", "released"}, ran, "Should run the callbacks of the committed savepoints")
+
+	ran = nil
+
+	suite.ErrorIs(suite.db.RunInTX(suite.ctx, func(ctx context.Context, _ DB) error {
+		after(ctx, "failed")
+
+		return errNestedFailed
+	}), errNestedFailed)
+	suite.Empty(ran, "Should drop the callbacks of a rolled back transaction")
+
+	suite.False(InTransaction(suite.ctx))
+	after(suite.ctx, "immediate")
+	suite.Equal([]string{"immediate"}, ran, "Should run immediately outside a transaction")
+}
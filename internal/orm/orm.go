@@ -17,3 +17,12 @@ func New(db bun.IDB) DB {
 
 	return inst.WithNamedArg(constants.ExprOperator, constants.OperatorSystem)
 }
+
+// newDB creates the DB of the application, isolating the data of tenants when a tenancy is configured.
+func newDB(db bun.IDB, tenancy *Tenancy) DB {
+	if tenancy == nil {
+		return New(db)
+	}
+
+	return New(db).WithTenancy(tenancy)
+}
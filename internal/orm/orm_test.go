@@ -73,6 +73,141 @@ func runAllOrmTests(t *testing.T, ctx context.Context, dsConfig *config.Datasour
 		},
 	}
 
+	// Create Upsert Suite
+	upsertSuite := &UpsertTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create Raw Query Suite
+	rawQuerySuite := &RawQueryTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create Proc Suite
+	procSuite := &ProcTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create Summary Suite
+	summarySuite := &SummaryTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create Denormalize Suite
+	denormalizeSuite := &DenormalizeTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create Tree Suite
+	treeSuite := &TreeTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create Array Suite
+	arraySuite := &ArrayTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create Data Scope Suite
+	dataScopeSuite := &DataScopeTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create Query Hook Suite
+	queryHookSuite := &QueryHookTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create Nested Tx Suite
+	nestedTxSuite := &NestedTxTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create Alias Suite
+	aliasSuite := &AliasTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create History Suite
+	historySuite := &HistoryTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create Tenancy Suite
+	tenancySuite := &TenancyTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create ID Suite
+	idSuite := &IDTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
+	// Create Audit Suite
+	auditSuite := &AuditTestSuite{
+		OrmTestSuite: &OrmTestSuite{
+			ctx:    ctx,
+			dbType: dsConfig.Type,
+			db:     ormDB,
+		},
+	}
+
 	t.Run("TestSelect", func(t *testing.T) {
 		suite.Run(t, selectSuite)
 	})
@@ -93,6 +228,66 @@ func runAllOrmTests(t *testing.T, ctx context.Context, dsConfig *config.Datasour
 		suite.Run(t, mergeSuite)
 	})
 
+	t.Run("TestUpsert", func(t *testing.T) {
+		suite.Run(t, upsertSuite)
+	})
+
+	t.Run("TestRawQuery", func(t *testing.T) {
+		suite.Run(t, rawQuerySuite)
+	})
+
+	t.Run("TestProc", func(t *testing.T) {
+		suite.Run(t, procSuite)
+	})
+
+	t.Run("TestSummary", func(t *testing.T) {
+		suite.Run(t, summarySuite)
+	})
+
+	t.Run("TestDenormalize", func(t *testing.T) {
+		suite.Run(t, denormalizeSuite)
+	})
+
+	t.Run("TestTree", func(t *testing.T) {
+		suite.Run(t, treeSuite)
+	})
+
+	t.Run("TestArray", func(t *testing.T) {
+		suite.Run(t, arraySuite)
+	})
+
+	t.Run("TestDataScope", func(t *testing.T) {
+		suite.Run(t, dataScopeSuite)
+	})
+
+	t.Run("TestQueryHook", func(t *testing.T) {
+		suite.Run(t, queryHookSuite)
+	})
+
+	t.Run("TestNestedTx", func(t *testing.T) {
+		suite.Run(t, nestedTxSuite)
+	})
+
+	t.Run("TestAlias", func(t *testing.T) {
+		suite.Run(t, aliasSuite)
+	})
+
+	t.Run("TestHistory", func(t *testing.T) {
+		suite.Run(t, historySuite)
+	})
+
+	t.Run("TestTenancy", func(t *testing.T) {
+		suite.Run(t, tenancySuite)
+	})
+
+	t.Run("TestID", func(t *testing.T) {
+		suite.Run(t, idSuite)
+	})
+
+	t.Run("TestAudit", func(t *testing.T) {
+		suite.Run(t, auditSuite)
+	})
+
 	t.Run("TestConditionBuilder", func(t *testing.T) {
 		runAllConditionBuilderTests(t, ctx, dsConfig.Type, ormDB)
 	})
@@ -154,6 +349,10 @@ func runAllConditionBuilderTests(t *testing.T, ctx context.Context, dbType const
 		ConditionBuilderTestSuite: baseSuite,
 	}
 
+	specSuite := &SpecTestSuite{
+		ConditionBuilderTestSuite: baseSuite,
+	}
+
 	// Run all test suites
 	t.Run("TestBasicComparison", func(t *testing.T) {
 		suite.Run(t, basicComparisonSuite)
@@ -194,6 +393,10 @@ func runAllConditionBuilderTests(t *testing.T, ctx context.Context, dbType const
 	t.Run("TestConditionComprehensive", func(t *testing.T) {
 		suite.Run(t, conditionComprehensiveSuite)
 	})
+
+	t.Run("TestSpec", func(t *testing.T) {
+		suite.Run(t, specSuite)
+	})
 }
 
 // runAllExprBuilderTests executes all ExprBuilder test suites on the given database.
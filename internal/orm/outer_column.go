@@ -0,0 +1,123 @@
+package orm
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+func (q *BunSelectQuery) OuterColumn(column string) schema.QueryAppender {
+	if q.outer == nil {
+		logger.Panicf("OuterColumn: column %q can only be referenced inside a subquery", column)
+	}
+
+	alias, name, qualified := strings.Cut(column, constants.Dot)
+	if !qualified {
+		alias, name = constants.Empty, column
+	}
+
+	var known []string
+
+	// An unqualified column belongs to the model table of the directly enclosing query,
+	// a qualified one to the nearest enclosing query having a table of the alias.
+	for outer := q.outer; outer != nil; outer = outerOf(outer) {
+		qualifier, table, ok := resolveSource(outer, alias)
+		if ok {
+			if table != nil && !table.HasField(name) {
+				logger.Panicf("OuterColumn: table %s aliased %q has no column %q", table.Name, qualifier, name)
+			}
+
+			return q.eb.Expr("?.?", bun.Name(qualifier), bun.Name(name))
+		}
+
+		if !qualified {
+			logger.Panicf("OuterColumn: the enclosing query of column %q has no model, qualify the column with an alias", column)
+		}
+
+		known = append(known, sourceNames(outer)...)
+	}
+
+	slices.Sort(known)
+	logger.Panicf("OuterColumn: no enclosing query has a table aliased %q in column %q, known aliases are %v", alias, column, slices.Compact(known))
+
+	return nil
+}
+
+// addSource records a table source of the query under the name qualifying its columns, alias overriding name.
+// Table is nil for sources whose columns are unknown, e.g. subqueries and plain table names.
+func (q *BunSelectQuery) addSource(name string, table *schema.Table, alias ...string) {
+	if len(alias) > 0 && alias[0] != constants.Empty {
+		name = alias[0]
+	}
+
+	if name == constants.Empty {
+		return
+	}
+
+	if q.sources == nil {
+		q.sources = make(map[string]*schema.Table)
+	}
+
+	q.sources[name] = table
+}
+
+// outerOf returns the query enclosing the query, or nil if the query is not a subquery.
+func outerOf(qb QueryBuilder) QueryBuilder {
+	if query, ok := qb.(*BunSelectQuery); ok {
+		return query.outer
+	}
+
+	return nil
+}
+
+// modelSource returns the alias and table of the model of the query, the alias being empty without a model.
+func modelSource(qb QueryBuilder) (string, *schema.Table) {
+	table := qb.GetTable()
+
+	if query, ok := qb.(*BunSelectQuery); ok && query.modelAlias != constants.Empty {
+		return query.modelAlias, table
+	}
+
+	if table == nil {
+		return constants.Empty, nil
+	}
+
+	return table.Alias, table
+}
+
+// resolveSource finds the table source of the query qualified by alias, the model table for an empty alias.
+func resolveSource(qb QueryBuilder, alias string) (string, *schema.Table, bool) {
+	modelAlias, modelTable := modelSource(qb)
+	if alias == constants.Empty || alias == modelAlias {
+		return modelAlias, modelTable, modelAlias != constants.Empty
+	}
+
+	if query, ok := qb.(*BunSelectQuery); ok {
+		if table, ok := query.sources[alias]; ok {
+			return alias, table, true
+		}
+	}
+
+	return constants.Empty, nil, false
+}
+
+// sourceNames returns the aliases qualifying the table sources of the query.
+func sourceNames(qb QueryBuilder) []string {
+	var names []string
+
+	if modelAlias, _ := modelSource(qb); modelAlias != constants.Empty {
+		names = append(names, modelAlias)
+	}
+
+	if query, ok := qb.(*BunSelectQuery); ok {
+		for name := range query.sources {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
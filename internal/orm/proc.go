@@ -0,0 +1,271 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// procVarPrefix prefixes the MySQL session variables receiving output parameters.
+const procVarPrefix = "@vef_proc_"
+
+// ProcParam is an output parameter of a stored procedure call, created with Out or InOut.
+type ProcParam struct {
+	dest  any
+	inout bool
+}
+
+// Out passes an OUT parameter; dest is a pointer receiving the value after the call.
+func Out(dest any) ProcParam {
+	return ProcParam{dest: dest}
+}
+
+// InOut passes an INOUT parameter; the value dest points to is passed in and replaced by the returned value.
+func InOut(dest any) ProcParam {
+	return ProcParam{dest: dest, inout: true}
+}
+
+// procQuery calls a stored procedure using the call syntax of the dialect:
+// PostgreSQL returns output parameters as a row, MySQL assigns them to session variables read after the call.
+type procQuery struct {
+	db   *BunDB
+	name string
+	args []any
+}
+
+func newProcQuery(db *BunDB, name string, args ...any) *procQuery {
+	return &procQuery{
+		db:   db,
+		name: name,
+		args: args,
+	}
+}
+
+func (q *procQuery) Exec(ctx context.Context, dest ...any) (sql.Result, error) {
+	ctx = withRejection(ctx)
+
+	if len(dest) > 0 || q.db.db.Dialect().Name() == dialect.PG && q.hasOutputs() {
+		// Output parameters and destinations are read from the returned rows, which carry no affected row count
+		if err := q.ScanMulti(ctx, dest...); err != nil {
+			return nil, err
+		}
+
+		return driver.RowsAffected(0), nil
+	}
+
+	var res sql.Result
+
+	err := q.call(ctx, func(conn bun.IConn, query string, args []any) (err error) {
+		res, err = conn.ExecContext(ctx, query, args...)
+
+		return err
+	})
+
+	return res, contextError(ctx, err)
+}
+
+func (q *procQuery) Scan(ctx context.Context, dest ...any) error {
+	return q.scan(ctx, func(rows *sql.Rows, outputs []any) error {
+		if len(dest) > 1 {
+			// Multiple destinations receive the columns of the first row
+			dest = []any{rowColumns(dest)}
+		}
+
+		return scanResultSets(ctx, q.db.getBunDB(), rows, append(outputs, dest...)...)
+	})
+}
+
+func (q *procQuery) ScanMulti(ctx context.Context, dest ...any) error {
+	return q.scan(ctx, func(rows *sql.Rows, outputs []any) error {
+		return scanResultSets(ctx, q.db.getBunDB(), rows, append(outputs, dest...)...)
+	})
+}
+
+// scan runs the call and passes the returned rows to fn along with the leading destinations they start with:
+// the output parameter row on PostgreSQL, nothing otherwise.
+func (q *procQuery) scan(ctx context.Context, fn func(rows *sql.Rows, outputs []any) error) error {
+	ctx = withRejection(ctx)
+
+	err := q.call(ctx, func(conn bun.IConn, query string, args []any) error {
+		rows, err := conn.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		var outputs []any
+		if q.db.db.Dialect().Name() == dialect.PG && q.hasOutputs() {
+			outputs = []any{rowColumns(q.outputs())}
+		}
+
+		return fn(rows, outputs)
+	})
+
+	return scanContextError(ctx, err)
+}
+
+// call builds the call statement and runs fn with it, assigning the output parameters of MySQL afterwards.
+func (q *procQuery) call(ctx context.Context, fn func(conn bun.IConn, query string, args []any) error) error {
+	if err := q.validate(); err != nil {
+		return err
+	}
+
+	db, _, err := q.db.conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch name := db.Dialect().Name(); name {
+	case dialect.PG:
+		query, args := q.build(func(int, ProcParam) string { return constants.QuestionMark })
+
+		return fn(db, query, args)
+	case dialect.MySQL:
+		return q.callMySQL(ctx, db, fn)
+	default:
+		return fmt.Errorf("%w: stored procedures on %s", ErrDialectUnsupportedOperation, name)
+	}
+}
+
+func (q *procQuery) callMySQL(ctx context.Context, db bun.IDB, fn func(conn bun.IConn, query string, args []any) error) error {
+	if !q.hasOutputs() {
+		query, args := q.build(nil)
+
+		return fn(db, query, args)
+	}
+
+	// Session variables are only visible on the connection that assigned them
+	var conn bun.IConn = db
+	if db, ok := db.(*bun.DB); ok {
+		c, err := db.Conn(ctx)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = c.Close()
+		}()
+
+		conn = c
+	}
+
+	var (
+		assignments []string
+		values      []any
+		outputs     []string
+	)
+
+	query, args := q.build(func(i int, param ProcParam) string {
+		variable := procVarPrefix + strconv.Itoa(i)
+		if param.inout {
+			assignments = append(assignments, variable+" = ?")
+			values = append(values, reflect.ValueOf(param.dest).Elem().Interface())
+		}
+
+		outputs = append(outputs, variable)
+
+		return variable
+	})
+
+	if len(assignments) > 0 {
+		if _, err := conn.ExecContext(ctx, "SET "+strings.Join(assignments, ", "), values...); err != nil {
+			return err
+		}
+	}
+
+	if err := fn(conn, query, args); err != nil {
+		return err
+	}
+
+	return conn.QueryRowContext(ctx, "SELECT "+strings.Join(outputs, ", ")).Scan(q.outputs()...)
+}
+
+// build renders the CALL statement; output parameters are rendered by output, or passed as their input value
+// (NULL for OUT parameters) when output is nil or returns a placeholder.
+func (q *procQuery) build(output func(i int, param ProcParam) string) (string, []any) {
+	var (
+		sb   strings.Builder
+		args = make([]any, 0, len(q.args))
+	)
+
+	sb.WriteString("CALL ")
+	sb.WriteString(q.db.quoteIdent(q.name))
+	sb.WriteByte(constants.ByteLeftParenthesis)
+
+	for i, arg := range q.args {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+
+		param, ok := arg.(ProcParam)
+		if !ok {
+			sb.WriteString(constants.QuestionMark)
+
+			args = append(args, arg)
+
+			continue
+		}
+
+		placeholder := constants.QuestionMark
+		if output != nil {
+			placeholder = output(i, param)
+		}
+
+		sb.WriteString(placeholder)
+
+		if placeholder == constants.QuestionMark {
+			if param.inout {
+				args = append(args, reflect.ValueOf(param.dest).Elem().Interface())
+			} else {
+				args = append(args, nil)
+			}
+		}
+	}
+
+	sb.WriteByte(constants.ByteRightParenthesis)
+
+	return sb.String(), args
+}
+
+func (q *procQuery) validate() error {
+	for _, arg := range q.args {
+		if param, ok := arg.(ProcParam); ok {
+			if v := reflect.ValueOf(param.dest); v.Kind() != reflect.Pointer || v.IsNil() {
+				return fmt.Errorf("%w: %T", ErrInvalidProcParam, param.dest)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (q *procQuery) hasOutputs() bool {
+	for _, arg := range q.args {
+		if _, ok := arg.(ProcParam); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// outputs returns the destinations of the output parameters in order.
+func (q *procQuery) outputs() []any {
+	var dests []any
+
+	for _, arg := range q.args {
+		if param, ok := arg.(ProcParam); ok {
+			dests = append(dests, param.dest)
+		}
+	}
+
+	return dests
+}
@@ -0,0 +1,153 @@
+package orm
+
+import (
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// ProcTestSuite tests stored procedure and function calls.
+// Stored procedures are supported on PostgreSQL and MySQL, stored functions on every database.
+type ProcTestSuite struct {
+	*OrmTestSuite
+}
+
+// routines returns the statements creating and dropping the test routines of the database.
+func (suite *ProcTestSuite) routines() (create, drop []string) {
+	switch suite.dbType {
+	case constants.Postgres:
+		return []string{
+			`CREATE OR REPLACE PROCEDURE test_proc_users(min_age integer, INOUT total integer, OUT matched integer)
+				LANGUAGE plpgsql AS $$
+				BEGIN
+					SELECT COUNT(*) INTO matched FROM test_user WHERE age >= min_age;
+					total := total + matched;
+				END $$`,
+			`CREATE OR REPLACE FUNCTION test_func_add(a integer, b integer) RETURNS integer LANGUAGE sql AS $$ SELECT a + b $$`,
+		}, []string{
+			"DROP PROCEDURE IF EXISTS test_proc_users",
+			"DROP FUNCTION IF EXISTS test_func_add",
+		}
+	case constants.MySQL:
+		return []string{
+			"DROP PROCEDURE IF EXISTS test_proc_users",
+			`CREATE PROCEDURE test_proc_users(IN min_age INT, INOUT total INT, OUT matched INT)
+				BEGIN
+					SELECT name FROM test_user WHERE age >= min_age ORDER BY name;
+					SELECT COUNT(*) FROM test_user;
+					SELECT COUNT(*) INTO matched FROM test_user WHERE age >= min_age;
+					SET total = total + matched;
+				END`,
+			"DROP FUNCTION IF EXISTS test_func_add",
+			"CREATE FUNCTION test_func_add(a INT, b INT) RETURNS INT DETERMINISTIC RETURN a + b",
+		}, []string{
+			"DROP PROCEDURE IF EXISTS test_proc_users",
+			"DROP FUNCTION IF EXISTS test_func_add",
+		}
+	default:
+		return nil, nil
+	}
+}
+
+func (suite *ProcTestSuite) SetupSuite() {
+	suite.OrmTestSuite.SetupSuite()
+
+	create, _ := suite.routines()
+	for _, stmt := range create {
+		_, err := suite.db.NewRaw(stmt).Exec(suite.ctx)
+		suite.Require().NoError(err, "Failed to create test routine")
+	}
+}
+
+func (suite *ProcTestSuite) TearDownSuite() {
+	_, drop := suite.routines()
+	for _, stmt := range drop {
+		_, err := suite.db.NewRaw(stmt).Exec(suite.ctx)
+		suite.NoError(err, "Failed to drop test routine")
+	}
+}
+
+// countAdults returns the number of users matched by test_proc_users with a minimum age of 30.
+func (suite *ProcTestSuite) countAdults() int {
+	count, err := suite.db.NewSelect().Model((*User)(nil)).Where(func(cb ConditionBuilder) {
+		cb.GreaterThanOrEqual("age", 30)
+	}).Count(suite.ctx)
+	suite.Require().NoError(err)
+
+	return int(count)
+}
+
+// TestCallProcOutputParams tests that OUT and INOUT parameters receive the values assigned by the procedure.
+func (suite *ProcTestSuite) TestCallProcOutputParams() {
+	if suite.dbType == constants.SQLite {
+		suite.T().Skipf("Stored procedures are not supported by %s", suite.dbType)
+	}
+
+	var (
+		total   = 100
+		matched int
+	)
+
+	_, err := suite.db.CallProc("test_proc_users", 30, InOut(&total), Out(&matched)).Exec(suite.ctx)
+	suite.Require().NoError(err, "Procedure call should succeed on %s", suite.dbType)
+
+	expected := suite.countAdults()
+	suite.Equal(expected, matched, "OUT parameter should receive the matched count")
+	suite.Equal(100+expected, total, "INOUT parameter should receive the updated total")
+}
+
+// TestCallProcResultSets tests scanning the result sets of a procedure in order.
+func (suite *ProcTestSuite) TestCallProcResultSets() {
+	if suite.dbType != constants.MySQL {
+		suite.T().Skipf("Procedures returning result sets are only tested on MySQL, skipping for %s", suite.dbType)
+	}
+
+	var (
+		total   int
+		matched int
+		names   []string
+		count   int
+	)
+
+	err := suite.db.CallProc("test_proc_users", 30, InOut(&total), Out(&matched)).ScanMulti(suite.ctx, &names, &count)
+	suite.Require().NoError(err, "Procedure call should succeed")
+
+	totalUsers, err := suite.db.NewSelect().Model((*User)(nil)).Count(suite.ctx)
+	suite.Require().NoError(err)
+
+	suite.Len(names, suite.countAdults(), "First result set should be scanned into the first destination")
+	suite.Equal(int(totalUsers), count, "Second result set should be scanned into the second destination")
+	suite.Equal(matched, total, "Output parameters should be assigned after the result sets")
+}
+
+// TestCallProcUnsupported tests that procedure calls fail on databases without stored procedures.
+func (suite *ProcTestSuite) TestCallProcUnsupported() {
+	if suite.dbType != constants.SQLite {
+		suite.T().Skipf("%s supports stored procedures", suite.dbType)
+	}
+
+	var matched int
+
+	_, err := suite.db.CallProc("test_proc_users", 30, Out(&matched)).Exec(suite.ctx)
+	suite.ErrorIs(err, ErrDialectUnsupportedOperation, "Procedure calls should be rejected on SQLite")
+}
+
+// TestCallProcInvalidParam tests that output parameters must point to their destination.
+func (suite *ProcTestSuite) TestCallProcInvalidParam() {
+	_, err := suite.db.CallProc("test_proc_users", 30, Out(0)).Exec(suite.ctx)
+	suite.ErrorIs(err, ErrInvalidProcParam, "Non-pointer output parameters should be rejected")
+}
+
+// TestCallFunc tests selecting the result of a stored function.
+func (suite *ProcTestSuite) TestCallFunc() {
+	var result int
+
+	if suite.dbType == constants.SQLite {
+		// SQLite has no stored functions, call a built-in one instead
+		suite.Require().NoError(suite.db.CallFunc("abs", -42).Scan(suite.ctx, &result))
+		suite.Equal(42, result, "Function result should be scanned")
+
+		return
+	}
+
+	suite.Require().NoError(suite.db.CallFunc("test_func_add", 40, 2).Scan(suite.ctx, &result))
+	suite.Equal(42, result, "Function result should be scanned on %s", suite.dbType)
+}
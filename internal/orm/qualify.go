@@ -0,0 +1,65 @@
+package orm
+
+import (
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// applyQualifyState wraps the query in a derived table under the alias of its model filtered by the Qualify
+// conditions, as window functions are evaluated after WHERE and cannot be filtered in it. The order, limit and
+// offset move to the wrapping query so they apply to the filtered rows.
+func (q *BunSelectQuery) applyQualifyState() {
+	if len(q.qualifies) == 0 || q.qualifyStateApplied {
+		return
+	}
+
+	q.qualifyStateApplied = true
+
+	inner := q.query.Limit(0).Offset(0)
+	outer := q.wrapQuery(inner, inner)
+
+	for _, qualify := range q.qualifies {
+		outer.Where("?", qualify)
+	}
+
+	q.query = outer
+}
+
+// wrapQuery selects all columns of the derived table of the source, built from the inner query, under the alias of
+// the model of the query, with the order, limit and offset of the query.
+func (q *BunSelectQuery) wrapQuery(inner *bun.SelectQuery, source schema.QueryAppender) *bun.SelectQuery {
+	outer := inner.NewSelect()
+
+	if table := q.GetTable(); table != nil {
+		outer.Model(inner.GetModel().Value())
+
+		if q.modelAlias != constants.Empty {
+			outer.ModelTableExpr("(?) AS ?", source, bun.Name(q.modelAlias)).ColumnExpr("?.*", bun.Name(q.modelAlias))
+		} else {
+			outer.ModelTableExpr("(?) AS ?TableAlias", source).ColumnExpr("?TableAlias.*")
+		}
+
+		// The derived table holds the rows the soft delete filter of the model kept already
+		if table.SoftDeleteField != nil {
+			outer.WhereAllWithDeleted()
+		}
+	} else {
+		outer.TableExpr("(?) AS ?", source, bun.Name(qualifyAlias)).
+			ColumnExpr("?.*", bun.Name(qualifyAlias))
+	}
+
+	for _, order := range q.orders {
+		outer.OrderExpr(order.Query, order.Args...)
+	}
+
+	outer.Limit(q.limit).Offset(q.offset)
+
+	// Derived tables render the errors of their queries into the SQL instead of returning them
+	if q.err != nil {
+		outer.Err(q.err)
+	}
+
+	return outer
+}
@@ -56,6 +56,10 @@ func (b *BaseQueryBuilder) CreateSubQuery(subQuery *bun.SelectQuery) SelectQuery
 		eb:         eb,
 		isSubQuery: true,
 	}
+
+	if outerEB, ok := b.eb.(*QueryExprBuilder); ok {
+		query.outer = outerEB.qb
+	}
 	eb.qb = query
 
 	return query
@@ -71,6 +75,10 @@ func (b *BaseQueryBuilder) BuildSubQuery(builder func(query SelectQuery)) *bun.S
 	// This ensures that select operations in the subquery are properly applied
 	if sq, ok := wrappedQuery.(*BunSelectQuery); ok {
 		sq.applySelectState()
+		sq.applyFullJoinState()
+		sq.applyQualifyState()
+
+		return sq.query
 	}
 
 	return subQuery
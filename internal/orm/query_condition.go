@@ -34,3 +34,36 @@ func newQueryConditionBuilder(builder bun.QueryBuilder, qb QueryBuilder) *QueryC
 		},
 	}
 }
+
+// whereTracker records whether conditions are added through the wrapped query builder,
+// including conditions added inside non-empty groups.
+type whereTracker struct {
+	bun.QueryBuilder
+
+	hasWhere *bool
+}
+
+func (t *whereTracker) Where(query string, args ...any) bun.QueryBuilder {
+	*t.hasWhere = true
+	t.QueryBuilder.Where(query, args...)
+
+	return t
+}
+
+func (t *whereTracker) WhereOr(query string, args ...any) bun.QueryBuilder {
+	*t.hasWhere = true
+	t.QueryBuilder.WhereOr(query, args...)
+
+	return t
+}
+
+func (t *whereTracker) WhereGroup(sep string, fn func(bun.QueryBuilder) bun.QueryBuilder) bun.QueryBuilder {
+	// Bun unwraps the builder returned by fn, so the wrapped builder is returned instead of the tracker
+	t.QueryBuilder.WhereGroup(sep, func(builder bun.QueryBuilder) bun.QueryBuilder {
+		fn(&whereTracker{QueryBuilder: builder, hasWhere: t.hasWhere})
+
+		return builder
+	})
+
+	return t
+}
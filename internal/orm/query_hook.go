@@ -0,0 +1,135 @@
+package orm
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlredact"
+	"github.com/ilxqx/vef-framework-go/log"
+)
+
+// queryEventStashKey is the stash key for storing the QueryEvent of a statement.
+type queryEventStashKey struct{}
+
+// QueryEvent describes a statement executed by a DB.
+type QueryEvent struct {
+	// Operation is the kind of statement, e.g. SELECT or INSERT, empty for raw queries.
+	Operation string
+	// Table is the model table of the statement, empty for raw queries.
+	Table string
+	// Query is the formatted SQL of the statement.
+	Query string
+	// Args are the arguments of raw queries, formatted into Query.
+	Args []any
+	// StartTime is the time the statement started.
+	StartTime time.Time
+	// Duration is the execution time of the statement, set for AfterQuery.
+	Duration time.Duration
+	// RowsAffected is the number of rows returned or affected, -1 if unknown. Set for AfterQuery.
+	RowsAffected int64
+	// Err is the error of the statement, set for AfterQuery.
+	Err error
+	// Stash holds values shared between BeforeQuery and AfterQuery of a statement.
+	Stash map[any]any
+}
+
+// QueryHook observes every statement executed by a DB it is registered on with WithQueryHooks,
+// e.g. for slow query logging, tracing spans or metrics.
+type QueryHook interface {
+	// BeforeQuery is called before the statement executes. The returned context is used for the statement
+	// and passed to AfterQuery, e.g. carrying a tracing span.
+	BeforeQuery(ctx context.Context, event *QueryEvent) context.Context
+	// AfterQuery is called once the statement executed.
+	AfterQuery(ctx context.Context, event *QueryEvent)
+}
+
+// bunQueryHook adapts a QueryHook to bun.
+type bunQueryHook struct {
+	hook QueryHook
+}
+
+func (h bunQueryHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	if event.Stash == nil {
+		event.Stash = make(map[any]any)
+	}
+
+	qe, _ := event.Stash[queryEventStashKey{}].(*QueryEvent)
+	if qe == nil {
+		qe = &QueryEvent{
+			Query:        sqlredact.Redact(event.Query),
+			Args:         event.QueryArgs,
+			StartTime:    event.StartTime,
+			RowsAffected: -1,
+			Stash:        make(map[any]any),
+		}
+
+		if query, ok := event.IQuery.(schema.Query); ok {
+			qe.Operation = query.Operation()
+			qe.Table = strings.Trim(query.GetTableName(), `"`+"`")
+		}
+
+		// Hooks registered together observe the same event
+		event.Stash[queryEventStashKey{}] = qe
+	}
+
+	return h.hook.BeforeQuery(ctx, qe)
+}
+
+func (h bunQueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	qe, _ := event.Stash[queryEventStashKey{}].(*QueryEvent)
+	if qe == nil {
+		return
+	}
+
+	qe.Duration = time.Since(qe.StartTime)
+	qe.Err = event.Err
+
+	if event.Result != nil {
+		if rows, err := event.Result.RowsAffected(); err == nil {
+			qe.RowsAffected = rows
+		}
+	}
+
+	h.hook.AfterQuery(ctx, qe)
+}
+
+// NewSlowQueryHook logs the statements running longer than the threshold, along with their error if any.
+func NewSlowQueryHook(threshold time.Duration, logger log.Logger) QueryHook {
+	return &slowQueryHook{
+		threshold: threshold,
+		logger:    logger,
+	}
+}
+
+type slowQueryHook struct {
+	threshold time.Duration
+	logger    log.Logger
+}
+
+func (*slowQueryHook) BeforeQuery(ctx context.Context, _ *QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *slowQueryHook) AfterQuery(_ context.Context, event *QueryEvent) {
+	if event.Duration < h.threshold {
+		return
+	}
+
+	table := event.Table
+	if table == constants.Empty {
+		table = "-"
+	}
+
+	if event.Err != nil {
+		h.logger.Warnf("Slow query took %s, table=%s, error=%v: %s", event.Duration, table, event.Err, event.Query)
+
+		return
+	}
+
+	h.logger.Warnf("Slow query took %s, table=%s, rows=%d: %s", event.Duration, table, event.RowsAffected, event.Query)
+}
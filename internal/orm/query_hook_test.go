@@ -0,0 +1,137 @@
+package orm
+
+import (
+	"context"
+	"sync"
+
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/internal/database/querypolicy"
+)
+
+// recordingQueryHook records the events of the statements it observes.
+type recordingQueryHook struct {
+	mu     sync.Mutex
+	before []QueryEvent
+	after  []QueryEvent
+}
+
+type hookMarkerKey struct{}
+
+func (h *recordingQueryHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.before = append(h.before, *event)
+	event.Stash[hookMarkerKey{}] = len(h.before)
+
+	return context.WithValue(ctx, hookMarkerKey{}, true)
+}
+
+func (h *recordingQueryHook) AfterQuery(ctx context.Context, event *QueryEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if marked, _ := ctx.Value(hookMarkerKey{}).(bool); marked && event.Stash[hookMarkerKey{}] == len(h.before) {
+		h.after = append(h.after, *event)
+	}
+}
+
+// QueryHookTestSuite tests observing the statements of a DB with query hooks.
+type QueryHookTestSuite struct {
+	*OrmTestSuite
+}
+
+// TestSelect tests the events of a successful select.
+func (suite *QueryHookTestSuite) TestSelect() {
+	hook := new(recordingQueryHook)
+	db := suite.db.WithQueryHooks(hook)
+
+	var users []User
+
+	suite.Require().NoError(db.NewSelect().Model(&users).Scan(suite.ctx), "Should select users")
+
+	suite.Require().Len(hook.before, 1, "Should observe the statement before it executes")
+	suite.Require().Len(hook.after, 1, "Should pass the context and stash of BeforeQuery to AfterQuery")
+
+	event := hook.after[0]
+	suite.Equal("SELECT", event.Operation)
+	suite.Equal("test_user", event.Table)
+	suite.Contains(event.Query, "test_user", "Should expose the formatted sql")
+	suite.False(event.StartTime.IsZero(), "Should set the start time")
+	suite.Positive(event.Duration, "Should measure the duration")
+	suite.NoError(event.Err)
+}
+
+// TestError tests that failing statements report their error.
+func (suite *QueryHookTestSuite) TestError() {
+	hook := new(recordingQueryHook)
+	db := suite.db.WithQueryHooks(hook)
+
+	_, err := db.NewRaw("SELECT * FROM test_missing_table").Exec(suite.ctx)
+	suite.Require().Error(err, "Should fail on a missing table")
+
+	suite.Require().Len(hook.after, 1)
+	suite.Error(hook.after[0].Err, "Should report the error of the statement")
+	suite.Empty(hook.after[0].Table, "Should not know the table of raw queries")
+}
+
+// TestTransaction tests that statements of transactions are observed, while the original DB is not.
+func (suite *QueryHookTestSuite) TestTransaction() {
+	hook := new(recordingQueryHook)
+	db := suite.db.WithQueryHooks(hook)
+
+	err := db.RunInReadOnlyTX(suite.ctx, func(ctx context.Context, tx DB) error {
+		_, err := tx.NewSelect().Model((*User)(nil)).Count(ctx)
+
+		return err
+	})
+	suite.Require().NoError(err, "Should count users in a transaction")
+	suite.NotEmpty(hook.after, "Should observe statements of transactions")
+
+	observed := len(hook.after)
+
+	_, err = suite.db.NewSelect().Model((*User)(nil)).Count(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Len(hook.after, observed, "Should not observe statements of the original DB")
+
+	suite.Panics(func() {
+		_ = db.RunInTX(suite.ctx, func(_ context.Context, tx DB) error {
+			tx.WithQueryHooks(hook)
+
+			return nil
+		})
+	}, "Should not support adding hooks within a transaction")
+}
+
+// TestPolicyViolation tests that statements rejected by their query policy report the violation.
+func (suite *QueryHookTestSuite) TestPolicyViolation() {
+	hook, err := querypolicy.NewHook(&config.QueryPolicyConfig{
+		Enabled: true,
+		Models: map[string]config.QueryPolicy{
+			"test_user": {
+				MaxRows:   1,
+				Forbidden: []string{querypolicy.OperationDeleteWithoutWhere},
+			},
+		},
+	}, logger)
+	suite.Require().NoError(err)
+
+	db := New(suite.db.(*BunDB).db.(*bun.DB).WithQueryHook(hook))
+
+	var users []User
+
+	err = db.NewSelect().Model(&users).Limit(10).Scan(suite.ctx)
+	suite.ErrorIs(err, ErrPolicyViolation, "Select limited above the maximum should report the violation")
+
+	count, err := suite.db.NewSelect().Model((*User)(nil)).Count(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = db.NewRaw("DELETE FROM test_user").Exec(suite.ctx)
+	suite.ErrorIs(err, ErrPolicyViolation, "Delete without WHERE should report the violation")
+
+	remaining, err := suite.db.NewSelect().Model((*User)(nil)).Count(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(count, remaining, "Rejected statements should not be executed")
+}
@@ -10,19 +10,53 @@ import (
 type bunRawQuery struct {
 	db    *BunDB
 	query *bun.RawQuery
+	sql   string
+	args  []any
 }
 
 func newRawQuery(db *BunDB, query string, args ...any) *bunRawQuery {
 	return &bunRawQuery{
 		db:    db,
 		query: db.db.NewRaw(query, args...),
+		sql:   query,
+		args:  args,
 	}
 }
 
 func (b *bunRawQuery) Exec(ctx context.Context, dest ...any) (sql.Result, error) {
-	return b.query.Exec(ctx, dest...)
+	ctx = withRejection(ctx)
+
+	if _, err := b.db.applyTenancy(ctx, b.query, nil, nil); err != nil {
+		return nil, err
+	}
+
+	res, err := b.query.Exec(ctx, dest...)
+
+	return res, contextError(ctx, err)
 }
 
 func (b *bunRawQuery) Scan(ctx context.Context, dest ...any) error {
-	return b.query.Scan(ctx, dest...)
+	ctx = withRejection(ctx)
+
+	if _, err := b.db.applyTenancy(ctx, b.query, nil, nil); err != nil {
+		return err
+	}
+
+	return scanContextError(ctx, b.query.Scan(ctx, dest...))
+}
+
+func (b *bunRawQuery) ScanMulti(ctx context.Context, dest ...any) error {
+	ctx = withRejection(ctx)
+
+	conn, _, err := b.db.conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows, err := conn.QueryContext(ctx, b.sql, b.args...)
+	if err != nil {
+		return contextError(ctx, err)
+	}
+
+	return scanContextError(ctx, scanResultSets(ctx, b.db.getBunDB(), rows, dest...))
 }
@@ -0,0 +1,81 @@
+package orm
+
+import (
+	"database/sql"
+)
+
+// RawQueryTestSuite tests raw query execution.
+type RawQueryTestSuite struct {
+	*OrmTestSuite
+}
+
+// TestScanMulti tests scanning a result set into different kinds of destinations.
+func (suite *RawQueryTestSuite) TestScanMulti() {
+	var expected []User
+
+	err := suite.db.NewSelect().Model(&expected).OrderBy("name").Scan(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(expected)
+
+	suite.Run("ModelSlice", func() {
+		var users []User
+
+		err := suite.db.NewRaw("SELECT * FROM test_user ORDER BY name").ScanMulti(suite.ctx, &users)
+		suite.Require().NoError(err)
+		suite.Len(users, len(expected), "Every row should be scanned")
+		suite.Equal(expected[0].Name, users[0].Name)
+	})
+
+	suite.Run("PointerSlice", func() {
+		var users []*User
+
+		err := suite.db.NewRaw("SELECT * FROM test_user ORDER BY name").ScanMulti(suite.ctx, &users)
+		suite.Require().NoError(err)
+		suite.Len(users, len(expected), "Every row should be scanned")
+		suite.Equal(expected[0].Email, users[0].Email)
+	})
+
+	suite.Run("Model", func() {
+		var user User
+
+		err := suite.db.NewRaw("SELECT * FROM test_user ORDER BY name").ScanMulti(suite.ctx, &user)
+		suite.Require().NoError(err)
+		suite.Equal(expected[0].ID, user.ID, "The first row should be scanned")
+	})
+
+	suite.Run("ScalarSlice", func() {
+		var names []string
+
+		err := suite.db.NewRaw("SELECT name FROM test_user ORDER BY name").ScanMulti(suite.ctx, &names)
+		suite.Require().NoError(err)
+		suite.Len(names, len(expected))
+		suite.Equal(expected[len(expected)-1].Name, names[len(names)-1])
+	})
+
+	suite.Run("MapSlice", func() {
+		var rows []map[string]any
+
+		err := suite.db.NewRaw("SELECT name, email FROM test_user ORDER BY name").ScanMulti(suite.ctx, &rows)
+		suite.Require().NoError(err)
+		suite.Len(rows, len(expected))
+		suite.Equal(expected[0].Email, rows[0]["email"], "Columns should be keyed by name")
+	})
+}
+
+// TestScanMultiErrors tests the errors reported for unmatched destinations.
+func (suite *RawQueryTestSuite) TestScanMultiErrors() {
+	var (
+		names []string
+		count int
+		user  User
+	)
+
+	err := suite.db.NewRaw("SELECT name FROM test_user").ScanMulti(suite.ctx, &names, &count)
+	suite.ErrorIs(err, ErrMissingResultSet, "Destinations without result set should be reported")
+
+	err = suite.db.NewRaw("SELECT * FROM test_user WHERE 1 = 0").ScanMulti(suite.ctx, &user)
+	suite.ErrorIs(err, sql.ErrNoRows, "Empty result set should be reported for single row destinations")
+
+	err = suite.db.NewRaw("SELECT name FROM test_user").ScanMulti(suite.ctx, names)
+	suite.ErrorIs(err, ErrInvalidScanDest, "Non-pointer destinations should be rejected")
+}
@@ -0,0 +1,40 @@
+package orm
+
+import (
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// queryGen returns the generator of the SQL of the query, holding the named arguments of its DB.
+func queryGen(query QueryBuilder) schema.QueryGen {
+	if accessor, ok := query.(DBAccessor); ok {
+		if db, ok := accessor.DB().(*BunDB); ok {
+			return db.getBunDB().QueryGen()
+		}
+	}
+
+	return schema.NewQueryGen(query.Dialect())
+}
+
+// RenderSQL renders the SQL the query executes. Unlike String, it reports the errors of building the query rather
+// than embedding them in the SQL. Rendering finalizes select queries, so they should not be changed afterwards.
+// The data scopes and the tenancy resolved from the context of the execution are not applied.
+func RenderSQL(query QueryBuilder) (string, error) {
+	if selectQuery, ok := query.(*BunSelectQuery); ok {
+		selectQuery.applySelectState()
+	}
+
+	b, err := query.Query().AppendQuery(queryGen(query), nil)
+	if err != nil {
+		return constants.Empty, err
+	}
+
+	return string(b), nil
+}
+
+// FormatSQL formats the SQL with the arguments the way the query formats its own, e.g. quoting strings
+// for its dialect.
+func FormatSQL(query QueryBuilder, sql string, args ...any) string {
+	return queryGen(query).FormatQuery(sql, args...)
+}
@@ -0,0 +1,154 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/uptrace/bun"
+)
+
+// scanResultSets scans the result sets of rows into dest in order, one result set per destination, and closes rows.
+// Result sets beyond the destinations are discarded.
+func scanResultSets(ctx context.Context, db *bun.DB, rows *sql.Rows, dest ...any) error {
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for i, d := range dest {
+		if i > 0 && !rows.NextResultSet() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			return fmt.Errorf("%w: got %d, want %d", ErrMissingResultSet, i, len(dest))
+		}
+
+		if err := scanResultSet(ctx, db, rows, d); err != nil {
+			return err
+		}
+	}
+
+	return rows.Close()
+}
+
+// rowScanner scans a row by itself.
+type rowScanner interface {
+	ScanRow(ctx context.Context, rows *sql.Rows) error
+}
+
+// rowColumns scans the columns of a row into their destinations.
+type rowColumns []any
+
+func (r rowColumns) ScanRow(_ context.Context, rows *sql.Rows) error {
+	return rows.Scan(r...)
+}
+
+// scanResultSet scans the current result set into dest.
+// Slices receive every row, other destinations the first row and fail with sql.ErrNoRows when there is none.
+func scanResultSet(ctx context.Context, db *bun.DB, rows *sql.Rows, dest any) error {
+	if scanner, ok := dest.(rowScanner); ok {
+		return scanFirstRow(rows, func() error {
+			return scanner.ScanRow(ctx, rows)
+		})
+	}
+
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return fmt.Errorf("%w: %T", ErrInvalidScanDest, dest)
+	}
+
+	slice := value.Elem()
+	if slice.Kind() != reflect.Slice || slice.Type().Elem().Kind() == reflect.Uint8 {
+		return scanFirstRow(rows, func() error {
+			return scanRow(ctx, db, rows, dest)
+		})
+	}
+
+	elemType := slice.Type().Elem()
+	isPointer := elemType.Kind() == reflect.Pointer
+
+	if isPointer {
+		elemType = elemType.Elem()
+	}
+
+	slice.SetLen(0)
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		if err := scanRow(ctx, db, rows, elem.Interface()); err != nil {
+			return err
+		}
+
+		if isPointer {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+
+	return rows.Err()
+}
+
+// scanFirstRow scans the first row of the current result set with scan and discards the others.
+func scanFirstRow(rows *sql.Rows, scan func() error) error {
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		return sql.ErrNoRows
+	}
+
+	if err := scan(); err != nil {
+		return err
+	}
+
+	// Drain the remaining rows so that the next result set can be reached
+	for rows.Next() {
+	}
+
+	return rows.Err()
+}
+
+// scanRow scans the current row into dest, which is a pointer to a model, a map or a scalar.
+func scanRow(ctx context.Context, db *bun.DB, rows *sql.Rows, dest any) error {
+	if m, ok := dest.(*map[string]any); ok {
+		return scanMapRow(rows, m)
+	}
+
+	return db.ScanRow(ctx, rows, dest)
+}
+
+func scanMapRow(rows *sql.Rows, dest *map[string]any) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	if err := rows.Scan(pointers...); err != nil {
+		return err
+	}
+
+	if *dest == nil {
+		*dest = make(map[string]any, len(columns))
+	}
+
+	for i, column := range columns {
+		if b, ok := values[i].([]byte); ok {
+			values[i] = string(b)
+		}
+
+		(*dest)[column] = values[i]
+	}
+
+	return nil
+}
@@ -0,0 +1,316 @@
+package orm
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/decimal"
+)
+
+// Row is a row of a query without a pre-declared struct, keeping its columns in select order.
+// Scanning into Row, []Row, map[string]any or []map[string]any converts the values by their column types:
+// integers to int64 (unsigned ones beyond its range to uint64), floats to float64, decimals to decimal.Decimal,
+// booleans to bool, dates and times to time.Time and JSON to the decoded value, other values, times of day included,
+// to their driver values with text as string and binary data as []byte.
+type Row struct {
+	Columns []string
+	Values  []any
+}
+
+// Get returns the value of the column, false when the row has no such column.
+func (r Row) Get(column string) (any, bool) {
+	for i, name := range r.Columns {
+		if name == column {
+			return r.Values[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// Map returns the values of the row by column.
+func (r Row) Map() map[string]any {
+	values := make(map[string]any, len(r.Columns))
+	for i, column := range r.Columns {
+		values[column] = r.Values[i]
+	}
+
+	return values
+}
+
+// MarshalJSON encodes the row as an object with the columns in select order.
+func (r Row) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteByte('{')
+
+	for i, column := range r.Columns {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(r.Values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
+
+// Rows is the result of SelectQuery.Rows. Close ends the timeout the query runs under, e.g. commits the transaction
+// bounding it on PostgreSQL, so the rows must be closed also when read to the end.
+type Rows struct {
+	*sql.Rows
+
+	once   sync.Once
+	finish func(err error) error
+	err    error
+}
+
+// Close closes the rows and ends the timeout of the query, returning the first error of both.
+func (r *Rows) Close() error {
+	r.once.Do(func() {
+		err := r.Rows.Close()
+		if err == nil {
+			err = r.Rows.Err()
+		}
+
+		r.err = r.finish(err)
+	})
+
+	return r.err
+}
+
+// dynamicDest reports whether the scan destinations are a single Row, map or slice of them, scanned with
+// scanRows.
+func dynamicDest(dest []any) bool {
+	if len(dest) != 1 {
+		return false
+	}
+
+	switch dest[0].(type) {
+	case *Row, *[]Row, *map[string]any, *[]map[string]any:
+		return true
+	default:
+		return false
+	}
+}
+
+// scanRows scans the rows into the dynamic destination, converting the values by their column types.
+func scanRows(rows *sql.Rows, dest any) error {
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	columns := make([]string, len(columnTypes))
+	typeNames := make([]string, len(columnTypes))
+
+	for i, columnType := range columnTypes {
+		columns[i] = columnType.Name()
+		typeNames[i] = columnTypeName(columnType.DatabaseTypeName())
+	}
+
+	var scanned []Row
+
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+
+		for i, value := range values {
+			if values[i], err = convertColumnValue(typeNames[i], value); err != nil {
+				return fmt.Errorf("%w: column %s of type %s: %w", ErrColumnValueConversion, columns[i], typeNames[i], err)
+			}
+		}
+
+		scanned = append(scanned, Row{Columns: columns, Values: values})
+
+		// A single row is scanned into Row and map destinations
+		if _, ok := dest.(*Row); ok {
+			break
+		}
+
+		if _, ok := dest.(*map[string]any); ok {
+			break
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	switch dest := dest.(type) {
+	case *[]Row:
+		*dest = append((*dest)[:0], scanned...)
+	case *[]map[string]any:
+		maps := make([]map[string]any, len(scanned))
+		for i, row := range scanned {
+			maps[i] = row.Map()
+		}
+
+		*dest = maps
+	case *Row:
+		if len(scanned) == 0 {
+			return sql.ErrNoRows
+		}
+
+		*dest = scanned[0]
+	case *map[string]any:
+		if len(scanned) == 0 {
+			return sql.ErrNoRows
+		}
+
+		*dest = scanned[0].Map()
+	}
+
+	return nil
+}
+
+// columnTypeName normalizes the database type name of a column, dropping its length, precision and modifiers.
+func columnTypeName(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if i := strings.IndexAny(name, "( "); i >= 0 {
+		name = name[:i]
+	}
+
+	return name
+}
+
+// timeLayouts parses the dates and times drivers return as text.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	time.DateTime + ".999999999",
+	time.DateTime,
+	time.DateOnly,
+}
+
+// binaryTypes are the column types whose values are kept as []byte.
+var binaryTypes = map[string]bool{
+	"BLOB":       true,
+	"TINYBLOB":   true,
+	"MEDIUMBLOB": true,
+	"LONGBLOB":   true,
+	"BINARY":     true,
+	"VARBINARY":  true,
+	"BYTEA":      true,
+}
+
+// moneySymbols strips the currency symbol and the group separators of PostgreSQL money values.
+var moneySymbols = strings.NewReplacer("$", constants.Empty, ",", constants.Empty)
+
+// convertColumnValue converts the driver value of a column of the type.
+func convertColumnValue(typeName string, value any) (any, error) {
+	if b, ok := value.([]byte); ok && !binaryTypes[typeName] {
+		value = string(b)
+	}
+
+	text, isText := value.(string)
+
+	switch typeName {
+	case "INT", "INTEGER", "BIGINT", "SMALLINT", "TINYINT", "MEDIUMINT", "INT2", "INT4", "INT8",
+		"SERIAL", "BIGSERIAL", "UNSIGNED":
+		if isText {
+			i, err := strconv.ParseInt(text, 10, 64)
+			if errors.Is(err, strconv.ErrRange) && !strings.HasPrefix(text, "-") {
+				return strconv.ParseUint(text, 10, 64)
+			}
+
+			return i, err
+		}
+	case "FLOAT", "DOUBLE", "REAL", "FLOAT4", "FLOAT8":
+		if isText {
+			return strconv.ParseFloat(text, 64)
+		}
+	case "DECIMAL", "NUMERIC", "NUMBER", "MONEY":
+		switch v := value.(type) {
+		case string:
+			if typeName == "MONEY" {
+				v = moneySymbols.Replace(v)
+			}
+
+			return decimal.NewFromString(v)
+		case int64:
+			return decimal.NewFromInt(v), nil
+		case float64:
+			return decimal.NewFromFloat(v), nil
+		}
+	case "BOOL", "BOOLEAN":
+		switch v := value.(type) {
+		case string:
+			return strconv.ParseBool(v)
+		case int64:
+			return v != 0, nil
+		}
+	case "DATE", "DATETIME", "TIMESTAMP", "TIMESTAMPTZ":
+		if isText {
+			return parseColumnTime(text)
+		}
+	case "JSON", "JSONB":
+		if isText {
+			var decoded any
+			if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+				return nil, err
+			}
+
+			return decoded, nil
+		}
+	}
+
+	return value, nil
+}
+
+func parseColumnTime(text string) (time.Time, error) {
+	var err error
+
+	for _, layout := range timeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
+// scanDynamic runs the query and scans its rows into the dynamic destination.
+func (q *BunSelectQuery) scanDynamic(ctx context.Context, dest any) error {
+	rows, err := q.query.Rows(ctx)
+	if err != nil {
+		return err
+	}
+
+	return scanRows(rows, dest)
+}
@@ -0,0 +1,105 @@
+package orm
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/sqlredact"
+)
+
+// SecretProvider resolves the secrets referenced by name in expressions, e.g. the keys of encryption expressions,
+// so the keys stay out of the application code.
+type SecretProvider interface {
+	// Secret returns the value of the named secret, wrapping ErrSecretNotFound when there is none.
+	Secret(name string) (string, error)
+}
+
+// SecretProviderFunc adapts a function to the SecretProvider interface.
+type SecretProviderFunc func(name string) (string, error)
+
+func (f SecretProviderFunc) Secret(name string) (string, error) {
+	return f(name)
+}
+
+// EnvSecretProvider resolves secrets from the environment variables of the same name. It is the default provider.
+var EnvSecretProvider SecretProvider = SecretProviderFunc(func(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return constants.Empty, fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+
+	return value, nil
+})
+
+// secretProviderHolder holds the provider in an atomic value, which requires a consistent concrete type.
+type secretProviderHolder struct {
+	provider SecretProvider
+}
+
+var secretProvider atomic.Value
+
+// SetSecretProvider sets the provider resolving secret references, nil restores EnvSecretProvider.
+func SetSecretProvider(provider SecretProvider) {
+	secretProvider.Store(secretProviderHolder{provider: provider})
+}
+
+func currentSecretProvider() SecretProvider {
+	if holder, ok := secretProvider.Load().(secretProviderHolder); ok && holder.provider != nil {
+		return holder.provider
+	}
+
+	return EnvSecretProvider
+}
+
+// SecretRef references a secret by name. It renders as the string literal of the secret, resolved from the secret
+// provider when the statement is built, and the value is masked in logged statements.
+type SecretRef string
+
+// SecretKey references the named secret, e.g. the key of eb.AesEncrypt.
+func SecretKey(name string) SecretRef {
+	return SecretRef(name)
+}
+
+func (r SecretRef) AppendQuery(gen schema.QueryGen, b []byte) ([]byte, error) {
+	value, err := r.resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	return gen.Dialect().AppendString(b, value), nil
+}
+
+// resolve resolves the secret from the secret provider and registers its value to be masked in logged statements.
+func (r SecretRef) resolve() (string, error) {
+	value, err := currentSecretProvider().Secret(string(r))
+	if err != nil {
+		return constants.Empty, fmt.Errorf("failed to resolve secret %s: %w", string(r), err)
+	}
+
+	sqlredact.Register(value)
+
+	return value, nil
+}
+
+// secretArg resolves the key of an expression referencing a secret when the expression is built, failing the query
+// when the secret cannot be resolved, as errors of appended expressions would only surface as invalid SQL.
+func secretArg(query bun.Query, key any) any {
+	ref, ok := key.(SecretRef)
+	if !ok {
+		return key
+	}
+
+	value, err := ref.resolve()
+	if err != nil {
+		setQueryErr(query, err)
+
+		return ref
+	}
+
+	return value
+}
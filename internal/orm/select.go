@@ -4,6 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/schema"
@@ -11,6 +15,7 @@ import (
 	"github.com/ilxqx/vef-framework-go/constants"
 	"github.com/ilxqx/vef-framework-go/page"
 	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/sortx"
 )
 
 // NewSelectQuery creates a new SelectQuery instance with the provided database instance.
@@ -51,6 +56,41 @@ type BunSelectQuery struct {
 	explicitSelects       []func()
 	exprSelects           []func()
 	selectStateApplied    bool
+
+	// State tracking for emulated sampling
+	limit              int
+	samplePercent      float64
+	hasSampleFallback  bool
+	sampleStateApplied bool
+
+	// Statement timeout of the query, zero for none
+	timeout time.Duration
+
+	// State tracking for emulated QUALIFY, the order and paging moving to the wrapping query
+	qualifies           []schema.QueryAppender
+	orders              []schema.QueryWithArgs
+	offset              int
+	qualifyStateApplied bool
+
+	// State tracking for the FULL JOIN emulated on databases lacking it
+	fullJoin             *fullJoin
+	fullJoinStateApplied bool
+
+	// Error of building the query, returned when executing it
+	err error
+
+	// State tracking for model default scope and order
+	isUnscoped bool
+	hasOrder   bool
+
+	// State tracking for the data scopes of the DB, which also restrict the relations loaded with the query
+	dataScopesApplied bool
+	relations         []string
+
+	// State tracking for resolving outer columns of subqueries
+	outer      QueryBuilder
+	modelAlias string
+	sources    map[string]*schema.Table
 }
 
 func (q *BunSelectQuery) DB() DB {
@@ -80,6 +120,42 @@ func (q *BunSelectQuery) WithRecursive(name string, builder func(query SelectQue
 	return q
 }
 
+func (q *BunSelectQuery) WithTree(parentColumn string, options TreeOptions) SelectQuery {
+	table := q.GetTable()
+	if table == nil {
+		q.query.Err(ErrTreeMissingModel)
+
+		return q
+	}
+
+	if options.Name == constants.Empty {
+		options.Name = defaultTreeName
+	}
+
+	if options.KeyColumn == constants.Empty {
+		if len(table.PKs) != 1 {
+			q.query.Err(ErrTreeMissingKey)
+
+			return q
+		}
+
+		options.KeyColumn = table.PKs[0].Name
+	}
+
+	if options.PathColumn == constants.Empty {
+		options.PathColumn = options.KeyColumn
+	}
+
+	if options.PathSeparator == constants.Empty {
+		options.PathSeparator = defaultTreePathSeparator
+	}
+
+	q.query.WithRecursive(options.Name, q.buildTree(table, parentColumn, options))
+	q.query.ModelTableExpr("? AS ?TableAlias", bun.Name(options.Name))
+
+	return q
+}
+
 func (q *BunSelectQuery) SelectAll() SelectQuery {
 	q.clearSelectState()
 	q.hasSelectAll = true
@@ -206,6 +282,7 @@ func (q *BunSelectQuery) Model(model any) SelectQuery {
 
 func (q *BunSelectQuery) ModelTable(name string, alias ...string) SelectQuery {
 	if len(alias) > 0 && alias[0] != constants.Empty {
+		q.modelAlias = alias[0]
 		q.query.ModelTableExpr("? AS ?", bun.Name(name), bun.Name(alias[0]))
 	} else {
 		q.query.ModelTableExpr("? AS ?TableAlias", bun.Name(name))
@@ -215,6 +292,8 @@ func (q *BunSelectQuery) ModelTable(name string, alias ...string) SelectQuery {
 }
 
 func (q *BunSelectQuery) Table(name string, alias ...string) SelectQuery {
+	q.addSource(name, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.TableExpr("? AS ?", bun.Name(name), bun.Name(alias[0]))
 	} else {
@@ -232,12 +311,15 @@ func (q *BunSelectQuery) TableFrom(model any, alias ...string) SelectQuery {
 		aliasToUse = alias[0]
 	}
 
+	q.addSource(aliasToUse, table)
 	q.query.TableExpr("? AS ?", bun.Name(table.Name), bun.Name(aliasToUse))
 
 	return q
 }
 
 func (q *BunSelectQuery) TableExpr(builder func(ExprBuilder) any, alias ...string) SelectQuery {
+	q.addSource(constants.Empty, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.TableExpr("? AS ?", builder(q.eb), bun.Name(alias[0]))
 	} else {
@@ -248,6 +330,8 @@ func (q *BunSelectQuery) TableExpr(builder func(ExprBuilder) any, alias ...strin
 }
 
 func (q *BunSelectQuery) TableSubQuery(builder func(query SelectQuery), alias ...string) SelectQuery {
+	q.addSource(constants.Empty, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.TableExpr("(?) AS ?", q.BuildSubQuery(builder), bun.Name(alias[0]))
 	} else {
@@ -265,6 +349,7 @@ func (q *BunSelectQuery) Join(model any, builder func(ConditionBuilder), alias .
 		aliasToUse = alias[0]
 	}
 
+	q.addSource(aliasToUse, table)
 	q.query.Join(
 		"? ? AS ?",
 		bun.Safe(JoinInner.String()),
@@ -277,6 +362,8 @@ func (q *BunSelectQuery) Join(model any, builder func(ConditionBuilder), alias .
 }
 
 func (q *BunSelectQuery) JoinTable(name string, builder func(ConditionBuilder), alias ...string) SelectQuery {
+	q.addSource(name, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.Join("? ? AS ?", bun.Safe(JoinInner.String()), bun.Name(name), bun.Name(alias[0]))
 	} else {
@@ -289,6 +376,8 @@ func (q *BunSelectQuery) JoinTable(name string, builder func(ConditionBuilder),
 }
 
 func (q *BunSelectQuery) JoinSubQuery(sqBuilder func(query SelectQuery), cBuilder func(ConditionBuilder), alias ...string) SelectQuery {
+	q.addSource(constants.Empty, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.Join("? (?) AS ?", bun.Safe(JoinInner.String()), q.BuildSubQuery(sqBuilder), bun.Name(alias[0]))
 	} else {
@@ -301,6 +390,8 @@ func (q *BunSelectQuery) JoinSubQuery(sqBuilder func(query SelectQuery), cBuilde
 }
 
 func (q *BunSelectQuery) JoinExpr(eBuilder func(ExprBuilder) any, cBuilder func(ConditionBuilder), alias ...string) SelectQuery {
+	q.addSource(constants.Empty, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.Join("? (?) AS ?", bun.Safe(JoinInner.String()), eBuilder(q.eb), bun.Name(alias[0]))
 	} else {
@@ -320,6 +411,7 @@ func (q *BunSelectQuery) LeftJoin(model any, builder func(ConditionBuilder), ali
 		aliasToUse = alias[0]
 	}
 
+	q.addSource(aliasToUse, table)
 	q.query.Join(
 		"? ? AS ?",
 		bun.Safe(JoinLeft.String()),
@@ -332,6 +424,8 @@ func (q *BunSelectQuery) LeftJoin(model any, builder func(ConditionBuilder), ali
 }
 
 func (q *BunSelectQuery) LeftJoinTable(name string, builder func(ConditionBuilder), alias ...string) SelectQuery {
+	q.addSource(name, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.Join("? ? AS ?", bun.Safe(JoinLeft.String()), bun.Name(name), bun.Name(alias[0]))
 	} else {
@@ -344,6 +438,8 @@ func (q *BunSelectQuery) LeftJoinTable(name string, builder func(ConditionBuilde
 }
 
 func (q *BunSelectQuery) LeftJoinSubQuery(sqBuilder func(query SelectQuery), cBuilder func(ConditionBuilder), alias ...string) SelectQuery {
+	q.addSource(constants.Empty, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.Join("? (?) AS ?", bun.Safe(JoinLeft.String()), q.BuildSubQuery(sqBuilder), bun.Name(alias[0]))
 	} else {
@@ -356,6 +452,8 @@ func (q *BunSelectQuery) LeftJoinSubQuery(sqBuilder func(query SelectQuery), cBu
 }
 
 func (q *BunSelectQuery) LeftJoinExpr(eBuilder func(ExprBuilder) any, cBuilder func(ConditionBuilder), alias ...string) SelectQuery {
+	q.addSource(constants.Empty, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.Join("? (?) AS ?", bun.Safe(JoinLeft.String()), eBuilder(q.eb), bun.Name(alias[0]))
 	} else {
@@ -375,6 +473,7 @@ func (q *BunSelectQuery) RightJoin(model any, builder func(ConditionBuilder), al
 		aliasToUse = alias[0]
 	}
 
+	q.addSource(aliasToUse, table)
 	q.query.Join(
 		"? ? AS ?",
 		bun.Safe(JoinRight.String()),
@@ -387,6 +486,8 @@ func (q *BunSelectQuery) RightJoin(model any, builder func(ConditionBuilder), al
 }
 
 func (q *BunSelectQuery) RightJoinTable(name string, builder func(ConditionBuilder), alias ...string) SelectQuery {
+	q.addSource(name, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.Join("? ? AS ?", bun.Safe(JoinRight.String()), bun.Name(name), bun.Name(alias[0]))
 	} else {
@@ -399,6 +500,8 @@ func (q *BunSelectQuery) RightJoinTable(name string, builder func(ConditionBuild
 }
 
 func (q *BunSelectQuery) RightJoinSubQuery(sqBuilder func(query SelectQuery), cBuilder func(ConditionBuilder), alias ...string) SelectQuery {
+	q.addSource(constants.Empty, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.Join("? (?) AS ?", bun.Safe(JoinRight.String()), q.BuildSubQuery(sqBuilder), bun.Name(alias[0]))
 	} else {
@@ -411,6 +514,8 @@ func (q *BunSelectQuery) RightJoinSubQuery(sqBuilder func(query SelectQuery), cB
 }
 
 func (q *BunSelectQuery) RightJoinExpr(eBuilder func(ExprBuilder) any, cBuilder func(ConditionBuilder), alias ...string) SelectQuery {
+	q.addSource(constants.Empty, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.Join("? (?) AS ?", bun.Safe(JoinRight.String()), eBuilder(q.eb), bun.Name(alias[0]))
 	} else {
@@ -430,49 +535,61 @@ func (q *BunSelectQuery) FullJoin(model any, builder func(ConditionBuilder), ali
 		aliasToUse = alias[0]
 	}
 
+	q.addSource(aliasToUse, table)
+
+	join := q.newFullJoin(builder)
 	q.query.Join(
 		"? ? AS ?",
-		bun.Safe(JoinFull.String()),
+		join,
 		bun.Name(table.Name),
 		bun.Name(aliasToUse),
 	)
-	q.query.JoinOn("?", q.BuildCondition(builder))
+	q.query.JoinOn("?", join.condition)
 
 	return q
 }
 
 func (q *BunSelectQuery) FullJoinTable(name string, builder func(ConditionBuilder), alias ...string) SelectQuery {
+	q.addSource(name, nil, alias...)
+
+	join := q.newFullJoin(builder)
 	if len(alias) > 0 && alias[0] != constants.Empty {
-		q.query.Join("? ? AS ?", bun.Safe(JoinFull.String()), bun.Name(name), bun.Name(alias[0]))
+		q.query.Join("? ? AS ?", join, bun.Name(name), bun.Name(alias[0]))
 	} else {
-		q.query.Join("? ?", bun.Safe(JoinFull.String()), bun.Name(name))
+		q.query.Join("? ?", join, bun.Name(name))
 	}
 
-	q.query.JoinOn("?", q.BuildCondition(builder))
+	q.query.JoinOn("?", join.condition)
 
 	return q
 }
 
 func (q *BunSelectQuery) FullJoinSubQuery(sqBuilder func(query SelectQuery), cBuilder func(ConditionBuilder), alias ...string) SelectQuery {
+	q.addSource(constants.Empty, nil, alias...)
+
+	join := q.newFullJoin(cBuilder)
 	if len(alias) > 0 && alias[0] != constants.Empty {
-		q.query.Join("? (?) AS ?", bun.Safe(JoinFull.String()), q.BuildSubQuery(sqBuilder), bun.Name(alias[0]))
+		q.query.Join("? (?) AS ?", join, q.BuildSubQuery(sqBuilder), bun.Name(alias[0]))
 	} else {
-		q.query.Join("? (?)", bun.Safe(JoinFull.String()), q.BuildSubQuery(sqBuilder))
+		q.query.Join("? (?)", join, q.BuildSubQuery(sqBuilder))
 	}
 
-	q.query.JoinOn("?", q.BuildCondition(cBuilder))
+	q.query.JoinOn("?", join.condition)
 
 	return q
 }
 
 func (q *BunSelectQuery) FullJoinExpr(eBuilder func(ExprBuilder) any, cBuilder func(ConditionBuilder), alias ...string) SelectQuery {
+	q.addSource(constants.Empty, nil, alias...)
+
+	join := q.newFullJoin(cBuilder)
 	if len(alias) > 0 && alias[0] != constants.Empty {
-		q.query.Join("? (?) AS ?", bun.Safe(JoinFull.String()), eBuilder(q.eb), bun.Name(alias[0]))
+		q.query.Join("? (?) AS ?", join, eBuilder(q.eb), bun.Name(alias[0]))
 	} else {
-		q.query.Join("? (?)", bun.Safe(JoinFull.String()), eBuilder(q.eb))
+		q.query.Join("? (?)", join, eBuilder(q.eb))
 	}
 
-	q.query.JoinOn("?", q.BuildCondition(cBuilder))
+	q.query.JoinOn("?", join.condition)
 
 	return q
 }
@@ -485,6 +602,7 @@ func (q *BunSelectQuery) CrossJoin(model any, alias ...string) SelectQuery {
 		aliasToUse = alias[0]
 	}
 
+	q.addSource(aliasToUse, table)
 	q.query.Join(
 		"? ? AS ?",
 		bun.Safe(JoinCross.String()),
@@ -496,6 +614,8 @@ func (q *BunSelectQuery) CrossJoin(model any, alias ...string) SelectQuery {
 }
 
 func (q *BunSelectQuery) CrossJoinTable(name string, alias ...string) SelectQuery {
+	q.addSource(name, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.Join("? ? AS ?", bun.Safe(JoinCross.String()), bun.Name(name), bun.Name(alias[0]))
 	} else {
@@ -506,6 +626,8 @@ func (q *BunSelectQuery) CrossJoinTable(name string, alias ...string) SelectQuer
 }
 
 func (q *BunSelectQuery) CrossJoinSubQuery(sqBuilder func(query SelectQuery), alias ...string) SelectQuery {
+	q.addSource(constants.Empty, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.Join("? (?) AS ?", bun.Safe(JoinCross.String()), q.BuildSubQuery(sqBuilder), bun.Name(alias[0]))
 	} else {
@@ -516,6 +638,8 @@ func (q *BunSelectQuery) CrossJoinSubQuery(sqBuilder func(query SelectQuery), al
 }
 
 func (q *BunSelectQuery) CrossJoinExpr(eBuilder func(ExprBuilder) any, alias ...string) SelectQuery {
+	q.addSource(constants.Empty, nil, alias...)
+
 	if len(alias) > 0 && alias[0] != constants.Empty {
 		q.query.Join("? (?) AS ?", bun.Safe(JoinCross.String()), eBuilder(q.eb), bun.Name(alias[0]))
 	} else {
@@ -534,6 +658,8 @@ func (q *BunSelectQuery) JoinRelations(specs ...*RelationSpec) SelectQuery {
 }
 
 func (q *BunSelectQuery) Relation(name string, apply ...func(query SelectQuery)) SelectQuery {
+	q.relations = append(q.relations, name)
+
 	if len(apply) == 0 {
 		q.query.Relation(name)
 	} else {
@@ -552,11 +678,15 @@ func (q *BunSelectQuery) Relation(name string, apply ...func(query SelectQuery))
 
 func (q *BunSelectQuery) Where(builder func(ConditionBuilder)) SelectQuery {
 	cb := newQueryConditionBuilder(q.query.QueryBuilder(), q)
-	builder(cb)
+	q.db.whereScoped(cb, q.GetTable(), builder)
 
 	return q
 }
 
+func (q *BunSelectQuery) WhereSpec(spec Spec) SelectQuery {
+	return q.Where(And(spec))
+}
+
 func (q *BunSelectQuery) WherePK(columns ...string) SelectQuery {
 	q.query.WherePK(columns...)
 
@@ -596,36 +726,105 @@ func (q *BunSelectQuery) Having(builder func(ConditionBuilder)) SelectQuery {
 	return q
 }
 
+func (q *BunSelectQuery) Qualify(builder func(ConditionBuilder)) SelectQuery {
+	q.qualifies = append(q.qualifies, q.BuildCondition(builder))
+
+	return q
+}
+
+func (q *BunSelectQuery) TopNPerGroup(partitionColumns []string, orders []sortx.OrderSpec, n int) SelectQuery {
+	if n <= 0 {
+		logger.Panicf("TopNPerGroup: n must be positive, got %d", n)
+	}
+
+	rowNumber := newRowNumberExpr(q.eb)
+	rowNumber.Over().PartitionBy(partitionColumns...)
+
+	for _, order := range orders {
+		if order.IsValid() {
+			rowNumber.orderExprs = append(rowNumber.orderExprs, orderExpr{
+				builders:   q.eb,
+				column:     order.Column,
+				direction:  order.Direction,
+				nullsOrder: order.NullsOrder,
+			})
+		}
+	}
+
+	if len(rowNumber.orderExprs) == 0 {
+		logger.Panicf("TopNPerGroup: the rows of a group must be ordered to pick the top %d", n)
+	}
+
+	q.exprSelects = append(q.exprSelects, func() {
+		// The row number alone would replace the model columns selected by default
+		if !q.hasSelectAll && !q.hasSelectModelColumns && !q.hasSelectModelPKs && !q.hasExplicitSelect && q.GetTable() != nil {
+			q.query.ColumnExpr(constants.ExprTableColumns)
+		}
+
+		q.query.ColumnExpr("? AS ?", rowNumber, bun.Name(groupRowNumberAlias))
+	})
+
+	return q.Qualify(func(cb ConditionBuilder) {
+		cb.LessThanOrEqual(groupRowNumberAlias, n)
+	})
+}
+
 func (q *BunSelectQuery) OrderBy(columns ...string) SelectQuery {
+	q.hasOrder = true
+
 	for _, column := range columns {
-		q.query.OrderExpr("? ASC", q.eb.Column(column))
+		q.orderExpr("? ASC", q.eb.Column(column))
 	}
 
 	return q
 }
 
 func (q *BunSelectQuery) OrderByDesc(columns ...string) SelectQuery {
+	q.hasOrder = true
+
 	for _, column := range columns {
-		q.query.OrderExpr("? DESC", q.eb.Column(column))
+		q.orderExpr("? DESC", q.eb.Column(column))
 	}
 
 	return q
 }
 
 func (q *BunSelectQuery) OrderByExpr(builder func(ExprBuilder) any) SelectQuery {
+	q.hasOrder = true
+
 	expr := builder(q.eb)
-	q.query.OrderExpr("?", expr)
+	q.orderExpr("?", expr)
+
+	return q
+}
+
+// orderExpr adds an order expression, recorded to order the wrapping query of Qualify.
+func (q *BunSelectQuery) orderExpr(query string, args ...any) {
+	q.query.OrderExpr(query, args...)
+	q.orders = append(q.orders, schema.SafeQuery(query, args))
+}
+
+func (q *BunSelectQuery) Unscoped() SelectQuery {
+	q.isUnscoped = true
+
+	return q
+}
+
+func (q *BunSelectQuery) Timeout(timeout time.Duration) SelectQuery {
+	q.timeout = timeout
 
 	return q
 }
 
 func (q *BunSelectQuery) Limit(limit int) SelectQuery {
+	q.limit = limit
 	q.query.Limit(limit)
 
 	return q
 }
 
 func (q *BunSelectQuery) Offset(offset int) SelectQuery {
+	q.offset = offset
 	q.query.Offset(offset)
 
 	return q
@@ -637,6 +836,48 @@ func (q *BunSelectQuery) Paginate(pageable page.Pageable) SelectQuery {
 	return q.Offset(pageable.Offset()).Limit(pageable.Size)
 }
 
+func (q *BunSelectQuery) Sample(percent float64) SelectQuery {
+	percent = max(0, min(percent, 100))
+
+	q.eb.ExecByDialect(DialectExecs{
+		Postgres: func() {
+			q.query.ModelTableExpr("?TableName AS ?TableAlias TABLESAMPLE SYSTEM (?)", percent)
+		},
+		SQLServer: func() {
+			q.query.ModelTableExpr("?TableName AS ?TableAlias TABLESAMPLE (? PERCENT)", percent)
+		},
+		Default: func() {
+			logger.Warnf("TABLESAMPLE is not supported by %s, falling back to random ordering with a computed limit", q.dialect.Name())
+
+			q.samplePercent = percent
+			q.hasSampleFallback = true
+			q.hasOrder = true
+			q.orderExpr("?", q.eb.Random())
+		},
+	})
+
+	return q
+}
+
+func (q *BunSelectQuery) AsOf(at time.Time) SelectQuery {
+	table := q.GetTable()
+	if table == nil {
+		logger.Panicf("AsOf: the query has no model, call Model before AsOf")
+	}
+
+	if !historyOf(table) {
+		logger.Panicf("AsOf: the history of table %s is not recorded, register it with RegisterHistory", table.Name)
+	}
+
+	if q.modelAlias != constants.Empty {
+		q.query.ModelTableExpr("(?) AS ?", asOfSource(q.db.db, table, at), bun.Name(q.modelAlias))
+	} else {
+		q.query.ModelTableExpr("(?) AS ?TableAlias", asOfSource(q.db.db, table, at))
+	}
+
+	return q
+}
+
 func (q *BunSelectQuery) ForShare(tables ...string) SelectQuery {
 	if len(tables) == 0 {
 		q.query.For("SHARE")
@@ -787,17 +1028,224 @@ func (q *BunSelectQuery) applySelectState() {
 		exprFn()
 	}
 
+	q.applyDefaultScope()
+
 	q.selectStateApplied = true
 }
 
+// applyDefaultScope adds the default scope and, without explicit ordering, the default order of the model.
+func (q *BunSelectQuery) applyDefaultScope() {
+	if q.isUnscoped {
+		return
+	}
+
+	table := q.GetTable()
+	if table == nil {
+		return
+	}
+
+	if defaultScoped(table) {
+		q.Where(table.ZeroIface.(DefaultScoper).DefaultScope)
+	}
+
+	if orderer, ok := table.ZeroIface.(DefaultOrderer); ok && !q.hasOrder {
+		ApplySort(q, orderer.DefaultOrder())
+	}
+}
+
+// defaultScoped reports whether the select queries on the table have the default scope of its model.
+func defaultScoped(table *schema.Table) bool {
+	if table == nil {
+		return false
+	}
+
+	_, ok := table.ZeroIface.(DefaultScoper)
+
+	return ok
+}
+
+// applyScopes routes the query to the database of the tenant and adds the tenant and data scopes of the DB
+// for the model table, once.
+func (q *BunSelectQuery) applyScopes(ctx context.Context) error {
+	if q.dataScopesApplied {
+		return nil
+	}
+
+	q.dataScopesApplied = true
+
+	cb := newQueryConditionBuilder(q.query.QueryBuilder(), q)
+	if _, err := q.db.applyTenancy(ctx, q.query, q.GetTable(), cb); err != nil {
+		return err
+	}
+
+	if err := applyDataScopes(ctx, q.db.dataScopes, q.GetTable(), cb); err != nil {
+		return err
+	}
+
+	return q.applyRelationScopes(ctx)
+}
+
+// applyRelationScopes restricts the rows of every relation loaded with the query, including the relations a nested
+// relation is loaded through, to those the tenant and data scopes of the DB admit.
+func (q *BunSelectQuery) applyRelationScopes(ctx context.Context) error {
+	table := q.GetTable()
+	if table == nil || len(q.relations) == 0 || (len(q.db.dataScopes) == 0 && !q.db.tenancy.filtersRows()) {
+		return nil
+	}
+
+	scoped := make(map[string]bool)
+
+	for _, name := range q.relations {
+		current := table
+		segments := strings.Split(name, constants.Dot)
+		aliases := make([]string, 0, len(segments))
+
+		for i, segment := range segments {
+			relation, ok := current.Relations[segment]
+			if !ok {
+				// Unknown relations are reported by bun
+				break
+			}
+
+			current = relation.JoinTable
+			aliases = append(aliases, relation.Field.Name)
+
+			path := strings.Join(segments[:i+1], constants.Dot)
+			if scoped[path] {
+				continue
+			}
+
+			scoped[path] = true
+
+			condition, err := q.relationScope(ctx, relation, strings.Join(aliases, "__"))
+			if err != nil {
+				return err
+			}
+
+			if condition != nil {
+				q.query.RelationWithOpts(path, bun.RelationOpts{
+					AdditionalJoinOnConditions: []schema.QueryWithArgs{*condition},
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// relationScope returns the condition restricting the rows of the relation to those the tenant and data scopes admit,
+// the primary key of the related rows being in the scoped rows of the related table; nil when nothing restricts them.
+// Joined relations are referred to by their join alias, relations loaded by their own query by the table alias.
+func (q *BunSelectQuery) relationScope(ctx context.Context, relation *schema.Relation, alias string) (*schema.QueryWithArgs, error) {
+	table := relation.JoinTable
+	if len(table.PKs) == 0 {
+		return nil, nil
+	}
+
+	sub := NewSelectQuery(q.db)
+	sub.query.Model(reflect.New(table.Type).Interface())
+
+	cb := newQueryConditionBuilder(sub.query.QueryBuilder(), sub)
+
+	tenantID, err := q.db.applyTenancy(ctx, sub.query, table, cb)
+	if err != nil {
+		return nil, err
+	}
+
+	_, exempt := table.ZeroIface.(DataScopeExempt)
+	sc := DataScopeContextFrom(ctx)
+	scoped := len(q.db.dataScopes) > 0 && !exempt && (sc == nil || !sc.Bypass)
+
+	if !scoped && (tenantID == constants.Empty || !q.db.tenancy.filters(table)) {
+		return nil, nil
+	}
+
+	if err := applyDataScopes(ctx, q.db.dataScopes, table, cb); err != nil {
+		return nil, err
+	}
+
+	var owner any = bun.Safe(table.SQLAlias)
+	if relation.Type == schema.HasOneRelation || relation.Type == schema.BelongsToRelation {
+		owner = bun.Ident(alias)
+	}
+
+	columns := make([]string, len(table.PKs))
+	args := make([]any, 0, 2*len(table.PKs)+1)
+
+	for i, pk := range table.PKs {
+		columns[i] = "?.?"
+		args = append(args, owner, bun.Safe(pk.SQLName))
+		sub.query.ColumnExpr("?TableAlias.?", bun.Safe(pk.SQLName))
+	}
+
+	args = append(args, sub.query)
+
+	condition := schema.SafeQuery("("+strings.Join(columns, ", ")+") IN (?)", args)
+
+	return &condition, nil
+}
+
+// applySampleState computes the row limit for emulated sampling from the current table row count.
+// An explicit Limit smaller than the computed sample size takes precedence.
+func (q *BunSelectQuery) applySampleState(ctx context.Context) error {
+	if !q.hasSampleFallback || q.sampleStateApplied {
+		return nil
+	}
+
+	q.sampleStateApplied = true
+
+	table := q.GetTable()
+	if table == nil {
+		return nil
+	}
+
+	total, err := q.db.db.NewSelect().TableExpr("?", bun.Name(table.Name)).Count(ctx)
+	if err != nil {
+		return err
+	}
+
+	limit := int(math.Ceil(float64(total) * q.samplePercent / 100))
+	if q.limit > 0 && q.limit < limit {
+		limit = q.limit
+	}
+
+	if limit == 0 {
+		q.query.Where("1 = 0")
+
+		return nil
+	}
+
+	q.query.Limit(limit)
+
+	return nil
+}
+
 func (q *BunSelectQuery) Exec(ctx context.Context, dest ...any) (res sql.Result, err error) {
+	ctx = withRejection(ctx)
+
 	if q.isSubQuery {
 		return nil, ErrSubQuery
 	}
 
 	q.applySelectState()
 
-	if res, err = q.query.Exec(ctx, dest...); err != nil && errors.Is(err, sql.ErrNoRows) {
+	if err = q.applyScopes(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = q.applySampleState(ctx); err != nil {
+		return nil, err
+	}
+
+	q.applyFullJoinState()
+	q.applyQualifyState()
+
+	err = q.runWithTimeout(ctx, func(ctx context.Context) error {
+		res, err = q.query.Exec(ctx, dest...)
+
+		return contextError(ctx, err)
+	})
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
 		return nil, result.ErrRecordNotFound
 	}
 
@@ -805,41 +1253,110 @@ func (q *BunSelectQuery) Exec(ctx context.Context, dest ...any) (res sql.Result,
 }
 
 func (q *BunSelectQuery) Scan(ctx context.Context, dest ...any) (err error) {
+	ctx = withRejection(ctx)
+
 	if q.isSubQuery {
 		return ErrSubQuery
 	}
 
 	q.applySelectState()
 
-	if err = q.query.Scan(ctx, dest...); err != nil && errors.Is(err, sql.ErrNoRows) {
+	if err = q.applyScopes(ctx); err != nil {
+		return err
+	}
+
+	if err = q.applySampleState(ctx); err != nil {
+		return err
+	}
+
+	q.applyFullJoinState()
+	q.applyQualifyState()
+
+	if err = q.runWithTimeout(ctx, func(ctx context.Context) error {
+		if dynamicDest(dest) {
+			return scanContextError(ctx, q.scanDynamic(ctx, dest[0]))
+		}
+
+		return scanContextError(ctx, q.query.Scan(ctx, dest...))
+	}); err != nil && errors.Is(err, sql.ErrNoRows) {
 		return result.ErrRecordNotFound
 	}
 
 	return err
 }
 
-func (q *BunSelectQuery) Rows(ctx context.Context) (rows *sql.Rows, err error) {
+func (q *BunSelectQuery) Rows(ctx context.Context) (*Rows, error) {
+	ctx = withRejection(ctx)
+
 	if q.isSubQuery {
 		return nil, ErrSubQuery
 	}
 
 	q.applySelectState()
 
-	if rows, err = q.query.Rows(ctx); err != nil && errors.Is(err, sql.ErrNoRows) {
-		return nil, result.ErrRecordNotFound
+	if err := q.applyScopes(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := q.applySampleState(ctx); err != nil {
+		return nil, err
 	}
 
-	return rows, err
+	q.applyFullJoinState()
+	q.applyQualifyState()
+
+	ctx, finish, err := q.startTimeout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.query.Rows(ctx)
+	if err != nil {
+		if err = finish(contextError(ctx, err)); errors.Is(err, sql.ErrNoRows) {
+			return nil, result.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return &Rows{Rows: rows, finish: finish}, nil
 }
 
 func (q *BunSelectQuery) ScanAndCount(ctx context.Context, dest ...any) (int64, error) {
+	ctx = withRejection(ctx)
+
 	if q.isSubQuery {
 		return 0, ErrSubQuery
 	}
 
 	q.applySelectState()
 
-	total, err := q.query.ScanAndCount(ctx, dest...)
+	if err := q.applyScopes(ctx); err != nil {
+		return 0, err
+	}
+
+	if err := q.applySampleState(ctx); err != nil {
+		return 0, err
+	}
+
+	q.applyFullJoinState()
+	q.applyQualifyState()
+
+	var total int
+
+	err := q.runWithTimeout(ctx, func(ctx context.Context) (err error) {
+		if dynamicDest(dest) {
+			if total, err = q.query.Count(ctx); err != nil {
+				return scanContextError(ctx, err)
+			}
+
+			return scanContextError(ctx, q.scanDynamic(ctx, dest[0]))
+		}
+
+		total, err = q.query.ScanAndCount(ctx, dest...)
+
+		return scanContextError(ctx, err)
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return 0, result.ErrRecordNotFound
@@ -852,25 +1369,63 @@ func (q *BunSelectQuery) ScanAndCount(ctx context.Context, dest ...any) (int64,
 }
 
 func (q *BunSelectQuery) Count(ctx context.Context) (int64, error) {
+	ctx = withRejection(ctx)
+
 	if q.isSubQuery {
 		return 0, ErrSubQuery
 	}
 
 	q.applySelectState()
 
-	total, err := q.query.Count(ctx)
+	if err := q.applyScopes(ctx); err != nil {
+		return 0, err
+	}
+
+	q.applyFullJoinState()
+	q.applyQualifyState()
+
+	var total int
 
-	return int64(total), err
+	err := q.runWithTimeout(ctx, func(ctx context.Context) (err error) {
+		total, err = q.query.Count(ctx)
+
+		return scanContextError(ctx, err)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(total), nil
 }
 
 func (q *BunSelectQuery) Exists(ctx context.Context) (bool, error) {
+	ctx = withRejection(ctx)
+
 	if q.isSubQuery {
 		return false, ErrSubQuery
 	}
 
 	q.applySelectState()
 
-	return q.query.Exists(ctx)
+	if err := q.applyScopes(ctx); err != nil {
+		return false, err
+	}
+
+	q.applyFullJoinState()
+	q.applyQualifyState()
+
+	var exists bool
+
+	err := q.runWithTimeout(ctx, func(ctx context.Context) (err error) {
+		exists, err = q.query.Exists(ctx)
+
+		return scanContextError(ctx, err)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
 }
 
 func (q *BunSelectQuery) Unwrap() *bun.SelectQuery {
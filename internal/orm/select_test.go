@@ -1,12 +1,61 @@
 package orm
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
 	"github.com/uptrace/bun"
 
 	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/decimal"
 	"github.com/ilxqx/vef-framework-go/page"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/sortx"
 )
 
+// cancelFuncKey carries the cancel function that CancellingUser invokes after scanning a row.
+type cancelFuncKey struct{}
+
+// CancellingUser cancels the query context after its first row is scanned to simulate a mid-scan cancellation.
+type CancellingUser struct {
+	bun.BaseModel `bun:"table:test_user,alias:u"`
+
+	ID   string `bun:"id,pk"`
+	Name string `bun:"name"`
+}
+
+func (*CancellingUser) AfterScanRow(ctx context.Context) error {
+	if cancel, ok := ctx.Value(cancelFuncKey{}).(context.CancelFunc); ok {
+		cancel()
+	}
+
+	return nil
+}
+
+// ScopedUser declares a default scope restricted to active users and a default order by age descending.
+type ScopedUser struct {
+	bun.BaseModel `bun:"table:test_user,alias:u"`
+
+	ID       string `bun:"id,pk"`
+	Name     string `bun:"name"`
+	Age      int16  `bun:"age"`
+	IsActive bool   `bun:"is_active"`
+}
+
+func (*ScopedUser) DefaultScope(cb ConditionBuilder) {
+	cb.IsTrue("is_active")
+}
+
+func (*ScopedUser) DefaultOrder() []sortx.OrderSpec {
+	return []sortx.OrderSpec{{Column: "age", Direction: sortx.OrderDesc}}
+}
+
 // SelectTestSuite tests SELECT operations including basic queries, column selection,
 // joins, subqueries, ordering, pagination, locking, set operations, and execution methods
 // across all databases (PostgreSQL, MySQL, SQLite).
@@ -1223,12 +1272,6 @@ func (suite *SelectTestSuite) TestJoins() {
 	})
 
 	suite.Run("FullJoin", func() {
-		if suite.dbType == constants.MySQL {
-			suite.T().Skip("Skipping for MySQL: FULL JOIN not supported (use LEFT JOIN UNION RIGHT JOIN instead)")
-
-			return
-		}
-
 		type UserWithPosts struct {
 			ID        string `bun:"id"`
 			Name      string `bun:"name"`
@@ -1258,6 +1301,71 @@ func (suite *SelectTestSuite) TestJoins() {
 		}
 	})
 
+	suite.Run("FullJoinEmulated", func() {
+		fullJoined := func() []string {
+			var rows []struct {
+				UserName  *string `bun:"user_name"`
+				PostTitle *string `bun:"post_title"`
+			}
+
+			err := suite.db.NewSelect().
+				Model((*User)(nil)).
+				SelectAs("name", "user_name").
+				SelectAs("p.title", "post_title").
+				FullJoin((*Post)(nil), func(cb ConditionBuilder) {
+					cb.EqualsColumn("p.user_id", "id").GreaterThan("p.view_count", 70)
+				}).
+				Scan(suite.ctx, &rows)
+			suite.Require().NoError(err, "FULL JOIN should execute successfully")
+
+			pairs := make([]string, len(rows))
+			for i, row := range rows {
+				pairs[i] = fmt.Sprintf("%s|%s", lo.FromPtr(row.UserName), lo.FromPtr(row.PostTitle))
+			}
+
+			slices.Sort(pairs)
+
+			return pairs
+		}
+
+		var native []string
+		if !fullJoinEmulated[suite.getBunDB().Dialect().Name()] {
+			native = fullJoined()
+		}
+
+		name := suite.getBunDB().Dialect().Name()
+		emulated := fullJoinEmulated[name]
+		fullJoinEmulated[name] = true
+
+		defer func() {
+			fullJoinEmulated[name] = emulated
+		}()
+
+		pairs := fullJoined()
+		suite.Contains(pairs, "|Machine Learning Basics", "Should keep the right rows matching no left row")
+		suite.True(slices.ContainsFunc(pairs, func(pair string) bool {
+			return strings.HasSuffix(pair, "|") && pair != "|"
+		}), "Should keep the left rows matching no right row")
+
+		if native != nil {
+			suite.Equal(native, pairs, "Emulated FULL JOIN should match the native one")
+		}
+
+		suite.NotPanics(func() {
+			count, err := suite.db.NewSelect().
+				Model((*User)(nil)).
+				FullJoin((*Post)(nil), func(cb ConditionBuilder) {
+					cb.EqualsColumn("p.user_id", "id")
+				}).
+				FullJoinTable("test_category", func(cb ConditionBuilder) {
+					cb.EqualsColumn("c.id", "p.category_id")
+				}, "c").
+				Count(suite.ctx)
+			suite.ErrorIs(err, ErrDialectUnsupportedOperation, "Emulation should reject a second FULL JOIN when executed")
+			suite.Zero(count)
+		})
+	})
+
 	suite.Run("CrossJoin", func() {
 		type UserCategoryCross struct {
 			UserID       string `bun:"user_id"`
@@ -1895,6 +2003,174 @@ func (suite *SelectTestSuite) TestPagination() {
 	})
 }
 
+// TestSample tests the Sample method.
+func (suite *SelectTestSuite) TestSample() {
+	suite.T().Logf("Testing Sample method for %s", suite.dbType)
+
+	totalCount, err := suite.db.NewSelect().
+		Model((*Post)(nil)).
+		Count(suite.ctx)
+	suite.NoError(err, "Should count total posts")
+
+	suite.Run("FullSample", func() {
+		var posts []Post
+
+		err := suite.db.NewSelect().
+			Model(&posts).
+			Sample(100).
+			Scan(suite.ctx)
+
+		suite.NoError(err, "Sample should work correctly")
+		suite.Len(posts, int(totalCount), "A 100 percent sample should return all posts")
+	})
+
+	suite.Run("PartialSample", func() {
+		var posts []Post
+
+		err := suite.db.NewSelect().
+			Model(&posts).
+			Sample(50).
+			Scan(suite.ctx)
+
+		suite.NoError(err, "Partial sample should work correctly")
+		suite.True(len(posts) <= int(totalCount), "Sample should not return more rows than the table has")
+
+		// Emulated sampling is deterministic in size, native TABLESAMPLE is page-based
+		if suite.dbType == constants.MySQL || suite.dbType == constants.SQLite {
+			suite.Len(posts, int(math.Ceil(float64(totalCount)/2)), "Emulated sample should return half of the posts")
+		}
+
+		suite.T().Logf("Sampled %d of %d posts", len(posts), totalCount)
+	})
+
+	suite.Run("SampleWithSmallerLimit", func() {
+		var posts []Post
+
+		err := suite.db.NewSelect().
+			Model(&posts).
+			Sample(100).
+			Limit(2).
+			Scan(suite.ctx)
+
+		suite.NoError(err, "Sample with limit should work correctly")
+		suite.Len(posts, 2, "Explicit smaller limit should take precedence")
+	})
+}
+
+// TestExplain tests the Explain method.
+func (suite *SelectTestSuite) TestExplain() {
+	suite.T().Logf("Testing Explain method for %s", suite.dbType)
+
+	suite.Run("FullTableScan", func() {
+		plan, err := suite.db.NewSelect().
+			Model((*User)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.GreaterThan("age", 20)
+			}).
+			Explain(suite.ctx, ExplainOptions{})
+
+		suite.Require().NoError(err, "Explain should work correctly")
+		suite.NotEmpty(plan.Raw, "Plan should keep the raw EXPLAIN output")
+		suite.NotEmpty(plan.Nodes, "Plan should have nodes")
+		suite.NotEmpty(plan.FullScans(), "Filtering a column without index should scan the table")
+		suite.NotEmpty(plan.String(), "Plan should render its nodes")
+
+		suite.T().Logf("Plan:\n%s", plan)
+	})
+
+	suite.Run("IndexLookup", func() {
+		// PostgreSQL prefers sequential scans on tables as small as the fixtures
+		if suite.dbType == constants.Postgres {
+			suite.T().Skipf("Index choice depends on table statistics, skipping for %s", suite.dbType)
+		}
+
+		var user User
+		suite.Require().NoError(suite.db.NewSelect().Model(&user).Limit(1).Scan(suite.ctx), "Should select a user")
+
+		// MySQL plans no table access for primary keys matching no row
+		plan, err := suite.db.NewSelect().
+			Model((*User)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.PKEquals(user.ID)
+			}).
+			Explain(suite.ctx, ExplainOptions{})
+
+		suite.Require().NoError(err, "Explain should work correctly")
+		suite.Empty(plan.FullScans(), "Primary key lookups should not scan the table")
+
+		var indexes []string
+		plan.Walk(func(node *PlanNode, _ int) {
+			if node.Index != constants.Empty {
+				indexes = append(indexes, node.Index)
+			}
+		})
+		suite.NotEmpty(indexes, "Primary key lookups should read an index")
+	})
+
+	suite.Run("Analyze", func() {
+		plan, err := suite.db.NewSelect().
+			Model((*User)(nil)).
+			Explain(suite.ctx, ExplainOptions{Analyze: true})
+
+		suite.Require().NoError(err, "Explain with analyze should work correctly")
+		suite.Require().NotEmpty(plan.Nodes, "Plan should have nodes")
+
+		if suite.dbType == constants.Postgres {
+			suite.Equal(float64(3), plan.Nodes[0].ActualRows, "Analyzed plans should report the actual rows")
+		}
+	})
+
+	suite.Run("ParsePostgresPlan", func() {
+		plan := &QueryPlan{Raw: `[{"Plan": {"Node Type": "Nested Loop", "Total Cost": 42.5, "Actual Rows": 2,
+			"Actual Total Time": 1.5, "Plans": [
+			{"Node Type": "Seq Scan", "Relation Name": "sys_user", "Total Cost": 20.1, "Plan Rows": 10},
+			{"Node Type": "Index Scan", "Relation Name": "sys_role", "Index Name": "sys_role_pkey", "Total Cost": 0.3}
+		]}}]`}
+
+		suite.Require().NoError(parsePostgresPlan(plan))
+		suite.Equal(42.5, plan.Cost)
+		suite.Equal("Nested Loop\n  Seq Scan on sys_user\n  Index Scan on sys_role using sys_role_pkey\n", plan.String())
+		suite.Equal([]string{"sys_user"}, plan.FullScans())
+		suite.Equal(float64(2), plan.Nodes[0].ActualRows)
+		suite.Equal(1500*time.Microsecond, plan.Nodes[0].ActualTime)
+		suite.Equal(float64(10), plan.Nodes[0].Children[0].Rows)
+	})
+
+	suite.Run("ParseMySQLPlan", func() {
+		plan := &QueryPlan{Raw: `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "3.75"},
+			"ordering_operation": {"using_filesort": true, "nested_loop": [
+			{"table": {"table_name": "u", "access_type": "ALL", "rows_produced_per_join": 10,
+				"cost_info": {"prefix_cost": "1.25"}}},
+			{"table": {"table_name": "r", "access_type": "eq_ref", "key": "PRIMARY"}}
+		]}}}`}
+
+		suite.Require().NoError(parseMySQLPlan(plan))
+		suite.Equal(3.75, plan.Cost)
+		suite.Equal("ordering_operation\n  nested_loop\n    ALL on u\n    eq_ref on r using PRIMARY\n", plan.String())
+		suite.Equal([]string{"u"}, plan.FullScans())
+
+		scan := plan.Nodes[0].Children[0].Children[0]
+		suite.Equal(1.25, scan.Cost)
+		suite.Equal(float64(10), scan.Rows)
+	})
+
+	suite.Run("ParseSQLitePlan", func() {
+		plan := &QueryPlan{}
+		parseSQLitePlan([]sqlitePlanRow{
+			{ID: 2, Detail: "SCAN TABLE test_user AS u"},
+			{ID: 3, Detail: "SEARCH p USING INDEX idx_post_user (user_id=?)"},
+			{ID: 5, Detail: "CORRELATED SCALAR SUBQUERY 1"},
+			{ID: 7, Parent: 5, Detail: "SEARCH c USING INTEGER PRIMARY KEY (rowid=?)"},
+		}, plan)
+
+		suite.Equal("SCAN TABLE test_user AS u\nSEARCH p USING INDEX idx_post_user (user_id=?)\n"+
+			"CORRELATED SCALAR SUBQUERY 1\n  SEARCH c USING INTEGER PRIMARY KEY (rowid=?)\n", plan.String())
+		suite.Equal([]string{"test_user"}, plan.FullScans())
+		suite.Equal("idx_post_user", plan.Nodes[1].Index)
+		suite.Equal("PRIMARY KEY", plan.Nodes[2].Children[0].Index)
+	})
+}
+
 // TestLocking tests ForShare and ForUpdate methods.
 func (suite *SelectTestSuite) TestLocking() {
 	suite.T().Logf("Testing Locking methods for %s", suite.dbType)
@@ -2312,3 +2588,544 @@ func (suite *SelectTestSuite) TestExecution() {
 		suite.T().Logf("Exec result: %s", result.Name)
 	})
 }
+
+// TestContextCancellation tests that execution methods report context cancellation and deadline expiry.
+func (suite *SelectTestSuite) TestContextCancellation() {
+	suite.T().Logf("Testing context cancellation for %s", suite.dbType)
+
+	cancelled, cancel := context.WithCancel(suite.ctx)
+	cancel()
+
+	suite.Run("ScanWithCancelledContext", func() {
+		var users []User
+
+		err := suite.db.NewSelect().Model(&users).Scan(cancelled)
+		suite.ErrorIs(err, context.Canceled, "Scan should fail with the context error")
+	})
+
+	suite.Run("CountAndExistsWithCancelledContext", func() {
+		_, err := suite.db.NewSelect().Model((*User)(nil)).Count(cancelled)
+		suite.ErrorIs(err, context.Canceled, "Count should fail with the context error")
+
+		_, err = suite.db.NewSelect().Model((*User)(nil)).Exists(cancelled)
+		suite.ErrorIs(err, context.Canceled, "Exists should fail with the context error")
+
+		_, err = suite.db.NewSelect().Model((*User)(nil)).ScanAndCount(cancelled)
+		suite.ErrorIs(err, context.Canceled, "ScanAndCount should fail with the context error")
+	})
+
+	suite.Run("ScanCancelledMidScan", func() {
+		ctx, cancel := context.WithCancel(suite.ctx)
+		defer cancel()
+
+		var users []CancellingUser
+
+		err := suite.db.NewSelect().
+			Model(&users).
+			OrderBy("name").
+			Scan(context.WithValue(ctx, cancelFuncKey{}, cancel))
+		suite.ErrorIs(err, context.Canceled, "Scan cancelled while scanning rows should not report success")
+		suite.NotErrorIs(err, result.ErrRecordNotFound, "Cancellation should not be reported as not found")
+	})
+
+	suite.Run("DeadlineExceeded", func() {
+		ctx, cancel := context.WithTimeout(suite.ctx, 0)
+		defer cancel()
+
+		var user User
+
+		err := suite.db.NewSelect().Model(&user).Where(func(cb ConditionBuilder) {
+			cb.Equals("name", "Alice Johnson")
+		}).Scan(ctx)
+		suite.ErrorIs(err, context.DeadlineExceeded, "Scan should fail with the deadline error")
+	})
+
+	suite.Run("WriteWithCancelledContext", func() {
+		_, err := suite.db.NewUpdate().
+			Model((*User)(nil)).
+			Set("age", 99).
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("name", "Alice Johnson")
+			}).
+			Exec(cancelled)
+		suite.ErrorIs(err, context.Canceled, "Update should fail with the context error")
+
+		var user User
+
+		err = suite.db.NewSelect().Model(&user).Where(func(cb ConditionBuilder) {
+			cb.Equals("name", "Alice Johnson")
+		}).Scan(suite.ctx)
+		suite.Require().NoError(err)
+		suite.Equal(int16(30), user.Age, "Cancelled update should not be applied")
+	})
+}
+
+// TestTimeout tests that queries running longer than their timeout are cancelled with the deadline error.
+func (suite *SelectTestSuite) TestTimeout() {
+	suite.T().Logf("Testing query timeout for %s", suite.dbType)
+
+	if suite.dbType == constants.SQLite {
+		// Interrupted SQLite connections are discarded, another one keeps the shared in-memory database alive
+		conn, err := suite.getBunDB().Conn(suite.ctx)
+		suite.Require().NoError(err)
+
+		defer func() {
+			suite.NoError(conn.Close())
+		}()
+	}
+
+	// Counts to a hundred million, running for seconds
+	slow := func(cb ConditionBuilder) {
+		cb.Expr(func(eb ExprBuilder) any {
+			return eb.Expr("EXISTS (WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 100000000) SELECT 1 FROM n WHERE i = 0)")
+		})
+	}
+
+	suite.Run("CancelsSlowQuery", func() {
+		if suite.dbType == constants.MySQL {
+			suite.T().Skip("Skipping for MySQL: recursive common table expressions stop at cte_max_recursion_depth before timing out")
+		}
+
+		start := time.Now()
+
+		_, err := suite.db.NewSelect().Model((*User)(nil)).Where(slow).Timeout(100 * time.Millisecond).Count(suite.ctx)
+		suite.ErrorIs(err, context.DeadlineExceeded, "Count should fail with the deadline error")
+		suite.Less(time.Since(start), 5*time.Second, "Count should be cancelled at the timeout")
+	})
+
+	suite.Run("CompletesFastQuery", func() {
+		var users []User
+
+		err := suite.db.NewSelect().Model(&users).Timeout(time.Minute).Scan(suite.ctx)
+		suite.Require().NoError(err)
+		suite.Len(users, 3)
+	})
+
+	suite.Run("CancelsSlowRows", func() {
+		if suite.dbType == constants.MySQL {
+			suite.T().Skip("Skipping for MySQL: recursive common table expressions stop at cte_max_recursion_depth before timing out")
+		}
+
+		start := time.Now()
+
+		rows, err := suite.db.NewSelect().Model((*User)(nil)).Where(slow).Timeout(100 * time.Millisecond).Rows(suite.ctx)
+		if err == nil {
+			for rows.Next() {
+			}
+
+			err = rows.Close()
+		}
+
+		suite.ErrorIs(err, context.DeadlineExceeded, "Rows should fail with the deadline error")
+		suite.Less(time.Since(start), 5*time.Second, "Rows should be cancelled at the timeout")
+	})
+
+	suite.Run("ReadsRowsUntilClosed", func() {
+		rows, err := suite.db.NewSelect().Model((*User)(nil)).Select("name").OrderBy("name").Timeout(time.Minute).Rows(suite.ctx)
+		suite.Require().NoError(err)
+
+		var names []string
+		for rows.Next() {
+			var name string
+			suite.Require().NoError(rows.Scan(&name))
+			names = append(names, name)
+		}
+
+		suite.NoError(rows.Close(), "Closing the rows should end the timeout")
+		suite.NoError(rows.Close(), "Closing the rows again should be a no-op")
+		suite.Len(names, 3, "Should read all rows while the timeout is active")
+	})
+
+	suite.Run("InTransaction", func() {
+		err := suite.db.RunInTX(suite.ctx, func(ctx context.Context, tx DB) error {
+			exists, err := tx.NewSelect().Model((*User)(nil)).Timeout(time.Minute).Exists(ctx)
+			suite.Require().NoError(err)
+			suite.True(exists)
+
+			if suite.dbType == constants.Postgres {
+				var statementTimeout string
+				suite.Require().NoError(tx.NewRaw("SELECT current_setting('statement_timeout')").Scan(ctx, &statementTimeout))
+				suite.Equal("0", statementTimeout, "Statement timeout should not outlive the query")
+			}
+
+			return nil
+		})
+		suite.NoError(err)
+	})
+
+	suite.Run("RestoresAfterCancellation", func() {
+		if suite.dbType != constants.MySQL {
+			suite.T().Skipf("Session timeouts are only set on MySQL, skipping for %s", suite.dbType)
+		}
+
+		conn, err := suite.getBunDB().Conn(suite.ctx)
+		suite.Require().NoError(err)
+
+		defer func() {
+			suite.NoError(conn.Close())
+		}()
+
+		var previous, current int64
+		suite.Require().NoError(conn.QueryRowContext(suite.ctx, "SELECT @@SESSION.max_execution_time").Scan(&previous))
+
+		ctx, cancel := context.WithCancel(suite.ctx)
+		query := suite.db.NewSelect().Model((*User)(nil)).Timeout(time.Minute).(*BunSelectQuery)
+
+		ctx, finish, err := query.startMaxExecutionTime(ctx, conn)
+		suite.Require().NoError(err)
+
+		cancel()
+		suite.ErrorIs(finish(ctx.Err()), context.Canceled)
+
+		suite.Require().NoError(conn.QueryRowContext(suite.ctx, "SELECT @@SESSION.max_execution_time").Scan(&current))
+		suite.Equal(previous, current, "Session timeout should be restored after the context is cancelled")
+	})
+}
+
+// TestQualify tests filtering on window function results through the emulated QUALIFY.
+func (suite *SelectTestSuite) TestQualify() {
+	suite.T().Logf("Testing Qualify for %s", suite.dbType)
+
+	topPosts := func(n int) SelectQuery {
+		return suite.db.NewSelect().
+			Model((*Post)(nil)).
+			SelectModelColumns().
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.RowNumber(func(rn RowNumberBuilder) {
+					rn.Over().PartitionBy("user_id").OrderByDesc("view_count")
+				})
+			}, "rn").
+			Qualify(func(cb ConditionBuilder) {
+				cb.LessThanOrEqual("rn", n)
+			})
+	}
+
+	titles := func(posts []Post) []string {
+		titles := make([]string, len(posts))
+		for i, post := range posts {
+			titles[i] = post.Title
+		}
+
+		return titles
+	}
+
+	suite.Run("TopPerGroup", func() {
+		var posts []Post
+
+		suite.Require().NoError(topPosts(1).OrderByDesc("view_count").Scan(suite.ctx, &posts))
+		suite.Equal([]string{"Introduction to Go", "Database Design Basics", "Business Strategy Fundamentals"}, titles(posts),
+			"Should keep the most viewed post of each user")
+	})
+
+	suite.Run("WhereAppliesBeforeWindow", func() {
+		var posts []Post
+
+		err := topPosts(1).
+			Where(func(cb ConditionBuilder) {
+				cb.NotEquals("title", "Introduction to Go")
+			}).
+			OrderByDesc("view_count").
+			Scan(suite.ctx, &posts)
+		suite.Require().NoError(err)
+		suite.Equal([]string{"Advanced Go Patterns", "Database Design Basics", "Business Strategy Fundamentals"}, titles(posts),
+			"Window functions should rank the rows WHERE keeps")
+	})
+
+	suite.Run("LimitAppliesAfterFilter", func() {
+		var posts []Post
+
+		suite.Require().NoError(topPosts(2).OrderByDesc("view_count").Limit(2).Offset(1).Scan(suite.ctx, &posts))
+		suite.Equal([]string{"Advanced Go Patterns", "Database Design Basics"}, titles(posts),
+			"Limit and offset should page the filtered rows")
+	})
+
+	suite.Run("CountAndExists", func() {
+		count, err := topPosts(2).Count(suite.ctx)
+		suite.Require().NoError(err)
+		suite.Equal(int64(6), count, "Should count the filtered rows")
+
+		var posts []Post
+
+		total, err := topPosts(2).OrderBy("title").Limit(1).ScanAndCount(suite.ctx, &posts)
+		suite.Require().NoError(err)
+		suite.Equal(int64(6), total)
+		suite.Equal([]string{"Advanced Go Patterns"}, titles(posts))
+
+		exists, err := topPosts(0).Exists(suite.ctx)
+		suite.Require().NoError(err)
+		suite.False(exists, "No row should rank zeroth")
+	})
+
+	suite.Run("TableSubQuery", func() {
+		var userIDs []string
+
+		err := suite.db.NewSelect().
+			TableSubQuery(func(sq SelectQuery) {
+				sq.Model((*Post)(nil)).
+					Select("user_id", "view_count").
+					SelectExpr(func(eb ExprBuilder) any {
+						return eb.RowNumber(func(rn RowNumberBuilder) {
+							rn.Over().OrderByDesc("view_count")
+						})
+					}, "rn").
+					Qualify(func(cb ConditionBuilder) {
+						cb.Equals("rn", 1)
+					})
+			}, "top").
+			Select("top.user_id").
+			Scan(suite.ctx, &userIDs)
+		suite.Require().NoError(err)
+		suite.Len(userIDs, 1, "Should filter inside subqueries")
+	})
+}
+
+// TestTopNPerGroup tests keeping the first rows of every group with TopNPerGroup.
+func (suite *SelectTestSuite) TestTopNPerGroup() {
+	suite.T().Logf("Testing TopNPerGroup for %s", suite.dbType)
+
+	mostViewed := []sortx.OrderSpec{{Column: "view_count", Direction: sortx.OrderDesc}}
+
+	suite.Run("TopPerGroup", func() {
+		var posts []Post
+
+		err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			TopNPerGroup([]string{"user_id"}, mostViewed, 1).
+			OrderByDesc("view_count").
+			Scan(suite.ctx, &posts)
+		suite.Require().NoError(err)
+
+		titles := make([]string, len(posts))
+		for i, post := range posts {
+			suite.NotEmpty(post.ID, "Should select the model columns by default")
+
+			titles[i] = post.Title
+		}
+
+		suite.Equal([]string{"Introduction to Go", "Database Design Basics", "Business Strategy Fundamentals"}, titles,
+			"Should keep the most viewed post of each user")
+	})
+
+	suite.Run("CountPerGroup", func() {
+		count, err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			TopNPerGroup([]string{"user_id"}, mostViewed, 2).
+			Count(suite.ctx)
+		suite.Require().NoError(err)
+		suite.Equal(int64(6), count, "Should keep two posts of each user")
+	})
+
+	suite.Run("ExplicitSelect", func() {
+		var rows []map[string]any
+
+		err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			Select("user_id", "title").
+			TopNPerGroup([]string{"user_id"}, mostViewed, 1).
+			Scan(suite.ctx, &rows)
+		suite.Require().NoError(err)
+		suite.Require().Len(rows, 3)
+
+		for _, row := range rows {
+			suite.Len(row, 3, "Should select the chosen columns and the row number")
+			suite.EqualValues(1, row["group_row_number"])
+		}
+	})
+
+	suite.Run("InvalidArguments", func() {
+		suite.Panics(func() {
+			suite.db.NewSelect().Model((*Post)(nil)).TopNPerGroup([]string{"user_id"}, mostViewed, 0)
+		}, "Should reject non-positive counts")
+		suite.Panics(func() {
+			suite.db.NewSelect().Model((*Post)(nil)).TopNPerGroup([]string{"user_id"}, nil, 1)
+		}, "Should require an order")
+	})
+}
+
+// TestDynamicScan tests scanning rows into Row and map destinations with values converted by their column types.
+func (suite *SelectTestSuite) TestDynamicScan() {
+	suite.T().Logf("Testing dynamic scan for %s", suite.dbType)
+
+	suite.Run("Rows", func() {
+		var rows []Row
+
+		err := suite.db.NewSelect().
+			Model((*User)(nil)).
+			Select("name", "age", "is_active", "created_at").
+			OrderBy("name").
+			Scan(suite.ctx, &rows)
+		suite.Require().NoError(err)
+		suite.Require().NotEmpty(rows)
+
+		for _, row := range rows {
+			suite.Equal([]string{"name", "age", "is_active", "created_at"}, row.Columns,
+				"Should keep the columns in select order")
+			suite.IsType("", row.Values[0])
+			suite.IsType(int64(0), row.Values[1], "Should scan integers as int64")
+			suite.IsType(false, row.Values[2], "Should scan booleans as bool")
+			suite.IsType(time.Time{}, row.Values[3], "Should scan timestamps as time.Time")
+		}
+
+		encoded, err := json.Marshal(rows[0])
+		suite.Require().NoError(err)
+		suite.True(strings.HasPrefix(string(encoded), `{"name":`), "Should encode the columns in select order")
+	})
+
+	suite.Run("Maps", func() {
+		var rows []map[string]any
+
+		total, err := suite.db.NewSelect().
+			Model((*Post)(nil)).
+			Select("title").
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.Expr("? * 1.5", eb.Column("view_count"))
+			}, "weighted_views").
+			OrderBy("title").
+			Limit(2).
+			ScanAndCount(suite.ctx, &rows)
+		suite.Require().NoError(err)
+		suite.Equal(int64(8), total, "Should count all rows ignoring the limit")
+		suite.Require().Len(rows, 2)
+
+		for _, row := range rows {
+			suite.IsType("", row["title"])
+			suite.NotNil(row["weighted_views"])
+		}
+	})
+
+	suite.Run("SingleRow", func() {
+		var row map[string]any
+
+		err := suite.db.NewSelect().
+			Model((*User)(nil)).
+			Select("name", "age").
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("name", "Alice Johnson")
+			}).
+			Scan(suite.ctx, &row)
+		suite.Require().NoError(err)
+		suite.Equal(map[string]any{"name": "Alice Johnson", "age": int64(30)}, row)
+
+		var missing Row
+
+		err = suite.db.NewSelect().
+			Model((*User)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("name", "Nobody")
+			}).
+			Scan(suite.ctx, &missing)
+		suite.ErrorIs(err, result.ErrRecordNotFound, "Should report a missing row")
+	})
+
+	suite.Run("ColumnValues", func() {
+		value, err := convertColumnValue("BLOB", []byte{0x00, 0xff})
+		suite.Require().NoError(err)
+		suite.Equal([]byte{0x00, 0xff}, value, "Should keep binary values as bytes")
+
+		value, err = convertColumnValue("VARCHAR", []byte("text"))
+		suite.Require().NoError(err)
+		suite.Equal("text", value, "Should convert textual bytes to strings")
+
+		value, err = convertColumnValue("MONEY", "$1,234.56")
+		suite.Require().NoError(err)
+		suite.True(decimal.RequireFromString("1234.56").Equal(value.(decimal.Decimal)), "Should parse grouped money values")
+
+		value, err = convertColumnValue("UNSIGNED", []byte("18446744073709551615"))
+		suite.Require().NoError(err)
+		suite.Equal(uint64(math.MaxUint64), value, "Should parse unsigned values beyond int64 as uint64")
+
+		value, err = convertColumnValue("UNSIGNED", []byte("42"))
+		suite.Require().NoError(err)
+		suite.Equal(int64(42), value)
+	})
+}
+
+// TestDefaultScope tests that model default scopes and default orders are applied unless Unscoped is called.
+func (suite *SelectTestSuite) TestDefaultScope() {
+	suite.T().Logf("Testing default scope for %s", suite.dbType)
+
+	suite.Run("DefaultScopeAndOrder", func() {
+		var users []ScopedUser
+
+		err := suite.db.NewSelect().Model(&users).Scan(suite.ctx)
+		suite.Require().NoError(err)
+		suite.Require().Len(users, 2, "Default scope should exclude inactive users")
+		suite.Equal("Alice Johnson", users[0].Name, "Default order should sort by age descending")
+		suite.Equal("Bob Smith", users[1].Name)
+	})
+
+	suite.Run("ExplicitOrderOverridesDefaultOrder", func() {
+		var users []ScopedUser
+
+		err := suite.db.NewSelect().Model(&users).OrderBy("age").Scan(suite.ctx)
+		suite.Require().NoError(err)
+		suite.Require().Len(users, 2, "Default scope should still apply with explicit ordering")
+		suite.Equal("Bob Smith", users[0].Name, "Explicit order should replace the default order")
+	})
+
+	suite.Run("DefaultScopeCombinesWithWhere", func() {
+		var users []ScopedUser
+
+		err := suite.db.NewSelect().Model(&users).Where(func(cb ConditionBuilder) {
+			cb.GreaterThan("age", 26)
+		}).Scan(suite.ctx)
+		suite.Require().NoError(err)
+		suite.Require().Len(users, 1, "Default scope should be combined with explicit conditions")
+		suite.Equal("Alice Johnson", users[0].Name)
+	})
+
+	suite.Run("DefaultScopeCombinesWithOrConditions", func() {
+		var users []ScopedUser
+
+		err := suite.db.NewSelect().Model(&users).Where(func(cb ConditionBuilder) {
+			cb.Equals("name", "Charlie Brown").OrEquals("name", "Bob Smith")
+		}).Scan(suite.ctx)
+		suite.Require().NoError(err)
+		suite.Require().Len(users, 1, "Or conditions should not escape the default scope")
+		suite.Equal("Bob Smith", users[0].Name)
+	})
+
+	suite.Run("CountAndExists", func() {
+		count, err := suite.db.NewSelect().Model((*ScopedUser)(nil)).Count(suite.ctx)
+		suite.Require().NoError(err)
+		suite.Equal(int64(2), count, "Count should apply the default scope")
+
+		exists, err := suite.db.NewSelect().Model((*ScopedUser)(nil)).Where(func(cb ConditionBuilder) {
+			cb.Equals("name", "Charlie Brown")
+		}).Exists(suite.ctx)
+		suite.Require().NoError(err)
+		suite.False(exists, "Exists should apply the default scope")
+	})
+
+	suite.Run("Unscoped", func() {
+		var users []ScopedUser
+
+		err := suite.db.NewSelect().Model(&users).Unscoped().OrderBy("name").Scan(suite.ctx)
+		suite.Require().NoError(err)
+		suite.Require().Len(users, 3, "Unscoped should include inactive users")
+		suite.Equal("Charlie Brown", users[2].Name)
+	})
+
+	suite.Run("SubQuery", func() {
+		var posts []Post
+
+		err := suite.db.NewSelect().Model(&posts).Where(func(cb ConditionBuilder) {
+			cb.InSubQuery("user_id", func(query SelectQuery) {
+				query.Model((*ScopedUser)(nil)).Select("id")
+			})
+		}).Scan(suite.ctx)
+		suite.Require().NoError(err)
+
+		var users []User
+
+		err = suite.db.NewSelect().Model(&users).Where(func(cb ConditionBuilder) {
+			cb.IsFalse("is_active")
+		}).Scan(suite.ctx)
+		suite.Require().NoError(err)
+		suite.Require().Len(users, 1)
+
+		for _, post := range posts {
+			suite.NotEqual(users[0].ID, post.UserID, "Subquery should apply the default scope")
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package orm
+
+// Spec is a reusable query predicate, e.g. a domain rule like "active customers", defined once and applied to
+// queries with WhereSpec. Specs are combined with And, Or and Not; every combined spec is grouped, so the Or
+// conditions of one spec never change the meaning of another.
+type Spec ApplyFunc[ConditionBuilder]
+
+// And returns a spec matching the rows matched by all of the specs. Nil specs are ignored.
+func And(specs ...Spec) Spec {
+	return func(cb ConditionBuilder) {
+		for _, spec := range specs {
+			if spec != nil {
+				cb.Group(spec)
+			}
+		}
+	}
+}
+
+// Or returns a spec matching the rows matched by any of the specs. Nil specs are ignored.
+func Or(specs ...Spec) Spec {
+	return func(cb ConditionBuilder) {
+		cb.Group(func(cb ConditionBuilder) {
+			for _, spec := range specs {
+				if spec != nil {
+					cb.OrGroup(spec)
+				}
+			}
+		})
+	}
+}
+
+// Not returns a spec matching the rows not matched by the spec. A nil spec matches all rows.
+func Not(spec Spec) Spec {
+	return func(cb ConditionBuilder) {
+		if spec != nil {
+			cb.NotGroup(spec)
+		}
+	}
+}
+
+// And returns a spec matching the rows matched by the spec and all of the others.
+func (s Spec) And(others ...Spec) Spec {
+	return And(append([]Spec{s}, others...)...)
+}
+
+// Or returns a spec matching the rows matched by the spec or any of the others.
+func (s Spec) Or(others ...Spec) Spec {
+	return Or(append([]Spec{s}, others...)...)
+}
+
+// Not returns a spec matching the rows not matched by the spec.
+func (s Spec) Not() Spec {
+	return Not(s)
+}
@@ -0,0 +1,98 @@
+package orm
+
+// SpecTestSuite tests composing specs and applying them with WhereSpec.
+type SpecTestSuite struct {
+	*ConditionBuilderTestSuite
+}
+
+var (
+	activeSpec Spec = func(cb ConditionBuilder) {
+		cb.Equals("is_active", true)
+	}
+	youngOrOldSpec Spec = func(cb ConditionBuilder) {
+		cb.LessThan("age", 26).
+			OrGreaterThan("age", 34)
+	}
+)
+
+func (suite *SpecTestSuite) names(query SelectQuery) []string {
+	users := suite.assertQueryReturnsUsers(query.OrderBy("age"))
+
+	names := make([]string, len(users))
+	for i, user := range users {
+		names[i] = user.Name
+	}
+
+	return names
+}
+
+func (suite *SpecTestSuite) TestWhereSpec() {
+	suite.Run("SingleSpec", func() {
+		suite.Equal(
+			[]string{"Bob Smith", "Alice Johnson"},
+			suite.names(suite.db.NewSelect().Model((*User)(nil)).WhereSpec(activeSpec)),
+		)
+	})
+
+	suite.Run("GroupedWithWhere", func() {
+		// The Or of the spec must not bypass the preceding condition.
+		names := suite.names(suite.db.NewSelect().
+			Model((*User)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("is_active", true)
+			}).
+			WhereSpec(youngOrOldSpec))
+
+		suite.Equal([]string{"Bob Smith"}, names)
+	})
+
+	suite.Run("NilSpec", func() {
+		suite.Len(suite.names(suite.db.NewSelect().Model((*User)(nil)).WhereSpec(nil)), 3, "Should match all rows")
+	})
+}
+
+func (suite *SpecTestSuite) TestCompose() {
+	suite.Run("And", func() {
+		names := suite.names(suite.db.NewSelect().Model((*User)(nil)).WhereSpec(And(activeSpec, youngOrOldSpec)))
+		suite.Equal([]string{"Bob Smith"}, names)
+	})
+
+	suite.Run("Or", func() {
+		names := suite.names(suite.db.NewSelect().Model((*User)(nil)).WhereSpec(Or(activeSpec, youngOrOldSpec)))
+		suite.Equal([]string{"Bob Smith", "Alice Johnson", "Charlie Brown"}, names)
+	})
+
+	suite.Run("Not", func() {
+		names := suite.names(suite.db.NewSelect().Model((*User)(nil)).WhereSpec(Not(youngOrOldSpec)))
+		suite.Equal([]string{"Alice Johnson"}, names)
+	})
+
+	suite.Run("Methods", func() {
+		names := suite.names(suite.db.NewSelect().Model((*User)(nil)).WhereSpec(activeSpec.Not().Or(youngOrOldSpec.Not())))
+		suite.Equal([]string{"Alice Johnson", "Charlie Brown"}, names)
+	})
+
+	suite.Run("NilSpecsIgnored", func() {
+		names := suite.names(suite.db.NewSelect().Model((*User)(nil)).WhereSpec(And(nil, activeSpec, Not(nil))))
+		suite.Equal([]string{"Bob Smith", "Alice Johnson"}, names)
+	})
+}
+
+func (suite *SpecTestSuite) TestUpdateAndDelete() {
+	result, err := suite.db.NewUpdate().
+		Model((*User)(nil)).
+		Set("updated_by", "spec_test").
+		WhereSpec(Not(activeSpec).And(youngOrOldSpec)).
+		Exec(suite.ctx)
+	suite.Require().NoError(err, "Update should execute successfully")
+
+	rowsAffected, _ := result.RowsAffected()
+	suite.Equal(int64(1), rowsAffected, "Should update the inactive user only")
+
+	_, err = suite.db.NewDelete().
+		Model((*User)(nil)).
+		WhereSpec(And(activeSpec, Not(activeSpec))).
+		Exec(suite.ctx)
+	suite.Require().NoError(err, "Delete should execute successfully")
+	suite.Len(suite.names(suite.db.NewSelect().Model((*User)(nil))), 3, "Should delete nothing with contradicting specs")
+}
@@ -0,0 +1,407 @@
+package orm
+
+import (
+	"cmp"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/spf13/cast"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/strhelpers"
+)
+
+const (
+	// TagSummary is the struct tag declaring a column maintained as an aggregate of the rows of a has-many relation.
+	// The default value names the relation, the rows are counted unless a column to sum is given.
+	// Example: `summary:"Posts"` or `summary:"Items,sum=amount"`.
+	TagSummary = "summary"
+	// AttrSummarySum names the column of the related rows summed instead of counting them.
+	AttrSummarySum = "sum"
+
+	// summaryAlias aliases the related table in recomputations, which may be the summarized table itself.
+	summaryAlias = "_summary"
+)
+
+// summary is a summary column declared on a model.
+type summary struct {
+	table  *schema.Table
+	column string
+	rel    *schema.Relation
+	sum    string // Summed column of the related rows, empty to count them
+}
+
+var (
+	// summaryMu guards summaries.
+	summaryMu sync.RWMutex
+	// summaries holds the declared summary columns keyed by the name of the table of their related rows,
+	// then by the summarized table and column.
+	summaries = make(map[string]map[string]*summary)
+)
+
+// RegisterSummaries registers the summary columns declared through summary tags on the given models.
+// Inserts and deletes of related rows through the orm, including soft deletes and cascading deletes, then adjust
+// the summary columns in the same transaction. Upserts, restores and updates moving related rows between
+// parents are not tracked, RecomputeSummaries repairs the columns after them.
+func RegisterSummaries(db DB, models ...any) error {
+	for _, model := range models {
+		table := db.TableOf(model)
+
+		for _, field := range table.Fields {
+			tag, ok := field.StructField.Tag.Lookup(TagSummary)
+			if !ok {
+				continue
+			}
+
+			attrs := strhelpers.ParseTag(tag)
+
+			rel := table.Relations[attrs[strhelpers.DefaultKey]]
+			if rel == nil || rel.Type != schema.HasManyRelation {
+				return fmt.Errorf("%w: %s.%s must name a has-many relation, got %q",
+					ErrInvalidSummary, table.Name, field.Name, attrs[strhelpers.DefaultKey])
+			}
+
+			sum := attrs[AttrSummarySum]
+			if _, ok := rel.JoinTable.FieldMap[sum]; sum != constants.Empty && !ok {
+				return fmt.Errorf("%w: %s.%s sums unknown column %s.%s",
+					ErrInvalidSummary, table.Name, field.Name, rel.JoinTable.Name, sum)
+			}
+
+			summaryMu.Lock()
+
+			if summaries[rel.JoinTable.Name] == nil {
+				summaries[rel.JoinTable.Name] = make(map[string]*summary)
+			}
+
+			summaries[rel.JoinTable.Name][table.Name+constants.Dot+field.Name] = &summary{
+				table:  table,
+				column: field.Name,
+				rel:    rel,
+				sum:    sum,
+			}
+
+			summaryMu.Unlock()
+		}
+	}
+
+	return nil
+}
+
+// RecomputeSummaries recomputes the registered summary columns of the given models from their related rows,
+// repairing drift left by changes the incremental maintenance does not track.
+func RecomputeSummaries(ctx context.Context, db DB, models ...any) error {
+	bunDB, ok := db.(*BunDB)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrDialectUnsupportedOperation, db)
+	}
+
+	return bunDB.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, model := range models {
+			table := db.TableOf(model)
+
+			for _, s := range summariesOf(table.Name) {
+				if err := s.recompute(ctx, tx); err != nil {
+					return fmt.Errorf("failed to recompute summary column %s.%s: %w", table.Name, s.column, err)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// summariesOf returns the summary columns declared on the given table, sorted by column.
+func summariesOf(table string) []*summary {
+	summaryMu.RLock()
+	defer summaryMu.RUnlock()
+
+	var result []*summary
+
+	for _, related := range summaries {
+		for _, s := range related {
+			if s.table.Name == table {
+				result = append(result, s)
+			}
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *summary) int {
+		return cmp.Compare(a.column, b.column)
+	})
+
+	return result
+}
+
+// summariesOver returns the summary columns aggregating the rows of the given table.
+func summariesOver(table *schema.Table) []*summary {
+	if table == nil {
+		return nil
+	}
+
+	summaryMu.RLock()
+	defer summaryMu.RUnlock()
+
+	result := make([]*summary, 0, len(summaries[table.Name]))
+	for _, s := range summaries[table.Name] {
+		result = append(result, s)
+	}
+
+	return result
+}
+
+// summaryColumns returns the columns of related rows the given summaries read.
+func summaryColumns(summaries []*summary) []string {
+	var columns []string
+
+	add := func(column string) {
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+
+	for _, s := range summaries {
+		for _, field := range s.rel.JoinPKs {
+			add(field.Name)
+		}
+
+		if s.rel.PolymorphicField != nil {
+			add(s.rel.PolymorphicField.Name)
+		}
+
+		if s.sum != constants.Empty {
+			add(s.sum)
+		}
+	}
+
+	return columns
+}
+
+// modelRows reads the given columns of the live rows of an inserted model, a struct or a slice of structs.
+func modelRows(table *schema.Table, columns []string, model any) []map[string]any {
+	value := reflect.Indirect(reflect.ValueOf(model))
+
+	var elems []reflect.Value
+
+	if value.Kind() == reflect.Slice {
+		for i := range value.Len() {
+			elems = append(elems, reflect.Indirect(value.Index(i)))
+		}
+	} else {
+		elems = append(elems, value)
+	}
+
+	rows := make([]map[string]any, 0, len(elems))
+
+	for _, elem := range elems {
+		if !elem.IsValid() || elem.Kind() != reflect.Struct {
+			continue
+		}
+
+		if table.SoftDeleteField != nil && !table.SoftDeleteField.HasZeroValue(elem) {
+			continue
+		}
+
+		row := make(map[string]any, len(columns))
+
+		for _, column := range columns {
+			if field := table.FieldMap[column]; field != nil && !field.HasZeroValue(elem) {
+				row[column] = field.Value(elem).Interface()
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// whereLive restricts a select query to the rows not soft deleted.
+func whereLive(field *schema.Field) ApplyFunc[SelectQuery] {
+	return func(query SelectQuery) {
+		query.Where(func(cb ConditionBuilder) {
+			if field.IsPtr || field.NullZero {
+				cb.IsNull(field.Name)
+			} else {
+				cb.Equals(field.Name, reflect.Zero(field.IndirectType).Interface())
+			}
+		})
+	}
+}
+
+// summaryDelta is the change of the summary column of a row.
+type summaryDelta struct {
+	key   []any
+	value float64
+}
+
+// summaryDeltas accumulates the changes of summary columns by summary and key of the summarized row.
+type summaryDeltas map[*summary]map[string]*summaryDelta
+
+// add adds the contribution of related rows to the summary columns, with sign -1 for removed rows.
+func (d summaryDeltas) add(summaries []*summary, rows []map[string]any, sign float64) error {
+	for _, s := range summaries {
+		for _, row := range rows {
+			if s.rel.PolymorphicField != nil &&
+				fmt.Sprint(row[s.rel.PolymorphicField.Name]) != fmt.Sprint(s.rel.PolymorphicValue) {
+				continue
+			}
+
+			key := make([]any, len(s.rel.JoinPKs))
+			for i, field := range s.rel.JoinPKs {
+				key[i] = row[field.Name]
+			}
+
+			if slices.Contains(key, nil) {
+				continue
+			}
+
+			value := 1.0
+			if s.sum != constants.Empty {
+				var err error
+				if value, err = summaryValue(row[s.sum]); err != nil {
+					return fmt.Errorf("failed to sum %s.%s: %w", s.rel.JoinTable.Name, s.sum, err)
+				}
+			}
+
+			if d[s] == nil {
+				d[s] = make(map[string]*summaryDelta)
+			}
+
+			id := fmt.Sprintf("%#v", key)
+			if d[s][id] == nil {
+				d[s][id] = &summaryDelta{key: key}
+			}
+
+			d[s][id].value += sign * value
+		}
+	}
+
+	return nil
+}
+
+// apply adds the accumulated changes to the summary columns, with one statement per distinct change and batch.
+func (d summaryDeltas) apply(ctx context.Context, db bun.IDB) error {
+	for s, deltas := range d {
+		groups := make(map[float64][][]any)
+		for _, delta := range deltas {
+			if delta.value != 0 {
+				groups[delta.value] = append(groups[delta.value], delta.key)
+			}
+		}
+
+		for value, keys := range groups {
+			for batch := range slices.Chunk(keys, cascadeBatchSize) {
+				query := db.NewUpdate().
+					Model(s.table.ZeroIface).
+					Set("? = COALESCE(?, 0) + ?", bun.Ident(s.column), bun.Ident(s.column), value)
+				if s.table.SoftDeleteField != nil {
+					query.WhereAllWithDeleted()
+				}
+
+				whereKeys(query, s.rel.BasePKs, batch)
+
+				if _, err := query.Exec(ctx); err != nil {
+					return fmt.Errorf("failed to update summary column %s.%s: %w", s.table.Name, s.column, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// whereKeys restricts an update to the rows whose columns equal one of the keys.
+func whereKeys(query *bun.UpdateQuery, fields []*schema.Field, keys [][]any) {
+	if len(fields) == 1 {
+		values := make([]any, len(keys))
+		for i, key := range keys {
+			values[i] = key[0]
+		}
+
+		query.Where("?TableAlias.? IN (?)", bun.Ident(fields[0].Name), bun.In(values))
+
+		return
+	}
+
+	query.WhereGroup(" AND ", func(query *bun.UpdateQuery) *bun.UpdateQuery {
+		for _, key := range keys {
+			query.WhereGroup(" OR ", func(query *bun.UpdateQuery) *bun.UpdateQuery {
+				for i, field := range fields {
+					query.Where("?TableAlias.? = ?", bun.Ident(field.Name), key[i])
+				}
+
+				return query
+			})
+		}
+
+		return query
+	})
+}
+
+// recompute sets the summary column of all rows to the aggregate of their live related rows.
+func (s *summary) recompute(ctx context.Context, db bun.IDB) error {
+	join := s.rel.JoinTable
+
+	aggregate := db.NewSelect().TableExpr("? AS ?", bun.Ident(join.Name), bun.Ident(summaryAlias))
+	if s.sum == constants.Empty {
+		aggregate.ColumnExpr("COUNT(*)")
+	} else {
+		aggregate.ColumnExpr("COALESCE(SUM(?.?), 0)", bun.Ident(summaryAlias), bun.Ident(s.sum))
+	}
+
+	for i, field := range s.rel.JoinPKs {
+		aggregate.Where("?.? = ?.?",
+			bun.Ident(summaryAlias), bun.Ident(field.Name),
+			bun.Ident(s.table.Alias), bun.Ident(s.rel.BasePKs[i].Name))
+	}
+
+	if s.rel.PolymorphicField != nil {
+		aggregate.Where("?.? = ?", bun.Ident(summaryAlias), bun.Ident(s.rel.PolymorphicField.Name), s.rel.PolymorphicValue)
+	}
+
+	if field := join.SoftDeleteField; field != nil {
+		if field.IsPtr || field.NullZero {
+			aggregate.Where("?.? IS NULL", bun.Ident(summaryAlias), bun.Ident(field.Name))
+		} else {
+			aggregate.Where("?.? = ?", bun.Ident(summaryAlias), bun.Ident(field.Name),
+				reflect.Zero(field.IndirectType).Interface())
+		}
+	}
+
+	query := db.NewUpdate().
+		Model(s.table.ZeroIface).
+		Set("? = (?)", bun.Ident(s.column), aggregate).
+		Where("1 = 1")
+	if s.table.SoftDeleteField != nil {
+		query.WhereAllWithDeleted()
+	}
+
+	_, err := query.Exec(ctx)
+
+	return err
+}
+
+// summaryValue converts a summed value, e.g. an int64, a decimal or the bytes of a numeric column, to a float.
+func summaryValue(value any) (float64, error) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		var err error
+		if value, err = valuer.Value(); err != nil {
+			return 0, err
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case []byte:
+		value = string(v)
+	}
+
+	return cast.ToFloat64E(value)
+}
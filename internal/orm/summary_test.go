@@ -0,0 +1,142 @@
+package orm
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type SummaryComment struct {
+	bun.BaseModel `bun:"table:test_summary_comment,alias:tsc"`
+	Model
+
+	ArticleID string    `json:"articleId" bun:"article_id,notnull"`
+	Likes     int64     `json:"likes"     bun:"likes,notnull"`
+	DeletedAt time.Time `json:"deletedAt" bun:",soft_delete,nullzero"`
+}
+
+type SummaryArticle struct {
+	bun.BaseModel `bun:"table:test_summary_article,alias:tsa"`
+	Model
+
+	Title        string            `json:"title"        bun:"title,notnull"`
+	CommentCount int64             `json:"commentCount" bun:"comment_count,notnull,default:0"   summary:"Comments"`
+	TotalLikes   int64             `json:"totalLikes"   bun:"total_likes,notnull,default:0"     summary:"Comments,sum=likes"`
+	Comments     []*SummaryComment `json:"comments"     bun:"rel:has-many,join:id=article_id"`
+}
+
+// SummaryTestSuite tests the maintenance of summary columns.
+type SummaryTestSuite struct {
+	*OrmTestSuite
+}
+
+func (suite *SummaryTestSuite) SetupSuite() {
+	bunDB := suite.getBunDB()
+	for _, model := range []any{(*SummaryArticle)(nil), (*SummaryComment)(nil)} {
+		_, err := bunDB.NewDropTable().Model(model).IfExists().Exec(suite.ctx)
+		suite.Require().NoError(err, "Should drop existing summary table")
+
+		_, err = bunDB.NewCreateTable().Model(model).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should create summary table")
+	}
+
+	suite.Require().NoError(RegisterSummaries(suite.db, (*SummaryArticle)(nil)), "Should register summaries")
+}
+
+func (suite *SummaryTestSuite) TearDownSuite() {
+	bunDB := suite.getBunDB()
+	for _, model := range []any{(*SummaryArticle)(nil), (*SummaryComment)(nil)} {
+		_, err := bunDB.NewDropTable().Model(model).IfExists().Exec(suite.ctx)
+		suite.NoError(err, "Should cleanup summary table")
+	}
+}
+
+func (suite *SummaryTestSuite) article(id string) *SummaryArticle {
+	article := new(SummaryArticle)
+	err := suite.db.NewSelect().
+		Model(article).
+		Where(func(cb ConditionBuilder) {
+			cb.PKEquals(id)
+		}).
+		Scan(suite.ctx)
+	suite.Require().NoError(err, "Should read article")
+
+	return article
+}
+
+func (suite *SummaryTestSuite) TestMaintenance() {
+	suite.T().Logf("Testing summary maintenance for %s", suite.dbType)
+
+	first := &SummaryArticle{Title: "First"}
+	second := &SummaryArticle{Title: "Second"}
+	_, err := suite.db.NewInsert().Model(&[]*SummaryArticle{first, second}).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert articles")
+
+	comments := []*SummaryComment{
+		{ArticleID: first.ID, Likes: 3},
+		{ArticleID: first.ID, Likes: 5},
+		{ArticleID: second.ID, Likes: 2},
+	}
+	_, err = suite.db.NewInsert().Model(&comments).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert comments")
+
+	suite.Run("Insert", func() {
+		suite.Equal(int64(2), suite.article(first.ID).CommentCount)
+		suite.Equal(int64(8), suite.article(first.ID).TotalLikes)
+		suite.Equal(int64(1), suite.article(second.ID).CommentCount)
+		suite.Equal(int64(2), suite.article(second.ID).TotalLikes)
+	})
+
+	suite.Run("SoftDelete", func() {
+		_, err := suite.db.NewDelete().Model(comments[0]).WherePK().Exec(suite.ctx)
+		suite.Require().NoError(err, "Should soft delete comment")
+
+		suite.Equal(int64(1), suite.article(first.ID).CommentCount)
+		suite.Equal(int64(5), suite.article(first.ID).TotalLikes)
+
+		// Force deleting the soft deleted comment must not count it twice
+		_, err = suite.db.NewDelete().Model(comments[0]).WherePK().ForceDelete().IncludeDeleted().Exec(suite.ctx)
+		suite.Require().NoError(err, "Should force delete comment")
+
+		suite.Equal(int64(1), suite.article(first.ID).CommentCount)
+	})
+
+	suite.Run("CascadingDelete", func() {
+		_, err := suite.db.NewDelete().Model(second).WherePK().Cascade().Exec(suite.ctx)
+		suite.Require().NoError(err, "Should cascade delete article")
+
+		count, err := suite.db.NewSelect().
+			Model((*SummaryComment)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("article_id", second.ID)
+			}).
+			Count(suite.ctx)
+		suite.Require().NoError(err, "Should count comments")
+		suite.Equal(int64(0), count, "Comments of the article should be deleted")
+		suite.Equal(int64(1), suite.article(first.ID).CommentCount, "Other articles should be unaffected")
+	})
+
+	suite.Run("Recompute", func() {
+		_, err := suite.getBunDB().NewUpdate().
+			Model((*SummaryArticle)(nil)).
+			Set("comment_count = 42, total_likes = 42").
+			Where("1 = 1").
+			Exec(suite.ctx)
+		suite.Require().NoError(err, "Should corrupt summaries")
+
+		suite.Require().NoError(RecomputeSummaries(suite.ctx, suite.db, (*SummaryArticle)(nil)), "Should recompute summaries")
+		suite.Equal(int64(1), suite.article(first.ID).CommentCount)
+		suite.Equal(int64(5), suite.article(first.ID).TotalLikes)
+	})
+}
+
+func (suite *SummaryTestSuite) TestInvalidDeclaration() {
+	type InvalidSummary struct {
+		bun.BaseModel `bun:"table:test_summary_invalid"`
+		Model
+
+		Count int64 `bun:"count" summary:"Missing"`
+	}
+
+	suite.ErrorIs(RegisterSummaries(suite.db, (*InvalidSummary)(nil)), ErrInvalidSummary)
+}
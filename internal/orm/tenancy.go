@@ -0,0 +1,296 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database/txcommit"
+)
+
+// TenancyStrategy is how the data of tenants is isolated.
+type TenancyStrategy string
+
+const (
+	// TenancyRowPerTenant keeps the rows of all tenants in shared tables holding the tenant in a column.
+	TenancyRowPerTenant TenancyStrategy = "row"
+	// TenancySchemaPerTenant keeps the tables of each tenant in a schema of its own, PostgreSQL only.
+	TenancySchemaPerTenant TenancyStrategy = "schema"
+)
+
+// defaultTenantSchemaPrefix prefixes the tenant ID to name the schema of the tenant.
+const defaultTenantSchemaPrefix = "tenant_"
+
+// tenantIDPattern restricts the tenant IDs of schema-per-tenant tenancy to those naming a schema safely.
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// TenantResolver extracts the tenant a request acts for from its context.
+type TenantResolver interface {
+	// ResolveTenant returns the ID of the tenant, empty for requests acting for no tenant, e.g. system jobs.
+	ResolveTenant(ctx context.Context) string
+}
+
+// TenantResolverFunc adapts a function to the TenantResolver interface.
+type TenantResolverFunc func(ctx context.Context) string
+
+func (f TenantResolverFunc) ResolveTenant(ctx context.Context) string {
+	return f(ctx)
+}
+
+// TenantDBOpener opens a database whose unqualified table names resolve in the given schema first,
+// e.g. a connection pool with the schema leading its search_path.
+type TenantDBOpener func(schema string) (*bun.DB, error)
+
+// Tenancy isolates the data of the tenants resolved from the request context on a DB it is registered on with
+// WithTenancy. Under row-per-tenant tenancy selects, updates and deletes of tables having the tenant column are
+// restricted to the rows of the tenant and inserts store the tenant, upserts only updating conflicting rows of the
+// tenant; under schema-per-tenant tenancy statements run on a connection pool of the schema of the tenant and
+// transactions put that schema first in their search_path. Requests without tenant are not isolated unless RequireTenant is set. Like data scopes, only the model table of
+// the executed statement is filtered by the tenant column; joined relations and subqueries are not.
+type Tenancy struct {
+	strategy     TenancyStrategy
+	resolver     TenantResolver
+	column       string
+	schemaPrefix string
+	open         TenantDBOpener
+	required     bool
+
+	mu    sync.Mutex
+	pools map[string]*bun.DB
+}
+
+// NewRowTenancy creates a row-per-tenant tenancy storing the tenant in the column, tenant_id when empty.
+func NewRowTenancy(resolver TenantResolver, column string) *Tenancy {
+	if column == constants.Empty {
+		column = constants.ColumnTenantID
+	}
+
+	return &Tenancy{
+		strategy: TenancyRowPerTenant,
+		resolver: resolver,
+		column:   column,
+	}
+}
+
+// NewSchemaTenancy creates a schema-per-tenant tenancy keeping the tables of each tenant in the schema named by the
+// prefix, tenant_ when empty, followed by the tenant ID. The connection pool of a schema is opened on first use.
+func NewSchemaTenancy(resolver TenantResolver, schemaPrefix string, open TenantDBOpener) *Tenancy {
+	if schemaPrefix == constants.Empty {
+		schemaPrefix = defaultTenantSchemaPrefix
+	}
+
+	return &Tenancy{
+		strategy:     TenancySchemaPerTenant,
+		resolver:     resolver,
+		schemaPrefix: schemaPrefix,
+		open:         open,
+		pools:        make(map[string]*bun.DB),
+	}
+}
+
+// RequireTenant makes the statements of requests without tenant fail with ErrTenantRequired,
+// rather than running on the rows of all tenants or the shared schema.
+func (t *Tenancy) RequireTenant() *Tenancy {
+	t.required = true
+
+	return t
+}
+
+// Strategy returns the strategy isolating the tenants.
+func (t *Tenancy) Strategy() TenancyStrategy {
+	return t.strategy
+}
+
+// Column returns the tenant column of row-per-tenant tenancy.
+func (t *Tenancy) Column() string {
+	return t.column
+}
+
+// SchemaOf returns the schema of the tenant under schema-per-tenant tenancy, e.g. to create it in a migration.
+func (t *Tenancy) SchemaOf(tenantID string) (string, error) {
+	if !tenantIDPattern.MatchString(tenantID) {
+		return constants.Empty, fmt.Errorf("%w: %q", ErrInvalidTenant, tenantID)
+	}
+
+	return t.schemaPrefix + tenantID, nil
+}
+
+// Close closes the connection pools of the tenant schemas.
+func (t *Tenancy) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var errs []error
+	for schema, pool := range t.pools {
+		if err := pool.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close the pool of schema %s: %w", schema, err))
+		}
+	}
+
+	clear(t.pools)
+
+	return errors.Join(errs...)
+}
+
+// tenant resolves the tenant of the request, failing for requests without tenant when one is required.
+func (t *Tenancy) tenant(ctx context.Context) (string, error) {
+	tenantID := t.resolver.ResolveTenant(ctx)
+	if tenantID == constants.Empty && t.required {
+		return constants.Empty, ErrTenantRequired
+	}
+
+	return tenantID, nil
+}
+
+// pool returns the connection pool of the schema of the tenant, opening it on first use.
+func (t *Tenancy) pool(tenantID string) (*bun.DB, error) {
+	schema, err := t.SchemaOf(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if pool, ok := t.pools[schema]; ok {
+		return pool, nil
+	}
+
+	pool, err := t.open(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the pool of schema %s: %w", schema, err)
+	}
+
+	t.pools[schema] = pool
+
+	return pool, nil
+}
+
+// filters reports whether the rows of the table are restricted to the tenant.
+func (t *Tenancy) filters(table *schema.Table) bool {
+	return t.filtersRows() && table != nil && table.HasField(t.column)
+}
+
+// filtersRows reports whether the tenancy restricts the rows of tables to the tenant, which tables it applies to
+// being known only at execution.
+func (t *Tenancy) filtersRows() bool {
+	return t != nil && t.strategy == TenancyRowPerTenant
+}
+
+// conn returns the database the statements of the request run on: the pool of the schema of its tenant outside
+// transactions under schema-per-tenant tenancy, the wrapped database otherwise. Transactions begun on the wrapped
+// database switch the schema themselves, see runInTx.
+func (d *BunDB) conn(ctx context.Context) (bun.IDB, string, error) {
+	if d.tenancy == nil {
+		return d.db, constants.Empty, nil
+	}
+
+	tenantID, err := d.tenancy.tenant(ctx)
+	if err != nil || tenantID == constants.Empty || d.tenancy.strategy != TenancySchemaPerTenant {
+		return d.db, tenantID, err
+	}
+
+	if _, ok := d.db.(bun.Tx); ok {
+		return d.db, tenantID, nil
+	}
+
+	pool, err := d.tenancy.pool(tenantID)
+	if err != nil {
+		return nil, constants.Empty, err
+	}
+
+	return pool, tenantID, nil
+}
+
+// runInTx runs fn in a transaction switched to the schema of the tenant of the request under schema-per-tenant tenancy.
+// The transaction is begun on the wrapped database, so it keeps its named arguments and query hooks.
+// Functions registered with AfterCommit run once it committed.
+func (d *BunDB) runInTx(ctx context.Context, opts *sql.TxOptions, fn func(context.Context, bun.Tx) error) error {
+	return txcommit.Collect(ctx, func(ctx context.Context) error {
+		return d.runInTenantTx(ctx, opts, fn)
+	})
+}
+
+// runInTenantTx begins the transaction of runInTx.
+func (d *BunDB) runInTenantTx(ctx context.Context, opts *sql.TxOptions, fn func(context.Context, bun.Tx) error) error {
+	if d.tenancy == nil || d.tenancy.strategy != TenancySchemaPerTenant {
+		return d.db.RunInTx(ctx, opts, fn)
+	}
+
+	tenantID, err := d.tenancy.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	if tenantID == constants.Empty {
+		return d.db.RunInTx(ctx, opts, fn)
+	}
+
+	schema, err := d.tenancy.SchemaOf(tenantID)
+	if err != nil {
+		return err
+	}
+
+	return d.db.RunInTx(ctx, opts, func(ctx context.Context, tx bun.Tx) error {
+		if tx.Dialect().Name() != dialect.PG {
+			return fmt.Errorf("%w: schema-per-tenant tenancy on %s", ErrDialectUnsupportedOperation, tx.Dialect().Name())
+		}
+
+		// Prepends the schema for the transaction only, keeping the shared schemas of the connection resolvable
+		if _, err := tx.ExecContext(
+			ctx,
+			"SELECT set_config('search_path', ? || ', ' || current_setting('search_path'), true)",
+			string(dialect.AppendIdent(nil, schema, tx.Dialect().IdentQuote())),
+		); err != nil {
+			return fmt.Errorf("failed to switch to schema %s: %w", schema, err)
+		}
+
+		return fn(ctx, tx)
+	})
+}
+
+// applyTenancy routes the statement to the database of the tenant of the request and, under row-per-tenant tenancy,
+// restricts the rows of the model table to the tenant. It returns the tenant, empty when the request has none.
+func (d *BunDB) applyTenancy(ctx context.Context, query bun.Query, table *schema.Table, cb ConditionBuilder) (string, error) {
+	conn, tenantID, err := d.conn(ctx)
+	if err != nil {
+		return constants.Empty, err
+	}
+
+	// Overriding the connection of every query would make bun run the scans of ScanAndCount sequentially
+	if d.tenancy != nil && d.tenancy.strategy == TenancySchemaPerTenant {
+		setConn(query, conn)
+	}
+
+	if tenantID != constants.Empty && cb != nil && d.tenancy.filters(table) {
+		cb.Equals(d.tenancy.column, tenantID)
+	}
+
+	return tenantID, nil
+}
+
+// setConn sets the connection the bun query runs on.
+func setConn(query bun.Query, conn bun.IConn) {
+	switch q := query.(type) {
+	case *bun.SelectQuery:
+		q.Conn(conn)
+	case *bun.InsertQuery:
+		q.Conn(conn)
+	case *bun.UpdateQuery:
+		q.Conn(conn)
+	case *bun.DeleteQuery:
+		q.Conn(conn)
+	case *bun.MergeQuery:
+		q.Conn(conn)
+	case *bun.RawQuery:
+		q.Conn(conn)
+	}
+}
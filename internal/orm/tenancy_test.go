@@ -0,0 +1,368 @@
+package orm
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+)
+
+type TenantNote struct {
+	bun.BaseModel `bun:"table:test_tenant_note,alias:ttn"`
+	Model
+
+	Title    string  `json:"title"    bun:"title,notnull"`
+	TenantID string  `json:"tenantId" bun:"tenant_id,notnull"`
+	ParentID *string `json:"parentId" bun:"parent_id"`
+
+	Parent   *TenantNote   `json:"parent"   bun:"rel:belongs-to,join:parent_id=id"`
+	Children []*TenantNote `json:"children" bun:"rel:has-many,join:id=parent_id"`
+}
+
+type tenantContextKey struct{}
+
+// tenantResolver reads the tenant the tests put on the context.
+var tenantResolver = TenantResolverFunc(func(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantContextKey{}).(string)
+
+	return tenantID
+})
+
+// TenancyTestSuite tests the isolation of the data of tenants by the tenancy of the DB.
+type TenancyTestSuite struct {
+	*OrmTestSuite
+
+	tenanted DB
+}
+
+func (suite *TenancyTestSuite) SetupSuite() {
+	bunDB := suite.getBunDB()
+
+	_, err := bunDB.NewDropTable().Model((*TenantNote)(nil)).IfExists().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should drop existing tenancy test table")
+
+	_, err = bunDB.NewCreateTable().Model((*TenantNote)(nil)).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should create tenancy test table")
+
+	suite.tenanted = suite.db.WithTenancy(NewRowTenancy(tenantResolver, constants.Empty))
+}
+
+func (suite *TenancyTestSuite) TearDownSuite() {
+	_, err := suite.getBunDB().NewDropTable().Model((*TenantNote)(nil)).IfExists().Exec(suite.ctx)
+	suite.NoError(err, "Should cleanup tenancy test table")
+}
+
+func (suite *TenancyTestSuite) SetupTest() {
+	_, err := suite.db.NewDelete().Model((*TenantNote)(nil)).AllowFullTable().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should clear tenancy test table")
+
+	for _, note := range []struct{ tenant, title string }{
+		{"acme", "Acme roadmap"},
+		{"acme", "Acme budget"},
+		{"globex", "Globex roadmap"},
+	} {
+		_, err := suite.tenanted.NewInsert().
+			Model(&TenantNote{Title: note.title}).
+			Exec(suite.withTenant(note.tenant))
+		suite.Require().NoError(err, "Should insert the notes of the tenant")
+	}
+}
+
+func (suite *TenancyTestSuite) withTenant(tenantID string) context.Context {
+	return context.WithValue(suite.ctx, tenantContextKey{}, tenantID)
+}
+
+func (suite *TenancyTestSuite) titles(ctx context.Context, db DB) []string {
+	var titles []string
+
+	suite.Require().NoError(
+		db.NewSelect().Model((*TenantNote)(nil)).Select("title").OrderBy("title").Scan(ctx, &titles),
+		"Should select the notes",
+	)
+
+	return titles
+}
+
+func (suite *TenancyTestSuite) TestRowIsolation() {
+	suite.Run("InsertStoresTenant", func() {
+		_, err := suite.tenanted.NewInsert().
+			Model(&TenantNote{Title: "Acme forged", TenantID: "globex"}).
+			Exec(suite.withTenant("acme"))
+		suite.Require().NoError(err)
+
+		var note TenantNote
+		suite.Require().NoError(suite.db.NewSelect().
+			Model(&note).
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("title", "Acme forged")
+			}).
+			Scan(suite.ctx))
+		suite.Equal("acme", note.TenantID, "Inserts should store the tenant of the request")
+	})
+
+	suite.Run("SelectFiltersTenant", func() {
+		suite.Equal([]string{"Acme budget", "Acme forged", "Acme roadmap"}, suite.titles(suite.withTenant("acme"), suite.tenanted))
+		suite.Equal([]string{"Globex roadmap"}, suite.titles(suite.withTenant("globex"), suite.tenanted))
+		suite.Len(suite.titles(suite.ctx, suite.tenanted), 4, "Requests without tenant should not be isolated")
+	})
+
+	suite.Run("UpdateAndDeleteFilterTenant", func() {
+		res, err := suite.tenanted.NewUpdate().
+			Model((*TenantNote)(nil)).
+			Set("title", "Renamed").
+			Where(func(cb ConditionBuilder) {
+				cb.EndsWith("title", "roadmap")
+			}).
+			Exec(suite.withTenant("globex"))
+		suite.Require().NoError(err)
+
+		affected, _ := res.RowsAffected()
+		suite.Equal(int64(1), affected, "Updates should only change the rows of the tenant")
+
+		res, err = suite.tenanted.NewDelete().
+			Model((*TenantNote)(nil)).
+			AllowFullTable().
+			Exec(suite.withTenant("acme"))
+		suite.Require().NoError(err)
+
+		affected, _ = res.RowsAffected()
+		suite.Equal(int64(3), affected, "Deletes should only remove the rows of the tenant")
+		suite.Equal([]string{"Renamed"}, suite.titles(suite.ctx, suite.db))
+	})
+}
+
+func (suite *TenancyTestSuite) TestOrConditionsKeepTenant() {
+	ctx := suite.withTenant("acme")
+	eitherTitle := func(cb ConditionBuilder) {
+		cb.Equals("title", "Globex roadmap").OrEquals("title", "Acme budget")
+	}
+
+	suite.Run("Select", func() {
+		var titles []string
+
+		suite.Require().NoError(suite.tenanted.NewSelect().
+			Model((*TenantNote)(nil)).
+			Select("title").
+			Where(eitherTitle).
+			Scan(ctx, &titles))
+		suite.Equal([]string{"Acme budget"}, titles, "Or conditions should not select the rows of other tenants")
+	})
+
+	suite.Run("Update", func() {
+		res, err := suite.tenanted.NewUpdate().
+			Model((*TenantNote)(nil)).
+			Set("title", "Renamed").
+			Where(eitherTitle).
+			Exec(ctx)
+		suite.Require().NoError(err)
+
+		affected, _ := res.RowsAffected()
+		suite.Equal(int64(1), affected, "Or conditions should not update the rows of other tenants")
+		suite.Equal([]string{"Globex roadmap"}, suite.titles(suite.withTenant("globex"), suite.tenanted))
+	})
+
+	suite.Run("Delete", func() {
+		res, err := suite.tenanted.NewDelete().
+			Model((*TenantNote)(nil)).
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("title", "Globex roadmap").OrEquals("title", "Acme roadmap")
+			}).
+			Exec(ctx)
+		suite.Require().NoError(err)
+
+		affected, _ := res.RowsAffected()
+		suite.Equal(int64(1), affected, "Or conditions should not delete the rows of other tenants")
+		suite.Equal([]string{"Globex roadmap"}, suite.titles(suite.withTenant("globex"), suite.tenanted))
+	})
+}
+
+func (suite *TenancyTestSuite) TestModelUpdateKeepsTenant() {
+	ctx := suite.withTenant("acme")
+
+	var note TenantNote
+	suite.Require().NoError(suite.tenanted.NewSelect().Model(&note).OrderBy("title").Limit(1).Scan(ctx))
+
+	note.Title = "Acme plan"
+	note.TenantID = constants.Empty
+
+	_, err := suite.tenanted.NewUpdate().Model(&note).WherePK().Exec(ctx)
+	suite.Require().NoError(err)
+
+	var stored TenantNote
+	suite.Require().NoError(suite.db.NewSelect().Model(&stored).Where(func(cb ConditionBuilder) {
+		cb.PKEquals(note.ID)
+	}).Scan(suite.ctx))
+	suite.Equal("Acme plan", stored.Title)
+	suite.Equal("acme", stored.TenantID, "Model updates should not change the tenant")
+}
+
+func (suite *TenancyTestSuite) TestUpsertKeepsOtherTenants() {
+	var note TenantNote
+	suite.Require().NoError(suite.tenanted.NewSelect().Model(&note).OrderBy("title").Limit(1).Scan(suite.withTenant("acme")))
+
+	stored := func() TenantNote {
+		var stored TenantNote
+		suite.Require().NoError(suite.db.NewSelect().Model(&stored).Where(func(cb ConditionBuilder) {
+			cb.PKEquals(note.ID)
+		}).Scan(suite.ctx))
+
+		return stored
+	}
+
+	suite.Run("Upsert", func() {
+		_, err := suite.tenanted.NewUpsert().
+			Model(&TenantNote{Model: Model{ID: note.ID}, Title: "Globex takeover"}).
+			Exec(suite.withTenant("globex"))
+		suite.Require().NoError(err)
+
+		suite.Equal(note.Title, stored().Title, "Upserts should not overwrite the rows of other tenants")
+		suite.Equal("acme", stored().TenantID, "Upserts should not move rows to other tenants")
+	})
+
+	suite.Run("OnConflictDoUpdate", func() {
+		_, err := suite.tenanted.NewInsert().
+			Model(&TenantNote{Model: Model{ID: note.ID}, Title: "Globex takeover"}).
+			OnConflict(func(cb ConflictBuilder) {
+				cb.Columns("id").DoUpdate().Set("title").Set("tenant_id")
+			}).
+			Exec(suite.withTenant("globex"))
+		suite.Require().NoError(err)
+
+		suite.Equal(note.Title, stored().Title, "Conflict updates should not overwrite the rows of other tenants")
+		suite.Equal("acme", stored().TenantID)
+	})
+
+	suite.Run("OwnRow", func() {
+		_, err := suite.tenanted.NewUpsert().
+			Model(&TenantNote{Model: Model{ID: note.ID}, Title: "Acme plan"}).
+			Exec(suite.withTenant("acme"))
+		suite.Require().NoError(err)
+
+		suite.Equal("Acme plan", stored().Title, "Upserts should update the rows of the tenant")
+		suite.Equal("acme", stored().TenantID)
+	})
+}
+
+func (suite *TenancyTestSuite) TestRelationsKeepTenant() {
+	var roadmap TenantNote
+	suite.Require().NoError(suite.db.NewSelect().
+		Model(&roadmap).
+		Where(func(cb ConditionBuilder) {
+			cb.Equals("title", "Globex roadmap")
+		}).
+		Scan(suite.ctx))
+
+	// A note of acme referring to a note of globex
+	_, err := suite.db.NewInsert().
+		Model(&TenantNote{Title: "Acme comment", TenantID: "acme", ParentID: &roadmap.ID}).
+		Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	suite.Run("BelongsTo", func() {
+		var note TenantNote
+		suite.Require().NoError(suite.tenanted.NewSelect().
+			Model(&note).
+			Relation("Parent").
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("title", "Acme comment")
+			}).
+			Scan(suite.withTenant("acme")))
+		suite.Nil(note.Parent, "Joined relations should not load the rows of other tenants")
+
+		suite.Require().NoError(suite.db.NewSelect().
+			Model(&note).
+			Relation("Parent").
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("title", "Acme comment")
+			}).
+			Scan(suite.ctx))
+		suite.Require().NotNil(note.Parent, "Relations should load without tenancy")
+		suite.Equal("Globex roadmap", note.Parent.Title)
+	})
+
+	suite.Run("HasMany", func() {
+		var note TenantNote
+		suite.Require().NoError(suite.tenanted.NewSelect().
+			Model(&note).
+			Relation("Children").
+			Where(func(cb ConditionBuilder) {
+				cb.Equals("title", "Globex roadmap")
+			}).
+			Scan(suite.withTenant("globex")))
+		suite.Empty(note.Children, "Relations loaded by their own query should not load the rows of other tenants")
+	})
+}
+
+func (suite *TenancyTestSuite) TestRequireTenant() {
+	required := suite.db.WithTenancy(NewRowTenancy(tenantResolver, constants.Empty).RequireTenant())
+
+	_, err := required.NewSelect().Model((*TenantNote)(nil)).Count(suite.ctx)
+	suite.ErrorIs(err, ErrTenantRequired, "Selects without tenant should fail")
+
+	_, err = required.NewInsert().Model(&TenantNote{Title: "Orphan"}).Exec(suite.ctx)
+	suite.ErrorIs(err, ErrTenantRequired, "Inserts without tenant should fail")
+
+	err = required.RunInTX(suite.ctx, func(ctx context.Context, tx DB) error {
+		_, err := tx.NewDelete().Model((*TenantNote)(nil)).AllowFullTable().Exec(ctx)
+
+		return err
+	})
+	suite.ErrorIs(err, ErrTenantRequired, "Transactions should keep the tenancy")
+
+	count, err := required.NewSelect().Model((*TenantNote)(nil)).Count(suite.withTenant("acme"))
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), count)
+}
+
+func (suite *TenancyTestSuite) TestSchemaPerTenant() {
+	if suite.dbType != constants.SQLite {
+		suite.T().Skipf("Routing to tenant pools is tested with SQLite files, skipping for %s", suite.dbType)
+	}
+
+	dir := suite.T().TempDir()
+
+	var opened []string
+
+	tenancy := NewSchemaTenancy(tenantResolver, constants.Empty, func(schema string) (*bun.DB, error) {
+		opened = append(opened, schema)
+
+		db, err := database.New(&config.DatasourceConfig{
+			Type: constants.SQLite,
+			Path: filepath.Join(dir, schema+".db"),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = db.NewCreateTable().Model((*TenantNote)(nil)).Exec(suite.ctx); err != nil {
+			return nil, err
+		}
+
+		_, err = db.NewInsert().Model(&TenantNote{Model: Model{ID: schema}, Title: "Note of " + schema}).Exec(suite.ctx)
+
+		return db, err
+	})
+
+	defer func() {
+		suite.NoError(tenancy.Close(), "Should close the tenant pools")
+	}()
+
+	schemaDB := suite.db.WithTenancy(tenancy)
+
+	suite.Equal([]string{"Note of tenant_acme"}, suite.titles(suite.withTenant("acme"), schemaDB), "Statements should run on the pool of the tenant")
+	suite.Equal([]string{"Note of tenant_acme"}, suite.titles(suite.withTenant("acme"), schemaDB), "Pools should be reused")
+	suite.Equal([]string{"tenant_acme"}, opened)
+	suite.Len(suite.titles(suite.ctx, schemaDB), 3, "Requests without tenant should use the shared database")
+
+	_, err := schemaDB.NewSelect().Model((*TenantNote)(nil)).Count(suite.withTenant("acme; DROP"))
+	suite.ErrorIs(err, ErrInvalidTenant, "Tenant IDs should name schemas safely")
+
+	err = schemaDB.RunInTX(suite.withTenant("acme"), func(context.Context, DB) error {
+		return nil
+	})
+	suite.ErrorIs(err, ErrDialectUnsupportedOperation, "Transactions switch schemas on PostgreSQL only")
+}
@@ -0,0 +1,177 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+
+	"github.com/ilxqx/vef-framework-go/dbhelpers"
+)
+
+// runWithTimeout runs the statements of the query under its timeout, run receiving the context to execute them with.
+// PostgreSQL bounds them with SET LOCAL statement_timeout, in a transaction of their own unless the query runs in one
+// already. MySQL bounds them with the max_execution_time of their connection, the session variable the
+// MAX_EXECUTION_TIME optimizer hint sets per statement, as bun renders no hints. Both restore the previous setting
+// afterwards. Other databases bound the context. Expired timeouts match context.DeadlineExceeded on all databases.
+func (q *BunSelectQuery) runWithTimeout(ctx context.Context, run func(ctx context.Context) error) error {
+	ctx, finish, err := q.startTimeout(ctx)
+	if err != nil {
+		return err
+	}
+
+	return finish(run(ctx))
+}
+
+// startTimeout applies the timeout of the query as runWithTimeout describes and returns the context to execute its
+// statements with. finish ends the timeout once the statements are done, e.g. when their rows are closed, and
+// returns the error of the statements or of restoring the previous setting.
+func (q *BunSelectQuery) startTimeout(ctx context.Context) (context.Context, func(err error) error, error) {
+	if q.timeout <= 0 {
+		return ctx, func(err error) error { return err }, nil
+	}
+
+	db, _, err := q.db.conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch q.dialect.Name() {
+	case dialect.PG:
+		return q.startStatementTimeout(ctx, db)
+	case dialect.MySQL:
+		return q.startMaxExecutionTime(ctx, db)
+	default:
+		ctx, cancel := context.WithTimeout(ctx, q.timeout)
+
+		return ctx, func(err error) error {
+			cancel()
+
+			return err
+		}, nil
+	}
+}
+
+func (q *BunSelectQuery) startStatementTimeout(ctx context.Context, db bun.IDB) (context.Context, func(err error) error, error) {
+	tx, ok := db.(bun.Tx)
+	if !ok {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if _, err := tx.ExecContext(ctx, "SET LOCAL statement_timeout = ?", timeoutMillis(q.timeout)); err != nil {
+			_ = tx.Rollback()
+
+			return nil, nil, err
+		}
+
+		q.query.Conn(tx)
+
+		return ctx, func(err error) error {
+			if err != nil {
+				_ = tx.Rollback()
+
+				return timeoutError(err)
+			}
+
+			return timeoutError(tx.Commit())
+		}, nil
+	}
+
+	// SET LOCAL lasts until the end of the enclosing transaction, so the setting of the transaction is restored
+	var previous string
+	if err := tx.QueryRowContext(ctx, "SELECT current_setting('statement_timeout')").Scan(&previous); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "SET LOCAL statement_timeout = ?", timeoutMillis(q.timeout)); err != nil {
+		return nil, nil, err
+	}
+
+	return ctx, func(err error) error {
+		if _, restoreErr := tx.ExecContext(ctx, "SELECT set_config('statement_timeout', ?, true)", previous); err == nil {
+			err = restoreErr
+		}
+
+		return timeoutError(err)
+	}, nil
+}
+
+func (q *BunSelectQuery) startMaxExecutionTime(ctx context.Context, db bun.IDB) (context.Context, func(err error) error, error) {
+	// Session variables are only visible on the connection that assigned them
+	var (
+		conn   bun.IConn = db
+		pooled *sql.Conn
+	)
+
+	if db, ok := db.(*bun.DB); ok {
+		c, err := db.Conn(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		conn, pooled = c, c.Conn
+	}
+
+	release := func() {
+		if pooled != nil {
+			_ = pooled.Close()
+		}
+	}
+
+	var previous int64
+	if err := conn.QueryRowContext(ctx, "SELECT @@SESSION.max_execution_time").Scan(&previous); err != nil {
+		release()
+
+		return nil, nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, "SET SESSION max_execution_time = ?", timeoutMillis(q.timeout)); err != nil {
+		release()
+
+		return nil, nil, err
+	}
+
+	q.query.Conn(conn)
+
+	return ctx, func(err error) error {
+		defer release()
+
+		// Restores the setting also when the context ended, as it does when the timeout fires
+		if _, restoreErr := conn.ExecContext(context.WithoutCancel(ctx), "SET SESSION max_execution_time = ?", previous); restoreErr != nil {
+			// Discards the connection rather than return it to the pool, where later queries would inherit the timeout
+			if pooled != nil {
+				_ = pooled.Raw(func(any) error {
+					return driver.ErrBadConn
+				})
+			}
+
+			if err == nil {
+				err = restoreErr
+			}
+		}
+
+		return timeoutError(err)
+	}, nil
+}
+
+// timeoutError reports context.DeadlineExceeded in place of the error of a statement cancelled by the database
+// for running longer than its timeout, keeping the driver error in the message.
+func timeoutError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || !dbhelpers.IsStatementTimeoutError(err) {
+		return err
+	}
+
+	return fmt.Errorf("%w (%v)", context.DeadlineExceeded, err)
+}
+
+// timeoutMillis returns the timeout in whole milliseconds, at least one as zero disables the server timeouts.
+func timeoutMillis(timeout time.Duration) int64 {
+	return max(timeout.Milliseconds(), 1)
+}
@@ -0,0 +1,149 @@
+package orm
+
+import (
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+const (
+	// TreeDepthColumn is the column of tree queries holding the distance of rows from their root row, 0 for roots.
+	TreeDepthColumn = "tree_depth"
+	// TreePathColumn is the column of tree queries holding the path from the root row, e.g. "/a/b/c/".
+	TreePathColumn = "tree_path"
+
+	defaultTreeName          = "tree"
+	defaultTreePathSeparator = "/"
+	// treeParentAlias aliases the rows found so far in the recursive member of tree queries.
+	treeParentAlias = "_tree"
+	// treePathLength bounds the paths on MySQL, which sizes the columns of recursive common table expressions
+	// from the rows of the anchor member.
+	treePathLength = 4000
+)
+
+// TreeOptions configures the walk of an adjacency-list tree by SelectQuery.WithTree.
+type TreeOptions struct {
+	// Name names the recursive common table expression, "tree" by default.
+	Name string
+	// Direction walks from the root rows to their descendants by default, or to their ancestors.
+	Direction TreeDirection
+	// Root selects the root rows, the rows without parent by default.
+	Root func(ConditionBuilder)
+	// KeyColumn is the column referenced by the parent column, the primary key by default.
+	KeyColumn string
+	// MaxDepth stops the walk at the given distance from the root rows, 0 for no limit.
+	MaxDepth int
+	// PathColumn is the column whose values make up the path, the key column by default.
+	PathColumn string
+	// PathSeparator separates and encloses the values of the path, "/" by default.
+	PathSeparator string
+	// DetectCycles stops the walk at rows already on the path, which requires the path values to be unique
+	// and free of the separator and of LIKE wildcards.
+	DetectCycles bool
+}
+
+// treeQuery joins the anchor and recursive members of a tree query by UNION ALL, without the parentheses
+// bun puts around union members, which SQLite rejects in recursive common table expressions.
+type treeQuery struct {
+	*bun.SelectQuery
+
+	recursive *bun.SelectQuery
+}
+
+func (t treeQuery) AppendQuery(gen schema.QueryGen, b []byte) (_ []byte, err error) {
+	if b, err = t.SelectQuery.AppendQuery(gen, b); err != nil {
+		return nil, err
+	}
+
+	b = append(b, " UNION ALL "...)
+
+	return t.recursive.AppendQuery(gen, b)
+}
+
+// buildTree builds the recursive common table expression walking the tree of the model of the query.
+func (q *BunSelectQuery) buildTree(table *schema.Table, parentColumn string, opts TreeOptions) treeQuery {
+	// Rows are selected without default order, which MySQL rejects in recursive members,
+	// and without the tree columns models may declare to read them
+	scope := func(query SelectQuery) {
+		query.Model(table.ZeroIface).Unscoped()
+
+		for _, field := range table.Fields {
+			if field.Name != TreeDepthColumn && field.Name != TreePathColumn {
+				query.Select(field.Name)
+			}
+		}
+
+		if scoper, ok := table.ZeroIface.(DefaultScoper); ok {
+			query.Where(scoper.DefaultScope)
+		}
+	}
+
+	anchor := q.BuildSubQuery(func(query SelectQuery) {
+		scope(query)
+
+		root := opts.Root
+		if root == nil {
+			root = func(cb ConditionBuilder) {
+				cb.IsNull(parentColumn)
+			}
+		}
+
+		query.SelectExpr(func(eb ExprBuilder) any {
+			return eb.Expr("?", 0)
+		}, TreeDepthColumn).
+			SelectExpr(func(eb ExprBuilder) any {
+				path := eb.Concat(opts.PathSeparator, eb.ToString(eb.Column(opts.PathColumn)), opts.PathSeparator)
+
+				return eb.ExprByDialect(DialectExprs{
+					MySQL: func() schema.QueryAppender {
+						return eb.Expr("CAST(? AS CHAR(?))", path, treePathLength)
+					},
+					Default: func() schema.QueryAppender {
+						return path
+					},
+				})
+			}, TreePathColumn).
+			Where(root)
+	})
+
+	recursive := q.BuildSubQuery(func(query SelectQuery) {
+		scope(query)
+
+		query.SelectExpr(func(eb ExprBuilder) any {
+			return eb.Expr("? + 1", eb.Column(treeParentAlias+constants.Dot+TreeDepthColumn))
+		}, TreeDepthColumn).
+			SelectExpr(func(eb ExprBuilder) any {
+				return eb.Concat(
+					eb.Column(treeParentAlias+constants.Dot+TreePathColumn),
+					eb.ToString(eb.Column(opts.PathColumn)),
+					opts.PathSeparator,
+				)
+			}, TreePathColumn).
+			JoinTable(opts.Name, func(cb ConditionBuilder) {
+				if opts.Direction == TreeAncestors {
+					cb.EqualsColumn(opts.KeyColumn, treeParentAlias+constants.Dot+parentColumn)
+				} else {
+					cb.EqualsColumn(parentColumn, treeParentAlias+constants.Dot+opts.KeyColumn)
+				}
+			}, treeParentAlias)
+
+		if opts.MaxDepth > 0 {
+			query.Where(func(cb ConditionBuilder) {
+				cb.LessThan(treeParentAlias+constants.Dot+TreeDepthColumn, opts.MaxDepth)
+			})
+		}
+
+		if opts.DetectCycles {
+			query.Where(func(cb ConditionBuilder) {
+				cb.Expr(func(eb ExprBuilder) any {
+					return eb.Expr("? NOT LIKE ?",
+						eb.Column(treeParentAlias+constants.Dot+TreePathColumn),
+						eb.Concat("%", opts.PathSeparator, eb.ToString(eb.Column(opts.PathColumn)), opts.PathSeparator, "%"))
+				})
+			})
+		}
+	})
+
+	return treeQuery{SelectQuery: anchor, recursive: recursive}
+}
@@ -0,0 +1,178 @@
+package orm
+
+import (
+	"github.com/uptrace/bun"
+)
+
+type TreeNode struct {
+	bun.BaseModel `bun:"table:test_tree_node,alias:ttn"`
+	Model
+
+	Name     string  `json:"name"     bun:"name,notnull"`
+	ParentID *string `json:"parentId" bun:"parent_id"`
+}
+
+type TreeNodeRow struct {
+	TreeNode `bun:",extend"`
+
+	Depth int    `json:"depth" bun:"tree_depth"`
+	Path  string `json:"path"  bun:"tree_path"`
+}
+
+// TreeTestSuite tests recursive tree queries over adjacency lists.
+type TreeTestSuite struct {
+	*OrmTestSuite
+
+	nodes map[string]*TreeNode
+}
+
+func (suite *TreeTestSuite) SetupSuite() {
+	bunDB := suite.getBunDB()
+
+	_, err := bunDB.NewDropTable().Model((*TreeNode)(nil)).IfExists().Exec(suite.ctx)
+	suite.Require().NoError(err, "Should drop existing tree table")
+
+	_, err = bunDB.NewCreateTable().Model((*TreeNode)(nil)).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should create tree table")
+
+	// root
+	// ├── a
+	// │   └── a1
+	// │       └── a1x
+	// └── b
+	suite.nodes = make(map[string]*TreeNode)
+	for _, node := range [][2]string{{"root", ""}, {"a", "root"}, {"b", "root"}, {"a1", "a"}, {"a1x", "a1"}} {
+		row := &TreeNode{Name: node[0]}
+		if node[1] != "" {
+			row.ParentID = &suite.nodes[node[1]].ID
+		}
+
+		_, err := suite.db.NewInsert().Model(row).Exec(suite.ctx)
+		suite.Require().NoError(err, "Should insert tree node")
+
+		suite.nodes[node[0]] = row
+	}
+}
+
+func (suite *TreeTestSuite) TearDownSuite() {
+	_, err := suite.getBunDB().NewDropTable().Model((*TreeNode)(nil)).IfExists().Exec(suite.ctx)
+	suite.NoError(err, "Should cleanup tree table")
+}
+
+func (suite *TreeTestSuite) walk(parentColumn string, options TreeOptions) map[string]TreeNodeRow {
+	var rows []TreeNodeRow
+
+	suite.Require().NoError(suite.db.NewSelect().
+		Model(&rows).
+		WithTree(parentColumn, options).
+		Scan(suite.ctx), "Should walk the tree")
+
+	result := make(map[string]TreeNodeRow, len(rows))
+	for _, row := range rows {
+		result[row.Name] = row
+	}
+
+	return result
+}
+
+func (suite *TreeTestSuite) byName(name string) func(ConditionBuilder) {
+	return func(cb ConditionBuilder) {
+		cb.Equals("name", name)
+	}
+}
+
+// TestDescendants tests walking from the root rows to their descendants.
+func (suite *TreeTestSuite) TestDescendants() {
+	rows := suite.walk("parent_id", TreeOptions{PathColumn: "name"})
+
+	suite.Len(rows, 5, "Should find all nodes from the rows without parent")
+	suite.Equal(0, rows["root"].Depth, "Should start at depth 0")
+	suite.Equal(3, rows["a1x"].Depth, "Should count the distance from the root")
+	suite.Equal("/root/a/a1/a1x/", rows["a1x"].Path, "Should collect the path")
+	suite.Equal("/root/b/", rows["b"].Path, "Should collect the path of siblings")
+
+	rows = suite.walk("parent_id", TreeOptions{Root: suite.byName("a")})
+
+	suite.Len(rows, 3, "Should find the subtree of the root rows")
+	suite.Equal("/"+suite.nodes["a"].ID+"/"+suite.nodes["a1"].ID+"/", rows["a1"].Path, "Should collect keys by default")
+}
+
+// TestMaxDepth tests stopping the walk at a distance from the root rows.
+func (suite *TreeTestSuite) TestMaxDepth() {
+	rows := suite.walk("parent_id", TreeOptions{MaxDepth: 1})
+
+	suite.Len(rows, 3, "Should find the root and its children only")
+	suite.Contains(rows, "a")
+	suite.Contains(rows, "b")
+}
+
+// TestAncestors tests walking from the root rows to their ancestors.
+func (suite *TreeTestSuite) TestAncestors() {
+	rows := suite.walk("parent_id", TreeOptions{
+		Direction:  TreeAncestors,
+		Root:       suite.byName("a1x"),
+		PathColumn: "name",
+	})
+
+	suite.Len(rows, 4, "Should find the chain up to the root")
+	suite.Equal(3, rows["root"].Depth, "Should count the distance from the starting row")
+	suite.Equal("/a1x/a1/a/root/", rows["root"].Path, "Should collect the path upwards")
+}
+
+// TestDetectCycles tests stopping the walk at rows already visited.
+func (suite *TreeTestSuite) TestDetectCycles() {
+	// a1x -> root closes a cycle
+	_, err := suite.db.NewUpdate().
+		Model((*TreeNode)(nil)).
+		Set("parent_id", suite.nodes["a1x"].ID).
+		Where(suite.byName("root")).
+		Exec(suite.ctx)
+	suite.Require().NoError(err, "Should close a cycle")
+
+	defer func() {
+		_, err := suite.db.NewUpdate().
+			Model((*TreeNode)(nil)).
+			Set("parent_id", nil).
+			Where(suite.byName("root")).
+			Exec(suite.ctx)
+		suite.NoError(err, "Should break the cycle")
+	}()
+
+	rows := suite.walk("parent_id", TreeOptions{Root: suite.byName("root"), DetectCycles: true})
+
+	suite.Len(rows, 5, "Should visit every node once")
+	suite.Equal(3, rows["a1x"].Depth, "Should stop before revisiting the root")
+}
+
+// TestFilterAndOrder tests filtering and ordering the rows found.
+func (suite *TreeTestSuite) TestFilterAndOrder() {
+	var rows []TreeNodeRow
+
+	suite.Require().NoError(suite.db.NewSelect().
+		Model(&rows).
+		WithTree("parent_id", TreeOptions{}).
+		Where(func(cb ConditionBuilder) {
+			cb.GreaterThan(TreeDepthColumn, 0)
+		}).
+		OrderByDesc(TreeDepthColumn).
+		OrderBy("name").
+		Scan(suite.ctx), "Should filter and order the tree")
+
+	names := make([]string, len(rows))
+	for i, row := range rows {
+		names[i] = row.Name
+	}
+
+	suite.Equal([]string{"a1x", "a1", "a", "b"}, names, "Should order by depth then name")
+}
+
+// TestMissingModel tests walking without a model.
+func (suite *TreeTestSuite) TestMissingModel() {
+	var rows []map[string]any
+
+	err := suite.db.NewSelect().
+		Table("test_tree_node").
+		WithTree("parent_id", TreeOptions{}).
+		Scan(suite.ctx, &rows)
+	suite.ErrorIs(err, ErrTreeMissingModel, "Should require a model")
+}
@@ -0,0 +1,131 @@
+package orm
+
+import "github.com/uptrace/bun/schema"
+
+// tupleOperator describes a row value comparison operator together with its strict counterpart,
+// which is needed to expand the comparison into AND/OR form on databases without row value support.
+type tupleOperator struct {
+	op       string
+	strictOp string
+}
+
+var (
+	tupleGreaterThan        = tupleOperator{op: ">", strictOp: ">"}
+	tupleGreaterThanOrEqual = tupleOperator{op: ">=", strictOp: ">"}
+	tupleLessThan           = tupleOperator{op: "<", strictOp: "<"}
+	tupleLessThanOrEqual    = tupleOperator{op: "<=", strictOp: "<"}
+)
+
+// buildTupleColumns builds the column expressions of a tuple, panicking if no column is given.
+func buildTupleColumns(eb ExprBuilder, columns []string) []any {
+	if len(columns) == 0 {
+		logger.Panic("tuple condition requires at least one column")
+	}
+
+	exprs := make([]any, len(columns))
+	for i, column := range columns {
+		exprs[i] = eb.Column(column)
+	}
+
+	return exprs
+}
+
+// checkTupleArity panics if a value tuple does not match the number of columns.
+func checkTupleArity(columns []any, values []any) {
+	if len(values) != len(columns) {
+		logger.Panicf("tuple condition expects %d values per tuple, got %d", len(columns), len(values))
+	}
+}
+
+// buildTupleIn builds a row value IN condition: (a, b) IN ((1, 2), (3, 4)).
+// SQL Server lacks row value support, so the condition is expanded to ((a = 1 AND b = 2) OR (a = 3 AND b = 4)).
+func buildTupleIn(eb ExprBuilder, columns []string, values [][]any, negate bool) schema.QueryAppender {
+	columnExprs := buildTupleColumns(eb, columns)
+	for _, tuple := range values {
+		checkTupleArity(columnExprs, tuple)
+	}
+
+	if len(values) == 0 {
+		if negate {
+			return eb.Expr("1 = 1")
+		}
+
+		return eb.Expr("1 = 0")
+	}
+
+	return eb.ExprByDialect(DialectExprs{
+		SQLServer: func() schema.QueryAppender {
+			alternatives := make([]any, len(values))
+			for i, tuple := range values {
+				alternatives[i] = eb.Paren(buildTupleEquality(eb, columnExprs, tuple, len(columnExprs)))
+			}
+
+			expr := eb.Paren(eb.ExprsWithSep(separatorOr, alternatives...))
+			if negate {
+				return eb.Not(expr)
+			}
+
+			return expr
+		},
+		Default: func() schema.QueryAppender {
+			tuples := make([]any, len(values))
+			for i, tuple := range values {
+				tuples[i] = eb.Paren(eb.Exprs(tuple...))
+			}
+
+			if negate {
+				return eb.Expr("(?) NOT IN (?)", eb.Exprs(columnExprs...), eb.Exprs(tuples...))
+			}
+
+			return eb.Expr("(?) IN (?)", eb.Exprs(columnExprs...), eb.Exprs(tuples...))
+		},
+	})
+}
+
+// buildTupleComparison builds a row value comparison: (a, b) > (1, 2).
+// On SQL Server and Oracle it is expanded lexicographically: (a > 1) OR (a = 1 AND b > 2).
+func buildTupleComparison(eb ExprBuilder, columns []string, values []any, operator tupleOperator) schema.QueryAppender {
+	columnExprs := buildTupleColumns(eb, columns)
+	checkTupleArity(columnExprs, values)
+
+	expand := func() schema.QueryAppender {
+		alternatives := make([]any, len(columnExprs))
+		for i := range columnExprs {
+			op := operator.strictOp
+			if i == len(columnExprs)-1 {
+				op = operator.op
+			}
+
+			comparison := eb.Expr("? "+op+" ?", columnExprs[i], values[i])
+			if i == 0 {
+				alternatives[i] = eb.Paren(comparison)
+			} else {
+				alternatives[i] = eb.Paren(eb.ExprsWithSep(
+					separatorAnd,
+					buildTupleEquality(eb, columnExprs, values, i),
+					comparison,
+				))
+			}
+		}
+
+		return eb.Paren(eb.ExprsWithSep(separatorOr, alternatives...))
+	}
+
+	return eb.ExprByDialect(DialectExprs{
+		SQLServer: expand,
+		Oracle:    expand,
+		Default: func() schema.QueryAppender {
+			return eb.Expr("(?) "+operator.op+" (?)", eb.Exprs(columnExprs...), eb.Exprs(values...))
+		},
+	})
+}
+
+// buildTupleEquality builds the conjunction of equalities for the first n columns of a tuple.
+func buildTupleEquality(eb ExprBuilder, columns []any, values []any, n int) schema.QueryAppender {
+	equalities := make([]any, n)
+	for i := range n {
+		equalities[i] = eb.Equals(columns[i], values[i])
+	}
+
+	return eb.ExprsWithSep(separatorAnd, equalities...)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"reflect"
+	"slices"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/feature"
@@ -30,6 +31,7 @@ func NewUpdateQuery(db *BunDB) *BunUpdateQuery {
 
 		selectedColumns:  collections.NewHashSet[string](),
 		returningColumns: collections.NewHashSet[string](),
+		setColumns:       collections.NewHashSet[string](),
 	}
 	eb.qb = query
 
@@ -47,8 +49,13 @@ type BunUpdateQuery struct {
 	query            *bun.UpdateQuery
 	hasSet           bool
 	isBulk           bool
+	hasWhere         bool
+	allowFullTable   bool
 	selectedColumns  collections.Set[string]
 	returningColumns collections.Set[string]
+	setColumns       collections.Set[string]
+	// filters replays the filtering of the query on a select query reading the rows to update
+	filters []ApplyFunc[SelectQuery]
 }
 
 func (q *BunUpdateQuery) DB() DB {
@@ -138,27 +145,49 @@ func (q *BunUpdateQuery) TableSubQuery(builder func(SelectQuery), alias ...strin
 }
 
 func (q *BunUpdateQuery) Where(builder func(ConditionBuilder)) UpdateQuery {
-	cb := newQueryConditionBuilder(q.query.QueryBuilder(), q)
-	builder(cb)
+	cb := newQueryConditionBuilder(&whereTracker{QueryBuilder: q.query.QueryBuilder(), hasWhere: &q.hasWhere}, q)
+	q.db.whereScoped(cb, nil, builder)
+
+	q.filters = append(q.filters, func(query SelectQuery) {
+		query.Where(builder)
+	})
 
 	return q
 }
 
+func (q *BunUpdateQuery) WhereSpec(spec Spec) UpdateQuery {
+	return q.Where(And(spec))
+}
+
 func (q *BunUpdateQuery) WherePK(columns ...string) UpdateQuery {
+	q.hasWhere = true
 	q.query.WherePK(columns...)
 
+	q.filters = append(q.filters, func(query SelectQuery) {
+		query.WherePK(columns...)
+	})
+
 	return q
 }
 
 func (q *BunUpdateQuery) WhereDeleted() UpdateQuery {
+	q.hasWhere = true
 	q.query.WhereDeleted()
 
+	q.filters = append(q.filters, func(query SelectQuery) {
+		query.WhereDeleted()
+	})
+
 	return q
 }
 
 func (q *BunUpdateQuery) IncludeDeleted() UpdateQuery {
 	q.query.WhereAllWithDeleted()
 
+	q.filters = append(q.filters, func(query SelectQuery) {
+		query.IncludeDeleted()
+	})
+
 	return q
 }
 
@@ -189,12 +218,14 @@ func (q *BunUpdateQuery) ExcludeAll() UpdateQuery {
 
 func (q *BunUpdateQuery) Column(name string, value any) UpdateQuery {
 	q.query.Value(name, "?", value)
+	q.setColumns.Add(name)
 
 	return q
 }
 
 func (q *BunUpdateQuery) ColumnExpr(name string, builder func(ExprBuilder) any) UpdateQuery {
 	q.query.Value(name, "?", builder(q.eb))
+	q.setColumns.Add(name)
 
 	return q
 }
@@ -207,6 +238,7 @@ func (q *BunUpdateQuery) Set(name string, value any) UpdateQuery {
 	}
 
 	q.hasSet = true
+	q.setColumns.Add(name)
 
 	return q
 }
@@ -219,6 +251,7 @@ func (q *BunUpdateQuery) SetExpr(name string, builder func(ExprBuilder) any) Upd
 	}
 
 	q.hasSet = true
+	q.setColumns.Add(name)
 
 	return q
 }
@@ -279,6 +312,16 @@ func (q *BunUpdateQuery) Bulk() UpdateQuery {
 	q.isBulk = true
 	q.query.Bulk()
 
+	q.filters = append(q.filters, func(query SelectQuery) {
+		query.WherePK()
+	})
+
+	return q
+}
+
+func (q *BunUpdateQuery) AllowFullTable() UpdateQuery {
+	q.allowFullTable = true
+
 	return q
 }
 
@@ -300,10 +343,34 @@ func (q *BunUpdateQuery) ApplyIf(condition bool, fns ...ApplyFunc[UpdateQuery])
 	return q
 }
 
-func (q *BunUpdateQuery) beforeUpdate() {
+func (q *BunUpdateQuery) beforeUpdate(ctx context.Context) error {
+	// Bulk updates match rows by primary key
+	if !q.hasWhere && !q.isBulk {
+		if !q.allowFullTable {
+			return ErrMissingWhereClause
+		}
+
+		q.query.Where("1 = 1")
+		q.hasWhere = true
+	}
+
+	cb := newQueryConditionBuilder(q.query.QueryBuilder(), q)
+	if _, err := q.db.applyTenancy(ctx, q.query, q.GetTable(), cb); err != nil {
+		return err
+	}
+
+	if err := applyDataScopes(ctx, q.db.dataScopes, q.GetTable(), cb); err != nil {
+		return err
+	}
+
 	if table := q.GetTable(); table != nil {
 		q.skipCreateAuditColumns(table)
 
+		// Rows stay with their tenant, model updates would otherwise write the tenant they were built with
+		if q.db.tenancy.filters(table) && !q.hasSet && q.selectedColumns.IsEmpty() {
+			q.Exclude(q.db.tenancy.column)
+		}
+
 		modelValue := q.query.GetModel().Value()
 		mv := reflect.Indirect(reflect.ValueOf(modelValue))
 
@@ -313,6 +380,8 @@ func (q *BunUpdateQuery) beforeUpdate() {
 	if !q.returningColumns.IsEmpty() {
 		q.query.Returning("?", buildReturningExpr(q.returningColumns, q.eb))
 	}
+
+	return nil
 }
 
 func (q *BunUpdateQuery) skipCreateAuditColumns(table *schema.Table) {
@@ -330,26 +399,158 @@ func (q *BunUpdateQuery) skipCreateAuditColumns(table *schema.Table) {
 }
 
 func (q *BunUpdateQuery) Exec(ctx context.Context, dest ...any) (sql.Result, error) {
-	q.beforeUpdate()
+	ctx = withRejection(ctx)
 
-	res, err := q.query.Exec(ctx, dest...)
-	if err != nil {
-		return nil, translateWriteError(err)
+	if err := q.beforeUpdate(ctx); err != nil {
+		return nil, err
+	}
+
+	var (
+		res sql.Result
+		err error
+	)
+
+	if err = q.run(ctx, func(ctx context.Context) error {
+		res, err = q.query.Exec(ctx, dest...)
+
+		return err
+	}); err != nil {
+		return nil, contextError(ctx, translateWriteError(err))
 	}
 
 	return res, nil
 }
 
 func (q *BunUpdateQuery) Scan(ctx context.Context, dest ...any) error {
-	q.beforeUpdate()
+	ctx = withRejection(ctx)
 
-	if err := q.query.Scan(ctx, dest...); err != nil {
-		return translateWriteError(err)
+	if err := q.beforeUpdate(ctx); err != nil {
+		return err
+	}
+
+	if err := q.run(ctx, func(ctx context.Context) error {
+		return q.query.Scan(ctx, dest...)
+	}); err != nil {
+		return contextError(ctx, translateWriteError(err))
 	}
 
 	return nil
 }
 
+// updatesColumn reports whether the update may change the column.
+func (q *BunUpdateQuery) updatesColumn(name string) bool {
+	switch {
+	case q.hasSet:
+		return q.setColumns.Contains(name)
+	case !q.selectedColumns.IsEmpty():
+		return q.selectedColumns.Contains(name) || q.setColumns.Contains(name)
+	default:
+		return true
+	}
+}
+
+// run executes the update, within a transaction recording the history of the updated rows if enabled, refreshing
+// the denormalized columns depending on the updated rows if any: the copies of changed columns and the copies held
+// by rows whose relation columns changed, and reading the updated rows before and after for the audit events.
+func (q *BunUpdateQuery) run(ctx context.Context, exec func(context.Context) error) error {
+	model, ok := q.query.GetModel().(bun.TableModel)
+	if !ok {
+		return exec(ctx)
+	}
+
+	table := model.Table()
+	history := historyOf(table)
+
+	sources := slices.DeleteFunc(denormsFrom(table), func(d *denorm) bool {
+		return !q.updatesColumn(d.source)
+	})
+	copies := slices.DeleteFunc(denormsOf(table), func(d *denorm) bool {
+		return !slices.ContainsFunc(d.rel.BasePKs, func(field *schema.Field) bool {
+			return q.updatesColumn(field.Name)
+		})
+	})
+
+	sink := auditOf(table)
+
+	if !history && len(sources) == 0 && len(copies) == 0 && sink == nil {
+		return exec(ctx)
+	}
+
+	return q.db.runInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		txDB := &BunDB{db: tx, dataScopes: q.db.dataScopes, tenancy: q.db.tenancy}
+
+		if history {
+			if err := recordHistory(ctx, txDB, model.Value(), q.filters, HistoryOperationUpdate); err != nil {
+				return err
+			}
+		}
+
+		columns := make([]string, 0, len(table.PKs))
+		for _, pk := range table.PKs {
+			columns = append(columns, pk.Name)
+		}
+
+		for _, d := range sources {
+			for _, field := range d.rel.JoinPKs {
+				if !slices.Contains(columns, field.Name) {
+					columns = append(columns, field.Name)
+				}
+			}
+		}
+
+		// The keys are read first as the update may change the rows matching its filters
+		var rows []map[string]any
+
+		if len(sources) > 0 || len(copies) > 0 {
+			if err := NewSelectQuery(txDB).
+				Model(model.Value()).
+				Select(columns...).
+				Apply(q.filters...).
+				Scan(ctx, &rows); err != nil {
+				return err
+			}
+		}
+
+		var before []map[string]any
+
+		if sink != nil {
+			var err error
+			if before, err = readAuditRows(ctx, txDB, model.Value(), table, q.filters...); err != nil {
+				return err
+			}
+		}
+
+		q.query.Conn(tx)
+
+		if err := exec(ctx); err != nil {
+			return err
+		}
+
+		for _, d := range sources {
+			if err := d.refresh(ctx, tx, d.rel.BasePKs, rowKeys(d.rel.JoinPKs, rows)); err != nil {
+				return err
+			}
+		}
+
+		for _, d := range copies {
+			if err := d.refresh(ctx, tx, table.PKs, rowKeys(table.PKs, rows)); err != nil {
+				return err
+			}
+		}
+
+		if sink != nil {
+			after, err := readAuditRowsByKeys(ctx, txDB, model.Value(), table, rowKeys(table.PKs, before))
+			if err != nil {
+				return err
+			}
+
+			return writeAuditEvents(ctx, txDB, sink, auditEvents(table, AuditOperationUpdate, before, after))
+		}
+
+		return nil
+	})
+}
+
 func (q *BunUpdateQuery) Unwrap() *bun.UpdateQuery {
 	return q.query
 }
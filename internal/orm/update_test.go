@@ -1146,3 +1146,62 @@ func (suite *UpdateTestSuite) TestExecution() {
 		suite.T().Logf("Exec with invalid field correctly returned error")
 	})
 }
+
+// TestFullTableGuard tests that updates without a where clause are rejected unless AllowFullTable is called.
+func (suite *UpdateTestSuite) TestFullTableGuard() {
+	suite.T().Logf("Testing full table guard for %s", suite.dbType)
+
+	testModels := []*SimpleModel{
+		{Name: "Guard Update 1", Value: 1},
+		{Name: "Guard Update 2", Value: 2},
+	}
+
+	_, err := suite.db.NewInsert().Model(&testModels).Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert test simple models")
+
+	defer func() {
+		_, err := suite.db.NewDelete().Model((*SimpleModel)(nil)).Where(func(cb ConditionBuilder) {
+			cb.StartsWith("name", "Guard Update")
+		}).Exec(suite.ctx)
+		suite.NoError(err, "Should clean up test simple models")
+	}()
+
+	suite.Run("RejectWithoutWhere", func() {
+		_, err := suite.db.NewUpdate().Model((*SimpleModel)(nil)).Set("value", 10).Exec(suite.ctx)
+		suite.ErrorIs(err, ErrMissingWhereClause, "UPDATE without WHERE should be rejected")
+
+		count, err := suite.db.NewSelect().Model((*SimpleModel)(nil)).Where(func(cb ConditionBuilder) {
+			cb.Equals("value", 10)
+		}).Count(suite.ctx)
+		suite.NoError(err)
+		suite.Equal(int64(0), count, "Rejected updates should not change rows")
+	})
+
+	suite.Run("AllowWherePK", func() {
+		model := testModels[0]
+		model.Value = 5
+
+		_, err := suite.db.NewUpdate().Model(model).Select("value").WherePK().Exec(suite.ctx)
+		suite.NoError(err, "UPDATE with WherePK should be allowed")
+	})
+
+	suite.Run("AllowBulk", func() {
+		for _, model := range testModels {
+			model.Value += 100
+		}
+
+		_, err := suite.db.NewUpdate().Model(&testModels).Select("value").Bulk().Exec(suite.ctx)
+		suite.NoError(err, "Bulk UPDATE matching rows by primary key should be allowed")
+	})
+
+	suite.Run("AllowFullTable", func() {
+		_, err := suite.db.NewUpdate().Model((*SimpleModel)(nil)).Set("value", 10).AllowFullTable().Exec(suite.ctx)
+		suite.NoError(err, "UPDATE with AllowFullTable should be allowed")
+
+		count, err := suite.db.NewSelect().Model((*SimpleModel)(nil)).Where(func(cb ConditionBuilder) {
+			cb.NotEquals("value", 10)
+		}).Count(suite.ctx)
+		suite.NoError(err)
+		suite.Equal(int64(0), count, "UPDATE with AllowFullTable should update all rows")
+	})
+}
@@ -0,0 +1,258 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// upsertSourceName names the CTE holding the rows to upsert in MERGE statements.
+const upsertSourceName = "_upsert_source"
+
+// upsertQuery inserts rows or updates the existing rows with the same conflict key using the syntax of the dialect:
+// INSERT ... ON CONFLICT on PostgreSQL and SQLite, INSERT ... ON DUPLICATE KEY UPDATE on MySQL
+// and MERGE on SQL Server and Oracle.
+type upsertQuery struct {
+	db              *BunDB
+	model           any
+	table           string
+	conflictColumns []string
+	updateColumns   []string
+	doNothing       bool
+}
+
+func newUpsertQuery(db *BunDB) *upsertQuery {
+	return &upsertQuery{db: db}
+}
+
+func (q *upsertQuery) DB() DB {
+	return q.db
+}
+
+func (q *upsertQuery) Model(model any) UpsertQuery {
+	q.model = model
+
+	return q
+}
+
+func (q *upsertQuery) Table(name string) UpsertQuery {
+	q.table = name
+
+	return q
+}
+
+func (q *upsertQuery) ConflictColumns(columns ...string) UpsertQuery {
+	q.conflictColumns = append(q.conflictColumns, columns...)
+
+	return q
+}
+
+func (q *upsertQuery) UpdateColumns(columns ...string) UpsertQuery {
+	q.updateColumns = append(q.updateColumns, columns...)
+
+	return q
+}
+
+func (q *upsertQuery) DoNothing() UpsertQuery {
+	q.doNothing = true
+
+	return q
+}
+
+func (q *upsertQuery) Exec(ctx context.Context, dest ...any) (sql.Result, error) {
+	executor, err := q.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return executor.Exec(ctx, dest...)
+}
+
+func (q *upsertQuery) Scan(ctx context.Context, dest ...any) error {
+	executor, err := q.build(ctx)
+	if err != nil {
+		return err
+	}
+
+	return executor.Scan(ctx, dest...)
+}
+
+func (q *upsertQuery) SQL() (string, error) {
+	ctx := context.Background()
+
+	executor, err := q.build(ctx)
+	if err != nil {
+		return constants.Empty, err
+	}
+
+	var query schema.QueryAppender
+
+	switch statement := executor.(type) {
+	case *BunInsertQuery:
+		if err = statement.beforeInsert(ctx); err != nil {
+			return constants.Empty, err
+		}
+
+		query = statement.Unwrap()
+	case *BunMergeQuery:
+		query = statement.Unwrap()
+	}
+
+	b, err := query.AppendQuery(q.db.getBunDB().QueryGen(), nil)
+	if err != nil {
+		return constants.Empty, err
+	}
+
+	return string(b), nil
+}
+
+// build creates the dialect-specific statement of the upsert.
+func (q *upsertQuery) build(ctx context.Context) (QueryExecutor, error) {
+	if q.model == nil {
+		return nil, ErrUpsertMissingModel
+	}
+
+	// The insert query applies the auto columns (ids, audit timestamps and users) to the model for both statements
+	insert := NewInsertQuery(q.db)
+	insert.Model(q.model)
+
+	if q.table != constants.Empty {
+		insert.Table(q.table)
+	}
+
+	table := insert.GetTable()
+	if table == nil && q.table == constants.Empty {
+		return nil, ErrModelMustBePointerToStruct
+	}
+
+	conflictColumns := q.conflictColumns
+	if len(conflictColumns) == 0 && table != nil {
+		for _, pk := range table.PKs {
+			conflictColumns = append(conflictColumns, pk.Name)
+		}
+	}
+
+	if len(conflictColumns) == 0 {
+		return nil, ErrUpsertMissingConflictColumns
+	}
+
+	updateColumns := q.updateColumns
+	if len(updateColumns) == 0 {
+		if table != nil {
+			for _, field := range table.Fields {
+				if field.IsPK || field.Tag.HasOption("skipupdate") || slices.Contains(conflictColumns, field.Name) {
+					continue
+				}
+
+				updateColumns = append(updateColumns, field.Name)
+			}
+		} else {
+			for _, column := range mapModelColumns(q.model) {
+				if !slices.Contains(conflictColumns, column) {
+					updateColumns = append(updateColumns, column)
+				}
+			}
+		}
+	}
+
+	var executor QueryExecutor
+
+	buildMerge := func() error {
+		if table == nil {
+			// MERGE reads the rows from a VALUES list, which requires a struct model
+			return ErrDialectUnsupportedOperation
+		}
+
+		if err := insert.beforeInsert(ctx); err != nil {
+			return err
+		}
+
+		// Conflicting rows of other tenants are left unmatched, failing the insert rather than being updated
+		var tenantID string
+		if q.db.tenancy.filters(table) {
+			var err error
+			if tenantID, err = q.db.tenancy.tenant(ctx); err != nil {
+				return err
+			}
+		}
+
+		insertColumns := make([]string, 0, len(table.Fields))
+		for _, field := range table.Fields {
+			insertColumns = append(insertColumns, field.Name)
+		}
+
+		merge := NewMergeQuery(q.db)
+		merge.Model(q.model).
+			WithValues(upsertSourceName, q.model).
+			UsingTable(upsertSourceName).
+			On(func(cb ConditionBuilder) {
+				for _, column := range conflictColumns {
+					cb.EqualsColumn(
+						table.Alias+constants.Dot+column,
+						upsertSourceName+constants.Dot+column,
+					)
+				}
+
+				if tenantID != constants.Empty {
+					cb.Equals(table.Alias+constants.Dot+q.db.tenancy.column, tenantID)
+				}
+			})
+
+		if !q.doNothing && len(updateColumns) > 0 {
+			merge.WhenMatched().ThenUpdate(func(ub MergeUpdateBuilder) {
+				ub.SetColumns(updateColumns...)
+			})
+		}
+
+		merge.WhenNotMatched().ThenInsert(func(ib MergeInsertBuilder) {
+			ib.Values(insertColumns...)
+		})
+
+		executor = merge
+
+		return nil
+	}
+
+	err := insert.eb.ExecByDialectWithErr(DialectExecsWithErr{
+		Oracle:    buildMerge,
+		SQLServer: buildMerge,
+		Default: func() error {
+			insert.OnConflict(func(cb ConflictBuilder) {
+				cb.Columns(conflictColumns...)
+
+				if q.doNothing || len(updateColumns) == 0 {
+					cb.DoNothing()
+
+					return
+				}
+
+				ub := cb.DoUpdate()
+				for _, column := range updateColumns {
+					ub.Set(column)
+				}
+			})
+
+			executor = insert
+
+			return nil
+		},
+	})
+
+	return executor, err
+}
+
+// mapModelColumns returns the sorted keys of a map model.
+func mapModelColumns(model any) []string {
+	values, ok := reflect.Indirect(reflect.ValueOf(model)).Interface().(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	return slices.Sorted(maps.Keys(values))
+}
@@ -0,0 +1,194 @@
+package orm
+
+import "github.com/ilxqx/vef-framework-go/constants"
+
+// UpsertTestSuite tests UPSERT operations through the portable UpsertQuery builder.
+type UpsertTestSuite struct {
+	*OrmTestSuite
+}
+
+func (suite *UpsertTestSuite) findByEmail(email string) *User {
+	var user User
+
+	suite.Require().NoError(suite.db.NewSelect().
+		Model(&user).
+		Where(func(cb ConditionBuilder) {
+			cb.Equals("email", email)
+		}).
+		Scan(suite.ctx))
+
+	return &user
+}
+
+func (suite *UpsertTestSuite) deleteByEmail(emails ...string) {
+	_, err := suite.db.NewDelete().
+		Model((*User)(nil)).
+		Where(func(cb ConditionBuilder) {
+			cb.In("email", emails)
+		}).
+		Exec(suite.ctx)
+	suite.NoError(err)
+}
+
+// TestUpsertByPrimaryKey tests inserting and then updating a row identified by its primary key.
+func (suite *UpsertTestSuite) TestUpsertByPrimaryKey() {
+	suite.T().Logf("Testing UPSERT by primary key for %s", suite.dbType)
+
+	defer suite.deleteByEmail("upsert-pk@example.com")
+
+	_, err := suite.db.NewUpsert().
+		Model(&User{
+			Model: Model{ID: "upsert-pk"},
+			Name:  "Upsert Original",
+			Email: "upsert-pk@example.com",
+			Age:   20,
+		}).
+		Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert a new row")
+
+	inserted := suite.findByEmail("upsert-pk@example.com")
+	suite.Equal("upsert-pk", inserted.ID)
+	suite.Equal("Upsert Original", inserted.Name)
+
+	_, err = suite.db.NewUpsert().
+		Model(&User{
+			Model:    Model{ID: "upsert-pk"},
+			Name:     "Upsert Modified",
+			Email:    "upsert-pk@example.com",
+			Age:      21,
+			IsActive: true,
+		}).
+		Exec(suite.ctx)
+	suite.Require().NoError(err, "Should update the existing row")
+
+	updated := suite.findByEmail("upsert-pk@example.com")
+	suite.Equal("Upsert Modified", updated.Name, "Name should be updated")
+	suite.Equal(int16(21), updated.Age, "Age should be updated")
+	suite.True(updated.IsActive, "IsActive should be updated")
+	suite.Equal(inserted.CreatedAt.Unwrap().Unix(), updated.CreatedAt.Unwrap().Unix(), "Skip-update columns should be kept")
+}
+
+// TestUpsertByConflictColumns tests updating selected columns of a row identified by a unique column.
+func (suite *UpsertTestSuite) TestUpsertByConflictColumns() {
+	suite.T().Logf("Testing UPSERT by conflict columns for %s", suite.dbType)
+
+	defer suite.deleteByEmail("upsert-email@example.com")
+
+	original := &User{Name: "Email Original", Email: "upsert-email@example.com", Age: 30, IsActive: true}
+	_, err := suite.db.NewInsert().Model(original).Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.db.NewUpsert().
+		Model(&User{Name: "Email Modified", Email: "upsert-email@example.com", Age: 31}).
+		ConflictColumns("email").
+		UpdateColumns("name", "age").
+		Exec(suite.ctx)
+	suite.Require().NoError(err, "Should update the row with the same email")
+
+	updated := suite.findByEmail("upsert-email@example.com")
+	suite.Equal(original.ID, updated.ID, "Should keep the existing row")
+	suite.Equal("Email Modified", updated.Name, "Name should be updated")
+	suite.Equal(int16(31), updated.Age, "Age should be updated")
+	suite.True(updated.IsActive, "Columns not listed should be unchanged")
+}
+
+// TestUpsertDoNothing tests keeping existing rows unchanged while inserting new ones.
+func (suite *UpsertTestSuite) TestUpsertDoNothing() {
+	suite.T().Logf("Testing UPSERT DO NOTHING for %s", suite.dbType)
+
+	defer suite.deleteByEmail("upsert-keep@example.com", "upsert-new@example.com")
+
+	_, err := suite.db.NewInsert().
+		Model(&User{Name: "Keep Original", Email: "upsert-keep@example.com", Age: 40}).
+		Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	users := []User{
+		{Name: "Keep Modified", Email: "upsert-keep@example.com", Age: 41},
+		{Name: "New User", Email: "upsert-new@example.com", Age: 22},
+	}
+
+	_, err = suite.db.NewUpsert().
+		Model(&users).
+		ConflictColumns("email").
+		DoNothing().
+		Exec(suite.ctx)
+	suite.Require().NoError(err, "Should insert the new rows only")
+
+	suite.Equal("Keep Original", suite.findByEmail("upsert-keep@example.com").Name, "Existing row should be unchanged")
+	suite.Equal("New User", suite.findByEmail("upsert-new@example.com").Name, "New row should be inserted")
+}
+
+// TestUpsertErrors tests the validation of the upsert configuration.
+func (suite *UpsertTestSuite) TestUpsertErrors() {
+	suite.T().Logf("Testing UPSERT errors for %s", suite.dbType)
+
+	suite.Run("MissingModel", func() {
+		_, err := suite.db.NewUpsert().Exec(suite.ctx)
+		suite.ErrorIs(err, ErrUpsertMissingModel)
+	})
+
+	suite.Run("MissingConflictColumns", func() {
+		type keyless struct {
+			Name string `bun:"name"`
+		}
+
+		_, err := suite.db.NewUpsert().Model(&keyless{Name: "keyless"}).Exec(suite.ctx)
+		suite.ErrorIs(err, ErrUpsertMissingConflictColumns)
+	})
+}
+
+// TestUpsertMapModel tests upserting map models into a table.
+func (suite *UpsertTestSuite) TestUpsertMapModel() {
+	suite.T().Logf("Testing UPSERT of map models for %s", suite.dbType)
+
+	defer suite.deleteByEmail("upsert-map@example.com")
+
+	row := map[string]any{
+		"id":         "upsert-map",
+		"name":       "Map Original",
+		"email":      "upsert-map@example.com",
+		"age":        50,
+		"is_active":  true,
+		"created_by": "system",
+		"updated_by": "system",
+	}
+
+	for _, name := range []string{"Map Original", "Map Modified"} {
+		row["name"] = name
+
+		_, err := suite.db.NewUpsert().
+			Model(&row).
+			Table("test_user").
+			ConflictColumns("id").
+			Exec(suite.ctx)
+		suite.Require().NoError(err, "Should upsert the map model")
+	}
+
+	suite.Equal("Map Modified", suite.findByEmail("upsert-map@example.com").Name, "Should update the existing row")
+
+	suite.Run("MissingConflictColumns", func() {
+		_, err := suite.db.NewUpsert().Model(&row).Table("test_user").Exec(suite.ctx)
+		suite.ErrorIs(err, ErrUpsertMissingConflictColumns)
+	})
+}
+
+// TestUpsertSQL tests rendering the statement of an upsert without executing it.
+func (suite *UpsertTestSuite) TestUpsertSQL() {
+	suite.T().Logf("Testing UPSERT SQL for %s", suite.dbType)
+
+	query, err := suite.db.NewUpsert().
+		Model(&User{Model: Model{ID: "upsert-sql"}, Name: "SQL", Email: "upsert-sql@example.com"}).
+		UpdateColumns("name").
+		SQL()
+	suite.Require().NoError(err)
+	suite.Contains(query, "INSERT INTO", "Should render the insert")
+	suite.Contains(query, "upsert-sql@example.com", "Should render the values")
+
+	switch suite.dbType {
+	case constants.MySQL:
+		suite.Contains(query, "ON DUPLICATE KEY UPDATE")
+	default:
+		suite.Contains(query, "ON CONFLICT")
+	}
+}
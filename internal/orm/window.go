@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/uptrace/bun/schema"
@@ -39,9 +40,10 @@ type WindowFrameablePartitionBuilder interface {
 	BaseWindowPartitionBuilder[WindowFrameablePartitionBuilder]
 	// Rows configures a ROWS frame clause.
 	Rows() WindowFrameBuilder
-	// Range configures a RANGE frame clause.
+	// Range configures a RANGE frame clause. SQL Server only supports UNBOUNDED and CURRENT ROW bounds.
 	Range() WindowFrameBuilder
-	// Groups configures a GROUPS frame clause.
+	// Groups configures a GROUPS frame clause. MySQL and SQL Server fall back to an equivalent RANGE frame
+	// when the bounds are UNBOUNDED or CURRENT ROW and fail for other bounds.
 	Groups() WindowFrameBuilder
 }
 
@@ -66,9 +68,23 @@ type WindowEndBoundable[T any] interface {
 	UnboundedFollowing() T
 }
 
+// WindowFrameExcludable defines the EXCLUDE clause of window frames.
+// MySQL and SQL Server do not support it: EXCLUDE NO OTHERS, the default, is omitted there and the other exclusions fail.
+type WindowFrameExcludable[T any] interface {
+	// ExcludeCurrentRow excludes the current row from the frame.
+	ExcludeCurrentRow() T
+	// ExcludeGroup excludes the current row and its ordering peers from the frame.
+	ExcludeGroup() T
+	// ExcludeTies excludes the ordering peers of the current row, but not the row itself, from the frame.
+	ExcludeTies() T
+	// ExcludeNoOthers excludes no rows from the frame.
+	ExcludeNoOthers() T
+}
+
 // WindowFrameBuilder defines the window frame builder interface.
 type WindowFrameBuilder interface {
 	WindowStartBoundable[WindowFrameBuilder]
+	WindowFrameExcludable[WindowFrameBuilder]
 
 	// And switches to configuring the end boundary for BETWEEN ... AND ... syntax.
 	And() WindowFrameEndBuilder
@@ -77,6 +93,7 @@ type WindowFrameBuilder interface {
 // WindowFrameEndBuilder defines the window frame end boundary builder interface.
 type WindowFrameEndBuilder interface {
 	WindowEndBoundable[WindowFrameEndBuilder]
+	WindowFrameExcludable[WindowFrameEndBuilder]
 }
 
 // RowNumberBuilder defines the ROW_NUMBER() window function builder.
@@ -303,6 +320,7 @@ type baseWindowExpr struct {
 	frameStartN    int
 	frameEndKind   FrameBoundKind
 	frameEndN      int
+	frameExclusion FrameExclusion
 }
 
 func (w *baseWindowExpr) setArgs(args ...any) {
@@ -446,18 +464,22 @@ func (w *baseWindowExpr) AppendQuery(gen schema.QueryGen, b []byte) (_ []byte, e
 			b = append(b, constants.ByteSpace)
 		}
 
-		b = append(b, w.frameType.String()...)
-
-		b = append(b, constants.ByteSpace)
-		if w.frameEndKind != FrameBoundNone {
-			// Use BETWEEN syntax when both start and end bounds are present
-			b = append(b, "BETWEEN "...)
-			b = w.appendFrameBound(b, w.frameStartKind, w.frameStartN)
-			b = append(b, " AND "...)
-			b = w.appendFrameBound(b, w.frameEndKind, w.frameEndN)
-		} else {
-			b = w.appendFrameBound(b, w.frameStartKind, w.frameStartN)
+		frameBytes, err := w.eb.FragmentByDialect(DialectFragments{
+			MySQL: func() ([]byte, error) {
+				return w.appendCompatibleFrame(nil, true)
+			},
+			SQLServer: func() ([]byte, error) {
+				return w.appendCompatibleFrame(nil, false)
+			},
+			Default: func() ([]byte, error) {
+				return w.appendFrame(nil, w.frameType, w.frameExclusion), nil
+			},
+		})
+		if err != nil {
+			return b, err
 		}
+
+		b = append(b, frameBytes...)
 	}
 
 	b = append(b, constants.ByteRightParenthesis)
@@ -465,6 +487,52 @@ func (w *baseWindowExpr) AppendQuery(gen schema.QueryGen, b []byte) (_ []byte, e
 	return b, nil
 }
 
+func (w *baseWindowExpr) appendFrame(b []byte, frameType FrameType, exclusion FrameExclusion) []byte {
+	b = append(b, frameType.String()...)
+
+	b = append(b, constants.ByteSpace)
+	if w.frameEndKind != FrameBoundNone {
+		// Use BETWEEN syntax when both start and end bounds are present
+		b = append(b, "BETWEEN "...)
+		b = w.appendFrameBound(b, w.frameStartKind, w.frameStartN)
+		b = append(b, " AND "...)
+		b = w.appendFrameBound(b, w.frameEndKind, w.frameEndN)
+	} else {
+		b = w.appendFrameBound(b, w.frameStartKind, w.frameStartN)
+	}
+
+	if exclusion != FrameExcludeDefault {
+		b = append(b, constants.ByteSpace)
+		b = append(b, exclusion.String()...)
+	}
+
+	return b
+}
+
+// appendCompatibleFrame appends the frame for MySQL and SQL Server, which support neither GROUPS frames nor EXCLUDE.
+// A GROUPS frame bounded by UNBOUNDED or CURRENT ROW covers the same rows as the RANGE frame with the same bounds,
+// as both extend CURRENT ROW to the ordering peers, and EXCLUDE NO OTHERS is the default.
+func (w *baseWindowExpr) appendCompatibleFrame(b []byte, rangeOffsets bool) ([]byte, error) {
+	hasOffset := w.frameStartKind == FrameBoundPreceding || w.frameStartKind == FrameBoundFollowing ||
+		w.frameEndKind == FrameBoundPreceding || w.frameEndKind == FrameBoundFollowing
+
+	frameType := w.frameType
+	switch {
+	case frameType == FrameGroups && hasOffset:
+		return nil, fmt.Errorf("%w: GROUPS frames with offset bounds", ErrDialectUnsupportedOperation)
+	case frameType == FrameGroups:
+		frameType = FrameRange
+	case frameType == FrameRange && hasOffset && !rangeOffsets:
+		return nil, fmt.Errorf("%w: RANGE frames with offset bounds", ErrDialectUnsupportedOperation)
+	}
+
+	if w.frameExclusion != FrameExcludeDefault && w.frameExclusion != FrameExcludeNoOthers {
+		return nil, fmt.Errorf("%w: frame exclusion %s", ErrDialectUnsupportedOperation, w.frameExclusion)
+	}
+
+	return w.appendFrame(b, frameType, FrameExcludeDefault), nil
+}
+
 func (*baseWindowExpr) appendFrameBound(b []byte, kind FrameBoundKind, n int) []byte {
 	switch kind {
 	case FrameBoundUnboundedPreceding, FrameBoundUnboundedFollowing, FrameBoundCurrentRow:
@@ -591,6 +659,30 @@ func (b *windowFrameBuilder) Following(n int) WindowFrameBuilder {
 	return b
 }
 
+func (b *windowFrameBuilder) ExcludeCurrentRow() WindowFrameBuilder {
+	b.frameExclusion = FrameExcludeCurrentRow
+
+	return b
+}
+
+func (b *windowFrameBuilder) ExcludeGroup() WindowFrameBuilder {
+	b.frameExclusion = FrameExcludeGroup
+
+	return b
+}
+
+func (b *windowFrameBuilder) ExcludeTies() WindowFrameBuilder {
+	b.frameExclusion = FrameExcludeTies
+
+	return b
+}
+
+func (b *windowFrameBuilder) ExcludeNoOthers() WindowFrameBuilder {
+	b.frameExclusion = FrameExcludeNoOthers
+
+	return b
+}
+
 func (b *windowFrameBuilder) And() WindowFrameEndBuilder {
 	return &windowFrameEndBuilder{baseWindowExpr: b.baseWindowExpr}
 }
@@ -634,6 +726,30 @@ func (b *windowFrameEndBuilder) UnboundedFollowing() WindowFrameEndBuilder {
 	return b
 }
 
+func (b *windowFrameEndBuilder) ExcludeCurrentRow() WindowFrameEndBuilder {
+	b.frameExclusion = FrameExcludeCurrentRow
+
+	return b
+}
+
+func (b *windowFrameEndBuilder) ExcludeGroup() WindowFrameEndBuilder {
+	b.frameExclusion = FrameExcludeGroup
+
+	return b
+}
+
+func (b *windowFrameEndBuilder) ExcludeTies() WindowFrameEndBuilder {
+	b.frameExclusion = FrameExcludeTies
+
+	return b
+}
+
+func (b *windowFrameEndBuilder) ExcludeNoOthers() WindowFrameEndBuilder {
+	b.frameExclusion = FrameExcludeNoOthers
+
+	return b
+}
+
 type baseWindowNullHandlingBuilder[T any] struct {
 	*baseWindowExpr
 
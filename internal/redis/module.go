@@ -6,26 +6,61 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/fx"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
 )
 
 // Module provides Redis client functionality with automatic lifecycle management.
 var Module = fx.Module(
 	"vef:redis",
 	fx.Provide(
-		fx.Annotate(
-			NewClient,
-			fx.OnStart(func(ctx context.Context, client *redis.Client) error {
-				if err := client.Ping(ctx).Err(); err != nil {
-					return fmt.Errorf("failed to connect to redis: %w", err)
-				}
-
-				return logRedisServerInfo(ctx, client)
-			}),
-			fx.OnStop(func(client *redis.Client) error {
-				logger.Info("Closing Redis client...")
-
-				return client.Close()
-			}),
-		),
+		newConfigured,
+		newSharedClient,
 	),
 )
+
+// Configured holds the client when vef.redis sets a host, nil otherwise.
+// Components preferring Redis over memory depend on it, so applications not configuring Redis need no server.
+type Configured struct {
+	Client *redis.Client
+}
+
+func newConfigured(lc fx.Lifecycle, cfg *config.RedisConfig, appCfg *config.AppConfig) *Configured {
+	if cfg.Host == constants.Empty {
+		return new(Configured)
+	}
+
+	return &Configured{Client: newManagedClient(lc, cfg, appCfg)}
+}
+
+// newSharedClient provides the client of the configured Redis, or one of the default address when unconfigured.
+func newSharedClient(lc fx.Lifecycle, configured *Configured, cfg *config.RedisConfig, appCfg *config.AppConfig) *redis.Client {
+	if configured.Client != nil {
+		return configured.Client
+	}
+
+	return newManagedClient(lc, cfg, appCfg)
+}
+
+// newManagedClient creates a client connected on start and closed on stop.
+func newManagedClient(lc fx.Lifecycle, cfg *config.RedisConfig, appCfg *config.AppConfig) *redis.Client {
+	client := NewClient(cfg, appCfg)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := client.Ping(ctx).Err(); err != nil {
+				return fmt.Errorf("failed to connect to redis: %w", err)
+			}
+
+			return logRedisServerInfo(ctx, client)
+		},
+		OnStop: func(context.Context) error {
+			logger.Info("Closing Redis client...")
+
+			return client.Close()
+		},
+	})
+
+	return client
+}
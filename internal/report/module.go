@@ -0,0 +1,40 @@
+package report
+
+import (
+	"go.uber.org/fx"
+
+	"github.com/ilxqx/vef-framework-go/report"
+)
+
+// Module provides the report scheduler.
+var Module = fx.Module(
+	"vef:report",
+	fx.Provide(
+		fx.Annotate(
+			NewScheduler,
+			fx.ParamTags(``, ``, ``, ``, ``, ``, `group:"vef:report:reports"`, `group:"vef:report:renderers"`, `group:"vef:report:deliverers"`),
+			fx.OnStart(func(scheduler *Scheduler) error {
+				if scheduler == nil {
+					return nil
+				}
+
+				return scheduler.Start()
+			}),
+			fx.OnStop(func(scheduler *Scheduler) {
+				if scheduler != nil {
+					scheduler.Stop()
+				}
+			}),
+		),
+		newGenerator,
+	),
+)
+
+// newGenerator exposes the scheduler through the public interface, nil when scheduled reports are disabled.
+func newGenerator(scheduler *Scheduler) report.Generator {
+	if scheduler == nil {
+		return nil
+	}
+
+	return scheduler
+}
@@ -0,0 +1,49 @@
+package report
+
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/ilxqx/vef-framework-go/csv"
+	"github.com/ilxqx/vef-framework-go/excel"
+	"github.com/ilxqx/vef-framework-go/report"
+)
+
+// tabularRenderer renders reports with the exporters of the tabular modules.
+type tabularRenderer struct {
+	format      report.Format
+	contentType string
+	export      func(rowType reflect.Type, rows any) (*bytes.Buffer, error)
+}
+
+func (r *tabularRenderer) Format() report.Format {
+	return r.format
+}
+
+func (r *tabularRenderer) ContentType() string {
+	return r.contentType
+}
+
+func (r *tabularRenderer) Render(rowType reflect.Type, rows any) (*bytes.Buffer, error) {
+	return r.export(rowType, rows)
+}
+
+// builtinRenderers returns the renderers of the formats supported out of the box.
+func builtinRenderers() []report.Renderer {
+	return []report.Renderer{
+		&tabularRenderer{
+			format:      report.FormatXLSX,
+			contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			export: func(rowType reflect.Type, rows any) (*bytes.Buffer, error) {
+				return excel.NewExporter(rowType).Export(rows)
+			},
+		},
+		&tabularRenderer{
+			format:      report.FormatCSV,
+			contentType: "text/csv",
+			export: func(rowType reflect.Type, rows any) (*bytes.Buffer, error) {
+				return csv.NewExporter(rowType).Export(rows)
+			},
+		},
+	}
+}
@@ -0,0 +1,283 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/cron"
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/encoding"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/report"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+var logger = log.Named("report")
+
+const (
+	defaultKeyPrefix = "reports/"
+	jobTag           = "vef:report"
+)
+
+// Scheduler runs the registered reports on their cron schedules.
+// Every run renders the rows of the report in each of its formats, stores the files in the storage and
+// hands signed download links to the deliverers and a generated event.
+// Scheduled runs are recorded in the sys_report_run table, claimed by exactly one application instance.
+type Scheduler struct {
+	cron        cron.Scheduler
+	db          orm.DB
+	storage     storage.Service
+	signer      storage.URLSigner
+	publisher   event.Publisher
+	reports     map[string]report.Report
+	renderers   map[report.Format]report.Renderer
+	deliverers  []report.Deliverer
+	keyPrefix   string
+	linkExpires time.Duration
+	now         func() time.Time
+}
+
+// NewScheduler creates the report scheduler, returning nil when scheduled reports are disabled.
+// Registered renderers replace the built-in renderer of their format.
+func NewScheduler(
+	cfg *config.ReportConfig,
+	db orm.DB,
+	service storage.Service,
+	signer storage.URLSigner,
+	publisher event.Publisher,
+	scheduler cron.Scheduler,
+	reports []report.Report,
+	renderers []report.Renderer,
+	deliverers []report.Deliverer,
+) (*Scheduler, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	s := &Scheduler{
+		cron:        scheduler,
+		db:          db,
+		storage:     service,
+		signer:      signer,
+		publisher:   publisher,
+		reports:     make(map[string]report.Report, len(reports)),
+		renderers:   make(map[report.Format]report.Renderer),
+		deliverers:  deliverers,
+		keyPrefix:   cfg.KeyPrefix,
+		linkExpires: cfg.LinkExpires,
+		now:         time.Now,
+	}
+
+	if s.keyPrefix == "" {
+		s.keyPrefix = defaultKeyPrefix
+	}
+
+	for _, renderer := range append(builtinRenderers(), renderers...) {
+		s.renderers[renderer.Format()] = renderer
+	}
+
+	for _, r := range reports {
+		if _, exists := s.reports[r.Name()]; exists {
+			return nil, fmt.Errorf("duplicate report %q", r.Name())
+		}
+
+		for _, format := range r.Formats() {
+			if _, ok := s.renderers[format]; !ok {
+				return nil, fmt.Errorf("%w: report %q requests format %q", report.ErrRendererNotFound, r.Name(), format)
+			}
+		}
+
+		s.reports[r.Name()] = r
+	}
+
+	return s, nil
+}
+
+// Start schedules the registered reports.
+func (s *Scheduler) Start() error {
+	for name, r := range s.reports {
+		if _, err := s.cron.NewJob(cron.NewCronJob(
+			r.Schedule(),
+			false,
+			cron.WithName("report:"+name),
+			cron.WithTags(jobTag),
+			cron.WithTask(func(ctx context.Context) {
+				s.runScheduled(ctx, name)
+			}),
+		)); err != nil {
+			return fmt.Errorf("failed to schedule report %q: %w", name, err)
+		}
+	}
+
+	logger.Infof("Scheduled %d reports", len(s.reports))
+
+	return nil
+}
+
+// Stop removes the jobs of the reports from the cron scheduler.
+func (s *Scheduler) Stop() {
+	s.cron.RemoveByTags(jobTag)
+}
+
+// runScheduled generates the report for the current minute unless another instance claimed it.
+func (s *Scheduler) runScheduled(ctx context.Context, name string) {
+	if _, err := s.generate(ctx, s.reports[name], s.now().Truncate(time.Minute)); err != nil {
+		logger.Errorf("Failed to generate report %s: %v", name, err)
+	}
+}
+
+func (s *Scheduler) Generate(ctx context.Context, name string) (*report.Run, error) {
+	r, ok := s.reports[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", report.ErrReportNotFound, name)
+	}
+
+	return s.generate(ctx, r, s.now().Truncate(time.Second))
+}
+
+// generate claims the run of the report scheduled at scheduledAt and generates it.
+// Returns a nil run when the run was claimed by another instance.
+func (s *Scheduler) generate(ctx context.Context, r report.Report, scheduledAt time.Time) (*report.Run, error) {
+	run := &report.Run{
+		ReportName:  r.Name(),
+		ScheduledAt: datetime.Of(scheduledAt),
+		Status:      report.StatusRunning,
+	}
+
+	if _, err := s.db.NewInsert().Model(run).Exec(ctx); err != nil {
+		if errors.Is(err, result.ErrRecordAlreadyExists) {
+			logger.Debugf("Report %s scheduled at %s is generated by another instance", r.Name(), scheduledAt.Format(time.DateTime))
+
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to claim report run: %w", err)
+	}
+
+	delivery, err := s.render(ctx, r, run)
+	if err != nil {
+		run.Status = report.StatusFailed
+		run.Error = null.StringFrom(err.Error())
+	} else {
+		run.Status = report.StatusSucceeded
+	}
+
+	run.FinishedAt = null.DateTimeFrom(datetime.Of(s.now()))
+
+	if _, updateErr := s.db.NewUpdate().
+		Model(run).
+		Select("status", "rows", "artifacts", "error", "finished_at").
+		WherePK().
+		Exec(ctx); updateErr != nil {
+		return run, errors.Join(err, fmt.Errorf("failed to record report run: %w", updateErr))
+	}
+
+	if err != nil {
+		return run, err
+	}
+
+	s.deliver(ctx, delivery)
+
+	return run, nil
+}
+
+// render queries the rows of the report and stores them in each format, recording the artifacts in the run.
+func (s *Scheduler) render(ctx context.Context, r report.Report, run *report.Run) (*report.Delivery, error) {
+	rows, err := r.Query(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	if value := reflect.ValueOf(rows); value.Kind() == reflect.Slice {
+		run.Rows = value.Len()
+	}
+
+	scheduledAt := run.ScheduledAt.Unwrap()
+	artifacts := make([]report.Artifact, 0, len(r.Formats()))
+
+	for _, format := range r.Formats() {
+		renderer := s.renderers[format]
+
+		content, err := renderer.Render(r.RowType(), rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", format, err)
+		}
+
+		artifact, err := s.store(ctx, r.Name(), scheduledAt, run.ID, renderer, content)
+		if err != nil {
+			return nil, err
+		}
+
+		artifacts = append(artifacts, *artifact)
+	}
+
+	if run.Artifacts, err = encoding.ToJSON(artifacts); err != nil {
+		return nil, fmt.Errorf("failed to encode artifacts: %w", err)
+	}
+
+	return &report.Delivery{
+		RunID:       run.ID,
+		ReportName:  r.Name(),
+		ScheduledAt: scheduledAt,
+		Rows:        run.Rows,
+		Recipients:  r.Recipients(),
+		Artifacts:   artifacts,
+	}, nil
+}
+
+// store puts the rendered file into the storage and signs its download link.
+func (s *Scheduler) store(
+	ctx context.Context,
+	name string,
+	scheduledAt time.Time,
+	runID string,
+	renderer report.Renderer,
+	content *bytes.Buffer,
+) (*report.Artifact, error) {
+	filename := fmt.Sprintf("%s-%s.%s", name, scheduledAt.Format("20060102150405"), renderer.Format())
+	key := fmt.Sprintf("%s%s/%s/%s", s.keyPrefix, name, runID, filename)
+
+	if _, err := s.storage.PutObject(ctx, storage.PutObjectOptions{
+		Key:         key,
+		Reader:      content,
+		Size:        int64(content.Len()),
+		ContentType: renderer.ContentType(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to store %s: %w", renderer.Format(), err)
+	}
+
+	url, err := s.signer.Sign(ctx, storage.SignURLOptions{
+		Key:      key,
+		Expires:  s.linkExpires,
+		Filename: filename,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign link of %s: %w", renderer.Format(), err)
+	}
+
+	return &report.Artifact{
+		Format: renderer.Format(),
+		Key:    key,
+		URL:    url,
+	}, nil
+}
+
+// deliver hands the generated report to every deliverer and publishes the generated event.
+func (s *Scheduler) deliver(ctx context.Context, delivery *report.Delivery) {
+	for _, deliverer := range s.deliverers {
+		if err := deliverer.Deliver(ctx, delivery); err != nil {
+			logger.Errorf("Failed to deliver report %s: %v", delivery.ReportName, err)
+		}
+	}
+
+	s.publisher.Publish(report.NewGeneratedEvent(delivery))
+}
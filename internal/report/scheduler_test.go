@@ -0,0 +1,238 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/internal/storage/services/memory"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/report"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+var errQueryFailed = errors.New("query failed")
+
+type salesRow struct {
+	Region string `tabular:"Region"`
+	Amount int    `tabular:"Amount"`
+}
+
+type signer struct{}
+
+func (signer) Sign(_ context.Context, opts storage.SignURLOptions) (string, error) {
+	return storage.SignedURLPrefix + opts.Key, nil
+}
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []*report.GeneratedEvent
+}
+
+func (p *recordingPublisher) Publish(evt event.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, evt.(*report.GeneratedEvent))
+}
+
+type recordingDeliverer struct {
+	deliveries []*report.Delivery
+}
+
+func (d *recordingDeliverer) Deliver(_ context.Context, delivery *report.Delivery) error {
+	d.deliveries = append(d.deliveries, delivery)
+
+	return nil
+}
+
+type pdfRenderer struct{}
+
+func (pdfRenderer) Format() report.Format {
+	return report.FormatPDF
+}
+
+func (pdfRenderer) ContentType() string {
+	return "application/pdf"
+}
+
+func (pdfRenderer) Render(reflect.Type, any) (*bytes.Buffer, error) {
+	return bytes.NewBufferString("%PDF"), nil
+}
+
+// SchedulerTestSuite tests generating, storing and delivering reports on SQLite.
+type SchedulerTestSuite struct {
+	suite.Suite
+
+	ctx   context.Context
+	bunDB *bun.DB
+	db    orm.DB
+
+	storage   storage.Service
+	publisher *recordingPublisher
+	deliverer *recordingDeliverer
+	scheduler *Scheduler
+	now       time.Time
+}
+
+func (s *SchedulerTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	var err error
+
+	s.bunDB, err = database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	_, err = s.bunDB.NewCreateTable().Model((*report.Run)(nil)).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	s.db = iorm.New(s.bunDB)
+}
+
+func (s *SchedulerTestSuite) TearDownSuite() {
+	if s.bunDB != nil {
+		s.Require().NoError(s.bunDB.Close())
+	}
+}
+
+func (s *SchedulerTestSuite) SetupTest() {
+	_, err := s.bunDB.NewDelete().Model((*report.Run)(nil)).Where("1 = 1").Exec(s.ctx)
+	s.Require().NoError(err)
+
+	s.storage = memory.New()
+	s.publisher = new(recordingPublisher)
+	s.deliverer = new(recordingDeliverer)
+	s.now = time.Date(2025, 3, 3, 8, 0, 12, 0, time.Local)
+	s.scheduler = s.newScheduler(
+		report.New("sales", "0 8 * * 1", func(context.Context, orm.DB) ([]salesRow, error) {
+			return []salesRow{{Region: "north", Amount: 10}, {Region: "south", Amount: 20}}, nil
+		}, report.WithFormats(report.FormatXLSX, report.FormatCSV, report.FormatPDF), report.WithRecipients("alice")),
+		report.New("broken", "0 8 * * *", func(context.Context, orm.DB) ([]salesRow, error) {
+			return nil, errQueryFailed
+		}),
+	)
+}
+
+func (s *SchedulerTestSuite) newScheduler(reports ...report.Report) *Scheduler {
+	scheduler, err := NewScheduler(
+		&config.ReportConfig{Enabled: true},
+		s.db,
+		s.storage,
+		signer{},
+		s.publisher,
+		nil,
+		reports,
+		[]report.Renderer{pdfRenderer{}},
+		[]report.Deliverer{s.deliverer},
+	)
+	s.Require().NoError(err)
+
+	scheduler.now = func() time.Time { return s.now }
+
+	return scheduler
+}
+
+func (s *SchedulerTestSuite) read(key string) string {
+	reader, err := s.storage.GetObject(s.ctx, storage.GetObjectOptions{Key: key})
+	s.Require().NoError(err)
+
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	s.Require().NoError(err)
+
+	return string(content)
+}
+
+func (s *SchedulerTestSuite) TestGenerate() {
+	run, err := s.scheduler.Generate(s.ctx, "sales")
+	s.Require().NoError(err)
+	s.Equal(report.StatusSucceeded, run.Status)
+	s.Equal(2, run.Rows)
+	s.True(run.FinishedAt.Valid)
+
+	artifacts, err := run.DecodeArtifacts()
+	s.Require().NoError(err)
+	s.Require().Len(artifacts, 3)
+	s.Equal(report.FormatXLSX, artifacts[0].Format)
+	s.Equal("reports/sales/"+run.ID+"/sales-20250303080012.xlsx", artifacts[0].Key)
+	s.Equal(storage.SignedURLPrefix+artifacts[0].Key, artifacts[0].URL)
+	s.Contains(s.read(artifacts[1].Key), "north", "Should render the rows as csv")
+	s.Equal("%PDF", s.read(artifacts[2].Key), "Should render with registered renderers")
+
+	s.Require().Len(s.deliverer.deliveries, 1)
+	s.Equal([]string{"alice"}, s.deliverer.deliveries[0].Recipients)
+	s.Equal(artifacts, s.deliverer.deliveries[0].Artifacts)
+
+	s.Require().Len(s.publisher.events, 1)
+	s.Equal(report.EventTypeGenerated, s.publisher.events[0].Type())
+	s.Equal(run.ID, s.publisher.events[0].RunID)
+
+	var stored report.Run
+	s.Require().NoError(s.db.NewSelect().Model(&stored).Where(func(cb orm.ConditionBuilder) {
+		cb.PKEquals(run.ID)
+	}).Scan(s.ctx))
+	s.Equal(report.StatusSucceeded, stored.Status)
+	s.Equal(run.Artifacts, stored.Artifacts, "Should record the artifacts")
+}
+
+func (s *SchedulerTestSuite) TestScheduledRunClaimedOnce() {
+	other := s.newScheduler(s.scheduler.reports["sales"])
+
+	s.scheduler.runScheduled(s.ctx, "sales")
+
+	s.now = s.now.Add(20 * time.Second)
+	other.runScheduled(s.ctx, "sales")
+
+	count, err := s.db.NewSelect().Model((*report.Run)(nil)).Count(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(1), count, "Should generate a scheduled run on one instance only")
+	s.Len(s.deliverer.deliveries, 1)
+
+	s.now = s.now.Add(time.Minute)
+	other.runScheduled(s.ctx, "sales")
+	s.Len(s.deliverer.deliveries, 2, "Should generate the next schedule again")
+}
+
+func (s *SchedulerTestSuite) TestFailedRun() {
+	run, err := s.scheduler.Generate(s.ctx, "broken")
+	s.Require().ErrorIs(err, errQueryFailed)
+	s.Equal(report.StatusFailed, run.Status)
+	s.Contains(run.Error.ValueOrZero(), errQueryFailed.Error())
+	s.Empty(s.deliverer.deliveries, "Should not deliver failed runs")
+	s.Empty(s.publisher.events)
+}
+
+func (s *SchedulerTestSuite) TestUnknownReport() {
+	_, err := s.scheduler.Generate(s.ctx, "missing")
+	s.ErrorIs(err, report.ErrReportNotFound)
+}
+
+func (s *SchedulerTestSuite) TestMissingRenderer() {
+	_, err := NewScheduler(
+		&config.ReportConfig{Enabled: true},
+		s.db, s.storage, signer{}, s.publisher, nil,
+		[]report.Report{report.New("sales", "0 8 * * 1", func(context.Context, orm.DB) ([]salesRow, error) {
+			return nil, nil
+		}, report.WithFormats(report.FormatPDF))},
+		nil, nil,
+	)
+	s.ErrorIs(err, report.ErrRendererNotFound, "Should reject formats without renderer")
+}
+
+func TestScheduler(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
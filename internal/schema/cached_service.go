@@ -0,0 +1,169 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/cache"
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	ilog "github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/log"
+	"github.com/ilxqx/vef-framework-go/schema"
+)
+
+const (
+	// defaultCheckInterval is the minimum interval between schema version checks.
+	defaultCheckInterval = 30 * time.Second
+	// listKey is the cache key of schema listings.
+	listKey = "all"
+)
+
+// CachedService is a decorator that adds caching to a schema.Service.
+// Cached schemas are invalidated by schema change events and, when a version query is configured,
+// whenever the schema version it returns changes.
+type CachedService struct {
+	service       schema.Service
+	tableCache    cache.Cache[[]schema.Table]
+	schemaCache   cache.Cache[*schema.TableSchema]
+	viewCache     cache.Cache[[]schema.View]
+	triggerCache  cache.Cache[[]schema.Trigger]
+	db            *sql.DB
+	versionQuery  string
+	checkInterval time.Duration
+	logger        log.Logger
+
+	mu        sync.Mutex
+	version   string
+	checkedAt time.Time
+}
+
+// NewCachedService creates a new cached schema service.
+// It automatically subscribes to schema change events to invalidate cache.
+func NewCachedService(
+	service schema.Service,
+	db *sql.DB,
+	cfg *config.SchemaCacheConfig,
+	subscriber event.Subscriber,
+) *CachedService {
+	cached := &CachedService{
+		service:       service,
+		tableCache:    cache.NewMemory[[]schema.Table](),
+		schemaCache:   cache.NewMemory[*schema.TableSchema](),
+		viewCache:     cache.NewMemory[[]schema.View](),
+		triggerCache:  cache.NewMemory[[]schema.Trigger](),
+		db:            db,
+		versionQuery:  cfg.VersionQuery,
+		checkInterval: cfg.CheckInterval,
+		logger:        ilog.Named("schema:cached_service"),
+	}
+
+	if cached.checkInterval <= 0 {
+		cached.checkInterval = defaultCheckInterval
+	}
+
+	if subscriber != nil {
+		subscriber.Subscribe(schema.EventTypeSchemaChanged, cached.handleSchemaChanged)
+	}
+
+	return cached
+}
+
+func (c *CachedService) handleSchemaChanged(ctx context.Context, evt event.Event) {
+	changeEvent, ok := evt.(*schema.SchemaChangedEvent)
+	if !ok {
+		c.logger.Errorf("Received invalid event type: %T", evt)
+
+		return
+	}
+
+	c.Invalidate(ctx, changeEvent.Tables...)
+}
+
+// Invalidate removes the cached schemas of the given tables, or all cached schemas when no table is given.
+// Listings are always removed since they may include the changed tables.
+func (c *CachedService) Invalidate(ctx context.Context, tables ...string) {
+	if len(tables) == 0 {
+		if err := c.schemaCache.Clear(ctx); err != nil {
+			c.logger.Errorf("Failed to clear table schema cache: %v", err)
+		}
+	}
+
+	for _, table := range tables {
+		if err := c.schemaCache.Delete(ctx, table); err != nil {
+			c.logger.Errorf("Failed to delete schema cache for table %s: %v", table, err)
+		}
+	}
+
+	for _, listCache := range []interface{ Clear(context.Context) error }{c.tableCache, c.viewCache, c.triggerCache} {
+		if err := listCache.Clear(ctx); err != nil {
+			c.logger.Errorf("Failed to clear schema listing cache: %v", err)
+		}
+	}
+
+	if len(tables) == 0 {
+		c.logger.Info("Cleared schema cache")
+	} else {
+		c.logger.Infof("Cleared schema cache for tables: %v", tables)
+	}
+}
+
+// checkVersion invalidates the cache when the schema version changed since the last check.
+// The version is queried at most once per check interval; failed checks keep the cache.
+func (c *CachedService) checkVersion(ctx context.Context) {
+	if c.versionQuery == constants.Empty {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.checkedAt) < c.checkInterval {
+		return
+	}
+
+	var version sql.NullString
+	if err := c.db.QueryRowContext(ctx, c.versionQuery).Scan(&version); err != nil {
+		c.logger.Warnf("Failed to query schema version: %v", err)
+
+		return
+	}
+
+	changed := !c.checkedAt.IsZero() && version.String != c.version
+	c.version = version.String
+	c.checkedAt = time.Now()
+
+	if changed {
+		c.logger.Infof("Schema version changed to %q", version.String)
+		c.Invalidate(ctx)
+	}
+}
+
+func (c *CachedService) ListTables(ctx context.Context) ([]schema.Table, error) {
+	c.checkVersion(ctx)
+
+	return c.tableCache.GetOrLoad(ctx, listKey, c.service.ListTables)
+}
+
+func (c *CachedService) GetTableSchema(ctx context.Context, name string) (*schema.TableSchema, error) {
+	c.checkVersion(ctx)
+
+	return c.schemaCache.GetOrLoad(ctx, name, func(ctx context.Context) (*schema.TableSchema, error) {
+		return c.service.GetTableSchema(ctx, name)
+	})
+}
+
+func (c *CachedService) ListViews(ctx context.Context) ([]schema.View, error) {
+	c.checkVersion(ctx)
+
+	return c.viewCache.GetOrLoad(ctx, listKey, c.service.ListViews)
+}
+
+func (c *CachedService) ListTriggers(ctx context.Context) ([]schema.Trigger, error) {
+	c.checkVersion(ctx)
+
+	return c.triggerCache.GetOrLoad(ctx, listKey, c.service.ListTriggers)
+}
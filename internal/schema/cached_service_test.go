@@ -0,0 +1,146 @@
+package schema_test
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	ievent "github.com/ilxqx/vef-framework-go/internal/event"
+	"github.com/ilxqx/vef-framework-go/internal/schema"
+	pkgschema "github.com/ilxqx/vef-framework-go/schema"
+)
+
+// countingService counts the calls reaching the underlying service.
+type countingService struct {
+	pkgschema.Service
+
+	tableCalls  atomic.Int32
+	schemaCalls atomic.Int32
+}
+
+func (s *countingService) ListTables(ctx context.Context) ([]pkgschema.Table, error) {
+	s.tableCalls.Add(1)
+
+	return s.Service.ListTables(ctx)
+}
+
+func (s *countingService) GetTableSchema(ctx context.Context, name string) (*pkgschema.TableSchema, error) {
+	s.schemaCalls.Add(1)
+
+	return s.Service.GetTableSchema(ctx, name)
+}
+
+// CachedServiceTestSuite tests the CachedService decorator.
+type CachedServiceTestSuite struct {
+	suite.Suite
+
+	ctx     context.Context
+	db      *sql.DB
+	bus     event.Bus
+	counter *countingService
+	service *schema.CachedService
+}
+
+func (suite *CachedServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+
+	dsConfig := &config.DatasourceConfig{
+		Type: constants.SQLite,
+	}
+
+	bunDB, err := database.New(dsConfig)
+	suite.Require().NoError(err)
+
+	suite.db = bunDB.DB
+
+	_, err = suite.db.ExecContext(suite.ctx, "CREATE TABLE schema_version (version INTEGER NOT NULL)")
+	suite.Require().NoError(err)
+	_, err = suite.db.ExecContext(suite.ctx, "INSERT INTO schema_version (version) VALUES (1)")
+	suite.Require().NoError(err)
+	_, err = suite.db.ExecContext(suite.ctx, "CREATE TABLE cached_item (id INTEGER PRIMARY KEY)")
+	suite.Require().NoError(err)
+
+	service, err := schema.NewService(suite.db, dsConfig)
+	suite.Require().NoError(err)
+
+	suite.bus = ievent.NewMemoryBus(nil)
+	suite.Require().NoError(suite.bus.Start())
+
+	suite.counter = &countingService{Service: service}
+	suite.service = schema.NewCachedService(suite.counter, suite.db, &config.SchemaCacheConfig{
+		Enabled:       true,
+		VersionQuery:  "SELECT MAX(version) FROM schema_version",
+		CheckInterval: time.Millisecond,
+	}, suite.bus)
+}
+
+func (suite *CachedServiceTestSuite) TearDownTest() {
+	suite.NoError(suite.bus.Shutdown(suite.ctx))
+	suite.NoError(suite.db.Close())
+}
+
+func (suite *CachedServiceTestSuite) columns() []string {
+	tableSchema, err := suite.service.GetTableSchema(suite.ctx, "cached_item")
+	suite.Require().NoError(err)
+
+	names := make([]string, len(tableSchema.Columns))
+	for i, column := range tableSchema.Columns {
+		names[i] = column.Name
+	}
+
+	return names
+}
+
+func (suite *CachedServiceTestSuite) TestCachesSchemas() {
+	suite.Equal([]string{"id"}, suite.columns())
+	suite.Equal([]string{"id"}, suite.columns())
+	suite.Equal(int32(1), suite.counter.schemaCalls.Load(), "Table schema should be inspected once")
+
+	_, err := suite.service.ListTables(suite.ctx)
+	suite.Require().NoError(err)
+	_, err = suite.service.ListTables(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(int32(1), suite.counter.tableCalls.Load(), "Tables should be listed once")
+}
+
+func (suite *CachedServiceTestSuite) TestInvalidatesOnSchemaChangedEvent() {
+	suite.Equal([]string{"id"}, suite.columns())
+
+	_, err := suite.db.ExecContext(suite.ctx, "ALTER TABLE cached_item ADD COLUMN name TEXT")
+	suite.Require().NoError(err)
+	suite.Equal([]string{"id"}, suite.columns(), "Stale schema should be served until invalidated")
+
+	pkgschema.PublishSchemaChangedEvent(suite.bus, "cached_item")
+
+	suite.Eventually(func() bool {
+		return len(suite.columns()) == 2
+	}, time.Second, 10*time.Millisecond, "Schema should be reloaded after the change event")
+}
+
+func (suite *CachedServiceTestSuite) TestInvalidatesOnVersionChange() {
+	suite.Equal([]string{"id"}, suite.columns())
+
+	_, err := suite.db.ExecContext(suite.ctx, "ALTER TABLE cached_item ADD COLUMN name TEXT")
+	suite.Require().NoError(err)
+
+	time.Sleep(5 * time.Millisecond)
+	suite.Equal([]string{"id"}, suite.columns(), "Schema should be kept while the version is unchanged")
+
+	_, err = suite.db.ExecContext(suite.ctx, "INSERT INTO schema_version (version) VALUES (2)")
+	suite.Require().NoError(err)
+
+	time.Sleep(5 * time.Millisecond)
+	suite.Equal([]string{"id", "name"}, suite.columns(), "Schema should be reloaded after the version changed")
+}
+
+func TestCachedServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(CachedServiceTestSuite))
+}
@@ -1,14 +1,27 @@
 package schema
 
 import (
+	"database/sql"
+
 	"go.uber.org/fx"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/schema"
 )
 
 // Module is the FX module for schema inspection functionality.
 var Module = fx.Module(
 	"vef:schema",
 	fx.Provide(
-		NewService,
+		func(db *sql.DB, dsConfig *config.DatasourceConfig, subscriber event.Subscriber) (schema.Service, error) {
+			service, err := NewService(db, dsConfig)
+			if err != nil || !dsConfig.SchemaCache.Enabled {
+				return service, err
+			}
+
+			return NewCachedService(service, db, &dsConfig.SchemaCache, subscriber), nil
+		},
 		fx.Annotate(
 			NewResource,
 			fx.ResultTags(`group:"vef:api:resources"`),
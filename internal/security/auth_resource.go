@@ -1,9 +1,12 @@
 package security
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v3"
 
 	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/config"
 	"github.com/ilxqx/vef-framework-go/constants"
 	"github.com/ilxqx/vef-framework-go/contextx"
 	"github.com/ilxqx/vef-framework-go/event"
@@ -14,12 +17,15 @@ import (
 )
 
 // NewAuthResource creates a new authentication resource with the provided auth manager and token generator.
-func NewAuthResource(authManager security.AuthManager, tokenGenerator security.TokenGenerator, userInfoLoader security.UserInfoLoader, publisher event.Publisher) api.Resource {
+// Client addresses are resolved through the trusted proxies of the IP filter configuration.
+func NewAuthResource(authManager security.AuthManager, tokenGenerator security.TokenGenerator, userInfoLoader security.UserInfoLoader, sessions security.SessionManager, publisher event.Publisher, securityConfig *config.SecurityConfig) api.Resource {
 	return &AuthResource{
 		authManager:    authManager,
 		tokenGenerator: tokenGenerator,
 		userInfoLoader: userInfoLoader,
+		sessions:       sessions,
 		publisher:      publisher,
+		trustedProxies: security.NewTrustedProxies(securityConfig.IPFilter.TrustedProxies),
 		Resource: api.NewRPCResource(
 			"security/auth",
 			api.WithOperations(
@@ -51,7 +57,9 @@ type AuthResource struct {
 	authManager    security.AuthManager
 	tokenGenerator security.TokenGenerator
 	userInfoLoader security.UserInfoLoader
+	sessions       security.SessionManager
 	publisher      event.Publisher
+	trustedProxies *security.TrustedProxies
 }
 
 // LoginParams represents the request parameters for user login.
@@ -64,8 +72,10 @@ type LoginParams struct {
 
 // Login authenticates a user and returns token credentials.
 func (a *AuthResource) Login(ctx fiber.Ctx, params LoginParams) error {
-	loginIP := webhelpers.GetIP(ctx)
-	userAgent := ctx.Get(fiber.HeaderUserAgent)
+	// Request values are only valid during the request, so the ones handed to events and sessions are copied.
+	loginIP := webhelpers.GetClientIP(ctx, a.trustedProxies.Contains)
+	userAgent := strings.Clone(ctx.Get(fiber.HeaderUserAgent))
+	deviceID := strings.Clone(ctx.Get(security.HeaderDeviceID))
 	traceID := contextx.RequestID(ctx)
 	username := params.Principal
 
@@ -90,6 +100,7 @@ func (a *AuthResource) Login(ctx fiber.Ctx, params LoginParams) error {
 			Username:   username,
 			LoginIP:    loginIP,
 			UserAgent:  userAgent,
+			DeviceID:   deviceID,
 			TraceID:    traceID,
 			IsOk:       false,
 			FailReason: failReason,
@@ -100,6 +111,16 @@ func (a *AuthResource) Login(ctx fiber.Ctx, params LoginParams) error {
 		return err
 	}
 
+	if a.sessions != nil {
+		if _, err := a.sessions.Create(ctx.Context(), principal, security.DeviceInfo{
+			DeviceID:  deviceID,
+			IP:        loginIP,
+			UserAgent: userAgent,
+		}); err != nil {
+			return err
+		}
+	}
+
 	credentials, err := a.tokenGenerator.Generate(principal)
 	if err != nil {
 		return err
@@ -111,6 +132,7 @@ func (a *AuthResource) Login(ctx fiber.Ctx, params LoginParams) error {
 		Username:   username,
 		LoginIP:    loginIP,
 		UserAgent:  userAgent,
+		DeviceID:   deviceID,
 		TraceID:    traceID,
 		IsOk:       true,
 		FailReason: constants.Empty,
@@ -147,9 +169,27 @@ func (a *AuthResource) Refresh(ctx fiber.Ctx, params RefreshParams) error {
 	return result.Ok(credentials).Response(ctx)
 }
 
-// Logout returns success immediately.
-// Token invalidation should be handled on the client side by removing stored tokens.
-func (*AuthResource) Logout(ctx fiber.Ctx) error {
+// Logout revokes the current session when session management is enabled, which invalidates its tokens.
+// Otherwise token invalidation should be handled on the client side by removing stored tokens.
+// A LogoutEvent is published in either case.
+func (a *AuthResource) Logout(ctx fiber.Ctx, principal *security.Principal) error {
+	// Impersonation sessions belong to the impersonating operator.
+	if a.sessions != nil && principal.SessionID != constants.Empty {
+		if err := a.sessions.Revoke(ctx.Context(), principal.Operator().ID, principal.SessionID); err != nil {
+			return err
+		}
+	}
+
+	logoutEvent := security.NewLogoutEvent(security.LogoutEventParams{
+		UserID:    principal.ID,
+		Username:  principal.Name,
+		LogoutIP:  webhelpers.GetClientIP(ctx, a.trustedProxies.Contains),
+		UserAgent: strings.Clone(ctx.Get(fiber.HeaderUserAgent)),
+		DeviceID:  strings.Clone(ctx.Get(security.HeaderDeviceID)),
+		TraceID:   contextx.RequestID(ctx),
+	})
+	a.publisher.Publish(logoutEvent)
+
 	return result.Ok().Response(ctx)
 }
 
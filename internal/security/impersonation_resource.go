@@ -0,0 +1,212 @@
+package security
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/samber/lo"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/i18n"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/security"
+	"github.com/ilxqx/vef-framework-go/webhelpers"
+)
+
+const (
+	permTokenImpersonate        = "sys.user.impersonate"
+	defaultImpersonationExpires = time.Hour
+)
+
+// NewImpersonationResource creates the resource letting administrators act as another user.
+func NewImpersonationResource(
+	userLoader security.UserLoader,
+	tokenGenerator security.TokenGenerator,
+	sessions security.SessionManager,
+	permissionsLoader security.RolePermissionsLoader,
+	securityConfig *config.SecurityConfig,
+) api.Resource {
+	expires := securityConfig.ImpersonationExpires
+	if expires <= 0 {
+		expires = defaultImpersonationExpires
+	}
+
+	return &ImpersonationResource{
+		userLoader:     userLoader,
+		tokenGenerator: tokenGenerator,
+		sessions:       sessions,
+		guard:          impersonationGuard{loader: permissionsLoader},
+		expires:        expires,
+		trustedProxies: security.NewTrustedProxies(securityConfig.IPFilter.TrustedProxies),
+		Resource: api.NewRPCResource(
+			"security/impersonation",
+			api.WithOperations(
+				api.OperationSpec{
+					Action:      "start",
+					PermToken:   permTokenImpersonate,
+					EnableAudit: true,
+				},
+				api.OperationSpec{
+					Action:      "stop",
+					EnableAudit: true,
+				},
+			),
+		),
+	}
+}
+
+// ImpersonationResource handles impersonation Api endpoints.
+// Impersonation tokens authenticate as the impersonated user, so permissions, data scopes and audit columns
+// are those of the user, while the operator is kept in Principal.Impersonator and recorded in audit events.
+type ImpersonationResource struct {
+	api.Resource
+
+	userLoader     security.UserLoader
+	tokenGenerator security.TokenGenerator
+	sessions       security.SessionManager
+	guard          impersonationGuard
+	expires        time.Duration
+	trustedProxies *security.TrustedProxies
+}
+
+// StartImpersonationParams represents the request parameters for impersonating a user.
+type StartImpersonationParams struct {
+	api.P
+
+	UserID string `json:"userId" validate:"required"`
+}
+
+// Start returns tokens acting as the user, who may hold no permission the operator lacks. The client keeps the tokens
+// of the operator to return to them. The tokens expire with the impersonation, refreshes included; with session
+// management enabled they get a session of the operator, ended by Stop.
+func (r *ImpersonationResource) Start(ctx fiber.Ctx, principal *security.Principal, params StartImpersonationParams) error {
+	if r.userLoader == nil {
+		return result.ErrNotImplemented(i18n.T(result.ErrMessageUserLoaderNotImplemented))
+	}
+
+	// Impersonation cannot be chained, which would hide the operator.
+	if principal.Type != security.PrincipalTypeUser || principal.IsImpersonated() || params.UserID == principal.ID {
+		return result.ErrImpersonationNotAllowed
+	}
+
+	target, err := r.userLoader.LoadByID(ctx.Context(), params.UserID)
+	if err != nil {
+		return err
+	}
+
+	if target == nil {
+		return result.ErrRecordNotFound
+	}
+
+	if err := r.guard.check(ctx.Context(), principal, target); err != nil {
+		return err
+	}
+
+	target.Impersonator = security.NewUser(principal.ID, principal.Name)
+	target.ImpersonationExpiresAt = time.Now().Add(r.expires)
+
+	if r.sessions != nil {
+		// The device id is left out, a session of the device would replace the session of the operator.
+		session, err := r.sessions.Create(ctx.Context(), principal, security.DeviceInfo{
+			IP:        webhelpers.GetClientIP(ctx, r.trustedProxies.Contains),
+			UserAgent: strings.Clone(ctx.Get(fiber.HeaderUserAgent)),
+		})
+		if err != nil {
+			return err
+		}
+
+		target.SessionID = session.ID
+	}
+
+	credentials, err := r.tokenGenerator.Generate(target)
+	if err != nil {
+		return err
+	}
+
+	return result.Ok(credentials).Response(ctx)
+}
+
+// Stop ends the impersonation by revoking its session, which invalidates its tokens.
+// Without session management the client discards the impersonation tokens.
+func (r *ImpersonationResource) Stop(ctx fiber.Ctx, principal *security.Principal) error {
+	if !principal.IsImpersonated() {
+		return result.ErrImpersonationNotAllowed
+	}
+
+	if r.sessions != nil && principal.SessionID != constants.Empty {
+		if err := r.sessions.Revoke(ctx.Context(), principal.Impersonator.ID, principal.SessionID); err != nil {
+			return err
+		}
+	}
+
+	return result.Ok().Response(ctx)
+}
+
+// impersonationGuard decides whether an operator may act as a user: the operator must hold the impersonate
+// permission and every permission of the user with a data scope at least as broad, so impersonating never gains
+// privileges. Without a RolePermissionsLoader the roles of the user must be roles of the operator.
+type impersonationGuard struct {
+	loader security.RolePermissionsLoader
+}
+
+// check returns ErrImpersonationNotAllowed unless the operator may act as the target.
+func (g impersonationGuard) check(ctx context.Context, operator, target *security.Principal) error {
+	if g.loader == nil {
+		if !lo.Every(operator.Roles, target.Roles) {
+			return result.ErrImpersonationNotAllowed
+		}
+
+		return nil
+	}
+
+	operatorScopes, err := g.scopes(ctx, operator.Roles)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := operatorScopes[permTokenImpersonate]; !ok {
+		return result.ErrImpersonationNotAllowed
+	}
+
+	targetScopes, err := g.scopes(ctx, target.Roles)
+	if err != nil {
+		return err
+	}
+
+	for token, priority := range targetScopes {
+		if operatorPriority, ok := operatorScopes[token]; !ok || operatorPriority < priority {
+			return result.ErrImpersonationNotAllowed
+		}
+	}
+
+	return nil
+}
+
+// scopes returns the priority of the broadest data scope of each permission of the roles, the scope that applies.
+func (g impersonationGuard) scopes(ctx context.Context, roles []string) (map[string]int, error) {
+	scopes := make(map[string]int)
+
+	for _, role := range roles {
+		permissions, err := g.loader.LoadPermissions(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+
+		for token, scope := range permissions {
+			priority := -1
+			if scope != nil {
+				priority = scope.Priority()
+			}
+
+			if current, ok := scopes[token]; !ok || priority > current {
+				scopes[token] = priority
+			}
+		}
+	}
+
+	return scopes, nil
+}
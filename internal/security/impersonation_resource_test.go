@@ -0,0 +1,334 @@
+package security_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/fx"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/encoding"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/app"
+	"github.com/ilxqx/vef-framework-go/internal/apptest"
+	isecurity "github.com/ilxqx/vef-framework-go/internal/security"
+	"github.com/ilxqx/vef-framework-go/password"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+// staticRolePermissions grants fixed permissions per role.
+type staticRolePermissions map[string][]string
+
+func (s staticRolePermissions) LoadPermissions(_ context.Context, role string) (map[string]security.DataScope, error) {
+	permissions := make(map[string]security.DataScope)
+	for _, token := range s[role] {
+		permissions[token] = security.NewAllDataScope()
+	}
+
+	return permissions, nil
+}
+
+// staticUserLoader loads fixed users, returning copies since loaded principals are modified by the framework.
+type staticUserLoader struct {
+	users          []*security.Principal
+	hashedPassword string
+}
+
+func (l *staticUserLoader) LoadByUsername(_ context.Context, username string) (*security.Principal, string, error) {
+	for _, user := range l.users {
+		if user.Name == username {
+			return security.NewUser(user.ID, user.Name, user.Roles...), l.hashedPassword, nil
+		}
+	}
+
+	return nil, constants.Empty, nil
+}
+
+func (l *staticUserLoader) LoadByID(_ context.Context, id string) (*security.Principal, error) {
+	for _, user := range l.users {
+		if user.ID == id {
+			return security.NewUser(user.ID, user.Name, user.Roles...), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ImpersonationResourceTestSuite is the test suite for admin impersonation.
+type ImpersonationResourceTestSuite struct {
+	suite.Suite
+
+	ctx       context.Context
+	app       *app.App
+	stop      func()
+	publisher *MockPublisher
+	sessions  security.SessionManager
+	jwt       *security.JWT
+	users     *staticUserLoader
+	admin     *security.Principal
+	user      *security.Principal
+	auditor   *security.Principal
+}
+
+// SetupSuite runs once before all tests in the suite.
+func (suite *ImpersonationResourceTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	suite.admin = security.NewUser("admin001", "admin", "admin")
+	suite.user = security.NewUser("user001", "testuser", "user")
+	suite.auditor = security.NewUser("auditor001", "auditor", "auditor")
+	suite.publisher = new(MockPublisher)
+
+	hashedPassword, err := password.NewBcryptEncoder().Encode("password123")
+	suite.Require().NoError(err)
+
+	suite.users = &staticUserLoader{
+		users:          []*security.Principal{suite.admin, suite.user, suite.auditor},
+		hashedPassword: hashedPassword,
+	}
+
+	suite.app, suite.stop = apptest.NewTestApp(
+		suite.T(),
+		fx.Supply(
+			fx.Annotate(
+				suite.users,
+				fx.As(new(security.UserLoader)),
+			),
+			fx.Annotate(
+				staticRolePermissions{"admin": {"sys.user.impersonate"}, "auditor": {"sys.audit.query"}},
+				fx.As(new(security.RolePermissionsLoader)),
+			),
+		),
+		fx.Replace(
+			fx.Annotate(
+				suite.publisher,
+				fx.As(new(event.Publisher)),
+			),
+		),
+		fx.Replace(
+			&config.DatasourceConfig{
+				Type: "sqlite",
+			},
+			&config.SecurityConfig{
+				TokenExpires: 24 * time.Hour,
+				Session: config.SessionConfig{
+					Enabled: true,
+				},
+			},
+			&security.JWTConfig{
+				Secret:   "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Audience: "test-app",
+			},
+		),
+		fx.Populate(&suite.sessions, &suite.jwt),
+		fx.Invoke(func() {
+			suite.publisher.On("Publish", mock.Anything).
+				Maybe()
+		}),
+	)
+}
+
+// TearDownSuite runs once after all tests in the suite.
+func (suite *ImpersonationResourceTestSuite) TearDownSuite() {
+	if suite.stop != nil {
+		suite.stop()
+	}
+}
+
+// SetupTest signs all users out before each test.
+func (suite *ImpersonationResourceTestSuite) SetupTest() {
+	suite.Require().NoError(suite.sessions.RevokeAll(suite.ctx, suite.admin.ID))
+	suite.Require().NoError(suite.sessions.RevokeAll(suite.ctx, suite.user.ID))
+	suite.publisher.ClearPublishedEvents()
+}
+
+func (suite *ImpersonationResourceTestSuite) request(action string, params map[string]any, token string) result.Result {
+	resource := "security/impersonation"
+	if action == "login" || action == "refresh" || action == "logout" {
+		resource = "security/auth"
+	}
+
+	jsonBody, err := encoding.ToJSON(api.Request{
+		Identifier: api.Identifier{Resource: resource, Action: action, Version: "v1"},
+		Params:     params,
+	})
+	suite.Require().NoError(err, "Should encode request to JSON")
+
+	req := httptest.NewRequest(fiber.MethodPost, "/api", strings.NewReader(jsonBody))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	if token != constants.Empty {
+		req.Header.Set(fiber.HeaderAuthorization, constants.AuthSchemeBearer+" "+token)
+	}
+
+	resp, err := suite.app.Test(req, 30*time.Second)
+	suite.Require().NoError(err, "Api request should not fail")
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err, "Should read response body")
+
+	res, err := encoding.FromJSON[result.Result](string(data))
+	suite.Require().NoError(err, "Should decode response JSON")
+
+	return *res
+}
+
+func (suite *ImpersonationResourceTestSuite) tokens(body result.Result) (accessToken, refreshToken string) {
+	suite.Require().True(body.IsOk(), "Request should succeed: %s", body.Message)
+
+	tokens := body.Data.(map[string]any)
+
+	return tokens["accessToken"].(string), tokens["refreshToken"].(string)
+}
+
+func (suite *ImpersonationResourceTestSuite) login(username string) string {
+	accessToken, _ := suite.tokens(suite.request("login", map[string]any{
+		"kind":        isecurity.AuthKindPassword,
+		"principal":   username,
+		"credentials": "password123",
+	}, constants.Empty))
+
+	return accessToken
+}
+
+func (suite *ImpersonationResourceTestSuite) impersonate(token, userID string) result.Result {
+	return suite.request("start", map[string]any{"userId": userID}, token)
+}
+
+func (suite *ImpersonationResourceTestSuite) lastAuditEvent() *api.AuditEvent {
+	events := suite.publisher.GetPublishedEvents()
+	for i := len(events) - 1; i >= 0; i-- {
+		if evt, ok := events[i].(*api.AuditEvent); ok {
+			return evt
+		}
+	}
+
+	suite.FailNow("Should publish an audit event")
+
+	return nil
+}
+
+// TestImpersonate tests acting as the user and recording the operator in audit events.
+func (suite *ImpersonationResourceTestSuite) TestImpersonate() {
+	adminToken := suite.login("admin")
+
+	accessToken, _ := suite.tokens(suite.impersonate(adminToken, suite.user.ID))
+
+	evt := suite.lastAuditEvent()
+	suite.Equal(suite.admin.ID, evt.UserID, "Should audit the start as the operator")
+	suite.Empty(evt.ImpersonatorID)
+
+	body := suite.request("stop", nil, accessToken)
+	suite.Require().True(body.IsOk(), "Stop should succeed: %s", body.Message)
+
+	evt = suite.lastAuditEvent()
+	suite.Equal(suite.user.ID, evt.UserID, "Should act as the impersonated user")
+	suite.Equal(suite.admin.ID, evt.ImpersonatorID, "Should record the real operator")
+
+	body = suite.request("stop", nil, accessToken)
+	suite.Equal(result.ErrCodeSessionRevoked, body.Code, "Should invalidate the impersonation tokens on stop")
+
+	body = suite.request("stop", nil, adminToken)
+	suite.Equal(result.ErrCodeImpersonationNotAllowed, body.Code, "Should keep the session of the operator")
+}
+
+// TestRefreshKeepsImpersonating tests that refreshed impersonation tokens keep the operator.
+func (suite *ImpersonationResourceTestSuite) TestRefreshKeepsImpersonating() {
+	_, refreshToken := suite.tokens(suite.impersonate(suite.login("admin"), suite.user.ID))
+
+	accessToken, _ := suite.tokens(suite.request("refresh", map[string]any{"refreshToken": refreshToken}, constants.Empty))
+
+	body := suite.request("stop", nil, accessToken)
+	suite.Require().True(body.IsOk(), "Stop should succeed: %s", body.Message)
+	suite.Equal(suite.admin.ID, suite.lastAuditEvent().ImpersonatorID)
+}
+
+// TestLogoutEndsImpersonation tests that logging out an impersonation revokes its session only.
+func (suite *ImpersonationResourceTestSuite) TestLogoutEndsImpersonation() {
+	adminToken := suite.login("admin")
+	accessToken, _ := suite.tokens(suite.impersonate(adminToken, suite.user.ID))
+
+	suite.Require().True(suite.request("logout", nil, accessToken).IsOk(), "Logout should succeed")
+
+	suite.Equal(result.ErrCodeSessionRevoked, suite.request("stop", nil, accessToken).Code)
+	suite.Equal(result.ErrCodeImpersonationNotAllowed, suite.request("stop", nil, adminToken).Code, "Should keep the session of the operator")
+}
+
+// TestNotAllowed tests the impersonations rejected.
+func (suite *ImpersonationResourceTestSuite) TestNotAllowed() {
+	body := suite.impersonate(suite.login("testuser"), suite.admin.ID)
+	suite.Equal(result.ErrCodeAccessDenied, body.Code, "Should require the impersonate permission")
+
+	adminToken := suite.login("admin")
+
+	body = suite.impersonate(adminToken, suite.admin.ID)
+	suite.Equal(result.ErrCodeImpersonationNotAllowed, body.Code, "Should reject impersonating oneself")
+
+	body = suite.impersonate(adminToken, "missing")
+	suite.Equal(result.ErrCodeRecordNotFound, body.Code, "Should reject unknown users")
+
+	body = suite.impersonate(adminToken, suite.auditor.ID)
+	suite.Equal(result.ErrCodeImpersonationNotAllowed, body.Code, "Should reject users holding permissions the operator lacks")
+}
+
+// TestRefreshRevalidatesOperator tests that impersonation tokens are not refreshed once the operator is gone.
+func (suite *ImpersonationResourceTestSuite) TestRefreshRevalidatesOperator() {
+	_, refreshToken := suite.tokens(suite.impersonate(suite.login("admin"), suite.user.ID))
+
+	users := suite.users.users
+	suite.users.users = []*security.Principal{suite.user, suite.auditor}
+
+	defer func() {
+		suite.users.users = users
+	}()
+
+	body := suite.request("refresh", map[string]any{"refreshToken": refreshToken}, constants.Empty)
+	suite.Equal(result.ErrCodeImpersonationNotAllowed, body.Code, "Should not refresh impersonations of removed operators")
+}
+
+// TestImpersonationExpires tests that impersonations end at their absolute expiry, refreshes included.
+func (suite *ImpersonationResourceTestSuite) TestImpersonationExpires() {
+	accessToken, refreshToken := suite.tokens(suite.impersonate(suite.login("admin"), suite.user.ID))
+
+	for _, token := range []string{accessToken, refreshToken} {
+		claims, err := suite.jwt.Parse(token)
+		suite.Require().NoError(err)
+
+		expiresAt := claims.ImpersonationExpiresAt()
+		suite.WithinDuration(time.Now().Add(time.Hour), expiresAt, time.Minute, "Should end impersonations after an hour by default")
+
+		exp, ok := claims.Claim("exp").(float64)
+		suite.Require().True(ok)
+		suite.LessOrEqual(int64(exp), expiresAt.Unix(), "Tokens should not outlive the impersonation")
+	}
+
+	ended, err := suite.jwt.Generate(
+		security.NewJWTClaimsBuilder().
+			WithID("ended").
+			WithSubject(suite.user.ID+"@"+suite.user.Name).
+			WithType("refresh").
+			WithImpersonator(suite.admin.ID+"@"+suite.admin.Name).
+			WithImpersonationExpiresAt(time.Now().Add(-time.Minute)),
+		time.Hour,
+		0,
+	)
+	suite.Require().NoError(err)
+
+	body := suite.request("refresh", map[string]any{"refreshToken": ended}, constants.Empty)
+	suite.Equal(result.ErrCodeTokenExpired, body.Code, "Should not refresh ended impersonations")
+}
+
+func TestImpersonationResource(t *testing.T) {
+	suite.Run(t, new(ImpersonationResourceTestSuite))
+}
@@ -0,0 +1,90 @@
+package security
+
+import (
+	"context"
+
+	"github.com/ilxqx/vef-framework-go/cache"
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+const ipAccessListCacheKey = "access_list"
+
+// IPFilter is the application wide IP filter combining the configured entries with the managed rules.
+type IPFilter struct {
+	allow          []string
+	deny           []string
+	trustedProxies *security.TrustedProxies
+	loader         security.IPRuleLoader
+	listCache      cache.Cache[*security.IPAccessList]
+	publisher      event.Publisher
+}
+
+// NewIPFilter creates the global IP filter, returning nil when IP filtering is disabled.
+// Managed rules are read through the loader, falling back to the sys_ip_rule table, and reloaded on IPRulesChangedEvent.
+func NewIPFilter(securityConfig *config.SecurityConfig, loader security.IPRuleLoader, db orm.DB, bus event.Bus) *IPFilter {
+	cfg := securityConfig.IPFilter
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if loader == nil && cfg.Managed {
+		loader = security.NewDBIPRuleLoader(db)
+	}
+
+	filter := &IPFilter{
+		allow:          cfg.Allow,
+		deny:           cfg.Deny,
+		trustedProxies: security.NewTrustedProxies(cfg.TrustedProxies),
+		loader:         loader,
+		listCache:      cache.NewMemory[*security.IPAccessList](),
+		publisher:      bus,
+	}
+
+	security.SubscribeIPRulesChangedEvent(bus, func(ctx context.Context, _ *security.IPRulesChangedEvent) {
+		if err := filter.listCache.Clear(ctx); err != nil {
+			logger.Errorf("Failed to clear IP access list cache: %v", err)
+		}
+	})
+
+	return filter
+}
+
+// Check returns the reason the ip is blocked, or an empty string when it passes.
+// When the managed rules cannot be loaded only the configured entries are applied.
+func (f *IPFilter) Check(ctx context.Context, ip string) string {
+	list, err := f.listCache.GetOrLoad(ctx, ipAccessListCacheKey, f.loadAccessList)
+	if err != nil {
+		logger.Errorf("Failed to load IP rules: %v", err)
+
+		list = security.NewIPAccessList(f.allow, f.deny)
+	}
+
+	return list.Check(ip)
+}
+
+// IsTrustedProxy reports whether the ip is a configured trusted proxy.
+func (f *IPFilter) IsTrustedProxy(ip string) bool {
+	return f.trustedProxies.Contains(ip)
+}
+
+// Blocked logs and publishes a blocked attempt.
+func (f *IPFilter) Blocked(params security.IPBlockedEventParams) {
+	logger.Warnf("Blocked request from %s to %s %s: %s", params.IP, params.Method, params.Path, params.Reason)
+	f.publisher.Publish(security.NewIPBlockedEvent(params))
+}
+
+func (f *IPFilter) loadAccessList(ctx context.Context) (*security.IPAccessList, error) {
+	if f.loader == nil {
+		return security.NewIPAccessList(f.allow, f.deny), nil
+	}
+
+	rules, err := f.loader.LoadIPRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return security.NewIPAccessListFromRules(f.allow, f.deny, rules), nil
+}
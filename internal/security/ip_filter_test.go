@@ -0,0 +1,113 @@
+package security_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/event"
+	ievent "github.com/ilxqx/vef-framework-go/internal/event"
+	isecurity "github.com/ilxqx/vef-framework-go/internal/security"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+// staticIPRuleLoader serves the rules it holds and counts the loads.
+type staticIPRuleLoader struct {
+	rules atomic.Value
+	loads atomic.Int32
+}
+
+func (l *staticIPRuleLoader) LoadIPRules(context.Context) ([]security.IPRule, error) {
+	l.loads.Add(1)
+
+	return l.rules.Load().([]security.IPRule), nil
+}
+
+// IPFilterTestSuite tests the global IP filter.
+type IPFilterTestSuite struct {
+	suite.Suite
+
+	ctx context.Context
+	bus event.Bus
+}
+
+func (s *IPFilterTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	s.bus = ievent.NewMemoryBus([]event.Middleware{})
+	s.Require().NoError(s.bus.(interface{ Start() error }).Start(), "Should start event bus")
+}
+
+func (s *IPFilterTestSuite) newFilter(cfg config.IPFilterConfig, loader security.IPRuleLoader) *isecurity.IPFilter {
+	return isecurity.NewIPFilter(&config.SecurityConfig{IPFilter: cfg}, loader, nil, s.bus)
+}
+
+func (s *IPFilterTestSuite) TestDisabled() {
+	s.Nil(s.newFilter(config.IPFilterConfig{}, nil), "Should not create the filter when disabled")
+}
+
+func (s *IPFilterTestSuite) TestConfiguredEntries() {
+	filter := s.newFilter(config.IPFilterConfig{
+		Enabled: true,
+		Deny:    []string{"203.0.113.0/24"},
+	}, nil)
+	s.Require().NotNil(filter)
+
+	s.Equal(security.IPBlockReasonDenied, filter.Check(s.ctx, "203.0.113.7"))
+	s.Empty(filter.Check(s.ctx, "198.51.100.1"))
+}
+
+func (s *IPFilterTestSuite) TestManagedRulesReloadOnChange() {
+	loader := new(staticIPRuleLoader)
+	loader.rules.Store([]security.IPRule{})
+
+	filter := s.newFilter(config.IPFilterConfig{Enabled: true}, loader)
+	s.Require().NotNil(filter)
+
+	s.Empty(filter.Check(s.ctx, "198.51.100.1"))
+	s.Empty(filter.Check(s.ctx, "198.51.100.1"))
+	s.Equal(int32(1), loader.loads.Load(), "Should cache the rules")
+
+	loader.rules.Store([]security.IPRule{{Type: security.IPRuleTypeDeny, Address: "198.51.100.1"}})
+	security.PublishIPRulesChangedEvent(s.bus)
+
+	s.Eventually(func() bool {
+		return filter.Check(s.ctx, "198.51.100.1") == security.IPBlockReasonDenied
+	}, time.Second, 10*time.Millisecond, "Should apply the changed rules")
+}
+
+func (s *IPFilterTestSuite) TestBlockedPublishesEvent() {
+	filter := s.newFilter(config.IPFilterConfig{Enabled: true}, nil)
+	s.Require().NotNil(filter)
+
+	received := make(chan *security.IPBlockedEvent, 1)
+	unsubscribe := security.SubscribeIPBlockedEvent(s.bus, func(_ context.Context, evt *security.IPBlockedEvent) {
+		received <- evt
+	})
+	defer unsubscribe()
+
+	filter.Blocked(security.IPBlockedEventParams{
+		IP:     "203.0.113.7",
+		Reason: security.IPBlockReasonDenied,
+		Scope:  security.IPFilterScopeGlobal,
+		Method: "POST",
+		Path:   "/api",
+	})
+
+	select {
+	case evt := <-received:
+		s.Equal("203.0.113.7", evt.IP)
+		s.Equal(security.IPBlockReasonDenied, evt.Reason)
+		s.Equal(security.IPFilterScopeGlobal, evt.Scope)
+	case <-time.After(time.Second):
+		s.Fail("Should publish the blocked event")
+	}
+}
+
+func TestIPFilterSuite(t *testing.T) {
+	suite.Run(t, new(IPFilterTestSuite))
+}
@@ -17,12 +17,21 @@ const (
 type JWTRefreshAuthenticator struct {
 	jwt        *security.JWT
 	userLoader security.UserLoader
+	sessions   security.SessionManager
+	guard      impersonationGuard
 }
 
-func NewJWTRefreshAuthenticator(jwt *security.JWT, userLoader security.UserLoader) security.Authenticator {
+func NewJWTRefreshAuthenticator(
+	jwt *security.JWT,
+	userLoader security.UserLoader,
+	sessions security.SessionManager,
+	permissionsLoader security.RolePermissionsLoader,
+) security.Authenticator {
 	return &JWTRefreshAuthenticator{
 		jwt:        jwt,
 		userLoader: userLoader,
+		sessions:   sessions,
+		guard:      impersonationGuard{loader: permissionsLoader},
 	}
 }
 
@@ -54,6 +63,31 @@ func (j *JWTRefreshAuthenticator) Authenticate(ctx context.Context, authenticati
 	subjectParts := strings.SplitN(claimsAccessor.Subject(), constants.At, 2)
 	userID := subjectParts[0]
 
+	// Ended impersonations are not refreshed.
+	var impersonation security.Principal
+	if err := parseImpersonator(claimsAccessor, &impersonation); err != nil {
+		return nil, err
+	}
+
+	impersonator := impersonation.Impersonator
+
+	if j.sessions != nil {
+		session, err := j.sessions.Refresh(ctx, claimsAccessor.ID())
+		if err != nil {
+			return nil, err
+		}
+
+		// Impersonation sessions belong to the impersonating operator.
+		ownerID := userID
+		if impersonator != nil {
+			ownerID = impersonator.ID
+		}
+
+		if session.UserID != ownerID {
+			return nil, result.ErrTokenInvalid
+		}
+	}
+
 	// Reload user to get latest permissions/status instead of relying on stale token data.
 	principal, err := j.userLoader.LoadByID(ctx, userID)
 	if err != nil {
@@ -68,5 +102,31 @@ func (j *JWTRefreshAuthenticator) Authenticate(ctx context.Context, authenticati
 		return nil, result.ErrRecordNotFound
 	}
 
+	// Keep impersonating with the refreshed tokens while the operator may still act as the user.
+	if impersonator != nil {
+		operator, err := j.userLoader.LoadByID(ctx, impersonator.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if operator == nil {
+			logger.Warnf("Impersonating operator not found by ID %q", impersonator.ID)
+
+			return nil, result.ErrImpersonationNotAllowed
+		}
+
+		if err := j.guard.check(ctx, operator, principal); err != nil {
+			return nil, err
+		}
+
+		principal.Impersonator = impersonator
+		principal.ImpersonationExpiresAt = impersonation.ImpersonationExpiresAt
+	}
+
+	// Keep the refreshed tokens in the session of the refresh token.
+	if j.sessions != nil {
+		principal.SessionID = claimsAccessor.ID()
+	}
+
 	return principal, nil
 }
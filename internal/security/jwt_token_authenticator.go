@@ -14,12 +14,14 @@ const (
 )
 
 type JWTTokenAuthenticator struct {
-	jwt *security.JWT
+	jwt      *security.JWT
+	sessions security.SessionManager
 }
 
-func NewJWTAuthenticator(jwt *security.JWT) security.Authenticator {
+func NewJWTAuthenticator(jwt *security.JWT, sessions security.SessionManager) security.Authenticator {
 	return &JWTTokenAuthenticator{
-		jwt: jwt,
+		jwt:      jwt,
+		sessions: sessions,
 	}
 }
 
@@ -27,7 +29,7 @@ func (*JWTTokenAuthenticator) Supports(kind string) bool {
 	return kind == AuthKindToken
 }
 
-func (ja *JWTTokenAuthenticator) Authenticate(_ context.Context, authentication security.Authentication) (*security.Principal, error) {
+func (ja *JWTTokenAuthenticator) Authenticate(ctx context.Context, authentication security.Authentication) (*security.Principal, error) {
 	token := authentication.Principal
 	if token == constants.Empty {
 		return nil, result.ErrTokenInvalid
@@ -42,9 +44,24 @@ func (ja *JWTTokenAuthenticator) Authenticate(_ context.Context, authentication
 		return nil, result.ErrTokenInvalid
 	}
 
+	// Tokens are bound to their session by the token id, so revoking the session invalidates them.
+	if ja.sessions != nil {
+		if _, err := ja.sessions.Validate(ctx, claimsAccessor.ID()); err != nil {
+			return nil, err
+		}
+	}
+
 	subjectParts := strings.SplitN(claimsAccessor.Subject(), constants.At, 2)
 	principal := security.NewUser(subjectParts[0], subjectParts[1], claimsAccessor.Roles()...)
 	principal.AttemptUnmarshalDetails(claimsAccessor.Details())
 
+	if err := parseImpersonator(claimsAccessor, principal); err != nil {
+		return nil, err
+	}
+
+	if ja.sessions != nil {
+		principal.SessionID = claimsAccessor.ID()
+	}
+
 	return principal, nil
 }
@@ -2,10 +2,13 @@ package security
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
 	"github.com/ilxqx/vef-framework-go/id"
+	"github.com/ilxqx/vef-framework-go/result"
 	"github.com/ilxqx/vef-framework-go/security"
 )
 
@@ -28,7 +31,11 @@ func NewJWTTokenGenerator(jwt *security.JWT, securityConfig *config.SecurityConf
 }
 
 func (g *JWTTokenGenerator) Generate(principal *security.Principal) (*security.AuthTokens, error) {
-	jwtID := id.GenerateUUID()
+	// The token id identifies the session the tokens belong to.
+	jwtID := principal.SessionID
+	if jwtID == constants.Empty {
+		jwtID = id.GenerateUUID()
+	}
 
 	accessToken, err := g.generateAccessToken(jwtID, principal)
 	if err != nil {
@@ -58,8 +65,9 @@ func (g *JWTTokenGenerator) generateAccessToken(jwtID string, principal *securit
 		WithRoles(principal.Roles).
 		WithDetails(principal.Details).
 		WithType(tokenTypeAccess)
+	withImpersonator(claimsBuilder, principal)
 
-	return g.jwt.Generate(claimsBuilder, accessTokenExpires, 0)
+	return g.jwt.Generate(claimsBuilder, impersonationBounded(principal, accessTokenExpires), 0)
 }
 
 func (g *JWTTokenGenerator) generateRefreshToken(jwtID string, principal *security.Principal) (string, error) {
@@ -67,6 +75,49 @@ func (g *JWTTokenGenerator) generateRefreshToken(jwtID string, principal *securi
 		WithID(jwtID).
 		WithSubject(fmt.Sprintf("%s@%s", principal.ID, principal.Name)).
 		WithType(tokenTypeRefresh)
+	withImpersonator(claimsBuilder, principal)
+
+	return g.jwt.Generate(claimsBuilder, impersonationBounded(principal, g.tokenExpires), refreshTokenNotBefore)
+}
+
+// withImpersonator records the operator of impersonated principals in the tokens, encoded as id@name like the subject,
+// and the end of the impersonation.
+func withImpersonator(claimsBuilder *security.JWTClaimsBuilder, principal *security.Principal) {
+	if impersonator := principal.Impersonator; impersonator != nil {
+		claimsBuilder.WithImpersonator(fmt.Sprintf("%s@%s", impersonator.ID, impersonator.Name)).
+			WithImpersonationExpiresAt(principal.ImpersonationExpiresAt)
+	}
+}
+
+// impersonationBounded shortens the lifetime of the tokens of impersonated principals to the end of the impersonation.
+func impersonationBounded(principal *security.Principal, expires time.Duration) time.Duration {
+	if principal.Impersonator == nil {
+		return expires
+	}
+
+	return min(expires, time.Until(principal.ImpersonationExpiresAt))
+}
+
+// parseImpersonator restores the operator and the end of the impersonation recorded by withImpersonator onto the
+// principal, failing for impersonations that ended or record no end.
+func parseImpersonator(claimsAccessor *security.JWTClaimsAccessor, principal *security.Principal) error {
+	impersonator := claimsAccessor.Impersonator()
+	if impersonator == constants.Empty {
+		return nil
+	}
+
+	parts := strings.SplitN(impersonator, constants.At, 2)
+	if len(parts) != 2 {
+		return result.ErrTokenInvalid
+	}
+
+	expiresAt := claimsAccessor.ImpersonationExpiresAt()
+	if expiresAt.IsZero() || !time.Now().Before(expiresAt) {
+		return result.ErrTokenExpired
+	}
+
+	principal.Impersonator = security.NewUser(parts[0], parts[1])
+	principal.ImpersonationExpiresAt = expiresAt
 
-	return g.jwt.Generate(claimsBuilder, g.tokenExpires, refreshTokenNotBefore)
+	return nil
 }
@@ -0,0 +1,231 @@
+package security
+
+import (
+	"context"
+	"math"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+const (
+	defaultMaxTravelSpeed = 1000.0 // km/h, faster than commercial flights
+	// minTravelDistance ignores short distances, which are within the inaccuracy of IP geolocation.
+	minTravelDistance = 500.0 // km
+	earthRadius       = 6371.0
+)
+
+// LoginAuditor records login and logout events to the login audit table and flags risky logins.
+type LoginAuditor struct {
+	db             orm.DB
+	locator        security.GeoLocator
+	publisher      event.Publisher
+	maxTravelSpeed float64
+}
+
+// NewLoginAuditor creates a login auditor subscribed to login and logout events,
+// or returns nil when login auditing is disabled.
+// Locations and impossible travel are only available when a security.GeoLocator is provided.
+func NewLoginAuditor(securityConfig *config.SecurityConfig, db orm.DB, locator security.GeoLocator, bus event.Bus) *LoginAuditor {
+	if !securityConfig.LoginAudit.Enabled {
+		return nil
+	}
+
+	maxTravelSpeed := securityConfig.LoginAudit.MaxTravelSpeed
+	if maxTravelSpeed <= 0 {
+		maxTravelSpeed = defaultMaxTravelSpeed
+	}
+
+	auditor := &LoginAuditor{
+		db:             db,
+		locator:        locator,
+		publisher:      bus,
+		maxTravelSpeed: maxTravelSpeed,
+	}
+
+	security.SubscribeLoginEvent(bus, auditor.handleLogin)
+	security.SubscribeLogoutEvent(bus, auditor.handleLogout)
+
+	return auditor
+}
+
+func (a *LoginAuditor) handleLogin(ctx context.Context, evt *security.LoginEvent) {
+	record := &security.LoginRecord{
+		Action:     security.LoginActionLogin,
+		AuthType:   evt.AuthType,
+		UserID:     evt.UserID,
+		Username:   evt.Username,
+		IsOk:       evt.IsOk,
+		FailReason: evt.FailReason,
+		ErrorCode:  evt.ErrorCode,
+		IP:         evt.LoginIP,
+		UserAgent:  evt.UserAgent,
+		DeviceID:   evt.DeviceID,
+		TraceID:    evt.TraceID,
+	}
+	record.CreatedAt = datetime.Of(evt.Time())
+
+	a.locate(ctx, record)
+
+	if record.IsOk && record.UserID != constants.Empty {
+		flags, err := a.detectRisks(ctx, record)
+		if err != nil {
+			logger.Errorf("Failed to detect login risks of user %q: %v", record.UserID, err)
+		}
+
+		record.RiskFlags = flags
+	}
+
+	if !a.save(ctx, record) {
+		return
+	}
+
+	if len(record.RiskFlags) > 0 {
+		logger.Warnf("Risky login of user %q from %s: %v", record.Username, record.IP, record.RiskFlags)
+		a.publisher.Publish(security.NewLoginRiskEvent(record))
+	}
+}
+
+func (a *LoginAuditor) handleLogout(ctx context.Context, evt *security.LogoutEvent) {
+	record := &security.LoginRecord{
+		Action:    security.LoginActionLogout,
+		UserID:    evt.UserID,
+		Username:  evt.Username,
+		IsOk:      true,
+		IP:        evt.LogoutIP,
+		UserAgent: evt.UserAgent,
+		DeviceID:  evt.DeviceID,
+		TraceID:   evt.TraceID,
+	}
+	record.CreatedAt = datetime.Of(evt.Time())
+
+	a.locate(ctx, record)
+	a.save(ctx, record)
+}
+
+func (a *LoginAuditor) locate(ctx context.Context, record *security.LoginRecord) {
+	if a.locator == nil || record.IP == constants.Empty {
+		return
+	}
+
+	location, err := a.locator.Locate(ctx, record.IP)
+	if err != nil {
+		logger.Warnf("Failed to locate ip %q: %v", record.IP, err)
+
+		return
+	}
+
+	if location == nil {
+		return
+	}
+
+	record.Country = location.Country
+	record.Region = location.Region
+	record.City = location.City
+	record.Latitude = null.FloatFrom(location.Latitude)
+	record.Longitude = null.FloatFrom(location.Longitude)
+}
+
+func (a *LoginAuditor) save(ctx context.Context, record *security.LoginRecord) bool {
+	if _, err := a.db.NewInsert().Model(record).Exec(ctx); err != nil {
+		logger.Errorf("Failed to record %s of user %q: %v", record.Action, record.Username, err)
+
+		return false
+	}
+
+	return true
+}
+
+// detectRisks compares a successful login with the previous successful logins of the user.
+// The first login of a user is never flagged.
+func (a *LoginAuditor) detectRisks(ctx context.Context, record *security.LoginRecord) (security.RiskFlags, error) {
+	var previous security.LoginRecord
+	if err := a.db.NewSelect().
+		Model(&previous).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.Equals("user_id", record.UserID).
+				Equals("action", security.LoginActionLogin).
+				IsTrue("is_ok")
+		}).
+		OrderByDesc("created_at").
+		Limit(1).
+		Scan(ctx); err != nil {
+		if result.IsRecordNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var flags security.RiskFlags
+
+	knownDevice, err := a.db.NewSelect().
+		Model((*security.LoginRecord)(nil)).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.Equals("user_id", record.UserID).
+				Equals("action", security.LoginActionLogin).
+				IsTrue("is_ok")
+
+			// Clients without a device id are recognized by their user agent.
+			if record.DeviceID != constants.Empty {
+				cb.Equals("device_id", record.DeviceID)
+			} else {
+				cb.Equals("user_agent", record.UserAgent)
+			}
+		}).
+		Exists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !knownDevice {
+		flags = append(flags, security.RiskFlagNewDevice)
+	}
+
+	if a.isImpossibleTravel(&previous, record) {
+		flags = append(flags, security.RiskFlagImpossibleTravel)
+	}
+
+	return flags, nil
+}
+
+func (a *LoginAuditor) isImpossibleTravel(previous, current *security.LoginRecord) bool {
+	if !previous.Latitude.Valid || !previous.Longitude.Valid || !current.Latitude.Valid || !current.Longitude.Valid {
+		return false
+	}
+
+	distance := haversineDistance(
+		previous.Latitude.Float64, previous.Longitude.Float64,
+		current.Latitude.Float64, current.Longitude.Float64,
+	)
+	if distance < minTravelDistance {
+		return false
+	}
+
+	elapsed := current.CreatedAt.Unwrap().Sub(previous.CreatedAt.Unwrap())
+	if elapsed <= 0 {
+		return true
+	}
+
+	return distance/elapsed.Hours() > a.maxTravelSpeed
+}
+
+// haversineDistance returns the great-circle distance in km between two coordinates in degrees.
+func haversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(degrees float64) float64 {
+		return degrees * math.Pi / 180
+	}
+
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
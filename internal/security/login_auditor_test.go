@@ -0,0 +1,206 @@
+package security_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	ievent "github.com/ilxqx/vef-framework-go/internal/event"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	isecurity "github.com/ilxqx/vef-framework-go/internal/security"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+// staticGeoLocator resolves ips from a fixed table.
+type staticGeoLocator map[string]*security.GeoLocation
+
+func (l staticGeoLocator) Locate(_ context.Context, ip string) (*security.GeoLocation, error) {
+	return l[ip], nil
+}
+
+var testLocations = staticGeoLocator{
+	"10.0.0.1": {Country: "China", City: "Beijing", Latitude: 39.9042, Longitude: 116.4074},
+	"10.0.0.2": {Country: "China", City: "Tianjin", Latitude: 39.3434, Longitude: 117.3616},
+	"10.0.0.3": {Country: "United States", City: "New York", Latitude: 40.7128, Longitude: -74.0060},
+}
+
+// LoginAuditorTestSuite tests recording of login events and risk detection.
+type LoginAuditorTestSuite struct {
+	suite.Suite
+
+	ctx   context.Context
+	bus   event.Bus
+	bunDB *bun.DB
+	db    orm.DB
+
+	mu          sync.Mutex
+	riskRecords []*security.LoginRecord
+}
+
+func (s *LoginAuditorTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	s.bus = ievent.NewMemoryBus([]event.Middleware{})
+	s.Require().NoError(s.bus.(interface{ Start() error }).Start(), "Should start event bus")
+
+	var err error
+
+	s.bunDB, err = database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	_, err = s.bunDB.NewCreateTable().Model((*security.LoginRecord)(nil)).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	s.db = iorm.New(s.bunDB)
+
+	auditor := isecurity.NewLoginAuditor(&config.SecurityConfig{
+		LoginAudit: config.LoginAuditConfig{Enabled: true},
+	}, s.db, testLocations, s.bus)
+	s.Require().NotNil(auditor, "Should create the auditor when enabled")
+
+	security.SubscribeLoginRiskEvent(s.bus, func(_ context.Context, evt *security.LoginRiskEvent) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.riskRecords = append(s.riskRecords, evt.Record)
+	})
+}
+
+func (s *LoginAuditorTestSuite) TearDownSuite() {
+	if s.bunDB != nil {
+		s.Require().NoError(s.bunDB.Close())
+	}
+}
+
+// publish publishes the event and waits until its record is written.
+func (s *LoginAuditorTestSuite) publish(evt event.Event) *security.LoginRecord {
+	var traceID string
+
+	switch e := evt.(type) {
+	case *security.LoginEvent:
+		traceID = e.TraceID
+	case *security.LogoutEvent:
+		traceID = e.TraceID
+	}
+
+	s.bus.Publish(evt)
+
+	var record security.LoginRecord
+	s.Require().Eventually(func() bool {
+		return s.db.NewSelect().
+			Model(&record).
+			Where(func(cb orm.ConditionBuilder) {
+				cb.Equals("trace_id", traceID)
+			}).
+			Scan(s.ctx) == nil
+	}, 5*time.Second, 10*time.Millisecond, "Should record the event")
+
+	return &record
+}
+
+func (s *LoginAuditorTestSuite) login(traceID, userID, ip, deviceID string, ok bool) *security.LoginRecord {
+	params := security.LoginEventParams{
+		AuthType:  isecurity.AuthKindPassword,
+		UserID:    userID,
+		Username:  "user-" + userID,
+		LoginIP:   ip,
+		UserAgent: "test-agent",
+		DeviceID:  deviceID,
+		TraceID:   traceID,
+		IsOk:      ok,
+	}
+	if !ok {
+		params.UserID = constants.Empty
+		params.FailReason = "invalid credentials"
+		params.ErrorCode = 1011
+	}
+
+	return s.publish(security.NewLoginEvent(params))
+}
+
+func (s *LoginAuditorTestSuite) TestDisabled() {
+	auditor := isecurity.NewLoginAuditor(&config.SecurityConfig{}, s.db, nil, s.bus)
+	s.Nil(auditor, "Should not audit logins when disabled")
+}
+
+func (s *LoginAuditorTestSuite) TestFirstLoginIsNotFlagged() {
+	record := s.login("first-1", "first", "10.0.0.1", "device-a", true)
+
+	s.True(record.IsOk)
+	s.Equal(security.LoginActionLogin, record.Action)
+	s.Equal("Beijing", record.City, "Should record the location")
+	s.True(record.Latitude.Valid, "Should record the coordinates")
+	s.Empty(record.RiskFlags, "First login should not be flagged")
+}
+
+func (s *LoginAuditorTestSuite) TestKnownDeviceNearbyIsNotFlagged() {
+	s.login("nearby-1", "nearby", "10.0.0.1", "device-a", true)
+	record := s.login("nearby-2", "nearby", "10.0.0.2", "device-a", true)
+
+	s.Equal("Tianjin", record.City)
+	s.Empty(record.RiskFlags, "Login from a known device nearby should not be flagged")
+}
+
+func (s *LoginAuditorTestSuite) TestNewDevice() {
+	s.login("device-1", "device", "10.0.0.1", "device-a", true)
+	record := s.login("device-2", "device", "10.0.0.1", "device-b", true)
+
+	s.Equal(security.RiskFlags{security.RiskFlagNewDevice}, record.RiskFlags, "Should flag the new device")
+	s.Eventually(func() bool {
+		return s.hasRiskEvent("device-2")
+	}, 5*time.Second, 10*time.Millisecond, "Should publish a login risk event")
+}
+
+func (s *LoginAuditorTestSuite) TestImpossibleTravel() {
+	s.login("travel-1", "travel", "10.0.0.1", "device-a", true)
+	record := s.login("travel-2", "travel", "10.0.0.3", "device-a", true)
+
+	s.True(record.RiskFlags.Has(security.RiskFlagImpossibleTravel), "Should flag Beijing to New York within seconds")
+	s.False(record.RiskFlags.Has(security.RiskFlagNewDevice), "Device is known")
+}
+
+func (s *LoginAuditorTestSuite) TestFailedLoginAndLogout() {
+	failed := s.login("failed-1", "failed", "10.0.0.1", "device-a", false)
+
+	s.False(failed.IsOk)
+	s.Equal("invalid credentials", failed.FailReason)
+	s.Equal(1011, failed.ErrorCode)
+	s.Empty(failed.RiskFlags, "Failed logins are not checked for risks")
+
+	logout := s.publish(security.NewLogoutEvent(security.LogoutEventParams{
+		UserID:   "failed",
+		Username: "user-failed",
+		LogoutIP: "10.0.0.2",
+		DeviceID: "device-a",
+		TraceID:  "failed-2",
+	}))
+
+	s.Equal(security.LoginActionLogout, logout.Action)
+	s.Equal("Tianjin", logout.City)
+}
+
+func (s *LoginAuditorTestSuite) hasRiskEvent(traceID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, record := range s.riskRecords {
+		if record.TraceID == traceID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestLoginAuditorSuite(t *testing.T) {
+	suite.Run(t, new(LoginAuditorTestSuite))
+}
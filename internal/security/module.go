@@ -7,6 +7,7 @@ import (
 	"github.com/ilxqx/vef-framework-go/config"
 	"github.com/ilxqx/vef-framework-go/event"
 	"github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/orm"
 	"github.com/ilxqx/vef-framework-go/password"
 	"github.com/ilxqx/vef-framework-go/security"
 )
@@ -47,18 +48,38 @@ var Module = fx.Module(
 				})
 			},
 		),
+		fx.Annotate(
+			NewIPFilter,
+			fx.ParamTags(``, `optional:"true"`),
+		),
+		fx.Annotate(
+			NewSessionManager,
+			fx.ParamTags(``, `optional:"true"`),
+		),
 		fx.Annotate(
 			NewJWTAuthenticator,
 			fx.ResultTags(`group:"vef:security:authenticators"`),
 		),
 		fx.Annotate(
 			NewJWTRefreshAuthenticator,
-			fx.ParamTags(``, `optional:"true"`),
+			fx.ParamTags(``, `optional:"true"`, ``, `optional:"true"`),
 			fx.ResultTags(`group:"vef:security:authenticators"`),
 		),
 		NewJWTTokenGenerator,
 		fx.Annotate(
-			NewSignatureAuthenticator,
+			func(
+				loader security.ExternalAppLoader,
+				nonceStore security.NonceStore,
+				securityConfig *config.SecurityConfig,
+				db orm.DB,
+				bus event.Bus,
+			) security.Authenticator {
+				if loader == nil && securityConfig.ExternalApp.Registry {
+					loader = security.NewDBExternalAppLoader(db, bus)
+				}
+
+				return NewSignatureAuthenticator(loader, nonceStore)
+			},
 			fx.ParamTags(`optional:"true"`, `optional:"true"`),
 			fx.ResultTags(`group:"vef:security:authenticators"`),
 		),
@@ -84,5 +105,20 @@ var Module = fx.Module(
 			fx.ParamTags(``, ``, `optional:"true"`),
 			fx.ResultTags(`group:"vef:api:resources"`),
 		),
+		fx.Annotate(
+			NewImpersonationResource,
+			fx.ParamTags(`optional:"true"`, ``, ``, `optional:"true"`),
+			fx.ResultTags(`group:"vef:api:resources"`),
+		),
+		fx.Annotate(
+			NewSessionResource,
+			fx.ResultTags(`group:"vef:api:resources"`),
+		),
+		fx.Annotate(
+			NewLoginAuditor,
+			fx.ParamTags(``, ``, `optional:"true"`),
+		),
 	),
+	// The login auditor records events through its subscriptions, so nothing else depends on it.
+	fx.Invoke(func(*LoginAuditor) {}),
 )
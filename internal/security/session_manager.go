@@ -0,0 +1,299 @@
+package security
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/id"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+const (
+	// sessionTouchInterval throttles the LastActiveAt updates written on every authenticated request.
+	sessionTouchInterval = time.Minute
+
+	sessionRevokeReasonRevoked  = "revoked"
+	sessionRevokeReasonEvicted  = "evicted"
+	sessionRevokeReasonReplaced = "replaced"
+)
+
+// DefaultSessionManager implements security.SessionManager on top of a security.SessionStore.
+type DefaultSessionManager struct {
+	mu            sync.Mutex
+	store         security.SessionStore
+	publisher     event.Publisher
+	maxConcurrent int
+	limitStrategy string
+	lifetime      time.Duration
+	now           func() time.Time
+}
+
+// NewSessionManager creates a session manager, or returns nil when session management is disabled.
+// Sessions are kept in memory unless a security.SessionStore is provided.
+func NewSessionManager(securityConfig *config.SecurityConfig, store security.SessionStore, publisher event.Publisher) security.SessionManager {
+	if !securityConfig.Session.Enabled {
+		return nil
+	}
+
+	if store == nil {
+		store = security.NewMemorySessionStore()
+	}
+
+	limitStrategy := securityConfig.Session.LimitStrategy
+	if limitStrategy == constants.Empty {
+		limitStrategy = security.SessionLimitEvictOldest
+	}
+
+	// A session must outlive its refresh token, otherwise valid refresh tokens would be rejected.
+	lifetime := max(securityConfig.TokenExpires, accessTokenExpires)
+
+	return &DefaultSessionManager{
+		store:         store,
+		publisher:     publisher,
+		maxConcurrent: securityConfig.Session.MaxConcurrent,
+		limitStrategy: limitStrategy,
+		lifetime:      lifetime,
+		now:           time.Now,
+	}
+}
+
+func (m *DefaultSessionManager) Create(ctx context.Context, principal *security.Principal, device security.DeviceInfo) (*security.Session, error) {
+	// Serializes logins so that concurrent logins of a user cannot exceed the limit.
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessions, err := m.store.ListByUser(ctx, principal.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A login from a known device replaces the session of that device.
+	if device.DeviceID != constants.Empty {
+		var replaced []string
+
+		sessions = slices.DeleteFunc(sessions, func(session *security.Session) bool {
+			if session.DeviceID == device.DeviceID {
+				replaced = append(replaced, session.ID)
+
+				return true
+			}
+
+			return false
+		})
+
+		if err := m.delete(ctx, principal.ID, replaced, sessionRevokeReasonReplaced); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.maxConcurrent > 0 && len(sessions) >= m.maxConcurrent {
+		if m.limitStrategy == security.SessionLimitReject {
+			return nil, result.ErrSessionLimitExceeded
+		}
+
+		slices.SortFunc(sessions, func(a, b *security.Session) int {
+			return a.LastActiveAt.Compare(b.LastActiveAt)
+		})
+
+		evicted := make([]string, 0, len(sessions)-m.maxConcurrent+1)
+		for _, session := range sessions[:len(sessions)-m.maxConcurrent+1] {
+			evicted = append(evicted, session.ID)
+		}
+
+		if err := m.delete(ctx, principal.ID, evicted, sessionRevokeReasonEvicted); err != nil {
+			return nil, err
+		}
+	}
+
+	now := m.now()
+	session := &security.Session{
+		ID:           id.GenerateUUID(),
+		UserID:       principal.ID,
+		Username:     principal.Name,
+		DeviceID:     device.DeviceID,
+		DeviceName:   parseDeviceName(device.UserAgent),
+		IP:           device.IP,
+		UserAgent:    device.UserAgent,
+		CreatedAt:    now,
+		LastActiveAt: now,
+		ExpiresAt:    now.Add(m.lifetime),
+	}
+	if err := m.store.Save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	principal.SessionID = session.ID
+
+	return session, nil
+}
+
+func (m *DefaultSessionManager) Validate(ctx context.Context, id string) (*security.Session, error) {
+	session, err := m.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil {
+		return nil, result.ErrSessionRevoked
+	}
+
+	if now := m.now(); now.Sub(session.LastActiveAt) >= sessionTouchInterval {
+		// Touching only updates a session which still exists, a session revoked since it was read stays revoked
+		touched, err := m.store.Touch(ctx, id, now, session.ExpiresAt)
+		if err != nil {
+			logger.Warnf("Failed to record activity of session %q: %v", id, err)
+
+			return session, nil
+		}
+
+		if touched == nil {
+			return nil, result.ErrSessionRevoked
+		}
+
+		return touched, nil
+	}
+
+	return session, nil
+}
+
+func (m *DefaultSessionManager) Refresh(ctx context.Context, id string) (*security.Session, error) {
+	now := m.now()
+
+	session, err := m.store.Touch(ctx, id, now, now.Add(m.lifetime))
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil {
+		return nil, result.ErrSessionRevoked
+	}
+
+	return session, nil
+}
+
+func (m *DefaultSessionManager) List(ctx context.Context, userID string) ([]*security.Session, error) {
+	sessions, err := m.store.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(sessions, func(a, b *security.Session) int {
+		return cmp.Or(
+			b.LastActiveAt.Compare(a.LastActiveAt),
+			strings.Compare(a.ID, b.ID),
+		)
+	})
+
+	return sessions, nil
+}
+
+func (m *DefaultSessionManager) Revoke(ctx context.Context, userID string, ids ...string) error {
+	sessions, err := m.store.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	revoked := make([]string, 0, len(ids))
+	for _, session := range sessions {
+		if slices.Contains(ids, session.ID) {
+			revoked = append(revoked, session.ID)
+		}
+	}
+
+	return m.delete(ctx, userID, revoked, sessionRevokeReasonRevoked)
+}
+
+func (m *DefaultSessionManager) RevokeAll(ctx context.Context, userID string, except ...string) error {
+	sessions, err := m.store.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	revoked := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		if !slices.Contains(except, session.ID) {
+			revoked = append(revoked, session.ID)
+		}
+	}
+
+	return m.delete(ctx, userID, revoked, sessionRevokeReasonRevoked)
+}
+
+func (m *DefaultSessionManager) delete(ctx context.Context, userID string, ids []string, reason string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := m.store.Delete(ctx, ids...); err != nil {
+		return err
+	}
+
+	logger.Infof("Revoked %d session(s) of user %q (%s)", len(ids), userID, reason)
+	m.publisher.Publish(security.NewSessionRevokedEvent(userID, ids, reason))
+
+	return nil
+}
+
+// parseDeviceName derives a readable device name such as "Chrome on Windows" from a user agent.
+func parseDeviceName(userAgent string) string {
+	if userAgent == constants.Empty {
+		return constants.Empty
+	}
+
+	var platform, browser string
+
+	switch {
+	case strings.Contains(userAgent, "Windows"):
+		platform = "Windows"
+	case strings.Contains(userAgent, "iPhone"):
+		platform = "iPhone"
+	case strings.Contains(userAgent, "iPad"):
+		platform = "iPad"
+	case strings.Contains(userAgent, "Android"):
+		platform = "Android"
+	case strings.Contains(userAgent, "Mac OS X"), strings.Contains(userAgent, "Macintosh"):
+		platform = "macOS"
+	case strings.Contains(userAgent, "CrOS"):
+		platform = "ChromeOS"
+	case strings.Contains(userAgent, "Linux"):
+		platform = "Linux"
+	}
+
+	// Order matters: Edge and Opera report Chrome, and Chrome reports Safari.
+	switch {
+	case strings.Contains(userAgent, "MicroMessenger"):
+		browser = "WeChat"
+	case strings.Contains(userAgent, "Edg/"), strings.Contains(userAgent, "Edge/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"), strings.Contains(userAgent, "Opera"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"), strings.Contains(userAgent, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	}
+
+	switch {
+	case browser != constants.Empty && platform != constants.Empty:
+		return browser + " on " + platform
+	case browser != constants.Empty:
+		return browser
+	case platform != constants.Empty:
+		return platform
+	default:
+		// Non-browser clients such as "okhttp/4.12.0" are named after their product token.
+		name, _, _ := strings.Cut(userAgent, constants.Slash)
+
+		return name
+	}
+}
@@ -0,0 +1,68 @@
+package security
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+// revokingStore revokes every session right after it was read, as a concurrent logout would.
+type revokingStore struct {
+	security.SessionStore
+}
+
+func (s *revokingStore) Get(ctx context.Context, id string) (*security.Session, error) {
+	session, err := s.SessionStore.Get(ctx, id)
+	if err != nil || session == nil {
+		return session, err
+	}
+
+	return session, s.Delete(ctx, id)
+}
+
+func TestSessionManagerKeepsRevokedSessions(t *testing.T) {
+	ctx := context.Background()
+
+	newManager := func(t *testing.T) (*DefaultSessionManager, security.SessionStore) {
+		t.Helper()
+
+		store := &revokingStore{SessionStore: security.NewMemorySessionStore()}
+		require.NoError(t, store.Save(ctx, &security.Session{
+			ID:           "s1",
+			UserID:       "u1",
+			LastActiveAt: time.Now().Add(-time.Hour),
+			ExpiresAt:    time.Now().Add(time.Hour),
+		}))
+
+		return &DefaultSessionManager{store: store, lifetime: time.Hour, now: time.Now}, store
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		manager, store := newManager(t)
+
+		_, err := manager.Validate(ctx, "s1")
+		require.ErrorIs(t, err, result.ErrSessionRevoked, "Should not record activity of a session revoked meanwhile")
+
+		sessions, err := store.ListByUser(ctx, "u1")
+		require.NoError(t, err)
+		assert.Empty(t, sessions, "Should not save the revoked session again")
+	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		manager, store := newManager(t)
+		require.NoError(t, store.Delete(ctx, "s1"))
+
+		_, err := manager.Refresh(ctx, "s1")
+		require.ErrorIs(t, err, result.ErrSessionRevoked, "Should not extend a revoked session")
+
+		sessions, err := store.ListByUser(ctx, "u1")
+		require.NoError(t, err)
+		assert.Empty(t, sessions, "Should not save the revoked session again")
+	})
+}
@@ -0,0 +1,158 @@
+package security
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/i18n"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+const (
+	permTokenSessionQuery  = "sys.session.query"
+	permTokenSessionRevoke = "sys.session.revoke"
+)
+
+// NewSessionResource creates the resource listing and revoking login sessions.
+// Users manage their own sessions ("my devices"); managing the sessions of other users requires permissions.
+func NewSessionResource(sessions security.SessionManager) api.Resource {
+	return &SessionResource{
+		sessions: sessions,
+		Resource: api.NewRPCResource(
+			"security/session",
+			api.WithOperations(
+				api.OperationSpec{
+					Action: "list",
+				},
+				api.OperationSpec{
+					Action: "revoke",
+				},
+				api.OperationSpec{
+					Action: "revoke_others",
+				},
+				api.OperationSpec{
+					Action:    "list_user_sessions",
+					PermToken: permTokenSessionQuery,
+				},
+				api.OperationSpec{
+					Action:      "revoke_user_sessions",
+					PermToken:   permTokenSessionRevoke,
+					EnableAudit: true,
+				},
+			),
+		),
+	}
+}
+
+// SessionResource handles session management Api endpoints.
+type SessionResource struct {
+	api.Resource
+
+	sessions security.SessionManager
+}
+
+// SessionView is a session as shown on the devices page.
+type SessionView struct {
+	*security.Session
+
+	Current bool `json:"current"`
+}
+
+// RevokeSessionParams represents the request parameters for revoking an own session.
+type RevokeSessionParams struct {
+	api.P
+
+	SessionID string `json:"sessionId" validate:"required"`
+}
+
+// UserSessionsParams represents the request parameters for managing the sessions of a user.
+type UserSessionsParams struct {
+	api.P
+
+	UserID string `json:"userId" validate:"required"`
+	// SessionIDs limits revocation to the sessions; all sessions of the user are revoked when empty.
+	SessionIDs []string `json:"sessionIds"`
+}
+
+// List returns the active sessions of the current user, marking the session of the request.
+func (r *SessionResource) List(ctx fiber.Ctx, principal *security.Principal) error {
+	if r.sessions == nil {
+		return result.ErrNotImplemented(i18n.T(result.ErrMessageSessionManagementDisabled))
+	}
+
+	sessions, err := r.sessions.List(ctx.Context(), principal.ID)
+	if err != nil {
+		return err
+	}
+
+	views := make([]SessionView, len(sessions))
+	for i, session := range sessions {
+		views[i] = SessionView{
+			Session: session,
+			Current: session.ID == principal.SessionID,
+		}
+	}
+
+	return result.Ok(views).Response(ctx)
+}
+
+// Revoke signs the current user out of one of their sessions.
+func (r *SessionResource) Revoke(ctx fiber.Ctx, principal *security.Principal, params RevokeSessionParams) error {
+	if r.sessions == nil {
+		return result.ErrNotImplemented(i18n.T(result.ErrMessageSessionManagementDisabled))
+	}
+
+	if err := r.sessions.Revoke(ctx.Context(), principal.ID, params.SessionID); err != nil {
+		return err
+	}
+
+	return result.Ok().Response(ctx)
+}
+
+// RevokeOthers signs the current user out of all sessions except the session of the request.
+func (r *SessionResource) RevokeOthers(ctx fiber.Ctx, principal *security.Principal) error {
+	if r.sessions == nil {
+		return result.ErrNotImplemented(i18n.T(result.ErrMessageSessionManagementDisabled))
+	}
+
+	if err := r.sessions.RevokeAll(ctx.Context(), principal.ID, principal.SessionID); err != nil {
+		return err
+	}
+
+	return result.Ok().Response(ctx)
+}
+
+// ListUserSessions returns the active sessions of a user.
+func (r *SessionResource) ListUserSessions(ctx fiber.Ctx, params UserSessionsParams) error {
+	if r.sessions == nil {
+		return result.ErrNotImplemented(i18n.T(result.ErrMessageSessionManagementDisabled))
+	}
+
+	sessions, err := r.sessions.List(ctx.Context(), params.UserID)
+	if err != nil {
+		return err
+	}
+
+	return result.Ok(sessions).Response(ctx)
+}
+
+// RevokeUserSessions signs a user out remotely, either of the given sessions or of all sessions.
+func (r *SessionResource) RevokeUserSessions(ctx fiber.Ctx, params UserSessionsParams) error {
+	if r.sessions == nil {
+		return result.ErrNotImplemented(i18n.T(result.ErrMessageSessionManagementDisabled))
+	}
+
+	var err error
+	if len(params.SessionIDs) > 0 {
+		err = r.sessions.Revoke(ctx.Context(), params.UserID, params.SessionIDs...)
+	} else {
+		err = r.sessions.RevokeAll(ctx.Context(), params.UserID)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return result.Ok().Response(ctx)
+}
@@ -0,0 +1,322 @@
+package security_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/fx"
+
+	"github.com/ilxqx/vef-framework-go/api"
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/encoding"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/app"
+	"github.com/ilxqx/vef-framework-go/internal/apptest"
+	isecurity "github.com/ilxqx/vef-framework-go/internal/security"
+	"github.com/ilxqx/vef-framework-go/password"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+const chromeOnWindows = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+
+// SessionResourceTestSuite is the test suite for session management.
+type SessionResourceTestSuite struct {
+	suite.Suite
+
+	ctx        context.Context
+	app        *app.App
+	stop       func()
+	userLoader *MockUserLoader
+	publisher  *MockPublisher
+	sessions   security.SessionManager
+	testUser   *security.Principal
+}
+
+// SetupSuite runs once before all tests in the suite.
+func (suite *SessionResourceTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	suite.testUser = security.NewUser("user001", "Test User", "user")
+	suite.userLoader = new(MockUserLoader)
+	suite.publisher = new(MockPublisher)
+
+	hashedPassword, err := password.NewBcryptEncoder().Encode("password123")
+	suite.Require().NoError(err)
+
+	suite.app, suite.stop = apptest.NewTestApp(
+		suite.T(),
+		fx.Supply(
+			fx.Annotate(
+				suite.userLoader,
+				fx.As(new(security.UserLoader)),
+			),
+		),
+		fx.Replace(
+			fx.Annotate(
+				suite.publisher,
+				fx.As(new(event.Publisher)),
+			),
+		),
+		fx.Replace(
+			&config.DatasourceConfig{
+				Type: "sqlite",
+			},
+			&config.SecurityConfig{
+				TokenExpires: 24 * time.Hour,
+				Session: config.SessionConfig{
+					Enabled:       true,
+					MaxConcurrent: 2,
+				},
+			},
+			&security.JWTConfig{
+				Secret:   "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Audience: "test-app",
+			},
+		),
+		fx.Populate(&suite.sessions),
+		fx.Invoke(func() {
+			suite.userLoader.On("LoadByUsername", mock.Anything, "testuser").
+				Return(suite.testUser, hashedPassword, nil).
+				Maybe()
+
+			suite.userLoader.On("LoadByID", mock.Anything, "user001").
+				Return(suite.testUser, nil).
+				Maybe()
+
+			suite.publisher.On("Publish", mock.Anything).
+				Maybe()
+		}),
+	)
+}
+
+// TearDownSuite runs once after all tests in the suite.
+func (suite *SessionResourceTestSuite) TearDownSuite() {
+	if suite.stop != nil {
+		suite.stop()
+	}
+}
+
+// SetupTest signs the test user out of all sessions before each test.
+func (suite *SessionResourceTestSuite) SetupTest() {
+	suite.Require().NoError(suite.sessions.RevokeAll(suite.ctx, suite.testUser.ID))
+	suite.publisher.ClearPublishedEvents()
+}
+
+func (suite *SessionResourceTestSuite) request(body api.Request, token string, headers map[string]string) result.Result {
+	jsonBody, err := encoding.ToJSON(body)
+	suite.Require().NoError(err, "Should encode request to JSON")
+
+	req := httptest.NewRequest(fiber.MethodPost, "/api", strings.NewReader(jsonBody))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	if token != constants.Empty {
+		req.Header.Set(fiber.HeaderAuthorization, constants.AuthSchemeBearer+" "+token)
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := suite.app.Test(req, 30*time.Second)
+	suite.Require().NoError(err, "Api request should not fail")
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err, "Should read response body")
+
+	res, err := encoding.FromJSON[result.Result](string(data))
+	suite.Require().NoError(err, "Should decode response JSON")
+
+	return *res
+}
+
+func (suite *SessionResourceTestSuite) login(deviceID string) (accessToken, refreshToken string) {
+	body := suite.request(api.Request{
+		Identifier: api.Identifier{Resource: "security/auth", Action: "login", Version: "v1"},
+		Params: map[string]any{
+			"kind":        isecurity.AuthKindPassword,
+			"principal":   "testuser",
+			"credentials": "password123",
+		},
+	}, constants.Empty, map[string]string{
+		security.HeaderDeviceID: deviceID,
+		fiber.HeaderUserAgent:   chromeOnWindows,
+	})
+	suite.Require().True(body.IsOk(), "Login should succeed: %s", body.Message)
+
+	tokens := body.Data.(map[string]any)
+
+	return tokens["accessToken"].(string), tokens["refreshToken"].(string)
+}
+
+func (suite *SessionResourceTestSuite) listSessions(token string) result.Result {
+	return suite.request(api.Request{
+		Identifier: api.Identifier{Resource: "security/session", Action: "list", Version: "v1"},
+	}, token, nil)
+}
+
+// TestListSessions tests that logins are listed with their device and the current session is marked.
+func (suite *SessionResourceTestSuite) TestListSessions() {
+	suite.login("device-a")
+	accessToken, _ := suite.login("device-b")
+
+	body := suite.listSessions(accessToken)
+	suite.Require().True(body.IsOk(), "List should succeed")
+
+	sessions := body.Data.([]any)
+	suite.Require().Len(sessions, 2, "Should list both sessions")
+
+	current := sessions[0].(map[string]any)
+	suite.Equal("device-b", current["deviceId"], "Most recently active session should come first")
+	suite.Equal("Chrome on Windows", current["deviceName"], "Should derive the device name from the user agent")
+	suite.Equal(true, current["current"], "Session of the request should be marked current")
+	suite.Equal(false, sessions[1].(map[string]any)["current"], "Other sessions should not be marked current")
+}
+
+// TestLogoutRevokesSession tests that logout invalidates the access and refresh tokens of the session.
+func (suite *SessionResourceTestSuite) TestLogoutRevokesSession() {
+	accessToken, refreshToken := suite.login("device-a")
+
+	body := suite.request(api.Request{
+		Identifier: api.Identifier{Resource: "security/auth", Action: "logout", Version: "v1"},
+	}, accessToken, nil)
+	suite.Require().True(body.IsOk(), "Logout should succeed")
+
+	body = suite.listSessions(accessToken)
+	suite.False(body.IsOk(), "Access token of a revoked session should be rejected")
+	suite.Equal(result.ErrCodeSessionRevoked, body.Code, "Should report the revoked session")
+
+	body = suite.request(api.Request{
+		Identifier: api.Identifier{Resource: "security/auth", Action: "refresh", Version: "v1"},
+		Params:     map[string]any{"refreshToken": refreshToken},
+	}, constants.Empty, nil)
+	suite.False(body.IsOk(), "Refresh token of a revoked session should be rejected")
+	suite.Equal(result.ErrCodeSessionRevoked, body.Code, "Should report the revoked session")
+}
+
+// TestRefreshKeepsSession tests that refreshed tokens stay in the session of the refresh token.
+func (suite *SessionResourceTestSuite) TestRefreshKeepsSession() {
+	_, refreshToken := suite.login("device-a")
+
+	body := suite.request(api.Request{
+		Identifier: api.Identifier{Resource: "security/auth", Action: "refresh", Version: "v1"},
+		Params:     map[string]any{"refreshToken": refreshToken},
+	}, constants.Empty, nil)
+	suite.Require().True(body.IsOk(), "Refresh should succeed")
+
+	accessToken := body.Data.(map[string]any)["accessToken"].(string)
+	body = suite.listSessions(accessToken)
+	suite.Require().True(body.IsOk(), "Refreshed access token should be accepted")
+
+	sessions := body.Data.([]any)
+	suite.Require().Len(sessions, 1, "Refresh should not create a session")
+	suite.Equal(true, sessions[0].(map[string]any)["current"], "Refreshed tokens should belong to the session")
+}
+
+// TestSameDeviceReplacesSession tests that a new login on a device replaces its previous session.
+func (suite *SessionResourceTestSuite) TestSameDeviceReplacesSession() {
+	oldToken, _ := suite.login("device-a")
+	accessToken, _ := suite.login("device-a")
+
+	body := suite.listSessions(oldToken)
+	suite.Equal(result.ErrCodeSessionRevoked, body.Code, "Replaced session should be revoked")
+
+	body = suite.listSessions(accessToken)
+	suite.Require().True(body.IsOk(), "List should succeed")
+	suite.Len(body.Data.([]any), 1, "Device should have a single session")
+}
+
+// TestConcurrentLimitEvictsOldest tests that logins beyond the limit sign out the least recently active session.
+func (suite *SessionResourceTestSuite) TestConcurrentLimitEvictsOldest() {
+	oldestToken, _ := suite.login("device-a")
+	suite.login("device-b")
+	accessToken, _ := suite.login("device-c")
+
+	body := suite.listSessions(oldestToken)
+	suite.Equal(result.ErrCodeSessionRevoked, body.Code, "Oldest session should be evicted")
+
+	body = suite.listSessions(accessToken)
+	suite.Require().True(body.IsOk(), "List should succeed")
+	suite.Len(body.Data.([]any), 2, "Should keep the session limit")
+
+	var evicted *security.SessionRevokedEvent
+	for _, evt := range suite.publisher.GetPublishedEvents() {
+		if revokedEvt, ok := evt.(*security.SessionRevokedEvent); ok {
+			evicted = revokedEvt
+		}
+	}
+
+	suite.Require().NotNil(evicted, "Should publish a session revoked event")
+	suite.Equal("evicted", evicted.Reason, "Should report the eviction")
+}
+
+// TestRevokeOthers tests signing out of all other devices.
+func (suite *SessionResourceTestSuite) TestRevokeOthers() {
+	otherToken, _ := suite.login("device-a")
+	accessToken, _ := suite.login("device-b")
+
+	body := suite.request(api.Request{
+		Identifier: api.Identifier{Resource: "security/session", Action: "revoke_others", Version: "v1"},
+	}, accessToken, nil)
+	suite.Require().True(body.IsOk(), "Revoke others should succeed")
+
+	body = suite.listSessions(otherToken)
+	suite.Equal(result.ErrCodeSessionRevoked, body.Code, "Other session should be revoked")
+
+	body = suite.listSessions(accessToken)
+	suite.Require().True(body.IsOk(), "Current session should stay active")
+	suite.Len(body.Data.([]any), 1, "Only the current session should remain")
+}
+
+// TestRevokeSession tests remote logout of a single own session.
+func (suite *SessionResourceTestSuite) TestRevokeSession() {
+	otherToken, _ := suite.login("device-a")
+	accessToken, _ := suite.login("device-b")
+
+	body := suite.listSessions(accessToken)
+	suite.Require().True(body.IsOk(), "List should succeed")
+
+	var otherID string
+	for _, item := range body.Data.([]any) {
+		if session := item.(map[string]any); session["current"] == false {
+			otherID = session["id"].(string)
+		}
+	}
+
+	body = suite.request(api.Request{
+		Identifier: api.Identifier{Resource: "security/session", Action: "revoke", Version: "v1"},
+		Params:     map[string]any{"sessionId": otherID},
+	}, accessToken, nil)
+	suite.Require().True(body.IsOk(), "Revoke should succeed")
+
+	body = suite.listSessions(otherToken)
+	suite.Equal(result.ErrCodeSessionRevoked, body.Code, "Revoked session should be rejected")
+}
+
+// TestRevokeIgnoresOtherUsers tests that sessions of other users cannot be revoked through the own session api.
+func (suite *SessionResourceTestSuite) TestRevokeIgnoresOtherUsers() {
+	accessToken, _ := suite.login("device-a")
+
+	body := suite.listSessions(accessToken)
+	suite.Require().True(body.IsOk(), "List should succeed")
+
+	sessionID := body.Data.([]any)[0].(map[string]any)["id"].(string)
+	suite.Require().NoError(suite.sessions.Revoke(suite.ctx, "someone-else", sessionID))
+
+	body = suite.listSessions(accessToken)
+	suite.True(body.IsOk(), "Session should not be revoked on behalf of another user")
+}
+
+// TestSessionResourceSuite runs the test suite.
+func TestSessionResourceSuite(t *testing.T) {
+	suite.Run(t, new(SessionResourceTestSuite))
+}
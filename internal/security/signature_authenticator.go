@@ -21,18 +21,20 @@ type SignatureAuthenticator struct {
 }
 
 // NewSignatureAuthenticator creates a new signature authenticator.
+// Without a nonce store used nonces are kept in memory, which only rejects replays against the same instance.
 func NewSignatureAuthenticator(
 	loader security.ExternalAppLoader,
 	nonceStore security.NonceStore,
 ) security.Authenticator {
-	var options []security.SignatureOption
-	if nonceStore != nil {
-		options = append(options, security.WithNonceStore(nonceStore))
+	// The store must outlive the per-request Signature instances, otherwise every request
+	// would check its nonce against an empty store.
+	if nonceStore == nil {
+		nonceStore = security.NewMemoryNonceStore()
 	}
 
 	return &SignatureAuthenticator{
 		loader:  loader,
-		options: options,
+		options: []security.SignatureOption{security.WithNonceStore(nonceStore)},
 	}
 }
 
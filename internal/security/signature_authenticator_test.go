@@ -436,6 +436,27 @@ func TestSignatureAuthenticator_NonceValidation(t *testing.T) {
 		loader.AssertExpectations(t)
 		nonceStore.AssertExpectations(t)
 	})
+
+	t.Run("ReplayRejectedWithDefaultStore", func(t *testing.T) {
+		loader := new(MockExternalAppLoader)
+
+		principal := security.NewExternalApp("app1", "Test App", "api_user")
+		loader.On("LoadByID", mock.Anything, "app1").Return(principal, testSecretHex, nil)
+
+		auth := NewSignatureAuthenticator(loader, nil)
+
+		authentication := security.Authentication{
+			Kind:        AuthKindSignature,
+			Principal:   "app1",
+			Credentials: generateValidCredentials(t, "app1", testSecretHex),
+		}
+
+		_, err := auth.Authenticate(ctx, authentication)
+		require.NoError(t, err, "First request should be accepted")
+
+		_, err = auth.Authenticate(ctx, authentication)
+		assert.Error(t, err, "Replayed request should be rejected")
+	})
 }
 
 func TestSignatureAuthenticator_IPWhitelist(t *testing.T) {
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepThreshold is the number of counters above which expired counters are swept on increment.
+const sweepThreshold = 1024
+
+// MemoryDownloadCounter counts downloads in memory, so limits only hold within a single instance.
+type MemoryDownloadCounter struct {
+	mu       sync.Mutex
+	counters map[string]*downloadCount
+}
+
+type downloadCount struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// NewMemoryDownloadCounter creates an in-memory download counter.
+func NewMemoryDownloadCounter() *MemoryDownloadCounter {
+	return &MemoryDownloadCounter{counters: make(map[string]*downloadCount)}
+}
+
+func (c *MemoryDownloadCounter) Increment(_ context.Context, id string, ttl time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	if len(c.counters) >= sweepThreshold {
+		for key, counter := range c.counters {
+			if now.After(counter.expiresAt) {
+				delete(c.counters, key)
+			}
+		}
+	}
+
+	counter, ok := c.counters[id]
+	if !ok || now.After(counter.expiresAt) {
+		counter = &downloadCount{expiresAt: now.Add(ttl)}
+		c.counters[id] = counter
+	}
+
+	counter.count++
+
+	return counter.count, nil
+}
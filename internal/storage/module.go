@@ -1,11 +1,14 @@
 package storage
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 
 	"go.uber.org/fx"
 
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/cron"
 	"github.com/ilxqx/vef-framework-go/internal/contract"
 	"github.com/ilxqx/vef-framework-go/internal/log"
 	"github.com/ilxqx/vef-framework-go/storage"
@@ -28,6 +31,27 @@ var Module = fx.Module(
 				return nil
 			}),
 		),
+		fx.Annotate(
+			NewPipeline,
+			fx.ParamTags(``, ``, ``, `group:"vef:storage:processors"`),
+			fx.OnStop(func(ctx context.Context, pipeline *Pipeline) error {
+				if pipeline == nil {
+					return nil
+				}
+
+				return pipeline.Shutdown(ctx)
+			}),
+		),
+		fx.Annotate(
+			NewMemoryDownloadCounter,
+			fx.As(new(storage.DownloadCounter)),
+		),
+		fx.Annotate(
+			NewURLSigner,
+			fx.ParamTags(``, ``, ``, `group:"vef:storage:watermarkers"`),
+			fx.As(fx.Self()),
+			fx.As(new(storage.URLSigner)),
+		),
 		fx.Annotate(
 			NewResource,
 			fx.ResultTags(`group:"vef:api:resources"`),
@@ -37,4 +61,31 @@ var Module = fx.Module(
 			fx.ResultTags(`group:"vef:app:middlewares"`),
 		),
 	),
+	fx.Invoke(scheduleMultipartSweep),
 )
+
+// scheduleMultipartSweep registers the periodic removal of abandoned chunked uploads.
+func scheduleMultipartSweep(cfg *config.StorageConfig, service storage.Service, scheduler cron.Scheduler) error {
+	expiry := cmp.Or(cfg.Multipart.Expiry, defaultMultipartExpiry)
+	uploader := NewMultipartUploader(service)
+
+	_, err := scheduler.NewJob(cron.NewDurationJob(
+		min(expiry, multipartSweepInterval),
+		cron.WithName("multipart_sweep"),
+		cron.WithTags("vef", "storage"),
+		cron.WithTask(func(ctx context.Context) error {
+			removed, err := uploader.Sweep(ctx, expiry)
+			if err != nil {
+				return fmt.Errorf("failed to sweep abandoned multipart uploads: %w", err)
+			}
+
+			if removed > 0 {
+				logger.Infof("Removed %d abandoned multipart uploads", removed)
+			}
+
+			return nil
+		}),
+	))
+
+	return err
+}
@@ -0,0 +1,618 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/id"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+const (
+	manifestName  = "upload.json"
+	partExtension = ".part"
+	// defaultMaxPartSize bounds parts when an upload does not declare its part size.
+	defaultMaxPartSize = 64 << 20
+	// defaultMultipartExpiry is how long uploads are kept without activity unless configured otherwise.
+	defaultMultipartExpiry = 24 * time.Hour
+	// multipartSweepInterval is how often abandoned uploads are swept, or the expiry if shorter.
+	multipartSweepInterval = time.Hour
+)
+
+// InitMultipartOptions contains parameters for initiating a chunked upload.
+type InitMultipartOptions struct {
+	// Key is the object key the parts are merged into
+	Key string
+	// Filename is the original filename
+	Filename string
+	// ContentType is the content type of the merged object
+	ContentType string
+	// Size is the total size in bytes, 0 if unknown
+	Size int64
+	// PartSize is the size in bytes of every part but the last, 0 to allow any part size up to 64 MiB
+	PartSize int64
+	// MD5 is the expected hex MD5 checksum of the merged object, empty to skip verification
+	MD5 string
+	// Metadata is the metadata of the merged object
+	Metadata map[string]string
+}
+
+// UploadPartOptions contains parameters for uploading a part of a chunked upload.
+type UploadPartOptions struct {
+	// UploadID identifies the upload session
+	UploadID string
+	// PartNumber is the 1-based position of the part
+	PartNumber int
+	// Reader provides the part content
+	Reader io.Reader
+	// Size is the part size in bytes
+	Size int64
+	// MD5 is the expected hex MD5 checksum of the part, empty to skip verification
+	MD5 string
+}
+
+// MultipartUploader implements chunked, resumable uploads on top of any storage service.
+// Parts are stored as temporary objects named after their number and MD5 checksum, next to a manifest describing
+// the upload; completing the upload merges the parts in order into the target object and removes them.
+type MultipartUploader struct {
+	service storage.Service
+}
+
+// NewMultipartUploader creates a chunked upload manager for the storage service.
+func NewMultipartUploader(service storage.Service) *MultipartUploader {
+	return &MultipartUploader{service: service}
+}
+
+// Init starts a chunked upload and persists its manifest.
+func (u *MultipartUploader) Init(ctx context.Context, opts InitMultipartOptions) (*storage.MultipartUpload, error) {
+	partSize := maxPartSize(opts.PartSize)
+	if opts.Size < 0 || opts.Size > 0 && (opts.Size+partSize-1)/partSize > storage.MaxMultipartParts {
+		return nil, fmt.Errorf("%w: %d bytes exceed %d parts of %d bytes", storage.ErrInvalidPart, opts.Size, storage.MaxMultipartParts, partSize)
+	}
+
+	if opts.MD5 != constants.Empty && !isMD5(opts.MD5) {
+		return nil, fmt.Errorf("%w: invalid MD5 checksum %q", storage.ErrChecksumMismatch, opts.MD5)
+	}
+
+	upload := &storage.MultipartUpload{
+		UploadID:    id.GenerateUUID(),
+		Key:         opts.Key,
+		Filename:    opts.Filename,
+		ContentType: opts.ContentType,
+		Size:        opts.Size,
+		PartSize:    max(opts.PartSize, 0),
+		MD5:         strings.ToLower(opts.MD5),
+		Metadata:    opts.Metadata,
+		CreatedAt:   time.Now(),
+	}
+
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.service.PutObject(ctx, storage.PutObjectOptions{
+		Key:         manifestKey(upload.UploadID),
+		Reader:      bytes.NewReader(data),
+		Size:        int64(len(data)),
+		ContentType: fiber.MIMEApplicationJSON,
+	}); err != nil {
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+// Get returns the manifest of a chunked upload.
+func (u *MultipartUploader) Get(ctx context.Context, uploadID string) (*storage.MultipartUpload, error) {
+	if !isUploadID(uploadID) {
+		return nil, storage.ErrUploadNotFound
+	}
+
+	reader, err := u.service.GetObject(ctx, storage.GetObjectOptions{Key: manifestKey(uploadID)})
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, storage.ErrUploadNotFound
+		}
+
+		return nil, err
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	var upload storage.MultipartUpload
+	if err := json.NewDecoder(reader).Decode(&upload); err != nil {
+		return nil, fmt.Errorf("failed to decode multipart upload manifest %s: %w", uploadID, err)
+	}
+
+	return &upload, nil
+}
+
+// UploadPart stores a part, replacing a previously uploaded part with the same number.
+// The part checksum is computed while storing it and the part is discarded when it does not match the expected one.
+func (u *MultipartUploader) UploadPart(ctx context.Context, opts UploadPartOptions) (*storage.UploadedPart, error) {
+	upload, err := u.Get(ctx, opts.UploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.PartNumber < 1 || opts.PartNumber > storage.MaxMultipartParts {
+		return nil, fmt.Errorf("%w: part number %d out of range", storage.ErrInvalidPart, opts.PartNumber)
+	}
+
+	if limit := maxPartSize(upload.PartSize); opts.Size <= 0 || opts.Size > limit {
+		return nil, fmt.Errorf("%w: part size %d exceeds %d bytes", storage.ErrInvalidPart, opts.Size, limit)
+	}
+
+	if opts.MD5 != constants.Empty && !isMD5(opts.MD5) {
+		return nil, fmt.Errorf("%w: invalid MD5 checksum %q", storage.ErrChecksumMismatch, opts.MD5)
+	}
+
+	previous, err := u.ListParts(ctx, opts.UploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The checksum is only known once the content is read, so the part is stored under a staging key first
+	hasher := md5.New()
+	staging := uploadPrefix(opts.UploadID) + id.GenerateUUID() + ".tmp"
+
+	if _, err := u.service.PutObject(ctx, storage.PutObjectOptions{
+		Key:         staging,
+		Reader:      io.TeeReader(opts.Reader, hasher),
+		Size:        opts.Size,
+		ContentType: contentTypeOctetStream,
+	}); err != nil {
+		return nil, err
+	}
+
+	etag := hex.EncodeToString(hasher.Sum(nil))
+	if opts.MD5 != constants.Empty && !strings.EqualFold(opts.MD5, etag) {
+		u.delete(ctx, staging)
+
+		return nil, fmt.Errorf("%w: part %d has MD5 %s, expected %s", storage.ErrChecksumMismatch, opts.PartNumber, etag, opts.MD5)
+	}
+
+	key := partKey(opts.UploadID, opts.PartNumber, etag)
+	if _, err := u.service.MoveObject(ctx, storage.MoveObjectOptions{
+		CopyObjectOptions: storage.CopyObjectOptions{SourceKey: staging, DestKey: key},
+	}); err != nil {
+		u.delete(ctx, staging)
+
+		return nil, err
+	}
+
+	// Drop the replaced upload of the part
+	for _, part := range previous {
+		if part.PartNumber == opts.PartNumber && part.ETag != etag {
+			u.delete(ctx, partKey(opts.UploadID, part.PartNumber, part.ETag))
+		}
+	}
+
+	return &storage.UploadedPart{
+		PartNumber: opts.PartNumber,
+		ETag:       etag,
+		Size:       opts.Size,
+	}, nil
+}
+
+// ListParts returns the stored parts of a chunked upload ordered by part number, used to resume an upload.
+func (u *MultipartUploader) ListParts(ctx context.Context, uploadID string) ([]storage.UploadedPart, error) {
+	if !isUploadID(uploadID) {
+		return nil, storage.ErrUploadNotFound
+	}
+
+	objects, err := u.service.ListObjects(ctx, storage.ListObjectsOptions{
+		Prefix:    uploadPrefix(uploadID),
+		Recursive: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]storage.UploadedPart, 0, len(objects))
+	for _, object := range objects {
+		if part, ok := parsePartKey(object.Key); ok {
+			part.Size = object.Size
+			parts = append(parts, part)
+		}
+	}
+
+	slices.SortFunc(parts, func(a, b storage.UploadedPart) int {
+		return a.PartNumber - b.PartNumber
+	})
+
+	return parts, nil
+}
+
+// Complete merges the given parts in order into the target object and removes the upload.
+// Parts must be listed in ascending order with the ETags returned on upload; every part but the last must be
+// exactly the part size. The declared size and checksum of the upload are verified against the merged object.
+// Services implementing storage.ObjectComposer merge the parts within the backend when every part but the last
+// holds at least storage.MinComposePartSize bytes, the others have the parts streamed through the application.
+func (u *MultipartUploader) Complete(ctx context.Context, uploadID string, completed []storage.CompletedPart) (*storage.ObjectInfo, error) {
+	upload, err := u.Get(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := u.ListParts(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, size, err := selectParts(upload, stored, completed)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]string, len(upload.Metadata)+1)
+	maps.Copy(metadata, upload.Metadata)
+
+	if upload.Filename != constants.Empty {
+		metadata[storage.MetadataKeyOriginalFilename] = upload.Filename
+	}
+
+	var (
+		info     *storage.ObjectInfo
+		checksum string
+	)
+
+	if composer, ok := u.service.(storage.ObjectComposer); ok && composable(parts) {
+		info, checksum, err = u.compose(ctx, composer, upload, parts, metadata)
+	} else {
+		info, checksum, err = u.merge(ctx, upload, parts, size, metadata)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.MD5 != constants.Empty && checksum != upload.MD5 {
+		u.delete(ctx, upload.Key)
+
+		return nil, fmt.Errorf("%w: merged file has MD5 %s, expected %s", storage.ErrChecksumMismatch, checksum, upload.MD5)
+	}
+
+	if err := u.Abort(ctx, uploadID); err != nil {
+		logger.Warnf("Failed to remove parts of completed multipart upload %s: %v", uploadID, err)
+	}
+
+	return info, nil
+}
+
+// compose merges the parts within the backend. The checksum of the merged object is only computed, by reading it
+// back, when the upload declares one to verify.
+func (u *MultipartUploader) compose(
+	ctx context.Context,
+	composer storage.ObjectComposer,
+	upload *storage.MultipartUpload,
+	parts []storage.UploadedPart,
+	metadata map[string]string,
+) (*storage.ObjectInfo, string, error) {
+	keys := make([]string, len(parts))
+	for i, part := range parts {
+		keys[i] = partKey(upload.UploadID, part.PartNumber, part.ETag)
+	}
+
+	info, err := composer.ComposeObject(ctx, storage.ComposeObjectOptions{
+		SourceKeys:  keys,
+		DestKey:     upload.Key,
+		ContentType: upload.ContentType,
+		Metadata:    metadata,
+	})
+	if err != nil || upload.MD5 == constants.Empty {
+		return info, constants.Empty, err
+	}
+
+	reader, err := u.service.GetObject(ctx, storage.GetObjectOptions{Key: upload.Key})
+	if err != nil {
+		return nil, constants.Empty, err
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	hasher := md5.New()
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return nil, constants.Empty, err
+	}
+
+	return info, hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// merge streams the parts in order into the target object, computing its checksum on the way.
+func (u *MultipartUploader) merge(
+	ctx context.Context,
+	upload *storage.MultipartUpload,
+	parts []storage.UploadedPart,
+	size int64,
+	metadata map[string]string,
+) (*storage.ObjectInfo, string, error) {
+	hasher := md5.New()
+	reader := &partsReader{ctx: ctx, service: u.service, uploadID: upload.UploadID, parts: parts, hasher: hasher}
+
+	defer reader.Close()
+
+	info, err := u.service.PutObject(ctx, storage.PutObjectOptions{
+		Key:         upload.Key,
+		Reader:      reader,
+		Size:        size,
+		ContentType: upload.ContentType,
+		Metadata:    metadata,
+	})
+	if err != nil {
+		return nil, constants.Empty, err
+	}
+
+	return info, hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// Abort removes the manifest and the stored parts of a chunked upload.
+func (u *MultipartUploader) Abort(ctx context.Context, uploadID string) error {
+	if !isUploadID(uploadID) {
+		return storage.ErrUploadNotFound
+	}
+
+	objects, err := u.service.ListObjects(ctx, storage.ListObjectsOptions{
+		Prefix:    uploadPrefix(uploadID),
+		Recursive: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(objects) == 0 {
+		return storage.ErrUploadNotFound
+	}
+
+	keys := make([]string, len(objects))
+	for i, object := range objects {
+		keys[i] = object.Key
+	}
+
+	return u.service.DeleteObjects(ctx, storage.DeleteObjectsOptions{Keys: keys})
+}
+
+// Sweep removes the uploads without activity since the expiry, i.e. whose manifest and parts were all last
+// modified before it, and returns the number of removed uploads. Objects left by interrupted uploads without a
+// manifest are removed alike.
+func (u *MultipartUploader) Sweep(ctx context.Context, expiry time.Duration) (int, error) {
+	objects, err := u.service.ListObjects(ctx, storage.ListObjectsOptions{
+		Prefix:    storage.MultipartPrefix,
+		Recursive: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		keys         = make(map[string][]string)
+		lastModified = make(map[string]time.Time)
+	)
+
+	for _, object := range objects {
+		uploadID, _, ok := strings.Cut(strings.TrimPrefix(object.Key, storage.MultipartPrefix), constants.Slash)
+		if !ok {
+			continue
+		}
+
+		keys[uploadID] = append(keys[uploadID], object.Key)
+		if object.LastModified.After(lastModified[uploadID]) {
+			lastModified[uploadID] = object.LastModified
+		}
+	}
+
+	var (
+		cutoff  = time.Now().Add(-expiry)
+		expired []string
+		removed int
+	)
+
+	for uploadID, modified := range lastModified {
+		if modified.Before(cutoff) {
+			expired = append(expired, keys[uploadID]...)
+			removed++
+		}
+	}
+
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	if err := u.service.DeleteObjects(ctx, storage.DeleteObjectsOptions{Keys: expired}); err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
+
+func (u *MultipartUploader) delete(ctx context.Context, key string) {
+	if err := u.service.DeleteObject(ctx, storage.DeleteObjectOptions{Key: key}); err != nil {
+		logger.Warnf("Failed to delete object %s: %v", key, err)
+	}
+}
+
+// selectParts matches the completed parts against the stored ones and returns them with the total size.
+func selectParts(
+	upload *storage.MultipartUpload,
+	stored []storage.UploadedPart,
+	completed []storage.CompletedPart,
+) ([]storage.UploadedPart, int64, error) {
+	if len(completed) == 0 {
+		return nil, 0, fmt.Errorf("%w: no parts to complete", storage.ErrInvalidPart)
+	}
+
+	byNumber := make(map[int]storage.UploadedPart, len(stored))
+	for _, part := range stored {
+		byNumber[part.PartNumber] = part
+	}
+
+	var (
+		parts = make([]storage.UploadedPart, 0, len(completed))
+		size  int64
+	)
+
+	for i, c := range completed {
+		if i > 0 && c.PartNumber <= completed[i-1].PartNumber {
+			return nil, 0, fmt.Errorf("%w: parts must be listed in ascending order", storage.ErrInvalidPart)
+		}
+
+		part, ok := byNumber[c.PartNumber]
+		if !ok || !strings.EqualFold(strings.Trim(c.ETag, `"`), part.ETag) {
+			return nil, 0, fmt.Errorf("%w: part %d was not uploaded with ETag %s", storage.ErrInvalidPart, c.PartNumber, c.ETag)
+		}
+
+		if upload.PartSize > 0 && i < len(completed)-1 && part.Size != upload.PartSize {
+			return nil, 0, fmt.Errorf("%w: part %d has %d bytes, expected %d", storage.ErrInvalidPart, c.PartNumber, part.Size, upload.PartSize)
+		}
+
+		parts = append(parts, part)
+		size += part.Size
+	}
+
+	if upload.Size > 0 && size != upload.Size {
+		return nil, 0, fmt.Errorf("%w: parts have %d bytes, expected %d", storage.ErrInvalidPart, size, upload.Size)
+	}
+
+	return parts, size, nil
+}
+
+// composable reports whether the parts meet the minimum size of every source but the last of a backend merge.
+func composable(parts []storage.UploadedPart) bool {
+	for _, part := range parts[:len(parts)-1] {
+		if part.Size < storage.MinComposePartSize {
+			return false
+		}
+	}
+
+	return true
+}
+
+// partsReader reads the parts of an upload in order, opening each part once the previous one is exhausted.
+type partsReader struct {
+	ctx      context.Context
+	service  storage.Service
+	uploadID string
+	parts    []storage.UploadedPart
+	hasher   hash.Hash
+	current  io.ReadCloser
+}
+
+func (r *partsReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.parts) == 0 {
+				return 0, io.EOF
+			}
+
+			part := r.parts[0]
+			r.parts = r.parts[1:]
+
+			reader, err := r.service.GetObject(r.ctx, storage.GetObjectOptions{Key: partKey(r.uploadID, part.PartNumber, part.ETag)})
+			if err != nil {
+				return 0, err
+			}
+
+			r.current = reader
+		}
+
+		n, err := r.current.Read(p)
+		r.hasher.Write(p[:n])
+
+		if errors.Is(err, io.EOF) {
+			_ = r.current.Close()
+			r.current = nil
+
+			if n > 0 {
+				return n, nil
+			}
+
+			continue
+		}
+
+		return n, err
+	}
+}
+
+func (r *partsReader) Close() {
+	if r.current != nil {
+		_ = r.current.Close()
+		r.current = nil
+	}
+}
+
+// maxPartSize returns the size limit of parts, the declared part size or 64 MiB.
+func maxPartSize(partSize int64) int64 {
+	if partSize > 0 {
+		return partSize
+	}
+
+	return defaultMaxPartSize
+}
+
+func uploadPrefix(uploadID string) string {
+	return storage.MultipartPrefix + uploadID + constants.Slash
+}
+
+func manifestKey(uploadID string) string {
+	return uploadPrefix(uploadID) + manifestName
+}
+
+// partKey returns the key of a part, e.g. "temp/multipart/{uploadId}/00001.{md5}.part".
+func partKey(uploadID string, partNumber int, etag string) string {
+	return fmt.Sprintf("%s%05d.%s%s", uploadPrefix(uploadID), partNumber, etag, partExtension)
+}
+
+func parsePartKey(key string) (storage.UploadedPart, bool) {
+	name, ok := strings.CutSuffix(path.Base(key), partExtension)
+	if !ok {
+		return storage.UploadedPart{}, false
+	}
+
+	number, etag, ok := strings.Cut(name, constants.Dot)
+	if !ok || !isMD5(etag) {
+		return storage.UploadedPart{}, false
+	}
+
+	partNumber, err := strconv.Atoi(number)
+	if err != nil {
+		return storage.UploadedPart{}, false
+	}
+
+	return storage.UploadedPart{PartNumber: partNumber, ETag: etag}, true
+}
+
+func isMD5(checksum string) bool {
+	if len(checksum) != md5.Size*2 {
+		return false
+	}
+
+	_, err := hex.DecodeString(checksum)
+
+	return err == nil
+}
+
+// isUploadID reports whether the upload ID is safe to use in object keys.
+func isUploadID(uploadID string) bool {
+	return uploadID != constants.Empty && !strings.ContainsAny(uploadID, "/\\.")
+}
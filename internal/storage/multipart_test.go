@@ -0,0 +1,318 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	istorage "github.com/ilxqx/vef-framework-go/internal/storage"
+	"github.com/ilxqx/vef-framework-go/internal/storage/services/filesystem"
+	"github.com/ilxqx/vef-framework-go/internal/storage/services/memory"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func uploadPart(t *testing.T, uploader *istorage.MultipartUploader, uploadID string, number int, data []byte) *storage.UploadedPart {
+	t.Helper()
+
+	part, err := uploader.UploadPart(context.Background(), istorage.UploadPartOptions{
+		UploadID:   uploadID,
+		PartNumber: number,
+		Reader:     bytes.NewReader(data),
+		Size:       int64(len(data)),
+		MD5:        md5Hex(data),
+	})
+	require.NoError(t, err)
+
+	return part
+}
+
+func TestMultipartUploader(t *testing.T) {
+	backends := map[string]func(t *testing.T) storage.Service{
+		"Memory": func(*testing.T) storage.Service {
+			return memory.New()
+		},
+		"Filesystem": func(t *testing.T) storage.Service {
+			service, err := filesystem.New(config.FilesystemConfig{Root: t.TempDir()})
+			require.NoError(t, err)
+
+			return service
+		},
+	}
+
+	for name, newService := range backends {
+		t.Run(name, func(t *testing.T) {
+			testMultipartUploader(t, newService)
+		})
+	}
+}
+
+func testMultipartUploader(t *testing.T, newService func(t *testing.T) storage.Service) {
+	ctx := context.Background()
+	content := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	chunks := [][]byte{content[:10], content[10:20], content[20:30], content[30:]}
+
+	t.Run("CompleteWithResume", func(t *testing.T) {
+		service := newService(t)
+		uploader := istorage.NewMultipartUploader(service)
+
+		upload, err := uploader.Init(ctx, istorage.InitMultipartOptions{
+			Key:         "temp/2025/01/01/file.txt",
+			Filename:    "file.txt",
+			ContentType: "text/plain",
+			Size:        int64(len(content)),
+			PartSize:    10,
+			MD5:         md5Hex(content),
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, upload.UploadID)
+
+		// Parts may arrive out of order and be re-sent
+		part3 := uploadPart(t, uploader, upload.UploadID, 3, chunks[2])
+		part1 := uploadPart(t, uploader, upload.UploadID, 1, []byte("corrupted!"))
+		part1 = uploadPart(t, uploader, upload.UploadID, 1, chunks[0])
+
+		// Resume: another instance learns which parts are missing
+		resumed, err := uploader.Get(ctx, upload.UploadID)
+		require.NoError(t, err)
+		assert.Equal(t, upload.Key, resumed.Key)
+
+		parts, err := uploader.ListParts(ctx, upload.UploadID)
+		require.NoError(t, err)
+		require.Len(t, parts, 2, "Replaced parts should not be listed")
+		assert.Equal(t, *part1, parts[0])
+		assert.Equal(t, *part3, parts[1])
+
+		part2 := uploadPart(t, uploader, upload.UploadID, 2, chunks[1])
+		part4 := uploadPart(t, uploader, upload.UploadID, 4, chunks[3])
+
+		info, err := uploader.Complete(ctx, upload.UploadID, []storage.CompletedPart{
+			{PartNumber: 1, ETag: part1.ETag},
+			{PartNumber: 2, ETag: `"` + part2.ETag + `"`},
+			{PartNumber: 3, ETag: part3.ETag},
+			{PartNumber: 4, ETag: part4.ETag},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "temp/2025/01/01/file.txt", info.Key)
+		assert.Equal(t, int64(len(content)), info.Size)
+		assert.Equal(t, content, readObject(t, service, info.Key))
+
+		_, err = uploader.Get(ctx, upload.UploadID)
+		assert.ErrorIs(t, err, storage.ErrUploadNotFound, "Completed uploads should be removed")
+
+		leftovers, err := service.ListObjects(ctx, storage.ListObjectsOptions{Prefix: storage.MultipartPrefix, Recursive: true})
+		require.NoError(t, err)
+		assert.Empty(t, leftovers, "Parts should be removed after completion")
+	})
+
+	t.Run("PartChecksumMismatch", func(t *testing.T) {
+		service := newService(t)
+		uploader := istorage.NewMultipartUploader(service)
+
+		upload, err := uploader.Init(ctx, istorage.InitMultipartOptions{Key: "temp/a.bin"})
+		require.NoError(t, err)
+
+		_, err = uploader.UploadPart(ctx, istorage.UploadPartOptions{
+			UploadID:   upload.UploadID,
+			PartNumber: 1,
+			Reader:     bytes.NewReader(chunks[0]),
+			Size:       int64(len(chunks[0])),
+			MD5:        md5Hex(chunks[1]),
+		})
+		assert.ErrorIs(t, err, storage.ErrChecksumMismatch)
+
+		parts, err := uploader.ListParts(ctx, upload.UploadID)
+		require.NoError(t, err)
+		assert.Empty(t, parts, "Corrupted parts should be discarded")
+	})
+
+	t.Run("FileChecksumMismatch", func(t *testing.T) {
+		service := newService(t)
+		uploader := istorage.NewMultipartUploader(service)
+
+		upload, err := uploader.Init(ctx, istorage.InitMultipartOptions{Key: "temp/a.bin", MD5: md5Hex([]byte("other"))})
+		require.NoError(t, err)
+
+		part := uploadPart(t, uploader, upload.UploadID, 1, chunks[0])
+
+		_, err = uploader.Complete(ctx, upload.UploadID, []storage.CompletedPart{{PartNumber: 1, ETag: part.ETag}})
+		assert.ErrorIs(t, err, storage.ErrChecksumMismatch)
+
+		_, err = service.StatObject(ctx, storage.StatObjectOptions{Key: "temp/a.bin"})
+		assert.ErrorIs(t, err, storage.ErrObjectNotFound, "Merged object failing verification should be removed")
+	})
+
+	t.Run("InvalidCompletion", func(t *testing.T) {
+		service := newService(t)
+		uploader := istorage.NewMultipartUploader(service)
+
+		upload, err := uploader.Init(ctx, istorage.InitMultipartOptions{Key: "temp/a.bin", PartSize: 10, Size: 20})
+		require.NoError(t, err)
+
+		part1 := uploadPart(t, uploader, upload.UploadID, 1, chunks[0])
+		part2 := uploadPart(t, uploader, upload.UploadID, 2, []byte("short"))
+
+		tests := []struct {
+			name  string
+			parts []storage.CompletedPart
+		}{
+			{"Empty", nil},
+			{"Descending", []storage.CompletedPart{{PartNumber: 2, ETag: part2.ETag}, {PartNumber: 1, ETag: part1.ETag}}},
+			{"WrongETag", []storage.CompletedPart{{PartNumber: 1, ETag: part2.ETag}}},
+			{"MissingPart", []storage.CompletedPart{{PartNumber: 1, ETag: part1.ETag}, {PartNumber: 3, ETag: part2.ETag}}},
+			{"SizeMismatch", []storage.CompletedPart{{PartNumber: 1, ETag: part1.ETag}, {PartNumber: 2, ETag: part2.ETag}}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := uploader.Complete(ctx, upload.UploadID, tt.parts)
+
+				assert.ErrorIs(t, err, storage.ErrInvalidPart)
+			})
+		}
+
+		_, err = uploader.UploadPart(ctx, istorage.UploadPartOptions{
+			UploadID:   upload.UploadID,
+			PartNumber: 3,
+			Reader:     bytes.NewReader(content),
+			Size:       int64(len(content)),
+		})
+		assert.ErrorIs(t, err, storage.ErrInvalidPart, "Parts larger than the part size should be refused")
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		service := newService(t)
+		uploader := istorage.NewMultipartUploader(service)
+
+		upload, err := uploader.Init(ctx, istorage.InitMultipartOptions{Key: "temp/a.bin"})
+		require.NoError(t, err)
+
+		uploadPart(t, uploader, upload.UploadID, 1, chunks[0])
+
+		require.NoError(t, uploader.Abort(ctx, upload.UploadID))
+
+		_, err = uploader.UploadPart(ctx, istorage.UploadPartOptions{
+			UploadID:   upload.UploadID,
+			PartNumber: 2,
+			Reader:     bytes.NewReader(chunks[1]),
+			Size:       int64(len(chunks[1])),
+		})
+		assert.ErrorIs(t, err, storage.ErrUploadNotFound)
+		assert.ErrorIs(t, uploader.Abort(ctx, upload.UploadID), storage.ErrUploadNotFound)
+	})
+
+	t.Run("Sweep", func(t *testing.T) {
+		service := newService(t)
+		uploader := istorage.NewMultipartUploader(service)
+
+		upload, err := uploader.Init(ctx, istorage.InitMultipartOptions{Key: "temp/a.bin"})
+		require.NoError(t, err)
+
+		uploadPart(t, uploader, upload.UploadID, 1, chunks[0])
+
+		removed, err := uploader.Sweep(ctx, time.Hour)
+		require.NoError(t, err)
+		assert.Zero(t, removed, "Active uploads should be kept")
+
+		_, err = uploader.Get(ctx, upload.UploadID)
+		require.NoError(t, err)
+
+		removed, err = uploader.Sweep(ctx, -time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed, "Uploads without activity since the expiry should be removed")
+
+		leftovers, err := service.ListObjects(ctx, storage.ListObjectsOptions{Prefix: storage.MultipartPrefix, Recursive: true})
+		require.NoError(t, err)
+		assert.Empty(t, leftovers, "Manifest and parts of expired uploads should be removed")
+	})
+
+	t.Run("InvalidUploadID", func(t *testing.T) {
+		uploader := istorage.NewMultipartUploader(newService(t))
+
+		_, err := uploader.Get(ctx, "../../etc")
+		assert.ErrorIs(t, err, storage.ErrUploadNotFound)
+	})
+}
+
+// composingService merges objects like a backend composing them, recording the merges.
+type composingService struct {
+	storage.Service
+
+	composed [][]string
+}
+
+func (s *composingService) ComposeObject(ctx context.Context, opts storage.ComposeObjectOptions) (*storage.ObjectInfo, error) {
+	s.composed = append(s.composed, opts.SourceKeys)
+
+	var merged bytes.Buffer
+	for _, key := range opts.SourceKeys {
+		reader, err := s.GetObject(ctx, storage.GetObjectOptions{Key: key})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = merged.ReadFrom(reader)
+		_ = reader.Close()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.PutObject(ctx, storage.PutObjectOptions{
+		Key:         opts.DestKey,
+		Reader:      &merged,
+		Size:        int64(merged.Len()),
+		ContentType: opts.ContentType,
+		Metadata:    opts.Metadata,
+	})
+}
+
+func TestMultipartUploaderCompose(t *testing.T) {
+	ctx := context.Background()
+	large := bytes.Repeat([]byte("a"), storage.MinComposePartSize)
+
+	tests := []struct {
+		name     string
+		chunks   [][]byte
+		composed bool
+	}{
+		{"LargeParts", [][]byte{large, []byte("tail")}, true},
+		{"SmallParts", [][]byte{[]byte("head"), []byte("tail")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &composingService{Service: memory.New()}
+			uploader := istorage.NewMultipartUploader(service)
+			content := bytes.Join(tt.chunks, nil)
+
+			upload, err := uploader.Init(ctx, istorage.InitMultipartOptions{Key: "temp/a.bin", MD5: md5Hex(content)})
+			require.NoError(t, err)
+
+			completed := make([]storage.CompletedPart, len(tt.chunks))
+			for i, chunk := range tt.chunks {
+				part := uploadPart(t, uploader, upload.UploadID, i+1, chunk)
+				completed[i] = storage.CompletedPart{PartNumber: part.PartNumber, ETag: part.ETag}
+			}
+
+			info, err := uploader.Complete(ctx, upload.UploadID, completed)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(content)), info.Size)
+			assert.Equal(t, content, readObject(t, service, info.Key))
+			assert.Equal(t, tt.composed, len(service.composed) == 1, "Parts should be composed by the backend when large enough")
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/taskpool"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+const (
+	defaultProcessingWorkers = 2
+	defaultProcessingQueue   = 100
+	defaultProcessingTimeout = 2 * time.Minute
+	defaultProcessingMaxSize = 32 << 20
+)
+
+// Pipeline post-processes uploaded files in the background.
+// Each upload runs through the processors in order and its outcome is recorded in the object metadata:
+// completed uploads are replaced by the processed content, rejected uploads are emptied and failed uploads are kept unchanged.
+// Uploads larger than the processing size limit are not scanned but quarantined as failed;
+// only completed uploads are served while the pipeline is enabled.
+type Pipeline struct {
+	service    storage.Service
+	processors []storage.Processor
+	publisher  event.Publisher
+	maxSize    int64
+	scheduler  taskpool.Scheduler[string, struct{}]
+}
+
+// NewPipeline creates the upload processing pipeline, or returns nil when processing is disabled.
+// The built-in processors sniff the content type, scan for viruses when clamd is configured and process images;
+// custom processors run after them.
+func NewPipeline(
+	cfg *config.StorageConfig,
+	service storage.Service,
+	publisher event.Publisher,
+	processors []storage.Processor,
+) (*Pipeline, error) {
+	pc := cfg.Processing
+	if !pc.Enabled {
+		return nil, nil
+	}
+
+	builtins := []storage.Processor{NewContentTypeProcessor()}
+
+	if pc.ClamAV.Address != constants.Empty {
+		scanner, err := NewClamAVProcessor(pc.ClamAV)
+		if err != nil {
+			return nil, err
+		}
+
+		builtins = append(builtins, scanner)
+	}
+
+	builtins = append(builtins, NewImageProcessor(pc.Image))
+
+	return newPipeline(pc, service, publisher, append(builtins, processors...))
+}
+
+func newPipeline(
+	cfg config.ProcessingConfig,
+	service storage.Service,
+	publisher event.Publisher,
+	processors []storage.Processor,
+) (*Pipeline, error) {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = defaultProcessingWorkers
+	}
+
+	queue := cfg.Queue
+	if queue <= 0 {
+		queue = defaultProcessingQueue
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultProcessingTimeout
+	}
+
+	maxSize := cfg.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultProcessingMaxSize
+	}
+
+	p := &Pipeline{
+		service:    service,
+		processors: processors,
+		publisher:  publisher,
+		maxSize:    maxSize,
+	}
+
+	scheduler, err := taskpool.New(taskpool.Config[string, struct{}]{
+		MinWorkers:     1,
+		MaxWorkers:     workers,
+		TaskQueueSize:  queue,
+		TaskTimeout:    timeout,
+		MaxTaskTimeout: timeout,
+		DelegateFactory: func() taskpool.WorkerDelegate[string, struct{}] {
+			return &processingDelegate{pipeline: p}
+		},
+		Logger: logger.Named("processing"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload processing pool: %w", err)
+	}
+
+	p.scheduler = scheduler
+
+	return p, nil
+}
+
+// Submit queues the uploaded object for processing, which must have been stored with the pending status.
+// When the queue is full the object is marked as failed instead.
+func (p *Pipeline) Submit(ctx context.Context, info *storage.ObjectInfo) error {
+	// Processing outlives the upload request
+	if _, err := p.scheduler.SubmitAsync(context.Background(), info.Key); err != nil {
+		logger.Warnf("Failed to queue object %s for processing: %v", info.Key, err)
+
+		return p.fail(ctx, info, err.Error())
+	}
+
+	return nil
+}
+
+// Shutdown waits for the queued uploads to be processed.
+func (p *Pipeline) Shutdown(ctx context.Context) error {
+	return p.scheduler.Shutdown(ctx)
+}
+
+// Process runs the processors on the uploaded object and records the outcome in its metadata.
+func (p *Pipeline) Process(ctx context.Context, key string) error {
+	info, err := p.stat(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			logger.Warnf("Skipped processing of missing object %s", key)
+
+			return nil
+		}
+
+		return err
+	}
+
+	key = info.Key
+
+	if info.Size > p.maxSize {
+		logger.Warnf("Quarantined object %s of %d bytes exceeding the processing size limit", key, info.Size)
+
+		return p.fail(ctx, info, fmt.Sprintf("file exceeds the processing size limit of %d bytes", p.maxSize))
+	}
+
+	data, err := p.read(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	file := &storage.ProcessingFile{
+		Key:         key,
+		ContentType: info.ContentType,
+		Data:        data,
+		Metadata:    maps.Clone(info.Metadata),
+	}
+	if file.Metadata == nil {
+		file.Metadata = make(map[string]string)
+	}
+
+	for _, processor := range p.processors {
+		err := processor.Process(ctx, file)
+		if err == nil {
+			continue
+		}
+
+		if errors.Is(err, storage.ErrFileRejected) {
+			logger.Warnf("Processor %s rejected object %s: %v", processor.Name(), key, err)
+
+			return p.reject(ctx, info, err.Error())
+		}
+
+		logger.Errorf("Processor %s failed on object %s: %v", processor.Name(), key, err)
+
+		return p.fail(ctx, info, err.Error())
+	}
+
+	return p.complete(ctx, file)
+}
+
+// stat returns the object to process, which is the promoted object when the upload was promoted before it was processed.
+func (p *Pipeline) stat(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	info, err := p.service.StatObject(ctx, storage.StatObjectOptions{Key: key})
+	if err == nil || !errors.Is(err, storage.ErrObjectNotFound) || !strings.HasPrefix(key, storage.TempPrefix) {
+		return info, err
+	}
+
+	info, err = p.service.StatObject(ctx, storage.StatObjectOptions{Key: strings.TrimPrefix(key, storage.TempPrefix)})
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Metadata[storage.MetadataKeyProcessingStatus] != string(storage.ProcessingPending) {
+		return nil, storage.ErrObjectNotFound
+	}
+
+	return info, nil
+}
+
+func (p *Pipeline) read(ctx context.Context, key string) ([]byte, error) {
+	reader, err := p.service.GetObject(ctx, storage.GetObjectOptions{Key: key})
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if closeErr := reader.Close(); closeErr != nil {
+			logger.Errorf("failed to close object %s: %v", key, closeErr)
+		}
+	}()
+
+	return io.ReadAll(io.LimitReader(reader, p.maxSize+1))
+}
+
+func (p *Pipeline) complete(ctx context.Context, file *storage.ProcessingFile) error {
+	for _, variant := range file.Variants {
+		key := VariantKey(file.Key, variant.Suffix)
+		if _, err := p.service.PutObject(ctx, storage.PutObjectOptions{
+			Key:         key,
+			Reader:      bytes.NewReader(variant.Data),
+			Size:        int64(len(variant.Data)),
+			ContentType: variant.ContentType,
+		}); err != nil {
+			return err
+		}
+
+		if variant.Suffix == ThumbnailSuffix {
+			file.Metadata[storage.MetadataKeyThumbnailKey] = key
+		}
+	}
+
+	file.Metadata[storage.MetadataKeyProcessingStatus] = string(storage.ProcessingCompleted)
+	delete(file.Metadata, storage.MetadataKeyProcessingError)
+
+	if _, err := p.service.PutObject(ctx, storage.PutObjectOptions{
+		Key:         file.Key,
+		Reader:      bytes.NewReader(file.Data),
+		Size:        int64(len(file.Data)),
+		ContentType: file.ContentType,
+		Metadata:    file.Metadata,
+	}); err != nil {
+		return err
+	}
+
+	p.publish(file.Key, storage.ProcessingCompleted, constants.Empty)
+
+	return nil
+}
+
+// reject empties the object so that the refused content can never be served.
+func (p *Pipeline) reject(ctx context.Context, info *storage.ObjectInfo, reason string) error {
+	metadata := statusMetadata(info.Metadata, storage.ProcessingRejected, reason)
+	if _, err := p.service.PutObject(ctx, storage.PutObjectOptions{
+		Key:         info.Key,
+		Reader:      bytes.NewReader(nil),
+		ContentType: "application/octet-stream",
+		Metadata:    metadata,
+	}); err != nil {
+		return err
+	}
+
+	p.publish(info.Key, storage.ProcessingRejected, reason)
+
+	return nil
+}
+
+// fail keeps the object content and only records the failure, the object is rewritten since metadata cannot be updated in place.
+func (p *Pipeline) fail(ctx context.Context, info *storage.ObjectInfo, reason string) error {
+	reader, err := p.service.GetObject(ctx, storage.GetObjectOptions{Key: info.Key})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if closeErr := reader.Close(); closeErr != nil {
+			logger.Errorf("failed to close object %s: %v", info.Key, closeErr)
+		}
+	}()
+
+	if _, err := p.service.PutObject(ctx, storage.PutObjectOptions{
+		Key:         info.Key,
+		Reader:      reader,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		Metadata:    statusMetadata(info.Metadata, storage.ProcessingFailed, reason),
+	}); err != nil {
+		return err
+	}
+
+	p.publish(info.Key, storage.ProcessingFailed, reason)
+
+	return nil
+}
+
+func (p *Pipeline) publish(key string, status storage.ProcessingStatus, reason string) {
+	if p.publisher != nil {
+		p.publisher.Publish(storage.NewFileProcessedEvent(key, status, reason))
+	}
+}
+
+func statusMetadata(metadata map[string]string, status storage.ProcessingStatus, reason string) map[string]string {
+	result := maps.Clone(metadata)
+	if result == nil {
+		result = make(map[string]string, 2)
+	}
+
+	result[storage.MetadataKeyProcessingStatus] = string(status)
+	result[storage.MetadataKeyProcessingError] = reason
+
+	return result
+}
+
+// checkProcessed refuses uploads whose processing has not completed.
+// Objects without a processing status, such as variants and objects stored by the application, are not uploads and pass.
+func checkProcessed(info *storage.ObjectInfo) error {
+	status, ok := info.Metadata[storage.MetadataKeyProcessingStatus]
+	if ok && status != string(storage.ProcessingCompleted) {
+		return storage.ErrFileNotProcessed
+	}
+
+	return nil
+}
+
+// VariantKey returns the key of a variant of the object, e.g. "a/b_thumb.jpg" for "a/b.jpg" and "thumb".
+func VariantKey(key, suffix string) string {
+	ext := path.Ext(key)
+
+	return strings.TrimSuffix(key, ext) + constants.Underscore + suffix + ext
+}
+
+// processingDelegate processes the uploads queued to a pool worker.
+type processingDelegate struct {
+	pipeline *Pipeline
+}
+
+func (*processingDelegate) Init(context.Context, any) error {
+	return nil
+}
+
+func (d *processingDelegate) Execute(ctx context.Context, key string) (struct{}, error) {
+	if err := d.pipeline.Process(ctx, key); err != nil {
+		logger.Errorf("Failed to process object %s: %v", key, err)
+
+		return struct{}{}, err
+	}
+
+	return struct{}{}, nil
+}
+
+func (*processingDelegate) Destroy() error {
+	return nil
+}
+
+func (*processingDelegate) HealthCheck() error {
+	return nil
+}
@@ -0,0 +1,333 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	istorage "github.com/ilxqx/vef-framework-go/internal/storage"
+	"github.com/ilxqx/vef-framework-go/internal/storage/services/memory"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []event.Event
+}
+
+func (p *recordingPublisher) Publish(evt event.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, evt)
+}
+
+func (p *recordingPublisher) processed() []*storage.FileProcessedEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var events []*storage.FileProcessedEvent
+	for _, evt := range p.events {
+		if processed, ok := evt.(*storage.FileProcessedEvent); ok {
+			events = append(events, processed)
+		}
+	}
+
+	return events
+}
+
+type funcProcessor func(ctx context.Context, file *storage.ProcessingFile) error
+
+func (funcProcessor) Name() string {
+	return "func"
+}
+
+func (f funcProcessor) Process(ctx context.Context, file *storage.ProcessingFile) error {
+	return f(ctx, file)
+}
+
+func newJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+
+	return buf.Bytes()
+}
+
+func putPending(t *testing.T, service storage.Service, key, contentType string, data []byte) *storage.ObjectInfo {
+	t.Helper()
+
+	info, err := service.PutObject(context.Background(), storage.PutObjectOptions{
+		Key:         key,
+		Reader:      bytes.NewReader(data),
+		Size:        int64(len(data)),
+		ContentType: contentType,
+		Metadata: map[string]string{
+			storage.MetadataKeyOriginalFilename: "original",
+			storage.MetadataKeyProcessingStatus: string(storage.ProcessingPending),
+		},
+	})
+	require.NoError(t, err)
+
+	return info
+}
+
+func readObject(t *testing.T, service storage.Service, key string) []byte {
+	t.Helper()
+
+	reader, err := service.GetObject(context.Background(), storage.GetObjectOptions{Key: key})
+	require.NoError(t, err)
+
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestNewPipelineDisabled(t *testing.T) {
+	pipeline, err := istorage.NewPipeline(&config.StorageConfig{}, memory.New(), nil, nil)
+
+	require.NoError(t, err)
+	assert.Nil(t, pipeline, "Disabled processing should not create a pipeline")
+}
+
+func TestPipelineProcess(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("CompletedImage", func(t *testing.T) {
+		service := memory.New()
+		publisher := new(recordingPublisher)
+
+		pipeline, err := istorage.NewPipeline(&config.StorageConfig{
+			Processing: config.ProcessingConfig{
+				Enabled: true,
+				Image: config.ImageConfig{
+					MaxWidth:       100,
+					ThumbnailWidth: 20,
+					StripEXIF:      true,
+				},
+			},
+		}, service, publisher, nil)
+		require.NoError(t, err)
+
+		defer pipeline.Shutdown(ctx)
+
+		putPending(t, service, "temp/photo.jpg", "application/octet-stream", newJPEG(t, 200, 100))
+
+		require.NoError(t, pipeline.Process(ctx, "temp/photo.jpg"))
+
+		info, err := service.StatObject(ctx, storage.StatObjectOptions{Key: "temp/photo.jpg"})
+		require.NoError(t, err)
+		assert.Equal(t, string(storage.ProcessingCompleted), info.Metadata[storage.MetadataKeyProcessingStatus])
+		assert.Equal(t, "original", info.Metadata[storage.MetadataKeyOriginalFilename], "Existing metadata should be kept")
+		assert.Equal(t, "temp/photo_thumb.jpg", info.Metadata[storage.MetadataKeyThumbnailKey])
+		assert.Equal(t, "image/jpeg", info.ContentType, "Generic content type should be replaced by the sniffed one")
+
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(readObject(t, service, "temp/photo.jpg")))
+		require.NoError(t, err)
+		assert.Equal(t, 100, cfg.Width)
+		assert.Equal(t, 50, cfg.Height)
+
+		thumb, _, err := image.DecodeConfig(bytes.NewReader(readObject(t, service, "temp/photo_thumb.jpg")))
+		require.NoError(t, err)
+		assert.Equal(t, 20, thumb.Width)
+		assert.Equal(t, 10, thumb.Height)
+
+		events := publisher.processed()
+		require.Len(t, events, 1)
+		assert.Equal(t, storage.ProcessingCompleted, events[0].Status)
+		assert.Equal(t, "temp/photo.jpg", events[0].FileKey)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		service := memory.New()
+		publisher := new(recordingPublisher)
+
+		pipeline, err := istorage.NewPipeline(&config.StorageConfig{
+			Processing: config.ProcessingConfig{Enabled: true},
+		}, service, publisher, []storage.Processor{
+			funcProcessor(func(context.Context, *storage.ProcessingFile) error {
+				return errors.Join(storage.ErrFileRejected, errors.New("virus found"))
+			}),
+		})
+		require.NoError(t, err)
+
+		defer pipeline.Shutdown(ctx)
+
+		putPending(t, service, "temp/evil.txt", "text/plain", []byte("evil content"))
+
+		require.NoError(t, pipeline.Process(ctx, "temp/evil.txt"))
+
+		info, err := service.StatObject(ctx, storage.StatObjectOptions{Key: "temp/evil.txt"})
+		require.NoError(t, err)
+		assert.Equal(t, string(storage.ProcessingRejected), info.Metadata[storage.MetadataKeyProcessingStatus])
+		assert.Contains(t, info.Metadata[storage.MetadataKeyProcessingError], "virus found")
+		assert.Empty(t, readObject(t, service, "temp/evil.txt"), "Rejected content should be removed")
+
+		events := publisher.processed()
+		require.Len(t, events, 1)
+		assert.Equal(t, storage.ProcessingRejected, events[0].Status)
+	})
+
+	t.Run("HTMLDisguisedAsImage", func(t *testing.T) {
+		service := memory.New()
+
+		pipeline, err := istorage.NewPipeline(&config.StorageConfig{
+			Processing: config.ProcessingConfig{Enabled: true},
+		}, service, nil, nil)
+		require.NoError(t, err)
+
+		defer pipeline.Shutdown(ctx)
+
+		putPending(t, service, "temp/fake.png", "image/png", []byte("<html><script>alert(1)</script></html>"))
+
+		require.NoError(t, pipeline.Process(ctx, "temp/fake.png"))
+
+		info, err := service.StatObject(ctx, storage.StatObjectOptions{Key: "temp/fake.png"})
+		require.NoError(t, err)
+		assert.Equal(t, string(storage.ProcessingRejected), info.Metadata[storage.MetadataKeyProcessingStatus])
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		service := memory.New()
+
+		pipeline, err := istorage.NewPipeline(&config.StorageConfig{
+			Processing: config.ProcessingConfig{Enabled: true},
+		}, service, nil, []storage.Processor{
+			funcProcessor(func(_ context.Context, file *storage.ProcessingFile) error {
+				file.Data = []byte("partially processed")
+
+				return errors.New("scanner unavailable")
+			}),
+		})
+		require.NoError(t, err)
+
+		defer pipeline.Shutdown(ctx)
+
+		putPending(t, service, "temp/doc.txt", "text/plain", []byte("document"))
+
+		require.NoError(t, pipeline.Process(ctx, "temp/doc.txt"))
+
+		info, err := service.StatObject(ctx, storage.StatObjectOptions{Key: "temp/doc.txt"})
+		require.NoError(t, err)
+		assert.Equal(t, string(storage.ProcessingFailed), info.Metadata[storage.MetadataKeyProcessingStatus])
+		assert.Equal(t, "scanner unavailable", info.Metadata[storage.MetadataKeyProcessingError])
+		assert.Equal(t, []byte("document"), readObject(t, service, "temp/doc.txt"), "Failed uploads should be kept unchanged")
+	})
+
+	t.Run("ExceedsMaxSize", func(t *testing.T) {
+		service := memory.New()
+		called := false
+
+		pipeline, err := istorage.NewPipeline(&config.StorageConfig{
+			Processing: config.ProcessingConfig{Enabled: true, MaxSize: 4},
+		}, service, nil, []storage.Processor{
+			funcProcessor(func(context.Context, *storage.ProcessingFile) error {
+				called = true
+
+				return nil
+			}),
+		})
+		require.NoError(t, err)
+
+		defer pipeline.Shutdown(ctx)
+
+		putPending(t, service, "temp/big.txt", "text/plain", []byte("too large"))
+
+		require.NoError(t, pipeline.Process(ctx, "temp/big.txt"))
+
+		info, err := service.StatObject(ctx, storage.StatObjectOptions{Key: "temp/big.txt"})
+		require.NoError(t, err)
+		assert.False(t, called, "Processors should not run on oversized files")
+		assert.Equal(t, string(storage.ProcessingFailed), info.Metadata[storage.MetadataKeyProcessingStatus])
+		assert.Equal(t, []byte("too large"), readObject(t, service, "temp/big.txt"))
+	})
+
+	t.Run("PromotedObject", func(t *testing.T) {
+		service := memory.New()
+
+		pipeline, err := istorage.NewPipeline(&config.StorageConfig{
+			Processing: config.ProcessingConfig{Enabled: true},
+		}, service, nil, nil)
+		require.NoError(t, err)
+
+		defer pipeline.Shutdown(ctx)
+
+		putPending(t, service, "temp/promoted.txt", constants.Empty, []byte("plain notes"))
+
+		_, err = service.PromoteObject(ctx, "temp/promoted.txt")
+		require.NoError(t, err)
+
+		require.NoError(t, pipeline.Process(ctx, "temp/promoted.txt"))
+
+		info, err := service.StatObject(ctx, storage.StatObjectOptions{Key: "promoted.txt"})
+		require.NoError(t, err)
+		assert.Equal(t, string(storage.ProcessingCompleted), info.Metadata[storage.MetadataKeyProcessingStatus], "Promoted upload should be processed under its permanent key")
+		assert.Equal(t, "text/plain", info.ContentType)
+	})
+
+	t.Run("MissingObject", func(t *testing.T) {
+		pipeline, err := istorage.NewPipeline(&config.StorageConfig{
+			Processing: config.ProcessingConfig{Enabled: true},
+		}, memory.New(), nil, nil)
+		require.NoError(t, err)
+
+		defer pipeline.Shutdown(ctx)
+
+		assert.NoError(t, pipeline.Process(ctx, "temp/missing.txt"), "Missing objects should be skipped")
+	})
+}
+
+func TestPipelineSubmit(t *testing.T) {
+	ctx := context.Background()
+	service := memory.New()
+	publisher := new(recordingPublisher)
+
+	pipeline, err := istorage.NewPipeline(&config.StorageConfig{
+		Processing: config.ProcessingConfig{Enabled: true},
+	}, service, publisher, nil)
+	require.NoError(t, err)
+
+	defer pipeline.Shutdown(ctx)
+
+	info := putPending(t, service, "temp/notes.txt", constants.Empty, []byte("plain notes"))
+
+	require.NoError(t, pipeline.Submit(ctx, info))
+
+	require.Eventually(t, func() bool {
+		return len(publisher.processed()) == 1
+	}, 5*time.Second, 10*time.Millisecond, "Submitted upload should be processed in the background")
+
+	stat, err := service.StatObject(ctx, storage.StatObjectOptions{Key: "temp/notes.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, string(storage.ProcessingCompleted), stat.Metadata[storage.MetadataKeyProcessingStatus])
+	assert.Equal(t, "text/plain", stat.ContentType)
+}
+
+func TestVariantKey(t *testing.T) {
+	assert.Equal(t, "temp/2025/a_thumb.jpg", istorage.VariantKey("temp/2025/a.jpg", "thumb"))
+	assert.Equal(t, "temp/a_thumb", istorage.VariantKey("temp/a", "thumb"))
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+const (
+	defaultClamAVTimeout = 30 * time.Second
+	// clamAVChunkSize is the size of the chunks streamed to clamd, well below its default StreamMaxLength.
+	clamAVChunkSize = 64 << 10
+)
+
+var (
+	// ErrInvalidClamAVAddress indicates the clamd address is neither a tcp nor a unix address.
+	ErrInvalidClamAVAddress = errors.New("invalid clamav address")
+	// ErrClamAVScan indicates clamd could not scan the content.
+	ErrClamAVScan = errors.New("clamav scan failed")
+)
+
+// ClamAVProcessor scans uploads for viruses with a clamd daemon using its INSTREAM command.
+type ClamAVProcessor struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamAVProcessor creates a virus scanning processor for the clamd address,
+// either "tcp://host:port", "unix:///path/to/clamd.sock" or a bare "host:port".
+func NewClamAVProcessor(cfg config.ClamAVConfig) (*ClamAVProcessor, error) {
+	network, address, err := parseClamAVAddress(cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultClamAVTimeout
+	}
+
+	return &ClamAVProcessor{
+		network: network,
+		address: address,
+		timeout: timeout,
+	}, nil
+}
+
+func parseClamAVAddress(address string) (network, addr string, err error) {
+	if !strings.Contains(address, "://") {
+		return "tcp", address, nil
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return constants.Empty, constants.Empty, fmt.Errorf("%w: %w", ErrInvalidClamAVAddress, err)
+	}
+
+	switch u.Scheme {
+	case "tcp":
+		return "tcp", u.Host, nil
+	case "unix":
+		return "unix", u.Path, nil
+	default:
+		return constants.Empty, constants.Empty, fmt.Errorf("%w: unsupported scheme %q", ErrInvalidClamAVAddress, u.Scheme)
+	}
+}
+
+func (*ClamAVProcessor) Name() string {
+	return "clamav"
+}
+
+func (p *ClamAVProcessor) Process(ctx context.Context, file *storage.ProcessingFile) error {
+	signature, err := p.Scan(ctx, file.Data)
+	if err != nil {
+		return err
+	}
+
+	if signature != constants.Empty {
+		return fmt.Errorf("%w: virus %s found", storage.ErrFileRejected, signature)
+	}
+
+	return nil
+}
+
+// Scan streams data to clamd and returns the signature of the virus found, or an empty string for clean data.
+func (p *ClamAVProcessor) Scan(ctx context.Context, data []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, p.network, p.address)
+	if err != nil {
+		return constants.Empty, fmt.Errorf("%w: %w", ErrClamAVScan, err)
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	writer := bufio.NewWriter(conn)
+	if _, err := writer.WriteString("zINSTREAM\x00"); err != nil {
+		return constants.Empty, fmt.Errorf("%w: %w", ErrClamAVScan, err)
+	}
+
+	var size [4]byte
+
+	for chunk := range slices.Chunk(data, clamAVChunkSize) {
+		binary.BigEndian.PutUint32(size[:], uint32(len(chunk)))
+
+		if _, err := writer.Write(size[:]); err != nil {
+			return constants.Empty, fmt.Errorf("%w: %w", ErrClamAVScan, err)
+		}
+
+		if _, err := writer.Write(chunk); err != nil {
+			return constants.Empty, fmt.Errorf("%w: %w", ErrClamAVScan, err)
+		}
+	}
+
+	// A zero-length chunk ends the stream
+	clear(size[:])
+
+	if _, err := writer.Write(size[:]); err != nil {
+		return constants.Empty, fmt.Errorf("%w: %w", ErrClamAVScan, err)
+	}
+
+	if err := writer.Flush(); err != nil {
+		return constants.Empty, fmt.Errorf("%w: %w", ErrClamAVScan, err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return constants.Empty, fmt.Errorf("%w: %w", ErrClamAVScan, err)
+	}
+
+	return parseClamAVReply(strings.TrimSuffix(reply, "\x00"))
+}
+
+// parseClamAVReply parses replies such as "stream: OK" and "stream: Eicar-Signature FOUND".
+func parseClamAVReply(reply string) (string, error) {
+	_, result, _ := strings.Cut(reply, ": ")
+
+	switch {
+	case result == "OK":
+		return constants.Empty, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return strings.TrimSuffix(result, " FOUND"), nil
+	default:
+		return constants.Empty, fmt.Errorf("%w: %s", ErrClamAVScan, reply)
+	}
+}
@@ -0,0 +1,126 @@
+package storage_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	istorage "github.com/ilxqx/vef-framework-go/internal/storage"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+// startFakeClamd serves the INSTREAM command, reporting a virus for streams containing "EICAR".
+func startFakeClamd(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go serveClamd(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveClamd(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	command, err := reader.ReadString(0)
+	if err != nil || command != "zINSTREAM\x00" {
+		_, _ = conn.Write([]byte("UNKNOWN COMMAND\x00"))
+
+		return
+	}
+
+	var (
+		stream bytes.Buffer
+		size   [4]byte
+	)
+
+	for {
+		if _, err := io.ReadFull(reader, size[:]); err != nil {
+			return
+		}
+
+		n := binary.BigEndian.Uint32(size[:])
+		if n == 0 {
+			break
+		}
+
+		if _, err := io.CopyN(&stream, reader, int64(n)); err != nil {
+			return
+		}
+	}
+
+	if bytes.Contains(stream.Bytes(), []byte("EICAR")) {
+		_, _ = conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+	} else {
+		_, _ = conn.Write([]byte("stream: OK\x00"))
+	}
+}
+
+func TestClamAVProcessor(t *testing.T) {
+	ctx := context.Background()
+	address := startFakeClamd(t)
+
+	processor, err := istorage.NewClamAVProcessor(config.ClamAVConfig{Address: "tcp://" + address})
+	require.NoError(t, err)
+
+	t.Run("Clean", func(t *testing.T) {
+		err := processor.Process(ctx, &storage.ProcessingFile{Data: bytes.Repeat([]byte("clean "), 30000)})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Infected", func(t *testing.T) {
+		err := processor.Process(ctx, &storage.ProcessingFile{Data: []byte("X5O!P%@AP EICAR test file")})
+
+		assert.ErrorIs(t, err, storage.ErrFileRejected)
+		assert.Contains(t, err.Error(), "Eicar-Test-Signature")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		signature, err := processor.Scan(ctx, nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, signature)
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		unreachable, err := istorage.NewClamAVProcessor(config.ClamAVConfig{Address: "127.0.0.1:1"})
+		require.NoError(t, err)
+
+		err = unreachable.Process(ctx, &storage.ProcessingFile{Data: []byte("data")})
+
+		assert.ErrorIs(t, err, istorage.ErrClamAVScan)
+		assert.NotErrorIs(t, err, storage.ErrFileRejected, "Scan errors should fail processing instead of rejecting")
+	})
+}
+
+func TestNewClamAVProcessorInvalidAddress(t *testing.T) {
+	_, err := istorage.NewClamAVProcessor(config.ClamAVConfig{Address: "http://127.0.0.1:3310"})
+
+	assert.ErrorIs(t, err, istorage.ErrInvalidClamAVAddress)
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+const contentTypeOctetStream = "application/octet-stream"
+
+// ContentTypeProcessor verifies the declared content type of uploads against their sniffed content.
+// HTML disguised as another type and media whose content does not match their declared kind are rejected;
+// missing or generic content types are replaced by the sniffed one.
+type ContentTypeProcessor struct{}
+
+// NewContentTypeProcessor creates a content type sniffing processor.
+func NewContentTypeProcessor() *ContentTypeProcessor {
+	return new(ContentTypeProcessor)
+}
+
+func (*ContentTypeProcessor) Name() string {
+	return "content_type"
+}
+
+func (*ContentTypeProcessor) Process(_ context.Context, file *storage.ProcessingFile) error {
+	sniffed := mediaType(http.DetectContentType(file.Data))
+	declared := mediaType(file.ContentType)
+
+	if declared == constants.Empty || declared == contentTypeOctetStream {
+		file.ContentType = sniffed
+
+		return nil
+	}
+
+	if sniffed == "text/html" && declared != sniffed {
+		return fmt.Errorf("%w: HTML content declared as %s", storage.ErrFileRejected, declared)
+	}
+
+	if !isMediaKind(declared) || sniffed == contentTypeOctetStream || declared == sniffed {
+		return nil
+	}
+
+	if kind(declared) != kind(sniffed) {
+		return fmt.Errorf("%w: content of type %s declared as %s", storage.ErrFileRejected, sniffed, declared)
+	}
+
+	// Same kind of media with another format, e.g. a JPEG uploaded as image/png
+	file.ContentType = sniffed
+
+	return nil
+}
+
+// mediaType returns the content type without parameters.
+func mediaType(contentType string) string {
+	if contentType == constants.Empty {
+		return constants.Empty
+	}
+
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mt
+	}
+
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// kind returns the top-level type of a media type, e.g. "image" for "image/png".
+func kind(mediaType string) string {
+	k, _, _ := strings.Cut(mediaType, constants.Slash)
+
+	return k
+}
+
+// isMediaKind reports whether the media type is a binary image, audio or video type,
+// whose content is identified reliably by sniffing.
+func isMediaKind(mediaType string) bool {
+	if mediaType == "image/svg+xml" {
+		return false
+	}
+
+	switch kind(mediaType) {
+	case "image", "audio", "video":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,321 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+const (
+	// ThumbnailSuffix is the variant suffix of generated thumbnails.
+	ThumbnailSuffix = "thumb"
+
+	imageFormatJPEG = "jpeg"
+	imageFormatPNG  = "png"
+	imageFormatGIF  = "gif"
+
+	jpegQuality = 90
+	// maxImagePixels bounds the decoded size of images to guard against decompression bombs.
+	maxImagePixels = 64 << 20
+)
+
+// ErrImageTooLarge indicates the decoded image would exceed the pixel limit.
+var ErrImageTooLarge = errors.New("image too large")
+
+// ImageProcessor scales down oversized images, generates thumbnails and strips metadata from JPEG images.
+// Images are re-encoded in their format; animated GIF images are only used for thumbnails to keep their frames.
+type ImageProcessor struct {
+	cfg config.ImageConfig
+}
+
+// NewImageProcessor creates an image processor.
+func NewImageProcessor(cfg config.ImageConfig) *ImageProcessor {
+	return &ImageProcessor{cfg: cfg}
+}
+
+func (*ImageProcessor) Name() string {
+	return "image"
+}
+
+func (p *ImageProcessor) Process(_ context.Context, file *storage.ProcessingFile) error {
+	if !p.enabled() {
+		return nil
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(file.Data))
+	if err != nil || !isProcessedFormat(format) {
+		// Not an image, or one of a format that is left untouched
+		return nil
+	}
+
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return fmt.Errorf("%w: %dx%d pixels", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(file.Data))
+	if err != nil {
+		return fmt.Errorf("failed to decode %s image: %w", format, err)
+	}
+
+	img := toRGBA(src)
+	if format == imageFormatJPEG {
+		// Re-encoding drops the EXIF orientation, so it is applied to the pixels instead
+		img = orient(img, jpegOrientation(file.Data))
+	}
+
+	if format != imageFormatGIF {
+		width, height := fitSize(img.Bounds().Dx(), img.Bounds().Dy(), p.cfg.MaxWidth, p.cfg.MaxHeight)
+		resized := width != img.Bounds().Dx() || height != img.Bounds().Dy()
+
+		if resized {
+			img = resize(img, width, height)
+		}
+
+		if resized || format == imageFormatJPEG && p.cfg.StripEXIF {
+			data, err := encodeImage(img, format)
+			if err != nil {
+				return err
+			}
+
+			file.Data = data
+		}
+	}
+
+	if p.cfg.ThumbnailWidth > 0 || p.cfg.ThumbnailHeight > 0 {
+		width, height := fitSize(img.Bounds().Dx(), img.Bounds().Dy(), p.cfg.ThumbnailWidth, p.cfg.ThumbnailHeight)
+
+		data, err := encodeImage(resize(img, width, height), format)
+		if err != nil {
+			return err
+		}
+
+		file.Variants = append(file.Variants, storage.FileVariant{
+			Suffix:      ThumbnailSuffix,
+			ContentType: "image/" + format,
+			Data:        data,
+		})
+	}
+
+	return nil
+}
+
+func (p *ImageProcessor) enabled() bool {
+	return p.cfg.MaxWidth > 0 || p.cfg.MaxHeight > 0 ||
+		p.cfg.ThumbnailWidth > 0 || p.cfg.ThumbnailHeight > 0 ||
+		p.cfg.StripEXIF
+}
+
+func isProcessedFormat(format string) bool {
+	return format == imageFormatJPEG || format == imageFormatPNG || format == imageFormatGIF
+}
+
+func encodeImage(img image.Image, format string) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		err error
+	)
+
+	switch format {
+	case imageFormatJPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	case imageFormatGIF:
+		err = gif.Encode(&buf, img, nil)
+	default:
+		err = png.Encode(&buf, img)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s image: %w", format, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// fitSize scales width and height down to fit within the bounds keeping the aspect ratio; zero bounds are unlimited.
+func fitSize(width, height, maxWidth, maxHeight int) (int, int) {
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		scale = float64(maxWidth) / float64(width)
+	}
+
+	if maxHeight > 0 && height > maxHeight {
+		scale = min(scale, float64(maxHeight)/float64(height))
+	}
+
+	if scale == 1 {
+		return width, height
+	}
+
+	return max(1, int(float64(width)*scale+0.5)), max(1, int(float64(height)*scale+0.5))
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	if img, ok := src.(*image.RGBA); ok && img.Bounds().Min == (image.Point{}) {
+		return img
+	}
+
+	bounds := src.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(img, img.Bounds(), src, bounds.Min, draw.Src)
+
+	return img
+}
+
+// resize scales the image down with a box filter, averaging the source pixels covered by each target pixel.
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := range height {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+
+		for x := range width {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var sum [4]int
+
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					pixel := row[sx*4 : sx*4+4]
+					sum[0] += int(pixel[0])
+					sum[1] += int(pixel[1])
+					sum[2] += int(pixel[2])
+					sum[3] += int(pixel[3])
+				}
+			}
+
+			count := (y1 - y0) * (x1 - x0)
+			offset := y*dst.Stride + x*4
+
+			for i := range sum {
+				dst.Pix[offset+i] = uint8(sum[i] / count)
+			}
+		}
+	}
+
+	return dst
+}
+
+// orient transforms the image to display upright according to its EXIF orientation (1-8).
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := range dstHeight {
+		for x := range dstWidth {
+			var sx, sy int
+
+			switch orientation {
+			case 2: // Mirrored horizontally
+				sx, sy = width-1-x, y
+			case 3: // Rotated 180°
+				sx, sy = width-1-x, height-1-y
+			case 4: // Mirrored vertically
+				sx, sy = x, height-1-y
+			case 5: // Transposed
+				sx, sy = y, x
+			case 6: // Rotated 90° clockwise to display
+				sx, sy = y, height-1-x
+			case 7: // Transversed
+				sx, sy = width-1-y, height-1-x
+			case 8: // Rotated 90° counterclockwise to display
+				sx, sy = width-1-y, x
+			}
+
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], src.Pix[sy*src.Stride+sx*4:sy*src.Stride+sx*4+4])
+		}
+	}
+
+	return dst
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG image, or 1 when it has none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for pos := 2; pos+4 <= len(data) && data[pos] == 0xFF; {
+		marker := data[pos+1]
+		if marker == 0xDA {
+			// Start of scan, no metadata segments follow
+			break
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			break
+		}
+
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+
+		pos += 2 + length
+	}
+
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the first IFD of the TIFF structure of an EXIF segment.
+func exifOrientation(tiff []byte) int {
+	const orientationTag = 0x0112
+
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := range count {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+
+		if order.Uint16(tiff[entry:]) == orientationTag {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+
+	return 1
+}
@@ -0,0 +1,245 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+// withOrientation inserts an APP1 EXIF segment holding the orientation after the SOI marker of a JPEG image.
+func withOrientation(data []byte, orientation uint16, order binary.ByteOrder) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	header := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(header[2:], uint16(len(segment)+2))
+
+	result := append([]byte{}, data[:2]...)
+	result = append(result, header...)
+	result = append(result, segment...)
+
+	return append(result, data[2:]...)
+}
+
+func encodeTestImage(t *testing.T, width, height int, format string) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	// Mark the top-left quarter to verify orientation
+	for y := range height {
+		for x := range width {
+			if x < width/2 && y < height/2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if format == imageFormatPNG {
+		require.NoError(t, png.Encode(&buf, img))
+	} else {
+		require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}))
+	}
+
+	return buf.Bytes()
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	data := encodeTestImage(t, 4, 2, imageFormatJPEG)
+
+	assert.Equal(t, 1, jpegOrientation(data), "Images without EXIF should be upright")
+	assert.Equal(t, 6, jpegOrientation(withOrientation(data, 6, binary.BigEndian)))
+	assert.Equal(t, 8, jpegOrientation(withOrientation(data, 8, binary.LittleEndian)))
+	assert.Equal(t, 1, jpegOrientation([]byte("not a jpeg")))
+	assert.Equal(t, 1, jpegOrientation(withOrientation(data, 6, binary.BigEndian)[:20]), "Truncated data should be ignored")
+}
+
+func TestOrient(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	src.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	tests := []struct {
+		orientation   int
+		width, height int
+		x, y          int
+	}{
+		{1, 3, 2, 0, 0},
+		{2, 3, 2, 2, 0},
+		{3, 3, 2, 2, 1},
+		{4, 3, 2, 0, 1},
+		{5, 2, 3, 0, 0},
+		{6, 2, 3, 1, 0},
+		{7, 2, 3, 1, 2},
+		{8, 2, 3, 0, 2},
+	}
+
+	for _, tt := range tests {
+		dst := orient(src, tt.orientation)
+
+		assert.Equal(t, tt.width, dst.Bounds().Dx(), "Width of orientation %d", tt.orientation)
+		assert.Equal(t, tt.height, dst.Bounds().Dy(), "Height of orientation %d", tt.orientation)
+		assert.Equal(t, color.RGBA{R: 255, A: 255}, dst.RGBAAt(tt.x, tt.y), "Marked pixel of orientation %d", tt.orientation)
+	}
+}
+
+func TestFitSize(t *testing.T) {
+	tests := []struct {
+		name                  string
+		width, height         int
+		maxWidth, maxHeight   int
+		wantWidth, wantHeight int
+	}{
+		{"WithinBounds", 100, 50, 200, 200, 100, 50},
+		{"Unlimited", 100, 50, 0, 0, 100, 50},
+		{"WidthBound", 400, 200, 100, 0, 100, 50},
+		{"HeightBound", 400, 200, 0, 100, 200, 100},
+		{"BothBounds", 400, 200, 100, 100, 100, 50},
+		{"NeverZero", 1000, 1, 10, 0, 10, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := fitSize(tt.width, tt.height, tt.maxWidth, tt.maxHeight)
+
+			assert.Equal(t, tt.wantWidth, width)
+			assert.Equal(t, tt.wantHeight, height)
+		})
+	}
+}
+
+func TestImageProcessor(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("StripEXIFAppliesOrientation", func(t *testing.T) {
+		processor := NewImageProcessor(config.ImageConfig{StripEXIF: true})
+		file := &storage.ProcessingFile{
+			Key:  "temp/a.jpg",
+			Data: withOrientation(encodeTestImage(t, 40, 20, imageFormatJPEG), 6, binary.BigEndian),
+		}
+
+		require.NoError(t, processor.Process(ctx, file))
+
+		assert.Equal(t, 1, jpegOrientation(file.Data), "EXIF should be stripped")
+
+		img, _, err := image.Decode(bytes.NewReader(file.Data))
+		require.NoError(t, err)
+		assert.Equal(t, 20, img.Bounds().Dx())
+		assert.Equal(t, 40, img.Bounds().Dy())
+
+		r, _, b, _ := img.At(15, 5).RGBA()
+		assert.Greater(t, r, b, "Top-left quarter should be rotated to the top-right")
+	})
+
+	t.Run("PNGResizedWithThumbnail", func(t *testing.T) {
+		processor := NewImageProcessor(config.ImageConfig{MaxHeight: 50, ThumbnailWidth: 10, ThumbnailHeight: 10})
+		file := &storage.ProcessingFile{
+			Key:  "temp/a.png",
+			Data: encodeTestImage(t, 200, 100, imageFormatPNG),
+		}
+
+		require.NoError(t, processor.Process(ctx, file))
+
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(file.Data))
+		require.NoError(t, err)
+		assert.Equal(t, imageFormatPNG, format)
+		assert.Equal(t, 100, cfg.Width)
+		assert.Equal(t, 50, cfg.Height)
+
+		require.Len(t, file.Variants, 1)
+		assert.Equal(t, ThumbnailSuffix, file.Variants[0].Suffix)
+		assert.Equal(t, "image/png", file.Variants[0].ContentType)
+
+		thumb, _, err := image.DecodeConfig(bytes.NewReader(file.Variants[0].Data))
+		require.NoError(t, err)
+		assert.Equal(t, 10, thumb.Width)
+		assert.Equal(t, 5, thumb.Height)
+	})
+
+	t.Run("UnchangedWithinBounds", func(t *testing.T) {
+		processor := NewImageProcessor(config.ImageConfig{MaxWidth: 500})
+		data := encodeTestImage(t, 100, 100, imageFormatPNG)
+		file := &storage.ProcessingFile{Key: "temp/a.png", Data: data}
+
+		require.NoError(t, processor.Process(ctx, file))
+
+		assert.Equal(t, data, file.Data, "Images within bounds should not be re-encoded")
+		assert.Empty(t, file.Variants)
+	})
+
+	t.Run("NonImage", func(t *testing.T) {
+		processor := NewImageProcessor(config.ImageConfig{MaxWidth: 10, ThumbnailWidth: 10, StripEXIF: true})
+		file := &storage.ProcessingFile{Key: "temp/a.txt", Data: []byte("plain text")}
+
+		require.NoError(t, processor.Process(ctx, file))
+
+		assert.Equal(t, []byte("plain text"), file.Data)
+		assert.Empty(t, file.Variants)
+	})
+}
+
+func TestContentTypeProcessor(t *testing.T) {
+	pngData := encodeTestImage(t, 2, 2, imageFormatPNG)
+	jpegData := encodeTestImage(t, 2, 2, imageFormatJPEG)
+
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+		want        string
+		rejected    bool
+	}{
+		{"MissingType", "", pngData, "image/png", false},
+		{"GenericType", "application/octet-stream", []byte("hello"), "text/plain", false},
+		{"MatchingType", "image/png", pngData, "image/png", false},
+		{"OtherImageFormat", "image/png", jpegData, "image/jpeg", false},
+		{"TextSubtype", "text/csv", []byte("a,b\n1,2\n"), "text/csv", false},
+		{"UnsniffableDocument", "application/pdf", []byte{0x00, 0x01, 0x02}, "application/pdf", false},
+		{"SVGImage", "image/svg+xml", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), "image/svg+xml", false},
+		{"TextAsImage", "image/png", []byte("just some text"), "", true},
+		{"HTMLAsText", "text/plain", []byte("<!DOCTYPE html><html></html>"), "", true},
+	}
+
+	processor := NewContentTypeProcessor()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &storage.ProcessingFile{ContentType: tt.contentType, Data: tt.data}
+
+			err := processor.Process(context.Background(), file)
+			if tt.rejected {
+				assert.ErrorIs(t, err, storage.ErrFileRejected)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, file.ContentType)
+		})
+	}
+}
@@ -15,8 +15,11 @@ import (
 	"github.com/ilxqx/vef-framework-go/storage"
 )
 
+// ProxyMiddleware serves objects through the framework; uploads are only served once processed when pipeline is not nil.
 type ProxyMiddleware struct {
-	service storage.Service
+	service  storage.Service
+	signer   *URLSigner
+	pipeline *Pipeline
 }
 
 func (*ProxyMiddleware) Name() string {
@@ -29,6 +32,7 @@ func (*ProxyMiddleware) Order() int {
 
 func (p *ProxyMiddleware) Apply(router fiber.Router) {
 	router.Get("/storage/files/+", p.handleFileProxy)
+	router.Get(storage.SignedURLPrefix+"+", p.handleSignedFile)
 }
 
 func (p *ProxyMiddleware) handleFileProxy(ctx fiber.Ctx) error {
@@ -63,6 +67,14 @@ func (p *ProxyMiddleware) handleFileProxy(ctx fiber.Ctx) error {
 		logger.Warnf("Failed to stat object %s: %v", key, err)
 	}
 
+	if p.pipeline != nil && (stat == nil || checkProcessed(stat) != nil) {
+		if closeErr := reader.Close(); closeErr != nil {
+			logger.Errorf("failed to close object %s: %v", key, closeErr)
+		}
+
+		return fileNotProcessedError()
+	}
+
 	contentType := detectContentType(stat, key)
 	ctx.Set(fiber.HeaderContentType, contentType)
 
@@ -75,9 +87,84 @@ func (p *ProxyMiddleware) handleFileProxy(ctx fiber.Ctx) error {
 	return ctx.SendStream(reader)
 }
 
-func NewProxyMiddleware(service storage.Service) app.Middleware {
+// handleSignedFile serves objects through URLs signed by URLSigner, which are neither cached nor shared.
+func (p *ProxyMiddleware) handleSignedFile(ctx fiber.Ctx) error {
+	key, err := url.PathUnescape(ctx.Params("+"))
+	if err != nil {
+		return result.Err(
+			i18n.T(result.ErrMessageInvalidFileKey),
+			result.WithCode(result.ErrCodeInvalidFileKey),
+		)
+	}
+
+	query, err := url.ParseQuery(string(ctx.Request().URI().QueryString()))
+	if err != nil {
+		return signedFileError(key, storage.ErrInvalidSignature)
+	}
+
+	object, err := p.signer.Open(ctx.Context(), key, query)
+	if err != nil {
+		return signedFileError(key, err)
+	}
+
+	ctx.Set(fiber.HeaderContentType, detectContentType(object.Info, key))
+	ctx.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	if object.Filename != constants.Empty {
+		ctx.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": object.Filename}))
+	}
+
+	// The object is closed once its stream is sent
+	return ctx.SendStream(object)
+}
+
+func signedFileError(key string, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrInvalidSignature):
+		return result.Err(
+			i18n.T(result.ErrMessageFileURLInvalid),
+			result.WithCode(result.ErrCodeFileURLInvalid),
+			result.WithStatus(fiber.StatusForbidden),
+		)
+	case errors.Is(err, storage.ErrURLExpired):
+		return result.Err(
+			i18n.T(result.ErrMessageFileURLExpired),
+			result.WithCode(result.ErrCodeFileURLExpired),
+			result.WithStatus(fiber.StatusForbidden),
+		)
+	case errors.Is(err, storage.ErrDownloadLimitReached):
+		return result.Err(
+			i18n.T(result.ErrMessageDownloadLimitReached),
+			result.WithCode(result.ErrCodeDownloadLimitReached),
+			result.WithStatus(fiber.StatusGone),
+		)
+	case errors.Is(err, storage.ErrObjectNotFound):
+		return result.Err(
+			i18n.T(result.ErrMessageFileNotFound),
+			result.WithCode(result.ErrCodeFileNotFound),
+		)
+	case errors.Is(err, storage.ErrFileNotProcessed):
+		return fileNotProcessedError()
+	default:
+		logger.Errorf("Failed to get signed object %s: %v", key, err)
+
+		return result.Err(i18n.T(result.ErrMessageFailedToGetFile))
+	}
+}
+
+func fileNotProcessedError() error {
+	return result.Err(
+		i18n.T(result.ErrMessageFileNotProcessed),
+		result.WithCode(result.ErrCodeFileNotProcessed),
+		result.WithStatus(fiber.StatusForbidden),
+	)
+}
+
+func NewProxyMiddleware(service storage.Service, signer *URLSigner, pipeline *Pipeline) app.Middleware {
 	return &ProxyMiddleware{
-		service: service,
+		service:  service,
+		signer:   signer,
+		pipeline: pipeline,
 	}
 }
 
@@ -101,7 +101,7 @@ func TestProxyMiddleware(t *testing.T) {
 		}, nil)
 
 		app := createApp()
-		middleware := NewProxyMiddleware(mockService)
+		middleware := NewProxyMiddleware(mockService, nil, nil)
 		middleware.Apply(app)
 
 		req := httptest.NewRequest(http.MethodGet, "/storage/files/temp/2025/01/15/test.jpg", nil)
@@ -126,7 +126,7 @@ func TestProxyMiddleware(t *testing.T) {
 		}).Return(nil, storage.ErrObjectNotFound)
 
 		app := createApp()
-		middleware := NewProxyMiddleware(mockService)
+		middleware := NewProxyMiddleware(mockService, nil, nil)
 		middleware.Apply(app)
 
 		req := httptest.NewRequest(http.MethodGet, "/storage/files/nonexistent.jpg", nil)
@@ -140,7 +140,7 @@ func TestProxyMiddleware(t *testing.T) {
 
 	t.Run("EmptyFileKey", func(t *testing.T) {
 		app := createApp()
-		middleware := NewProxyMiddleware(nil)
+		middleware := NewProxyMiddleware(nil, nil, nil)
 		middleware.Apply(app)
 
 		req := httptest.NewRequest(http.MethodGet, "/storage/files/", nil)
@@ -166,7 +166,7 @@ func TestProxyMiddleware(t *testing.T) {
 		}, nil)
 
 		app := createApp()
-		middleware := NewProxyMiddleware(mockService)
+		middleware := NewProxyMiddleware(mockService, nil, nil)
 		middleware.Apply(app)
 
 		// URL encode the Chinese characters
@@ -188,7 +188,7 @@ func TestProxyMiddleware(t *testing.T) {
 		}).Return(nil, errors.New("storage error"))
 
 		app := createApp()
-		middleware := NewProxyMiddleware(mockService)
+		middleware := NewProxyMiddleware(mockService, nil, nil)
 		middleware.Apply(app)
 
 		req := httptest.NewRequest(http.MethodGet, "/storage/files/error.jpg", nil)
@@ -214,7 +214,7 @@ func TestProxyMiddleware(t *testing.T) {
 		}).Return(nil, errors.New("stat failed"))
 
 		app := createApp()
-		middleware := NewProxyMiddleware(mockService)
+		middleware := NewProxyMiddleware(mockService, nil, nil)
 		middleware.Apply(app)
 
 		req := httptest.NewRequest(http.MethodGet, "/storage/files/test.png", nil)
@@ -244,7 +244,7 @@ func TestProxyMiddleware(t *testing.T) {
 		}, nil)
 
 		app := createApp()
-		middleware := NewProxyMiddleware(mockService)
+		middleware := NewProxyMiddleware(mockService, nil, nil)
 		middleware.Apply(app)
 
 		req := httptest.NewRequest(http.MethodGet, "/storage/files/document.pdf", nil)
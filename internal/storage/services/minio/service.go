@@ -218,6 +218,39 @@ func (s *Service) CopyObject(ctx context.Context, opts storage.CopyObjectOptions
 	}, nil
 }
 
+// ComposeObject merges the sources with minio ComposeObject, which copies them as the parts of a multipart upload
+// completed within the backend.
+func (s *Service) ComposeObject(ctx context.Context, opts storage.ComposeObjectOptions) (*storage.ObjectInfo, error) {
+	srcs := make([]minio.CopySrcOptions, len(opts.SourceKeys))
+	for i, key := range opts.SourceKeys {
+		srcs[i] = minio.CopySrcOptions{
+			Bucket: s.bucket,
+			Object: key,
+		}
+	}
+
+	dst := minio.CopyDestOptions{
+		Bucket:          s.bucket,
+		Object:          opts.DestKey,
+		UserMetadata:    opts.Metadata,
+		ReplaceMetadata: true,
+		ContentType:     opts.ContentType,
+	}
+
+	info, err := s.client.ComposeObject(ctx, dst, srcs...)
+	if err != nil {
+		return nil, s.translateError(err)
+	}
+
+	return &storage.ObjectInfo{
+		Bucket:       info.Bucket,
+		Key:          info.Key,
+		ETag:         info.ETag,
+		Size:         info.Size,
+		LastModified: info.LastModified,
+	}, nil
+}
+
 func (s *Service) MoveObject(ctx context.Context, opts storage.MoveObjectOptions) (info *storage.ObjectInfo, err error) {
 	if info, err = s.CopyObject(ctx, opts.CopyObjectOptions); err != nil {
 		return info, err
@@ -350,6 +350,51 @@ func (suite *MinIOServiceTestSuite) TestCopyObject() {
 	})
 }
 
+func (suite *MinIOServiceTestSuite) TestComposeObject() {
+	suite.T().Logf("Testing ComposeObject for MinIO service")
+
+	composer, ok := suite.service.(storage.ObjectComposer)
+	suite.Require().True(ok, "MinIO service should compose objects")
+
+	suite.Run("Success", func() {
+		first := bytes.Repeat([]byte("a"), storage.MinComposePartSize)
+		suite.uploadObject("compose/part1", first)
+		suite.uploadObject("compose/part2", []byte("tail"))
+
+		info, err := composer.ComposeObject(suite.ctx, storage.ComposeObjectOptions{
+			SourceKeys:  []string{"compose/part1", "compose/part2"},
+			DestKey:     "composed.txt",
+			ContentType: "text/plain",
+			Metadata:    map[string]string{"Purpose": "test"},
+		})
+		suite.Require().NoError(err, "ComposeObject should succeed")
+		suite.Equal("composed.txt", info.Key, "Destination key should match")
+
+		reader, err := suite.service.GetObject(suite.ctx, storage.GetObjectOptions{Key: "composed.txt"})
+		suite.Require().NoError(err, "Should be able to get composed object")
+
+		defer reader.Close()
+
+		data, err := io.ReadAll(reader)
+		suite.Require().NoError(err, "Reading composed data should succeed")
+		suite.Equal(append(first, "tail"...), data, "Composed data should be the sources in order")
+
+		stat, err := suite.service.StatObject(suite.ctx, storage.StatObjectOptions{Key: "composed.txt"})
+		suite.Require().NoError(err)
+		suite.Equal("text/plain", stat.ContentType, "Content type should be set")
+		suite.Equal("test", stat.Metadata["Purpose"], "Metadata should be set")
+	})
+
+	suite.Run("NotFound", func() {
+		_, err := composer.ComposeObject(suite.ctx, storage.ComposeObjectOptions{
+			SourceKeys: []string{"non-existent.txt"},
+			DestKey:    "destination.txt",
+		})
+
+		suite.ErrorIs(err, storage.ErrObjectNotFound, "Error should be ErrObjectNotFound")
+	})
+}
+
 func (suite *MinIOServiceTestSuite) TestMoveObject() {
 	suite.T().Logf("Testing MoveObject for MinIO service")
 
@@ -22,19 +22,32 @@ import (
 const (
 	templateDatePath = "2006/01/02"
 	defaultExtension = ".bin"
+
+	// completeMultipartTimeout bounds merging the parts of large uploads.
+	completeMultipartTimeout = 10 * time.Minute
 )
 
-func NewResource(service storage.Service) api.Resource {
+// NewResource creates the storage resource; uploads are queued for post-processing when pipeline is not nil.
+func NewResource(service storage.Service, pipeline *Pipeline, signer storage.URLSigner) api.Resource {
 	return &Resource{
-		service: service,
+		service:  service,
+		uploader: NewMultipartUploader(service),
+		pipeline: pipeline,
+		signer:   signer,
 		Resource: api.NewRPCResource(
 			"sys/storage",
 			api.WithOperations(
 				api.OperationSpec{Action: "upload", Public: isStorageApiPublic},
 				api.OperationSpec{Action: "get_presigned_url", Public: isStorageApiPublic},
+				api.OperationSpec{Action: "sign_url", Public: isStorageApiPublic},
 				api.OperationSpec{Action: "delete_temp", Public: isStorageApiPublic},
 				api.OperationSpec{Action: "stat", Public: isStorageApiPublic},
 				api.OperationSpec{Action: "list", Public: isStorageApiPublic},
+				api.OperationSpec{Action: "init_multipart_upload", Public: isStorageApiPublic},
+				api.OperationSpec{Action: "upload_part", Public: isStorageApiPublic},
+				api.OperationSpec{Action: "list_parts", Public: isStorageApiPublic},
+				api.OperationSpec{Action: "complete_multipart_upload", Public: isStorageApiPublic, Timeout: completeMultipartTimeout},
+				api.OperationSpec{Action: "abort_multipart_upload", Public: isStorageApiPublic},
 			),
 		),
 	}
@@ -43,7 +56,10 @@ func NewResource(service storage.Service) api.Resource {
 type Resource struct {
 	api.Resource
 
-	service storage.Service
+	service  storage.Service
+	uploader *MultipartUploader
+	pipeline *Pipeline
+	signer   storage.URLSigner
 }
 
 type UploadParams struct {
@@ -56,6 +72,7 @@ type UploadParams struct {
 }
 
 // Upload generates date-partitioned keys (temp/YYYY/MM/DD/{uuid}{ext}) to organize uploads and avoid conflicts.
+// With post-processing enabled the upload is returned with the pending status, stat reports the final status.
 func (r *Resource) Upload(ctx fiber.Ctx, params UploadParams) error {
 	if webhelpers.IsJSON(ctx) {
 		return result.Err(i18n.T("upload_requires_multipart"))
@@ -89,6 +106,7 @@ func (r *Resource) Upload(ctx fiber.Ctx, params UploadParams) error {
 	}
 
 	metadata[storage.MetadataKeyOriginalFilename] = params.File.Filename
+	r.markPending(metadata)
 
 	info, err := r.service.PutObject(ctx.Context(), storage.PutObjectOptions{
 		Key:         key,
@@ -101,9 +119,29 @@ func (r *Resource) Upload(ctx fiber.Ctx, params UploadParams) error {
 		return err
 	}
 
+	if err := r.submit(ctx, info); err != nil {
+		return err
+	}
+
 	return result.Ok(info).Response(ctx)
 }
 
+// markPending records the pending processing status in the metadata of an upload when post-processing is enabled.
+func (r *Resource) markPending(metadata map[string]string) {
+	if r.pipeline != nil {
+		metadata[storage.MetadataKeyProcessingStatus] = string(storage.ProcessingPending)
+	}
+}
+
+// submit queues a stored upload for post-processing when it is enabled.
+func (r *Resource) submit(ctx fiber.Ctx, info *storage.ObjectInfo) error {
+	if r.pipeline == nil {
+		return nil
+	}
+
+	return r.pipeline.Submit(ctx.Context(), info)
+}
+
 func (*Resource) generateObjectKey(filename string) string {
 	datePath := time.Now().Format(templateDatePath)
 	uuid := id.GenerateUUID()
@@ -135,6 +173,22 @@ func (r *Resource) GetPresignedURL(ctx fiber.Ctx, params GetPresignedURLParams)
 		method = http.MethodGet
 	}
 
+	// Presigned downloads bypass the proxy, so unprocessed uploads are refused here
+	if r.pipeline != nil && method == http.MethodGet {
+		info, err := r.service.StatObject(ctx.Context(), storage.StatObjectOptions{Key: params.Key})
+		if err != nil {
+			if errors.Is(err, storage.ErrObjectNotFound) {
+				return result.Err(i18n.T("object_not_found"))
+			}
+
+			return err
+		}
+
+		if err := checkProcessed(info); err != nil {
+			return fileNotProcessedError()
+		}
+	}
+
 	url, err := r.service.GetPresignedURL(ctx.Context(), storage.PresignedURLOptions{
 		Key:     params.Key,
 		Expires: time.Duration(expires) * time.Second,
@@ -147,6 +201,40 @@ func (r *Resource) GetPresignedURL(ctx fiber.Ctx, params GetPresignedURLParams)
 	return result.Ok(fiber.Map{"url": url}).Response(ctx)
 }
 
+type SignURLParams struct {
+	api.P
+
+	Key          string `json:"key" validate:"required"`
+	Expires      int    `json:"expires" validate:"min=0"`
+	MaxDownloads int    `json:"maxDownloads" validate:"min=0"`
+	Watermark    string `json:"watermark"`
+	Filename     string `json:"filename"`
+}
+
+// SignURL returns a signed framework URL of a private object, optionally limited in downloads and watermarked.
+// Unlike GetPresignedURL it works with every storage provider since the framework serves the object itself.
+func (r *Resource) SignURL(ctx fiber.Ctx, params SignURLParams) error {
+	url, err := r.signer.Sign(ctx.Context(), storage.SignURLOptions{
+		Key:          params.Key,
+		Expires:      time.Duration(params.Expires) * time.Second,
+		MaxDownloads: params.MaxDownloads,
+		Watermark:    params.Watermark,
+		Filename:     params.Filename,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrObjectNotFound):
+			return result.Err(i18n.T("object_not_found"))
+		case errors.Is(err, storage.ErrWatermarkUnsupported):
+			return result.Err(i18n.T("watermark_unsupported"))
+		default:
+			return err
+		}
+	}
+
+	return result.Ok(fiber.Map{"url": url}).Response(ctx)
+}
+
 type DeleteTempParams struct {
 	api.P
 
@@ -281,3 +369,156 @@ func (r *Resource) Stat(ctx fiber.Ctx, params StatParams) error {
 
 	return result.Ok(info).Response(ctx)
 }
+
+type InitMultipartUploadParams struct {
+	api.P
+
+	Filename    string            `json:"filename" validate:"required"`
+	ContentType string            `json:"contentType"`
+	Size        int64             `json:"size" validate:"min=0"`
+	PartSize    int64             `json:"partSize" validate:"min=0"`
+	MD5         string            `json:"md5"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+// InitMultipartUpload starts a chunked upload into a key generated like Upload does.
+// The returned uploadId is used to upload, list and complete the parts, also to resume the upload later.
+func (r *Resource) InitMultipartUpload(ctx fiber.Ctx, params InitMultipartUploadParams) error {
+	metadata := params.Metadata
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+
+	r.markPending(metadata)
+
+	upload, err := r.uploader.Init(ctx.Context(), InitMultipartOptions{
+		Key:         r.generateObjectKey(params.Filename),
+		Filename:    params.Filename,
+		ContentType: params.ContentType,
+		Size:        params.Size,
+		PartSize:    params.PartSize,
+		MD5:         params.MD5,
+		Metadata:    metadata,
+	})
+	if err != nil {
+		return multipartError(err)
+	}
+
+	return result.Ok(upload).Response(ctx)
+}
+
+type UploadPartParams struct {
+	api.P
+
+	File *multipart.FileHeader
+
+	UploadID   string `json:"uploadId" validate:"required"`
+	PartNumber int    `json:"partNumber" validate:"required,min=1"`
+	MD5        string `json:"md5"`
+}
+
+// UploadPart stores a part of a chunked upload; uploading a part number again replaces the part.
+// The returned eTag is the MD5 checksum of the part and must be passed when completing the upload.
+func (r *Resource) UploadPart(ctx fiber.Ctx, params UploadPartParams) error {
+	if webhelpers.IsJSON(ctx) {
+		return result.Err(i18n.T("upload_requires_multipart"))
+	}
+
+	if params.File == nil {
+		return result.Err(i18n.T("upload_requires_file"))
+	}
+
+	file, err := params.File.Open()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			logger.Errorf("failed to close file: %v", closeErr)
+		}
+	}()
+
+	part, err := r.uploader.UploadPart(ctx.Context(), UploadPartOptions{
+		UploadID:   params.UploadID,
+		PartNumber: params.PartNumber,
+		Reader:     file,
+		Size:       params.File.Size,
+		MD5:        params.MD5,
+	})
+	if err != nil {
+		return multipartError(err)
+	}
+
+	return result.Ok(part).Response(ctx)
+}
+
+type ListPartsParams struct {
+	api.P
+
+	UploadID string `json:"uploadId" validate:"required"`
+}
+
+// ListParts returns the upload and its stored parts so that an interrupted upload can be resumed.
+func (r *Resource) ListParts(ctx fiber.Ctx, params ListPartsParams) error {
+	upload, err := r.uploader.Get(ctx.Context(), params.UploadID)
+	if err != nil {
+		return multipartError(err)
+	}
+
+	parts, err := r.uploader.ListParts(ctx.Context(), params.UploadID)
+	if err != nil {
+		return err
+	}
+
+	return result.Ok(fiber.Map{"upload": upload, "parts": parts}).Response(ctx)
+}
+
+type CompleteMultipartUploadParams struct {
+	api.P
+
+	UploadID string                  `json:"uploadId" validate:"required"`
+	Parts    []storage.CompletedPart `json:"parts" validate:"required,min=1,dive"`
+}
+
+// CompleteMultipartUpload merges the parts into the upload key and returns the merged object like Upload does.
+func (r *Resource) CompleteMultipartUpload(ctx fiber.Ctx, params CompleteMultipartUploadParams) error {
+	info, err := r.uploader.Complete(ctx.Context(), params.UploadID, params.Parts)
+	if err != nil {
+		return multipartError(err)
+	}
+
+	if err := r.submit(ctx, info); err != nil {
+		return err
+	}
+
+	return result.Ok(info).Response(ctx)
+}
+
+type AbortMultipartUploadParams struct {
+	api.P
+
+	UploadID string `json:"uploadId" validate:"required"`
+}
+
+func (r *Resource) AbortMultipartUpload(ctx fiber.Ctx, params AbortMultipartUploadParams) error {
+	if err := r.uploader.Abort(ctx.Context(), params.UploadID); err != nil {
+		return multipartError(err)
+	}
+
+	return result.Ok().Response(ctx)
+}
+
+// multipartError translates the client errors of chunked uploads into results.
+func multipartError(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrUploadNotFound):
+		return result.Err(i18n.T("multipart_upload_not_found"))
+	case errors.Is(err, storage.ErrInvalidPart):
+		return result.Err(i18n.T("multipart_invalid_part", map[string]any{"error": err.Error()}))
+	case errors.Is(err, storage.ErrChecksumMismatch):
+		return result.Err(i18n.T("multipart_checksum_mismatch", map[string]any{"error": err.Error()}))
+	default:
+		return err
+	}
+}
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/id"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+const (
+	defaultSignedURLExpires    = time.Hour
+	defaultMaxSignedURLExpires = 7 * 24 * time.Hour
+
+	queryExpires   = "expires"
+	queryLimit     = "limit"
+	queryWatermark = "watermark"
+	queryFilename  = "filename"
+	queryNonce     = "nonce"
+	querySignature = "signature"
+)
+
+// URLSigner signs object URLs with HMAC-SHA256 and opens the objects of verified URLs.
+// Signed URLs carry their expiry, download limit, watermark and download filename, all covered by the signature;
+// URLs with a download limit also carry a nonce so that every signed URL is counted on its own.
+// While upload processing is enabled only uploads whose processing completed are opened.
+type URLSigner struct {
+	service        storage.Service
+	processing     bool
+	counter        storage.DownloadCounter
+	watermarkers   []storage.Watermarker
+	secret         []byte
+	defaultExpires time.Duration
+	maxExpires     time.Duration
+	now            func() time.Time
+}
+
+// NewURLSigner creates a URL signer; without a configured secret a random one is used.
+func NewURLSigner(
+	cfg *config.StorageConfig,
+	service storage.Service,
+	counter storage.DownloadCounter,
+	watermarkers []storage.Watermarker,
+) (*URLSigner, error) {
+	sc := cfg.Signing
+
+	secret := []byte(sc.Secret)
+	if len(secret) == 0 {
+		secret = make([]byte, sha256.Size)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate url signing secret: %w", err)
+		}
+
+		logger.Warn("No url signing secret configured, signed urls are only valid on this instance until it restarts")
+	}
+
+	signer := &URLSigner{
+		service:        service,
+		processing:     cfg.Processing.Enabled,
+		counter:        counter,
+		watermarkers:   watermarkers,
+		secret:         secret,
+		defaultExpires: sc.DefaultExpires,
+		maxExpires:     sc.MaxExpires,
+		now:            time.Now,
+	}
+
+	if signer.defaultExpires <= 0 {
+		signer.defaultExpires = defaultSignedURLExpires
+	}
+
+	if signer.maxExpires <= 0 {
+		signer.maxExpires = defaultMaxSignedURLExpires
+	}
+
+	return signer, nil
+}
+
+// Sign returns the signed path of the object; lifetimes beyond the configured maximum are capped.
+// The object must exist, and be of a type a watermarker supports when a watermark is requested.
+func (s *URLSigner) Sign(ctx context.Context, opts storage.SignURLOptions) (string, error) {
+	if opts.Key == constants.Empty {
+		return constants.Empty, storage.ErrInvalidObjectKey
+	}
+
+	info, err := s.service.StatObject(ctx, storage.StatObjectOptions{Key: opts.Key})
+	if err != nil {
+		return constants.Empty, err
+	}
+
+	if opts.Watermark != constants.Empty && s.watermarker(info.ContentType) == nil {
+		return constants.Empty, fmt.Errorf("%w: %s", storage.ErrWatermarkUnsupported, info.ContentType)
+	}
+
+	expires := opts.Expires
+	if expires <= 0 {
+		expires = s.defaultExpires
+	}
+
+	expires = min(expires, s.maxExpires)
+
+	query := url.Values{}
+	query.Set(queryExpires, strconv.FormatInt(s.now().Add(expires).Unix(), 10))
+
+	if opts.MaxDownloads > 0 {
+		query.Set(queryLimit, strconv.Itoa(opts.MaxDownloads))
+		query.Set(queryNonce, id.GenerateUUID())
+	}
+
+	if opts.Watermark != constants.Empty {
+		query.Set(queryWatermark, opts.Watermark)
+	}
+
+	if opts.Filename != constants.Empty {
+		query.Set(queryFilename, opts.Filename)
+	}
+
+	query.Set(querySignature, s.signature(opts.Key, query))
+
+	return storage.SignedURLPrefix + escapeKey(opts.Key) + constants.QuestionMark + query.Encode(), nil
+}
+
+// SignedObject is an object opened through a verified signed URL.
+type SignedObject struct {
+	io.Reader
+
+	Info     *storage.ObjectInfo
+	Filename string
+
+	closer io.Closer
+}
+
+func (o *SignedObject) Close() error {
+	return o.closer.Close()
+}
+
+// Open verifies the signed URL of the object and opens it, counting the download and applying the watermark.
+func (s *URLSigner) Open(ctx context.Context, key string, query url.Values) (*SignedObject, error) {
+	signature, err := base64.RawURLEncoding.DecodeString(query.Get(querySignature))
+	if err != nil || !hmac.Equal(signature, s.mac(key, query)) {
+		return nil, storage.ErrInvalidSignature
+	}
+
+	expires, err := strconv.ParseInt(query.Get(queryExpires), 10, 64)
+	if err != nil {
+		return nil, storage.ErrInvalidSignature
+	}
+
+	expiresAt := time.Unix(expires, 0)
+	if !s.now().Before(expiresAt) {
+		return nil, storage.ErrURLExpired
+	}
+
+	info, err := s.service.StatObject(ctx, storage.StatObjectOptions{Key: key})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.processing {
+		if err := checkProcessed(info); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit := query.Get(queryLimit); limit != constants.Empty {
+		maxDownloads, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil {
+			return nil, storage.ErrInvalidSignature
+		}
+
+		count, err := s.counter.Increment(ctx, query.Get(queryNonce), expiresAt.Sub(s.now()))
+		if err != nil {
+			return nil, err
+		}
+
+		if count > maxDownloads {
+			return nil, storage.ErrDownloadLimitReached
+		}
+	}
+
+	reader, err := s.service.GetObject(ctx, storage.GetObjectOptions{Key: key})
+	if err != nil {
+		return nil, err
+	}
+
+	object := &SignedObject{
+		Reader:   reader,
+		Info:     info,
+		Filename: query.Get(queryFilename),
+		closer:   reader,
+	}
+
+	if text := query.Get(queryWatermark); text != constants.Empty {
+		watermarker := s.watermarker(info.ContentType)
+		if watermarker == nil {
+			_ = reader.Close()
+
+			return nil, fmt.Errorf("%w: %s", storage.ErrWatermarkUnsupported, info.ContentType)
+		}
+
+		if object.Reader, err = watermarker.Watermark(ctx, reader, info.ContentType, text); err != nil {
+			_ = reader.Close()
+
+			return nil, fmt.Errorf("failed to watermark object %s: %w", key, err)
+		}
+	}
+
+	return object, nil
+}
+
+func (s *URLSigner) watermarker(contentType string) storage.Watermarker {
+	for _, watermarker := range s.watermarkers {
+		if watermarker.Supports(contentType) {
+			return watermarker
+		}
+	}
+
+	return nil
+}
+
+func (s *URLSigner) signature(key string, query url.Values) string {
+	return base64.RawURLEncoding.EncodeToString(s.mac(key, query))
+}
+
+// mac authenticates the key and the signed query parameters, escaped so that no field can run into another.
+func (s *URLSigner) mac(key string, query url.Values) []byte {
+	fields := []string{key}
+	for _, name := range []string{queryExpires, queryLimit, queryNonce, queryWatermark, queryFilename} {
+		fields = append(fields, query.Get(name))
+	}
+
+	for i, field := range fields {
+		fields[i] = url.QueryEscape(field)
+	}
+
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(strings.Join(fields, "\n")))
+
+	return h.Sum(nil)
+}
+
+// escapeKey escapes the segments of an object key for use in a URL path.
+func escapeKey(key string) string {
+	segments := strings.Split(key, constants.Slash)
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, constants.Slash)
+}
@@ -0,0 +1,320 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/internal/storage/services/memory"
+	"github.com/ilxqx/vef-framework-go/result"
+	"github.com/ilxqx/vef-framework-go/storage"
+)
+
+// upperWatermarker "watermarks" text documents by appending the text in upper case.
+type upperWatermarker struct{}
+
+func (upperWatermarker) Supports(contentType string) bool {
+	return strings.HasPrefix(contentType, "text/")
+}
+
+func (upperWatermarker) Watermark(_ context.Context, content io.Reader, _, text string) (io.Reader, error) {
+	return io.MultiReader(content, strings.NewReader(" "+strings.ToUpper(text))), nil
+}
+
+func newTestSigner(t *testing.T, secret string) (*URLSigner, storage.Service) {
+	t.Helper()
+
+	service := memory.New()
+	for key, content := range map[string]string{"docs/report.txt": "report", "images/a.png": "png"} {
+		contentType := "text/plain"
+		if strings.HasSuffix(key, ".png") {
+			contentType = "image/png"
+		}
+
+		_, err := service.PutObject(context.Background(), storage.PutObjectOptions{
+			Key:         key,
+			Reader:      strings.NewReader(content),
+			Size:        int64(len(content)),
+			ContentType: contentType,
+		})
+		require.NoError(t, err)
+	}
+
+	signer, err := NewURLSigner(&config.StorageConfig{
+		Signing: config.SigningConfig{Secret: secret, MaxExpires: 24 * time.Hour},
+	}, service, NewMemoryDownloadCounter(), []storage.Watermarker{upperWatermarker{}})
+	require.NoError(t, err)
+
+	return signer, service
+}
+
+// openSigned opens the object of a signed path.
+func openSigned(signer *URLSigner, signed string) (*SignedObject, error) {
+	u, err := url.Parse(signed)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := url.PathUnescape(strings.TrimPrefix(u.EscapedPath(), storage.SignedURLPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.Open(context.Background(), key, u.Query())
+}
+
+func readSigned(t *testing.T, signer *URLSigner, signed string) string {
+	t.Helper()
+
+	object, err := openSigned(signer, signed)
+	require.NoError(t, err)
+
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestURLSigner(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("SignAndOpen", func(t *testing.T) {
+		signer, _ := newTestSigner(t, "secret")
+
+		signed, err := signer.Sign(ctx, storage.SignURLOptions{Key: "docs/report.txt", Filename: "年度报告.txt"})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(signed, "/storage/signed/docs/report.txt?"))
+
+		object, err := openSigned(signer, signed)
+		require.NoError(t, err)
+
+		defer object.Close()
+
+		assert.Equal(t, "年度报告.txt", object.Filename)
+		assert.Equal(t, "text/plain", object.Info.ContentType)
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		signer, _ := newTestSigner(t, "secret")
+
+		signed, err := signer.Sign(ctx, storage.SignURLOptions{Key: "docs/report.txt", MaxDownloads: 1})
+		require.NoError(t, err)
+
+		for _, tampered := range []string{
+			strings.Replace(signed, "report.txt", "a.png", 1),
+			strings.Replace(signed, "limit=1", "limit=9", 1),
+			signed + "&watermark=x",
+			signed[:len(signed)-2],
+		} {
+			_, err := openSigned(signer, tampered)
+			assert.ErrorIs(t, err, storage.ErrInvalidSignature, "Tampered url %s should be refused", tampered)
+		}
+
+		other, _ := newTestSigner(t, "other secret")
+		_, err = openSigned(other, signed)
+		assert.ErrorIs(t, err, storage.ErrInvalidSignature, "Urls signed with another secret should be refused")
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		signer, _ := newTestSigner(t, "secret")
+
+		signed, err := signer.Sign(ctx, storage.SignURLOptions{Key: "docs/report.txt", Expires: time.Minute})
+		require.NoError(t, err)
+
+		signer.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+		_, err = openSigned(signer, signed)
+		assert.ErrorIs(t, err, storage.ErrURLExpired)
+	})
+
+	t.Run("ExpiresCapped", func(t *testing.T) {
+		signer, _ := newTestSigner(t, "secret")
+
+		signed, err := signer.Sign(ctx, storage.SignURLOptions{Key: "docs/report.txt", Expires: 30 * 24 * time.Hour})
+		require.NoError(t, err)
+
+		signer.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+
+		_, err = openSigned(signer, signed)
+		assert.ErrorIs(t, err, storage.ErrURLExpired, "Lifetime should be capped at the maximum")
+	})
+
+	t.Run("DownloadLimit", func(t *testing.T) {
+		signer, _ := newTestSigner(t, "secret")
+
+		signed, err := signer.Sign(ctx, storage.SignURLOptions{Key: "docs/report.txt", MaxDownloads: 2})
+		require.NoError(t, err)
+
+		another, err := signer.Sign(ctx, storage.SignURLOptions{Key: "docs/report.txt", MaxDownloads: 2})
+		require.NoError(t, err)
+
+		assert.Equal(t, "report", readSigned(t, signer, signed))
+		assert.Equal(t, "report", readSigned(t, signer, signed))
+
+		_, err = openSigned(signer, signed)
+		assert.ErrorIs(t, err, storage.ErrDownloadLimitReached)
+
+		assert.Equal(t, "report", readSigned(t, signer, another), "Every signed url should be counted on its own")
+	})
+
+	t.Run("Watermark", func(t *testing.T) {
+		signer, _ := newTestSigner(t, "secret")
+
+		signed, err := signer.Sign(ctx, storage.SignURLOptions{Key: "docs/report.txt", Watermark: "alice"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "report ALICE", readSigned(t, signer, signed))
+
+		_, err = signer.Sign(ctx, storage.SignURLOptions{Key: "images/a.png", Watermark: "alice"})
+		assert.ErrorIs(t, err, storage.ErrWatermarkUnsupported)
+	})
+
+	t.Run("MissingObject", func(t *testing.T) {
+		signer, service := newTestSigner(t, "secret")
+
+		_, err := signer.Sign(ctx, storage.SignURLOptions{Key: "docs/missing.txt"})
+		assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+
+		signed, err := signer.Sign(ctx, storage.SignURLOptions{Key: "docs/report.txt"})
+		require.NoError(t, err)
+		require.NoError(t, service.DeleteObject(ctx, storage.DeleteObjectOptions{Key: "docs/report.txt"}))
+
+		_, err = openSigned(signer, signed)
+		assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+	})
+}
+
+func TestProxyMiddlewareSignedFile(t *testing.T) {
+	signer, service := newTestSigner(t, "secret")
+
+	_, err := service.PutObject(context.Background(), storage.PutObjectOptions{
+		Key:         "docs/with space.txt",
+		Reader:      bytes.NewReader([]byte("spaced")),
+		Size:        6,
+		ContentType: "text/plain",
+	})
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(ctx fiber.Ctx, err error) error {
+			var resultErr result.Error
+			if errors.As(err, &resultErr) {
+				return ctx.Status(resultErr.Status).JSON(fiber.Map{"code": resultErr.Code})
+			}
+
+			return ctx.SendStatus(http.StatusInternalServerError)
+		},
+	})
+	NewProxyMiddleware(service, signer, nil).Apply(app)
+
+	t.Run("Download", func(t *testing.T) {
+		signed, err := signer.Sign(context.Background(), storage.SignURLOptions{
+			Key:          "docs/with space.txt",
+			MaxDownloads: 1,
+			Filename:     "notes.txt",
+		})
+		require.NoError(t, err)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, signed, nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "private, no-store", resp.Header.Get(fiber.HeaderCacheControl))
+		assert.Equal(t, `attachment; filename=notes.txt`, resp.Header.Get(fiber.HeaderContentDisposition))
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "spaced", string(body))
+
+		resp, err = app.Test(httptest.NewRequest(http.MethodGet, signed, nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+	})
+
+	t.Run("InvalidSignature", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/storage/signed/docs/report.txt?expires=9999999999&signature=abc", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestUnprocessedUploadsNotServed(t *testing.T) {
+	ctx := context.Background()
+	service := memory.New()
+
+	for key, status := range map[string]storage.ProcessingStatus{
+		"temp/pending.txt":   storage.ProcessingPending,
+		"temp/failed.txt":    storage.ProcessingFailed,
+		"temp/completed.txt": storage.ProcessingCompleted,
+	} {
+		_, err := service.PutObject(ctx, storage.PutObjectOptions{
+			Key:         key,
+			Reader:      strings.NewReader("content"),
+			Size:        7,
+			ContentType: "text/plain",
+			Metadata:    map[string]string{storage.MetadataKeyProcessingStatus: string(status)},
+		})
+		require.NoError(t, err)
+	}
+
+	cfg := &config.StorageConfig{
+		Processing: config.ProcessingConfig{Enabled: true},
+		Signing:    config.SigningConfig{Secret: "secret"},
+	}
+
+	signer, err := NewURLSigner(cfg, service, NewMemoryDownloadCounter(), nil)
+	require.NoError(t, err)
+
+	pipeline, err := newPipeline(cfg.Processing, service, nil, nil)
+	require.NoError(t, err)
+
+	defer pipeline.Shutdown(ctx)
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(ctx fiber.Ctx, err error) error {
+			var resultErr result.Error
+			if errors.As(err, &resultErr) {
+				return ctx.Status(resultErr.Status).JSON(fiber.Map{"code": resultErr.Code})
+			}
+
+			return ctx.SendStatus(http.StatusInternalServerError)
+		},
+	})
+	NewProxyMiddleware(service, signer, pipeline).Apply(app)
+
+	tests := []struct {
+		key    string
+		status int
+	}{
+		{"temp/pending.txt", http.StatusForbidden},
+		{"temp/failed.txt", http.StatusForbidden},
+		{"temp/completed.txt", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/storage/files/"+tt.key, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode, "Proxy should only serve processed uploads")
+
+			signed, err := signer.Sign(ctx, storage.SignURLOptions{Key: tt.key})
+			require.NoError(t, err)
+
+			resp, err = app.Test(httptest.NewRequest(http.MethodGet, signed, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode, "Signed URLs should only serve processed uploads")
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package tenancy
+
+import "errors"
+
+var (
+	// ErrUnknownStrategy indicates the configured tenancy strategy is neither row nor schema.
+	ErrUnknownStrategy = errors.New("unknown tenancy strategy")
+	// ErrSchemaRequiresPostgres indicates schema-per-tenant tenancy is configured on a database other than PostgreSQL.
+	ErrSchemaRequiresPostgres = errors.New("schema-per-tenant tenancy requires PostgreSQL")
+)
@@ -0,0 +1,79 @@
+package tenancy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+	"go.uber.org/fx"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	"github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+var logger = log.Named("tenancy")
+
+// Module provides the tenancy of the ORM configured under vef.tenancy, nil when no strategy is configured.
+// Applications can supply their own orm.TenantResolver, e.g. to read the tenant from a subdomain.
+var Module = fx.Module(
+	"vef:tenancy",
+	fx.Provide(
+		fx.Annotate(
+			newTenancy,
+			fx.ParamTags(``, ``, ``, `optional:"true"`),
+		),
+	),
+)
+
+func newTenancy(
+	lc fx.Lifecycle,
+	cfg *config.TenancyConfig,
+	dsConfig *config.DatasourceConfig,
+	resolver orm.TenantResolver,
+) (*orm.Tenancy, error) {
+	if resolver == nil {
+		resolver = orm.TenantResolverFunc(contextx.TenantID)
+	}
+
+	var tenancy *orm.Tenancy
+
+	switch orm.TenancyStrategy(cfg.Strategy) {
+	case constants.Empty:
+		return nil, nil
+	case orm.TenancyRowPerTenant:
+		tenancy = orm.NewRowTenancy(resolver, cfg.Column)
+	case orm.TenancySchemaPerTenant:
+		if dsConfig.Type != constants.Postgres {
+			return nil, fmt.Errorf("%w, got %s", ErrSchemaRequiresPostgres, dsConfig.Type)
+		}
+
+		tenancy = orm.NewSchemaTenancy(resolver, cfg.SchemaPrefix, schemaOpener(dsConfig))
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStrategy, cfg.Strategy)
+	}
+
+	if cfg.RequireTenant {
+		tenancy.RequireTenant()
+	}
+
+	lc.Append(fx.StopHook(tenancy.Close))
+	logger.Infof("Tenancy enabled with the %s-per-tenant strategy", tenancy.Strategy())
+
+	return tenancy, nil
+}
+
+// schemaOpener opens the pools of the tenant schemas with the datasource config, putting the schema of the tenant
+// before the configured schema in the search_path so shared tables stay resolvable.
+func schemaOpener(dsConfig *config.DatasourceConfig) orm.TenantDBOpener {
+	return func(schema string) (*bun.DB, error) {
+		tenantConfig := *dsConfig
+		tenantConfig.Schema = strings.Join([]string{schema, lo.Ternary(dsConfig.Schema != constants.Empty, dsConfig.Schema, "public")}, constants.Comma)
+
+		return database.New(&tenantConfig)
+	}
+}
@@ -0,0 +1,67 @@
+package livequery
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Row is a row of a live query result keyed by column.
+type Row = map[string]any
+
+// Diff is the change of a live query result between two runs.
+type Diff struct {
+	Query   string `json:"query"`
+	Added   []Row  `json:"added,omitempty"`
+	Removed []Row  `json:"removed,omitempty"`
+	Changed []Row  `json:"changed,omitempty"`
+	Error   string `json:"error,omitempty"` // Failure of the run, whose cause is only logged; the previous result stays current
+}
+
+// IsEmpty reports whether the diff carries neither changes nor an error.
+func (d Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && d.Error == ""
+}
+
+// rowKey identifies a row by the values of the key columns.
+func rowKey(key []string, row Row) string {
+	values := make([]any, len(key))
+	for i, column := range key {
+		values[i] = row[column]
+	}
+
+	return fmt.Sprintf("%#v", values)
+}
+
+// diffRows compares two results of a live query, matching rows by the key columns.
+// Added and changed rows follow the order of next, removed rows the order of prev.
+func diffRows(name string, key []string, prev, next []Row) Diff {
+	diff := Diff{Query: name}
+
+	previous := make(map[string]Row, len(prev))
+	for _, row := range prev {
+		previous[rowKey(key, row)] = row
+	}
+
+	seen := make(map[string]bool, len(next))
+
+	for _, row := range next {
+		id := rowKey(key, row)
+		seen[id] = true
+
+		old, ok := previous[id]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, row)
+		case !reflect.DeepEqual(old, row):
+			diff.Changed = append(diff.Changed, row)
+		}
+	}
+
+	for _, row := range prev {
+		if !seen[rowKey(key, row)] {
+			diff.Removed = append(diff.Removed, row)
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,51 @@
+package livequery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiffRows tests matching rows of two results by their key columns.
+func TestDiffRows(t *testing.T) {
+	prev := []Row{
+		{"id": 1, "status": "open"},
+		{"id": 2, "status": "open"},
+		{"id": 3, "status": "open"},
+	}
+	next := []Row{
+		{"id": 2, "status": "closed"},
+		{"id": 3, "status": "open"},
+		{"id": 4, "status": "open"},
+	}
+
+	diff := diffRows("orders", []string{"id"}, prev, next)
+
+	assert.Equal(t, "orders", diff.Query, "Should name the query")
+	assert.Equal(t, []Row{{"id": 4, "status": "open"}}, diff.Added, "Should add rows missing before")
+	assert.Equal(t, []Row{{"id": 1, "status": "open"}}, diff.Removed, "Should remove rows missing now")
+	assert.Equal(t, []Row{{"id": 2, "status": "closed"}}, diff.Changed, "Should report rows with changed values")
+	assert.False(t, diff.IsEmpty(), "Should not be empty")
+}
+
+// TestDiffRowsCompositeKey tests rows identified by several columns.
+func TestDiffRowsCompositeKey(t *testing.T) {
+	prev := []Row{{"order_id": 1, "line": 1, "qty": 2}}
+	next := []Row{
+		{"order_id": 1, "line": 1, "qty": 2},
+		{"order_id": 1, "line": 2, "qty": 5},
+	}
+
+	diff := diffRows("lines", []string{"order_id", "line"}, prev, next)
+
+	assert.Equal(t, []Row{{"order_id": 1, "line": 2, "qty": 5}}, diff.Added, "Should add the new line only")
+	assert.Empty(t, diff.Removed, "Should remove nothing")
+	assert.Empty(t, diff.Changed, "Should change nothing")
+}
+
+// TestDiffRowsUnchanged tests identical results yielding an empty diff.
+func TestDiffRowsUnchanged(t *testing.T) {
+	rows := []Row{{"id": 1, "name": "a"}}
+
+	assert.True(t, diffRows("q", []string{"id"}, rows, []Row{{"id": 1, "name": "a"}}).IsEmpty(), "Should be empty")
+}
@@ -0,0 +1,18 @@
+package livequery
+
+import "errors"
+
+var (
+	// ErrQueryNameRequired indicates the live query name is required.
+	ErrQueryNameRequired = errors.New("live query name is required")
+	// ErrQueryRequired indicates the live query function is required.
+	ErrQueryRequired = errors.New("live query function is required")
+	// ErrKeyRequired indicates the key columns identifying the rows are required.
+	ErrKeyRequired = errors.New("live query key columns are required")
+	// ErrQueryExists indicates a live query with the same name is already registered.
+	ErrQueryExists = errors.New("live query already registered")
+	// ErrQueryNotFound indicates no live query with the given name is registered.
+	ErrQueryNotFound = errors.New("live query not found")
+	// ErrHubClosed indicates the hub no longer accepts subscriptions.
+	ErrHubClosed = errors.New("live query hub is closed")
+)
@@ -0,0 +1,330 @@
+package livequery
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+const (
+	// defaultInterval is the polling interval of live queries declaring none.
+	defaultInterval = 5 * time.Second
+	// subscriberBuffer is the number of diffs buffered per subscriber, slower subscribers are disconnected.
+	subscriberBuffer = 16
+	// keepAliveInterval is the interval of comments written to idle streams to detect disconnected clients.
+	keepAliveInterval = 15 * time.Second
+	// runFailedError is the error pushed for failed runs, the cause is logged only.
+	runFailedError = "live query failed"
+)
+
+var logger = log.Named("livequery")
+
+// Query reads the current rows of a live query.
+type Query func(ctx context.Context, db orm.DB) ([]Row, error)
+
+// Definition declares a live query.
+type Definition struct {
+	// Name identifies the live query to subscribers.
+	Name string
+	// Key names the columns identifying the rows across runs.
+	Key []string
+	// Interval is the time between two runs, 5 seconds by default.
+	Interval time.Duration
+	// Query reads the rows of the live query.
+	Query Query
+}
+
+// SseHeaders contains the headers of live query event streams.
+var SseHeaders = map[string]string{
+	fiber.HeaderContentType:  "text/event-stream",
+	fiber.HeaderCacheControl: "no-cache",
+	fiber.HeaderConnection:   "keep-alive",
+	"X-Accel-Buffering":      "no",
+}
+
+// Hub runs registered live queries while they have subscribers and pushes the changes of their results to them.
+// A query runs separately for each principal and tenant subscribing, with the context of its first subscription,
+// so tenancy and data scopes apply to it; it polls on its interval from its first subscriber on and stops with its last one.
+type Hub struct {
+	db orm.DB
+
+	mu      sync.Mutex
+	queries map[string]*liveQuery
+	closed  bool
+}
+
+// liveQuery is a registered live query.
+type liveQuery struct {
+	def    Definition
+	scopes map[string]*scopedQuery
+}
+
+// scopedQuery is the state of a live query run for the subscribers of a principal and tenant.
+type scopedQuery struct {
+	key         string
+	subscribers map[chan Diff]struct{}
+	rows        []Row // Result of the last successful run, nil before the first one
+	stop        context.CancelFunc
+}
+
+// NewHub creates a hub running live queries against the given database.
+func NewHub(db orm.DB) *Hub {
+	return &Hub{
+		db:      db,
+		queries: make(map[string]*liveQuery),
+	}
+}
+
+// Register registers a live query.
+func (h *Hub) Register(def Definition) error {
+	switch {
+	case def.Name == "":
+		return ErrQueryNameRequired
+	case def.Query == nil:
+		return ErrQueryRequired
+	case len(def.Key) == 0:
+		return ErrKeyRequired
+	}
+
+	if def.Interval <= 0 {
+		def.Interval = defaultInterval
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.queries[def.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrQueryExists, def.Name)
+	}
+
+	h.queries[def.Name] = &liveQuery{
+		def:    def,
+		scopes: make(map[string]*scopedQuery),
+	}
+
+	return nil
+}
+
+// Subscribe subscribes to the changes of a live query until the context is done.
+// The query runs with the values of the context, whose principal and tenant select the subscribers it is shared with.
+// The first diff adds all current rows, following ones carry the changes of each run.
+// The channel is closed when the subscription ends, the hub closes or the subscriber falls behind,
+// subscribing again then starts over from the current rows.
+func (h *Hub) Subscribe(ctx context.Context, name string) (<-chan Diff, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+
+	q, ok := h.queries[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrQueryNotFound, name)
+	}
+
+	key := scopeKey(ctx)
+
+	scoped, ok := q.scopes[key]
+	if !ok {
+		scoped = &scopedQuery{key: key, subscribers: make(map[chan Diff]struct{})}
+		q.scopes[key] = scoped
+	}
+
+	ch := make(chan Diff, subscriberBuffer)
+	scoped.subscribers[ch] = struct{}{}
+
+	if scoped.rows != nil {
+		ch <- diffRows(name, q.def.Key, nil, scoped.rows)
+	}
+
+	if scoped.stop == nil {
+		pollCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+		scoped.stop = stop
+
+		go h.poll(pollCtx, q, scoped)
+	}
+
+	go func() {
+		<-ctx.Done()
+		h.unsubscribe(q, scoped, ch)
+	}()
+
+	return ch, nil
+}
+
+// scopeKey identifies the principal and tenant of a subscription, whose subscribers see the same rows.
+func scopeKey(ctx context.Context) string {
+	principal := contextx.Principal(ctx)
+	if principal == nil {
+		principal = security.PrincipalAnonymous
+	}
+
+	return string(principal.Type) + constants.Colon + principal.ID + constants.Colon + contextx.TenantID(ctx)
+}
+
+// Close stops all live queries and ends their subscriptions.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+
+	for _, q := range h.queries {
+		for _, scoped := range q.scopes {
+			for ch := range scoped.subscribers {
+				h.removeLocked(q, scoped, ch)
+			}
+		}
+	}
+}
+
+// Stream subscribes the client of the request to a live query and streams the diffs as server-sent events,
+// "diff" events for changes and "error" events for failed runs.
+// The query runs as the principal and for the tenant of the request.
+func (h *Hub) Stream(ctx fiber.Ctx, name string) error {
+	// The request context is recycled once the handler returns, the values the query runs with are copied
+	subCtx := contextx.SetPrincipal(context.WithoutCancel(ctx.Context()), contextx.Principal(ctx))
+	subCtx = contextx.SetTenantID(subCtx, contextx.TenantID(ctx))
+	subCtx, cancel := context.WithCancel(subCtx)
+
+	diffs, err := h.Subscribe(subCtx, name)
+	if err != nil {
+		cancel()
+
+		return err
+	}
+
+	for k, v := range SseHeaders {
+		ctx.Set(k, v)
+	}
+
+	return ctx.SendStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		ticker := time.NewTicker(keepAliveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case diff, ok := <-diffs:
+				if !ok || writeEvent(w, diff) != nil {
+					return
+				}
+
+			case <-ticker.C:
+				if _, err := w.WriteString(": keep-alive\n\n"); err != nil || w.Flush() != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+// unsubscribe ends a subscription unless it already ended.
+func (h *Hub) unsubscribe(q *liveQuery, scoped *scopedQuery, ch chan Diff) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := scoped.subscribers[ch]; ok {
+		h.removeLocked(q, scoped, ch)
+	}
+}
+
+// removeLocked ends a subscription, stopping the query of the scope with its last subscriber.
+func (*Hub) removeLocked(q *liveQuery, scoped *scopedQuery, ch chan Diff) {
+	delete(scoped.subscribers, ch)
+	close(ch)
+
+	if len(scoped.subscribers) == 0 {
+		scoped.stop()
+		delete(q.scopes, scoped.key)
+	}
+}
+
+// poll runs the query of the scope on its interval until stopped.
+func (h *Hub) poll(ctx context.Context, q *liveQuery, scoped *scopedQuery) {
+	ticker := time.NewTicker(q.def.Interval)
+	defer ticker.Stop()
+
+	for {
+		h.run(ctx, q, scoped)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run runs the query of the scope once and pushes the diff against the previous result to its subscribers.
+func (h *Hub) run(ctx context.Context, q *liveQuery, scoped *scopedQuery) {
+	rows, err := q.def.Query(ctx, h.db)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// The query may have stopped, and restarted for new subscribers, while running
+	if ctx.Err() != nil {
+		return
+	}
+
+	var diff Diff
+	if err != nil {
+		logger.Errorf("Live query %s failed: %v", q.def.Name, err)
+
+		diff = Diff{Query: q.def.Name, Error: runFailedError}
+	} else {
+		if rows == nil {
+			rows = []Row{}
+		}
+
+		diff = diffRows(q.def.Name, q.def.Key, scoped.rows, rows)
+		scoped.rows = rows
+	}
+
+	if diff.IsEmpty() {
+		return
+	}
+
+	for _, ch := range slices.Collect(maps.Keys(scoped.subscribers)) {
+		select {
+		case ch <- diff:
+		default:
+			h.removeLocked(q, scoped, ch)
+		}
+	}
+}
+
+// writeEvent writes a diff as a server-sent event.
+func writeEvent(w *bufio.Writer, diff Diff) error {
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return fmt.Errorf("failed to marshal diff: %w", err)
+	}
+
+	event := "diff"
+	if diff.Error != "" {
+		event = "error"
+	}
+
+	if _, err := w.WriteString("event: " + event + "\ndata: " + string(data) + "\n\n"); err != nil {
+		return fmt.Errorf("failed to write sse event: %w", err)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,224 @@
+package livequery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+// fakeSource serves the rows of a live query set by the test.
+type fakeSource struct {
+	mu   sync.Mutex
+	rows []Row
+	err  error
+	runs int
+}
+
+func (s *fakeSource) set(rows []Row, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rows, s.err = rows, err
+}
+
+func (s *fakeSource) query(context.Context, orm.DB) ([]Row, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.runs++
+
+	return s.rows, s.err
+}
+
+func (s *fakeSource) runCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.runs
+}
+
+func receive(t *testing.T, diffs <-chan Diff) Diff {
+	t.Helper()
+
+	select {
+	case diff, ok := <-diffs:
+		require.True(t, ok, "Subscription should be open")
+
+		return diff
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "Should receive a diff")
+
+		return Diff{}
+	}
+}
+
+// TestRegister tests validation of live query definitions.
+func TestRegister(t *testing.T) {
+	hub := NewHub(nil)
+	source := &fakeSource{}
+
+	assert.ErrorIs(t, hub.Register(Definition{Key: []string{"id"}, Query: source.query}), ErrQueryNameRequired)
+	assert.ErrorIs(t, hub.Register(Definition{Name: "q", Key: []string{"id"}}), ErrQueryRequired)
+	assert.ErrorIs(t, hub.Register(Definition{Name: "q", Query: source.query}), ErrKeyRequired)
+	require.NoError(t, hub.Register(Definition{Name: "q", Key: []string{"id"}, Query: source.query}))
+	assert.ErrorIs(t, hub.Register(Definition{Name: "q", Key: []string{"id"}, Query: source.query}), ErrQueryExists)
+
+	_, err := hub.Subscribe(t.Context(), "missing")
+	assert.ErrorIs(t, err, ErrQueryNotFound)
+}
+
+// TestSubscribe tests pushing the changes of each run to subscribers.
+func TestSubscribe(t *testing.T) {
+	hub := NewHub(nil)
+	defer hub.Close()
+
+	source := &fakeSource{}
+	source.set([]Row{{"id": 1, "value": 10}}, nil)
+
+	require.NoError(t, hub.Register(Definition{
+		Name:     "metrics",
+		Key:      []string{"id"},
+		Interval: 10 * time.Millisecond,
+		Query:    source.query,
+	}))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	diffs, err := hub.Subscribe(ctx, "metrics")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Row{{"id": 1, "value": 10}}, receive(t, diffs).Added, "Should add the initial rows")
+
+	source.set([]Row{{"id": 1, "value": 11}, {"id": 2, "value": 5}}, nil)
+
+	diff := receive(t, diffs)
+	assert.Equal(t, []Row{{"id": 2, "value": 5}}, diff.Added, "Should add the new row")
+	assert.Equal(t, []Row{{"id": 1, "value": 11}}, diff.Changed, "Should report the changed row")
+
+	late, err := hub.Subscribe(ctx, "metrics")
+	require.NoError(t, err)
+	assert.Len(t, receive(t, late).Added, 2, "Should add all current rows for late subscribers")
+
+	source.set(nil, errors.New("connection lost"))
+	assert.Equal(t, runFailedError, receive(t, diffs).Error, "Should push failed runs without their cause")
+
+	source.set([]Row{{"id": 2, "value": 5}}, nil)
+	assert.Equal(t, []Row{{"id": 1, "value": 11}}, receive(t, diffs).Removed, "Should diff against the last result")
+}
+
+// TestScopes tests running queries separately for each principal and tenant with the context of their subscribers.
+func TestScopes(t *testing.T) {
+	hub := NewHub(nil)
+	defer hub.Close()
+
+	var (
+		mu   sync.Mutex
+		runs = make(map[string]int)
+	)
+
+	require.NoError(t, hub.Register(Definition{
+		Name:     "mine",
+		Key:      []string{"owner"},
+		Interval: time.Hour,
+		Query: func(ctx context.Context, _ orm.DB) ([]Row, error) {
+			owner := contextx.Principal(ctx).ID + "@" + contextx.TenantID(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			runs[owner]++
+
+			return []Row{{"owner": owner}}, nil
+		},
+	}))
+
+	subscribe := func(userID, tenantID string) <-chan Diff {
+		ctx := contextx.SetTenantID(contextx.SetPrincipal(t.Context(), security.NewUser(userID, userID)), tenantID)
+
+		diffs, err := hub.Subscribe(ctx, "mine")
+		require.NoError(t, err)
+
+		return diffs
+	}
+
+	alice := subscribe("alice", "t1")
+	assert.Equal(t, []Row{{"owner": "alice@t1"}}, receive(t, alice).Added, "Should run as the subscriber")
+
+	bob := subscribe("bob", "t1")
+	assert.Equal(t, []Row{{"owner": "bob@t1"}}, receive(t, bob).Added, "Should not share the rows of other principals")
+
+	otherTenant := subscribe("alice", "t2")
+	assert.Equal(t, []Row{{"owner": "alice@t2"}}, receive(t, otherTenant).Added, "Should not share the rows of other tenants")
+
+	again := subscribe("alice", "t1")
+	assert.Equal(t, []Row{{"owner": "alice@t1"}}, receive(t, again).Added, "Should share the rows of the same principal and tenant")
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, map[string]int{"alice@t1": 1, "bob@t1": 1, "alice@t2": 1}, runs, "Should run once per principal and tenant")
+}
+
+// TestUnsubscribe tests stopping the query with its last subscriber.
+func TestUnsubscribe(t *testing.T) {
+	hub := NewHub(nil)
+	source := &fakeSource{}
+	source.set([]Row{{"id": 1}}, nil)
+
+	require.NoError(t, hub.Register(Definition{
+		Name:     "q",
+		Key:      []string{"id"},
+		Interval: 10 * time.Millisecond,
+		Query:    source.query,
+	}))
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	diffs, err := hub.Subscribe(ctx, "q")
+	require.NoError(t, err)
+	receive(t, diffs)
+
+	cancel()
+
+	require.Eventually(t, func() bool {
+		_, ok := <-diffs
+
+		return !ok
+	}, 2*time.Second, 10*time.Millisecond, "Should close the subscription")
+
+	runs := source.runCount()
+	time.Sleep(50 * time.Millisecond)
+	assert.LessOrEqual(t, source.runCount(), runs+1, "Should stop polling without subscribers")
+
+	hub.Close()
+
+	_, err = hub.Subscribe(t.Context(), "q")
+	assert.ErrorIs(t, err, ErrHubClosed)
+}
+
+// TestWriteEvent tests the server-sent event framing of diffs.
+func TestWriteEvent(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := bufio.NewWriter(&buf)
+
+	require.NoError(t, writeEvent(w, Diff{Query: "q", Added: []Row{{"id": 1}}}))
+	require.NoError(t, writeEvent(w, Diff{Query: "q", Error: "boom"}))
+
+	assert.Equal(t,
+		"event: diff\ndata: {\"query\":\"q\",\"added\":[{\"id\":1}]}\n\n"+
+			"event: error\ndata: {\"query\":\"q\",\"error\":\"boom\"}\n\n",
+		buf.String())
+}
@@ -0,0 +1,28 @@
+package menu
+
+import (
+	"context"
+
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+// Service provides the menu tree and the menus permitted to users.
+type Service interface {
+	// Tree returns all menu entries, including disabled entries and buttons, as a tree ordered by sort order.
+	Tree(ctx context.Context) ([]Menu, error)
+	// RoleMenuIDs returns the ids of the entries bound to the role.
+	RoleMenuIDs(ctx context.Context, role string) ([]string, error)
+	// Permitted returns the menus and buttons permitted to the principal through its roles.
+	Permitted(ctx context.Context, principal *security.Principal) (*Permitted, error)
+	// RolePermTokens returns the permission tokens granted to the role by its bound, enabled entries.
+	RolePermTokens(ctx context.Context, role string) ([]string, error)
+}
+
+// Loader loads menu entries and role bindings.
+// Implementations typically read the menu tables, see NewDBLoader.
+type Loader interface {
+	// LoadMenus returns all menu entries.
+	LoadMenus(ctx context.Context) ([]Menu, error)
+	// LoadRoleMenuIDs returns the ids of the entries bound to the role.
+	LoadRoleMenuIDs(ctx context.Context, role string) ([]string, error)
+}
@@ -0,0 +1,46 @@
+package menu
+
+import (
+	"context"
+
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// dbLoader loads menus from the sys_menu and sys_role_menu tables.
+type dbLoader struct {
+	db orm.DB
+}
+
+// NewDBLoader creates a Loader reading the menu tables through the given database.
+func NewDBLoader(db orm.DB) Loader {
+	return &dbLoader{db: db}
+}
+
+func (l *dbLoader) LoadMenus(ctx context.Context) ([]Menu, error) {
+	var menus []Menu
+
+	if err := l.db.NewSelect().
+		Model(&menus).
+		OrderBy("sort_order", "id").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	return menus, nil
+}
+
+func (l *dbLoader) LoadRoleMenuIDs(ctx context.Context, role string) ([]string, error) {
+	var ids []string
+
+	if err := l.db.NewSelect().
+		Model((*RoleMenu)(nil)).
+		Select("menu_id").
+		Where(func(cb orm.ConditionBuilder) {
+			cb.Equals("role", role)
+		}).
+		Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,193 @@
+package menu
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	ievent "github.com/ilxqx/vef-framework-go/internal/event"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+func entry(id, parentID string, typ Type, name string, sortOrder int, permToken string) Menu {
+	menu := Menu{
+		Type:      typ,
+		Name:      name,
+		Path:      "/" + id,
+		SortOrder: sortOrder,
+		IsEnabled: true,
+	}
+	menu.ID = id
+
+	if parentID != constants.Empty {
+		menu.ParentID = null.StringFrom(parentID)
+	}
+
+	if permToken != constants.Empty {
+		menu.PermToken = null.StringFrom(permToken)
+	}
+
+	return menu
+}
+
+// testMenus is a system directory with the user and role pages, each with buttons, and a disabled log page.
+var testMenus = []Menu{
+	entry("system", "", TypeDirectory, "System", 1, ""),
+	entry("roles", "system", TypeMenu, "Roles", 2, "sys.role.query"),
+	entry("users", "system", TypeMenu, "Users", 1, "sys.user.query"),
+	entry("user-create", "users", TypeButton, "Create user", 1, "sys.user.create"),
+	entry("user-delete", "users", TypeButton, "Delete user", 2, "sys.user.delete"),
+	entry("role-create", "roles", TypeButton, "Create role", 1, "sys.role.create"),
+	entry("dashboard", "", TypeDashboard, "Dashboard", 0, ""),
+}
+
+type MenuTestSuite struct {
+	suite.Suite
+
+	ctx   context.Context
+	bus   event.Bus
+	bunDB *bun.DB
+	db    orm.DB
+}
+
+func (s *MenuTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	s.bus = ievent.NewMemoryBus([]event.Middleware{})
+	s.Require().NoError(s.bus.(interface{ Start() error }).Start(), "Should start event bus")
+
+	var err error
+
+	s.bunDB, err = database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	for _, model := range []any{(*Menu)(nil), (*RoleMenu)(nil)} {
+		_, err = s.bunDB.NewCreateTable().Model(model).Exec(s.ctx)
+		s.Require().NoError(err)
+	}
+
+	s.db = iorm.New(s.bunDB)
+
+	logs := entry("logs", "system", TypeMenu, "Logs", 3, "sys.log.query")
+	logs.IsEnabled = false
+	menus := append(append([]Menu{}, testMenus...), logs)
+
+	_, err = s.db.NewInsert().Model(&menus).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	bindings := []RoleMenu{
+		{Role: "user-admin", MenuID: "users"},
+		{Role: "user-admin", MenuID: "user-create"},
+		{Role: "user-admin", MenuID: "logs"},
+		{Role: "role-admin", MenuID: "role-create"},
+		{Role: "viewer", MenuID: "dashboard"},
+	}
+	_, err = s.db.NewInsert().Model(&bindings).Exec(s.ctx)
+	s.Require().NoError(err)
+}
+
+func (s *MenuTestSuite) TearDownSuite() {
+	if s.bunDB != nil {
+		s.Require().NoError(s.bunDB.Close())
+	}
+}
+
+func (s *MenuTestSuite) TestTree() {
+	tree, err := New(NewDBLoader(s.db), nil).Tree(s.ctx)
+	s.Require().NoError(err)
+
+	s.Require().Len(tree, 2, "Should have the dashboard and system roots")
+	s.Equal("dashboard", tree[0].ID, "Roots should be ordered by sort order")
+
+	system := tree[1]
+	s.Require().Len(system.Children, 3, "System should contain users, roles and the disabled logs")
+	s.Equal("users", system.Children[0].ID)
+	s.Len(system.Children[0].Children, 2, "Users should contain its buttons")
+}
+
+func (s *MenuTestSuite) TestPermitted() {
+	service := New(NewDBLoader(s.db), nil)
+
+	permitted, err := service.Permitted(s.ctx, security.NewUser("u1", "User", "user-admin", "role-admin"))
+	s.Require().NoError(err)
+
+	s.Require().Len(permitted.Menus, 1, "Only the system directory should be a root")
+
+	system := permitted.Menus[0]
+	s.Equal(security.UserMenuTypeDirectory, system.Type)
+	s.Equal("System", system.Name, "Ancestors of bound entries should be included")
+	s.Require().Len(system.Children, 2, "Disabled logs should be excluded")
+	s.Equal("Users", system.Children[0].Name)
+	s.Equal("Roles", system.Children[1].Name, "Pages of bound buttons should be included")
+	s.Empty(system.Children[0].Children, "Buttons should not be part of the navigation")
+
+	s.Equal(
+		[]string{"sys.role.create", "sys.user.create", "sys.user.query"},
+		permitted.PermTokens,
+		"Should grant the tokens of bound entries only, not of the ancestors shown for navigation",
+	)
+
+	permitted, err = service.Permitted(s.ctx, security.NewUser("u2", "Nobody"))
+	s.Require().NoError(err)
+	s.Empty(permitted.Menus, "Users without roles have no menus")
+	s.Empty(permitted.PermTokens, "Users without roles have no tokens")
+}
+
+func (s *MenuTestSuite) TestRolePermissionsLoader() {
+	permissions, err := NewRolePermissionsLoader(New(NewDBLoader(s.db), nil)).LoadPermissions(s.ctx, "role-admin")
+	s.Require().NoError(err)
+
+	s.Len(permissions, 1)
+	s.Contains(permissions, "sys.role.create")
+	s.NotContains(permissions, "sys.role.query", "Should not grant the token of the page of a bound button")
+}
+
+func (s *MenuTestSuite) TestMenusChangedEvent() {
+	service := New(NewDBLoader(s.db), s.bus)
+
+	ids, err := service.RoleMenuIDs(s.ctx, "viewer")
+	s.Require().NoError(err)
+	s.Equal([]string{"dashboard"}, ids)
+
+	_, err = s.db.NewInsert().Model(&RoleMenu{Role: "viewer", MenuID: "users"}).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	ids, err = service.RoleMenuIDs(s.ctx, "viewer")
+	s.Require().NoError(err)
+	s.Len(ids, 1, "Bindings should be cached until the change event")
+
+	rolePermissionsChanged := make(chan []string, 1)
+	s.bus.Subscribe("vef.security.role_permissions.changed", func(_ context.Context, evt event.Event) {
+		rolePermissionsChanged <- evt.(*security.RolePermissionsChangedEvent).Roles
+	})
+
+	PublishMenusChangedEvent(s.bus, "viewer")
+
+	s.Eventually(func() bool {
+		ids, err := service.RoleMenuIDs(s.ctx, "viewer")
+		s.Require().NoError(err)
+
+		return len(ids) == 2
+	}, time.Second, 10*time.Millisecond, "Should reload the bindings after the change event")
+
+	select {
+	case roles := <-rolePermissionsChanged:
+		s.Equal([]string{"viewer"}, roles, "Should invalidate the role permissions of the role")
+	case <-time.After(time.Second):
+		s.Fail("Should publish a role permissions changed event")
+	}
+}
+
+func TestMenuTestSuite(t *testing.T) {
+	suite.Run(t, new(MenuTestSuite))
+}
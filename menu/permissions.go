@@ -0,0 +1,33 @@
+package menu
+
+import (
+	"context"
+
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+// rolePermissionsLoader derives role permissions from the menu entries bound to the role.
+type rolePermissionsLoader struct {
+	service Service
+}
+
+// NewRolePermissionsLoader creates a security.RolePermissionsLoader granting the permission tokens
+// of the menu entries bound to a role, all with security.NewAllDataScope.
+// Applications restricting data scopes per role should implement their own loader instead.
+func NewRolePermissionsLoader(service Service) security.RolePermissionsLoader {
+	return &rolePermissionsLoader{service: service}
+}
+
+func (l *rolePermissionsLoader) LoadPermissions(ctx context.Context, role string) (map[string]security.DataScope, error) {
+	tokens, err := l.service.RolePermTokens(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make(map[string]security.DataScope, len(tokens))
+	for _, token := range tokens {
+		permissions[token] = security.NewAllDataScope()
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,258 @@
+package menu
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/ilxqx/vef-framework-go/cache"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	ilog "github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/log"
+	"github.com/ilxqx/vef-framework-go/security"
+	"github.com/ilxqx/vef-framework-go/treebuilder"
+)
+
+const (
+	// eventTypeMenusChanged is the event type for menu table and role binding changes.
+	eventTypeMenusChanged = "vef.menu.changed"
+	menusCacheKey         = "menus"
+)
+
+// MenusChangedEvent is published when menus or role bindings are modified.
+type MenusChangedEvent struct {
+	event.BaseEvent
+
+	Roles []string `json:"roles"` // Roles whose bindings changed (empty means the menus themselves changed)
+}
+
+// PublishMenusChangedEvent publishes a menus changed event via the provided publisher.
+// Pass the roles whose bindings changed, or no roles when menu entries were modified.
+// Since role permissions are usually derived from the bound menus, a security.RolePermissionsChangedEvent
+// is published for the same roles to keep the RBAC permission cache in sync.
+func PublishMenusChangedEvent(publisher event.Publisher, roles ...string) {
+	publisher.Publish(&MenusChangedEvent{
+		BaseEvent: event.NewBaseEvent(eventTypeMenusChanged),
+
+		Roles: roles,
+	})
+	security.PublishRolePermissionsChangedEvent(publisher, roles...)
+}
+
+// service implements Service on top of a Loader, caching the menus and role bindings.
+type service struct {
+	loader    Loader
+	menuCache cache.Cache[[]Menu]
+	roleCache cache.Cache[[]string]
+	logger    log.Logger
+}
+
+// New creates a menu service backed by the given loader.
+// Menus and role bindings are cached and invalidated by MenusChangedEvent when a subscriber is given.
+func New(loader Loader, subscriber event.Subscriber) Service {
+	s := &service{
+		loader:    loader,
+		menuCache: cache.NewMemory[[]Menu](),
+		roleCache: cache.NewMemory[[]string](),
+		logger:    ilog.Named("menu"),
+	}
+
+	if subscriber != nil {
+		subscriber.Subscribe(eventTypeMenusChanged, s.handleMenusChanged)
+	}
+
+	return s
+}
+
+func (s *service) handleMenusChanged(ctx context.Context, evt event.Event) {
+	changeEvent, ok := evt.(*MenusChangedEvent)
+	if !ok {
+		s.logger.Errorf("Received invalid event type: %T", evt)
+
+		return
+	}
+
+	if len(changeEvent.Roles) == 0 {
+		if err := s.menuCache.Clear(ctx); err != nil {
+			s.logger.Errorf("Failed to clear menu cache: %v", err)
+		}
+
+		if err := s.roleCache.Clear(ctx); err != nil {
+			s.logger.Errorf("Failed to clear role menu cache: %v", err)
+		}
+
+		return
+	}
+
+	for _, role := range changeEvent.Roles {
+		if err := s.roleCache.Delete(ctx, role); err != nil {
+			s.logger.Errorf("Failed to delete menu cache for role %s: %v", role, err)
+		}
+	}
+}
+
+// menus returns the cached menu entries ordered by sort order.
+func (s *service) menus(ctx context.Context) ([]Menu, error) {
+	return s.menuCache.GetOrLoad(ctx, menusCacheKey, func(ctx context.Context) ([]Menu, error) {
+		menus, err := s.loader.LoadMenus(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		slices.SortStableFunc(menus, func(a, b Menu) int {
+			return cmp.Compare(a.SortOrder, b.SortOrder)
+		})
+
+		return menus, nil
+	})
+}
+
+func (s *service) Tree(ctx context.Context) ([]Menu, error) {
+	menus, err := s.menus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The cached entries must not receive the children of the tree.
+	return treebuilder.Build(slices.Clone(menus), treebuilder.Adapter[Menu]{
+		GetID:       func(menu Menu) string { return menu.ID },
+		GetParentID: func(menu Menu) string { return menu.ParentID.ValueOrZero() },
+		GetChildren: func(menu Menu) []Menu { return menu.Children },
+		SetChildren: func(menu *Menu, children []Menu) { menu.Children = children },
+	}), nil
+}
+
+func (s *service) RoleMenuIDs(ctx context.Context, role string) ([]string, error) {
+	return s.roleCache.GetOrLoad(ctx, role, func(ctx context.Context) ([]string, error) {
+		return s.loader.LoadRoleMenuIDs(ctx, role)
+	})
+}
+
+func (s *service) Permitted(ctx context.Context, principal *security.Principal) (*Permitted, error) {
+	var roles []string
+	if principal != nil {
+		roles = principal.Roles
+	}
+
+	menus, visible, err := s.visibleMenus(ctx, roles...)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[string][]Menu)
+	for _, menu := range menus {
+		if _, ok := visible[menu.ID]; ok && menu.Type != TypeButton {
+			parentID := menu.ParentID.ValueOrZero()
+			children[parentID] = append(children[parentID], menu)
+		}
+	}
+
+	return &Permitted{
+		Menus:      buildUserMenus(children, constants.Empty),
+		PermTokens: permTokens(menus, visible),
+	}, nil
+}
+
+func (s *service) RolePermTokens(ctx context.Context, role string) ([]string, error) {
+	menus, visible, err := s.visibleMenus(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return permTokens(menus, visible), nil
+}
+
+// visibleMenus resolves the enabled entries bound to any of the roles together with their ancestors.
+// The visible entries map to whether they are bound: ancestors of bound entries are visible for navigation only
+// and grant no permission tokens.
+func (s *service) visibleMenus(ctx context.Context, roles ...string) ([]Menu, map[string]bool, error) {
+	menus, err := s.menus(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[string]*Menu, len(menus))
+	for i := range menus {
+		byID[menus[i].ID] = &menus[i]
+	}
+
+	visible := make(map[string]bool)
+
+	for _, role := range roles {
+		ids, err := s.RoleMenuIDs(ctx, role)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, id := range ids {
+			path, ok := ancestry(byID, id)
+			if !ok {
+				continue
+			}
+
+			visible[id] = true
+
+			for _, menuID := range path[1:] {
+				if _, ok := visible[menuID]; !ok {
+					visible[menuID] = false
+				}
+			}
+		}
+	}
+
+	return menus, visible, nil
+}
+
+// ancestry returns the id of the entry and its ancestors, or false when any of them is disabled or missing.
+func ancestry(byID map[string]*Menu, id string) ([]string, bool) {
+	var path []string
+
+	for id != constants.Empty {
+		menu, ok := byID[id]
+		// The length check guards against cyclic parent references.
+		if !ok || !menu.IsEnabled || len(path) > len(byID) {
+			return nil, false
+		}
+
+		path = append(path, id)
+		id = menu.ParentID.ValueOrZero()
+	}
+
+	return path, true
+}
+
+func buildUserMenus(children map[string][]Menu, parentID string) []security.UserMenu {
+	entries := children[parentID]
+	if len(entries) == 0 {
+		return nil
+	}
+
+	userMenus := make([]security.UserMenu, len(entries))
+	for i, menu := range entries {
+		userMenus[i] = security.UserMenu{
+			Type:     security.UserMenuType(menu.Type),
+			Path:     menu.Path,
+			Name:     menu.Name,
+			Icon:     menu.Icon,
+			Children: buildUserMenus(children, menu.ID),
+		}
+	}
+
+	return userMenus
+}
+
+// permTokens returns the sorted permission tokens of the bound entries.
+func permTokens(menus []Menu, visible map[string]bool) []string {
+	tokens := make([]string, 0)
+
+	for _, menu := range menus {
+		if visible[menu.ID] && menu.PermToken.ValueOrZero() != constants.Empty {
+			tokens = append(tokens, menu.PermToken.String)
+		}
+	}
+
+	slices.Sort(tokens)
+
+	return slices.Compact(tokens)
+}
@@ -0,0 +1,68 @@
+package menu
+
+import (
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+// Type is the kind of a menu entry.
+// Besides the navigation entries of security.UserMenuType, a menu may contain buttons,
+// which are not displayed in the navigation but grant their permission token.
+type Type string
+
+const (
+	TypeDirectory = Type(security.UserMenuTypeDirectory)
+	TypeMenu      = Type(security.UserMenuTypeMenu)
+	TypeView      = Type(security.UserMenuTypeView)
+	TypeDashboard = Type(security.UserMenuTypeDashboard)
+	TypeReport    = Type(security.UserMenuTypeReport)
+	TypeButton    = Type("button")
+)
+
+// Menu is an entry of the menu table.
+// Entries form a tree through ParentID; buttons are leaves below the page they belong to.
+type Menu struct {
+	orm.BaseModel `bun:"table:sys_menu,alias:sm"`
+	orm.Model
+
+	// ParentID is the id of the parent entry, null for top-level entries
+	ParentID null.String `json:"parentId"   bun:"parent_id"`
+	// Type is the kind of the entry
+	Type Type `json:"type"       bun:"type,notnull"`
+	// Name is the display name of the entry
+	Name string `json:"name"       bun:"name,notnull"`
+	// Path is the frontend route of navigation entries
+	Path string `json:"path"       bun:"path,notnull"`
+	// Icon is the icon of navigation entries
+	Icon null.String `json:"icon"       bun:"icon"`
+	// PermToken is the permission token granted by the entry, e.g. "sys.user.create" for a button
+	PermToken null.String `json:"permToken"  bun:"perm_token"`
+	// SortOrder orders siblings ascending
+	SortOrder int `json:"sortOrder"  bun:"sort_order,notnull"`
+	// IsEnabled hides the entry and its descendants when false
+	IsEnabled bool `json:"isEnabled"  bun:"is_enabled,notnull"`
+	// Children are the child entries when the menus are returned as a tree
+	Children []Menu `json:"children,omitempty" bun:"-"`
+}
+
+// RoleMenu binds a menu entry to a role.
+// Binding an entry shows its ancestors, so that permitted pages stay reachable in the navigation,
+// but grants the permission token of the bound entry only.
+type RoleMenu struct {
+	orm.BaseModel `bun:"table:sys_role_menu,alias:srm"`
+	orm.CreatedModel
+
+	// Role is the role name as found in security.Principal.Roles
+	Role string `json:"role"   bun:"role,pk"`
+	// MenuID is the id of the bound menu entry
+	MenuID string `json:"menuId" bun:"menu_id,pk"`
+}
+
+// Permitted is the menu and button set permitted to a user.
+type Permitted struct {
+	// Menus is the navigation tree of the permitted entries, without buttons
+	Menus []security.UserMenu `json:"menus"`
+	// PermTokens are the permission tokens of the bound entries, sorted
+	PermTokens []string `json:"permTokens"`
+}
@@ -0,0 +1,15 @@
+package metering
+
+import "context"
+
+// Recorder counts the usage of billing subjects, e.g. tenants, into aggregation windows.
+// Recording only buffers the quantity in memory, the buffered usage is flushed to the sys_metered_usage table
+// periodically and emitted as one billing event per subject, meter and window once the window closed.
+type Recorder interface {
+	// Add adds quantity to a counted meter of the subject, e.g. API calls or rows imported.
+	// The window bills the sum of the quantities added.
+	Add(ctx context.Context, subject, meter string, quantity int64)
+	// Observe records the current value of a gauged meter of the subject, e.g. the storage bytes in use.
+	// The window bills the peak of the values observed.
+	Observe(ctx context.Context, subject, meter string, value int64)
+}
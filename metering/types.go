@@ -0,0 +1,96 @@
+package metering
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// EventTypeBilling is the type of the billing events published for closed usage windows.
+const EventTypeBilling = "vef.metering.billing"
+
+// Meters counted by the framework or commonly recorded by applications.
+const (
+	// MeterAPICalls counts the API requests of a tenant, recorded by the metering middleware.
+	MeterAPICalls = "api_calls"
+	// MeterStorageBytes gauges the storage bytes in use.
+	MeterStorageBytes = "storage_bytes"
+	// MeterRows counts rows, e.g. rows imported or exported.
+	MeterRows = "rows"
+)
+
+// Aggregation is how the quantities recorded within a window are combined.
+type Aggregation string
+
+const (
+	// AggregationSum bills the sum of the quantities added.
+	AggregationSum Aggregation = "sum"
+	// AggregationMax bills the peak of the values observed.
+	AggregationMax Aggregation = "max"
+)
+
+// Usage is the usage of a subject for a meter aggregated within one window.
+type Usage struct {
+	orm.BaseModel `bun:"table:sys_metered_usage,alias:smu"`
+	orm.Model
+
+	Subject     string            `json:"subject"     bun:",notnull,unique:uk_sys_metered_usage"`
+	Meter       string            `json:"meter"       bun:",notnull,unique:uk_sys_metered_usage"`
+	WindowStart datetime.DateTime `json:"windowStart" bun:",notnull,type:timestamp,unique:uk_sys_metered_usage"`
+	WindowEnd   datetime.DateTime `json:"windowEnd"   bun:",notnull,type:timestamp"`
+	Aggregation Aggregation       `json:"aggregation" bun:",notnull"`
+	Quantity    int64             `json:"quantity"    bun:",notnull"`
+	EmittedAt   null.DateTime     `json:"emittedAt"   bun:",type:timestamp"` // Set once the billing record of the closed window is written
+}
+
+// BillingRecord is the billing event of a closed window kept in the outbox until it is published.
+type BillingRecord struct {
+	orm.BaseModel `bun:"table:sys_billing_outbox,alias:sbo"`
+	orm.Model
+
+	IdempotencyKey string            `json:"idempotencyKey" bun:",notnull,unique"`
+	Subject        string            `json:"subject"        bun:",notnull"`
+	Meter          string            `json:"meter"          bun:",notnull"`
+	Aggregation    Aggregation       `json:"aggregation"    bun:",notnull"`
+	Quantity       int64             `json:"quantity"       bun:",notnull"`
+	WindowStart    datetime.DateTime `json:"windowStart"    bun:",notnull,type:timestamp"`
+	WindowEnd      datetime.DateTime `json:"windowEnd"      bun:",notnull,type:timestamp"`
+	PublishedAt    null.DateTime     `json:"publishedAt"    bun:",type:timestamp"`
+}
+
+// IdempotencyKey identifies the billing event of a subject, meter and window.
+func IdempotencyKey(subject, meter string, windowStart time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", subject, meter, windowStart.Unix())
+}
+
+// BillingEvent reports the usage of a subject for a meter within a closed window.
+// Events are delivered at least once, consumers deduplicate them by IdempotencyKey.
+type BillingEvent struct {
+	event.BaseEvent
+
+	IdempotencyKey string      `json:"idempotencyKey"`
+	Subject        string      `json:"subject"`
+	Meter          string      `json:"meter"`
+	Aggregation    Aggregation `json:"aggregation"`
+	Quantity       int64       `json:"quantity"`
+	WindowStart    time.Time   `json:"windowStart"`
+	WindowEnd      time.Time   `json:"windowEnd"`
+}
+
+// NewBillingEvent creates the billing event of the outbox record.
+func NewBillingEvent(record *BillingRecord) *BillingEvent {
+	return &BillingEvent{
+		BaseEvent:      event.NewBaseEvent(EventTypeBilling, event.WithMeta("idempotencyKey", record.IdempotencyKey)),
+		IdempotencyKey: record.IdempotencyKey,
+		Subject:        record.Subject,
+		Meter:          record.Meter,
+		Aggregation:    record.Aggregation,
+		Quantity:       record.Quantity,
+		WindowStart:    record.WindowStart.Unwrap(),
+		WindowEnd:      record.WindowEnd.Unwrap(),
+	}
+}
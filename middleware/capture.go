@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/encoding"
+)
+
+const (
+	// CaptureKeyPrefix is the storage key prefix of captured records, followed by the capture date and the record id.
+	CaptureKeyPrefix = "captures/"
+	// RedactedValue replaces redacted header and field values.
+	RedactedValue = "******"
+)
+
+// redactedHeaders are request and response headers never recorded in clear text.
+var redactedHeaders = map[string]struct{}{
+	"authorization":       {},
+	"proxy-authorization": {},
+	"cookie":              {},
+	"set-cookie":          {},
+	"x-signature":         {},
+}
+
+// sensitiveFieldMarkers are name fragments of JSON fields whose values are redacted.
+var sensitiveFieldMarkers = []string{"password", "secret", "token", "credential"}
+
+// CaptureRecord is a captured request/response pair.
+type CaptureRecord struct {
+	ID         string           `json:"id"`
+	RequestID  string           `json:"requestId"`
+	CapturedAt time.Time        `json:"capturedAt"`
+	Latency    int64            `json:"latency"` // Milliseconds
+	Request    CapturedRequest  `json:"request"`
+	Response   CapturedResponse `json:"response"`
+}
+
+// CapturedRequest is the recorded request of a CaptureRecord.
+type CapturedRequest struct {
+	Method      string            `json:"method"`
+	Path        string            `json:"path"`
+	Query       string            `json:"query,omitempty"`
+	Headers     map[string]string `json:"headers"`
+	Body        string            `json:"body,omitempty"`
+	BodyOmitted bool              `json:"bodyOmitted,omitempty"` // The body was too large or not textual
+}
+
+// CapturedResponse is the recorded response of a CaptureRecord.
+type CapturedResponse struct {
+	Status      int               `json:"status"`
+	Headers     map[string]string `json:"headers"`
+	Body        string            `json:"body,omitempty"`
+	BodyOmitted bool              `json:"bodyOmitted,omitempty"`
+}
+
+// IsRedactedHeader reports whether the header value must not be recorded.
+func IsRedactedHeader(name string) bool {
+	_, ok := redactedHeaders[strings.ToLower(name)]
+
+	return ok
+}
+
+// RedactJSON replaces the values of sensitive fields in a JSON document.
+// Fields are sensitive when their name contains password, secret, token or credential,
+// or matches one of the extra field names case-insensitively. Documents that are not valid JSON are returned as is.
+func RedactJSON(body string, extraFields ...string) string {
+	// Numbers are kept as written, decoding them as float64 would corrupt large ids.
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+
+	redacted, err := encoding.ToJSON(redactValue(value, extraFieldSet(extraFields)))
+	if err != nil {
+		return body
+	}
+
+	return redacted
+}
+
+// RedactQuery replaces the values of sensitive parameters in a query string or form-urlencoded body,
+// using the same rules as RedactJSON.
+func RedactQuery(query string, extraFields ...string) string {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return query
+	}
+
+	extra := extraFieldSet(extraFields)
+	for key, items := range values {
+		if isSensitiveField(key, extra) {
+			for i := range items {
+				items[i] = RedactedValue
+			}
+		}
+	}
+
+	return values.Encode()
+}
+
+func extraFieldSet(fields []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		set[strings.ToLower(field)] = struct{}{}
+	}
+
+	return set
+}
+
+func redactValue(value any, extra map[string]struct{}) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if isSensitiveField(key, extra) {
+				v[key] = RedactedValue
+			} else {
+				v[key] = redactValue(item, extra)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item, extra)
+		}
+	}
+
+	return value
+}
+
+func isSensitiveField(name string, extra map[string]struct{}) bool {
+	name = strings.ToLower(name)
+	if _, ok := extra[name]; ok {
+		return true
+	}
+
+	for _, marker := range sensitiveFieldMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CaptureKey returns the storage key of the record.
+func CaptureKey(record *CaptureRecord) string {
+	return CaptureKeyPrefix + record.CapturedAt.Format("2006/01/02") + constants.Slash + record.ID + ".json"
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		extra    []string
+		expected string
+	}{
+		{
+			"SensitiveFields",
+			`{"username":"alice","password":"p@ss","accessToken":"abc"}`,
+			nil,
+			`{"accessToken":"******","password":"******","username":"alice"}`,
+		},
+		{
+			"NestedObjectsAndArrays",
+			`{"items":[{"appSecret":"s","id":9007199254740993}]}`,
+			nil,
+			`{"items":[{"appSecret":"******","id":9007199254740993}]}`,
+		},
+		{
+			"ExtraFields",
+			`{"idCard":"110101199003071234","name":"bob"}`,
+			[]string{"IDCARD"},
+			`{"idCard":"******","name":"bob"}`,
+		},
+		{
+			"InvalidJSON",
+			`not json`,
+			nil,
+			`not json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RedactJSON(tt.body, tt.extra...))
+		})
+	}
+}
+
+func TestRedactQuery(t *testing.T) {
+	assert.Equal(t, "page=1&token=%2A%2A%2A%2A%2A%2A", RedactQuery("page=1&token=abc"))
+	assert.Equal(t, "phone=%2A%2A%2A%2A%2A%2A", RedactQuery("phone=123", "phone"))
+}
+
+func TestIsRedactedHeader(t *testing.T) {
+	assert.True(t, IsRedactedHeader("Authorization"))
+	assert.True(t, IsRedactedHeader("cookie"))
+	assert.False(t, IsRedactedHeader("Content-Type"))
+}
+
+func TestCaptureKey(t *testing.T) {
+	record := &CaptureRecord{
+		ID:         "abc",
+		CapturedAt: time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, "captures/2026/10/16/abc.json", CaptureKey(record))
+}
@@ -23,3 +23,29 @@ type Service interface {
 	// BuildInfo returns application build information if available.
 	BuildInfo() *BuildInfo
 }
+
+// LockContentionTracker tracks database lock waits and flags statements and rows with repeated contention.
+type LockContentionTracker interface {
+	// LockContention returns the lock waits aggregated over the contention window, hottest first.
+	LockContention(ctx context.Context) (*LockContentionReport, error)
+}
+
+// ConnectionLeakDetector exposes the database connections currently held, for diagnosing connection leaks.
+type ConnectionLeakDetector interface {
+	// ConnectionHolders returns the current connection holders, longest held first.
+	ConnectionHolders(ctx context.Context) (*ConnectionHoldersReport, error)
+}
+
+// ConnectionPoolMonitor exposes the statistics of the database connection pool, sampled on an interval.
+type ConnectionPoolMonitor interface {
+	// ConnectionPoolStats returns the latest sample of the connection pool.
+	ConnectionPoolStats(ctx context.Context) (*ConnectionPoolStats, error)
+}
+
+// HealthContributor reports the health of a component to the health check.
+type HealthContributor interface {
+	// Name returns the name of the component in the health report.
+	Name() string
+	// Health returns the health of the component.
+	Health(ctx context.Context) *ComponentHealth
+}
@@ -262,3 +262,94 @@ type BuildInfo struct {
 	BuildTime  string `json:"buildTime"`
 	GitCommit  string `json:"gitCommit"`
 }
+
+// LockContentionReport summarizes the lock waits observed within the contention window.
+type LockContentionReport struct {
+	Enabled    bool                  `json:"enabled"`
+	Supported  bool                  `json:"supported"`
+	WindowMs   int64                 `json:"windowMs"`
+	Threshold  int                   `json:"threshold"`
+	SampledAt  int64                 `json:"sampledAt"`
+	Statements []StatementContention `json:"statements"`
+	Rows       []RowContention       `json:"rows"`
+}
+
+// StatementContention aggregates the lock waits of a normalized statement.
+type StatementContention struct {
+	Query       string `json:"query"`
+	WaitCount   int    `json:"waitCount"`
+	TotalWaitMs int64  `json:"totalWaitMs"`
+	MaxWaitMs   int64  `json:"maxWaitMs"`
+	LastSeen    int64  `json:"lastSeen"`
+	Hot         bool   `json:"hot"`
+}
+
+// RowContention aggregates the lock waits on a row (or the finest lock target reported by the database).
+type RowContention struct {
+	Table       string `json:"table"`
+	Locator     string `json:"locator"`
+	WaitCount   int    `json:"waitCount"`
+	TotalWaitMs int64  `json:"totalWaitMs"`
+	MaxWaitMs   int64  `json:"maxWaitMs"`
+	LastSeen    int64  `json:"lastSeen"`
+	Hot         bool   `json:"hot"`
+}
+
+// ConnectionHoldersReport lists the database connections currently held by open result sets or transactions.
+type ConnectionHoldersReport struct {
+	Enabled         bool               `json:"enabled"`
+	HoldThresholdMs int64              `json:"holdThresholdMs"`
+	Holders         []ConnectionHolder `json:"holders"`
+}
+
+// ConnectionHolder is a connection checkout with the stack trace of the code that acquired it.
+type ConnectionHolder struct {
+	ID         uint64 `json:"id"`
+	Kind       string `json:"kind"`
+	Query      string `json:"query"`
+	AcquiredAt int64  `json:"acquiredAt"`
+	HeldMs     int64  `json:"heldMs"`
+	Leaked     bool   `json:"leaked"`
+	Stack      string `json:"stack"`
+}
+
+// ConnectionPoolStats is a sample of the statistics of the database connection pool.
+type ConnectionPoolStats struct {
+	Enabled             bool  `json:"enabled"`
+	SampledAt           int64 `json:"sampledAt"`
+	MaxOpenConnections  int   `json:"maxOpenConnections"`
+	OpenConnections     int   `json:"openConnections"`
+	InUse               int   `json:"inUse"`
+	Idle                int   `json:"idle"`
+	WaitCount           int64 `json:"waitCount"`
+	WaitDurationMs      int64 `json:"waitDurationMs"`
+	MaxIdleClosed       int64 `json:"maxIdleClosed"`
+	MaxIdleTimeClosed   int64 `json:"maxIdleTimeClosed"`
+	MaxLifetimeClosed   int64 `json:"maxLifetimeClosed"`
+	WaitsSinceLastCheck int64 `json:"waitsSinceLastCheck"`
+}
+
+// HealthStatus is the health of a component or of the whole application.
+type HealthStatus string
+
+const (
+	// HealthUp means the component works normally.
+	HealthUp HealthStatus = "up"
+	// HealthDegraded means the component works but is close to its limits, e.g. a nearly exhausted connection pool.
+	HealthDegraded HealthStatus = "degraded"
+	// HealthDown means the component does not work.
+	HealthDown HealthStatus = "down"
+)
+
+// HealthReport is the health of the application, the worst health of its components.
+type HealthReport struct {
+	Status     HealthStatus                `json:"status"`
+	Components map[string]*ComponentHealth `json:"components"`
+}
+
+// ComponentHealth is the health of a component with the details it was derived from.
+type ComponentHealth struct {
+	Status  HealthStatus   `json:"status"`
+	Reason  string         `json:"reason,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
@@ -0,0 +1,87 @@
+// Package audit streams the changes the orm makes to the rows of auditable models to pluggable sinks: the
+// application log, the sys_audit_log table or the event bus, which relays them to message queues.
+//
+// Models opt in by implementing Auditable, columns opt out with the `audit:"-"` tag. The sink is set with
+// orm.SetAuditSink or provided to the application as an orm.AuditSink, e.g.
+//
+//	vef.Provide(func(bus event.Bus) orm.AuditSink { return audit.NewEventSink(bus) })
+package audit
+
+import (
+	"context"
+	"errors"
+
+	ilog "github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+var logger = ilog.Named("audit")
+
+type (
+	// Sink receives the audit events of the statements changing auditable models.
+	Sink = orm.AuditSink
+	// SinkFunc adapts a function to the Sink interface.
+	SinkFunc = orm.AuditSinkFunc
+	// TransactionalSink is a Sink writing the events with the transaction of the audited statement.
+	TransactionalSink = orm.TransactionalAuditSink
+	// Event records the change of a row of an auditable model.
+	Event = orm.AuditEvent
+	// Change is the change of a column.
+	Change = orm.AuditChange
+	// Operation is the kind of change an Event records.
+	Operation = orm.AuditOperation
+	// Auditable marks the models whose changes are audited.
+	Auditable = orm.Auditable
+)
+
+const (
+	OperationInsert = orm.AuditOperationInsert
+	OperationUpdate = orm.AuditOperationUpdate
+	OperationDelete = orm.AuditOperationDelete
+)
+
+// Multi returns a sink writing the events to all the sinks, joining their errors. Within the transaction of an
+// audited statement the transactional sinks write with it, the others once it commits.
+func Multi(sinks ...Sink) Sink {
+	return multiSink(sinks)
+}
+
+type multiSink []Sink
+
+func (m multiSink) Write(ctx context.Context, events []Event) error {
+	errs := make([]error, 0, len(m))
+	for _, sink := range m {
+		errs = append(errs, sink.Write(ctx, events))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m multiSink) WriteInTx(ctx context.Context, tx orm.DB, events []Event) error {
+	var (
+		errs     = make([]error, 0, len(m))
+		deferred multiSink
+	)
+
+	for _, sink := range m {
+		if txSink, ok := sink.(TransactionalSink); ok {
+			errs = append(errs, txSink.WriteInTx(ctx, tx, events))
+		} else {
+			deferred = append(deferred, sink)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	if len(deferred) > 0 {
+		orm.AfterCommit(ctx, func() {
+			if err := deferred.Write(ctx, events); err != nil {
+				logger.Errorf("Failed to write %d audit events of %s: %v", len(events), events[0].Table, err)
+			}
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/encoding"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+type auditedNote struct {
+	orm.BaseModel `bun:"table:test_audited_note,alias:tan"`
+	orm.IDModel
+
+	Title string `bun:",notnull"`
+}
+
+func (*auditedNote) Auditable() {}
+
+type publisherFunc func(event.Event)
+
+func (f publisherFunc) Publish(e event.Event) {
+	f(e)
+}
+
+type AuditTestSuite struct {
+	suite.Suite
+
+	ctx     context.Context
+	db      orm.DB
+	closeDB func() error
+}
+
+func (s *AuditTestSuite) SetupTest() {
+	s.ctx = contextx.SetRequestID(context.Background(), "req-1")
+
+	bunDB, err := database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	s.closeDB = bunDB.Close
+	s.db = iorm.New(bunDB)
+
+	for _, model := range []any{(*Entry)(nil), (*auditedNote)(nil)} {
+		_, err = bunDB.NewCreateTable().Model(model).Exec(s.ctx)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *AuditTestSuite) TearDownTest() {
+	orm.SetAuditSink(nil)
+	s.Require().NoError(s.closeDB())
+}
+
+func (s *AuditTestSuite) TestTableSink() {
+	orm.SetAuditSink(NewTableSink(s.db))
+
+	note := &auditedNote{Title: "Draft"}
+	_, err := s.db.NewInsert().Model(note).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	_, err = s.db.NewUpdate().Model(note).Set("title", "Final").WherePK().Exec(s.ctx)
+	s.Require().NoError(err)
+
+	var entries []Entry
+	s.Require().NoError(s.db.NewSelect().Model(&entries).OrderBy("changed_at", "operation").Scan(s.ctx))
+	s.Require().Len(entries, 2)
+
+	s.Equal(OperationInsert, entries[0].Operation)
+	s.Equal(OperationUpdate, entries[1].Operation)
+
+	for _, entry := range entries {
+		s.Equal("test_audited_note", entry.TableName)
+		s.Equal(constants.OperatorSystem, entry.Actor)
+		s.Equal("req-1", entry.RequestID, "Should record the request of the change")
+		s.JSONEq(`{"id":"`+note.ID+`"}`, entry.PK)
+	}
+
+	changes, err := encoding.FromJSON[map[string]Change](entries[1].Changes)
+	s.Require().NoError(err)
+	s.Equal(Change{Old: "Draft", New: "Final"}, (*changes)["title"])
+}
+
+func (s *AuditTestSuite) TestTableSinkTransaction() {
+	var published int
+
+	orm.SetAuditSink(Multi(NewTableSink(s.db), NewEventSink(publisherFunc(func(event.Event) {
+		published++
+	}))))
+
+	errRollback := errors.New("rollback")
+
+	err := s.db.RunInTX(s.ctx, func(ctx context.Context, tx orm.DB) error {
+		_, err := tx.NewInsert().Model(&auditedNote{Title: "Draft"}).Exec(ctx)
+		s.Require().NoError(err)
+
+		count, err := tx.NewSelect().Model((*Entry)(nil)).Count(ctx)
+		s.Require().NoError(err)
+		s.Equal(int64(1), count, "Should insert the entries with the transaction of the statement")
+		s.Zero(published, "Should publish the events once the transaction commits")
+
+		return errRollback
+	})
+	s.Require().ErrorIs(err, errRollback)
+
+	count, err := s.db.NewSelect().Model((*Entry)(nil)).Count(s.ctx)
+	s.Require().NoError(err)
+	s.Zero(count, "Should roll back the entries with the changes")
+	s.Zero(published, "Should not publish the events of rolled back changes")
+
+	_, err = s.db.NewInsert().Model(&auditedNote{Title: "Final"}).Exec(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(1, published, "Should publish the events of committed changes")
+}
+
+func (s *AuditTestSuite) TestEventSink() {
+	var published []*ChangeEvent
+
+	orm.SetAuditSink(NewEventSink(publisherFunc(func(e event.Event) {
+		published = append(published, e.(*ChangeEvent))
+	})))
+
+	_, err := s.db.NewInsert().Model(&[]auditedNote{{Title: "One"}, {Title: "Two"}}).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	s.Require().Len(published, 2, "Should publish an event per changed row")
+
+	for _, e := range published {
+		s.Equal(EventTypeChange, e.Type())
+		s.Equal("test_audited_note", e.Source())
+		s.Equal(OperationInsert, e.Operation)
+		s.Equal("req-1", e.RequestID)
+	}
+}
+
+func (s *AuditTestSuite) TestMulti() {
+	var written int
+
+	counter := SinkFunc(func(_ context.Context, events []Event) error {
+		written += len(events)
+
+		return nil
+	})
+	failing := SinkFunc(func(context.Context, []Event) error {
+		return errors.New("unavailable")
+	})
+
+	err := Multi(counter, failing, counter).Write(s.ctx, []Event{{Table: "t"}})
+	s.EqualError(err, "unavailable", "Should join the errors of the sinks")
+	s.Equal(2, written, "Should write to the sinks after a failing one")
+}
+
+func TestAudit(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/encoding"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/log"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// EventTypeChange is the type of the events published by the event sink, one per changed row.
+const EventTypeChange = "vef.orm.audit.change"
+
+// ChangeEvent is the event bus event of an audit event.
+type ChangeEvent struct {
+	event.BaseEvent
+	Event
+
+	RequestID string `json:"requestId"`
+}
+
+// NewLogSink returns a sink logging the events as JSON at info level.
+func NewLogSink(logger log.Logger) Sink {
+	return SinkFunc(func(_ context.Context, events []Event) error {
+		for _, event := range events {
+			data, err := encoding.ToJSON(event)
+			if err != nil {
+				return fmt.Errorf("failed to encode audit event of %s: %w", event.Table, err)
+			}
+
+			logger.Infof("Audit %s %s: %s", event.Operation, event.Table, data)
+		}
+
+		return nil
+	})
+}
+
+// NewEventSink returns a sink publishing the events as ChangeEvents, relayed to message queues by the
+// subscribers of EventTypeChange.
+func NewEventSink(publisher event.Publisher) Sink {
+	return SinkFunc(func(ctx context.Context, events []Event) error {
+		requestID := contextx.RequestID(ctx)
+
+		for _, e := range events {
+			publisher.Publish(&ChangeEvent{
+				BaseEvent: event.NewBaseEvent(EventTypeChange, event.WithSource(e.Table)),
+				Event:     e,
+				RequestID: requestID,
+			})
+		}
+
+		return nil
+	})
+}
+
+// Entry is a row of the sys_audit_log table, created by the migrations of the application like the tables of its
+// models, see orm/migrate.
+type Entry struct {
+	orm.BaseModel `bun:"table:sys_audit_log,alias:sal"`
+	orm.IDModel
+
+	TableName string            `json:"tableName" bun:",notnull"`
+	Operation Operation         `json:"operation" bun:",notnull"`
+	PK        string            `json:"pk"        bun:"pk,notnull"` // JSON-encoded primary key
+	Changes   string            `json:"changes"   bun:",notnull"`   // JSON-encoded changes
+	Actor     string            `json:"actor"     bun:",notnull"`
+	ChangedAt datetime.DateTime `json:"changedAt" bun:",notnull,type:timestamp"`
+	RequestID string            `json:"requestId"`
+}
+
+// NewTableSink returns a sink inserting the events into the sys_audit_log table of the database. Audited statements
+// insert the entries with their transaction, so that the entries commit and roll back with the changes.
+func NewTableSink(db orm.DB) Sink {
+	return &tableSink{db: db}
+}
+
+// tableSink inserts the events into the sys_audit_log table.
+type tableSink struct {
+	db orm.DB
+}
+
+func (s *tableSink) Write(ctx context.Context, events []Event) error {
+	return s.WriteInTx(ctx, s.db, events)
+}
+
+func (*tableSink) WriteInTx(ctx context.Context, tx orm.DB, events []Event) error {
+	requestID := contextx.RequestID(ctx)
+	entries := make([]Entry, len(events))
+
+	for i, event := range events {
+		pk, err := encoding.ToJSON(event.PK)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit event of %s: %w", event.Table, err)
+		}
+
+		changes, err := encoding.ToJSON(event.Changes)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit event of %s: %w", event.Table, err)
+		}
+
+		entries[i] = Entry{
+			TableName: event.Table,
+			Operation: event.Operation,
+			PK:        pk,
+			Changes:   changes,
+			Actor:     event.Actor,
+			ChangedAt: datetime.Of(event.At),
+			RequestID: requestID,
+		}
+	}
+
+	if _, err := tx.NewInsert().Model(&entries).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to insert audit entries: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package orm
+
+import "github.com/ilxqx/vef-framework-go/internal/database/iamauth"
+
+// AuthTokenSource issues the short-lived tokens datasources authenticate with instead of a password,
+// see the auth datasource setting. Identity providers beyond the built-in aws_rds_iam, gcp_cloud_sql_iam
+// and azure_ad are registered with RegisterAuthProvider before the database is created, e.g. Vault:
+//
+//	orm.RegisterAuthProvider("vault", func(cfg config.DatasourceAuthConfig, target orm.AuthTarget) (orm.AuthTokenSource, error) {
+//		return &vaultSource{role: target.User}, nil
+//	})
+type (
+	AuthToken           = iamauth.Token
+	AuthTokenSource     = iamauth.TokenSource
+	AuthTarget          = iamauth.Target
+	AuthProviderFactory = iamauth.Factory
+)
+
+const (
+	AuthProviderAWSRDS      = iamauth.AWSRDS
+	AuthProviderGCPCloudSQL = iamauth.GCPCloudSQL
+	AuthProviderAzureAD     = iamauth.AzureAD
+)
+
+var RegisterAuthProvider = iamauth.Register
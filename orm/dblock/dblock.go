@@ -0,0 +1,268 @@
+// Package dblock coordinates replicas through locks held in the database, e.g. so only one instance runs a
+// scheduled task or a migration, without running Redis.
+//
+// PostgreSQL takes session advisory locks and MySQL named locks with GET_LOCK. Both are held by a connection the
+// lock reserves from the pool until it is released or expires, and the database releases them when the connection
+// of a crashed instance closes. Other databases, SQLite in particular, record the locks in the vef_lock table,
+// created on first use, where locks past their expiry, judged by the clocks of the instances, are taken over.
+package dblock
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+
+	"github.com/ilxqx/vef-framework-go/dbhelpers"
+	"github.com/ilxqx/vef-framework-go/id"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// TableName is the table recording the locks on databases without native locks.
+const TableName = "vef_lock"
+
+// maxMySQLLockName is the longest name GET_LOCK accepts.
+const maxMySQLLockName = 64
+
+// Locker acquires locks on a database.
+type Locker struct {
+	db *bun.DB
+
+	tableOnce sync.Once
+	tableErr  error
+}
+
+// New creates a locker for the database.
+func New(db orm.DB) (*Locker, error) {
+	bunDB, ok := db.(*iorm.BunDB)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedDB, db)
+	}
+
+	sqlDB, ok := bunDB.Unwrap().(*bun.DB)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedDB, bunDB.Unwrap())
+	}
+
+	return &Locker{db: sqlDB}, nil
+}
+
+// AcquireLock takes the lock of the key for at most ttl without waiting, returning ErrLockHeld when another owner
+// holds it. Callers waiting for the lock retry on ErrLockHeld.
+func (l *Locker) AcquireLock(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	if ttl <= 0 {
+		return nil, ErrInvalidTTL
+	}
+
+	lock := &Lock{
+		locker:    l,
+		key:       key,
+		expiresAt: time.Now().Add(ttl),
+	}
+
+	var err error
+
+	switch l.db.Dialect().Name() {
+	case dialect.PG:
+		err = lock.acquireSession(ctx, "SELECT pg_try_advisory_lock(?)", "SELECT pg_advisory_unlock(?)", advisoryKey(key))
+	case dialect.MySQL:
+		err = lock.acquireSession(ctx, "SELECT GET_LOCK(?, 0) = 1", "SELECT RELEASE_LOCK(?)", mysqlLockName(key))
+	default:
+		err = lock.acquireRow(ctx)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if lock.conn != nil {
+		// Held until the timer is set, so an expiry racing with it finds the timer to stop
+		lock.mu.Lock()
+		lock.timer = time.AfterFunc(ttl, func() {
+			_ = lock.release(context.Background(), true)
+		})
+		lock.mu.Unlock()
+	}
+
+	return lock, nil
+}
+
+// ensureTable creates the lock table once per locker.
+func (l *Locker) ensureTable(ctx context.Context) error {
+	l.tableOnce.Do(func() {
+		_, l.tableErr = l.db.NewRaw(
+			"CREATE TABLE IF NOT EXISTS ? (lock_key VARCHAR(255) NOT NULL PRIMARY KEY, owner VARCHAR(64) NOT NULL, expires_at BIGINT NOT NULL)",
+			bun.Ident(TableName),
+		).Exec(ctx)
+	})
+
+	return l.tableErr
+}
+
+// Lock is a lock held on the database.
+type Lock struct {
+	locker    *Locker
+	key       string
+	expiresAt time.Time
+
+	// Session locks are held by the connection
+	conn        *bun.Conn
+	unlockQuery string
+	unlockArg   any
+	timer       *time.Timer
+
+	// Row locks are recorded under the owner
+	owner string
+
+	mu       sync.Mutex
+	released bool
+	// expired reports the expiry of a session lock released by its timer to the next Release
+	expired bool
+}
+
+// Key returns the key of the lock.
+func (l *Lock) Key() string {
+	return l.key
+}
+
+// ExpiresAt returns when the lock expires unless released before.
+func (l *Lock) ExpiresAt() time.Time {
+	return l.expiresAt
+}
+
+// Release releases the lock. It returns ErrLockExpired when the lock expired before, so the work it guarded may
+// have overlapped with another owner, and nil when it was released already.
+func (l *Lock) Release(ctx context.Context) error {
+	return l.release(ctx, false)
+}
+
+func (l *Lock) acquireSession(ctx context.Context, lockQuery, unlockQuery string, arg any) error {
+	conn, err := l.locker.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	var acquired bool
+	if err := conn.NewRaw(lockQuery, arg).Scan(ctx, &acquired); err != nil {
+		_ = conn.Close()
+
+		return fmt.Errorf("failed to acquire lock %s: %w", l.key, err)
+	}
+
+	if !acquired {
+		_ = conn.Close()
+
+		return fmt.Errorf("%w: %s", ErrLockHeld, l.key)
+	}
+
+	l.conn = &conn
+	l.unlockQuery = unlockQuery
+	l.unlockArg = arg
+
+	return nil
+}
+
+func (l *Lock) acquireRow(ctx context.Context) error {
+	if err := l.locker.ensureTable(ctx); err != nil {
+		return fmt.Errorf("failed to create lock table: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+	if _, err := l.locker.db.NewRaw("DELETE FROM ? WHERE lock_key = ? AND expires_at <= ?", bun.Ident(TableName), l.key, now).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("failed to acquire lock %s: %w", l.key, err)
+	}
+
+	l.owner = id.Generate()
+	if _, err := l.locker.db.NewRaw("INSERT INTO ? (lock_key, owner, expires_at) VALUES (?, ?, ?)",
+		bun.Ident(TableName), l.key, l.owner, l.expiresAt.UnixMilli()).Exec(ctx); err != nil {
+		if dbhelpers.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %s", ErrLockHeld, l.key)
+		}
+
+		return fmt.Errorf("failed to acquire lock %s: %w", l.key, err)
+	}
+
+	return nil
+}
+
+func (l *Lock) release(ctx context.Context, byTimer bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.released {
+		if l.expired && !byTimer {
+			l.expired = false
+
+			return fmt.Errorf("%w: %s", ErrLockExpired, l.key)
+		}
+
+		return nil
+	}
+
+	l.released = true
+	expired := byTimer || !time.Now().Before(l.expiresAt)
+
+	if l.conn != nil {
+		l.timer.Stop()
+
+		_, err := l.conn.NewRaw(l.unlockQuery, l.unlockArg).Exec(ctx)
+		// Closing the connection also releases the session locks the unlock failed to release
+		if closeErr := l.conn.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to release lock %s: %w", l.key, err)
+		}
+	} else {
+		res, err := l.locker.db.NewRaw("DELETE FROM ? WHERE lock_key = ? AND owner = ?", bun.Ident(TableName), l.key, l.owner).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to release lock %s: %w", l.key, err)
+		}
+
+		if deleted, err := res.RowsAffected(); err == nil && deleted == 0 {
+			// Taken over by another owner after it expired
+			expired = true
+		}
+	}
+
+	if byTimer {
+		l.expired = true
+
+		return nil
+	}
+
+	if expired {
+		return fmt.Errorf("%w: %s", ErrLockExpired, l.key)
+	}
+
+	return nil
+}
+
+// advisoryKey maps the key to the bigint keying PostgreSQL advisory locks.
+func advisoryKey(key string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(key))
+
+	return int64(hash.Sum64())
+}
+
+// mysqlLockName returns the key as the name of a MySQL lock, hashed when it is longer than GET_LOCK accepts.
+func mysqlLockName(key string) string {
+	if len(key) <= maxMySQLLockName {
+		return key
+	}
+
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:maxMySQLLockName/2])
+}
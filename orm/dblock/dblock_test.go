@@ -0,0 +1,101 @@
+package dblock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+type DBLockTestSuite struct {
+	suite.Suite
+
+	ctx     context.Context
+	db      orm.DB
+	locker  *Locker
+	closeDB func() error
+}
+
+func (s *DBLockTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	bunDB, err := database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	s.closeDB = bunDB.Close
+	s.db = iorm.New(bunDB)
+
+	s.locker, err = New(s.db)
+	s.Require().NoError(err)
+}
+
+func (s *DBLockTestSuite) TearDownTest() {
+	s.Require().NoError(s.closeDB())
+}
+
+func (s *DBLockTestSuite) TestAcquireAndRelease() {
+	lock, err := s.locker.AcquireLock(s.ctx, "report:daily", time.Minute)
+	s.Require().NoError(err)
+	s.Equal("report:daily", lock.Key())
+
+	other, err := New(s.db)
+	s.Require().NoError(err)
+
+	_, err = other.AcquireLock(s.ctx, "report:daily", time.Minute)
+	s.ErrorIs(err, ErrLockHeld, "Held lock should not be acquired by another locker")
+
+	otherLock, err := other.AcquireLock(s.ctx, "report:weekly", time.Minute)
+	s.Require().NoError(err, "Locks of other keys should be independent")
+	s.NoError(otherLock.Release(s.ctx))
+
+	s.Require().NoError(lock.Release(s.ctx))
+	s.NoError(lock.Release(s.ctx), "Releasing twice should be a no-op")
+
+	lock, err = other.AcquireLock(s.ctx, "report:daily", time.Minute)
+	s.Require().NoError(err, "Released lock should be acquired again")
+	s.NoError(lock.Release(s.ctx))
+}
+
+func (s *DBLockTestSuite) TestExpiry() {
+	lock, err := s.locker.AcquireLock(s.ctx, "cleanup", 50*time.Millisecond)
+	s.Require().NoError(err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	next, err := s.locker.AcquireLock(s.ctx, "cleanup", time.Minute)
+	s.Require().NoError(err, "Expired lock should be taken over")
+
+	s.ErrorIs(lock.Release(s.ctx), ErrLockExpired)
+
+	_, err = s.locker.AcquireLock(s.ctx, "cleanup", time.Minute)
+	s.ErrorIs(err, ErrLockHeld, "Releasing the expired lock should keep the lock of the new owner")
+	s.NoError(next.Release(s.ctx))
+}
+
+func (s *DBLockTestSuite) TestInvalidTTL() {
+	_, err := s.locker.AcquireLock(s.ctx, "cleanup", 0)
+	s.ErrorIs(err, ErrInvalidTTL)
+}
+
+func (s *DBLockTestSuite) TestUnsupportedDB() {
+	_, err := New(nil)
+	s.ErrorIs(err, ErrUnsupportedDB)
+
+	err = s.db.RunInTX(s.ctx, func(_ context.Context, tx orm.DB) error {
+		_, err := New(tx)
+
+		return err
+	})
+	s.ErrorIs(err, ErrUnsupportedDB, "Locks should not be held by transactions")
+}
+
+func TestDBLock(t *testing.T) {
+	suite.Run(t, new(DBLockTestSuite))
+}
@@ -0,0 +1,14 @@
+package dblock
+
+import "errors"
+
+var (
+	// ErrUnsupportedDB indicates the database is not backed by the framework orm or is a transaction.
+	ErrUnsupportedDB = errors.New("locks require a database created by the orm, outside transactions")
+	// ErrInvalidTTL indicates a lock was requested without a positive time to live.
+	ErrInvalidTTL = errors.New("lock ttl must be positive")
+	// ErrLockHeld is returned when the lock is held by another owner.
+	ErrLockHeld = errors.New("lock is held by another owner")
+	// ErrLockExpired is returned when releasing a lock that expired before, so others may have held it meanwhile.
+	ErrLockExpired = errors.New("lock expired before it was released")
+)
@@ -0,0 +1,16 @@
+package filter
+
+import "errors"
+
+var (
+	// ErrInvalidFilter indicates a filter that is not exactly one of a group, a negation or a field condition.
+	ErrInvalidFilter = errors.New("invalid filter")
+	// ErrUnknownField indicates a condition on a field the schema does not allow filtering on.
+	ErrUnknownField = errors.New("filter field not allowed")
+	// ErrOperatorNotAllowed indicates an operator unknown or not allowed on the field.
+	ErrOperatorNotAllowed = errors.New("filter operator not allowed")
+	// ErrInvalidValue indicates a value the operator does not accept.
+	ErrInvalidValue = errors.New("invalid filter value")
+	// ErrTooComplex indicates a filter nested deeper or holding more conditions than the schema allows.
+	ErrTooComplex = errors.New("filter too complex")
+)
@@ -0,0 +1,121 @@
+// Package filter turns filters sent by clients as JSON into conditions, so list APIs let clients filter freely
+// without exposing more than the fields they choose:
+//
+//	{"and": [
+//	  {"field": "age", "op": "gte", "value": 18},
+//	  {"or": [
+//	    {"field": "name", "op": "contains", "value": "li"},
+//	    {"not": {"field": "status", "op": "in", "value": ["banned", "locked"]}}
+//	  ]}
+//	]}
+//
+// A filter is a group of filters under "and" or "or", a negated filter under "not" or a condition on a field.
+// Conditions take the operators of the search package:
+//
+//   - eq, neq, gt, gte, lt and lte compare with a string, number or boolean
+//   - between and notBetween take an array of the start and the end
+//   - in and notIn take a non-empty array
+//   - isNull and isNotNull take no value
+//   - contains, startsWith, endsWith, their negations notContains, notStartsWith and notEndsWith, and their
+//     case-insensitive variants prefixed with i take a non-empty string
+//
+// A Schema lists the fields clients may filter on, the columns they map to and the operators allowed on them.
+// Filters naming any other field or operator are rejected, and values are always bound as query arguments.
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ilxqx/vef-framework-go/search"
+)
+
+// Filter is a filter decoded from JSON, which holds exactly one of And, Or, Not or a condition on Field.
+// Numbers of values decode to int64 when integral and float64 otherwise.
+type Filter struct {
+	And   []Filter        `json:"and,omitempty"`
+	Or    []Filter        `json:"or,omitempty"`
+	Not   *Filter         `json:"not,omitempty"`
+	Field string          `json:"field,omitempty"`
+	Op    search.Operator `json:"op,omitempty"`
+	Value any             `json:"value,omitempty"`
+}
+
+// UnmarshalJSON decodes the filter, rejecting unknown keys.
+func (f *Filter) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		And   []Filter        `json:"and"`
+		Or    []Filter        `json:"or"`
+		Not   *Filter         `json:"not"`
+		Field string          `json:"field"`
+		Op    search.Operator `json:"op"`
+		Value json.RawMessage `json:"value"`
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&raw); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+
+	*f = Filter{
+		And:   raw.And,
+		Or:    raw.Or,
+		Not:   raw.Not,
+		Field: raw.Field,
+		Op:    raw.Op,
+	}
+
+	if len(raw.Value) == 0 {
+		return nil
+	}
+
+	decoder = json.NewDecoder(bytes.NewReader(raw.Value))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&f.Value); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+
+	f.Value = normalizeNumbers(f.Value)
+
+	return nil
+}
+
+// Parse decodes a filter from JSON.
+func Parse(data []byte) (*Filter, error) {
+	var f Filter
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// normalizeNumbers converts the numbers of the decoded value to int64 when integral and float64 otherwise.
+func normalizeNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+
+		if n, err := v.Float64(); err == nil {
+			return n
+		}
+
+		return v.String()
+	case []any:
+		for i, element := range v {
+			v[i] = normalizeNumbers(element)
+		}
+	case map[string]any:
+		for key, element := range v {
+			v[key] = normalizeNumbers(element)
+		}
+	}
+
+	return value
+}
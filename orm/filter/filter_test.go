@@ -0,0 +1,185 @@
+package filter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/search"
+)
+
+type member struct {
+	orm.BaseModel `bun:"table:test_filter_member,alias:tfm"`
+	orm.IDModel
+
+	Name     string  `bun:",notnull"`
+	Age      int     `bun:",notnull"`
+	Status   string  `bun:",notnull"`
+	Note     *string `bun:"note"`
+	JoinedAt *string `json:"joinedOn" bun:"joined_at"`
+}
+
+type FilterTestSuite struct {
+	suite.Suite
+
+	ctx     context.Context
+	db      orm.DB
+	closeDB func() error
+	schema  *Schema
+}
+
+func (s *FilterTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	bunDB, err := database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	s.closeDB = bunDB.Close
+	s.db = iorm.New(bunDB)
+
+	_, err = bunDB.NewCreateTable().Model((*member)(nil)).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	note := "vip"
+	_, err = s.db.NewInsert().Model(&[]member{
+		{Name: "Alice", Age: 17, Status: "active"},
+		{Name: "Bob", Age: 25, Status: "banned"},
+		{Name: "Carol", Age: 32, Status: "active", Note: &note},
+		{Name: "Dave", Age: 45, Status: "locked"},
+	}).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	s.schema = NewSchema(map[string]Field{
+		"name":   {},
+		"age":    {Column: "tfm.age"},
+		"status": {Operators: []search.Operator{search.Equals, search.In, search.NotIn}},
+		"note":   {},
+	})
+}
+
+func (s *FilterTestSuite) TearDownTest() {
+	s.Require().NoError(s.closeDB())
+}
+
+func (s *FilterTestSuite) names(payload string) []string {
+	apply, err := s.schema.Parse([]byte(payload))
+	s.Require().NoError(err)
+
+	var names []string
+
+	err = s.db.NewSelect().
+		Model((*member)(nil)).
+		Select("name").
+		Where(apply).
+		OrderBy("name").
+		Scan(s.ctx, &names)
+	s.Require().NoError(err)
+
+	return names
+}
+
+func (s *FilterTestSuite) TestConditions() {
+	s.Equal([]string{"Bob", "Carol", "Dave"}, s.names(`{"field":"age","op":"gte","value":18}`))
+	s.Equal([]string{"Bob", "Carol"}, s.names(`{"field":"age","op":"between","value":[18,40]}`))
+	s.Equal([]string{"Alice", "Carol"}, s.names(`{"field":"status","op":"in","value":["active"]}`))
+	s.Equal([]string{"Carol"}, s.names(`{"field":"note","op":"isNotNull"}`))
+	s.Equal([]string{"Alice", "Carol", "Dave"}, s.names(`{"field":"name","op":"iContains","value":"A"}`))
+}
+
+func (s *FilterTestSuite) TestGroups() {
+	names := s.names(`{"and":[
+		{"field":"age","op":"gte","value":18},
+		{"or":[
+			{"field":"name","op":"startsWith","value":"C"},
+			{"not":{"field":"status","op":"in","value":["banned","locked"]}}
+		]}
+	]}`)
+	s.Equal([]string{"Carol"}, names, "Should combine groups and negations")
+
+	s.Equal([]string{"Alice", "Dave"}, s.names(`{"or":[
+		{"field":"age","op":"lt","value":18},
+		{"field":"status","op":"eq","value":"locked"}
+	]}`))
+}
+
+func (s *FilterTestSuite) TestNilFilter() {
+	apply, err := s.schema.Build(nil)
+	s.Require().NoError(err)
+
+	count, err := s.db.NewSelect().Model((*member)(nil)).Where(apply).Count(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(4), count, "Should add no conditions")
+}
+
+func (s *FilterTestSuite) TestValues() {
+	f, err := Parse([]byte(`{"field":"age","op":"in","value":[18,2.5,"x",true]}`))
+	s.Require().NoError(err)
+	s.Equal([]any{int64(18), 2.5, "x", true}, f.Value, "Should decode integral numbers as int64")
+}
+
+func (s *FilterTestSuite) TestRejected() {
+	cases := []struct {
+		name    string
+		payload string
+		err     error
+	}{
+		{"UnknownField", `{"field":"password","op":"eq","value":"x"}`, ErrUnknownField},
+		{"OperatorNotAllowed", `{"field":"status","op":"contains","value":"act"}`, ErrOperatorNotAllowed},
+		{"UnknownOperator", `{"field":"name","op":"regex","value":".*"}`, ErrOperatorNotAllowed},
+		{"UnknownKey", `{"field":"name","operator":"eq","value":"x"}`, ErrInvalidFilter},
+		{"SeveralKinds", `{"field":"name","op":"eq","value":"x","and":[]}`, ErrInvalidFilter},
+		{"EmptyGroup", `{"or":[]}`, ErrInvalidFilter},
+		{"ObjectValue", `{"field":"name","op":"eq","value":{"$ne":1}}`, ErrInvalidValue},
+		{"BetweenBounds", `{"field":"age","op":"between","value":[1]}`, ErrInvalidValue},
+		{"EmptyIn", `{"field":"status","op":"in","value":[]}`, ErrInvalidValue},
+		{"NullValue", `{"field":"note","op":"isNull","value":"x"}`, ErrInvalidValue},
+		{"EmptyPattern", `{"field":"name","op":"contains","value":""}`, ErrInvalidValue},
+	}
+
+	for _, c := range cases {
+		s.Run(c.name, func() {
+			_, err := s.schema.Parse([]byte(c.payload))
+			s.ErrorIs(err, c.err)
+		})
+	}
+}
+
+func (s *FilterTestSuite) TestLimits() {
+	schema := NewSchema(map[string]Field{"age": {}}, WithMaxDepth(2), WithMaxConditions(2))
+
+	_, err := schema.Parse([]byte(`{"not":{"not":{"field":"age","op":"eq","value":1}}}`))
+	s.ErrorIs(err, ErrTooComplex, "Should limit the nesting")
+
+	_, err = schema.Parse([]byte(`{"or":[
+		{"field":"age","op":"eq","value":1},
+		{"field":"age","op":"eq","value":2},
+		{"field":"age","op":"eq","value":3}
+	]}`))
+	s.ErrorIs(err, ErrTooComplex, "Should limit the conditions")
+}
+
+func (s *FilterTestSuite) TestSchemaFor() {
+	schema, err := NewSchemaFor[member]([]string{"Name", "joinedOn"})
+	s.Require().NoError(err)
+
+	s.schema = schema
+	s.Equal([]string{"Alice", "Bob", "Carol", "Dave"}, s.names(`{"field":"joinedOn","op":"isNull"}`),
+		"Api fields should map to their columns")
+	s.Equal([]string{"Bob"}, s.names(`{"field":"Name","op":"eq","value":"Bob"}`))
+
+	_, err = schema.Parse([]byte(`{"field":"Age","op":"eq","value":1}`))
+	s.ErrorIs(err, ErrUnknownField, "Fields not listed should be rejected")
+
+	_, err = NewSchemaFor[member]([]string{"joined_at"})
+	s.ErrorIs(err, ErrUnknownField, "Fields the model does not map should be rejected")
+}
+
+func TestFilter(t *testing.T) {
+	suite.Run(t, new(FilterTestSuite))
+}
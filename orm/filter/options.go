@@ -0,0 +1,18 @@
+package filter
+
+// Option configures a Schema.
+type Option func(*Schema)
+
+// WithMaxDepth sets how deep groups and negations may nest, 5 by default.
+func WithMaxDepth(depth int) Option {
+	return func(s *Schema) {
+		s.maxDepth = depth
+	}
+}
+
+// WithMaxConditions sets how many field conditions a filter may hold, 50 by default.
+func WithMaxConditions(conditions int) Option {
+	return func(s *Schema) {
+		s.maxConditions = conditions
+	}
+}
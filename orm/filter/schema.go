@@ -0,0 +1,305 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/fieldmap"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/search"
+)
+
+const (
+	defaultMaxDepth      = 5
+	defaultMaxConditions = 50
+)
+
+// Field describes a field clients may filter on.
+type Field struct {
+	// Column is the column of the field, which may be qualified by a table alias; the name of the field by default.
+	Column string
+	// Operators are the operators allowed on the field, all by default.
+	Operators []search.Operator
+}
+
+// Schema lists the fields clients may filter on and builds the conditions of their filters.
+type Schema struct {
+	fields        map[string]Field
+	maxDepth      int
+	maxConditions int
+}
+
+// NewSchema creates a schema allowing filters on the fields, keyed by the names clients use.
+func NewSchema(fields map[string]Field, opts ...Option) *Schema {
+	s := &Schema{
+		fields:        fields,
+		maxDepth:      defaultMaxDepth,
+		maxConditions: defaultMaxConditions,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// NewSchemaFor creates a schema allowing filters with all operators on the Api fields of model T, mapped to their
+// columns through the field mapping of the model. It fails with ErrUnknownField on fields the model does not map.
+func NewSchemaFor[T any](fields []string, opts ...Option) (*Schema, error) {
+	columns, unknown := fieldmap.For[T]().Columns(fields...)
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownField, strings.Join(unknown, constants.CommaSpace))
+	}
+
+	mapped := make(map[string]Field, len(fields))
+	for i, field := range fields {
+		mapped[field] = Field{Column: columns[i]}
+	}
+
+	return NewSchema(mapped, opts...), nil
+}
+
+// condition applies a validated filter to a condition builder.
+type condition func(orm.ConditionBuilder)
+
+// Build validates the filter and returns the conditions it describes, to pass to Where. A nil filter adds no
+// conditions.
+func (s *Schema) Build(f *Filter) (orm.ApplyFunc[orm.ConditionBuilder], error) {
+	if f == nil {
+		return func(orm.ConditionBuilder) {}, nil
+	}
+
+	var conditions int
+
+	apply, err := s.build(f, 1, &conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(cb orm.ConditionBuilder) {
+		cb.Group(apply)
+	}, nil
+}
+
+// Parse decodes the filter from JSON and builds its conditions.
+func (s *Schema) Parse(data []byte) (orm.ApplyFunc[orm.ConditionBuilder], error) {
+	f, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Build(f)
+}
+
+func (s *Schema) build(f *Filter, depth int, conditions *int) (condition, error) {
+	if depth > s.maxDepth {
+		return nil, fmt.Errorf("%w: nested deeper than %d", ErrTooComplex, s.maxDepth)
+	}
+
+	var kinds int
+	for _, set := range []bool{f.And != nil, f.Or != nil, f.Not != nil, f.Field != constants.Empty} {
+		if set {
+			kinds++
+		}
+	}
+
+	if kinds != 1 {
+		return nil, fmt.Errorf("%w: a filter holds exactly one of and, or, not or field", ErrInvalidFilter)
+	}
+
+	switch {
+	case f.And != nil:
+		children, err := s.buildAll(f.And, "and", depth, conditions)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(cb orm.ConditionBuilder) {
+			for _, child := range children {
+				cb.Group(child)
+			}
+		}, nil
+
+	case f.Or != nil:
+		children, err := s.buildAll(f.Or, "or", depth, conditions)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(cb orm.ConditionBuilder) {
+			for _, child := range children {
+				cb.OrGroup(child)
+			}
+		}, nil
+
+	case f.Not != nil:
+		child, err := s.build(f.Not, depth+1, conditions)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(cb orm.ConditionBuilder) {
+			cb.NotGroup(child)
+		}, nil
+
+	default:
+		if *conditions++; *conditions > s.maxConditions {
+			return nil, fmt.Errorf("%w: more than %d conditions", ErrTooComplex, s.maxConditions)
+		}
+
+		return s.buildField(f)
+	}
+}
+
+func (s *Schema) buildAll(filters []Filter, kind string, depth int, conditions *int) ([]condition, error) {
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("%w: %s holds no filters", ErrInvalidFilter, kind)
+	}
+
+	children := make([]condition, len(filters))
+	for i := range filters {
+		child, err := s.build(&filters[i], depth+1, conditions)
+		if err != nil {
+			return nil, err
+		}
+
+		children[i] = child
+	}
+
+	return children, nil
+}
+
+func (s *Schema) buildField(f *Filter) (condition, error) {
+	field, ok := s.fields[f.Field]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownField, f.Field)
+	}
+
+	if len(field.Operators) > 0 && !slices.Contains(field.Operators, f.Op) {
+		return nil, fmt.Errorf("%w: %s on %s", ErrOperatorNotAllowed, f.Op, f.Field)
+	}
+
+	column := field.Column
+	if column == constants.Empty {
+		column = f.Field
+	}
+
+	apply, err := buildCondition(column, f.Op, f.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", f.Field, f.Op, err)
+	}
+
+	return apply, nil
+}
+
+// buildCondition maps the operator to the condition builder method applied to the column and the value.
+func buildCondition(column string, op search.Operator, value any) (condition, error) {
+	switch op {
+	case search.Equals, search.NotEquals, search.GreaterThan, search.GreaterThanOrEqual,
+		search.LessThan, search.LessThanOrEqual:
+		if !isScalar(value) {
+			return nil, fmt.Errorf("%w: expected a string, number or boolean", ErrInvalidValue)
+		}
+
+		return func(cb orm.ConditionBuilder) {
+			switch op {
+			case search.Equals:
+				cb.Equals(column, value)
+			case search.NotEquals:
+				cb.NotEquals(column, value)
+			case search.GreaterThan:
+				cb.GreaterThan(column, value)
+			case search.GreaterThanOrEqual:
+				cb.GreaterThanOrEqual(column, value)
+			case search.LessThan:
+				cb.LessThan(column, value)
+			case search.LessThanOrEqual:
+				cb.LessThanOrEqual(column, value)
+			}
+		}, nil
+
+	case search.Between, search.NotBetween:
+		bounds, ok := value.([]any)
+		if !ok || len(bounds) != 2 || !isScalar(bounds[0]) || !isScalar(bounds[1]) {
+			return nil, fmt.Errorf("%w: expected an array of the start and the end", ErrInvalidValue)
+		}
+
+		return func(cb orm.ConditionBuilder) {
+			if op == search.Between {
+				cb.Between(column, bounds[0], bounds[1])
+			} else {
+				cb.NotBetween(column, bounds[0], bounds[1])
+			}
+		}, nil
+
+	case search.In, search.NotIn:
+		values, ok := value.([]any)
+		if !ok || len(values) == 0 || slices.ContainsFunc(values, func(v any) bool { return !isScalar(v) }) {
+			return nil, fmt.Errorf("%w: expected a non-empty array of strings, numbers or booleans", ErrInvalidValue)
+		}
+
+		return func(cb orm.ConditionBuilder) {
+			if op == search.In {
+				cb.In(column, values)
+			} else {
+				cb.NotIn(column, values)
+			}
+		}, nil
+
+	case search.IsNull, search.IsNotNull:
+		if value != nil {
+			return nil, fmt.Errorf("%w: expected no value", ErrInvalidValue)
+		}
+
+		return func(cb orm.ConditionBuilder) {
+			if op == search.IsNull {
+				cb.IsNull(column)
+			} else {
+				cb.IsNotNull(column)
+			}
+		}, nil
+	}
+
+	like, ok := likeMethods[op]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrOperatorNotAllowed, op)
+	}
+
+	content, ok := value.(string)
+	if !ok || content == constants.Empty {
+		return nil, fmt.Errorf("%w: expected a non-empty string", ErrInvalidValue)
+	}
+
+	return func(cb orm.ConditionBuilder) {
+		like(cb, column, content)
+	}, nil
+}
+
+// likeMethods maps the pattern operators to their condition builder methods.
+var likeMethods = map[search.Operator]func(cb orm.ConditionBuilder, column, content string) orm.ConditionBuilder{
+	search.Contains:                orm.ConditionBuilder.Contains,
+	search.NotContains:             orm.ConditionBuilder.NotContains,
+	search.StartsWith:              orm.ConditionBuilder.StartsWith,
+	search.NotStartsWith:           orm.ConditionBuilder.NotStartsWith,
+	search.EndsWith:                orm.ConditionBuilder.EndsWith,
+	search.NotEndsWith:             orm.ConditionBuilder.NotEndsWith,
+	search.ContainsIgnoreCase:      orm.ConditionBuilder.ContainsIgnoreCase,
+	search.NotContainsIgnoreCase:   orm.ConditionBuilder.NotContainsIgnoreCase,
+	search.StartsWithIgnoreCase:    orm.ConditionBuilder.StartsWithIgnoreCase,
+	search.NotStartsWithIgnoreCase: orm.ConditionBuilder.NotStartsWithIgnoreCase,
+	search.EndsWithIgnoreCase:      orm.ConditionBuilder.EndsWithIgnoreCase,
+	search.NotEndsWithIgnoreCase:   orm.ConditionBuilder.NotEndsWithIgnoreCase,
+}
+
+// isScalar reports whether the decoded value is a string, number or boolean.
+func isScalar(value any) bool {
+	switch value.(type) {
+	case string, int64, float64, bool:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,27 @@
+package orm
+
+import "github.com/ilxqx/vef-framework-go/internal/database/jsoncodec"
+
+// JSONCodec encodes model JSON fields and map/struct bind parameters, see the json_codec datasource setting.
+// Codecs backed by other libraries are registered with RegisterJSONCodec before the database is created, e.g. go-json:
+//
+//	type goJSONCodec struct{}
+//
+//	func (goJSONCodec) Marshal(v any) ([]byte, error)          { return gojson.Marshal(v) }
+//	func (goJSONCodec) Unmarshal(data []byte, v any) error     { return gojson.Unmarshal(data, v) }
+//	func (goJSONCodec) NewEncoder(w io.Writer) orm.JSONEncoder { return gojson.NewEncoder(w) }
+//	func (goJSONCodec) NewDecoder(r io.Reader) orm.JSONDecoder { return gojson.NewDecoder(r) }
+//
+//	orm.RegisterJSONCodec("go-json", goJSONCodec{})
+type (
+	JSONCodec   = jsoncodec.Codec
+	JSONEncoder = jsoncodec.Encoder
+	JSONDecoder = jsoncodec.Decoder
+)
+
+const (
+	JSONCodecStd   = jsoncodec.Std
+	JSONCodecSonic = jsoncodec.Sonic
+)
+
+var RegisterJSONCodec = jsoncodec.Register
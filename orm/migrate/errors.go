@@ -0,0 +1,6 @@
+package migrate
+
+import "errors"
+
+// ErrUnsupportedDB indicates the database is not backed by the framework orm.
+var ErrUnsupportedDB = errors.New("migrations require a database created by the orm")
@@ -0,0 +1,563 @@
+// Package migrate keeps the database schema in line with the registered models.
+//
+// A Migrator compares the models with the live schema reported by the schema service and plans the statements
+// creating what is missing: tables, columns, unique constraints, indexes declared through index and exprindex
+// tags and, on request, foreign keys. The comments declared through comment tags are set on the tables and columns
+// it creates, on PostgreSQL and MySQL. Changes are additive only, columns and indexes the models no longer declare
+// and columns whose type changed are left for hand-written migrations.
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/sqltype"
+	bunschema "github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	ilog "github.com/ilxqx/vef-framework-go/internal/log"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/log"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/schema"
+)
+
+// TagIndex is the struct tag declaring a plain index on a column. The value names the index,
+// idx_<table>_<column> by default; columns sharing a name form a composite index in field order.
+// Example: `index:""` or `index:"idx_order_customer_date"`.
+const TagIndex = "index"
+
+// Migrator plans and applies the schema changes bringing the database in line with the registered models.
+type Migrator struct {
+	db          orm.DB
+	bunDB       bun.IDB
+	service     schema.Service
+	models      []any
+	publisher   event.Publisher
+	dryRun      io.Writer
+	foreignKeys bool
+	logger      log.Logger
+}
+
+// New creates a migrator for the database, reading the live schema from the schema service.
+func New(db orm.DB, service schema.Service, opts ...Option) (*Migrator, error) {
+	bunDB, ok := db.(*iorm.BunDB)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedDB, db)
+	}
+
+	m := &Migrator{
+		db:      db,
+		bunDB:   bunDB.Unwrap(),
+		service: service,
+		logger:  ilog.Named("migrate"),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m, nil
+}
+
+// Register adds models whose tables the migrator maintains.
+func (m *Migrator) Register(models ...any) *Migrator {
+	m.models = append(m.models, models...)
+
+	return m
+}
+
+// Plan compares the registered models with the live schema and returns the changes to apply,
+// tables created first with the tables they reference before them.
+func (m *Migrator) Plan(ctx context.Context) (*Plan, error) {
+	tables, err := m.service.ListTables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: failed to list tables: %w", err)
+	}
+
+	existing := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		existing[strings.ToLower(table.Name)] = true
+	}
+
+	var (
+		created []*bunschema.Table
+		altered []Change
+	)
+
+	for _, model := range m.models {
+		table := m.db.TableOf(model)
+		if !existing[strings.ToLower(table.Name)] {
+			created = append(created, table)
+
+			continue
+		}
+
+		live, err := m.service.GetTableSchema(ctx, table.Name)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: failed to inspect table %s: %w", table.Name, err)
+		}
+
+		changes, err := m.alterTable(table, live)
+		if err != nil {
+			return nil, err
+		}
+
+		altered = append(altered, changes...)
+	}
+
+	plan := &Plan{Changes: make([]Change, 0)}
+
+	for _, table := range sortByReferences(created) {
+		changes, err := m.createTable(table)
+		if err != nil {
+			return nil, err
+		}
+
+		plan.Changes = append(plan.Changes, changes...)
+	}
+
+	plan.Changes = append(plan.Changes, altered...)
+
+	return plan, nil
+}
+
+// Apply plans the changes and executes them in order, or writes them to the dry-run writer.
+// Executed changes stay applied when a later one fails, since not all databases roll back schema changes.
+func (m *Migrator) Apply(ctx context.Context) (*Plan, error) {
+	plan, err := m.Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.dryRun != nil {
+		if _, err := io.WriteString(m.dryRun, plan.SQL()); err != nil {
+			return nil, fmt.Errorf("migrate: failed to write dry run: %w", err)
+		}
+
+		return plan, nil
+	}
+
+	if plan.IsEmpty() {
+		return plan, nil
+	}
+
+	for i, change := range plan.Changes {
+		if _, err := m.db.NewRaw(change.SQL).Exec(ctx); err != nil {
+			m.publishChanged(&Plan{Changes: plan.Changes[:i]})
+
+			return nil, fmt.Errorf("migrate: failed to %s %s: %w", strings.ReplaceAll(string(change.Kind), "_", " "), change.Table, err)
+		}
+
+		m.logger.Infof("Applied %s on %s: %s", change.Kind, change.Table, change.SQL)
+	}
+
+	m.publishChanged(plan)
+
+	return plan, nil
+}
+
+func (m *Migrator) publishChanged(plan *Plan) {
+	if m.publisher != nil && !plan.IsEmpty() {
+		schema.PublishSchemaChangedEvent(m.publisher, plan.Tables()...)
+	}
+}
+
+// createTable plans the creation of a table with its indexes.
+// Unique constraints and, with foreign keys enabled, foreign keys are part of the table definition.
+func (m *Migrator) createTable(table *bunschema.Table) ([]Change, error) {
+	query := m.bunDB.NewCreateTable().Model(table.ZeroIface)
+	if m.foreignKeys {
+		query.WithForeignKeys()
+	}
+
+	changes := []Change{{
+		Kind:  ChangeCreateTable,
+		Table: table.Name,
+		SQL:   query.String(),
+	}}
+
+	indexes, err := m.indexChanges(table, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	changes = append(changes, indexes...)
+
+	if comment := orm.TableCommentOf(table); comment != constants.Empty {
+		if change, ok := m.tableComment(table, comment); ok {
+			changes = append(changes, change)
+		}
+	}
+
+	for _, field := range table.Fields {
+		if comment := orm.ColumnCommentOf(field); comment != constants.Empty {
+			if change, ok := m.columnComment(table, field, comment); ok {
+				changes = append(changes, change)
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// alterTable plans the columns, unique constraints, indexes and foreign keys missing from an existing table.
+func (m *Migrator) alterTable(table *bunschema.Table, live *schema.TableSchema) ([]Change, error) {
+	var changes []Change
+
+	for _, field := range table.Fields {
+		if slices.ContainsFunc(live.Columns, func(c schema.Column) bool { return strings.EqualFold(c.Name, field.Name) }) {
+			continue
+		}
+
+		// MySQL declares comments inline, the other databases set them apart.
+		comment := orm.ColumnCommentOf(field)
+		inline := comment != constants.Empty && m.bunDB.Dialect().Name() == dialect.MySQL
+
+		column := m.bunDB.NewAddColumn().Model(table.ZeroIface)
+		if inline {
+			column.ColumnExpr("? ? COMMENT ?", bun.Ident(field.Name), bun.Safe(m.columnDefinition(field)), comment)
+		} else {
+			column.ColumnExpr("? ?", bun.Ident(field.Name), bun.Safe(m.columnDefinition(field)))
+		}
+
+		changes = append(changes, Change{
+			Kind:  ChangeAddColumn,
+			Table: table.Name,
+			Name:  field.Name,
+			SQL:   m.sql(column),
+		})
+
+		if comment != constants.Empty && !inline {
+			if change, ok := m.columnComment(table, field, comment); ok {
+				changes = append(changes, change)
+			}
+		}
+	}
+
+	for _, unique := range uniqueKeys(table) {
+		if hasKey(live, unique.columns) {
+			continue
+		}
+
+		changes = append(changes, Change{
+			Kind:  ChangeCreateIndex,
+			Table: table.Name,
+			Name:  unique.name,
+			SQL: m.sql(m.bunDB.NewCreateIndex().
+				Model(table.ZeroIface).
+				Unique().
+				Index(unique.name).
+				Column(unique.columns...)),
+		})
+	}
+
+	indexes, err := m.indexChanges(table, live)
+	if err != nil {
+		return nil, err
+	}
+
+	changes = append(changes, indexes...)
+
+	if m.foreignKeys {
+		changes = append(changes, m.foreignKeyChanges(table, live)...)
+	}
+
+	return changes, nil
+}
+
+// indexChanges plans the indexes declared through index and exprindex tags missing from the live table,
+// all of them for tables about to be created.
+func (m *Migrator) indexChanges(table *bunschema.Table, live *schema.TableSchema) ([]Change, error) {
+	var changes []Change
+
+	for _, index := range taggedIndexes(table) {
+		if live != nil && (hasIndex(live, index.name) || hasKey(live, index.columns)) {
+			continue
+		}
+
+		changes = append(changes, Change{
+			Kind:  ChangeCreateIndex,
+			Table: table.Name,
+			Name:  index.name,
+			SQL: m.sql(m.bunDB.NewCreateIndex().
+				Model(table.ZeroIface).
+				Index(index.name).
+				Column(index.columns...)),
+		})
+	}
+
+	for _, index := range orm.ExpressionIndexesOf(table) {
+		if live != nil && hasIndex(live, index.Name) {
+			continue
+		}
+
+		query, err := orm.ExpressionIndexSQL(m.db, index)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: index %s: %w", index.Name, err)
+		}
+
+		changes = append(changes, Change{
+			Kind:  ChangeCreateIndex,
+			Table: table.Name,
+			Name:  index.Name,
+			SQL:   query,
+		})
+	}
+
+	return changes, nil
+}
+
+// foreignKeyChanges plans the foreign keys of the belongs-to relations missing from the live table.
+func (m *Migrator) foreignKeyChanges(table *bunschema.Table, live *schema.TableSchema) []Change {
+	var changes []Change
+
+	for _, rel := range sortedRelations(table) {
+		columns := fieldNames(rel.BasePKs)
+		if slices.ContainsFunc(live.ForeignKeys, func(fk schema.ForeignKey) bool { return sameColumns(fk.Columns, columns) }) {
+			continue
+		}
+
+		if m.bunDB.Dialect().Name() == dialect.SQLite {
+			m.logger.Warnf("SQLite cannot add foreign keys to existing tables, skipping %s(%s) referencing %s",
+				table.Name, strings.Join(columns, ", "), rel.JoinTable.Name)
+
+			continue
+		}
+
+		name := "fk_" + table.Name + "_" + strings.Join(columns, "_")
+		changes = append(changes, Change{
+			Kind:  ChangeAddForeignKey,
+			Table: table.Name,
+			Name:  name,
+			SQL: m.bunDB.NewRaw("ALTER TABLE ? ADD CONSTRAINT ? FOREIGN KEY (?) REFERENCES ? (?)",
+				bun.Ident(table.Name), bun.Ident(name), identList(columns),
+				bun.Ident(rel.JoinTable.Name), identList(fieldNames(rel.JoinPKs))).String(),
+		})
+	}
+
+	return changes
+}
+
+// tableComment plans setting the comment of a table, false on databases keeping no comments.
+func (m *Migrator) tableComment(table *bunschema.Table, comment string) (Change, bool) {
+	var query *bun.RawQuery
+
+	switch m.bunDB.Dialect().Name() {
+	case dialect.PG:
+		query = m.bunDB.NewRaw("COMMENT ON TABLE ? IS ?", bun.Ident(table.Name), comment)
+	case dialect.MySQL:
+		query = m.bunDB.NewRaw("ALTER TABLE ? COMMENT = ?", bun.Ident(table.Name), comment)
+	default:
+		return Change{}, false
+	}
+
+	return Change{Kind: ChangeComment, Table: table.Name, SQL: query.String()}, true
+}
+
+// columnComment plans setting the comment of a column, false on databases keeping no comments.
+// MySQL sets comments only along with the whole column definition, which is restated from the model.
+func (m *Migrator) columnComment(table *bunschema.Table, field *bunschema.Field, comment string) (Change, bool) {
+	var query *bun.RawQuery
+
+	switch m.bunDB.Dialect().Name() {
+	case dialect.PG:
+		query = m.bunDB.NewRaw("COMMENT ON COLUMN ?.? IS ?", bun.Ident(table.Name), bun.Ident(field.Name), comment)
+	case dialect.MySQL:
+		definition := m.columnDefinition(field)
+		if field.AutoIncrement {
+			definition += " AUTO_INCREMENT"
+		}
+
+		query = m.bunDB.NewRaw("ALTER TABLE ? MODIFY COLUMN ? ? COMMENT ?",
+			bun.Ident(table.Name), bun.Ident(field.Name), bun.Safe(definition), comment)
+	default:
+		return Change{}, false
+	}
+
+	return Change{Kind: ChangeComment, Table: table.Name, Name: field.Name, SQL: query.String()}, true
+}
+
+// sql renders a query built by bun as SQL text.
+func (m *Migrator) sql(query bunschema.QueryAppender) string {
+	return m.bunDB.NewRaw("?", query).String()
+}
+
+// columnDefinition returns the type and constraints of a column added to an existing table. VARCHAR columns get
+// the default length of the dialect like bun gives them on table creation, as MySQL requires a length.
+func (m *Migrator) columnDefinition(field *bunschema.Field) string {
+	definition := field.CreateTableSQLType
+	if length := m.bunDB.Dialect().DefaultVarcharLen(); length > 0 &&
+		strings.EqualFold(definition, sqltype.VarChar) && strings.EqualFold(definition, field.DiscoveredSQLType) {
+		definition += "(" + strconv.Itoa(length) + ")"
+	}
+
+	if field.NotNull {
+		definition += " NOT NULL"
+	}
+
+	if field.SQLDefault != constants.Empty {
+		definition += " DEFAULT " + field.SQLDefault
+	}
+
+	return definition
+}
+
+// index is a unique constraint or plain index declared on a model.
+type index struct {
+	name    string
+	columns []string
+}
+
+// uniqueKeys returns the unique constraints declared through unique tags, named like bun names them.
+func uniqueKeys(table *bunschema.Table) []index {
+	var keys []index
+
+	for name, fields := range table.Unique {
+		if name != constants.Empty {
+			keys = append(keys, index{name: name, columns: fieldNames(fields)})
+
+			continue
+		}
+
+		for _, field := range fields {
+			keys = append(keys, index{name: "uk_" + table.Name + "_" + field.Name, columns: []string{field.Name}})
+		}
+	}
+
+	slices.SortFunc(keys, func(a, b index) int {
+		return cmp.Compare(a.name, b.name)
+	})
+
+	return keys
+}
+
+// taggedIndexes returns the plain indexes declared through index tags, in order of their first column.
+func taggedIndexes(table *bunschema.Table) []index {
+	var indexes []index
+
+	for _, field := range table.Fields {
+		name, ok := field.StructField.Tag.Lookup(TagIndex)
+		if !ok {
+			continue
+		}
+
+		if name == constants.Empty {
+			name = "idx_" + table.Name + "_" + field.Name
+		}
+
+		if i := slices.IndexFunc(indexes, func(idx index) bool { return idx.name == name }); i >= 0 {
+			indexes[i].columns = append(indexes[i].columns, field.Name)
+		} else {
+			indexes = append(indexes, index{name: name, columns: []string{field.Name}})
+		}
+	}
+
+	return indexes
+}
+
+// hasKey reports whether the live table has a primary key, unique key or index over exactly the columns.
+func hasKey(live *schema.TableSchema, columns []string) bool {
+	if live.PrimaryKey != nil && sameColumns(live.PrimaryKey.Columns, columns) {
+		return true
+	}
+
+	return slices.ContainsFunc(live.UniqueKeys, func(k schema.UniqueKey) bool { return sameColumns(k.Columns, columns) }) ||
+		slices.ContainsFunc(live.Indexes, func(i schema.Index) bool { return sameColumns(i.Columns, columns) })
+}
+
+// hasIndex reports whether the live table has an index or unique key with the name.
+func hasIndex(live *schema.TableSchema, name string) bool {
+	return slices.ContainsFunc(live.Indexes, func(i schema.Index) bool { return strings.EqualFold(i.Name, name) }) ||
+		slices.ContainsFunc(live.UniqueKeys, func(k schema.UniqueKey) bool { return strings.EqualFold(k.Name, name) })
+}
+
+// sameColumns reports whether two column lists hold the same columns in the same order.
+func sameColumns(a, b []string) bool {
+	return slices.EqualFunc(a, b, strings.EqualFold)
+}
+
+// sortedRelations returns the belongs-to relations of a table sorted by name.
+func sortedRelations(table *bunschema.Table) []*bunschema.Relation {
+	var relations []*bunschema.Relation
+
+	for _, rel := range table.Relations {
+		if rel.Type == bunschema.BelongsToRelation {
+			relations = append(relations, rel)
+		}
+	}
+
+	slices.SortFunc(relations, func(a, b *bunschema.Relation) int {
+		return cmp.Compare(a.Field.Name, b.Field.Name)
+	})
+
+	return relations
+}
+
+// sortByReferences orders tables so that tables referenced through belongs-to relations come first.
+// Tables referencing each other keep their registration order.
+func sortByReferences(tables []*bunschema.Table) []*bunschema.Table {
+	sorted := make([]*bunschema.Table, 0, len(tables))
+	state := make(map[string]int, len(tables)) // 1 while visiting, 2 once sorted
+
+	byName := make(map[string]*bunschema.Table, len(tables))
+	for _, table := range tables {
+		byName[table.Name] = table
+	}
+
+	var visit func(table *bunschema.Table)
+	visit = func(table *bunschema.Table) {
+		if state[table.Name] != 0 {
+			return
+		}
+
+		state[table.Name] = 1
+
+		for _, rel := range sortedRelations(table) {
+			if referenced, ok := byName[rel.JoinTable.Name]; ok {
+				visit(referenced)
+			}
+		}
+
+		state[table.Name] = 2
+		sorted = append(sorted, table)
+	}
+
+	for _, table := range tables {
+		visit(table)
+	}
+
+	return sorted
+}
+
+func fieldNames(fields []*bunschema.Field) []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+
+	return names
+}
+
+// identList appends names as a comma-separated list of quoted identifiers.
+type identList []string
+
+func (l identList) AppendQuery(gen bunschema.QueryGen, b []byte) ([]byte, error) {
+	for i, name := range l {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+
+		b = gen.AppendIdent(b, name)
+	}
+
+	return b, nil
+}
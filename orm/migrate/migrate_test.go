@@ -0,0 +1,281 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	ischema "github.com/ilxqx/vef-framework-go/internal/schema"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/orm/ormtest"
+	"github.com/ilxqx/vef-framework-go/schema"
+)
+
+// Customer is referenced by Order and registered after it to check the creation order.
+type Customer struct {
+	orm.BaseModel `bun:"table:test_migrate_customer"`
+
+	ID    string `bun:"id,pk"`
+	Email string `bun:"email,notnull,unique"`
+}
+
+type Order struct {
+	orm.BaseModel `bun:"table:test_migrate_order"`
+
+	ID         string    `bun:"id,pk"`
+	CustomerID string    `bun:"customer_id,notnull" index:"idx_test_migrate_order_customer_status"`
+	Status     string    `bun:"status,notnull"      index:"idx_test_migrate_order_customer_status"`
+	Code       string    `bun:"code,notnull"        index:""`
+	Customer   *Customer `bun:"rel:belongs-to,join:customer_id=id"`
+}
+
+// OrderV2 is Order with a column added later.
+type OrderV2 struct {
+	orm.BaseModel `bun:"table:test_migrate_order"`
+
+	ID         string `bun:"id,pk"`
+	CustomerID string `bun:"customer_id,notnull" index:"idx_test_migrate_order_customer_status"`
+	Status     string `bun:"status,notnull"      index:"idx_test_migrate_order_customer_status"`
+	Code       string `bun:"code,notnull"        index:""`
+	Note       string `bun:"note,notnull,default:''" index:""`
+}
+
+// Note is documented through comment tags.
+type Note struct {
+	orm.BaseModel `bun:"table:test_migrate_note" comment:"Notes of orders"`
+
+	ID      string `bun:"id,pk"        comment:"Note ID"`
+	OrderID string `bun:"order_id"`
+	Text    string `bun:"text,notnull" comment:"Text, e.g. 'shipped'"`
+}
+
+// NoteV2 is Note with a documented column added later.
+type NoteV2 struct {
+	orm.BaseModel `bun:"table:test_migrate_note" comment:"Notes of orders"`
+
+	ID      string `bun:"id,pk"                     comment:"Note ID"`
+	OrderID string `bun:"order_id"`
+	Text    string `bun:"text,notnull"              comment:"Text, e.g. 'shipped'"`
+	Author  string `bun:"author,notnull,default:''" comment:"Author"`
+}
+
+// staticService reports the tables of a fixed schema.
+type staticService struct {
+	schema.Service
+
+	tables []*schema.TableSchema
+}
+
+func (s *staticService) ListTables(context.Context) ([]schema.Table, error) {
+	tables := make([]schema.Table, len(s.tables))
+	for i, table := range s.tables {
+		tables[i] = schema.Table{Name: table.Name}
+	}
+
+	return tables, nil
+}
+
+func (s *staticService) GetTableSchema(_ context.Context, name string) (*schema.TableSchema, error) {
+	for _, table := range s.tables {
+		if table.Name == name {
+			return table, nil
+		}
+	}
+
+	return nil, nil
+}
+
+type recordingPublisher struct {
+	events []event.Event
+}
+
+func (p *recordingPublisher) Publish(evt event.Event) {
+	p.events = append(p.events, evt)
+}
+
+type MigrateTestSuite struct {
+	suite.Suite
+
+	ctx     context.Context
+	db      orm.DB
+	service schema.Service
+	closeDB func() error
+}
+
+func (s *MigrateTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dsConfig := &config.DatasourceConfig{Type: constants.SQLite}
+
+	bunDB, err := database.New(dsConfig)
+	s.Require().NoError(err)
+
+	s.closeDB = bunDB.Close
+	s.db = iorm.New(bunDB)
+
+	s.service, err = ischema.NewService(bunDB.DB, dsConfig)
+	s.Require().NoError(err)
+}
+
+func (s *MigrateTestSuite) TearDownTest() {
+	s.Require().NoError(s.closeDB())
+}
+
+func (s *MigrateTestSuite) newMigrator(opts ...Option) *Migrator {
+	m, err := New(s.db, s.service, opts...)
+	s.Require().NoError(err)
+
+	return m
+}
+
+func (s *MigrateTestSuite) TestCreateTables() {
+	plan, err := s.newMigrator().Register((*Order)(nil), (*Customer)(nil)).Apply(s.ctx)
+	s.Require().NoError(err)
+
+	s.Equal([]string{"test_migrate_customer", "test_migrate_order"}, plan.Tables(), "Referenced tables should be created first")
+	s.Equal(ChangeCreateTable, plan.Changes[0].Kind)
+
+	var indexes []string
+
+	for _, change := range plan.Changes {
+		if change.Kind == ChangeCreateIndex {
+			indexes = append(indexes, change.Name)
+		}
+	}
+
+	s.Equal([]string{"idx_test_migrate_order_customer_status", "idx_test_migrate_order_code"}, indexes)
+
+	order, err := s.service.GetTableSchema(s.ctx, "test_migrate_order")
+	s.Require().NoError(err)
+	s.True(hasIndex(order, "idx_test_migrate_order_customer_status"))
+	s.True(hasKey(order, []string{"customer_id", "status"}))
+
+	customer, err := s.service.GetTableSchema(s.ctx, "test_migrate_customer")
+	s.Require().NoError(err)
+	s.True(hasKey(customer, []string{"email"}), "Unique column should be part of the table definition")
+
+	plan, err = s.newMigrator().Register((*Order)(nil), (*Customer)(nil)).Plan(s.ctx)
+	s.Require().NoError(err)
+	s.True(plan.IsEmpty(), "Migrated models should need no further changes")
+}
+
+func (s *MigrateTestSuite) TestAddColumn() {
+	_, err := s.newMigrator().Register((*Customer)(nil), (*Order)(nil)).Apply(s.ctx)
+	s.Require().NoError(err)
+
+	publisher := new(recordingPublisher)
+
+	plan, err := s.newMigrator(WithPublisher(publisher)).Register((*OrderV2)(nil)).Apply(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(plan.Changes, 2)
+	s.Equal(ChangeAddColumn, plan.Changes[0].Kind)
+	s.Equal("note", plan.Changes[0].Name)
+	s.Equal(ChangeCreateIndex, plan.Changes[1].Kind)
+	s.Equal("idx_test_migrate_order_note", plan.Changes[1].Name)
+
+	_, err = s.db.NewInsert().Model(&OrderV2{ID: "o1", CustomerID: "c1", Status: "new", Code: "A"}).Exec(s.ctx)
+	s.Require().NoError(err, "Added column should accept rows")
+
+	s.Require().Len(publisher.events, 1)
+	s.Equal([]string{"test_migrate_order"}, publisher.events[0].(*schema.SchemaChangedEvent).Tables)
+}
+
+func (s *MigrateTestSuite) TestDryRun() {
+	var out bytes.Buffer
+
+	plan, err := s.newMigrator(WithDryRun(&out)).Register((*Customer)(nil)).Apply(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(plan.SQL(), out.String())
+	s.Contains(out.String(), `CREATE TABLE "test_migrate_customer"`)
+
+	tables, err := s.service.ListTables(s.ctx)
+	s.Require().NoError(err)
+	s.Empty(tables, "Dry run should not change the database")
+}
+
+func (s *MigrateTestSuite) TestComments() {
+	noteTable := &schema.TableSchema{
+		Name:    "test_migrate_note",
+		Columns: []schema.Column{{Name: "id"}, {Name: "order_id"}, {Name: "text"}},
+	}
+
+	statements := func(dbType constants.DBType, live []*schema.TableSchema, model any) []string {
+		m, err := New(ormtest.NewDB(dbType), &staticService{tables: live})
+		s.Require().NoError(err)
+
+		plan, err := m.Register(model).Plan(s.ctx)
+		s.Require().NoError(err)
+
+		var statements []string
+
+		for _, change := range plan.Changes {
+			if change.Kind != ChangeCreateTable {
+				statements = append(statements, change.SQL)
+			}
+		}
+
+		return statements
+	}
+
+	s.Run("Postgres", func() {
+		s.Equal([]string{
+			`COMMENT ON TABLE "test_migrate_note" IS 'Notes of orders'`,
+			`COMMENT ON COLUMN "test_migrate_note"."id" IS 'Note ID'`,
+			`COMMENT ON COLUMN "test_migrate_note"."text" IS 'Text, e.g. ''shipped'''`,
+		}, statements(constants.Postgres, nil, (*Note)(nil)))
+
+		s.Equal([]string{
+			`ALTER TABLE "test_migrate_note" ADD "author" VARCHAR NOT NULL DEFAULT ''`,
+			`COMMENT ON COLUMN "test_migrate_note"."author" IS 'Author'`,
+		}, statements(constants.Postgres, []*schema.TableSchema{noteTable}, (*NoteV2)(nil)))
+	})
+
+	s.Run("MySQL", func() {
+		s.Equal([]string{
+			"ALTER TABLE `test_migrate_note` COMMENT = 'Notes of orders'",
+			"ALTER TABLE `test_migrate_note` MODIFY COLUMN `id` VARCHAR(255) NOT NULL COMMENT 'Note ID'",
+			"ALTER TABLE `test_migrate_note` MODIFY COLUMN `text` VARCHAR(255) NOT NULL COMMENT 'Text, e.g. ''shipped'''",
+		}, statements(constants.MySQL, nil, (*Note)(nil)))
+
+		s.Equal([]string{
+			"ALTER TABLE `test_migrate_note` ADD `author` VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Author'",
+		}, statements(constants.MySQL, []*schema.TableSchema{noteTable}, (*NoteV2)(nil)))
+	})
+
+	s.Run("SQLite", func() {
+		s.Empty(statements(constants.SQLite, nil, (*Note)(nil)), "SQLite should keep no comments")
+	})
+}
+
+func (s *MigrateTestSuite) TestSchema() {
+	noteSchema := s.db.Schema((*Note)(nil))
+
+	s.Equal("test_migrate_note", noteSchema.Table)
+	s.Equal("Notes of orders", noteSchema.Comment)
+	s.Require().Len(noteSchema.Columns, 3)
+	s.Equal(orm.ColumnSchema{
+		Name:         "id",
+		Field:        "ID",
+		Type:         "VARCHAR",
+		Comment:      "Note ID",
+		IsPrimaryKey: true,
+	}, noteSchema.Columns[0])
+	s.True(noteSchema.Columns[1].Nullable, "Columns without notnull should be nullable")
+	s.Empty(noteSchema.Columns[1].Comment)
+}
+
+func (s *MigrateTestSuite) TestUnsupportedDB() {
+	_, err := New(nil, s.service)
+	s.ErrorIs(err, ErrUnsupportedDB)
+}
+
+func TestMigrate(t *testing.T) {
+	suite.Run(t, new(MigrateTestSuite))
+}
@@ -0,0 +1,33 @@
+package migrate
+
+import (
+	"io"
+
+	"github.com/ilxqx/vef-framework-go/event"
+)
+
+// Option configures a Migrator.
+type Option func(*Migrator)
+
+// WithPublisher publishes a schema changed event for the tables a migration changed,
+// which reloads cached table schemas.
+func WithPublisher(publisher event.Publisher) Option {
+	return func(m *Migrator) {
+		m.publisher = publisher
+	}
+}
+
+// WithDryRun makes Apply write the statements of the migration to w instead of executing them.
+func WithDryRun(w io.Writer) Option {
+	return func(m *Migrator) {
+		m.dryRun = w
+	}
+}
+
+// WithForeignKeys generates foreign keys for the belongs-to relations of the models.
+// SQLite cannot add foreign keys to existing tables, they are only declared on table creation there.
+func WithForeignKeys() Option {
+	return func(m *Migrator) {
+		m.foreignKeys = true
+	}
+}
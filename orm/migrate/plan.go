@@ -0,0 +1,66 @@
+package migrate
+
+import (
+	"strings"
+)
+
+// ChangeKind is the kind of a schema change.
+type ChangeKind string
+
+const (
+	// ChangeCreateTable creates the table of a model.
+	ChangeCreateTable ChangeKind = "create_table"
+	// ChangeAddColumn adds a column of a model to its table.
+	ChangeAddColumn ChangeKind = "add_column"
+	// ChangeCreateIndex creates an index declared on a model.
+	ChangeCreateIndex ChangeKind = "create_index"
+	// ChangeAddForeignKey adds the foreign key of a belongs-to relation.
+	ChangeAddForeignKey ChangeKind = "add_foreign_key"
+	// ChangeComment sets the comment of a table or column declared through a comment tag.
+	ChangeComment ChangeKind = "comment"
+)
+
+// Change is a single statement of a migration.
+type Change struct {
+	Kind  ChangeKind `json:"kind"`
+	Table string     `json:"table"`
+	Name  string     `json:"name,omitempty"` // Column, index or constraint name
+	SQL   string     `json:"sql"`
+}
+
+// Plan is the ordered list of changes bringing the database in line with the models.
+type Plan struct {
+	Changes []Change `json:"changes"`
+}
+
+// IsEmpty reports whether the database already matches the models.
+func (p *Plan) IsEmpty() bool {
+	return len(p.Changes) == 0
+}
+
+// Tables returns the tables the plan changes, in order of their first change.
+func (p *Plan) Tables() []string {
+	var tables []string
+
+	seen := make(map[string]bool)
+	for _, change := range p.Changes {
+		if !seen[change.Table] {
+			seen[change.Table] = true
+			tables = append(tables, change.Table)
+		}
+	}
+
+	return tables
+}
+
+// SQL returns the statements of the plan as a script.
+func (p *Plan) SQL() string {
+	var sb strings.Builder
+
+	for _, change := range p.Changes {
+		sb.WriteString(change.SQL)
+		sb.WriteString(";\n")
+	}
+
+	return sb.String()
+}
@@ -9,11 +9,17 @@ type (
 	UpdateQuery                = orm.UpdateQuery
 	DeleteQuery                = orm.DeleteQuery
 	MergeQuery                 = orm.MergeQuery
+	UpsertQuery                = orm.UpsertQuery
+	QueryExecutor              = orm.QueryExecutor
 	RawQuery                   = orm.RawQuery
+	ProcQuery                  = orm.ProcQuery
+	MultiResultScanner         = orm.MultiResultScanner
+	ProcParam                  = orm.ProcParam
 	QueryBuilder               = orm.QueryBuilder
 	ConditionBuilder           = orm.ConditionBuilder
 	Applier[T any]             = orm.Applier[T]
 	ApplyFunc[T any]           = orm.ApplyFunc[T]
+	Spec                       = orm.Spec
 	RelationSpec               = orm.RelationSpec
 	JoinType                   = orm.JoinType
 	FuzzyKind                  = orm.FuzzyKind
@@ -21,16 +27,55 @@ type (
 	FromDirection              = orm.FromDirection
 	FrameType                  = orm.FrameType
 	FrameBoundKind             = orm.FrameBoundKind
+	FrameExclusion             = orm.FrameExclusion
 	StatisticalMode            = orm.StatisticalMode
 	ConflictAction             = orm.ConflictAction
+	CascadeMode                = orm.CascadeMode
+	CascadeHook                = orm.CascadeHook
+	TreeDirection              = orm.TreeDirection
+	TreeOptions                = orm.TreeOptions
+	ExplainOptions             = orm.ExplainOptions
+	QueryPlan                  = orm.QueryPlan
+	PlanNode                   = orm.PlanNode
 	DateTimeUnit               = orm.DateTimeUnit
 	ColumnInfo                 = orm.ColumnInfo
 	Model                      = orm.Model
 	IDModel                    = orm.IDModel
 	CreatedModel               = orm.CreatedModel
 	AuditedModel               = orm.AuditedModel
+	DefaultScoper              = orm.DefaultScoper
+	DefaultOrderer             = orm.DefaultOrderer
+	DataScope                  = orm.DataScope
+	DataScopeFunc              = orm.DataScopeFunc
+	DataScopeExempt            = orm.DataScopeExempt
+	DataScopeContext           = orm.DataScopeContext
+	Tenancy                    = orm.Tenancy
+	TenancyStrategy            = orm.TenancyStrategy
+	TenantResolver             = orm.TenantResolver
+	TenantResolverFunc         = orm.TenantResolverFunc
+	TenantDBOpener             = orm.TenantDBOpener
+	SecretProvider             = orm.SecretProvider
+	SecretProviderFunc         = orm.SecretProviderFunc
+	SecretRef                  = orm.SecretRef
+	Auditable                  = orm.Auditable
+	AuditSink                  = orm.AuditSink
+	AuditSinkFunc              = orm.AuditSinkFunc
+	TransactionalAuditSink     = orm.TransactionalAuditSink
+	AuditEvent                 = orm.AuditEvent
+	AuditChange                = orm.AuditChange
+	AuditOperation             = orm.AuditOperation
+	QueryHook                  = orm.QueryHook
+	QueryEvent                 = orm.QueryEvent
+	Row                        = orm.Row
+	Rows                       = orm.Rows
 	PKField                    = orm.PKField
+	BucketBoundary             = orm.BucketBoundary
+	ExpressionIndex            = orm.ExpressionIndex
+	ModelSchema                = orm.ModelSchema
+	ColumnSchema               = orm.ColumnSchema
 	ExprBuilder                = orm.ExprBuilder
+	DialectExecs               = orm.DialectExecs
+	DialectAction              = orm.DialectAction
 	OrderBuilder               = orm.OrderBuilder
 	CaseBuilder                = orm.CaseBuilder
 	CaseWhenBuilder            = orm.CaseWhenBuilder
@@ -45,6 +90,7 @@ type (
 	MinBuilder                 = orm.MinBuilder
 	MaxBuilder                 = orm.MaxBuilder
 	StringAggBuilder           = orm.StringAggBuilder
+	PercentileBuilder          = orm.PercentileBuilder
 	ArrayAggBuilder            = orm.ArrayAggBuilder
 	StdDevBuilder              = orm.StdDevBuilder
 	VarianceBuilder            = orm.VarianceBuilder
@@ -112,6 +158,13 @@ const (
 	FrameRange   = orm.FrameRange
 	FrameGroups  = orm.FrameGroups
 
+	// FrameExclusion constants.
+	FrameExcludeDefault    = orm.FrameExcludeDefault
+	FrameExcludeCurrentRow = orm.FrameExcludeCurrentRow
+	FrameExcludeGroup      = orm.FrameExcludeGroup
+	FrameExcludeTies       = orm.FrameExcludeTies
+	FrameExcludeNoOthers   = orm.FrameExcludeNoOthers
+
 	// FrameBoundKind constants.
 	FrameBoundNone               = orm.FrameBoundNone
 	FrameBoundUnboundedPreceding = orm.FrameBoundUnboundedPreceding
@@ -129,6 +182,19 @@ const (
 	ConflictDoNothing = orm.ConflictDoNothing
 	ConflictDoUpdate  = orm.ConflictDoUpdate
 
+	// CascadeMode constants.
+	CascadeDefault = orm.CascadeDefault
+	CascadeForce   = orm.CascadeForce
+	CascadeSkip    = orm.CascadeSkip
+
+	// TreeDirection constants.
+	TreeDescendants = orm.TreeDescendants
+	TreeAncestors   = orm.TreeAncestors
+
+	// Tree query column constants.
+	TreeDepthColumn = orm.TreeDepthColumn
+	TreePathColumn  = orm.TreePathColumn
+
 	// DateTimeUnit constants.
 	UnitYear   = orm.UnitYear
 	UnitMonth  = orm.UnitMonth
@@ -136,6 +202,102 @@ const (
 	UnitHour   = orm.UnitHour
 	UnitMinute = orm.UnitMinute
 	UnitSecond = orm.UnitSecond
+
+	// Expression index tag constants.
+	TagExprIndex        = orm.TagExprIndex
+	AttrExprIndexName   = orm.AttrExprIndexName
+	AttrExprIndexUsing  = orm.AttrExprIndexUsing
+	AttrExprIndexOps    = orm.AttrExprIndexOps
+	AttrExprIndexUnique = orm.AttrExprIndexUnique
+
+	// Summary column tag constants.
+	TagSummary     = orm.TagSummary
+	AttrSummarySum = orm.AttrSummarySum
+
+	// Denormalized column tag constants.
+	TagDenorm = orm.TagDenorm
+
+	// Comment tag constants.
+	TagComment = orm.TagComment
+
+	// InLarge constants.
+	DefaultInLargeThreshold = orm.DefaultInLargeThreshold
+
+	// ID generator constants.
+	TagIDGenerator       = orm.TagIDGenerator
+	IDGeneratorXID       = orm.IDGeneratorXID
+	IDGeneratorSnowflake = orm.IDGeneratorSnowflake
+	IDGeneratorULID      = orm.IDGeneratorULID
+	IDGeneratorUUID      = orm.IDGeneratorUUID
+
+	// Audit constants.
+	TagAudit             = orm.TagAudit
+	AuditOperationInsert = orm.AuditOperationInsert
+	AuditOperationUpdate = orm.AuditOperationUpdate
+	AuditOperationDelete = orm.AuditOperationDelete
+
+	// History table constants.
+	HistoryTableSuffix     = orm.HistoryTableSuffix
+	ColumnHistoryOperation = orm.ColumnHistoryOperation
+	ColumnHistoryActor     = orm.ColumnHistoryActor
+	ColumnHistoryAt        = orm.ColumnHistoryAt
+	HistoryOperationUpdate = orm.HistoryOperationUpdate
+	HistoryOperationDelete = orm.HistoryOperationDelete
+
+	// TenancyStrategy constants.
+	TenancyRowPerTenant    = orm.TenancyRowPerTenant
+	TenancySchemaPerTenant = orm.TenancySchemaPerTenant
 )
 
-var ApplySort = orm.ApplySort
+var (
+	ApplySort                = orm.ApplySort
+	Out                      = orm.Out
+	InOut                    = orm.InOut
+	WidthBucketBoundaries    = orm.WidthBucketBoundaries
+	RegisterExpressionIndex  = orm.RegisterExpressionIndex
+	ExpressionIndexesOf      = orm.ExpressionIndexesOf
+	CreateExpressionIndex    = orm.CreateExpressionIndex
+	ExpressionIndexSQL       = orm.ExpressionIndexSQL
+	CreateExpressionIndexes  = orm.CreateExpressionIndexes
+	ModelSchemaOf            = orm.ModelSchemaOf
+	TableCommentOf           = orm.TableCommentOf
+	ColumnCommentOf          = orm.ColumnCommentOf
+	RegisterSummaries        = orm.RegisterSummaries
+	RecomputeSummaries       = orm.RecomputeSummaries
+	RegisterDenormalizations = orm.RegisterDenormalizations
+	BackfillDenormalizations = orm.BackfillDenormalizations
+	WithDataScopeContext     = orm.WithDataScopeContext
+	DataScopeContextFrom     = orm.DataScopeContextFrom
+	NewCreatedByDataScope    = orm.NewCreatedByDataScope
+	NewColumnDataScope       = orm.NewColumnDataScope
+	NewSlowQueryHook         = orm.NewSlowQueryHook
+	And                      = orm.And
+	Or                       = orm.Or
+	Not                      = orm.Not
+	DeriveAlias              = orm.DeriveAlias
+	RegisterAliases          = orm.RegisterAliases
+	MustAlias                = orm.MustAlias
+	RegisterHistory          = orm.RegisterHistory
+	CreateHistoryTables      = orm.CreateHistoryTables
+	NewRowTenancy            = orm.NewRowTenancy
+	NewSchemaTenancy         = orm.NewSchemaTenancy
+	ErrTenantRequired        = orm.ErrTenantRequired
+	ErrInvalidTenant         = orm.ErrInvalidTenant
+	EnvSecretProvider        = orm.EnvSecretProvider
+	SetSecretProvider        = orm.SetSecretProvider
+	SecretKey                = orm.SecretKey
+	SetInLargeThreshold      = orm.SetInLargeThreshold
+	ErrSecretNotFound        = orm.ErrSecretNotFound
+	IDSequence               = orm.IDSequence
+	RegisterIDGenerator      = orm.RegisterIDGenerator
+	RegisterIDStrategy       = orm.RegisterIDStrategy
+	SetDefaultIDStrategy     = orm.SetDefaultIDStrategy
+	ErrUnknownIDGenerator    = orm.ErrUnknownIDGenerator
+	ErrPolicyViolation       = orm.ErrPolicyViolation
+	SetAuditSink             = orm.SetAuditSink
+	AfterCommit              = orm.AfterCommit
+	InTransaction            = orm.InTransaction
+
+	ErrDialectUnsupportedOperation = orm.ErrDialectUnsupportedOperation
+	ErrDataScopeContextMissing     = orm.ErrDataScopeContextMissing
+)
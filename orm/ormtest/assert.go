@@ -0,0 +1,97 @@
+package ormtest
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// SnapshotDir is the directory of the golden files, relative to the directory of the package under test.
+const SnapshotDir = "testdata/snapshots"
+
+// snapshotNamePattern restricts snapshot names to characters that are safe to use as file names.
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// AssertSQL fails the test when the SQL of the query differs from the expected SQL. Given arguments, the expected SQL
+// is formatted with them like the query formats its own, so values can be written as ? placeholders. Runs of
+// whitespace compare as single spaces, so the expected SQL can span lines.
+func AssertSQL(t testing.TB, query orm.QueryBuilder, expectedSQL string, args ...any) {
+	t.Helper()
+
+	actual, err := iorm.RenderSQL(query)
+	if err != nil {
+		t.Fatalf("Failed to render the query: %v", err)
+	}
+
+	if len(args) > 0 {
+		expectedSQL = iorm.FormatSQL(query, expectedSQL, args...)
+	}
+
+	if normalizeSQL(actual) != normalizeSQL(expectedSQL) {
+		t.Errorf("SQL of the query differs:\nexpected: %s\n  actual: %s", normalizeSQL(expectedSQL), actual)
+	}
+}
+
+// AssertSnapshot fails the test when the SQL of the query differs from its golden file, named by the snapshot name
+// and the dialect of the query, e.g. testdata/snapshots/user.find_by_email.pg.sql. With VEF_SQL_UPDATE=true the
+// golden file is rewritten instead.
+func AssertSnapshot(t testing.TB, query orm.QueryBuilder, name string) {
+	t.Helper()
+
+	if !snapshotNamePattern.MatchString(name) {
+		t.Fatalf("Invalid snapshot name %q", name)
+	}
+
+	actual, err := iorm.RenderSQL(query)
+	if err != nil {
+		t.Fatalf("Failed to render the query: %v", err)
+	}
+
+	path := filepath.Join(SnapshotDir, name+"."+query.Dialect().Name().String()+".sql")
+
+	if update, _ := strconv.ParseBool(os.Getenv(constants.EnvSQLUpdate)); update {
+		if err := writeSnapshot(path, actual); err != nil {
+			t.Fatalf("Failed to write snapshot %s: %v", path, err)
+		}
+
+		t.Logf("Updated snapshot %s", path)
+
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("No snapshot at %s, run with %s=true to create it", path, constants.EnvSQLUpdate)
+	}
+
+	if err != nil {
+		t.Fatalf("Failed to read snapshot %s: %v", path, err)
+	}
+
+	if expected := strings.TrimSuffix(string(data), "\n"); actual != expected {
+		t.Errorf("SQL of the query differs from snapshot %s:\nexpected: %s\n  actual: %s", path, expected, actual)
+	}
+}
+
+// writeSnapshot stores the SQL in the golden file, ending it with a newline for diffs.
+func writeSnapshot(path, sql string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(sql+"\n"), 0o644)
+}
+
+// normalizeSQL collapses the runs of whitespace into single spaces.
+func normalizeSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), constants.Space)
+}
@@ -0,0 +1,6 @@
+package ormtest
+
+import "errors"
+
+// ErrNoDatabase indicates a query of a DB created by NewDB was executed, which only renders queries.
+var ErrNoDatabase = errors.New("ormtest databases render queries without executing them")
@@ -0,0 +1,86 @@
+// Package ormtest catches query builder regressions by comparing the SQL generated for queries with expected SQL
+// or golden files, without a database.
+//
+// NewDB creates a DB of a dialect which renders queries but cannot execute them. AssertSQL compares the SQL of a
+// query with the expected SQL and AssertSnapshot with the golden file of the query in testdata/snapshots, one file
+// per dialect. Set VEF_SQL_UPDATE=true to rewrite the golden files after intended changes.
+package ormtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Dialects are the database types NewDB supports.
+var Dialects = []constants.DBType{constants.Postgres, constants.MySQL, constants.SQLite}
+
+// NewDB creates a DB of the database type that renders queries without connecting to a database.
+// Executing its queries fails with ErrNoDatabase. It panics on database types other than Dialects.
+func NewDB(dbType constants.DBType) orm.DB {
+	var dialect schema.Dialect
+
+	switch dbType {
+	case constants.Postgres:
+		dialect = pgdialect.New()
+	case constants.MySQL:
+		dialect = &mysql8Dialect{Dialect: mysqldialect.New()}
+	case constants.SQLite:
+		dialect = sqlitedialect.New()
+	default:
+		panic(fmt.Sprintf("ormtest: unsupported database type %q", dbType))
+	}
+
+	return iorm.New(bun.NewDB(sql.OpenDB(connector{}), dialect))
+}
+
+// ForEachDialect runs fn as a subtest named by the database type for each of the Dialects.
+func ForEachDialect(t *testing.T, fn func(t *testing.T, db orm.DB)) {
+	t.Helper()
+
+	for _, dbType := range Dialects {
+		t.Run(string(dbType), func(t *testing.T) {
+			fn(t, NewDB(dbType))
+		})
+	}
+}
+
+// mysql8Dialect is the MySQL dialect with the features of MySQL 8, which the dialect otherwise
+// detects from the version of the server.
+type mysql8Dialect struct {
+	*mysqldialect.Dialect
+}
+
+func (*mysql8Dialect) Init(*sql.DB) {}
+
+func (d *mysql8Dialect) Features() feature.Feature {
+	return d.Dialect.Features() | feature.CTE | feature.WithValues | feature.DeleteTableAlias
+}
+
+// connector is a connector failing to connect, so queries render but do not execute.
+type connector struct{}
+
+func (connector) Connect(context.Context) (driver.Conn, error) {
+	return nil, ErrNoDatabase
+}
+
+func (c connector) Driver() driver.Driver {
+	return c
+}
+
+func (connector) Open(string) (driver.Conn, error) {
+	return nil, ErrNoDatabase
+}
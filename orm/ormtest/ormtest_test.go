@@ -0,0 +1,159 @@
+package ormtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Account is a test model.
+type Account struct {
+	orm.BaseModel `bun:"table:test_account,alias:ta"`
+
+	ID    string `bun:"id,pk"`
+	Email string `bun:"email,notnull"`
+	Name  string `bun:"name,notnull"`
+}
+
+func findEmailByName(db orm.DB) orm.SelectQuery {
+	return db.NewSelect().
+		Model((*Account)(nil)).
+		Select("email").
+		Where(func(cb orm.ConditionBuilder) {
+			cb.Equals("name", "Alice")
+		}).
+		Limit(1)
+}
+
+// recordingT records the failures of assertions, so failing assertions can be tested.
+type recordingT struct {
+	testing.TB
+
+	failures []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Logf(string, ...any) {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// record runs the assertion on a recordingT in a goroutine of its own, as Fatalf exits the goroutine.
+func record(t testing.TB, assertion func(t testing.TB)) []string {
+	r := &recordingT{TB: t}
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		assertion(r)
+	})
+	wg.Wait()
+
+	return r.failures
+}
+
+type OrmTestTestSuite struct {
+	suite.Suite
+}
+
+func (s *OrmTestTestSuite) TestAssertSQL() {
+	ForEachDialect(s.T(), func(t *testing.T, db orm.DB) {
+		expected := `SELECT "ta"."email" FROM "test_account" AS "ta" WHERE ("ta"."name" = ?) LIMIT 1`
+		if db.NewSelect().Dialect().Name().String() == string(constants.MySQL) {
+			expected = "SELECT `ta`.`email` FROM `test_account` AS `ta` WHERE (`ta`.`name` = ?) LIMIT 1"
+		}
+
+		AssertSQL(t, findEmailByName(db), expected, "Alice")
+	})
+}
+
+func (s *OrmTestTestSuite) TestAssertSQLWhitespace() {
+	AssertSQL(s.T(), findEmailByName(NewDB(constants.Postgres)), `
+		SELECT "ta"."email"
+		FROM "test_account" AS "ta"
+		WHERE ("ta"."name" = 'Alice')
+		LIMIT 1
+	`)
+}
+
+func (s *OrmTestTestSuite) TestAssertSQLMismatch() {
+	failures := record(s.T(), func(t testing.TB) {
+		AssertSQL(t, findEmailByName(NewDB(constants.SQLite)), `SELECT "ta"."name" FROM "test_account" AS "ta"`)
+	})
+
+	s.Require().Len(failures, 1)
+	s.Contains(failures[0], `actual: SELECT "ta"."email"`)
+}
+
+func (s *OrmTestTestSuite) TestAssertSnapshot() {
+	s.T().Chdir(s.T().TempDir())
+
+	db := NewDB(constants.Postgres)
+	path := filepath.Join(SnapshotDir, "account.find_email_by_name.pg.sql")
+
+	failures := record(s.T(), func(t testing.TB) {
+		AssertSnapshot(t, findEmailByName(db), "account.find_email_by_name")
+	})
+	s.Require().Len(failures, 1, "Missing snapshots should fail")
+	s.Contains(failures[0], constants.EnvSQLUpdate)
+
+	s.T().Setenv(constants.EnvSQLUpdate, "true")
+	AssertSnapshot(s.T(), findEmailByName(db), "account.find_email_by_name")
+
+	data, err := os.ReadFile(path)
+	s.Require().NoError(err, "Update mode should write the snapshot")
+	s.Equal(`SELECT "ta"."email" FROM "test_account" AS "ta" WHERE ("ta"."name" = 'Alice') LIMIT 1`+"\n", string(data))
+
+	s.T().Setenv(constants.EnvSQLUpdate, "false")
+	AssertSnapshot(s.T(), findEmailByName(db), "account.find_email_by_name")
+
+	failures = record(s.T(), func(t testing.TB) {
+		AssertSnapshot(t, db.NewSelect().Model((*Account)(nil)), "account.find_email_by_name")
+	})
+	s.Len(failures, 1, "Changed SQL should fail")
+
+	failures = record(s.T(), func(t testing.TB) {
+		AssertSnapshot(t, findEmailByName(db), "../escape")
+	})
+	s.Len(failures, 1, "Unsafe names should fail")
+}
+
+func (s *OrmTestTestSuite) TestRenderErrors() {
+	failures := record(s.T(), func(t testing.TB) {
+		AssertSQL(t, NewDB(constants.SQLite).NewSelect().Model((*Account)(nil)).SelectExpr(func(eb orm.ExprBuilder) any {
+			return eb.PgpEncrypt(eb.Column("email"), "key")
+		}, "encrypted_email"), `SELECT 1`)
+	})
+
+	s.Require().Len(failures, 1, "Errors of building the query should fail")
+	s.Contains(failures[0], "Failed to render the query")
+}
+
+func (s *OrmTestTestSuite) TestExecution() {
+	_, err := NewDB(constants.MySQL).NewSelect().Model((*Account)(nil)).Count(s.T().Context())
+	s.ErrorIs(err, ErrNoDatabase)
+}
+
+func (s *OrmTestTestSuite) TestUnsupportedDialect() {
+	s.Panics(func() {
+		NewDB(constants.Oracle)
+	})
+}
+
+func TestOrmTestTestSuite(t *testing.T) {
+	suite.Run(t, new(OrmTestTestSuite))
+}
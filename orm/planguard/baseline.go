@@ -0,0 +1,47 @@
+package planguard
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// baselinePath returns the baseline file of a query, one file per dialect since plans differ between databases.
+func baselinePath(dir, name, dialect string) string {
+	return filepath.Join(dir, name+"."+dialect+".json")
+}
+
+// readBaseline loads a stored plan, returning nil when the baseline does not exist.
+func readBaseline(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// writeBaseline stores a plan as indented JSON so baseline changes are reviewable in diffs.
+func writeBaseline(path string, plan *Plan) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,10 @@
+package planguard
+
+import "errors"
+
+var (
+	// ErrPlanRegression is returned by Report.Err when at least one query plan regressed.
+	ErrPlanRegression = errors.New("query plan regression detected")
+	// ErrUnsupportedDialect is returned when explaining queries is not supported for the database dialect.
+	ErrUnsupportedDialect = errors.New("query plan explain is not supported by the database dialect")
+)
@@ -0,0 +1,225 @@
+package planguard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Plan is a normalized query plan.
+// Volatile details such as row estimates and timings are dropped so that plans stay comparable across runs.
+type Plan struct {
+	// Dialect is the database dialect the plan was produced by.
+	Dialect string `json:"dialect"`
+	// Nodes are the plan nodes in depth-first order, indented by two spaces per level.
+	Nodes []string `json:"nodes"`
+	// SeqScans lists the tables read by a sequential (full table) scan.
+	SeqScans []string `json:"seqScans"`
+	// Cost is the estimated total cost, zero for dialects without cost estimates (SQLite).
+	Cost float64 `json:"cost"`
+}
+
+// sqlitePlanRow is a row of SQLite's EXPLAIN QUERY PLAN output.
+type sqlitePlanRow struct {
+	ID      int    `bun:"id"`
+	Parent  int    `bun:"parent"`
+	NotUsed int    `bun:"notused"`
+	Detail  string `bun:"detail"`
+}
+
+// Explain returns the normalized plan of the query on db.
+// PostgreSQL, MySQL and SQLite are supported; other dialects return ErrUnsupportedDialect.
+func Explain(ctx context.Context, db orm.DB, query orm.SelectQuery) (*Plan, error) {
+	sql := bun.Safe(query.String())
+	plan := &Plan{Dialect: query.Dialect().Name().String(), Nodes: []string{}, SeqScans: []string{}}
+
+	switch query.Dialect().Name() {
+	case dialect.PG:
+		var raw string
+		if err := db.NewRaw("EXPLAIN (FORMAT JSON) ?", sql).Scan(ctx, &raw); err != nil {
+			return nil, err
+		}
+
+		if err := normalizePostgresPlan(raw, plan); err != nil {
+			return nil, err
+		}
+
+	case dialect.MySQL:
+		var raw string
+		if err := db.NewRaw("EXPLAIN FORMAT=JSON ?", sql).Scan(ctx, &raw); err != nil {
+			return nil, err
+		}
+
+		if err := normalizeMySQLPlan(raw, plan); err != nil {
+			return nil, err
+		}
+
+	case dialect.SQLite:
+		var rows []sqlitePlanRow
+		if err := db.NewRaw("EXPLAIN QUERY PLAN ?", sql).Scan(ctx, &rows); err != nil {
+			return nil, err
+		}
+
+		normalizeSQLitePlan(rows, plan)
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDialect, plan.Dialect)
+	}
+
+	return plan, nil
+}
+
+// normalizePostgresPlan walks the JSON plan tree keeping node types, relations and indexes.
+func normalizePostgresPlan(raw string, plan *Plan) error {
+	var explained []struct {
+		Plan map[string]any `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(raw), &explained); err != nil {
+		return fmt.Errorf("failed to parse PostgreSQL plan: %w", err)
+	}
+
+	if len(explained) == 0 {
+		return nil
+	}
+
+	var walk func(node map[string]any, depth int)
+	walk = func(node map[string]any, depth int) {
+		nodeType, _ := node["Node Type"].(string)
+		relation, _ := node["Relation Name"].(string)
+		index, _ := node["Index Name"].(string)
+
+		line := nodeType
+		if relation != "" {
+			line += " on " + relation
+		}
+
+		if index != "" {
+			line += " using " + index
+		}
+
+		plan.Nodes = append(plan.Nodes, strings.Repeat("  ", depth)+line)
+		if nodeType == "Seq Scan" && relation != "" {
+			plan.SeqScans = appendUnique(plan.SeqScans, relation)
+		}
+
+		children, _ := node["Plans"].([]any)
+		for _, child := range children {
+			if childNode, ok := child.(map[string]any); ok {
+				walk(childNode, depth+1)
+			}
+		}
+	}
+
+	root := explained[0].Plan
+	plan.Cost, _ = root["Total Cost"].(float64)
+	walk(root, 0)
+
+	return nil
+}
+
+// normalizeMySQLPlan walks the JSON plan keeping table accesses; access type ALL denotes a full table scan.
+func normalizeMySQLPlan(raw string, plan *Plan) error {
+	var explained struct {
+		QueryBlock map[string]any `json:"query_block"`
+	}
+	if err := json.Unmarshal([]byte(raw), &explained); err != nil {
+		return fmt.Errorf("failed to parse MySQL plan: %w", err)
+	}
+
+	if costInfo, ok := explained.QueryBlock["cost_info"].(map[string]any); ok {
+		if cost, ok := costInfo["query_cost"].(string); ok {
+			plan.Cost, _ = strconv.ParseFloat(cost, 64)
+		}
+	}
+
+	var walk func(value any, depth int)
+	walk = func(value any, depth int) {
+		switch v := value.(type) {
+		case []any:
+			for _, item := range v {
+				walk(item, depth)
+			}
+
+		case map[string]any:
+			if table, ok := v["table"].(map[string]any); ok {
+				name, _ := table["table_name"].(string)
+				access, _ := table["access_type"].(string)
+				key, _ := table["key"].(string)
+
+				line := access + " on " + name
+				if key != "" {
+					line += " using " + key
+				}
+
+				plan.Nodes = append(plan.Nodes, strings.Repeat("  ", depth)+line)
+				if access == "ALL" {
+					plan.SeqScans = appendUnique(plan.SeqScans, name)
+				}
+			}
+
+			// Map iteration order is random, sort keys to keep the normalized plan stable
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+
+			slices.Sort(keys)
+
+			for _, key := range keys {
+				switch v[key].(type) {
+				case map[string]any, []any:
+					walk(v[key], depth+1)
+				}
+			}
+		}
+	}
+
+	walk(explained.QueryBlock, 0)
+
+	return nil
+}
+
+// normalizeSQLitePlan rebuilds the plan tree from the parent links; a SCAN without an index is a full table scan.
+func normalizeSQLitePlan(rows []sqlitePlanRow, plan *Plan) {
+	depths := make(map[int]int, len(rows))
+	for _, row := range rows {
+		depth := 0
+		if parentDepth, ok := depths[row.Parent]; ok {
+			depth = parentDepth + 1
+		}
+
+		depths[row.ID] = depth
+		plan.Nodes = append(plan.Nodes, strings.Repeat("  ", depth)+row.Detail)
+
+		if table, ok := strings.CutPrefix(row.Detail, "SCAN "); ok && isSQLiteTableScan(table) {
+			if name, _, found := strings.Cut(table, " "); found {
+				table = name
+			}
+
+			plan.SeqScans = appendUnique(plan.SeqScans, table)
+		}
+	}
+}
+
+// isSQLiteTableScan excludes index scans and scans of constant rows or materialized subqueries.
+func isSQLiteTableScan(target string) bool {
+	return !strings.Contains(target, " INDEX ") &&
+		!strings.HasPrefix(target, "CONSTANT ROW") &&
+		!strings.HasPrefix(target, "(")
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+
+	return append(values, value)
+}
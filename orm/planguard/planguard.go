@@ -0,0 +1,207 @@
+// Package planguard protects performance-critical queries against query plan regressions.
+//
+// Applications register their named queries once and run Guard.Check (or Guard.Assert from a test)
+// against a reference database in CI. The normalized plan of each query is compared with the baseline
+// stored in the baseline directory, and the check fails when a plan regresses to a sequential scan or
+// exceeds its cost thresholds. Set VEF_PLAN_UPDATE=true to rewrite the baselines after intended changes.
+package planguard
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	ilog "github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/log"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// defaultCostTolerance allows the estimated cost to grow by 50% over the baseline before it counts as a regression.
+const defaultCostTolerance = 0.5
+
+// queryNamePattern restricts query names to characters that are safe to use as baseline file names.
+var queryNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// QueryBuilder builds the query to explain against the given database.
+type QueryBuilder func(db orm.DB) orm.SelectQuery
+
+// Query is a named query guarded against plan regressions.
+type Query struct {
+	// Name identifies the query and its baseline file, e.g. "user.find_by_email".
+	Name string
+	// Build constructs the query; it is explained but never executed.
+	Build QueryBuilder
+	// MaxCost is the absolute ceiling of the estimated plan cost, zero disables the check.
+	// Only dialects reporting a cost estimate (PostgreSQL, MySQL) are checked.
+	MaxCost float64
+	// AllowSeqScan lists the tables (or aliases, as reported by the dialect) that may be scanned
+	// sequentially, typically small lookup tables.
+	AllowSeqScan []string
+}
+
+// Option configures a Guard.
+type Option func(*Guard)
+
+// WithCostTolerance sets how much the estimated cost may grow relative to the baseline,
+// e.g. 0.5 allows a growth of 50%. A negative value disables the relative cost check.
+func WithCostTolerance(tolerance float64) Option {
+	return func(g *Guard) {
+		g.costTolerance = tolerance
+	}
+}
+
+// WithUpdate forces (or prevents) rewriting the baselines, overriding the VEF_PLAN_UPDATE environment variable.
+func WithUpdate(update bool) Option {
+	return func(g *Guard) {
+		g.update = update
+	}
+}
+
+// Guard explains registered queries and compares their plans against stored baselines.
+type Guard struct {
+	dir           string
+	queries       []Query
+	costTolerance float64
+	update        bool
+	logger        log.Logger
+}
+
+// New creates a guard storing its baselines in dir, usually a testdata directory under version control.
+func New(dir string, opts ...Option) *Guard {
+	update, _ := strconv.ParseBool(os.Getenv(constants.EnvPlanUpdate))
+
+	g := &Guard{
+		dir:           dir,
+		costTolerance: defaultCostTolerance,
+		update:        update,
+		logger:        ilog.Named("planguard"),
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// Register adds queries to the guard.
+// It panics on an invalid or duplicate name since registrations are static program configuration.
+func (g *Guard) Register(queries ...Query) *Guard {
+	for _, query := range queries {
+		if !queryNamePattern.MatchString(query.Name) {
+			panic(fmt.Sprintf("planguard: invalid query name %q", query.Name))
+		}
+
+		if query.Build == nil {
+			panic(fmt.Sprintf("planguard: query %q has no builder", query.Name))
+		}
+
+		if slices.ContainsFunc(g.queries, func(q Query) bool { return q.Name == query.Name }) {
+			panic(fmt.Sprintf("planguard: query %q is already registered", query.Name))
+		}
+
+		g.queries = append(g.queries, query)
+	}
+
+	return g
+}
+
+// Check explains all registered queries against db and evaluates them against their baselines.
+// In update mode the current plans are written as new baselines and only the absolute cost ceilings are enforced.
+// The returned error reports failures to explain a query or to access a baseline; plan regressions are
+// reported through the Report.
+func (g *Guard) Check(ctx context.Context, db orm.DB) (*Report, error) {
+	report := &Report{Results: make([]Result, 0, len(g.queries))}
+
+	for _, query := range g.queries {
+		result, err := g.check(ctx, db, query)
+		if err != nil {
+			return nil, fmt.Errorf("planguard: query %q: %w", query.Name, err)
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+// Assert runs Check and fails the test on errors or plan regressions.
+func (g *Guard) Assert(t testing.TB, ctx context.Context, db orm.DB) {
+	t.Helper()
+
+	report, err := g.Check(ctx, db)
+	if err != nil {
+		t.Fatalf("Failed to check query plans: %v", err)
+	}
+
+	if err := report.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (g *Guard) check(ctx context.Context, db orm.DB, query Query) (Result, error) {
+	selectQuery := query.Build(db)
+
+	plan, err := Explain(ctx, db, selectQuery)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Query: query.Name, Plan: plan}
+	if query.MaxCost > 0 && plan.Cost > query.MaxCost {
+		result.addViolation(ViolationMaxCost, "estimated cost %.2f exceeds the ceiling %.2f", plan.Cost, query.MaxCost)
+	}
+
+	path := baselinePath(g.dir, query.Name, plan.Dialect)
+	if g.update {
+		if err := writeBaseline(path, plan); err != nil {
+			return Result{}, err
+		}
+
+		g.logger.Infof("Updated plan baseline of query %q: %s", query.Name, path)
+
+		return result, nil
+	}
+
+	baseline, err := readBaseline(path)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Baseline = baseline
+	if baseline == nil {
+		result.addViolation(ViolationMissingBaseline, "no baseline at %s, run with %s=true to create it", path, constants.EnvPlanUpdate)
+	}
+
+	for _, table := range plan.SeqScans {
+		if slices.Contains(query.AllowSeqScan, table) || (baseline != nil && slices.Contains(baseline.SeqScans, table)) {
+			continue
+		}
+
+		result.addViolation(ViolationSeqScan, "plan regressed to a sequential scan on %s", table)
+	}
+
+	if baseline == nil {
+		return result, nil
+	}
+
+	result.Changed = !slices.Equal(plan.Nodes, baseline.Nodes)
+	if result.Changed {
+		g.logger.Warnf("Plan of query %q differs from its baseline", query.Name)
+	}
+
+	if g.costTolerance >= 0 && baseline.Cost > 0 && plan.Cost > baseline.Cost*(1+g.costTolerance) {
+		result.addViolation(
+			ViolationCostRegression,
+			"estimated cost %.2f exceeds the baseline %.2f by more than %.0f%%",
+			plan.Cost, baseline.Cost, g.costTolerance*100,
+		)
+	}
+
+	return result, nil
+}
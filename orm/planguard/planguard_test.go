@@ -0,0 +1,183 @@
+package planguard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Account is a test model with an indexed and a non-indexed column.
+type Account struct {
+	orm.BaseModel `bun:"table:test_account,alias:ta"`
+
+	ID    string `bun:"id,pk"`
+	Email string `bun:"email,notnull"`
+	Name  string `bun:"name,notnull"`
+}
+
+func findByEmail(db orm.DB) orm.SelectQuery {
+	return db.NewSelect().Model((*Account)(nil)).Where(func(cb orm.ConditionBuilder) {
+		cb.Equals("email", "alice@example.com")
+	})
+}
+
+func findByName(db orm.DB) orm.SelectQuery {
+	return db.NewSelect().Model((*Account)(nil)).Where(func(cb orm.ConditionBuilder) {
+		cb.Equals("name", "Alice")
+	})
+}
+
+type PlanGuardTestSuite struct {
+	suite.Suite
+
+	ctx     context.Context
+	db      orm.DB
+	closeDB func() error
+}
+
+func (s *PlanGuardTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	bunDB, err := database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	s.closeDB = bunDB.Close
+
+	_, err = bunDB.NewCreateTable().Model((*Account)(nil)).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	_, err = bunDB.NewCreateIndex().Model((*Account)(nil)).Index("idx_test_account_email").Column("email").Exec(s.ctx)
+	s.Require().NoError(err)
+
+	s.db = iorm.New(bunDB)
+}
+
+func (s *PlanGuardTestSuite) TearDownSuite() {
+	s.Require().NoError(s.closeDB())
+}
+
+func (s *PlanGuardTestSuite) TestExplain() {
+	plan, err := Explain(s.ctx, s.db, findByEmail(s.db))
+	s.Require().NoError(err)
+	s.Equal("sqlite", plan.Dialect)
+	s.NotEmpty(plan.Nodes)
+	s.Contains(plan.Nodes[0], "idx_test_account_email", "Should search through the email index")
+	s.Empty(plan.SeqScans, "Indexed lookup should not scan the table")
+
+	plan, err = Explain(s.ctx, s.db, findByName(s.db))
+	s.Require().NoError(err)
+	s.Len(plan.SeqScans, 1, "Lookup on a non-indexed column should scan the table")
+}
+
+func (s *PlanGuardTestSuite) TestMissingBaseline() {
+	guard := New(s.T().TempDir(), WithUpdate(false)).Register(Query{Name: "account.by_email", Build: findByEmail})
+
+	report, err := guard.Check(s.ctx, s.db)
+	s.Require().NoError(err)
+
+	violations := report.Violations()
+	s.Require().Len(violations, 1)
+	s.Equal(ViolationMissingBaseline, violations[0].Kind)
+}
+
+func (s *PlanGuardTestSuite) TestUpdateAndCheck() {
+	dir := s.T().TempDir()
+	queries := []Query{
+		{Name: "account.by_email", Build: findByEmail},
+		{Name: "account.by_name", Build: findByName},
+	}
+
+	report, err := New(dir, WithUpdate(true)).Register(queries...).Check(s.ctx, s.db)
+	s.Require().NoError(err)
+	s.NoError(report.Err(), "Update mode should accept the current plans")
+
+	report, err = New(dir, WithUpdate(false)).Register(queries...).Check(s.ctx, s.db)
+	s.Require().NoError(err)
+	s.NoError(report.Err(), "Unchanged plans should match their baselines, including accepted scans")
+
+	for _, result := range report.Results {
+		s.False(result.Changed, "Plan of %s should not change", result.Query)
+	}
+}
+
+func (s *PlanGuardTestSuite) TestSeqScanRegression() {
+	dir := s.T().TempDir()
+
+	// Record the indexed plan as baseline, then point the same query name to a non-indexed lookup
+	_, err := New(dir, WithUpdate(true)).Register(Query{Name: "account.lookup", Build: findByEmail}).Check(s.ctx, s.db)
+	s.Require().NoError(err)
+
+	report, err := New(dir, WithUpdate(false)).Register(Query{Name: "account.lookup", Build: findByName}).Check(s.ctx, s.db)
+	s.Require().NoError(err)
+	s.Require().Len(report.Results, 1)
+	s.True(report.Results[0].Changed, "Plan should differ from the baseline")
+
+	violations := report.Violations()
+	s.Require().Len(violations, 1)
+	s.Equal(ViolationSeqScan, violations[0].Kind)
+	s.True(errors.Is(report.Err(), ErrPlanRegression))
+
+	table := report.Results[0].Plan.SeqScans[0]
+	report, err = New(dir, WithUpdate(false)).
+		Register(Query{Name: "account.lookup", Build: findByName, AllowSeqScan: []string{table}}).
+		Check(s.ctx, s.db)
+	s.Require().NoError(err)
+	s.NoError(report.Err(), "Allowed sequential scans should not be reported")
+}
+
+func (s *PlanGuardTestSuite) TestCostThresholds() {
+	result := Result{Query: "q"}
+	result.addViolation(ViolationMaxCost, "estimated cost %.2f exceeds the ceiling %.2f", 12.0, 10.0)
+
+	report := &Report{Results: []Result{result}}
+	s.ErrorIs(report.Err(), ErrPlanRegression)
+	s.Contains(report.Err().Error(), "q [max_cost]: estimated cost 12.00 exceeds the ceiling 10.00")
+}
+
+func (s *PlanGuardTestSuite) TestNormalizePostgresPlan() {
+	raw := `[{"Plan": {"Node Type": "Nested Loop", "Total Cost": 42.5, "Plan Rows": 10, "Plans": [
+		{"Node Type": "Seq Scan", "Relation Name": "sys_user", "Total Cost": 20.1},
+		{"Node Type": "Index Scan", "Relation Name": "sys_role", "Index Name": "sys_role_pkey", "Total Cost": 0.3}
+	]}}]`
+
+	plan := &Plan{}
+	s.Require().NoError(normalizePostgresPlan(raw, plan))
+	s.Equal(42.5, plan.Cost)
+	s.Equal([]string{"Nested Loop", "  Seq Scan on sys_user", "  Index Scan on sys_role using sys_role_pkey"}, plan.Nodes)
+	s.Equal([]string{"sys_user"}, plan.SeqScans)
+}
+
+func (s *PlanGuardTestSuite) TestNormalizeMySQLPlan() {
+	raw := `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "3.75"}, "nested_loop": [
+		{"table": {"table_name": "u", "access_type": "ALL", "rows_examined_per_scan": 10}},
+		{"table": {"table_name": "r", "access_type": "eq_ref", "key": "PRIMARY"}}
+	]}}`
+
+	plan := &Plan{}
+	s.Require().NoError(normalizeMySQLPlan(raw, plan))
+	s.Equal(3.75, plan.Cost)
+	s.Equal([]string{"  ALL on u", "  eq_ref on r using PRIMARY"}, plan.Nodes)
+	s.Equal([]string{"u"}, plan.SeqScans)
+}
+
+func (s *PlanGuardTestSuite) TestRegisterValidation() {
+	guard := New(s.T().TempDir())
+
+	s.Panics(func() { guard.Register(Query{Name: "../escape", Build: findByEmail}) }, "Should reject unsafe names")
+	s.Panics(func() { guard.Register(Query{Name: "no_builder"}) }, "Should reject queries without builder")
+	s.Panics(func() {
+		guard.Register(Query{Name: "dup", Build: findByEmail}, Query{Name: "dup", Build: findByName})
+	}, "Should reject duplicate names")
+}
+
+func TestPlanGuardTestSuite(t *testing.T) {
+	suite.Run(t, new(PlanGuardTestSuite))
+}
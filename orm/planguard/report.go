@@ -0,0 +1,79 @@
+package planguard
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ViolationKind classifies a plan regression.
+type ViolationKind string
+
+const (
+	// ViolationSeqScan reports a sequential scan that is neither allowed nor part of the baseline.
+	ViolationSeqScan ViolationKind = "seq_scan"
+	// ViolationMaxCost reports an estimated cost above the absolute ceiling of the query.
+	ViolationMaxCost ViolationKind = "max_cost"
+	// ViolationCostRegression reports an estimated cost growth beyond the tolerance relative to the baseline.
+	ViolationCostRegression ViolationKind = "cost_regression"
+	// ViolationMissingBaseline reports a registered query without a stored baseline.
+	ViolationMissingBaseline ViolationKind = "missing_baseline"
+)
+
+// Violation is a single plan regression of a query.
+type Violation struct {
+	Query   string        `json:"query"`
+	Kind    ViolationKind `json:"kind"`
+	Message string        `json:"message"`
+}
+
+// Result is the outcome of checking a single query.
+type Result struct {
+	// Query is the name of the checked query.
+	Query string `json:"query"`
+	// Plan is the current normalized plan.
+	Plan *Plan `json:"plan"`
+	// Baseline is the stored plan, nil when missing or in update mode.
+	Baseline *Plan `json:"baseline,omitempty"`
+	// Changed reports whether the plan shape differs from the baseline.
+	Changed bool `json:"changed"`
+	// Violations lists the detected regressions.
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+func (r *Result) addViolation(kind ViolationKind, format string, args ...any) {
+	r.Violations = append(r.Violations, Violation{
+		Query:   r.Query,
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Report collects the results of a guard check.
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Violations returns the violations of all queries.
+func (r *Report) Violations() []Violation {
+	var violations []Violation
+	for _, result := range r.Results {
+		violations = append(violations, result.Violations...)
+	}
+
+	return violations
+}
+
+// Err returns an error wrapping ErrPlanRegression that lists all violations, or nil when there are none.
+func (r *Report) Err() error {
+	violations := r.Violations()
+	if len(violations) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	for _, violation := range violations {
+		_, _ = fmt.Fprintf(&sb, "\n  %s [%s]: %s", violation.Query, violation.Kind, violation.Message)
+	}
+
+	return fmt.Errorf("%w:%s", ErrPlanRegression, sb.String())
+}
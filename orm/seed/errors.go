@@ -0,0 +1,14 @@
+package seed
+
+import "errors"
+
+var (
+	// ErrUnsupportedDB indicates the database is not backed by the framework orm.
+	ErrUnsupportedDB = errors.New("seeding requires a database created by the orm")
+	// ErrUnknownStep indicates a step to run or a dependency is not registered.
+	ErrUnknownStep = errors.New("unknown seed step")
+	// ErrDependencyCycle indicates steps depending on each other.
+	ErrDependencyCycle = errors.New("seed steps depend on each other")
+	// ErrUnknownFake indicates a fixture asks for a kind of fake value the faker does not generate.
+	ErrUnknownFake = errors.New("unknown kind of fake value")
+)
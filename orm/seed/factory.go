@@ -0,0 +1,34 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+)
+
+// Factory builds the i-th of a batch of rows, usually filling its fields with fake values.
+type Factory[T any] func(faker *Faker, i int) *T
+
+// Build builds n rows with the factory without inserting them.
+func Build[T any](faker *Faker, n int, factory Factory[T]) []*T {
+	rows := make([]*T, n)
+	for i := range rows {
+		rows[i] = factory(faker, i)
+	}
+
+	return rows
+}
+
+// Create builds n rows with the factory and inserts them, returning the inserted rows for later steps
+// to reference.
+func Create[T any](ctx context.Context, session *Session, n int, factory Factory[T]) ([]*T, error) {
+	rows := Build(session.Faker, n, factory)
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	if _, err := session.DB.NewInsert().Model(&rows).Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to insert %d rows of %T: %w", n, rows[0], err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,157 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+var (
+	firstNames = []string{
+		"Alice", "Bob", "Charlie", "Diana", "Ethan", "Fiona", "George", "Hannah", "Ivan", "Julia",
+		"Kevin", "Laura", "Michael", "Nina", "Oscar", "Paula", "Quentin", "Rachel", "Samuel", "Tina",
+	}
+	lastNames = []string{
+		"Anderson", "Brown", "Clark", "Davis", "Evans", "Foster", "Garcia", "Harris", "Jackson", "Johnson",
+		"King", "Lewis", "Martin", "Nelson", "Owens", "Parker", "Roberts", "Smith", "Taylor", "Walker",
+	}
+	words = []string{
+		"alpha", "bridge", "cloud", "delta", "engine", "forest", "garden", "harbor", "island", "journey",
+		"kernel", "ledger", "meadow", "nebula", "orbit", "pixel", "quartz", "river", "signal", "timber",
+		"update", "vector", "window", "yield", "zephyr",
+	}
+)
+
+// Faker generates fake values from a seeded source, so seeding again generates the same values.
+// It is safe for concurrent use.
+type Faker struct {
+	mu     sync.Mutex
+	rand   *rand.Rand
+	emails int
+}
+
+// NewFaker creates a faker generating the values determined by the seed.
+func NewFaker(seed uint64) *Faker {
+	return &Faker{rand: rand.New(rand.NewPCG(seed, seed))}
+}
+
+func (f *Faker) pick(values []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return values[f.rand.IntN(len(values))]
+}
+
+// FirstName returns a first name.
+func (f *Faker) FirstName() string {
+	return f.pick(firstNames)
+}
+
+// LastName returns a last name.
+func (f *Faker) LastName() string {
+	return f.pick(lastNames)
+}
+
+// Name returns a full name.
+func (f *Faker) Name() string {
+	return f.FirstName() + constants.Space + f.LastName()
+}
+
+// Email returns an email address at example.com, unique among the addresses of the faker.
+func (f *Faker) Email() string {
+	local := strings.ToLower(f.FirstName() + constants.Dot + f.LastName())
+
+	f.mu.Lock()
+	f.emails++
+	n := f.emails
+	f.mu.Unlock()
+
+	return fmt.Sprintf("%s%d@example.com", local, n)
+}
+
+// Phone returns a phone number in the reserved 555 range.
+func (f *Faker) Phone() string {
+	return fmt.Sprintf("+1-555-%03d-%04d", f.IntBetween(100, 999), f.IntBetween(0, 9999))
+}
+
+// Word returns a word.
+func (f *Faker) Word() string {
+	return f.pick(words)
+}
+
+// Sentence returns a capitalized sentence of the given number of words.
+func (f *Faker) Sentence(wordCount int) string {
+	parts := make([]string, max(wordCount, 1))
+	for i := range parts {
+		parts[i] = f.Word()
+	}
+
+	sentence := strings.Join(parts, constants.Space)
+
+	return strings.ToUpper(sentence[:1]) + sentence[1:] + constants.Dot
+}
+
+// IntBetween returns an integer between minimum and maximum, both included.
+func (f *Faker) IntBetween(minimum, maximum int) int {
+	if maximum <= minimum {
+		return minimum
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return minimum + f.rand.IntN(maximum-minimum+1)
+}
+
+// Bool returns true or false.
+func (f *Faker) Bool() bool {
+	return f.IntBetween(0, 1) == 1
+}
+
+// Pick returns one of the options.
+func (f *Faker) Pick(options ...string) string {
+	if len(options) == 0 {
+		return constants.Empty
+	}
+
+	return f.pick(options)
+}
+
+// TimeBetween returns a time between from and to.
+func (f *Faker) TimeBetween(from, to time.Time) time.Time {
+	if !to.After(from) {
+		return from
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return from.Add(time.Duration(f.rand.Int64N(int64(to.Sub(from)))))
+}
+
+// Fake returns a value of the given kind: name, first_name, last_name, email, phone, word or sentence.
+// Fixtures call it as "{{ fake \"email\" }}".
+func (f *Faker) Fake(kind string) (string, error) {
+	switch kind {
+	case "name":
+		return f.Name(), nil
+	case "first_name":
+		return f.FirstName(), nil
+	case "last_name":
+		return f.LastName(), nil
+	case "email":
+		return f.Email(), nil
+	case "phone":
+		return f.Phone(), nil
+	case "word":
+		return f.Word(), nil
+	case "sentence":
+		return f.Sentence(f.IntBetween(4, 10)), nil
+	default:
+		return constants.Empty, fmt.Errorf("%w: %q", ErrUnknownFake, kind)
+	}
+}
@@ -0,0 +1,70 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"text/template"
+
+	"github.com/uptrace/bun/dbfixture"
+
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/id"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+)
+
+// Fixtures returns a step loading the given YAML fixture files, see Session.LoadFixtures.
+func Fixtures(name string, fsys fs.FS, files ...string) Step {
+	return Step{
+		Name: name,
+		Run: func(ctx context.Context, session *Session) error {
+			return session.LoadFixtures(ctx, fsys, files...)
+		},
+	}
+}
+
+// LoadFixtures inserts the rows of the given YAML fixture files, listing rows per model as the test fixtures do:
+//
+//	# fixtures/users.yaml
+//	- model: User
+//	  rows:
+//	    - _id: alice
+//	      id: "{{ id }}"
+//	      name: "{{ fake \"name\" }}"
+//	      age: "{{ fakeInt 18 60 }}"
+//	      created_at: "{{ now }}"
+//
+// Rows reference the fields of earlier rows through their _id, e.g. "{{ $.User.alice.ID }}". The models must be
+// registered through Seeder.Models. Templates can call id for a new identifier, now for the current time, and
+// fake and fakeInt for values of the faker of the session.
+func (s *Session) LoadFixtures(ctx context.Context, fsys fs.FS, files ...string) error {
+	bunDB, ok := s.DB.(*iorm.BunDB)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnsupportedDB, s.DB)
+	}
+
+	opts := []dbfixture.FixtureOption{
+		dbfixture.WithTemplateFuncs(template.FuncMap{
+			"id": func() string {
+				return id.Generate()
+			},
+			"now": func() string {
+				return datetime.Now().String()
+			},
+			"fake": s.Faker.Fake,
+			// Fixtures evaluate integer literals as int64.
+			"fakeInt": func(minimum, maximum int64) int64 {
+				return int64(s.Faker.IntBetween(int(minimum), int(maximum)))
+			},
+		}),
+	}
+	if s.truncateTables {
+		opts = append(opts, dbfixture.WithTruncateTables())
+	}
+
+	if err := dbfixture.New(bunDB.Unwrap(), opts...).Load(ctx, fsys, files...); err != nil {
+		return fmt.Errorf("failed to load fixtures %v: %w", files, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package seed
+
+// Option configures a Seeder.
+type Option func(*Seeder)
+
+// WithEnv sets the environment seeded, which selects the steps restricted to environments.
+func WithEnv(env string) Option {
+	return func(s *Seeder) {
+		s.env = env
+	}
+}
+
+// WithFaker sets the faker shared by the steps, a faker seeded with 1 by default so runs seed the same values.
+func WithFaker(faker *Faker) Option {
+	return func(s *Seeder) {
+		s.faker = faker
+	}
+}
+
+// WithTruncateTables truncates the tables of the models of fixtures before loading their rows,
+// so seeding again replaces the rows instead of failing on duplicate keys.
+func WithTruncateTables() Option {
+	return func(s *Seeder) {
+		s.truncateTables = true
+	}
+}
@@ -0,0 +1,207 @@
+// Package seed fills databases with development and staging data.
+//
+// A Seeder runs named steps in dependency order, each in its own transaction. Steps load YAML fixtures, the format
+// of the framework test fixtures, or create rows through Go factories generating fake values with a Faker.
+// Steps can be restricted to environments, e.g. demo accounts only seeded on stage. vef-cli db seed runs the
+// seeding entry point of a project, which calls RunFromEnv.
+package seed
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	ilog "github.com/ilxqx/vef-framework-go/internal/log"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/log"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Step is a named unit of seeding data.
+type Step struct {
+	// Name identifies the step for dependencies and selective runs.
+	Name string
+	// DependsOn names the steps seeding the data this step references, run before it.
+	DependsOn []string
+	// Envs restricts the step to the given environments, it runs in all of them when empty.
+	Envs []string
+	// Run seeds the data through the session.
+	Run func(ctx context.Context, session *Session) error
+}
+
+// Session gives the steps access to the database, within the transaction of the step, and the faker.
+type Session struct {
+	// DB is the database within the transaction of the step.
+	DB orm.DB
+	// Faker generates fake values, shared by all steps of a run.
+	Faker *Faker
+	// Env is the environment seeded.
+	Env string
+
+	truncateTables bool
+}
+
+// Seeder runs the registered steps in dependency order.
+type Seeder struct {
+	db             orm.DB
+	env            string
+	faker          *Faker
+	truncateTables bool
+	steps          map[string]Step
+	logger         log.Logger
+}
+
+// New creates a seeder for the database.
+func New(db orm.DB, opts ...Option) (*Seeder, error) {
+	if _, ok := db.(*iorm.BunDB); !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedDB, db)
+	}
+
+	s := &Seeder{
+		db:     db,
+		faker:  NewFaker(1),
+		steps:  make(map[string]Step),
+		logger: ilog.Named("seed"),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// Register adds steps to the seeder. A step replaces the registered step of the same name.
+func (s *Seeder) Register(steps ...Step) *Seeder {
+	for _, step := range steps {
+		s.steps[step.Name] = step
+	}
+
+	return s
+}
+
+// Models registers the models fixtures reference by name, e.g. "model: User".
+func (s *Seeder) Models(models ...any) *Seeder {
+	for _, model := range models {
+		_ = s.db.TableOf(model)
+	}
+
+	return s
+}
+
+// Run runs the named steps, all registered steps without names, after the steps they depend on.
+// Steps not meant for the environment of the seeder are skipped along with the steps depending on them.
+func (s *Seeder) Run(ctx context.Context, names ...string) error {
+	steps, err := s.plan(names)
+	if err != nil {
+		return err
+	}
+
+	skipped := make(map[string]bool)
+
+	for _, step := range steps {
+		if !s.runsIn(step) || slices.ContainsFunc(step.DependsOn, func(name string) bool { return skipped[name] }) {
+			skipped[step.Name] = true
+			s.logger.Infof("Skipping seed step %s in environment %q", step.Name, s.env)
+
+			continue
+		}
+
+		if err := s.db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+			return step.Run(ctx, &Session{
+				DB:             tx,
+				Faker:          s.faker,
+				Env:            s.env,
+				truncateTables: s.truncateTables,
+			})
+		}); err != nil {
+			return fmt.Errorf("seed: step %s failed: %w", step.Name, err)
+		}
+
+		s.logger.Infof("Seeded step %s", step.Name)
+	}
+
+	return nil
+}
+
+// RunFromEnv runs the steps listed comma-separated in VEF_SEED_STEPS, all when unset, in the environment named by
+// VEF_SEED_ENV unless set through WithEnv. vef-cli db seed sets both variables.
+func (s *Seeder) RunFromEnv(ctx context.Context) error {
+	if s.env == constants.Empty {
+		s.env = os.Getenv(constants.EnvSeedEnv)
+	}
+
+	var names []string
+	for name := range strings.SplitSeq(os.Getenv(constants.EnvSeedSteps), constants.Comma) {
+		if name = strings.TrimSpace(name); name != constants.Empty {
+			names = append(names, name)
+		}
+	}
+
+	return s.Run(ctx, names...)
+}
+
+func (s *Seeder) runsIn(step Step) bool {
+	return len(step.Envs) == 0 || slices.Contains(step.Envs, s.env)
+}
+
+// plan orders the named steps and the steps they depend on, dependencies first and otherwise by name.
+func (s *Seeder) plan(names []string) ([]Step, error) {
+	if len(names) == 0 {
+		names = make([]string, 0, len(s.steps))
+		for name := range s.steps {
+			names = append(names, name)
+		}
+	}
+
+	slices.SortFunc(names, cmp.Compare)
+
+	var (
+		sorted []Step
+		state  = make(map[string]int, len(s.steps)) // 1 while visiting, 2 once sorted
+		visit  func(name string, path []string) error
+	)
+
+	visit = func(name string, path []string) error {
+		step, ok := s.steps[name]
+		if !ok {
+			if len(path) == 0 {
+				return fmt.Errorf("%w: %s", ErrUnknownStep, name)
+			}
+
+			return fmt.Errorf("%w: %s, required by %s", ErrUnknownStep, name, path[len(path)-1])
+		}
+
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(append(path, name), " -> "))
+		case 2:
+			return nil
+		}
+
+		state[name] = 1
+
+		for _, dependency := range slices.Sorted(slices.Values(step.DependsOn)) {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		state[name] = 2
+		sorted = append(sorted, step)
+
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
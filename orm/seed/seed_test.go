@@ -0,0 +1,225 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/id"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+type SeedUser struct {
+	orm.BaseModel `bun:"table:test_seed_user"`
+
+	ID    string `bun:"id,pk"`
+	Name  string `bun:"name,notnull"`
+	Email string `bun:"email,notnull,unique"`
+	Age   int    `bun:"age,notnull"`
+}
+
+type SeedPost struct {
+	orm.BaseModel `bun:"table:test_seed_post"`
+
+	ID     string `bun:"id,pk"`
+	UserID string `bun:"user_id,notnull"`
+	Title  string `bun:"title,notnull"`
+}
+
+var userFactory Factory[SeedUser] = func(faker *Faker, _ int) *SeedUser {
+	return &SeedUser{
+		ID:    id.Generate(),
+		Name:  faker.Name(),
+		Email: faker.Email(),
+		Age:   faker.IntBetween(18, 60),
+	}
+}
+
+type SeedTestSuite struct {
+	suite.Suite
+
+	ctx     context.Context
+	db      orm.DB
+	closeDB func() error
+}
+
+func (s *SeedTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	bunDB, err := database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	s.closeDB = bunDB.Close
+	s.db = iorm.New(bunDB)
+
+	for _, model := range []any{(*SeedUser)(nil), (*SeedPost)(nil)} {
+		_, err = bunDB.NewCreateTable().Model(model).Exec(s.ctx)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *SeedTestSuite) TearDownTest() {
+	s.Require().NoError(s.closeDB())
+}
+
+func (s *SeedTestSuite) newSeeder(opts ...Option) *Seeder {
+	seeder, err := New(s.db, opts...)
+	s.Require().NoError(err)
+
+	return seeder.Models((*SeedUser)(nil), (*SeedPost)(nil))
+}
+
+// recorder returns a step recording its name in the order of the run.
+func recorder(order *[]string, name string, dependsOn ...string) Step {
+	return Step{
+		Name:      name,
+		DependsOn: dependsOn,
+		Run: func(context.Context, *Session) error {
+			*order = append(*order, name)
+
+			return nil
+		},
+	}
+}
+
+func (s *SeedTestSuite) TestDependencyOrder() {
+	var order []string
+
+	seeder := s.newSeeder().Register(
+		recorder(&order, "posts", "users", "categories"),
+		recorder(&order, "users", "roles"),
+		recorder(&order, "roles"),
+		recorder(&order, "categories"),
+	)
+
+	s.Require().NoError(seeder.Run(s.ctx))
+	s.Equal([]string{"categories", "roles", "users", "posts"}, order, "Dependencies should run first")
+
+	order = nil
+	s.Require().NoError(seeder.Run(s.ctx, "users"))
+	s.Equal([]string{"roles", "users"}, order, "Named steps should run with their dependencies only")
+}
+
+func (s *SeedTestSuite) TestPlanErrors() {
+	seeder := s.newSeeder().Register(
+		Step{Name: "a", DependsOn: []string{"b"}},
+		Step{Name: "b", DependsOn: []string{"a"}},
+		Step{Name: "c", DependsOn: []string{"missing"}},
+	)
+
+	s.ErrorIs(seeder.Run(s.ctx, "a"), ErrDependencyCycle)
+	s.ErrorContains(seeder.Run(s.ctx, "c"), "missing, required by c")
+	s.ErrorIs(seeder.Run(s.ctx, "unknown"), ErrUnknownStep)
+}
+
+func (s *SeedTestSuite) TestEnvs() {
+	var order []string
+
+	demo := recorder(&order, "demo")
+	demo.Envs = []string{"stage"}
+
+	steps := []Step{recorder(&order, "base"), demo, recorder(&order, "demo_posts", "demo")}
+
+	s.Require().NoError(s.newSeeder(WithEnv("dev")).Register(steps...).Run(s.ctx))
+	s.Equal([]string{"base"}, order, "Steps of other environments and their dependents should be skipped")
+
+	order = nil
+	s.Require().NoError(s.newSeeder(WithEnv("stage")).Register(steps...).Run(s.ctx))
+	s.Equal([]string{"base", "demo", "demo_posts"}, order)
+}
+
+func (s *SeedTestSuite) TestRunFromEnv() {
+	var order []string
+
+	s.T().Setenv(constants.EnvSeedEnv, "dev")
+	s.T().Setenv(constants.EnvSeedSteps, "users, roles")
+
+	seeder := s.newSeeder().Register(recorder(&order, "users"), recorder(&order, "roles"), recorder(&order, "posts"))
+	s.Require().NoError(seeder.RunFromEnv(s.ctx))
+	s.Equal([]string{"roles", "users"}, order)
+}
+
+func (s *SeedTestSuite) TestFixtures() {
+	s.Require().NoError(s.newSeeder().Register(Fixtures("users", os.DirFS("testdata"), "users.yaml")).Run(s.ctx))
+
+	var user SeedUser
+	s.Require().NoError(s.db.NewSelect().Model(&user).Scan(s.ctx))
+	s.Equal("Alice Johnson", user.Name)
+	s.Contains(user.Email, "@example.com", "Should fill fake values")
+	s.GreaterOrEqual(user.Age, 18)
+
+	var post SeedPost
+	s.Require().NoError(s.db.NewSelect().Model(&post).Scan(s.ctx))
+	s.Equal(user.ID, post.UserID, "Should resolve references to earlier rows")
+	s.NotEmpty(post.Title)
+
+	s.Require().NoError(
+		s.newSeeder(WithTruncateTables()).Register(Fixtures("users", os.DirFS("testdata"), "users.yaml")).Run(s.ctx),
+		"Should seed again after truncating the tables",
+	)
+
+	count, err := s.db.NewSelect().Model((*SeedUser)(nil)).Count(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(1), count)
+}
+
+func (s *SeedTestSuite) TestFactories() {
+	var created []*SeedUser
+
+	seeder := s.newSeeder().Register(Step{
+		Name: "users",
+		Run: func(ctx context.Context, session *Session) (err error) {
+			created, err = Create(ctx, session, 5, userFactory)
+
+			return err
+		},
+	})
+	s.Require().NoError(seeder.Run(s.ctx))
+	s.Len(created, 5)
+
+	var users []SeedUser
+	s.Require().NoError(s.db.NewSelect().Model(&users).Scan(s.ctx))
+	s.Len(users, 5, "Should insert the built rows")
+
+	first, second := Build(NewFaker(7), 3, userFactory), Build(NewFaker(7), 3, userFactory)
+	for i := range first {
+		s.Equal(first[i].Name, second[i].Name, "Fakers with the same seed should generate the same values")
+		s.Equal(first[i].Email, second[i].Email)
+	}
+}
+
+func (s *SeedTestSuite) TestRollback() {
+	errFailed := errors.New("failed")
+
+	seeder := s.newSeeder().Register(Step{
+		Name: "users",
+		Run: func(ctx context.Context, session *Session) error {
+			if _, err := Create(ctx, session, 2, userFactory); err != nil {
+				return err
+			}
+
+			return errFailed
+		},
+	})
+	s.ErrorIs(seeder.Run(s.ctx), errFailed)
+
+	count, err := s.db.NewSelect().Model((*SeedUser)(nil)).Count(s.ctx)
+	s.Require().NoError(err)
+	s.Zero(count, "Should roll back the rows of the failed step")
+}
+
+func (s *SeedTestSuite) TestUnknownFake() {
+	_, err := NewFaker(1).Fake("planet")
+	s.ErrorIs(err, ErrUnknownFake)
+}
+
+func TestSeed(t *testing.T) {
+	suite.Run(t, new(SeedTestSuite))
+}
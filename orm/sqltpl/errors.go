@@ -0,0 +1,11 @@
+package sqltpl
+
+import "errors"
+
+var (
+	ErrSyntax       = errors.New("sql template syntax error")
+	ErrNotFound     = errors.New("sql template not found")
+	ErrDuplicate    = errors.New("sql template already defined")
+	ErrMissingParam = errors.New("sql template parameter not found")
+	ErrInvalidParam = errors.New("invalid sql template parameter")
+)
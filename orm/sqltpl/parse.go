@@ -0,0 +1,383 @@
+package sqltpl
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+const (
+	tagOpen    = "{{"
+	tagClose   = "}}"
+	paramOpen  = "#{"
+	paramClose = "}"
+)
+
+// node is an element of a parsed template.
+type node interface {
+	render(s *renderState) error
+}
+
+type textNode string
+
+// paramNode binds the value at path as a query argument.
+type paramNode struct {
+	path string
+}
+
+type ifBranch struct {
+	path   string
+	negate bool
+	body   []node
+}
+
+// ifNode renders the first branch whose condition holds, or the else body.
+type ifNode struct {
+	branches []ifBranch
+	elseBody []node
+}
+
+// trimNode renders its body behind a keyword, dropping dangling separators; nothing is rendered for an empty body.
+type trimNode struct {
+	keyword string
+	body    []node
+}
+
+// foreachNode renders its body for every element of the collection at path.
+type foreachNode struct {
+	path      string
+	item      string
+	index     string
+	separator string
+	body      []node
+}
+
+// includeNode renders a named fragment.
+type includeNode struct {
+	name string
+}
+
+// parser builds the node tree of a template.
+type parser struct {
+	name string
+	text string
+	pos  int
+}
+
+// parse parses the template text.
+func parse(name, text string) ([]node, error) {
+	p := &parser{name: name, text: text}
+
+	nodes, end, err := p.parseNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if end != constants.Empty {
+		return nil, p.errorf("unexpected {{%s}}", end)
+	}
+
+	return nodes, nil
+}
+
+// parseNodes parses nodes until the end of the text or a closing tag ("end", "else", "else if ..."),
+// which is returned without its delimiters.
+func (p *parser) parseNodes() (nodes []node, end string, err error) {
+	for p.pos < len(p.text) {
+		rest := p.text[p.pos:]
+		tagAt := strings.Index(rest, tagOpen)
+		paramAt := strings.Index(rest, paramOpen)
+
+		next := tagAt
+		if next < 0 || paramAt >= 0 && paramAt < next {
+			next = paramAt
+		}
+
+		if next < 0 {
+			nodes = append(nodes, textNode(rest))
+			p.pos = len(p.text)
+
+			break
+		}
+
+		if next > 0 {
+			nodes = append(nodes, textNode(rest[:next]))
+			p.pos += next
+		}
+
+		if next == paramAt {
+			param, err := p.parseParam()
+			if err != nil {
+				return nil, constants.Empty, err
+			}
+
+			nodes = append(nodes, param)
+
+			continue
+		}
+
+		tag, err := p.readTag()
+		if err != nil {
+			return nil, constants.Empty, err
+		}
+
+		keyword, args := splitTag(tag)
+		switch keyword {
+		case "end", "else":
+			return nodes, tag, nil
+		case "if":
+			n, err := p.parseIf(args)
+			if err != nil {
+				return nil, constants.Empty, err
+			}
+
+			nodes = append(nodes, n)
+		case "where", "set":
+			n, err := p.parseTrim(keyword, args)
+			if err != nil {
+				return nil, constants.Empty, err
+			}
+
+			nodes = append(nodes, n)
+		case "foreach":
+			n, err := p.parseForeach(args)
+			if err != nil {
+				return nil, constants.Empty, err
+			}
+
+			nodes = append(nodes, n)
+		case "include":
+			if !isIdentifier(args, true) {
+				return nil, constants.Empty, p.errorf("invalid fragment name %q", args)
+			}
+
+			nodes = append(nodes, &includeNode{name: args})
+		default:
+			return nil, constants.Empty, p.errorf("unknown tag {{%s}}", tag)
+		}
+	}
+
+	return nodes, constants.Empty, nil
+}
+
+func (p *parser) parseParam() (*paramNode, error) {
+	start := p.pos + len(paramOpen)
+
+	closeAt := strings.Index(p.text[start:], paramClose)
+	if closeAt < 0 {
+		return nil, p.errorf("unclosed #{")
+	}
+
+	path := strings.TrimSpace(p.text[start : start+closeAt])
+	if !isPath(path) {
+		return nil, p.errorf("invalid parameter #{%s}", path)
+	}
+
+	p.pos = start + closeAt + len(paramClose)
+
+	return &paramNode{path: path}, nil
+}
+
+// readTag reads a {{...}} tag and returns its trimmed content.
+func (p *parser) readTag() (string, error) {
+	start := p.pos + len(tagOpen)
+
+	closeAt := strings.Index(p.text[start:], tagClose)
+	if closeAt < 0 {
+		return constants.Empty, p.errorf("unclosed {{")
+	}
+
+	p.pos = start + closeAt + len(tagClose)
+
+	return strings.TrimSpace(p.text[start : start+closeAt]), nil
+}
+
+func (p *parser) parseIf(cond string) (*ifNode, error) {
+	n := new(ifNode)
+
+	for {
+		branch, err := p.parseCondition(cond)
+		if err != nil {
+			return nil, err
+		}
+
+		if branch.body, cond, err = p.parseBlockBody("if"); err != nil {
+			return nil, err
+		}
+
+		n.branches = append(n.branches, branch)
+
+		switch {
+		case cond == "end":
+			return n, nil
+		case cond == "else":
+			body, end, err := p.parseBlockBody("else")
+			if err != nil {
+				return nil, err
+			}
+
+			if end != "end" {
+				return nil, p.errorf("expected {{end}} after {{else}}, got {{%s}}", end)
+			}
+
+			n.elseBody = body
+
+			return n, nil
+		default:
+			// {{else if <cond>}}
+			cond = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(cond, "else")), "if"))
+		}
+	}
+}
+
+func (p *parser) parseCondition(cond string) (ifBranch, error) {
+	var branch ifBranch
+
+	cond = strings.TrimSpace(cond)
+	if rest, ok := strings.CutPrefix(cond, "!"); ok {
+		branch.negate = true
+		cond = strings.TrimSpace(rest)
+	}
+
+	if !isPath(cond) {
+		return branch, p.errorf("invalid condition %q", cond)
+	}
+
+	branch.path = cond
+
+	return branch, nil
+}
+
+// parseBlockBody parses the body of a block up to its closing tag, which must be "end", "else" or "else if ...".
+func (p *parser) parseBlockBody(block string) ([]node, string, error) {
+	body, end, err := p.parseNodes()
+	if err != nil {
+		return nil, constants.Empty, err
+	}
+
+	if end == constants.Empty {
+		return nil, constants.Empty, p.errorf("missing {{end}} for {{%s}}", block)
+	}
+
+	if end != "end" && block != "if" {
+		return nil, constants.Empty, p.errorf("unexpected {{%s}} in {{%s}}", end, block)
+	}
+
+	if keyword, args := splitTag(end); keyword == "else" && args != constants.Empty && !strings.HasPrefix(args, "if ") {
+		return nil, constants.Empty, p.errorf("invalid tag {{%s}}", end)
+	}
+
+	return body, end, nil
+}
+
+func (p *parser) parseTrim(keyword, args string) (*trimNode, error) {
+	if args != constants.Empty {
+		return nil, p.errorf("{{%s}} takes no arguments", keyword)
+	}
+
+	body, end, err := p.parseBlockBody(keyword)
+	if err != nil {
+		return nil, err
+	}
+
+	if end != "end" {
+		return nil, p.errorf("unexpected {{%s}} in {{%s}}", end, keyword)
+	}
+
+	return &trimNode{keyword: strings.ToUpper(keyword), body: body}, nil
+}
+
+// parseForeach parses "<path> as <item>[, <index>] [join <separator>]",
+// where the separator is a double-quoted string.
+func (p *parser) parseForeach(args string) (*foreachNode, error) {
+	n := new(foreachNode)
+
+	if before, separator, ok := strings.Cut(args, " join "); ok {
+		separator = strings.TrimSpace(separator)
+		if len(separator) < 2 || separator[0] != '"' || separator[len(separator)-1] != '"' {
+			return nil, p.errorf("foreach separator must be a double-quoted string, got %s", separator)
+		}
+
+		n.separator = separator[1 : len(separator)-1]
+		args = before
+	}
+
+	path, vars, ok := strings.Cut(args, " as ")
+	if !ok {
+		return nil, p.errorf("foreach must be written as {{foreach <collection> as <item>[, <index>]}}")
+	}
+
+	n.path = strings.TrimSpace(path)
+	if !isPath(n.path) {
+		return nil, p.errorf("invalid foreach collection %q", n.path)
+	}
+
+	item, index, _ := strings.Cut(vars, constants.Comma)
+	n.item, n.index = strings.TrimSpace(item), strings.TrimSpace(index)
+
+	if !isIdentifier(n.item, false) || n.index != constants.Empty && !isIdentifier(n.index, false) {
+		return nil, p.errorf("invalid foreach variables %q", vars)
+	}
+
+	body, end, err := p.parseBlockBody("foreach")
+	if err != nil {
+		return nil, err
+	}
+
+	if end != "end" {
+		return nil, p.errorf("unexpected {{%s}} in {{foreach}}", end)
+	}
+
+	n.body = body
+
+	return n, nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	line := strings.Count(p.text[:min(p.pos, len(p.text))], "\n") + 1
+
+	return fmt.Errorf("%w: %s:%d: %s", ErrSyntax, p.name, line, fmt.Sprintf(format, args...))
+}
+
+// splitTag splits a tag into its keyword and trimmed arguments.
+func splitTag(tag string) (keyword, args string) {
+	keyword, args, _ = strings.Cut(tag, constants.Space)
+
+	return keyword, strings.TrimSpace(args)
+}
+
+// isPath reports whether s is a dotted path of identifiers, e.g. "user.name".
+func isPath(s string) bool {
+	if s == constants.Empty {
+		return false
+	}
+
+	for segment := range strings.SplitSeq(s, constants.Dot) {
+		if !isIdentifier(segment, false) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isIdentifier reports whether s is an identifier, optionally allowing dots, dashes and slashes for fragment names.
+func isIdentifier(s string, name bool) bool {
+	if s == constants.Empty {
+		return false
+	}
+
+	for i, r := range s {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case unicode.IsDigit(r) && i > 0:
+		case name && i > 0 && (r == '.' || r == '-' || r == '/'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
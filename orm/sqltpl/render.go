@@ -0,0 +1,275 @@
+package sqltpl
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// maxIncludeDepth bounds nested fragment includes to detect cycles.
+const maxIncludeDepth = 16
+
+var (
+	// leadingOperatorRegex matches the dangling operator at the start of a where body.
+	leadingOperatorRegex = regexp.MustCompile(`(?i)^(AND|OR)\b\s*`)
+	// trailingCommaRegex matches the dangling comma at the end of a set body.
+	trailingCommaRegex = regexp.MustCompile(`\s*,$`)
+)
+
+// renderState accumulates the query text and its arguments while rendering.
+type renderState struct {
+	sb     *strings.Builder
+	args   []any
+	params reflect.Value
+	scopes []map[string]reflect.Value
+	frags  map[string][]node
+	depth  int
+}
+
+func (s *renderState) renderNodes(nodes []node) error {
+	for _, n := range nodes {
+		if err := n.render(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderSub renders nodes into a separate buffer and returns the rendered text, keeping the arguments.
+func (s *renderState) renderSub(nodes []node) (string, error) {
+	saved := s.sb
+	s.sb = new(strings.Builder)
+
+	err := s.renderNodes(nodes)
+	text := s.sb.String()
+	s.sb = saved
+
+	return text, err
+}
+
+// lookup resolves a dotted path against the loop variables, innermost first, and then the parameters.
+func (s *renderState) lookup(path string) (reflect.Value, bool) {
+	segments := strings.Split(path, constants.Dot)
+
+	var (
+		current reflect.Value
+		found   bool
+	)
+
+	for i := len(s.scopes) - 1; i >= 0; i-- {
+		if current, found = s.scopes[i][segments[0]]; found {
+			segments = segments[1:]
+
+			break
+		}
+	}
+
+	if !found {
+		current = s.params
+	}
+
+	for _, segment := range segments {
+		if current, found = field(current, segment); !found {
+			return reflect.Value{}, false
+		}
+	}
+
+	return current, true
+}
+
+func (t textNode) render(s *renderState) error {
+	s.sb.WriteString(string(t))
+
+	return nil
+}
+
+func (n *paramNode) render(s *renderState) error {
+	value, ok := s.lookup(n.path)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMissingParam, n.path)
+	}
+
+	s.sb.WriteString(constants.QuestionMark)
+	s.args = append(s.args, bindValue(value))
+
+	return nil
+}
+
+func (n *ifNode) render(s *renderState) error {
+	for _, branch := range n.branches {
+		value, _ := s.lookup(branch.path)
+		if truthy(value) != branch.negate {
+			return s.renderNodes(branch.body)
+		}
+	}
+
+	return s.renderNodes(n.elseBody)
+}
+
+func (n *trimNode) render(s *renderState) error {
+	body, err := s.renderSub(n.body)
+	if err != nil {
+		return err
+	}
+
+	body = strings.TrimSpace(body)
+	if n.keyword == "WHERE" {
+		body = leadingOperatorRegex.ReplaceAllString(body, constants.Empty)
+	} else {
+		body = trailingCommaRegex.ReplaceAllString(body, constants.Empty)
+	}
+
+	if body == constants.Empty {
+		return nil
+	}
+
+	s.sb.WriteString(n.keyword)
+	s.sb.WriteString(constants.Space)
+	s.sb.WriteString(body)
+
+	return nil
+}
+
+func (n *foreachNode) render(s *renderState) error {
+	collection, _ := s.lookup(n.path)
+	collection = indirect(collection)
+
+	if !collection.IsValid() {
+		return nil
+	}
+
+	if collection.Kind() != reflect.Slice && collection.Kind() != reflect.Array {
+		return fmt.Errorf("%w: foreach over %s requires a slice, got %s", ErrInvalidParam, n.path, collection.Type())
+	}
+
+	scope := make(map[string]reflect.Value, 2)
+	s.scopes = append(s.scopes, scope)
+
+	defer func() {
+		s.scopes = s.scopes[:len(s.scopes)-1]
+	}()
+
+	for i := range collection.Len() {
+		if i > 0 {
+			s.sb.WriteString(n.separator)
+		}
+
+		scope[n.item] = collection.Index(i)
+		if n.index != constants.Empty {
+			scope[n.index] = reflect.ValueOf(i)
+		}
+
+		if err := s.renderNodes(n.body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n *includeNode) render(s *renderState) error {
+	fragment, ok := s.frags[n.name]
+	if !ok {
+		return fmt.Errorf("%w: fragment %s", ErrNotFound, n.name)
+	}
+
+	if s.depth >= maxIncludeDepth {
+		return fmt.Errorf("%w: fragment %s is included recursively", ErrSyntax, n.name)
+	}
+
+	s.depth++
+	defer func() { s.depth-- }()
+
+	return s.renderNodes(fragment)
+}
+
+// field returns the map entry or struct field named name.
+// Struct fields match by name, by their json tag and then by name ignoring case.
+func field(value reflect.Value, name string) (reflect.Value, bool) {
+	value = indirect(value)
+	if !value.IsValid() {
+		return reflect.Value{}, false
+	}
+
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+
+		entry := value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key()))
+
+		return entry, entry.IsValid()
+	case reflect.Struct:
+		if f := value.FieldByName(name); f.IsValid() && f.CanInterface() {
+			return f, true
+		}
+
+		t := value.Type()
+		for i := range t.NumField() {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+
+			if tag, _, _ := strings.Cut(sf.Tag.Get("json"), constants.Comma); tag == name {
+				return value.Field(i), true
+			}
+		}
+
+		if f := value.FieldByNameFunc(func(n string) bool { return strings.EqualFold(n, name) }); f.IsValid() && f.CanInterface() {
+			return f, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// indirect dereferences pointers and interfaces.
+func indirect(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+
+		value = value.Elem()
+	}
+
+	return value
+}
+
+// truthy reports whether a condition value holds: it must be set and not the zero value or an empty collection.
+func truthy(value reflect.Value) bool {
+	value = indirect(value)
+	if !value.IsValid() {
+		return false
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return value.Len() > 0
+	default:
+		return !value.IsZero()
+	}
+}
+
+// bindValue returns the argument bound for a value; slices other than byte slices expand to a list for IN clauses.
+func bindValue(value reflect.Value) any {
+	if !value.IsValid() {
+		return nil
+	}
+
+	arg := value.Interface()
+
+	if v := indirect(value); v.IsValid() && v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		return bun.In(v.Interface())
+	}
+
+	return arg
+}
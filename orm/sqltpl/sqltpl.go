@@ -0,0 +1,327 @@
+// Package sqltpl renders hand-written SQL from named templates with safe parameter binding.
+//
+// Templates are loaded from .sql files, typically embedded with go:embed. A file holds a single
+// template named after its path without the extension, or several statements and fragments each
+// introduced by a marker comment:
+//
+//	-- name: user.findActive
+//	SELECT {{include user.columns}} FROM sys_user
+//	{{where}}
+//	  {{if keyword}} AND name LIKE #{keyword} {{end}}
+//	  {{if ids}} AND id IN (#{ids}) {{end}}
+//	  AND is_active = #{active}
+//	{{end}}
+//
+//	-- fragment: user.columns
+//	id, name, email
+//
+// Supported constructs:
+//
+//   - #{path} binds the value at a dotted path as a query argument, slices expand to a list for IN clauses
+//   - {{if path}} ... {{else if !path}} ... {{else}} ... {{end}} renders when the value is set, non-zero and non-empty
+//   - {{where}} ... {{end}} renders WHERE with its body, dropping a leading AND/OR, or nothing for an empty body
+//   - {{set}} ... {{end}} renders SET with its body, dropping a trailing comma, or nothing for an empty body
+//   - {{foreach path as item[, index] join ", "}} ... {{end}} renders its body for every element of a slice
+//   - {{include name}} renders a fragment
+//
+// Paths resolve against foreach variables first and then the parameters, which may be maps with string keys
+// or structs whose fields match by name, json tag or name ignoring case. Values are never interpolated into the query text;
+// a literal question mark must be escaped as \? since arguments are bound through placeholders.
+package sqltpl
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+const (
+	// Extension is the file extension of template files.
+	Extension = ".sql"
+
+	nameMarker     = "name:"
+	fragmentMarker = "fragment:"
+)
+
+// Template is a parsed SQL template.
+type Template struct {
+	name     string
+	nodes    []node
+	registry *Registry
+}
+
+// Name returns the template name.
+func (t *Template) Name() string {
+	return t.name
+}
+
+// Render renders the template into a query with placeholders and its arguments.
+func (t *Template) Render(params any) (query string, args []any, err error) {
+	s := &renderState{
+		sb:     new(strings.Builder),
+		params: reflect.ValueOf(params),
+	}
+
+	if t.registry != nil {
+		s.frags = t.registry.fragments
+	}
+
+	if err = s.renderNodes(t.nodes); err != nil {
+		return constants.Empty, nil, fmt.Errorf("render %s: %w", t.name, err)
+	}
+
+	return strings.TrimSpace(s.sb.String()), s.args, nil
+}
+
+// Query renders the template and returns a raw query, which scans rows into models like any raw query.
+func (t *Template) Query(db orm.DB, params any) (orm.RawQuery, error) {
+	query, args, err := t.Render(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.NewRaw(query, args...), nil
+}
+
+// Parse parses a standalone template; it cannot include fragments.
+func Parse(name, text string) (*Template, error) {
+	nodes, err := parse(name, text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{name: name, nodes: nodes}, nil
+}
+
+// Registry holds named templates and the fragments they include.
+type Registry struct {
+	templates map[string]*Template
+	fragments map[string][]node
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		templates: make(map[string]*Template),
+		fragments: make(map[string][]node),
+	}
+}
+
+// Load creates a registry from the template files of the file system.
+// Without patterns all .sql files are loaded recursively, otherwise the files matching the fs.Glob patterns.
+func Load(fsys fs.FS, patterns ...string) (*Registry, error) {
+	r := NewRegistry()
+	if err := r.LoadFS(fsys, patterns...); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// LoadFS adds the template files of the file system to the registry, see Load.
+func (r *Registry) LoadFS(fsys fs.FS, patterns ...string) error {
+	files, err := templateFiles(fsys, patterns)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+
+		if err := r.Add(strings.TrimSuffix(file, Extension), string(content)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Add parses the content of a template file and adds its statements and fragments.
+// Content without markers is a single template with the given name.
+func (r *Registry) Add(name, content string) error {
+	sections, err := splitSections(name, content)
+	if err != nil {
+		return err
+	}
+
+	for _, section := range sections {
+		nodes, err := parse(section.source, strings.TrimSpace(section.text))
+		if err != nil {
+			return err
+		}
+
+		if section.fragment {
+			if _, exists := r.fragments[section.name]; exists {
+				return fmt.Errorf("%w: fragment %s", ErrDuplicate, section.name)
+			}
+
+			r.fragments[section.name] = nodes
+
+			continue
+		}
+
+		if _, exists := r.templates[section.name]; exists {
+			return fmt.Errorf("%w: template %s", ErrDuplicate, section.name)
+		}
+
+		r.templates[section.name] = &Template{name: section.name, nodes: nodes, registry: r}
+	}
+
+	return nil
+}
+
+// Lookup returns the named template.
+func (r *Registry) Lookup(name string) (*Template, bool) {
+	t, ok := r.templates[name]
+
+	return t, ok
+}
+
+// Names returns the names of the templates.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// Render renders the named template, see Template.Render.
+func (r *Registry) Render(name string, params any) (string, []any, error) {
+	t, ok := r.templates[name]
+	if !ok {
+		return constants.Empty, nil, fmt.Errorf("%w: template %s", ErrNotFound, name)
+	}
+
+	return t.Render(params)
+}
+
+// Query renders the named template and returns a raw query, see Template.Query.
+func (r *Registry) Query(db orm.DB, name string, params any) (orm.RawQuery, error) {
+	t, ok := r.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: template %s", ErrNotFound, name)
+	}
+
+	return t.Query(db, params)
+}
+
+func templateFiles(fsys fs.FS, patterns []string) ([]string, error) {
+	var files []string
+
+	if len(patterns) == 0 {
+		err := fs.WalkDir(fsys, constants.Dot, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.IsDir() && path.Ext(p) == Extension {
+				files = append(files, p)
+			}
+
+			return nil
+		})
+
+		return files, err
+	}
+
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, matches...)
+	}
+
+	return files, nil
+}
+
+// section is a statement or fragment of a template file.
+type section struct {
+	name     string
+	source   string
+	text     string
+	fragment bool
+}
+
+// splitSections splits file content at "-- name:" and "-- fragment:" marker comments.
+func splitSections(file, content string) ([]section, error) {
+	var (
+		sections []section
+		current  *section
+		preamble strings.Builder
+		line     int
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+
+		if name, fragment, ok := parseMarker(text); ok {
+			if !isIdentifier(name, true) {
+				return nil, fmt.Errorf("%w: %s:%d: invalid name %q", ErrSyntax, file, line, name)
+			}
+
+			sections = append(sections, section{
+				name:     name,
+				source:   fmt.Sprintf("%s:%s", file, name),
+				fragment: fragment,
+			})
+			current = &sections[len(sections)-1]
+
+			continue
+		}
+
+		if current == nil {
+			preamble.WriteString(text)
+			preamble.WriteByte(constants.ByteNewline)
+
+			continue
+		}
+
+		current.text += text + string(constants.ByteNewline)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(sections) == 0 {
+		return []section{{name: file, source: file, text: preamble.String()}}, nil
+	}
+
+	return sections, nil
+}
+
+// parseMarker parses a "-- name: <name>" or "-- fragment: <name>" line.
+func parseMarker(line string) (name string, fragment, ok bool) {
+	comment, ok := strings.CutPrefix(strings.TrimSpace(line), "--")
+	if !ok {
+		return constants.Empty, false, false
+	}
+
+	comment = strings.TrimSpace(comment)
+	if name, ok = strings.CutPrefix(comment, nameMarker); ok {
+		return strings.TrimSpace(name), false, true
+	}
+
+	if name, ok = strings.CutPrefix(comment, fragmentMarker); ok {
+		return strings.TrimSpace(name), true, true
+	}
+
+	return constants.Empty, false, false
+}
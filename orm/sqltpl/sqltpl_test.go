@@ -0,0 +1,245 @@
+package sqltpl
+
+import (
+	"context"
+	"embed"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+//go:embed testdata
+var testdata embed.FS
+
+type filter struct {
+	Keyword string  `json:"keyword"`
+	IDs     []int64 `json:"ids"`
+	MinAge  *int    `json:"minAge"`
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		params any
+		query  string
+		args   []any
+	}{
+		{
+			name:   "Param",
+			text:   "SELECT * FROM t WHERE id = #{id}",
+			params: map[string]any{"id": 1},
+			query:  "SELECT * FROM t WHERE id = ?",
+			args:   []any{1},
+		},
+		{
+			name:   "NestedParam",
+			text:   "SELECT * FROM t WHERE name = #{user.name}",
+			params: map[string]any{"user": struct{ Name string }{"alice"}},
+			query:  "SELECT * FROM t WHERE name = ?",
+			args:   []any{"alice"},
+		},
+		{
+			name:   "IfElse",
+			text:   "SELECT * FROM t ORDER BY {{if desc}}id DESC{{else if !asc}}name{{else}}id{{end}}",
+			params: map[string]any{"desc": false, "asc": false},
+			query:  "SELECT * FROM t ORDER BY name",
+		},
+		{
+			name:   "WhereDropsLeadingOperator",
+			text:   "SELECT * FROM t {{where}} {{if a}}AND a = #{a}{{end}} {{if b}}OR b = #{b}{{end}} {{end}}",
+			params: map[string]any{"b": 2},
+			query:  "SELECT * FROM t WHERE b = ?",
+			args:   []any{2},
+		},
+		{
+			name:   "EmptyWhere",
+			text:   "SELECT * FROM t {{where}}{{if a}}AND a = #{a}{{end}}{{end}}",
+			params: map[string]any{},
+			query:  "SELECT * FROM t",
+		},
+		{
+			name:   "SetDropsTrailingComma",
+			text:   "UPDATE t {{set}}{{if a}}a = #{a},{{end}} {{if b}}b = #{b},{{end}}{{end}} WHERE id = 1",
+			params: map[string]any{"a": "x"},
+			query:  "UPDATE t SET a = ? WHERE id = 1",
+			args:   []any{"x"},
+		},
+		{
+			name:   "Foreach",
+			text:   `INSERT INTO t (id, name) VALUES {{foreach rows as row, i join ", "}}(#{i}, #{row.name}){{end}}`,
+			params: map[string]any{"rows": []map[string]string{{"name": "a"}, {"name": "b"}}},
+			query:  "INSERT INTO t (id, name) VALUES (?, ?), (?, ?)",
+			args:   []any{0, "a", 1, "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tpl, err := Parse(tt.name, tt.text)
+			require.NoError(t, err)
+
+			query, args, err := tpl.Render(tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.query, query, "Should render the query")
+			assert.Equal(t, tt.args, args, "Should bind the arguments")
+		})
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	tpl, err := Parse("missing", "SELECT * FROM t WHERE id = #{id}")
+	require.NoError(t, err)
+
+	_, _, err = tpl.Render(map[string]any{})
+	assert.ErrorIs(t, err, ErrMissingParam, "Should reject missing parameters")
+
+	tpl, err = Parse("foreach", "{{foreach id as x}}#{x}{{end}}")
+	require.NoError(t, err)
+
+	_, _, err = tpl.Render(map[string]any{"id": 1})
+	assert.ErrorIs(t, err, ErrInvalidParam, "Should reject foreach over a non-slice")
+
+	tpl, err = Parse("include", "{{include columns}}")
+	require.NoError(t, err)
+
+	_, _, err = tpl.Render(nil)
+	assert.ErrorIs(t, err, ErrNotFound, "Should reject unknown fragments")
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"UnclosedIf", "{{if a}} a"},
+		{"UnexpectedEnd", "a {{end}}"},
+		{"UnknownTag", "{{choose}}"},
+		{"InvalidParam", "#{a b}"},
+		{"UnclosedParam", "#{a"},
+		{"ElseInWhere", "{{where}}{{else}}{{end}}"},
+		{"UnquotedSeparator", "{{foreach a as b join ,}}{{end}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.name, tt.text)
+			assert.ErrorIs(t, err, ErrSyntax, "Should reject invalid templates")
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	registry, err := Load(testdata)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"testdata/count", "user.find", "user.updateName"}, registry.Names(), "Should load all statements")
+
+	minAge := 18
+
+	query, args, err := registry.Render("user.find", filter{IDs: []int64{1, 2}, MinAge: &minAge})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, name, age FROM test_tpl_user WHERE id IN (?) AND age >= ? ORDER BY id", strings.Join(strings.Fields(query), " "))
+	assert.Len(t, args, 2)
+
+	_, _, err = registry.Render("user.delete", nil)
+	assert.ErrorIs(t, err, ErrNotFound, "Should reject unknown templates")
+
+	err = registry.Add("dup", "-- name: user.find\nSELECT 1")
+	assert.ErrorIs(t, err, ErrDuplicate, "Should reject duplicate names")
+}
+
+// TplUser is a test model scanned from templated queries.
+type TplUser struct {
+	orm.BaseModel `bun:"table:test_tpl_user"`
+
+	ID   int64  `bun:"id,pk"`
+	Name string `bun:"name,notnull"`
+	Age  int    `bun:"age,notnull"`
+}
+
+type SQLTemplateTestSuite struct {
+	suite.Suite
+
+	ctx      context.Context
+	db       orm.DB
+	closeDB  func() error
+	registry *Registry
+}
+
+func (s *SQLTemplateTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	bunDB, err := database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	s.closeDB = bunDB.Close
+
+	_, err = bunDB.NewCreateTable().Model((*TplUser)(nil)).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	users := []TplUser{{ID: 1, Name: "alice", Age: 30}, {ID: 2, Name: "bob", Age: 17}, {ID: 3, Name: "carol", Age: 25}}
+	_, err = bunDB.NewInsert().Model(&users).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	s.db = iorm.New(bunDB)
+
+	s.registry, err = Load(testdata, "testdata/user/*.sql")
+	s.Require().NoError(err)
+}
+
+func (s *SQLTemplateTestSuite) TearDownSuite() {
+	if s.closeDB != nil {
+		s.Require().NoError(s.closeDB())
+	}
+}
+
+func (s *SQLTemplateTestSuite) TestScanIntoModels() {
+	minAge := 18
+
+	query, err := s.registry.Query(s.db, "user.find", filter{IDs: []int64{1, 2, 3}, MinAge: &minAge})
+	s.Require().NoError(err)
+
+	var users []TplUser
+	s.Require().NoError(query.Scan(s.ctx, &users))
+
+	s.Require().Len(users, 2, "Should bind the slice and the pointer parameter")
+	s.Equal("alice", users[0].Name)
+	s.Equal("carol", users[1].Name)
+
+	query, err = s.registry.Query(s.db, "user.find", map[string]any{"keyword": "b%"})
+	s.Require().NoError(err)
+
+	users = nil
+	s.Require().NoError(query.Scan(s.ctx, &users))
+	s.Require().Len(users, 1, "Should bind the keyword")
+	s.Equal("bob", users[0].Name)
+}
+
+func (s *SQLTemplateTestSuite) TestExec() {
+	query, err := s.registry.Query(s.db, "user.updateName", map[string]any{"id": 1, "age": 31})
+	s.Require().NoError(err)
+
+	_, err = query.Exec(s.ctx)
+	s.Require().NoError(err)
+
+	var user TplUser
+	s.Require().NoError(s.db.NewSelect().Model(&user).Where(func(cb orm.ConditionBuilder) {
+		cb.Equals("id", 1)
+	}).Scan(s.ctx))
+	s.Equal("alice", user.Name, "Unset fields should not be updated")
+	s.Equal(31, user.Age)
+}
+
+func TestSQLTemplateTestSuite(t *testing.T) {
+	suite.Run(t, new(SQLTemplateTestSuite))
+}
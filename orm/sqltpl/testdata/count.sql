@@ -0,0 +1 @@
+SELECT COUNT(*) FROM test_tpl_user
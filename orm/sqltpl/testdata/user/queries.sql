@@ -0,0 +1,19 @@
+-- fragment: user.columns
+id, name, age
+
+-- name: user.find
+SELECT {{include user.columns}} FROM test_tpl_user
+{{where}}
+  {{if keyword}} AND name LIKE #{keyword} {{end}}
+  {{if ids}} AND id IN (#{ids}) {{end}}
+  {{if minAge}} AND age >= #{minAge} {{end}}
+{{end}}
+ORDER BY id
+
+-- name: user.updateName
+UPDATE test_tpl_user
+{{set}}
+  {{if name}} name = #{name}, {{end}}
+  {{if age}} age = #{age}, {{end}}
+{{end}}
+WHERE id = #{id}
@@ -17,20 +17,34 @@ type Pageable struct {
 
 // Normalize normalizes the pageable parameters.
 func (p *Pageable) Normalize(size ...int) {
+	if len(size) > 0 {
+		p.NormalizeWithin(size[0], MaxPageSize)
+	} else {
+		p.NormalizeWithin(DefaultPageSize, MaxPageSize)
+	}
+}
+
+// NormalizeWithin normalizes the pageable parameters, using defaultSize when no size is given
+// and capping the size at maxSize. A non-positive maxSize, or one above MaxPageSize, is replaced by MaxPageSize.
+func (p *Pageable) NormalizeWithin(defaultSize, maxSize int) {
+	if maxSize < 1 || maxSize > MaxPageSize {
+		maxSize = MaxPageSize
+	}
+
 	if p.Page < 1 {
 		p.Page = DefaultPageNumber
 	}
 
 	if p.Size < 1 {
-		if len(size) > 0 {
-			p.Size = size[0]
-		} else {
-			p.Size = DefaultPageSize
-		}
+		p.Size = defaultSize
+	}
+
+	if p.Size < 1 {
+		p.Size = DefaultPageSize
 	}
 
-	if p.Size > MaxPageSize {
-		p.Size = MaxPageSize
+	if p.Size > maxSize {
+		p.Size = maxSize
 	}
 }
 
@@ -62,6 +62,33 @@ func TestPageableNormalize(t *testing.T) {
 	}
 }
 
+func TestPageableNormalizeWithin(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       Pageable
+		defaultSize int
+		maxSize     int
+		expected    int
+	}{
+		{"DefaultSizeApplied", Pageable{Page: 1}, 20, 50, 20},
+		{"InvalidDefaultSize", Pageable{Page: 1}, 0, 50, DefaultPageSize},
+		{"SizeCappedAtMaxSize", Pageable{Page: 1, Size: 100000}, 20, 50, 50},
+		{"SizeWithinMaxSize", Pageable{Page: 1, Size: 30}, 20, 50, 30},
+		{"DefaultSizeCappedAtMaxSize", Pageable{Page: 1}, 100, 50, 50},
+		{"UnsetMaxSize", Pageable{Page: 1, Size: 100000}, 20, 0, MaxPageSize},
+		{"MaxSizeAboveLimit", Pageable{Page: 1, Size: 100000}, 20, 5000, MaxPageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.NormalizeWithin(tt.defaultSize, tt.maxSize)
+
+			assert.Equal(t, DefaultPageNumber, tt.input.Page, "Page should be normalized correctly")
+			assert.Equal(t, tt.expected, tt.input.Size, "Size should be normalized correctly")
+		})
+	}
+}
+
 func TestPageableOffset(t *testing.T) {
 	tests := []struct {
 		name     string
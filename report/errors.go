@@ -0,0 +1,10 @@
+package report
+
+import "errors"
+
+var (
+	// ErrReportNotFound is returned when generating a report that is not registered.
+	ErrReportNotFound = errors.New("report not found")
+	// ErrRendererNotFound is returned when a report requests a format no renderer is registered for.
+	ErrRendererNotFound = errors.New("report renderer not found")
+)
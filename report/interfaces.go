@@ -0,0 +1,50 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Report is a query run on a cron schedule whose rows are rendered into files and delivered as download links.
+// Reports are registered with vef.ProvideReport.
+type Report interface {
+	// Name returns the unique name of the report, used in artifact keys and run records.
+	Name() string
+	// Schedule returns the standard 5-field cron expression the report runs on.
+	Schedule() string
+	// Formats returns the formats the rows are rendered to.
+	Formats() []Format
+	// Recipients returns the recipients the links are delivered to, e.g. user ids or email addresses.
+	Recipients() []string
+	// RowType returns the struct type of the rows, its tabular tags define the columns.
+	RowType() reflect.Type
+	// Query returns the rows of the report as a slice of RowType.
+	Query(ctx context.Context, db orm.DB) (any, error)
+}
+
+// Renderer renders the rows of a report into a file of one format.
+// XLSX and CSV renderers are built in; renderers of other formats, e.g. PDF, are registered with vef.ProvideReportRenderer.
+type Renderer interface {
+	// Format returns the format rendered.
+	Format() Format
+	// ContentType returns the MIME type of the rendered files.
+	ContentType() string
+	// Render renders the rows, a slice of rowType, into a file.
+	Render(rowType reflect.Type, rows any) (*bytes.Buffer, error)
+}
+
+// Deliverer sends the links of generated reports to their recipients, e.g. by email or in-app notification.
+// Deliverers are registered with vef.ProvideReportDeliverer; every deliverer receives every delivery.
+type Deliverer interface {
+	// Deliver sends the delivery. A returned error is logged and does not fail the run.
+	Deliver(ctx context.Context, delivery *Delivery) error
+}
+
+// Generator generates registered reports on demand, besides their schedule.
+type Generator interface {
+	// Generate runs the report with the name now, returning the run record. Returns ErrReportNotFound for unknown reports.
+	Generate(ctx context.Context, name string) (*Run, error)
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// Option configures a report created with New.
+type Option func(*definition)
+
+// WithFormats sets the formats the report is rendered to. Defaults to XLSX.
+func WithFormats(formats ...Format) Option {
+	return func(d *definition) {
+		d.formats = formats
+	}
+}
+
+// WithRecipients sets the recipients the links of the report are delivered to.
+func WithRecipients(recipients ...string) Option {
+	return func(d *definition) {
+		d.recipients = recipients
+	}
+}
+
+type definition struct {
+	name       string
+	schedule   string
+	formats    []Format
+	recipients []string
+	rowType    reflect.Type
+	query      func(ctx context.Context, db orm.DB) (any, error)
+}
+
+func (d *definition) Name() string {
+	return d.name
+}
+
+func (d *definition) Schedule() string {
+	return d.schedule
+}
+
+func (d *definition) Formats() []Format {
+	return d.formats
+}
+
+func (d *definition) Recipients() []string {
+	return d.recipients
+}
+
+func (d *definition) RowType() reflect.Type {
+	return d.rowType
+}
+
+func (d *definition) Query(ctx context.Context, db orm.DB) (any, error) {
+	return d.query(ctx, db)
+}
+
+// New creates a Report running query on the 5-field cron schedule, e.g. "0 8 * * 1" for Mondays at 8:00.
+// The columns are defined by the tabular tags of T.
+func New[T any](name, schedule string, query func(ctx context.Context, db orm.DB) ([]T, error), opts ...Option) Report {
+	d := &definition{
+		name:     name,
+		schedule: schedule,
+		formats:  []Format{FormatXLSX},
+		rowType:  reflect.TypeFor[T](),
+		query: func(ctx context.Context, db orm.DB) (any, error) {
+			return query(ctx, db)
+		},
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
@@ -0,0 +1,96 @@
+package report
+
+import (
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/datetime"
+	"github.com/ilxqx/vef-framework-go/encoding"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// EventTypeGenerated is the type of the events published for every successfully generated report.
+const EventTypeGenerated = "vef.report.generated"
+
+// Format is the file format a report is rendered to, also used as the file extension.
+type Format string
+
+const (
+	// FormatXLSX renders Excel workbooks, built in.
+	FormatXLSX Format = "xlsx"
+	// FormatCSV renders CSV files, built in.
+	FormatCSV Format = "csv"
+	// FormatPDF renders PDF documents, requiring a renderer registered with vef.ProvideReportRenderer.
+	FormatPDF Format = "pdf"
+)
+
+// Status is the status of a report run.
+type Status string
+
+const (
+	// StatusRunning runs are claimed by an instance and generating.
+	StatusRunning Status = "running"
+	// StatusSucceeded runs stored all their artifacts.
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed runs failed querying, rendering or storing.
+	StatusFailed Status = "failed"
+)
+
+// Run records one generation of a report.
+// A scheduled run is claimed by exactly one application instance through its report name and schedule time.
+type Run struct {
+	orm.BaseModel `bun:"table:sys_report_run,alias:srr"`
+	orm.Model
+
+	ReportName  string            `json:"reportName"  bun:",notnull,unique:uk_sys_report_run"`
+	ScheduledAt datetime.DateTime `json:"scheduledAt" bun:",notnull,type:timestamp,unique:uk_sys_report_run"`
+	Status      Status            `json:"status"      bun:",notnull"`
+	Rows        int               `json:"rows"        bun:",notnull"`
+	Artifacts   string            `json:"artifacts"` // JSON-encoded artifacts
+	Error       null.String       `json:"error"`
+	FinishedAt  null.DateTime     `json:"finishedAt"  bun:",type:timestamp"`
+}
+
+// DecodeArtifacts decodes the artifacts stored by the run.
+func (r *Run) DecodeArtifacts() ([]Artifact, error) {
+	var artifacts []Artifact
+	if r.Artifacts == "" {
+		return artifacts, nil
+	}
+
+	err := encoding.DecodeJSON(r.Artifacts, &artifacts)
+
+	return artifacts, err
+}
+
+// Artifact is a rendered report file kept in the storage.
+type Artifact struct {
+	Format Format `json:"format"`
+	Key    string `json:"key"` // Object key in the storage
+	URL    string `json:"url"` // Signed download path, relative to the application root
+}
+
+// Delivery is the generated report handed to the deliverers.
+type Delivery struct {
+	RunID       string     `json:"runId"`
+	ReportName  string     `json:"reportName"`
+	ScheduledAt time.Time  `json:"scheduledAt"`
+	Rows        int        `json:"rows"`
+	Recipients  []string   `json:"recipients"`
+	Artifacts   []Artifact `json:"artifacts"`
+}
+
+// GeneratedEvent reports a successfully generated report, e.g. for notification modules of the application.
+type GeneratedEvent struct {
+	event.BaseEvent
+	Delivery
+}
+
+// NewGeneratedEvent creates the generated event of the delivery.
+func NewGeneratedEvent(delivery *Delivery) *GeneratedEvent {
+	return &GeneratedEvent{
+		BaseEvent: event.NewBaseEvent(EventTypeGenerated, event.WithMeta("report", delivery.ReportName)),
+		Delivery:  *delivery,
+	}
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+// ErrCounterOutOfRange is returned when an increment would move a counter beyond its floor or ceiling.
+var ErrCounterOutOfRange = errors.New("increment would move the counter out of range")
+
+// Number is the type of counter deltas.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// Increment atomically adds delta, negative to decrement, to a column of the row of model T with the primary key,
+// as SET column = column + delta without reading the row, so concurrent increments never get lost.
+// With WithFloor or WithCeiling the row is left unchanged and ErrCounterOutOfRange returned
+// when the counter would cross the bound. Counter columns are expected to be NOT NULL.
+func Increment[T any, N Number](ctx context.Context, db orm.DB, pk any, column string, delta N, opts ...Option) error {
+	if delta == 0 {
+		return nil
+	}
+
+	res, err := incrementQuery[T](db, column, delta, newOptions(opts)).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.PKEquals(pk)
+		}).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected > 0 {
+		return err
+	}
+
+	exists, err := db.NewSelect().
+		Model((*T)(nil)).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.PKEquals(pk)
+		}).
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return result.ErrRecordNotFound
+	}
+
+	return ErrCounterOutOfRange
+}
+
+// IncrementMany atomically adds the deltas to a column of the rows of model T with their primary keys in one
+// transaction, e.g. to flush view counts aggregated in memory. Rows sharing a delta are updated by one statement
+// per batch. Rows whose counter would cross the floor or ceiling are left unchanged, as are missing rows,
+// and the number of updated rows is returned.
+func IncrementMany[T any, K comparable, N Number](ctx context.Context, db orm.DB, column string, deltas map[K]N, opts ...Option) (int64, error) {
+	o := newOptions(opts)
+
+	groups := make(map[N][]K)
+	for pk, delta := range deltas {
+		if delta != 0 {
+			groups[delta] = append(groups[delta], pk)
+		}
+	}
+
+	var total int64
+
+	err := db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+		for _, delta := range slices.Sorted(maps.Keys(groups)) {
+			for pks := range slices.Chunk(groups[delta], o.batchSize) {
+				res, err := incrementQuery[T](tx, column, delta, o).
+					Where(func(cb orm.ConditionBuilder) {
+						cb.PKIn(pks)
+					}).
+					Exec(ctx)
+				if err != nil {
+					return err
+				}
+
+				affected, err := res.RowsAffected()
+				if err != nil {
+					return err
+				}
+
+				total += affected
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// incrementQuery builds the update adding delta to the column, guarded by the bound it moves towards.
+func incrementQuery[T any, N Number](db orm.DB, column string, delta N, o *options) orm.UpdateQuery {
+	return db.NewUpdate().
+		Model((*T)(nil)).
+		SetExpr(column, func(eb orm.ExprBuilder) any {
+			return eb.Add(eb.Column(column), delta)
+		}).
+		Where(func(cb orm.ConditionBuilder) {
+			switch {
+			case delta < 0 && o.floor != nil:
+				cb.GreaterThanOrEqual(column, *o.floor-float64(delta))
+			case delta > 0 && o.ceiling != nil:
+				cb.LessThanOrEqual(column, *o.ceiling-float64(delta))
+			}
+		})
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+type testArticle struct {
+	bun.BaseModel `bun:"table:test_article"`
+
+	ID        string `bun:"id,pk"`
+	ViewCount int64  `bun:"view_count,notnull"`
+	Stock     int64  `bun:"stock,notnull"`
+}
+
+func (s *RepositoryTestSuite) resetArticles() {
+	_, err := s.bunDB.NewDelete().Model((*testArticle)(nil)).Where("1 = 1").Exec(s.ctx)
+	s.Require().NoError(err)
+
+	articles := []testArticle{
+		{ID: "a1", ViewCount: 10, Stock: 2},
+		{ID: "a2", ViewCount: 0, Stock: 5},
+		{ID: "a3", ViewCount: 7, Stock: 0},
+	}
+
+	_, err = s.bunDB.NewInsert().Model(&articles).Exec(s.ctx)
+	s.Require().NoError(err)
+}
+
+func (s *RepositoryTestSuite) article(id string) testArticle {
+	var article testArticle
+
+	s.Require().NoError(s.bunDB.NewSelect().Model(&article).Where("id = ?", id).Scan(s.ctx))
+
+	return article
+}
+
+func (s *RepositoryTestSuite) TestIncrement() {
+	s.resetArticles()
+
+	s.Require().NoError(Increment[testArticle](s.ctx, s.db, "a1", "view_count", 5))
+	s.Equal(int64(15), s.article("a1").ViewCount)
+
+	s.Require().NoError(Increment[testArticle](s.ctx, s.db, "a1", "stock", -2, WithFloor(0)))
+	s.Equal(int64(0), s.article("a1").Stock)
+
+	err := Increment[testArticle](s.ctx, s.db, "a1", "stock", -1, WithFloor(0))
+	s.ErrorIs(err, ErrCounterOutOfRange)
+	s.Equal(int64(0), s.article("a1").Stock, "Counter should be unchanged")
+
+	err = Increment[testArticle](s.ctx, s.db, "a2", "stock", 6, WithCeiling(10))
+	s.ErrorIs(err, ErrCounterOutOfRange)
+
+	err = Increment[testArticle](s.ctx, s.db, "a9", "stock", 1)
+	s.ErrorIs(err, result.ErrRecordNotFound)
+}
+
+func (s *RepositoryTestSuite) TestIncrementMany() {
+	s.resetArticles()
+
+	updated, err := IncrementMany[testArticle](s.ctx, s.db, "view_count", map[string]int64{
+		"a1": 3,
+		"a2": 3,
+		"a3": 1,
+		"a9": 1,
+	})
+	s.Require().NoError(err)
+	s.Equal(int64(3), updated)
+	s.Equal(int64(13), s.article("a1").ViewCount)
+	s.Equal(int64(3), s.article("a2").ViewCount)
+	s.Equal(int64(8), s.article("a3").ViewCount)
+
+	updated, err = IncrementMany[testArticle](s.ctx, s.db, "stock", map[string]int{
+		"a1": -1,
+		"a2": -1,
+		"a3": -1,
+	}, WithFloor(0))
+	s.Require().NoError(err)
+	s.Equal(int64(2), updated, "Rows reaching the floor should be skipped")
+	s.Equal(int64(0), s.article("a3").Stock)
+}
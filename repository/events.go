@@ -0,0 +1,22 @@
+package repository
+
+import "github.com/ilxqx/vef-framework-go/event"
+
+const (
+	// EventTypeRowSoftDeleted is the event type published for every row soft deleted by SoftDeleteWhere.
+	EventTypeRowSoftDeleted = "vef.repository.row.soft_deleted"
+	// EventTypeRowRestored is the event type published for every row restored by RestoreWhere.
+	EventTypeRowRestored = "vef.repository.row.restored"
+)
+
+// RowChangedEvent records a row affected by a bulk operation, for audit trails and change data capture.
+// Rows of related models affected by cascading are covered by the event of their root row.
+type RowChangedEvent struct {
+	event.BaseEvent
+
+	Table     string         `json:"table"`
+	PK        map[string]any `json:"pk"`
+	Cascade   bool           `json:"cascade"`   // Whether related rows were affected as well
+	Operator  string         `json:"operator"`  // Id of the principal of the operation, empty outside requests
+	RequestID string         `json:"requestId"` // Id of the request of the operation, empty outside requests
+}
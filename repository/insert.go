@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"iter"
+
+	"github.com/uptrace/bun/dialect"
+
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// maxBindParams is the number of bind parameters a statement may hold per database, with SQLite limited to the
+// default of builds before 3.32.0 and SQL Server keeping a margin below 2100 for the parameters added by drivers.
+var maxBindParams = map[dialect.Name]int{
+	dialect.SQLite: 999,
+	dialect.PG:     65535,
+	dialect.MySQL:  65535,
+	dialect.MSSQL:  2000,
+	dialect.Oracle: 65535,
+}
+
+// BulkInsert inserts the models yielded by the source in batches, each in its own transaction, so sources of
+// millions of rows are streamed without being held in memory. The batch size is lowered as needed to keep the
+// statements within the bind parameter limit of the database. The number of inserted models is returned
+// even when a later batch fails.
+func BulkInsert[T any](ctx context.Context, db orm.DB, source iter.Seq[*T], opts ...Option) (int64, error) {
+	o := newOptions(opts)
+	query := db.NewInsert().Model((*T)(nil))
+
+	batchSize := o.batchSize
+	if limit, ok := maxBindParams[query.Dialect().Name()]; ok {
+		if columns := len(query.GetTable().Fields); columns > 0 {
+			batchSize = max(min(batchSize, limit/columns), 1)
+		}
+	}
+
+	var (
+		total int64
+		batch = make([]*T, 0, batchSize)
+	)
+
+	flush := func() error {
+		if err := db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+			_, err := tx.NewInsert().Model(&batch).Exec(ctx)
+
+			return err
+		}); err != nil {
+			return err
+		}
+
+		total += int64(len(batch))
+		batch = batch[:0]
+
+		if o.progress != nil {
+			o.progress(total)
+		}
+
+		return nil
+	}
+
+	for model := range source {
+		if batch = append(batch, model); len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return total, err
+			}
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
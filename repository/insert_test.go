@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"fmt"
+	"iter"
+)
+
+func tags(count int) iter.Seq[*testTag] {
+	return func(yield func(*testTag) bool) {
+		for i := range count {
+			if !yield(&testTag{ID: fmt.Sprintf("t%04d", i)}) {
+				return
+			}
+		}
+	}
+}
+
+func (s *RepositoryTestSuite) TestBulkInsert() {
+	_, err := s.bunDB.NewDelete().Model((*testTag)(nil)).Where("1 = 1").Exec(s.ctx)
+	s.Require().NoError(err)
+
+	var progress []int64
+
+	inserted, err := BulkInsert(s.ctx, s.db, tags(1200), WithBatchSize(500), WithProgress(func(done int64) {
+		progress = append(progress, done)
+	}))
+	s.Require().NoError(err)
+	s.Equal(int64(1200), inserted)
+	s.Equal([]int64{500, 1000, 1200}, progress)
+	s.Equal(1200, s.count((*testTag)(nil), false))
+}
+
+func (s *RepositoryTestSuite) TestBulkInsertParamLimit() {
+	orders := func(yield func(*testOrder) bool) {
+		for i := range 700 {
+			if !yield(&testOrder{ID: fmt.Sprintf("b%04d", i), Status: "new"}) {
+				return
+			}
+		}
+	}
+
+	var progress []int64
+
+	// Three columns per row keep SQLite batches at 333 rows within its 999 parameters
+	inserted, err := BulkInsert(s.ctx, s.db, orders, WithBatchSize(1000), WithProgress(func(done int64) {
+		progress = append(progress, done)
+	}))
+	s.Require().NoError(err)
+	s.Equal(int64(700), inserted)
+	s.Equal([]int64{333, 666, 700}, progress)
+}
+
+func (s *RepositoryTestSuite) TestBulkInsertFailure() {
+	_, err := s.bunDB.NewDelete().Model((*testTag)(nil)).Where("1 = 1").Exec(s.ctx)
+	s.Require().NoError(err)
+	_, err = s.bunDB.NewInsert().Model(&testTag{ID: "t0150"}).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	inserted, err := BulkInsert(s.ctx, s.db, tags(300), WithBatchSize(100))
+	s.Error(err)
+	s.Equal(int64(100), inserted, "Batches committed before the failure should be counted")
+	s.Equal(101, s.count((*testTag)(nil), false), "The failed batch should be rolled back")
+}
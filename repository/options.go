@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// defaultBatchSize is the default number of rows processed per transaction.
+const defaultBatchSize = 500
+
+type options struct {
+	batchSize    int
+	cascade      bool
+	cascadeHooks []orm.CascadeHook
+	publisher    event.Publisher
+	progress     func(done int64)
+	floor        *float64
+	ceiling      *float64
+}
+
+// Option configures a bulk operation.
+type Option func(*options)
+
+// WithBatchSize sets the number of rows processed per transaction, 500 by default.
+func WithBatchSize(size int) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.batchSize = size
+		}
+	}
+}
+
+// WithCascade extends the operation to the rows related through the has-one and has-many relations of the model,
+// see orm.DeleteQuery.Cascade. Hooks override the handling of single relations.
+func WithCascade(hooks ...orm.CascadeHook) Option {
+	return func(o *options) {
+		o.cascade = true
+		o.cascadeHooks = append(o.cascadeHooks, hooks...)
+	}
+}
+
+// WithPublisher publishes a RowChangedEvent for every affected row once its batch is committed.
+func WithPublisher(publisher event.Publisher) Option {
+	return func(o *options) {
+		o.publisher = publisher
+	}
+}
+
+// WithProgress calls the callback with the number of rows processed so far once each batch is committed.
+func WithProgress(callback func(done int64)) Option {
+	return func(o *options) {
+		o.progress = callback
+	}
+}
+
+// WithFloor sets the lowest value counters may be decremented to.
+func WithFloor(floor float64) Option {
+	return func(o *options) {
+		o.floor = &floor
+	}
+}
+
+// WithCeiling sets the highest value counters may be incremented to.
+func WithCeiling(ceiling float64) Option {
+	return func(o *options) {
+		o.ceiling = &ceiling
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{batchSize: defaultBatchSize}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
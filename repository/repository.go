@@ -0,0 +1,289 @@
+// Package repository provides bulk operations on the rows of models, processed in batches of transactions.
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/uptrace/bun/schema"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/contextx"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+// maxInValues bounds the values of IN lists, within the limit of Oracle.
+const maxInValues = 1000
+
+var (
+	// ErrNotSoftDeletable is returned when the model has no soft delete column.
+	ErrNotSoftDeletable = errors.New("model does not support soft deletes")
+	// ErrNoPrimaryKey is returned when the model has no primary key to identify the affected rows.
+	ErrNoPrimaryKey = errors.New("model has no primary key")
+)
+
+// SoftDeleteWhere soft deletes the rows of model T matching the condition, all rows when it is nil.
+// Rows are processed in batches, each in its own transaction, and the number of soft deleted rows is returned
+// even when a later batch fails. With WithCascade the related rows are deleted as well.
+func SoftDeleteWhere[T any](ctx context.Context, db orm.DB, where func(orm.ConditionBuilder), opts ...Option) (int64, error) {
+	return run[T](ctx, db, where, newOptions(opts), false)
+}
+
+// RestoreWhere restores the soft deleted rows of model T matching the condition, all of them when it is nil.
+// Rows are processed in batches, each in its own transaction, and the number of restored rows is returned
+// even when a later batch fails. With WithCascade all soft deleted related rows are restored as well.
+func RestoreWhere[T any](ctx context.Context, db orm.DB, where func(orm.ConditionBuilder), opts ...Option) (int64, error) {
+	return run[T](ctx, db, where, newOptions(opts), true)
+}
+
+func run[T any](ctx context.Context, db orm.DB, where func(orm.ConditionBuilder), o *options, restore bool) (int64, error) {
+	model := (*T)(nil)
+
+	table := db.TableOf(model)
+	if table.SoftDeleteField == nil {
+		return 0, fmt.Errorf("%w: %s", ErrNotSoftDeletable, table.Name)
+	}
+
+	if len(table.PKs) == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrNoPrimaryKey, table.Name)
+	}
+
+	pks := make([]string, len(table.PKs))
+	for i, pk := range table.PKs {
+		pks[i] = pk.Name
+	}
+
+	var total int64
+
+	for {
+		var rows []map[string]any
+
+		// Processed rows no longer match, so every batch reads the first remaining rows
+		if err := db.RunInTX(ctx, func(ctx context.Context, tx orm.DB) error {
+			if err := tx.NewSelect().
+				Model(model).
+				Select(keyColumns(table)...).
+				ApplyIf(where != nil, func(query orm.SelectQuery) {
+					query.Where(where)
+				}).
+				ApplyIf(restore, func(query orm.SelectQuery) {
+					query.WhereDeleted()
+				}).
+				OrderBy(pks...).
+				Limit(o.batchSize).
+				Scan(ctx, &rows); err != nil || len(rows) == 0 {
+				return err
+			}
+
+			if restore {
+				return restoreRows(ctx, tx, table, rows, o)
+			}
+
+			query := tx.NewDelete().
+				Model(model).
+				Where(keyCondition(table.PKs, table.PKs, rows))
+			if o.cascade {
+				query.Cascade(o.cascadeHooks...)
+			}
+
+			_, err := query.Exec(ctx)
+
+			return err
+		}); err != nil {
+			return total, err
+		}
+
+		total += int64(len(rows))
+		publish(ctx, o, table, rows, restore)
+
+		if o.progress != nil {
+			o.progress(total)
+		}
+
+		if len(rows) < o.batchSize {
+			return total, nil
+		}
+	}
+}
+
+// restoreRows restores soft deleted rows of a table and, when cascading, their soft deleted related rows.
+func restoreRows(ctx context.Context, tx orm.DB, table *schema.Table, rows []map[string]any, o *options) error {
+	if _, err := tx.NewUpdate().
+		Model(table.ZeroIface).
+		Set(table.SoftDeleteField.Name, restoredValue(table.SoftDeleteField)).
+		WhereDeleted().
+		Where(keyCondition(table.PKs, table.PKs, rows)).
+		Exec(ctx); err != nil {
+		return err
+	}
+
+	if !o.cascade {
+		return nil
+	}
+
+	return restoreRelated(ctx, tx, table, rows, constants.Empty, o.cascadeHooks)
+}
+
+// restoreRelated restores the soft deleted rows related to the given rows, recursively.
+// Restored rows no longer match, so cyclic data ends the recursion.
+func restoreRelated(ctx context.Context, tx orm.DB, table *schema.Table, rows []map[string]any, path string, hooks []orm.CascadeHook) error {
+	for _, name := range cascadeRelations(table) {
+		rel := table.Relations[name]
+		relation := path + name
+
+		if cascadeMode(hooks, relation) == orm.CascadeSkip || rel.JoinTable.SoftDeleteField == nil {
+			continue
+		}
+
+		for batch := range slices.Chunk(rows, maxInValues) {
+			var related []map[string]any
+
+			condition := keyCondition(rel.JoinPKs, rel.BasePKs, batch)
+			if rel.PolymorphicField != nil {
+				base := condition
+				condition = func(cb orm.ConditionBuilder) {
+					base(cb)
+					cb.Equals(rel.PolymorphicField.Name, rel.PolymorphicValue)
+				}
+			}
+
+			if err := tx.NewSelect().
+				Model(rel.JoinTable.ZeroIface).
+				Select(keyColumns(rel.JoinTable)...).
+				WhereDeleted().
+				Where(condition).
+				Scan(ctx, &related); err != nil {
+				return fmt.Errorf("failed to read related rows of %s: %w", relation, err)
+			}
+
+			if len(related) == 0 {
+				continue
+			}
+
+			if err := restoreRows(ctx, tx, rel.JoinTable, related, &options{}); err != nil {
+				return fmt.Errorf("failed to restore related rows of %s: %w", relation, err)
+			}
+
+			if err := restoreRelated(ctx, tx, rel.JoinTable, related, relation+constants.Dot, hooks); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// restoredValue returns the value of the soft delete column of rows that are not deleted.
+func restoredValue(field *schema.Field) any {
+	if field.IsPtr || field.NullZero {
+		return nil
+	}
+
+	return reflect.Zero(field.IndirectType).Interface()
+}
+
+func cascadeMode(hooks []orm.CascadeHook, relation string) orm.CascadeMode {
+	for _, hook := range hooks {
+		if mode := hook(relation); mode != orm.CascadeDefault {
+			return mode
+		}
+	}
+
+	return orm.CascadeDefault
+}
+
+// cascadeRelations returns the sorted names of the has-one and has-many relations of a table.
+func cascadeRelations(table *schema.Table) []string {
+	names := make([]string, 0, len(table.Relations))
+	for name, rel := range table.Relations {
+		if rel.Type == schema.HasOneRelation || rel.Type == schema.HasManyRelation {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// keyColumns returns the primary key columns of a table and the columns its relations join on.
+func keyColumns(table *schema.Table) []string {
+	columns := make([]string, 0, len(table.PKs))
+	for _, pk := range table.PKs {
+		columns = append(columns, pk.Name)
+	}
+
+	for _, name := range cascadeRelations(table) {
+		for _, field := range table.Relations[name].BasePKs {
+			if !slices.Contains(columns, field.Name) {
+				columns = append(columns, field.Name)
+			}
+		}
+	}
+
+	return columns
+}
+
+// keyCondition matches the rows whose columns equal the values of the source columns of one of the given rows.
+func keyCondition(columns, sources []*schema.Field, rows []map[string]any) func(orm.ConditionBuilder) {
+	return func(cb orm.ConditionBuilder) {
+		if len(columns) == 1 {
+			values := make([]any, 0, len(rows))
+			for _, row := range rows {
+				values = append(values, row[sources[0].Name])
+			}
+
+			cb.In(columns[0].Name, values)
+
+			return
+		}
+
+		cb.Group(func(cb orm.ConditionBuilder) {
+			for _, row := range rows {
+				cb.OrGroup(func(cb orm.ConditionBuilder) {
+					for i, column := range columns {
+						cb.Equals(column.Name, row[sources[i].Name])
+					}
+				})
+			}
+		})
+	}
+}
+
+func publish(ctx context.Context, o *options, table *schema.Table, rows []map[string]any, restore bool) {
+	if o.publisher == nil {
+		return
+	}
+
+	eventType := EventTypeRowSoftDeleted
+	if restore {
+		eventType = EventTypeRowRestored
+	}
+
+	var operator string
+	if principal := contextx.Principal(ctx); principal != nil {
+		operator = principal.ID
+	}
+
+	requestID := contextx.RequestID(ctx)
+
+	for _, row := range rows {
+		pk := make(map[string]any, len(table.PKs))
+		for _, field := range table.PKs {
+			pk[field.Name] = row[field.Name]
+		}
+
+		o.publisher.Publish(&RowChangedEvent{
+			BaseEvent: event.NewBaseEvent(eventType),
+			Table:     table.Name,
+			PK:        pk,
+			Cascade:   o.cascade,
+			Operator:  operator,
+			RequestID: requestID,
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+type testOrder struct {
+	bun.BaseModel `bun:"table:test_order"`
+
+	ID        string           `bun:"id,pk"`
+	Status    string           `bun:"status"`
+	DeletedAt time.Time        `bun:",soft_delete,nullzero"`
+	Items     []*testOrderItem `bun:"rel:has-many,join:id=order_id"`
+}
+
+type testOrderItem struct {
+	bun.BaseModel `bun:"table:test_order_item"`
+
+	ID        string    `bun:"id,pk"`
+	OrderID   string    `bun:"order_id"`
+	DeletedAt time.Time `bun:",soft_delete,nullzero"`
+}
+
+type testTag struct {
+	bun.BaseModel `bun:"table:test_tag"`
+
+	ID string `bun:"id,pk"`
+}
+
+type recordingPublisher struct {
+	events []event.Event
+}
+
+func (p *recordingPublisher) Publish(e event.Event) {
+	p.events = append(p.events, e)
+}
+
+// RepositoryTestSuite tests bulk soft deletes and restores on SQLite.
+type RepositoryTestSuite struct {
+	suite.Suite
+
+	ctx   context.Context
+	bunDB *bun.DB
+	db    orm.DB
+}
+
+func (s *RepositoryTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	var err error
+
+	s.bunDB, err = database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	for _, model := range []any{(*testOrder)(nil), (*testOrderItem)(nil), (*testTag)(nil), (*testArticle)(nil)} {
+		_, err = s.bunDB.NewCreateTable().Model(model).Exec(s.ctx)
+		s.Require().NoError(err)
+	}
+
+	s.db = iorm.New(s.bunDB)
+}
+
+func (s *RepositoryTestSuite) TearDownSuite() {
+	if s.bunDB != nil {
+		s.Require().NoError(s.bunDB.Close())
+	}
+}
+
+func (s *RepositoryTestSuite) SetupTest() {
+	for _, model := range []any{(*testOrder)(nil), (*testOrderItem)(nil)} {
+		_, err := s.bunDB.NewDelete().Model(model).Where("1 = 1").ForceDelete().Exec(s.ctx)
+		s.Require().NoError(err)
+	}
+
+	orders := []testOrder{
+		{ID: "o1", Status: "closed"},
+		{ID: "o2", Status: "closed"},
+		{ID: "o3", Status: "closed"},
+		{ID: "o4", Status: "closed"},
+		{ID: "o5", Status: "open"},
+	}
+	items := []testOrderItem{
+		{ID: "i1", OrderID: "o1"},
+		{ID: "i2", OrderID: "o1"},
+		{ID: "i3", OrderID: "o3"},
+		{ID: "i4", OrderID: "o5"},
+	}
+
+	_, err := s.bunDB.NewInsert().Model(&orders).Exec(s.ctx)
+	s.Require().NoError(err)
+	_, err = s.bunDB.NewInsert().Model(&items).Exec(s.ctx)
+	s.Require().NoError(err)
+}
+
+func (s *RepositoryTestSuite) closed(cb orm.ConditionBuilder) {
+	cb.Equals("status", "closed")
+}
+
+func (s *RepositoryTestSuite) count(model any, deleted bool) int {
+	query := s.db.NewSelect().Model(model)
+	if deleted {
+		query.WhereDeleted()
+	}
+
+	count, err := query.Count(s.ctx)
+	s.Require().NoError(err)
+
+	return int(count)
+}
+
+func (s *RepositoryTestSuite) TestSoftDeleteWhere() {
+	publisher := &recordingPublisher{}
+
+	affected, err := SoftDeleteWhere[testOrder](s.ctx, s.db, s.closed, WithBatchSize(3), WithPublisher(publisher))
+	s.Require().NoError(err)
+	s.Equal(int64(4), affected)
+	s.Equal(1, s.count((*testOrder)(nil), false))
+	s.Equal(4, s.count((*testOrder)(nil), true))
+	s.Equal(4, s.count((*testOrderItem)(nil), false), "Related rows should be kept without cascading")
+
+	s.Require().Len(publisher.events, 4)
+
+	for i, e := range publisher.events {
+		changed, ok := e.(*RowChangedEvent)
+		s.Require().True(ok)
+		s.Equal(EventTypeRowSoftDeleted, changed.Type())
+		s.Equal("test_order", changed.Table)
+		s.Equal(map[string]any{"id": []string{"o1", "o2", "o3", "o4"}[i]}, changed.PK)
+		s.False(changed.Cascade)
+	}
+}
+
+func (s *RepositoryTestSuite) TestCascade() {
+	publisher := &recordingPublisher{}
+
+	affected, err := SoftDeleteWhere[testOrder](s.ctx, s.db, s.closed, WithBatchSize(2), WithCascade(), WithPublisher(publisher))
+	s.Require().NoError(err)
+	s.Equal(int64(4), affected)
+	s.Equal(1, s.count((*testOrderItem)(nil), false))
+	s.Equal(3, s.count((*testOrderItem)(nil), true))
+	s.Len(publisher.events, 4)
+
+	affected, err = RestoreWhere[testOrder](s.ctx, s.db, func(cb orm.ConditionBuilder) {
+		cb.In("id", []string{"o1", "o2"})
+	}, WithCascade(), WithPublisher(publisher))
+	s.Require().NoError(err)
+	s.Equal(int64(2), affected)
+	s.Equal(3, s.count((*testOrder)(nil), false))
+	s.Equal(3, s.count((*testOrderItem)(nil), false), "Items of restored orders should be restored")
+
+	s.Require().Len(publisher.events, 6)
+
+	restored, ok := publisher.events[4].(*RowChangedEvent)
+	s.Require().True(ok)
+	s.Equal(EventTypeRowRestored, restored.Type())
+	s.True(restored.Cascade)
+}
+
+func (s *RepositoryTestSuite) TestRestoreSkipHook() {
+	_, err := SoftDeleteWhere[testOrder](s.ctx, s.db, nil, WithCascade())
+	s.Require().NoError(err)
+	s.Equal(0, s.count((*testOrderItem)(nil), false))
+
+	affected, err := RestoreWhere[testOrder](s.ctx, s.db, nil, WithCascade(func(relation string) orm.CascadeMode {
+		if relation == "Items" {
+			return orm.CascadeSkip
+		}
+
+		return orm.CascadeDefault
+	}))
+	s.Require().NoError(err)
+	s.Equal(int64(5), affected)
+	s.Equal(5, s.count((*testOrder)(nil), false))
+	s.Equal(0, s.count((*testOrderItem)(nil), false))
+}
+
+func (s *RepositoryTestSuite) TestNotSoftDeletable() {
+	_, err := SoftDeleteWhere[testTag](s.ctx, s.db, nil)
+	s.ErrorIs(err, ErrNotSoftDeletable)
+
+	_, err = RestoreWhere[testTag](s.ctx, s.db, nil)
+	s.ErrorIs(err, ErrNotSoftDeletable)
+}
+
+func TestRepository(t *testing.T) {
+	suite.Run(t, new(RepositoryTestSuite))
+}
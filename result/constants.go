@@ -30,10 +30,15 @@ const (
 	ErrMessageAccessDenied                    = "access_denied"
 	ErrMessageUnsupportedMediaType            = "unsupported_media_type"
 	ErrMessageRequestTimeout                  = "request_timeout"
+	ErrMessageRequestEntityTooLarge           = "request_entity_too_large"
 	ErrMessageMonitorNotReady                 = "monitor_not_ready"
 	ErrMessageInvalidFileKey                  = "invalid_file_key"
 	ErrMessageFileNotFound                    = "file_not_found"
 	ErrMessageFailedToGetFile                 = "failed_to_get_file"
+	ErrMessageFileURLInvalid                  = "file_url_invalid"
+	ErrMessageFileURLExpired                  = "file_url_expired"
+	ErrMessageDownloadLimitReached            = "download_limit_reached"
+	ErrMessageFileNotProcessed                = "file_not_processed"
 	ErrMessageApiRequestParamsInvalidJSON     = "api_request_params_invalid_json"
 	ErrMessageApiRequestMetaInvalidJSON       = "api_request_meta_invalid_json"
 	ErrMessageDangerousSQL                    = "dangerous_sql"
@@ -49,6 +54,14 @@ const (
 	ErrMessageUnsupportedAuthenticationType   = "unsupported_authentication_type"
 	ErrMessageUserLoaderNotImplemented        = "user_loader_not_implemented"
 	ErrMessageUserInfoLoaderNotImplemented    = "user_info_loader_not_implemented"
+	ErrMessageSessionRevoked                  = "session_revoked"
+	ErrMessageSessionLimitExceeded            = "session_limit_exceeded"
+	ErrMessageSessionManagementDisabled       = "session_management_disabled"
+	ErrMessageImpersonationNotAllowed         = "impersonation_not_allowed"
+	ErrMessageCronJobNotFound                 = "cron_job_not_found"
+	ErrMessageCronJobNotRunning               = "cron_job_not_running"
+	ErrMessageIntegrityReferenceNotFound      = "integrity_reference_not_found"
+	ErrMessageSyncModelUnknown                = "sync_model_unknown"
 )
 
 // Response codes for API results.
@@ -83,9 +96,12 @@ const (
 	ErrCodeNonceAlreadyUsed              = 1023
 	ErrCodeAuthHeaderMissing             = 1024
 	ErrCodeAuthHeaderInvalid             = 1025
+	ErrCodeSessionRevoked                = 1026
+	ErrCodeSessionLimitExceeded          = 1027
 
 	// Authorization errors (1100-1199).
-	ErrCodeAccessDenied = 1100
+	ErrCodeAccessDenied            = 1100
+	ErrCodeImpersonationNotAllowed = 1101
 
 	// Resource errors (1200-1299).
 	ErrCodeNotFound = 1200
@@ -94,9 +110,10 @@ const (
 	ErrCodeUnsupportedMediaType = 1300
 
 	// Request errors (1400-1499).
-	ErrCodeBadRequest      = 1400
-	ErrCodeTooManyRequests = 1401
-	ErrCodeRequestTimeout  = 1402
+	ErrCodeBadRequest            = 1400
+	ErrCodeTooManyRequests       = 1401
+	ErrCodeRequestTimeout        = 1402
+	ErrCodeRequestEntityTooLarge = 1403
 
 	// Not implemented (1500-1599).
 	ErrCodeNotImplemented = 1500
@@ -108,12 +125,20 @@ const (
 	ErrCodeUnknown = 1900
 
 	// Business errors (2000+).
-	ErrCodeDefault             = 2000
-	ErrCodeRecordNotFound      = 2001
-	ErrCodeRecordAlreadyExists = 2002
-	ErrCodeForeignKeyViolation = 2003
-	ErrCodeMonitorNotReady     = 2100
-	ErrCodeInvalidFileKey      = 2200
-	ErrCodeFileNotFound        = 2201
-	ErrCodeSchemaTableNotFound = 2300
+	ErrCodeDefault                    = 2000
+	ErrCodeRecordNotFound             = 2001
+	ErrCodeRecordAlreadyExists        = 2002
+	ErrCodeForeignKeyViolation        = 2003
+	ErrCodeMonitorNotReady            = 2100
+	ErrCodeInvalidFileKey             = 2200
+	ErrCodeFileNotFound               = 2201
+	ErrCodeFileURLInvalid             = 2202
+	ErrCodeFileURLExpired             = 2203
+	ErrCodeDownloadLimitReached       = 2204
+	ErrCodeFileNotProcessed           = 2205
+	ErrCodeSchemaTableNotFound        = 2300
+	ErrCodeCronJobNotFound            = 2500
+	ErrCodeCronJobNotRunning          = 2501
+	ErrCodeIntegrityReferenceNotFound = 2600
+	ErrCodeSyncModelUnknown           = 2700
 )
@@ -107,8 +107,20 @@ func Errf(format string, args ...any) Error {
 	return err
 }
 
+// Converter is implemented by errors that translate themselves into an Error, e.g. bizerror.Error.
+type Converter interface {
+	error
+	ResultError() Error
+}
+
 // AsErr extracts an Error from err if present.
+// A Converter in the chain takes precedence, as an Error never wraps other errors and can only be its cause.
 func AsErr(err error) (Error, bool) {
+	var converter Converter
+	if errors.As(err, &converter) {
+		return converter.ResultError(), true
+	}
+
 	var target Error
 	if errors.As(err, &target) {
 		return target, true
@@ -122,6 +122,16 @@ var (
 		WithCode(ErrCodeAuthHeaderInvalid),
 		WithStatus(fiber.StatusUnauthorized),
 	)
+	ErrSessionRevoked = Err(
+		i18n.T(ErrMessageSessionRevoked),
+		WithCode(ErrCodeSessionRevoked),
+		WithStatus(fiber.StatusUnauthorized),
+	)
+	ErrSessionLimitExceeded = Err(
+		i18n.T(ErrMessageSessionLimitExceeded),
+		WithCode(ErrCodeSessionLimitExceeded),
+		WithStatus(fiber.StatusForbidden),
+	)
 )
 
 // Predefined authorization and request errors.
@@ -131,6 +141,11 @@ var (
 		WithCode(ErrCodeAccessDenied),
 		WithStatus(fiber.StatusForbidden),
 	)
+	ErrImpersonationNotAllowed = Err(
+		i18n.T(ErrMessageImpersonationNotAllowed),
+		WithCode(ErrCodeImpersonationNotAllowed),
+		WithStatus(fiber.StatusForbidden),
+	)
 	ErrTooManyRequests = Err(
 		i18n.T(ErrMessageTooManyRequests),
 		WithCode(ErrCodeTooManyRequests),
@@ -141,6 +156,11 @@ var (
 		WithCode(ErrCodeRequestTimeout),
 		WithStatus(fiber.StatusRequestTimeout),
 	)
+	ErrRequestEntityTooLarge = Err(
+		i18n.T(ErrMessageRequestEntityTooLarge),
+		WithCode(ErrCodeRequestEntityTooLarge),
+		WithStatus(fiber.StatusRequestEntityTooLarge),
+	)
 	ErrUnknown = Err(
 		i18n.T(ErrMessageUnknown),
 		WithCode(ErrCodeUnknown),
@@ -14,15 +14,35 @@ type Result struct {
 }
 
 // Response sends the result as JSON with optional HTTP status (defaults to 200).
+// The data passes the DataFilter of the request, if any, before it is serialized.
 func (r Result) Response(ctx fiber.Ctx, status ...int) error {
 	statusCode := fiber.StatusOK
 	if len(status) > 0 {
 		statusCode = status[0]
 	}
 
+	if filter, ok := ctx.Locals(dataFilterKey{}).(DataFilter); ok && r.Data != nil {
+		data, err := filter(ctx, r.Data)
+		if err != nil {
+			return err
+		}
+
+		r.Data = data
+	}
+
 	return ctx.Status(statusCode).JSON(r)
 }
 
+// DataFilter rewrites response data before it is serialized, e.g. to remove fields the caller may not see.
+type DataFilter func(ctx fiber.Ctx, data any) (any, error)
+
+type dataFilterKey struct{}
+
+// SetDataFilter installs the filter applied to the data of results responded to the request.
+func SetDataFilter(ctx fiber.Ctx, filter DataFilter) {
+	ctx.Locals(dataFilterKey{}, filter)
+}
+
 // IsOk returns true if the result code indicates success.
 func (r Result) IsOk() bool {
 	return r.Code == OkCode
@@ -3,6 +3,7 @@ package result
 import (
 	"encoding/json"
 	"io"
+	"maps"
 	"net/http/httptest"
 	"testing"
 
@@ -141,6 +142,36 @@ func TestResultResponse(t *testing.T) {
 		assert.NotNil(t, result.Data, "Response should have data")
 	})
 
+	t.Run("DataFilter", func(t *testing.T) {
+		app := fiber.New()
+		app.Post("/test", func(ctx fiber.Ctx) error {
+			SetDataFilter(ctx, func(_ fiber.Ctx, data any) (any, error) {
+				filtered := maps.Clone(data.(map[string]any))
+				delete(filtered, "secret")
+
+				return filtered, nil
+			})
+
+			return Ok(map[string]any{"name": "test", "secret": "hidden"}).Response(ctx)
+		})
+
+		req := httptest.NewRequest("POST", "/test", nil)
+		resp, err := app.Test(req)
+		require.NoError(t, err, "Request should succeed")
+
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Should read response body")
+
+		var result Result
+
+		err = json.Unmarshal(body, &result)
+		require.NoError(t, err, "Should unmarshal JSON response")
+
+		assert.Equal(t, map[string]any{"name": "test"}, result.Data, "Data should pass the filter")
+	})
+
 	t.Run("CustomStatus", func(t *testing.T) {
 		app := fiber.New()
 		app.Post("/test", func(ctx fiber.Ctx) error {
@@ -0,0 +1,26 @@
+package schema
+
+import "github.com/ilxqx/vef-framework-go/event"
+
+const (
+	// EventTypeSchemaChanged is the event type for database schema changes.
+	// When this event is published, cached table schemas are reloaded on next use.
+	EventTypeSchemaChanged = "vef.schema.changed"
+)
+
+// SchemaChangedEvent is published when the database schema changes, e.g. after migrations run.
+type SchemaChangedEvent struct {
+	event.BaseEvent
+
+	Tables []string `json:"tables"` // Affected table names (empty means all tables)
+}
+
+// PublishSchemaChangedEvent publishes a schema changed event via the provided publisher.
+// If no tables are specified, subscribers should interpret the event as affecting the whole schema.
+func PublishSchemaChangedEvent(publisher event.Publisher, tables ...string) {
+	publisher.Publish(&SchemaChangedEvent{
+		BaseEvent: event.NewBaseEvent(EventTypeSchemaChanged),
+
+		Tables: tables,
+	})
+}
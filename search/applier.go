@@ -2,8 +2,8 @@ package search
 
 import "github.com/ilxqx/vef-framework-go/orm"
 
-func Applier[T any]() func(T) orm.ApplyFunc[orm.ConditionBuilder] {
-	f := NewFor[T]()
+func Applier[T any](opts ...Option) func(T) orm.ApplyFunc[orm.ConditionBuilder] {
+	f := NewFor[T](opts...)
 
 	return func(value T) orm.ApplyFunc[orm.ConditionBuilder] {
 		return func(cb orm.ConditionBuilder) {
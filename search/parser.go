@@ -8,13 +8,30 @@ import (
 
 	"github.com/ilxqx/vef-framework-go/api"
 	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/fieldmap"
 	"github.com/ilxqx/vef-framework-go/reflectx"
 	"github.com/ilxqx/vef-framework-go/strhelpers"
 )
 
 var apiInType = reflect.TypeFor[api.P]()
 
-func New(typ reflect.Type) Search {
+// Option configures how a search struct is parsed.
+type Option func(*parseOptions)
+
+type parseOptions struct {
+	mapping *fieldmap.Mapping
+}
+
+// WithFieldMapping resolves the columns of fields without an explicit column through the field mapping
+// of the searched model, matching the json name of the search field against the Api fields of the model.
+// Fields not found in the mapping fall back to their snake_case name.
+func WithFieldMapping(mapping *fieldmap.Mapping) Option {
+	return func(o *parseOptions) {
+		o.mapping = mapping
+	}
+}
+
+func New(typ reflect.Type, opts ...Option) Search {
 	typ = reflectx.Indirect(typ)
 	if typ.Kind() != reflect.Struct {
 		logger.Warnf("Invalid value type, expected struct, got %s", typ.Name())
@@ -22,14 +39,19 @@ func New(typ reflect.Type) Search {
 		return Search{}
 	}
 
-	return Search{conditions: parseStruct(typ)}
+	var options parseOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return Search{conditions: parseStruct(typ, options)}
 }
 
-func NewFor[T any]() Search {
-	return New(reflect.TypeFor[T]())
+func NewFor[T any](opts ...Option) Search {
+	return New(reflect.TypeFor[T](), opts...)
 }
 
-func parseStruct(t reflect.Type) []Condition {
+func parseStruct(t reflect.Type, options parseOptions) []Condition {
 	conditions := make([]Condition, 0)
 
 	visitor := reflectx.TypeVisitor{
@@ -50,14 +72,14 @@ func parseStruct(t reflect.Type) []Condition {
 				}
 
 				attrs := strhelpers.ParseTag(tag)
-				conditions = append(conditions, buildCondition(field, attrs))
+				conditions = append(conditions, buildCondition(field, attrs, options))
 			} else {
 				if field.Anonymous {
 					return reflectx.SkipChildren
 				}
 
 				// Default to eq operator with snake_case column when no tag specified
-				conditions = append(conditions, buildCondition(field, make(map[string]string)))
+				conditions = append(conditions, buildCondition(field, make(map[string]string), options))
 			}
 
 			return reflectx.SkipChildren
@@ -73,11 +95,11 @@ func parseStruct(t reflect.Type) []Condition {
 	return conditions
 }
 
-func buildCondition(field reflect.StructField, attrs map[string]string) Condition {
+func buildCondition(field reflect.StructField, attrs map[string]string, options parseOptions) Condition {
 	column := attrs[AttrColumn]
 	columns := lo.Ternary(
 		column == constants.Empty,
-		[]string{lo.SnakeCase(field.Name)},
+		[]string{defaultColumn(field, options.mapping)},
 		strings.Split(column, constants.Pipe),
 	)
 
@@ -99,3 +121,17 @@ func buildCondition(field reflect.StructField, attrs map[string]string) Conditio
 		Params:   params,
 	}
 }
+
+// defaultColumn returns the column of a field without an explicit column.
+func defaultColumn(field reflect.StructField, mapping *fieldmap.Mapping) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), constants.Comma)
+	if name == constants.Empty || name == constants.Hyphen {
+		name = field.Name
+	}
+
+	if column, ok := mapping.Column(name); ok {
+		return column
+	}
+
+	return lo.SnakeCase(field.Name)
+}
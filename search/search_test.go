@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ilxqx/vef-framework-go/fieldmap"
 	"github.com/ilxqx/vef-framework-go/monad"
 )
 
@@ -456,3 +457,26 @@ func TestNoTagStruct(t *testing.T) {
 		})
 	}
 }
+
+func TestWithFieldMapping(t *testing.T) {
+	type Model struct {
+		Name     string `json:"userName" bun:"name"`
+		DeptCode string `json:"deptCode" bun:"department_code"`
+	}
+
+	type ModelSearch struct {
+		UserName string `json:"userName" search:"contains"`
+		DeptCode string `json:"deptCode"`
+		Remark   string `json:"remark"`
+		Contact  string `json:"contact"  search:"column=email"`
+	}
+
+	search := NewFor[ModelSearch](WithFieldMapping(fieldmap.For[Model]()))
+
+	assert.Len(t, search.conditions, 4, "Should have a condition per field")
+	assert.Equal(t, []string{"name"}, search.conditions[0].Columns, "Should resolve the column through the mapping")
+	assert.Equal(t, Contains, search.conditions[0].Operator, "Should keep the operator of the tag")
+	assert.Equal(t, []string{"department_code"}, search.conditions[1].Columns, "Should resolve untagged fields through the mapping")
+	assert.Equal(t, []string{"remark"}, search.conditions[2].Columns, "Should fall back to snake_case for unmapped fields")
+	assert.Equal(t, []string{"email"}, search.conditions[3].Columns, "Should prefer the explicit column")
+}
@@ -0,0 +1,46 @@
+package security
+
+import (
+	"context"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/cache"
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+// CacheNonceStore implements AtomicNonceStore on top of any cache.Cache.
+// Backed by a Redis cache it shares used nonces between instances, which multi-instance deployments need
+// to reject a request replayed against another instance:
+//
+//	store := security.NewCacheNonceStore(cache.NewRedis[bool](client, "signature_nonce"))
+type CacheNonceStore struct {
+	cache cache.Cache[bool]
+}
+
+// NewCacheNonceStore creates a nonce store persisting used nonces in the given cache.
+func NewCacheNonceStore(c cache.Cache[bool]) AtomicNonceStore {
+	return &CacheNonceStore{
+		cache: c,
+	}
+}
+
+// buildKey creates a unique cache key for the app-nonce combination.
+func (*CacheNonceStore) buildKey(appID, nonce string) string {
+	return appID + constants.Colon + nonce
+}
+
+// Exists checks if a nonce has already been used for the given app.
+// Errors of the cache report the nonce as unused; signature verification uses StoreIfAbsent, which fails on them.
+func (s *CacheNonceStore) Exists(ctx context.Context, appID, nonce string) (bool, error) {
+	return s.cache.Contains(ctx, s.buildKey(appID, nonce)), nil
+}
+
+// Store saves a nonce with the specified TTL.
+func (s *CacheNonceStore) Store(ctx context.Context, appID, nonce string, ttl time.Duration) error {
+	return s.cache.Set(ctx, s.buildKey(appID, nonce), true, ttl)
+}
+
+// StoreIfAbsent saves a nonce with the specified TTL unless it has already been used.
+func (s *CacheNonceStore) StoreIfAbsent(ctx context.Context, appID, nonce string, ttl time.Duration) (bool, error) {
+	return s.cache.SetIfAbsent(ctx, s.buildKey(appID, nonce), true, ttl)
+}
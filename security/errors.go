@@ -19,4 +19,6 @@ var (
 
 	ErrQueryNotQueryBuilder = errors.New("query does not implement QueryBuilder interface")
 	ErrQueryModelNotSet     = errors.New("query must call Model() before applying data permission")
+
+	ErrUnsupportedScanType = errors.New("unsupported scan type")
 )
@@ -0,0 +1,150 @@
+package security
+
+import (
+	"context"
+	"crypto/rand"
+	"strings"
+
+	"github.com/ilxqx/vef-framework-go/cache"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/encoding"
+	"github.com/ilxqx/vef-framework-go/event"
+	ilog "github.com/ilxqx/vef-framework-go/internal/log"
+	"github.com/ilxqx/vef-framework-go/log"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/result"
+)
+
+const (
+	// eventTypeExternalAppsChanged is the event type for external app registry changes.
+	eventTypeExternalAppsChanged = "vef.security.external_apps.changed"
+	externalAppSecretSize        = 32
+)
+
+// ExternalApp is a registered open-platform caller signing its requests with an app id and app secret.
+// The model id is the app id sent in the X-App-ID header.
+type ExternalApp struct {
+	orm.BaseModel `bun:"table:sys_external_app,alias:sea"`
+	orm.Model
+
+	Name        string      `json:"name"        bun:",notnull"`
+	Secret      string      `json:"-"           bun:",notnull"` // Hex-encoded signing secret
+	IsEnabled   bool        `json:"isEnabled"   bun:",notnull,default:true"`
+	IPWhitelist string      `json:"ipWhitelist"` // Comma-separated IPs or CIDR ranges; empty allows any IP
+	Roles       string      `json:"roles"`       // Comma-separated roles granted to the app
+	Remark      null.String `json:"remark"`
+}
+
+// Principal returns the principal authenticated requests of the app run as.
+func (a *ExternalApp) Principal() *Principal {
+	var roles []string
+	if a.Roles != constants.Empty {
+		roles = strings.Split(a.Roles, constants.Comma)
+	}
+
+	principal := NewExternalApp(a.ID, a.Name, roles...)
+	principal.Details = &ExternalAppConfig{
+		Enabled:     a.IsEnabled,
+		IPWhitelist: a.IPWhitelist,
+	}
+
+	return principal
+}
+
+// NewExternalAppSecret generates a random hex-encoded secret for registering an external app.
+func NewExternalAppSecret() string {
+	secret := make([]byte, externalAppSecretSize)
+	_, _ = rand.Read(secret)
+
+	return encoding.ToHex(secret)
+}
+
+// ExternalAppsChangedEvent is published when registered external apps are modified.
+type ExternalAppsChangedEvent struct {
+	event.BaseEvent
+
+	AppIDs []string `json:"appIds"` // Affected app ids (empty means all apps)
+}
+
+// PublishExternalAppsChangedEvent publishes an external apps changed event via the provided publisher.
+// If no app ids are specified, subscribers should interpret the event as affecting all apps.
+func PublishExternalAppsChangedEvent(publisher event.Publisher, appIDs ...string) {
+	publisher.Publish(&ExternalAppsChangedEvent{
+		BaseEvent: event.NewBaseEvent(eventTypeExternalAppsChanged),
+
+		AppIDs: appIDs,
+	})
+}
+
+// DBExternalAppLoader loads external apps from the sys_external_app table.
+// Apps are cached and invalidated by ExternalAppsChangedEvent.
+type DBExternalAppLoader struct {
+	db       orm.DB
+	appCache cache.Cache[ExternalApp]
+	logger   log.Logger
+}
+
+// NewDBExternalAppLoader creates an ExternalAppLoader reading the caller registry through the given database.
+func NewDBExternalAppLoader(db orm.DB, subscriber event.Subscriber) ExternalAppLoader {
+	loader := &DBExternalAppLoader{
+		db:       db,
+		appCache: cache.NewMemory[ExternalApp](),
+		logger:   ilog.Named("security:external_app_loader"),
+	}
+
+	if subscriber != nil {
+		subscriber.Subscribe(eventTypeExternalAppsChanged, loader.handleAppsChanged)
+	}
+
+	return loader
+}
+
+func (l *DBExternalAppLoader) handleAppsChanged(ctx context.Context, evt event.Event) {
+	changeEvent, ok := evt.(*ExternalAppsChangedEvent)
+	if !ok {
+		l.logger.Errorf("Received invalid event type: %T", evt)
+
+		return
+	}
+
+	if len(changeEvent.AppIDs) == 0 {
+		if err := l.appCache.Clear(ctx); err != nil {
+			l.logger.Errorf("Failed to clear external app cache: %v", err)
+		}
+
+		return
+	}
+
+	for _, appID := range changeEvent.AppIDs {
+		if err := l.appCache.Delete(ctx, appID); err != nil {
+			l.logger.Errorf("Failed to delete cache for external app %s: %v", appID, err)
+		}
+	}
+}
+
+// LoadByID returns the app principal and secret, or a nil principal when the app is not registered.
+func (l *DBExternalAppLoader) LoadByID(ctx context.Context, id string) (*Principal, string, error) {
+	// Unknown app ids are not cached, otherwise probing with random ids would grow the cache unbounded.
+	app, err := l.appCache.GetOrLoad(ctx, id, func(ctx context.Context) (ExternalApp, error) {
+		var app ExternalApp
+
+		err := l.db.NewSelect().
+			Model(&app).
+			Where(func(cb orm.ConditionBuilder) {
+				cb.PKEquals(id)
+			}).
+			Scan(ctx)
+
+		return app, err
+	})
+	if err != nil {
+		if result.IsRecordNotFound(err) {
+			return nil, constants.Empty, nil
+		}
+
+		return nil, constants.Empty, err
+	}
+
+	return app.Principal(), app.Secret, nil
+}
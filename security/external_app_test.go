@@ -0,0 +1,126 @@
+package security_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+
+	"github.com/ilxqx/vef-framework-go/config"
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/internal/database"
+	ievent "github.com/ilxqx/vef-framework-go/internal/event"
+	iorm "github.com/ilxqx/vef-framework-go/internal/orm"
+	"github.com/ilxqx/vef-framework-go/orm"
+	"github.com/ilxqx/vef-framework-go/security"
+)
+
+// ExternalAppLoaderTestSuite tests loading callers from the sys_external_app table.
+type ExternalAppLoaderTestSuite struct {
+	suite.Suite
+
+	ctx    context.Context
+	bus    event.Bus
+	bunDB  *bun.DB
+	db     orm.DB
+	loader security.ExternalAppLoader
+}
+
+func (s *ExternalAppLoaderTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	s.bus = ievent.NewMemoryBus([]event.Middleware{})
+	s.Require().NoError(s.bus.(interface{ Start() error }).Start(), "Should start event bus")
+
+	var err error
+
+	s.bunDB, err = database.New(&config.DatasourceConfig{Type: constants.SQLite})
+	s.Require().NoError(err)
+
+	_, err = s.bunDB.NewCreateTable().Model((*security.ExternalApp)(nil)).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	s.db = iorm.New(s.bunDB)
+	s.loader = security.NewDBExternalAppLoader(s.db, s.bus)
+
+	_, err = s.db.NewInsert().Model(&security.ExternalApp{
+		Model:       orm.Model{ID: "app1"},
+		Name:        "Partner",
+		Secret:      security.NewExternalAppSecret(),
+		IsEnabled:   true,
+		IPWhitelist: "10.0.0.0/8",
+		Roles:       "partner,reader",
+	}).Exec(s.ctx)
+	s.Require().NoError(err)
+}
+
+func (s *ExternalAppLoaderTestSuite) TearDownSuite() {
+	if s.bunDB != nil {
+		s.Require().NoError(s.bunDB.Close())
+	}
+}
+
+func (s *ExternalAppLoaderTestSuite) TestLoadRegisteredApp() {
+	principal, secret, err := s.loader.LoadByID(s.ctx, "app1")
+	s.Require().NoError(err)
+	s.Require().NotNil(principal, "Should load the registered app")
+
+	s.Equal(security.PrincipalTypeExternalApp, principal.Type)
+	s.Equal("Partner", principal.Name)
+	s.Equal([]string{"partner", "reader"}, principal.Roles)
+	s.Len(secret, 64, "Should return the hex-encoded secret")
+
+	details, ok := principal.Details.(*security.ExternalAppConfig)
+	s.Require().True(ok, "Should carry the external app config as details")
+	s.True(details.Enabled)
+	s.Equal("10.0.0.0/8", details.IPWhitelist)
+}
+
+func (s *ExternalAppLoaderTestSuite) TestLoadUnknownApp() {
+	principal, secret, err := s.loader.LoadByID(s.ctx, "unknown")
+	s.Require().NoError(err, "Unknown apps should not be an error")
+	s.Nil(principal)
+	s.Empty(secret)
+}
+
+func (s *ExternalAppLoaderTestSuite) TestChangedEventInvalidatesCache() {
+	_, err := s.db.NewInsert().Model(&security.ExternalApp{
+		Model:     orm.Model{ID: "app2"},
+		Name:      "Vendor",
+		Secret:    security.NewExternalAppSecret(),
+		IsEnabled: true,
+	}).Exec(s.ctx)
+	s.Require().NoError(err)
+
+	principal, _, err := s.loader.LoadByID(s.ctx, "app2")
+	s.Require().NoError(err)
+	s.Require().NotNil(principal)
+
+	_, err = s.db.NewUpdate().
+		Model((*security.ExternalApp)(nil)).
+		Set("is_enabled", false).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.PKEquals("app2")
+		}).
+		Exec(s.ctx)
+	s.Require().NoError(err)
+
+	principal, _, err = s.loader.LoadByID(s.ctx, "app2")
+	s.Require().NoError(err)
+	s.True(principal.Details.(*security.ExternalAppConfig).Enabled, "Should serve the cached app before the event")
+
+	security.PublishExternalAppsChangedEvent(s.bus, "app2")
+
+	s.Eventually(func() bool {
+		principal, _, err := s.loader.LoadByID(s.ctx, "app2")
+
+		return err == nil && !principal.Details.(*security.ExternalAppConfig).Enabled
+	}, time.Second, 10*time.Millisecond, "Should reload the app after the changed event")
+}
+
+func TestExternalAppLoaderSuite(t *testing.T) {
+	suite.Run(t, new(ExternalAppLoaderTestSuite))
+}
@@ -0,0 +1,334 @@
+package security
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
+
+const (
+	// TagPerm declares the permission token required to see a field, e.g. `perm:"sys.user.salary"`.
+	// Without the permission the field is zeroed, so it is omitted when its json tag has omitempty or omitzero.
+	// With the mask option, e.g. `perm:"sys.user.phone,mask"`, string fields are masked instead.
+	TagPerm = "perm"
+
+	permOptionMask = "mask"
+	maskChar       = '*'
+)
+
+// filterableTypes caches whether values of a type may contain perm tagged fields.
+var filterableTypes sync.Map
+
+// FilterFields returns the value with the perm tagged fields the principal is not permitted to see removed or masked.
+// Structs, pointers, slices, arrays, maps and interfaces are traversed; the value itself is never modified,
+// affected parts are copied instead. System principals see all fields.
+func FilterFields(ctx context.Context, checker PermissionChecker, principal *Principal, value any) (any, error) {
+	if value == nil || (principal != nil && principal.Type == PrincipalTypeSystem) {
+		return value, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if !isFilterable(rv.Type()) {
+		return value, nil
+	}
+
+	filter := &fieldFilter{
+		ctx:       ctx,
+		checker:   checker,
+		principal: principal,
+		granted:   make(map[string]bool),
+	}
+
+	filtered, changed, err := filter.filter(rv)
+	if err != nil || !changed {
+		return value, err
+	}
+
+	return filtered.Interface(), nil
+}
+
+// MaskString masks the middle of a string, keeping a quarter of the runes visible at each end,
+// e.g. "13812345678" becomes "13*******78".
+func MaskString(value string) string {
+	runes := []rune(value)
+
+	visible := len(runes) / 4
+	for i := visible; i < len(runes)-visible; i++ {
+		runes[i] = maskChar
+	}
+
+	return string(runes)
+}
+
+type fieldFilter struct {
+	ctx       context.Context
+	checker   PermissionChecker
+	principal *Principal
+	granted   map[string]bool
+}
+
+// filter returns a filtered copy of the value and whether anything was filtered.
+func (f *fieldFilter) filter(value reflect.Value) (reflect.Value, bool, error) {
+	if !isFilterable(value.Type()) {
+		return value, false, nil
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return value, false, nil
+		}
+
+		elem, changed, err := f.filter(value.Elem())
+		if err != nil || !changed {
+			return value, false, err
+		}
+
+		ptr := reflect.New(elem.Type())
+		ptr.Elem().Set(elem)
+
+		return ptr, true, nil
+
+	case reflect.Interface:
+		if value.IsNil() {
+			return value, false, nil
+		}
+
+		elem, changed, err := f.filter(value.Elem())
+		if err != nil || !changed {
+			return value, false, err
+		}
+
+		iface := reflect.New(value.Type()).Elem()
+		iface.Set(elem)
+
+		return iface, true, nil
+
+	case reflect.Struct:
+		return f.filterStruct(value)
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return value, false, nil
+		}
+
+		var copied reflect.Value
+
+		for i := range value.Len() {
+			elem, changed, err := f.filter(value.Index(i))
+			if err != nil {
+				return value, false, err
+			}
+
+			if !changed {
+				continue
+			}
+
+			if !copied.IsValid() {
+				copied = copySequence(value)
+			}
+
+			copied.Index(i).Set(elem)
+		}
+
+		if !copied.IsValid() {
+			return value, false, nil
+		}
+
+		return copied, true, nil
+
+	case reflect.Map:
+		if value.IsNil() {
+			return value, false, nil
+		}
+
+		var copied reflect.Value
+
+		iter := value.MapRange()
+		for iter.Next() {
+			elem, changed, err := f.filter(iter.Value())
+			if err != nil {
+				return value, false, err
+			}
+
+			if !changed {
+				continue
+			}
+
+			if !copied.IsValid() {
+				copied = reflect.MakeMapWithSize(value.Type(), value.Len())
+
+				copyIter := value.MapRange()
+				for copyIter.Next() {
+					copied.SetMapIndex(copyIter.Key(), copyIter.Value())
+				}
+			}
+
+			copied.SetMapIndex(iter.Key(), elem)
+		}
+
+		if !copied.IsValid() {
+			return value, false, nil
+		}
+
+		return copied, true, nil
+
+	default:
+		return value, false, nil
+	}
+}
+
+func (f *fieldFilter) filterStruct(value reflect.Value) (reflect.Value, bool, error) {
+	var (
+		typ    = value.Type()
+		copied reflect.Value
+	)
+
+	ensureCopy := func() {
+		if !copied.IsValid() {
+			copied = reflect.New(typ).Elem()
+			copied.Set(value)
+		}
+	}
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if tag, ok := field.Tag.Lookup(TagPerm); ok {
+			token, option, _ := strings.Cut(tag, constants.Comma)
+
+			granted, err := f.isGranted(token)
+			if err != nil {
+				return value, false, err
+			}
+
+			if !granted {
+				if value.Field(i).IsZero() {
+					continue
+				}
+
+				ensureCopy()
+
+				target := copied.Field(i)
+				if option == permOptionMask && target.Kind() == reflect.String {
+					target.SetString(MaskString(target.String()))
+				} else {
+					target.SetZero()
+				}
+
+				continue
+			}
+		}
+
+		filtered, changed, err := f.filter(value.Field(i))
+		if err != nil {
+			return value, false, err
+		}
+
+		if changed {
+			ensureCopy()
+			copied.Field(i).Set(filtered)
+		}
+	}
+
+	if !copied.IsValid() {
+		return value, false, nil
+	}
+
+	return copied, true, nil
+}
+
+// isGranted checks a permission once per filtered value.
+func (f *fieldFilter) isGranted(token string) (bool, error) {
+	if granted, ok := f.granted[token]; ok {
+		return granted, nil
+	}
+
+	granted := false
+
+	if f.checker != nil && f.principal != nil && token != constants.Empty {
+		var err error
+		if granted, err = f.checker.HasPermission(f.ctx, f.principal, token); err != nil {
+			return false, err
+		}
+	}
+
+	f.granted[token] = granted
+
+	return granted, nil
+}
+
+func copySequence(value reflect.Value) reflect.Value {
+	if value.Kind() == reflect.Array {
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+
+		return copied
+	}
+
+	copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+	reflect.Copy(copied, value)
+
+	return copied
+}
+
+// isFilterable reports whether values of the type may contain perm tagged fields.
+// Interfaces are always filterable since their dynamic values are only known at runtime.
+func isFilterable(typ reflect.Type) bool {
+	if cached, ok := filterableTypes.Load(typ); ok {
+		return cached.(bool)
+	}
+
+	filterable := computeFilterable(typ, make(map[reflect.Type]bool))
+	filterableTypes.Store(typ, filterable)
+
+	return filterable
+}
+
+func computeFilterable(typ reflect.Type, visiting map[reflect.Type]bool) bool {
+	if cached, ok := filterableTypes.Load(typ); ok {
+		return cached.(bool)
+	}
+
+	// Recursive types are filterable only through their other fields.
+	if visiting[typ] {
+		return false
+	}
+
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	switch typ.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return computeFilterable(typ.Elem(), visiting)
+	case reflect.Map:
+		return computeFilterable(typ.Elem(), visiting)
+	case reflect.Struct:
+		for i := range typ.NumField() {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			if _, ok := field.Tag.Lookup(TagPerm); ok {
+				return true
+			}
+
+			if computeFilterable(field.Type, visiting) {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return false
+	}
+}
@@ -0,0 +1,136 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// grantingChecker grants the listed permission tokens and counts the checks.
+type grantingChecker struct {
+	tokens map[string]bool
+	calls  int
+	err    error
+}
+
+func (c *grantingChecker) HasPermission(_ context.Context, _ *Principal, permToken string) (bool, error) {
+	c.calls++
+
+	return c.tokens[permToken], c.err
+}
+
+type employeeAddress struct {
+	City   string `json:"city"`
+	Street string `json:"street,omitempty" perm:"hr.address"`
+}
+
+type employee struct {
+	Name     string           `json:"name"`
+	Salary   int              `json:"salary,omitempty" perm:"hr.salary"`
+	Phone    string           `json:"phone"            perm:"hr.phone,mask"`
+	Address  *employeeAddress `json:"address"`
+	Manager  *employee        `json:"manager,omitempty"`
+	internal string
+}
+
+type plainDTO struct {
+	Name string `json:"name"`
+}
+
+func newEmployee() *employee {
+	return &employee{
+		Name:     "Alice",
+		Salary:   10000,
+		Phone:    "13812345678",
+		Address:  &employeeAddress{City: "Beijing", Street: "Chang'an Avenue"},
+		Manager:  &employee{Name: "Bob", Salary: 20000},
+		internal: "kept",
+	}
+}
+
+func TestFilterFields(t *testing.T) {
+	ctx := context.Background()
+	user := NewUser("u1", "User", "staff")
+
+	t.Run("RemovesAndMasksDeniedFields", func(t *testing.T) {
+		checker := &grantingChecker{tokens: map[string]bool{"hr.address": true}}
+		original := newEmployee()
+
+		filtered, err := FilterFields(ctx, checker, user, original)
+		require.NoError(t, err, "Should filter without error")
+
+		result := filtered.(*employee)
+		assert.Equal(t, "Alice", result.Name, "Untagged fields should be kept")
+		assert.Zero(t, result.Salary, "Denied field should be removed")
+		assert.Equal(t, "13*******78", result.Phone, "Denied mask field should be masked")
+		assert.Equal(t, "Chang'an Avenue", result.Address.Street, "Granted field should be kept")
+		assert.Zero(t, result.Manager.Salary, "Nested structs should be filtered")
+		assert.Equal(t, "kept", result.internal, "Unexported fields should be copied")
+		assert.Equal(t, 3, checker.calls, "Each permission should be checked once")
+
+		assert.Equal(t, 10000, original.Salary, "Original value should not be modified")
+		assert.Equal(t, 20000, original.Manager.Salary, "Original nested value should not be modified")
+	})
+
+	t.Run("FiltersCollections", func(t *testing.T) {
+		checker := &grantingChecker{}
+
+		filtered, err := FilterFields(ctx, checker, user, map[string]any{
+			"items": []employee{*newEmployee()},
+			"total": 1,
+		})
+		require.NoError(t, err, "Should filter without error")
+
+		items := filtered.(map[string]any)["items"].([]employee)
+		assert.Zero(t, items[0].Salary, "Slices inside maps should be filtered")
+		assert.Zero(t, items[0].Address.Street, "Denied nested field should be removed")
+		assert.Equal(t, 1, filtered.(map[string]any)["total"], "Other entries should be kept")
+	})
+
+	t.Run("SystemPrincipalSeesAll", func(t *testing.T) {
+		checker := &grantingChecker{}
+		original := newEmployee()
+
+		filtered, err := FilterFields(ctx, checker, PrincipalSystem, original)
+		require.NoError(t, err, "Should filter without error")
+
+		assert.Same(t, original, filtered, "System principal should get the value unchanged")
+		assert.Zero(t, checker.calls, "Should not check permissions")
+	})
+
+	t.Run("UntaggedTypesAreUnchanged", func(t *testing.T) {
+		checker := &grantingChecker{}
+		original := []plainDTO{{Name: "a"}}
+
+		filtered, err := FilterFields(ctx, checker, user, original)
+		require.NoError(t, err, "Should filter without error")
+
+		assert.Equal(t, original, filtered, "Untagged values should be returned as is")
+		assert.Zero(t, checker.calls, "Should not check permissions")
+	})
+
+	t.Run("NilCheckerDeniesTaggedFields", func(t *testing.T) {
+		filtered, err := FilterFields(ctx, nil, user, newEmployee())
+		require.NoError(t, err, "Should filter without error")
+
+		assert.Zero(t, filtered.(*employee).Salary, "Tagged fields should be removed without checker")
+	})
+
+	t.Run("CheckerError", func(t *testing.T) {
+		checker := &grantingChecker{err: errors.New("loader failed")}
+
+		_, err := FilterFields(ctx, checker, user, newEmployee())
+		assert.Error(t, err, "Should return the checker error")
+	})
+}
+
+func TestMaskString(t *testing.T) {
+	assert.Equal(t, "13*******78", MaskString("13812345678"))
+	assert.Equal(t, "a**d", MaskString("abcd"))
+	assert.Equal(t, "**", MaskString("ab"))
+	assert.Equal(t, "张**四", MaskString("张三丰四"), "Should mask runes, not bytes")
+	assert.Empty(t, MaskString(""))
+}
@@ -101,6 +101,14 @@ type DataPermissionResolver interface {
 	ResolveDataScope(ctx context.Context, principal *Principal, permToken string) (DataScope, error)
 }
 
+// DataScopeContextResolver resolves the orm.DataScopeContext of the requests of a principal, e.g. to look up the
+// departments whose rows it may access. Without a resolver requests carry the ID of the principal and the tenant
+// of the request, and system principals bypass the data scopes.
+type DataScopeContextResolver interface {
+	// ResolveDataScopeContext returns the scope context of the request, nil to use the default one.
+	ResolveDataScopeContext(ctx context.Context, principal *Principal) (*orm.DataScopeContext, error)
+}
+
 // DataPermissionApplier applies data permission filters to database queries.
 // Wraps the resolution and application of DataScope into a single operation.
 type DataPermissionApplier interface {
@@ -119,3 +127,12 @@ type NonceStore interface {
 	// nonces remain valid while their corresponding timestamps are accepted.
 	Store(ctx context.Context, appID, nonce string, ttl time.Duration) error
 }
+
+// AtomicNonceStore is a NonceStore checking and storing a nonce in one atomic step.
+// Signature verification prefers it, as concurrent replays of a nonce may all pass Exists before any Store.
+type AtomicNonceStore interface {
+	NonceStore
+	// StoreIfAbsent saves a nonce with the specified TTL unless it has already been used,
+	// reporting whether it was saved.
+	StoreIfAbsent(ctx context.Context, appID, nonce string, ttl time.Duration) (bool, error)
+}
@@ -0,0 +1,225 @@
+package security
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+const (
+	// IPRuleTypeAllow admits matching addresses; once any allow rule exists, all other addresses are blocked.
+	IPRuleTypeAllow = "allow"
+	// IPRuleTypeDeny blocks matching addresses. Deny rules take precedence over allow rules.
+	IPRuleTypeDeny = "deny"
+
+	// IPBlockReasonDenied means the address matched a deny rule.
+	IPBlockReasonDenied = "denied"
+	// IPBlockReasonNotAllowed means allow rules exist and the address matched none of them.
+	IPBlockReasonNotAllowed = "not_allowed"
+
+	// IPFilterScopeGlobal marks attempts blocked by the application wide filter.
+	IPFilterScopeGlobal = "global"
+	// IPFilterScopeOperation marks attempts blocked by the filter of an Api operation.
+	IPFilterScopeOperation = "operation"
+
+	eventTypeIPRulesChanged = "vef.security.ip_rules.changed"
+	eventTypeIPBlocked      = "vef.security.ip.blocked"
+)
+
+// IPRule is a managed allow or deny rule for an IP address or CIDR range.
+// Rules are applied by the global IP filter in addition to the configured ones.
+type IPRule struct {
+	orm.BaseModel `bun:"table:sys_ip_rule,alias:sir"`
+	orm.Model
+
+	Type      string      `json:"type"      bun:",notnull"` // allow or deny
+	Address   string      `json:"address"   bun:",notnull"` // IP address (e.g. 192.168.1.1) or CIDR range (e.g. 192.168.1.0/24)
+	IsEnabled bool        `json:"isEnabled" bun:",notnull,default:true"`
+	Remark    null.String `json:"remark"`
+}
+
+// IPRuleLoader loads the managed IP rules.
+type IPRuleLoader interface {
+	// LoadIPRules returns the enabled rules.
+	LoadIPRules(ctx context.Context) ([]IPRule, error)
+}
+
+// dbIPRuleLoader loads rules from the sys_ip_rule table.
+type dbIPRuleLoader struct {
+	db orm.DB
+}
+
+// NewDBIPRuleLoader creates an IPRuleLoader reading the sys_ip_rule table through the given database.
+func NewDBIPRuleLoader(db orm.DB) IPRuleLoader {
+	return &dbIPRuleLoader{db: db}
+}
+
+func (l *dbIPRuleLoader) LoadIPRules(ctx context.Context) ([]IPRule, error) {
+	var rules []IPRule
+
+	if err := l.db.NewSelect().
+		Model(&rules).
+		Where(func(cb orm.ConditionBuilder) {
+			cb.IsTrue("is_enabled")
+		}).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// IPAccessList decides whether addresses pass a set of allow and deny entries.
+// Entries are IP addresses or CIDR ranges; deny entries take precedence and
+// a non-empty allow list blocks every address it does not contain.
+type IPAccessList struct {
+	allow *IPWhitelistValidator
+	deny  *IPWhitelistValidator
+}
+
+// NewIPAccessList creates an access list from allow and deny entries.
+func NewIPAccessList(allow, deny []string) *IPAccessList {
+	return &IPAccessList{
+		allow: NewIPWhitelistValidator(strings.Join(allow, constants.Comma)),
+		deny:  NewIPWhitelistValidator(strings.Join(deny, constants.Comma)),
+	}
+}
+
+// NewIPAccessListFromRules creates an access list from configured entries merged with managed rules.
+func NewIPAccessListFromRules(allow, deny []string, rules []IPRule) *IPAccessList {
+	allow = append([]string(nil), allow...)
+	deny = append([]string(nil), deny...)
+
+	for _, rule := range rules {
+		switch rule.Type {
+		case IPRuleTypeAllow:
+			allow = append(allow, rule.Address)
+		case IPRuleTypeDeny:
+			deny = append(deny, rule.Address)
+		default:
+			logger.Warnf("Ignoring IP rule %s with unknown type %q", rule.ID, rule.Type)
+		}
+	}
+
+	return NewIPAccessList(allow, deny)
+}
+
+// Check returns the reason the ip is blocked, or an empty string when it passes.
+func (l *IPAccessList) Check(ip string) string {
+	if !l.deny.IsEmpty() && l.deny.IsAllowed(ip) {
+		return IPBlockReasonDenied
+	}
+
+	if !l.allow.IsAllowed(ip) {
+		return IPBlockReasonNotAllowed
+	}
+
+	return constants.Empty
+}
+
+// IsEmpty reports whether the list has no entries and therefore passes every address.
+func (l *IPAccessList) IsEmpty() bool {
+	return l.allow.IsEmpty() && l.deny.IsEmpty()
+}
+
+// TrustedProxies holds the proxies trusted to append the address of their client to X-Forwarded-For.
+type TrustedProxies struct {
+	validator *IPWhitelistValidator
+}
+
+// NewTrustedProxies creates the trusted proxies from IP addresses and CIDR ranges.
+func NewTrustedProxies(entries []string) *TrustedProxies {
+	return &TrustedProxies{
+		validator: NewIPWhitelistValidator(strings.Join(entries, constants.Comma)),
+	}
+}
+
+// Contains reports whether the ip is a trusted proxy; none is without entries.
+func (p *TrustedProxies) Contains(ip string) bool {
+	return p != nil && !p.validator.IsEmpty() && p.validator.IsAllowed(ip)
+}
+
+// IPRulesChangedEvent is published when managed IP rules are modified.
+type IPRulesChangedEvent struct {
+	event.BaseEvent
+}
+
+// PublishIPRulesChangedEvent publishes an IP rules changed event so that the IP filter reloads its rules.
+func PublishIPRulesChangedEvent(publisher event.Publisher) {
+	publisher.Publish(&IPRulesChangedEvent{
+		BaseEvent: event.NewBaseEvent(eventTypeIPRulesChanged),
+	})
+}
+
+// SubscribeIPRulesChangedEvent subscribes to IP rules changed events.
+func SubscribeIPRulesChangedEvent(subscriber event.Subscriber, handler func(context.Context, *IPRulesChangedEvent)) event.UnsubscribeFunc {
+	return subscriber.Subscribe(eventTypeIPRulesChanged, func(ctx context.Context, evt event.Event) {
+		if changedEvent, ok := evt.(*IPRulesChangedEvent); ok {
+			handler(ctx, changedEvent)
+		}
+	})
+}
+
+// IPBlockedEvent is published for every request rejected by an IP filter.
+type IPBlockedEvent struct {
+	event.BaseEvent
+
+	IP        string    `json:"ip"`
+	Reason    string    `json:"reason"` // denied or not_allowed
+	Scope     string    `json:"scope"`  // global or operation
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Resource  string    `json:"resource,omitempty"`
+	Action    string    `json:"action,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	RequestID string    `json:"requestId"`
+	UserAgent string    `json:"userAgent"`
+	BlockedAt time.Time `json:"blockedAt"`
+}
+
+// IPBlockedEventParams contains the parameters for creating an IPBlockedEvent.
+type IPBlockedEventParams struct {
+	IP        string
+	Reason    string
+	Scope     string
+	Method    string
+	Path      string
+	Resource  string
+	Action    string
+	Version   string
+	RequestID string
+	UserAgent string
+}
+
+// NewIPBlockedEvent creates a new IP blocked event.
+func NewIPBlockedEvent(params IPBlockedEventParams) *IPBlockedEvent {
+	return &IPBlockedEvent{
+		BaseEvent: event.NewBaseEvent(eventTypeIPBlocked),
+
+		IP:        params.IP,
+		Reason:    params.Reason,
+		Scope:     params.Scope,
+		Method:    params.Method,
+		Path:      params.Path,
+		Resource:  params.Resource,
+		Action:    params.Action,
+		Version:   params.Version,
+		RequestID: params.RequestID,
+		UserAgent: params.UserAgent,
+		BlockedAt: time.Now(),
+	}
+}
+
+// SubscribeIPBlockedEvent subscribes to IP blocked events, e.g. to persist blocked attempts.
+func SubscribeIPBlockedEvent(subscriber event.Subscriber, handler func(context.Context, *IPBlockedEvent)) event.UnsubscribeFunc {
+	return subscriber.Subscribe(eventTypeIPBlocked, func(ctx context.Context, evt event.Event) {
+		if blockedEvent, ok := evt.(*IPBlockedEvent); ok {
+			handler(ctx, blockedEvent)
+		}
+	})
+}
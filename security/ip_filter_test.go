@@ -0,0 +1,51 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPAccessList_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		allow    []string
+		deny     []string
+		ip       string
+		expected string
+	}{
+		{"EmptyListPassesAll", nil, nil, "203.0.113.7", ""},
+		{"DenyListBlocksMatch", nil, []string{"203.0.113.0/24"}, "203.0.113.7", IPBlockReasonDenied},
+		{"DenyListPassesOthers", nil, []string{"203.0.113.0/24"}, "198.51.100.1", ""},
+		{"AllowListPassesMatch", []string{"10.0.0.0/8", "192.168.1.1"}, nil, "192.168.1.1", ""},
+		{"AllowListBlocksOthers", []string{"10.0.0.0/8"}, nil, "192.168.1.1", IPBlockReasonNotAllowed},
+		{"DenyTakesPrecedence", []string{"10.0.0.0/8"}, []string{"10.0.0.13"}, "10.0.0.13", IPBlockReasonDenied},
+		{"IPv6Range", []string{"2001:db8::/32"}, nil, "2001:db8::1", ""},
+		{"InvalidAddressNotAllowed", []string{"10.0.0.0/8"}, nil, "not-an-ip", IPBlockReasonNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := NewIPAccessList(tt.allow, tt.deny)
+			assert.Equal(t, tt.expected, list.Check(tt.ip))
+		})
+	}
+}
+
+func TestNewIPAccessListFromRules(t *testing.T) {
+	allow := []string{"10.0.0.0/8"}
+	rules := []IPRule{
+		{Type: IPRuleTypeAllow, Address: "192.168.1.0/24"},
+		{Type: IPRuleTypeDeny, Address: "10.0.0.13"},
+		{Type: "unknown", Address: "198.51.100.1"},
+	}
+
+	list := NewIPAccessListFromRules(allow, nil, rules)
+
+	assert.Empty(t, list.Check("10.1.1.1"), "Configured allow entries should apply")
+	assert.Empty(t, list.Check("192.168.1.20"), "Managed allow rules should apply")
+	assert.Equal(t, IPBlockReasonDenied, list.Check("10.0.0.13"), "Managed deny rules should apply")
+	assert.Equal(t, IPBlockReasonNotAllowed, list.Check("198.51.100.1"), "Rules of unknown type should be ignored")
+	assert.Equal(t, []string{"10.0.0.0/8"}, allow, "Configured entries should not be modified")
+	assert.False(t, list.IsEmpty())
+}
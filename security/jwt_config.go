@@ -1,6 +1,8 @@
 package security
 
 import (
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/spf13/cast"
 )
@@ -8,16 +10,18 @@ import (
 // Custom and standard JWT claim keys.
 // Short keys are used for custom claims to keep token size small.
 const (
-	claimJWTID     = "jti" // JWT ID
-	claimSubject   = "sub" // Subject
-	claimIssuer    = "iss" // Issuer
-	claimAudience  = "aud" // Audience
-	claimIssuedAt  = "iat" // Issued At
-	claimNotBefore = "nbf" // Not Before
-	claimExpiresAt = "exp" // Expires At
-	claimType      = "typ" // Token Type
-	claimRoles     = "rls" // User Roles
-	claimDetails   = "det" // User Details
+	claimJWTID                  = "jti" // JWT ID
+	claimSubject                = "sub" // Subject
+	claimIssuer                 = "iss" // Issuer
+	claimAudience               = "aud" // Audience
+	claimIssuedAt               = "iat" // Issued At
+	claimNotBefore              = "nbf" // Not Before
+	claimExpiresAt              = "exp" // Expires At
+	claimType                   = "typ" // Token Type
+	claimRoles                  = "rls" // User Roles
+	claimDetails                = "det" // User Details
+	claimImpersonator           = "imp" // Impersonating operator
+	claimImpersonationExpiresAt = "ixp" // End of the impersonation
 )
 
 // JWTConfig is the configuration for the JWT token.
@@ -90,6 +94,27 @@ func (b *JWTClaimsBuilder) Details() (any, bool) {
 	return details, ok
 }
 
+// WithImpersonator sets the subject of the operator impersonating the subject of the token.
+func (b *JWTClaimsBuilder) WithImpersonator(subject string) *JWTClaimsBuilder {
+	b.claims[claimImpersonator] = subject
+
+	return b
+}
+
+// Impersonator returns the impersonator claim.
+func (b *JWTClaimsBuilder) Impersonator() (string, bool) {
+	impersonator, ok := b.claims[claimImpersonator]
+
+	return cast.ToString(impersonator), ok
+}
+
+// WithImpersonationExpiresAt sets the time the impersonation ends, whatever the expiry of the token.
+func (b *JWTClaimsBuilder) WithImpersonationExpiresAt(expiresAt time.Time) *JWTClaimsBuilder {
+	b.claims[claimImpersonationExpiresAt] = expiresAt.Unix()
+
+	return b
+}
+
 func (b *JWTClaimsBuilder) WithType(typ string) *JWTClaimsBuilder {
 	b.claims[claimType] = typ
 
@@ -155,6 +180,23 @@ func (a *JWTClaimsAccessor) Details() any {
 	return a.claims[claimDetails]
 }
 
+// Impersonator returns the subject of the operator impersonating the subject of the token.
+// Returns empty string if the token is not an impersonation token.
+func (a *JWTClaimsAccessor) Impersonator() string {
+	return cast.ToString(a.claims[claimImpersonator])
+}
+
+// ImpersonationExpiresAt returns the time the impersonation ends.
+// Returns the zero time if the claim is missing.
+func (a *JWTClaimsAccessor) ImpersonationExpiresAt() time.Time {
+	expiresAt := cast.ToInt64(a.claims[claimImpersonationExpiresAt])
+	if expiresAt == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(expiresAt, 0)
+}
+
 // Type returns the token type claim.
 // Returns empty string if the claim is missing or not a string.
 func (a *JWTClaimsAccessor) Type() string {
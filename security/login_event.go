@@ -7,7 +7,8 @@ import (
 )
 
 const (
-	eventTypeLogin = "vef.security.login"
+	eventTypeLogin  = "vef.security.login"
+	eventTypeLogout = "vef.security.logout"
 )
 
 // LoginEvent represents a user login event.
@@ -19,6 +20,7 @@ type LoginEvent struct {
 	Username   string `json:"username"`
 	LoginIP    string `json:"loginIp"`
 	UserAgent  string `json:"userAgent"`
+	DeviceID   string `json:"deviceId"` // From the X-Device-Id header, if sent
 	TraceID    string `json:"traceId"`
 	IsOk       bool   `json:"isOk"`
 	FailReason string `json:"failReason"` // Populated on failure
@@ -32,6 +34,7 @@ type LoginEventParams struct {
 	Username   string
 	LoginIP    string
 	UserAgent  string
+	DeviceID   string
 	TraceID    string
 	IsOk       bool
 	FailReason string
@@ -47,6 +50,7 @@ func NewLoginEvent(params LoginEventParams) *LoginEvent {
 		Username:   params.Username,
 		LoginIP:    params.LoginIP,
 		UserAgent:  params.UserAgent,
+		DeviceID:   params.DeviceID,
 		TraceID:    params.TraceID,
 		IsOk:       params.IsOk,
 		FailReason: params.FailReason,
@@ -63,3 +67,48 @@ func SubscribeLoginEvent(subscriber event.Subscriber, handler func(context.Conte
 		}
 	})
 }
+
+// LogoutEvent represents a user logout event.
+type LogoutEvent struct {
+	event.BaseEvent
+
+	UserID    string `json:"userId"`
+	Username  string `json:"username"`
+	LogoutIP  string `json:"logoutIp"`
+	UserAgent string `json:"userAgent"`
+	DeviceID  string `json:"deviceId"`
+	TraceID   string `json:"traceId"`
+}
+
+// LogoutEventParams contains parameters for creating a LogoutEvent.
+type LogoutEventParams struct {
+	UserID    string
+	Username  string
+	LogoutIP  string
+	UserAgent string
+	DeviceID  string
+	TraceID   string
+}
+
+// NewLogoutEvent creates a new logout event with the given parameters.
+func NewLogoutEvent(params LogoutEventParams) *LogoutEvent {
+	return &LogoutEvent{
+		BaseEvent: event.NewBaseEvent(eventTypeLogout),
+		UserID:    params.UserID,
+		Username:  params.Username,
+		LogoutIP:  params.LogoutIP,
+		UserAgent: params.UserAgent,
+		DeviceID:  params.DeviceID,
+		TraceID:   params.TraceID,
+	}
+}
+
+// SubscribeLogoutEvent subscribes to logout events.
+// Returns an unsubscribe function that can be called to remove the subscription.
+func SubscribeLogoutEvent(subscriber event.Subscriber, handler func(context.Context, *LogoutEvent)) event.UnsubscribeFunc {
+	return subscriber.Subscribe(eventTypeLogout, func(ctx context.Context, evt event.Event) {
+		if logoutEvt, ok := evt.(*LogoutEvent); ok {
+			handler(ctx, logoutEvt)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package security
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+	"github.com/ilxqx/vef-framework-go/event"
+	"github.com/ilxqx/vef-framework-go/null"
+	"github.com/ilxqx/vef-framework-go/orm"
+)
+
+const (
+	// LoginActionLogin records a login attempt, successful or failed.
+	LoginActionLogin = "login"
+	// LoginActionLogout records a logout.
+	LoginActionLogout = "logout"
+
+	// RiskFlagNewDevice marks a successful login from a device the user has not logged in from before.
+	RiskFlagNewDevice = "new_device"
+	// RiskFlagImpossibleTravel marks a successful login from a location that cannot be reached
+	// from the location of the previous login in the elapsed time.
+	RiskFlagImpossibleTravel = "impossible_travel"
+
+	eventTypeLoginRisk = "vef.security.login.risk"
+)
+
+// GeoLocation is the approximate location of an IP address.
+type GeoLocation struct {
+	Country   string  `json:"country"`
+	Region    string  `json:"region"`
+	City      string  `json:"city"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// GeoLocator resolves the location of IP addresses, e.g. backed by a GeoIP database.
+// Provide an implementation to record login locations and detect impossible travel.
+type GeoLocator interface {
+	// Locate returns the location of the ip, or nil when it is unknown.
+	Locate(ctx context.Context, ip string) (*GeoLocation, error)
+}
+
+// RiskFlags is the set of anomalies detected for a login, stored as a comma-separated column.
+type RiskFlags []string
+
+// Has reports whether the flag is set.
+func (f RiskFlags) Has(flag string) bool {
+	return slices.Contains(f, flag)
+}
+
+// Value implements driver.Valuer.
+func (f RiskFlags) Value() (driver.Value, error) {
+	return strings.Join(f, constants.Comma), nil
+}
+
+// Scan implements sql.Scanner.
+func (f *RiskFlags) Scan(src any) error {
+	var value string
+
+	switch v := src.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("%w: cannot scan %T into RiskFlags", ErrUnsupportedScanType, src)
+	}
+
+	if value == constants.Empty {
+		*f = nil
+	} else {
+		*f = strings.Split(value, constants.Comma)
+	}
+
+	return nil
+}
+
+// LoginRecord is an entry of the login audit table.
+// Every login attempt and logout is recorded when login auditing is enabled.
+type LoginRecord struct {
+	orm.BaseModel `bun:"table:sys_login_record,alias:slr"`
+	orm.IDModel
+	orm.CreatedModel
+
+	// Action is either LoginActionLogin or LoginActionLogout
+	Action string `json:"action"     bun:"action,notnull"`
+	// AuthType is the authentication kind of the login, e.g. "password"
+	AuthType string `json:"authType"   bun:"auth_type,notnull"`
+	// UserID is empty for failed logins of unknown users
+	UserID   string `json:"userId"     bun:"user_id,notnull"`
+	Username string `json:"username"   bun:"username,notnull"`
+	// IsOk reports whether the login succeeded; logouts are always ok
+	IsOk       bool   `json:"isOk"       bun:"is_ok,notnull"`
+	FailReason string `json:"failReason" bun:"fail_reason,notnull"`
+	ErrorCode  int    `json:"errorCode"  bun:"error_code,notnull"`
+	IP         string `json:"ip"         bun:"ip,notnull"`
+	UserAgent  string `json:"userAgent"  bun:"user_agent,notnull"`
+	DeviceID   string `json:"deviceId"   bun:"device_id,notnull"`
+	TraceID    string `json:"traceId"    bun:"trace_id,notnull"`
+	// Country, Region, City and the coordinates are resolved by the GeoLocator, if any
+	Country   string     `json:"country"    bun:"country,notnull"`
+	Region    string     `json:"region"     bun:"region,notnull"`
+	City      string     `json:"city"       bun:"city,notnull"`
+	Latitude  null.Float `json:"latitude"   bun:"latitude"`
+	Longitude null.Float `json:"longitude"  bun:"longitude"`
+	// RiskFlags lists the anomalies detected for successful logins
+	RiskFlags RiskFlags `json:"riskFlags"  bun:"risk_flags,notnull"`
+}
+
+// LoginRiskEvent is published for successful logins with anomalies, e.g. to alert the user or administrators.
+type LoginRiskEvent struct {
+	event.BaseEvent
+
+	Record *LoginRecord `json:"record"`
+}
+
+// NewLoginRiskEvent creates a new login risk event for the record.
+func NewLoginRiskEvent(record *LoginRecord) *LoginRiskEvent {
+	return &LoginRiskEvent{
+		BaseEvent: event.NewBaseEvent(eventTypeLoginRisk),
+		Record:    record,
+	}
+}
+
+// SubscribeLoginRiskEvent subscribes to login risk events.
+// Returns an unsubscribe function that can be called to remove the subscription.
+func SubscribeLoginRiskEvent(subscriber event.Subscriber, handler func(context.Context, *LoginRiskEvent)) event.UnsubscribeFunc {
+	return subscriber.Subscribe(eventTypeLoginRisk, func(ctx context.Context, evt event.Event) {
+		if riskEvt, ok := evt.(*LoginRiskEvent); ok {
+			handler(ctx, riskEvt)
+		}
+	})
+}
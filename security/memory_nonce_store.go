@@ -10,7 +10,7 @@ import (
 
 // MemoryNonceStore implements NonceStore using an in-memory cache.
 // This implementation is suitable for development and single-instance deployments.
-// For distributed systems, use RedisNonceStore instead.
+// For distributed systems, use NewCacheNonceStore with a Redis cache instead.
 type MemoryNonceStore struct {
 	cache cache.Cache[bool]
 }
@@ -40,3 +40,8 @@ func (m *MemoryNonceStore) Store(ctx context.Context, appID, nonce string, ttl t
 
 	return m.cache.Set(ctx, key, true, ttl)
 }
+
+// StoreIfAbsent saves a nonce with the specified TTL unless it has already been used.
+func (m *MemoryNonceStore) StoreIfAbsent(ctx context.Context, appID, nonce string, ttl time.Duration) (bool, error) {
+	return m.cache.SetIfAbsent(ctx, m.buildKey(appID, nonce), true, ttl)
+}
@@ -0,0 +1,129 @@
+package security
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemorySessionStore implements SessionStore in memory.
+// This implementation is suitable for development and single-instance deployments.
+type MemorySessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+	byUser   map[string]map[string]struct{}
+}
+
+// NewMemorySessionStore creates a new in-memory session store.
+func NewMemorySessionStore() SessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[string]*Session),
+		byUser:   make(map[string]map[string]struct{}),
+	}
+}
+
+// Save creates or replaces a session.
+func (m *MemorySessionStore) Save(_ context.Context, session *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *session
+	m.sessions[session.ID] = &stored
+
+	ids, ok := m.byUser[session.UserID]
+	if !ok {
+		ids = make(map[string]struct{})
+		m.byUser[session.UserID] = ids
+	}
+
+	ids[session.ID] = struct{}{}
+
+	return nil
+}
+
+// Get returns a copy of the session, or nil when it does not exist or has expired.
+func (m *MemorySessionStore) Get(_ context.Context, id string) (*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, ok := m.sessions[id]
+	if !ok || !session.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+
+	copied := *session
+
+	return &copied, nil
+}
+
+// Touch updates the activity and expiry of the unexpired session and returns a copy of it, or nil when it is gone.
+func (m *MemorySessionStore) Touch(_ context.Context, id string, lastActiveAt, expiresAt time.Time) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[id]
+	if !ok || !session.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+
+	session.LastActiveAt = lastActiveAt
+	session.ExpiresAt = expiresAt
+
+	copied := *session
+
+	return &copied, nil
+}
+
+// ListByUser returns copies of the unexpired sessions of the user and drops the expired ones.
+func (m *MemorySessionStore) ListByUser(_ context.Context, userID string) ([]*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var (
+		now      = time.Now()
+		sessions []*Session
+	)
+
+	for id := range m.byUser[userID] {
+		session := m.sessions[id]
+		if !session.ExpiresAt.After(now) {
+			m.delete(id)
+
+			continue
+		}
+
+		copied := *session
+		sessions = append(sessions, &copied)
+	}
+
+	return sessions, nil
+}
+
+// Delete removes the sessions.
+func (m *MemorySessionStore) Delete(_ context.Context, ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range ids {
+		m.delete(id)
+	}
+
+	return nil
+}
+
+func (m *MemorySessionStore) delete(id string) {
+	session, ok := m.sessions[id]
+	if !ok {
+		return
+	}
+
+	delete(m.sessions, id)
+
+	if ids := m.byUser[session.UserID]; ids != nil {
+		delete(ids, id)
+
+		if len(ids) == 0 {
+			delete(m.byUser, session.UserID)
+		}
+	}
+}
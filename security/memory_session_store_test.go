@@ -0,0 +1,137 @@
+package security
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSession(id, userID string, expiresAt time.Time) *Session {
+	return &Session{
+		ID:           id,
+		UserID:       userID,
+		CreatedAt:    time.Now(),
+		LastActiveAt: time.Now(),
+		ExpiresAt:    expiresAt,
+	}
+}
+
+func TestMemorySessionStore_SaveAndGet(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ExistingSession", func(t *testing.T) {
+		store := NewMemorySessionStore()
+		require.NoError(t, store.Save(ctx, newTestSession("s1", "u1", time.Now().Add(time.Hour))), "Should save session")
+
+		session, err := store.Get(ctx, "s1")
+
+		require.NoError(t, err, "Should get session without error")
+		require.NotNil(t, session, "Session should exist")
+		assert.Equal(t, "u1", session.UserID, "Should return the stored session")
+	})
+
+	t.Run("MissingSession", func(t *testing.T) {
+		store := NewMemorySessionStore()
+
+		session, err := store.Get(ctx, "missing")
+
+		require.NoError(t, err, "Should get session without error")
+		assert.Nil(t, session, "Missing session should be nil")
+	})
+
+	t.Run("ExpiredSession", func(t *testing.T) {
+		store := NewMemorySessionStore()
+		require.NoError(t, store.Save(ctx, newTestSession("s1", "u1", time.Now().Add(-time.Second))), "Should save session")
+
+		session, err := store.Get(ctx, "s1")
+
+		require.NoError(t, err, "Should get session without error")
+		assert.Nil(t, session, "Expired session should be nil")
+	})
+
+	t.Run("ReturnsCopies", func(t *testing.T) {
+		store := NewMemorySessionStore()
+		original := newTestSession("s1", "u1", time.Now().Add(time.Hour))
+		require.NoError(t, store.Save(ctx, original), "Should save session")
+
+		original.UserID = "changed"
+		session, err := store.Get(ctx, "s1")
+		require.NoError(t, err, "Should get session without error")
+		session.Username = "changed"
+
+		stored, err := store.Get(ctx, "s1")
+		require.NoError(t, err, "Should get session without error")
+		assert.Equal(t, "u1", stored.UserID, "Store should not share the saved session")
+		assert.Empty(t, stored.Username, "Store should not share the returned session")
+	})
+}
+
+func TestMemorySessionStore_ListByUser(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySessionStore()
+
+	require.NoError(t, store.Save(ctx, newTestSession("s1", "u1", time.Now().Add(time.Hour))), "Should save session")
+	require.NoError(t, store.Save(ctx, newTestSession("s2", "u1", time.Now().Add(time.Hour))), "Should save session")
+	require.NoError(t, store.Save(ctx, newTestSession("s3", "u1", time.Now().Add(-time.Second))), "Should save session")
+	require.NoError(t, store.Save(ctx, newTestSession("s4", "u2", time.Now().Add(time.Hour))), "Should save session")
+
+	sessions, err := store.ListByUser(ctx, "u1")
+
+	require.NoError(t, err, "Should list sessions without error")
+
+	ids := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		ids = append(ids, session.ID)
+	}
+
+	assert.ElementsMatch(t, []string{"s1", "s2"}, ids, "Should list only unexpired sessions of the user")
+
+	sessions, err = store.ListByUser(ctx, "unknown")
+	require.NoError(t, err, "Should list sessions without error")
+	assert.Empty(t, sessions, "Unknown user should have no sessions")
+}
+
+func TestMemorySessionStore_Delete(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySessionStore()
+
+	require.NoError(t, store.Save(ctx, newTestSession("s1", "u1", time.Now().Add(time.Hour))), "Should save session")
+	require.NoError(t, store.Save(ctx, newTestSession("s2", "u1", time.Now().Add(time.Hour))), "Should save session")
+
+	require.NoError(t, store.Delete(ctx, "s1", "missing"), "Should delete sessions without error")
+
+	session, err := store.Get(ctx, "s1")
+	require.NoError(t, err, "Should get session without error")
+	assert.Nil(t, session, "Deleted session should be gone")
+
+	sessions, err := store.ListByUser(ctx, "u1")
+	require.NoError(t, err, "Should list sessions without error")
+	require.Len(t, sessions, 1, "Should keep the other session")
+	assert.Equal(t, "s2", sessions[0].ID, "Should keep the other session")
+}
+
+func TestMemorySessionStore_Touch(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySessionStore()
+	expiresAt := time.Now().Add(2 * time.Hour)
+
+	require.NoError(t, store.Save(ctx, newTestSession("s1", "u1", time.Now().Add(time.Hour))), "Should save session")
+
+	session, err := store.Touch(ctx, "s1", time.Now(), expiresAt)
+	require.NoError(t, err, "Should touch session without error")
+	require.NotNil(t, session, "Should return the touched session")
+	assert.True(t, session.ExpiresAt.Equal(expiresAt), "Should extend the session")
+
+	require.NoError(t, store.Delete(ctx, "s1"), "Should delete session")
+
+	session, err = store.Touch(ctx, "s1", time.Now(), expiresAt)
+	require.NoError(t, err, "Should touch session without error")
+	assert.Nil(t, session, "Should not touch a deleted session")
+
+	session, err = store.Get(ctx, "s1")
+	require.NoError(t, err, "Should get session without error")
+	assert.Nil(t, session, "Should not save a deleted session again")
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/samber/lo"
 
@@ -66,6 +67,12 @@ type Principal struct {
 	Roles []string `json:"roles"`
 	// Details is the details of the user.
 	Details any `json:"details"`
+	// SessionID is the id of the session the principal was authenticated in, empty outside sessions.
+	SessionID string `json:"-"`
+	// Impersonator is the operator acting as this principal, nil unless impersonating.
+	Impersonator *Principal `json:"impersonator,omitempty"`
+	// ImpersonationExpiresAt is the time the impersonation ends, its tokens expiring then at the latest.
+	ImpersonationExpiresAt time.Time `json:"-"`
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for Principal.
@@ -167,6 +174,20 @@ func (p *Principal) WithRoles(roles ...string) *Principal {
 	return p
 }
 
+// IsImpersonated reports whether an operator acts as the principal.
+func (p *Principal) IsImpersonated() bool {
+	return p.Impersonator != nil
+}
+
+// Operator returns the principal really operating: the impersonator when impersonating, otherwise the principal itself.
+func (p *Principal) Operator() *Principal {
+	if p.Impersonator != nil {
+		return p.Impersonator
+	}
+
+	return p
+}
+
 // NewUser is the function to create a new user principal.
 func NewUser(id, name string, roles ...string) *Principal {
 	return &Principal{
@@ -0,0 +1,105 @@
+package security
+
+import (
+	"context"
+	"time"
+
+	"github.com/ilxqx/vef-framework-go/event"
+)
+
+const (
+	// SessionLimitEvictOldest revokes the least recently active session when a login exceeds the session limit.
+	SessionLimitEvictOldest = "evict_oldest"
+	// SessionLimitReject refuses logins exceeding the session limit.
+	SessionLimitReject = "reject"
+
+	// HeaderDeviceID is the request header identifying the client device; a login from a device replaces its previous session.
+	HeaderDeviceID = "X-Device-Id"
+
+	eventTypeSessionRevoked = "vef.security.session.revoked"
+)
+
+// Session is an active login of a user on a device.
+// A session lives as long as its refresh token and is continued by every token refresh.
+type Session struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"userId"`
+	Username     string    `json:"username"`
+	DeviceID     string    `json:"deviceId"`
+	DeviceName   string    `json:"deviceName"` // Derived from the user agent, e.g. "Chrome on Windows"
+	IP           string    `json:"ip"`
+	UserAgent    string    `json:"userAgent"`
+	CreatedAt    time.Time `json:"createdAt"`
+	LastActiveAt time.Time `json:"lastActiveAt"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// DeviceInfo describes the client a session is created for.
+type DeviceInfo struct {
+	DeviceID  string
+	IP        string
+	UserAgent string
+}
+
+// SessionStore persists sessions.
+// The default store keeps sessions in memory; deployments running several instances should provide a shared store.
+// Implementations must be thread-safe for concurrent access.
+type SessionStore interface {
+	// Save creates or replaces a session; it may be dropped once expired.
+	Save(ctx context.Context, session *Session) error
+	// Get returns the session, or nil when it does not exist or has expired.
+	Get(ctx context.Context, id string) (*Session, error)
+	// Touch sets the activity and expiry of the session if it still exists and has not expired, and returns it,
+	// or nil otherwise. The check and the update must be atomic, so that a revoked session is never saved again.
+	Touch(ctx context.Context, id string, lastActiveAt, expiresAt time.Time) (*Session, error)
+	// ListByUser returns the unexpired sessions of the user.
+	ListByUser(ctx context.Context, userID string) ([]*Session, error)
+	// Delete removes the sessions.
+	Delete(ctx context.Context, ids ...string) error
+}
+
+// SessionManager tracks the sessions of users, enforces the concurrent session limit and revokes sessions.
+type SessionManager interface {
+	// Create starts a session for the principal on the device, enforcing the session limit.
+	// The session id is assigned to the principal so that tokens generated for it belong to the session.
+	Create(ctx context.Context, principal *Principal, device DeviceInfo) (*Session, error)
+	// Validate returns the active session with the id, recording the activity, or result.ErrSessionRevoked.
+	Validate(ctx context.Context, id string) (*Session, error)
+	// Refresh extends the session to the lifetime of newly issued tokens, or fails with result.ErrSessionRevoked.
+	Refresh(ctx context.Context, id string) (*Session, error)
+	// List returns the active sessions of the user, most recently active first.
+	List(ctx context.Context, userID string) ([]*Session, error)
+	// Revoke ends the sessions of the user with the ids; sessions of other users are left untouched.
+	Revoke(ctx context.Context, userID string, ids ...string) error
+	// RevokeAll ends all sessions of the user except the given ones.
+	RevokeAll(ctx context.Context, userID string, except ...string) error
+}
+
+// SessionRevokedEvent is published when sessions are revoked, e.g. to notify connected clients.
+type SessionRevokedEvent struct {
+	event.BaseEvent
+
+	UserID     string   `json:"userId"`
+	SessionIDs []string `json:"sessionIds"`
+	Reason     string   `json:"reason"` // revoked, evicted (session limit) or replaced (new login on the device)
+}
+
+// NewSessionRevokedEvent creates a new session revoked event.
+func NewSessionRevokedEvent(userID string, sessionIDs []string, reason string) *SessionRevokedEvent {
+	return &SessionRevokedEvent{
+		BaseEvent:  event.NewBaseEvent(eventTypeSessionRevoked),
+		UserID:     userID,
+		SessionIDs: sessionIDs,
+		Reason:     reason,
+	}
+}
+
+// SubscribeSessionRevokedEvent subscribes to session revoked events.
+// Returns an unsubscribe function that can be called to remove the subscription.
+func SubscribeSessionRevokedEvent(subscriber event.Subscriber, handler func(context.Context, *SessionRevokedEvent)) event.UnsubscribeFunc {
+	return subscriber.Subscribe(eventTypeSessionRevoked, func(ctx context.Context, evt event.Event) {
+		if revokedEvt, ok := evt.(*SessionRevokedEvent); ok {
+			handler(ctx, revokedEvt)
+		}
+	})
+}
@@ -188,11 +188,27 @@ func (s *Signature) verifyWithSecret(ctx context.Context, secret []byte, appID s
 }
 
 // checkAndStoreNonce validates nonce uniqueness and stores it if NonceStore is configured.
+// Errors of the store reject the request, so that an unavailable store never lets replays through.
 func (s *Signature) checkAndStoreNonce(ctx context.Context, appID, nonce string) error {
 	if s.nonceStore == nil {
 		return nil
 	}
 
+	ttl := s.timestampTolerance + nonceTTLBuffer
+
+	if store, ok := s.nonceStore.(AtomicNonceStore); ok {
+		stored, err := store.StoreIfAbsent(ctx, appID, nonce, ttl)
+		if err != nil {
+			return fmt.Errorf("failed to store nonce: %w", err)
+		}
+
+		if !stored {
+			return ErrSignatureNonceUsed
+		}
+
+		return nil
+	}
+
 	exists, err := s.nonceStore.Exists(ctx, appID, nonce)
 	if err != nil {
 		return fmt.Errorf("failed to check nonce: %w", err)
@@ -202,7 +218,7 @@ func (s *Signature) checkAndStoreNonce(ctx context.Context, appID, nonce string)
 		return ErrSignatureNonceUsed
 	}
 
-	if err := s.nonceStore.Store(ctx, appID, nonce, s.timestampTolerance+nonceTTLBuffer); err != nil {
+	if err := s.nonceStore.Store(ctx, appID, nonce, ttl); err != nil {
 		return fmt.Errorf("failed to store nonce: %w", err)
 	}
 
@@ -2,15 +2,30 @@ package security
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ilxqx/vef-framework-go/cache"
 )
 
 const testSignatureSecret = "af6675678bd81ad7c93c4a51d122ef61e9750fe5d42ceac1c33b293f36bc14c2"
 
+// slowContainsCache widens the window between checking and storing a nonce, which concurrent replays race through.
+type slowContainsCache struct {
+	cache.Cache[bool]
+}
+
+func (c slowContainsCache) Contains(ctx context.Context, key string) bool {
+	time.Sleep(10 * time.Millisecond)
+
+	return c.Cache.Contains(ctx, key)
+}
+
 func TestNewSignature(t *testing.T) {
 	t.Run("ValidSecret", func(t *testing.T) {
 		sig, err := NewSignature(testSignatureSecret)
@@ -281,6 +296,46 @@ func TestSignature_Verify(t *testing.T) {
 		assert.ErrorIs(t, err, ErrSignatureNonceUsed, "Should return nonce used error for replay attack")
 	})
 
+	t.Run("ConcurrentReplayPrevention", func(t *testing.T) {
+		nonces := slowContainsCache{Cache: cache.NewMemory[bool]()}
+
+		sig, err := NewSignature(testSignatureSecret, WithNonceStore(NewCacheNonceStore(nonces)))
+		require.NoError(t, err, "Should create signature without error")
+
+		result, err := sig.Sign("test-app")
+		require.NoError(t, err, "Should sign without error")
+
+		var (
+			wg       sync.WaitGroup
+			verified atomic.Int32
+		)
+
+		for range 20 {
+			wg.Go(func() {
+				if sig.Verify(ctx, result.AppID, result.Timestamp, result.Nonce, result.Signature) == nil {
+					verified.Add(1)
+				}
+			})
+		}
+
+		wg.Wait()
+		assert.Equal(t, int32(1), verified.Load(), "Should accept a nonce replayed concurrently only once")
+	})
+
+	t.Run("NonceStoreErrorRejects", func(t *testing.T) {
+		nonces := cache.NewMemory[bool]()
+		require.NoError(t, nonces.Close())
+
+		sig, err := NewSignature(testSignatureSecret, WithNonceStore(NewCacheNonceStore(nonces)))
+		require.NoError(t, err, "Should create signature without error")
+
+		result, err := sig.Sign("test-app")
+		require.NoError(t, err, "Should sign without error")
+
+		err = sig.Verify(ctx, result.AppID, result.Timestamp, result.Nonce, result.Signature)
+		assert.ErrorIs(t, err, cache.ErrCacheClosed, "Should reject the request when the nonce store fails")
+	})
+
 	t.Run("WithoutNonceStoreAllowsReplay", func(t *testing.T) {
 		sig, err := NewSignature(testSignatureSecret, WithNonceStore(nil))
 		require.NoError(t, err, "Should create signature without error")
@@ -36,6 +36,14 @@ type Service interface {
 	PromoteObject(ctx context.Context, tempKey string) (*ObjectInfo, error)
 }
 
+// ObjectComposer is implemented by services merging objects within the backend, e.g. MinIO and S3 through
+// server-side multipart copies, so that the content does not pass through the application.
+// Every source but the last must hold at least MinComposePartSize bytes.
+type ObjectComposer interface {
+	// ComposeObject merges the source objects in order into the destination object
+	ComposeObject(ctx context.Context, opts ComposeObjectOptions) (*ObjectInfo, error)
+}
+
 // Promoter defines the interface for automatic file field promotion and cleanup.
 // It supports three types of meta information fields:
 // - uploaded_file: Direct file fields (string, *string, null.String, []string)
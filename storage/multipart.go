@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// MultipartPrefix is the prefix under which the parts of chunked uploads are kept until they are merged.
+	// Uploads without activity for the configured multipart expiry are removed by a periodic sweep.
+	MultipartPrefix = TempPrefix + "multipart/"
+
+	// MaxMultipartParts is the maximum number of parts of a chunked upload.
+	MaxMultipartParts = 10000
+
+	// MinComposePartSize is the minimum size of every source but the last merged by an ObjectComposer,
+	// the minimum part size of S3 multipart uploads.
+	MinComposePartSize = 5 << 20
+)
+
+var (
+	// ErrUploadNotFound indicates the chunked upload does not exist, or was completed or aborted.
+	ErrUploadNotFound = errors.New("multipart upload not found")
+	// ErrInvalidPart indicates a part number, part size or the list of completed parts is invalid.
+	ErrInvalidPart = errors.New("invalid multipart part")
+	// ErrChecksumMismatch indicates the MD5 checksum of a part or of the merged file does not match the expected one.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// MultipartUpload is a chunked upload session; it is persisted next to its parts so that any instance can resume it.
+type MultipartUpload struct {
+	// UploadID identifies the upload session
+	UploadID string `json:"uploadId"`
+	// Key is the object key the parts are merged into
+	Key string `json:"key"`
+	// Filename is the original filename
+	Filename string `json:"filename"`
+	// ContentType is the content type of the merged object
+	ContentType string `json:"contentType"`
+	// Size is the declared total size in bytes, verified on completion when positive
+	Size int64 `json:"size"`
+	// PartSize is the size in bytes of every part but the last, 0 if parts may vary in size
+	PartSize int64 `json:"partSize"`
+	// MD5 is the expected hex MD5 checksum of the merged object, verified on completion when set
+	MD5 string `json:"md5,omitempty"`
+	// Metadata is the metadata of the merged object
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// CreatedAt is the time the upload was initiated
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// UploadedPart is a part stored for a chunked upload.
+type UploadedPart struct {
+	// PartNumber is the 1-based position of the part
+	PartNumber int `json:"partNumber"`
+	// ETag is the hex MD5 checksum of the part content
+	ETag string `json:"eTag"`
+	// Size is the part size in bytes
+	Size int64 `json:"size"`
+}
+
+// CompletedPart identifies a part to merge when completing a chunked upload.
+type CompletedPart struct {
+	// PartNumber is the 1-based position of the part
+	PartNumber int `json:"partNumber" validate:"required,min=1"`
+	// ETag is the ETag returned when the part was uploaded
+	ETag string `json:"eTag" validate:"required"`
+}
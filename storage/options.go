@@ -65,6 +65,18 @@ type CopyObjectOptions struct {
 	DestKey string
 }
 
+// ComposeObjectOptions contains parameters for merging objects into one.
+type ComposeObjectOptions struct {
+	// SourceKeys are the identifiers of the objects to merge, in order
+	SourceKeys []string
+	// DestKey is the identifier for the merged object
+	DestKey string
+	// ContentType specifies the MIME type of the merged object
+	ContentType string
+	// Metadata contains custom key-value pairs to store with the merged object
+	Metadata map[string]string
+}
+
 // MoveObjectOptions contains parameters for moving an object.
 type MoveObjectOptions struct {
 	CopyObjectOptions
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ilxqx/vef-framework-go/event"
+)
+
+const (
+	// MetadataKeyProcessingStatus is the metadata key for storing the post-processing status of an upload.
+	MetadataKeyProcessingStatus = "Processing-Status"
+	// MetadataKeyProcessingError is the metadata key for storing why processing rejected or failed an upload.
+	MetadataKeyProcessingError = "Processing-Error"
+	// MetadataKeyThumbnailKey is the metadata key for storing the object key of the generated thumbnail.
+	MetadataKeyThumbnailKey = "Thumbnail-Key"
+
+	// EventTypeFileProcessed is published when post-processing of an uploaded file finished.
+	EventTypeFileProcessed = "vef.storage.file.processed"
+)
+
+// ErrFileRejected is returned (wrapped) by processors that refuse an upload, e.g. when a virus was found.
+// Rejected uploads are emptied and keep their status and reason in the object metadata.
+var ErrFileRejected = errors.New("file rejected")
+
+// ErrFileNotProcessed indicates an upload is not served since its processing is pending or did not complete.
+var ErrFileNotProcessed = errors.New("file not processed")
+
+// ProcessingStatus is the post-processing status of an uploaded file.
+type ProcessingStatus string
+
+const (
+	// ProcessingPending indicates the upload is queued for processing.
+	ProcessingPending ProcessingStatus = "pending"
+	// ProcessingCompleted indicates all processors accepted the upload.
+	ProcessingCompleted ProcessingStatus = "completed"
+	// ProcessingRejected indicates a processor rejected the upload.
+	ProcessingRejected ProcessingStatus = "rejected"
+	// ProcessingFailed indicates processing could not be finished, the upload is kept unchanged.
+	ProcessingFailed ProcessingStatus = "failed"
+)
+
+// ProcessingFile is an uploaded file passing through the processors.
+// Processors may replace its content and content type and add derived variants such as thumbnails.
+type ProcessingFile struct {
+	// Key is the object key of the upload
+	Key string
+	// ContentType is the content type of the upload
+	ContentType string
+	// Data is the content of the upload
+	Data []byte
+	// Metadata is the object metadata of the upload
+	Metadata map[string]string
+	// Variants are derived files stored next to the upload
+	Variants []FileVariant
+}
+
+// FileVariant is a file derived from an upload, stored under the upload key with the name suffix appended to its base name.
+type FileVariant struct {
+	// Suffix is appended to the base name of the upload key, e.g. "thumb" stores "a/b.jpg" as "a/b_thumb.jpg"
+	Suffix string
+	// ContentType is the content type of the variant
+	ContentType string
+	// Data is the content of the variant
+	Data []byte
+}
+
+// Processor post-processes uploaded files.
+// Custom processors are registered with vef.ProvideStorageProcessor and run after the built-in ones.
+type Processor interface {
+	// Name returns the processor name used in logs
+	Name() string
+	// Process inspects or transforms the file; returning an error wrapping ErrFileRejected rejects the upload
+	Process(ctx context.Context, file *ProcessingFile) error
+}
+
+// FileProcessedEvent is published when post-processing of an uploaded file finished.
+type FileProcessedEvent struct {
+	event.BaseEvent
+
+	// The object key of the upload
+	FileKey string `json:"fileKey"`
+	// The resulting processing status
+	Status ProcessingStatus `json:"status"`
+	// The reason of a rejected or failed upload
+	Reason string `json:"reason,omitempty"`
+}
+
+// NewFileProcessedEvent creates a new file processed event.
+func NewFileProcessedEvent(fileKey string, status ProcessingStatus, reason string) *FileProcessedEvent {
+	return &FileProcessedEvent{
+		BaseEvent: event.NewBaseEvent(EventTypeFileProcessed),
+		FileKey:   fileKey,
+		Status:    status,
+		Reason:    reason,
+	}
+}
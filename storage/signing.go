@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// SignedURLPrefix is the path prefix of signed object URLs served by the framework.
+const SignedURLPrefix = "/storage/signed/"
+
+var (
+	// ErrInvalidSignature indicates a signed URL was tampered with or signed with another secret.
+	ErrInvalidSignature = errors.New("invalid url signature")
+	// ErrURLExpired indicates a signed URL is past its expiry.
+	ErrURLExpired = errors.New("signed url expired")
+	// ErrDownloadLimitReached indicates a signed URL was used as many times as it allows.
+	ErrDownloadLimitReached = errors.New("download limit reached")
+	// ErrWatermarkUnsupported indicates no watermarker supports the content type of the object.
+	ErrWatermarkUnsupported = errors.New("watermark unsupported")
+)
+
+// SignURLOptions contains parameters for signing an object URL.
+type SignURLOptions struct {
+	// Key is the object key
+	Key string
+	// Expires is the URL lifetime, 0 for the configured default
+	Expires time.Duration
+	// MaxDownloads limits how many times the URL can be used, 0 for unlimited
+	MaxDownloads int
+	// Watermark is the text applied to the document on download, empty for none
+	Watermark string
+	// Filename makes the URL download the object as an attachment with this name, empty to display it inline
+	Filename string
+}
+
+// URLSigner signs URLs granting temporary access to private objects through the framework file route.
+type URLSigner interface {
+	// Sign returns the signed path of the object, relative to the application root
+	Sign(ctx context.Context, opts SignURLOptions) (string, error)
+}
+
+// DownloadCounter counts the uses of signed URLs with a download limit.
+// The default counter is kept in memory; deployments running several instances should provide a shared one.
+type DownloadCounter interface {
+	// Increment increments the counter of the id and returns the new count; the counter may be dropped after ttl
+	Increment(ctx context.Context, id string, ttl time.Duration) (int64, error)
+}
+
+// Watermarker applies a text watermark to documents served through signed URLs.
+// Watermarkers are registered with vef.ProvideStorageWatermarker; the first one supporting a content type is used.
+type Watermarker interface {
+	// Supports reports whether documents of the content type can be watermarked
+	Supports(contentType string) bool
+	// Watermark returns the document content with the text applied
+	Watermark(ctx context.Context, content io.Reader, contentType, text string) (io.Reader, error)
+}
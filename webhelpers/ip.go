@@ -1,8 +1,41 @@
 package webhelpers
 
-import "github.com/gofiber/fiber/v3"
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+
+	"github.com/ilxqx/vef-framework-go/constants"
+)
 
 // GetIP retrieves X-Forwarded-For header or falls back to direct IP.
+// Clients can forge the header, use GetClientIP for addresses that are recorded or acted upon.
 func GetIP(ctx fiber.Ctx) string {
 	return ctx.Get(fiber.HeaderXForwardedFor, ctx.IP())
 }
+
+// GetClientIP retrieves the client address for access decisions: the remote address unless isTrustedProxy reports it
+// as a trusted proxy, in which case the right-most X-Forwarded-For entry that is not a trusted proxy, since clients
+// can forge every entry left of it. A nil isTrustedProxy trusts no proxy. The returned string is safe to keep after
+// the request.
+func GetClientIP(ctx fiber.Ctx, isTrustedProxy func(ip string) bool) string {
+	ip := ctx.IP()
+
+	if isTrustedProxy != nil && isTrustedProxy(ip) {
+		hops := strings.Split(ctx.Get(fiber.HeaderXForwardedFor), constants.Comma)
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if hop == constants.Empty {
+				continue
+			}
+
+			ip = hop
+			if !isTrustedProxy(hop) {
+				break
+			}
+		}
+	}
+
+	return utils.CopyString(ip)
+}
@@ -2,6 +2,7 @@ package webhelpers
 
 import (
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gofiber/fiber/v3"
@@ -97,3 +98,43 @@ func TestGetIP(t *testing.T) {
 		require.Equal(t, 200, resp.StatusCode)
 	})
 }
+
+func TestGetClientIP(t *testing.T) {
+	// The remote address of test requests is 0.0.0.0
+	trusted := func(ip string) bool {
+		return ip == "0.0.0.0" || strings.HasPrefix(ip, "10.")
+	}
+
+	tests := []struct {
+		name           string
+		forwarded      string
+		isTrustedProxy func(string) bool
+		expected       string
+	}{
+		{"NoTrustedProxies", "192.168.1.100", nil, "0.0.0.0"},
+		{"SingleAddress", "192.168.1.100", trusted, "192.168.1.100"},
+		{"ProxyChain", "203.0.113.7, 10.0.0.1, 10.0.0.2", trusted, "203.0.113.7"},
+		{"ForgedEntry", "10.1.2.3, 203.0.113.7, 10.0.0.1", trusted, "203.0.113.7"},
+		{"SurroundingSpaces", " 203.0.113.7 ,10.0.0.1", trusted, "203.0.113.7"},
+		{"OnlyTrustedHops", "10.0.0.1, 10.0.0.2", trusted, "10.0.0.1"},
+		{"NoHeader", "", trusted, "0.0.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+
+			app.Get("/test", func(c fiber.Ctx) error {
+				assert.Equal(t, tt.expected, GetClientIP(c, tt.isTrustedProxy), "Should return the address of the client")
+
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("X-Forwarded-For", tt.forwarded)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			require.Equal(t, 200, resp.StatusCode)
+		})
+	}
+}